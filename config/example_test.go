@@ -41,7 +41,7 @@ func ExamplePath() {
 	fmt.Println(p.BindStore(3).String())
 	// alternative way
 	fmt.Println(p.BindStore(3).String())
-	// Group is not supported and falls back to default
+	// Group scope gets kept
 	fmt.Println(p.Bind(scope.Group.WithID(4)).String())
 
 	p, err := config.MakePath("system/smtp/host")
@@ -63,7 +63,7 @@ func ExamplePath() {
 	// websites/1/system/smtp/host
 	// stores/3/system/smtp/host
 	// stores/3/system/smtp/host
-	// default/0/system/smtp/host
+	// group/4/system/smtp/host
 	// default/0/system/smtp/host
 	// dev/css/merge_css_files =>  dev css merge_css_files
 }
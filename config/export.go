@@ -0,0 +1,433 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// Format defines the file format of Export and Import.
+type Format uint8
+
+// Available formats of Export and Import.
+const (
+	// FormatJSON nests the values like the "system" key of Magento's
+	// app/etc/config.php, e.g. {"system":{"default":{"web":{"secure":
+	// {"base_url":"https://..."}}},"stores":{"2":{...}}}}. Websites and stores
+	// are identified by their ID and not by their code.
+	FormatJSON Format = iota + 1
+	// FormatCSV writes a flat CSV with the header scope,scope_id,path,value. A
+	// NULL value gets written as an empty string.
+	FormatCSV
+)
+
+// ExportMask replaces the values of sensitive paths if ExportOptions.Mask has
+// been set. Import skips sensitive paths with this value.
+const ExportMask = "******"
+
+// Iterator gets implemented by storage backends which can list all their
+// values. It is required by Export and by the dry run of Import.
+type Iterator interface {
+	// Iterate calls fn for each stored path. A NULL value gets passed as nil
+	// slice. Returning an error from fn stops the iteration.
+	Iterate(ctx context.Context, fn func(p Path, v []byte) error) error
+}
+
+// multiSetter gets implemented by storage backends which can write multiple
+// values at once, for example within one database transaction.
+type multiSetter interface {
+	SetMulti(ctx context.Context, scopes []string, scopeIDs []uint32, paths []string, values [][]byte) error
+}
+
+// ExportOptions applies options to the Export function.
+type ExportOptions struct {
+	// Sensitive identifies the routes which contain secrets, for example the
+	// same path matcher as used by storage.Encrypted. Sensitive routes get
+	// skipped or masked.
+	Sensitive func(route string) bool
+	// Mask writes the sensitive values as ExportMask instead of skipping them.
+	Mask bool
+}
+
+// ImportOptions applies options to the Import function.
+type ImportOptions struct {
+	Format Format
+	// DryRun does not write any value. Only the diff gets written to
+	// DiffOutput.
+	DryRun bool
+	// DiffOutput receives optionally one line per added (prefix "+") or
+	// changed (prefix "~") path.
+	DiffOutput io.Writer
+	// AllowUnknownPaths imports paths for which neither a FieldMeta nor an
+	// Observer has been registered. By default such paths get rejected.
+	AllowUnknownPaths bool
+	// Sensitive identifies the routes which contain secrets. Sensitive routes
+	// with the value ExportMask get skipped. In the diff the values of
+	// sensitive routes get masked.
+	Sensitive func(route string) bool
+}
+
+// ImportResult contains the statistics of an Import.
+type ImportResult struct {
+	Added     int
+	Changed   int
+	Unchanged int
+	Skipped   int
+}
+
+type exportRow struct {
+	path  Path
+	value []byte
+}
+
+// sortExportRows sorts the rows by scope default, websites, stores, then by ID
+// and route.
+func sortExportRows(rows []exportRow) {
+	sort.Slice(rows, func(i, j int) bool {
+		ri, rj := rows[i].path, rows[j].path
+		if ri.ScopeID != rj.ScopeID {
+			return ri.ScopeID < rj.ScopeID
+		}
+		return ri.route < rj.route
+	})
+}
+
+// Export writes all values of the level2 storage into w. The storage must
+// implement Iterator. The output gets sorted by scope and route.
+func (s *Service) Export(ctx context.Context, w io.Writer, format Format, o ...ExportOptions) error {
+	var opt ExportOptions
+	if len(o) == 1 {
+		opt = o[0]
+	}
+	it, ok := s.level2.(Iterator)
+	if !ok {
+		return errors.NotSupported.Newf("[config] Service.Export: Storage %T does not implement config.Iterator", s.level2)
+	}
+
+	var rows []exportRow
+	err := it.Iterate(ctx, func(p Path, v []byte) error {
+		if opt.Sensitive != nil && opt.Sensitive(p.route.String()) {
+			if !opt.Mask {
+				return nil
+			}
+			v = []byte(ExportMask)
+		}
+		if v != nil {
+			v = append([]byte{}, v...)
+		}
+		rows = append(rows, exportRow{path: p, value: v})
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	sortExportRows(rows)
+
+	switch format {
+	case FormatJSON:
+		return errors.WithStack(exportJSON(w, rows))
+	case FormatCSV:
+		return errors.WithStack(exportCSV(w, rows))
+	}
+	return errors.NotSupported.Newf("[config] Service.Export: Unknown format %d", format)
+}
+
+func exportJSON(w io.Writer, rows []exportRow) error {
+	system := map[string]interface{}{}
+	for _, r := range rows {
+		scp, id := r.path.ScopeID.Unpack()
+		var node map[string]interface{}
+		switch scp {
+		case scope.Website, scope.Group, scope.Store:
+			node = jsonChild(system, scp.StrType())
+			node = jsonChild(node, strconv.FormatUint(uint64(id), 10))
+		default:
+			node = jsonChild(system, scope.StrDefault.String())
+		}
+		parts := strings.Split(r.path.route.String(), sPathSeparator)
+		for _, part := range parts[:len(parts)-1] {
+			if node = jsonChild(node, part); node == nil {
+				return errors.NotSupported.Newf("[config] Export: Path %q is a value and a parent of another path", r.path.String())
+			}
+		}
+		leaf := parts[len(parts)-1]
+		if _, ok := node[leaf]; ok {
+			return errors.NotSupported.Newf("[config] Export: Path %q is a value and a parent of another path", r.path.String())
+		}
+		if r.value == nil {
+			node[leaf] = nil
+		} else {
+			node[leaf] = string(r.value)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{"system": system}) // sorts the keys
+}
+
+// jsonChild returns the child map of key and creates it if missing. Returns nil
+// if key contains a value.
+func jsonChild(node map[string]interface{}, key string) map[string]interface{} {
+	c, ok := node[key]
+	if !ok {
+		m := map[string]interface{}{}
+		node[key] = m
+		return m
+	}
+	m, _ := c.(map[string]interface{})
+	return m
+}
+
+func exportCSV(w io.Writer, rows []exportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"scope", "scope_id", "path", "value"}); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		scp, id := r.path.ScopeID.Unpack()
+		if scp != scope.Website && scp != scope.Group && scp != scope.Store {
+			scp, id = scope.Default, 0
+		}
+		if err := cw.Write([]string{scp.StrType(), strconv.FormatUint(uint64(id), 10), r.path.route.String(), string(r.value)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Import reads the values from r and writes them into the level2 storage. Each
+// path gets validated and runs through the before set observers, so a single
+// invalid value aborts the whole import before anything gets written. If the
+// storage supports writing multiple values at once, like storage.DB.SetMulti,
+// all values get written in one transaction.
+func (s *Service) Import(ctx context.Context, r io.Reader, o ImportOptions) (res ImportResult, err error) {
+	var rows []exportRow
+	switch o.Format {
+	case FormatJSON:
+		rows, err = importJSON(r)
+	case FormatCSV:
+		rows, err = importCSV(r)
+	default:
+		return res, errors.NotSupported.Newf("[config] Service.Import: Unknown format %d", o.Format)
+	}
+	if err != nil {
+		return res, errors.WithStack(err)
+	}
+
+	write := rows[:0]
+	for _, row := range rows {
+		route := row.path.route.String()
+		isSensitive := o.Sensitive != nil && o.Sensitive(route)
+		if isSensitive && string(row.value) == ExportMask {
+			res.Skipped++
+			continue
+		}
+		if row.value, err = s.importValidate(row.path, row.value, o.AllowUnknownPaths); err != nil {
+			return res, errors.WithStack(err)
+		}
+
+		current, found, err := s.level2.Get(row.path)
+		if err != nil {
+			return res, errors.Wrapf(err, "[config] Service.Import with path %q", row.path.String())
+		}
+		var diffFmt string
+		switch {
+		case !found:
+			res.Added++
+			diffFmt = "+ %s: %s\n"
+		case bytes.Equal(current, row.value) && (current == nil) == (row.value == nil):
+			res.Unchanged++
+			continue
+		default:
+			res.Changed++
+			diffFmt = "~ %s: %s\n"
+		}
+		if o.DiffOutput != nil {
+			v := fmt.Sprintf("%q", row.value)
+			switch {
+			case isSensitive:
+				v = ExportMask
+			case row.value == nil:
+				v = "NULL"
+			}
+			if _, err := fmt.Fprintf(o.DiffOutput, diffFmt, row.path.String(), v); err != nil {
+				return res, errors.WithStack(err)
+			}
+		}
+		write = append(write, row)
+	}
+	if o.DryRun || len(write) == 0 {
+		return res, nil
+	}
+
+	if ms, ok := s.level2.(multiSetter); ok {
+		scopes := make([]string, len(write))
+		scopeIDs := make([]uint32, len(write))
+		paths := make([]string, len(write))
+		values := make([][]byte, len(write))
+		for i, row := range write {
+			scp, id := row.path.ScopeID.Unpack()
+			scopes[i], scopeIDs[i], paths[i], values[i] = scp.StrType(), id, row.path.route.String(), row.value
+		}
+		if err := ms.SetMulti(ctx, scopes, scopeIDs, paths, values); err != nil {
+			return res, errors.Wrap(err, "[config] Service.Import.SetMulti")
+		}
+	} else {
+		for _, row := range write {
+			if err := s.level2.Set(row.path, row.value); err != nil {
+				return res, errors.Wrapf(err, "[config] Service.Import with path %q", row.path.String())
+			}
+		}
+	}
+	if s.pubSub != nil {
+		for _, row := range write {
+			s.pubSub.sendMsg(row.path)
+		}
+	}
+	return res, nil
+}
+
+// importValidate checks the path against the registered FieldMeta and runs the
+// validators and the before set observers.
+func (s *Service) importValidate(p Path, v []byte, allowUnknown bool) ([]byte, error) {
+	if err := p.IsValid(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !allowUnknown && !s.routeConfig.Has(p.route.String()) {
+		return nil, errors.NotFound.Newf("[config] Service.Import: Path %q has not been registered", p.String())
+	}
+	v, err := s.validate(p, v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	key := buildTrieKey(p.separatorSuffixRoute(), p.ScopeID)
+	if v, _, err = s.routeConfig.process(key, EventOnBeforeSet, p, v, true); err != nil {
+		return nil, errors.Wrapf(err, "[config] Service.Import with path %q", p.String())
+	}
+	return v, nil
+}
+
+func importCSV(r io.Reader) ([]exportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 4
+	var rows []exportRow
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, errors.CorruptData.New(err, "[config] Import: Invalid CSV")
+		}
+		if line == 1 && rec[0] == "scope" {
+			continue // header
+		}
+		var p Path
+		if err := p.ParseStrings(rec[0], rec[1], rec[2]); err != nil {
+			return nil, errors.Wrapf(err, "[config] Import: CSV line %d", line)
+		}
+		rows = append(rows, exportRow{path: p, value: []byte(rec[3])})
+	}
+}
+
+func importJSON(r io.Reader) ([]exportRow, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.CorruptData.New(err, "[config] Import: Invalid JSON")
+	}
+	if system, ok := doc["system"].(map[string]interface{}); ok {
+		doc = system
+	}
+
+	var rows []exportRow
+	for scpStr, scpNode := range doc {
+		scpMap, ok := scpNode.(map[string]interface{})
+		if !ok {
+			return nil, errors.CorruptData.Newf("[config] Import: Scope %q must be an object", scpStr)
+		}
+		if scpStr == scope.StrDefault.String() {
+			if err := importJSONRoutes(&rows, scpStr, "0", "", scpMap); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for id, idNode := range scpMap {
+			if _, err := strconv.ParseUint(id, 10, 32); err != nil {
+				return nil, errors.NotSupported.Newf("[config] Import: Scope %q with code %q is not supported, use the ID", scpStr, id)
+			}
+			idMap, ok := idNode.(map[string]interface{})
+			if !ok {
+				return nil, errors.CorruptData.Newf("[config] Import: Scope %s/%s must be an object", scpStr, id)
+			}
+			if err := importJSONRoutes(&rows, scpStr, id, "", idMap); err != nil {
+				return nil, err
+			}
+		}
+	}
+	sortExportRows(rows)
+	return rows, nil
+}
+
+func importJSONRoutes(rows *[]exportRow, scp, id, prefix string, node map[string]interface{}) error {
+	for k, v := range node {
+		route := k
+		if prefix != "" {
+			route = prefix + sPathSeparator + k
+		}
+		var val []byte
+		switch tv := v.(type) {
+		case map[string]interface{}:
+			if err := importJSONRoutes(rows, scp, id, route, tv); err != nil {
+				return err
+			}
+			continue
+		case nil:
+			// NULL value
+		case string:
+			val = []byte(tv)
+		case json.Number:
+			val = []byte(tv.String())
+		case bool:
+			val = []byte("0")
+			if tv {
+				val = []byte("1")
+			}
+		default:
+			return errors.NotSupported.Newf("[config] Import: Type %T of path %s/%s/%s is not supported", v, scp, id, route)
+		}
+		var p Path
+		if err := p.ParseStrings(scp, id, route); err != nil {
+			return errors.WithStack(err)
+		}
+		*rows = append(*rows, exportRow{path: p, value: val})
+	}
+	return nil
+}
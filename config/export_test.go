@@ -0,0 +1,193 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func isSensitive(route string) bool {
+	return strings.HasSuffix(route, "/secret_key")
+}
+
+func newExportService(t *testing.T) *config.Service {
+	srv := config.MustNewService(storage.NewMap(
+		"default/0/web/secure/base_url", "https://corestore.io/",
+		"default/0/payment/stripe/secret_key", "sk_live_123",
+		"websites/1/web/secure/base_url", "https://eu.corestore.io/",
+		"stores/2/general/locale/code", "de_CH",
+		"stores/2/general/locale/timezone", "",
+	), config.Options{})
+	return srv
+}
+
+func TestService_Export(t *testing.T) {
+	t.Parallel()
+	srv := newExportService(t)
+
+	t.Run("CSV masked", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, srv.Export(context.TODO(), &buf, config.FormatCSV, config.ExportOptions{Sensitive: isSensitive, Mask: true}))
+		assert.Exactly(t, `scope,scope_id,path,value
+default,0,payment/stripe/secret_key,******
+default,0,web/secure/base_url,https://corestore.io/
+websites,1,web/secure/base_url,https://eu.corestore.io/
+stores,2,general/locale/code,de_CH
+stores,2,general/locale/timezone,
+`, buf.String())
+	})
+
+	t.Run("JSON skipped", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, srv.Export(context.TODO(), &buf, config.FormatJSON, config.ExportOptions{Sensitive: isSensitive}))
+		assert.Exactly(t, `{
+  "system": {
+    "default": {
+      "web": {
+        "secure": {
+          "base_url": "https://corestore.io/"
+        }
+      }
+    },
+    "stores": {
+      "2": {
+        "general": {
+          "locale": {
+            "code": "de_CH",
+            "timezone": ""
+          }
+        }
+      }
+    },
+    "websites": {
+      "1": {
+        "web": {
+          "secure": {
+            "base_url": "https://eu.corestore.io/"
+          }
+        }
+      }
+    }
+  }
+}
+`, buf.String())
+	})
+
+	t.Run("storage without Iterator", func(t *testing.T) {
+		srv2 := config.MustNewService(storage.NewLRU(10), config.Options{})
+		err := srv2.Export(context.TODO(), &bytes.Buffer{}, config.FormatJSON)
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+}
+
+func TestService_Import_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []config.Format{config.FormatJSON, config.FormatCSV} {
+		srv := newExportService(t)
+		var want bytes.Buffer
+		assert.NoError(t, srv.Export(context.TODO(), &want, format))
+
+		// import into a truncated storage
+		srv2 := config.MustNewService(storage.NewMap(), config.Options{})
+		res, err := srv2.Import(context.TODO(), bytes.NewReader(want.Bytes()), config.ImportOptions{
+			Format:            format,
+			AllowUnknownPaths: true,
+		})
+		assert.NoError(t, err)
+		assert.Exactly(t, config.ImportResult{Added: 5}, res)
+
+		var have bytes.Buffer
+		assert.NoError(t, srv2.Export(context.TODO(), &have, format))
+		assert.Exactly(t, want.String(), have.String(), "Format %d", format)
+	}
+}
+
+func TestService_Import(t *testing.T) {
+	t.Parallel()
+
+	const data = `scope,scope_id,path,value
+default,0,payment/stripe/secret_key,******
+default,0,web/secure/base_url,https://new.corestore.io/
+stores,2,general/locale/code,de_CH
+stores,3,general/locale/code,fr_CH
+`
+
+	t.Run("dry run diff", func(t *testing.T) {
+		srv := newExportService(t)
+		var diff bytes.Buffer
+		res, err := srv.Import(context.TODO(), strings.NewReader(data), config.ImportOptions{
+			Format:            config.FormatCSV,
+			DryRun:            true,
+			DiffOutput:        &diff,
+			AllowUnknownPaths: true,
+			Sensitive:         isSensitive,
+		})
+		assert.NoError(t, err)
+		assert.Exactly(t, config.ImportResult{Added: 1, Changed: 1, Unchanged: 1, Skipped: 1}, res)
+		assert.Exactly(t, "~ default/0/web/secure/base_url: \"https://new.corestore.io/\"\n+ stores/3/general/locale/code: \"fr_CH\"\n", diff.String())
+
+		v, ok, err := srv.Get(config.MustMakePath("web/secure/base_url")).Str()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Exactly(t, "https://corestore.io/", v, "dry run must not write")
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		srv := config.MustNewService(storage.NewMap(), config.Options{},
+			config.WithFieldMeta(&config.FieldMeta{Route: "web/secure/base_url"}),
+		)
+		_, err := srv.Import(context.TODO(), strings.NewReader(data), config.ImportOptions{
+			Format:    config.FormatCSV,
+			Sensitive: isSensitive,
+		})
+		assert.ErrorIsKind(t, errors.NotFound, err)
+		validateNotFound(t, srv, config.MustMakePath("web/secure/base_url"))
+	})
+
+	t.Run("scope permission", func(t *testing.T) {
+		srv := config.MustNewService(storage.NewMap(), config.Options{},
+			config.WithFieldMeta(&config.FieldMeta{Route: "general/locale/code", WriteScopePerm: scope.PermWebsite}),
+		)
+		_, err := srv.Import(context.TODO(), strings.NewReader("stores,2,general/locale/code,de_CH"), config.ImportOptions{
+			Format: config.FormatCSV,
+		})
+		assert.ErrorIsKind(t, errors.NotAllowed, err)
+	})
+
+	t.Run("JSON store code not supported", func(t *testing.T) {
+		srv := config.MustNewService(storage.NewMap(), config.Options{})
+		_, err := srv.Import(context.TODO(), strings.NewReader(`{"system":{"stores":{"de":{"a":{"b":{"c":1}}}}}}`), config.ImportOptions{
+			Format:            config.FormatJSON,
+			AllowUnknownPaths: true,
+		})
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+}
+
+func validateNotFound(t *testing.T, srv *config.Service, p config.Path) {
+	_, ok, err := srv.Get(p).Str()
+	assert.NoError(t, err)
+	assert.False(t, ok, "Path %q should not be found", p.String())
+}
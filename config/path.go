@@ -39,6 +39,10 @@ var timeNow = time.Now
 // Like a/b/c for 3 parts. And 5 for a fully qualified path.
 const PathLevels = 3
 
+// MaxRouteLength defines the maximum length in bytes of a route, which is the
+// size of the column path in table core_configuration.
+const MaxRouteLength = 255
+
 // maxLevels maximum numbers of supported separators. used as array initializer.
 const maxLevels = 8 // up to 8. just a guess
 
@@ -56,6 +60,7 @@ const (
 	errIncorrectPathTpl     = "[config] Invalid Path %q. Either to short or missing path separator."
 	errIncorrectPositionTpl = "[config] Position '%d' does not exists"
 	errRouteInvalidBytesTpl = "[config] Route contains invalid bytes %q which are not runes."
+	errRouteTooLongTpl      = "[config] Route length %d exceeds the maximum of %d bytes."
 )
 
 func joinParts(buf *bytes.Buffer, parts ...string) {
@@ -92,6 +97,14 @@ func (r Route) BindWebsite(id uint32) Path {
 	}
 }
 
+// BindGroup creates a new Path and binds it to a store group scope and its ID.
+func (r Route) BindGroup(id uint32) Path {
+	return Path{
+		route:   r,
+		ScopeID: scope.MakeTypeID(scope.Group, id),
+	}
+}
+
 // BindStore creates a new Path and binds it to a store scope and its ID.
 func (r Route) BindStore(id uint32) Path {
 	return Path{
@@ -132,6 +145,9 @@ func (r Route) IsValid() error {
 	if seps == rLen {
 		return errors.NotValid.Newf("[config] Invalid Route %q. Either to short or missing path separator.", r)
 	}
+	if rLen > MaxRouteLength {
+		return errors.NotValid.Newf(errRouteTooLongTpl, rLen, MaxRouteLength)
+	}
 
 	if !utf8.ValidString(string(r)) {
 		return errors.NotValid.Newf(errRouteInvalidBytesTpl, r)
@@ -240,6 +256,14 @@ func (p Path) BindWebsite(id uint32) Path {
 	return p
 }
 
+// BindGroup binds a path to a store group scope and its ID. Returns a new Path
+// pointer and does not apply the changes to the current Path. Convenience
+// helper function. Fluent API design.
+func (p Path) BindGroup(id uint32) Path {
+	p.ScopeID = scope.MakeTypeID(scope.Group, id)
+	return p
+}
+
 // BindStore binds a path to a store scope and its ID. Returns a new Path
 // pointer and does not apply the changes to the current Path. Convenience
 // helper function. Fluent API design.
@@ -319,7 +343,7 @@ func (p Path) AppendFQ(buf *bytes.Buffer) error {
 	}
 
 	scp, id := p.ScopeID.Unpack()
-	if scp != scope.Website && scp != scope.Store {
+	if scp != scope.Website && scp != scope.Group && scp != scope.Store {
 		scp = scope.Default
 		id = 0
 	}
@@ -415,6 +439,9 @@ func (p Path) IsValid() error {
 		if seps == rLen {
 			return errors.NotValid.Newf(errIncorrectPathTpl, p.route)
 		}
+		if rLen > MaxRouteLength {
+			return errors.NotValid.Newf(errRouteTooLongTpl, rLen, MaxRouteLength)
+		}
 
 		if !utf8.ValidString(string(p.route)) {
 			return errors.NotValid.Newf(errRouteInvalidBytesTpl, p.route)
@@ -744,6 +771,104 @@ func (p Path) RouteHasPrefix(route string) bool {
 	return len(p.route) >= lr && lr > 0 && string(p.route[0:lr]) == route
 }
 
+// Match reports whether the route matches the glob-style pattern. A "*"
+// segment matches any single segment and a trailing "**" segment matches one or
+// more segments:
+//		carriers/*/active matches carriers/ups/active
+//		carriers/** matches carriers/ups/active and carriers/dhl/rates/express
+// An invalid pattern never matches.
+func (r Route) Match(pattern string) bool {
+	return matchRoute(pattern, string(r))
+}
+
+// Match reports whether the path matches the glob-style pattern, see
+// Route.Match. A pattern whose first segment is a scope, like "default",
+// "websites" or "stores", gets matched against the fully qualified path,
+// otherwise against the route. For example stores/*/carriers/** matches the
+// carriers routes of all stores. The environment suffix gets ignored.
+func (p Path) Match(pattern string) bool {
+	if i := strings.IndexByte(pattern, PathSeparator); i > 0 && scope.Valid(pattern[:i]) {
+		scp, id := p.ScopeID.Unpack()
+		if scp != scope.Website && scp != scope.Group && scp != scope.Store {
+			scp = scope.Default
+			id = 0
+		}
+		return pattern[:i] == scp.StrType() &&
+			matchRoute(pattern[i+1:], strconv.FormatUint(uint64(id), 10)+sPathSeparator+string(p.route))
+	}
+	return matchRoute(pattern, string(p.route))
+}
+
+// RouteMatcher returns a function which reports whether a route matches at
+// least one of the glob-style patterns, see Route.Match. It can be used as the
+// path matcher of the encrypted storage.
+func RouteMatcher(patterns ...string) func(route string) bool {
+	return func(route string) bool {
+		for _, pattern := range patterns {
+			if matchRoute(pattern, route) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// IsValidRoutePattern returns a NotValid error if the pattern contains empty
+// segments, invalid characters or a "**" segment which is not the last one.
+func IsValidRoutePattern(pattern string) error {
+	if pattern == "" {
+		return errors.NotValid.Newf("[config] Route pattern cannot be empty")
+	}
+	segs := strings.Split(pattern, sPathSeparator)
+	for i, seg := range segs {
+		switch {
+		case seg == "":
+			return errors.NotValid.Newf("[config] Route pattern %q contains an empty segment", pattern)
+		case seg == "**" && i < len(segs)-1:
+			return errors.NotValid.Newf("[config] Route pattern %q can only end with **", pattern)
+		case seg == "*", seg == "**":
+			continue
+		}
+		for _, rn := range seg {
+			if rn != '_' && !unicode.IsDigit(rn) && !unicode.IsLetter(rn) && !unicode.IsNumber(rn) {
+				return errors.NotValid.Newf("[config] Route pattern %q contains invalid character: %q", pattern, rn)
+			}
+		}
+	}
+	return nil
+}
+
+// matchRoute compares segment by segment without allocations.
+func matchRoute(pattern, route string) bool {
+	if pattern == "" || route == "" {
+		return false
+	}
+	for {
+		seg, pRest, pMore := cutSegment(pattern)
+		if seg == "**" {
+			return !pMore && route != ""
+		}
+		part, rRest, rMore := cutSegment(route)
+		if part == "" || (seg != "*" && seg != part) {
+			return false
+		}
+		if pMore != rMore {
+			return false
+		}
+		if !pMore {
+			return true
+		}
+		pattern, route = pRest, rRest
+	}
+}
+
+func cutSegment(s string) (seg, rest string, more bool) {
+	if i := strings.IndexByte(s, PathSeparator); i >= 0 {
+		return s[:i], s[i+1:], true
+	}
+	return s, "", false
+}
+
 // ExpireIn sets the current time with the default time zone and adds the
 // duration. It returns a copy of the path with the new expiration value.
 func (p Path) ExpireIn(d time.Duration) Path {
@@ -18,6 +18,7 @@ import (
 	"encoding"
 	"fmt"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -176,7 +177,7 @@ func TestMakePath(t *testing.T) {
 		{"ab/ba/cd", scope.Website, 3, "websites/3/ab/ba/cd", errors.NoKind},
 		{"ad/ba/ca/sd", scope.Website, 3, "websites/3/ad/ba/ca/sd", errors.NoKind},
 		{"as/sb", scope.Website, 3, "websites/3/a/b/c/d", errors.NotValid},
-		{"aa/bb/cc", scope.Group, 3, "default/0/aa/bb/cc", errors.NoKind},
+		{"aa/bb/cc", scope.Group, 3, "group/3/aa/bb/cc", errors.NoKind},
 		{"aa/bb/cc", scope.Store, 3, "stores/3/aa/bb/cc", errors.NoKind},
 	}
 	for i, test := range tests {
@@ -295,6 +296,7 @@ func TestPath_Parse(t *testing.T) {
 		{"catalog/frontend/list_allow_all", "default", 0, "default/0/catalog/frontend/list_allow_all", errors.NoKind},
 		{"catalog/frontend/list/allow_all", "default", 0, "default/0/catalog/frontend/list/allow_all", errors.NoKind},
 		{"groups/1/catalog/frontend/list_allow_all", "default", 0, "", errors.NotSupported},
+		{"group/3/catalog/frontend/list_allow_all", scope.StrGroup.String(), 3, "group/3/catalog/frontend/list_allow_all", errors.NoKind},
 		{"stores/7475/catalog/frontend/list_allow_all", scope.StrStores.String(), 7475, "stores/7475/catalog/frontend/list_allow_all", errors.NoKind},
 		{"stores/4/system/full_page_cache/varnish/backend_port", scope.StrStores.String(), 4, "stores/4/system/full_page_cache/varnish/backend_port", errors.NoKind},
 		{"websites/1/catalog/frontend/list_allow_all", scope.StrWebsites.String(), 1, "websites/1/catalog/frontend/list_allow_all", errors.NoKind},
@@ -855,6 +857,62 @@ func TestPath_HasRoutePrefix(t *testing.T) {
 	assert.True(t, p.RouteHasPrefix("xx/yy/zz"))
 }
 
+func TestPath_Match(t *testing.T) {
+	t.Parallel()
+	p := MustMakePathWithScope(scope.Store.WithID(4), "carriers/ups/active")
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"carriers/ups/active", true},
+		{"carriers/*/active", true},
+		{"*/*/*", true},
+		{"carriers/**", true},
+		{"**", true},
+		{"carriers/ups/active/**", false},
+		{"carriers/*", false},
+		{"carriers/dhl/active", false},
+		{"carriers/**/active", false},
+		{"", false},
+		{"stores/4/carriers/**", true},
+		{"stores/*/carriers/ups/active", true},
+		{"stores/5/carriers/**", false},
+		{"websites/*/carriers/**", false},
+		{"default/0/carriers/**", false},
+	}
+	for _, test := range tests {
+		assert.Exactly(t, test.want, p.Match(test.pattern), "Pattern %q", test.pattern)
+	}
+	assert.True(t, MustMakePath("carriers/ups/active").Match("default/0/carriers/**"))
+	assert.True(t, Route("carriers/dhl/rates/express").Match("carriers/**"))
+	assert.False(t, Route("carriers/dhl/rates/express").Match("carriers/*/*"))
+
+	m := RouteMatcher("payment/*/secret_key", "carriers/**")
+	assert.True(t, m("payment/stripe/secret_key"))
+	assert.True(t, m("carriers/ups/active"))
+	assert.False(t, m("payment/stripe/title"))
+}
+
+func TestIsValidRoutePattern(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, IsValidRoutePattern("carriers/*/active"))
+	assert.NoError(t, IsValidRoutePattern("carriers/**"))
+	assert.ErrorIsKind(t, errors.NotValid, IsValidRoutePattern(""))
+	assert.ErrorIsKind(t, errors.NotValid, IsValidRoutePattern("carriers//active"))
+	assert.ErrorIsKind(t, errors.NotValid, IsValidRoutePattern("carriers/**/active"))
+	assert.ErrorIsKind(t, errors.NotValid, IsValidRoutePattern("carriers/u-s/active"))
+}
+
+func TestPath_MaxRouteLength(t *testing.T) {
+	t.Parallel()
+	route := "aa/bb/" + strings.Repeat("c", MaxRouteLength-6)
+	_, err := MakePath(route)
+	assert.NoError(t, err)
+	_, err = MakePath(route + "c")
+	assert.ErrorIsKind(t, errors.NotValid, err)
+	assert.ErrorIsKind(t, errors.NotValid, Route(route+"c").IsValid())
+}
+
 func TestPath_EnvName(t *testing.T) {
 	t.Parallel()
 	t.Run("String and Binary", func(t *testing.T) {
@@ -0,0 +1,205 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+)
+
+// Validator checks a value before it gets written by Service.Set or
+// Service.Import. A nil value represents NULL and should usually be accepted.
+type Validator interface {
+	// Rule returns a short description of the rule for error messages, e.g.
+	// "url" or "int_range[1,100]".
+	Rule() string
+	// Validate returns the value to write, which can be rewritten, or an
+	// error to reject the value.
+	Validate(p Path, v []byte) ([]byte, error)
+}
+
+type pathValidator struct {
+	pattern string
+	v       Validator
+}
+
+// match reports whether the route starts with the pattern segments, see
+// Route.Match.
+func (pv pathValidator) match(route string) bool {
+	return matchRoute(pv.pattern, route) || matchRoute(pv.pattern+"/**", route)
+}
+
+// RegisterValidator adds a validator for all routes starting with pathPrefix.
+// A "*" segment in pathPrefix matches any single segment, e.g.
+// "carriers/*/max_weight", and a trailing "**" matches all routes below, see
+// Route.Match. Validators run before the EventOnBeforeSet observers in the
+// order of their registration. The first rejection stops the write and
+// returns an error with kind NotValid which names the path and the rule.
+func (s *Service) RegisterValidator(pathPrefix string, v Validator) error {
+	pathPrefix = strings.Trim(pathPrefix, sPathSeparator)
+	if pathPrefix == "" || v == nil {
+		return errors.Empty.Newf("[config] Service.RegisterValidator: Arguments pathPrefix %q and v cannot be empty", pathPrefix)
+	}
+	if err := IsValidRoutePattern(pathPrefix); err != nil {
+		return errors.WithStack(err)
+	}
+	s.mu.Lock()
+	s.validators = append(s.validators, pathValidator{
+		pattern: pathPrefix,
+		v:       v,
+	})
+	s.mu.Unlock()
+	return nil
+}
+
+// RegisterRouteValidator same as RegisterValidator but accepts a Route.
+func (s *Service) RegisterRouteValidator(r Route, v Validator) error {
+	return s.RegisterValidator(string(r), v)
+}
+
+// validate runs all matching validators. The caller must hold the lock.
+func (s *Service) validate(p Path, v []byte) (_ []byte, err error) {
+	route := p.route.String()
+	for _, pv := range s.validators {
+		if !pv.match(route) {
+			continue
+		}
+		if v, err = pv.v.Validate(p, v); err != nil {
+			return nil, errors.NotValid.New(err, "[config] Path %q failed rule %q", p.String(), pv.v.Rule())
+		}
+	}
+	return v, nil
+}
+
+type validatorFunc struct {
+	rule string
+	fn   func(v []byte) error
+}
+
+func (vf validatorFunc) Rule() string { return vf.rule }
+
+func (vf validatorFunc) Validate(_ Path, v []byte) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return v, vf.fn(v)
+}
+
+// NewURLValidator accepts absolute URLs with a scheme and a host.
+func NewURLValidator() Validator {
+	return validatorFunc{
+		rule: "url",
+		fn: func(v []byte) error {
+			u, err := url.Parse(string(v))
+			if err != nil {
+				return err
+			}
+			if u.Scheme == "" || u.Host == "" {
+				return errors.NotValid.Newf("URL %q must be absolute", v)
+			}
+			return nil
+		},
+	}
+}
+
+// NewIntRangeValidator accepts integers between min and max, both inclusive.
+func NewIntRangeValidator(min, max int64) Validator {
+	return validatorFunc{
+		rule: fmt.Sprintf("int_range[%d,%d]", min, max),
+		fn: func(v []byte) error {
+			i, err := strconv.ParseInt(strings.TrimSpace(string(v)), 10, 64)
+			if err != nil {
+				return err
+			}
+			if i < min || i > max {
+				return errors.OutOfRange.Newf("%d is not between %d and %d", i, min, max)
+			}
+			return nil
+		},
+	}
+}
+
+// NewEnumValidator accepts only the provided values. Case sensitive.
+func NewEnumValidator(values ...string) Validator {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return validatorFunc{
+		rule: "enum[" + strings.Join(values, ",") + "]",
+		fn: func(v []byte) error {
+			if _, ok := set[string(v)]; !ok {
+				return errors.NotFound.Newf("%q is not allowed", v)
+			}
+			return nil
+		},
+	}
+}
+
+// NewRegexpValidator accepts values which match the regular expression.
+func NewRegexpValidator(expr string) (Validator, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[config] NewRegexpValidator with expression %q", expr)
+	}
+	return validatorFunc{
+		rule: "regexp[" + expr + "]",
+		fn: func(v []byte) error {
+			if !re.Match(v) {
+				return errors.Mismatch.Newf("%q does not match", v)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// MustNewRegexpValidator same as NewRegexpValidator but panics on error.
+func MustNewRegexpValidator(expr string) Validator {
+	v, err := NewRegexpValidator(expr)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+type compositeValidator []Validator
+
+// NewCompositeValidator runs all validators in the given order and passes the
+// possibly rewritten value to the next one. The first rejection wins.
+func NewCompositeValidator(vs ...Validator) Validator {
+	return compositeValidator(vs)
+}
+
+func (cv compositeValidator) Rule() string {
+	rules := make([]string, len(cv))
+	for i, v := range cv {
+		rules[i] = v.Rule()
+	}
+	return "all(" + strings.Join(rules, ",") + ")"
+}
+
+func (cv compositeValidator) Validate(p Path, v []byte) (_ []byte, err error) {
+	for _, vv := range cv {
+		if v, err = vv.Validate(p, v); err != nil {
+			return nil, errors.Wrapf(err, "rule %q", vv.Rule())
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type trimValidator struct{}
+
+func (trimValidator) Rule() string { return "trim" }
+
+func (trimValidator) Validate(_ config.Path, v []byte) ([]byte, error) {
+	return bytes.TrimSpace(v), nil
+}
+
+func TestService_RegisterValidator(t *testing.T) {
+	t.Parallel()
+
+	srv := config.MustNewService(storage.NewMap(), config.Options{})
+	assert.NoError(t, srv.RegisterValidator("web/secure/base_url", config.NewURLValidator()))
+	assert.NoError(t, srv.RegisterValidator("carriers/*/max_weight", trimValidator{}))
+	assert.NoError(t, srv.RegisterValidator("carriers/*/max_weight", config.NewIntRangeValidator(1, 1000)))
+	assert.NoError(t, srv.RegisterValidator("general/locale", config.NewEnumValidator("de_CH", "fr_CH")))
+	assert.ErrorIsKind(t, errors.Empty, srv.RegisterValidator("/", trimValidator{}))
+	assert.ErrorIsKind(t, errors.NotValid, srv.RegisterValidator("carriers/**/max_weight", trimValidator{}))
+	assert.NoError(t, srv.RegisterRouteValidator("sales/**", config.NewEnumValidator("1", "0")))
+
+	t.Run("URL rejected", func(t *testing.T) {
+		err := srv.Set(config.MustMakePath("web/secure/base_url"), []byte(`corestore.io`))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.Contains(t, err.Error(), `"default/0/web/secure/base_url" failed rule "url"`)
+	})
+
+	t.Run("URL accepted", func(t *testing.T) {
+		p := config.MustMakePath("web/secure/base_url")
+		assert.NoError(t, srv.Set(p, []byte(`https://corestore.io/`)))
+		v, ok, err := srv.Get(p).Str()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Exactly(t, "https://corestore.io/", v)
+	})
+
+	t.Run("wildcard rewrite in order", func(t *testing.T) {
+		p := config.MustMakePath("carriers/dhl/max_weight")
+		assert.NoError(t, srv.Set(p, []byte(" 250 ")))
+		v, ok, err := srv.Get(p).Str()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Exactly(t, "250", v)
+
+		err = srv.Set(config.MustMakePath("carriers/ups/max_weight"), []byte("0"))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.Contains(t, err.Error(), `failed rule "int_range[1,1000]"`)
+		validateNotFound(t, srv, config.MustMakePath("carriers/ups/max_weight"))
+	})
+
+	t.Run("double star", func(t *testing.T) {
+		assert.NoError(t, srv.Set(config.MustMakePath("sales/reorder/allow"), []byte("1")))
+		assert.ErrorIsKind(t, errors.NotValid, srv.Set(config.MustMakePath("sales/reorder/allow"), []byte("yes")))
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		assert.NoError(t, srv.Set(config.MustMakePath("general/locale/code"), []byte("de_CH")))
+		assert.ErrorIsKind(t, errors.NotValid, srv.Set(config.MustMakePath("general/locale/code"), []byte("en_US")))
+		// prefix must match full segments
+		assert.NoError(t, srv.Set(config.MustMakePath("general/localeX/code"), []byte("en_US")))
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		res, err := srv.Import(context.TODO(), strings.NewReader("default,0,carriers/fedex/max_weight,5000\n"), config.ImportOptions{
+			Format:            config.FormatCSV,
+			AllowUnknownPaths: true,
+		})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.Contains(t, err.Error(), `"default/0/carriers/fedex/max_weight" failed rule "int_range[1,1000]"`)
+		assert.Exactly(t, config.ImportResult{}, res)
+		validateNotFound(t, srv, config.MustMakePath("carriers/fedex/max_weight"))
+	})
+}
+
+func TestNewCompositeValidator(t *testing.T) {
+	t.Parallel()
+
+	re, err := config.NewRegexpValidator(`^[0-9]+$`)
+	assert.NoError(t, err)
+	cv := config.NewCompositeValidator(trimValidator{}, re, config.NewIntRangeValidator(10, 20))
+	assert.Exactly(t, "all(trim,regexp[^[0-9]+$],int_range[10,20])", cv.Rule())
+
+	p := config.MustMakePath("aa/bb/cc")
+	v, err := cv.Validate(p, []byte(" 15\n"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "15", string(v))
+
+	_, err = cv.Validate(p, []byte("-15"))
+	assert.ErrorIsKind(t, errors.Mismatch, err)
+	assert.Contains(t, err.Error(), `rule "regexp[^[0-9]+$]"`)
+
+	_, err = cv.Validate(p, []byte("25"))
+	assert.ErrorIsKind(t, errors.OutOfRange, err)
+
+	v, err = cv.Validate(p, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	_, err = config.NewRegexpValidator(`[`)
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
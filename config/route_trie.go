@@ -182,6 +182,21 @@ func (trie *trieRoute) Get(key string) FieldMeta {
 	return node.fm
 }
 
+// Has returns true if a node exists at the given key, because a FieldMeta or an
+// Observer has been registered for it or for one of its scopes.
+func (trie *trieRoute) Has(key string) bool {
+	key = buildTrieKey(key, 0)
+	node := trie
+	for part, i := segmentRoute(key, 0); ; part, i = segmentRoute(key, i) {
+		if node = node.children[part]; node == nil {
+			return false
+		}
+		if i == -1 {
+			return true
+		}
+	}
+}
+
 // process runs on each tree level and dispatches the events and checks for
 // scope permission and default value.
 func (trie *trieRoute) process(key string, event uint8, p Path, v []byte, found bool) (v2 []byte, found2 bool, err error) {
@@ -0,0 +1,109 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// ScopeFallbackFunc returns the scopes in the order in which Scoped.Get queries
+// a value. Argument scp and id define the scope of the Scoped type, for example
+// scope.Store and 5. The first found value wins. An empty or nil result falls
+// back to the default chain store -> group -> website -> default.
+type ScopeFallbackFunc func(scp scope.Type, id uint32) scope.TypeIDs
+
+// NoFallback queries only the requested scope and never bubbles up to the
+// parent scopes. Useful for security settings which must be set explicitly per
+// store.
+func NoFallback(scp scope.Type, id uint32) scope.TypeIDs {
+	return scope.TypeIDs{scp.WithID(id)}
+}
+
+type pathScopeStrategy struct {
+	routePrefix string
+	fn          ScopeFallbackFunc
+}
+
+// WithScopeFallback sets the global scope fallback strategy which Scoped.Get
+// uses to find a value. Per route prefix strategies set via
+// WithPathScopeStrategy take precedence.
+func WithScopeFallback(fn ScopeFallbackFunc) LoadDataOption {
+	return LoadDataOption{
+		load: func(s *Service) error {
+			if fn == nil {
+				return errors.Empty.Newf("[config] WithScopeFallback: Argument fn cannot be nil")
+			}
+			s.mu.Lock()
+			s.scopeFallback = fn
+			s.mu.Unlock()
+			return nil
+		},
+	}
+}
+
+// WithPathScopeStrategy sets a scope fallback strategy for all routes which
+// start with routePrefix, e.g. "payment/". The longest matching prefix wins.
+// Setting the same prefix twice replaces the previous strategy.
+//		config.WithPathScopeStrategy("payment/", config.NoFallback)
+func WithPathScopeStrategy(routePrefix string, fn ScopeFallbackFunc) LoadDataOption {
+	return LoadDataOption{
+		load: func(s *Service) error {
+			if routePrefix == "" || fn == nil {
+				return errors.Empty.Newf("[config] WithPathScopeStrategy: Arguments routePrefix %q and fn cannot be empty", routePrefix)
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for i, pss := range s.pathScopeStrategies {
+				if pss.routePrefix == routePrefix {
+					s.pathScopeStrategies[i].fn = fn
+					return nil
+				}
+			}
+			s.pathScopeStrategies = append(s.pathScopeStrategies, pathScopeStrategy{routePrefix: routePrefix, fn: fn})
+			sort.SliceStable(s.pathScopeStrategies, func(i, j int) bool {
+				return len(s.pathScopeStrategies[i].routePrefix) > len(s.pathScopeStrategies[j].routePrefix)
+			})
+			return nil
+		},
+	}
+}
+
+// scopeFallbacker gets implemented by the Service to provide a custom scope
+// fallback chain to the Scoped type.
+type scopeFallbacker interface {
+	scopeFallbackChain(route string, scp scope.Type, id uint32) scope.TypeIDs
+}
+
+// scopeFallbackChain returns nil if no custom strategy has been set for the
+// route.
+func (s *Service) scopeFallbackChain(route string, scp scope.Type, id uint32) scope.TypeIDs {
+	s.mu.RLock()
+	fn := s.scopeFallback
+	for _, pss := range s.pathScopeStrategies {
+		if strings.HasPrefix(route, pss.routePrefix) {
+			fn = pss.fn
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if fn == nil {
+		return nil
+	}
+	return fn(scp, id)
+}
@@ -0,0 +1,129 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestScoped_Get_FoundInScope(t *testing.T) {
+	srv := config.MustNewService(storage.NewMap(
+		"default/0/aa/bb/cc", "a",
+		"websites/1/aa/bb/cc", "b",
+		"stores/2/aa/bb/dd", "d",
+	), config.Options{})
+
+	v := srv.Scoped(1, 2).Get(scope.Absent, "aa/bb/cc")
+	assert.Exactly(t, "b", v.UnsafeStr())
+	assert.Exactly(t, scope.Website.WithID(1), v.FoundInScope())
+	assert.True(t, v.IsInherited(), "value must be inherited from website")
+
+	v = srv.Scoped(1, 2).Get(scope.Absent, "aa/bb/dd")
+	assert.Exactly(t, scope.Store.WithID(2), v.FoundInScope())
+	assert.False(t, v.IsInherited(), "value must not be inherited")
+
+	v = srv.Scoped(1, 2).Get(scope.Absent, "aa/bb/ee")
+	assert.Exactly(t, scope.TypeID(0), v.FoundInScope())
+	assert.False(t, v.IsInherited())
+
+	v = srv.Get(config.MustMakePath("aa/bb/cc"))
+	assert.False(t, v.IsInherited(), "direct Get cannot inherit")
+}
+
+func TestScoped_Get_Group(t *testing.T) {
+	srv := config.MustNewService(storage.NewMap(
+		"default/0/aa/bb/cc", "a",
+		"websites/1/aa/bb/cc", "b",
+		"group/3/aa/bb/cc", "g",
+		"stores/2/aa/bb/dd", "d",
+		"group/3/aa/bb/dd", "gd",
+	), config.Options{})
+
+	v := srv.Scoped(1, 2).WithGroup(3).Get(scope.Absent, "aa/bb/cc")
+	assert.Exactly(t, "g", v.UnsafeStr())
+	assert.Exactly(t, scope.Group.WithID(3), v.FoundInScope())
+	assert.True(t, v.IsInherited(), "value must be inherited from the group")
+
+	v = srv.Scoped(1, 2).WithGroup(3).Get(scope.Absent, "aa/bb/dd")
+	assert.Exactly(t, "d", v.UnsafeStr())
+
+	v = srv.Scoped(1, 2).WithGroup(3).Get(scope.Group, "aa/bb/dd")
+	assert.Exactly(t, "gd", v.UnsafeStr(), "store scope must be skipped")
+
+	v = srv.Scoped(1, 2).WithGroup(3).Get(scope.Website, "aa/bb/cc")
+	assert.Exactly(t, "b", v.UnsafeStr(), "group scope must be skipped")
+
+	v = srv.Scoped(1, 0).WithGroup(3).Get(scope.Absent, "aa/bb/cc")
+	assert.Exactly(t, "g", v.UnsafeStr())
+	assert.False(t, v.IsInherited())
+
+	v = srv.Scoped(1, 2).Get(scope.Absent, "aa/bb/cc")
+	assert.Exactly(t, "b", v.UnsafeStr(), "without a group ID the group scope gets ignored")
+}
+
+func TestWithScopeFallback(t *testing.T) {
+	// store 2 belongs to the store group 3 which belongs to website 1
+	storeGroupChain := func(scp scope.Type, id uint32) scope.TypeIDs {
+		if scp == scope.Store && id == 2 {
+			return scope.TypeIDs{scope.Store.WithID(2), scope.Group.WithID(3), scope.Website.WithID(1), scope.DefaultTypeID}
+		}
+		return nil // default chain
+	}
+
+	srv := config.MustNewService(storage.NewMap(
+		"default/0/aa/bb/cc", "a",
+		"websites/1/aa/bb/cc", "b",
+		"default/0/payment/stripe/secret_key", "sk_default",
+	), config.Options{},
+		config.WithScopeFallback(storeGroupChain),
+		config.WithPathScopeStrategy("payment/", config.NoFallback),
+	)
+	assert.NoError(t, srv.Set(config.MustMakePath("aa/bb/cc").Bind(scope.Group.WithID(3)), []byte("g")))
+
+	t.Run("store group level", func(t *testing.T) {
+		v := srv.Scoped(1, 2).Get(scope.Absent, "aa/bb/cc")
+		assert.Exactly(t, "g", v.UnsafeStr())
+		assert.Exactly(t, scope.Group.WithID(3), v.FoundInScope())
+		assert.True(t, v.IsInherited())
+	})
+	t.Run("restricted up to website", func(t *testing.T) {
+		v := srv.Scoped(1, 2).Get(scope.Website, "aa/bb/cc")
+		assert.Exactly(t, "b", v.UnsafeStr())
+	})
+	t.Run("default chain for other scopes", func(t *testing.T) {
+		v := srv.Scoped(1, 4).Get(scope.Absent, "aa/bb/cc")
+		assert.Exactly(t, "b", v.UnsafeStr())
+		assert.Exactly(t, scope.Website.WithID(1), v.FoundInScope())
+	})
+	t.Run("no fallback per path prefix", func(t *testing.T) {
+		v := srv.Scoped(1, 2).Get(scope.Absent, "payment/stripe/secret_key")
+		_, ok, err := v.Str()
+		assert.NoError(t, err)
+		assert.False(t, ok, "value must not fall back to the default scope")
+
+		v = srv.Scoped(0, 0).Get(scope.Absent, "payment/stripe/secret_key")
+		assert.Exactly(t, "sk_default", v.UnsafeStr())
+	})
+	t.Run("nil function", func(t *testing.T) {
+		_, err := config.NewService(storage.NewMap(), config.Options{}, config.WithScopeFallback(nil))
+		assert.True(t, errors.Empty.Match(err), "%+v", err)
+	})
+}
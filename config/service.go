@@ -90,6 +90,14 @@ type Service struct {
 	// routeConfig contains essential information about a route like scope for
 	// permission, default value or events.
 	routeConfig *trieRoute
+	// scopeFallback defines the order of the scopes in Scoped.Get. Nil uses
+	// the default chain store -> website -> default.
+	scopeFallback ScopeFallbackFunc
+	// pathScopeStrategies overrides scopeFallback per route prefix, sorted by
+	// the longest prefix first.
+	pathScopeStrategies []pathScopeStrategy
+	// validators run before each write in the order of their registration.
+	validators []pathValidator
 }
 
 // NewService creates the main new configuration for all scopes: default,
@@ -330,6 +338,10 @@ func (s *Service) Set(p Path, v []byte) (err error) { // TODO v should be an imm
 	}
 
 	s.mu.RLock()
+	if v, err = s.validate(p, v); err != nil {
+		s.mu.RUnlock()
+		return errors.WithStack(err)
+	}
 	key := p.separatorSuffixRoute() // this can be optimized to move it into the process signature
 	key = buildTrieKey(key, p.ScopeID)
 	if v, _, err = s.routeConfig.process(key, EventOnBeforeSet, p, v, true); err != nil {
@@ -469,9 +481,10 @@ func (s *Service) Unsubscribe(subscriptionID int) error {
 }
 
 // Scoped is equal to Getter but not an interface and the underlying
-// implementation takes care of providing the correct scope: default, website or
-// store and bubbling up the scope chain from store -> website -> default if a
-// value won't get found in the desired scope. The Path for each primitive type
+// implementation takes care of providing the correct scope: default, website,
+// group or store and bubbling up the scope chain from store -> group -> website
+// -> default if a value won't get found in the desired scope. The group scope
+// gets only considered if a group ID has been set via WithGroup. The Path for each primitive type
 // represents always a path like "section/group/element" without the scope
 // string and scope ID.
 //
@@ -490,6 +503,7 @@ type Scoped struct {
 	// storage or a fake service.
 	rootSrv   getter
 	websiteID uint32
+	groupID   uint32
 	storeID   uint32
 }
 
@@ -509,17 +523,30 @@ func makeScoped(s getter, websiteID, storeID uint32) Scoped {
 	}
 }
 
+// WithGroup returns a copy of ss bound to the store group. Then the group
+// scope gets queried between the store and the website scope. The group must
+// belong to the website.
+func (ss Scoped) WithGroup(groupID uint32) Scoped {
+	ss.groupID = groupID
+	return ss
+}
+
 // IsValid checks if the object has been set up correctly.
 func (ss Scoped) IsValid() bool {
-	return ss.rootSrv != nil && ((ss.websiteID == 0 && ss.storeID == 0) ||
-		(ss.websiteID > 0 && ss.storeID == 0) ||
-		(ss.websiteID > 0 && ss.storeID > 0))
+	return ss.rootSrv != nil && (ss.groupID == 0 || ss.websiteID > 0) &&
+		((ss.websiteID == 0 && ss.storeID == 0) ||
+			(ss.websiteID > 0 && ss.storeID == 0) ||
+			(ss.websiteID > 0 && ss.storeID > 0))
 }
 
 // ParentID tells you the parent underlying scope and its ID. Store falls back
-// to website and website falls back to default.
+// to group, if set, or website. Group falls back to website and website falls
+// back to default.
 func (ss Scoped) ParentID() scope.TypeID {
-	if ss.storeID > 0 {
+	if ss.storeID > 0 && ss.groupID > 0 {
+		return scope.Group.WithID(ss.groupID)
+	}
+	if ss.storeID > 0 || ss.groupID > 0 {
 		return scope.Website.WithID(ss.websiteID)
 	}
 	return scope.DefaultTypeID
@@ -531,6 +558,9 @@ func (ss Scoped) ScopeID() scope.TypeID {
 	if ss.storeID > 0 {
 		return scope.Store.WithID(ss.storeID)
 	}
+	if ss.groupID > 0 {
+		return scope.Group.WithID(ss.groupID)
+	}
 	if ss.websiteID > 0 {
 		return scope.Website.WithID(ss.websiteID)
 	}
@@ -556,6 +586,14 @@ func (ss Scoped) isAllowedStore(restrictUpTo scope.Type) bool {
 	return ss.storeID > 0 && scope.PermStoreReverse.Has(scp)
 }
 
+func (ss Scoped) isAllowedGroup(restrictUpTo scope.Type) bool {
+	scp := ss.ScopeID().Type()
+	if restrictUpTo > scope.Absent {
+		scp = restrictUpTo
+	}
+	return ss.groupID > 0 && scope.PermGroupReverse.Has(scp)
+}
+
 func (ss Scoped) isAllowedWebsite(restrictUpTo scope.Type) bool {
 	scp := ss.ScopeID().Type()
 	if restrictUpTo > scope.Absent {
@@ -564,14 +602,58 @@ func (ss Scoped) isAllowedWebsite(restrictUpTo scope.Type) bool {
 	return ss.websiteID > 0 && scope.PermWebsiteReverse.Has(scp)
 }
 
-// Get traverses through the scopes store->website->default to find a matching
-// byte slice value. The argument `restrictUpTo` scope.Type restricts the
-// bubbling. For example a path gets stored in all three scopes but argument
-// `restrictUpTo` specifies only website scope, then the store scope will be
-// ignored for querying. If argument `restrictUpTo` has been set to zero aka.
-// scope.Absent, then all three scopes are considered for querying.
+// Get traverses through the scopes store->group->website->default to find a
+// matching byte slice value. The group scope gets skipped if no group ID has
+// been set. The argument `restrictUpTo` scope.Type restricts the
+// bubbling. For example a path gets stored in all scopes but argument
+// `restrictUpTo` specifies only website scope, then the store and group scope
+// will be ignored for querying. If argument `restrictUpTo` has been set to zero
+// aka. scope.Absent, then all scopes are considered for querying.
+// The order of the scopes can be changed with the options WithScopeFallback and
+// WithPathScopeStrategy. Value.FoundInScope and Value.IsInherited report the
+// scope which supplied the value.
 // Returns a guaranteed non-nil Value.
 func (ss Scoped) Get(restrictUpTo scope.Type, route string) (v *Value) {
+	scpID := ss.ScopeID()
+	if sf, ok := ss.rootSrv.(scopeFallbacker); ok {
+		scp, id := scpID.Unpack()
+		if chain := sf.scopeFallbackChain(route, scp, id); len(chain) > 0 {
+			v = ss.getChain(restrictUpTo, route, chain)
+			v.requestedScopeID = scpID
+			return v
+		}
+	}
+	v = ss.get(restrictUpTo, route)
+	v.requestedScopeID = scpID
+	return v
+}
+
+// getChain queries the scopes in the order of the chain. Scopes below
+// restrictUpTo get skipped.
+func (ss Scoped) getChain(restrictUpTo scope.Type, route string, chain scope.TypeIDs) (v *Value) {
+	p := Path{
+		route: Route(route),
+	}
+	for _, scpID := range chain {
+		if restrictUpTo > scope.Absent && scpID.Type() > restrictUpTo {
+			continue
+		}
+		p.ScopeID = scpID
+		v = ss.rootSrv.Get(p)
+		if v.found > valFoundNo || v.lastErr != nil {
+			if v.lastErr != nil {
+				v.lastErr = errors.WithStack(v.lastErr)
+			}
+			return v
+		}
+	}
+	if v == nil {
+		v = &Value{Path: p}
+	}
+	return v
+}
+
+func (ss Scoped) get(restrictUpTo scope.Type, route string) (v *Value) {
 	// fallback to next parent scope if value does not exists
 	p := Path{
 		route: Route(route),
@@ -587,6 +669,16 @@ func (ss Scoped) Get(restrictUpTo scope.Type, route string) (v *Value) {
 			return v
 		}
 	}
+	if ss.isAllowedGroup(restrictUpTo) {
+		p.ScopeID = scope.Group.WithID(ss.groupID)
+		v := ss.rootSrv.Get(p)
+		if v.found > valFoundNo || v.lastErr != nil {
+			if v.lastErr != nil {
+				v.lastErr = errors.WithStack(v.lastErr)
+			}
+			return v
+		}
+	}
 	if ss.isAllowedWebsite(restrictUpTo) {
 		p.ScopeID = scope.Website.WithID(ss.websiteID)
 		v := ss.rootSrv.Get(p)
@@ -35,6 +35,8 @@ func TestScopedService_Parent(t *testing.T) {
 		{makeScoped(nil, 33, 1), scope.Store, 1, scope.Website, 33},
 		{makeScoped(nil, 3, 0), scope.Website, 3, scope.Default, 0},
 		{makeScoped(nil, 0, 0), scope.Default, 0, scope.Default, 0},
+		{makeScoped(nil, 33, 1).WithGroup(4), scope.Store, 1, scope.Group, 4},
+		{makeScoped(nil, 33, 0).WithGroup(4), scope.Group, 4, scope.Website, 33},
 	}
 	for _, test := range tests {
 		haveScp, haveID := test.sg.ParentID().Unpack()
@@ -132,6 +132,14 @@ func TestScoped_IsValid(t *testing.T) {
 	}
 }
 
+func TestScoped_IsValid_Group(t *testing.T) {
+	t.Parallel()
+	cfg := config.NewFakeService(storage.NewMap())
+	assert.True(t, cfg.Scoped(1, 2).WithGroup(3).IsValid())
+	assert.True(t, cfg.Scoped(1, 0).WithGroup(3).IsValid())
+	assert.False(t, cfg.Scoped(0, 0).WithGroup(3).IsValid())
+}
+
 func TestScopedServiceScope(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -272,7 +280,7 @@ func TestScopedServicePermission_All(t *testing.T) {
 	}{
 		{scope.Default, "a", scope.TypeIDs{scope.DefaultTypeID}},
 		{scope.Website, "b", scope.TypeIDs{scope.Website.WithID(1)}},
-		{scope.Group, "a", scope.TypeIDs{scope.DefaultTypeID}},
+		{scope.Group, "b", scope.TypeIDs{scope.Website.WithID(1)}}, // no group ID set, hence group gets skipped
 		{scope.Store, "c", scope.TypeIDs{scope.Store.WithID(1)}},
 		{scope.Absent, "c", scope.TypeIDs{scope.Store.WithID(1)}}, // because ScopedGetter bound to store scope
 	}
@@ -17,9 +17,15 @@ package storage
 import (
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/storage/null"
 	"github.com/corestoreio/pkg/store/scope"
 )
 
+// DBPollerSubscriber gets called for each changed configuration path. An
+// invalid value means that the path has been deleted or has expired. Used by
+// DBPoller and Etcd.Watch.
+type DBPollerSubscriber func(scope string, scopeID uint32, path string, value null.String)
+
 type cacheKey struct {
 	scp   scope.TypeID
 	route string // route
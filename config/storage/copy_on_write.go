@@ -0,0 +1,186 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+)
+
+type cowEntry struct {
+	value   []byte
+	deleted bool
+}
+
+// CopyOnWriteSnapshot represents the state of a CopyOnWrite storage at the
+// time of calling Snapshot.
+type CopyOnWriteSnapshot struct {
+	overlay   map[cacheKey]cowEntry
+	truncated bool
+}
+
+// CopyOnWrite reads the values from a frozen base storage and writes all
+// changes into an overlay map. The base never gets modified. Mainly used for
+// testing, to avoid leaking mutated values into other tests. CopyOnWrite
+// implements config.Storager and is safe for concurrent use.
+type CopyOnWrite struct {
+	base config.Storager
+
+	mu        sync.RWMutex
+	overlay   map[cacheKey]cowEntry
+	truncated bool
+	// shared gets set when the overlay map is referenced by a snapshot. The
+	// next write must copy the map first.
+	shared bool
+}
+
+// NewCopyOnWrite creates a new copy-on-write storage on top of base.
+func NewCopyOnWrite(base config.Storager) *CopyOnWrite {
+	return &CopyOnWrite{
+		base:    base,
+		overlay: make(map[cacheKey]cowEntry),
+	}
+}
+
+// writable copies the overlay map if a snapshot references it. Must be called
+// while holding the write lock.
+func (c *CopyOnWrite) writable() {
+	if !c.shared {
+		return
+	}
+	o := make(map[cacheKey]cowEntry, len(c.overlay)+1)
+	for k, v := range c.overlay {
+		o[k] = v
+	}
+	c.overlay = o
+	c.shared = false
+}
+
+// Set writes the value into the overlay.
+func (c *CopyOnWrite) Set(p config.Path, value []byte) error {
+	if value != nil {
+		value = append([]byte{}, value...) // the caller might modify value
+	}
+	c.mu.Lock()
+	c.writable()
+	c.overlay[makeCacheKey(p.ScopeRoute())] = cowEntry{value: value}
+	c.mu.Unlock()
+	return nil
+}
+
+// Get returns the value from the overlay or falls back to the base, if the
+// path has not been deleted or truncated in the overlay.
+func (c *CopyOnWrite) Get(p config.Path) (v []byte, found bool, err error) {
+	c.mu.RLock()
+	e, ok := c.overlay[makeCacheKey(p.ScopeRoute())]
+	truncated := c.truncated
+	c.mu.RUnlock()
+	switch {
+	case ok && e.deleted:
+		return nil, false, nil
+	case ok:
+		if e.value == nil {
+			return nil, true, nil
+		}
+		return append([]byte{}, e.value...), true, nil
+	case truncated:
+		return nil, false, nil
+	}
+	return c.base.Get(p)
+}
+
+// Delete marks the path as deleted in the overlay.
+func (c *CopyOnWrite) Delete(p config.Path) error {
+	c.mu.Lock()
+	c.writable()
+	c.overlay[makeCacheKey(p.ScopeRoute())] = cowEntry{deleted: true}
+	c.mu.Unlock()
+	return nil
+}
+
+// Truncate marks all paths as deleted without touching the base.
+func (c *CopyOnWrite) Truncate() error {
+	c.mu.Lock()
+	c.overlay = make(map[cacheKey]cowEntry)
+	c.truncated = true
+	c.shared = false
+	c.mu.Unlock()
+	return nil
+}
+
+// Snapshot captures the current state in O(1). The overlay gets copied with the
+// next write.
+func (c *CopyOnWrite) Snapshot() CopyOnWriteSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shared = true
+	return CopyOnWriteSnapshot{overlay: c.overlay, truncated: c.truncated}
+}
+
+// Restore resets the state in O(1) to a previously taken snapshot. A snapshot
+// can be restored multiple times. A zero snapshot restores the state of
+// NewCopyOnWrite.
+func (c *CopyOnWrite) Restore(s CopyOnWriteSnapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s.overlay == nil {
+		s.overlay = make(map[cacheKey]cowEntry)
+	}
+	c.overlay = s.overlay
+	c.truncated = s.truncated
+	c.shared = true
+}
+
+// Iterate implements config.Iterator and merges the overlay with the base. The
+// base must implement config.Iterator unless Truncate has been called.
+func (c *CopyOnWrite) Iterate(ctx context.Context, fn func(p config.Path, v []byte) error) error {
+	c.mu.Lock()
+	overlay := c.overlay
+	truncated := c.truncated
+	c.shared = true // overlay gets read without lock
+	c.mu.Unlock()
+
+	if !truncated {
+		it, ok := c.base.(config.Iterator)
+		if !ok {
+			return errors.NotSupported.Newf("[config/storage] CopyOnWrite.Iterate: %T does not support Iterate", c.base)
+		}
+		err := it.Iterate(ctx, func(p config.Path, v []byte) error {
+			if _, ok := overlay[makeCacheKey(p.ScopeRoute())]; ok {
+				return nil
+			}
+			return fn(p, v)
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	for k, e := range overlay {
+		if e.deleted {
+			continue
+		}
+		p, err := config.MakePathWithScope(k.scp, k.route)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := fn(p, e.value); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var _ config.Storager = (*storage.CopyOnWrite)(nil)
+
+func TestCopyOnWrite(t *testing.T) {
+	t.Parallel()
+
+	base := storage.NewMap(
+		"default/0/aa/bb/cc", "base-cc",
+		"stores/2/aa/bb/dd", "base-dd",
+	)
+	cow := storage.NewCopyOnWrite(base)
+	pCC := config.MustMakePath("aa/bb/cc")
+	pDD := config.MustMakePathWithScope(scope.Store.WithID(2), "aa/bb/dd")
+
+	validateFoundGet(t, cow, scope.DefaultTypeID, "aa/bb/cc", "base-cc")
+
+	assert.NoError(t, cow.Set(pCC, []byte(`overlay-cc`)))
+	assert.NoError(t, cow.Delete(pDD))
+	validateFoundGet(t, cow, scope.DefaultTypeID, "aa/bb/cc", "overlay-cc")
+	validateNotFoundGet(t, cow, scope.Store.WithID(2), "aa/bb/dd")
+	// base stays untouched
+	validateFoundGet(t, base, scope.DefaultTypeID, "aa/bb/cc", "base-cc")
+	validateFoundGet(t, base, scope.Store.WithID(2), "aa/bb/dd", "base-dd")
+
+	snap := cow.Snapshot()
+
+	assert.NoError(t, cow.Truncate())
+	validateNotFoundGet(t, cow, scope.DefaultTypeID, "aa/bb/cc")
+	assert.NoError(t, cow.Set(pDD, []byte(`overlay-dd`)))
+	validateFoundGet(t, cow, scope.Store.WithID(2), "aa/bb/dd", "overlay-dd")
+	validateFoundGet(t, base, scope.Store.WithID(2), "aa/bb/dd", "base-dd")
+
+	cow.Restore(snap)
+	validateFoundGet(t, cow, scope.DefaultTypeID, "aa/bb/cc", "overlay-cc")
+	validateNotFoundGet(t, cow, scope.Store.WithID(2), "aa/bb/dd")
+
+	// writing after a restore must not modify the snapshot
+	assert.NoError(t, cow.Set(pCC, []byte(`changed`)))
+	cow.Restore(snap)
+	validateFoundGet(t, cow, scope.DefaultTypeID, "aa/bb/cc", "overlay-cc")
+
+	cow.Restore(storage.CopyOnWriteSnapshot{})
+	validateFoundGet(t, cow, scope.DefaultTypeID, "aa/bb/cc", "base-cc")
+	validateFoundGet(t, cow, scope.Store.WithID(2), "aa/bb/dd", "base-dd")
+}
+
+func TestCopyOnWrite_Parallel(t *testing.T) {
+	t.Parallel()
+
+	cow := storage.NewCopyOnWrite(storage.NewMap("default/0/aa/bb/cc", "base"))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p := config.MustMakePathWithScope(scope.Store.WithID(uint32(i)), "aa/bb/cc")
+			for j := 0; j < 100; j++ {
+				snap := cow.Snapshot()
+				assert.NoError(t, cow.Set(p, []byte(fmt.Sprintf("%d-%d", i, j))))
+				_, _, err := cow.Get(p)
+				assert.NoError(t, err)
+				if j%10 == 0 {
+					assert.NoError(t, cow.Delete(p))
+					cow.Restore(snap)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	validateFoundGet(t, cow, scope.DefaultTypeID, "aa/bb/cc", "base")
+}
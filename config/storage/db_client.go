@@ -20,6 +20,7 @@ package storage
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 
@@ -49,6 +50,10 @@ type DBOptions struct {
 	// SkipSchemaValidation disables the validation of the DB schema compared
 	// with the schema stored in Go source files.
 	SkipSchemaValidation bool
+	// MultiMaxBytes limits the estimated size of a single multi row INSERT
+	// statement created by SetMulti, to stay below the MySQL/MariaDB setting
+	// max_allowed_packet. Default 1MiB.
+	MultiMaxBytes int
 	// TODO implement UseDedicatedDBConnection per prepared statement, bit complicated
 	// UseDedicatedDBConnection *sql.DB
 }
@@ -67,7 +72,9 @@ type dbStats struct {
 // Service connects the MySQL/MariaDB with the config.Service type. Implements
 // interface config.Storager.
 type DB struct {
-	cfg DBOptions
+	cfg      DBOptions
+	connPool *dml.ConnPool
+	tbl      *ddl.Table
 
 	sqlRead  *dml.Select
 	sqlWrite *dml.Insert
@@ -103,9 +110,8 @@ func NewDB(tbls *ddl.Tables, o DBOptions) (*DB, error) {
 	// block Set and Value operations to let the caller wait until restart
 	// complete. might be an overhead.
 
-	tn := o.TableName
-	if tn == "" {
-		tn = TableNameCoreConfiguration
+	if o.TableName == "" {
+		o.TableName = TableNameCoreConfiguration
 	}
 
 	if !o.SkipSchemaValidation {
@@ -120,31 +126,25 @@ func NewDB(tbls *ddl.Tables, o DBOptions) (*DB, error) {
 		}
 	}
 
-	tbl, err := tbls.Table(tn)
+	tbl, err := tbls.Table(o.TableName)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	qryAll := tbl.Select("scope", "scope_id", "path").OrderBy("scope", "scope_id", "path")
-	qryAll.Log = o.Log
-
 	qryRead := tbl.Select("value").Where(
 		dml.Column("scope").PlaceHolder(),
 		dml.Column("scope_id").PlaceHolder(),
 		dml.Column("path").PlaceHolder(),
-	)
-	qryRead.Log = o.Log
-
-	qryWrite := tbl.Insert().BuildValues()
-	qryWrite.OnDuplicateKeys = dml.Conditions{dml.Column("value")}
-	qryWrite.Log = o.Log
+	).Where(dbNotExpired()...).OrderByDesc("expires").Limit(0, 1)
 
 	dbs := &DB{
 		cfg:              o,
+		connPool:         tbls.ConnPool,
+		tbl:              tbl,
 		tickerDaemonStop: make(chan struct{}),
 		sqlRead:          qryRead,
-		sqlWrite:         qryWrite,
 	}
+	dbs.sqlWrite = dbs.newInsert()
 	if dbs.cfg.IdleRead == 0 {
 		dbs.cfg.IdleRead = time.Second * 20 // just a guess
 	}
@@ -157,6 +157,9 @@ func NewDB(tbls *ddl.Tables, o DBOptions) (*DB, error) {
 	if dbs.cfg.ContextTimeoutWrite == 0 {
 		dbs.cfg.ContextTimeoutWrite = time.Second * 10 // just a guess
 	}
+	if dbs.cfg.MultiMaxBytes == 0 {
+		dbs.cfg.MultiMaxBytes = 1 << 20
+	}
 	dbs.runStateCheckers()
 	return dbs, nil
 }
@@ -224,9 +227,26 @@ func (dbs *DB) Close() error {
 	return nil
 }
 
-// Set puts a value with its key. Database errors get logged as Info message.
-// Enabled debug level logs the insert ID or rows affected.
+// Set puts a value with its key. Enabled debug level logs the insert ID or
+// rows affected. Set delegates to SetMulti.
 func (dbs *DB) Set(p config.Path, value []byte) error {
+	scp, path := p.ScopeRoute()
+	s, id := scp.Unpack()
+	return dbs.SetMulti(context.Background(), []string{s.StrType()}, []uint32{id}, []string{path}, [][]byte{value})
+}
+
+// SetScoped writes a single value with the typed scope. An invalid scope
+// returns a NotValid error before any SQL gets executed.
+func (dbs *DB) SetScoped(ctx context.Context, scp CoreConfigurationScope, scopeID uint32, path string, value []byte) error {
+	if err := validateScope(scp); err != nil {
+		return err
+	}
+	return dbs.setOne(ctx, scp, scopeID, path, value)
+}
+
+// setOne writes a single row with the prepared statement, which gets closed
+// after the idle time.
+func (dbs *DB) setOne(ctx context.Context, scp CoreConfigurationScope, scopeID uint32, path string, value []byte) error {
 	dbs.muWrite.Lock()
 	prevState := dbs.stmtWriteState
 	dbs.stmtWriteState = stateInUse
@@ -235,28 +255,21 @@ func (dbs *DB) Set(p config.Path, value []byte) error {
 		dbs.muWrite.Unlock()
 	}()
 
-	ctx := context.Background()
 	if prevState == stateClosed {
 		ctx2, cancel := context.WithTimeout(ctx, dbs.cfg.ContextTimeoutWrite)
 		defer cancel()
-		stmt, err := dbs.sqlWrite.Prepare(ctx2)
+		stmt, err := dbs.sqlWrite.WithDBR(dbs.connPool.DB).Prepare(ctx2)
 		if err != nil {
 			return errors.WithStack(err)
 		}
-		if dbs.stmtWrite == nil {
-			dbs.stmtWrite = stmt.WithDBR()
-		} else {
-			dbs.stmtWrite.WithPreparedStmt(stmt.Stmt)
-		}
+		dbs.stmtWrite = stmt
 		dbs.stmtWriteStat.Open++
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, dbs.cfg.ContextTimeoutWrite)
 	defer cancel()
-	scp, path := p.ScopeRoute()
-	res, err := dbs.stmtWrite.Uint64(scp.ToUint64()).String(path).Bytes(value).ExecContext(ctx)
-	dbs.stmtWrite.Reset()
-	if dbs.cfg.Log != nil && dbs.cfg.Log.IsDebug() {
+	res, err := dbs.stmtWrite.ExecContext(ctx, string(scp), scopeID, path, value)
+	if err == nil && dbs.cfg.Log != nil && dbs.cfg.Log.IsDebug() {
 		li, err1 := res.LastInsertId()
 		ra, err2 := res.RowsAffected()
 		dbs.cfg.Log.Debug(
@@ -265,7 +278,9 @@ func (dbs *DB) Set(p config.Path, value []byte) error {
 			log.ErrWithKey("lastInsertIDErr", err1),
 			log.Int64("rowsAffected", ra),
 			log.ErrWithKey("rowsAffectedErr", err2),
-			log.String("path", p.String()),
+			log.String("scope", string(scp)),
+			log.Uint("scope_id", uint(scopeID)),
+			log.String("path", path),
 			log.Int("value_len", len(value)),
 		)
 	}
@@ -276,7 +291,30 @@ func (dbs *DB) Set(p config.Path, value []byte) error {
 // Get performs a read operation from the database and returns a value from
 // the table. The `ok` return argument can be true even if byte slice `v` is
 // nil, which means that the path and scope are stored in the database table.
+// Expired rows get ignored and a temporary value takes precedence over a value
+// without expiration. Get delegates to GetMulti.
 func (dbs *DB) Get(p config.Path) (v []byte, ok bool, err error) {
+	scp, path := p.ScopeRoute()
+	s, id := scp.Unpack()
+	values, found, err := dbs.GetMulti(context.Background(), []string{s.StrType()}, []uint32{id}, []string{path})
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+	return values[0], found[0], nil
+}
+
+// GetScoped reads a single value with the typed scope. An invalid scope
+// returns a NotValid error before any SQL gets executed. See Get.
+func (dbs *DB) GetScoped(ctx context.Context, scp CoreConfigurationScope, scopeID uint32, path string) (v []byte, ok bool, err error) {
+	if err := validateScope(scp); err != nil {
+		return nil, false, err
+	}
+	return dbs.getOne(ctx, scp, scopeID, path)
+}
+
+// getOne reads a single row with the prepared statement, which gets closed
+// after the idle time.
+func (dbs *DB) getOne(ctx context.Context, scp CoreConfigurationScope, scopeID uint32, path string) (v []byte, ok bool, err error) {
 	dbs.muRead.Lock()
 	prevState := dbs.stmtReadState
 	dbs.stmtReadState = stateInUse
@@ -285,30 +323,22 @@ func (dbs *DB) Get(p config.Path) (v []byte, ok bool, err error) {
 		dbs.muRead.Unlock()
 	}()
 
-	ctx := context.Background()
 	if prevState == stateClosed {
 		ctx2, cancel := context.WithTimeout(ctx, dbs.cfg.ContextTimeoutRead)
 		defer cancel()
-		stmt, err := dbs.sqlRead.Prepare(ctx2)
+		stmt, err := dbs.sqlRead.WithDBR(dbs.connPool.DB).Prepare(ctx2)
 		if err != nil {
 			return nil, false, errors.WithStack(err)
 		}
-		if dbs.stmtRead == nil {
-			dbs.stmtRead = stmt.WithDBR()
-		} else {
-			dbs.stmtRead.WithPreparedStmt(stmt.Stmt)
-		}
+		dbs.stmtRead = stmt
 		dbs.stmtReadStat.Open++
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, dbs.cfg.ContextTimeoutRead)
 	defer cancel()
-	scp, path := p.ScopeRoute()
-	s, id := scp.Unpack()
-	nv, found, err := dbs.stmtRead.String(s.StrType()).Uint(uint(id)).String(path).LoadNullString(ctx)
-	defer dbs.stmtRead.Reset()
+	nv, found, err := dbs.stmtRead.LoadNullString(ctx, string(scp), scopeID, path)
 	if err != nil {
-		return nil, false, errors.Wrapf(err, "[config/storage] DB Scope %q Path %q", scp.String(), path)
+		return nil, false, errors.Wrapf(err, "[config/storage] DB Scope %q ID %d Path %q", scp, scopeID, path)
 	}
 	if !found {
 		return nil, false, nil
@@ -320,6 +350,255 @@ func (dbs *DB) Get(p config.Path) (v []byte, ok bool, err error) {
 	return ret, true, nil
 }
 
+// dbMultiMaxRows limits the rows per statement of SetMulti and GetMulti to stay
+// below the maximum of 65535 placeholders per statement.
+const dbMultiMaxRows = 65535 / 4
+
+func checkMultiArgs(scopes []string, scopeIDs []uint32, paths []string, values [][]byte, withValues bool) error {
+	if len(scopes) != len(paths) || len(scopeIDs) != len(paths) || (withValues && len(values) != len(paths)) {
+		return errors.Mismatch.Newf("[config/storage] All slices must have the same length. scopes %d scopeIDs %d paths %d values %d",
+			len(scopes), len(scopeIDs), len(paths), len(values))
+	}
+	for i, scp := range scopes {
+		if err := validateScope(CoreConfigurationScope(scp)); err != nil {
+			return errors.Wrapf(err, "[config/storage] Row %d", i)
+		}
+	}
+	return nil
+}
+
+func validateScope(scp CoreConfigurationScope) error {
+	if !scp.IsValid() {
+		return errors.NotValid.Newf("[config/storage] Invalid scope %q", scp)
+	}
+	return nil
+}
+
+// SetScope sets the columns scope and scope_id. An invalid scope returns a
+// NotValid error and leaves the entity unchanged.
+func (e *CoreConfiguration) SetScope(scp CoreConfigurationScope, id uint32) error {
+	if err := validateScope(scp); err != nil {
+		return err
+	}
+	e.Scope = string(scp)
+	e.ScopeID = int32(id)
+	return nil
+}
+
+// newInsert creates an INSERT ... ON DUPLICATE KEY UPDATE statement for one
+// row. The columns must be listed explicitly, because the table contains
+// further columns like expires, which get only written by SetWithTTL.
+func (dbs *DB) newInsert() *dml.Insert {
+	ins := dml.NewInsert(dbs.cfg.TableName).AddColumns("scope", "scope_id", "path", "value").BuildValues()
+	ins.OnDuplicateKeys = dml.Conditions{dml.Column("value")}
+	return ins
+}
+
+// SetMulti writes all values within one transaction using multi row
+// INSERT ... ON DUPLICATE KEY UPDATE statements. The statements get split into
+// chunks to stay below the size of option MultiMaxBytes. On error the whole
+// transaction gets rolled back. All slices must have the same length and the
+// same index refers to the same row. The context controls the timeout.
+func (dbs *DB) SetMulti(ctx context.Context, scopes []string, scopeIDs []uint32, paths []string, values [][]byte) error {
+	if err := checkMultiArgs(scopes, scopeIDs, paths, values, true); err != nil {
+		return err
+	}
+	switch len(paths) {
+	case 0:
+		return nil
+	case 1:
+		return dbs.setOne(ctx, CoreConfigurationScope(scopes[0]), scopeIDs[0], paths[0], values[0])
+	}
+
+	return dbs.connPool.Transaction(ctx, nil, func(tx *dml.Tx) error {
+		for start := 0; start < len(paths); {
+			end := start
+			size := 0
+			for end < len(paths) && end-start < dbMultiMaxRows && (end == start || size < dbs.cfg.MultiMaxBytes) {
+				size += len(scopes[end]) + len(paths[end]) + len(values[end]) + 32 // 32 for the scope ID, quotes and commas
+				end++
+			}
+
+			ins := dbs.newInsert().SetRowCount(end - start)
+
+			args := make([]interface{}, 0, (end-start)*4)
+			for i := start; i < end; i++ {
+				args = append(args, scopes[i], scopeIDs[i], paths[i], values[i])
+			}
+			res, err := tx.WithQueryBuilder(ins).ExecContext(ctx, args...)
+			if err != nil {
+				return errors.Wrapf(err, "[config/storage] DB.SetMulti rows %d to %d", start, end)
+			}
+			if dbs.cfg.Log != nil && dbs.cfg.Log.IsDebug() {
+				ra, err := res.RowsAffected()
+				dbs.cfg.Log.Debug(
+					"config.storage.DB.SetMulti.Write.Result",
+					log.Int64("rowsAffected", ra),
+					log.ErrWithKey("rowsAffectedErr", err),
+					log.Int("rows", end-start),
+					log.Int("size", size),
+				)
+			}
+			start = end
+		}
+		return nil
+	})
+}
+
+// GetMulti reads the values with a single `(scope, scope_id, path) IN (...)`
+// query. The returned slices have the same order and length as the requested
+// paths. A missing row returns a nil value and false in slice found. A NULL
+// value returns a nil value and true. All slices must have the same length.
+// The context controls the timeout.
+func (dbs *DB) GetMulti(ctx context.Context, scopes []string, scopeIDs []uint32, paths []string) (values [][]byte, found []bool, err error) {
+	if err := checkMultiArgs(scopes, scopeIDs, paths, nil, false); err != nil {
+		return nil, nil, err
+	}
+	values = make([][]byte, len(paths))
+	found = make([]bool, len(paths))
+	switch len(paths) {
+	case 0:
+		return values, found, nil
+	case 1:
+		values[0], found[0], err = dbs.getOne(ctx, CoreConfigurationScope(scopes[0]), scopeIDs[0], paths[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		return values, found, nil
+	}
+
+	// duplicate requested paths get queried once
+	positions := make(map[dbPathKey][]int, len(paths))
+	keys := make([]dbPathKey, 0, len(paths))
+	for i := range paths {
+		k := dbPathKey{scope: scopes[i], scopeID: scopeIDs[i], path: paths[i]}
+		if _, ok := positions[k]; !ok {
+			keys = append(keys, k)
+		}
+		positions[k] = append(positions[k], i)
+	}
+
+	for start := 0; start < len(keys); start += dbMultiMaxRows {
+		end := start + dbMultiMaxRows
+		if end > len(keys) {
+			end = len(keys)
+		}
+		args := make([]interface{}, 0, (end-start)*3)
+		for _, k := range keys[start:end] {
+			args = append(args, k.scope, uint64(k.scopeID), k.path) // the tuple expansion does not support uint32
+		}
+
+		sel := dbs.tbl.Select("scope", "scope_id", "path", "value").Where(
+			dml.Columns("scope", "scope_id", "path").In().Tuples(),
+		).Where(dbNotExpired()...).OrderByDesc("expires")
+
+		err = dbs.connPool.WithQueryBuilder(sel).IterateSerial(ctx, func(cm *dml.ColumnMap) error {
+			var ccd CoreConfiguration
+			if err := ccd.MapColumns(cm); err != nil {
+				return errors.Wrapf(err, "[config/storage] DB.GetMulti at row %d", cm.Count)
+			}
+			var v []byte
+			if ccd.Value.Valid {
+				v = []byte(ccd.Value.Data)
+			}
+			idxs := positions[dbPathKey{scope: ccd.Scope, scopeID: uint32(ccd.ScopeID), path: ccd.Path}]
+			if len(idxs) == 0 || found[idxs[0]] {
+				return nil // a temporary value with a later expiration has already been found
+			}
+			for _, idx := range idxs {
+				values[idx] = v
+				found[idx] = true
+			}
+			return nil
+		}, args...)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "[config/storage] DB.GetMulti rows %d to %d", start, end)
+		}
+	}
+	return values, found, nil
+}
+
+// Iterate implements config.Iterator and calls fn for each not expired row. A
+// temporary value takes precedence over a value without expiration.
+func (dbs *DB) Iterate(ctx context.Context, fn func(p config.Path, v []byte) error) error {
+	sel := dbs.tbl.Select("scope", "scope_id", "path", "value").
+		Where(dbNotExpired()...).
+		OrderBy("scope", "scope_id", "path").OrderByDesc("expires")
+
+	var prev dbPathKey
+	var hasPrev bool
+	err := dbs.connPool.WithQueryBuilder(sel).IterateSerial(ctx, func(cm *dml.ColumnMap) error {
+		var ccd CoreConfiguration
+		if err := ccd.MapColumns(cm); err != nil {
+			return errors.Wrapf(err, "[config/storage] DB.Iterate at row %d", cm.Count)
+		}
+		k := dbPathKey{scope: ccd.Scope, scopeID: uint32(ccd.ScopeID), path: ccd.Path}
+		if hasPrev && k == prev {
+			return nil // value without expiration, hidden by a temporary value
+		}
+		prev, hasPrev = k, true
+		p, err := config.MakePathWithScope(scope.FromString(ccd.Scope).WithID(uint32(ccd.ScopeID)), ccd.Path)
+		if err != nil {
+			return errors.Wrapf(err, "[config/storage] DB.Iterate at row %d", cm.Count)
+		}
+		var v []byte
+		if ccd.Value.Valid {
+			v = []byte(ccd.Value.Data)
+		}
+		return fn(p, v)
+	})
+	return errors.WithStack(err)
+}
+
+// DeleteMatching deletes the rows of all scopes whose route matches the
+// glob-style pattern, e.g. "carriers/**", see config.Route.Match. It returns
+// the number of deleted rows.
+func (dbs *DB) DeleteMatching(ctx context.Context, pattern string) (deleted int64, err error) {
+	if err := config.IsValidRoutePattern(pattern); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	del := dbs.tbl.Delete().Where(dml.Column("path").Regexp().PlaceHolder())
+	res, err := dbs.connPool.WithQueryBuilder(del).ExecContext(ctx, routePatternRegexp(pattern))
+	if err != nil {
+		return 0, errors.Wrapf(err, "[config/storage] DB.DeleteMatching Pattern %q", pattern)
+	}
+	deleted, err = res.RowsAffected()
+	return deleted, errors.WithStack(err)
+}
+
+// routePatternRegexp converts a validated route pattern into a regular
+// expression for MySQL.
+func routePatternRegexp(pattern string) string {
+	var buf strings.Builder
+	buf.WriteByte('^')
+	for i, seg := range strings.Split(pattern, "/") {
+		if i > 0 {
+			buf.WriteByte('/')
+		}
+		switch seg {
+		case "*":
+			buf.WriteString("[^/]+")
+		case "**":
+			buf.WriteString(".+")
+		default:
+			buf.WriteString(seg)
+		}
+	}
+	buf.WriteByte('$')
+	return buf.String()
+}
+
+// dbHealthCheckRoute gets read by HealthCheck. The row does not need to exist.
+const dbHealthCheckRoute = "health/check/probe"
+
+// HealthCheck reads a probe path with the prepared read statement, which
+// checks the connection and the table. A missing row is not an error. Suitable
+// for readiness probes, see package storage/health.
+func (dbs *DB) HealthCheck(ctx context.Context) error {
+	_, _, err := dbs.getOne(ctx, CoreConfigurationScopeDefault, 0, dbHealthCheckRoute)
+	return errors.WithStack(err)
+}
+
 // Statistics returns live statistics about opening and closing prepared statements.
 func (dbs *DB) Statistics() (value dbStats, set dbStats) {
 	dbs.muRead.Lock()
@@ -354,7 +633,7 @@ func WithLoadFromDB(tbls *ddl.Tables, o DBOptions) config.LoadDataOption {
 		ctx, cancel := context.WithTimeout(context.Background(), o.ContextTimeoutRead)
 		defer cancel()
 
-		return tbl.Select("*").WithDBR().IterateSerial(ctx, func(cm *dml.ColumnMap) error {
+		return tbl.Select("*").WithDBR(tbls.ConnPool.DB).IterateSerial(ctx, func(cm *dml.ColumnMap) error {
 			var ccd CoreConfiguration
 			if err := ccd.MapColumns(cm); err != nil {
 				return errors.Wrapf(err, "[config/storage] dbs.stmtAll.IterateSerial at row %d", cm.Count)
@@ -42,6 +42,8 @@ func main() {
 		dmlgen.WithTableConfig(
 			"core_configuration", &dmlgen.TableConfig{
 				UniquifiedColumns: []string{"path"},
+				// the scope column is a varchar to stay compatible with Magento.
+				EnumColumns: map[string][]string{"scope": {"default", "websites", "stores"}},
 				// `max_len` defines for Faker package the maximum size/length for
 				// a field. Only used during testing.
 				StructTags: []string{"max_len"},
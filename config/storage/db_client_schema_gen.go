@@ -43,6 +43,26 @@ type CoreConfiguration struct {
 	VersionTe time.Time   // version_te timestamp(6) NOT NULL PRI  INVISIBLE "Timestamp End Versioning"
 }
 
+// CoreConfigurationScope defines the allowed values of column
+// core_configuration.scope. Auto generated.
+type CoreConfigurationScope string
+
+const (
+	CoreConfigurationScopeDefault  CoreConfigurationScope = "default"
+	CoreConfigurationScopeWebsites CoreConfigurationScope = "websites"
+	CoreConfigurationScopeStores   CoreConfigurationScope = "stores"
+)
+
+// IsValid reports whether v is one of the allowed values of column scope. Auto
+// generated.
+func (v CoreConfigurationScope) IsValid() bool {
+	switch v {
+	case CoreConfigurationScopeDefault, CoreConfigurationScopeWebsites, CoreConfigurationScopeStores:
+		return true
+	}
+	return false
+}
+
 // WriteTo implements io.WriterTo and writes the field names and their values to
 // w. This is especially useful for debugging or or generating a hash of the
 // struct.
@@ -72,7 +92,7 @@ func (e *CoreConfiguration) MapColumns(cm *dml.ColumnMap) error {
 	if cm.Mode() == dml.ColumnMapEntityReadAll {
 		return cm.Uint64(&e.ID).String(&e.Scope).Int32(&e.ScopeID).NullTime(&e.Expires).String(&e.Path).NullString(&e.Value).Time(&e.VersionTs).Time(&e.VersionTe).Err()
 	}
-	for cm.Next() {
+	for cm.Next(8) {
 		switch c := cm.Column(); c {
 		case "id":
 			cm.Uint64(&e.ID)
@@ -160,7 +180,7 @@ func (cc *CoreConfigurationCollection) MapColumns(cm *dml.ColumnMap) error {
 		}
 		cc.Data = append(cc.Data, e)
 	case dml.ColumnMapCollectionReadSet:
-		for cm.Next() {
+		for cm.Next(0) {
 			switch c := cm.Column(); c {
 			case "id":
 				cm = cm.Uint64s(cc.IDs()...)
@@ -175,6 +195,21 @@ func (cc *CoreConfigurationCollection) MapColumns(cm *dml.ColumnMap) error {
 	return cm.Err()
 }
 
+// FilterByScope returns a new collection with the entities whose column scope
+// equals v. Auto generated.
+func (cc *CoreConfigurationCollection) FilterByScope(v CoreConfigurationScope) *CoreConfigurationCollection {
+	ret := &CoreConfigurationCollection{}
+	if cc == nil {
+		return ret
+	}
+	for _, e := range cc.Data {
+		if e != nil && e.Scope == string(v) {
+			ret.Data = append(ret.Data, e)
+		}
+	}
+	return ret
+}
+
 // IDs returns a slice with the data or appends it to a slice.
 // Auto generated.
 func (cc *CoreConfigurationCollection) IDs(ret ...uint64) []uint64 {
@@ -19,6 +19,14 @@ import (
 func TestNewTablesNonDB(t *testing.T) {
 	ps := pseudo.MustNewService(0, &pseudo.Options{Lang: "de", MaxFloatDecimals: 6})
 	_ = ps
+	t.Run("CoreConfigurationScope_IsValid", func(t *testing.T) {
+		assert.True(t, CoreConfigurationScopeDefault.IsValid())
+		assert.True(t, CoreConfigurationScopeWebsites.IsValid())
+		assert.True(t, CoreConfigurationScopeStores.IsValid())
+		assert.False(t, CoreConfigurationScope("invalid_scope").IsValid())
+		c := &CoreConfigurationCollection{Data: []*CoreConfiguration{{Scope: string(CoreConfigurationScopeDefault)}, {Scope: "invalid_scope"}, nil}}
+		assert.Len(t, c.FilterByScope(CoreConfigurationScopeDefault).Data, 1)
+	})
 }
 
 func TestNewTablesDB(t *testing.T) {
@@ -35,36 +43,32 @@ func TestNewTablesDB(t *testing.T) {
 	assert.NoError(t, err)
 	var ps *pseudo.Service
 	ps = pseudo.MustNewService(0, &pseudo.Options{Lang: "de", MaxFloatDecimals: 6},
-		pseudo.WithTagFakeFunc("website_id", func(maxLen int) (interface{}, error) {
-			return 1, nil
+		pseudo.WithTagFakeFunc("website_id", func(maxLen int) interface{} {
+			return 1
 		}),
-		pseudo.WithTagFakeFunc("store_id", func(maxLen int) (interface{}, error) {
-			return 1, nil
+		pseudo.WithTagFakeFunc("store_id", func(maxLen int) interface{} {
+			return 1
 		}),
 	)
 	t.Run("CoreConfiguration_Entity", func(t *testing.T) {
 		tbl := tbls.MustTable(TableNameCoreConfiguration)
-		entSELECT := tbl.SelectByPK("*")
-		// WithDBR generates the cached SQL string with empty key "".
-		entSELECTStmtA := entSELECT.WithDBR().ExpandPlaceHolders()
-		entSELECT.WithCacheKey("select_10").Wheres.Reset()
-		_, _, err := entSELECT.Where(
+		selOneRow := tbl.Select("*").Where(
+			dml.Column("id").Equal().PlaceHolder(),
+		)
+		selTenRows := tbl.Select("*").Where(
 			dml.Column("id").LessOrEqual().Int(10),
-		).ToSQL() // ToSQL generates the new cached SQL string with key select_10
-		assert.NoError(t, err)
-		entCol := NewCoreConfigurationCollection()
-		entINSERT := tbl.Insert().BuildValues()
-		entINSERTStmtA := entINSERT.PrepareWithDBR(ctx)
+		)
+		selOneRowDBR := tbls.ConnPool.WithPrepare(ctx, selOneRow)
+		defer selOneRowDBR.Close()
+		selTenRowsDBR := tbls.ConnPool.WithQueryBuilder(selTenRows)
+		entINSERTStmtA := tbls.ConnPool.WithPrepare(ctx, tbl.Insert().BuildValues())
 		for i := 0; i < 9; i++ {
 			entIn := new(CoreConfiguration)
-			if err := ps.FakeData(entIn); err != nil {
-				t.Errorf("IDX[%d]: %+v", i, err)
-				return
-			}
-			lID := dmltest.CheckLastInsertID(t, "Error: TestNewTables.CoreConfiguration_Entity")(entINSERTStmtA.Record("", entIn).ExecContext(ctx))
+			assert.NoError(t, ps.FakeData(entIn), "Error at index %d", i)
+			lID := dmltest.CheckLastInsertID(t, "Error: TestNewTables.CoreConfiguration_Entity")(entINSERTStmtA.ExecContext(ctx, dml.Qualify("", entIn)))
 			entINSERTStmtA.Reset()
 			entOut := new(CoreConfiguration)
-			rowCount, err := entSELECTStmtA.Int64s(lID).Load(ctx, entOut)
+			rowCount, err := selOneRowDBR.Load(ctx, entOut, lID)
 			assert.NoError(t, err)
 			assert.Exactly(t, uint64(1), rowCount, "IDX%d: RowCount did not match", i)
 			assert.Exactly(t, entIn.ID, entOut.ID, "IDX%d: ID should match", lID)
@@ -77,14 +81,12 @@ func TestNewTablesDB(t *testing.T) {
 			// ignoring: version_te
 		}
 		dmltest.Close(t, entINSERTStmtA)
-		rowCount, err := entSELECTStmtA.WithCacheKey("select_10").Load(ctx, entCol)
+		entCol := NewCoreConfigurationCollection()
+		rowCount, err := selTenRowsDBR.Load(ctx, entCol)
 		assert.NoError(t, err)
 		t.Logf("Collection load rowCount: %d", rowCount)
-		entINSERTStmtA = entINSERT.WithCacheKey("row_count_%d", len(entCol.Data)).Replace().SetRowCount(len(entCol.Data)).PrepareWithDBR(ctx)
-		lID := dmltest.CheckLastInsertID(t, "Error:  CoreConfigurationCollection ")(entINSERTStmtA.Record("", entCol).ExecContext(ctx))
-		dmltest.Close(t, entINSERTStmtA)
+		colInsertDBR := tbls.ConnPool.WithQueryBuilder(tbl.Insert().Replace().SetRowCount(len(entCol.Data)).BuildValues())
+		lID := dmltest.CheckLastInsertID(t, "Error:  CoreConfigurationCollection ")(colInsertDBR.ExecContext(ctx, dml.Qualify("", entCol)))
 		t.Logf("Last insert ID into: %d", lID)
-		t.Logf("INSERT queries: %#v", entINSERT.CachedQueries())
-		t.Logf("SELECT queries: %#v", entSELECT.CachedQueries())
 	})
 }
@@ -18,6 +18,7 @@ package storage_test
 
 import (
 	"context"
+	"database/sql/driver"
 	"fmt"
 	"testing"
 	"time"
@@ -104,7 +105,7 @@ func TestService_Get(t *testing.T) {
 	defer leaktest.CheckTimeout(t, time.Second)()
 
 	testBody := func(t *testing.T, dbs *storage.DB, dbMock sqlmock.Sqlmock, sleep time.Duration) {
-		prepSel := dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("SELECT `value` FROM `core_configuration` AS `main_table` WHERE (`scope` = ?) AND (`scope_id` = ?) AND (`path` = ?)"))
+		prepSel := dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("SELECT `value` FROM `core_configuration` AS `main_table` WHERE (`scope` = ?) AND (`scope_id` = ?) AND (`path` = ?) AND ((`expires` IS NULL) OR (`expires` > NOW())) ORDER BY `expires` DESC LIMIT 0,1"))
 		for _, test := range serviceMultiTests {
 			scp, sID := test.scopeID.Unpack()
 			prepSel.ExpectQuery().WithArgs(scp.StrType(), sID, test.path).WillReturnRows(sqlmock.NewRows([]string{"value"}))
@@ -117,7 +118,7 @@ func TestService_Get(t *testing.T) {
 
 		if sleep > 0 {
 			time.Sleep(sleep)
-			prepSel = dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("SELECT `value` FROM `core_configuration` AS `main_table` WHERE (`scope` = ?) AND (`scope_id` = ?) AND (`path` = ?)"))
+			prepSel = dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("SELECT `value` FROM `core_configuration` AS `main_table` WHERE (`scope` = ?) AND (`scope_id` = ?) AND (`path` = ?) AND ((`expires` IS NULL) OR (`expires` > NOW())) ORDER BY `expires` DESC LIMIT 0,1"))
 		}
 
 		for _, test := range serviceMultiTests {
@@ -190,7 +191,7 @@ func TestService_Get(t *testing.T) {
 		assert.NoError(t, err)
 		defer dmltest.Close(t, dbs)
 
-		prepSel := dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("SELECT `value` FROM `core_configuration` AS `main_table` WHERE (`scope` = ?) AND (`scope_id` = ?) AND (`path` = ?)"))
+		prepSel := dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("SELECT `value` FROM `core_configuration` AS `main_table` WHERE (`scope` = ?) AND (`scope_id` = ?) AND (`path` = ?) AND ((`expires` IS NULL) OR (`expires` > NOW())) ORDER BY `expires` DESC LIMIT 0,1"))
 		for _, test := range serviceMultiTests {
 			scp, sID := test.scopeID.Unpack()
 			prepSel.ExpectQuery().WithArgs(scp.StrType(), sID, test.path).WillDelayFor(time.Millisecond * 110).WillReturnRows(sqlmock.NewRows([]string{"value"}))
@@ -218,8 +219,9 @@ func TestService_Set(t *testing.T) {
 				prepIns = dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("INSERT INTO `core_configuration` (`scope`,`scope_id`,`path`,`value`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)"))
 			}
 
+			scp, sID := test.scopeID.Unpack()
 			prepIns.ExpectExec().
-				WithArgs(test.scopeID, test.path, test.value).
+				WithArgs(scp.StrType(), sID, test.path, test.value).
 				WillReturnResult(sqlmock.NewResult(j, 0))
 			assert.NoError(t, dbs.Set(config.MustMakePathWithScope(test.scopeID, test.path), test.value))
 
@@ -290,9 +292,9 @@ func TestService_Set(t *testing.T) {
 
 		prepIns := dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("INSERT INTO `core_configuration` (`scope`,`scope_id`,`path`,`value`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)"))
 		for i, test := range serviceMultiTests {
-
+			scp, sID := test.scopeID.Unpack()
 			prepIns.ExpectExec().
-				WithArgs(test.scopeID, test.path, test.value).
+				WithArgs(scp.StrType(), sID, test.path, test.value).
 				WillDelayFor(time.Millisecond * 110).
 				WillReturnResult(sqlmock.NewResult(int64(i), 0))
 			haveErr := dbs.Set(config.MustMakePathWithScope(test.scopeID, test.path), test.value)
@@ -303,6 +305,151 @@ func TestService_Set(t *testing.T) {
 	})
 }
 
+func newMockedDB(t *testing.T, o storage.DBOptions) (*storage.DB, sqlmock.Sqlmock, func()) {
+	dbc, dbMock := dmltest.MockDB(t)
+	dbMock.ExpectQuery("SELECT.+FROM information_schema.COLUMNS").WithArgs().WillReturnRows(
+		dmltest.MustMockRows(dmltest.WithFile("testdata", "core_configuration_columns.csv")),
+	)
+	o.SkipSchemaValidation = true
+	dbs, err := storage.NewDB(mustNewTables(context.TODO(), ddl.WithConnPool(dbc)), o)
+	assert.NoError(t, err)
+	return dbs, dbMock, func() {
+		dmltest.Close(t, dbs)
+		dmltest.MockClose(t, dbc, dbMock)
+	}
+}
+
+func TestService_SetMulti(t *testing.T) {
+	defer leaktest.CheckTimeout(t, time.Second)()
+
+	var scopes []string
+	var scopeIDs []uint32
+	var paths []string
+	var values [][]byte
+	var args []driver.Value
+	for _, test := range serviceMultiTests {
+		scp, sID := test.scopeID.Unpack()
+		scopes = append(scopes, scp.StrType())
+		scopeIDs = append(scopeIDs, sID)
+		paths = append(paths, test.path)
+		values = append(values, test.value)
+		args = append(args, scp.StrType(), sID, test.path, test.value)
+	}
+
+	t.Run("mismatched length", func(t *testing.T) {
+		dbs, _, closeFn := newMockedDB(t, storage.DBOptions{})
+		defer closeFn()
+		err := dbs.SetMulti(context.TODO(), scopes, scopeIDs[:1], paths, values)
+		assert.True(t, errors.Mismatch.Match(err), "%+v", err)
+	})
+
+	t.Run("invalid scope", func(t *testing.T) {
+		dbs, _, closeFn := newMockedDB(t, storage.DBOptions{})
+		defer closeFn()
+		err := dbs.SetMulti(context.TODO(), []string{"store"}, []uint32{1}, []string{"aa/bb/cc"}, [][]byte{nil})
+		assert.True(t, errors.NotValid.Match(err), "%+v", err)
+		err = dbs.SetScoped(context.TODO(), storage.CoreConfigurationScope("website"), 1, "aa/bb/cc", nil)
+		assert.True(t, errors.NotValid.Match(err), "%+v", err)
+		_, _, err = dbs.GetScoped(context.TODO(), "", 0, "aa/bb/cc")
+		assert.True(t, errors.NotValid.Match(err), "%+v", err)
+	})
+
+	t.Run("single statement", func(t *testing.T) {
+		dbs, dbMock, closeFn := newMockedDB(t, storage.DBOptions{})
+		defer closeFn()
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `core_configuration` (`scope`,`scope_id`,`path`,`value`) VALUES (?,?,?,?),(?,?,?,?),(?,?,?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)")).
+			WithArgs(args...).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dbs.SetMulti(context.TODO(), scopes, scopeIDs, paths, values))
+	})
+
+	t.Run("chunks and rollback", func(t *testing.T) {
+		dbs, dbMock, closeFn := newMockedDB(t, storage.DBOptions{MultiMaxBytes: 10})
+		defer closeFn()
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `core_configuration` (`scope`,`scope_id`,`path`,`value`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)")).
+			WithArgs(args[:4]...).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `core_configuration` (`scope`,`scope_id`,`path`,`value`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)")).
+			WithArgs(args[4:8]...).
+			WillReturnError(errors.ConnectionLost.Newf("Upssss"))
+		dbMock.ExpectRollback()
+
+		err := dbs.SetMulti(context.TODO(), scopes, scopeIDs, paths, values)
+		assert.True(t, errors.ConnectionLost.Match(err), "%+v", err)
+	})
+}
+
+func TestDB_DeleteMatching(t *testing.T) {
+	dbs, dbMock, closeFn := newMockedDB(t, storage.DBOptions{})
+	defer closeFn()
+
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("DELETE FROM `core_configuration` WHERE (`path` REGEXP ?)")).
+		WithArgs("^carriers/[^/]+/.+$").
+		WillReturnResult(sqlmock.NewResult(0, 5))
+
+	deleted, err := dbs.DeleteMatching(context.TODO(), "carriers/*/**")
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(5), deleted)
+
+	_, err = dbs.DeleteMatching(context.TODO(), "carriers/**/active")
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
+
+func TestDB_HealthCheck(t *testing.T) {
+	dbs, dbMock, closeFn := newMockedDB(t, storage.DBOptions{})
+	defer closeFn()
+
+	prep := dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("SELECT `value` FROM `core_configuration` AS `main_table` WHERE (`scope` = ?) AND (`scope_id` = ?) AND (`path` = ?) AND ((`expires` IS NULL) OR (`expires` > NOW())) ORDER BY `expires` DESC LIMIT 0,1"))
+	prep.ExpectQuery().WithArgs("default", uint32(0), "health/check/probe").
+		WillReturnRows(sqlmock.NewRows([]string{"value"}))
+	prep.ExpectQuery().WithArgs("default", uint32(0), "health/check/probe").
+		WillReturnError(errors.ConnectionLost.Newf("Upssss"))
+
+	assert.NoError(t, dbs.HealthCheck(context.TODO()))
+	assert.ErrorIsKind(t, errors.ConnectionLost, dbs.HealthCheck(context.TODO()))
+}
+
+func TestCoreConfiguration_SetScope(t *testing.T) {
+	var ccd storage.CoreConfiguration
+	assert.NoError(t, ccd.SetScope(storage.CoreConfigurationScopeWebsites, 3))
+	assert.Exactly(t, "websites", ccd.Scope)
+	assert.Exactly(t, int32(3), ccd.ScopeID)
+
+	err := ccd.SetScope("website", 4)
+	assert.True(t, errors.NotValid.Match(err), "%+v", err)
+	assert.Exactly(t, "websites", ccd.Scope)
+	assert.Exactly(t, int32(3), ccd.ScopeID)
+}
+
+func TestService_GetMulti(t *testing.T) {
+	defer leaktest.CheckTimeout(t, time.Second)()
+
+	dbs, dbMock, closeFn := newMockedDB(t, storage.DBOptions{})
+	defer closeFn()
+
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `scope`, `scope_id`, `path`, `value` FROM `core_configuration` AS `main_table` WHERE ((`scope`, `scope_id`, `path`) IN ((?,?,?),(?,?,?),(?,?,?))) AND ((`expires` IS NULL) OR (`expires` > NOW())) ORDER BY `expires` DESC")).
+		WithArgs("websites", uint32(10), "testService/secure/base_url", "stores", uint32(9), "testService/log/active", "default", uint32(0), "testService/not/found").
+		WillReturnRows(sqlmock.NewRows([]string{"scope", "scope_id", "path", "value"}).
+			AddRow("stores", 9, "testService/log/active", nil).
+			AddRow("websites", 10, "testService/secure/base_url", "http://corestore.io"),
+		)
+
+	values, found, err := dbs.GetMulti(context.TODO(),
+		[]string{"websites", "stores", "default", "websites"},
+		[]uint32{10, 9, 0, 10},
+		[]string{"testService/secure/base_url", "testService/log/active", "testService/not/found", "testService/secure/base_url"},
+	)
+	assert.NoError(t, err)
+	assert.Exactly(t, []bool{true, true, false, true}, found)
+	assert.Exactly(t, [][]byte{[]byte("http://corestore.io"), nil, nil, []byte("http://corestore.io")}, values)
+}
+
 // Test_WithApplyCoreConfigData reads from the MySQL core_configuration table and applies
 // these value to the underlying storage. tries to get back the values from the
 // underlying storage
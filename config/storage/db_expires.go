@@ -0,0 +1,143 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build csall db
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/sql/dml"
+)
+
+// dbJanitorBatchSize defines the maximum amount of rows a single DELETE
+// statement of the janitor removes, to keep the locks short.
+const dbJanitorBatchSize = 100
+
+// dbNotExpired returns the conditions to filter out expired rows. A row expires
+// once the database time reaches the value of column expires, hence a row
+// whose expires equals the current second is already expired. All expiration
+// checks use the clock of the database server.
+func dbNotExpired() []*dml.Condition {
+	return []*dml.Condition{
+		dml.ParenthesisOpen(),
+		dml.Column("expires").Null(),
+		dml.Column("expires").Greater().Expr("NOW()").Or(),
+		dml.ParenthesisClose(),
+	}
+}
+
+// SetWithTTL writes a temporary value which expires after the duration ttl,
+// calculated from the current database time. The ttl gets rounded up to full
+// seconds. As long as the value has not expired, it takes precedence over the
+// value without expiration of the same path. A previous temporary value of the
+// same path gets replaced, even if it expires later. Use it for maintenance
+// banners or feature flags.
+func (dbs *DB) SetWithTTL(ctx context.Context, scope string, scopeID uint32, path string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		return errors.NotValid.Newf("[config/storage] DB.SetWithTTL: ttl cannot be negative, have %s", ttl)
+	}
+	seconds := int64((ttl + time.Second - 1) / time.Second)
+
+	qry := dml.QuerySQL(fmt.Sprintf(
+		"INSERT INTO `%s` (`scope`,`scope_id`,`expires`,`path`,`value`) VALUES (?,?,NOW() + INTERVAL ? SECOND,?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)",
+		dbs.tbl.Name))
+	// The column expires is part of the unique key, hence the previous
+	// temporary rows must be deleted, otherwise they would take precedence
+	// until they expire.
+	del := dbs.tbl.Delete().Where(
+		dml.Column("scope").PlaceHolder(),
+		dml.Column("scope_id").PlaceHolder(),
+		dml.Column("path").PlaceHolder(),
+		dml.Column("expires").NotNull(),
+	)
+	err := dbs.connPool.Transaction(ctx, nil, func(tx *dml.Tx) error {
+		if _, err := tx.WithQueryBuilder(del).ExecContext(ctx, scope, scopeID, path); err != nil {
+			return errors.WithStack(err)
+		}
+		res, err := tx.WithQueryBuilder(qry).ExecContext(ctx, scope, scopeID, seconds, path, value)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if dbs.cfg.Log != nil && dbs.cfg.Log.IsDebug() {
+			li, err1 := res.LastInsertId()
+			dbs.cfg.Log.Debug(
+				"config.storage.DB.SetWithTTL.Write.Result",
+				log.Int64("lastInsertID", li),
+				log.ErrWithKey("lastInsertIDErr", err1),
+				log.String("scope", scope),
+				log.Uint("scope_id", uint(scopeID)),
+				log.String("path", path),
+				log.Duration("ttl", ttl),
+			)
+		}
+		return nil
+	})
+	return errors.Wrapf(err, "[config/storage] DB.SetWithTTL Scope %q ID %d Path %q", scope, scopeID, path)
+}
+
+// DeleteExpired deletes all expired rows in batches and returns the number of
+// deleted rows.
+func (dbs *DB) DeleteExpired(ctx context.Context) (deleted int64, err error) {
+	del := dbs.tbl.Delete().Where(
+		dml.Column("expires").LessOrEqual().Expr("NOW()"),
+	).Limit(dbJanitorBatchSize)
+
+	dbr := dbs.connPool.WithQueryBuilder(del)
+	for {
+		res, err := dbr.ExecContext(ctx)
+		if err != nil {
+			return deleted, errors.Wrapf(err, "[config/storage] DB.DeleteExpired after %d deleted rows", deleted)
+		}
+		ra, err := res.RowsAffected()
+		if err != nil {
+			return deleted, errors.WithStack(err)
+		}
+		deleted += ra
+		if ra < dbJanitorBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// Janitor deletes every interval the expired rows until the context gets
+// cancelled. Errors get logged as Info message and the janitor continues with
+// the next interval. Janitor blocks and returns nil once the context has been
+// cancelled.
+func (dbs *DB) Janitor(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.NotValid.Newf("[config/storage] DB.Janitor: interval must be greater than zero, have %s", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		deleted, err := dbs.DeleteExpired(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if dbs.cfg.Log != nil && dbs.cfg.Log.IsInfo() && (err != nil || deleted > 0) {
+			dbs.cfg.Log.Info("config.storage.DB.Janitor", log.Err(err), log.Int64("deleted", deleted))
+		}
+	}
+}
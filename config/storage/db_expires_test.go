@@ -0,0 +1,97 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build csall db
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestDB_SetWithTTL_Mocked(t *testing.T) {
+	dbs, dbMock, closeFn := newMockedDB(t, storage.DBOptions{})
+	defer closeFn()
+
+	t.Run("rounds up to seconds", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("DELETE FROM `core_configuration` WHERE (`scope` = ?) AND (`scope_id` = ?) AND (`path` = ?) AND (`expires` IS NOT NULL)")).
+			WithArgs("stores", uint32(2), "maintenance/banner/text").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `core_configuration` (`scope`,`scope_id`,`expires`,`path`,`value`) VALUES (?,?,NOW() + INTERVAL ? SECOND,?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)")).
+			WithArgs("stores", uint32(2), int64(2), "maintenance/banner/text", []byte("Back soon")).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectCommit()
+		assert.NoError(t, dbs.SetWithTTL(context.TODO(), "stores", 2, "maintenance/banner/text", []byte("Back soon"), 1500*time.Millisecond))
+	})
+	t.Run("negative ttl", func(t *testing.T) {
+		err := dbs.SetWithTTL(context.TODO(), "stores", 2, "maintenance/banner/text", nil, -time.Second)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
+
+func TestDB_DeleteExpired_Mocked(t *testing.T) {
+	dbs, dbMock, closeFn := newMockedDB(t, storage.DBOptions{})
+	defer closeFn()
+
+	const delSQL = "DELETE FROM `core_configuration` WHERE (`expires` <= NOW()) LIMIT 100"
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(delSQL)).WillReturnResult(sqlmock.NewResult(0, 100))
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(delSQL)).WillReturnResult(sqlmock.NewResult(0, 7))
+
+	deleted, err := dbs.DeleteExpired(context.TODO())
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(107), deleted)
+}
+
+func TestDB_Expires_Live(t *testing.T) {
+	db := dmltest.MustConnectDB(t)
+	defer dmltest.Close(t, db)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	dbs, err := storage.NewDB(mustNewTables(ctx, ddl.WithConnPool(db)), storage.DBOptions{})
+	assert.NoError(t, err)
+	defer dmltest.Close(t, dbs)
+
+	const route = "dbexpires/live/test"
+	_, err = db.DB.ExecContext(ctx, "DELETE FROM `core_configuration` WHERE `path`=?", route)
+	assert.NoError(t, err)
+
+	p := config.MustMakePathWithScope(scope.Store.WithID(4), route)
+	assert.NoError(t, dbs.Set(p, []byte("permanent")))
+
+	assert.NoError(t, dbs.SetWithTTL(ctx, "stores", 4, route, []byte("temporary"), 3*time.Second))
+	validateFoundGet(t, dbs, scope.Store.WithID(4), route, "temporary")
+
+	// The new temporary value replaces the previous one. A ttl of zero sets
+	// expires to the current second, which counts as expired.
+	assert.NoError(t, dbs.SetWithTTL(ctx, "stores", 4, route, []byte("expired"), 0))
+	validateFoundGet(t, dbs, scope.Store.WithID(4), route, "permanent")
+
+	deleted, err := dbs.DeleteExpired(ctx)
+	assert.NoError(t, err)
+	assert.True(t, deleted >= 1, "Should delete at least one row, have %d", deleted)
+}
@@ -0,0 +1,273 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build csall db
+
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/storage/null"
+)
+
+// dbPollerMaxBackoff defines the maximum wait time between two polls after
+// database errors.
+const dbPollerMaxBackoff = time.Minute
+
+// dbPathKey identifies a row in table core_configuration.
+type dbPathKey struct {
+	scope   string
+	scopeID uint32
+	path    string
+}
+
+// DBPoller detects changes in the table core_configuration by polling the
+// column version_ts of the system versioned table. Each changed path gets
+// passed to the registered subscribers, so a config.Service can invalidate its
+// cache without a restart. Rows whose column expires has been reached get
+// reported as a change with an invalid null.String, which means the
+// subscriber should invalidate the path and read it again. Deleted rows cannot
+// be detected.
+type DBPoller struct {
+	cfg    DBOptions
+	dbr    *dml.DBR
+	dbrMax *dml.DBR
+	// dbrExpired and dbrMaxExpired detect rows which have expired since the
+	// last poll.
+	dbrExpired    *dml.DBR
+	dbrMaxExpired *dml.DBR
+	interval      time.Duration
+
+	mu          sync.RWMutex
+	subscribers []DBPollerSubscriber
+	lastVersion time.Time
+	lastExpires time.Time
+	lastSync    time.Time
+}
+
+// NewDBPoller creates a new poller which queries every interval the changed
+// rows of table core_configuration. The optional DBOptions can set the table
+// name, the logger and the read timeout. Call Run to start the polling.
+func NewDBPoller(tbls *ddl.Tables, interval time.Duration, o ...DBOptions) (*DBPoller, error) {
+	var cfg DBOptions
+	if len(o) == 1 {
+		cfg = o[0]
+	}
+	tn := cfg.TableName
+	if tn == "" {
+		tn = TableNameCoreConfiguration
+	}
+	if cfg.ContextTimeoutRead == 0 {
+		cfg.ContextTimeoutRead = time.Second * 10 // just a guess
+	}
+	if interval <= 0 {
+		return nil, errors.NotValid.Newf("[config/storage] NewDBPoller: interval must be greater than zero, have %s", interval)
+	}
+
+	tbl, err := tbls.Table(tn)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if tbls.ConnPool == nil {
+		return nil, errors.NotValid.Newf("[config/storage] NewDBPoller: ddl.Tables requires a ConnPool")
+	}
+
+	qryChanged := tbl.Select("scope", "scope_id", "path", "value", "version_ts").
+		Where(dml.Column("version_ts").Greater().PlaceHolder()).
+		OrderBy("version_ts")
+
+	qryMax := tbl.Select("version_ts").OrderByDesc("version_ts").Limit(0, 1)
+
+	qryExpired := tbl.Select("scope", "scope_id", "path", "expires").
+		Where(
+			dml.Column("expires").Greater().PlaceHolder(),
+			dml.Column("expires").LessOrEqual().Expr("NOW()"),
+		).
+		OrderBy("expires")
+
+	qryMaxExpired := tbl.Select("expires").
+		Where(dml.Column("expires").LessOrEqual().Expr("NOW()")).
+		OrderByDesc("expires").Limit(0, 1)
+
+	return &DBPoller{
+		cfg:           cfg,
+		dbr:           tbls.ConnPool.WithQueryBuilder(qryChanged),
+		dbrMax:        tbls.ConnPool.WithQueryBuilder(qryMax),
+		dbrExpired:    tbls.ConnPool.WithQueryBuilder(qryExpired),
+		dbrMaxExpired: tbls.ConnPool.WithQueryBuilder(qryMaxExpired),
+		interval:      interval,
+	}, nil
+}
+
+// Subscribe adds a new function which gets called for each changed path.
+// Subscribers get called sequentially in the order of registration.
+func (p *DBPoller) Subscribe(fn DBPollerSubscriber) {
+	p.mu.Lock()
+	p.subscribers = append(p.subscribers, fn)
+	p.mu.Unlock()
+}
+
+// LastSync returns the time of the last successful poll. A zero time indicates
+// that no poll has succeeded yet. Useful for health checks.
+func (p *DBPoller) LastSync() time.Time {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastSync
+}
+
+// Run polls the database until the context gets cancelled. The first poll
+// only determines the current version, so changes before calling Run are not
+// reported. Database errors get logged and the next poll gets delayed with an
+// exponential backoff up to one minute. Run blocks and returns nil once the
+// context has been cancelled.
+func (p *DBPoller) Run(ctx context.Context) error {
+	var failures uint
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		wait := p.interval
+		if err := p.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			failures++
+			wait = p.backoff(failures)
+			if p.cfg.Log != nil && p.cfg.Log.IsInfo() {
+				p.cfg.Log.Info("config.storage.DBPoller.Poll", log.Err(err), log.Uint("failures", failures), log.Duration("wait", wait))
+			}
+		} else {
+			failures = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+func (p *DBPoller) backoff(failures uint) time.Duration {
+	wait := p.interval
+	for i := uint(0); i < failures && wait < dbPollerMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > dbPollerMaxBackoff {
+		wait = dbPollerMaxBackoff
+	}
+	return wait
+}
+
+// Poll queries once the changed and the expired rows and calls the
+// subscribers. Multiple changes of the same path get coalesced, so each
+// subscriber receives only the latest value per path. An expiration gets
+// reported after the changes and overrides a change of the same path. The
+// first call only determines the current version. Usually there is no need to
+// call Poll directly, see Run.
+func (p *DBPoller) Poll(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.ContextTimeoutRead)
+	defer cancel()
+
+	p.mu.RLock()
+	lastVersion := p.lastVersion
+	lastExpires := p.lastExpires
+	p.mu.RUnlock()
+
+	if lastVersion.IsZero() {
+		nt, _, err := p.dbrMax.LoadNullTime(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		ne, _, err := p.dbrMaxExpired.LoadNullTime(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		p.mu.Lock()
+		p.lastVersion = nt.Time
+		if nt.Time.IsZero() {
+			p.lastVersion = time.Unix(0, 0) // empty table
+		}
+		p.lastExpires = ne.Time
+		if ne.Time.IsZero() {
+			p.lastExpires = time.Unix(0, 0) // nothing expired yet
+		}
+		p.lastSync = time.Now()
+		p.mu.Unlock()
+		return nil
+	}
+
+	var keys []dbPathKey
+	changes := make(map[dbPathKey]null.String)
+	newVersion := lastVersion
+	err := p.dbr.IterateSerial(ctx, func(cm *dml.ColumnMap) error {
+		var ccd CoreConfiguration
+		if err := ccd.MapColumns(cm); err != nil {
+			return errors.Wrapf(err, "[config/storage] DBPoller.Poll at row %d", cm.Count)
+		}
+		k := dbPathKey{scope: ccd.Scope, scopeID: uint32(ccd.ScopeID), path: ccd.Path}
+		if _, ok := changes[k]; !ok {
+			keys = append(keys, k)
+		}
+		changes[k] = ccd.Value
+		if ccd.VersionTs.After(newVersion) {
+			newVersion = ccd.VersionTs
+		}
+		return nil
+	}, lastVersion)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	newExpires := lastExpires
+	err = p.dbrExpired.IterateSerial(ctx, func(cm *dml.ColumnMap) error {
+		var ccd CoreConfiguration
+		if err := ccd.MapColumns(cm); err != nil {
+			return errors.Wrapf(err, "[config/storage] DBPoller.Poll expired at row %d", cm.Count)
+		}
+		k := dbPathKey{scope: ccd.Scope, scopeID: uint32(ccd.ScopeID), path: ccd.Path}
+		if _, ok := changes[k]; !ok {
+			keys = append(keys, k)
+		}
+		changes[k] = null.String{}
+		if ccd.Expires.Time.After(newExpires) {
+			newExpires = ccd.Expires.Time
+		}
+		return nil
+	}, lastExpires)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	p.mu.Lock()
+	p.lastVersion = newVersion
+	p.lastExpires = newExpires
+	p.lastSync = time.Now()
+	subscribers := p.subscribers
+	p.mu.Unlock()
+
+	for _, k := range keys {
+		for _, fn := range subscribers {
+			fn(k.scope, k.scopeID, k.path, changes[k])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build csall db
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestNewDBPoller_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("table not found", func(t *testing.T) {
+		p, err := storage.NewDBPoller(mustNewTables(context.TODO()), time.Second, storage.DBOptions{TableName: "non-existent"})
+		assert.Nil(t, p)
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+	t.Run("invalid interval", func(t *testing.T) {
+		p, err := storage.NewDBPoller(mustNewTables(context.TODO()), 0)
+		assert.Nil(t, p)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
+
+func TestDBPoller_Live(t *testing.T) {
+	db := dmltest.MustConnectDB(t)
+	defer dmltest.Close(t, db)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tbls := mustNewTables(ctx, ddl.WithConnPool(db))
+
+	const path = "dbpoller/live/test"
+	_, err := db.DB.ExecContext(ctx, "DELETE FROM `core_configuration` WHERE `path`=?", path)
+	assert.NoError(t, err)
+	_, err = db.DB.ExecContext(ctx, "INSERT INTO `core_configuration` (`scope`,`scope_id`,`path`,`value`) VALUES ('stores',3,?,'v1')", path)
+	assert.NoError(t, err)
+
+	const interval = 200 * time.Millisecond
+	p, err := storage.NewDBPoller(tbls, interval)
+	assert.NoError(t, err)
+
+	type change struct {
+		scope   string
+		scopeID uint32
+		path    string
+		value   null.String
+	}
+	changes := make(chan change, 10)
+	p.Subscribe(func(scope string, scopeID uint32, path string, value null.String) {
+		changes <- change{scope: scope, scopeID: scopeID, path: path, value: value}
+	})
+
+	go func() { assert.NoError(t, p.Run(ctx)) }()
+
+	for p.LastSync().IsZero() {
+		time.Sleep(interval / 10)
+	}
+
+	_, err = db.DB.ExecContext(ctx, "UPDATE `core_configuration` SET `value`='v2' WHERE `path`=?", path)
+	assert.NoError(t, err)
+
+	select {
+	case c := <-changes:
+		assert.Exactly(t, change{scope: "stores", scopeID: 3, path: path, value: null.MakeString("v2")}, c)
+	case <-time.After(interval + time.Second):
+		t.Fatal("subscriber has not been called within one polling interval")
+	}
+	assert.False(t, p.LastSync().IsZero())
+}
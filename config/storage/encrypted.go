@@ -0,0 +1,167 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+)
+
+// encryptedVersionAESGCM identifies values encrypted with AES-256-GCM. The
+// version byte allows to change the algorithm in the future. Plain text values
+// never start with this control character.
+const encryptedVersionAESGCM byte = 0x01
+
+// Encrypted encrypts the values of selected paths before writing them into the
+// wrapped storage and decrypts them while reading. The stored bytes have the
+// format: version byte, nonce and the sealed value. Values stored before the
+// encryption has been enabled are returned as they are and get encrypted with
+// the next Set. Encrypted implements config.Storager and is safe for
+// concurrent use.
+type Encrypted struct {
+	inner       config.Storager
+	pathMatcher func(route string) bool
+	// aeads contains all keys. The first key encrypts, all keys decrypt.
+	aeads []cipher.AEAD
+}
+
+// NewEncrypted creates a new decorator which encrypts the values of the paths
+// selected by pathMatcher with AES-256-GCM. pathMatcher receives the route,
+// e.g. "payment/stripe/secret_key". A nil pathMatcher encrypts all paths. Use
+// config.RouteMatcher to select the paths with glob-style patterns like
+// "payment/*/secret_key".
+// Argument key must have a length of 32 bytes and gets used for encryption and
+// decryption. The optional oldKeys get only used for decryption, which allows
+// a key rotation: add the new key as first argument and move the current key to
+// oldKeys.
+func NewEncrypted(inner config.Storager, key []byte, pathMatcher func(route string) bool, oldKeys ...[]byte) (*Encrypted, error) {
+	e := &Encrypted{
+		inner:       inner,
+		pathMatcher: pathMatcher,
+		aeads:       make([]cipher.AEAD, 0, len(oldKeys)+1),
+	}
+	for i, k := range append([][]byte{key}, oldKeys...) {
+		if len(k) != 32 {
+			return nil, errors.NotValid.Newf("[config/storage] NewEncrypted: Key at index %d must have a length of 32 bytes, have %d", i, len(k))
+		}
+		block, err := aes.NewCipher(k)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		e.aeads = append(e.aeads, aead)
+	}
+	return e, nil
+}
+
+func (e *Encrypted) isEncrypted(p config.Path) bool {
+	if e.pathMatcher == nil {
+		return true
+	}
+	_, route := p.ScopeRoute()
+	return e.pathMatcher(route)
+}
+
+// Set encrypts the value if the path matches and writes it into the wrapped
+// storage. A nil value does not get encrypted.
+func (e *Encrypted) Set(p config.Path, value []byte) error {
+	if value == nil || !e.isEncrypted(p) {
+		return e.inner.Set(p, value)
+	}
+	aead := e.aeads[0]
+	buf := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(value)+aead.Overhead())
+	buf[0] = encryptedVersionAESGCM
+	nonce := buf[1:]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return errors.WithStack(err)
+	}
+	return e.inner.Set(p, aead.Seal(buf, nonce, value, nil))
+}
+
+// Get reads the value from the wrapped storage and decrypts it if the path
+// matches. A value which does not start with the version byte gets treated as
+// plain text, written before the encryption has been enabled. A value which
+// cannot be decrypted with any key returns a DecryptionFailed error.
+func (e *Encrypted) Get(p config.Path) (v []byte, found bool, err error) {
+	v, found, err = e.inner.Get(p)
+	if err != nil || v == nil || !e.isEncrypted(p) {
+		return v, found, err
+	}
+	if v, err = e.decrypt(p, v); err != nil {
+		return nil, false, err
+	}
+	return v, found, nil
+}
+
+func (e *Encrypted) decrypt(p config.Path, v []byte) ([]byte, error) {
+	if len(v) == 0 || v[0] != encryptedVersionAESGCM {
+		return v, nil // plain text
+	}
+	for _, aead := range e.aeads {
+		ns := 1 + aead.NonceSize()
+		if len(v) < ns+aead.Overhead() {
+			break
+		}
+		if plain, err := aead.Open(nil, v[1:ns], v[ns:], nil); err == nil {
+			return plain, nil
+		}
+	}
+	return nil, errors.DecryptionFailed.Newf("[config/storage] Encrypted.Get: Failed to decrypt value of path %q", p.String())
+}
+
+// Delete passes the path to the wrapped storage, if it supports deletion.
+func (e *Encrypted) Delete(p config.Path) error {
+	d, ok := e.inner.(interface{ Delete(config.Path) error })
+	if !ok {
+		return errors.NotSupported.Newf("[config/storage] Encrypted.Delete: %T does not support Delete", e.inner)
+	}
+	return d.Delete(p)
+}
+
+// Truncate passes the call to the wrapped storage, if it supports truncation.
+func (e *Encrypted) Truncate() error {
+	t, ok := e.inner.(interface{ Truncate() error })
+	if !ok {
+		return errors.NotSupported.Newf("[config/storage] Encrypted.Truncate: %T does not support Truncate", e.inner)
+	}
+	return t.Truncate()
+}
+
+// Iterate implements config.Iterator and passes the decrypted values to fn, if
+// the wrapped storage supports iterating.
+func (e *Encrypted) Iterate(ctx context.Context, fn func(p config.Path, v []byte) error) error {
+	it, ok := e.inner.(config.Iterator)
+	if !ok {
+		return errors.NotSupported.Newf("[config/storage] Encrypted.Iterate: %T does not support Iterate", e.inner)
+	}
+	return it.Iterate(ctx, func(p config.Path, v []byte) error {
+		if v != nil && e.isEncrypted(p) {
+			var err error
+			if v, err = e.decrypt(p, v); err != nil {
+				return err
+			}
+		}
+		return fn(p, v)
+	})
+}
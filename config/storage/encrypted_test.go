@@ -0,0 +1,114 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var _ config.Storager = (*storage.Encrypted)(nil)
+
+func TestEncrypted(t *testing.T) {
+	key1 := bytes.Repeat([]byte{'1'}, 32)
+	key2 := bytes.Repeat([]byte{'2'}, 32)
+	isSecret := func(route string) bool { return strings.HasSuffix(route, "_key") }
+
+	pSecret := config.MustMakePathWithScope(scope.Website.WithID(1), "payment/stripe/secret_key")
+	pTitle := config.MustMakePathWithScope(scope.Website.WithID(1), "payment/stripe/title")
+
+	t.Run("invalid key", func(t *testing.T) {
+		e, err := storage.NewEncrypted(storage.NewMap(), key1, isSecret, []byte("short"))
+		assert.Nil(t, e)
+		assert.True(t, errors.NotValid.Match(err), "%+v", err)
+	})
+
+	t.Run("encrypt matched paths only", func(t *testing.T) {
+		inner := storage.NewMap()
+		e, err := storage.NewEncrypted(inner, key1, isSecret)
+		assert.NoError(t, err)
+
+		assert.NoError(t, e.Set(pSecret, []byte("sk_live_123")))
+		assert.NoError(t, e.Set(pTitle, []byte("Stripe")))
+
+		raw, ok, err := inner.Get(pSecret)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.False(t, bytes.Contains(raw, []byte("sk_live_123")), "value must be encrypted")
+
+		validateFoundGet(t, e, scope.Website.WithID(1), "payment/stripe/secret_key", "sk_live_123")
+		validateFoundGet(t, inner, scope.Website.WithID(1), "payment/stripe/title", "Stripe")
+		validateFoundGet(t, e, scope.Website.WithID(1), "payment/stripe/title", "Stripe")
+		validateNotFoundGet(t, e, scope.Website.WithID(2), "payment/stripe/secret_key")
+	})
+
+	t.Run("random nonce", func(t *testing.T) {
+		inner := storage.NewMap()
+		e, err := storage.NewEncrypted(inner, key1, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, e.Set(pSecret, []byte("sk_live_123")))
+		raw1, _, _ := inner.Get(pSecret)
+		assert.NoError(t, e.Set(pSecret, []byte("sk_live_123")))
+		raw2, _, _ := inner.Get(pSecret)
+		assert.NotEqual(t, raw1, raw2)
+	})
+
+	t.Run("plain text legacy value", func(t *testing.T) {
+		inner := storage.NewMap("websites/1/payment/stripe/secret_key", "sk_legacy")
+		e, err := storage.NewEncrypted(inner, key1, isSecret)
+		assert.NoError(t, err)
+		validateFoundGet(t, e, scope.Website.WithID(1), "payment/stripe/secret_key", "sk_legacy")
+
+		assert.NoError(t, e.Set(pSecret, []byte("sk_legacy")))
+		raw, _, err := inner.Get(pSecret)
+		assert.NoError(t, err)
+		assert.NotEqual(t, []byte("sk_legacy"), raw, "value must be encrypted after Set")
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		inner := storage.NewMap()
+		eOld, err := storage.NewEncrypted(inner, key1, isSecret)
+		assert.NoError(t, err)
+		assert.NoError(t, eOld.Set(pSecret, []byte("sk_old")))
+
+		eNew, err := storage.NewEncrypted(inner, key2, isSecret, key1)
+		assert.NoError(t, err)
+		validateFoundGet(t, eNew, scope.Website.WithID(1), "payment/stripe/secret_key", "sk_old")
+		assert.NoError(t, eNew.Set(pSecret, []byte("sk_new")))
+
+		eOnlyNew, err := storage.NewEncrypted(inner, key2, isSecret)
+		assert.NoError(t, err)
+		validateFoundGet(t, eOnlyNew, scope.Website.WithID(1), "payment/stripe/secret_key", "sk_new")
+
+		v, ok, err := eOld.Get(pSecret)
+		assert.Nil(t, v)
+		assert.False(t, ok)
+		assert.True(t, errors.DecryptionFailed.Match(err), "%+v", err)
+	})
+
+	t.Run("Delete not supported", func(t *testing.T) {
+		e, err := storage.NewEncrypted(storage.NewMap(), key1, isSecret)
+		assert.NoError(t, err)
+		assert.True(t, errors.NotSupported.Match(e.Delete(pSecret)))
+		assert.True(t, errors.NotSupported.Match(e.Truncate()))
+	})
+}
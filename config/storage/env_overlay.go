@@ -0,0 +1,151 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+)
+
+// EnvironmentMatch describes an environment variable which starts with the
+// prefix of an EnvironmentOverlay.
+type EnvironmentMatch struct {
+	// Name of the environment variable as found in the environment.
+	Name string
+	// Path contains the parsed path. Empty if Err is set.
+	Path config.Path
+	// Err contains the error if the name cannot be parsed into a path.
+	Err error
+}
+
+// EnvironmentOverlay overrides the values of a wrapped storage with values from
+// environment variables. The names of the environment variables get matched
+// case-insensitive and `__` separates the parts of a path:
+//		CONFIG__STORES__1__WEB__SECURE__BASE_URL => stores/1/web/secure/base_url
+//		CONFIG__WEB__SECURE__BASE_URL => default/0/web/secure/base_url
+// The environment gets read once during construction and again with each call
+// to Reload. EnvironmentOverlay implements config.Storager and is safe for
+// concurrent use.
+type EnvironmentOverlay struct {
+	prefix string
+	inner  config.Storager
+
+	mu      sync.RWMutex
+	values  map[cacheKey][]byte
+	matches []EnvironmentMatch
+}
+
+// NewEnvironmentOverlay creates a new storage which checks the environment
+// variables before delegating to the inner storage. An empty prefix falls back
+// to the constant Prefix.
+func NewEnvironmentOverlay(prefix string, inner config.Storager) *EnvironmentOverlay {
+	if prefix == "" {
+		prefix = Prefix
+	}
+	eo := &EnvironmentOverlay{
+		prefix: strings.ToUpper(prefix),
+		inner:  inner,
+	}
+	eo.Reload()
+	return eo
+}
+
+// Reload takes a new snapshot of the environment variables.
+func (eo *EnvironmentOverlay) Reload() {
+	values := make(map[cacheKey][]byte)
+	var matches []EnvironmentMatch
+	for _, ev := range os.Environ() {
+		equalPos := strings.IndexByte(ev, '=')
+		if equalPos < 0 {
+			continue
+		}
+		name, val := ev[:equalPos], ev[equalPos+1:]
+		upperName := strings.ToUpper(name)
+		if !strings.HasPrefix(upperName, eo.prefix) {
+			continue
+		}
+		m := EnvironmentMatch{Name: name}
+		if isEnvVarAllowed(eo.prefix, upperName) {
+			m.Path, m.Err = FromEnvVar(eo.prefix, upperName)
+		} else {
+			m.Err = errors.NotValid.Newf("[config/storage] Environment variable %q contains invalid characters", name)
+		}
+		if m.Err == nil {
+			values[makeCacheKey(m.Path.ScopeRoute())] = []byte(val)
+		}
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Name < matches[j].Name })
+
+	eo.mu.Lock()
+	eo.values = values
+	eo.matches = matches
+	eo.mu.Unlock()
+}
+
+// Set writes the value into the inner storage. An environment variable for
+// the same path still takes precedence when reading.
+func (eo *EnvironmentOverlay) Set(p config.Path, value []byte) error {
+	return eo.inner.Set(p, value)
+}
+
+// Get returns the value of the environment variable for path p. If there is no
+// environment variable the inner storage gets queried.
+func (eo *EnvironmentOverlay) Get(p config.Path) (v []byte, found bool, err error) {
+	eo.mu.RLock()
+	v, found = eo.values[makeCacheKey(p.ScopeRoute())]
+	eo.mu.RUnlock()
+	if found {
+		return v, true, nil
+	}
+	return eo.inner.Get(p)
+}
+
+// DryRun lists the environment variables of the snapshot. Argument knownRoutes
+// contains the routes, like "web/secure/base_url", which are available in the
+// application. A known route can also be a glob-style pattern like
+// "carriers/**", see config.Route.Match. A variable is matched if its route is
+// known. All other variables with the prefix are returned as unmatched,
+// including those which cannot be parsed, so typos are discoverable. If
+// knownRoutes is empty, all parseable variables are matched.
+func (eo *EnvironmentOverlay) DryRun(knownRoutes ...string) (matched, unmatched []EnvironmentMatch) {
+	known := make(map[string]bool, len(knownRoutes))
+	var patterns []string
+	for _, r := range knownRoutes {
+		if strings.IndexByte(r, '*') >= 0 {
+			patterns = append(patterns, r)
+			continue
+		}
+		known[r] = true
+	}
+	isKnown := config.RouteMatcher(patterns...)
+
+	eo.mu.RLock()
+	defer eo.mu.RUnlock()
+	for _, m := range eo.matches {
+		_, route := m.Path.ScopeRoute()
+		if m.Err == nil && (len(knownRoutes) == 0 || known[route] || isKnown(route)) {
+			matched = append(matched, m)
+		} else {
+			unmatched = append(unmatched, m)
+		}
+	}
+	return matched, unmatched
+}
@@ -0,0 +1,114 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestEnvironmentOverlay(t *testing.T) {
+	envVars := map[string]string{
+		"CONFIG__STORES__1__WEB__SECURE__BASE_URL": "https://env.example.com",
+		"config__web__cookie__domain":              ".env.example.com",
+		"CONFIG__WEB__SECURE":                      "typo",
+		"CONFIG__WEB__UNKNOWN__ROUTE":              "not used",
+	}
+	for k, v := range envVars {
+		assert.NoError(t, os.Setenv(k, v))
+	}
+	defer func() {
+		for k := range envVars {
+			assert.NoError(t, os.Unsetenv(k))
+		}
+	}()
+
+	inner := storage.NewMap(
+		"stores/1/web/secure/base_url", "https://db.example.com",
+		"default/0/web/secure/offloader_header", "X-Forwarded-Proto",
+	)
+	eo := storage.NewEnvironmentOverlay("", inner)
+
+	pBaseURL := config.MustMakePathWithScope(scope.Store.WithID(1), "web/secure/base_url")
+	pCookie := config.MustMakePathWithScope(scope.DefaultTypeID, "web/cookie/domain")
+	pOffloader := config.MustMakePathWithScope(scope.DefaultTypeID, "web/secure/offloader_header")
+
+	t.Run("env var overrides inner storage", func(t *testing.T) {
+		v, ok, err := eo.Get(pBaseURL)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Exactly(t, "https://env.example.com", string(v))
+	})
+	t.Run("case insensitive name", func(t *testing.T) {
+		v, ok, err := eo.Get(pCookie)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Exactly(t, ".env.example.com", string(v))
+	})
+	t.Run("fall back to inner storage", func(t *testing.T) {
+		v, ok, err := eo.Get(pOffloader)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Exactly(t, "X-Forwarded-Proto", string(v))
+	})
+	t.Run("Set writes to inner storage", func(t *testing.T) {
+		assert.NoError(t, eo.Set(pBaseURL, []byte("https://set.example.com")))
+		v, _, err := inner.Get(pBaseURL)
+		assert.NoError(t, err)
+		assert.Exactly(t, "https://set.example.com", string(v))
+
+		v, _, err = eo.Get(pBaseURL)
+		assert.NoError(t, err)
+		assert.Exactly(t, "https://env.example.com", string(v))
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		matched, unmatched := eo.DryRun("web/secure/base_url", "web/cookie/domain")
+		var matchedNames, unmatchedNames []string
+		for _, m := range matched {
+			assert.NoError(t, m.Err)
+			matchedNames = append(matchedNames, m.Name)
+		}
+		for _, m := range unmatched {
+			unmatchedNames = append(unmatchedNames, m.Name)
+		}
+		assert.Exactly(t, []string{"CONFIG__STORES__1__WEB__SECURE__BASE_URL", "config__web__cookie__domain"}, matchedNames)
+		assert.Exactly(t, []string{"CONFIG__WEB__SECURE", "CONFIG__WEB__UNKNOWN__ROUTE"}, unmatchedNames)
+		assert.Error(t, unmatched[0].Err)
+	})
+
+	t.Run("DryRun with pattern", func(t *testing.T) {
+		matched, unmatched := eo.DryRun("web/*/base_url", "web/cookie/**")
+		assert.Len(t, matched, 2)
+		assert.Len(t, unmatched, 2)
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		assert.NoError(t, os.Unsetenv("CONFIG__STORES__1__WEB__SECURE__BASE_URL"))
+		v, _, err := eo.Get(pBaseURL)
+		assert.NoError(t, err)
+		assert.Exactly(t, "https://env.example.com", string(v), "snapshot must not change")
+
+		eo.Reload()
+		v, _, err = eo.Get(pBaseURL)
+		assert.NoError(t, err)
+		assert.Exactly(t, "https://set.example.com", string(v))
+	})
+}
@@ -25,199 +25,294 @@
 
 package storage
 
-//
-//import (
-//	"bytes"
-//	"context"
-//	"strings"
-//	"time"
-//
-//	"github.com/corestoreio/errors"
-//	"github.com/corestoreio/pkg/config"
-//	"github.com/corestoreio/pkg/util/bufferpool"
-//	"go.etcd.io/etcd/api/v3/mvccpb"
-//	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
-//	"go.etcd.io/etcd/client/v3"
-//)
-//
-//// Etcdv3DefaultKeyPrefix defines the global key prefix, which can be overwritten.
-//const Etcdv3DefaultKeyPrefix = "csv3/"
-//
-//type Etcdv3Options struct {
-//	RequestTimeout time.Duration
-//	// KeyPrefix defines a global key prefix used for all keys
-//	KeyPrefix string
-//}
-//
-//// service implemented interface config.Storager.
-//type etcdv3Client struct {
-//	options Etcdv3Options
-//	client  clientv3.KV
-//}
-//
-//// NewEtcdv3Client creates a new storage client with either a concret or a mocked
-//// object of the etcd v3.
-//func NewEtcdv3Client(c clientv3.KV, o Etcdv3Options) (config.Storager, error) {
-//	s := &etcdv3Client{
-//		options: o,
-//		client:  c,
-//	}
-//	if s.options.KeyPrefix == "" {
-//		s.options.KeyPrefix = Etcdv3DefaultKeyPrefix
-//	}
-//
-//	return s, nil
-//}
-//
-//func (s *etcdv3Client) toKey(p config.Path) (string, error) {
-//	buf := bufferpool.Get()
-//	defer bufferpool.Put(buf)
-//
-//	buf.WriteString(s.options.KeyPrefix)
-//	err := p.AppendFQ(buf)
-//	return buf.String(), err
-//}
-//
-//// Set puts a key to the etcd service.
-//func (s *etcdv3Client) Set(p config.Path, value []byte) error {
-//	ctx := context.Background()
-//	if s.options.RequestTimeout > 0 {
-//		var cancel context.CancelFunc
-//		ctx, cancel = context.WithTimeout(context.Background(), s.options.RequestTimeout)
-//		defer cancel()
-//	}
-//
-//	key, err := s.toKey(p)
-//	if err != nil {
-//		return errors.Wrapf(err, "[storage/etcdv3] toKey with key %q", key)
-//	}
-//
-//	if _, err = s.client.Put(ctx, key, string(value)); err != nil {
-//		return errors.Wrapf(err, "[storage/etcdv3] Put failed with key %q", key)
-//	}
-//	return nil
-//}
-//
-//// Get returns a value from the etcd service.
-//func (s *etcdv3Client) Get(p config.Path) (v []byte, found bool, err error) {
-//	ctx := context.Background()
-//	if s.options.RequestTimeout > 0 {
-//		var cancel context.CancelFunc
-//		ctx, cancel = context.WithTimeout(context.Background(), s.options.RequestTimeout)
-//		defer cancel()
-//	}
-//
-//	key, err := s.toKey(p)
-//	if err != nil {
-//		return nil, false, errors.Wrapf(err, "[storage/etcdv3] toKey with key %q", key)
-//	}
-//	resp, err := s.client.Get(ctx, key)
-//	if err != nil {
-//		switch err {
-//		case context.Canceled:
-//			return nil, false, nil
-//		case context.DeadlineExceeded:
-//			return nil, false, nil
-//		case rpctypes.ErrEmptyKey:
-//			return nil, false, nil
-//		}
-//		return nil, false, errors.Wrapf(err, "[storage/etcdv3] Client Get with key %q", key)
-//	}
-//
-//	keyBytes := []byte(key)
-//	for _, ev := range resp.Kvs {
-//		if bytes.Equal(ev.Key, keyBytes) { // maybe not necessary.
-//			return ev.Value, true, nil
-//		}
-//	}
-//
-//	return nil, false, nil
-//}
-//
-//// Etcdv3FakeClient implementation for testing purposes.
-//type Etcdv3FakeClient struct {
-//	PutFn    func(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error)
-//	PutError error
-//	GetError error
-//	GetFn    func(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
-//	GetKey   []byte
-//	GetValue []byte
-//}
-//
-//func (cm Etcdv3FakeClient) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
-//	if cm.PutFn != nil {
-//		return cm.PutFn(ctx, key, val, opts...)
-//	}
-//	return nil, cm.PutError
-//}
-//
-//func (cm Etcdv3FakeClient) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
-//	if cm.GetFn != nil {
-//		return cm.GetFn(ctx, key, opts...)
-//	}
-//	if cm.GetError != nil {
-//		return nil, cm.GetError
-//	}
-//	return &clientv3.GetResponse{
-//		Kvs: []*mvccpb.KeyValue{
-//			{
-//				Key:   cm.GetKey,
-//				Value: cm.GetValue,
-//			},
-//		},
-//	}, nil
-//}
-//
-//func (cm Etcdv3FakeClient) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
-//	return nil, errors.NotImplemented.Newf("[storage/etcdv3] Delete Not Implemented")
-//}
-//
-//func (cm Etcdv3FakeClient) Compact(ctx context.Context, rev int64, opts ...clientv3.CompactOption) (*clientv3.CompactResponse, error) {
-//	return nil, errors.NotImplemented.Newf("[storage/etcdv3] Compact Not Implemented")
-//}
-//
-//func (cm Etcdv3FakeClient) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
-//	return clientv3.OpResponse{}, errors.NotImplemented.Newf("[storage/etcdv3] Do Not Implemented")
-//}
-//
-//func (cm Etcdv3FakeClient) Txn(ctx context.Context) clientv3.Txn { return nil }
-//
-//// WithLoadFromEtcdv3 reads the all keys and their values with the current or configured
-//// etcd key prefix and applies it to the config.service. This function option
-//// can be set when creating a new config.service or updating its internal DB.
-//func WithLoadFromEtcdv3(c clientv3.KV, o Etcdv3Options) config.LoadDataOption {
-//	if o.KeyPrefix == "" {
-//		o.KeyPrefix = Etcdv3DefaultKeyPrefix
-//	}
-//
-//	return config.MakeLoadDataOption(func(s *config.Service) error {
-//		ctx := context.Background()
-//		if o.RequestTimeout > 0 {
-//			var cancel context.CancelFunc
-//			ctx, cancel = context.WithTimeout(context.Background(), o.RequestTimeout)
-//			defer cancel()
-//		}
-//
-//		resp, err := c.Get(ctx, o.KeyPrefix, clientv3.WithPrefix())
-//		if err != nil {
-//			return errors.WithStack(err)
-//		}
-//		var p config.Path
-//		var buf strings.Builder
-//		for _, ev := range resp.Kvs {
-//			buf.Write(ev.Key)
-//
-//			if err := p.Parse(buf.String()); err != nil {
-//				return errors.Wrapf(err, "[storage/etcdv3] With Path %q", p.String())
-//			}
-//
-//			if err := s.Set(p, ev.Value); err != nil {
-//				return errors.Wrapf(err, "[storage/etcdv3] With Path %q", p.String())
-//			}
-//			buf.Reset()
-//			p.Reset()
-//		}
-//
-//		return nil
-//	}).WithUseStorageLevel(1)
-//}
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/storage/null"
+	"go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EtcdDefaultBasePath defines the default key prefix of all configuration
+// keys.
+const EtcdDefaultBasePath = "corestore/config"
+
+// etcdMaxBackoff defines the maximum wait time between two retries.
+const etcdMaxBackoff = time.Minute
+
+// EtcdClient defines the parts of the etcd v3 client used by Etcd. Type
+// *clientv3.Client implements this interface.
+type EtcdClient interface {
+	clientv3.KV
+	clientv3.Watcher
+}
+
+// EtcdOptions applies options to the etcd storage.
+type EtcdOptions struct {
+	// RequestTimeout defines the timeout of each request to etcd. Default 5s.
+	RequestTimeout time.Duration
+	// MaxRetries defines how often a request gets retried on a temporary
+	// error, like a lost connection. Default 2.
+	MaxRetries int
+	// RetryWait defines the initial wait time between two retries, which
+	// doubles with each retry. Default 100ms.
+	RetryWait time.Duration
+	Log       log.Logger
+}
+
+// Etcd stores the configuration values in etcd v3. A path maps to the key
+// `<basePath>/<scope>/<scopeID>/<route>`, e.g.
+// `corestore/config/stores/2/tax/calculation/rate`. Etcd implements
+// config.Storager and is safe for concurrent use.
+type Etcd struct {
+	client   EtcdClient
+	basePath string // with trailing slash
+	cfg      EtcdOptions
+
+	mu          sync.RWMutex
+	subscribers []DBPollerSubscriber
+}
+
+// NewEtcd creates a new etcd storage. An empty basePath falls back to
+// EtcdDefaultBasePath. Call Watch to receive changes made by other processes.
+func NewEtcd(client EtcdClient, basePath string, o ...EtcdOptions) (*Etcd, error) {
+	if client == nil {
+		return nil, errors.Empty.Newf("[config/storage] NewEtcd: Argument client cannot be nil")
+	}
+	var cfg EtcdOptions
+	if len(o) == 1 {
+		cfg = o[0]
+	}
+	if cfg.RequestTimeout == 0 {
+		cfg.RequestTimeout = 5 * time.Second
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 2
+	}
+	if cfg.RetryWait == 0 {
+		cfg.RetryWait = 100 * time.Millisecond
+	}
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		basePath = EtcdDefaultBasePath
+	}
+	return &Etcd{
+		client:   client,
+		basePath: basePath + "/",
+		cfg:      cfg,
+	}, nil
+}
+
+func (e *Etcd) key(p config.Path) (string, error) {
+	fq, err := p.FQ()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return e.basePath + fq, nil
+}
+
+// parseKey splits a key into scope, scope ID and route.
+func (e *Etcd) parseKey(key string) (scp string, scopeID uint32, route string, err error) {
+	if !strings.HasPrefix(key, e.basePath) {
+		return "", 0, "", errors.NotValid.Newf("[config/storage] Etcd: Key %q does not start with %q", key, e.basePath)
+	}
+	parts := strings.SplitN(key[len(e.basePath):], "/", 3)
+	if len(parts) != 3 {
+		return "", 0, "", errors.NotValid.Newf("[config/storage] Etcd: Invalid key %q", key)
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return "", 0, "", errors.NotValid.New(err, "[config/storage] Etcd: Invalid scope ID in key %q", key)
+	}
+	return parts[0], uint32(id), parts[2], nil
+}
+
+// isEtcdTemporary reports whether the request can be retried.
+func isEtcdTemporary(err error) bool {
+	if err == context.DeadlineExceeded {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+// do executes fn with a request scoped context and retries it on temporary
+// errors. A temporary error which persists gets returned with the behaviour
+// kind Temporary.
+func (e *Etcd) do(method, key string, fn func(ctx context.Context) error) error {
+	wait := e.cfg.RetryWait
+	for i := 0; ; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), e.cfg.RequestTimeout)
+		err := fn(ctx)
+		cancel()
+		switch {
+		case err == nil:
+			return nil
+		case !isEtcdTemporary(err):
+			return errors.Wrapf(err, "[config/storage] Etcd.%s with key %q", method, key)
+		case i >= e.cfg.MaxRetries:
+			return errors.Temporary.New(err, "[config/storage] Etcd.%s with key %q failed after %d retries", method, key, i)
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// Set puts the value into etcd. A nil value gets stored as an empty value.
+func (e *Etcd) Set(p config.Path, value []byte) error {
+	key, err := e.key(p)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return e.do("Set", key, func(ctx context.Context) error {
+		_, err := e.client.Put(ctx, key, string(value))
+		return err
+	})
+}
+
+// Get returns the value from etcd.
+func (e *Etcd) Get(p config.Path) (v []byte, found bool, err error) {
+	key, err := e.key(p)
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+	err = e.do("Get", key, func(ctx context.Context) error {
+		resp, err := e.client.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		for _, kv := range resp.Kvs {
+			if string(kv.Key) == key {
+				v, found = kv.Value, true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return v, found, nil
+}
+
+// Delete removes the path from etcd.
+func (e *Etcd) Delete(p config.Path) error {
+	key, err := e.key(p)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return e.do("Delete", key, func(ctx context.Context) error {
+		_, err := e.client.Delete(ctx, key)
+		return err
+	})
+}
+
+// Truncate removes all keys below the base path.
+func (e *Etcd) Truncate() error {
+	return e.do("Truncate", e.basePath, func(ctx context.Context) error {
+		_, err := e.client.Delete(ctx, e.basePath, clientv3.WithPrefix())
+		return err
+	})
+}
+
+// HealthCheck reads at most one key below the base path without its value and
+// without retries. Suitable for readiness probes, see package storage/health.
+func (e *Etcd) HealthCheck(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.RequestTimeout)
+	defer cancel()
+	if _, err := e.client.Get(ctx, e.basePath, clientv3.WithPrefix(), clientv3.WithLimit(1), clientv3.WithKeysOnly()); err != nil {
+		return errors.Wrapf(err, "[config/storage] Etcd.HealthCheck with key %q", e.basePath)
+	}
+	return nil
+}
+
+// Subscribe adds a new function which gets called by Watch for each changed
+// path. A deleted path gets reported with an invalid value. Subscribers get
+// called sequentially in the order of registration.
+func (e *Etcd) Subscribe(fn DBPollerSubscriber) {
+	e.mu.Lock()
+	e.subscribers = append(e.subscribers, fn)
+	e.mu.Unlock()
+}
+
+// Watch watches all keys below the base path and calls the subscribers for
+// each change. A lost connection or a closed watch channel gets logged and the
+// watch resumes after an exponential backoff from the last seen revision, so no
+// change gets lost, unless the revision has been compacted in the meantime.
+// Watch blocks and returns nil once the context has been cancelled.
+func (e *Etcd) Watch(ctx context.Context) error {
+	var rev int64 // zero starts at the current revision
+	var failures uint
+	for {
+		opts := []clientv3.OpOption{clientv3.WithPrefix()}
+		if rev > 0 {
+			opts = append(opts, clientv3.WithRev(rev+1))
+		}
+		wctx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+		var err error
+		for resp := range e.client.Watch(wctx, e.basePath, opts...) {
+			if resp.CompactRevision > 0 {
+				rev = resp.CompactRevision - 1 // changes in between are lost
+			}
+			if err = resp.Err(); err != nil {
+				break
+			}
+			failures = 0
+			for _, ev := range resp.Events {
+				e.notify(ev)
+				rev = ev.Kv.ModRevision
+			}
+		}
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		failures++
+		wait := e.cfg.RetryWait
+		for i := uint(1); i < failures && wait < etcdMaxBackoff; i++ {
+			wait *= 2
+		}
+		if wait > etcdMaxBackoff {
+			wait = etcdMaxBackoff
+		}
+		if e.cfg.Log != nil && e.cfg.Log.IsInfo() {
+			e.cfg.Log.Info("config.storage.Etcd.Watch", log.Err(err), log.Int64("revision", rev), log.Uint("failures", failures), log.Duration("wait", wait))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (e *Etcd) notify(ev *clientv3.Event) {
+	scp, id, route, err := e.parseKey(string(ev.Kv.Key))
+	if err != nil {
+		if e.cfg.Log != nil && e.cfg.Log.IsInfo() {
+			e.cfg.Log.Info("config.storage.Etcd.Watch.parseKey", log.Err(err))
+		}
+		return
+	}
+	var val null.String
+	if ev.Type == clientv3.EventTypePut {
+		val = null.MakeString(string(ev.Kv.Value))
+	}
+	e.mu.RLock()
+	subscribers := e.subscribers
+	e.mu.RUnlock()
+	for _, fn := range subscribers {
+		fn(scp, id, route, val)
+	}
+}
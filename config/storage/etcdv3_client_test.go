@@ -16,197 +16,230 @@
 
 // see etcdv3_client.go when build tag csall is missing.
 
-package storage
+package storage_test
 
-//import (
-//	"context"
-//	"flag"
-//	"testing"
-//	"time"
-//
-//	"github.com/corestoreio/errors"
-//	"github.com/corestoreio/pkg/config"
-//	"github.com/corestoreio/pkg/store/scope"
-//	"github.com/corestoreio/pkg/util/assert"
-//	"go.etcd.io/etcd/api/v3/mvccpb"
-//	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
-//	"go.etcd.io/etcd/client/v3"
-//)
-//
-//func init() {
-//	flag.BoolVar(&runIntegration, "integration", false, "Enables etcdv3 integration tests")
-//}
-//
-//var (
-//	runIntegration bool
-//	dialTimeout    = 1 * time.Second
-//	// requestTimeout = 2 * time.Second
-//	endpoints = []string{"localhost:2379", "localhost:22379", "localhost:32379"}
-//)
-//
-//func TestStorage_Get(t *testing.T) {
-//	testData := []byte(`You should turn it to eleven.`)
-//	// scpID := scope.Website.WithID(3)
-//	const path = "path/to/orion"
-//	p := config.MustMakePathWithScope(scope.Website.WithID(3), "path/to/orion")
-//
-//	t.Run("Get found", func(t *testing.T) {
-//		mo := Etcdv3FakeClient{
-//			GetKey:   []byte(Etcdv3DefaultKeyPrefix + `websites/3/` + path),
-//			GetValue: testData,
-//		}
-//
-//		s, err := NewEtcdv3Client(mo, Etcdv3Options{})
-//		assert.NoError(t, err)
-//
-//		haveData, found, err := s.Get(p)
-//		assert.NoError(t, err)
-//		assert.True(t, found, "Value and path must be found")
-//		assert.Exactly(t, haveData, testData)
-//	})
-//
-//	t.Run("Get not found", func(t *testing.T) {
-//		mo := Etcdv3FakeClient{
-//			GetKey:   []byte(`websites/3/`),
-//			GetValue: testData,
-//		}
-//
-//		s, err := NewEtcdv3Client(mo, Etcdv3Options{})
-//		assert.NoError(t, err)
-//
-//		haveData, found, err := s.Get(p)
-//		assert.NoError(t, err)
-//		assert.False(t, found, "Value and path must NOT be found")
-//		assert.Nil(t, haveData)
-//	})
-//
-//	testGetErrors := func(getErr error) func(*testing.T) {
-//		return func(t *testing.T) {
-//			mo := Etcdv3FakeClient{
-//				GetError: getErr,
-//			}
-//
-//			s, err := NewEtcdv3Client(mo, Etcdv3Options{})
-//			assert.NoError(t, err)
-//
-//			haveData, found, err := s.Get(p)
-//			assert.NoError(t, err)
-//			assert.False(t, found, "Value and path must NOT be found")
-//			assert.Nil(t, haveData)
-//		}
-//	}
-//
-//	t.Run("Get context.Canceled", testGetErrors(context.Canceled))
-//	t.Run("Get context.DeadlineExceeded", testGetErrors(context.DeadlineExceeded))
-//	t.Run("Get rpctypes.ErrEmptyKey", testGetErrors(rpctypes.ErrEmptyKey))
-//	t.Run("Get any other error", func(t *testing.T) {
-//		mo := Etcdv3FakeClient{
-//			GetError: errors.ConnectionLost.Newf("Ups"),
-//		}
-//
-//		s, err := NewEtcdv3Client(mo, Etcdv3Options{})
-//		assert.NoError(t, err)
-//
-//		haveData, found, err := s.Get(p)
-//		assert.True(t, errors.ConnectionLost.Match(err), "Should have error kind connection lost")
-//		assert.False(t, found, "Value and path must NOT be found")
-//		assert.Nil(t, haveData)
-//	})
-//
-//	t.Run("Set no error ", func(t *testing.T) {
-//		mo := Etcdv3FakeClient{}
-//
-//		s, err := NewEtcdv3Client(mo, Etcdv3Options{})
-//		assert.NoError(t, err)
-//
-//		err = s.Set(p, testData)
-//		assert.NoError(t, err)
-//	})
-//	t.Run("Set no error ", func(t *testing.T) {
-//		mo := Etcdv3FakeClient{
-//			PutError: errors.ConnectionLost.Newf("Ups"),
-//		}
-//
-//		s, err := NewEtcdv3Client(mo, Etcdv3Options{})
-//		assert.NoError(t, err)
-//
-//		err = s.Set(p, testData)
-//		assert.True(t, errors.ConnectionLost.Match(err), "Should have error kind connection lost")
-//	})
-//}
-//
-//func TestNewStorage_Integration(t *testing.T) {
-//	if !runIntegration {
-//		t.Skip("Skipped. To enable use -integration=1")
-//	}
-//	/*
-//	   $ etcdctl get --prefix csv3
-//	   csv3/default/0/tax/calculation/rate
-//	   19.0
-//	   csv3/stores/2/tax/calculation/rate
-//	   19.2
-//	*/
-//	c, err := clientv3.New(clientv3.Config{
-//		Endpoints:   endpoints,
-//		DialTimeout: dialTimeout,
-//	})
-//	if err != nil {
-//		t.Skipf("etcd daemon seems not to be running: %s", err)
-//	}
-//	assert.NotNil(t, c)
-//
-//	srv, err := NewEtcdv3Client(c, Etcdv3Options{})
-//	if err != nil {
-//		t.Fatalf("%+v", err)
-//	}
-//
-//	p := config.MustMakePath("tax/calculation/rate")
-//	p2 := p.BindStore(2)
-//
-//	assert.NoError(t, srv.Set(p, []byte(`19.0`)))
-//	assert.NoError(t, srv.Set(p2, []byte(`19.2`)))
-//
-//	data, _, err := srv.Get(p)
-//	assert.NoError(t, err)
-//	assert.Exactly(t, []byte(`19.0`), data)
-//
-//	data, _, err = srv.Get(p2)
-//	assert.NoError(t, err)
-//	assert.Exactly(t, []byte(`19.2`), data)
-//}
-//
-//func TestWithLoadData_Success(t *testing.T) {
-//	fc := Etcdv3FakeClient{
-//		GetFn: func(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
-//			return &clientv3.GetResponse{
-//				Kvs: []*mvccpb.KeyValue{
-//					{
-//						Key:   []byte(`websites/2/payment/datatr/sha1`),
-//						Value: []byte(`fc9d6fd2d8db223be4a7484a8619f26b`),
-//					},
-//					{
-//						Key:   []byte(`stores/1/payment/datatr/sha1`),
-//						Value: []byte(`46aaccbebf47d8f8fce8c02d621aa573`),
-//					},
-//					{
-//						Key:   []byte(`default/0/payment/datatr/sha1`),
-//						Value: []byte(`e30d8df9810bc36105c96ad3ae76ffd3`),
-//					},
-//				},
-//			}, nil
-//		},
-//	}
-//	inMem := NewMap()
-//	cfgSrv, err := config.NewService(
-//		inMem, config.Options{},
-//		WithLoadFromEtcdv3(fc, Etcdv3Options{}),
-//	)
-//	if err != nil {
-//		t.Fatalf("%+v", err)
-//	}
-//	p := config.MustMakePathWithScope(scope.Website.WithID(2), "payment/datatr/sha1")
-//
-//	assert.Exactly(t, `"fc9d6fd2d8db223be4a7484a8619f26b"`, cfgSrv.Get(p).String())
-//	assert.Exactly(t, `"46aaccbebf47d8f8fce8c02d621aa573"`, cfgSrv.Get(p.BindStore(1)).String())
-//	assert.Exactly(t, `"e30d8df9810bc36105c96ad3ae76ffd3"`, cfgSrv.Get(p.BindDefault()).String())
-//}
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ config.Storager = (*storage.Etcd)(nil)
+
+// fakeEtcd implements the used parts of storage.EtcdClient with an in-memory
+// map. The embedded interfaces panic when an unexpected method gets called.
+type fakeEtcd struct {
+	clientv3.KV
+	clientv3.Watcher
+
+	mu      sync.Mutex
+	data    map[string]string
+	errs    []error // returned by the next requests
+	calls   int
+	watches chan clientv3.WatchChan
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{
+		data:    map[string]string{},
+		watches: make(chan clientv3.WatchChan, 10),
+	}
+}
+
+func (f *fakeEtcd) nextErr() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeEtcd) Put(_ context.Context, key, val string, _ ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.nextErr(); err != nil {
+		return nil, err
+	}
+	f.data[key] = val
+	return &clientv3.PutResponse{}, nil
+}
+
+func (f *fakeEtcd) Get(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.nextErr(); err != nil {
+		return nil, err
+	}
+	resp := &clientv3.GetResponse{}
+	if v, ok := f.data[key]; ok {
+		resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(key), Value: []byte(v)})
+	}
+	return resp, nil
+}
+
+func (f *fakeEtcd) Delete(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.nextErr(); err != nil {
+		return nil, err
+	}
+	if len(opts) == 0 {
+		delete(f.data, key)
+		return &clientv3.DeleteResponse{}, nil
+	}
+	for k := range f.data { // WithPrefix
+		if len(k) >= len(key) && k[:len(key)] == key {
+			delete(f.data, k)
+		}
+	}
+	return &clientv3.DeleteResponse{}, nil
+}
+
+// Watch forwards the responses of the next prepared watch channel and closes
+// the returned channel like etcd once the context has been cancelled.
+func (f *fakeEtcd) Watch(ctx context.Context, _ string, _ ...clientv3.OpOption) clientv3.WatchChan {
+	out := make(chan clientv3.WatchResponse)
+	go func() {
+		defer close(out)
+		var in clientv3.WatchChan
+		select {
+		case in = <-f.watches:
+		case <-ctx.Done():
+			return
+		}
+		for {
+			select {
+			case resp, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- resp:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func TestEtcd_SetGetDelete(t *testing.T) {
+	f := newFakeEtcd()
+	e, err := storage.NewEtcd(f, "/kube/config/", storage.EtcdOptions{RetryWait: time.Millisecond})
+	assert.NoError(t, err)
+
+	p := config.MustMakePathWithScope(scope.Store.WithID(2), "tax/calculation/rate")
+	assert.NoError(t, e.Set(p, []byte(`19.2`)))
+	assert.Exactly(t, map[string]string{"kube/config/stores/2/tax/calculation/rate": "19.2"}, f.data)
+
+	validateFoundGet(t, e, scope.Store.WithID(2), "tax/calculation/rate", "19.2")
+	validateNotFoundGet(t, e, scope.Store.WithID(3), "tax/calculation/rate")
+
+	assert.NoError(t, e.Delete(p))
+	validateNotFoundGet(t, e, scope.Store.WithID(2), "tax/calculation/rate")
+
+	assert.NoError(t, e.Set(p, []byte(`19.2`)))
+	assert.NoError(t, e.Set(p.BindWebsite(1), []byte(`19.1`)))
+	assert.NoError(t, e.Truncate())
+	assert.Len(t, f.data, 0)
+}
+
+func TestEtcd_Retry(t *testing.T) {
+	p := config.MustMakePath("tax/calculation/rate")
+	unavailable := status.Error(codes.Unavailable, "connection lost")
+
+	t.Run("recovers", func(t *testing.T) {
+		f := newFakeEtcd()
+		f.errs = []error{unavailable, context.DeadlineExceeded}
+		e, err := storage.NewEtcd(f, "", storage.EtcdOptions{RetryWait: time.Millisecond})
+		assert.NoError(t, err)
+		assert.NoError(t, e.Set(p, []byte(`19.0`)))
+		assert.Exactly(t, 3, f.calls)
+	})
+	t.Run("temporary error", func(t *testing.T) {
+		f := newFakeEtcd()
+		f.errs = []error{unavailable, unavailable, unavailable}
+		e, err := storage.NewEtcd(f, "", storage.EtcdOptions{RetryWait: time.Millisecond})
+		assert.NoError(t, err)
+		v, ok, err := e.Get(p)
+		assert.ErrorIsKind(t, errors.Temporary, err)
+		assert.False(t, ok)
+		assert.Nil(t, v)
+	})
+	t.Run("no retry", func(t *testing.T) {
+		f := newFakeEtcd()
+		f.errs = []error{status.Error(codes.PermissionDenied, "nope")}
+		e, err := storage.NewEtcd(f, "", storage.EtcdOptions{RetryWait: time.Millisecond})
+		assert.NoError(t, err)
+		err = e.Set(p, []byte(`19.0`))
+		assert.Error(t, err)
+		assert.False(t, errors.Temporary.Match(err))
+		assert.Exactly(t, 1, f.calls)
+	})
+}
+
+func TestEtcd_HealthCheck(t *testing.T) {
+	f := newFakeEtcd()
+	e, err := storage.NewEtcd(f, "", storage.EtcdOptions{RetryWait: time.Millisecond})
+	assert.NoError(t, err)
+	assert.NoError(t, e.HealthCheck(context.TODO()))
+
+	f.errs = []error{status.Error(codes.Unavailable, "connection lost")}
+	assert.Error(t, e.HealthCheck(context.TODO()))
+	assert.Exactly(t, 2, f.calls, "must not retry")
+}
+
+func TestEtcd_Watch(t *testing.T) {
+	f := newFakeEtcd()
+	e, err := storage.NewEtcd(f, "", storage.EtcdOptions{RetryWait: time.Millisecond})
+	assert.NoError(t, err)
+
+	type change struct {
+		scope   string
+		scopeID uint32
+		path    string
+		value   null.String
+	}
+	changes := make(chan change, 10)
+	e.Subscribe(func(scope string, scopeID uint32, path string, value null.String) {
+		changes <- change{scope: scope, scopeID: scopeID, path: path, value: value}
+	})
+
+	// first watch breaks down, the second one delivers the events.
+	wc1 := make(chan clientv3.WatchResponse)
+	close(wc1)
+	wc2 := make(chan clientv3.WatchResponse, 1)
+	f.watches <- wc1
+	f.watches <- wc2
+	wc2 <- clientv3.WatchResponse{Events: []*clientv3.Event{
+		{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Key: []byte(storage.EtcdDefaultBasePath + "/stores/2/tax/calculation/rate"), Value: []byte("19.2"), ModRevision: 5}},
+		{Type: clientv3.EventTypeDelete, Kv: &mvccpb.KeyValue{Key: []byte(storage.EtcdDefaultBasePath + "/default/0/tax/calculation/rate"), ModRevision: 6}},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Watch(ctx) }()
+
+	assert.Exactly(t, change{scope: "stores", scopeID: 2, path: "tax/calculation/rate", value: null.MakeString("19.2")}, <-changes)
+	assert.Exactly(t, change{scope: "default", scopeID: 0, path: "tax/calculation/rate"}, <-changes)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
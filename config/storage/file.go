@@ -0,0 +1,483 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// FileOptions applies options to the `File` type.
+type FileOptions struct {
+	// Writable enables Set to write the changed data back into the file. The
+	// file gets written in a canonical format, comments and the order of the
+	// keys get lost.
+	Writable bool
+	// ReloadInterval if greater than zero starts a goroutine which checks the
+	// modification time of the file and reloads the file if it has changed.
+	// Call Close to stop the goroutine.
+	ReloadInterval time.Duration
+	// Log logs failed reloads as Info message.
+	Log log.Logger
+}
+
+// fileCodec parses and encodes a configuration file format. The file extension
+// selects the codec.
+type fileCodec struct {
+	parse  func(data []byte) (*fileNode, error)
+	encode func(v interface{}) ([]byte, error)
+}
+
+// fileCodecs contains the supported file extensions. YAML requires the build
+// tag yaml or csall.
+var fileCodecs = map[string]fileCodec{
+	".json": {
+		parse: parseJSONFile,
+		encode: func(v interface{}) ([]byte, error) {
+			return json.MarshalIndent(v, "", "  ")
+		},
+	},
+}
+
+// File reads the configuration values from a JSON or YAML file. The file must
+// contain the scopes as top level keys, followed by the scope IDs for websites,
+// group and stores. The route can be split into nested keys or written as a
+// single key:
+//		default:
+//		  web:
+//		    secure:
+//		      base_url: https://example.com/
+//		websites:
+//		  1:
+//		    web/secure/base_url: https://example.de/
+//		stores:
+//		  2:
+//		    web/secure/base_url: ~
+// Missing paths behave the same as in the DB storage: Get returns a nil value,
+// false and no error. A null value returns a nil value, true and no error.
+// File implements config.Storager and is safe for concurrent use.
+type File struct {
+	cfg   FileOptions
+	name  string
+	codec fileCodec
+
+	tickerDaemonStop chan struct{}
+
+	mu      sync.RWMutex
+	values  map[cacheKey][]byte
+	modTime time.Time
+	size    int64
+}
+
+// NewFile creates a new file based storage and loads the file. The extension
+// of the file name, .json, .yaml or .yml, defines the format.
+func NewFile(fileName string, o FileOptions) (*File, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	codec, ok := fileCodecs[ext]
+	if !ok {
+		return nil, errors.NotSupported.Newf("[config/storage] NewFile: File extension %q of file %q not supported. YAML requires the build tag yaml.", ext, fileName)
+	}
+	fs := &File{
+		cfg:   o,
+		name:  fileName,
+		codec: codec,
+	}
+	if err := fs.Reload(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if o.ReloadInterval > 0 {
+		fs.tickerDaemonStop = make(chan struct{})
+		go fs.runReloader(fs.tickerDaemonStop)
+	}
+	return fs, nil
+}
+
+// runReloader receives the stop channel as argument because Close resets the
+// field.
+func (fs *File) runReloader(stop <-chan struct{}) {
+	ticker := time.NewTicker(fs.cfg.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := fs.Reload(); err != nil && fs.cfg.Log != nil && fs.cfg.Log.IsInfo() {
+				fs.cfg.Log.Info("config.storage.File.Reload", log.Err(err), log.String("file", fs.name))
+			}
+		}
+	}
+}
+
+// Close stops the reload goroutine. Close can be called multiple times.
+func (fs *File) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.tickerDaemonStop != nil {
+		close(fs.tickerDaemonStop)
+		fs.tickerDaemonStop = nil
+	}
+	return nil
+}
+
+// HealthCheck verifies that the file can still be opened. Suitable for
+// readiness probes, see package storage/health.
+func (fs *File) HealthCheck(_ context.Context) error {
+	f, err := os.Open(fs.name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(f.Close())
+}
+
+// Reload parses the file again if its modification time or size has changed.
+// The parsed values get swapped atomically. On error the previous values are
+// kept.
+func (fs *File) Reload() error {
+	fi, err := os.Stat(fs.name)
+	if err != nil {
+		return errors.ReadFailed.New(err, "[config/storage] File.Reload: Stat %q", fs.name)
+	}
+	fs.mu.RLock()
+	unchanged := fs.values != nil && fi.ModTime().Equal(fs.modTime) && fi.Size() == fs.size
+	fs.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(fs.name)
+	if err != nil {
+		return errors.ReadFailed.New(err, "[config/storage] File.Reload: Read %q", fs.name)
+	}
+	root, err := fs.codec.parse(data)
+	if err != nil {
+		return errors.Wrapf(err, "[config/storage] File.Reload: Parse %q", fs.name)
+	}
+	values, err := flattenFileNode(root)
+	if err != nil {
+		return errors.Wrapf(err, "[config/storage] File.Reload: File %q", fs.name)
+	}
+
+	fs.mu.Lock()
+	fs.values = values
+	fs.modTime = fi.ModTime()
+	fs.size = fi.Size()
+	fs.mu.Unlock()
+	return nil
+}
+
+// Set writes the value into the file, if the option Writable has been set.
+// Otherwise returns a NotAllowed error. A nil value gets written as null.
+func (fs *File) Set(p config.Path, value []byte) error {
+	if !fs.cfg.Writable {
+		return errors.NotAllowed.Newf("[config/storage] File %q has not been opened writable", fs.name)
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	values := make(map[cacheKey][]byte, len(fs.values)+1)
+	for k, v := range fs.values {
+		values[k] = v
+	}
+	if value != nil {
+		value = append([]byte{}, value...) // the caller might reuse the slice
+	}
+	values[makeCacheKey(p.ScopeRoute())] = value
+
+	data, err := fs.codec.encode(fileTree(values))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fi, err := writeFileAtomic(fs.name, data)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fs.values = values
+	fs.modTime = fi.ModTime()
+	fs.size = fi.Size()
+	return nil
+}
+
+// Get returns the value for the path p. Missing paths return a nil value,
+// false and no error.
+func (fs *File) Get(p config.Path) (v []byte, found bool, err error) {
+	fs.mu.RLock()
+	v, found = fs.values[makeCacheKey(p.ScopeRoute())]
+	fs.mu.RUnlock()
+	return v, found, nil
+}
+
+// writeFileAtomic writes the data into a temporary file in the same directory
+// and renames it, so readers never see a partially written file.
+func writeFileAtomic(name string, data []byte) (os.FileInfo, error) {
+	perm := os.FileMode(0644)
+	if fi, err := os.Stat(name); err == nil {
+		perm = fi.Mode().Perm()
+	}
+	f, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".tmp")
+	if err != nil {
+		return nil, errors.WriteFailed.New(err, "[config/storage] Failed to create temporary file for %q", name)
+	}
+	tmpName := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if errC := f.Close(); err == nil {
+		err = errC
+	}
+	if err == nil {
+		err = os.Rename(tmpName, name)
+	}
+	if err != nil {
+		_ = os.Remove(tmpName)
+		return nil, errors.WriteFailed.New(err, "[config/storage] Failed to write file %q", name)
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, errors.ReadFailed.New(err, "[config/storage] Stat %q", name)
+	}
+	return fi, nil
+}
+
+// fileTree creates the canonical tree of the values for encoding. The routes
+// do not get split into nested keys.
+func fileTree(values map[cacheKey][]byte) map[string]interface{} {
+	tree := make(map[string]interface{}, 3)
+	for ck, v := range values {
+		var val interface{}
+		if v != nil {
+			val = string(v)
+		}
+		scp, id := ck.scp.Unpack()
+		scpStr := scp.StrType()
+		if scp == scope.Default {
+			routes, _ := tree[scpStr].(map[string]interface{})
+			if routes == nil {
+				routes = make(map[string]interface{})
+				tree[scpStr] = routes
+			}
+			routes[ck.route] = val
+			continue
+		}
+		ids, _ := tree[scpStr].(map[string]map[string]interface{})
+		if ids == nil {
+			ids = make(map[string]map[string]interface{})
+			tree[scpStr] = ids
+		}
+		idStr := strconv.FormatUint(uint64(id), 10)
+		if ids[idStr] == nil {
+			ids[idStr] = make(map[string]interface{})
+		}
+		ids[idStr][ck.route] = val
+	}
+	return tree
+}
+
+type fileNodeKind uint8
+
+const (
+	fileNodeScalar fileNodeKind = iota
+	fileNodeNull
+	fileNodeMap
+	fileNodeSeq
+)
+
+type filePos struct {
+	line   int
+	column int
+}
+
+func (fp filePos) String() string {
+	return fmt.Sprintf("line %d column %d", fp.line, fp.column)
+}
+
+// fileNode represents the parsed file independent of its format.
+type fileNode struct {
+	pos      filePos // position of the key or, for the root node, the value
+	key      string
+	kind     fileNodeKind
+	value    string
+	children []*fileNode
+}
+
+// flattenFileNode converts the tree into the paths and their values. It
+// rejects duplicate paths and non-scalar leaf values.
+func flattenFileNode(root *fileNode) (map[cacheKey][]byte, error) {
+	values := make(map[cacheKey][]byte)
+	if root.kind == fileNodeNull {
+		return values, nil // empty file
+	}
+	if root.kind != fileNodeMap {
+		return nil, errors.CorruptData.Newf("[config/storage] Expecting a mapping of scopes at %s", root.pos)
+	}
+
+	seen := make(map[cacheKey]filePos)
+	var p config.Path
+	var walk func(n *fileNode, scp, id, route string) error
+	walk = func(n *fileNode, scp, id, route string) error {
+		switch n.kind {
+		case fileNodeMap:
+			if len(n.children) == 0 {
+				return errors.CorruptData.Newf("[config/storage] Empty mapping for path %q in scope %s/%s at %s", route, scp, id, n.pos)
+			}
+			for _, c := range n.children {
+				r := c.key
+				if route != "" {
+					r = route + string(config.PathSeparator) + c.key
+				}
+				if err := walk(c, scp, id, r); err != nil {
+					return err
+				}
+			}
+			return nil
+		case fileNodeSeq:
+			return errors.CorruptData.Newf("[config/storage] Non-scalar value for path %q in scope %s/%s at %s", route, scp, id, n.pos)
+		}
+
+		if err := p.ParseStrings(scp, id, route); err != nil {
+			return errors.CorruptData.New(err, "[config/storage] Invalid path %q in scope %s/%s at %s", route, scp, id, n.pos)
+		}
+		ck := makeCacheKey(p.ScopeRoute())
+		if prev, ok := seen[ck]; ok {
+			return errors.Duplicated.Newf("[config/storage] Duplicate path %q in scope %s/%s at %s, previously defined at %s", route, scp, id, n.pos, prev)
+		}
+		seen[ck] = n.pos
+		if n.kind == fileNodeScalar {
+			values[ck] = []byte(n.value)
+		} else {
+			values[ck] = nil
+		}
+		return nil
+	}
+
+	for _, scpNode := range root.children {
+		switch scpNode.key {
+		case scope.StrDefault.String():
+			if err := walk(scpNode, scpNode.key, "0", ""); err != nil {
+				return nil, err
+			}
+		case scope.StrWebsites.String(), scope.StrGroup.String(), scope.StrStores.String():
+			if scpNode.kind != fileNodeMap {
+				return nil, errors.CorruptData.Newf("[config/storage] Expecting a mapping of IDs for scope %q at %s", scpNode.key, scpNode.pos)
+			}
+			for _, idNode := range scpNode.children {
+				if err := walk(idNode, scpNode.key, idNode.key, ""); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, errors.CorruptData.Newf("[config/storage] Unknown scope %q at %s, expecting default, websites, group or stores", scpNode.key, scpNode.pos)
+		}
+	}
+	return values, nil
+}
+
+// parseJSONFile parses the JSON data into a fileNode tree and records the
+// position of each key.
+func parseJSONFile(data []byte) (*fileNode, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &fileNode{kind: fileNodeNull}, nil
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	root, err := parseJSONValue(d, data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.CorruptData.Newf("[config/storage] Unexpected data after the JSON object at %s", jsonPos(data, d.InputOffset()))
+	}
+	return root, nil
+}
+
+func parseJSONValue(d *json.Decoder, data []byte) (*fileNode, error) {
+	n := &fileNode{pos: jsonPos(data, d.InputOffset())}
+	tok, err := d.Token()
+	if err != nil {
+		return nil, errors.CorruptData.New(err, "[config/storage] Invalid JSON at %s", n.pos)
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			n.kind = fileNodeSeq
+			for d.More() {
+				if _, err := parseJSONValue(d, data); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			n.kind = fileNodeMap
+			for d.More() {
+				keyPos := jsonPos(data, d.InputOffset())
+				kt, err := d.Token()
+				if err != nil {
+					return nil, errors.CorruptData.New(err, "[config/storage] Invalid JSON at %s", keyPos)
+				}
+				c, err := parseJSONValue(d, data)
+				if err != nil {
+					return nil, err
+				}
+				c.key, _ = kt.(string)
+				c.pos = keyPos
+				n.children = append(n.children, c)
+			}
+		}
+		if _, err := d.Token(); err != nil { // closing delimiter
+			return nil, errors.CorruptData.New(err, "[config/storage] Invalid JSON at %s", jsonPos(data, d.InputOffset()))
+		}
+	case nil:
+		n.kind = fileNodeNull
+	case string:
+		n.value = t
+	case json.Number:
+		n.value = t.String()
+	case bool:
+		n.value = strconv.FormatBool(t)
+	}
+	return n, nil
+}
+
+// jsonPos converts the offset into a line and column. The offset of the
+// json.Decoder points to the end of the previous token, so separators get
+// skipped.
+func jsonPos(data []byte, offset int64) filePos {
+	off := int(offset)
+	for off < len(data) && strings.IndexByte(" \t\r\n:,", data[off]) >= 0 {
+		off++
+	}
+	line := bytes.Count(data[:off], []byte{'\n'}) + 1
+	column := off - bytes.LastIndexByte(data[:off], '\n')
+	return filePos{line: line, column: column}
+}
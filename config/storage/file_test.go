@@ -0,0 +1,188 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func writeTempFile(t *testing.T, name, content string) (fileName string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "cs_config_file")
+	assert.NoError(t, err)
+	fileName = filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte(content), 0644))
+	return fileName, func() { _ = os.RemoveAll(dir) }
+}
+
+const fileTestJSON = `{
+  "default": {
+    "web": {
+      "secure": {
+        "base_url": "https://example.com/"
+      }
+    },
+    "dev/log/active": true
+  },
+  "websites": {
+    "1": {
+      "web/secure/base_url": "https://example.de/"
+    }
+  },
+  "stores": {
+    "2": {
+      "dev/js/merge_files": 2.002,
+      "web/secure/base_url": null
+    }
+  }
+}`
+
+func TestFile_JSON(t *testing.T) {
+	fileName, cleanup := writeTempFile(t, "config.json", fileTestJSON)
+	defer cleanup()
+
+	fs, err := storage.NewFile(fileName, storage.FileOptions{Writable: true})
+	assert.NoError(t, err)
+	defer fs.Close()
+
+	validateFoundGet(t, fs, scope.DefaultTypeID, "web/secure/base_url", "https://example.com/")
+	validateFoundGet(t, fs, scope.DefaultTypeID, "dev/log/active", "true")
+	validateFoundGet(t, fs, scope.Website.WithID(1), "web/secure/base_url", "https://example.de/")
+	validateFoundGet(t, fs, scope.Store.WithID(2), "dev/js/merge_files", "2.002")
+	validateNotFoundGet(t, fs, scope.Store.WithID(1), "web/secure/base_url")
+
+	t.Run("null value", func(t *testing.T) {
+		v, ok, err := fs.Get(config.MustMakePathWithScope(scope.Store.WithID(2), "web/secure/base_url"))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Nil(t, v)
+	})
+
+	t.Run("Set round trip", func(t *testing.T) {
+		p := config.MustMakePathWithScope(scope.Store.WithID(3), "carrier/dhl/title")
+		assert.NoError(t, fs.Set(p, []byte("DHL Express")))
+		validateFoundGet(t, fs, scope.Store.WithID(3), "carrier/dhl/title", "DHL Express")
+
+		fs2, err := storage.NewFile(fileName, storage.FileOptions{})
+		assert.NoError(t, err)
+		validateFoundGet(t, fs2, scope.Store.WithID(3), "carrier/dhl/title", "DHL Express")
+		validateFoundGet(t, fs2, scope.DefaultTypeID, "web/secure/base_url", "https://example.com/")
+		validateFoundGet(t, fs2, scope.Store.WithID(2), "dev/js/merge_files", "2.002")
+
+		err = fs2.Set(p, []byte("DHL"))
+		assert.True(t, errors.NotAllowed.Match(err), "%+v", err)
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		assert.NoError(t, ioutil.WriteFile(fileName, []byte(`{"default":{"web/secure/base_url":"https://reloaded.com/"}}`), 0644))
+		// make sure the modification time differs on file systems with a
+		// coarse resolution.
+		future := time.Now().Add(time.Minute)
+		assert.NoError(t, os.Chtimes(fileName, future, future))
+
+		assert.NoError(t, fs.Reload())
+		validateFoundGet(t, fs, scope.DefaultTypeID, "web/secure/base_url", "https://reloaded.com/")
+		validateNotFoundGet(t, fs, scope.Website.WithID(1), "web/secure/base_url")
+	})
+
+	t.Run("Reload keeps previous values on error", func(t *testing.T) {
+		assert.NoError(t, ioutil.WriteFile(fileName, []byte(`{"default":`), 0644))
+		future := time.Now().Add(2 * time.Minute)
+		assert.NoError(t, os.Chtimes(fileName, future, future))
+
+		err := fs.Reload()
+		assert.True(t, errors.CorruptData.Match(err), "%+v", err)
+		validateFoundGet(t, fs, scope.DefaultTypeID, "web/secure/base_url", "https://reloaded.com/")
+	})
+}
+
+func TestFile_ReloadInterval(t *testing.T) {
+	fileName, cleanup := writeTempFile(t, "config.json", `{"default":{"web/secure/base_url":"a"}}`)
+	defer cleanup()
+
+	fs, err := storage.NewFile(fileName, storage.FileOptions{ReloadInterval: 10 * time.Millisecond})
+	assert.NoError(t, err)
+	defer fs.Close()
+
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte(`{"default":{"web/secure/base_url":"bb"}}`), 0644))
+	time.Sleep(100 * time.Millisecond)
+	validateFoundGet(t, fs, scope.DefaultTypeID, "web/secure/base_url", "bb")
+}
+
+func TestFile_HealthCheck(t *testing.T) {
+	fileName, cleanup := writeTempFile(t, "config.json", fileTestJSON)
+	defer cleanup()
+
+	fs, err := storage.NewFile(fileName, storage.FileOptions{})
+	assert.NoError(t, err)
+	defer fs.Close()
+
+	assert.NoError(t, fs.HealthCheck(context.TODO()))
+	assert.NoError(t, os.Remove(fileName))
+	assert.Error(t, fs.HealthCheck(context.TODO()))
+}
+
+func TestFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		kind    errors.Kind
+		errMsg  string
+	}{
+		{"duplicate path", "{\"default\": {\n  \"web/secure/base_url\": \"a\",\n  \"web\": {\"secure\": {\"base_url\": \"b\"}}\n}}",
+			errors.Duplicated, "at line 3 column 22, previously defined at line 2 column 3"},
+		{"non-scalar leaf", "{\"stores\": {\"1\": {\n  \"web/secure/base_url\": [\"a\"]\n}}}",
+			errors.CorruptData, "Non-scalar value for path \"web/secure/base_url\" in scope stores/1 at line 2 column 3"},
+		{"short path", "{\"default\": {\"web/secure\": \"a\"}}",
+			errors.CorruptData, "at line 1 column 14"},
+		{"unknown scope", "{\"store\": {}}",
+			errors.CorruptData, "Unknown scope \"store\" at line 1 column 2"},
+		{"invalid scope ID", "{\"websites\": {\"x\": {\"web/secure/base_url\": \"a\"}}}",
+			errors.CorruptData, "at line 1 column 21"},
+		{"no mapping", "[]",
+			errors.CorruptData, "Expecting a mapping of scopes at line 1 column 1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileName, cleanup := writeTempFile(t, "config.json", test.content)
+			defer cleanup()
+			fs, err := storage.NewFile(fileName, storage.FileOptions{})
+			assert.Nil(t, fs)
+			assert.True(t, test.kind.Match(err), "%+v", err)
+			assert.Contains(t, err.Error(), test.errMsg)
+		})
+	}
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		fs, err := storage.NewFile("config.toml", storage.FileOptions{})
+		assert.Nil(t, fs)
+		assert.True(t, errors.NotSupported.Match(err), "%+v", err)
+	})
+	t.Run("file not found", func(t *testing.T) {
+		fs, err := storage.NewFile(filepath.Join("testdata", "not_found.json"), storage.FileOptions{})
+		assert.Nil(t, fs)
+		assert.True(t, errors.ReadFailed.Match(err), "%+v", err)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build csall yaml
+
+package storage
+
+import (
+	"github.com/corestoreio/errors"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+func init() {
+	c := fileCodec{
+		parse:  parseYAMLFile,
+		encode: yaml3.Marshal,
+	}
+	fileCodecs[".yaml"] = c
+	fileCodecs[".yml"] = c
+}
+
+// parseYAMLFile parses the YAML data into a fileNode tree. yaml.v3 is used
+// because yaml.v2 does not provide the positions of the nodes.
+func parseYAMLFile(data []byte) (*fileNode, error) {
+	var doc yaml3.Node
+	if err := yaml3.Unmarshal(data, &doc); err != nil {
+		return nil, errors.CorruptData.New(err, "[config/storage] Invalid YAML")
+	}
+	if len(doc.Content) == 0 {
+		return &fileNode{kind: fileNodeNull}, nil // empty file
+	}
+	return yamlToFileNode(doc.Content[0]), nil
+}
+
+func yamlToFileNode(yn *yaml3.Node) *fileNode {
+	n := &fileNode{pos: filePos{line: yn.Line, column: yn.Column}}
+	switch yn.Kind {
+	case yaml3.AliasNode:
+		an := yamlToFileNode(yn.Alias)
+		an.pos = n.pos
+		return an
+	case yaml3.MappingNode:
+		n.kind = fileNodeMap
+		for i := 0; i+1 < len(yn.Content); i += 2 {
+			k := yn.Content[i]
+			c := yamlToFileNode(yn.Content[i+1])
+			c.key = k.Value
+			c.pos = filePos{line: k.Line, column: k.Column}
+			n.children = append(n.children, c)
+		}
+	case yaml3.SequenceNode:
+		n.kind = fileNodeSeq
+	case yaml3.ScalarNode:
+		if yn.Tag == "!!null" {
+			n.kind = fileNodeNull
+		} else {
+			n.value = yn.Value
+		}
+	}
+	return n
+}
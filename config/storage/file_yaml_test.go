@@ -0,0 +1,94 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build csall yaml
+
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+const fileTestYAML = `default:
+  web:
+    secure:
+      base_url: https://example.com/
+  dev/log/active: true
+websites:
+  1:
+    web/secure/base_url: https://example.de/
+stores:
+  2:
+    dev/js/merge_files: 2.002
+    web/secure/base_url: ~
+`
+
+func TestFile_YAML(t *testing.T) {
+	fileName, cleanup := writeTempFile(t, "config.yaml", fileTestYAML)
+	defer cleanup()
+
+	fs, err := storage.NewFile(fileName, storage.FileOptions{Writable: true})
+	assert.NoError(t, err)
+	defer fs.Close()
+
+	validateFoundGet(t, fs, scope.DefaultTypeID, "web/secure/base_url", "https://example.com/")
+	validateFoundGet(t, fs, scope.DefaultTypeID, "dev/log/active", "true")
+	validateFoundGet(t, fs, scope.Website.WithID(1), "web/secure/base_url", "https://example.de/")
+	validateFoundGet(t, fs, scope.Store.WithID(2), "dev/js/merge_files", "2.002")
+	validateNotFoundGet(t, fs, scope.Store.WithID(1), "web/secure/base_url")
+
+	p := config.MustMakePathWithScope(scope.Store.WithID(3), "carrier/dhl/title")
+	assert.NoError(t, fs.Set(p, []byte("DHL Express")))
+
+	fs2, err := storage.NewFile(fileName, storage.FileOptions{})
+	assert.NoError(t, err)
+	validateFoundGet(t, fs2, scope.Store.WithID(3), "carrier/dhl/title", "DHL Express")
+	validateFoundGet(t, fs2, scope.Website.WithID(1), "web/secure/base_url", "https://example.de/")
+	v, ok, err := fs2.Get(config.MustMakePathWithScope(scope.Store.WithID(2), "web/secure/base_url"))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, v)
+}
+
+func TestFile_YAML_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		kind    errors.Kind
+		errMsg  string
+	}{
+		{"duplicate path", "default:\n  web/secure/base_url: a\n  web:\n    secure:\n      base_url: b\n",
+			errors.Duplicated, "at line 5 column 7, previously defined at line 2 column 3"},
+		{"non-scalar leaf", "stores:\n  1:\n    web/secure/base_url:\n      - a\n",
+			errors.CorruptData, "Non-scalar value for path \"web/secure/base_url\" in scope stores/1 at line 3 column 5"},
+		{"malformed", "default:\n  web/secure/base_url: a\n b: c\n",
+			errors.CorruptData, "Invalid YAML"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileName, cleanup := writeTempFile(t, "config.yml", test.content)
+			defer cleanup()
+			fs, err := storage.NewFile(fileName, storage.FileOptions{})
+			assert.Nil(t, fs)
+			assert.True(t, test.kind.Match(err), "%+v", err)
+			assert.Contains(t, err.Error(), test.errMsg)
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package storage
 
 import (
+	"context"
 	"sync"
 
 	"github.com/corestoreio/errors"
@@ -91,3 +92,26 @@ func (sp *kvmap) Keys(ret ...string) []string {
 	}
 	return ret
 }
+
+// Iterate implements config.Iterator and calls fn for each stored path.
+func (sp *kvmap) Iterate(ctx context.Context, fn func(p config.Path, v []byte) error) error {
+	sp.RLock()
+	kv := make(map[cacheKey]string, len(sp.kv))
+	for k, v := range sp.kv {
+		kv[k] = v
+	}
+	sp.RUnlock()
+	for k, v := range kv {
+		if err := ctx.Err(); err != nil {
+			return errors.WithStack(err)
+		}
+		p, err := config.MakePathWithScope(k.scp, k.route)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := fn(p, []byte(v)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
@@ -86,6 +86,7 @@ func WithLoadFieldMetaYAML(opts ...option) config.LoadDataOption {
 					Default  string
 					Perm     string
 					Websites map[int64]string
+					Group    map[int64]string
 					Stores   map[int64]string
 				}
 
@@ -116,6 +117,7 @@ func WithLoadFieldMetaYAML(opts ...option) config.LoadDataOption {
 						}
 
 						sendFM(fmC, meta.Websites, route, scope.Website)
+						sendFM(fmC, meta.Group, route, scope.Group)
 						sendFM(fmC, meta.Stores, route, scope.Store)
 					}
 				}
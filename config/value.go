@@ -26,6 +26,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/store/scope"
 	"github.com/corestoreio/pkg/util/byteconv"
 )
 
@@ -67,6 +68,8 @@ type Value struct {
 	// statistical flag to identify where a value comes from, e.g. from level2
 	// or from LRU.
 	found uint8
+	// requestedScopeID gets set by Scoped.Get to the scope of the Scoped type.
+	requestedScopeID scope.TypeID
 }
 
 // NewValue makes a new non-pointer value type.
@@ -77,6 +80,22 @@ func NewValue(data []byte) *Value {
 	}
 }
 
+// FoundInScope returns the scope which supplied the value. Returns zero if the
+// value has not been found.
+func (v *Value) FoundInScope() scope.TypeID {
+	if v.found == valFoundNo {
+		return 0
+	}
+	return v.Path.ScopeID
+}
+
+// IsInherited returns true if the value has been found in a parent scope of the
+// requested scope, for example a store requests a value which has been set in
+// the website scope. Only values returned by Scoped.Get can be inherited.
+func (v *Value) IsInherited() bool {
+	return v.found > valFoundNo && v.requestedScopeID > 0 && v.requestedScopeID != v.Path.ScopeID
+}
+
 func (v *Value) init() (found bool, err error) {
 	if v.lastErr != nil || valFoundNo == v.found {
 		return false, v.lastErr
@@ -523,13 +542,13 @@ func (v *Value) UnsafeDuration() (d time.Duration) {
 	return
 }
 
-// Duration converts the underlying converted data into the final type.
-// Uses internally time.ParseDuration
+// Duration converts the underlying converted data into the final type. Accepts
+// a Go duration string like "1h5m" or bare seconds like "90".
 func (v *Value) Duration() (d time.Duration, ok bool, err error) {
 	if ok, err = v.init(); err != nil || !ok {
 		return 0, false, errors.WithStack(err)
 	}
-	d, err = time.ParseDuration(string(v.data))
+	d, err = parseDuration(string(v.data))
 	ok = err == nil
 	return
 }
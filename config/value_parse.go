@@ -0,0 +1,232 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// The Parse* functions of Value return a single error instead of the ok flag.
+// A value which has not been found, is NULL or contains only white spaces
+// returns an error with behaviour kind NotFound. A value which cannot be
+// converted returns an error with behaviour kind NotValid. So callers can apply
+// their default value only for NotFound:
+//		d, err := val.ParseDuration()
+//		if errors.NotFound.Match(err) {
+//			d = 30 * time.Second
+//		} else if err != nil {
+//			return err
+//		}
+
+// parseData returns the trimmed data or a NotFound error.
+func (v *Value) parseData(method string) (string, error) {
+	found, err := v.init()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	s := strings.TrimSpace(string(v.data))
+	if !found || s == "" {
+		return "", errors.NotFound.Newf("[config] Value.%s: Path %q has no value", method, v.Path.String())
+	}
+	return s, nil
+}
+
+// parseDuration parses a Go duration string like "1h5m" or bare seconds like
+// "90".
+func parseDuration(s string) (time.Duration, error) {
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(sec) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// ParseDuration converts the value into a duration. It accepts a Go duration
+// string like "1h5m" or bare seconds like "90".
+func (v *Value) ParseDuration() (time.Duration, error) {
+	s, err := v.parseData("ParseDuration")
+	if err != nil {
+		return 0, err
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, errors.NotValid.New(err, "[config] Value.ParseDuration: Path %q", v.Path.String())
+	}
+	return d, nil
+}
+
+// ParseURL converts the value into an absolute URL. A URL without scheme or
+// host is not valid.
+func (v *Value) ParseURL() (*url.URL, error) {
+	s, err := v.parseData("ParseURL")
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[config] Value.ParseURL: Path %q", v.Path.String())
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, errors.NotValid.Newf("[config] Value.ParseURL: Path %q: URL %q must be absolute", v.Path.String(), s)
+	}
+	return u, nil
+}
+
+// ParseStrs splits the value with the separator sep. An empty sep falls back
+// to the CSVComma. Fields can be quoted with double quotes to contain the
+// separator, a double quote within a quoted field gets escaped by another
+// double quote. White spaces around the fields and empty unquoted fields get
+// removed, e.g. ` a, "b,c" ,,` results in [a b,c].
+func (v *Value) ParseStrs(sep string) ([]string, error) {
+	s, err := v.parseData("ParseStrs")
+	if err != nil {
+		return nil, err
+	}
+	if sep == "" {
+		sep = string(v.CSVComma)
+	}
+	ret, err := splitCSV(s, sep)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[config] Value.ParseStrs: Path %q", v.Path.String())
+	}
+	return ret, nil
+}
+
+// ParseInt64s splits the value with the separator sep, see ParseStrs, and
+// converts each field into an int64.
+func (v *Value) ParseInt64s(sep string) ([]int64, error) {
+	s, err := v.parseData("ParseInt64s")
+	if err != nil {
+		return nil, err
+	}
+	if sep == "" {
+		sep = string(v.CSVComma)
+	}
+	fields, err := splitCSV(s, sep)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[config] Value.ParseInt64s: Path %q", v.Path.String())
+	}
+	ret := make([]int64, len(fields))
+	for i, f := range fields {
+		if ret[i], err = strconv.ParseInt(f, 10, 64); err != nil {
+			return nil, errors.NotValid.New(err, "[config] Value.ParseInt64s: Path %q with index %d and entry %q", v.Path.String(), i, f)
+		}
+	}
+	return ret, nil
+}
+
+// ParseIP converts the value into an IPv4 or IPv6 address.
+func (v *Value) ParseIP() (net.IP, error) {
+	s, err := v.parseData("ParseIP")
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.NotValid.Newf("[config] Value.ParseIP: Path %q: Invalid IP address %q", v.Path.String(), s)
+	}
+	return ip, nil
+}
+
+// ParseCIDR converts the value into an IP network, e.g. "192.168.0.0/16".
+func (v *Value) ParseCIDR() (*net.IPNet, error) {
+	s, err := v.parseData("ParseCIDR")
+	if err != nil {
+		return nil, err
+	}
+	_, ipn, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[config] Value.ParseCIDR: Path %q", v.Path.String())
+	}
+	return ipn, nil
+}
+
+// ParseBool converts the value into a bool. It understands, case insensitive,
+// the Magento conventions 1/0, yes/no and true/false.
+func (v *Value) ParseBool() (bool, error) {
+	s, err := v.parseData("ParseBool")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(s) {
+	case "1", "yes", "true":
+		return true, nil
+	case "0", "no", "false":
+		return false, nil
+	}
+	return false, errors.NotValid.Newf("[config] Value.ParseBool: Path %q: Invalid bool %q", v.Path.String(), s)
+}
+
+// splitCSV splits s by sep and supports double quoted fields. sep must not
+// contain a double quote.
+func splitCSV(s, sep string) ([]string, error) {
+	var ret []string
+	for pos := 0; ; {
+		for pos < len(s) && isASCIISpace(s[pos]) {
+			pos++
+		}
+		if pos < len(s) && s[pos] == '"' {
+			var buf strings.Builder
+			i := pos + 1
+			for {
+				q := strings.IndexByte(s[i:], '"')
+				if q < 0 {
+					return nil, errors.NotValid.Newf("[config] Unterminated quote at position %d", pos)
+				}
+				buf.WriteString(s[i : i+q])
+				i += q + 1
+				if i < len(s) && s[i] == '"' { // escaped quote
+					buf.WriteByte('"')
+					i++
+					continue
+				}
+				break
+			}
+			ret = append(ret, buf.String())
+			for i < len(s) && isASCIISpace(s[i]) {
+				i++
+			}
+			if i == len(s) {
+				return ret, nil
+			}
+			if !strings.HasPrefix(s[i:], sep) {
+				return nil, errors.NotValid.Newf("[config] Unexpected character %q after quoted field at position %d", s[i], i)
+			}
+			pos = i + len(sep)
+			continue
+		}
+
+		end := strings.Index(s[pos:], sep)
+		if end < 0 {
+			if f := strings.TrimSpace(s[pos:]); f != "" {
+				ret = append(ret, f)
+			}
+			return ret, nil
+		}
+		if f := strings.TrimSpace(s[pos : pos+end]); f != "" {
+			ret = append(ret, f)
+		}
+		pos += end + len(sep)
+	}
+}
+
+func isASCIISpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
@@ -0,0 +1,160 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestValue_Parse(t *testing.T) {
+	t.Parallel()
+
+	notFound := NewValue([]byte("x"))
+	notFound.found = valFoundNo
+
+	t.Run("not found", func(t *testing.T) {
+		for _, v := range []*Value{notFound, NewValue(nil), NewValue([]byte(" \t"))} {
+			_, err := v.ParseDuration()
+			assert.ErrorIsKind(t, errors.NotFound, err)
+			_, err = v.ParseURL()
+			assert.ErrorIsKind(t, errors.NotFound, err)
+			_, err = v.ParseStrs(",")
+			assert.ErrorIsKind(t, errors.NotFound, err)
+			_, err = v.ParseInt64s(",")
+			assert.ErrorIsKind(t, errors.NotFound, err)
+			_, err = v.ParseIP()
+			assert.ErrorIsKind(t, errors.NotFound, err)
+			_, err = v.ParseCIDR()
+			assert.ErrorIsKind(t, errors.NotFound, err)
+			_, err = v.ParseBool()
+			assert.ErrorIsKind(t, errors.NotFound, err)
+		}
+	})
+
+	t.Run("Duration", func(t *testing.T) {
+		d, err := NewValue([]byte(`1h5m`)).ParseDuration()
+		assert.NoError(t, err)
+		assert.Exactly(t, time.Hour+5*time.Minute, d)
+
+		d, err = NewValue([]byte(` 90 `)).ParseDuration()
+		assert.NoError(t, err)
+		assert.Exactly(t, 90*time.Second, d)
+
+		_, err = NewValue([]byte(`5 apples`)).ParseDuration()
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		u, err := NewValue([]byte(`https://corestore.io/path?q=1`)).ParseURL()
+		assert.NoError(t, err)
+		assert.Exactly(t, "corestore.io", u.Host)
+
+		_, err = NewValue([]byte(`/relative/path`)).ParseURL()
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		_, err = NewValue([]byte(`http://[::1`)).ParseURL()
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("Strs", func(t *testing.T) {
+		s, err := NewValue([]byte(` a, "b,c" ,,`)).ParseStrs("")
+		assert.NoError(t, err)
+		assert.Exactly(t, []string{"a", "b,c"}, s)
+
+		s, err = NewValue([]byte(`a|"say ""hi"""|`)).ParseStrs("|")
+		assert.NoError(t, err)
+		assert.Exactly(t, []string{"a", `say "hi"`}, s)
+
+		_, err = NewValue([]byte(`a,"b`)).ParseStrs(",")
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		_, err = NewValue([]byte(`"a"b,c`)).ParseStrs(",")
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("Int64s", func(t *testing.T) {
+		i, err := NewValue([]byte(`1; -2 ;3;`)).ParseInt64s(";")
+		assert.NoError(t, err)
+		assert.Exactly(t, []int64{1, -2, 3}, i)
+
+		_, err = NewValue([]byte(`1,x,3`)).ParseInt64s(",")
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("IP", func(t *testing.T) {
+		ip, err := NewValue([]byte(`::1`)).ParseIP()
+		assert.NoError(t, err)
+		assert.True(t, ip.IsLoopback())
+
+		_, err = NewValue([]byte(`256.1.1.1`)).ParseIP()
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("CIDR", func(t *testing.T) {
+		ipn, err := NewValue([]byte(`192.168.1.7/16`)).ParseCIDR()
+		assert.NoError(t, err)
+		assert.Exactly(t, "192.168.0.0/16", ipn.String())
+
+		_, err = NewValue([]byte(`192.168.1.7`)).ParseCIDR()
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("Bool", func(t *testing.T) {
+		for _, s := range []string{"1", "yes", "YES", "true", "True"} {
+			b, err := NewValue([]byte(s)).ParseBool()
+			assert.NoError(t, err)
+			assert.True(t, b, "%q", s)
+		}
+		for _, s := range []string{"0", "no", "No", "false", "FALSE"} {
+			b, err := NewValue([]byte(s)).ParseBool()
+			assert.NoError(t, err)
+			assert.False(t, b, "%q", s)
+		}
+		_, err := NewValue([]byte(`2`)).ParseBool()
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
+
+// FuzzSplitCSV checks that splitCSV never panics and that quoting the returned
+// fields results again in the same fields.
+func FuzzSplitCSV(f *testing.F) {
+	for _, s := range []string{
+		``, `a,b`, ` a , b `, `a,,b,`, `,`, `"a,b",c`, `"a""b"`, `"unterminated`,
+		`"a" x`, `a"b,c`, `  "" , ""`, "\t1,\n2\r,3", `ä,ö;ü`,
+	} {
+		f.Add(s, ",")
+		f.Add(s, ";")
+	}
+	f.Fuzz(func(t *testing.T, s, sep string) {
+		if sep != "," && sep != ";" && sep != "||" {
+			t.Skip()
+		}
+		fields, err := splitCSV(s, sep)
+		if err != nil {
+			assert.ErrorIsKind(t, errors.NotValid, err)
+			return
+		}
+		quoted := make([]string, len(fields))
+		for i, fl := range fields {
+			quoted[i] = `"` + strings.Replace(fl, `"`, `""`, -1) + `"`
+		}
+		fields2, err := splitCSV(strings.Join(quoted, sep), sep)
+		assert.NoError(t, err)
+		assert.Exactly(t, fields, fields2)
+	})
+}
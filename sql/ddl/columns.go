@@ -81,38 +81,68 @@ const (
 	TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, COLUMN_DEFAULT, IS_NULLABLE,
 		DATA_TYPE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE,
 		COLUMN_TYPE, COLUMN_KEY, EXTRA, COLUMN_COMMENT, IS_GENERATED, GENERATION_EXPRESSION
-	 FROM information_schema.COLUMNS WHERE TABLE_SCHEMA=DATABASE()`
+	 FROM information_schema.COLUMNS WHERE TABLE_SCHEMA=`
 	// DMLLoadColumns specifies the data manipulation language for retrieving
 	// all columns in the current database for a specific table. TABLE_NAME is
 	// always lower case.
-	selTablesColumns    = selTablesColumnsBaseSelect + ` AND TABLE_NAME IN ? ORDER BY TABLE_NAME, ORDINAL_POSITION`
-	selAllTablesColumns = selTablesColumnsBaseSelect + ` ORDER BY TABLE_NAME, ORDINAL_POSITION`
+	selTablesColumns    = selTablesColumnsBaseSelect + `DATABASE() AND TABLE_NAME IN ? ORDER BY TABLE_NAME, ORDINAL_POSITION`
+	selAllTablesColumns = selTablesColumnsBaseSelect + `DATABASE() ORDER BY TABLE_NAME, ORDINAL_POSITION`
+	// selSchemaTablesColumns same as selTablesColumns but for tables in
+	// another database on the same server.
+	selSchemaTablesColumns = selTablesColumnsBaseSelect + `? AND TABLE_NAME IN ? ORDER BY TABLE_NAME, ORDINAL_POSITION`
 )
 
 // LoadColumns returns all columns from a list of table names in the current
 // database. Map key contains the table name. Returns a NotFound error if the
 // table is not available. All columns from all tables gets selected when you
-// don't provide the argument `tables`.
+// don't provide the argument `tables`. A table name can be qualified with its
+// database name, like `otherdb.table_name`, to load the columns from another
+// database on the same server. The map key of such a table contains then the
+// qualified name.
 func LoadColumns(ctx context.Context, db dml.Querier, tables ...string) (map[string]Columns, error) {
-	var rows *sql.Rows
+	tc := make(map[string]Columns)
 
 	if len(tables) == 0 {
-		var err error
-		rows, err = db.QueryContext(ctx, selAllTablesColumns)
+		rows, err := db.QueryContext(ctx, selAllTablesColumns)
 		if err != nil {
 			return nil, errors.Wrapf(err, "[ddl] LoadColumns QueryContext for tables %v", tables)
 		}
-	} else {
-		sqlStr, _, err := dml.Interpolate(selTablesColumns).Strs(tables...).ToSQL()
+		if err := scanColumns(rows, "", tc); err != nil {
+			return nil, errors.Wrapf(err, "[ddl] Scan Query for tables: %v", tables)
+		}
+	}
+
+	schemas, schemaTables := groupBySchema(tables)
+	for _, schema := range schemas {
+		tblNames := schemaTables[schema]
+		var sqlStr string
+		var err error
+		if schema == "" {
+			sqlStr, _, err = dml.Interpolate(selTablesColumns).Strs(tblNames...).ToSQL()
+		} else {
+			sqlStr, _, err = dml.Interpolate(selSchemaTablesColumns).Str(schema).Strs(tblNames...).ToSQL()
+		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "[ddl] LoadColumns dml.ExpandPlaceHolders for tables %v", tables)
 		}
-		rows, err = db.QueryContext(ctx, sqlStr)
+		rows, err := db.QueryContext(ctx, sqlStr)
 		if err != nil {
 			return nil, errors.Wrapf(err, "[ddl] LoadColumns QueryContext for tables %v with WHERE clause", tables)
 		}
+		if err := scanColumns(rows, schema, tc); err != nil {
+			return nil, errors.Wrapf(err, "[ddl] Scan Query for tables: %v", tables)
+		}
+	}
+
+	if len(tc) == 0 {
+		return nil, errors.NotFound.Newf("[ddl] Tables %v not found", tables)
 	}
-	var err error
+	return tc, nil
+}
+
+// scanColumns scans all rows into tc and closes the rows. If schema is not
+// empty, the map key gets qualified with the schema name.
+func scanColumns(rows *sql.Rows, schema string, tc map[string]Columns) (err error) {
 	defer func() {
 		// Not testable with the sqlmock package :-(
 		if err2 := rows.Close(); err2 != nil && err == nil {
@@ -120,18 +150,18 @@ func LoadColumns(ctx context.Context, db dml.Querier, tables ...string) (map[str
 		}
 	}()
 
-	tc := make(map[string]Columns)
 	rc := new(dml.ColumnMap)
 	for rows.Next() {
 		if err = rc.Scan(rows); err != nil {
-			return nil, errors.Wrapf(err, "[ddl] Scan Query for tables: %v", tables)
+			return errors.WithStack(err)
 		}
 		var c *Column
 		var tableName string
 		c, tableName, err = newColumn(rc)
 		if err != nil {
-			return nil, errors.WithStack(err)
+			return errors.WithStack(err)
 		}
+		tableName = qualifiedTableName(schema, tableName)
 
 		if _, ok := tc[tableName]; !ok {
 			tc[tableName] = make(Columns, 0, 10)
@@ -140,13 +170,7 @@ func LoadColumns(ctx context.Context, db dml.Querier, tables ...string) (map[str
 		c.DataType = strings.ToLower(c.DataType)
 		tc[tableName] = append(tc[tableName], c)
 	}
-	if err = rows.Err(); err != nil {
-		return nil, errors.WithStack(err)
-	}
-	if len(tc) == 0 {
-		return nil, errors.NotFound.Newf("[ddl] Tables %v not found", tables)
-	}
-	return tc, err
+	return errors.WithStack(rows.Err())
 }
 
 // Filter filters the columns by predicate f and appends the column pointers to
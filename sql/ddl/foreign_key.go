@@ -182,6 +182,17 @@ func (cc KeyColumnUsageCollection) ColumnNames(ret ...string) []string {
 	return ret
 }
 
+// EachForeignKey calls fn for each entry which references another table.
+// Implements interface pseudo.ForeignKeyIterator to generate fake data with
+// consistent foreign keys.
+func (cc KeyColumnUsageCollection) EachForeignKey(fn func(table, column, refTable, refColumn string)) {
+	for _, e := range cc.Data {
+		if e.ReferencedTableName.Valid && e.ReferencedColumnName.Valid {
+			fn(e.TableName, e.ColumnName, e.ReferencedTableName.Data, e.ReferencedColumnName.Data)
+		}
+	}
+}
+
 // LoadKeyColumnUsage returns all foreign key columns from a list of table names
 // in the current database. Map key contains TABLE_NAME and value
 // contains all of the table foreign keys. All columns from all tables gets
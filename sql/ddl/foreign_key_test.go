@@ -580,6 +580,23 @@ func TestReverseKeyColumnUsage(t *testing.T) {
 	}
 }
 
+func TestKeyColumnUsageCollection_EachForeignKey(t *testing.T) {
+	kcuc := KeyColumnUsageCollection{Data: []*KeyColumnUsage{
+		{TableName: "store", ColumnName: "store_id"},
+		{
+			TableName:            "store",
+			ColumnName:           "website_id",
+			ReferencedTableName:  null.MakeString("store_website"),
+			ReferencedColumnName: null.MakeString("website_id"),
+		},
+	}}
+	var have []string
+	kcuc.EachForeignKey(func(table, column, refTable, refColumn string) {
+		have = append(have, table+"."+column+" => "+refTable+"."+refColumn)
+	})
+	assert.Exactly(t, []string{"store.website_id => store_website.website_id"}, have)
+}
+
 func TestDisableForeignKeys(t *testing.T) {
 	db, mock := dmltest.MockDB(t)
 	defer dmltest.MockClose(t, db, mock)
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"crypto/md5"
 	"fmt"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -221,3 +222,38 @@ var translatedAbbreviations = strings.NewReplacer(
 	"varchar", "vchr",
 	"website", "ws",
 )
+
+// splitQualifiedName splits a schema qualified table name like
+// `otherdb.table_name` into its schema and table name. An unqualified name
+// returns an empty schema.
+func splitQualifiedName(name string) (schema, table string) {
+	if i := strings.IndexByte(name, '.'); i > 0 && i+1 < len(name) {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// qualifiedTableName joins schema and table name with a dot. An empty schema
+// returns the bare table name.
+func qualifiedTableName(schema, table string) string {
+	if schema == "" {
+		return table
+	}
+	return schema + "." + table
+}
+
+// groupBySchema groups the possibly qualified table names by their schema. The
+// returned schemas are sorted and the current database, an empty schema,
+// comes first.
+func groupBySchema(tableNames []string) (schemas []string, schemaTables map[string][]string) {
+	schemaTables = make(map[string][]string, 2)
+	for _, tn := range tableNames {
+		schema, table := splitQualifiedName(tn)
+		if _, ok := schemaTables[schema]; !ok {
+			schemas = append(schemas, schema)
+		}
+		schemaTables[schema] = append(schemaTables[schema], table)
+	}
+	sort.Strings(schemas)
+	return schemas, schemaTables
+}
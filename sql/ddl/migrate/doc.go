@@ -0,0 +1,29 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrate runs versioned schema migrations which live next to the Go
+// code.
+//
+// Migrations get registered as ordered structs with functions for the up and
+// down direction. The applied versions get tracked in the table
+// `schema_version`, created on demand. An advisory lock, acquired with
+// GET_LOCK, prevents concurrent application instances from running the
+// migrations at the same time.
+//
+// MySQL commits DDL statements implicitly, hence they cannot be rolled back
+// within a transaction. A migration which fails in the middle of its DDL
+// statements leaves its version marked as dirty. Further calls to Migrate get
+// refused until the database has been fixed manually and the migration has
+// been rolled back with Rollback.
+package migrate
@@ -0,0 +1,408 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/storage/null"
+)
+
+const (
+	// DefaultTableName defines the table which tracks the applied versions.
+	DefaultTableName = "schema_version"
+	// DefaultLockTimeout defines how long to wait for the advisory lock.
+	DefaultLockTimeout = 30 * time.Second
+	// Latest as target for Migrate applies all registered migrations.
+	Latest uint64 = math.MaxUint64
+)
+
+// Migration defines a versioned change of the database schema. Exactly one
+// of the fields Up, UpTx or UpSQL must be set and at most one of Down, DownTx
+// or DownSQL. A migration without a down direction cannot be rolled back.
+type Migration struct {
+	// Version must be unique and greater zero. Migrations run ordered by their
+	// version, for example a timestamp like 20210530142500.
+	Version     uint64
+	Description string
+	// Up and Down receive the connection pool. Use them for DDL statements,
+	// which MySQL commits implicitly, or for more complex logic. An error
+	// marks the version as dirty.
+	Up, Down func(ctx context.Context, db *dml.ConnPool) error
+	// UpTx and DownTx run in the same transaction as the update of the
+	// schema_version table. An error rolls back all changes. Use them only for
+	// DML statements, DDL statements commit the transaction implicitly.
+	UpTx, DownTx func(ctx context.Context, tx *dml.Tx) error
+	// UpSQL and DownSQL contain statements which run one after another. A
+	// failing statement gets reported as *StatementError and marks the version
+	// as dirty.
+	UpSQL, DownSQL []string
+}
+
+func (m Migration) validate() error {
+	if m.Version == 0 {
+		return errors.NotValid.Newf("[migrate] Migration %q must have a version greater zero", m.Description)
+	}
+	if countSet(m.Up != nil, m.UpTx != nil, len(m.UpSQL) > 0) != 1 {
+		return errors.NotValid.Newf("[migrate] Migration %d must have exactly one of Up, UpTx or UpSQL", m.Version)
+	}
+	if countSet(m.Down != nil, m.DownTx != nil, len(m.DownSQL) > 0) > 1 {
+		return errors.NotValid.Newf("[migrate] Migration %d must have only one of Down, DownTx or DownSQL", m.Version)
+	}
+	return nil
+}
+
+func (m Migration) hasDown() bool {
+	return m.Down != nil || m.DownTx != nil || len(m.DownSQL) > 0
+}
+
+func countSet(bs ...bool) (n int) {
+	for _, b := range bs {
+		if b {
+			n++
+		}
+	}
+	return n
+}
+
+// StatementError reports the failed statement of an UpSQL or DownSQL
+// migration. All statements before Index have been applied and the version
+// has been marked as dirty.
+type StatementError struct {
+	Version uint64
+	Down    bool
+	// Index of the failed statement.
+	Index     int
+	Statement string
+	Err       error
+}
+
+func (se *StatementError) Error() string {
+	dir := "up"
+	if se.Down {
+		dir = "down"
+	}
+	return fmt.Sprintf("[migrate] Migration %d %s failed at statement %d %q, the previous %d statement(s) have been applied and the version is dirty: %s",
+		se.Version, dir, se.Index, se.Statement, se.Index, se.Err)
+}
+
+// Cause returns the error of the database.
+func (se *StatementError) Cause() error { return se.Err }
+
+// Status describes a registered or applied migration.
+type Status struct {
+	Version     uint64
+	Description string
+	Applied     bool
+	AppliedAt   null.Time
+	// Dirty reports a migration which failed partially.
+	Dirty bool
+	// Registered reports whether the migration is known to the Migrator. An
+	// applied but unregistered version might come from a newer release of the
+	// application.
+	Registered bool
+}
+
+// statuses gets loaded from the schema_version table.
+type statuses []Status
+
+// MapColumns implements dml.ColumnMapper interface and scans a single row.
+func (ss *statuses) MapColumns(rc *dml.ColumnMap) error {
+	s := Status{Applied: true}
+	for rc.Next(4) {
+		switch col := rc.Column(); col {
+		case "version", "0":
+			rc.Uint64(&s.Version)
+		case "description", "1":
+			rc.String(&s.Description)
+		case "dirty", "2":
+			rc.Bool(&s.Dirty)
+		case "applied_at", "3":
+			rc.NullTime(&s.AppliedAt)
+		default:
+			return errors.NotFound.Newf("[migrate] Column %q not found", col)
+		}
+	}
+	*ss = append(*ss, s)
+	return errors.WithStack(rc.Err())
+}
+
+// Options configures the Migrator.
+type Options struct {
+	// TableName defaults to DefaultTableName.
+	TableName string
+	// LockTimeout defaults to DefaultLockTimeout.
+	LockTimeout time.Duration
+}
+
+// Migrator applies and rolls back the registered migrations. Safe for
+// concurrent use, also across several application instances.
+type Migrator struct {
+	tm          *ddl.Tables
+	table       *ddl.Table
+	lockTimeout time.Duration
+	migrations  []Migration // sorted by version
+}
+
+// NewMigrator creates a new Migrator for the database connection of tm and
+// registers the table to track the versions in tm. The migrations can be
+// passed in any order.
+func NewMigrator(tm *ddl.Tables, o Options, ms ...Migration) (*Migrator, error) {
+	if tm.ConnPool == nil {
+		return nil, errors.NotValid.Newf("[migrate] Tables requires a connection pool")
+	}
+	if o.TableName == "" {
+		o.TableName = DefaultTableName
+	}
+	if o.LockTimeout <= 0 {
+		o.LockTimeout = DefaultLockTimeout
+	}
+	if err := dml.IsValidIdentifier(o.TableName); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	m := &Migrator{
+		tm:          tm,
+		lockTimeout: o.LockTimeout,
+		migrations:  append([]Migration(nil), ms...),
+	}
+	sort.Slice(m.migrations, func(i, j int) bool { return m.migrations[i].Version < m.migrations[j].Version })
+	for i, mg := range m.migrations {
+		if err := mg.validate(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if i > 0 && m.migrations[i-1].Version == mg.Version {
+			return nil, errors.Duplicated.Newf("[migrate] Migration version %d has been registered twice", mg.Version)
+		}
+	}
+
+	if err := tm.Upsert(ddl.NewTable(o.TableName,
+		&ddl.Column{Field: "version", Pos: 1, Null: "NO", DataType: "bigint", ColumnType: "bigint(20) unsigned", Key: "PRI"},
+		&ddl.Column{Field: "description", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)"},
+		&ddl.Column{Field: "dirty", Pos: 3, Null: "NO", DataType: "tinyint", ColumnType: "tinyint(1)"},
+		&ddl.Column{Field: "applied_at", Pos: 4, Null: "NO", DataType: "datetime", ColumnType: "datetime", Default: null.MakeString("current_timestamp()")},
+	)); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	m.table = tm.MustTable(o.TableName)
+	return m, nil
+}
+
+// queryBuilder gets implemented by dml.ConnPool, dml.Conn and dml.Tx.
+type queryBuilder interface {
+	WithQueryBuilder(qb dml.QueryBuilder, opts ...dml.DBRFunc) *dml.DBR
+}
+
+// Migrate applies all pending migrations up to and including the target
+// version, see constant Latest. Applied migrations with a higher version than
+// target get rolled back. Migrate refuses to run if a version is dirty.
+func (m *Migrator) Migrate(ctx context.Context, target uint64) error {
+	return m.withLock(ctx, func(conn *dml.Conn, applied []Status) error {
+		for _, s := range applied {
+			if s.Dirty {
+				return errors.NotValid.Newf("[migrate] Version %d is dirty. Fix the database manually and call Rollback.", s.Version)
+			}
+		}
+		done := make(map[uint64]bool, len(applied))
+		for i := len(applied) - 1; i >= 0; i-- {
+			done[applied[i].Version] = true
+			if applied[i].Version > target {
+				if err := m.rollback(ctx, conn, applied[i].Version); err != nil {
+					return err
+				}
+			}
+		}
+		for _, mg := range m.migrations {
+			if mg.Version > target {
+				break
+			}
+			if !done[mg.Version] {
+				if err := m.run(ctx, conn, mg, false); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Rollback rolls back the last applied migrations, including a dirty one.
+// Argument steps defines the number of migrations.
+func (m *Migrator) Rollback(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *dml.Conn, applied []Status) error {
+		for i := len(applied) - 1; i >= 0 && steps > 0; i, steps = i-1, steps-1 {
+			if err := m.rollback(ctx, conn, applied[i].Version); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status returns the state of all registered and applied migrations, ordered
+// by version.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	// The primary has always the latest state.
+	ctx = dml.WithContextQueryOptions(ctx, dml.QueryOptions{ForcePrimary: true})
+	if err := m.createTable(ctx, m.tm.ConnPool); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	applied, err := m.loadApplied(ctx, m.tm.ConnPool)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	ss := make([]Status, 0, len(m.migrations)+len(applied))
+	ai := 0
+	for _, mg := range m.migrations {
+		for ; ai < len(applied) && applied[ai].Version < mg.Version; ai++ {
+			ss = append(ss, applied[ai])
+		}
+		s := Status{Version: mg.Version, Description: mg.Description, Registered: true}
+		if ai < len(applied) && applied[ai].Version == mg.Version {
+			s.Applied, s.AppliedAt, s.Dirty = true, applied[ai].AppliedAt, applied[ai].Dirty
+			ai++
+		}
+		ss = append(ss, s)
+	}
+	return append(ss, applied[ai:]...), nil
+}
+
+// withLock acquires the advisory lock, creates the version table if needed
+// and calls fn with the applied versions. The lock name contains the
+// database name because GET_LOCK works server wide. The lock belongs to the
+// session, hence all statements, except those of Migration.Up and
+// Migration.Down, run on the same connection.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *dml.Conn, applied []Status) error) (err error) {
+	conn, err := m.tm.ConnPool.Conn(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		if errC := conn.Close(); errC != nil && err == nil {
+			err = errors.WithStack(errC)
+		}
+	}()
+
+	lockName := m.table.Name
+	got, _, err := conn.WithQueryBuilder(dml.QuerySQL("SELECT GET_LOCK(CONCAT_WS('.',DATABASE(),?),?)")).
+		LoadNullInt64(ctx, lockName, int64(m.lockTimeout/time.Second))
+	if err != nil {
+		return errors.Wrapf(err, "[migrate] Failed to acquire the lock %q", lockName)
+	}
+	if got.Int64 != 1 {
+		return errors.Locked.Newf("[migrate] Lock %q is held by another process, waited %s", lockName, m.lockTimeout)
+	}
+	defer func() {
+		// Use a fresh context because ctx might have been canceled.
+		_, _, errR := conn.WithQueryBuilder(dml.QuerySQL("SELECT RELEASE_LOCK(CONCAT_WS('.',DATABASE(),?))")).
+			LoadNullInt64(context.Background(), lockName)
+		if errR != nil && err == nil {
+			err = errors.Wrapf(errR, "[migrate] Failed to release the lock %q", lockName)
+		}
+	}()
+
+	if err := m.createTable(ctx, conn); err != nil {
+		return errors.WithStack(err)
+	}
+	applied, err := m.loadApplied(ctx, conn)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return fn(conn, applied)
+}
+
+func (m *Migrator) createTable(ctx context.Context, qb queryBuilder) error {
+	_, err := qb.WithQueryBuilder(dml.QuerySQL("CREATE TABLE IF NOT EXISTS " + dml.Quoter.Name(m.table.Name) + ` (
+  version BIGINT(20) UNSIGNED NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  dirty TINYINT(1) NOT NULL DEFAULT 0,
+  applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (version)
+) ENGINE=InnoDB`)).ExecContext(ctx)
+	return errors.Wrapf(err, "[migrate] Failed to create table %q", m.table.Name)
+}
+
+// loadApplied loads the applied versions in ascending order.
+func (m *Migrator) loadApplied(ctx context.Context, qb queryBuilder) ([]Status, error) {
+	var ss statuses
+	_, err := qb.WithQueryBuilder(m.table.Select("*").OrderBy("version")).Load(ctx, &ss)
+	return ss, errors.Wrapf(err, "[migrate] Failed to load the applied versions from table %q", m.table.Name)
+}
+
+func (m *Migrator) rollback(ctx context.Context, conn *dml.Conn, version uint64) error {
+	for _, mg := range m.migrations {
+		if mg.Version == version {
+			if !mg.hasDown() {
+				return errors.NotImplemented.Newf("[migrate] Migration %d cannot be rolled back", version)
+			}
+			return m.run(ctx, conn, mg, true)
+		}
+	}
+	return errors.NotFound.Newf("[migrate] Version %d has been applied but is not registered", version)
+}
+
+// run executes one direction of a migration and updates the version table.
+// Migrations outside of a transaction mark their version as dirty while
+// running.
+func (m *Migrator) run(ctx context.Context, conn *dml.Conn, mg Migration, down bool) error {
+	fn, fnTx, stmts := mg.Up, mg.UpTx, mg.UpSQL
+	if down {
+		fn, fnTx, stmts = mg.Down, mg.DownTx, mg.DownSQL
+	}
+
+	if fnTx != nil {
+		err := conn.Transaction(ctx, nil, func(tx *dml.Tx) error {
+			if err := fnTx(ctx, tx); err != nil {
+				return err
+			}
+			return m.record(ctx, tx, mg, down, false)
+		})
+		return errors.Wrapf(err, "[migrate] Migration %d failed and has been rolled back", mg.Version)
+	}
+
+	if err := m.record(ctx, conn, mg, false, true); err != nil {
+		return errors.WithStack(err)
+	}
+	if fn != nil {
+		if err := fn(ctx, m.tm.ConnPool); err != nil {
+			return errors.Wrapf(err, "[migrate] Migration %d failed, the version is dirty", mg.Version)
+		}
+	}
+	for i, stmt := range stmts {
+		if _, err := conn.DB.ExecContext(ctx, stmt); err != nil {
+			return &StatementError{Version: mg.Version, Down: down, Index: i, Statement: stmt, Err: err}
+		}
+	}
+	return errors.WithStack(m.record(ctx, conn, mg, down, false))
+}
+
+// record inserts or updates the version or deletes it after a rollback.
+func (m *Migrator) record(ctx context.Context, qb queryBuilder, mg Migration, deleteVersion, dirty bool) error {
+	var err error
+	if deleteVersion {
+		_, err = qb.WithQueryBuilder(m.table.DeleteByPK()).ExecContext(ctx, mg.Version)
+	} else {
+		_, err = qb.WithQueryBuilder(dml.NewInsert(m.table.Name).AddColumns("version", "description", "dirty").
+			AddOnDuplicateKey(dml.Column("dirty").Values())).ExecContext(ctx, mg.Version, mg.Description, dirty)
+	}
+	return errors.Wrapf(err, "[migrate] Failed to record version %d in table %q", mg.Version, m.table.Name)
+}
@@ -0,0 +1,134 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build db
+
+package migrate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/ddl/migrate"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestMigrator_Integration(t *testing.T) {
+	dbc := dmltest.MustConnectDB(t)
+	defer dmltest.Close(t, dbc)
+	ctx := context.Background()
+
+	cleanup := func() {
+		_, err := dbc.DB.ExecContext(ctx, "DROP TABLE IF EXISTS `migrate_store`, `core_configuration`, `migrate_schema_version`")
+		assert.NoError(t, err)
+	}
+	cleanup()
+	defer cleanup()
+
+	tm := ddl.MustNewTables(ddl.WithConnPool(dbc))
+	m, err := migrate.NewMigrator(tm, migrate.Options{TableName: "migrate_schema_version"},
+		migrate.Migration{
+			Version:     1,
+			Description: "create core_configuration",
+			UpSQL: []string{"CREATE TABLE `core_configuration` (" +
+				"`config_id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+				"`scope` varchar(8) NOT NULL DEFAULT 'default'," +
+				"`scope_id` int(11) NOT NULL DEFAULT 0," +
+				"`path` varchar(255) NOT NULL," +
+				"`value` text DEFAULT NULL," +
+				"PRIMARY KEY (`config_id`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"},
+			DownSQL: []string{"DROP TABLE `core_configuration`"},
+		},
+		migrate.Migration{
+			Version:     2,
+			Description: "core_configuration expires and store table",
+			UpSQL: []string{
+				"ALTER TABLE `core_configuration` ADD COLUMN `expires` DATETIME NULL",
+				"ALTER TABLE `core_configuration` ADD UNIQUE KEY `CORE_CONFIG_SCOPE_PATH` (`scope`,`scope_id`,`expires`,`path`)",
+				"CREATE TABLE `migrate_store` (`store_id` smallint(5) unsigned NOT NULL, PRIMARY KEY (`store_id`)) ENGINE=InnoDB",
+			},
+			Down: func(ctx context.Context, db *dml.ConnPool) error {
+				for _, stmt := range []string{
+					"DROP TABLE IF EXISTS `migrate_store`",
+					"ALTER TABLE `core_configuration` DROP INDEX `CORE_CONFIG_SCOPE_PATH`",
+					"ALTER TABLE `core_configuration` DROP COLUMN `expires`",
+				} {
+					if _, err := db.DB.ExecContext(ctx, stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		migrate.Migration{
+			Version:     3,
+			Description: "core_configuration defaults",
+			UpTx: func(ctx context.Context, tx *dml.Tx) error {
+				_, err := tx.WithQueryBuilder(dml.NewInsert("core_configuration").AddColumns("path", "value").SetRowCount(2)).
+					ExecContext(ctx, "web/secure/base_url", "https://", "web/unsecure/base_url", "http://")
+				return err
+			},
+			DownTx: func(ctx context.Context, tx *dml.Tx) error {
+				_, err := tx.WithQueryBuilder(dml.NewDelete("core_configuration").Where(dml.Column("path").Like().Str("web/%"))).
+					ExecContext(ctx)
+				return err
+			},
+		},
+	)
+	assert.NoError(t, err)
+
+	assertApplied := func(want ...bool) {
+		t.Helper()
+		ss, err := m.Status(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, ss, len(want))
+		for i, s := range ss {
+			assert.Exactly(t, want[i], s.Applied, "Version %d", s.Version)
+			assert.False(t, s.Dirty, "Version %d", s.Version)
+		}
+	}
+	countConfig := func() int64 {
+		t.Helper()
+		n, _, err := dbc.WithQueryBuilder(dml.NewSelect().Count().From("core_configuration")).LoadNullInt64(ctx)
+		assert.NoError(t, err)
+		return n.Int64
+	}
+
+	assertApplied(false, false, false)
+
+	assert.NoError(t, m.Migrate(ctx, migrate.Latest))
+	assertApplied(true, true, true)
+	assert.Exactly(t, int64(2), countConfig())
+
+	assert.NoError(t, m.Rollback(ctx, 1))
+	assertApplied(true, true, false)
+	assert.Exactly(t, int64(0), countConfig())
+
+	assert.NoError(t, m.Migrate(ctx, 1))
+	assertApplied(true, false, false)
+
+	assert.NoError(t, m.Migrate(ctx, migrate.Latest))
+	assertApplied(true, true, true)
+	assert.Exactly(t, int64(2), countConfig())
+
+	assert.NoError(t, m.Migrate(ctx, 0))
+	assertApplied(false, false, false)
+
+	assert.NoError(t, m.Migrate(ctx, migrate.Latest))
+	assertApplied(true, true, true)
+}
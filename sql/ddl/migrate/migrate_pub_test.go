@@ -0,0 +1,237 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/ddl/migrate"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+const (
+	sqlGetLock     = "SELECT GET_LOCK(CONCAT_WS('.',DATABASE(),?),?)"
+	sqlReleaseLock = "SELECT RELEASE_LOCK(CONCAT_WS('.',DATABASE(),?))"
+	sqlSelect      = "SELECT `version`, `description`, `dirty`, `applied_at` FROM `schema_version` AS `main_table` ORDER BY `version`"
+	sqlInsert      = "INSERT INTO `schema_version` (`version`,`description`,`dirty`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `dirty`=VALUES(`dirty`)"
+	sqlDelete      = "DELETE FROM `schema_version` WHERE (`version` = ?)"
+)
+
+var statusColumns = []string{"version", "description", "dirty", "applied_at"}
+
+func expectLock(dbMock sqlmock.Sqlmock, applied *sqlmock.Rows) {
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlGetLock)).WithArgs("schema_version", 30).
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	dbMock.ExpectExec("CREATE TABLE IF NOT EXISTS `schema_version`").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlSelect)).WillReturnRows(applied)
+}
+
+func expectUnlock(dbMock sqlmock.Sqlmock) {
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlReleaseLock)).WithArgs("schema_version").
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+}
+
+func newMigrator(t *testing.T, dbc *dml.ConnPool, ms ...migrate.Migration) *migrate.Migrator {
+	m, err := migrate.NewMigrator(ddl.MustNewTables(ddl.WithConnPool(dbc)), migrate.Options{}, ms...)
+	assert.NoError(t, err)
+	return m
+}
+
+var testMigrations = []migrate.Migration{
+	{
+		Version:     2,
+		Description: "config defaults",
+		UpTx: func(ctx context.Context, tx *dml.Tx) error {
+			_, err := tx.WithQueryBuilder(dml.NewInsert("core_configuration").AddColumns("path", "value")).
+				ExecContext(ctx, "web/secure/base_url", "https://")
+			return err
+		},
+		DownTx: func(ctx context.Context, tx *dml.Tx) error {
+			_, err := tx.WithQueryBuilder(dml.NewDelete("core_configuration").Where(dml.Column("path").PlaceHolder())).
+				ExecContext(ctx, "web/secure/base_url")
+			return err
+		},
+	},
+	{
+		Version:     1,
+		Description: "config expires",
+		UpSQL: []string{
+			"ALTER TABLE `core_configuration` ADD COLUMN `expires` DATETIME NULL",
+			"ALTER TABLE `core_configuration` ADD INDEX `IDX_EXPIRES` (`expires`)",
+		},
+		DownSQL: []string{"ALTER TABLE `core_configuration` DROP COLUMN `expires`"},
+	},
+}
+
+func TestNewMigrator(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+	tm := ddl.MustNewTables(ddl.WithConnPool(dbc))
+	up := func(context.Context, *dml.ConnPool) error { return nil }
+
+	tests := []struct {
+		ms      []migrate.Migration
+		errKind errors.Kind
+	}{
+		{[]migrate.Migration{{Up: up}}, errors.NotValid},
+		{[]migrate.Migration{{Version: 1}}, errors.NotValid},
+		{[]migrate.Migration{{Version: 1, Up: up, UpSQL: []string{"SELECT 1"}}}, errors.NotValid},
+		{[]migrate.Migration{{Version: 1, Up: up, Down: up, DownSQL: []string{"SELECT 1"}}}, errors.NotValid},
+		{[]migrate.Migration{{Version: 1, Up: up}, {Version: 1, Up: up}}, errors.Duplicated},
+	}
+	for i, test := range tests {
+		_, err := migrate.NewMigrator(tm, migrate.Options{}, test.ms...)
+		assert.True(t, test.errKind.Match(err), "Index %d: %+v", i, err)
+	}
+
+	_, err := migrate.NewMigrator(ddl.MustNewTables(), migrate.Options{})
+	assert.True(t, errors.NotValid.Match(err), "%+v", err)
+
+	m, err := migrate.NewMigrator(tm, migrate.Options{}, testMigrations...)
+	assert.NoError(t, err)
+	assert.NotNil(t, m)
+	assert.True(t, tm.MustTable("schema_version").HasColumn("dirty"), "schema_version must be registered")
+}
+
+func TestMigrator_Migrate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all up", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		expectLock(dbMock, sqlmock.NewRows(statusColumns))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(sqlInsert)).WithArgs(1, "config expires", true).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec("ADD COLUMN `expires`").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("ADD INDEX `IDX_EXPIRES`").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(sqlInsert)).WithArgs(1, "config expires", false).WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("INSERT INTO `core_configuration`").WithArgs("web/secure/base_url", "https://").WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(sqlInsert)).WithArgs(2, "config defaults", false).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+		expectUnlock(dbMock)
+
+		assert.NoError(t, newMigrator(t, dbc, testMigrations...).Migrate(ctx, migrate.Latest))
+	})
+
+	t.Run("down to target", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		expectLock(dbMock, sqlmock.NewRows(statusColumns).AddRow(1, "config expires", 0, nil).AddRow(2, "config defaults", 0, nil))
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("DELETE FROM `core_configuration`").WithArgs("web/secure/base_url").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(sqlDelete)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+		expectUnlock(dbMock)
+
+		assert.NoError(t, newMigrator(t, dbc, testMigrations...).Migrate(ctx, 1))
+	})
+
+	t.Run("statement fails", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		expectLock(dbMock, sqlmock.NewRows(statusColumns))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(sqlInsert)).WithArgs(1, "config expires", true).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec("ADD COLUMN `expires`").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("ADD INDEX `IDX_EXPIRES`").WillReturnError(errors.New("Duplicate key name"))
+		expectUnlock(dbMock)
+
+		err := newMigrator(t, dbc, testMigrations...).Migrate(ctx, migrate.Latest)
+		se, ok := err.(*migrate.StatementError)
+		assert.True(t, ok, "%+v", err)
+		assert.Exactly(t, uint64(1), se.Version)
+		assert.Exactly(t, 1, se.Index)
+		assert.Exactly(t, "ALTER TABLE `core_configuration` ADD INDEX `IDX_EXPIRES` (`expires`)", se.Statement)
+		assert.Contains(t, se.Error(), "the previous 1 statement(s) have been applied")
+	})
+
+	t.Run("transaction fails", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		expectLock(dbMock, sqlmock.NewRows(statusColumns).AddRow(1, "config expires", 0, nil))
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("INSERT INTO `core_configuration`").WillReturnError(errors.New("Duplicate entry"))
+		dbMock.ExpectRollback()
+		expectUnlock(dbMock)
+
+		err := newMigrator(t, dbc, testMigrations...).Migrate(ctx, migrate.Latest)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Migration 2 failed and has been rolled back")
+	})
+
+	t.Run("dirty version", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		expectLock(dbMock, sqlmock.NewRows(statusColumns).AddRow(1, "config expires", 1, nil))
+		expectUnlock(dbMock)
+
+		err := newMigrator(t, dbc, testMigrations...).Migrate(ctx, migrate.Latest)
+		assert.True(t, errors.NotValid.Match(err), "%+v", err)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlGetLock)).WithArgs("schema_version", 30).
+			WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(0))
+
+		err := newMigrator(t, dbc, testMigrations...).Migrate(ctx, migrate.Latest)
+		assert.True(t, errors.Locked.Match(err), "%+v", err)
+	})
+}
+
+func TestMigrator_Rollback(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	expectLock(dbMock, sqlmock.NewRows(statusColumns).AddRow(1, "config expires", 1, nil))
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(sqlInsert)).WithArgs(1, "config expires", true).WillReturnResult(sqlmock.NewResult(0, 2))
+	dbMock.ExpectExec("DROP COLUMN `expires`").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(sqlDelete)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectUnlock(dbMock)
+
+	assert.NoError(t, newMigrator(t, dbc, testMigrations...).Rollback(context.Background(), 5))
+}
+
+func TestMigrator_Status(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	appliedAt := time.Date(2021, 5, 30, 14, 25, 0, 0, time.UTC)
+	dbMock.ExpectExec("CREATE TABLE IF NOT EXISTS `schema_version`").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlSelect)).WillReturnRows(sqlmock.NewRows(statusColumns).
+		AddRow(1, "config expires", 1, appliedAt).AddRow(3, "from the future", 0, appliedAt))
+
+	ss, err := newMigrator(t, dbc, testMigrations...).Status(context.Background())
+	assert.NoError(t, err)
+	assert.Exactly(t, []migrate.Status{
+		{Version: 1, Description: "config expires", Applied: true, AppliedAt: null.MakeTime(appliedAt), Dirty: true, Registered: true},
+		{Version: 2, Description: "config defaults", Registered: true},
+		{Version: 3, Description: "from the future", Applied: true, AppliedAt: null.MakeTime(appliedAt)},
+	}, ss)
+}
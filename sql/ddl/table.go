@@ -18,8 +18,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/corestoreio/errors"
@@ -27,6 +29,7 @@ import (
 	"github.com/corestoreio/pkg/sql/dml"
 	"github.com/corestoreio/pkg/storage/null"
 	"github.com/corestoreio/pkg/util/bufferpool"
+	"github.com/go-sql-driver/mysql"
 )
 
 // Table represents a table from a specific database with a bound default
@@ -104,18 +107,36 @@ type Table struct {
 	columnsUpsert []string
 	// colset is a set to check case-sensitively if a table has a column.
 	colset map[string]struct{}
+	// qualified reports whether the table has been registered with its
+	// database name, like `otherdb.table_name`. Such a table gets stored in
+	// the Tables map with its qualified name.
+	qualified bool
 }
 
 // NewTable initializes a new table structure with minimal information and
-// without a database connection.
+// without a database connection. The table name can be qualified with a
+// database name, like `otherdb.table_name`, to refer to a table in another
+// database on the same server.
 func NewTable(tableName string, cs ...*Column) *Table {
+	schema, name := splitQualifiedName(tableName)
 	ts := &Table{
-		Name:    tableName,
-		Columns: Columns(cs),
+		Schema:    schema,
+		Name:      name,
+		Columns:   Columns(cs),
+		qualified: schema != "",
 	}
 	return ts.update()
 }
 
+// mapKey returns the name under which the table gets stored in the Tables
+// map. Only qualified tables include the database name.
+func (t *Table) mapKey() string {
+	if t.qualified {
+		return qualifiedTableName(t.Schema, t.Name)
+	}
+	return t.Name
+}
+
 func newTable(rc *dml.ColumnMap) (*Table, error) {
 	t := new(Table)
 	for rc.Next(22) {
@@ -212,7 +233,7 @@ func (t *Table) update() *Table {
 // prepare a query, a call to `BuildValues()` triggers building the VALUES
 // clause, otherwise a SQL parse error will occur.
 func (t *Table) Insert() *dml.Insert {
-	return dml.NewInsert(t.Name).AddColumns(t.columnsUpsert...)
+	return dml.NewInsert(t.mapKey()).AddColumns(t.columnsUpsert...)
 }
 
 // Select creates a new SELECT statement. If "*" gets set as an argument, then
@@ -221,7 +242,7 @@ func (t *Table) Select(columns ...string) *dml.Select {
 	if len(columns) == 1 && columns[0] == "*" {
 		columns = t.columnsAll
 	}
-	return dml.NewSelect(columns...).FromAlias(t.Name, MainTable)
+	return dml.NewSelect(columns...).FromAlias(t.mapKey(), MainTable)
 }
 
 // SelectByPK creates a new `SELECT columns FROM table WHERE id = ?`. If "*"
@@ -231,34 +252,34 @@ func (t *Table) SelectByPK(columns ...string) *dml.Select {
 	if len(columns) == 1 && columns[0] == "*" {
 		columns = t.columnsAll
 	}
-	s := dml.NewSelect(columns...).FromAlias(t.Name, MainTable)
+	s := dml.NewSelect(columns...).FromAlias(t.mapKey(), MainTable)
 	s.Wheres = t.WhereByPK(dml.Equal)
 	return s
 }
 
 // DeleteByPK creates a new `DELETE FROM table WHERE id = ?`
 func (t *Table) DeleteByPK() *dml.Delete {
-	d := dml.NewDelete(t.Name)
+	d := dml.NewDelete(t.mapKey())
 	d.Wheres = t.WhereByPK(dml.Equal)
 	return d
 }
 
 // Delete creates a new `DELETE FROM table` statement.
 func (t *Table) Delete() *dml.Delete {
-	return dml.NewDelete(t.Name)
+	return dml.NewDelete(t.mapKey())
 }
 
 // UpdateByPK creates a new `UPDATE table SET ... WHERE id = ?`. The SET clause
 // contains all non primary columns.
 func (t *Table) UpdateByPK() *dml.Update {
-	u := dml.NewUpdate(t.Name).AddColumns(t.columnsUpsert...)
+	u := dml.NewUpdate(t.mapKey()).AddColumns(t.columnsUpsert...)
 	u.Wheres = t.WhereByPK(dml.Equal)
 	return u
 }
 
 // Update creates a new UPDATE statement without a WHERE clause.
 func (t *Table) Update() *dml.Update {
-	return dml.NewUpdate(t.Name).AddColumns(t.columnsUpsert...)
+	return dml.NewUpdate(t.mapKey()).AddColumns(t.columnsUpsert...)
 }
 
 // WhereByPK puts the primary keys as WHERE clauses into a condition.
@@ -406,6 +427,18 @@ func (t *Table) HasColumn(columnName string) bool {
 	return ok
 }
 
+// ColumnNames returns all column names, except system-versioned columns. The
+// returned slice must not be modified. Implements dml.TableColumner.
+func (t *Table) ColumnNames() []string {
+	return t.columnsAll
+}
+
+// PrimaryKeyNames returns the column names of the primary key. The returned
+// slice must not be modified. Implements dml.TableColumner.
+func (t *Table) PrimaryKeyNames() []string {
+	return t.columnsPK
+}
+
 // InfileOptions provides options for the function LoadDataInfile. Some columns
 // are self-describing.
 type InfileOptions struct {
@@ -467,7 +500,15 @@ func (t *Table) LoadDataInfile(ctx context.Context, filePath string, o InfileOpt
 	if o.Log == nil {
 		o.Log = log.BlackHole{}
 	}
+	qry := t.loadDataSQL(filePath, o)
+	if o.Log.IsDebug() {
+		o.Log.Debug("ddl.Table.Infile.SQL", log.String("sql", qry))
+	}
+	return t.runExec(ctx, Options{Execer: o.Execer}, qry)
+}
 
+// loadDataSQL builds the LOAD DATA statement.
+func (t *Table) loadDataSQL(filePath string, o InfileOptions) string {
 	var buf bytes.Buffer
 	buf.WriteString("LOAD DATA ")
 	if !o.IsNotLocal {
@@ -545,7 +586,7 @@ func (t *Table) LoadDataInfile(ctx context.Context, filePath string, o InfileOpt
 		if c != "" {
 			buf.WriteString(c) // do not quote because custom columns or variables
 		}
-		if i < len(t.Columns)-1 {
+		if i < len(o.Columns)-1 {
 			buf.WriteRune(',')
 		}
 	}
@@ -564,9 +605,102 @@ func (t *Table) LoadDataInfile(ctx context.Context, filePath string, o InfileOpt
 		}
 	}
 	buf.WriteRune(';')
+	return buf.String()
+}
+
+// LoadDataOptions configures Table.LoadData. The fields IsNotLocal and Execer
+// of InfileOptions get ignored.
+type LoadDataOptions struct {
+	InfileOptions
+	// SkipWarnings disables the query of the warnings after the statement.
+	SkipWarnings bool
+}
+
+// LoadDataWarning contains a warning or note returned by SHOW WARNINGS.
+type LoadDataWarning struct {
+	Level   string
+	Code    uint16
+	Message string
+}
+
+var loadDataReaderCount uint64
+
+// LoadData streams src into the table with a LOAD DATA LOCAL INFILE statement.
+// The reader gets registered with the MySQL driver via
+// mysql.RegisterReaderHandler, hence no file is needed. Without custom field
+// and line options, src must be encoded in the default format, tab separated
+// with NULL as \N, see dml.NewLoadDataReader. LoadData returns the number of
+// loaded rows and the warnings of the statement, for example truncated values.
+// The server variable local_infile must be enabled, otherwise a NotSupported
+// error gets returned.
+func (t *Table) LoadData(ctx context.Context, db *dml.ConnPool, src io.Reader, o LoadDataOptions) (rows int64, warnings []LoadDataWarning, err error) {
+	if t.IsView() {
+		return 0, nil, errors.NotSupported.Newf("[ddl] LoadData: %q is a view", t.Name)
+	}
+	if err := dml.IsValidIdentifier(t.Name); err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+	if o.Log == nil {
+		o.Log = log.BlackHole{}
+	}
+
+	// SHOW WARNINGS requires the same connection.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+	defer func() {
+		if errC := conn.Close(); errC != nil && err == nil {
+			err = errors.WithStack(errC)
+		}
+	}()
+
+	var localInfile null.String
+	if err := conn.DB.QueryRowContext(ctx, "SELECT @@local_infile").Scan(&localInfile); err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+	if v := strings.ToUpper(localInfile.Data); v != "1" && v != "ON" {
+		return 0, nil, errors.NotSupported.Newf("[ddl] LoadData: The server variable local_infile is disabled, enable it with SET GLOBAL local_infile = 1")
+	}
 
+	name := "ddl_" + t.Name + "_" + strconv.FormatUint(atomic.AddUint64(&loadDataReaderCount, 1), 10)
+	mysql.RegisterReaderHandler(name, func() io.Reader { return src })
+	defer mysql.DeregisterReaderHandler(name)
+
+	o.IsNotLocal = false
+	qry := t.loadDataSQL("Reader::"+name, o.InfileOptions)
 	if o.Log.IsDebug() {
-		o.Log.Debug("ddl.Table.Infile.SQL", log.String("sql", buf.String()))
+		o.Log.Debug("ddl.Table.LoadData.SQL", log.String("sql", qry))
+	}
+	res, err := conn.DB.ExecContext(ctx, qry)
+	switch n := dml.MySQLNumberFromError(err); {
+	case n == 1148 || n == 3948: // ER_NOT_ALLOWED_COMMAND, ER_CLIENT_LOCAL_FILES_DISABLED
+		return 0, nil, errors.NotSupported.New(err, "[ddl] LoadData: LOCAL INFILE is disabled by the client or the server")
+	case err != nil:
+		return 0, nil, errors.Wrapf(err, "[ddl] failed to exec %q", qry)
+	}
+	if rows, err = res.RowsAffected(); err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+	if o.SkipWarnings {
+		return rows, nil, nil
+	}
+
+	r, err := conn.DB.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return rows, nil, errors.WithStack(err)
+	}
+	defer func() {
+		if errC := r.Close(); errC != nil && err == nil {
+			err = errors.WithStack(errC)
+		}
+	}()
+	for r.Next() {
+		var w LoadDataWarning
+		if err = r.Scan(&w.Level, &w.Code, &w.Message); err != nil {
+			return rows, nil, errors.WithStack(err)
+		}
+		warnings = append(warnings, w)
 	}
-	return t.runExec(ctx, Options{Execer: o.Execer}, buf.String())
+	return rows, warnings, errors.WithStack(r.Err())
 }
@@ -16,15 +16,18 @@ package ddl_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
 	"github.com/corestoreio/pkg/sql/dmltest"
 	"github.com/corestoreio/pkg/storage/null"
 	"github.com/corestoreio/pkg/util/assert"
+	"github.com/go-sql-driver/mysql"
 )
 
 var tableMap *ddl.Tables
@@ -160,6 +163,24 @@ func TestTable_Select(t *testing.T) {
 	assert.Exactly(t, "SELECT `category_id`, `path` FROM `catalog_category_anc_categs_index_idx` AS `main_table`", sqlStr)
 }
 
+func TestTable_ColumnsFromMask(t *testing.T) {
+	t.Parallel()
+
+	tbl := tableMap.MustTable("admin_user")
+	var _ dml.TableColumner = tbl
+
+	sel := tbl.Select("*")
+	assert.NoError(t, sel.ColumnsFromMask([]string{"firstName", "email", "first_name"}, tbl, dml.FieldMaskCamelCase))
+	assert.Exactly(t, "SELECT `user_id`, `email`, `first_name` FROM `admin_user` AS `main_table`", sel.String())
+
+	assert.NoError(t, sel.ColumnsFromMask(nil, tbl))
+	assert.Exactly(t, "SELECT `user_id`, `email`, `first_name`, `username` FROM `admin_user` AS `main_table`", sel.String())
+
+	err := sel.ColumnsFromMask([]string{"password", "firstName", "lastName"}, tbl)
+	assert.ErrorIsKind(t, errors.NotFound, err)
+	assert.Contains(t, err.Error(), `["password" "firstName" "lastName"]`)
+}
+
 func TestTableStructure(t *testing.T) {
 	t.Parallel()
 
@@ -431,7 +452,7 @@ func TestTable_LoadDataInfile(t *testing.T) {
 		dbc, dbMock := dmltest.MockDB(t)
 		defer dmltest.MockClose(t, dbc, dbMock)
 
-		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("LOAD DATA LOCAL INFILE 'non-existent.csv' REPLACE INTO TABLE `admin_user` FIELDS TERMINATED BY '|' OPTIONALLY ENCLOSED BY '+' ESCAPED BY '\"' LINES TERMINATED BY ' ' STARTING BY '###' IGNORE 1 LINES (user_id,@email,@username,) SET username=UPPER(@username), email=UPPER(@email);")).
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("LOAD DATA LOCAL INFILE 'non-existent.csv' REPLACE INTO TABLE `admin_user` FIELDS TERMINATED BY '|' OPTIONALLY ENCLOSED BY '+' ESCAPED BY '\"' LINES TERMINATED BY ' ' STARTING BY '###' IGNORE 1 LINES (user_id,@email,@username) SET username=UPPER(@username), email=UPPER(@email);")).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 		err := tableMap.MustTable("admin_user").LoadDataInfile(context.TODO(), "non-existent.csv", ddl.InfileOptions{
 			Replace:                    true,
@@ -450,6 +471,55 @@ func TestTable_LoadDataInfile(t *testing.T) {
 	})
 }
 
+func TestTable_LoadData(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("loads rows and returns warnings", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT @@local_infile")).
+			WillReturnRows(sqlmock.NewRows([]string{"@@local_infile"}).AddRow("1"))
+		dbMock.ExpectExec("LOAD DATA LOCAL INFILE 'Reader::ddl_admin_user_[0-9]+' IGNORE INTO TABLE `admin_user` \\(user_id,email\\) ;").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectQuery("SHOW WARNINGS").
+			WillReturnRows(sqlmock.NewRows([]string{"Level", "Code", "Message"}).
+				AddRow("Warning", 1265, "Data truncated for column 'email' at row 2"))
+
+		rows, warnings, err := tableMap.MustTable("admin_user").LoadData(ctx, dbc, strings.NewReader("1\ta@b.c\n2\tx\n"), ddl.LoadDataOptions{
+			InfileOptions: ddl.InfileOptions{Ignore: true, Columns: []string{"user_id", "email"}},
+		})
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(2), rows)
+		assert.Exactly(t, []ddl.LoadDataWarning{{Level: "Warning", Code: 1265, Message: "Data truncated for column 'email' at row 2"}}, warnings)
+	})
+
+	t.Run("local_infile disabled on the server", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT @@local_infile")).
+			WillReturnRows(sqlmock.NewRows([]string{"@@local_infile"}).AddRow("0"))
+
+		_, _, err := tableMap.MustTable("admin_user").LoadData(ctx, dbc, strings.NewReader(""), ddl.LoadDataOptions{})
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+
+	t.Run("local infile rejected", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT @@local_infile")).
+			WillReturnRows(sqlmock.NewRows([]string{"@@local_infile"}).AddRow("ON"))
+		dbMock.ExpectExec("LOAD DATA LOCAL INFILE").
+			WillReturnError(&mysql.MySQLError{Number: 3948, Message: "Loading local data is disabled"})
+
+		_, _, err := tableMap.MustTable("admin_user").LoadData(ctx, dbc, strings.NewReader(""), ddl.LoadDataOptions{SkipWarnings: true})
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+}
+
 func TestTable_Artisan_Methods(t *testing.T) {
 	dbc, dbMock := dmltest.MockDB(t)
 	defer dmltest.MockClose(t, dbc, dbMock)
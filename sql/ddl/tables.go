@@ -96,6 +96,55 @@ type Tables struct {
 	mu sync.RWMutex
 	// tm a map where key = table name and value the table pointer
 	tm map[string]*Table
+	// loadHooks run for each table loaded from the INFORMATION_SCHEMA before
+	// the table gets stored in the map tm.
+	loadHooks []func(*Table) error
+}
+
+// WithTableLoadHook adds a hook which runs for each table loaded from the
+// INFORMATION_SCHEMA, before the table gets stored in the Tables map. The hook
+// can modify the table definition, for example mark columns as ignored for
+// code generation or inject a synthetic column. An error aborts the loading
+// and contains the name of the failing table. Several hooks run in the order
+// of their registration.
+func WithTableLoadHook(fn func(*Table) error) TableOption {
+	return TableOption{
+		sortOrder: 5, // before any table gets created or loaded
+		fn: func(tm *Tables) error {
+			tm.mu.Lock()
+			defer tm.mu.Unlock()
+			tm.loadHooks = append(tm.loadHooks, fn)
+			return nil
+		},
+	}
+}
+
+// WithTablesLoaded adds a hook which runs after all other options have been
+// applied and hence all tables have been loaded.
+func WithTablesLoaded(fn func(*Tables) error) TableOption {
+	return TableOption{
+		sortOrder: 250,
+		fn: func(tm *Tables) error {
+			if err := fn(tm); err != nil {
+				return errors.Wrap(err, "[ddl] WithTablesLoaded hook failed")
+			}
+			return nil
+		},
+	}
+}
+
+// runLoadHooks applies all load hooks to table t and recalculates the internal
+// cached columns.
+func (tm *Tables) runLoadHooks(t *Table) error {
+	for _, fn := range tm.loadHooks {
+		if err := fn(t); err != nil {
+			return errors.Wrapf(err, "[ddl] WithTableLoadHook failed for table %q", t.mapKey())
+		}
+	}
+	if len(tm.loadHooks) > 0 {
+		t.update()
+	}
+	return nil
 }
 
 // WithQueryDBR adds a pre-defined query with its key to the Tables object.
@@ -160,7 +209,8 @@ func WithConnPool(db *dml.ConnPool) TableOption {
 
 // WithTable inserts a new table to the Tables struct. You can optionally
 // specify the columns. Without columns the call to load the columns from the
-// INFORMATION_SCHEMA must be added.
+// INFORMATION_SCHEMA must be added. The table name can be qualified with its
+// database name, like `otherdb.table_name`.
 func WithTable(tableName string, cols ...*Column) TableOption {
 	return TableOption{
 		sortOrder: 10,
@@ -184,7 +234,8 @@ func WithTable(tableName string, cols ...*Column) TableOption {
 // statement. In case a SQL CREATE statement has been supplied, it gets executed
 // otherwise ignored. After table initialization the create syntax and the
 // column specifications are getting loaded but only if a connection has been
-// set beforehand. Write the SQL CREATE statement in upper case.
+// set beforehand. Write the SQL CREATE statement in upper case. A table/view
+// name can be qualified with its database name, like `otherdb.table_name`.
 //		WithCreateTable(
 //			"sales_order_history", "CREATE TABLE `sales_order_history` ( ... )", // table created if not exists
 //			"sales_order_stat", "CREATE VIEW `sales_order_stat` AS SELECT ...", // table created if not exists
@@ -215,11 +266,11 @@ func WithCreateTable(ctx context.Context, identifierCreateSyntax ...string) Tabl
 				tvNames = append(tvNames, tvName)
 				t := NewTable(tvName)
 				tm.tm[tvName] = t
-				if strings.Contains(tvCreate, " VIEW ") || strings.HasPrefix(tvName, PrefixView) || strings.HasSuffix(tvName, SuffixView) {
+				if strings.Contains(tvCreate, " VIEW ") || strings.HasPrefix(t.Name, PrefixView) || strings.HasSuffix(t.Name, SuffixView) {
 					t.Type = "VIEW"
 				}
 
-				if isCreateStmt(tvName, tvCreate) && tm.ConnPool != nil {
+				if isCreateStmt(t.Name, tvCreate) && tm.ConnPool != nil {
 					// TODO ConnPool != nil might lead to unexpected behaviour ... fix tests to make remove this check.
 					if _, err := tm.ConnPool.DB.ExecContext(ctx, tvCreate); err != nil {
 						return errors.Wrapf(err, "[ddl] WithCreateTable failed to run for table %q the query: %q", tvName, tvCreate)
@@ -235,9 +286,14 @@ func WithCreateTable(ctx context.Context, identifierCreateSyntax ...string) Tabl
 			}
 			for _, n := range tvNames {
 				t := tm.tm[n]
-				t.Schema = tm.Schema
+				if !t.qualified {
+					t.Schema = tm.Schema
+				}
 				t.Columns = tc[n]
 				t.update()
+				if err := tm.runLoadHooks(t); err != nil {
+					return errors.WithStack(err)
+				}
 			}
 			return nil
 		},
@@ -333,11 +389,12 @@ func withDropTable(ctx context.Context, tm *Tables, db dml.Execer, tableViewName
 		if err := dml.IsValidIdentifier(name); err != nil {
 			return errors.WithStack(err)
 		}
+		schema, tblName := splitQualifiedName(name)
 		typ := "TABLE"
-		if strings.HasPrefix(name, PrefixView) {
+		if strings.HasPrefix(tblName, PrefixView) {
 			typ = "VIEW"
 		}
-		if _, err = db.ExecContext(ctx, "DROP "+typ+" IF EXISTS "+dml.Quoter.Name(name)); err != nil {
+		if _, err = db.ExecContext(ctx, "DROP "+typ+" IF EXISTS "+dml.Quoter.QualifierName(schema, tblName)); err != nil {
 			return errors.Wrapf(err, "[ddl] Failed to drop %q", name)
 		}
 	}
@@ -349,16 +406,23 @@ const (
  VERSION, ROW_FORMAT, TABLE_ROWS, AVG_ROW_LENGTH, DATA_LENGTH,
  MAX_DATA_LENGTH, INDEX_LENGTH, DATA_FREE, AUTO_INCREMENT,
  CREATE_TIME, UPDATE_TIME, CHECK_TIME, TABLE_COLLATION, CHECKSUM,
- CREATE_OPTIONS, TABLE_COMMENT, MAX_INDEX_LENGTH FROM information_schema.TABLES WHERE TABLE_SCHEMA=DATABASE()`
+ CREATE_OPTIONS, TABLE_COMMENT, MAX_INDEX_LENGTH FROM information_schema.TABLES WHERE TABLE_SCHEMA=`
 	// DMLLoadColumns specifies the data manipulation language for retrieving
 	// all columns in the current database for a specific table. TABLE_NAME is
 	// always lower case.
-	selTables    = selTablessBaseSelect + ` AND TABLE_NAME IN ? ORDER BY TABLE_NAME`
-	selAllTables = selTablessBaseSelect + ` ORDER BY TABLE_NAME`
+	selTables    = selTablessBaseSelect + `DATABASE() AND TABLE_NAME IN ? ORDER BY TABLE_NAME`
+	selAllTables = selTablessBaseSelect + `DATABASE() ORDER BY TABLE_NAME`
+	// selSchemaTables same as selTables but for tables in another database on
+	// the same server.
+	selSchemaTables = selTablessBaseSelect + `? AND TABLE_NAME IN ? ORDER BY TABLE_NAME`
 )
 
 // WithLoadTables loads all tables and their columns in a database or only the specified tables.
 // Uses INFORMATION_SCHEMA.COLUMNS and INFORMATION_SCHEMA.TABLES system views.
+// A table name can be qualified with its database name, like
+// `otherdb.table_name`, to load a table from another database on the same
+// server. The TABLE_SCHEMA filter then uses that database name instead of
+// DATABASE().
 func WithLoadTables(ctx context.Context, db dml.Querier, tableNames ...string) TableOption {
 	return TableOption{
 		sortOrder: 70,
@@ -375,55 +439,75 @@ func WithLoadTables(ctx context.Context, db dml.Querier, tableNames ...string) T
 				return errors.WithStack(err)
 			}
 
-			var rows *sql.Rows
 			if len(tableNames) == 0 {
-				var err error
-				rows, err = db.QueryContext(ctx, selAllTables)
+				rows, err := db.QueryContext(ctx, selAllTables)
 				if err != nil {
 					return errors.WithStack(err)
 				}
-			} else {
-				sqlStr, _, err := dml.Interpolate(selTables).Strs(tableNames...).ToSQL()
+				if err := tm.upsertRows(rows, "", tblColMap); err != nil {
+					return errors.Wrapf(err, "[ddl] Scan Query for tables: %v", tableNames)
+				}
+				return nil
+			}
+
+			schemas, schemaTables := groupBySchema(tableNames)
+			for _, schema := range schemas {
+				var sqlStr string
+				var err error
+				if schema == "" {
+					sqlStr, _, err = dml.Interpolate(selTables).Strs(schemaTables[schema]...).ToSQL()
+				} else {
+					sqlStr, _, err = dml.Interpolate(selSchemaTables).Str(schema).Strs(schemaTables[schema]...).ToSQL()
+				}
 				if err != nil {
 					return errors.Wrapf(err, "[ddl] WithLoadTables dml.ExpandPlaceHolders for tables %v", tableNames)
 				}
-				rows, err = db.QueryContext(ctx, sqlStr)
+				rows, err := db.QueryContext(ctx, sqlStr)
 				if err != nil {
 					return errors.Wrapf(err, "[ddl] WithLoadTables QueryContext for tables %v with WHERE clause", tableNames)
 				}
-			}
-
-			defer func() {
-				// Not testable with the sqlmock package :-(
-				if err2 := rows.Close(); err2 != nil && err == nil {
-					err = errors.WithStack(err2)
-				}
-			}()
-
-			rc := new(dml.ColumnMap)
-			for rows.Next() {
-				if err = rc.Scan(rows); err != nil {
+				if err := tm.upsertRows(rows, schema, tblColMap); err != nil {
 					return errors.Wrapf(err, "[ddl] Scan Query for tables: %v", tableNames)
 				}
+			}
+			return nil
+		},
+	}
+}
 
-				nt, err := newTable(rc)
-				if err != nil {
-					return errors.WithStack(err)
-				}
+// upsertRows creates the tables from the rows of INFORMATION_SCHEMA.TABLES,
+// assigns their columns and closes the rows. A non-empty schema marks the
+// tables as qualified.
+func (tm *Tables) upsertRows(rows *sql.Rows, schema string, tblColMap map[string]Columns) (err error) {
+	defer func() {
+		// Not testable with the sqlmock package :-(
+		if err2 := rows.Close(); err2 != nil && err == nil {
+			err = errors.WithStack(err2)
+		}
+	}()
 
-				nt.Columns = tblColMap[nt.Name]
+	rc := new(dml.ColumnMap)
+	for rows.Next() {
+		if err = rc.Scan(rows); err != nil {
+			return errors.WithStack(err)
+		}
 
-				if err := tm.Upsert(nt); err != nil {
-					return errors.WithStack(err)
-				}
-			}
-			if err = rows.Err(); err != nil {
-				return errors.WithStack(err)
-			}
+		nt, err := newTable(rc)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		nt.qualified = schema != ""
+		nt.Columns = tblColMap[nt.mapKey()]
 
-			return err
-		},
+		if err := tm.runLoadHooks(nt); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := tm.Upsert(nt); err != nil {
+			return errors.WithStack(err)
+		}
 	}
+	return errors.WithStack(rows.Err())
 }
 
 // NewTables creates a new TableService satisfying interface Manager.
@@ -482,7 +566,9 @@ func (t errTableNotFound) Error() string {
 // reason to use int as the table index and not a name? Because table names
 // between M1 and M2 get renamed and in a Go SQL code generator script of the
 // CoreStore project, we can guarantee that the generated index constant will
-// always stay the same but the name of the table differs.
+// always stay the same but the name of the table differs. Tables from another
+// database must be requested with their qualified name, like
+// `otherdb.table_name`.
 func (tm *Tables) Table(name string) (*Table, error) {
 	tm.mu.RLock()
 	defer tm.mu.RUnlock()
@@ -502,8 +588,16 @@ func (tm *Tables) MustTable(name string) *Table {
 	return t
 }
 
+// HasColumn reports whether the table contains the column. It can be used as
+// dml.TimestampOptions.HasColumn.
+func (tm *Tables) HasColumn(table, column string) bool {
+	t, err := tm.Table(table)
+	return err == nil && t.Columns.Contains(column)
+}
+
 // Tables returns a random list of all available table names. It can append the
-// names to the argument slice.
+// names to the argument slice. Tables from another database are returned with
+// their qualified name.
 func (tm *Tables) Tables(ret ...string) []string {
 	tm.mu.RLock()
 	defer tm.mu.RUnlock()
@@ -535,9 +629,10 @@ func (tm *Tables) Upsert(tNew *Table) error {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	tOld, ok := tm.tm[tNew.Name]
+	key := tNew.mapKey()
+	tOld, ok := tm.tm[key]
 	if tOld == nil || !ok {
-		tm.tm[tNew.Name] = tNew
+		tm.tm[key] = tNew
 		return nil
 	}
 
@@ -551,7 +646,7 @@ func (tm *Tables) Upsert(tNew *Table) error {
 		tNew.Columns = tOld.Columns
 	}
 
-	tm.tm[tNew.Name] = tNew.update()
+	tm.tm[key] = tNew.update()
 	return nil
 }
 
@@ -579,8 +674,8 @@ func (tm *Tables) DeleteAllFromCache() {
 }
 
 // Validate validates the table names and their column against the current
-// database schema. The context is used to maybe cancel the "Load Columns"
-// query.
+// database schema. Qualified tables get validated against their own database.
+// The context is used to maybe cancel the "Load Columns" query.
 func (tm *Tables) Validate(ctx context.Context) error {
 	tm.mu.RLock()
 	defer tm.mu.RUnlock()
@@ -665,7 +760,11 @@ func (tm *Tables) Optimize(ctx context.Context, o Options) error {
 		if i > 0 {
 			buf.WriteByte(',')
 		}
-		dml.Quoter.WriteQualifierName(buf, tm.Schema, tn)
+		schema, tblName := splitQualifiedName(tn)
+		if schema == "" {
+			schema = tm.Schema
+		}
+		dml.Quoter.WriteQualifierName(buf, schema, tblName)
 	}
 	o.sqlAddShouldWait(buf)
 	_, err := o.exec(tm.ConnPool.DB).ExecContext(ctx, buf.String())
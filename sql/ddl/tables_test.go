@@ -639,6 +639,165 @@ func TestWithLoadTables(t *testing.T) {
 	})
 }
 
+func TestTables_SchemaQualified(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("MustTable", func(t *testing.T) {
+		ts := ddl.MustNewTables(ddl.WithTable("magento_sales.sales_order"), ddl.WithTable("sales_order"))
+		assert.Exactly(t, 2, ts.Len())
+		tbl := ts.MustTable("magento_sales.sales_order")
+		assert.Exactly(t, "magento_sales", tbl.Schema)
+		assert.Exactly(t, "sales_order", tbl.Name)
+		assert.Exactly(t, "", ts.MustTable("sales_order").Schema)
+	})
+
+	t.Run("Select", func(t *testing.T) {
+		ts := ddl.MustNewTables(ddl.WithTable("otherdb.t", &ddl.Column{Field: "id", Key: "PRI"}, &ddl.Column{Field: "name"}))
+		tbl := ts.MustTable("otherdb.t")
+
+		sqlStr, _, err := tbl.Select("*").ToSQL()
+		assert.NoError(t, err)
+		assert.Exactly(t, "SELECT `id`, `name` FROM `otherdb`.`t` AS `main_table`", sqlStr)
+
+		sqlStr, _, err = tbl.DeleteByPK().ToSQL()
+		assert.NoError(t, err)
+		assert.Exactly(t, "DELETE FROM `otherdb`.`t` WHERE (`id` = ?)", sqlStr)
+	})
+
+	t.Run("Optimize", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("OPTIMIZE TABLE `a3`,`magento_sales`.`sales_order`")).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ts := ddl.MustNewTables(ddl.WithTable("a3"), ddl.WithTable("magento_sales.sales_order"), ddl.WithConnPool(dbc))
+		assert.NoError(t, ts.Optimize(ctx, ddl.Options{}))
+	})
+
+	t.Run("WithDropTable", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("DROP TABLE IF EXISTS `magento_sales`.`sales_order`")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := ddl.NewTables(
+			ddl.WithDB(dbc.DB),
+			ddl.WithDropTable(ctx, "", "magento_sales.sales_order"),
+		)
+		assert.NoError(t, err, "%+v", err)
+	})
+
+	t.Run("WithLoadTables", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectQuery("SELECT.+FROM information_schema.COLUMNS WHERE TABLE_SCHEMA='magento_sales' AND TABLE_NAME IN.+").
+			WillReturnRows(
+				dmltest.MustMockRows(dmltest.WithFile("testdata/core_config_data_columns.csv")))
+
+		dbMock.ExpectQuery("SELECT.+FROM information_schema.TABLES WHERE TABLE_SCHEMA='magento_sales' AND TABLE_NAME IN.+").
+			WithArgs().
+			WillReturnRows(
+				dmltest.MustMockRows(dmltest.WithFile("testdata/core_config_data_tables_sales.csv")))
+
+		tbls, err := ddl.NewTables(ddl.WithLoadTables(ctx, dbc.DB, "magento_sales.core_config_data"))
+		assert.NoError(t, err, "%+v", err)
+		tbl := tbls.MustTable("magento_sales.core_config_data")
+		assert.Exactly(t, "magento_sales", tbl.Schema)
+		assert.Exactly(t, "Config Data", tbl.TableComment)
+		assert.Exactly(t, "config_id", tbl.Columns[0].Field)
+
+		_, err = tbls.Table("core_config_data")
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectQuery("SELECT.+FROM information_schema.COLUMNS WHERE TABLE_SCHEMA='magento_sales' AND TABLE_NAME IN.+").
+			WillReturnRows(
+				dmltest.MustMockRows(dmltest.WithFile("testdata/core_config_data_columns.csv")))
+
+		tbls := ddl.MustNewTables(
+			ddl.WithTable("magento_sales.core_config_data",
+				&ddl.Column{Field: `config_id`, ColumnType: `int(10) unsigned`, Null: `NO`, Key: `PRI`, Extra: `auto_increment`},
+			),
+			ddl.WithDB(dbc.DB),
+		)
+		assert.NoError(t, tbls.Validate(ctx))
+	})
+}
+
+func TestWithTableLoadHook(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("modifies table", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectQuery("SELECT.+FROM information_schema.COLUMNS WHERE.+TABLE_NAME IN.+").
+			WillReturnRows(
+				dmltest.MustMockRows(dmltest.WithFile("testdata/core_config_data_columns.csv")))
+		dbMock.ExpectQuery("SELECT.+FROM information_schema.TABLES WHERE.+TABLE_NAME IN.+").
+			WillReturnRows(
+				dmltest.MustMockRows(dmltest.WithFile("testdata/core_config_data_tables.csv")))
+
+		var loaded []string
+		tbls, err := ddl.NewTables(
+			ddl.WithLoadTables(ctx, dbc.DB, "core_config_data"),
+			ddl.WithTablesLoaded(func(tm *ddl.Tables) error {
+				loaded = tm.Tables()
+				return nil
+			}),
+			ddl.WithTableLoadHook(func(tbl *ddl.Table) error {
+				tbl.Columns.ByField("value").StructTag = `json:"-"`
+				tbl.Columns = append(tbl.Columns, &ddl.Column{Field: "synthetic", ColumnType: "varchar(10)"})
+				return nil
+			}),
+		)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, []string{"core_config_data"}, loaded)
+
+		tbl := tbls.MustTable("core_config_data")
+		assert.Exactly(t, `json:"-"`, tbl.Columns.ByField("value").StructTag)
+		assert.True(t, tbl.HasColumn("synthetic"))
+	})
+
+	t.Run("load hook error", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectQuery("SELECT.+FROM information_schema.COLUMNS WHERE.+TABLE_NAME IN.+").
+			WillReturnRows(
+				dmltest.MustMockRows(dmltest.WithFile("testdata/core_config_data_columns.csv")))
+		dbMock.ExpectQuery("SELECT.+FROM information_schema.TABLES WHERE.+TABLE_NAME IN.+").
+			WillReturnRows(
+				dmltest.MustMockRows(dmltest.WithFile("testdata/core_config_data_tables.csv")))
+
+		tbls, err := ddl.NewTables(
+			ddl.WithLoadTables(ctx, dbc.DB, "core_config_data"),
+			ddl.WithTableLoadHook(func(tbl *ddl.Table) error {
+				return errors.NotAcceptable.Newf("table %q not acceptable", tbl.Name)
+			}),
+		)
+		assert.Nil(t, tbls)
+		assert.ErrorIsKind(t, errors.NotAcceptable, err)
+		assert.Contains(t, err.Error(), `WithTableLoadHook failed for table "core_config_data"`)
+	})
+
+	t.Run("tables loaded error", func(t *testing.T) {
+		tbls, err := ddl.NewTables(
+			ddl.WithTable("core_config_data"),
+			ddl.WithTablesLoaded(func(tm *ddl.Tables) error {
+				return errors.NotValid.Newf("invalid")
+			}),
+		)
+		assert.Nil(t, tbls)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
+
 func TestWithQueryDBR(t *testing.T) {
 	p, err := dml.NewConnPool()
 	assert.NoError(t, err)
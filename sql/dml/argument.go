@@ -50,10 +50,46 @@ type QualifiedRecord struct {
 }
 
 // Qualify provides a more concise way to create QualifiedRecord values.
+// Several records with different qualifiers, e.g. for a self join, serve only
+// the placeholders of their qualifier. Several records with the same qualifier
+// must not provide the same column.
 func Qualify(q string, record ColumnMapper) QualifiedRecord {
 	return QualifiedRecord{Qualifier: q, Record: record}
 }
 
+// BindOnce binds a value to a column name, so that one value satisfies all
+// placeholders of that column, for example the same value in the SET and in
+// the WHERE clause. The name can contain a qualifier, like "p1.sku", otherwise
+// the value belongs to the default qualifier. A record providing the same
+// column with the same qualifier results in an error of kind Duplicated.
+// Placeholders of other columns fall back to the positional arguments. Not
+// supported for INSERT statements.
+func BindOnce(name string, value interface{}) QualifiedRecord {
+	q, c := splitColumn(name)
+	return QualifiedRecord{Qualifier: q, Record: boundValue{column: c, value: value}}
+}
+
+// boundValue implements ColumnMapper for a single column, see BindOnce.
+type boundValue struct {
+	column string
+	value  interface{}
+}
+
+func (bv boundValue) MapColumns(cm *ColumnMap) error {
+	for cm.Next(1) {
+		if c := cm.Column(); c != bv.column {
+			return errors.NotFound.Newf("[dml] BindOnce: Column %q not found, bound column is %q", c, bv.column)
+		}
+		cm.strict.claims++
+		if bv.value == nil {
+			cm.args = append(cm.args, internalNULLNIL{})
+		} else {
+			cm.args = append(cm.args, bv.value)
+		}
+	}
+	return cm.Err()
+}
+
 // internalNULLNIL represent an internal indicator that the value NULL should be
 // written, if an interface{} is nil, then nothing gets written in function
 // writeInterfaceValue.
@@ -61,7 +97,7 @@ type internalNULLNIL struct{}
 
 func sliceLen(arg interface{}) (l int, isSlice bool) {
 	switch v := arg.(type) {
-	case nil, int, int64, uint64, float64, bool, string, []byte, time.Time, null.String, null.Int64, null.Float64, null.Bool, null.Time:
+	case nil, int, int64, uint64, float64, bool, string, []byte, time.Time, null.String, null.Int64, null.Uint64, null.Uint32, null.Uint16, null.Float64, null.Bool, null.Time, null.Decimal:
 		l = 1
 	case []int:
 		l = len(v)
@@ -96,9 +132,21 @@ func sliceLen(arg interface{}) (l int, isSlice bool) {
 	case []null.Int64:
 		l = len(v)
 		isSlice = true
+	case []null.Uint64:
+		l = len(v)
+		isSlice = true
+	case []null.Uint32:
+		l = len(v)
+		isSlice = true
+	case []null.Uint16:
+		l = len(v)
+		isSlice = true
 	case []null.Float64:
 		l = len(v)
 		isSlice = true
+	case []null.Decimal:
+		l = len(v)
+		isSlice = true
 	case []null.Bool:
 		l = len(v)
 		isSlice = true
@@ -112,7 +160,7 @@ func sliceLen(arg interface{}) (l int, isSlice bool) {
 	return
 }
 
-func writeInterfaceValue(arg interface{}, w *bytes.Buffer, pos uint) (err error) {
+func writeInterfaceValue(d Dialect, arg interface{}, w *bytes.Buffer, pos uint) (err error) {
 	var requestPos bool
 	if pos > 0 {
 		requestPos = true
@@ -156,17 +204,62 @@ func writeInterfaceValue(arg interface{}, w *bytes.Buffer, pos uint) (err error)
 			w.WriteByte(')')
 		}
 	case null.Int64:
-		err = v.WriteTo(dialect, w)
+		err = v.WriteTo(d, w)
 	case []null.Int64:
 		if requestPos {
-			err = v[pos].WriteTo(dialect, w)
+			err = v[pos].WriteTo(d, w)
+		} else {
+			w.WriteByte('(')
+			for l, i := len(v), 0; i < l && err == nil; i++ {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				err = v[i].WriteTo(d, w)
+			}
+			w.WriteByte(')')
+		}
+	case null.Uint64:
+		err = v.WriteTo(d, w)
+	case []null.Uint64:
+		if requestPos {
+			err = v[pos].WriteTo(d, w)
 		} else {
 			w.WriteByte('(')
 			for l, i := len(v), 0; i < l && err == nil; i++ {
 				if i > 0 {
 					w.WriteByte(',')
 				}
-				err = v[i].WriteTo(dialect, w)
+				err = v[i].WriteTo(d, w)
+			}
+			w.WriteByte(')')
+		}
+	case null.Uint32:
+		err = v.WriteTo(d, w)
+	case []null.Uint32:
+		if requestPos {
+			err = v[pos].WriteTo(d, w)
+		} else {
+			w.WriteByte('(')
+			for l, i := len(v), 0; i < l && err == nil; i++ {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				err = v[i].WriteTo(d, w)
+			}
+			w.WriteByte(')')
+		}
+	case null.Uint16:
+		err = v.WriteTo(d, w)
+	case []null.Uint16:
+		if requestPos {
+			err = v[pos].WriteTo(d, w)
+		} else {
+			w.WriteByte('(')
+			for l, i := len(v), 0; i < l && err == nil; i++ {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				err = v[i].WriteTo(d, w)
 			}
 			w.WriteByte(')')
 		}
@@ -235,47 +328,62 @@ func writeInterfaceValue(arg interface{}, w *bytes.Buffer, pos uint) (err error)
 			w.WriteByte(')')
 		}
 	case null.Float64:
-		err = v.WriteTo(dialect, w)
+		err = v.WriteTo(d, w)
 	case []null.Float64:
 		if requestPos {
-			err = v[pos].WriteTo(dialect, w)
+			err = v[pos].WriteTo(d, w)
 		} else {
 			w.WriteByte('(')
 			for l, i := len(v), 0; i < l && err == nil; i++ {
 				if i > 0 {
 					w.WriteByte(',')
 				}
-				err = v[i].WriteTo(dialect, w)
+				err = v[i].WriteTo(d, w)
+			}
+			w.WriteByte(')')
+		}
+	case null.Decimal:
+		err = v.WriteTo(d, w)
+	case []null.Decimal:
+		if requestPos {
+			err = v[pos].WriteTo(d, w)
+		} else {
+			w.WriteByte('(')
+			for l, i := len(v), 0; i < l && err == nil; i++ {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				err = v[i].WriteTo(d, w)
 			}
 			w.WriteByte(')')
 		}
 	case bool:
-		dialect.EscapeBool(w, v)
+		d.EscapeBool(w, v)
 	case []bool:
 		if requestPos {
-			dialect.EscapeBool(w, v[pos])
+			d.EscapeBool(w, v[pos])
 		} else {
 			w.WriteByte('(')
 			for i, val := range v {
 				if i > 0 {
 					w.WriteByte(',')
 				}
-				dialect.EscapeBool(w, val)
+				d.EscapeBool(w, val)
 			}
 			w.WriteByte(')')
 		}
 	case null.Bool:
-		v.WriteTo(dialect, w)
+		v.WriteTo(d, w)
 	case []null.Bool:
 		if requestPos {
-			v[pos].WriteTo(dialect, w)
+			v[pos].WriteTo(d, w)
 		} else {
 			w.WriteByte('(')
 			for l, i := len(v), 0; i < l && err == nil; i++ {
 				if i > 0 {
 					w.WriteByte(',')
 				}
-				err = v[i].WriteTo(dialect, w)
+				err = v[i].WriteTo(d, w)
 			}
 			w.WriteByte(')')
 		}
@@ -283,11 +391,11 @@ func writeInterfaceValue(arg interface{}, w *bytes.Buffer, pos uint) (err error)
 		if !utf8.ValidString(v) {
 			return errors.NotValid.Newf("[dml] Argument.WriteTo: String is not UTF-8: %q", v)
 		}
-		dialect.EscapeString(w, v)
+		d.EscapeString(w, v)
 	case []string:
 		if requestPos {
 			if nv := v[pos]; utf8.ValidString(nv) {
-				dialect.EscapeString(w, nv)
+				d.EscapeString(w, nv)
 			} else {
 				err = errors.NotValid.Newf("[dml] Argument.WriteTo: String is not UTF-8: %q", nv)
 			}
@@ -298,7 +406,7 @@ func writeInterfaceValue(arg interface{}, w *bytes.Buffer, pos uint) (err error)
 					w.WriteByte(',')
 				}
 				if nv := v[i]; utf8.ValidString(nv) {
-					dialect.EscapeString(w, nv)
+					d.EscapeString(w, nv)
 				} else {
 					err = errors.NotValid.Newf("[dml] Argument.WriteTo: String is not UTF-8: %q", nv)
 				}
@@ -306,17 +414,17 @@ func writeInterfaceValue(arg interface{}, w *bytes.Buffer, pos uint) (err error)
 			w.WriteByte(')')
 		}
 	case null.String:
-		err = v.WriteTo(dialect, w)
+		err = v.WriteTo(d, w)
 	case []null.String:
 		if requestPos {
-			err = v[pos].WriteTo(dialect, w)
+			err = v[pos].WriteTo(d, w)
 		} else {
 			w.WriteByte('(')
 			for l, i := len(v), 0; i < l && err == nil; i++ {
 				if i > 0 {
 					w.WriteByte(',')
 				}
-				err = v[i].WriteTo(dialect, w)
+				err = v[i].WriteTo(d, w)
 			}
 			w.WriteByte(')')
 		}
@@ -337,32 +445,32 @@ func writeInterfaceValue(arg interface{}, w *bytes.Buffer, pos uint) (err error)
 			w.WriteByte(')')
 		}
 	case time.Time:
-		dialect.EscapeTime(w, v)
+		d.EscapeTime(w, v)
 	case []time.Time:
 		if requestPos {
-			dialect.EscapeTime(w, v[pos])
+			d.EscapeTime(w, v[pos])
 		} else {
 			w.WriteByte('(')
 			for l, i := len(v), 0; i < l && err == nil; i++ {
 				if i > 0 {
 					err = w.WriteByte(',')
 				}
-				dialect.EscapeTime(w, v[i])
+				d.EscapeTime(w, v[i])
 			}
 			w.WriteByte(')')
 		}
 	case null.Time:
-		err = v.WriteTo(dialect, w)
+		err = v.WriteTo(d, w)
 	case []null.Time:
 		if requestPos {
-			err = v[pos].WriteTo(dialect, w)
+			err = v[pos].WriteTo(d, w)
 		} else {
 			w.WriteByte('(')
 			for l, i := len(v), 0; i < l && err == nil; i++ {
 				if i > 0 {
 					w.WriteByte(',')
 				}
-				err = v[i].WriteTo(dialect, w)
+				err = v[i].WriteTo(d, w)
 			}
 			w.WriteByte(')')
 		}
@@ -372,7 +480,7 @@ func writeInterfaceValue(arg interface{}, w *bytes.Buffer, pos uint) (err error)
 		// do nothing
 		// _, err = w.WriteString("[PLEASE USE type internalNULLNIL]")
 	case sql.NamedArg:
-		return writeInterfaceValue(v.Value, w, pos)
+		return writeInterfaceValue(d, v.Value, w, pos)
 	default:
 		return errors.NotSupported.Newf("[dml] Unsupported field type: %T => %#v", arg, arg)
 	}
@@ -424,7 +532,7 @@ func writeInterfaces(buf *bytes.Buffer, args []interface{}) error {
 		if j > 0 {
 			buf.WriteByte(',')
 		}
-		if err := writeInterfaceValue(arg, buf, 0); err != nil {
+		if err := writeInterfaceValue(dialect, arg, buf, 0); err != nil {
 			return errors.Wrapf(err, "[dml] args write failed at pos %d with argument %#v", j, arg)
 		}
 	}
@@ -591,6 +699,12 @@ func expandInterface(appendTo []interface{}, arg interface{}) []interface{} {
 		for _, v := range vv {
 			appendTo = v.Append(appendTo)
 		}
+	case null.Decimal:
+		appendTo = vv.Append(appendTo)
+	case []null.Decimal:
+		for _, v := range vv {
+			appendTo = v.Append(appendTo)
+		}
 
 	case []bool:
 		for _, v := range vv {
@@ -188,7 +188,7 @@ func BenchmarkInterpolate(b *testing.B) {
 	b.ResetTimer()
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
-		if err := writeInterpolate(ipBuf, sqlBytes, args); err != nil {
+		if err := writeInterpolate(dialect, ipBuf, sqlBytes, args); err != nil {
 			b.Fatal(err)
 		}
 		preprocessSink = ipBuf.String()
@@ -233,7 +233,7 @@ func BenchmarkConditions_writeOnDuplicateKey(b *testing.B) {
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		if _, err := dk.writeOnDuplicateKey(buf, nil); err != nil {
+		if _, err := dk.writeOnDuplicateKey(dialect, buf, nil, nil); err != nil {
 			b.Fatalf("%+v", err)
 		}
 		buf.Reset()
@@ -83,6 +83,19 @@ type BuilderBase struct {
 	isWithDBR        bool // tuple handling before building the SQL string
 	containsTuples   bool
 	qualifiedColumns []string
+	// dialect if nil, falls back to MySQL. Gets set via WithDialect or by the
+	// ConnPool.
+	dialect Dialect
+	// listeners get called for the executions of this builder, see
+	// Select.AddListener.
+	listeners []Listener
+}
+
+func (bb *BuilderBase) sqlDialect() Dialect {
+	if bb.dialect == nil {
+		return dialect
+	}
+	return bb.dialect
 }
 
 // Clone creates a clone of the current object.
@@ -90,6 +103,7 @@ func (bb BuilderBase) Clone() BuilderBase {
 	cc := bb
 	cc.Table = bb.Table.Clone()
 	cc.ärgErr = nil
+	cc.listeners = append(bb.listeners[:0:0], bb.listeners...)
 	return cc
 }
 
@@ -121,6 +135,13 @@ func (bb *BuilderBase) buildToSQL(qb queryBuilder) (string, error) {
 	}
 	bb.qualifiedColumns = qualifiedColumns2
 
+	if d := bb.dialect; usesANSIQuotes(d) {
+		// The DBR numbers the placeholders after it has expanded them.
+		tBuf := bufferpool.Get()
+		defer bufferpool.Put(tBuf)
+		translateSQL(d, tBuf, buf.String(), true, !bb.isWithDBR)
+		return tBuf.String(), nil
+	}
 	return buf.String(), nil
 }
 
@@ -237,14 +258,9 @@ func sqlWriteOrderBy(w *bytes.Buffer, orderBys ids, br bool) {
 // LIMIT 0,0 quickly returns an empty set. This can be useful for checking the
 // validity of a query. When using one of the MySQL APIs, it can also be
 // employed for obtaining the types of the result columns.
-func sqlWriteLimitOffset(w *bytes.Buffer, limitValid, offsetValid bool, offsetCount, limitCount uint64) {
+func sqlWriteLimitOffset(d Dialect, w *bytes.Buffer, limitValid, offsetValid bool, offsetCount, limitCount uint64) {
 	if limitValid {
-		w.WriteString(" LIMIT ")
-		if offsetValid {
-			writeUint64(w, offsetCount)
-			w.WriteByte(',')
-		}
-		writeUint64(w, limitCount)
+		d.ApplyLimitAndOffset(w, limitCount, offsetCount, offsetValid)
 	}
 }
 
@@ -305,6 +321,25 @@ func calcInsertTemplatePlaceholderPos(columnCount uint) (start, end uint) {
 	return
 }
 
+// writeInsertTuples writes the VALUES tuples of an INSERT statement and
+// appends the values of the timestamps to each tuple.
+func writeInsertTuples(buf *bytes.Buffer, rowCount, columnCount uint, ts *timestamps) {
+	if ts == nil {
+		writeTuplePlaceholders(buf, rowCount, columnCount)
+		return
+	}
+	values := ts.values()
+	for r := uint(0); r < rowCount; r++ {
+		if r > 0 {
+			buf.WriteByte(',')
+		}
+		writeTuplePlaceholders(buf, 1, columnCount)
+		buf.Truncate(buf.Len() - 1) // removes the closing bracket
+		buf.WriteString(values)
+		buf.WriteByte(')')
+	}
+}
+
 func writeTuplePlaceholders(buf *bytes.Buffer, rowCount, columnCount uint) {
 	start, end := calcInsertTemplatePlaceholderPos(columnCount)
 	for r := uint(0); r < rowCount; r++ {
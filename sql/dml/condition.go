@@ -94,21 +94,21 @@ func (o Op) write(w *bytes.Buffer, args ...interface{}) (err error) {
 		err = writeInterfaces(w, args)
 	case Like, NotLike:
 		w.WriteString(" LIKE ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	case Regexp, NotRegexp:
 		w.WriteString(" REGEXP ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	case Between, NotBetween:
 		w.WriteString(" BETWEEN ")
 		if arg == nil {
 			w.WriteByte(placeHolderRune)
 			w.WriteString(" AND ") // don't write the last place holder as it gets written somewhere else
 		} else {
-			if err = writeInterfaceValue(arg, w, 1); err != nil {
+			if err = writeInterfaceValue(dialect, arg, w, 1); err != nil {
 				return errors.WithStack(err)
 			}
 			w.WriteString(" AND ")
-			if err = writeInterfaceValue(arg, w, 2); err != nil {
+			if err = writeInterfaceValue(dialect, arg, w, 2); err != nil {
 				return errors.WithStack(err)
 			}
 		}
@@ -123,31 +123,31 @@ func (o Op) write(w *bytes.Buffer, args ...interface{}) (err error) {
 		err = writeInterfaces(w, args)
 	case Xor:
 		w.WriteString(" XOR ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	case Exists, NotExists:
 		w.WriteString(" EXISTS ")
 		err = writeInterfaces(w, args)
 	case Less:
 		w.WriteString(" < ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	case Greater:
 		w.WriteString(" > ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	case LessOrEqual:
 		w.WriteString(" <= ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	case GreaterOrEqual:
 		w.WriteString(" >= ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	case SpaceShip:
 		w.WriteString(" <=> ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	case NotEqual:
 		w.WriteString(" != ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	default: // and case Equal
 		w.WriteString(" = ")
-		err = writeInterfaceValue(arg, w, 0)
+		err = writeInterfaceValue(dialect, arg, w, 0)
 	}
 	return
 }
@@ -620,21 +620,18 @@ func (c *Condition) Uint64s(i ...uint64) *Condition {
 // Add when needed
 // func (c *Condition) Decimals(d ...Decimal) *Condition {}
 
+// Decimal compares the value with the exact decimal d. An invalid d gets
+// treated as NULL.
 func (c *Condition) Decimal(d null.Decimal) *Condition {
-	v := d.String()
+	var v interface{}
+	if d.Valid {
+		v = d
+	}
 	if c.isExpression() {
-		if v == sqlStrNullUC {
-			c.Right.args = append(c.Right.args, nil)
-		} else {
-			c.Right.args = append(c.Right.args, v)
-		}
+		c.Right.args = append(c.Right.args, v)
 		return c
 	}
-	if v == sqlStrNullUC {
-		c.Right.arg = nil
-	} else {
-		c.Right.arg = v
-	}
+	c.Right.arg = v
 	return c
 }
 
@@ -785,6 +782,24 @@ func (c *Condition) NullInt64s(nv ...null.Int64) *Condition {
 	return c
 }
 
+func (c *Condition) NullUint64(nv null.Uint64) *Condition {
+	if c.isExpression() {
+		c.Right.args = append(c.Right.args, nv)
+		return c
+	}
+	c.Right.arg = nv
+	return c
+}
+
+func (c *Condition) NullUint64s(nv ...null.Uint64) *Condition {
+	if c.isExpression() {
+		c.Right.args = append(c.Right.args, nv)
+		return c
+	}
+	c.Right.arg = nv
+	return c
+}
+
 func (c *Condition) NullBool(nv null.Bool) *Condition {
 	if c.isExpression() {
 		c.Right.args = append(c.Right.args, nv)
@@ -1090,14 +1105,17 @@ func (cs Conditions) writeSetClauses(w *bytes.Buffer, placeHolders []string) ([]
 
 		switch {
 		case cnd.Right.arg != nil && len(cnd.Right.args) == 0: // One Argument and no expression
-			if err := writeInterfaceValue(cnd.Right.arg, w, 0); err != nil {
+			if err := writeInterfaceValue(dialect, cnd.Right.arg, w, 0); err != nil {
 				return nil, errors.WithStack(err)
 			}
 		case cnd.Right.IsExpression: // maybe that case is superfluous
-			if _, err := writeExpression(w, cnd.Right.Column, cnd.Right.args); err != nil {
+			phCount, err := writeExpression(w, cnd.Right.Column, cnd.Right.args)
+			if err != nil {
 				return nil, errors.WithStack(err)
 			}
-			placeHolders = append(placeHolders, cnd.Left)
+			if phCount > 0 && len(cnd.Right.args) == 0 {
+				placeHolders = append(placeHolders, cnd.Left)
+			}
 		case cnd.Right.Sub != nil:
 			w.WriteByte('(')
 			var err error
@@ -1105,6 +1123,13 @@ func (cs Conditions) writeSetClauses(w *bytes.Buffer, placeHolders []string) ([]
 				return nil, errors.WithStack(err)
 			}
 			w.WriteByte(')')
+		case cnd.Right.PlaceHolder != "" && cnd.Right.PlaceHolder != placeHolderStr && isNamedArg(cnd.Right.PlaceHolder):
+			ph := cnd.Right.PlaceHolder
+			if !strings.HasPrefix(ph, namedArgStartStr) {
+				ph = namedArgStartStr + ph
+			}
+			placeHolders = append(placeHolders, ph)
+			w.WriteByte(placeHolderRune)
 		default:
 			placeHolders = append(placeHolders, cnd.Left)
 			w.WriteByte(placeHolderRune)
@@ -1124,14 +1149,16 @@ var onDuplicateKeyPart = []byte(` ON DUPLICATE KEY UPDATE `)
 const onDuplicateKeyPartS = ` ON DUPLICATE KEY UPDATE `
 
 // writeOnDuplicateKey writes the columns to `w` and appends the arguments to
-// `args` and returns `args`.
+// `args` and returns `args`. The dialect `d` writes the upsert clause.
 // https://dev.mysql.com/doc/refman/5.7/en/insert-on-duplicate.html
-func (cs Conditions) writeOnDuplicateKey(w *bytes.Buffer, placeHolders []string) ([]string, error) {
+func (cs Conditions) writeOnDuplicateKey(d Dialect, w *bytes.Buffer, conflictColumns, placeHolders []string) ([]string, error) {
 	if len(cs) == 0 {
 		return placeHolders, nil
 	}
 
-	w.Write(onDuplicateKeyPart)
+	if err := d.WriteUpsert(w, conflictColumns); err != nil {
+		return nil, errors.WithStack(err)
+	}
 	for i, cnd := range cs {
 		addColon := false
 		for j, col := range cnd.Columns {
@@ -1140,7 +1167,7 @@ func (cs Conditions) writeOnDuplicateKey(w *bytes.Buffer, placeHolders []string)
 			}
 			Quoter.quote(w, col)
 			w.WriteByte('=')
-			writeSQLValues(w, col)
+			d.WriteUpsertValue(w, col)
 			addColon = true
 		}
 		if cnd.Left == "" {
@@ -1177,9 +1204,9 @@ func (cs Conditions) writeOnDuplicateKey(w *bytes.Buffer, placeHolders []string)
 			}
 
 		case cnd.Right.arg == nil:
-			writeSQLValues(w, cnd.Left)
+			d.WriteUpsertValue(w, cnd.Left)
 		case cnd.Right.arg != nil:
-			if err := writeInterfaceValue(cnd.Right.arg, w, 0); err != nil {
+			if err := writeInterfaceValue(d, cnd.Right.arg, w, 0); err != nil {
 				return nil, errors.WithStack(err)
 			}
 
@@ -246,7 +246,7 @@ func TestConditions_writeOnDuplicateKey(t *testing.T) {
 		return func(t *testing.T) {
 			buf := new(bytes.Buffer)
 
-			ph, err := cnds.writeOnDuplicateKey(buf, nil)
+			ph, err := cnds.writeOnDuplicateKey(dialect, buf, nil, nil)
 			assert.Nil(t, ph, "TODO check me")
 			assert.NoError(t, err)
 		}
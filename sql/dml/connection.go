@@ -25,6 +25,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 
@@ -46,6 +47,9 @@ const (
 )
 
 type queryCache struct {
+	// counters must be the first field to guarantee the 64-bit alignment of
+	// the atomic counters on 32-bit systems.
+	counters poolCounters
 	// makeUniqueID generates for each call a new unique ID. Those IDs will be
 	// assigned to a new connection or a new statement. The function signature is
 	// equal to fmt.Stringer so one can use for example:
@@ -55,6 +59,32 @@ type queryCache struct {
 	// comment-end-termination pattern: `*/`.
 	makeUniqueID uniqueIDFn
 	mapTableName func(oldName string) (newName string)
+	// slowQuery gets set via WithSlowQueryThreshold and gets assigned to all
+	// DBR types.
+	slowQuery *slowQueryMonitor
+	// queryComment gets set via WithQueryCommentTags and gets assigned to all
+	// DBR types.
+	queryComment *queryComment
+	// listeners gets assigned to all DBR types, see AddListenerForTable.
+	listeners *listeners
+	// timestamps gets set via WithTimestamps and gets applied to INSERT and
+	// UPDATE builders.
+	timestamps *TimestampOptions
+	// strictColumnMap gets set via WithStrictColumnMap and gets assigned to
+	// all DBR types.
+	strictColumnMap bool
+	// gtid gets set via WithSessionTracking and gets assigned to all DBR
+	// types and the replica router.
+	gtid *gtidTracker
+	// redactor gets set via WithArgumentRedactor and gets assigned to all DBR
+	// types.
+	redactor ArgumentRedactor
+	// stmts contains the statements prepared on the pool, see
+	// CloseIdleStatements.
+	stmts stmtRegistry
+	// dialect gets set via WithDialect and gets assigned to all builders and
+	// DBR types. Nil falls back to MySQL.
+	dialect Dialect
 
 	mu sync.RWMutex
 	// cachedSQL contains the final SQL string which gets send to the server.
@@ -78,6 +108,18 @@ type ConnPool struct {
 	// DB must be set using one of the ConnPoolOption function.
 	DB  *sql.DB
 	dsn *mysql.Config
+	// healthCheckTimeout and healthCheckQuery get set via WithHealthCheck.
+	healthCheckTimeout time.Duration
+	healthCheckQuery   bool
+	// stmtReaper gets set via WithStmtReaper.
+	stmtReaper *stmtReaper
+	// replicas contains the read replicas, see NewConnPoolWithReplicas.
+	replicas          []*sql.DB
+	replicaStrategy   ReplicaStrategy
+	onReplicaFailover func(ReplicaFailover)
+	// router routes the queries to the primary or the replicas. Nil if no
+	// replicas have been configured.
+	router *replicaRouter
 }
 
 // Conn represents a single database session rather a pool of database sessions.
@@ -176,6 +218,7 @@ func WithVerifyConnection(ctx context.Context, pingRetry time.Duration) ConnPool
 				case <-ctx.Done():
 					return ctx.Err()
 				case <-tkr.C:
+					atomic.AddUint64(&c.queryCache.counters.retries, 1)
 					if err := c.DB.PingContext(ctx); err == nil {
 						return nil
 					}
@@ -289,6 +332,62 @@ func WithSetNamesUTF8MB4() ConnPoolOption {
 	}
 }
 
+// WithMaxOpenConns sets the maximum number of open connections to the
+// database. See sql.DB.SetMaxOpenConns.
+func WithMaxOpenConns(n int) ConnPoolOption {
+	return withPoolSetting("WithMaxOpenConns", func(db *sql.DB) { db.SetMaxOpenConns(n) })
+}
+
+// WithMaxIdleConns sets the maximum number of connections in the idle
+// connection pool. See sql.DB.SetMaxIdleConns.
+func WithMaxIdleConns(n int) ConnPoolOption {
+	return withPoolSetting("WithMaxIdleConns", func(db *sql.DB) { db.SetMaxIdleConns(n) })
+}
+
+// WithConnMaxLifetime sets the maximum amount of time a connection may be
+// reused. See sql.DB.SetConnMaxLifetime.
+func WithConnMaxLifetime(d time.Duration) ConnPoolOption {
+	return withPoolSetting("WithConnMaxLifetime", func(db *sql.DB) { db.SetConnMaxLifetime(d) })
+}
+
+// WithConnMaxIdleTime sets the maximum amount of time a connection may be
+// idle. See sql.DB.SetConnMaxIdleTime.
+func WithConnMaxIdleTime(d time.Duration) ConnPoolOption {
+	return withPoolSetting("WithConnMaxIdleTime", func(db *sql.DB) { db.SetConnMaxIdleTime(d) })
+}
+
+// WithDialect sets the SQL dialect of the database server, defaults to
+// DialectMySQL. All queries created via the ConnPool, its Conn and Tx types get
+// rendered in the syntax of the dialect. The driver must be registered with
+// database/sql and the DB applied via WithDB.
+func WithDialect(d Dialect) ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			if d == nil {
+				return errors.NotValid.Newf("[dml] WithDialect: Dialect cannot be nil")
+			}
+			c.queryCache.dialect = d
+			return nil
+		},
+	}
+}
+
+func withPoolSetting(name string, fn func(*sql.DB)) ConnPoolOption {
+	return ConnPoolOption{
+		sortOrder: 4, // must run after WithDSN and WithDB
+		fn: func(c *ConnPool) error {
+			if c.DB == nil {
+				return errors.NotValid.Newf("[dml] %s requires a DSN or a DB", name)
+			}
+			fn(c.DB)
+			for _, db := range c.replicas {
+				fn(db)
+			}
+			return nil
+		},
+	}
+}
+
 const randomTestDBPrefix = "test_"
 
 // WithDB sets the DB value to an existing connection. Mainly used for testing.
@@ -432,7 +531,8 @@ func (t dsnConnector) Driver() driver.Driver {
 func NewConnPool(opts ...ConnPoolOption) (*ConnPool, error) {
 	c := ConnPool{
 		queryCache: &queryCache{
-			queries: make(map[string]*cachedSQL),
+			queries:   make(map[string]*cachedSQL),
+			listeners: new(listeners),
 		},
 	}
 	opts = append(opts, WithDB(nil))
@@ -446,6 +546,27 @@ func NewConnPool(opts ...ConnPoolOption) (*ConnPool, error) {
 	if c.queryCache.mapTableName == nil {
 		c.queryCache.mapTableName = mapTableNameNoOp
 	}
+	if sq := c.queryCache.slowQuery; sq != nil {
+		if sq.handler == nil {
+			c.queryCache.slowQuery = nil // only WithSlowQueryIncludeArgs has been applied
+		} else {
+			sq.start()
+		}
+	}
+	if c.stmtReaper != nil {
+		c.stmtReaper.start(&c)
+	}
+	if len(c.replicas) > 0 {
+		c.router = &replicaRouter{
+			primary:    c.DB,
+			replicas:   c.replicas,
+			strategy:   c.replicaStrategy,
+			counters:   &c.queryCache.counters,
+			log:        c.Log,
+			onFailover: c.onReplicaFailover,
+			gtid:       c.queryCache.gtid,
+		}
+	}
 	// validate that DSN contains the utf8mb4 setting, if DSN is set
 
 	return &c, nil
@@ -548,6 +669,13 @@ func (qc *queryCache) prependUniqueID(rawSQL string) (id, _rawSQL string) {
 	return id, rawSQL
 }
 
+func (qc *queryCache) sqlDialect() Dialect {
+	if qc.dialect == nil {
+		return dialect
+	}
+	return qc.dialect
+}
+
 func (qc *queryCache) cacheKeyExists(cacheKey string) bool {
 	qc.mu.RLock()
 	_, ok := qc.queries[cacheKey]
@@ -575,6 +703,7 @@ func (qc *queryCache) initDBRCacheKey(
 		DB:             db,
 		ResultCheckFn:  strictAffectedRowsResultCheck,
 		isPrepared:     isPrepared,
+		dialect:        qc.dialect,
 	}
 	for _, opt := range opts {
 		opt(dbr)
@@ -591,20 +720,47 @@ func (qc *queryCache) initDBRCacheKey(
 	}
 	dbr.cachedSQL = *sqlCache
 	dbr.log = l
+	dbr.slowQuery = qc.slowQuery
+	dbr.queryComment = qc.queryComment
+	dbr.listeners = qc.listeners
+	dbr.strictColumnMap = qc.strictColumnMap
+	dbr.gtid = qc.gtid
+	dbr.redactor = qc.redactor
 
 	if isPrepared {
-		stmt, err := db.PrepareContext(ctx, dbr.cachedSQL.rawSQL)
+		sw, err := qc.prepare(ctx, db, dbr.cachedSQL.rawSQL)
 		if err != nil {
 			return &DBR{
 				previousErr: err,
 			}
 		}
-		dbr.DB = stmtWrapper{stmt: stmt}
+		dbr.DB = sw
 	}
 
 	return dbr
 }
 
+// prepare creates a prepared statement on db. If db routes to the replicas,
+// the statement gets prepared on the connection on which it runs.
+func (qc *queryCache) prepare(ctx context.Context, db QueryExecPreparer, rawSQL string) (sw stmtWrapper, err error) {
+	rawSQL = rebindSQL(qc.dialect, rawSQL)
+	if qc.queryComment != nil && qc.queryComment.onPrepared {
+		rawSQL = qc.queryComment.prefix(ctx, rawSQL)
+	}
+	var stmt *sql.Stmt
+	if r, ok := db.(*replicaRouter); ok {
+		stmt, sw.onReplica, err = r.prepare(ctx, rawSQL)
+	} else {
+		stmt, err = db.PrepareContext(ctx, rawSQL)
+	}
+	if err != nil {
+		return sw, err
+	}
+	atomic.AddUint64(&qc.counters.preparedStatements, 1)
+	sw.stmt = qc.track(db, rawSQL, stmt, sw.onReplica)
+	return sw, nil
+}
+
 func (qc *queryCache) initDBRQB(
 	ctx context.Context,
 	l log.Logger,
@@ -614,7 +770,8 @@ func (qc *queryCache) initDBRQB(
 	db QueryExecPreparer,
 	opts []DBRFunc,
 ) *DBR {
-	prepareQueryBuilder(qc.mapTableName, qb)
+	prepareQueryBuilder(qc.mapTableName, qc.dialect, qb)
+	hasTimestamps := qc.timestamps.apply(qb)
 	rawSQL, _, err := qb.ToSQL()
 	if err != nil {
 		return &DBR{
@@ -623,13 +780,13 @@ func (qc *queryCache) initDBRQB(
 	}
 
 	if isPrepared {
-		stmt, err := db.PrepareContext(ctx, rawSQL)
+		sw, err := qc.prepare(ctx, db, rawSQL)
 		if err != nil {
 			return &DBR{
 				previousErr: errors.WithStack(err),
 			}
 		}
-		db = stmtWrapper{stmt: stmt}
+		db = sw
 	}
 
 	dbr := &DBR{
@@ -637,6 +794,7 @@ func (qc *queryCache) initDBRQB(
 		DB:             db,
 		ResultCheckFn:  strictAffectedRowsResultCheck,
 		isPrepared:     isPrepared,
+		dialect:        qc.dialect,
 	}
 
 	for _, opt := range opts {
@@ -649,6 +807,13 @@ func (qc *queryCache) initDBRQB(
 	if !ok {
 		id, rawSQL := qc.prependUniqueID(rawSQL)
 		sqlCache = makeCachedSQL(qb, rawSQL, id)
+		if hasTimestamps && !isPrepared {
+			if sqlCache.withoutTimestamps, err = withoutTimestamps(qb); err != nil {
+				return &DBR{
+					previousErr: errors.WithStack(err),
+				}
+			}
+		}
 		qc.queries[dbr.customCacheKey] = sqlCache
 	}
 	if l != nil {
@@ -659,6 +824,12 @@ func (qc *queryCache) initDBRQB(
 	// https://github.com/go101/go101/wiki
 	dbr.cachedSQL.qualifiedColumns = append(sqlCache.qualifiedColumns[:0:0], sqlCache.qualifiedColumns...)
 	dbr.log = l
+	dbr.slowQuery = qc.slowQuery
+	dbr.queryComment = qc.queryComment
+	dbr.listeners = qc.listeners
+	dbr.strictColumnMap = qc.strictColumnMap
+	dbr.gtid = qc.gtid
+	dbr.redactor = qc.redactor
 
 	return dbr
 }
@@ -731,12 +902,35 @@ func (c *ConnPool) Close() (err error) {
 			return errors.WithStack(err)
 		}
 	}
+	// All resources get closed even if a replica fails, the first error wins.
+	for i, db := range c.replicas {
+		if errC := db.Close(); errC != nil && err == nil {
+			err = errors.Wrapf(errC, "[dml] Failed to close replica at index %d", i)
+		}
+	}
 	if c.DB != nil {
-		err = c.DB.Close() // no stack wrap otherwise error is hard to compare
+		if errC := c.DB.Close(); errC != nil && err == nil {
+			err = errC // no stack wrap otherwise error is hard to compare
+		}
+	}
+	if c.queryCache.slowQuery != nil {
+		c.queryCache.slowQuery.close()
+	}
+	if c.stmtReaper != nil {
+		c.stmtReaper.close()
 	}
 	return
 }
 
+// queryExecPreparer returns the replica router, if replicas have been
+// configured, otherwise the primary.
+func (c *ConnPool) queryExecPreparer() QueryExecPreparer {
+	if c.router != nil {
+		return c.router
+	}
+	return c.DB
+}
+
 // BeginTx starts a transaction.
 //
 // The provided context is used until the transaction is committed or rolled
@@ -835,13 +1029,20 @@ func (c *ConnPool) RegisterByQueryBuilder(cacheKeyQB map[string]QueryBuilder) er
 			return errors.AlreadyExists.Newf("[dml] CacheKey %q already exists", cacheKey)
 		}
 
-		prepareQueryBuilder(c.queryCache.mapTableName, qb)
+		prepareQueryBuilder(c.queryCache.mapTableName, c.queryCache.dialect, qb)
+		hasTimestamps := c.queryCache.timestamps.apply(qb)
 		rawSQL, _, err := qb.ToSQL()
 		if err != nil {
 			return errors.Fatal.New(err, "Failed to build SQL for cache key %q", cacheKey)
 		}
 		id, rawSQL := c.queryCache.prependUniqueID(rawSQL)
-		c.queryCache.queries[cacheKey] = makeCachedSQL(qb, rawSQL, id)
+		sqlCache := makeCachedSQL(qb, rawSQL, id)
+		if hasTimestamps {
+			if sqlCache.withoutTimestamps, err = withoutTimestamps(qb); err != nil {
+				return errors.Fatal.New(err, "Failed to build SQL for cache key %q", cacheKey)
+			}
+		}
+		c.queryCache.queries[cacheKey] = sqlCache
 	}
 	return nil
 }
@@ -855,7 +1056,7 @@ func (c *ConnPool) DeregisterByCacheKey(cacheKey string) error {
 
 // WithCacheKey creates a DBR object from a cached query.
 func (c *ConnPool) WithCacheKey(cacheKey string, opts ...DBRFunc) *DBR {
-	return c.queryCache.initDBRCacheKey(context.Background(), c.Log, "ConnPool", cacheKey, false, c.DB, opts)
+	return c.queryCache.initDBRCacheKey(context.Background(), c.Log, "ConnPool", cacheKey, false, c.queryExecPreparer(), opts)
 }
 
 // CacheKeyExists returns true if a given key already exists.
@@ -865,7 +1066,7 @@ func (c *ConnPool) CacheKeyExists(cacheKey string) bool {
 
 // WithPrepareCacheKey creates a DBR object from a prepared cached query.
 func (c *ConnPool) WithPrepareCacheKey(ctx context.Context, cacheKey string, opts ...DBRFunc) *DBR {
-	return c.queryCache.initDBRCacheKey(ctx, c.Log, "ConnPool", cacheKey, true, c.DB, opts)
+	return c.queryCache.initDBRCacheKey(ctx, c.Log, "ConnPool", cacheKey, true, c.queryExecPreparer(), opts)
 }
 
 // WithQueryBuilder creates a new DBR for handling the arguments with the
@@ -874,7 +1075,7 @@ func (c *ConnPool) WithPrepareCacheKey(ctx context.Context, cacheKey string, opt
 // unique cache key based on the SQL string. The cache key can be retrieved via
 // DBR object.
 func (c *ConnPool) WithQueryBuilder(qb QueryBuilder, opts ...DBRFunc) *DBR {
-	return c.queryCache.initDBRQB(context.Background(), c.Log, "ConnPool", false, qb, c.DB, opts)
+	return c.queryCache.initDBRQB(context.Background(), c.Log, "ConnPool", false, qb, c.queryExecPreparer(), opts)
 }
 
 // Conn returns a single connection by either opening a new connection
@@ -909,7 +1110,7 @@ func (c *ConnPool) Conn(ctx context.Context) (*Conn, error) {
 // It generates a unique cache key based on the SQL string. The cache key can be
 // retrieved via DBR object.
 func (c *ConnPool) WithPrepare(ctx context.Context, qb QueryBuilder, opts ...DBRFunc) *DBR {
-	return c.queryCache.initDBRQB(ctx, c.Log, "ConnPool", true, qb, c.DB, opts)
+	return c.queryCache.initDBRQB(ctx, c.Log, "ConnPool", true, qb, c.queryExecPreparer(), opts)
 }
 
 // WithDisabledForeignKeyChecks runs the callBack with disabled foreign key
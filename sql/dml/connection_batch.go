@@ -0,0 +1,291 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/util/bufferpool"
+)
+
+// BatchOptions configures the execution of ExecBatch.
+type BatchOptions struct {
+	// MultiStatements sends all statements concatenated in one round trip.
+	// Gets enabled automatically if the DSN contains multiStatements=true. Set
+	// it if the *sql.DB has been provided via WithDB and its driver connection
+	// supports multiple statements.
+	MultiStatements bool
+	// Transaction executes all statements within one transaction. If a
+	// statement fails, the transaction gets rolled back.
+	Transaction bool
+	// OnEvent, if set, gets called once for each executed statement and once
+	// for the whole batch.
+	OnEvent func(BatchEvent)
+}
+
+// BatchEvent gets passed to BatchOptions.OnEvent. For the event of the whole
+// batch Index is -1 and SQL empty. In multi statement mode the statement
+// events get fired after the round trip succeeded and their Duration is zero.
+type BatchEvent struct {
+	Index          int
+	SQL            string
+	MultiStatement bool
+	Duration       time.Duration
+	Result         sql.Result
+	Err            error
+}
+
+// BatchError gets returned by ExecBatch and identifies the failing statement.
+// Index is -1 if the failing statement cannot be determined, e.g. when a
+// concatenated multi statement round trip fails.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (be *BatchError) Error() string {
+	if be.Index < 0 {
+		return fmt.Sprintf("[dml] ExecBatch: Multi statement round trip failed: %s", be.Err)
+	}
+	return fmt.Sprintf("[dml] ExecBatch: Statement at index %d failed: %s", be.Index, be.Err)
+}
+
+// Cause returns the underlying error.
+func (be *BatchError) Cause() error { return be.Err }
+
+// Unwrap returns the underlying error.
+func (be *BatchError) Unwrap() error { return be.Err }
+
+// batchResult implements sql.Result for the statements of a multi statement
+// round trip.
+type batchResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (br batchResult) LastInsertId() (int64, error) { return br.lastInsertID, nil }
+func (br batchResult) RowsAffected() (int64, error) { return br.rowsAffected, nil }
+
+// multiResulter gets implemented by the result of the MySQL driver when
+// executing multiple statements in one round trip.
+type multiResulter interface {
+	AllRowsAffected() []int64
+	AllLastInsertIds() []int64
+}
+
+// ExecBatch executes many independent statements on one pinned connection.
+// The returned results have the same order as stmts.
+//
+// With multi statement support, see BatchOptions.MultiStatements, the
+// interpolated statements get joined with semicolons and executed in one round
+// trip. Placeholders and semicolons outside of string literals are not allowed
+// in a statement. If the driver cannot report the result of each statement,
+// all results are equal to the single result returned by the driver.
+//
+// Otherwise the statements get executed sequentially. Errors are of type
+// *BatchError.
+func (c *ConnPool) ExecBatch(ctx context.Context, stmts []QueryBuilder, opts BatchOptions) (results []sql.Result, err error) {
+	opts.MultiStatements = opts.MultiStatements || (c.dsn != nil && c.dsn.MultiStatements)
+	start := now()
+	defer func() {
+		c.fireBatchEvent(opts, BatchEvent{Index: -1, MultiStatement: opts.MultiStatements, Duration: now().Sub(start), Err: err})
+	}()
+	if len(stmts) == 0 {
+		return nil, nil
+	}
+
+	conn, err := c.Conn(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() {
+		if errC := conn.Close(); errC != nil && err == nil {
+			err = errors.WithStack(errC)
+		}
+	}()
+
+	if opts.MultiStatements {
+		return c.execBatchMulti(ctx, conn, stmts, opts)
+	}
+
+	var db Execer = conn.DB
+	var tx *Tx
+	if opts.Transaction {
+		if tx, err = conn.BeginTx(ctx, nil); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		db = tx.DB
+	}
+	results = make([]sql.Result, 0, len(stmts))
+	for i, qb := range stmts {
+		stmtStart := now()
+		sqlStr, args, errSQL := qb.ToSQL()
+		var res sql.Result
+		if errSQL == nil {
+			res, errSQL = db.ExecContext(ctx, sqlStr, args...)
+		}
+		c.fireBatchEvent(opts, BatchEvent{Index: i, SQL: sqlStr, Duration: now().Sub(stmtStart), Result: res, Err: errSQL})
+		if errSQL != nil {
+			if tx != nil {
+				_ = tx.Rollback() // the error of the statement is more important
+			}
+			return nil, &BatchError{Index: i, Err: errors.WithStack(errSQL)}
+		}
+		results = append(results, res)
+	}
+	if tx != nil {
+		if err = tx.Commit(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return results, nil
+}
+
+func (c *ConnPool) execBatchMulti(ctx context.Context, conn *Conn, stmts []QueryBuilder, opts BatchOptions) ([]sql.Result, error) {
+	queries := make([]string, len(stmts))
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	if opts.Transaction {
+		buf.WriteString("START TRANSACTION;")
+	}
+	for i, qb := range stmts {
+		rawSQL, args, err := qb.ToSQL()
+		if err != nil {
+			return nil, &BatchError{Index: i, Err: errors.WithStack(err)}
+		}
+		sqlStr, redactedSQL := rawSQL, rawSQL
+		if len(args) > 0 {
+			ibuf := bufferpool.Get()
+			err = writeInterpolate(c.queryCache.sqlDialect(), ibuf, rawSQL, args)
+			sqlStr = ibuf.String()
+			bufferpool.Put(ibuf)
+			if err != nil {
+				return nil, &BatchError{Index: i, Err: errors.WithStack(err)}
+			}
+			redactedSQL = sqlStr
+			if c.queryCache.redactor != nil {
+				redactedSQL = c.queryCache.redactInterpolate(qb, rawSQL, args)
+			}
+		}
+		semicolons, placeholders := batchScanSQL(sqlStr)
+		switch {
+		case semicolons > 0:
+			return nil, &BatchError{Index: i, Err: errors.NotValid.Newf("[dml] ExecBatch: Statement contains a semicolon outside of a string literal: %q", redactedSQL)}
+		case placeholders > 0:
+			return nil, &BatchError{Index: i, Err: errors.NotValid.Newf("[dml] ExecBatch: Statement must be interpolated, found %d placeholders: %q", placeholders, redactedSQL)}
+		}
+		queries[i] = redactedSQL
+		if i > 0 {
+			buf.WriteByte(';')
+		}
+		buf.WriteString(sqlStr)
+	}
+	if opts.Transaction {
+		buf.WriteString(";COMMIT")
+	}
+
+	var res driver.Result
+	err := conn.DB.Raw(func(dc interface{}) error {
+		ex, ok := dc.(driver.ExecerContext)
+		if !ok {
+			return errors.NotSupported.Newf("[dml] ExecBatch: Driver connection %T does not implement driver.ExecerContext", dc)
+		}
+		var err error
+		res, err = ex.ExecContext(ctx, buf.String(), nil)
+		return err
+	})
+	if err != nil {
+		if opts.Transaction {
+			// A failed statement stops the execution, hence the transaction
+			// is still open.
+			_, _ = conn.DB.ExecContext(context.Background(), "ROLLBACK")
+		}
+		return nil, &BatchError{Index: -1, Err: errors.WithStack(err)}
+	}
+
+	results := make([]sql.Result, len(stmts))
+	var affected, insertIDs []int64
+	if mr, ok := res.(multiResulter); ok {
+		affected, insertIDs = mr.AllRowsAffected(), mr.AllLastInsertIds()
+		if opts.Transaction && len(affected) == len(stmts)+2 {
+			affected, insertIDs = affected[1:len(affected)-1], insertIDs[1:len(insertIDs)-1]
+		}
+	}
+	for i := range results {
+		if len(affected) == len(stmts) && len(insertIDs) == len(stmts) {
+			results[i] = batchResult{lastInsertID: insertIDs[i], rowsAffected: affected[i]}
+		} else {
+			results[i] = res
+		}
+		c.fireBatchEvent(opts, BatchEvent{Index: i, SQL: queries[i], MultiStatement: true, Result: results[i]})
+	}
+	return results, nil
+}
+
+func (c *ConnPool) fireBatchEvent(opts BatchOptions, be BatchEvent) {
+	atomic.AddUint64(&c.queryCache.counters.eventsFired, 1)
+	if c.Log != nil && c.Log.IsDebug() {
+		c.Log.Debug("ExecBatch", log.Int("index", be.Index), log.String("sql", be.SQL),
+			log.Bool("multi_statement", be.MultiStatement), log.Duration("duration", be.Duration), log.Err(be.Err))
+	}
+	if opts.OnEvent != nil {
+		opts.OnEvent(be)
+	}
+}
+
+// batchScanSQL counts the semicolons and placeholders outside of string
+// literals, quoted identifiers and comments.
+func batchScanSQL(s string) (semicolons, placeholders int) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case ';':
+			semicolons++
+		case '?':
+			placeholders++
+		case '\'', '"', '`':
+			for i++; i < len(s) && s[i] != c; i++ {
+				if s[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case '-':
+			if i+1 < len(s) && s[i+1] == '-' {
+				for i < len(s) && s[i] != '\n' {
+					i++
+				}
+			}
+		case '/':
+			if i+1 < len(s) && s[i+1] == '*' {
+				i += 2
+				for i+1 < len(s) && !(s[i] == '*' && s[i+1] == '/') {
+					i++
+				}
+				i++
+			}
+		}
+	}
+	return
+}
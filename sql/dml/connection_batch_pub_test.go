@@ -0,0 +1,154 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func batchArgs(query string, args ...interface{}) dml.QueryBuilder {
+	return dml.QuerySQLFn(func() (string, []interface{}, error) {
+		return query, args, nil
+	})
+}
+
+func TestConnPool_ExecBatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sequential", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `core_store` (`code`) VALUES (?)")).
+			WithArgs("de").WillReturnResult(sqlmock.NewResult(4, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta("DELETE FROM `core_store` WHERE `code` = 'at'")).
+			WithArgs().WillReturnResult(sqlmock.NewResult(0, 2))
+
+		var events []dml.BatchEvent
+		results, err := dbc.ExecBatch(ctx, []dml.QueryBuilder{
+			batchArgs("INSERT INTO `core_store` (`code`) VALUES (?)", "de"),
+			dml.QuerySQL("DELETE FROM `core_store` WHERE `code` = 'at'"),
+		}, dml.BatchOptions{
+			OnEvent: func(be dml.BatchEvent) { events = append(events, be) },
+		})
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		lid, _ := results[0].LastInsertId()
+		assert.Exactly(t, int64(4), lid)
+		ra, _ := results[1].RowsAffected()
+		assert.Exactly(t, int64(2), ra)
+
+		assert.Len(t, events, 3)
+		assert.Exactly(t, 0, events[0].Index)
+		assert.Exactly(t, 1, events[1].Index)
+		assert.Exactly(t, -1, events[2].Index)
+		assert.False(t, events[2].MultiStatement)
+		assert.Exactly(t, uint64(3), dbc.Stats().EventsFired)
+	})
+
+	t.Run("sequential transaction rollback", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec("UPDATE").WillReturnError(errors.AlreadyClosed.Newf("Connection lost"))
+		dbMock.ExpectRollback()
+
+		results, err := dbc.ExecBatch(ctx, []dml.QueryBuilder{
+			dml.QuerySQL("INSERT INTO `core_store` (`code`) VALUES ('de')"),
+			dml.QuerySQL("UPDATE `core_store` SET `code` = 'at'"),
+			dml.QuerySQL("DELETE FROM `core_store`"),
+		}, dml.BatchOptions{Transaction: true})
+		assert.Nil(t, results)
+		be, ok := err.(*dml.BatchError)
+		assert.True(t, ok, "%+v", err)
+		assert.Exactly(t, 1, be.Index)
+		assert.True(t, errors.AlreadyClosed.Match(err), "%+v", err)
+	})
+
+	t.Run("multi statement", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `core_store` (`code`) VALUES ('de');UPDATE `core_store` SET `name` = 'a;b' WHERE `store_id` = 3")).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		var events []dml.BatchEvent
+		results, err := dbc.ExecBatch(ctx, []dml.QueryBuilder{
+			batchArgs("INSERT INTO `core_store` (`code`) VALUES (?)", "de"),
+			dml.Interpolate("UPDATE `core_store` SET `name` = 'a;b' WHERE `store_id` = ?").Int(3),
+		}, dml.BatchOptions{
+			MultiStatements: true,
+			OnEvent:         func(be dml.BatchEvent) { events = append(events, be) },
+		})
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		ra, _ := results[1].RowsAffected()
+		assert.Exactly(t, int64(2), ra)
+
+		assert.Len(t, events, 3)
+		assert.Exactly(t, "INSERT INTO `core_store` (`code`) VALUES ('de')", events[0].SQL)
+		assert.True(t, events[1].MultiStatement)
+		assert.Exactly(t, -1, events[2].Index)
+	})
+
+	t.Run("multi statement transaction rollback", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectExec(regexp.QuoteMeta("START TRANSACTION;DELETE FROM `core_store`;DELETE FROM `core_website`;COMMIT")).
+			WillReturnError(errors.AlreadyClosed.Newf("Connection lost"))
+		dbMock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := dbc.ExecBatch(ctx, []dml.QueryBuilder{
+			dml.QuerySQL("DELETE FROM `core_store`"),
+			dml.QuerySQL("DELETE FROM `core_website`"),
+		}, dml.BatchOptions{MultiStatements: true, Transaction: true})
+		be, ok := err.(*dml.BatchError)
+		assert.True(t, ok, "%+v", err)
+		assert.Exactly(t, -1, be.Index)
+		assert.True(t, errors.AlreadyClosed.Match(err), "%+v", err)
+	})
+
+	t.Run("multi statement validation", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		for _, stmts := range [][]dml.QueryBuilder{
+			{dml.QuerySQL("SELECT 1"), dml.QuerySQL("DELETE FROM `core_store`; DROP TABLE `core_store`")},
+			{dml.QuerySQL("SELECT 1"), dml.QuerySQL("DELETE FROM `core_store` WHERE `code` = ?")},
+		} {
+			_, err := dbc.ExecBatch(ctx, stmts, dml.BatchOptions{MultiStatements: true})
+			be, ok := err.(*dml.BatchError)
+			assert.True(t, ok, "%+v", err)
+			assert.Exactly(t, 1, be.Index)
+			assert.True(t, errors.NotValid.Match(err), "%+v", err)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		results, err := dbc.ExecBatch(ctx, nil, dml.BatchOptions{})
+		assert.NoError(t, err)
+		assert.Exactly(t, []sql.Result(nil), results)
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"testing"
+
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestBatchScanSQL(t *testing.T) {
+	tests := []struct {
+		sql          string
+		semicolons   int
+		placeholders int
+	}{
+		{"SELECT 1", 0, 0},
+		{"SELECT 1;", 1, 0},
+		{"SELECT 'a;b', \"c;?\", `d;e` FROM t WHERE a = ?", 0, 1},
+		{`SELECT 'it\'s;' FROM t`, 0, 0},
+		{"SELECT 'it''s;' FROM t; DROP TABLE t", 1, 0},
+		{"SELECT 1 -- comment; ?\n; SELECT 2", 1, 0},
+		{"SELECT 1 # comment; ?\nFROM t WHERE a IN (?,?)", 0, 2},
+		{"SELECT /* a; ? */ 1 /* unterminated ;", 0, 0},
+		{"SELECT a-1 FROM t WHERE b = 'x'", 0, 0},
+		{"SELECT 'unterminated;", 0, 0},
+	}
+	for i, test := range tests {
+		s, p := batchScanSQL(test.sql)
+		assert.Exactly(t, test.semicolons, s, "Index %d semicolons", i)
+		assert.Exactly(t, test.placeholders, p, "Index %d placeholders", i)
+	}
+}
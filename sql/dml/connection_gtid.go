@@ -0,0 +1,176 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/go-sql-driver/mysql"
+)
+
+// DefaultGTIDWaitTimeout bounds the time a replica gets for applying the GTID
+// set of QueryOptions.WaitForGTID.
+const DefaultGTIDWaitTimeout = time.Second
+
+// GTIDResult gets returned by the Exec functions of a DBR if session tracking
+// has been enabled, see WithSessionTracking.
+type GTIDResult struct {
+	sql.Result
+	// GTID contains the executed GTID set of the primary after the write.
+	GTID string
+}
+
+// gtidTracker captures the GTID set after writes on the primary. A nil
+// gtidTracker or one with GTIDs disabled on the server is a no-op.
+type gtidTracker struct {
+	primary     *sql.DB
+	enabled     bool
+	waitTimeout time.Duration
+}
+
+// WithSessionTracking captures after each Exec on the primary the executed
+// GTID set, for read-your-writes consistency with replicas. The GTID set gets
+// returned as GTIDResult and via LastGTIDFromContext, if the context has been
+// created by WithContextSessionTracking. A read query with the GTID set in the
+// QueryOptions field WaitForGTID waits on the chosen replica until the replica
+// has applied the GTID set or falls back to the primary after the
+// DefaultGTIDWaitTimeout. Writes within a transaction capture no GTID. The
+// option is a no-op if the server has GTIDs disabled, which gets detected once
+// with the creation of the ConnPool.
+//
+//	ctx = dml.WithContextSessionTracking(ctx)
+//	_, err = dbc.WithQueryBuilder(insert).ExecContext(ctx, record)
+//	ctx = dml.WithContextQueryOptions(ctx, dml.QueryOptions{WaitForGTID: dml.LastGTIDFromContext(ctx)})
+//	_, err = dbc.WithQueryBuilder(sel).Load(ctx, record)
+func WithSessionTracking() ConnPoolOption {
+	return ConnPoolOption{
+		sortOrder: 160, // after WithVerifyConnection and WithCreateDatabase
+		fn: func(c *ConnPool) error {
+			if c.DB == nil {
+				return errors.NotValid.Newf("[dml] WithSessionTracking requires a database connection")
+			}
+			var mode string
+			if err := c.DB.QueryRowContext(context.Background(), "SELECT @@global.gtid_mode").Scan(&mode); err != nil {
+				if myErr, ok := errors.Cause(err).(*mysql.MySQLError); !ok || myErr.Number != 1193 { // 1193 = Unknown system variable, e.g. MariaDB
+					return errors.Wrapf(err, "[dml] WithSessionTracking failed to detect the gtid_mode")
+				}
+			}
+			c.queryCache.gtid = &gtidTracker{
+				primary:     c.DB,
+				enabled:     mode == "ON",
+				waitTimeout: DefaultGTIDWaitTimeout,
+			}
+			return nil
+		},
+	}
+}
+
+func (gt *gtidTracker) isEnabled() bool {
+	return gt != nil && gt.enabled
+}
+
+type ctxKeyGTID struct{}
+
+// gtidHolder stores the GTID set of the last write within a context.
+type gtidHolder struct {
+	mu   sync.Mutex
+	gtid string
+}
+
+// WithContextSessionTracking returns a context which stores the GTID set of
+// the last write executed with this context, see LastGTIDFromContext.
+func WithContextSessionTracking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyGTID{}, &gtidHolder{})
+}
+
+// LastGTIDFromContext returns the GTID set captured by the last write
+// executed with the context. It returns an empty string if no GTID has been
+// captured or if the context has not been created by
+// WithContextSessionTracking. See WithSessionTracking.
+func LastGTIDFromContext(ctx context.Context) string {
+	h, _ := ctx.Value(ctxKeyGTID{}).(*gtidHolder)
+	if h == nil {
+		return ""
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.gtid
+}
+
+// capturesGTID reports whether an Exec of the DBR runs on the primary outside
+// of a transaction.
+func (a *DBR) capturesGTID() bool {
+	if !a.gtid.isEnabled() {
+		return false
+	}
+	switch db := a.DB.(type) {
+	case *sql.DB, *replicaRouter, *sql.Conn:
+		return true
+	case stmtWrapper:
+		_, ok := db.stmt.(*preparedStmt)
+		return ok
+	}
+	return false
+}
+
+// captureGTID wraps the result with the executed GTID set of the primary. An
+// error gets logged because the write has already been executed.
+func (a *DBR) captureGTID(ctx context.Context, result sql.Result) sql.Result {
+	var gtid string
+	if err := a.gtid.primary.QueryRowContext(ctx, "SELECT @@global.gtid_executed").Scan(&gtid); err != nil {
+		if a.log != nil && a.log.IsInfo() {
+			a.log.Info("DBR.captureGTID", log.Err(err), log.String("id", a.cachedSQL.id))
+		}
+		return result
+	}
+	if h, _ := ctx.Value(ctxKeyGTID{}).(*gtidHolder); h != nil {
+		h.mu.Lock()
+		h.gtid = gtid
+		h.mu.Unlock()
+	}
+	return GTIDResult{Result: result, GTID: gtid}
+}
+
+// gtidToWaitFor returns the GTID set a replica must have applied before
+// running a read query.
+func (r *replicaRouter) gtidToWaitFor(ctx context.Context) string {
+	if !r.gtid.isEnabled() {
+		return ""
+	}
+	return FromContextQueryOptions(ctx).WaitForGTID
+}
+
+// waitForGTID waits until the replica db has applied the GTID set. It returns
+// false if the replica has not caught up within the timeout. Only connection
+// errors get returned, all other errors get logged.
+func (r *replicaRouter) waitForGTID(ctx context.Context, db *sql.DB, gtid string) (bool, error) {
+	var timedOut sql.NullInt64
+	err := db.QueryRowContext(ctx, "SELECT WAIT_FOR_EXECUTED_GTID_SET(?, ?)", gtid, r.gtid.waitTimeout.Seconds()).Scan(&timedOut)
+	switch {
+	case err != nil && isConnectionError(err):
+		return false, errors.WithStack(err)
+	case err != nil:
+		if r.log != nil && r.log.IsInfo() {
+			r.log.Info("ReplicaWaitForGTID", log.Err(err), log.String("gtid", gtid))
+		}
+		return false, nil
+	}
+	return timedOut.Valid && timedOut.Int64 == 0, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/go-sql-driver/mysql"
+)
+
+const gtidSet = "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5"
+
+// newGTIDPool creates a pool with one replica and the mocks expecting the
+// detection of the gtid_mode.
+func newGTIDPool(t *testing.T, gtidMode string) (*dml.ConnPool, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	rdb, replicaMock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	if gtidMode == "" {
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT @@global.gtid_mode")).
+			WillReturnError(&mysql.MySQLError{Number: 1193, Message: "Unknown system variable 'gtid_mode'"})
+	} else {
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT @@global.gtid_mode")).
+			WillReturnRows(sqlmock.NewRows([]string{"@@global.gtid_mode"}).AddRow(gtidMode))
+	}
+	dbc, err := dml.NewConnPool(dml.WithDB(db), dml.WithReplicaDB(rdb), dml.WithSessionTracking())
+	assert.NoError(t, err)
+	return dbc, dbMock, replicaMock
+}
+
+func closeGTIDPool(t *testing.T, dbc *dml.ConnPool, dbMock, replicaMock sqlmock.Sqlmock) {
+	replicaMock.ExpectClose()
+	dbMock.ExpectClose()
+	assert.NoError(t, dbc.Close())
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestWithSessionTracking(t *testing.T) {
+	insertA := dml.NewInsert("tableA").AddColumns("a")
+	selectA := dml.NewSelect("a").From("tableA")
+	rowsA := func(v int) *sqlmock.Rows { return sqlmock.NewRows([]string{"a"}).AddRow(v) }
+	waitSQL := dmltest.SQLMockQuoteMeta("SELECT WAIT_FOR_EXECUTED_GTID_SET(?, ?)")
+
+	t.Run("captures the GTID after a write", func(t *testing.T) {
+		dbc, dbMock, replicaMock := newGTIDPool(t, "ON")
+		defer closeGTIDPool(t, dbc, dbMock, replicaMock)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `tableA` (`a`) VALUES (?)")).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT @@global.gtid_executed")).
+			WillReturnRows(sqlmock.NewRows([]string{"@@global.gtid_executed"}).AddRow(gtidSet))
+
+		ctx := dml.WithContextSessionTracking(context.Background())
+		res, err := dbc.WithQueryBuilder(insertA.Clone()).ExecContext(ctx, 5)
+		assert.NoError(t, err)
+		gr, ok := res.(dml.GTIDResult)
+		assert.True(t, ok, "result must be a GTIDResult")
+		assert.Exactly(t, gtidSet, gr.GTID)
+		assert.Exactly(t, gtidSet, dml.LastGTIDFromContext(ctx))
+		ra, err := gr.RowsAffected()
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(1), ra)
+	})
+
+	t.Run("no capture within a transaction", func(t *testing.T) {
+		dbc, dbMock, replicaMock := newGTIDPool(t, "ON")
+		defer closeGTIDPool(t, dbc, dbMock, replicaMock)
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `tableA` (`a`) VALUES (?)")).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		ctx := dml.WithContextSessionTracking(context.Background())
+		assert.NoError(t, dbc.Transaction(ctx, nil, func(tx *dml.Tx) error {
+			_, err := tx.WithQueryBuilder(insertA.Clone()).ExecContext(ctx, 5)
+			return err
+		}))
+		assert.Exactly(t, "", dml.LastGTIDFromContext(ctx))
+	})
+
+	t.Run("replica waits for the GTID", func(t *testing.T) {
+		dbc, dbMock, replicaMock := newGTIDPool(t, "ON")
+		defer closeGTIDPool(t, dbc, dbMock, replicaMock)
+
+		replicaMock.ExpectQuery(waitSQL).WithArgs(gtidSet, 1.0).WillReturnRows(sqlmock.NewRows([]string{"w"}).AddRow(0))
+		replicaMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA`")).WillReturnRows(rowsA(1))
+
+		ctx := dml.WithContextQueryOptions(context.Background(), dml.QueryOptions{WaitForGTID: gtidSet})
+		v, _, err := dbc.WithQueryBuilder(selectA.Clone()).LoadNullInt64(ctx)
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(1), v.Int64)
+	})
+
+	t.Run("timeout falls back to the primary", func(t *testing.T) {
+		dbc, dbMock, replicaMock := newGTIDPool(t, "ON")
+		defer closeGTIDPool(t, dbc, dbMock, replicaMock)
+
+		replicaMock.ExpectQuery(waitSQL).WithArgs(gtidSet, 1.0).WillReturnRows(sqlmock.NewRows([]string{"w"}).AddRow(1))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA`")).WillReturnRows(rowsA(2))
+
+		ctx := dml.WithContextQueryOptions(context.Background(), dml.QueryOptions{WaitForGTID: gtidSet})
+		v, _, err := dbc.WithQueryBuilder(selectA.Clone()).LoadNullInt64(ctx)
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(2), v.Int64)
+	})
+
+	for _, mode := range []string{"OFF", ""} {
+		t.Run("GTIDs disabled "+mode, func(t *testing.T) {
+			dbc, dbMock, replicaMock := newGTIDPool(t, mode)
+			defer closeGTIDPool(t, dbc, dbMock, replicaMock)
+
+			dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `tableA` (`a`) VALUES (?)")).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+			replicaMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA`")).WillReturnRows(rowsA(3))
+
+			ctx := dml.WithContextSessionTracking(context.Background())
+			res, err := dbc.WithQueryBuilder(insertA.Clone()).ExecContext(ctx, 5)
+			assert.NoError(t, err)
+			_, ok := res.(dml.GTIDResult)
+			assert.False(t, ok)
+
+			ctx = dml.WithContextQueryOptions(ctx, dml.QueryOptions{WaitForGTID: gtidSet})
+			v, _, err := dbc.WithQueryBuilder(selectA.Clone()).LoadNullInt64(ctx)
+			assert.NoError(t, err)
+			assert.Exactly(t, int64(3), v.Int64)
+		})
+	}
+
+	t.Run("requires a DB", func(t *testing.T) {
+		_, err := dml.NewConnPool(dml.WithSessionTracking())
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"strings"
+
+	"github.com/corestoreio/pkg/util/bufferpool"
+)
+
+type ctxKeyQueryComment struct{}
+
+// WithQueryComment adds key/value pairs to the context which get prefixed as
+// a comment to the SQL of each query executed by a DBR with this context, for
+// example to attribute slow queries to the call site:
+//		/* route=checkout,user=42 */ SELECT ...
+// The pairs get appended to the pairs of the parent context. kv must be a
+// balanced key,value slice, a trailing key without value gets ignored. The
+// comment gets added at execution time and does not affect the cached SQL. It
+// gets not added to prepared statements, see WithQueryCommentOnPrepared.
+func WithQueryComment(ctx context.Context, kv ...string) context.Context {
+	prev, _ := ctx.Value(ctxKeyQueryComment{}).([]string)
+	return context.WithValue(ctx, ctxKeyQueryComment{}, append(prev[:len(prev):len(prev)], kv...))
+}
+
+// queryComment contains the static tags of a connection pool.
+type queryComment struct {
+	tags       []string
+	onPrepared bool
+}
+
+// WithQueryCommentTags adds static key/value pairs, like the service name and
+// its version, to the comment of each query. See WithQueryComment.
+func WithQueryCommentTags(kv ...string) ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			qc := c.queryCommenter()
+			qc.tags = append(qc.tags, kv...)
+			return nil
+		},
+	}
+}
+
+// WithQueryCommentOnPrepared adds the comment also to prepared statements. The
+// comment gets created from the context when preparing the statement, hence
+// statements with different comments cannot be reused by the server.
+func WithQueryCommentOnPrepared() ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			c.queryCommenter().onPrepared = true
+			return nil
+		},
+	}
+}
+
+func (c *ConnPool) queryCommenter() *queryComment {
+	if c.queryCache.queryComment == nil {
+		c.queryCache.queryComment = &queryComment{}
+	}
+	return c.queryCache.queryComment
+}
+
+// prefix prepends the comment of the static tags and the context to sqlStr.
+// The receiver can be nil.
+func (qc *queryComment) prefix(ctx context.Context, sqlStr string) string {
+	var tags []string
+	if qc != nil {
+		tags = qc.tags
+	}
+	ctxTags, _ := ctx.Value(ctxKeyQueryComment{}).([]string)
+	if sqlStr == "" || (len(tags) < 2 && len(ctxTags) < 2) {
+		return sqlStr
+	}
+
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	buf.WriteString("/* ")
+	var pairs int
+	for _, kv := range [2][]string{tags, ctxTags} {
+		for i := 0; i+1 < len(kv); i += 2 {
+			if pairs > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(escapeQueryComment(kv[i]))
+			buf.WriteByte('=')
+			buf.WriteString(escapeQueryComment(kv[i+1]))
+			pairs++
+		}
+	}
+	buf.WriteString(" */ ")
+	buf.WriteString(sqlStr)
+	return buf.String()
+}
+
+// escapeQueryComment avoids the termination of the comment.
+func escapeQueryComment(s string) string {
+	if !strings.Contains(s, "*/") {
+		return s
+	}
+	return strings.Replace(s, "*/", `*\/`, -1)
+}
@@ -0,0 +1,89 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestWithQueryComment(t *testing.T) {
+	t.Run("context and static tags", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithQueryCommentTags("service", "shop"))
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectQuery("^" + dmltest.SQLMockQuoteMeta("/* service=shop,route=checkout,x=a*\\/b */ SELECT `a` FROM `tableA` WHERE (`b` = ?)")).
+			WithArgs("secret").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+		dbMock.ExpectExec("^" + dmltest.SQLMockQuoteMeta("/* service=shop,route=checkout,x=a*\\/b,user=42 */ DELETE FROM `tableA`")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery("^" + dmltest.SQLMockQuoteMeta("/* service=shop */ SELECT `a` FROM `tableA` WHERE (`b` = ?)")).
+			WithArgs("secret").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+
+		ctx := dml.WithQueryComment(context.Background(), "route", "checkout", "x", "a*/b")
+		dbr := dbc.WithQueryBuilder(dml.NewSelect("a").From("tableA").Where(dml.Column("b").PlaceHolder()))
+
+		_, err := dbr.LoadInt64s(ctx, nil, "secret")
+		assert.NoError(t, err)
+		_, err = dbc.WithQueryBuilder(dml.NewDelete("tableA")).ExecContext(dml.WithQueryComment(ctx, "user", "42", "dangling"))
+		assert.NoError(t, err)
+		_, err = dbr.LoadInt64s(context.Background(), nil, "secret")
+		assert.NoError(t, err, "the cached SQL must not contain the comment of the previous call")
+	})
+
+	t.Run("no tags", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec("^" + dmltest.SQLMockQuoteMeta("/* route=checkout */ DELETE FROM `tableA`")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec("^" + dmltest.SQLMockQuoteMeta("DELETE FROM `tableA`")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		dbr := dbc.WithQueryBuilder(dml.NewDelete("tableA"))
+		_, err := dbr.ExecContext(dml.WithQueryComment(context.Background(), "route", "checkout"))
+		assert.NoError(t, err)
+		_, err = dbr.ExecContext(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("prepared statements", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithQueryCommentTags("service", "shop"))
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectPrepare("^" + dmltest.SQLMockQuoteMeta("DELETE FROM `tableA` WHERE (`a` = ?)")).
+			ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx := dml.WithQueryComment(context.Background(), "route", "checkout")
+		_, err := dbc.WithPrepare(ctx, dml.NewDelete("tableA").Where(dml.Column("a").PlaceHolder())).ExecContext(ctx, 2)
+		assert.NoError(t, err)
+	})
+
+	t.Run("prepared statements forced", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithQueryCommentTags("service", "shop"), dml.WithQueryCommentOnPrepared())
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectPrepare("^" + dmltest.SQLMockQuoteMeta("/* service=shop,route=checkout */ DELETE FROM `tableA` WHERE (`a` = ?)")).
+			ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx := dml.WithQueryComment(context.Background(), "route", "checkout")
+		_, err := dbc.WithPrepare(ctx, dml.NewDelete("tableA").Where(dml.Column("a").PlaceHolder())).ExecContext(ctx, 2)
+		assert.NoError(t, err)
+	})
+}
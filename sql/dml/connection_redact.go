@@ -0,0 +1,185 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"strings"
+
+	"github.com/corestoreio/pkg/util/bufferpool"
+)
+
+// ArgumentRedactor returns the value which gets rendered instead of v, for
+// example a mask or a truncated value. The column contains the name of the
+// column without qualifier, or is empty if the argument cannot be assigned to
+// a column, for example in an IN clause with an expanded slice.
+type ArgumentRedactor func(column string, v interface{}) interface{}
+
+// WithArgumentRedactor applies fn to each argument whenever SQL or arguments
+// get rendered for other purposes than the execution: interpolated SQL in log
+// messages and errors, the arguments of a SlowQueryInfo and the statements of
+// an ExecBatch event. The arguments sent to the driver never get redacted.
+// ListenerEvent.Args contains still the raw arguments because listeners might
+// need them. See NewArgumentRedactor for a default implementation.
+func WithArgumentRedactor(fn ArgumentRedactor) ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			c.queryCache.redactor = fn
+			return nil
+		},
+	}
+}
+
+// DefaultRedactColumns contains the default substrings of column names whose
+// values get masked by NewArgumentRedactor.
+var DefaultRedactColumns = []string{"password", "token", "email"}
+
+// RedactOptions configures the ArgumentRedactor created by
+// NewArgumentRedactor.
+type RedactOptions struct {
+	// Columns contains case-insensitive substrings of column names whose
+	// values get masked. Defaults to DefaultRedactColumns.
+	Columns []string
+	// Mask replaces the masked values, defaults to "[redacted]".
+	Mask string
+	// MaxBytes truncates byte slices which are longer, defaults to 64 bytes. A
+	// negative value disables the truncation.
+	MaxBytes int
+}
+
+// NewArgumentRedactor creates an ArgumentRedactor which masks the values of
+// matching columns and truncates large byte slices. NULL values stay
+// unchanged.
+func NewArgumentRedactor(o RedactOptions) ArgumentRedactor {
+	columns := o.Columns
+	if columns == nil {
+		columns = DefaultRedactColumns
+	}
+	lcColumns := make([]string, len(columns))
+	for i, c := range columns {
+		lcColumns[i] = strings.ToLower(c)
+	}
+	if o.Mask == "" {
+		o.Mask = "[redacted]"
+	}
+	if o.MaxBytes == 0 {
+		o.MaxBytes = 64
+	}
+
+	return func(column string, v interface{}) interface{} {
+		switch v.(type) {
+		case nil, internalNULLNIL:
+			return v
+		}
+		column = strings.ToLower(column)
+		for _, c := range lcColumns {
+			if column != "" && strings.Contains(column, c) {
+				return o.Mask
+			}
+		}
+		if b, ok := v.([]byte); ok && o.MaxBytes > 0 && len(b) > o.MaxBytes {
+			return append(b[:o.MaxBytes:o.MaxBytes], "..."...)
+		}
+		return v
+	}
+}
+
+// redactArgs returns a new slice with the redacted arguments. The column
+// names get resolved by the position of the argument in the qualified columns.
+// For INSERT statements the columns repeat for each row.
+func redactArgs(fn ArgumentRedactor, qualifiedColumns []string, args []interface{}) []interface{} {
+	lenCols := len(qualifiedColumns)
+	ret := make([]interface{}, len(args))
+	for i, arg := range args {
+		var column string
+		switch {
+		case lenCols == len(args):
+			column = qualifiedColumns[i]
+		case lenCols > 0 && len(args)%lenCols == 0:
+			column = qualifiedColumns[i%lenCols]
+		}
+		if pos := strings.LastIndexByte(column, '.'); pos >= 0 {
+			column = column[pos+1:]
+		}
+		ret[i] = fn(strings.TrimPrefix(column, ":"), arg)
+	}
+	return ret
+}
+
+// redactedQuery builds the SQL with placeholders and the redacted arguments.
+// It works on a copy because the options of the DBR might enable the
+// interpolation. The SQL is empty for prepared statements.
+func (a *DBR) redactedQuery(rawArgs []interface{}) (string, []interface{}, error) {
+	a2 := *a
+	a2.Options &^= argOptionInterpolate
+	a2.cachedSQL.qualifiedColumns = append(a.cachedSQL.qualifiedColumns[:0:0], a.cachedSQL.qualifiedColumns...)
+	sqlStr, args, err := a2.prepareQueryAndArgs(rawArgs)
+	if err != nil {
+		return "", nil, err
+	}
+	return sqlStr, redactArgs(a.redactor, a2.cachedSQL.qualifiedColumns, args), nil
+}
+
+// redactSQL returns sqlStr for logging and error messages. An interpolated
+// sqlStr gets rebuilt with the redacted arguments.
+func (a *DBR) redactSQL(sqlStr string, rawArgs []interface{}) string {
+	if a.redactor == nil || a.Options&argOptionInterpolate == 0 || sqlStr == "" {
+		return sqlStr
+	}
+	query, args, err := a.redactedQuery(rawArgs)
+	if err != nil || query == "" {
+		return a.cachedSQL.rawSQL
+	}
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	if err := writeInterpolate(a.sqlDialect(), buf, query, args); err != nil {
+		return query
+	}
+	return buf.String()
+}
+
+// redactArgs returns the redacted arguments in the order of the placeholders.
+// Without a redactor it returns a copy of rawArgs.
+func (a *DBR) redactArgs(rawArgs []interface{}) []interface{} {
+	if a.redactor == nil {
+		return append([]interface{}(nil), rawArgs...)
+	}
+	_, args, err := a.redactedQuery(rawArgs)
+	if err != nil {
+		return redactArgs(a.redactor, nil, rawArgs)
+	}
+	return args
+}
+
+// redactInterpolate interpolates the SQL of a builder with the redacted
+// arguments, for example for the events of ExecBatch. It requires a redactor.
+func (qc *queryCache) redactInterpolate(qb QueryBuilder, rawSQL string, args []interface{}) string {
+	var qualifiedColumns []string
+	switch qbs := qb.(type) {
+	case *Select:
+		qualifiedColumns = qbs.qualifiedColumns
+	case *Insert:
+		qualifiedColumns = qbs.qualifiedColumns
+	case *Update:
+		qualifiedColumns = qbs.qualifiedColumns
+	case *Delete:
+		qualifiedColumns = qbs.qualifiedColumns
+	}
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	if err := writeInterpolate(qc.sqlDialect(), buf, rawSQL, redactArgs(qc.redactor, qualifiedColumns, args)); err != nil {
+		return rawSQL
+	}
+	return buf.String()
+}
@@ -0,0 +1,94 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestNewArgumentRedactor(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		r := dml.NewArgumentRedactor(dml.RedactOptions{})
+		assert.Exactly(t, "[redacted]", r("password", "secret"))
+		assert.Exactly(t, "[redacted]", r("customer_EMAIL", "a@b.c"))
+		assert.Exactly(t, "[redacted]", r("rp_token", []byte("t0k3n")))
+		assert.Exactly(t, "John", r("name", "John"))
+		assert.Exactly(t, "John", r("", "John"))
+		assert.Nil(t, r("password", nil))
+		assert.Exactly(t, append([]byte(strings.Repeat("x", 64)), "..."...), r("data", []byte(strings.Repeat("x", 65))))
+		assert.Exactly(t, []byte("short"), r("data", []byte("short")))
+	})
+	t.Run("custom", func(t *testing.T) {
+		r := dml.NewArgumentRedactor(dml.RedactOptions{Columns: []string{"iban"}, Mask: "***", MaxBytes: -1})
+		assert.Exactly(t, "***", r("iban", "DE00"))
+		assert.Exactly(t, "secret", r("password", "secret"))
+		long := []byte(strings.Repeat("x", 100))
+		assert.Exactly(t, long, r("data", long))
+	})
+}
+
+func TestWithArgumentRedactor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("slow query args", func(t *testing.T) {
+		var sqc slowQueryCollector
+		dbc, dbMock := dmltest.MockDB(t,
+			dml.WithArgumentRedactor(dml.NewArgumentRedactor(dml.RedactOptions{MaxBytes: 2})),
+			dml.WithSlowQueryIncludeArgs(), dml.WithSlowQueryThreshold(0, sqc.handle))
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `customer` (`email`,`name`,`note`) VALUES (?,?,?),(?,?,?)")).
+			WithArgs("a@b.c", "John", []byte("abc"), "c@d.e", "Jane", nil).WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `customer` SET `password`=?, `name`=? WHERE (`customer`.`entity_id` = ?)")).
+			WithArgs("secret", "John", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := dbc.WithQueryBuilder(dml.NewInsert("customer").AddColumns("email", "name", "note").SetRowCount(2)).
+			ExecContext(ctx, "a@b.c", "John", []byte("abc"), "c@d.e", "Jane", nil)
+		assert.NoError(t, err)
+		_, err = dbc.WithQueryBuilder(dml.NewUpdate("customer").AddColumns("password", "name").
+			Where(dml.Column("customer.entity_id").PlaceHolder())).ExecContext(ctx, "secret", "John", 3)
+		assert.NoError(t, err)
+
+		dmltest.MockClose(t, dbc, dbMock) // waits for the handler
+		assert.Len(t, sqc.infos, 2)
+		assert.Exactly(t, []interface{}{"[redacted]", "John", []byte("ab..."), "[redacted]", "Jane", nil}, sqc.infos[0].Args)
+		assert.Exactly(t, []interface{}{"[redacted]", "John", int64(3)}, sqc.infos[1].Args)
+	})
+
+	t.Run("interpolated error message", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithArgumentRedactor(dml.NewArgumentRedactor(dml.RedactOptions{})))
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `customer` SET `email`='a@b.c' WHERE (`entity_id` = 3)")).
+			WillReturnError(errors.New("deadlock"))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `name` FROM `customer` WHERE (`email` = 'a@b.c')")).
+			WillReturnError(errors.New("timeout"))
+
+		_, err := dbc.WithQueryBuilder(dml.NewUpdate("customer").AddColumns("email").
+			Where(dml.Column("entity_id").PlaceHolder())).Interpolate().ExecContext(ctx, "a@b.c", 3)
+		assert.EqualError(t, err, "[dml] ExecContext with query \"UPDATE `customer` SET `email`='[redacted]' WHERE (`entity_id` = 3)\": deadlock")
+
+		_, err = dbc.WithQueryBuilder(dml.NewSelect("name").From("customer").
+			Where(dml.Column("email").PlaceHolder())).Interpolate().QueryContext(ctx, "a@b.c")
+		assert.EqualError(t, err, "[dml] Query.QueryContext with query \"SELECT `name` FROM `customer` WHERE (`email` = '[redacted]')\": timeout")
+	})
+}
@@ -0,0 +1,365 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/go-sql-driver/mysql"
+)
+
+// ReplicaStrategy defines how a replica gets selected for a read query.
+type ReplicaStrategy uint8
+
+// Strategies for selecting a replica.
+const (
+	// ReplicaRoundRobin selects the replicas in turn. Default strategy.
+	ReplicaRoundRobin ReplicaStrategy = iota
+	// ReplicaLeastConnections selects the replica with the fewest
+	// connections in use.
+	ReplicaLeastConnections
+)
+
+// ReplicaFailover describes a read query which failed on a replica due to a
+// connection error and gets retried on the next replica or on the primary.
+type ReplicaFailover struct {
+	// Replica is the index of the failed replica.
+	Replica int
+	// Next is the index of the next replica or -1 for the primary.
+	Next int
+	Err  error
+}
+
+// NewConnPoolWithReplicas creates a connection pool which routes SELECT, SHOW
+// and WITH queries, outside of a transaction, to the replicas. All other
+// statements, locking reads (FOR UPDATE, LOCK IN SHARE MODE) and everything
+// within a Tx or Conn run on the primary. The QueryOptions field ForcePrimary
+// in the context routes a read query to the primary, e.g. for read-after-write
+// consistency:
+//
+//	ctx = dml.WithContextQueryOptions(ctx, dml.QueryOptions{ForcePrimary: true})
+//
+// A read query failing with a connection error on a replica gets retried on
+// the next replica and finally on the primary, see
+// WithReplicaFailoverHandler. Prepared statements get prepared on the
+// connection on which they will run. A ConnPool created by NewConnPool with
+// the option WithReplicaDB behaves the same, useful for testing.
+func NewConnPoolWithReplicas(primaryDSN string, replicaDSNs []string, opts ...ConnPoolOption) (*ConnPool, error) {
+	return NewConnPool(append([]ConnPoolOption{WithDSN(primaryDSN), withReplicaDSN(replicaDSNs)}, opts...)...)
+}
+
+func withReplicaDSN(dsns []string) ConnPoolOption {
+	return ConnPoolOption{
+		sortOrder: 2, // must run after WithDSN
+		fn: func(c *ConnPool) error {
+			for i, dsn := range dsns {
+				if !strings.Contains(dsn, "parseTime") {
+					return errors.NotImplemented.Newf("[dml] The replica DSN at index %d must contain the parameters `?parseTime=true[&loc=YourTimeZone]`", i)
+				}
+				if _, err := mysql.ParseDSN(dsn); err != nil {
+					return errors.Wrapf(err, "[dml] Failed to parse replica DSN at index %d", i)
+				}
+				var drv driver.Driver = mysql.MySQLDriver{}
+				if c.driverCallBack != nil {
+					drv = wrapDriver(drv, c.driverCallBack, c.queryCache.makeUniqueID != nil)
+				}
+				c.replicas = append(c.replicas, sql.OpenDB(dsnConnector{
+					dsn:    dsn,
+					driver: drv,
+				}))
+			}
+			return nil
+		},
+	}
+}
+
+// WithReplicaDB adds existing connections as read replicas to the pool. Mainly
+// used for testing. See NewConnPoolWithReplicas.
+func WithReplicaDB(dbs ...*sql.DB) ConnPoolOption {
+	return ConnPoolOption{
+		sortOrder: 2,
+		fn: func(c *ConnPool) error {
+			c.replicas = append(c.replicas, dbs...)
+			return nil
+		},
+	}
+}
+
+// WithReplicaStrategy sets the strategy to select a replica. Defaults to
+// ReplicaRoundRobin.
+func WithReplicaStrategy(s ReplicaStrategy) ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			c.replicaStrategy = s
+			return nil
+		},
+	}
+}
+
+// WithReplicaFailoverHandler sets a function which gets called synchronously
+// each time a read query fails over from a replica. The function must be
+// thread safe and should return quickly.
+func WithReplicaFailoverHandler(fn func(ReplicaFailover)) ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			c.onReplicaFailover = fn
+			return nil
+		},
+	}
+}
+
+// replicaRouter implements QueryExecPreparer and routes the read queries to
+// the replicas and all other queries to the primary.
+type replicaRouter struct {
+	primary    *sql.DB
+	replicas   []*sql.DB
+	strategy   ReplicaStrategy
+	next       uint32 // round robin
+	counters   *poolCounters
+	log        log.Logger
+	onFailover func(ReplicaFailover)
+	gtid       *gtidTracker // optional, see WithSessionTracking
+}
+
+func (r *replicaRouter) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	stmt, _, err := r.prepare(ctx, query)
+	return stmt, err
+}
+
+// prepare prepares the statement on the replica or on the primary, depending
+// on the query. Argument onReplica reports whether the returned statement
+// runs on a replica.
+func (r *replicaRouter) prepare(ctx context.Context, query string) (stmt *sql.Stmt, onReplica bool, err error) {
+	if !r.useReplica(ctx, query) {
+		stmt, err = r.primary.PrepareContext(ctx, query)
+		return stmt, false, err
+	}
+	idx, err := r.tryReplicas(ctx, func(db *sql.DB) (err error) {
+		stmt, err = db.PrepareContext(ctx, query)
+		return err
+	})
+	return stmt, idx >= 0, err
+}
+
+func (r *replicaRouter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+func (r *replicaRouter) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	if !r.useReplica(ctx, query) {
+		return r.primary.QueryContext(ctx, query, args...)
+	}
+	_, err = r.tryReplicas(ctx, func(db *sql.DB) (err error) {
+		rows, err = db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext does not fail over because sql.Row defers the error until
+// Scan gets called.
+func (r *replicaRouter) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if !r.useReplica(ctx, query) {
+		return r.primary.QueryRowContext(ctx, query, args...)
+	}
+	db := r.replicas[r.pick()]
+	if gtid := r.gtidToWaitFor(ctx); gtid != "" {
+		if caughtUp, _ := r.waitForGTID(ctx, db, gtid); !caughtUp {
+			return r.primary.QueryRowContext(ctx, query, args...)
+		}
+	}
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+func (r *replicaRouter) useReplica(ctx context.Context, query string) bool {
+	return !FromContextQueryOptions(ctx).ForcePrimary && isReadQuery(query)
+}
+
+// tryReplicas runs fn with the selected replica and in case of a connection
+// error with the following replicas and finally with the primary. It returns
+// the index of the last used replica or -1 for the primary. A replica which
+// has not applied the GTID set of QueryOptions.WaitForGTID in time falls back
+// to the primary.
+func (r *replicaRouter) tryReplicas(ctx context.Context, fn func(*sql.DB) error) (int, error) {
+	gtid := r.gtidToWaitFor(ctx)
+	start := r.pick()
+	for i := range r.replicas {
+		idx := (start + i) % len(r.replicas)
+		var err error
+		if gtid != "" {
+			var caughtUp bool
+			if caughtUp, err = r.waitForGTID(ctx, r.replicas[idx], gtid); err == nil && !caughtUp {
+				break
+			}
+		}
+		if err == nil {
+			err = fn(r.replicas[idx])
+		}
+		if err == nil || ctx.Err() != nil || !isConnectionError(err) {
+			return idx, err
+		}
+		next := -1
+		if i+1 < len(r.replicas) {
+			next = (idx + 1) % len(r.replicas)
+		}
+		r.failover(ReplicaFailover{Replica: idx, Next: next, Err: err})
+	}
+	return -1, fn(r.primary)
+}
+
+func (r *replicaRouter) pick() int {
+	if r.strategy == ReplicaLeastConnections {
+		idx, least := 0, math.MaxInt32
+		for i, db := range r.replicas {
+			if inUse := db.Stats().InUse; inUse < least {
+				idx, least = i, inUse
+			}
+		}
+		return idx
+	}
+	return int((atomic.AddUint32(&r.next, 1) - 1) % uint32(len(r.replicas)))
+}
+
+func (r *replicaRouter) failover(rf ReplicaFailover) {
+	atomic.AddUint64(&r.counters.eventsFired, 1)
+	atomic.AddUint64(&r.counters.retries, 1)
+	if r.log != nil && r.log.IsInfo() {
+		r.log.Info("ReplicaFailover", log.Int("replica", rf.Replica), log.Int("next", rf.Next), log.Err(rf.Err))
+	}
+	if r.onFailover != nil {
+		r.onFailover(rf)
+	}
+}
+
+// isConnectionError reports whether err indicates an unreachable or broken
+// server, in contrast to an error caused by the query.
+func isConnectionError(err error) bool {
+	switch err := errors.Cause(err).(type) {
+	case *mysql.MySQLError:
+		switch err.Number {
+		// too many connections, server shutdown in progress, host blocked,
+		// aborted connection, connection killed, can't connect, server has
+		// gone away and lost connection
+		case 1040, 1053, 1129, 1152, 1927, 2002, 2003, 2006, 2013:
+			return true
+		}
+	case net.Error:
+		return true
+	default:
+		return err == driver.ErrBadConn || err == mysql.ErrInvalidConn
+	}
+	return false
+}
+
+// sessionFunctions return values bound to the connection or change its state,
+// hence a SELECT calling them must run on the primary.
+var sessionFunctions = [...]string{
+	"LAST_INSERT_ID(", "FOUND_ROWS(", "ROW_COUNT(", "GET_LOCK(", "RELEASE_LOCK(",
+	"RELEASE_ALL_LOCKS(", "IS_FREE_LOCK(", "IS_USED_LOCK(",
+}
+
+// isReadQuery reports whether a query can run on a replica. Leading comments,
+// like the unique query ID, get skipped. Locking reads, selects calling a
+// session function and common table expressions followed by a write must run
+// on the primary.
+func isReadQuery(query string) bool {
+	for {
+		query = strings.TrimLeft(query, " \t\r\n(")
+		if !strings.HasPrefix(query, "/*") {
+			break
+		}
+		idx := strings.Index(query, "*/")
+		if idx < 0 {
+			return false
+		}
+		query = query[idx+2:]
+	}
+	kw := leadingWord(query)
+	if strings.EqualFold(kw, "WITH") {
+		kw = cteStatementKeyword(query[len(kw):])
+	}
+	switch {
+	case strings.EqualFold(kw, "SELECT"):
+		if containsFold(query, " FOR UPDATE") || containsFold(query, " LOCK IN SHARE MODE") || containsFold(query, " FOR SHARE") {
+			return false
+		}
+		for _, fn := range sessionFunctions {
+			if containsFold(query, fn) {
+				return false
+			}
+		}
+		return true
+	case strings.EqualFold(kw, "SHOW"):
+		return true
+	}
+	return false
+}
+
+// leadingWord returns the letters at the beginning of s.
+func leadingWord(s string) string {
+	idx := strings.IndexFunc(s, func(r rune) bool { return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') })
+	if idx < 0 {
+		return s
+	}
+	return s[:idx]
+}
+
+// cteStatementKeyword returns the keyword of the statement following the
+// common table expressions of a WITH clause. Parentheses and quoted strings
+// or identifiers get skipped. Returns an empty string if no statement can be
+// found.
+func cteStatementKeyword(query string) string {
+	var depth int
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == '\'' || c == '"' || c == '`':
+			idx := strings.IndexByte(query[i+1:], c)
+			if idx < 0 {
+				return ""
+			}
+			i += idx + 1
+		case depth == 0 && ('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'):
+			w := leadingWord(query[i:])
+			switch strings.ToUpper(w) {
+			case "SELECT", "UPDATE", "DELETE", "INSERT", "REPLACE":
+				return w
+			}
+			i += len(w) - 1
+		}
+	}
+	return ""
+}
+
+// containsFold reports whether substr is within s, ignoring the case.
+func containsFold(s, substr string) bool {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return true
+		}
+	}
+	return false
+}
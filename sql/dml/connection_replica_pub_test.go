@@ -0,0 +1,165 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestConnPool_Replicas(t *testing.T) {
+	selectA := dml.NewSelect("a").From("tableA")
+	ctx := context.Background()
+	rowsA := func(v int) *sqlmock.Rows { return sqlmock.NewRows([]string{"a"}).AddRow(v) }
+
+	t.Run("round robin routing", func(t *testing.T) {
+		dbc, dbMock, replicas := dmltest.MockDBWithReplicas(t, 2)
+		defer dmltest.MockClose(t, dbc, dbMock, replicas...)
+
+		replicas[0].ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA`")).WillReturnRows(rowsA(1))
+		replicas[1].ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA`")).WillReturnRows(rowsA(2))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA` FOR UPDATE")).WillReturnRows(rowsA(3))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA` LOCK IN SHARE MODE")).WillReturnRows(rowsA(4))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `tableA` (`a`) VALUES (?)")).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA`")).WillReturnRows(rowsA(6))
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA`")).WillReturnRows(rowsA(7))
+		dbMock.ExpectCommit()
+
+		var got []int64
+		load := func(ctx context.Context, qb dml.QueryBuilder) {
+			v, _, err := dbc.WithQueryBuilder(qb).LoadNullInt64(ctx)
+			assert.NoError(t, err)
+			got = append(got, v.Int64)
+		}
+		load(ctx, selectA.Clone())
+		load(ctx, selectA.Clone())
+		load(ctx, selectA.Clone().ForUpdate())
+		load(ctx, selectA.Clone().LockInShareMode())
+
+		_, err := dbc.WithQueryBuilder(dml.NewInsert("tableA").AddColumns("a")).ExecContext(ctx, 5)
+		assert.NoError(t, err)
+
+		load(dml.WithContextQueryOptions(ctx, dml.QueryOptions{ForcePrimary: true}), selectA.Clone())
+
+		assert.NoError(t, dbc.Transaction(ctx, nil, func(tx *dml.Tx) error {
+			v, _, err := tx.WithQueryBuilder(selectA.Clone()).LoadNullInt64(ctx)
+			got = append(got, v.Int64)
+			return err
+		}))
+		assert.Exactly(t, []int64{1, 2, 3, 4, 6, 7}, got)
+	})
+
+	t.Run("failover to the next replica and the primary", func(t *testing.T) {
+		var events []dml.ReplicaFailover
+		dbc, dbMock, replicas := dmltest.MockDBWithReplicas(t, 2, dml.WithReplicaFailoverHandler(func(rf dml.ReplicaFailover) {
+			events = append(events, rf)
+		}))
+		defer dmltest.MockClose(t, dbc, dbMock, replicas...)
+
+		replicas[0].ExpectQuery("SELECT").WillReturnError(mysql.ErrInvalidConn)
+		replicas[1].ExpectQuery("SELECT").WillReturnError(&mysql.MySQLError{Number: 2013, Message: "Lost connection"})
+		dbMock.ExpectQuery("SELECT").WillReturnRows(rowsA(8))
+
+		v, _, err := dbc.WithQueryBuilder(selectA.Clone()).LoadNullInt64(ctx)
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(8), v.Int64)
+		assert.Exactly(t, []dml.ReplicaFailover{
+			{Replica: 0, Next: 1, Err: mysql.ErrInvalidConn},
+			{Replica: 1, Next: -1, Err: &mysql.MySQLError{Number: 2013, Message: "Lost connection"}},
+		}, events)
+		ps := dbc.Stats()
+		assert.Exactly(t, uint64(2), ps.EventsFired)
+		assert.Exactly(t, uint64(2), ps.Retries)
+		assert.Len(t, ps.Replicas, 2)
+	})
+
+	t.Run("query error does not fail over", func(t *testing.T) {
+		dbc, dbMock, replicas := dmltest.MockDBWithReplicas(t, 2)
+		defer dmltest.MockClose(t, dbc, dbMock, replicas...)
+
+		replicas[0].ExpectQuery("SELECT").WillReturnError(&mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"})
+
+		_, _, err := dbc.WithQueryBuilder(selectA.Clone()).LoadNullInt64(ctx)
+		assert.Error(t, err)
+		assert.Exactly(t, uint16(1146), dml.MySQLNumberFromError(err))
+	})
+
+	t.Run("least connections", func(t *testing.T) {
+		dbc, dbMock, replicas := dmltest.MockDBWithReplicas(t, 2, dml.WithReplicaStrategy(dml.ReplicaLeastConnections))
+		defer dmltest.MockClose(t, dbc, dbMock, replicas...)
+
+		replicas[0].ExpectQuery("SELECT").WillReturnRows(rowsA(1))
+		replicas[1].ExpectQuery("SELECT").WillReturnRows(rowsA(2))
+		replicas[1].ExpectQuery("SELECT").WillReturnRows(rowsA(3))
+
+		rows, err := dbc.WithQueryBuilder(selectA.Clone()).QueryContext(ctx) // keeps the connection of replica 0 busy
+		assert.NoError(t, err)
+		for i := int64(2); i <= 3; i++ {
+			v, _, err := dbc.WithQueryBuilder(selectA.Clone()).LoadNullInt64(ctx)
+			assert.NoError(t, err)
+			assert.Exactly(t, i, v.Int64)
+		}
+		assert.NoError(t, rows.Close())
+	})
+
+	t.Run("prepared statements run on their connection", func(t *testing.T) {
+		dbc, dbMock, replicas := dmltest.MockDBWithReplicas(t, 1)
+		defer dmltest.MockClose(t, dbc, dbMock, replicas...)
+
+		replicas[0].ExpectPrepare(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA` WHERE (`a` = ?)")).
+			ExpectQuery().WithArgs(1).WillReturnRows(rowsA(1))
+		dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("UPDATE `tableA` SET `a`=?")).
+			ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectBegin()
+		dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA` WHERE (`a` = ?)")).
+			ExpectQuery().WithArgs(3).WillReturnRows(rowsA(3))
+		dbMock.ExpectCommit()
+
+		sel := dbc.WithPrepare(ctx, selectA.Clone().Where(dml.Column("a").PlaceHolder()))
+		v, _, err := sel.LoadNullInt64(ctx, 1)
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(1), v.Int64)
+
+		_, err = dbc.WithPrepare(ctx, dml.NewUpdate("tableA").AddColumns("a")).ExecContext(ctx, 2)
+		assert.NoError(t, err)
+
+		assert.NoError(t, dbc.Transaction(ctx, nil, func(tx *dml.Tx) error {
+			v, _, err := sel.WithTx(tx).LoadNullInt64(ctx, 3)
+			assert.Exactly(t, int64(3), v.Int64)
+			return errors.WithStack(err)
+		}))
+	})
+
+	t.Run("close continues after a replica error", func(t *testing.T) {
+		dbc, dbMock, replicas := dmltest.MockDBWithReplicas(t, 2)
+
+		replicas[0].ExpectClose().WillReturnError(errors.ConnectionFailed.Newf("replica 0 gone"))
+		replicas[1].ExpectClose()
+		dbMock.ExpectClose()
+
+		err := dbc.Close()
+		assert.True(t, errors.ConnectionFailed.Match(err), "%+v", err)
+		assert.NoError(t, replicas[1].ExpectationsWereMet())
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
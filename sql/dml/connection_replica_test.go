@@ -0,0 +1,66 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"database/sql/driver"
+	"net"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestIsReadQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT 1", true},
+		{"  select * from a", true},
+		{"/*$ID$abc*/SELECT `a` FROM `b`", true},
+		{"/* x */ /* y */(SELECT a FROM b) UNION (SELECT a FROM c)", true},
+		{"WITH cte AS (SELECT 1) SELECT * FROM cte", true},
+		{"WITH RECURSIVE `select` (n) AS (SELECT 1 UNION ALL SELECT n+1 FROM `select` WHERE n < 5), b AS (SELECT 'update') SELECT * FROM `select`", true},
+		{"WITH cte AS (SELECT id FROM a) UPDATE b JOIN cte USING (id) SET c=1", false},
+		{"with cte AS (SELECT id FROM a) delete b FROM b JOIN cte USING (id)", false},
+		{"WITH cte AS (SELECT 1", false},
+		{"SHOW TABLES", true},
+		{"SELECT LAST_INSERT_ID()", false},
+		{"SELECT found_rows()", false},
+		{"SELECT GET_LOCK('key', 10)", false},
+		{"SELECT a FROM b FOR UPDATE", false},
+		{"SELECT a FROM b for share", false},
+		{"SELECT a FROM b LOCK IN SHARE MODE", false},
+		{"SELECTED", false},
+		{"INSERT INTO a SELECT * FROM b", false},
+		{"/*$ID$abc*/UPDATE a SET b=1", false},
+		{"/* unterminated SELECT", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		assert.Exactly(t, test.want, isReadQuery(test.query), "%q", test.query)
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, isConnectionError(driver.ErrBadConn))
+	assert.True(t, isConnectionError(mysql.ErrInvalidConn))
+	assert.True(t, isConnectionError(&net.DNSError{Err: "no such host", IsNotFound: true}))
+	assert.True(t, isConnectionError(&mysql.MySQLError{Number: 1040}))
+	assert.False(t, isConnectionError(&mysql.MySQLError{Number: 1064}))
+	assert.False(t, isConnectionError(errors.New("syntax error")))
+}
@@ -0,0 +1,160 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// slowQueryQueueSize defines the number of slow query events which can wait
+// for the handler. Further events get dropped.
+const slowQueryQueueSize = 256
+
+// SlowQueryInfo describes a query which took longer than the threshold set
+// with WithSlowQueryThreshold.
+type SlowQueryInfo struct {
+	CacheKey string
+	// QueryID is the unique ID of the query, see WithLogger.
+	QueryID string
+	// SQL contains the query with its placeholders, never the interpolated
+	// arguments.
+	SQL string
+	// Args contains the arguments passed to the query, only if enabled with
+	// WithSlowQueryIncludeArgs. Can contain sensitive data. With
+	// WithArgumentRedactor the arguments get redacted and expanded in the
+	// order of the placeholders.
+	Args []interface{}
+	// Table is the name of the table of the SELECT, INSERT, UPDATE or DELETE
+	// builder. Empty for raw SQL queries.
+	Table    string
+	Duration time.Duration
+	// RowsAffected by an Exec call, -1 if unknown.
+	RowsAffected int64
+	// RowsReturned by a Load call, -1 if unknown.
+	RowsReturned int64
+	Err          error
+}
+
+// WithSlowQueryThreshold calls the handler for each query whose duration
+// exceeds d, a zero duration reports all queries. The duration of Load and
+// Iterate functions includes the time for iterating over the rows. The
+// handler runs asynchronously in its own goroutine. If the handler cannot keep
+// up, the events get dropped and counted in PoolStats.SlowQueriesDropped.
+// Only queries created via ConnPool, Conn or Tx get measured.
+func WithSlowQueryThreshold(d time.Duration, handler func(SlowQueryInfo)) ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			sq := c.slowQueryMonitor()
+			sq.threshold = d
+			sq.handler = handler
+			return nil
+		},
+	}
+}
+
+// WithSlowQueryIncludeArgs adds the arguments of a query to the SlowQueryInfo.
+// The arguments might contain sensitive data, see WithArgumentRedactor.
+func WithSlowQueryIncludeArgs() ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			c.slowQueryMonitor().includeArgs = true
+			return nil
+		},
+	}
+}
+
+func (c *ConnPool) slowQueryMonitor() *slowQueryMonitor {
+	if c.queryCache.slowQuery == nil {
+		c.queryCache.slowQuery = &slowQueryMonitor{counters: &c.queryCache.counters}
+	}
+	return c.queryCache.slowQuery
+}
+
+// slowQueryMonitor forwards the slow queries via a bounded queue to the
+// handler.
+type slowQueryMonitor struct {
+	threshold   time.Duration
+	includeArgs bool
+	handler     func(SlowQueryInfo)
+	counters    *poolCounters
+
+	mu     sync.RWMutex // protects closed and the queue against sending after close
+	closed bool
+	queue  chan SlowQueryInfo
+	done   chan struct{}
+}
+
+func (sq *slowQueryMonitor) start() {
+	sq.queue = make(chan SlowQueryInfo, slowQueryQueueSize)
+	sq.done = make(chan struct{})
+	go func() {
+		defer close(sq.done)
+		for sqi := range sq.queue {
+			atomic.AddUint64(&sq.counters.eventsFired, 1)
+			sq.handler(sqi)
+		}
+	}()
+}
+
+// close stops accepting new events and waits until the handler has processed
+// the queue.
+func (sq *slowQueryMonitor) close() {
+	sq.mu.Lock()
+	if sq.closed {
+		sq.mu.Unlock()
+		return
+	}
+	sq.closed = true
+	close(sq.queue)
+	sq.mu.Unlock()
+	<-sq.done
+}
+
+// observe sends the query to the handler if it exceeds the threshold.
+func (sq *slowQueryMonitor) observe(a *DBR, start time.Time, args []interface{}, rowsAffected, rowsReturned int64, err error) {
+	d := time.Since(start)
+	if d < sq.threshold {
+		return
+	}
+	sqi := SlowQueryInfo{
+		CacheKey:     a.customCacheKey,
+		QueryID:      a.cachedSQL.id,
+		SQL:          a.cachedSQL.rawSQL,
+		Table:        a.cachedSQL.table,
+		Duration:     d,
+		RowsAffected: rowsAffected,
+		RowsReturned: rowsReturned,
+		Err:          err,
+	}
+	if a.cachedSQL.insertCachedSQL != "" {
+		sqi.SQL = a.cachedSQL.insertCachedSQL
+	}
+	if sq.includeArgs && len(args) > 0 {
+		sqi.Args = a.redactArgs(args)
+	}
+
+	sq.mu.RLock()
+	defer sq.mu.RUnlock()
+	if sq.closed {
+		return
+	}
+	select {
+	case sq.queue <- sqi:
+	default:
+		atomic.AddUint64(&sq.counters.slowQueriesDropped, 1)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type slowQueryCollector struct {
+	mu    sync.Mutex
+	infos []dml.SlowQueryInfo
+}
+
+func (sqc *slowQueryCollector) handle(sqi dml.SlowQueryInfo) {
+	sqc.mu.Lock()
+	defer sqc.mu.Unlock()
+	sqi.Duration = 0 // not testable
+	sqc.infos = append(sqc.infos, sqi)
+}
+
+// slowMapper sleeps while mapping a row to simulate a slow iteration.
+type slowMapper struct{ d time.Duration }
+
+func (sm slowMapper) MapColumns(cm *dml.ColumnMap) error {
+	time.Sleep(sm.d)
+	return nil
+}
+
+func TestWithSlowQueryThreshold(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("exec and load", func(t *testing.T) {
+		var sqc slowQueryCollector
+		dbc, dbMock := dmltest.MockDB(t, dml.WithSlowQueryThreshold(0, sqc.handle))
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `tableA` (`a`,`b`) VALUES (?,?)")).
+			WithArgs(1, "secret").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `a` FROM `tableA` WHERE (`b` = 'secret')")).
+			WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1).AddRow(2))
+		dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("DELETE FROM `tableA` WHERE (`a` = ?)")).
+			ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 3))
+
+		_, err := dbc.WithQueryBuilder(dml.NewInsert("tableA").AddColumns("a", "b")).ExecContext(ctx, 1, "secret")
+		assert.NoError(t, err)
+		_, err = dbc.WithQueryBuilder(dml.NewSelect("a").From("tableA").Where(dml.Column("b").PlaceHolder())).
+			Interpolate().Load(ctx, slowMapper{}, "secret")
+		assert.NoError(t, err)
+		_, err = dbc.WithPrepare(ctx, dml.NewDelete("tableA").Where(dml.Column("a").PlaceHolder())).ExecContext(ctx, 2)
+		assert.NoError(t, err)
+
+		dmltest.MockClose(t, dbc, dbMock) // waits for the handler
+		assert.Len(t, sqc.infos, 3)
+		assert.Exactly(t, dml.SlowQueryInfo{
+			CacheKey: sqc.infos[0].CacheKey, SQL: "INSERT INTO `tableA` (`a`,`b`) VALUES (?,?)", Table: "tableA",
+			RowsAffected: 1, RowsReturned: -1,
+		}, sqc.infos[0])
+		assert.Exactly(t, dml.SlowQueryInfo{
+			CacheKey: sqc.infos[1].CacheKey, SQL: "SELECT `a` FROM `tableA` WHERE (`b` = ?)", Table: "tableA",
+			RowsAffected: -1, RowsReturned: 2,
+		}, sqc.infos[1])
+		assert.Exactly(t, dml.SlowQueryInfo{
+			CacheKey: sqc.infos[2].CacheKey, SQL: "DELETE FROM `tableA` WHERE (`a` = ?)", Table: "tableA",
+			RowsAffected: 3, RowsReturned: -1,
+		}, sqc.infos[2])
+		assert.NotEmpty(t, sqc.infos[0].CacheKey)
+	})
+
+	t.Run("include args", func(t *testing.T) {
+		var sqc slowQueryCollector
+		dbc, dbMock := dmltest.MockDB(t, dml.WithSlowQueryIncludeArgs(), dml.WithSlowQueryThreshold(0, sqc.handle))
+		dbMock.ExpectExec("UPDATE").WithArgs("secret").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := dbc.WithQueryBuilder(dml.NewUpdate("tableA").AddColumns("b")).ExecContext(ctx, "secret")
+		assert.NoError(t, err)
+
+		dmltest.MockClose(t, dbc, dbMock)
+		assert.Len(t, sqc.infos, 1)
+		assert.Exactly(t, []interface{}{"secret"}, sqc.infos[0].Args)
+	})
+
+	t.Run("threshold includes the iteration", func(t *testing.T) {
+		var sqc slowQueryCollector
+		dbc, dbMock := dmltest.MockDB(t, dml.WithSlowQueryThreshold(20*time.Millisecond, sqc.handle))
+		dbMock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+		dbMock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1).AddRow(2))
+
+		_, err := dbc.WithQueryBuilder(dml.QuerySQL("SELECT 1")).Load(ctx, slowMapper{})
+		assert.NoError(t, err)
+		_, err = dbc.WithQueryBuilder(dml.QuerySQL("SELECT 2")).Load(ctx, slowMapper{d: 15 * time.Millisecond})
+		assert.NoError(t, err)
+
+		dmltest.MockClose(t, dbc, dbMock)
+		assert.Len(t, sqc.infos, 1)
+		assert.Exactly(t, "SELECT 2", sqc.infos[0].SQL)
+		assert.Exactly(t, int64(2), sqc.infos[0].RowsReturned)
+	})
+
+	t.Run("slow handler drops events", func(t *testing.T) {
+		release := make(chan struct{})
+		var handled int
+		dbc, dbMock := dmltest.MockDB(t, dml.WithSlowQueryThreshold(0, func(dml.SlowQueryInfo) {
+			<-release
+			handled++
+		}))
+
+		const queries = 300
+		for i := 0; i < queries; i++ {
+			dbMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		del := dbc.WithQueryBuilder(dml.NewDelete("tableA"))
+		for i := 0; i < queries; i++ {
+			_, err := del.ExecContext(ctx)
+			assert.NoError(t, err)
+		}
+		ps := dbc.Stats()
+		close(release)
+		dmltest.MockClose(t, dbc, dbMock)
+
+		assert.True(t, ps.SlowQueriesDropped > 0, "SlowQueriesDropped: %d", ps.SlowQueriesDropped)
+		assert.Exactly(t, queries, handled+int(ps.SlowQueriesDropped))
+		// queued events count once the handler receives them
+		assert.True(t, ps.EventsFired <= 1, "EventsFired: %d", ps.EventsFired)
+		assert.Exactly(t, uint64(handled), dbc.Stats().EventsFired)
+	})
+}
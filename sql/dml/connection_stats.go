@@ -0,0 +1,148 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// DefaultHealthCheckTimeout bounds the duration of HealthCheck, if not
+// configured otherwise with WithHealthCheck.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// poolCounters gets shared between a ConnPool and its Conn and Tx types. All
+// fields must be accessed atomically.
+type poolCounters struct {
+	preparedStatements uint64
+	eventsFired        uint64
+	retries            uint64
+	slowQueriesDropped uint64
+	// openStatements is a gauge of the statements tracked by the ConnPool.
+	openStatements       uint64
+	idleStatementsClosed uint64
+	statementsReprepared uint64
+}
+
+// PoolStats contains the statistics of the underlying sql.DB and the counters
+// of the dml package. PoolStats implements expvar.Var, so the stats can be
+// published with:
+//
+//	expvar.Publish("dml_pool", expvar.Func(func() interface{} { return db.Stats() }))
+type PoolStats struct {
+	sql.DBStats
+	// PreparedStatements counts all statements prepared via the ConnPool and
+	// its derived Conn and Tx types.
+	PreparedStatements uint64
+	// CachedSQL contains the number of SQL strings in the query cache.
+	CachedSQL int
+	// EventsFired counts the events triggered by the ConnPool.
+	EventsFired uint64
+	// Retries counts the retried operations, e.g. pings of
+	// WithVerifyConnection.
+	Retries uint64
+	// SlowQueriesDropped counts the slow query events which got dropped
+	// because the handler could not keep up, see WithSlowQueryThreshold.
+	SlowQueriesDropped uint64
+	// OpenStatements contains the number of open statements prepared on the
+	// ConnPool, see CloseIdleStatements.
+	OpenStatements uint64
+	// IdleStatementsClosed counts the statements closed because they were
+	// idle.
+	IdleStatementsClosed uint64
+	// StatementsReprepared counts the statements which have been prepared
+	// again after being closed because they were idle.
+	StatementsReprepared uint64
+	// Replicas contains the stats of each read replica, see
+	// NewConnPoolWithReplicas.
+	Replicas []sql.DBStats `json:",omitempty"`
+}
+
+// String returns the stats as JSON.
+func (ps PoolStats) String() string {
+	data, _ := json.Marshal(ps) // cannot fail
+	return string(data)
+}
+
+// Stats returns the statistics of the connection pool. It is safe for
+// concurrent use.
+func (c *ConnPool) Stats() PoolStats {
+	ps := PoolStats{
+		PreparedStatements: atomic.LoadUint64(&c.queryCache.counters.preparedStatements),
+		EventsFired:        atomic.LoadUint64(&c.queryCache.counters.eventsFired),
+		Retries:            atomic.LoadUint64(&c.queryCache.counters.retries),
+		SlowQueriesDropped: atomic.LoadUint64(&c.queryCache.counters.slowQueriesDropped),
+
+		OpenStatements:       atomic.LoadUint64(&c.queryCache.counters.openStatements),
+		IdleStatementsClosed: atomic.LoadUint64(&c.queryCache.counters.idleStatementsClosed),
+		StatementsReprepared: atomic.LoadUint64(&c.queryCache.counters.statementsReprepared),
+	}
+	if c.DB != nil {
+		ps.DBStats = c.DB.Stats()
+	}
+	for _, db := range c.replicas {
+		ps.Replicas = append(ps.Replicas, db.Stats())
+	}
+	c.queryCache.mu.RLock()
+	ps.CachedSQL = len(c.queryCache.queries)
+	c.queryCache.mu.RUnlock()
+	return ps
+}
+
+// WithHealthCheck configures HealthCheck. The timeout bounds the duration of
+// a health check, if zero DefaultHealthCheckTimeout applies. If withQuery is
+// true, HealthCheck runs additionally `SELECT 1` through the normal query path
+// so that logging and the driver call backs get exercised too.
+func WithHealthCheck(timeout time.Duration, withQuery bool) ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			c.healthCheckTimeout = timeout
+			c.healthCheckQuery = withQuery
+			return nil
+		},
+	}
+}
+
+// HealthCheck pings the database within a bounded time and, if enabled with
+// WithHealthCheck, runs a `SELECT 1` query. Suitable for readiness probes. A
+// failing check returns an error of kind ConnectionFailed.
+func (c *ConnPool) HealthCheck(ctx context.Context) error {
+	timeout := c.healthCheckTimeout
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := c.DB.PingContext(ctx); err != nil {
+		return errors.ConnectionFailed.New(err, "[dml] HealthCheck ping failed")
+	}
+	if !c.healthCheckQuery {
+		return nil
+	}
+	v, found, err := c.WithQueryBuilder(QuerySQL("SELECT 1")).LoadNullInt64(ctx)
+	switch {
+	case err != nil:
+		return errors.ConnectionFailed.New(err, "[dml] HealthCheck query failed")
+	case !found || v.Int64 != 1:
+		return errors.ConnectionFailed.Newf("[dml] HealthCheck query returned an unexpected result: %d (found %t)", v.Int64, found)
+	}
+	return nil
+}
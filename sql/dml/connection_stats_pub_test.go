@@ -0,0 +1,90 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestConnPool_Stats(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t, dml.WithMaxOpenConns(7), dml.WithMaxIdleConns(3),
+		dml.WithConnMaxLifetime(time.Minute), dml.WithConnMaxIdleTime(time.Second))
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	dbMock.ExpectPrepare("DROP TABLE \\?").ExpectExec().WithArgs("tabA").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec("DROP TABLE tabB").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := dbc.WithPrepare(context.TODO(), dml.QuerySQL("DROP TABLE ?")).ExecContext(context.TODO(), "tabA")
+	assert.NoError(t, err)
+	_, err = dbc.WithQueryBuilder(dml.QuerySQL("DROP TABLE tabB")).ExecContext(context.TODO())
+	assert.NoError(t, err)
+
+	ps := dbc.Stats()
+	assert.Exactly(t, 7, ps.MaxOpenConnections)
+	assert.Exactly(t, uint64(1), ps.PreparedStatements)
+	assert.Exactly(t, 2, ps.CachedSQL)
+	assert.Exactly(t, uint64(0), ps.Retries)
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(ps.String()), &decoded))
+	assert.Exactly(t, float64(7), decoded["MaxOpenConnections"])
+	assert.Exactly(t, float64(1), decoded["PreparedStatements"])
+}
+
+func TestWithMaxOpenConns_WithoutDB(t *testing.T) {
+	_, err := dml.NewConnPool(dml.WithMaxOpenConns(2))
+	assert.True(t, errors.NotValid.Match(err), "%+v", err)
+}
+
+func TestConnPool_HealthCheck(t *testing.T) {
+	t.Run("ping only", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		assert.NoError(t, dbc.HealthCheck(context.TODO()))
+	})
+
+	t.Run("with query", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithHealthCheck(time.Second, true))
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT 1")).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+		assert.NoError(t, dbc.HealthCheck(context.TODO()))
+	})
+
+	t.Run("query fails", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithHealthCheck(time.Second, true))
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT 1")).WillReturnError(errors.New("server has gone away"))
+		err := dbc.HealthCheck(context.TODO())
+		assert.True(t, errors.ConnectionFailed.Match(err), "%+v", err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithHealthCheck(10*time.Millisecond, true))
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT 1")).WillDelayFor(time.Second).
+			WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+		err := dbc.HealthCheck(context.TODO())
+		assert.True(t, errors.ConnectionFailed.Match(err), "%+v", err)
+	})
+}
@@ -0,0 +1,299 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+)
+
+// PreparedStmtStats contains the usage of a prepared statement, see
+// ConnPool.PreparedStmtStats.
+type PreparedStmtStats struct {
+	SQL        string
+	Executions uint64
+	LastUsed   time.Time
+	// Open is false if the statement has been closed because it was idle. It
+	// gets prepared again with the next execution.
+	Open bool
+}
+
+// preparedStmt tracks the usage of a statement prepared on a ConnPool. A
+// statement closed by CloseIdleStatements gets prepared again with the next
+// execution.
+type preparedStmt struct {
+	// lastUsed and executions must be the first fields to guarantee the
+	// 64-bit alignment of the atomic counters on 32-bit systems.
+	lastUsed   int64 // unix nano
+	executions uint64
+	qc         *queryCache
+	db         QueryExecPreparer
+	rawSQL     string
+
+	mu sync.RWMutex
+	// stmt is nil if the statement has been closed because it was idle.
+	stmt *sql.Stmt
+	// onReplica is true if stmt has been prepared on a read replica.
+	onReplica bool
+	// closed gets set by Close, the statement cannot be used anymore.
+	closed bool
+}
+
+// stmtRegistry contains all tracked statements of a connection pool.
+type stmtRegistry struct {
+	mu    sync.Mutex
+	stmts map[*preparedStmt]struct{}
+}
+
+// track wraps a statement prepared on the pool or on the replicas. Statements
+// of a Conn or a Tx are bound to a session and are not tracked.
+func (qc *queryCache) track(db QueryExecPreparer, rawSQL string, stmt *sql.Stmt, onReplica bool) interface {
+	ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, args ...interface{}) *sql.Row
+	ioCloser
+} {
+	switch db.(type) {
+	case *sql.DB, *replicaRouter:
+	default:
+		return stmt
+	}
+	ps := &preparedStmt{
+		lastUsed:  now().UnixNano(),
+		qc:        qc,
+		db:        db,
+		rawSQL:    rawSQL,
+		stmt:      stmt,
+		onReplica: onReplica,
+	}
+	qc.stmts.mu.Lock()
+	if qc.stmts.stmts == nil {
+		qc.stmts.stmts = make(map[*preparedStmt]struct{})
+	}
+	qc.stmts.stmts[ps] = struct{}{}
+	qc.stmts.mu.Unlock()
+	atomic.AddUint64(&qc.counters.openStatements, 1)
+	return ps
+}
+
+// use runs fn with an open statement and prepares the statement again, if it
+// has been closed because it was idle. CloseIdleStatements waits until fn
+// returns.
+func (ps *preparedStmt) use(ctx context.Context, fn func(*sql.Stmt)) error {
+	atomic.StoreInt64(&ps.lastUsed, now().UnixNano())
+	atomic.AddUint64(&ps.executions, 1)
+	ps.mu.RLock()
+	for ps.stmt == nil {
+		ps.mu.RUnlock()
+		if err := ps.reprepare(ctx); err != nil {
+			return errors.WithStack(err)
+		}
+		ps.mu.RLock()
+	}
+	defer ps.mu.RUnlock()
+	fn(ps.stmt)
+	return nil
+}
+
+func (ps *preparedStmt) reprepare(ctx context.Context) (err error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	switch {
+	case ps.closed:
+		return errors.AlreadyClosed.Newf("[dml] Prepared statement has been closed: %q", ps.rawSQL)
+	case ps.stmt != nil:
+		return nil
+	}
+	if r, ok := ps.db.(*replicaRouter); ok {
+		ps.stmt, ps.onReplica, err = r.prepare(ctx, ps.rawSQL)
+	} else {
+		ps.stmt, err = ps.db.PrepareContext(ctx, ps.rawSQL)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "[dml] Failed to prepare again the statement %q", ps.rawSQL)
+	}
+	atomic.AddUint64(&ps.qc.counters.preparedStatements, 1)
+	atomic.AddUint64(&ps.qc.counters.statementsReprepared, 1)
+	atomic.AddUint64(&ps.qc.counters.openStatements, 1)
+	return nil
+}
+
+func (ps *preparedStmt) ExecContext(ctx context.Context, args ...interface{}) (res sql.Result, err error) {
+	if errU := ps.use(ctx, func(s *sql.Stmt) { res, err = s.ExecContext(ctx, args...) }); errU != nil {
+		return nil, errU
+	}
+	return res, err
+}
+
+// QueryContext returns rows which stay valid if the statement gets closed
+// while iterating, because database/sql closes the statement after the rows.
+func (ps *preparedStmt) QueryContext(ctx context.Context, args ...interface{}) (rows *sql.Rows, err error) {
+	if errU := ps.use(ctx, func(s *sql.Stmt) { rows, err = s.QueryContext(ctx, args...) }); errU != nil {
+		return nil, errU
+	}
+	return rows, err
+}
+
+// QueryRowContext falls back to a non-prepared query if the statement cannot
+// be prepared again, because sql.Row cannot carry that error.
+func (ps *preparedStmt) QueryRowContext(ctx context.Context, args ...interface{}) (row *sql.Row) {
+	if err := ps.use(ctx, func(s *sql.Stmt) { row = s.QueryRowContext(ctx, args...) }); err != nil {
+		return ps.db.QueryRowContext(ctx, ps.rawSQL, args...)
+	}
+	return row
+}
+
+// closeIdle closes the statement if it has not been used since idleSince.
+func (ps *preparedStmt) closeIdle(idleSince int64) (bool, error) {
+	if atomic.LoadInt64(&ps.lastUsed) > idleSince {
+		return false, nil
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.stmt == nil || atomic.LoadInt64(&ps.lastUsed) > idleSince {
+		return false, nil
+	}
+	err := ps.stmt.Close()
+	ps.stmt = nil
+	atomic.AddUint64(&ps.qc.counters.openStatements, ^uint64(0))
+	atomic.AddUint64(&ps.qc.counters.idleStatementsClosed, 1)
+	return true, errors.WithStack(err)
+}
+
+// Close closes the statement and stops tracking it.
+func (ps *preparedStmt) Close() (err error) {
+	ps.qc.stmts.mu.Lock()
+	delete(ps.qc.stmts.stmts, ps)
+	ps.qc.stmts.mu.Unlock()
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.closed {
+		return nil
+	}
+	ps.closed = true
+	if ps.stmt != nil {
+		err = ps.stmt.Close()
+		ps.stmt = nil
+		atomic.AddUint64(&ps.qc.counters.openStatements, ^uint64(0))
+	}
+	return err
+}
+
+// current returns the open statement and prepares it again if needed. Used to
+// bind the statement to a transaction.
+func (ps *preparedStmt) current(ctx context.Context) (stmt *sql.Stmt, onReplica bool, err error) {
+	err = ps.use(ctx, func(s *sql.Stmt) { stmt, onReplica = s, ps.onReplica })
+	return stmt, onReplica, err
+}
+
+func (qc *queryCache) trackedStmts() []*preparedStmt {
+	qc.stmts.mu.Lock()
+	defer qc.stmts.mu.Unlock()
+	stmts := make([]*preparedStmt, 0, len(qc.stmts.stmts))
+	for ps := range qc.stmts.stmts {
+		stmts = append(stmts, ps)
+	}
+	return stmts
+}
+
+// CloseIdleStatements closes the prepared statements of the ConnPool which
+// have not been used within olderThan. A closed statement gets prepared again
+// with its next execution, also if a concurrent goroutine uses it right now.
+// Statements of a Conn or a Tx are not affected.
+func (c *ConnPool) CloseIdleStatements(olderThan time.Duration) (closed int) {
+	idleSince := now().Add(-olderThan).UnixNano()
+	for _, ps := range c.queryCache.trackedStmts() {
+		ok, err := ps.closeIdle(idleSince)
+		if ok {
+			closed++
+		}
+		if err != nil && c.Log != nil && c.Log.IsInfo() {
+			c.Log.Info("dml.ConnPool.CloseIdleStatements", log.Err(err), log.String("sql", ps.rawSQL))
+		}
+	}
+	return closed
+}
+
+// PreparedStmtStats returns the usage of the prepared statements of the
+// ConnPool.
+func (c *ConnPool) PreparedStmtStats() []PreparedStmtStats {
+	stmts := c.queryCache.trackedStmts()
+	ret := make([]PreparedStmtStats, 0, len(stmts))
+	for _, ps := range stmts {
+		ps.mu.RLock()
+		isOpen := ps.stmt != nil
+		ps.mu.RUnlock()
+		ret = append(ret, PreparedStmtStats{
+			SQL:        ps.rawSQL,
+			Executions: atomic.LoadUint64(&ps.executions),
+			LastUsed:   time.Unix(0, atomic.LoadInt64(&ps.lastUsed)),
+			Open:       isOpen,
+		})
+	}
+	return ret
+}
+
+// stmtReaper closes the idle statements in an interval, see WithStmtReaper.
+type stmtReaper struct {
+	interval time.Duration
+	maxIdle  time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// WithStmtReaper closes the prepared statements which have been idle for
+// maxIdle in the interval, to stay below the max_prepared_stmt_count of the
+// server. The reaper stops with Close. See CloseIdleStatements.
+func WithStmtReaper(interval, maxIdle time.Duration) ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			if interval <= 0 {
+				return errors.NotValid.Newf("[dml] WithStmtReaper: interval must be greater than zero, got %s", interval)
+			}
+			c.stmtReaper = &stmtReaper{interval: interval, maxIdle: maxIdle}
+			return nil
+		},
+	}
+}
+
+func (sr *stmtReaper) start(c *ConnPool) {
+	sr.stop = make(chan struct{})
+	sr.done = make(chan struct{})
+	go func() {
+		defer close(sr.done)
+		t := time.NewTicker(sr.interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				c.CloseIdleStatements(sr.maxIdle)
+			case <-sr.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (sr *stmtReaper) close() {
+	close(sr.stop)
+	<-sr.done
+}
@@ -0,0 +1,103 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestConnPool_CloseIdleStatements(t *testing.T) {
+	ctx := context.Background()
+	const deleteSQL = "DELETE FROM `tableA` WHERE (`a` = ?)"
+
+	t.Run("re-prepare after idle close", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectPrepare("^" + dmltest.SQLMockQuoteMeta(deleteSQL)).WillBeClosed().
+			ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectPrepare("^" + dmltest.SQLMockQuoteMeta(deleteSQL)).
+			ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		dbr := dbc.WithPrepare(ctx, dml.NewDelete("tableA").Where(dml.Column("a").PlaceHolder()))
+		_, err := dbr.ExecContext(ctx, 1)
+		assert.NoError(t, err)
+
+		assert.Exactly(t, 0, dbc.CloseIdleStatements(time.Hour), "statement is not idle")
+		assert.Exactly(t, 1, dbc.CloseIdleStatements(0))
+		assert.Exactly(t, 0, dbc.CloseIdleStatements(0), "statement already closed")
+
+		stats := dbc.PreparedStmtStats()
+		assert.Len(t, stats, 1)
+		assert.Exactly(t, deleteSQL, stats[0].SQL)
+		assert.Exactly(t, uint64(1), stats[0].Executions)
+		assert.False(t, stats[0].Open)
+
+		_, err = dbr.ExecContext(ctx, 2)
+		assert.NoError(t, err)
+
+		ps := dbc.Stats()
+		assert.Exactly(t, uint64(2), ps.PreparedStatements)
+		assert.Exactly(t, uint64(1), ps.OpenStatements)
+		assert.Exactly(t, uint64(1), ps.IdleStatementsClosed)
+		assert.Exactly(t, uint64(1), ps.StatementsReprepared)
+
+		stats = dbc.PreparedStmtStats()
+		assert.Exactly(t, uint64(2), stats[0].Executions)
+		assert.True(t, stats[0].Open)
+
+		assert.NoError(t, dbr.Close())
+		assert.Len(t, dbc.PreparedStmtStats(), 0)
+		assert.Exactly(t, uint64(0), dbc.Stats().OpenStatements)
+	})
+
+	t.Run("closed statement cannot be used", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectPrepare("^" + dmltest.SQLMockQuoteMeta(deleteSQL)).WillBeClosed()
+
+		dbr := dbc.WithPrepare(ctx, dml.NewDelete("tableA").Where(dml.Column("a").PlaceHolder()))
+		assert.NoError(t, dbr.Close())
+		_, err := dbr.ExecContext(ctx, 1)
+		assert.ErrorIsKind(t, errors.AlreadyClosed, err)
+	})
+
+	t.Run("reaper", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithStmtReaper(time.Millisecond, 0))
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectPrepare("^" + dmltest.SQLMockQuoteMeta(deleteSQL)).WillBeClosed()
+
+		dbc.WithPrepare(ctx, dml.NewDelete("tableA").Where(dml.Column("a").PlaceHolder()))
+		for i := 0; i < 100 && dbc.Stats().IdleStatementsClosed == 0; i++ {
+			time.Sleep(5 * time.Millisecond)
+		}
+		assert.Exactly(t, uint64(1), dbc.Stats().IdleStatementsClosed)
+	})
+
+	t.Run("reaper invalid interval", func(t *testing.T) {
+		_, err := dml.NewConnPool(dml.WithStmtReaper(0, time.Minute))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
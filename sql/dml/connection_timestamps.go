@@ -0,0 +1,219 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// Default column names of the automatic timestamps.
+const (
+	TimestampCreatedAt = "created_at"
+	TimestampUpdatedAt = "updated_at"
+)
+
+// timestampNamedArg gets used in an UPDATE statement to send the client time.
+const timestampNamedArg = "dmlTimestamp"
+
+// TimestampOptions configures the automatic timestamps, see WithTimestamps.
+type TimestampOptions struct {
+	// CreatedAt defines the column which gets set by an INSERT statement.
+	// Defaults to TimestampCreatedAt.
+	CreatedAt string
+	// UpdatedAt defines the column which gets set by an INSERT and an UPDATE
+	// statement. Defaults to TimestampUpdatedAt.
+	UpdatedAt string
+	// HasColumn reports whether a table contains a column, for example
+	// ddl.Tables.HasColumn. Required.
+	HasColumn func(table, column string) bool
+	// ClientTime sends the time of the client as an argument instead of
+	// writing CURRENT_TIMESTAMP. The driver converts the time into the
+	// location of the DSN, which must match the time_zone of the session to
+	// get the same results in both modes.
+	ClientTime bool
+	// Now returns the client time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// WithTimestamps sets the created and updated columns automatically. An
+// INSERT statement appends both columns and an UPDATE statement appends the
+// updated column to the SET clause, if the table has them and the statement
+// does not already contain them. INSERT ... SELECT statements, inserts with
+// Pairs and without a column list get not modified. Timestamps can be
+// disabled per builder with SkipTimestamps or per call with
+// QueryOptions.SkipTimestamps. Prepared statements ignore the QueryOptions.
+func WithTimestamps(o TimestampOptions) ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			if o.HasColumn == nil {
+				return errors.NotValid.Newf("[dml] WithTimestamps: HasColumn function is required")
+			}
+			if o.CreatedAt == "" {
+				o.CreatedAt = TimestampCreatedAt
+			}
+			if o.UpdatedAt == "" {
+				o.UpdatedAt = TimestampUpdatedAt
+			}
+			if o.ClientTime && o.Now == nil {
+				o.Now = time.Now
+			}
+			if !o.ClientTime {
+				o.Now = nil
+			}
+			c.queryCache.timestamps = &o
+			return nil
+		},
+	}
+}
+
+// timestamps contains the columns of a statement which get set automatically.
+type timestamps struct {
+	columns []string
+	// now returns the client time. If nil CURRENT_TIMESTAMP gets written.
+	now func() time.Time
+}
+
+// values returns the values of the columns for a VALUES tuple.
+func (ts *timestamps) values() string {
+	v := ",CURRENT_TIMESTAMP"
+	if ts.now != nil {
+		v = ",?"
+	}
+	var buf []byte
+	for range ts.columns {
+		buf = append(buf, v...)
+	}
+	return string(buf)
+}
+
+// apply resolves the timestamp columns of an INSERT or UPDATE statement and
+// reports whether the builder contains timestamps. The receiver can be nil.
+func (o *TimestampOptions) apply(qb QueryBuilder) bool {
+	if o == nil {
+		return false
+	}
+	var ts *timestamps
+	switch qbs := qb.(type) {
+	case *Insert:
+		if qbs.IsSkipTimestamps || qbs.Select != nil || len(qbs.Pairs) > 0 || len(qbs.Columns) == 0 {
+			return false
+		}
+		ts = o.resolve(qbs.Into, qbs.Columns, o.CreatedAt, o.UpdatedAt)
+		qbs.timestamps = ts
+	case *Update:
+		if qbs.IsSkipTimestamps {
+			return false
+		}
+		setColumns := make([]string, 0, len(qbs.SetClauses)+1)
+		setColumns = append(setColumns, qbs.optimisticLock)
+		for _, sc := range qbs.SetClauses {
+			setColumns = append(setColumns, sc.Left)
+		}
+		ts = o.resolve(qbs.Table.Name, setColumns, o.UpdatedAt)
+		qbs.timestamps = ts
+	}
+	return ts != nil
+}
+
+func (o *TimestampOptions) resolve(table string, setColumns []string, columns ...string) *timestamps {
+	var ts timestamps
+	for _, c := range columns {
+		if !strInSlice(c, setColumns) && o.HasColumn(table, c) {
+			ts.columns = append(ts.columns, c)
+		}
+	}
+	if len(ts.columns) == 0 {
+		return nil
+	}
+	ts.now = o.Now
+	return &ts
+}
+
+// withoutTimestamps renders the builder without the timestamps for the calls
+// with QueryOptions.SkipTimestamps.
+func withoutTimestamps(qb QueryBuilder) (*cachedSQL, error) {
+	var reset func()
+	switch qbs := qb.(type) {
+	case *Insert:
+		ts := qbs.timestamps
+		qbs.timestamps = nil
+		reset = func() { qbs.timestamps = ts }
+	case *Update:
+		ts := qbs.timestamps
+		qbs.timestamps = nil
+		reset = func() { qbs.timestamps = ts }
+	default:
+		return nil, nil
+	}
+	defer reset()
+	rawSQL, _, err := qb.ToSQL()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return makeCachedSQL(qb, rawSQL, ""), nil
+}
+
+// withoutTimestamps returns a copy of the DBR which executes the statement
+// without the timestamps.
+func (a *DBR) withoutTimestamps() *DBR {
+	a2 := *a
+	a2.cachedSQL = *a.cachedSQL.withoutTimestamps
+	a2.cachedSQL.id = a.cachedSQL.id
+	a2.cachedSQL.qualifiedColumns = append(a2.cachedSQL.qualifiedColumns[:0:0], a2.cachedSQL.qualifiedColumns...)
+	return &a2
+}
+
+// skipTimestamps reports whether the call disables the timestamps.
+func (a *DBR) skipTimestamps(ctx context.Context) bool {
+	return a.cachedSQL.withoutTimestamps != nil && FromContextQueryOptions(ctx).SkipTimestamps
+}
+
+// appendTimestampArgs adds the client time to the arguments of an UPDATE
+// statement.
+func (a *DBR) appendTimestampArgs(args []interface{}) []interface{} {
+	if ts := a.cachedSQL.timestamps; ts != nil && ts.now != nil && a.cachedSQL.source == dmlSourceUpdate {
+		return append(args[:len(args):len(args)], sql.Named(timestampNamedArg, ts.now()))
+	}
+	return args
+}
+
+// interleaveTimestampArgs adds the client time after the arguments of each
+// row of an INSERT statement.
+func (cs *cachedSQL) interleaveTimestampArgs(args []interface{}) ([]interface{}, error) {
+	ts := cs.timestamps
+	if ts == nil || ts.now == nil || len(args) == 0 {
+		return args, nil
+	}
+	width := int(cs.insertColumnCount)
+	if cs.tupleRowCount > 0 {
+		width = len(args) / int(cs.tupleRowCount)
+	}
+	if width == 0 || len(args)%width != 0 {
+		return nil, errors.Mismatch.Newf("[dml] Timestamps: %d arguments do not match %d columns", len(args), width)
+	}
+	now := ts.now()
+	ret := make([]interface{}, 0, len(args)+len(args)/width*len(ts.columns))
+	for i := 0; i < len(args); i += width {
+		ret = append(ret, args[i:i+width]...)
+		for range ts.columns {
+			ret = append(ret, now)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/go-sql-driver/mysql"
+)
+
+func timestampsHasColumn(table, column string) bool {
+	return table == "sales_order" && (column == "created_at" || column == "updated_at")
+}
+
+type timestampOrder struct {
+	ID    int64
+	State string
+}
+
+func (o *timestampOrder) MapColumns(cm *dml.ColumnMap) error {
+	for cm.Next(2) {
+		switch c := cm.Column(); c {
+		case "entity_id", "0":
+			cm.Int64(&o.ID)
+		case "state", "1":
+			cm.String(&o.State)
+		default:
+			return errors.NotFound.Newf("[dml_test] Column %q not found", c)
+		}
+	}
+	return cm.Err()
+}
+
+func TestWithTimestamps(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 2, 29, 12, 13, 14, 0, time.UTC)
+
+	t.Run("missing HasColumn", func(t *testing.T) {
+		_, err := dml.NewConnPool(dml.WithTimestamps(dml.TimestampOptions{}))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("CURRENT_TIMESTAMP", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithTimestamps(dml.TimestampOptions{HasColumn: timestampsHasColumn}))
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `sales_order` (`state`,`created_at`,`updated_at`) VALUES (?,CURRENT_TIMESTAMP,CURRENT_TIMESTAMP),(?,CURRENT_TIMESTAMP,CURRENT_TIMESTAMP)")).
+			WithArgs("new", "processing").WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `sales_order` SET `updated_at`=CURRENT_TIMESTAMP, `state`=? WHERE (`entity_id` = ?)")).
+			WithArgs("complete", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := dbc.WithQueryBuilder(dml.NewInsert("sales_order").AddColumns("state")).ExecContext(ctx, "new", "processing")
+		assert.NoError(t, err)
+		_, err = dbc.WithQueryBuilder(dml.NewUpdate("sales_order").AddColumns("state").
+			Where(dml.Column("entity_id").PlaceHolder())).ExecContext(ctx, "complete", 3)
+		assert.NoError(t, err)
+	})
+
+	t.Run("client time", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithTimestamps(dml.TimestampOptions{
+			HasColumn:  timestampsHasColumn,
+			ClientTime: true,
+			Now:        func() time.Time { return now },
+		}))
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `sales_order` (`entity_id`,`state`,`created_at`,`updated_at`) VALUES (?,?,?,?),(?,?,?,?)")).
+			WithArgs(1, "new", now, now, 2, "processing", now, now).WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `sales_order` SET `updated_at`=?, `state`=? WHERE (`entity_id` = ?)")).
+			WithArgs(now, "complete", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `sales_order` SET `updated_at`=?, `state`=? WHERE (`entity_id` = ?)")).
+			WithArgs(now, "canceled", 4).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := dbc.WithQueryBuilder(dml.NewInsert("sales_order").AddColumns("entity_id", "state")).
+			ExecContext(ctx, dml.Qualify("", &timestampOrder{ID: 1, State: "new"}), dml.Qualify("", &timestampOrder{ID: 2, State: "processing"}))
+		assert.NoError(t, err)
+
+		upd := dbc.WithQueryBuilder(dml.NewUpdate("sales_order").AddColumns("state").
+			Where(dml.Column("entity_id").PlaceHolder()))
+		_, err = upd.ExecContext(ctx, "complete", 3)
+		assert.NoError(t, err)
+		_, err = upd.ExecContext(ctx, dml.Qualify("", &timestampOrder{ID: 4, State: "canceled"}))
+		assert.NoError(t, err)
+	})
+
+	t.Run("not applied", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithTimestamps(dml.TimestampOptions{HasColumn: timestampsHasColumn}))
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `sales_order` (`state`,`created_at`,`updated_at`) VALUES (?,?,CURRENT_TIMESTAMP)")).
+			WithArgs("new", now).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `catalog_product` (`sku`) VALUES (?)")).
+			WithArgs("a").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `sales_order` SET `state`=?")).
+			WithArgs("new").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `sales_order` SET `state`=?")).
+			WithArgs("new").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// created_at gets set by the caller, hence only updated_at gets added.
+		_, err := dbc.WithQueryBuilder(dml.NewInsert("sales_order").AddColumns("state", "created_at")).
+			ExecContext(ctx, "new", now)
+		assert.NoError(t, err)
+		_, err = dbc.WithQueryBuilder(dml.NewInsert("catalog_product").AddColumns("sku")).ExecContext(ctx, "a")
+		assert.NoError(t, err)
+		_, err = dbc.WithQueryBuilder(dml.NewUpdate("sales_order").AddColumns("state").SkipTimestamps()).ExecContext(ctx, "new")
+		assert.NoError(t, err)
+
+		upd := dbc.WithQueryBuilder(dml.NewUpdate("sales_order").AddColumns("state"))
+		_, err = upd.ExecContext(dml.WithContextQueryOptions(ctx, dml.QueryOptions{SkipTimestamps: true}), "new")
+		assert.NoError(t, err)
+	})
+}
+
+func TestWithTimestamps_TimeZone(t *testing.T) {
+	hasColumn := func(table, column string) bool {
+		return table == "dml_timestamps" && (column == "created_at" || column == "updated_at")
+	}
+	serverPool := dmltest.MustConnectDB(t, dml.WithTimestamps(dml.TimestampOptions{HasColumn: hasColumn}))
+	defer dmltest.Close(t, serverPool)
+	clientPool := dmltest.MustConnectDB(t, dml.WithTimestamps(dml.TimestampOptions{HasColumn: hasColumn, ClientTime: true}))
+	defer dmltest.Close(t, clientPool)
+	ctx := context.Background()
+
+	dsn, err := mysql.ParseDSN(dmltest.MustGetDSN(t))
+	assert.NoError(t, err)
+	// The session time zone must match the location of the DSN, which the
+	// driver uses to convert the client time.
+	_, offset := time.Now().In(dsn.Loc).Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	timeZone := fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset%3600/60)
+
+	for _, sqlStr := range []string{
+		"DROP TABLE IF EXISTS `dml_timestamps`",
+		"CREATE TABLE `dml_timestamps` ( `id` int(10) unsigned NOT NULL PRIMARY KEY, `created_at` DATETIME NULL, `updated_at` DATETIME NULL )",
+	} {
+		_, err := serverPool.DB.ExecContext(ctx, sqlStr)
+		assert.NoError(t, err)
+	}
+	defer func() {
+		_, err := serverPool.DB.ExecContext(ctx, "DROP TABLE IF EXISTS `dml_timestamps`")
+		assert.NoError(t, err)
+	}()
+
+	for id, dbc := range []*dml.ConnPool{serverPool, clientPool} {
+		conn, err := dbc.Conn(ctx)
+		assert.NoError(t, err)
+		_, err = conn.DB.ExecContext(ctx, "SET time_zone = ?", timeZone)
+		assert.NoError(t, err)
+		_, err = conn.WithQueryBuilder(dml.NewInsert("dml_timestamps").AddColumns("id")).ExecContext(ctx, id+1)
+		assert.NoError(t, err)
+		_, err = conn.WithQueryBuilder(dml.NewUpdate("dml_timestamps").AddClauses(dml.Column("id").Int(id + 1)).
+			Where(dml.Column("id").Int(id + 1))).ExecContext(ctx)
+		assert.NoError(t, err)
+		dmltest.Close(t, conn)
+	}
+
+	var createdDiff, updatedDiff sql.NullInt64
+	err = serverPool.DB.QueryRowContext(ctx, "SELECT ABS(TIMESTAMPDIFF(SECOND, s.`created_at`, c.`created_at`)), ABS(TIMESTAMPDIFF(SECOND, s.`updated_at`, c.`updated_at`)) "+
+		"FROM `dml_timestamps` s JOIN `dml_timestamps` c ON s.`id` = 1 AND c.`id` = 2").Scan(&createdDiff, &updatedDiff)
+	assert.NoError(t, err)
+	assert.True(t, createdDiff.Valid && createdDiff.Int64 < 5, "created_at of client and server differ: %v", createdDiff)
+	assert.True(t, updatedDiff.Valid && updatedDiff.Int64 < 5, "updated_at of client and server differ: %v", updatedDiff)
+}
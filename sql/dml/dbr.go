@@ -18,8 +18,10 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/log"
@@ -62,39 +64,71 @@ type cachedSQL struct {
 	tupleCount          uint
 	tupleRowCount       uint
 	insertIsBuildValues bool
+	// table contains the name of the table of a SELECT, INSERT, UPDATE or
+	// DELETE statement.
+	table string
+	// joinedTables contains the names of the tables joined by a SELECT
+	// statement, see DBR.LoadCached.
+	joinedTables []string
+	// optimisticLock contains the version column of an UPDATE statement, see
+	// Update.WithOptimisticLock.
+	optimisticLock string
+	// existsSQL checks if the row of an UPDATE statement with optimistic
+	// locking still exists.
+	existsSQL *cachedSQL
+	// listeners of the builder, see Select.AddListener.
+	listeners []Listener
+	// timestamps contains the columns of an INSERT or UPDATE statement which
+	// get set automatically, see WithTimestamps.
+	timestamps *timestamps
+	// withoutTimestamps contains the statement without the timestamps for
+	// calls with QueryOptions.SkipTimestamps.
+	withoutTimestamps *cachedSQL
 }
 
 func noopMapTableNameFn(oldName string) string { return oldName }
 
-func prepareQueryBuilder(mapTableNameFn func(oldName string) (newName string), qb QueryBuilder) {
+// prepareQueryBuilder applies the table name mapper and a non-nil dialect `d`
+// to the builder.
+func prepareQueryBuilder(mapTableNameFn func(oldName string) (newName string), d Dialect, qb QueryBuilder) {
 	if mapTableNameFn == nil {
 		mapTableNameFn = noopMapTableNameFn
 	}
 
+	var bb *BuilderBase
 	switch qbs := qb.(type) {
 	case *Select:
 		qbs.Table.Name = mapTableNameFn(qbs.Table.Name)
-		qbs.BuilderBase.isWithDBR = true
+		bb = &qbs.BuilderBase
 	case *Insert:
 		qbs.Into = mapTableNameFn(qbs.Into)
-		qbs.BuilderBase.isWithDBR = true
+		bb = &qbs.BuilderBase
 		if qbs.Select != nil {
 			qbs.Select.Table.Name = mapTableNameFn(qbs.Select.Table.Name)
+			if d != nil {
+				qbs.Select.dialect = d
+			}
 		}
 	case *Delete:
 		qbs.Table.Name = mapTableNameFn(qbs.Table.Name)
-		qbs.BuilderBase.isWithDBR = true
+		bb = &qbs.BuilderBase
 	case *Update:
 		qbs.Table.Name = mapTableNameFn(qbs.Table.Name)
-		qbs.BuilderBase.isWithDBR = true
+		bb = &qbs.BuilderBase
 	case *Show:
-		qbs.BuilderBase.isWithDBR = true
+		bb = &qbs.BuilderBase
 	case *With:
 		qbs.Table.Name = mapTableNameFn(qbs.Table.Name)
-		qbs.BuilderBase.isWithDBR = true
+		bb = &qbs.BuilderBase
 	case *Union:
 		qbs.Table.Name = mapTableNameFn(qbs.Table.Name)
-		qbs.BuilderBase.isWithDBR = true
+		bb = &qbs.BuilderBase
+	default:
+		return
+	}
+	bb.isWithDBR = true
+	if d != nil {
+		bb.dialect = d
 	}
 }
 
@@ -108,10 +142,18 @@ func makeCachedSQL(qb QueryBuilder, rawSQL, id string) *cachedSQL {
 	switch qbs := qb.(type) {
 	case *Select:
 		sqlCache.defaultQualifier = qbs.Table.qualifier()
+		sqlCache.table = qbs.Table.Name
+		for _, j := range qbs.Joins {
+			if j.Table.DerivedTable == nil && j.Table.Expression == "" && j.Table.Name != "" {
+				sqlCache.joinedTables = append(sqlCache.joinedTables, j.Table.Name)
+			}
+		}
 		sqlCache.source = dmlSourceSelect
 		sqlCache.containsTuples = qbs.BuilderBase.containsTuples
 		sqlCache.qualifiedColumns = qbs.BuilderBase.qualifiedColumns
+		sqlCache.listeners = qbs.BuilderBase.listeners
 	case *Insert:
+		sqlCache.table = qbs.Into
 		sqlCache.source = dmlSourceInsert
 		if qbs.Select != nil {
 			// Must change to this source because to trigger a different argument
@@ -124,16 +166,27 @@ func makeCachedSQL(qb QueryBuilder, rawSQL, id string) *cachedSQL {
 		sqlCache.insertIsBuildValues = qbs.IsBuildValues
 		sqlCache.containsTuples = qbs.BuilderBase.containsTuples
 		sqlCache.qualifiedColumns = qbs.BuilderBase.qualifiedColumns
+		sqlCache.listeners = qbs.BuilderBase.listeners
+		sqlCache.timestamps = qbs.timestamps
 	case *Delete:
 		sqlCache.defaultQualifier = qbs.Table.qualifier()
+		sqlCache.table = qbs.Table.Name
 		sqlCache.source = dmlSourceDelete
 		sqlCache.containsTuples = qbs.BuilderBase.containsTuples
 		sqlCache.qualifiedColumns = qbs.BuilderBase.qualifiedColumns
+		sqlCache.listeners = qbs.BuilderBase.listeners
 	case *Update:
 		sqlCache.defaultQualifier = qbs.Table.qualifier()
+		sqlCache.table = qbs.Table.Name
 		sqlCache.source = dmlSourceUpdate
 		sqlCache.containsTuples = qbs.BuilderBase.containsTuples
 		sqlCache.qualifiedColumns = qbs.BuilderBase.qualifiedColumns
+		sqlCache.listeners = qbs.BuilderBase.listeners
+		sqlCache.timestamps = qbs.timestamps
+		if qbs.optimisticLock != "" {
+			sqlCache.optimisticLock = qbs.optimisticLock
+			sqlCache.existsSQL = qbs.existsCachedSQL()
+		}
 	case *Show:
 		sqlCache.source = dmlSourceShow
 		sqlCache.containsTuples = qbs.BuilderBase.containsTuples
@@ -162,13 +215,26 @@ func makeCachedSQL(qb QueryBuilder, rawSQL, id string) *cachedSQL {
 type DBR struct {
 	customCacheKey string // set before to access a different query
 	cachedSQL      cachedSQL
-	log            log.Logger // Log optional logger
+	log            log.Logger        // Log optional logger
+	slowQuery      *slowQueryMonitor // optional, see WithSlowQueryThreshold
+	queryComment   *queryComment     // optional, see WithQueryCommentTags
+	listeners      *listeners        // optional, see ConnPool.AddListenerForTable
+	// strictColumnMap see WithStrictColumnMap.
+	strictColumnMap bool
+	gtid            *gtidTracker     // optional, see WithSessionTracking
+	redactor        ArgumentRedactor // optional, see WithArgumentRedactor
 	// DB can be either a *sql.DB (connection pool), a *sql.Conn (a single
 	// dedicated database session) or a *sql.Tx (an in-progress database
 	// transaction).
 	DB QueryExecPreparer
 	// isPrepared if true the cachedSQL field in base gets ignored
 	isPrepared bool
+	// keepRawBytes disables the decoding in LoadRows and ScanRows.
+	keepRawBytes bool
+	// skipExistenceCheck see SkipExistenceCheck.
+	skipExistenceCheck bool
+	// dialect if nil, falls back to MySQL.
+	dialect Dialect
 	// Options like enable interpolation or expanding placeholders.
 	Options     uint
 	previousErr error
@@ -191,6 +257,13 @@ type DBR struct {
 	OffsetCount             uint64
 }
 
+func (a *DBR) sqlDialect() Dialect {
+	if a.dialect == nil {
+		return dialect
+	}
+	return a.dialect
+}
+
 // PreviousError returns the previous error. Mostly used for testing.
 func (a *DBR) PreviousError() error {
 	if a == nil {
@@ -207,6 +280,7 @@ func (b *Select) WithDBR(db QueryExecPreparer) *DBR {
 	return &DBR{
 		cachedSQL:   *sqlCache,
 		DB:          db,
+		dialect:     b.dialect,
 		previousErr: err,
 	}
 }
@@ -218,6 +292,7 @@ func (b *Insert) WithDBR(db QueryExecPreparer) *DBR {
 	return &DBR{
 		cachedSQL:   *sqlCache,
 		DB:          db,
+		dialect:     b.dialect,
 		previousErr: err,
 	}
 }
@@ -229,6 +304,7 @@ func (b *Delete) WithDBR(db QueryExecPreparer) *DBR {
 	return &DBR{
 		cachedSQL:   *sqlCache,
 		DB:          db,
+		dialect:     b.dialect,
 		previousErr: err,
 	}
 }
@@ -240,6 +316,7 @@ func (b *Update) WithDBR(db QueryExecPreparer) *DBR {
 	return &DBR{
 		cachedSQL:   *sqlCache,
 		DB:          db,
+		dialect:     b.dialect,
 		previousErr: err,
 	}
 }
@@ -251,6 +328,7 @@ func (b *Show) WithDBR(db QueryExecPreparer) *DBR {
 	return &DBR{
 		cachedSQL:   *sqlCache,
 		DB:          db,
+		dialect:     b.dialect,
 		previousErr: err,
 	}
 }
@@ -262,6 +340,7 @@ func (b *Union) WithDBR(db QueryExecPreparer) *DBR {
 	return &DBR{
 		cachedSQL:   *sqlCache,
 		DB:          db,
+		dialect:     b.dialect,
 		previousErr: err,
 	}
 }
@@ -273,6 +352,7 @@ func (b *With) WithDBR(db QueryExecPreparer) *DBR {
 	return &DBR{
 		cachedSQL:   *sqlCache,
 		DB:          db,
+		dialect:     b.dialect,
 		previousErr: err,
 	}
 }
@@ -419,10 +499,20 @@ func (a *DBR) ExpandPlaceHolders() *DBR {
 // allocations. All method receivers are not thread safe. The returned interface
 // slice is the same as `extArgs`.
 // The returned []QualifiedRecord slice is needed to use interface LastInsertIDAssigner.
-func (a *DBR) prepareQueryAndArgs(extArgs []interface{}) (_ string, _ []interface{}, err error) {
+// For dialects other than MySQL the placeholders of the returned SQL string
+// get numbered.
+func (a *DBR) prepareQueryAndArgs(extArgs []interface{}) (sqlStr string, _ []interface{}, err error) {
 	if a.previousErr != nil {
 		return "", nil, errors.WithStack(a.previousErr)
 	}
+	if usesANSIQuotes(a.dialect) {
+		defer func() {
+			if err == nil {
+				sqlStr = rebindSQL(a.dialect, sqlStr)
+			}
+		}()
+	}
+	extArgs = a.appendTimestampArgs(extArgs)
 	lenExtArgs := len(extArgs)
 	var hasNamedArgs uint8
 	var qualifiedRecordCount int
@@ -480,7 +570,7 @@ func (a *DBR) prepareQueryAndArgs(extArgs []interface{}) (_ string, _ []interfac
 		defer bufferpool.Put(buf)
 		buf.WriteString(cachedSQL)
 		sqlWriteOrderBy(buf, a.OrderBys, false)
-		sqlWriteLimitOffset(buf, a.LimitValid, a.OffsetValid, a.OffsetCount, a.LimitCount)
+		sqlWriteLimitOffset(a.sqlDialect(), buf, a.LimitValid, a.OffsetValid, a.OffsetCount, a.LimitCount)
 		return buf.String(), expandInterfaces(args), nil
 	}
 
@@ -514,7 +604,7 @@ func (a *DBR) prepareQueryAndArgs(extArgs []interface{}) (_ string, _ []interfac
 	}
 
 	sqlWriteOrderBy(sqlBuf.First, a.OrderBys, false)
-	sqlWriteLimitOffset(sqlBuf.First, a.LimitValid, a.OffsetValid, a.OffsetCount, a.LimitCount)
+	sqlWriteLimitOffset(a.sqlDialect(), sqlBuf.First, a.LimitValid, a.OffsetValid, a.OffsetCount, a.LimitCount)
 
 	// `switch` statement no suitable.
 	if a.Options > 0 && lenExtArgs > 0 && qualifiedRecordCount == 0 && len(args) == 0 {
@@ -557,7 +647,7 @@ func (a *DBR) prepareQueryAndArgs(extArgs []interface{}) (_ string, _ []interfac
 		}
 	}
 	if a.Options&argOptionInterpolate != 0 {
-		if err := writeInterpolateBytes(sqlBuf.Second, sqlBuf.First.Bytes(), args); err != nil {
+		if err := writeInterpolateBytes(a.sqlDialect(), sqlBuf.Second, sqlBuf.First.Bytes(), args); err != nil {
 			return "", nil, errors.Wrapf(err, "[dml] Interpolation failed: %q", sqlBuf.String())
 		}
 		return sqlBuf.Second.String(), nil, nil
@@ -601,7 +691,10 @@ func (a *DBR) appendConvertedRecordsToArguments(hasNamedArgs uint8, collectedArg
 	var nextUnnamedArgPos int
 	// TODO refactor prototype and make it performant and beautiful code
 	cm := NewColumnMap(len(collectedArgs)+containsQualifiedRecords, "") // can use an arg pool DBR sync.Pool, nope.
-	for tsc := 0; tsc < templateStmtCount; tsc++ {                      // only in case of UNION statements in combination with a template SELECT, can be optimized later
+	if a.strictColumnMap {
+		cm.enableStrict(a.customCacheKey)
+	}
+	for tsc := 0; tsc < templateStmtCount; tsc++ { // only in case of UNION statements in combination with a template SELECT, can be optimized later
 
 		// `qualifiedColumns` contains the correct order as the place holders
 		// appear in the SQL string.
@@ -619,26 +712,13 @@ func (a *DBR) appendConvertedRecordsToArguments(hasNamedArgs uint8, collectedArg
 					return collectedArgs, errors.WithStack(err)
 				}
 			} else {
-				found := false
+				found, err := a.mapQualifiedRecords(cm, qualifier, collectedArgs)
+				if err != nil {
+					return collectedArgs, errors.WithStack(err)
+				}
 				for _, arg := range collectedArgs {
-					switch qRec := arg.(type) {
-					case QualifiedRecord:
-						if qRec.Qualifier == "" && qualifier != "" {
-							qRec.Qualifier = a.cachedSQL.defaultQualifier
-						}
-						if qRec.Qualifier != "" && qualifier == "" {
-							qualifier = a.cachedSQL.defaultQualifier
-						}
-
-						if qRec.Qualifier == qualifier {
-							if err := qRec.Record.MapColumns(cm); err != nil {
-								return collectedArgs, errors.WithStack(err)
-							}
-							found = true
-						}
-
-					case ColumnMapper:
-						if err := qRec.MapColumns(cm); err != nil {
+					if qRec, ok := arg.(ColumnMapper); ok {
+						if err := cm.mapColumns(qRec); err != nil {
 							return collectedArgs, errors.WithStack(err)
 						}
 					}
@@ -671,6 +751,9 @@ func (a *DBR) prepareQueryAndArgsInsert(extArgs []interface{}, primitiveCounts i
 	defer bufferpool.PutTwin(sqlBuf)
 	cm := NewColumnMap(2*primitiveCounts, a.cachedSQL.qualifiedColumns...)
 	cm.args = extArgs
+	if a.strictColumnMap {
+		cm.enableStrict(a.customCacheKey)
+	}
 	lenExtArgsBefore := len(extArgs)
 	lenInsertCachedSQL := len(a.cachedSQL.insertCachedSQL)
 	cachedSQL := a.cachedSQL.rawSQL
@@ -689,12 +772,12 @@ func (a *DBR) prepareQueryAndArgsInsert(extArgs []interface{}, primitiveCounts i
 				if qRec.Qualifier != "" {
 					return "", nil, errors.Fatal.Newf("[dml] Qualifier in %T is not supported and not needed.", qRec)
 				}
-				if err := qRec.Record.MapColumns(cm); err != nil {
+				if err := cm.mapColumns(qRec.Record); err != nil {
 					return "", nil, errors.WithStack(err)
 				}
 				containsRecords = true
 			case ColumnMapper:
-				if err := qRec.MapColumns(cm); err != nil {
+				if err := cm.mapColumns(qRec); err != nil {
 					return "", nil, errors.WithStack(err)
 				}
 				containsRecords = true
@@ -702,6 +785,13 @@ func (a *DBR) prepareQueryAndArgsInsert(extArgs []interface{}, primitiveCounts i
 		}
 		primitiveCounts += len(cm.args) - lenExtArgsBefore
 	}
+	if a.cachedSQL.timestamps != nil {
+		args, err := a.cachedSQL.interleaveTimestampArgs(expandInterfaces(cm.args))
+		if err != nil {
+			return "", nil, errors.WithStack(err)
+		}
+		cm.args = args
+	}
 
 	if a.isPrepared {
 		// TODO above construct can be more optimized when using prepared statements
@@ -710,6 +800,9 @@ func (a *DBR) prepareQueryAndArgsInsert(extArgs []interface{}, primitiveCounts i
 
 	if !a.cachedSQL.insertIsBuildValues && lenInsertCachedSQL == 0 { // Write placeholder list e.g. "VALUES (?,?),(?,?)"
 		odkPos := strings.Index(cachedSQL, onDuplicateKeyPartS)
+		if odkPos < 0 {
+			odkPos = strings.Index(cachedSQL, onConflictPartS)
+		}
 		if odkPos > 0 {
 			sqlBuf.First.Reset()
 			sqlBuf.First.WriteString(cachedSQL[:odkPos])
@@ -717,13 +810,13 @@ func (a *DBR) prepareQueryAndArgsInsert(extArgs []interface{}, primitiveCounts i
 
 		if a.cachedSQL.tupleRowCount > 0 {
 			columnCount := uint(primitiveCounts) / a.cachedSQL.tupleRowCount
-			writeTuplePlaceholders(sqlBuf.First, a.cachedSQL.tupleRowCount, columnCount)
+			writeInsertTuples(sqlBuf.First, a.cachedSQL.tupleRowCount, columnCount, a.cachedSQL.timestamps)
 		} else if a.cachedSQL.insertColumnCount > 0 {
 			rowCount := uint(primitiveCounts) / a.cachedSQL.insertColumnCount
 			if rowCount == 0 {
 				rowCount = 1
 			}
-			writeTuplePlaceholders(sqlBuf.First, rowCount, a.cachedSQL.insertColumnCount)
+			writeInsertTuples(sqlBuf.First, rowCount, a.cachedSQL.insertColumnCount, a.cachedSQL.timestamps)
 		}
 		if odkPos > 0 {
 			sqlBuf.First.WriteString(cachedSQL[odkPos:])
@@ -739,7 +832,7 @@ func (a *DBR) prepareQueryAndArgsInsert(extArgs []interface{}, primitiveCounts i
 		}
 
 		if a.Options&argOptionInterpolate != 0 {
-			if err := writeInterpolateBytes(sqlBuf.Second, sqlBuf.First.Bytes(), cm.args); err != nil {
+			if err := writeInterpolateBytes(a.sqlDialect(), sqlBuf.Second, sqlBuf.First.Bytes(), cm.args); err != nil {
 				return "", nil, errors.Wrapf(err, "[dml] Interpolation failed: %q", sqlBuf.First.String())
 			}
 			return sqlBuf.Second.String(), nil, nil
@@ -750,6 +843,62 @@ func (a *DBR) prepareQueryAndArgsInsert(extArgs []interface{}, primitiveCounts i
 }
 
 // nextUnnamedArg returns an unnamed argument by its position.
+// mapQualifiedRecords maps the current column of cm from the records of the
+// qualifier. An empty qualifier of a record or of a placeholder refers to the
+// default qualifier. If several records share the qualifier, exactly one of
+// them must provide the column. If no record of the default qualifier exists,
+// an unqualified placeholder gets resolved by the records of the other
+// qualifiers and it is an error if more than one provides the column. It
+// returns false if no record provides the column.
+func (a *DBR) mapQualifiedRecords(cm *ColumnMap, qualifier string, args []interface{}) (bool, error) {
+	dq := a.cachedSQL.defaultQualifier
+	resolve := func(q string) string {
+		if q == "" {
+			return dq
+		}
+		return q
+	}
+	want := resolve(qualifier)
+
+	var candidates, boundValues int
+	var lastQRec QualifiedRecord
+	for _, arg := range args {
+		if qRec, ok := arg.(QualifiedRecord); ok && resolve(qRec.Qualifier) == want {
+			candidates++
+			lastQRec = qRec
+			if _, ok := qRec.Record.(boundValue); ok {
+				boundValues++
+			}
+		}
+	}
+	if candidates == 1 && boundValues == 0 {
+		// the only record must provide the column.
+		return true, errors.WithStack(cm.mapColumns(lastQRec.Record))
+	}
+
+	var providedBy []string
+	for _, arg := range args {
+		qRec, ok := arg.(QualifiedRecord)
+		if !ok || (candidates > 0 && resolve(qRec.Qualifier) != want) || (candidates == 0 && qualifier != "") {
+			continue
+		}
+		provided, err := cm.probeColumns(qRec.Record)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		if provided {
+			providedBy = append(providedBy, fmt.Sprintf("%s:%T", resolve(qRec.Qualifier), qRec.Record))
+		}
+	}
+	switch {
+	case len(providedBy) > 1:
+		return false, errors.Duplicated.Newf("[dml] Ambiguous placeholder for column %q with qualifier %q: Column gets provided by several records %q", cm.columns[0], qualifier, providedBy)
+	case len(providedBy) == 0 && candidates > boundValues:
+		return false, errors.NotFound.Newf("[dml] Column %q not found in the records with qualifier %q", cm.columns[0], want)
+	}
+	return len(providedBy) == 1, nil
+}
+
 func (a *DBR) nextUnnamedArg(nextUnnamedArgPos int, args []interface{}) (interface{}, int, bool) {
 	var unnamedCounter int
 	for _, arg := range args {
@@ -843,6 +992,9 @@ func (a *DBR) Prepare(ctx context.Context) (*DBR, error) {
 	if _, ok := a.DB.(stmtWrapper); ok {
 		return nil, errors.Fatal.Newf("already a prepared statement")
 	}
+	if a.queryComment != nil && a.queryComment.onPrepared {
+		sqlStr = a.queryComment.prefix(ctx, sqlStr)
+	}
 	stmt, err := a.DB.PrepareContext(ctx, sqlStr)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Preparation of query %q failed", sqlStr)
@@ -853,12 +1005,48 @@ func (a *DBR) Prepare(ctx context.Context) (*DBR, error) {
 }
 
 // WithTx sets the transaction query executor and the logger to run this query
-// within a transaction.
+// within a transaction. A statement prepared on the connection pool cannot see
+// the uncommitted data of the transaction, hence it gets rebound to the
+// transaction. The rebound statement gets closed with the transaction, the
+// statement of the connection pool stays open. A statement prepared on a read
+// replica gets prepared again on the transaction.
 func (a *DBR) WithTx(tx *Tx) *DBR {
 	if a.cachedSQL.id == "" {
 		a.cachedSQL.id = tx.queryCache.makeUniqueID()
 	}
 	a.log = tx.Log
+	if sw, ok := a.DB.(stmtWrapper); ok {
+		if sw.onReplica {
+			stmt, err := tx.DB.PrepareContext(context.Background(), a.cachedSQL.rawSQL)
+			if err != nil {
+				a.previousErr = errors.Wrapf(err, "[dml] DBR.WithTx failed to prepare the query %q", a.cachedSQL.rawSQL)
+				return a
+			}
+			a.DB = stmtWrapper{stmt: stmt}
+			return a
+		}
+		switch stmt := sw.stmt.(type) {
+		case *sql.Stmt:
+			a.DB = stmtWrapper{stmt: tx.DB.Stmt(stmt)}
+			return a
+		case *preparedStmt:
+			s, onReplica, err := stmt.current(context.Background())
+			switch {
+			case err != nil:
+				a.previousErr = errors.WithStack(err)
+			case onReplica:
+				s, err = tx.DB.PrepareContext(context.Background(), a.cachedSQL.rawSQL)
+				if err != nil {
+					a.previousErr = errors.Wrapf(err, "[dml] DBR.WithTx failed to prepare the query %q", a.cachedSQL.rawSQL)
+					return a
+				}
+				a.DB = stmtWrapper{stmt: s}
+			default:
+				a.DB = stmtWrapper{stmt: tx.DB.Stmt(s)}
+			}
+			return a
+		}
+	}
 	a.DB = tx.DB
 	return a
 }
@@ -938,26 +1126,33 @@ func (a *DBR) ExecContext(ctx context.Context, args ...interface{}) (sql.Result,
 }
 
 // QueryContext traditional way of the databasel/sql package.
-func (a *DBR) QueryContext(ctx context.Context, args ...interface{}) (*sql.Rows, error) {
+func (a *DBR) QueryContext(ctx context.Context, args ...interface{}) (rows *sql.Rows, err error) {
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() { sq.observe(a, start, args, -1, -1, err) }()
+	}
 	return a.query(ctx, args)
 }
 
 // QueryRowContext traditional way of the databasel/sql package.
 func (a *DBR) QueryRowContext(ctx context.Context, args ...interface{}) *sql.Row {
-	sqlStr, args, err := a.prepareQueryAndArgs(args)
+	if sq := a.slowQuery; sq != nil {
+		defer sq.observe(a, time.Now(), args, -1, -1, nil) // the error gets reported by sql.Row.Scan
+	}
+	sqlStr, preparedArgs, err := a.prepareQueryAndArgs(args)
 	if a.log != nil && a.log.IsDebug() {
 		defer log.WhenDone(a.log).Debug(
 			"QueryRowContext",
-			log.String("sql", sqlStr),
+			log.String("sql", a.redactSQL(sqlStr, args)),
 			log.String("source", string(a.cachedSQL.source)),
 			log.Err(err))
 	}
-	return a.DB.QueryRowContext(ctx, sqlStr, args...)
+	return a.DB.QueryRowContext(ctx, a.queryComment.prefix(ctx, sqlStr), preparedArgs...)
 }
 
 // IterateSerial iterates in serial order over the result set by loading one row each
 // iteration and then discarding it. Handles records one by one. The context
-// gets only used in the Query function.
+// gets checked before each row, see ErrPartialResult and QueryOptions.MaxRows.
 func (a *DBR) IterateSerial(ctx context.Context, callBack func(*ColumnMap) error, args ...interface{}) (err error) {
 	if a.log != nil && a.log.IsDebug() {
 		defer log.WhenDone(a.log).Debug(
@@ -965,6 +1160,11 @@ func (a *DBR) IterateSerial(ctx context.Context, callBack func(*ColumnMap) error
 			log.String("id", a.cachedSQL.id),
 			log.Err(err))
 	}
+	var rowCount int64
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() { sq.observe(a, start, args, -1, rowCount, err) }()
+	}
 
 	r, err := a.query(ctx, args)
 	if err != nil {
@@ -979,7 +1179,12 @@ func (a *DBR) IterateSerial(ctx context.Context, callBack func(*ColumnMap) error
 		}
 	})
 
+	g := newRowGuard(ctx)
 	for r.Next() {
+		if err = g.next(); err != nil {
+			return
+		}
+		rowCount++
 		if err = cmr.Scan(r); err != nil {
 			err = errors.WithStack(err)
 			return
@@ -988,8 +1193,9 @@ func (a *DBR) IterateSerial(ctx context.Context, callBack func(*ColumnMap) error
 			err = errors.WithStack(err)
 			return
 		}
+		g.rows++
 	}
-	err = errors.WithStack(r.Err())
+	err = g.wrap(r.Err())
 	return
 }
 
@@ -999,27 +1205,33 @@ func (a *DBR) IterateSerial(ctx context.Context, callBack func(*ColumnMap) error
 func iterateParallelForNextLoop(ctx context.Context, r *sql.Rows, rowChan chan<- *ColumnMap) (err error) {
 	defer func() {
 		if err2 := r.Err(); err2 != nil && err == nil {
-			err = errors.WithStack(err)
+			err = errors.WithStack(err2)
 		}
 		if err2 := r.Close(); err2 != nil && err == nil {
 			err = errors.Wrap(err2, "[dml] IterateParallel.QueryContext.Rows.Close")
 		}
 	}()
 
-	var idx uint64
+	g := newRowGuard(ctx)
 	for r.Next() {
+		if err = g.next(); err != nil {
+			if isPartial(err) {
+				err = nil // the error of the errgroup gets returned
+			}
+			return
+		}
 		var cm ColumnMap // must be empty because we're not collecting data
 		if errS := cm.Scan(r); errS != nil {
 			err = errors.WithStack(errS)
 			return
 		}
-		cm.Count = idx
+		cm.Count = g.rows
 		select {
 		case rowChan <- &cm:
 		case <-ctx.Done():
 			return
 		}
-		idx++
+		g.rows++
 	}
 	return
 }
@@ -1037,6 +1249,10 @@ func (a *DBR) IterateParallel(ctx context.Context, concurrencyLevel int, callBac
 	if concurrencyLevel < 1 {
 		return errors.OutOfRange.Newf("[dml] DBR.IterateParallel concurrencyLevel %d for query ID %q cannot be smaller zero.", concurrencyLevel, a.cachedSQL.id)
 	}
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() { sq.observe(a, start, args, -1, -1, err) }()
+	}
 
 	r, err := a.query(ctx, args)
 	if err != nil {
@@ -1073,10 +1289,23 @@ func (a *DBR) IterateParallel(ctx context.Context, concurrencyLevel int, callBac
 // Load loads data from a query into an object. Load can load a single row or
 // multiple-rows. It checks on top if ColumnMapper `s` implements io.Closer, to
 // call the custom close function. This is useful for e.g. unlocking a mutex.
+// If the context gets cancelled while iterating over the rows, Load returns
+// the number of already processed rows and an *ErrPartialResult. The same
+// applies to an errors.Exceeded if the query returns more rows than
+// QueryOptions.MaxRows. The rows get closed on each return.
 func (a *DBR) Load(ctx context.Context, s ColumnMapper, args ...interface{}) (rowCount uint64, err error) {
+	return a.load(ctx, s, nil, args)
+}
+
+// load implements Load and appends, if cr is not nil, the scanned rows to cr.
+func (a *DBR) load(ctx context.Context, s ColumnMapper, cr *cachedResult, args []interface{}) (rowCount uint64, err error) {
 	if a.log != nil && a.log.IsDebug() {
 		defer log.WhenDone(a.log).Debug("Load", log.String("id", a.cachedSQL.id), log.Err(err), log.ObjectTypeOf("ColumnMapper", s), log.Uint64("row_count", rowCount))
 	}
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() { sq.observe(a, start, args, -1, int64(rowCount), err) }()
+	}
 
 	r, err := a.query(ctx, args)
 	if err != nil {
@@ -1084,6 +1313,7 @@ func (a *DBR) Load(ctx context.Context, s ColumnMapper, args ...interface{}) (ro
 		return
 	}
 	cm := pooledColumnMapGet()
+	a.initColumnMap(ctx, cm)
 	defer pooledBufferColumnMapPut(cm, nil, func() {
 		// Not testable with the sqlmock package :-(
 		if err2 := r.Close(); err2 != nil && err == nil {
@@ -1096,16 +1326,27 @@ func (a *DBR) Load(ctx context.Context, s ColumnMapper, args ...interface{}) (ro
 		}
 	})
 
+	g := newRowGuard(ctx)
 	for r.Next() {
+		if err = g.next(); err != nil {
+			return g.rows, err
+		}
 		if err = cm.Scan(r); err != nil {
 			return 0, errors.WithStack(err)
 		}
-		if err = s.MapColumns(cm); err != nil {
+		if cr != nil {
+			cr.appendRow(cm)
+		}
+		if err = cm.mapColumns(s); err != nil {
 			return 0, errors.Wrapf(err, "[dml] DBR.Load failed with queryID %q and ColumnMapper %T", a.cachedSQL.id, s)
 		}
+		g.rows++
 	}
-	if err = r.Err(); err != nil {
-		return 0, errors.WithStack(err)
+	if err = g.wrap(r.Err()); err != nil {
+		if isPartial(err) {
+			return g.rows, err
+		}
+		return 0, err
 	}
 	if cm.HasRows {
 		cm.Count++ // because first row is zero but we want the actual row number
@@ -1158,11 +1399,24 @@ func (a *DBR) LoadDecimal(ctx context.Context, args ...interface{}) (nv null.Dec
 	return
 }
 
+// loadPrimitive scans the first row into ptr. The context gets checked before
+// each row and if QueryOptions.MaxRows has been set, all rows get counted to
+// return an errors.Exceeded.
 func (a *DBR) loadPrimitive(ctx context.Context, ptr interface{}, args ...interface{}) (found bool, err error) {
 	if a.log != nil && a.log.IsDebug() {
 		// do not use fullSQL because we might log sensitive data
 		defer log.WhenDone(a.log).Debug("LoadPrimitive", log.String("id", a.cachedSQL.id), log.Err(err), log.ObjectTypeOf("ptr_type", ptr))
 	}
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() {
+			var rowCount int64
+			if found {
+				rowCount = 1
+			}
+			sq.observe(a, start, args, -1, rowCount, err)
+		}()
+	}
 	var rows *sql.Rows
 	rows, err = a.query(ctx, args)
 	if err != nil {
@@ -1174,26 +1428,40 @@ func (a *DBR) loadPrimitive(ctx context.Context, ptr interface{}, args ...interf
 		}
 	}()
 
-	for rows.Next() && !found {
-		if err = rows.Scan(ptr); err != nil {
-			return false, errors.WithStack(err)
+	g := newRowGuard(ctx)
+	for rows.Next() {
+		if err = g.next(); err != nil {
+			return found, err
+		}
+		if !found {
+			if err = rows.Scan(ptr); err != nil {
+				return false, errors.WithStack(err)
+			}
+			found = true
+		}
+		g.rows++
+		if g.maxRows == 0 {
+			break // without MaxRows the remaining rows do not matter
 		}
-		found = true
-	}
-	if err = rows.Err(); err != nil {
-		err = errors.WithStack(err)
 	}
+	err = g.wrap(rows.Err())
 	return
 }
 
 // LoadInt64s executes the query and returns the values appended to slice
-// dest. It ignores and skips NULL values.
+// dest. It ignores and skips NULL values. If the context gets cancelled or
+// QueryOptions.MaxRows exceeded, it returns the already appended values and
+// an *ErrPartialResult or an errors.Exceeded.
 func (a *DBR) LoadInt64s(ctx context.Context, dest []int64, args ...interface{}) (_ []int64, err error) {
 	var rowCount int
 	if a.log != nil && a.log.IsDebug() {
 		// do not use fullSQL because we might log sensitive data
 		defer log.WhenDone(a.log).Debug("LoadInt64s", log.Int("row_count", rowCount), log.Err(err))
 	}
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() { sq.observe(a, start, args, -1, -1, err) }()
+	}
 	var r *sql.Rows
 	r, err = a.query(ctx, args)
 	if err != nil {
@@ -1204,7 +1472,11 @@ func (a *DBR) LoadInt64s(ctx context.Context, dest []int64, args ...interface{})
 			err = errors.WithStack(cErr)
 		}
 	}()
+	g := newRowGuard(ctx)
 	for r.Next() {
+		if err = g.next(); err != nil {
+			return dest, err
+		}
 		var nv sql.RawBytes
 		if err = r.Scan(&nv); err != nil {
 			return nil, errors.WithStack(err)
@@ -1214,9 +1486,13 @@ func (a *DBR) LoadInt64s(ctx context.Context, dest []int64, args ...interface{})
 		} else if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		g.rows++
 	}
-	if err = r.Err(); err != nil {
-		return nil, errors.WithStack(err)
+	if err = g.wrap(r.Err()); err != nil {
+		if isPartial(err) {
+			return dest, err
+		}
+		return nil, err
 	}
 
 	rowCount = len(dest)
@@ -1224,13 +1500,19 @@ func (a *DBR) LoadInt64s(ctx context.Context, dest []int64, args ...interface{})
 }
 
 // LoadUint64s executes the query and returns the values appended to slice
-// dest. It ignores and skips NULL values.
+// dest. It ignores and skips NULL values. If the context gets cancelled or
+// QueryOptions.MaxRows exceeded, it returns the already appended values and
+// an *ErrPartialResult or an errors.Exceeded.
 func (a *DBR) LoadUint64s(ctx context.Context, dest []uint64, args ...interface{}) (_ []uint64, err error) {
 	var rowCount int
 	if a.log != nil && a.log.IsDebug() {
 		// do not use fullSQL because we might log sensitive data
 		defer log.WhenDone(a.log).Debug("LoadUint64s", log.Int("row_count", rowCount), log.String("id", a.cachedSQL.id), log.Err(err))
 	}
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() { sq.observe(a, start, args, -1, -1, err) }()
+	}
 
 	rows, err := a.query(ctx, args)
 	if err != nil {
@@ -1242,7 +1524,11 @@ func (a *DBR) LoadUint64s(ctx context.Context, dest []uint64, args ...interface{
 		}
 	}()
 
+	g := newRowGuard(ctx)
 	for rows.Next() {
+		if err = g.next(); err != nil {
+			return dest, err
+		}
 		var nv sql.RawBytes
 		if err = rows.Scan(&nv); err != nil {
 			return nil, errors.WithStack(err)
@@ -1252,21 +1538,31 @@ func (a *DBR) LoadUint64s(ctx context.Context, dest []uint64, args ...interface{
 		} else if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		g.rows++
 	}
-	if err = rows.Err(); err != nil {
-		return nil, errors.WithStack(err)
+	if err = g.wrap(rows.Err()); err != nil {
+		if isPartial(err) {
+			return dest, err
+		}
+		return nil, err
 	}
 	rowCount = len(dest)
 	return dest, err
 }
 
 // LoadFloat64s executes the query and returns the values appended to slice
-// dest. It ignores and skips NULL values.
+// dest. It ignores and skips NULL values. If the context gets cancelled or
+// QueryOptions.MaxRows exceeded, it returns the already appended values and
+// an *ErrPartialResult or an errors.Exceeded.
 func (a *DBR) LoadFloat64s(ctx context.Context, dest []float64, args ...interface{}) (_ []float64, err error) {
 	if a.log != nil && a.log.IsDebug() {
 		// do not use fullSQL because we might log sensitive data
 		defer log.WhenDone(a.log).Debug("LoadFloat64s", log.String("id", a.cachedSQL.id), log.Err(err))
 	}
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() { sq.observe(a, start, args, -1, -1, err) }()
+	}
 
 	var rows *sql.Rows
 	if rows, err = a.query(ctx, args); err != nil {
@@ -1278,7 +1574,11 @@ func (a *DBR) LoadFloat64s(ctx context.Context, dest []float64, args ...interfac
 		}
 	}()
 
+	g := newRowGuard(ctx)
 	for rows.Next() {
+		if err = g.next(); err != nil {
+			return dest, err
+		}
 		var nv sql.RawBytes
 		if err = rows.Scan(&nv); err != nil {
 			return nil, errors.WithStack(err)
@@ -1288,21 +1588,31 @@ func (a *DBR) LoadFloat64s(ctx context.Context, dest []float64, args ...interfac
 		} else if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		g.rows++
 	}
-	if err = rows.Err(); err != nil {
-		return nil, errors.WithStack(err)
+	if err = g.wrap(rows.Err()); err != nil {
+		if isPartial(err) {
+			return dest, err
+		}
+		return nil, err
 	}
 	return dest, err
 }
 
 // LoadStrings executes the query and returns the values appended to slice
-// dest. It ignores and skips NULL values.
+// dest. It ignores and skips NULL values. If the context gets cancelled or
+// QueryOptions.MaxRows exceeded, it returns the already appended values and
+// an *ErrPartialResult or an errors.Exceeded.
 func (a *DBR) LoadStrings(ctx context.Context, dest []string, args ...interface{}) (_ []string, err error) {
 	var rowCount int
 	if a.log != nil && a.log.IsDebug() {
 		// do not use fullSQL because we might log sensitive data
 		defer log.WhenDone(a.log).Debug("LoadStrings", log.Int("row_count", rowCount), log.String("id", a.cachedSQL.id), log.Err(err))
 	}
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() { sq.observe(a, start, args, -1, -1, err) }()
+	}
 
 	rows, err := a.query(ctx, args)
 	if err != nil {
@@ -1314,7 +1624,11 @@ func (a *DBR) LoadStrings(ctx context.Context, dest []string, args ...interface{
 		}
 	}()
 
+	g := newRowGuard(ctx)
 	for rows.Next() {
+		if err = g.next(); err != nil {
+			return dest, err
+		}
 		var value sql.RawBytes
 		if err = rows.Scan(&value); err != nil {
 			return nil, errors.WithStack(err)
@@ -1322,37 +1636,88 @@ func (a *DBR) LoadStrings(ctx context.Context, dest []string, args ...interface{
 		if value != nil {
 			dest = append(dest, string(value))
 		}
+		g.rows++
 	}
-	if err = rows.Err(); err != nil {
-		return nil, errors.WithStack(err)
+	if err = g.wrap(rows.Err()); err != nil {
+		if isPartial(err) {
+			return dest, err
+		}
+		return nil, err
 	}
 	rowCount = len(dest)
 	return dest, err
 }
 
 func (a *DBR) query(ctx context.Context, args []interface{}) (rows *sql.Rows, err error) {
-	sqlStr, args, err := a.prepareQueryAndArgs(args)
+	if a.skipTimestamps(ctx) {
+		return a.withoutTimestamps().query(ctx, args)
+	}
+	if a.listenersEnabled(ctx) {
+		rawArgs := args
+		if err = a.dispatchEvent(ctx, false, rawArgs, nil); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer func() {
+			if errL := a.dispatchEvent(ctx, true, rawArgs, err); errL != nil && err == nil {
+				_ = rows.Close()
+				rows, err = nil, errL
+			}
+		}()
+	}
+	sqlStr, preparedArgs, err := a.prepareQueryAndArgs(args)
 	if a.log != nil && a.log.IsDebug() {
 		defer log.WhenDone(a.log).Debug(
-			"Query", log.String("sql", sqlStr), log.Int("length_args", len(args)), log.String("source", string(a.cachedSQL.source)), log.Err(err))
+			"Query", log.String("sql", a.redactSQL(sqlStr, args)), log.Int("length_args", len(preparedArgs)), log.String("source", string(a.cachedSQL.source)), log.Err(err))
 	}
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	rows, err = a.DB.QueryContext(ctx, sqlStr, args...)
+	rows, err = a.DB.QueryContext(ctx, a.queryComment.prefix(ctx, sqlStr), preparedArgs...)
 	if err != nil {
 		if sqlStr == "" {
 			sqlStr = "PREPARED:" + a.cachedSQL.rawSQL
 		}
-		return nil, errors.Wrapf(err, "[dml] Query.QueryContext with query %q", sqlStr)
+		return nil, errors.Wrapf(err, "[dml] Query.QueryContext with query %q", a.redactSQL(sqlStr, args))
 	}
 	return rows, err
 }
 
 func (a *DBR) exec(ctx context.Context, rawArgs []interface{}) (result sql.Result, err error) {
+	if a.skipTimestamps(ctx) {
+		return a.withoutTimestamps().exec(ctx, rawArgs)
+	}
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() {
+			rowsAffected := int64(-1)
+			if result != nil {
+				if ra, errRA := result.RowsAffected(); errRA == nil {
+					rowsAffected = ra
+				}
+			}
+			sq.observe(a, start, rawArgs, rowsAffected, -1, err)
+		}()
+	}
+	if a.listenersEnabled(ctx) {
+		if err = a.dispatchEvent(ctx, false, rawArgs, nil); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer func() {
+			if errL := a.dispatchEvent(ctx, true, rawArgs, err); errL != nil && err == nil {
+				result, err = nil, errL
+			}
+		}()
+	}
+	if a.capturesGTID() {
+		defer func() {
+			if err == nil && result != nil {
+				result = a.captureGTID(ctx, result)
+			}
+		}()
+	}
 	sqlStr, args, err := a.prepareQueryAndArgs(rawArgs)
 	if a.log != nil && a.log.IsDebug() {
-		defer log.WhenDone(a.log).Debug("Exec", log.String("sql", sqlStr),
+		defer log.WhenDone(a.log).Debug("Exec", log.String("sql", a.redactSQL(sqlStr, rawArgs)),
 			log.Int("length_args", len(args)), log.Int("length_raw_args", len(rawArgs)), log.String("source", string(a.cachedSQL.source)),
 			log.Err(err))
 	}
@@ -1360,9 +1725,14 @@ func (a *DBR) exec(ctx context.Context, rawArgs []interface{}) (result sql.Resul
 		return nil, errors.WithStack(err)
 	}
 
-	result, err = a.DB.ExecContext(ctx, sqlStr, args...)
+	result, err = a.DB.ExecContext(ctx, a.queryComment.prefix(ctx, sqlStr), args...)
 	if err != nil {
-		return nil, errors.Wrapf(err, "[dml] ExecContext with query %q", sqlStr) // err gets catched by the defer
+		return nil, errors.Wrapf(err, "[dml] ExecContext with query %q", a.redactSQL(sqlStr, rawArgs)) // err gets catched by the defer
+	}
+	if a.cachedSQL.optimisticLock != "" {
+		if ra, errRA := result.RowsAffected(); errRA == nil && ra == 0 {
+			return nil, a.staleObjectError(ctx, rawArgs)
+		}
 	}
 	lID, err := result.LastInsertId()
 	if err != nil {
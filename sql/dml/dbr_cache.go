@@ -0,0 +1,364 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+)
+
+// ResultCacher stores the results of DBR.LoadCached. *objcache.Service
+// implements this interface. Get must leave dst untouched or call its
+// Unmarshal function with empty data if the key cannot be found. Set must call
+// the Marshal function of src.
+type ResultCacher interface {
+	Get(ctx context.Context, key string, dst interface{}) error
+	Set(ctx context.Context, key string, src interface{}, expires time.Duration) error
+}
+
+const (
+	resultCachePrefix         = "dml_rc_"
+	resultCacheGenPrefix      = "dml_rc_gen_"
+	cachedResultVersion  byte = 1
+)
+
+// LoadCached same as Load but the rows get read from the ResultCacher `rc`. On
+// a cache miss the query runs and the rows get stored with the expiration
+// `ttl`. The cached rows contain the column values as returned by the driver,
+// hence every ColumnMapper can be used as destination. The cache key consists
+// of the SQL string, the arguments and the generations of the table and of the
+// joined tables, see InvalidateTable. Tables referenced in sub queries, derived
+// tables or expressions are not part of the key, hence writing to them does not
+// invalidate the cached result until its `ttl` expires. The option
+// QueryOptions.SkipResultCache of the context bypasses the cache.
+func (a *DBR) LoadCached(ctx context.Context, rc ResultCacher, ttl time.Duration, s ColumnMapper, args ...interface{}) (rowCount uint64, hit bool, err error) {
+	if FromContextQueryOptions(ctx).SkipResultCache {
+		rowCount, err = a.Load(ctx, s, args...)
+		return rowCount, false, err
+	}
+	if a.log != nil && a.log.IsDebug() {
+		defer func() {
+			a.log.Debug("LoadCached", log.String("id", a.cachedSQL.id), log.Bool("hit", hit), log.Uint64("row_count", rowCount), log.Err(err))
+		}()
+	}
+
+	key, err := a.resultCacheKey(ctx, rc, args)
+	if err != nil {
+		return 0, false, errors.WithStack(err)
+	}
+
+	cr := new(cachedResult)
+	if err = rc.Get(ctx, key, cr); err != nil && !errors.NotFound.Match(err) {
+		return 0, false, errors.Wrapf(err, "[dml] DBR.LoadCached failed to get key %q with queryID %q", key, a.cachedSQL.id)
+	}
+	if cr.found {
+		rowCount, err = cr.mapColumns(s, func(cm *ColumnMap) { a.initColumnMap(ctx, cm) })
+		return rowCount, true, errors.WithStack(err)
+	}
+
+	if rowCount, err = a.load(ctx, s, cr, args); err != nil {
+		if isPartial(err) {
+			return rowCount, false, err // a partial result must not be cached
+		}
+		return 0, false, errors.WithStack(err)
+	}
+	if err = rc.Set(ctx, key, cr, ttl); err != nil {
+		return 0, false, errors.Wrapf(err, "[dml] DBR.LoadCached failed to set key %q with queryID %q", key, a.cachedSQL.id)
+	}
+	return rowCount, false, nil
+}
+
+// resultCacheKey hashes the final SQL string, its arguments and the
+// generations of the joined tables and prefixes it with the table name and its
+// current generation.
+func (a *DBR) resultCacheKey(ctx context.Context, rc ResultCacher, args []interface{}) (string, error) {
+	sqlStr, qArgs, err := a.prepareQueryAndArgs(args)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if sqlStr == "" { // prepared statement
+		sqlStr = a.cachedSQL.rawSQL
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(sqlStr))
+	for _, arg := range qArgs {
+		if v, ok := arg.(driver.Valuer); ok {
+			if arg, err = v.Value(); err != nil {
+				return "", errors.WithStack(err)
+			}
+		}
+		_, _ = fmt.Fprintf(h, "\x00%T:%v", arg, arg)
+	}
+
+	for _, jt := range a.cachedSQL.joinedTables {
+		gen, err := loadTableGeneration(ctx, rc, jt)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		_, _ = fmt.Fprintf(h, "\x00%s:%s", jt, gen)
+	}
+
+	table := a.cachedSQL.table
+	var gen tableGeneration
+	if table != "" {
+		if gen, err = loadTableGeneration(ctx, rc, table); err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+	return resultCachePrefix + table + "_" + string(gen) + "_" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func loadTableGeneration(ctx context.Context, rc ResultCacher, table string) (gen tableGeneration, err error) {
+	if err = rc.Get(ctx, resultCacheGenPrefix+table, &gen); err != nil && !errors.NotFound.Match(err) {
+		return "", errors.Wrapf(err, "[dml] Failed to get the generation of table %q", table)
+	}
+	return gen, nil
+}
+
+// InvalidateTable invalidates all results of DBR.LoadCached which have been
+// loaded from or joined with table `table` by setting a new generation of the
+// table. The previous entries expire according to their TTL. Should be called
+// after writing to the table.
+func InvalidateTable(ctx context.Context, rc ResultCacher, table string) error {
+	gen := tableGeneration(strconv.FormatInt(now().UnixNano(), 36))
+	return errors.WithStack(rc.Set(ctx, resultCacheGenPrefix+table, gen, 0))
+}
+
+// tableGeneration gets embedded into the cache keys of a table.
+type tableGeneration string
+
+func (tg tableGeneration) Marshal() ([]byte, error) { return []byte(tg), nil }
+
+func (tg *tableGeneration) Unmarshal(data []byte) error {
+	*tg = tableGeneration(data)
+	return nil
+}
+
+// cachedResult contains the columns and the scanned values of all rows. The
+// binary format starts with a version byte followed by the columns and the
+// rows. Each value gets encoded with its field type of scannedColumn.
+type cachedResult struct {
+	found   bool
+	columns []string
+	values  []scannedColumn // len(columns) values per row
+}
+
+// appendRow copies the current row of cm because the byte slices are only
+// valid until the next call to Scan.
+func (cr *cachedResult) appendRow(cm *ColumnMap) {
+	if cr.columns == nil {
+		cr.columns = append([]string{}, cm.columns...)
+	}
+	for _, sc := range cm.scanCol {
+		if sc.field == 'y' {
+			sc.byte = append([]byte{}, sc.byte...)
+		}
+		cr.values = append(cr.values, sc)
+	}
+}
+
+// mapColumns passes the cached rows to s, same as DBR.Load.
+func (cr *cachedResult) mapColumns(s ColumnMapper, initCM func(*ColumnMap)) (rowCount uint64, err error) {
+	cm := pooledColumnMapGet()
+	initCM(cm)
+	defer pooledBufferColumnMapPut(cm, nil, func() {
+		if rc, ok := s.(ioCloser); ok {
+			if err2 := rc.Close(); err2 != nil && err == nil {
+				err = errors.Wrap(err2, "[dml] DBR.LoadCached.ColumnMapper.Close")
+			}
+		}
+	})
+	if len(cr.columns) == 0 {
+		return 0, nil
+	}
+	cm.initScan(cr.columns)
+	for i := 0; i < len(cr.values); i += len(cr.columns) {
+		copy(cm.scanCol, cr.values[i:i+len(cr.columns)])
+		cm.Count = rowCount
+		if err = cm.mapColumns(s); err != nil {
+			return 0, errors.Wrapf(err, "[dml] DBR.LoadCached failed with ColumnMapper %T", s)
+		}
+		rowCount++
+	}
+	return rowCount, nil
+}
+
+// Marshal encodes the cached result.
+func (cr *cachedResult) Marshal() ([]byte, error) {
+	buf := make([]byte, 0, 64+len(cr.values)*8)
+	buf = append(buf, cachedResultVersion)
+	buf = appendUvarint(buf, uint64(len(cr.columns)))
+	for _, c := range cr.columns {
+		buf = appendCachedBytes(buf, []byte(c))
+	}
+	for _, v := range cr.values {
+		buf = append(buf, v.field)
+		switch v.field {
+		case 'i':
+			var vb [binary.MaxVarintLen64]byte
+			buf = append(buf, vb[:binary.PutVarint(vb[:], v.int64)]...)
+		case 'f':
+			var fb [8]byte
+			binary.LittleEndian.PutUint64(fb[:], math.Float64bits(v.float64))
+			buf = append(buf, fb[:]...)
+		case 'b':
+			var b byte
+			if v.bool {
+				b = 1
+			}
+			buf = append(buf, b)
+		case 'y':
+			buf = appendCachedBytes(buf, v.byte)
+		case 's':
+			buf = appendCachedBytes(buf, []byte(v.string))
+		case 't':
+			tb, err := v.time.MarshalBinary()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			buf = appendCachedBytes(buf, tb)
+		case 'n':
+		default:
+			return nil, errors.NotSupported.Newf("[dml] Cached result does not support field type %q", v.field)
+		}
+	}
+	return buf, nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var vb [binary.MaxVarintLen64]byte
+	return append(buf, vb[:binary.PutUvarint(vb[:], v)]...)
+}
+
+func appendCachedBytes(buf, b []byte) []byte {
+	return append(appendUvarint(buf, uint64(len(b))), b...)
+}
+
+// Unmarshal decodes the cached result. Empty data indicates a cache miss.
+func (cr *cachedResult) Unmarshal(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if data[0] != cachedResultVersion {
+		return errors.NotValid.Newf("[dml] Cached result has an unknown version %d", data[0])
+	}
+	d := cachedResultDecoder{data: data[1:]}
+	colCount := d.uvarint()
+	if colCount > uint64(len(d.data)) {
+		return errors.NotValid.Newf("[dml] Cached result contains an invalid column count %d", colCount)
+	}
+	cols := make([]string, colCount)
+	for i := range cols {
+		cols[i] = string(d.bytes())
+	}
+	var values []scannedColumn
+	for d.err == nil && len(d.data) > 0 && len(cols) > 0 {
+		var v scannedColumn
+		v.field = d.data[0]
+		d.data = d.data[1:]
+		switch v.field {
+		case 'i':
+			v.int64 = d.varint()
+		case 'f':
+			v.float64 = math.Float64frombits(binary.LittleEndian.Uint64(d.next(8)))
+		case 'b':
+			v.bool = d.next(1)[0] == 1
+		case 'y':
+			v.byte = d.bytes()
+		case 's':
+			v.string = string(d.bytes())
+		case 't':
+			if tb := d.bytes(); d.err == nil {
+				d.err = v.time.UnmarshalBinary(tb)
+			}
+		case 'n':
+		default:
+			d.err = errors.NotSupported.Newf("[dml] Cached result does not support field type %q", v.field)
+		}
+		values = append(values, v)
+	}
+	switch {
+	case d.err != nil:
+		return errors.WithStack(d.err)
+	case len(cols) > 0 && len(values)%len(cols) != 0:
+		return errors.NotValid.Newf("[dml] Cached result contains %d values for %d columns", len(values), len(cols))
+	}
+	cr.found = true
+	cr.columns = cols
+	cr.values = values
+	return nil
+}
+
+// cachedResultDecoder reads from data until the first error occurs. On error
+// the functions return zero values.
+type cachedResultDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *cachedResultDecoder) fail() {
+	if d.err == nil {
+		d.err = errors.NotValid.Newf("[dml] Cached result is too short")
+	}
+}
+
+func (d *cachedResultDecoder) next(n int) []byte {
+	if d.err != nil || n > len(d.data) {
+		d.fail()
+		return make([]byte, n)
+	}
+	b := d.data[:n:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *cachedResultDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if d.err != nil || n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *cachedResultDecoder) varint() int64 {
+	v, n := binary.Varint(d.data)
+	if d.err != nil || n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *cachedResultDecoder) bytes() []byte {
+	l := d.uvarint()
+	if d.err != nil || l > uint64(len(d.data)) {
+		d.fail()
+		return nil
+	}
+	return d.next(int(l))
+}
@@ -0,0 +1,125 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// mapResultCache behaves like objcache.Service with a codec-less storage.
+type mapResultCache map[string][]byte
+
+func (m mapResultCache) Get(_ context.Context, key string, dst interface{}) error {
+	return dst.(interface{ Unmarshal([]byte) error }).Unmarshal(m[key])
+}
+
+func (m mapResultCache) Set(_ context.Context, key string, src interface{}, _ time.Duration) (err error) {
+	m[key], err = src.(interface{ Marshal() ([]byte, error) }).Marshal()
+	return err
+}
+
+func TestDBR_LoadCached(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	ctx := context.Background()
+	rc := mapResultCache{}
+	dbr := dbc.WithQueryBuilder(dml.NewSelect("*").From("core_config_data").Where(
+		dml.Column("scope").Equal().PlaceHolder(),
+	))
+	expectQuery := func(scope string) {
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT * FROM `core_config_data` WHERE (`scope` = ?)")).
+			WithArgs(scope).
+			WillReturnRows(dmltest.MustMockRows(dmltest.WithFile("testdata/core_config_data.csv")))
+	}
+
+	expectQuery("default")
+	ccd := &TableCoreConfigDataSlice{}
+	rowCount, hit, err := dbr.LoadCached(ctx, rc, time.Minute, ccd, "default")
+	assert.NoError(t, err)
+	assert.False(t, hit)
+	assert.Exactly(t, uint64(7), rowCount)
+	assert.Len(t, rc, 1)
+
+	cached := &TableCoreConfigDataSlice{}
+	rowCount, hit, err = dbr.LoadCached(ctx, rc, time.Minute, cached, "default")
+	assert.NoError(t, err)
+	assert.True(t, hit)
+	assert.Exactly(t, uint64(7), rowCount)
+	assert.Exactly(t, ccd.Data, cached.Data)
+	assert.False(t, cached.Data[5].Value.Valid, "NULL value must be restored")
+
+	t.Run("different arguments", func(t *testing.T) {
+		expectQuery("website")
+		_, hit, err := dbr.LoadCached(ctx, rc, time.Minute, &TableCoreConfigDataSlice{}, "website")
+		assert.NoError(t, err)
+		assert.False(t, hit)
+		assert.Len(t, rc, 2)
+	})
+
+	t.Run("skip result cache", func(t *testing.T) {
+		expectQuery("default")
+		ctx := dml.WithContextQueryOptions(ctx, dml.QueryOptions{SkipResultCache: true})
+		_, hit, err := dbr.LoadCached(ctx, rc, time.Minute, &TableCoreConfigDataSlice{}, "default")
+		assert.NoError(t, err)
+		assert.False(t, hit)
+	})
+
+	t.Run("invalidate table", func(t *testing.T) {
+		assert.NoError(t, dml.InvalidateTable(ctx, rc, "core_config_data"))
+		expectQuery("default")
+		_, hit, err := dbr.LoadCached(ctx, rc, time.Minute, &TableCoreConfigDataSlice{}, "default")
+		assert.NoError(t, err)
+		assert.False(t, hit)
+
+		_, hit, err = dbr.LoadCached(ctx, rc, time.Minute, &TableCoreConfigDataSlice{}, "default")
+		assert.NoError(t, err)
+		assert.True(t, hit)
+	})
+
+	t.Run("invalidate joined table", func(t *testing.T) {
+		dbrJoin := dbc.WithQueryBuilder(dml.NewSelect("ccd.*").FromAlias("core_config_data", "ccd").
+			Join(
+				dml.MakeIdentifier("store_website").Alias("sw"),
+				dml.Column("sw.website_id").Equal().Column("ccd.scope_id"),
+			).
+			Where(dml.Column("ccd.scope").Equal().PlaceHolder()))
+		expectJoin := func() {
+			dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `ccd`.* FROM `core_config_data` AS `ccd` INNER JOIN `store_website` AS `sw` ON (`sw`.`website_id` = `ccd`.`scope_id`) WHERE (`ccd`.`scope` = ?)")).
+				WithArgs("website").
+				WillReturnRows(dmltest.MustMockRows(dmltest.WithFile("testdata/core_config_data.csv")))
+		}
+
+		expectJoin()
+		_, hit, err := dbrJoin.LoadCached(ctx, rc, time.Minute, &TableCoreConfigDataSlice{}, "website")
+		assert.NoError(t, err)
+		assert.False(t, hit)
+		_, hit, err = dbrJoin.LoadCached(ctx, rc, time.Minute, &TableCoreConfigDataSlice{}, "website")
+		assert.NoError(t, err)
+		assert.True(t, hit)
+
+		assert.NoError(t, dml.InvalidateTable(ctx, rc, "store_website"))
+		expectJoin()
+		_, hit, err = dbrJoin.LoadCached(ctx, rc, time.Minute, &TableCoreConfigDataSlice{}, "website")
+		assert.NoError(t, err)
+		assert.False(t, hit)
+	})
+}
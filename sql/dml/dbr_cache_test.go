@@ -0,0 +1,60 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestCachedResult_MarshalUnmarshal(t *testing.T) {
+	in := &cachedResult{
+		columns: []string{"a", "b"},
+		values: []scannedColumn{
+			{field: 'i', int64: -4711}, {field: 'f', float64: 3.1415},
+			{field: 'b', bool: true}, {field: 'y', byte: []byte("raw")},
+			{field: 's', string: "text"}, {field: 't', time: time.Date(2020, 2, 29, 12, 13, 14, 123456000, time.UTC)},
+			{field: 'n'}, {field: 'y', byte: []byte{}},
+		},
+	}
+	data, err := in.Marshal()
+	assert.NoError(t, err)
+
+	out := new(cachedResult)
+	assert.NoError(t, out.Unmarshal(data))
+	assert.True(t, out.found)
+	assert.Exactly(t, in.columns, out.columns)
+	assert.Len(t, out.values, len(in.values))
+	for i, v := range in.values {
+		assert.Exactly(t, v.String(), out.values[i].String(), "Index %d", i)
+	}
+
+	t.Run("miss", func(t *testing.T) {
+		cr := new(cachedResult)
+		assert.NoError(t, cr.Unmarshal(nil))
+		assert.False(t, cr.found)
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		for _, d := range [][]byte{{9}, data[:len(data)-2], data[:5], {cachedResultVersion, 200}} {
+			cr := new(cachedResult)
+			assert.ErrorIsKind(t, errors.NotValid, cr.Unmarshal(d))
+			assert.False(t, cr.found)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/util/byteconv"
+)
+
+// KeepRawBytes disables the decoding of the column values in LoadRows and
+// ScanRows. The values get returned as delivered by the driver, mostly as
+// []byte.
+func (a *DBR) KeepRawBytes() *DBR {
+	a.keepRawBytes = true
+	return a
+}
+
+// LoadRows executes the query and returns each row as a map, keyed by the
+// column name. Useful for arbitrary queries where no ColumnMapper exists. The
+// values get decoded according to the column types into int64 (uint64 if too
+// large), float64, string, []byte, time.Time or nil. DECIMAL columns get
+// returned as string to keep the precision. Duplicate column names, e.g. of
+// joined tables, get a numeric suffix: id, id_2, id_3.
+func (a *DBR) LoadRows(ctx context.Context, args ...interface{}) (rows []map[string]interface{}, err error) {
+	err = a.scanRows(ctx, args, false, func(row map[string]interface{}) error {
+		rows = append(rows, row)
+		return nil
+	})
+	return rows, err
+}
+
+// ScanRows executes the query and calls fn for each row. Like LoadRows but
+// streams the rows. The map and the scan destinations get reused for each
+// row, so fn must copy the map if it wants to retain it.
+func (a *DBR) ScanRows(ctx context.Context, fn func(row map[string]interface{}) error, args ...interface{}) error {
+	return a.scanRows(ctx, args, true, fn)
+}
+
+func (a *DBR) scanRows(ctx context.Context, args []interface{}, reuseRow bool, fn func(map[string]interface{}) error) (err error) {
+	var rowCount int64
+	if a.log != nil && a.log.IsDebug() {
+		defer log.WhenDone(a.log).Debug("ScanRows", log.String("id", a.cachedSQL.id), log.Err(err))
+	}
+	if sq := a.slowQuery; sq != nil {
+		start := time.Now()
+		defer func() { sq.observe(a, start, args, -1, rowCount, err) }()
+	}
+
+	r, err := a.query(ctx, args)
+	if err != nil {
+		return errors.Wrapf(err, "[dml] DBR.ScanRows.QueryContext failed with queryID %q", a.cachedSQL.id)
+	}
+	defer func() {
+		if errC := r.Close(); errC != nil && err == nil {
+			err = errors.WithStack(errC)
+		}
+	}()
+
+	cts, err := r.ColumnTypes()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	names := uniqueColumnNames(cts)
+	values := make([]interface{}, len(cts))
+	dest := make([]interface{}, len(cts))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var row map[string]interface{}
+	g := newRowGuard(ctx)
+	for r.Next() {
+		if err = g.next(); err != nil {
+			return err
+		}
+		if err = r.Scan(dest...); err != nil {
+			return errors.WithStack(err)
+		}
+		if row == nil || !reuseRow {
+			row = make(map[string]interface{}, len(names))
+		}
+		for i, v := range values {
+			if !a.keepRawBytes {
+				if v, err = decodeColumnValue(cts[i], v); err != nil {
+					return errors.NotValid.New(err, "[dml] DBR.ScanRows failed to decode column %q with queryID %q", names[i], a.cachedSQL.id)
+				}
+			}
+			row[names[i]] = v
+		}
+		rowCount++
+		if err = fn(row); err != nil {
+			return errors.WithStack(err)
+		}
+		g.rows++
+	}
+	return g.wrap(r.Err())
+}
+
+// uniqueColumnNames appends a numeric suffix to duplicate column names.
+func uniqueColumnNames(cts []*sql.ColumnType) []string {
+	names := make([]string, len(cts))
+	seen := make(map[string]bool, len(cts))
+	for i, ct := range cts {
+		name := ct.Name()
+		for n := 2; seen[name]; n++ {
+			name = ct.Name() + "_" + strconv.Itoa(n)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// decodeColumnValue converts a []byte value into the Go type of the column.
+// All other types have already been decoded by the driver.
+func decodeColumnValue(ct *sql.ColumnType, v interface{}) (interface{}, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return v, nil
+	}
+	switch strings.TrimPrefix(ct.DatabaseTypeName(), "UNSIGNED ") {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "YEAR":
+		if i, _, err := byteconv.ParseInt(b); err == nil {
+			return i, nil
+		}
+		u, _, err := byteconv.ParseUint(b, 10, 64)
+		return u, errors.WithStack(err)
+	case "FLOAT", "DOUBLE", "REAL":
+		f, _, err := byteconv.ParseFloat(b)
+		return f, errors.WithStack(err)
+	case "DATE", "DATETIME", "TIMESTAMP":
+		s := string(b)
+		if strings.HasPrefix(s, "0000-00-00") {
+			return s, nil // zero dates cannot be represented by time.Time
+		}
+		layout := timeFormat
+		if len(s) == len("2006-01-02") {
+			layout = "2006-01-02"
+		}
+		t, err := time.Parse(layout, s)
+		return t, errors.WithStack(err)
+	case "BIT", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "GEOMETRY":
+		return b, nil
+	}
+	return string(b), nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func loadRowsMockRows() *sqlmock.Rows {
+	return sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("entity_id").OfType("INT", int64(0)),
+		sqlmock.NewColumn("email").OfType("VARCHAR", ""),
+		sqlmock.NewColumn("price").OfType("DECIMAL", ""),
+		sqlmock.NewColumn("weight").OfType("DOUBLE", float64(0)),
+		sqlmock.NewColumn("created_at").OfType("DATETIME", time.Time{}),
+		sqlmock.NewColumn("dob").OfType("DATE", time.Time{}),
+		sqlmock.NewColumn("hash").OfType("VARBINARY", []byte{}),
+		sqlmock.NewColumn("entity_id").OfType("BIGINT", int64(0)),
+		sqlmock.NewColumn("counter").OfType("BIGINT", int64(0)),
+	).
+		AddRow([]byte("1"), []byte("a@b.c"), []byte("12.3400"), []byte("1.5"), []byte("2021-04-05 06:07:08"), []byte("1980-02-03"), []byte{0xff, 0x00}, []byte("11"), []byte("18446744073709551615")).
+		AddRow(int64(2), nil, nil, float64(2.25), time.Date(2021, 4, 5, 6, 7, 8, 0, time.UTC), []byte("0000-00-00"), nil, int64(22), int64(-3))
+}
+
+func TestDBR_LoadRows(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("decoded", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(loadRowsMockRows())
+
+		rows, err := dbc.WithQueryBuilder(dml.QuerySQL("SELECT * FROM customer_entity ce JOIN sales_order so")).LoadRows(ctx)
+		assert.NoError(t, err)
+		assert.Exactly(t, []map[string]interface{}{
+			{
+				"entity_id": int64(1), "email": "a@b.c", "price": "12.3400", "weight": 1.5,
+				"created_at": time.Date(2021, 4, 5, 6, 7, 8, 0, time.UTC), "dob": time.Date(1980, 2, 3, 0, 0, 0, 0, time.UTC),
+				"hash": []byte{0xff, 0x00}, "entity_id_2": int64(11), "counter": uint64(18446744073709551615),
+			},
+			{
+				"entity_id": int64(2), "email": nil, "price": nil, "weight": 2.25,
+				"created_at": time.Date(2021, 4, 5, 6, 7, 8, 0, time.UTC), "dob": "0000-00-00",
+				"hash": nil, "entity_id_2": int64(22), "counter": int64(-3),
+			},
+		}, rows)
+	})
+
+	t.Run("raw bytes", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(loadRowsMockRows())
+
+		rows, err := dbc.WithQueryBuilder(dml.QuerySQL("SELECT * FROM customer_entity")).KeepRawBytes().LoadRows(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Exactly(t, []byte("12.3400"), rows[0]["price"])
+		assert.Exactly(t, []byte("2021-04-05 06:07:08"), rows[0]["created_at"])
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRowsWithColumnDefinition(
+			sqlmock.NewColumn("entity_id").OfType("INT", int64(0))).AddRow([]byte("x")))
+
+		_, err := dbc.WithQueryBuilder(dml.QuerySQL("SELECT entity_id FROM customer_entity")).LoadRows(ctx)
+		assert.True(t, errors.NotValid.Match(err), "%+v", err)
+		assert.Contains(t, err.Error(), `column "entity_id"`)
+	})
+}
+
+func TestDBR_ScanRows(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+	dbMock.ExpectQuery("SELECT").WillReturnRows(loadRowsMockRows())
+
+	var ids []interface{}
+	var previous map[string]interface{}
+	err := dbc.WithQueryBuilder(dml.QuerySQL("SELECT * FROM customer_entity")).ScanRows(context.Background(), func(row map[string]interface{}) error {
+		if previous != nil {
+			previous["reused"] = true
+			assert.Exactly(t, true, row["reused"], "map must be reused")
+		}
+		previous = row
+		ids = append(ids, row["entity_id"])
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Exactly(t, []interface{}{int64(1), int64(2)}, ids)
+}
@@ -0,0 +1,83 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/corestoreio/errors"
+)
+
+// ErrPartialResult gets returned by the Load and Iterate functions if the
+// context has been cancelled or its deadline exceeded while iterating over the
+// rows. The collection contains only the first Rows rows. The cause is the
+// error of the context.
+type ErrPartialResult struct {
+	Rows uint64
+	Err  error
+}
+
+func (e *ErrPartialResult) Error() string {
+	return fmt.Sprintf("[dml] Partial result after %d rows: %s", e.Rows, e.Err)
+}
+
+// Cause returns the error of the context, used by errors.Cause.
+func (e *ErrPartialResult) Cause() error { return e.Err }
+
+// Unwrap returns the error of the context, used by errors.Is.
+func (e *ErrPartialResult) Unwrap() error { return e.Err }
+
+// rowGuard aborts the iteration over the rows if the context is done or the
+// number of rows exceeds QueryOptions.MaxRows. Rows counts the processed rows
+// and must be incremented by the caller.
+type rowGuard struct {
+	ctx     context.Context
+	maxRows uint64
+	rows    uint64
+}
+
+func newRowGuard(ctx context.Context) rowGuard {
+	return rowGuard{ctx: ctx, maxRows: FromContextQueryOptions(ctx).MaxRows}
+}
+
+// next gets called before processing a row.
+func (g *rowGuard) next() error {
+	if err := g.ctx.Err(); err != nil {
+		return &ErrPartialResult{Rows: g.rows, Err: err}
+	}
+	if g.maxRows > 0 && g.rows >= g.maxRows {
+		return errors.Exceeded.Newf("[dml] The query returns more rows than QueryOptions.MaxRows %d", g.maxRows)
+	}
+	return nil
+}
+
+// wrap returns an *ErrPartialResult if err has been caused by the context,
+// for example the error of sql.Rows.Err.
+func (g *rowGuard) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errCtx := g.ctx.Err(); errCtx != nil {
+		return &ErrPartialResult{Rows: g.rows, Err: errCtx}
+	}
+	return errors.WithStack(err)
+}
+
+// isPartial reports whether err is an *ErrPartialResult.
+func isPartial(err error) bool {
+	_, ok := err.(*ErrPartialResult)
+	return ok
+}
@@ -0,0 +1,205 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// cancelAfterRows cancels the context after n rows and optionally sleeps for
+// each row to simulate a slow row source.
+type cancelAfterRows struct {
+	n      int
+	sleep  time.Duration
+	cancel context.CancelFunc
+	ids    []int64
+}
+
+func (ca *cancelAfterRows) MapColumns(cm *dml.ColumnMap) error {
+	var id int64
+	for cm.Next(1) {
+		cm.Int64(&id)
+	}
+	ca.ids = append(ca.ids, id)
+	if len(ca.ids) == ca.n && ca.cancel != nil {
+		ca.cancel()
+	}
+	time.Sleep(ca.sleep)
+	return cm.Err()
+}
+
+func mockIDRows(n int) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id"})
+	for i := 1; i <= n; i++ {
+		rows.AddRow(int64(i))
+	}
+	return rows
+}
+
+// assertNoLeaks waits until all goroutines started after goroutinesBefore
+// have been terminated and no connection is in use anymore, which would
+// indicate not closed rows.
+func assertNoLeaks(t *testing.T, db *sql.DB, goroutinesBefore int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		goroutines, inUse := runtime.NumGoroutine(), db.Stats().InUse
+		if goroutines <= goroutinesBefore && inUse == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Leak detected: %d goroutines (before %d) and %d connections in use", goroutines, goroutinesBefore, inUse)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDBR_Load_Cancellation(t *testing.T) {
+	t.Run("cancelled while iterating", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		goroutines := runtime.NumGoroutine()
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockIDRows(5)).RowsWillBeClosed()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ca := &cancelAfterRows{n: 2, cancel: cancel}
+		rowCount, err := dbc.WithQueryBuilder(dml.NewSelect("id").From("catalog_product")).Load(ctx, ca)
+		assert.Exactly(t, uint64(2), rowCount)
+		assert.Exactly(t, []int64{1, 2}, ca.ids)
+		pErr, ok := err.(*dml.ErrPartialResult)
+		assert.True(t, ok, "%+v", err)
+		assert.Exactly(t, uint64(2), pErr.Rows)
+		assert.Exactly(t, context.Canceled, errors.Cause(err))
+		assertNoLeaks(t, dbc.DB, goroutines)
+	})
+
+	t.Run("deadline with slow row source", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		goroutines := runtime.NumGoroutine()
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockIDRows(100)).RowsWillBeClosed()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+		ca := &cancelAfterRows{sleep: 10 * time.Millisecond}
+		rowCount, err := dbc.WithQueryBuilder(dml.NewSelect("id").From("catalog_product")).Load(ctx, ca)
+		assert.True(t, rowCount > 0 && rowCount < 100, "rowCount %d", rowCount)
+		assert.Exactly(t, int(rowCount), len(ca.ids))
+		assert.Exactly(t, context.DeadlineExceeded, errors.Cause(err))
+		assertNoLeaks(t, dbc.DB, goroutines)
+	})
+
+	t.Run("IterateSerial", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		goroutines := runtime.NumGoroutine()
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockIDRows(5)).RowsWillBeClosed()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var calls int
+		err := dbc.WithQueryBuilder(dml.NewSelect("id").From("catalog_product")).IterateSerial(ctx, func(cm *dml.ColumnMap) error {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			return nil
+		})
+		assert.Exactly(t, 3, calls)
+		assert.Exactly(t, uint64(3), err.(*dml.ErrPartialResult).Rows)
+		assertNoLeaks(t, dbc.DB, goroutines)
+	})
+}
+
+func TestQueryOptions_MaxRows(t *testing.T) {
+	ctx := dml.WithContextQueryOptions(context.Background(), dml.QueryOptions{MaxRows: 3})
+
+	t.Run("Load exceeded", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		goroutines := runtime.NumGoroutine()
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockIDRows(10)).RowsWillBeClosed()
+
+		ca := &cancelAfterRows{}
+		rowCount, err := dbc.WithQueryBuilder(dml.NewSelect("id").From("catalog_product")).Load(ctx, ca)
+		assert.ErrorIsKind(t, errors.Exceeded, err)
+		assert.Exactly(t, uint64(3), rowCount)
+		assert.Exactly(t, []int64{1, 2, 3}, ca.ids)
+		assertNoLeaks(t, dbc.DB, goroutines)
+	})
+
+	t.Run("Load within limit", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockIDRows(3))
+
+		rowCount, err := dbc.WithQueryBuilder(dml.NewSelect("id").From("catalog_product")).Load(ctx, &cancelAfterRows{})
+		assert.NoError(t, err)
+		assert.Exactly(t, uint64(3), rowCount)
+	})
+
+	t.Run("LoadInt64s exceeded", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockIDRows(4)).RowsWillBeClosed()
+
+		ids, err := dbc.WithQueryBuilder(dml.NewSelect("id").From("catalog_product")).LoadInt64s(ctx, nil)
+		assert.ErrorIsKind(t, errors.Exceeded, err)
+		assert.Exactly(t, []int64{1, 2, 3}, ids)
+	})
+
+	t.Run("LoadStrings within limit", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockIDRows(2))
+
+		ids, err := dbc.WithQueryBuilder(dml.NewSelect("id").From("catalog_product")).LoadStrings(ctx, nil)
+		assert.NoError(t, err)
+		assert.Exactly(t, []string{"1", "2"}, ids)
+	})
+
+	t.Run("LoadNullInt64 exceeded", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockIDRows(4)).RowsWillBeClosed()
+
+		id, found, err := dbc.WithQueryBuilder(dml.NewSelect("id").From("catalog_product")).LoadNullInt64(ctx)
+		assert.ErrorIsKind(t, errors.Exceeded, err)
+		assert.True(t, found)
+		assert.Exactly(t, int64(1), id.Int64)
+	})
+
+	t.Run("LoadNullInt64 within limit", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockIDRows(3))
+
+		id, found, err := dbc.WithQueryBuilder(dml.NewSelect("id").From("catalog_product")).LoadNullInt64(ctx)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Exactly(t, int64(1), id.Int64)
+	})
+}
@@ -221,7 +221,7 @@ func TestArguments_WriteTo(t *testing.T) {
 	})
 	t.Run("should panic because unknown field type", func(t *testing.T) {
 		var buf bytes.Buffer
-		assert.ErrorIsKind(t, errors.NotSupported, writeInterfaceValue(complex64(1), &buf, 0))
+		assert.ErrorIsKind(t, errors.NotSupported, writeInterfaceValue(dialect, complex64(1), &buf, 0))
 		assert.Empty(t, buf.String(), "buffer should be empty")
 	})
 }
@@ -128,6 +128,13 @@ func (b *Delete) Unsafe() *Delete {
 	return b
 }
 
+// WithDialect renders the SQL string in the syntax of dialect `d`. Queries
+// created via a ConnPool use the dialect of the ConnPool.
+func (b *Delete) WithDialect(d Dialect) *Delete {
+	b.dialect = d
+	return b
+}
+
 // Where appends a WHERE clause to the statement whereSQLOrMap can be a string
 // or map. If it'ab a string, args wil replaces any places holders.
 func (b *Delete) Where(wf ...*Condition) *Delete {
@@ -232,7 +239,7 @@ func (b *Delete) toSQL(w *bytes.Buffer, placeHolders []string) (_ []string, err
 	}
 
 	sqlWriteOrderBy(w, b.OrderBys, false)
-	sqlWriteLimitOffset(w, b.LimitValid, false, 0, b.LimitCount)
+	sqlWriteLimitOffset(b.sqlDialect(), w, b.LimitValid, false, 0, b.LimitCount)
 
 	if b.Returning != nil {
 		w.WriteString(" RETURNING ")
@@ -19,6 +19,8 @@ import (
 	"encoding/hex"
 	"strings"
 	"time"
+
+	"github.com/corestoreio/pkg/util/bufferpool"
 )
 
 const (
@@ -27,27 +29,76 @@ const (
 	namedArgStartByte   = ':'
 )
 
-var dialect dialecter = mysqlDialect{
-	identR: strings.NewReplacer("`", "``", ".", "`.`"),
-}
+// dialectNameMySQL defines the name of the default dialect. The builders
+// generate the SQL syntax of this dialect.
+const dialectNameMySQL = "mysql"
 
-// dialecter at an interface that wraps the diverse properties of individual
-// SQL drivers.
-type dialecter interface {
+var (
+	// DialectMySQL defines the default dialect for MySQL and MariaDB.
+	DialectMySQL Dialect = mysqlDialect{
+		identR: strings.NewReplacer("`", "``", ".", "`.`"),
+	}
+	// DialectPostgreSQL defines the dialect for PostgreSQL. Identifiers get
+	// quoted with double quotes, placeholders are written as $1, $2, ... and
+	// string literals follow the standard_conforming_strings rules. Booleans
+	// written inline by the builders, without interpolation, are still 1 or 0.
+	// MySQL only clauses like REPLACE, IGNORE, STRAIGHT_JOIN or a LIMIT in
+	// UPDATE and DELETE statements do not get translated. The question mark
+	// operators of jsonb conflict with the placeholder.
+	DialectPostgreSQL Dialect = postgresDialect{
+		identR: strings.NewReplacer(`"`, `""`, ".", `"."`),
+	}
+)
+
+// dialect gets used by all builders and interpolation functions without an
+// applied Dialect.
+var dialect = DialectMySQL
+
+// Dialect defines the SQL syntax of a database server. All builders generate
+// the syntax of MySQL. For all other dialects the final SQL string gets
+// translated: backtick quoted identifiers, string literals and placeholders get
+// rewritten with the functions of the dialect. Double quoted identifiers and
+// comments stay unchanged.
+type Dialect interface {
+	// Name returns the unique name of the dialect, e.g. mysql or postgres.
+	Name() string
 	EscapeIdent(w *bytes.Buffer, ident string)
 	EscapeBool(w *bytes.Buffer, b bool)
 	EscapeString(w *bytes.Buffer, s string)
 	EscapeTime(w *bytes.Buffer, t time.Time)
 	EscapeBinary(w *bytes.Buffer, b []byte)
-	ApplyLimitAndOffset(w *bytes.Buffer, limit, offset uint64)
+	// WritePlaceholder writes the placeholder for the argument at position
+	// `pos`. The first position is one.
+	WritePlaceholder(w *bytes.Buffer, pos int)
+	// ApplyLimitAndOffset writes the LIMIT clause including a leading space.
+	// The offset gets only written if withOffset is true.
+	ApplyLimitAndOffset(w *bytes.Buffer, limit, offset uint64, withOffset bool)
+	// WriteUpsert writes the clause which turns an INSERT into an update of the
+	// existing row, when a unique key gets violated. The SET pairs follow the
+	// clause. `conflictColumns` contains the columns of the unique key, see
+	// Insert.OnConflict.
+	WriteUpsert(w *bytes.Buffer, conflictColumns []string) error
+	// WriteUpsertValue writes the reference to the value of `column` which
+	// should have been inserted.
+	WriteUpsertValue(w *bytes.Buffer, column string)
+}
+
+// usesANSIQuotes returns true if the dialect quotes identifiers with double
+// quotes. Then the builder generated SQL must be translated.
+func usesANSIQuotes(d Dialect) bool {
+	return d != nil && d.Name() != dialectNameMySQL
 }
 
-const mysqlTimeFormat = "2006-01-02 15:04:05"
+// mysqlTimeFormat writes up to six fractional digits for DATETIME(6) and
+// TIMESTAMP(6) columns. Trailing zeros get removed.
+const mysqlTimeFormat = "2006-01-02 15:04:05.999999"
 
 type mysqlDialect struct {
 	identR *strings.Replacer
 }
 
+func (d mysqlDialect) Name() string { return dialectNameMySQL }
+
 func (d mysqlDialect) EscapeIdent(w *bytes.Buffer, ident string) {
 	w.WriteByte('`')
 	w.WriteString(d.identR.Replace(ident))
@@ -112,18 +163,26 @@ func (d mysqlDialect) EscapeTime(w *bytes.Buffer, t time.Time) {
 	w.WriteByte('\'')
 }
 
-func (d mysqlDialect) ApplyLimitAndOffset(w *bytes.Buffer, limit, offset uint64) {
+func (d mysqlDialect) ApplyLimitAndOffset(w *bytes.Buffer, limit, offset uint64, withOffset bool) {
 	w.WriteString(" LIMIT ")
-	if limit == 0 {
-		// In MYSQL, OFFSET cannot be used alone. Set the limit to the max possible value.
-		w.WriteString("18446744073709551615")
-	} else {
-		writeUint64(w, limit)
-	}
-	if offset > 0 {
-		w.WriteString(" OFFSET ")
+	if withOffset {
 		writeUint64(w, offset)
+		w.WriteByte(',')
 	}
+	writeUint64(w, limit)
+}
+
+func (d mysqlDialect) WritePlaceholder(w *bytes.Buffer, _ int) {
+	w.WriteByte(placeHolderRune)
+}
+
+func (d mysqlDialect) WriteUpsert(w *bytes.Buffer, _ []string) error {
+	_, err := w.Write(onDuplicateKeyPart)
+	return err
+}
+
+func (d mysqlDialect) WriteUpsertValue(w *bytes.Buffer, column string) {
+	writeSQLValues(w, column)
 }
 
 func cutNamedArgStartStr(s string) (string, bool) {
@@ -133,3 +192,140 @@ func cutNamedArgStartStr(s string) (string, bool) {
 	}
 	return s, false
 }
+
+// translateSQL writes the builder generated MySQL syntax in `sql` as the
+// syntax of dialect `d` into `w`. Backtick quoted identifiers get quoted with
+// the dialect. If withLiterals is true, the MySQL escaped string literals and
+// hexadecimal literals get written with the escape functions of the dialect,
+// otherwise string literals must already follow the rules of the dialect. If
+// withPlaceholders is true, the placeholders get numbered. Double quoted
+// identifiers and comments get copied.
+func translateSQL(d Dialect, w *bytes.Buffer, sql string, withLiterals, withPlaceholders bool) {
+	var phPos int
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '`':
+			j := i + 1
+			var ident []byte
+			for ; j < len(sql); j++ {
+				if sql[j] == '`' {
+					if j+1 < len(sql) && sql[j+1] == '`' {
+						j++
+					} else {
+						break
+					}
+				}
+				ident = append(ident, sql[j])
+			}
+			d.EscapeIdent(w, string(ident))
+			i = j
+		case c == '\'' && withLiterals:
+			var lit string
+			lit, i = unescapeMySQLString(sql, i)
+			d.EscapeString(w, lit)
+		case c == '\'', c == '"':
+			j := strings.IndexByte(sql[i+1:], c)
+			if j < 0 {
+				w.WriteString(sql[i:])
+				return
+			}
+			w.WriteString(sql[i : i+j+2])
+			i += j + 1
+		case c == '-' && strings.HasPrefix(sql[i:], "--"), c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := "\n"
+			if c == '/' {
+				end = "*/"
+			}
+			j := strings.Index(sql[i+2:], end)
+			if j < 0 {
+				w.WriteString(sql[i:])
+				return
+			}
+			j += i + 2 + len(end)
+			w.WriteString(sql[i:j])
+			i = j - 1
+		case c == placeHolderRune && withPlaceholders:
+			phPos++
+			d.WritePlaceholder(w, phPos)
+		case c == '0' && withLiterals && i+2 < len(sql) && (sql[i+1] == 'x' || sql[i+1] == 'X') && (i == 0 || !isIdentByte(sql[i-1])):
+			j := i + 2
+			for j < len(sql) && isHexByte(sql[j]) {
+				j++
+			}
+			b, err := hex.DecodeString(sql[i+2 : j])
+			if err != nil || j == i+2 || (j < len(sql) && isIdentByte(sql[j])) {
+				w.WriteByte(c) // not a hexadecimal literal
+				continue
+			}
+			d.EscapeBinary(w, b)
+			i = j - 1
+		default:
+			w.WriteByte(c)
+		}
+	}
+}
+
+// unescapeMySQLString decodes the single quoted string literal starting at
+// sql[start] and returns the index of the closing quote.
+func unescapeMySQLString(sql string, start int) (string, int) {
+	buf := make([]byte, 0, 16)
+	i := start + 1
+	for ; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' && i+1 < len(sql) && sql[i+1] == '\'':
+			buf = append(buf, c)
+			i++
+		case c == '\'':
+			return string(buf), i
+		case c == '\\' && i+1 < len(sql):
+			i++
+			switch c = sql[i]; c {
+			case '0':
+				c = 0
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'Z':
+				c = 0x1a
+			case 'x':
+				if i+2 < len(sql) {
+					if b, err := hex.DecodeString(sql[i+1 : i+3]); err == nil {
+						c = b[0]
+						i += 2
+					}
+				}
+			}
+			buf = append(buf, c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return string(buf), i
+}
+
+// rebindSQL translates the builder generated SQL into the syntax of `d` and
+// numbers the placeholders. The string literals must already follow the rules
+// of `d`. Returns `sql` for MySQL.
+func rebindSQL(d Dialect, sql string) string {
+	if !usesANSIQuotes(d) || sql == "" {
+		return sql
+	}
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	translateSQL(d, buf, sql, false, true)
+	return buf.String()
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isHexByte(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
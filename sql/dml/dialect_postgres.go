@@ -0,0 +1,124 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// postgresTimeFormat writes up to six fractional digits and the time zone
+// offset. A TIMESTAMP column without time zone ignores the offset.
+const postgresTimeFormat = "2006-01-02 15:04:05.999999-07:00"
+
+// onConflictPartS starts the upsert clause of PostgreSQL.
+const onConflictPartS = ` ON CONFLICT `
+
+type postgresDialect struct {
+	identR *strings.Replacer
+}
+
+func (d postgresDialect) Name() string { return "postgres" }
+
+func (d postgresDialect) EscapeIdent(w *bytes.Buffer, ident string) {
+	w.WriteByte('"')
+	w.WriteString(d.identR.Replace(ident))
+	w.WriteByte('"')
+}
+
+func (d postgresDialect) EscapeBool(w *bytes.Buffer, b bool) {
+	if b {
+		w.WriteString("TRUE")
+	} else {
+		w.WriteString("FALSE")
+	}
+}
+
+// EscapeString doubles the single quotes. Backslashes have no special meaning
+// since standard_conforming_strings defaults to on.
+func (d postgresDialect) EscapeString(w *bytes.Buffer, s string) {
+	w.WriteByte('\'')
+	for {
+		i := strings.IndexByte(s, '\'')
+		if i < 0 {
+			break
+		}
+		w.WriteString(s[:i+1])
+		w.WriteByte('\'')
+		s = s[i+1:]
+	}
+	w.WriteString(s)
+	w.WriteByte('\'')
+}
+
+func (d postgresDialect) EscapeTime(w *bytes.Buffer, t time.Time) {
+	w.WriteByte('\'')
+	b := w.Bytes()
+	w.Reset()
+	w.Write(t.AppendFormat(b, postgresTimeFormat))
+	w.WriteByte('\'')
+}
+
+func (d postgresDialect) EscapeBinary(w *bytes.Buffer, b []byte) {
+	if b == nil {
+		w.WriteString(sqlStrNullUC)
+		return
+	}
+	w.WriteString(`'\x`)
+	w.WriteString(hex.EncodeToString(b))
+	w.WriteString(`'::bytea`)
+}
+
+func (d postgresDialect) WritePlaceholder(w *bytes.Buffer, pos int) {
+	w.WriteByte('$')
+	w.WriteString(strconv.Itoa(pos))
+}
+
+func (d postgresDialect) ApplyLimitAndOffset(w *bytes.Buffer, limit, offset uint64, withOffset bool) {
+	w.WriteString(" LIMIT ")
+	writeUint64(w, limit)
+	if withOffset && offset > 0 {
+		w.WriteString(" OFFSET ")
+		writeUint64(w, offset)
+	}
+}
+
+// WriteUpsert requires the conflict target because PostgreSQL cannot infer
+// the unique key for DO UPDATE.
+func (d postgresDialect) WriteUpsert(w *bytes.Buffer, conflictColumns []string) error {
+	if len(conflictColumns) == 0 {
+		return errors.NotValid.Newf("[dml] PostgreSQL requires the conflict columns for ON CONFLICT DO UPDATE, see Insert.OnConflict")
+	}
+	w.WriteString(onConflictPartS)
+	w.WriteByte('(')
+	for i, c := range conflictColumns {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		d.EscapeIdent(w, c)
+	}
+	w.WriteString(") DO UPDATE SET ")
+	return nil
+}
+
+func (d postgresDialect) WriteUpsertValue(w *bytes.Buffer, column string) {
+	w.WriteString("EXCLUDED.")
+	d.EscapeIdent(w, column)
+}
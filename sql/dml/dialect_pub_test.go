@@ -0,0 +1,102 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestDialectPostgreSQL_ToSQL(t *testing.T) {
+	pg := dml.DialectPostgreSQL
+
+	t.Run("select", func(t *testing.T) {
+		compareToSQL(t,
+			dml.NewSelect("a", "b").FromAlias("tableA", "ta").
+				Where(dml.Column("ta.c").PlaceHolder(), dml.Column("d").Str("it's"), dml.Column("e").In().Int64s(1, 2)).
+				OrderBy("a").Limit(10, 20).WithDialect(pg),
+			errors.NoKind,
+			`SELECT "a", "b" FROM "tableA" AS "ta" WHERE ("ta"."c" = $1) AND ("d" = 'it''s') AND ("e" IN (1,2)) ORDER BY "a" LIMIT 20 OFFSET 10`, "",
+		)
+	})
+	t.Run("insert on conflict", func(t *testing.T) {
+		compareToSQL(t,
+			dml.NewInsert("tableA").AddColumns("id", "code", "name").SetRowCount(1).BuildValues().
+				OnConflict("id").AddOnDuplicateKeyExclude("id").WithDialect(pg),
+			errors.NoKind,
+			`INSERT INTO "tableA" ("id","code","name") VALUES ($1,$2,$3) ON CONFLICT ("id") DO UPDATE SET "code"=EXCLUDED."code", "name"=EXCLUDED."name"`, "",
+		)
+	})
+	t.Run("insert on conflict without target", func(t *testing.T) {
+		compareToSQL(t,
+			dml.NewInsert("tableA").AddColumns("id", "code").SetRowCount(1).OnDuplicateKey().WithDialect(pg),
+			errors.NotValid, "", "",
+		)
+	})
+	t.Run("update", func(t *testing.T) {
+		compareToSQL(t,
+			dml.NewUpdate("tableA").AddClauses(dml.Column("a").Bool(true), dml.Column("b").PlaceHolder()).
+				Where(dml.Column("c").Str(`C:\`)).WithDialect(pg),
+			errors.NoKind,
+			`UPDATE "tableA" SET "a"=1, "b"=$1 WHERE ("c" = 'C:\')`, "",
+		)
+	})
+	t.Run("delete", func(t *testing.T) {
+		compareToSQL(t,
+			dml.NewDelete("tableA").Where(dml.Column("a").PlaceHolder(), dml.Column("b").PlaceHolder()).WithDialect(pg),
+			errors.NoKind,
+			`DELETE FROM "tableA" WHERE ("a" = $1) AND ("b" = $2)`, "",
+		)
+	})
+}
+
+func TestWithDialect_ConnPool(t *testing.T) {
+	ctx := context.Background()
+	dbc, dbMock := dmltest.MockDB(t, dml.WithDialect(dml.DialectPostgreSQL))
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "core_store" ("code","is_active") VALUES ($1,$2),($3,$4)`)).
+		WithArgs("de", true, "at", false).WillReturnResult(sqlmock.NewResult(0, 2))
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "core_store" SET "name"='it''s' WHERE ("code" = 'de') AND ("is_active" = TRUE)`)).
+		WithArgs().WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT "code" FROM "core_store" WHERE ("store_id" IN ($1,$2)) ORDER BY "code" LIMIT 5`)).
+		WithArgs(1, 2).WillReturnRows(sqlmock.NewRows([]string{"code"}).AddRow("de"))
+	dbMock.ExpectPrepare(regexp.QuoteMeta(`DELETE FROM "core_store" WHERE ("code" = $1)`)).
+		ExpectExec().WithArgs("de").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := dbc.WithQueryBuilder(dml.NewInsert("core_store").AddColumns("code", "is_active")).
+		ExecContext(ctx, "de", true, "at", false)
+	assert.NoError(t, err)
+
+	_, err = dbc.WithQueryBuilder(dml.NewUpdate("core_store").AddClauses(dml.Column("name").PlaceHolder()).
+		Where(dml.Column("code").PlaceHolder(), dml.Column("is_active").PlaceHolder())).
+		Interpolate().ExecContext(ctx, "it's", "de", true)
+	assert.NoError(t, err)
+
+	var codes []string
+	_, err = dbc.WithQueryBuilder(dml.NewSelect("code").From("core_store").Where(dml.Column("store_id").In().PlaceHolder())).
+		ExpandPlaceHolders().OrderBy("code").Limit(0, 5).LoadStrings(ctx, codes, []int{1, 2})
+	assert.NoError(t, err)
+
+	_, err = dbc.WithPrepare(ctx, dml.NewDelete("core_store").Where(dml.Column("code").PlaceHolder())).ExecContext(ctx, "de")
+	assert.NoError(t, err)
+}
@@ -15,17 +15,23 @@
 package dml
 
 import (
+	"bytes"
 	"context"
 	"testing"
+	"time"
 
+	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
 	"github.com/corestoreio/pkg/util/naughtystrings"
 )
 
 // They both must be kept in sync
 var (
 	_ null.Dialecter = (*mysqlDialect)(nil)
-	_ dialecter      = (*mysqlDialect)(nil)
+	_ Dialect        = (*mysqlDialect)(nil)
+	_ null.Dialecter = (*postgresDialect)(nil)
+	_ Dialect        = (*postgresDialect)(nil)
 )
 
 func TestEscapeWith_NaughtyStrings(t *testing.T) {
@@ -48,3 +54,56 @@ func TestEscapeWith_NaughtyStrings(t *testing.T) {
 		sel.Wheres = sel.Wheres[:0]
 	}
 }
+
+func TestTranslateSQL(t *testing.T) {
+	tests := []struct {
+		sql              string
+		withLiterals     bool
+		withPlaceholders bool
+		want             string
+	}{
+		{"SELECT `a`.`b` FROM `t` AS `a` WHERE (`c` = ?)", false, true, `SELECT "a"."b" FROM "t" AS "a" WHERE ("c" = $1)`},
+		{"SELECT `a``b` FROM `t` WHERE `c` IN (?,?)", false, false, "SELECT \"a`b\" FROM \"t\" WHERE \"c\" IN (?,?)"},
+		{`SELECT 'it\'s ?', "x?" FROM t WHERE a = ?`, true, true, `SELECT 'it''s ?', "x?" FROM t WHERE a = $1`},
+		{`SELECT 'C:\\', '\0\n' FROM t`, true, false, "SELECT 'C:\\', '\x00\n' FROM t"},
+		{`SELECT 'it''s ?' FROM t WHERE a = ?`, false, true, `SELECT 'it''s ?' FROM t WHERE a = $1`},
+		{`SELECT 'C:\' FROM t WHERE a = ?`, false, true, `SELECT 'C:\' FROM t WHERE a = $1`},
+		{"/*$ID$a?*/SELECT 1 -- `x` ?\nFROM `t`", false, true, "/*$ID$a?*/SELECT 1 -- `x` ?\nFROM \"t\""},
+		{"SELECT 0x0a0B, a0x1, 0x, 0xZZ FROM t", true, false, `SELECT '\x0a0b'::bytea, a0x1, 0x, 0xZZ FROM t`},
+		{"SELECT 'unterminated ?", false, true, "SELECT 'unterminated ?"},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		translateSQL(DialectPostgreSQL, &buf, test.sql, test.withLiterals, test.withPlaceholders)
+		assert.Exactly(t, test.want, buf.String(), "Index %d", i)
+	}
+
+	assert.Exactly(t, "SELECT `a` FROM t WHERE b = ?", rebindSQL(DialectMySQL, "SELECT `a` FROM t WHERE b = ?"))
+}
+
+func TestPostgresDialect(t *testing.T) {
+	d := DialectPostgreSQL
+	var buf bytes.Buffer
+	d.EscapeIdent(&buf, `sch.ta"ble`)
+	buf.WriteByte(' ')
+	d.EscapeBool(&buf, true)
+	buf.WriteByte(' ')
+	d.EscapeString(&buf, `it's C:\`)
+	buf.WriteByte(' ')
+	d.EscapeTime(&buf, time.Date(2019, 1, 2, 3, 4, 5, 600000000, time.FixedZone("", 3600)))
+	buf.WriteByte(' ')
+	d.EscapeBinary(&buf, []byte{1, 255})
+	buf.WriteByte(' ')
+	d.WritePlaceholder(&buf, 12)
+	d.ApplyLimitAndOffset(&buf, 5, 0, true)
+	d.ApplyLimitAndOffset(&buf, 5, 10, true)
+	assert.Exactly(t, `"sch"."ta""ble" TRUE 'it''s C:\' '2019-01-02 03:04:05.6+01:00' '\x01ff'::bytea $12 LIMIT 5 LIMIT 5 OFFSET 10`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, d.WriteUpsert(&buf, []string{"id", "code"}))
+	d.WriteUpsertValue(&buf, "name")
+	assert.Exactly(t, ` ON CONFLICT ("id","code") DO UPDATE SET EXCLUDED."name"`, buf.String())
+
+	err := d.WriteUpsert(&buf, nil)
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
@@ -25,9 +25,20 @@ type ctxKeyQueryOption uint8
 
 // QueryOptions provides different options while executing code for SQL queries.
 type QueryOptions struct {
-	SkipEvents     bool // skips above defined EventFlag
-	SkipTimestamps bool // skips generating timestamps (TODO)
-	SkipRelations  bool // skips executing relation based SQL code
+	SkipEvents      bool // skips above defined EventFlag
+	SkipTimestamps  bool // skips the automatic timestamps, see WithTimestamps
+	SkipRelations   bool // skips executing relation based SQL code
+	WithDeleted     bool // includes soft deleted rows in generated SELECT queries
+	ForcePrimary    bool // routes read queries to the primary instead of a replica
+	SkipResultCache bool // bypasses the result cache in DBR.LoadCached
+	StrictColumnMap bool // verifies the ColumnMapper, see WithStrictColumnMap
+	// WaitForGTID lets a replica wait until it has applied the GTID set
+	// before running a read query, see WithSessionTracking.
+	WaitForGTID string
+	// MaxRows aborts the iteration of the Load and Iterate functions with an
+	// errors.Exceeded once the query returns more rows, independent of the
+	// LIMIT clause. Zero disables the check.
+	MaxRows uint64
 }
 
 // WithContextQueryOptions adds options for executing queries, mostly in generated code.
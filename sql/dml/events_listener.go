@@ -0,0 +1,183 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/corestoreio/errors"
+)
+
+// ListenerEvent gets passed to a Listener.
+type ListenerEvent struct {
+	// Flag defines the operation and if the event gets dispatched before or
+	// after the execution, for example EventFlagBeforeUpdate.
+	Flag EventFlag
+	// Table contains the name of the table of the SELECT, INSERT, UPDATE or
+	// DELETE statement.
+	Table string
+	// DBR executes the statement. It must not be modified.
+	DBR *DBR
+	// Args contains the arguments as passed to the execution function.
+	Args []interface{}
+	// Err contains the error of the execution in an after event.
+	Err error
+}
+
+// Listener gets called before and after a DBR executes a statement. An error
+// returned in a before event aborts the execution. Listeners can be disabled
+// per call with QueryOptions.SkipEvents. QueryRowContext dispatches no events
+// because sql.Row cannot report an error.
+type Listener func(ctx context.Context, e ListenerEvent) error
+
+// ListenerToken identifies a listener registered with a ConnPool.
+type ListenerToken uint64
+
+type listenerEntry struct {
+	token ListenerToken
+	table string
+	fn    Listener
+}
+
+// listeners contains the listeners of a connection pool. The slice gets
+// replaced on each change, hence the DBR can read it without locking.
+type listeners struct {
+	mu        sync.Mutex
+	lastToken ListenerToken
+	entries   atomic.Value // []listenerEntry
+}
+
+func (ls *listeners) load() []listenerEntry {
+	if ls == nil {
+		return nil
+	}
+	e, _ := ls.entries.Load().([]listenerEntry)
+	return e
+}
+
+// AddListenerForTable registers a listener which gets called for all
+// statements of a table. An empty table registers the listener for all
+// tables. Pool listeners get called in the order of registration, before the
+// listeners of a builder in before events and after them in after events. The
+// returned token removes the listener, see RemoveListener.
+func (c *ConnPool) AddListenerForTable(table string, l Listener) ListenerToken {
+	ls := c.queryCache.listeners
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.lastToken++
+	old := ls.load()
+	entries := make([]listenerEntry, len(old), len(old)+1)
+	copy(entries, old)
+	ls.entries.Store(append(entries, listenerEntry{token: ls.lastToken, table: table, fn: l}))
+	return ls.lastToken
+}
+
+// RemoveListener removes a listener registered with AddListenerForTable and
+// reports whether the token has been found.
+func (c *ConnPool) RemoveListener(t ListenerToken) bool {
+	ls := c.queryCache.listeners
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	old := ls.load()
+	for i, e := range old {
+		if e.token == t {
+			entries := make([]listenerEntry, 0, len(old)-1)
+			entries = append(entries, old[:i]...)
+			ls.entries.Store(append(entries, old[i+1:]...))
+			return true
+		}
+	}
+	return false
+}
+
+// AddListener adds a listener which gets only called for the executions of
+// this builder. See ConnPool.AddListenerForTable for the order.
+func (b *Select) AddListener(l Listener) *Select {
+	b.listeners = append(b.listeners, l)
+	return b
+}
+
+// AddListener adds a listener which gets only called for the executions of
+// this builder. See ConnPool.AddListenerForTable for the order.
+func (b *Insert) AddListener(l Listener) *Insert {
+	b.listeners = append(b.listeners, l)
+	return b
+}
+
+// AddListener adds a listener which gets only called for the executions of
+// this builder. See ConnPool.AddListenerForTable for the order.
+func (b *Update) AddListener(l Listener) *Update {
+	b.listeners = append(b.listeners, l)
+	return b
+}
+
+// AddListener adds a listener which gets only called for the executions of
+// this builder. See ConnPool.AddListenerForTable for the order.
+func (b *Delete) AddListener(l Listener) *Delete {
+	b.listeners = append(b.listeners, l)
+	return b
+}
+
+// eventFlags returns the before and after flags of the statement.
+func (cs *cachedSQL) eventFlags() (before, after EventFlag) {
+	switch cs.source {
+	case dmlSourceInsert, dmlSourceInsertSelect:
+		return EventFlagBeforeInsert, EventFlagAfterInsert
+	case dmlSourceUpdate:
+		return EventFlagBeforeUpdate, EventFlagAfterUpdate
+	case dmlSourceDelete:
+		return EventFlagBeforeDelete, EventFlagAfterDelete
+	}
+	return EventFlagBeforeSelect, EventFlagAfterSelect
+}
+
+// listenersEnabled reports whether events must be dispatched for this call.
+func (a *DBR) listenersEnabled(ctx context.Context) bool {
+	if len(a.cachedSQL.listeners) == 0 && len(a.listeners.load()) == 0 {
+		return false
+	}
+	return !FromContextQueryOptions(ctx).SkipEvents
+}
+
+// dispatchEvent calls the pool listeners of the table and the builder
+// listeners. In after events the order gets reversed.
+func (a *DBR) dispatchEvent(ctx context.Context, after bool, args []interface{}, errExec error) error {
+	e := ListenerEvent{Table: a.cachedSQL.table, DBR: a, Args: args, Err: errExec}
+	e.Flag, _ = a.cachedSQL.eventFlags()
+	if after {
+		_, e.Flag = a.cachedSQL.eventFlags()
+	}
+
+	fns := make([]Listener, 0, 8)
+	for _, le := range a.listeners.load() {
+		if le.table == "" || le.table == e.Table {
+			fns = append(fns, le.fn)
+		}
+	}
+	fns = append(fns, a.cachedSQL.listeners...)
+
+	for i := range fns {
+		fn := fns[i]
+		if after {
+			fn = fns[len(fns)-1-i]
+		}
+		if err := fn(ctx, e); err != nil {
+			return errors.Wrapf(err, "[dml] Listener for table %q with query ID %q", e.Table, a.cachedSQL.id)
+		}
+	}
+	return nil
+}
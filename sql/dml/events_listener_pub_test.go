@@ -0,0 +1,124 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type listenerRecorder []string
+
+func (lr *listenerRecorder) listener(name string) dml.Listener {
+	return func(_ context.Context, e dml.ListenerEvent) error {
+		*lr = append(*lr, fmt.Sprintf("%s:%d:%s:%v", name, e.Flag, e.Table, e.Args))
+		return nil
+	}
+}
+
+func TestConnPool_AddListenerForTable(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("order and isolation", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `sales_order` SET `state`=?")).
+			WithArgs("complete").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `sku` FROM `catalog_product`")).
+			WillReturnRows(sqlmock.NewRows([]string{"sku"}).AddRow("a"))
+
+		var lr listenerRecorder
+		dbc.AddListenerForTable("sales_order", lr.listener("pool_order"))
+		dbc.AddListenerForTable("", lr.listener("pool_all"))
+
+		updOrder := dbc.WithQueryBuilder(dml.NewUpdate("sales_order").AddColumns("state").
+			AddListener(lr.listener("builder_order")))
+		selProduct := dbc.WithQueryBuilder(dml.NewSelect("sku").From("catalog_product").
+			AddListener(lr.listener("builder_product")))
+
+		_, err := updOrder.ExecContext(ctx, "complete")
+		assert.NoError(t, err)
+		_, err = selProduct.LoadStrings(ctx, nil)
+		assert.NoError(t, err)
+
+		assert.Exactly(t, listenerRecorder{
+			fmt.Sprintf("pool_order:%d:sales_order:[complete]", dml.EventFlagBeforeUpdate),
+			fmt.Sprintf("pool_all:%d:sales_order:[complete]", dml.EventFlagBeforeUpdate),
+			fmt.Sprintf("builder_order:%d:sales_order:[complete]", dml.EventFlagBeforeUpdate),
+			fmt.Sprintf("builder_order:%d:sales_order:[complete]", dml.EventFlagAfterUpdate),
+			fmt.Sprintf("pool_all:%d:sales_order:[complete]", dml.EventFlagAfterUpdate),
+			fmt.Sprintf("pool_order:%d:sales_order:[complete]", dml.EventFlagAfterUpdate),
+			fmt.Sprintf("pool_all:%d:catalog_product:[]", dml.EventFlagBeforeSelect),
+			fmt.Sprintf("builder_product:%d:catalog_product:[]", dml.EventFlagBeforeSelect),
+			fmt.Sprintf("builder_product:%d:catalog_product:[]", dml.EventFlagAfterSelect),
+			fmt.Sprintf("pool_all:%d:catalog_product:[]", dml.EventFlagAfterSelect),
+		}, lr)
+	})
+
+	t.Run("remove and skip events", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("DELETE FROM `sales_order`")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("DELETE FROM `sales_order`")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		var lr listenerRecorder
+		tok1 := dbc.AddListenerForTable("sales_order", lr.listener("first"))
+		dbc.AddListenerForTable("sales_order", lr.listener("second"))
+		dbr := dbc.WithQueryBuilder(dml.NewDelete("sales_order"))
+
+		assert.True(t, dbc.RemoveListener(tok1))
+		assert.False(t, dbc.RemoveListener(tok1))
+
+		_, err := dbr.ExecContext(ctx)
+		assert.NoError(t, err)
+		_, err = dbr.ExecContext(dml.WithContextQueryOptions(ctx, dml.QueryOptions{SkipEvents: true}))
+		assert.NoError(t, err)
+
+		assert.Exactly(t, listenerRecorder{
+			fmt.Sprintf("second:%d:sales_order:[]", dml.EventFlagBeforeDelete),
+			fmt.Sprintf("second:%d:sales_order:[]", dml.EventFlagAfterDelete),
+		}, lr)
+	})
+
+	t.Run("before event aborts", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		var afterCalled bool
+		ins := dml.NewInsert("sales_order").AddColumns("state").
+			AddListener(func(_ context.Context, e dml.ListenerEvent) error {
+				if e.Flag == dml.EventFlagBeforeInsert {
+					return errors.NotAllowed.Newf("read only")
+				}
+				afterCalled = true
+				return nil
+			})
+
+		_, err := dbc.WithQueryBuilder(ins).ExecContext(ctx, "new")
+		assert.ErrorIsKind(t, errors.NotAllowed, err)
+		assert.False(t, afterCalled)
+	})
+}
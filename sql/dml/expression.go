@@ -30,7 +30,7 @@ func writeExpression(w *bytes.Buffer, expression string, args []interface{}) (ph
 		// fast path
 		_, err = w.WriteString(expression)
 	} else {
-		err = writeInterpolate(w, expression, args)
+		err = writeInterpolate(dialect, w, expression, args)
 	}
 	return
 }
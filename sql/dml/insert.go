@@ -101,6 +101,10 @@ type Insert struct {
 	// IsOnDuplicateKey if enabled adds all columns to the ON DUPLICATE KEY
 	// claus. Takes the OnDuplicateKeyExclude field into consideration.
 	IsOnDuplicateKey bool
+	// OnConflictColumns defines the columns of the unique key for dialects
+	// which require a conflict target in the upsert clause, like PostgreSQL.
+	// MySQL ignores them. See function OnConflict().
+	OnConflictColumns []string
 	// IsReplace uses the REPLACE syntax. See function Replace().
 	IsReplace bool
 	// IsIgnore ignores error. See function Ignore().
@@ -109,6 +113,11 @@ type Insert struct {
 	// VALUES do not need to get build by default because mostly WithDBR gets
 	// called to build the VALUES part dynamically.
 	IsBuildValues bool
+	// IsSkipTimestamps disables the timestamps of the ConnPool. See function
+	// SkipTimestamps().
+	IsSkipTimestamps bool
+	// timestamps gets set by the ConnPool, see WithTimestamps.
+	timestamps *timestamps
 }
 
 // NewInsert creates a new Insert object.
@@ -129,6 +138,23 @@ func (b *Insert) Ignore() *Insert {
 	return b
 }
 
+// SkipTimestamps disables the automatic timestamps of the ConnPool for this
+// statement. See WithTimestamps.
+func (b *Insert) SkipTimestamps() *Insert {
+	b.IsSkipTimestamps = true
+	return b
+}
+
+// WithDialect renders the SQL string in the syntax of dialect `d`. Queries
+// created via a ConnPool use the dialect of the ConnPool.
+func (b *Insert) WithDialect(d Dialect) *Insert {
+	b.dialect = d
+	if b.Select != nil {
+		b.Select.dialect = d
+	}
+	return b
+}
+
 // Replace instead of INSERT to overwrite old rows. REPLACE is the counterpart
 // to INSERT IGNORE in the treatment of new rows that contain unique key values
 // that duplicate old rows: The new rows are used to replace the old rows rather
@@ -191,6 +217,13 @@ func (b *Insert) AddOnDuplicateKeyExclude(columns ...string) *Insert {
 	return b
 }
 
+// OnConflict sets the columns of the unique key which the PostgreSQL dialect
+// writes into ON CONFLICT (columns) DO UPDATE SET. MySQL ignores them.
+func (b *Insert) OnConflict(columns ...string) *Insert {
+	b.OnConflictColumns = append(b.OnConflictColumns, columns...)
+	return b
+}
+
 // OnDuplicateKey enables for all columns to be written into the ON DUPLICATE
 // KEY claus. Takes the field OnDuplicateKeyExclude into consideration.
 func (b *Insert) OnDuplicateKey() *Insert {
@@ -274,6 +307,12 @@ func (b *Insert) toSQL(buf *bytes.Buffer, placeHolders []string) ([]string, erro
 			}
 			Quoter.quote(buf, c)
 		}
+		if b.timestamps != nil {
+			for _, c := range b.timestamps.columns {
+				buf.WriteByte(',')
+				Quoter.quote(buf, c)
+			}
+		}
 		placeHolders = append(placeHolders, b.Columns...)
 		buf.WriteString(") ")
 	}
@@ -305,7 +344,7 @@ func (b *Insert) toSQL(buf *bytes.Buffer, placeHolders []string) ([]string, erro
 				}
 				switch {
 				case cv.Right.arg != nil:
-					if err := writeInterfaceValue(cv.Right.arg, buf, 0); err != nil {
+					if err := writeInterfaceValue(dialect, cv.Right.arg, buf, 0); err != nil {
 						return nil, errors.WithStack(err)
 					}
 				case cv.Right.IsExpression:
@@ -324,7 +363,7 @@ func (b *Insert) toSQL(buf *bytes.Buffer, placeHolders []string) ([]string, erro
 			}
 			buf.WriteByte(')')
 		} else {
-			writeTuplePlaceholders(buf, uint(rowCount), uint(argCount0))
+			writeInsertTuples(buf, uint(rowCount), uint(argCount0), b.timestamps)
 		}
 	}
 
@@ -353,7 +392,7 @@ func (b *Insert) writeOnDuplicateKey(buf *bytes.Buffer, placeHolders []string) (
 		}
 	}
 
-	return b.OnDuplicateKeys.writeOnDuplicateKey(buf, placeHolders)
+	return b.OnDuplicateKeys.writeOnDuplicateKey(b.sqlDialect(), buf, b.OnConflictColumns, placeHolders)
 }
 
 func strInSlice(search string, sl []string) bool {
@@ -375,6 +414,7 @@ func (b *Insert) Clone() *Insert {
 	c.BuilderBase = b.BuilderBase.Clone()
 	c.Columns = cloneStringSlice(b.Columns)
 	c.OnDuplicateKeyExclude = cloneStringSlice(b.OnDuplicateKeyExclude)
+	c.OnConflictColumns = cloneStringSlice(b.OnConflictColumns)
 	c.OnDuplicateKeys = b.OnDuplicateKeys.Clone()
 	c.Select = b.Select.Clone()
 	c.Pairs = b.Pairs.Clone()
@@ -173,7 +173,7 @@ func (in *ip) ToSQL() (_ string, alwaysNil []interface{}, _ error) {
 	}
 	buf := bufferpool.Get()
 	defer bufferpool.Put(buf)
-	if err := writeInterpolate(buf, in.queryCache, in.args); err != nil {
+	if err := writeInterpolate(dialect, buf, in.queryCache, in.args); err != nil {
 		return "", nil, errors.WithStack(err)
 	}
 	return buf.String(), nil, nil
@@ -225,10 +225,14 @@ func (in *ip) NullFloat64(nv null.Float64) *ip     { in.args = append(in.args, n
 func (in *ip) NullFloat64s(nv ...null.Float64) *ip { in.args = append(in.args, nv); return in }
 func (in *ip) NullInt64(nv null.Int64) *ip         { in.args = append(in.args, nv); return in }
 func (in *ip) NullInt64s(nv ...null.Int64) *ip     { in.args = append(in.args, nv); return in }
+func (in *ip) NullUint64(nv null.Uint64) *ip       { in.args = append(in.args, nv); return in }
+func (in *ip) NullUint64s(nv ...null.Uint64) *ip   { in.args = append(in.args, nv); return in }
 func (in *ip) NullBool(nv null.Bool) *ip           { in.args = append(in.args, nv); return in }
 func (in *ip) NullBools(nv ...null.Bool) *ip       { in.args = append(in.args, nv); return in }
 func (in *ip) NullTime(nv null.Time) *ip           { in.args = append(in.args, nv); return in }
 func (in *ip) NullTimes(nv ...null.Time) *ip       { in.args = append(in.args, nv); return in }
+func (in *ip) Decimal(nv null.Decimal) *ip         { in.args = append(in.args, nv); return in }
+func (in *ip) Decimals(nv ...null.Decimal) *ip     { in.args = append(in.args, nv); return in }
 
 // DriverValues adds each Valuer as its own argument.
 func (in *ip) DriverValues(dvs ...driver.Valuer) *ip {
@@ -270,8 +274,8 @@ func (in *ip) Named(nArgs ...sql.NamedArg) *ip {
 }
 
 // writeInterpolate merges `args` into `sql` and writes the result into `buf`. `sql`
-// stays unchanged.
-func writeInterpolate(buf *bytes.Buffer, sql string, args []interface{}) error {
+// stays unchanged. The arguments get escaped with the rules of dialect `d`.
+func writeInterpolate(d Dialect, buf *bytes.Buffer, sql string, args []interface{}) error {
 	// TODO support :name identifier and the name field in argument
 
 	phCount, argCount := strings.Count(sql, placeHolderStr), len(args)
@@ -288,14 +292,14 @@ func writeInterpolate(buf *bytes.Buffer, sql string, args []interface{}) error {
 		switch {
 		case r == placeHolderRune && argCount > 0:
 			if phCounter < argCount { // protect for index out of bounds
-				if err := writeInterfaceValue(args[phCounter], buf, 0); err != nil {
+				if err := writeInterfaceValue(d, args[phCounter], buf, 0); err != nil {
 					return errors.WithStack(err)
 				}
 			}
 			phCounter++
 		case r == '`', r == '\'', r == '"':
 			p := strings.IndexRune(sql[pos:], r)
-			if r == '"' {
+			if r == '"' && !usesANSIQuotes(d) {
 				r = '\''
 			}
 			buf.WriteRune(r)
@@ -307,7 +311,7 @@ func writeInterpolate(buf *bytes.Buffer, sql string, args []interface{}) error {
 		case r == '[':
 			w = strings.IndexRune(sql[pos:], ']')
 			col := sql[pos : pos+w]
-			dialect.EscapeIdent(buf, col)
+			d.EscapeIdent(buf, col)
 			pos += w + 1 // size of ']'
 		default:
 			buf.WriteString(sql[pos-w : pos])
@@ -320,7 +324,7 @@ func writeInterpolate(buf *bytes.Buffer, sql string, args []interface{}) error {
 // writeInterpolateByte same as writeInterpolate. Maybe package unsafe can do
 // here some magic to avoid duplicate code, but for now we stick with a copy of
 // the above original function writeInterpolateByte.
-func writeInterpolateBytes(buf *bytes.Buffer, sql []byte, args []interface{}) error {
+func writeInterpolateBytes(d Dialect, buf *bytes.Buffer, sql []byte, args []interface{}) error {
 	args2 := args[:0] // filter without memory allocation
 	for _, arg := range args {
 		switch arg.(type) {
@@ -346,14 +350,14 @@ func writeInterpolateBytes(buf *bytes.Buffer, sql []byte, args []interface{}) er
 		switch {
 		case r == placeHolderRune && argCount > 0:
 			if phCounter < argCount { // protect for index out of bounds
-				if err := writeInterfaceValue(args[phCounter], buf, 0); err != nil {
+				if err := writeInterfaceValue(d, args[phCounter], buf, 0); err != nil {
 					return errors.WithStack(err)
 				}
 			}
 			phCounter++
 		case r == '`', r == '\'', r == '"':
 			p := bytes.IndexRune(sql[pos:], r)
-			if r == '"' {
+			if r == '"' && !usesANSIQuotes(d) {
 				r = '\''
 			}
 			buf.WriteRune(r)
@@ -365,7 +369,7 @@ func writeInterpolateBytes(buf *bytes.Buffer, sql []byte, args []interface{}) er
 		case r == '[':
 			w = bytes.IndexByte(sql[pos:], ']')
 			col := sql[pos : pos+w]
-			dialect.EscapeIdent(buf, string(col))
+			d.EscapeIdent(buf, string(col))
 			pos += w + 1 // size of ']'
 		default:
 			buf.Write(sql[pos-w : pos])
@@ -19,6 +19,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -274,6 +275,17 @@ func TestInterpolate_Int64(t *testing.T) {
 	})
 }
 
+func TestInterpolate_NullUint64(t *testing.T) {
+	compareToSQL2(t,
+		Interpolate("SELECT * FROM x WHERE a = ? AND b IN ? AND c = ? AND d IN ?").
+			NullUint64(null.MakeUint64(math.MaxUint64)).
+			NullUint64s(null.MakeUint64(1), null.Uint64{}, null.MakeUint64(math.MaxInt64+1)).
+			Unsafe(null.MakeUint32(math.MaxUint32), []null.Uint16{null.MakeUint16(math.MaxUint16), {}}),
+		errors.NoKind,
+		"SELECT * FROM x WHERE a = 18446744073709551615 AND b IN (1,NULL,9223372036854775808) AND c = 4294967295 AND d IN (65535,NULL)",
+	)
+}
+
 func TestInterpolate_Bools(t *testing.T) {
 	t.Run("single args", func(t *testing.T) {
 		compareToSQL2(t,
@@ -344,6 +356,15 @@ func TestInterpolate_Time(t *testing.T) {
 			"SELECT * FROM x WHERE a IN ('2006-01-02 15:04:05','2006-01-02 15:05:05')",
 		)
 	})
+	t.Run("fractional seconds", func(t *testing.T) {
+		t3 := time.Date(2006, 1, 2, 15, 4, 5, 123456789, time.UTC)
+		t4 := time.Date(2006, 1, 2, 15, 4, 5, 120000000, time.UTC)
+		compareToSQL2(t,
+			Interpolate("SELECT * FROM x WHERE a = ? AND b IN ?").Time(t3).NullTimes(null.MakeTime(t4), null.Time{}),
+			errors.NoKind,
+			"SELECT * FROM x WHERE a = '2006-01-02 15:04:05.123456' AND b IN ('2006-01-02 15:04:05.12',NULL)",
+		)
+	})
 	t.Run("empty arg", func(t *testing.T) {
 		compareToSQL2(t,
 			Interpolate("SELECT * FROM x WHERE a IN ? ?").Times(),
@@ -377,6 +398,26 @@ func TestInterpolate_Floats(t *testing.T) {
 	})
 }
 
+func TestInterpolate_Decimals(t *testing.T) {
+	t.Run("single args", func(t *testing.T) {
+		compareToSQL2(t,
+			Interpolate("SELECT * FROM x WHERE a = ? AND b = ? AND c = ?").
+				Decimal(null.MustMakeDecimalBytes([]byte("-0.00000000000000000001"))).
+				Decimal(null.MustMakeDecimalBytes([]byte("12345678901234567890123456789.1234"))).
+				Decimal(null.Decimal{}),
+			errors.NoKind,
+			"SELECT * FROM x WHERE a = -0.00000000000000000001 AND b = 12345678901234567890123456789.1234 AND c = NULL",
+		)
+	})
+	t.Run("IN args", func(t *testing.T) {
+		compareToSQL2(t,
+			Interpolate("SELECT * FROM x WHERE a IN ?").Decimals(null.MakeDecimalInt64(2681700, 4), null.MakeDecimalInt64(-1, 0)),
+			errors.NoKind,
+			"SELECT * FROM x WHERE a IN (268.17,-1)",
+		)
+	})
+}
+
 func TestInterpolate_Slices_Strings_Between(t *testing.T) {
 	t.Run("BETWEEN at the end", func(t *testing.T) {
 		compareToSQL2(t,
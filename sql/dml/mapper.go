@@ -16,6 +16,7 @@ package dml
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"encoding"
 	"fmt"
 	"math"
@@ -72,6 +73,9 @@ type ColumnMap struct {
 	scanErr    error
 	index      int // current column index
 	fieldCount int
+	// strict gets enabled via WithStrictColumnMap or
+	// QueryOptions.StrictColumnMap.
+	strict columnMapStrict
 }
 
 // NewColumnMap exported for testing reasons.
@@ -104,6 +108,7 @@ func (b *ColumnMap) reset() {
 	b.columnsLen = 0
 	b.scanErr = nil
 	b.index = 0
+	b.strict.reset()
 }
 
 func (b *ColumnMap) setColumns(cols []string) {
@@ -205,6 +210,25 @@ func (s scannedColumn) String() string {
 	return fmt.Sprintf("Field Type %q not supported", s.field)
 }
 
+// driverValue returns the scanned value as a driver.Value.
+func (s scannedColumn) driverValue() driver.Value {
+	switch s.field {
+	case 'i':
+		return s.int64
+	case 'f':
+		return s.float64
+	case 'b':
+		return s.bool
+	case 'y':
+		return s.byte
+	case 's':
+		return s.string
+	case 't':
+		return s.time
+	}
+	return nil
+}
+
 func (s *scannedColumn) reset() {
 	s.field = 0
 	s.bool = false
@@ -268,20 +292,7 @@ func (b *ColumnMap) Scan(r *sql.Rows) error {
 			return errors.WithStack(err)
 		}
 
-		b.setColumns(cols)
-		if cap(b.scanCol) >= b.columnsLen { // reuse from pool!
-			b.scanCol = b.scanCol[:b.columnsLen]
-			b.scanArgs = b.scanArgs[:b.columnsLen]
-		} else {
-			b.scanCol = make([]scannedColumn, b.columnsLen)
-			b.scanArgs = make([]interface{}, b.columnsLen)
-			for i := 0; i < b.columnsLen; i++ {
-				b.scanArgs[i] = &b.scanCol[i]
-			}
-		}
-		b.initialized = true
-		b.Count = 0
-		b.HasRows = true
+		b.initScan(cols)
 	} else {
 		b.Count++
 	}
@@ -291,6 +302,25 @@ func (b *ColumnMap) Scan(r *sql.Rows) error {
 	return nil
 }
 
+// initScan prepares the internal slices for scanning the values of the columns
+// `cols`.
+func (b *ColumnMap) initScan(cols []string) {
+	b.setColumns(cols)
+	if cap(b.scanCol) >= b.columnsLen { // reuse from pool!
+		b.scanCol = b.scanCol[:b.columnsLen]
+		b.scanArgs = b.scanArgs[:b.columnsLen]
+	} else {
+		b.scanCol = make([]scannedColumn, b.columnsLen)
+		b.scanArgs = make([]interface{}, b.columnsLen)
+		for i := 0; i < b.columnsLen; i++ {
+			b.scanArgs[i] = &b.scanCol[i]
+		}
+	}
+	b.initialized = true
+	b.Count = 0
+	b.HasRows = true
+}
+
 // Err returns the delayed error from one of the scans and parsings. Function is
 // idempotent.
 func (b *ColumnMap) Err() error {
@@ -311,6 +341,9 @@ func (b *ColumnMap) Column() string {
 // Next moves the internal index to the next position. It may return false if
 // during RawBytes scanning an error has occurred.
 func (b *ColumnMap) Next(fieldCount int) bool {
+	if b.strict.enabled {
+		b.strictNext(fieldCount)
+	}
 	b.fieldCount = fieldCount
 	b.index++
 	columnsLen := b.columnsLen
@@ -325,13 +358,36 @@ func (b *ColumnMap) Next(fieldCount int) bool {
 		// where the error has happened.
 		b.index = -1
 	}
+	if !ok && b.strict.enabled {
+		b.strictDone()
+	}
 	return ok
 }
 
+// probeColumns maps the columns of rec and reports whether rec provides them. A
+// NotFound error of rec gets discarded.
+func (b *ColumnMap) probeColumns(rec ColumnMapper) (bool, error) {
+	lenArgs := len(b.args)
+	err := b.mapColumns(rec)
+	switch {
+	case err == nil:
+		return len(b.args) > lenArgs, nil
+	case errors.NotFound.Match(err):
+		// rec has returned within the Next loop.
+		b.args = b.args[:lenArgs]
+		b.index = -1
+		b.strict.inLoop = false
+		b.strict.clearBits()
+		return false, nil
+	}
+	return false, err
+}
+
 // Bool reads a bool value and appends it to the arguments slice or assigns the
 // bool value stored in sql.RawBytes to the pointer. See the documentation for
 // function Scan.
 func (b *ColumnMap) Bool(ptr *bool) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -368,6 +424,7 @@ func (b *ColumnMap) Bool(ptr *bool) *ColumnMap {
 // bool value stored in sql.RawBytes to the pointer. See the documentation for
 // function Scan.
 func (b *ColumnMap) NullBool(ptr *null.Bool) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -414,6 +471,7 @@ func (b *ColumnMap) NullBool(ptr *null.Bool) *ColumnMap {
 // int value stored in sql.RawBytes to the pointer. See the documentation for
 // function Scan.
 func (b *ColumnMap) Int(ptr *int) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -444,6 +502,7 @@ func (b *ColumnMap) Int(ptr *int) *ColumnMap {
 // the int64 value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) Int64(ptr *int64) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -473,6 +532,7 @@ func (b *ColumnMap) Int64(ptr *int64) *ColumnMap {
 // the int32 value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) Int32(ptr *int32) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -508,6 +568,7 @@ func (b *ColumnMap) Int32(ptr *int32) *ColumnMap {
 // the int16 value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) Int16(ptr *int16) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -543,6 +604,7 @@ func (b *ColumnMap) Int16(ptr *int16) *ColumnMap {
 // the int8 value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) Int8(ptr *int8) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -578,6 +640,7 @@ func (b *ColumnMap) Int8(ptr *int8) *ColumnMap {
 // assigns the int64 value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan.
 func (b *ColumnMap) NullInt64(ptr *null.Int64) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -610,6 +673,7 @@ func (b *ColumnMap) NullInt64(ptr *null.Int64) *ColumnMap {
 // assigns the int32 value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan.
 func (b *ColumnMap) NullInt32(ptr *null.Int32) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -642,6 +706,7 @@ func (b *ColumnMap) NullInt32(ptr *null.Int32) *ColumnMap {
 // assigns the int16 value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan.
 func (b *ColumnMap) NullInt16(ptr *null.Int16) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -674,6 +739,7 @@ func (b *ColumnMap) NullInt16(ptr *null.Int16) *ColumnMap {
 // assigns the int8 value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan.
 func (b *ColumnMap) NullInt8(ptr *null.Int8) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -702,10 +768,12 @@ func (b *ColumnMap) NullInt8(ptr *null.Int8) *ColumnMap {
 	return b
 }
 
-// NullInt64 reads an int64 value and appends it to the arguments slice or
-// assigns the int64 value stored in sql.RawBytes to the pointer. See the
-// documentation for function Scan.
+// NullUint64 reads an uint64 value and appends it to the arguments slice or
+// assigns the uint64 value stored in sql.RawBytes to the pointer. Negative or
+// too large values return an Overflowed error. See the documentation for
+// function Scan.
 func (b *ColumnMap) NullUint64(ptr *null.Uint64) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -717,8 +785,9 @@ func (b *ColumnMap) NullUint64(ptr *null.Uint64) *ColumnMap {
 	if b.scanErr == nil {
 		switch v := b.scanCol[b.index]; v.field {
 		case 'i':
-			ptr.Uint64 = uint64(v.int64)
-			ptr.Valid = true
+			if b.scanErr = ptr.Scan(v.int64); b.scanErr != nil {
+				b.scanErr = errors.Wrapf(b.scanErr, "[dml] Column %q", b.Column())
+			}
 		case 'n':
 			ptr.Uint64 = 0
 			ptr.Valid = false
@@ -734,10 +803,12 @@ func (b *ColumnMap) NullUint64(ptr *null.Uint64) *ColumnMap {
 	return b
 }
 
-// NullInt32 reads an int32 value and appends it to the arguments slice or
-// assigns the int32 value stored in sql.RawBytes to the pointer. See the
-// documentation for function Scan.
+// NullUint32 reads an uint32 value and appends it to the arguments slice or
+// assigns the uint32 value stored in sql.RawBytes to the pointer. Negative or
+// too large values return an Overflowed error. See the documentation for
+// function Scan.
 func (b *ColumnMap) NullUint32(ptr *null.Uint32) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -749,8 +820,9 @@ func (b *ColumnMap) NullUint32(ptr *null.Uint32) *ColumnMap {
 	if b.scanErr == nil {
 		switch v := b.scanCol[b.index]; v.field {
 		case 'i':
-			ptr.Uint32 = uint32(v.int64) // TODO check overflow
-			ptr.Valid = true
+			if b.scanErr = ptr.Scan(v.int64); b.scanErr != nil {
+				b.scanErr = errors.Wrapf(b.scanErr, "[dml] Column %q", b.Column())
+			}
 		case 'n':
 			ptr.Uint32 = 0
 			ptr.Valid = false
@@ -766,10 +838,12 @@ func (b *ColumnMap) NullUint32(ptr *null.Uint32) *ColumnMap {
 	return b
 }
 
-// NullInt16 reads an int16 value and appends it to the arguments slice or
-// assigns the int16 value stored in sql.RawBytes to the pointer. See the
-// documentation for function Scan.
+// NullUint16 reads an uint16 value and appends it to the arguments slice or
+// assigns the uint16 value stored in sql.RawBytes to the pointer. Negative or
+// too large values return an Overflowed error. See the documentation for
+// function Scan.
 func (b *ColumnMap) NullUint16(ptr *null.Uint16) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -781,8 +855,9 @@ func (b *ColumnMap) NullUint16(ptr *null.Uint16) *ColumnMap {
 	if b.scanErr == nil {
 		switch v := b.scanCol[b.index]; v.field {
 		case 'i':
-			ptr.Uint16 = uint16(v.int64) // TODO check overflow
-			ptr.Valid = true
+			if b.scanErr = ptr.Scan(v.int64); b.scanErr != nil {
+				b.scanErr = errors.Wrapf(b.scanErr, "[dml] Column %q", b.Column())
+			}
 		case 'n':
 			ptr.Uint16 = 0
 			ptr.Valid = false
@@ -802,6 +877,7 @@ func (b *ColumnMap) NullUint16(ptr *null.Uint16) *ColumnMap {
 // assigns the int8 value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan.
 func (b *ColumnMap) NullUint8(ptr *null.Uint8) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -834,6 +910,7 @@ func (b *ColumnMap) NullUint8(ptr *null.Uint8) *ColumnMap {
 // assigns the float64 value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan.
 func (b *ColumnMap) Float64(ptr *float64) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -859,14 +936,16 @@ func (b *ColumnMap) Float64(ptr *float64) *ColumnMap {
 }
 
 // Decimal reads a Decimal value and appends it to the arguments slice or
-// assigns the numeric value stored in sql.RawBytes to the pointer. See the
+// assigns the numeric value stored in sql.RawBytes to the pointer. The
+// interpolation writes the exact digits without exponent notation. See the
 // documentation for function Scan.
 func (b *ColumnMap) Decimal(ptr *null.Decimal) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
-		if v := ptr.String(); ptr == nil || v == sqlStrNullUC {
+		if ptr == nil || !ptr.Valid {
 			b.args = append(b.args, internalNULLNIL{})
 		} else {
-			b.args = append(b.args, v)
+			b.args = append(b.args, *ptr)
 		}
 		return b
 	}
@@ -891,6 +970,7 @@ func (b *ColumnMap) Decimal(ptr *null.Decimal) *ColumnMap {
 // assigns the float64 value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan.
 func (b *ColumnMap) NullFloat64(ptr *null.Float64) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -922,6 +1002,7 @@ func (b *ColumnMap) NullFloat64(ptr *null.Float64) *ColumnMap {
 // uint value stored in sql.RawBytes to the pointer. See the documentation for
 // function Scan.
 func (b *ColumnMap) Uint(ptr *uint) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -952,6 +1033,7 @@ func (b *ColumnMap) Uint(ptr *uint) *ColumnMap {
 // the uint8 value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) Uint8(ptr *uint8) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -982,6 +1064,7 @@ func (b *ColumnMap) Uint8(ptr *uint8) *ColumnMap {
 // the uint16 value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) Uint16(ptr *uint16) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -1012,6 +1095,7 @@ func (b *ColumnMap) Uint16(ptr *uint16) *ColumnMap {
 // the uint32 value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) Uint32(ptr *uint32) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -1042,6 +1126,7 @@ func (b *ColumnMap) Uint32(ptr *uint32) *ColumnMap {
 // the uint64 value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) Uint64(ptr *uint64) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -1094,6 +1179,7 @@ func (b *ColumnMap) Debug(w ioWriter) (err error) {
 // the []byte value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) Byte(ptr *[]byte) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -1125,6 +1211,7 @@ func (b *ColumnMap) Text(enc interface {
 	encoding.TextMarshaler
 	encoding.TextUnmarshaler
 }) *ColumnMap {
+	b.strict.claims++
 	if b.scanErr != nil {
 		return b
 	}
@@ -1160,6 +1247,7 @@ func (b *ColumnMap) Binary(enc interface {
 	encoding.BinaryMarshaler
 	encoding.BinaryUnmarshaler
 }) *ColumnMap {
+	b.strict.claims++
 	if b.scanErr != nil {
 		return b
 	}
@@ -1181,10 +1269,42 @@ func (b *ColumnMap) Binary(enc interface {
 	return b
 }
 
+// ScannerValuer allows to use custom types which implement the interfaces
+// sql.Scanner and driver.Valuer. When arguments are requested the value of
+// function Value gets appended to the arguments slice. When data gets retrieved
+// from the server, the scanned value gets passed to function Scan. The
+// underlying byte slice is only valid until the next call to rows.Next, so Scan
+// must copy it. Use this function for types defined outside of package null.
+func (b *ColumnMap) ScannerValuer(sv interface {
+	sql.Scanner
+	driver.Valuer
+}) *ColumnMap {
+	b.strict.claims++
+	if b.scanErr != nil {
+		return b
+	}
+	if b.shouldCollectArgs() {
+		var v driver.Value
+		if v, b.scanErr = sv.Value(); b.scanErr == nil {
+			if v == nil {
+				v = internalNULLNIL{}
+			}
+			b.args = append(b.args, v)
+		}
+		return b
+	}
+
+	if b.scanErr = sv.Scan(b.scanCol[b.index].driverValue()); b.scanErr != nil {
+		b.scanErr = errors.BadEncoding.New(b.scanErr, "[dml] Column %q", b.Column())
+	}
+	return b
+}
+
 // String reads a string value and appends it to the arguments slice or assigns
 // the string value stored in sql.RawBytes to the pointer. See the documentation
 // for function Scan.
 func (b *ColumnMap) String(ptr *string) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -1219,6 +1339,7 @@ func (b *ColumnMap) String(ptr *string) *ColumnMap {
 // assigns the string value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan.
 func (b *ColumnMap) NullString(ptr *null.String) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -1259,6 +1380,7 @@ func (b *ColumnMap) NullString(ptr *null.String) *ColumnMap {
 // the time.Time value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan. It supports all MySQL/MariaDB date/time types.
 func (b *ColumnMap) Time(ptr *time.Time) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -1276,7 +1398,7 @@ func (b *ColumnMap) Time(ptr *time.Time) *ColumnMap {
 				b.scanErr = errors.Empty.Newf("[dml] Column %q Time cannot be empty.", b.Column())
 			} else {
 				var nt null.Time
-				nt, b.scanErr = null.ParseDateTime(string(v.byte), time.UTC) // time.Location can be merged into ColumnMap but then change NullTime method receiver.
+				nt, b.scanErr = null.ParseDateTime(string(v.byte), null.TimeLocation)
 				*ptr = nt.Time
 				if b.scanErr != nil {
 					b.scanErr = errors.BadEncoding.New(b.scanErr, "[dml] Column %q", b.Column())
@@ -1293,6 +1415,7 @@ func (b *ColumnMap) Time(ptr *time.Time) *ColumnMap {
 // the NullTime value stored in sql.RawBytes to the pointer. See the
 // documentation for function Scan.
 func (b *ColumnMap) NullTime(ptr *null.Time) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() {
 		if ptr == nil {
 			b.args = append(b.args, internalNULLNIL{})
@@ -1327,6 +1450,7 @@ func (b *ColumnMap) NullTime(ptr *null.Time) *ColumnMap {
 const columnMapErrMsgSlices = "[dml] ColumnMap.%s does only support mode ColumnMapCollectionReadSet"
 
 func (b *ColumnMap) addSlice(fnName string, slice interface{}) *ColumnMap {
+	b.strict.claims++
 	if b.shouldCollectArgs() && b.scanErr == nil && b.Mode() == ColumnMapCollectionReadSet {
 		b.args = append(b.args, slice)
 	} else {
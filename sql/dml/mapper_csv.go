@@ -0,0 +1,132 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// CSVOptions configures the CSVWriter.
+type CSVOptions struct {
+	// Comma sets the field delimiter, defaults to ','. Use '\t' for TSV.
+	Comma rune
+	// UseCRLF terminates each line with \r\n as defined in RFC 4180.
+	UseCRLF bool
+	// NoHeader disables the header row with the column names.
+	NoHeader bool
+	// Null represents a NULL value, defaults to an empty field. For example
+	// `\N` as used by LOAD DATA INFILE.
+	Null string
+	// TimeFormat formats values decoded by the driver as time.Time, defaults
+	// to time.RFC3339Nano. Dates returned as text stay unchanged.
+	TimeFormat string
+	// FlushInterval flushes the writer after each n rows. Zero flushes only on
+	// Close.
+	FlushInterval uint64
+}
+
+// CSVWriter writes each row of a query as a CSV record, quoted according to
+// RFC 4180. It implements ColumnMapper and streams the rows without buffering
+// the result set. DBR.Load calls Close after the last row, which flushes the
+// writer.
+//		cw := dml.NewCSVWriter(w, dml.CSVOptions{Comma: '\t'})
+//		_, err := dbr.Load(ctx, cw)
+// The header row gets written with the first row, hence an empty result set
+// produces no output.
+type CSVWriter struct {
+	w      *csv.Writer
+	opts   CSVOptions
+	record []string
+	rows   uint64
+}
+
+// NewCSVWriter creates a new CSVWriter.
+func NewCSVWriter(w io.Writer, opts CSVOptions) *CSVWriter {
+	cw := &CSVWriter{
+		w:    csv.NewWriter(w),
+		opts: opts,
+	}
+	if opts.Comma != 0 {
+		cw.w.Comma = opts.Comma
+	}
+	cw.w.UseCRLF = opts.UseCRLF
+	if cw.opts.TimeFormat == "" {
+		cw.opts.TimeFormat = time.RFC3339Nano
+	}
+	return cw
+}
+
+// MapColumns implements interface ColumnMapper and writes the current row.
+func (cw *CSVWriter) MapColumns(cm *ColumnMap) error {
+	if m := cm.Mode(); m != ColumnMapScan {
+		return errors.NotSupported.Newf("[dml] CSVWriter supports only scanning of rows, got mode %q", string(m))
+	}
+	if cw.rows == 0 && !cw.opts.NoHeader {
+		if err := cw.w.Write(cm.columns); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	cw.record = cw.record[:0]
+	for _, sc := range cm.scanCol {
+		var field string
+		switch sc.field {
+		case 'n':
+			field = cw.opts.Null
+		case 't':
+			field = sc.time.Format(cw.opts.TimeFormat)
+		case 'b':
+			field = "0"
+			if sc.bool {
+				field = "1"
+			}
+		case 'i':
+			field = strconv.FormatInt(sc.int64, 10)
+		case 'f':
+			field = strconv.FormatFloat(sc.float64, 'f', -1, 64)
+		case 'y':
+			field = string(sc.byte)
+		case 's':
+			field = sc.string
+		default:
+			return errors.NotSupported.Newf("[dml] CSVWriter: Column %q does not support field type: %q", cm.columns[len(cw.record)], sc.field)
+		}
+		cw.record = append(cw.record, field)
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return errors.WithStack(err)
+	}
+	cw.rows++
+	if cw.opts.FlushInterval > 0 && cw.rows%cw.opts.FlushInterval == 0 {
+		cw.w.Flush()
+		return errors.WithStack(cw.w.Error())
+	}
+	return nil
+}
+
+// Rows returns the number of written rows, without the header row.
+func (cw *CSVWriter) Rows() uint64 { return cw.rows }
+
+// Close flushes the buffered rows to the underlying writer. It does not close
+// the underlying writer.
+func (cw *CSVWriter) Close() error {
+	cw.w.Flush()
+	return errors.WithStack(cw.w.Error())
+}
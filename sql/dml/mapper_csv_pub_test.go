@@ -0,0 +1,98 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// writeCounter counts the calls to Write.
+type writeCounter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (wc *writeCounter) Write(p []byte) (int, error) {
+	wc.writes++
+	return wc.Buffer.Write(p)
+}
+
+func TestCSVWriter(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2020, 2, 29, 12, 13, 14, 0, time.UTC)
+	mockRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "price", "active", "created_at", "note"}).
+			AddRow(int64(1), []byte(`Shirt, "red"`), 9.95, true, created, nil).
+			AddRow(int64(2), "Pants\nblue", []byte("19.90"), false, []byte("2020-03-01 08:00:00"), []byte("\\N"))
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockRows())
+
+		var buf bytes.Buffer
+		cw := dml.NewCSVWriter(&buf, dml.CSVOptions{})
+		rowCount, err := dbc.WithQueryBuilder(dml.NewSelect("*").From("catalog_product")).Load(ctx, cw)
+		assert.NoError(t, err)
+		assert.Exactly(t, uint64(2), rowCount)
+		assert.Exactly(t, uint64(2), cw.Rows())
+		assert.Exactly(t, "id,name,price,active,created_at,note\n"+
+			"1,\"Shirt, \"\"red\"\"\",9.95,1,2020-02-29T12:13:14Z,\n"+
+			"2,\"Pants\nblue\",19.90,0,2020-03-01 08:00:00,\\N\n", buf.String())
+	})
+
+	t.Run("TSV with options", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockRows())
+
+		buf := new(writeCounter)
+		cw := dml.NewCSVWriter(buf, dml.CSVOptions{
+			Comma:         '\t',
+			UseCRLF:       true,
+			NoHeader:      true,
+			Null:          "NULL",
+			TimeFormat:    "2006-01-02",
+			FlushInterval: 1,
+		})
+		_, err := dbc.WithQueryBuilder(dml.NewSelect("*").From("catalog_product")).Load(ctx, cw)
+		assert.NoError(t, err)
+		assert.Exactly(t, 2, buf.writes, "each row must be flushed")
+		assert.Exactly(t, "1\t\"Shirt, \"\"red\"\"\"\t9.95\t1\t2020-02-29\tNULL\r\n"+
+			"2\t\"Pants\r\nblue\"\t19.90\t0\t2020-03-01 08:00:00\t\\N\r\n", buf.String())
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		var buf bytes.Buffer
+		cw := dml.NewCSVWriter(&buf, dml.CSVOptions{})
+		rowCount, err := dbc.WithQueryBuilder(dml.NewSelect("*").From("catalog_product")).Load(ctx, cw)
+		assert.NoError(t, err)
+		assert.Exactly(t, uint64(0), rowCount)
+		assert.Exactly(t, "", buf.String())
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// LoadDataReader encodes the records of a ColumnMapper into the default format
+// of a LOAD DATA INFILE statement: fields terminated by a tab, lines terminated
+// by a newline, special characters escaped by a backslash and NULL written as
+// \N. The rows get encoded on the fly while reading.
+type LoadDataReader struct {
+	rec     ColumnMapper
+	columns []string
+	args    []interface{}
+	buf     bytes.Buffer
+	err     error
+	mapped  bool
+}
+
+// NewLoadDataReader creates a new reader for a collection or a single record.
+// The columns define the order of the fields and must match the column list of
+// the LOAD DATA statement.
+func NewLoadDataReader(rec ColumnMapper, columns ...string) *LoadDataReader {
+	return &LoadDataReader{rec: rec, columns: columns}
+}
+
+// Read implements io.Reader.
+func (r *LoadDataReader) Read(p []byte) (n int, err error) {
+	if !r.mapped {
+		r.mapped = true
+		r.err = r.mapColumns()
+	}
+	for r.buf.Len() < len(p) && len(r.args) > 0 && r.err == nil {
+		row := r.args[:len(r.columns)]
+		r.args = r.args[len(r.columns):]
+		for i := 0; i < len(row) && r.err == nil; i++ {
+			if i > 0 {
+				r.buf.WriteByte('\t')
+			}
+			if err := writeLoadDataValue(&r.buf, row[i]); err != nil {
+				r.err = errors.Wrapf(err, "[dml] LoadDataReader: Column %q", r.columns[i])
+			}
+		}
+		r.buf.WriteByte('\n')
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.buf.Len() == 0 {
+		return 0, io.EOF
+	}
+	return r.buf.Read(p)
+}
+
+func (r *LoadDataReader) mapColumns() error {
+	if len(r.columns) == 0 {
+		return errors.NotValid.Newf("[dml] LoadDataReader: Columns are required")
+	}
+	cm := NewColumnMap(argumentPoolMaxSize, r.columns...)
+	if err := r.rec.MapColumns(cm); err != nil {
+		return errors.WithStack(err)
+	}
+	if len(cm.args)%len(r.columns) != 0 {
+		return errors.Mismatch.Newf("[dml] LoadDataReader: %d arguments do not match %d columns", len(cm.args), len(r.columns))
+	}
+	r.args = cm.args
+	return nil
+}
+
+func writeLoadDataValue(buf *bytes.Buffer, arg interface{}) error {
+	if v, ok := arg.(driver.Valuer); ok {
+		var err error
+		if arg, err = v.Value(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	switch v := arg.(type) {
+	case nil, internalNULLNIL:
+		buf.WriteString(`\N`)
+	case string:
+		writeLoadDataEscaped(buf, v)
+	case []byte:
+		if v == nil {
+			buf.WriteString(`\N`)
+			return nil
+		}
+		writeLoadDataEscaped(buf, string(v))
+	case bool:
+		if v {
+			buf.WriteByte('1')
+		} else {
+			buf.WriteByte('0')
+		}
+	case int:
+		buf.WriteString(strconv.Itoa(v))
+	case int8:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int16:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int32:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case uint:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint8:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint16:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint32:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		buf.WriteString(strconv.FormatUint(v, 10))
+	case float32:
+		buf.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case time.Time:
+		buf.WriteString(v.Format(timeFormat))
+	default:
+		return errors.NotSupported.Newf("[dml] Type %T not supported", arg)
+	}
+	return nil
+}
+
+func writeLoadDataEscaped(buf *bytes.Buffer, s string) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case 0:
+			buf.WriteString(`\0`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+}
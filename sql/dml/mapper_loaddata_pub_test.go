@@ -0,0 +1,97 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"io/ioutil"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type loadDataProduct struct {
+	ID        int64
+	SKU       string
+	Name      null.String
+	Active    bool
+	UpdatedAt time.Time
+}
+
+func (p *loadDataProduct) MapColumns(cm *dml.ColumnMap) error {
+	for cm.Next(5) {
+		switch c := cm.Column(); c {
+		case "id", "0":
+			cm.Int64(&p.ID)
+		case "sku", "1":
+			cm.String(&p.SKU)
+		case "name", "2":
+			cm.NullString(&p.Name)
+		case "active", "3":
+			cm.Bool(&p.Active)
+		case "updated_at", "4":
+			cm.Time(&p.UpdatedAt)
+		default:
+			return errors.NotFound.Newf("[dml_test] Column %q not found", c)
+		}
+	}
+	return cm.Err()
+}
+
+type loadDataProducts []*loadDataProduct
+
+func (ps loadDataProducts) MapColumns(cm *dml.ColumnMap) error {
+	for _, p := range ps {
+		if err := p.MapColumns(cm); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func TestLoadDataReader(t *testing.T) {
+	updated := time.Date(2020, 2, 29, 12, 13, 14, 0, time.UTC)
+	products := loadDataProducts{
+		{ID: 1, SKU: "tab\tnew\nline", Name: null.MakeString(`back\slash`), Active: true, UpdatedAt: updated},
+		{ID: 2, SKU: "nul\x00cr\r", UpdatedAt: updated},
+	}
+
+	t.Run("all columns", func(t *testing.T) {
+		data, err := ioutil.ReadAll(iotest.OneByteReader(dml.NewLoadDataReader(products, "id", "sku", "name", "active", "updated_at")))
+		assert.NoError(t, err)
+		assert.Exactly(t, "1\ttab\\tnew\\nline\tback\\\\slash\t1\t2020-02-29 12:13:14\n"+
+			"2\tnul\\0cr\\r\t\\N\t0\t2020-02-29 12:13:14\n", string(data))
+	})
+
+	t.Run("custom order", func(t *testing.T) {
+		data, err := ioutil.ReadAll(dml.NewLoadDataReader(products, "name", "id"))
+		assert.NoError(t, err)
+		assert.Exactly(t, "back\\\\slash\t1\n\\N\t2\n", string(data))
+	})
+
+	t.Run("no columns", func(t *testing.T) {
+		_, err := ioutil.ReadAll(dml.NewLoadDataReader(products))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		_, err := ioutil.ReadAll(dml.NewLoadDataReader(products, "id", "price"))
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+}
@@ -0,0 +1,152 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/corestoreio/errors"
+)
+
+// columnMapStrict verifies in strict mode that a ColumnMapper claims each
+// requested column exactly once, see WithStrictColumnMap. The bitsets get
+// reused between the rows.
+type columnMapStrict struct {
+	// claims counts the calls of the type functions of ColumnMap for the
+	// current column. It gets incremented also in non-strict mode because the
+	// increment is cheaper than a branch.
+	claims   int
+	enabled  bool
+	inLoop   bool
+	unmapped []uint64 // bitset over the column indexes
+	doubled  []uint64 // bitset over the column indexes
+	cacheKey string
+	record   ColumnMapper
+}
+
+func (s *columnMapStrict) reset() {
+	s.claims = 0
+	s.enabled = false
+	s.inLoop = false
+	s.clearBits()
+	s.cacheKey = ""
+	s.record = nil
+}
+
+func (s *columnMapStrict) clearBits() {
+	for i := range s.unmapped {
+		s.unmapped[i] = 0
+		s.doubled[i] = 0
+	}
+}
+
+// WithStrictColumnMap enables the strict mode of the ColumnMap for all
+// queries. After mapping a row or collecting the arguments of a record, the
+// ColumnMap verifies that the ColumnMapper claimed each requested column
+// exactly once. Otherwise it returns an error of kind Mismatch listing the
+// unmapped and the doubly mapped columns. The strict mode can also be enabled
+// per query with QueryOptions.StrictColumnMap. Cheap enough for staging but
+// should be disabled in production.
+func WithStrictColumnMap() ConnPoolOption {
+	return ConnPoolOption{
+		fn: func(c *ConnPool) error {
+			c.queryCache.strictColumnMap = true
+			return nil
+		},
+	}
+}
+
+// initColumnMap enables the strict mode of cm, if configured.
+func (a *DBR) initColumnMap(ctx context.Context, cm *ColumnMap) {
+	if a.strictColumnMap || FromContextQueryOptions(ctx).StrictColumnMap {
+		cm.enableStrict(a.customCacheKey)
+	}
+}
+
+func (b *ColumnMap) enableStrict(cacheKey string) {
+	b.strict.enabled = true
+	b.strict.cacheKey = cacheKey
+}
+
+// mapColumns calls rec.MapColumns and remembers rec for the error message of
+// the strict mode.
+func (b *ColumnMap) mapColumns(rec ColumnMapper) error {
+	if !b.strict.enabled {
+		return rec.MapColumns(b)
+	}
+	b.strict.record = rec
+	if err := rec.MapColumns(b); err != nil {
+		return err
+	}
+	return b.scanErr // in case rec does not return ColumnMap.Err
+}
+
+// strictNext gets called by Next before moving to the next column and
+// evaluates the claims of the current column.
+func (b *ColumnMap) strictNext(fieldCount int) {
+	s := &b.strict
+	switch {
+	case !s.inLoop:
+		s.inLoop = true
+		size := b.columnsLen
+		if size == 0 {
+			size = fieldCount
+		}
+		if words := (size + 63) / 64; cap(s.unmapped) < words {
+			s.unmapped = make([]uint64, words)
+			s.doubled = make([]uint64, words)
+		} else {
+			s.unmapped = s.unmapped[:words]
+			s.doubled = s.doubled[:words]
+		}
+	case b.index >= 0:
+		switch i := b.index; s.claims {
+		case 0:
+			s.unmapped[i/64] |= 1 << uint(i%64)
+		case 1:
+		default:
+			s.doubled[i/64] |= 1 << uint(i%64)
+		}
+	}
+	s.claims = 0
+}
+
+// strictDone gets called after the last column and sets the delayed error.
+func (b *ColumnMap) strictDone() {
+	s := &b.strict
+	s.inLoop = false
+	var unmapped, doubled []string
+	for i := 0; i < len(s.unmapped)*64; i++ {
+		if s.unmapped[i/64]&(1<<uint(i%64)) != 0 {
+			unmapped = append(unmapped, b.columnName(i))
+		}
+		if s.doubled[i/64]&(1<<uint(i%64)) != 0 {
+			doubled = append(doubled, b.columnName(i))
+		}
+	}
+	s.clearBits()
+	if (unmapped != nil || doubled != nil) && b.scanErr == nil {
+		b.scanErr = errors.Mismatch.Newf("[dml] ColumnMap strict mode: ColumnMapper %T with cache key %q: unmapped columns %q, doubly mapped columns %q",
+			s.record, s.cacheKey, unmapped, doubled)
+	}
+}
+
+func (b *ColumnMap) columnName(i int) string {
+	if b.columnsLen == 0 {
+		return strconv.Itoa(i)
+	}
+	return b.columns[i]
+}
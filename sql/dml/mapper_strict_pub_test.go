@@ -0,0 +1,136 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// strictEntity maps the column name not at all or the column id twice.
+type strictEntity struct {
+	ID         int64
+	Name       string
+	skipName   bool
+	doubleID   bool
+	noErrCheck bool
+}
+
+func (e *strictEntity) MapColumns(cm *dml.ColumnMap) error {
+	for cm.Next(3) {
+		switch c := cm.Column(); c {
+		case "id", "0":
+			cm.Int64(&e.ID)
+			if e.doubleID {
+				cm.Int64(&e.ID)
+			}
+		case "name", "1":
+			if !e.skipName {
+				cm.String(&e.Name)
+			}
+		case "note", "2": // forgotten
+		default:
+			return errors.NotFound.Newf("[dml_test] Column %q not found", c)
+		}
+	}
+	if e.noErrCheck {
+		return nil
+	}
+	return cm.Err()
+}
+
+func TestWithStrictColumnMap(t *testing.T) {
+	ctx := context.Background()
+	strictCtx := dml.WithContextQueryOptions(ctx, dml.QueryOptions{StrictColumnMap: true})
+
+	mockRows := func(cols ...string) *sqlmock.Rows {
+		r := sqlmock.NewRows(cols)
+		for i := 1; i <= 2; i++ {
+			vals := []driver.Value{int64(i), "Gopher", "xyz"}
+			r.AddRow(vals[:len(cols)]...)
+		}
+		return r
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockRows("id", "name"))
+
+		e := &strictEntity{skipName: true, doubleID: true}
+		_, err := dbc.WithQueryBuilder(dml.NewSelect("id", "name").From("tableA")).Load(ctx, e)
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(2), e.ID)
+	})
+
+	t.Run("valid mapper", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockRows("id", "name"))
+
+		e := &strictEntity{}
+		rowCount, err := dbc.WithQueryBuilder(dml.NewSelect("id", "name").From("tableA")).Load(strictCtx, e)
+		assert.NoError(t, err)
+		assert.Exactly(t, uint64(2), rowCount)
+		assert.Exactly(t, "Gopher", e.Name)
+	})
+
+	t.Run("unmapped and doubly mapped via context", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockRows("id", "name", "note"))
+
+		assert.NoError(t, dbc.RegisterByQueryBuilder(map[string]dml.QueryBuilder{
+			"selectStrict": dml.NewSelect("id", "name", "note").From("tableA"),
+		}))
+		e := &strictEntity{doubleID: true}
+		_, err := dbc.WithCacheKey("selectStrict").Load(strictCtx, e)
+		assert.ErrorIsKind(t, errors.Mismatch, err)
+		assert.Contains(t, err.Error(), `*dml_test.strictEntity with cache key "selectStrict": unmapped columns ["note"], doubly mapped columns ["id"]`)
+	})
+
+	t.Run("mapper ignores Err", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithStrictColumnMap())
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery("SELECT").WillReturnRows(mockRows("id", "name"))
+
+		e := &strictEntity{skipName: true, noErrCheck: true}
+		_, err := dbc.WithQueryBuilder(dml.NewSelect("id", "name").From("tableA")).Load(ctx, e)
+		assert.ErrorIsKind(t, errors.Mismatch, err)
+		assert.Contains(t, err.Error(), `unmapped columns ["name"]`)
+	})
+
+	t.Run("collecting arguments", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t, dml.WithStrictColumnMap())
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `tableA` (`id`,`name`) VALUES (?,?)")).
+			WithArgs(int64(1), "Gopher").WillReturnResult(sqlmock.NewResult(1, 1))
+
+		ins := dbc.WithQueryBuilder(dml.NewInsert("tableA").AddColumns("id", "name"))
+		_, err := ins.ExecContext(ctx, dml.Qualify("", &strictEntity{ID: 1, Name: "Gopher"}))
+		assert.NoError(t, err)
+
+		_, err = ins.ExecContext(ctx, dml.Qualify("", &strictEntity{ID: 1, skipName: true}))
+		assert.ErrorIsKind(t, errors.Mismatch, err)
+		assert.Contains(t, err.Error(), `unmapped columns ["name"]`)
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -86,6 +87,27 @@ func TestColumnMap_BinaryText(t *testing.T) {
 	assert.ErrorIsKind(t, errors.NotValid, err)
 }
 
+func TestColumnMap_ScannerValuer(t *testing.T) {
+	t.Run("args", func(t *testing.T) {
+		cm := NewColumnMap(2)
+		assert.NoError(t, cm.ScannerValuer(&null.String{Data: "JSON", Valid: true}).ScannerValuer(&null.String{}).Err())
+		assert.Exactly(t, []interface{}{"JSON", nil}, expandInterfaces(cm.args))
+	})
+	t.Run("scan", func(t *testing.T) {
+		cm := NewColumnMap(0, "value", "empty")
+		cm.scanCol = []scannedColumn{{field: 'y', byte: []byte(`{"a":1}`)}, {field: 'n'}}
+		cm.scanArgs = []interface{}{&cm.scanCol[0], &cm.scanCol[1]}
+
+		var v, empty null.String
+		cm.index = 0
+		assert.NoError(t, cm.ScannerValuer(&v).Err())
+		cm.index = 1
+		assert.NoError(t, cm.ScannerValuer(&empty).Err())
+		assert.Exactly(t, null.MakeString(`{"a":1}`), v)
+		assert.False(t, empty.Valid)
+	})
+}
+
 func TestColumnMap_Nil_Pointers(t *testing.T) {
 	cm := NewColumnMap(20)
 	cm.
@@ -172,6 +194,39 @@ func TestScannedColumn_Scan(t *testing.T) {
 	assert.ErrorIsKind(t, errors.NotSupported, err)
 }
 
+func TestColumnMap_NullUint_Overflow(t *testing.T) {
+	cm := NewColumnMap(0, "entity_id")
+	cm.index = 0
+	cm.scanCol = make([]scannedColumn, 1)
+	cm.scanCol[0].field = 'i'
+
+	cm.scanCol[0].int64 = math.MaxUint16
+	var u16 null.Uint16
+	assert.NoError(t, cm.NullUint16(&u16).Err())
+	assert.Exactly(t, null.MakeUint16(math.MaxUint16), u16)
+
+	cm.scanCol[0].int64 = math.MaxUint16 + 1
+	assert.ErrorIsKind(t, errors.Overflowed, cm.NullUint16(&u16).Err())
+	cm.scanErr = nil
+
+	cm.scanCol[0].int64 = math.MaxUint32 + 1
+	var u32 null.Uint32
+	assert.ErrorIsKind(t, errors.Overflowed, cm.NullUint32(&u32).Err())
+	cm.scanErr = nil
+
+	cm.scanCol[0].int64 = -1
+	var u64 null.Uint64
+	err := cm.NullUint64(&u64).Err()
+	assert.ErrorIsKind(t, errors.Overflowed, err)
+	assert.Contains(t, err.Error(), "entity_id")
+	cm.scanErr = nil
+
+	cm.scanCol[0].field = 'y'
+	cm.scanCol[0].byte = []byte("18446744073709551615")
+	assert.NoError(t, cm.NullUint64(&u64).Err())
+	assert.Exactly(t, null.MakeUint64(math.MaxUint64), u64)
+}
+
 func TestColumnMap_Scan_Empty_Bytes(t *testing.T) {
 	cm := NewColumnMap(0, "SomeColumn")
 	cm.index = 0
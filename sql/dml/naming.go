@@ -250,7 +250,7 @@ func (idc ids) appendConditions(expressions Conditions) (ids, error) {
 			idf.Name = ""
 
 			if len(e.Right.args) > 0 {
-				if err := writeInterpolate(buf, idf.Expression, e.Right.args); err != nil {
+				if err := writeInterpolate(dialect, buf, idf.Expression, e.Right.args); err != nil {
 					bufferpool.Put(buf)
 					return nil, errors.Wrapf(err, "[dml] ids.appendConditions with expression: %q", idf.Expression)
 				}
@@ -0,0 +1,80 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestNullTime_RoundTrip_Microseconds(t *testing.T) {
+	dbc := dmltest.MustConnectDB(t)
+	defer dmltest.Close(t, dbc)
+	ctx := context.Background()
+
+	for _, sqlStr := range []string{
+		"DROP TABLE IF EXISTS `dml_null_time`",
+		"CREATE TABLE `dml_null_time` ( `id` int(10) unsigned NOT NULL PRIMARY KEY, `dt` DATETIME(6) NULL, `ts` TIMESTAMP(6) NULL DEFAULT NULL )",
+	} {
+		_, err := dbc.DB.ExecContext(ctx, sqlStr)
+		assert.NoError(t, err)
+	}
+	defer func() {
+		_, err := dbc.DB.ExecContext(ctx, "DROP TABLE IF EXISTS `dml_null_time`")
+		assert.NoError(t, err)
+	}()
+
+	tests := []null.Time{
+		null.MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 123456000, null.TimeLocation)),
+		null.MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 1000, null.TimeLocation)),
+		null.MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 0, null.TimeLocation)),
+		{},
+	}
+
+	insert := func(interpolate bool, id int, nt null.Time) {
+		dbr := dbc.WithQueryBuilder(dml.NewInsert("dml_null_time").AddColumns("id", "dt", "ts"))
+		if interpolate {
+			dbr = dbr.Interpolate()
+		}
+		_, err := dbr.ExecContext(ctx, id, nt, nt)
+		assert.NoError(t, err)
+	}
+	load := func(column string, id int) null.Time {
+		nt, found, err := dbc.WithQueryBuilder(
+			dml.NewSelect(column).From("dml_null_time").Where(dml.Column("id").PlaceHolder()),
+		).LoadNullTime(ctx, id)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		return nt
+	}
+
+	for i, want := range tests {
+		for j, interpolate := range []bool{false, true} {
+			id := i*2 + j + 1
+			insert(interpolate, id, want)
+			for _, column := range []string{"dt", "ts"} {
+				have := load(column, id)
+				assert.Exactly(t, want.Valid, have.Valid, "ID %d column %s", id, column)
+				assert.True(t, want.Time.Equal(have.Time), "ID %d column %s: want %s have %s", id, column, want.Time, have.Time)
+			}
+		}
+	}
+}
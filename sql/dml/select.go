@@ -93,6 +93,13 @@ func (b *Select) Unsafe() *Select {
 	return b
 }
 
+// WithDialect renders the SQL string in the syntax of dialect `d`. Queries
+// created via a ConnPool use the dialect of the ConnPool.
+func (b *Select) WithDialect(d Dialect) *Select {
+	b.dialect = d
+	return b
+}
+
 // StraightJoin forces the optimizer to join the tables in the order in which
 // they are listed in the FROM clause. You can use this to speed up a query if
 // the optimizer joins the tables in nonoptimal order.
@@ -464,7 +471,7 @@ func (b *Select) toSQL(w *bytes.Buffer, placeHolders []string) (_placeHolders []
 		sqlWriteOrderBy(w, b.OrderBys, false)
 	}
 
-	sqlWriteLimitOffset(w, b.LimitValid, true, b.OffsetCount, b.LimitCount)
+	sqlWriteLimitOffset(b.sqlDialect(), w, b.LimitValid, true, b.OffsetCount, b.LimitCount)
 
 	switch {
 	case b.IsLockInShareMode:
@@ -0,0 +1,88 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+// TableColumner provides the column names of a table. It gets implemented by
+// *ddl.Table.
+type TableColumner interface {
+	// ColumnNames returns all column names in the order of the table.
+	ColumnNames() []string
+	// PrimaryKeyNames returns the column names of the primary key.
+	PrimaryKeyNames() []string
+}
+
+// FieldMaskOption configures the function Select.ColumnsFromMask.
+type FieldMaskOption uint8
+
+// FieldMaskCamelCase converts mask entries in camelCase, which are not a column
+// name, into snake_case column names, e.g. scopeId to scope_id.
+const FieldMaskCamelCase FieldMaskOption = 1 << iota
+
+// ColumnsFromMask replaces the selected columns with the columns of the field
+// mask, as sent by gRPC or REST clients. Each entry must be a column of the
+// table, otherwise a NotFound error lists all unknown entries. The primary key
+// columns get always selected, so loading via a ColumnMapper still works. The
+// columns get deduplicated and written in the order of the table. An empty
+// mask selects all columns.
+func (b *Select) ColumnsFromMask(mask []string, table TableColumner, opts ...FieldMaskOption) error {
+	var o FieldMaskOption
+	for _, opt := range opts {
+		o |= opt
+	}
+	columns := table.ColumnNames()
+	if len(mask) == 0 {
+		b.IsStar = false
+		b.Columns = ids(nil).AppendColumns(b.IsUnsafe, columns...)
+		return nil
+	}
+
+	selected := make(map[string]bool, len(mask)+2)
+	for _, c := range columns {
+		selected[c] = false
+	}
+	for _, pk := range table.PrimaryKeyNames() {
+		selected[pk] = true
+	}
+	var unknown []string
+	for _, m := range mask {
+		c := m
+		if _, ok := selected[c]; !ok && o&FieldMaskCamelCase != 0 {
+			c = strs.FromCamelCase(m)
+		}
+		if _, ok := selected[c]; !ok {
+			unknown = append(unknown, m)
+			continue
+		}
+		selected[c] = true
+	}
+	if len(unknown) > 0 {
+		return errors.NotFound.Newf("[dml] Select.ColumnsFromMask: Unknown fields %q in table %q", unknown, b.Table.Name)
+	}
+
+	cols := make([]string, 0, len(mask)+2)
+	for _, c := range columns {
+		if selected[c] {
+			cols = append(cols, c)
+		}
+	}
+	b.IsStar = false
+	b.Columns = ids(nil).AppendColumns(b.IsUnsafe, cols...)
+	return nil
+}
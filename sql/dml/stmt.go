@@ -28,6 +28,8 @@ type stmtWrapper struct {
 		QueryRowContext(ctx context.Context, args ...interface{}) *sql.Row
 		ioCloser
 	}
+	// onReplica is true if the statement has been prepared on a read replica.
+	onReplica bool
 }
 
 func (sw stmtWrapper) PrepareContext(_ context.Context, _ string) (*sql.Stmt, error) {
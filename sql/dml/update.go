@@ -28,6 +28,15 @@ type Update struct {
 	// SetClauses contains the column/argument association. For each column
 	// there must be one argument.
 	SetClauses Conditions
+	// optimisticLock contains the name of the version column, see
+	// WithOptimisticLock.
+	optimisticLock          string
+	optimisticLockTimestamp bool
+	// IsSkipTimestamps disables the timestamps of the ConnPool. See function
+	// SkipTimestamps().
+	IsSkipTimestamps bool
+	// timestamps gets set by the ConnPool, see WithTimestamps.
+	timestamps *timestamps
 }
 
 // NewUpdate creates a new Update object.
@@ -52,6 +61,20 @@ func (b *Update) Unsafe() *Update {
 	return b
 }
 
+// SkipTimestamps disables the automatic timestamps of the ConnPool for this
+// statement. See WithTimestamps.
+func (b *Update) SkipTimestamps() *Update {
+	b.IsSkipTimestamps = true
+	return b
+}
+
+// WithDialect renders the SQL string in the syntax of dialect `d`. Queries
+// created via a ConnPool use the dialect of the ConnPool.
+func (b *Update) WithDialect(d Dialect) *Update {
+	b.dialect = d
+	return b
+}
+
 // AddClauses appends a column/value pair for the statement.
 func (b *Update) AddClauses(c ...*Condition) *Update {
 	b.SetClauses = append(b.SetClauses, c...)
@@ -136,19 +159,48 @@ func (b *Update) toSQL(buf *bytes.Buffer, placeHolders []string) ([]string, erro
 	_, _ = b.Table.writeQuoted(buf, nil)
 	buf.WriteString(" SET ")
 
-	placeHolders, err := b.SetClauses.writeSetClauses(buf, placeHolders)
+	setClauses, wheres := b.SetClauses, b.Wheres
+	if lc := b.optimisticLock; lc != "" {
+		setClauses = make(Conditions, 0, len(b.SetClauses)+1)
+		for _, c := range b.SetClauses {
+			if c.Left != lc {
+				setClauses = append(setClauses, c)
+			}
+		}
+		next := Column(lc).Expr(Quoter.Name(lc) + " + 1")
+		if b.optimisticLockTimestamp {
+			next = Column(lc).Expr("CURRENT_TIMESTAMP(6)")
+		}
+		setClauses = append(setClauses, next)
+		// full slice expression avoids modifying the underlying array.
+		wheres = append(wheres[:len(wheres):len(wheres)], Column(lc).Equal().PlaceHolder())
+	}
+	if ts := b.timestamps; ts != nil {
+		// prepended to keep the position of the arguments of a record.
+		tsClauses := make(Conditions, 0, len(setClauses)+len(ts.columns))
+		for _, c := range ts.columns {
+			if ts.now != nil {
+				tsClauses = append(tsClauses, Column(c).NamedArg(timestampNamedArg))
+			} else {
+				tsClauses = append(tsClauses, Column(c).Expr("CURRENT_TIMESTAMP"))
+			}
+		}
+		setClauses = append(tsClauses, setClauses...)
+	}
+
+	placeHolders, err := setClauses.writeSetClauses(buf, placeHolders)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
 	// Write WHERE clause if we have any fragments
-	placeHolders, err = b.Wheres.write(buf, 'w', placeHolders, b.isWithDBR)
+	placeHolders, err = wheres.write(buf, 'w', placeHolders, b.isWithDBR)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
 	sqlWriteOrderBy(buf, b.OrderBys, false)
-	sqlWriteLimitOffset(buf, b.LimitValid, false, 0, b.LimitCount)
+	sqlWriteLimitOffset(b.sqlDialect(), buf, b.LimitValid, false, 0, b.LimitCount)
 	return placeHolders, nil
 }
 
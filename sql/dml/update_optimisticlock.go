@@ -0,0 +1,114 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/corestoreio/errors"
+)
+
+// ErrStaleObject gets returned by DBR.ExecContext if an UPDATE statement with
+// optimistic locking has not affected any row, because another process has
+// modified the row in the meantime and the version of the record is outdated.
+type ErrStaleObject struct {
+	Table  string
+	Column string
+}
+
+func (e *ErrStaleObject) Error() string {
+	return fmt.Sprintf("[dml] Stale object in table %q: The version column %q has been modified concurrently", e.Table, e.Column)
+}
+
+// WithOptimisticLock enables optimistic locking with the integer version
+// column `column`. The SET clause increments the version and the WHERE clause
+// compares the version with a placeholder, which gets bound from the current
+// version of the record. An existing SET clause of the column gets replaced.
+//		UPDATE `t` SET ..., `version`=`version` + 1 WHERE (...) AND (`version` = ?)
+// If no row has been affected, DBR.ExecContext returns an *ErrStaleObject or,
+// if the row does not exist anymore, a NotFound error. See
+// DBR.SkipExistenceCheck.
+func (b *Update) WithOptimisticLock(column string) *Update {
+	b.optimisticLock = column
+	b.optimisticLockTimestamp = false
+	return b
+}
+
+// WithOptimisticLockTimestamp same as WithOptimisticLock but the version
+// column `column` is a DATETIME(6) or TIMESTAMP(6) which gets set to
+// CURRENT_TIMESTAMP(6) by the database server. The record must be reloaded to
+// get the new version.
+func (b *Update) WithOptimisticLockTimestamp(column string) *Update {
+	b.optimisticLock = column
+	b.optimisticLockTimestamp = true
+	return b
+}
+
+// existsCachedSQL creates the SELECT statement which checks if the row of an
+// UPDATE statement with optimistic locking still exists. Returns nil if the
+// statement cannot be build.
+func (b *Update) existsCachedSQL() *cachedSQL {
+	sel := NewSelect().Unsafe().AddColumns("1").Limit(0, 1)
+	sel.Table = b.Table
+	sel.Wheres = b.Wheres.Clone()
+	sel.dialect = b.dialect
+	sel.isWithDBR = true
+	rawSQL, _, err := sel.ToSQL()
+	if err != nil {
+		return nil
+	}
+	return makeCachedSQL(sel, rawSQL, "")
+}
+
+// SkipExistenceCheck disables the additional SELECT query after an UPDATE
+// statement with optimistic locking has not affected any row. ExecContext then
+// returns an *ErrStaleObject even if the row does not exist anymore.
+func (a *DBR) SkipExistenceCheck() *DBR {
+	a.skipExistenceCheck = true
+	return a
+}
+
+// staleObjectError returns an *ErrStaleObject or a NotFound error if the row
+// does not exist anymore. The existence check requires records as arguments
+// because primitive arguments also contain the values of the SET clause.
+func (a *DBR) staleObjectError(ctx context.Context, rawArgs []interface{}) error {
+	se := &ErrStaleObject{Table: a.cachedSQL.table, Column: a.cachedSQL.optimisticLock}
+	if a.skipExistenceCheck || a.isPrepared || a.cachedSQL.existsSQL == nil ||
+		len(rawArgs) == 0 || len(a.QualifiedColumnsAliases) > 0 {
+		return se
+	}
+	for _, ra := range rawArgs {
+		switch ra.(type) {
+		case QualifiedRecord, ColumnMapper:
+		default:
+			return se
+		}
+	}
+	exists := &DBR{
+		cachedSQL: *a.cachedSQL.existsSQL,
+		DB:        a.DB,
+		log:       a.log,
+		dialect:   a.dialect,
+	}
+	_, found, err := exists.LoadNullInt64(ctx, rawArgs...)
+	switch {
+	case err != nil:
+		return errors.WithStack(err)
+	case !found:
+		return errors.NotFound.Newf("[dml] The row in table %q does not exist anymore", se.Table)
+	}
+	return se
+}
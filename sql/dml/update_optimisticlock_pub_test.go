@@ -0,0 +1,117 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type versionedPage struct {
+	PageID  int64
+	Title   string
+	Version int64
+}
+
+func (p *versionedPage) MapColumns(cm *dml.ColumnMap) error {
+	for cm.Next(3) {
+		switch c := cm.Column(); c {
+		case "page_id", "0":
+			cm.Int64(&p.PageID)
+		case "title", "1":
+			cm.String(&p.Title)
+		case "version", "2":
+			cm.Int64(&p.Version)
+		default:
+			return errors.NotFound.Newf("[dml_test] Column %q not found", c)
+		}
+	}
+	return cm.Err()
+}
+
+func TestUpdate_WithOptimisticLock(t *testing.T) {
+	const updateSQL = "UPDATE `cms_page` SET `title`=?, `version`=`version` + 1 WHERE (`page_id` = ?) AND (`version` = ?)"
+	const existsSQL = "SELECT 1 FROM `cms_page` WHERE (`page_id` = ?) LIMIT 0,1"
+	ctx := context.Background()
+	newUpdate := func() *dml.Update {
+		return dml.NewUpdate("cms_page").AddColumns("title", "version").
+			Where(dml.Column("page_id").PlaceHolder()).WithOptimisticLock("version")
+	}
+
+	t.Run("updated", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(updateSQL)).
+			WithArgs("Home", int64(3), int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		res, err := newUpdate().WithDBR(dbc.DB).ExecContext(ctx, &versionedPage{PageID: 3, Title: "Home", Version: 7})
+		assert.NoError(t, err)
+		ra, _ := res.RowsAffected()
+		assert.Exactly(t, int64(1), ra)
+	})
+
+	t.Run("stale object", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(updateSQL)).
+			WithArgs("Home", int64(3), int64(7)).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(existsSQL)).
+			WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+		res, err := newUpdate().WithDBR(dbc.DB).ExecContext(ctx, &versionedPage{PageID: 3, Title: "Home", Version: 7})
+		assert.Nil(t, res)
+		se, ok := err.(*dml.ErrStaleObject)
+		assert.True(t, ok, "%+v", err)
+		assert.Exactly(t, &dml.ErrStaleObject{Table: "cms_page", Column: "version"}, se)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(updateSQL)).
+			WithArgs("Home", int64(3), int64(7)).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(existsSQL)).
+			WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+		_, err := newUpdate().WithDBR(dbc.DB).ExecContext(ctx, &versionedPage{PageID: 3, Title: "Home", Version: 7})
+		assert.True(t, errors.NotFound.Match(err), "%+v", err)
+	})
+
+	t.Run("skip existence check", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(updateSQL)).
+			WithArgs("Home", int64(3), int64(7)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := newUpdate().WithDBR(dbc.DB).SkipExistenceCheck().ExecContext(ctx, &versionedPage{PageID: 3, Title: "Home", Version: 7})
+		_, ok := err.(*dml.ErrStaleObject)
+		assert.True(t, ok, "%+v", err)
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		compareToSQL(t, dml.NewUpdate("cms_page").AddColumns("title").
+			Where(dml.Column("page_id").Int64(3)).WithOptimisticLockTimestamp("updated_at"),
+			errors.NoKind,
+			"UPDATE `cms_page` SET `title`=?, `updated_at`=CURRENT_TIMESTAMP(6) WHERE (`page_id` = 3) AND (`updated_at` = ?)",
+			"",
+		)
+	})
+}
@@ -361,6 +361,87 @@ func TestUpdate_BindRecord(t *testing.T) {
 	})
 }
 
+func TestUpdate_QualifiedRecords(t *testing.T) {
+	child := &categoryEntity{EntityID: 678, ParentID: "p456", Path: null.MakeString("3/4/5")}
+	parent := &categoryEntity{EntityID: 456, Path: null.MakeString("3/4")}
+
+	t.Run("self join", func(t *testing.T) {
+		// A fictional self join which already reflects future JOIN
+		// implementation.
+		u := dml.NewUpdate("catalog_category_entity").Alias("child").
+			AddColumns("path").
+			Where(
+				dml.Column("child.entity_id").Equal().PlaceHolder(),
+				dml.Column("parent.entity_id").Equal().PlaceHolder(),
+				dml.Column("parent.path").Equal().PlaceHolder(),
+			).WithDBR(dbMock{}).TestWithArgs(dml.Qualify("parent", parent), dml.Qualify("child", child))
+		compareToSQL(t, u, errors.NoKind,
+			"UPDATE `catalog_category_entity` AS `child` SET `path`=? WHERE (`child`.`entity_id` = ?) AND (`parent`.`entity_id` = ?) AND (`parent`.`path` = ?)",
+			"UPDATE `catalog_category_entity` AS `child` SET `path`='3/4/5' WHERE (`child`.`entity_id` = 678) AND (`parent`.`entity_id` = 456) AND (`parent`.`path` = '3/4')",
+			"3/4/5", int64(678), int64(456), "3/4",
+		)
+	})
+
+	t.Run("unqualified placeholder is ambiguous", func(t *testing.T) {
+		u := dml.NewUpdate("catalog_category_entity").Alias("c").
+			AddColumns("path").
+			Where(dml.Column("c.entity_id").Equal().PlaceHolder()).
+			WithDBR(dbMock{}).TestWithArgs(dml.Qualify("parent", parent), dml.Qualify("child", child))
+		_, _, err := u.ToSQL()
+		assert.ErrorIsKind(t, errors.Duplicated, err)
+		assert.Contains(t, err.Error(), `Ambiguous placeholder for column "path"`)
+	})
+
+	t.Run("unqualified placeholder of the only record", func(t *testing.T) {
+		u := dml.NewUpdate("catalog_category_entity").Alias("c").
+			AddColumns("path").
+			Where(dml.Column("c.entity_id").Equal().PlaceHolder()).
+			WithDBR(dbMock{}).TestWithArgs(dml.Qualify("child", child), int64(33))
+		compareToSQL(t, u, errors.NoKind,
+			"UPDATE `catalog_category_entity` AS `c` SET `path`=? WHERE (`c`.`entity_id` = ?)",
+			"UPDATE `catalog_category_entity` AS `c` SET `path`='3/4/5' WHERE (`c`.`entity_id` = 33)",
+			"3/4/5", int64(33),
+		)
+	})
+
+	t.Run("BindOnce", func(t *testing.T) {
+		u := dml.NewUpdate("catalog_category_entity").
+			AddColumns("parent_id", "path").
+			Where(
+				dml.Column("parent_id").Equal().PlaceHolder(),
+				dml.Column("entity_id").Greater().PlaceHolder(),
+			).WithDBR(dbMock{}).TestWithArgs(dml.BindOnce("parent_id", "p456"), "3/4/5", int64(678))
+		compareToSQL(t, u, errors.NoKind,
+			"UPDATE `catalog_category_entity` SET `parent_id`=?, `path`=? WHERE (`parent_id` = ?) AND (`entity_id` > ?)",
+			"UPDATE `catalog_category_entity` SET `parent_id`='p456', `path`='3/4/5' WHERE (`parent_id` = 'p456') AND (`entity_id` > 678)",
+			"p456", "3/4/5", "p456", int64(678),
+		)
+	})
+
+	t.Run("BindOnce with qualifier and record", func(t *testing.T) {
+		u := dml.NewUpdate("catalog_category_entity").Alias("child").
+			AddColumns("path").
+			Where(
+				dml.Column("child.entity_id").Equal().PlaceHolder(),
+				dml.Column("parent.path").Equal().PlaceHolder(),
+			).WithDBR(dbMock{}).TestWithArgs(dml.Qualify("", child), dml.BindOnce("parent.path", "3/4"))
+		compareToSQL(t, u, errors.NoKind,
+			"UPDATE `catalog_category_entity` AS `child` SET `path`=? WHERE (`child`.`entity_id` = ?) AND (`parent`.`path` = ?)",
+			"UPDATE `catalog_category_entity` AS `child` SET `path`='3/4/5' WHERE (`child`.`entity_id` = 678) AND (`parent`.`path` = '3/4')",
+			"3/4/5", int64(678), "3/4",
+		)
+	})
+
+	t.Run("BindOnce conflicts with record", func(t *testing.T) {
+		u := dml.NewUpdate("catalog_category_entity").
+			AddColumns("path").
+			Where(dml.Column("entity_id").Equal().PlaceHolder()).
+			WithDBR(dbMock{}).TestWithArgs(dml.Qualify("", child), dml.BindOnce("path", "3"))
+		_, _, err := u.ToSQL()
+		assert.ErrorIsKind(t, errors.Duplicated, err)
+	})
+}
+
 func TestUpdate_Clone(t *testing.T) {
 	dbc, dbMock := dmltest.MockDB(t, dml.WithLogger(log.BlackHole{}, func() string { return "uniqueID" }))
 	defer dmltest.MockClose(t, dbc, dbMock)
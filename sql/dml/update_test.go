@@ -110,6 +110,14 @@ func TestUpdate_SetExprToSQL(t *testing.T) {
 			nil, int64(99))
 		assert.Exactly(t, []string{"fooNULL", "bar99"}, u.cachedSQL.qualifiedColumns)
 	})
+
+	t.Run("no placeholder with records", func(t *testing.T) {
+		u := NewUpdate("a").
+			AddClauses(Column("deleted_at").Expr("NOW()")).
+			Where(Column("id").PlaceHolder()).
+			WithDBR(dbMock{})
+		assert.Exactly(t, []string{"id"}, u.cachedSQL.qualifiedColumns)
+	})
 }
 
 func TestUpdateKeywordColumnName(t *testing.T) {
@@ -0,0 +1,193 @@
+package dmlgen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+)
+
+// newTypeDef creates the type definition for argument goType. A goType
+// without an import path must be a key of the internal type map, e.g. bool,
+// int64, string or decimal. A goType with an import path like
+// "github.com/acme/money.Money" defines a custom type which must implement the
+// interfaces sql.Scanner and driver.Valuer. A custom type handles NULL values
+// itself.
+func newTypeDef(goType, serializer string) (*TypeDef, error) {
+	dot := strings.LastIndexByte(goType, '.')
+	slash := strings.LastIndexByte(goType, '/')
+	if dot < 0 || dot < slash {
+		if serializer == "" {
+			serializer = "default"
+		}
+		td, ok := typeMap[goType][serializer]
+		if !ok {
+			return nil, errors.NotFound.Newf("[dmlgen] Go type %q not found. Either use a full import path or a type of: %s",
+				goType, strings.Join(typeMapKeys(), ", "))
+		}
+		return td, nil
+	}
+	if dot == len(goType)-1 {
+		return nil, errors.NotValid.Newf("[dmlgen] Go type %q requires a type name after the import path", goType)
+	}
+	t := goType[slash+1:] // money.Money
+	return &TypeDef{
+		GoUNull:            t,
+		GoUNotNull:         t,
+		GoNull:             t,
+		GoNotNull:          t,
+		SerializerUNull:    "bytes",
+		SerializerUNotNull: "bytes",
+		SerializerNull:     "bytes",
+		SerializerNotNull:  "bytes",
+		importPath:         goType[:dot],
+	}, nil
+}
+
+func typeMapKeys() []string {
+	keys := make([]string, 0, len(typeMap))
+	for k := range typeMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WithColumnType overrides the generated Go type of a single column. Argument
+// tableColumn has the format "table.column". Argument goType is either a type
+// of the internal type map (bool, int64, string, decimal, etc) or a custom
+// type including its import path, e.g. "github.com/acme/money.Money". A
+// custom type must implement the interfaces sql.Scanner and driver.Valuer
+// because the generated MapColumns function uses dml.ColumnMap.ScannerValuer.
+// Custom types cannot be used for primary or unique keys and not together with
+// a serializer. Unknown tables or columns return a NotFound error.
+func WithColumnType(tableColumn, goType string) (opt Option) {
+	opt.sortOrder = 120 // after WithProtobuf and all WithTable* functions
+	opt.fn = func(g *Generator) error {
+		dot := strings.IndexByte(tableColumn, '.')
+		if dot < 1 {
+			return errors.NotValid.Newf("[dmlgen] WithColumnType: Argument %q must have the format table.column", tableColumn)
+		}
+		tblName, colName := tableColumn[:dot], tableColumn[dot+1:]
+		t, ok := g.Tables[tblName]
+		if !ok {
+			tables := make([]string, 0, len(g.Tables))
+			for tn := range g.Tables {
+				tables = append(tables, tn)
+			}
+			sort.Strings(tables)
+			return errors.NotFound.Newf("[dmlgen] WithColumnType: Table %q not found. Known tables: %s",
+				tblName, strings.Join(tables, ", "))
+		}
+		c := t.Table.Columns.ByField(colName)
+		if c.Field == "" {
+			return errors.NotFound.Newf("[dmlgen] WithColumnType: For table %q the Column %q cannot be found. Known columns: %s",
+				tblName, colName, strings.Join(t.Table.Columns.FieldNames(), ", "))
+		}
+		td, err := newTypeDef(goType, g.Serializer)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := g.checkCustomTypeDef(td, c); err != nil {
+			return errors.Wrapf(err, "[dmlgen] WithColumnType: Table %q", tblName)
+		}
+		if g.columnTypes == nil {
+			g.columnTypes = make(map[*ddl.Column]*TypeDef)
+		}
+		g.columnTypes[c] = td
+		return nil
+	}
+	return opt
+}
+
+// WithMySQLTypeRule overrides the generated Go type for all columns of all
+// tables which match the MySQL type. Argument mysqlType matches either the full
+// column type, like "tinyint(1)" or "decimal(12,4) unsigned", or the data type
+// like "decimal". The full column type takes precedence. Argument goType
+// behaves the same as in WithColumnType. WithColumnType takes precedence over
+// a rule.
+func WithMySQLTypeRule(mysqlType, goType string) (opt Option) {
+	opt.sortOrder = 121
+	opt.fn = func(g *Generator) error {
+		td, err := newTypeDef(goType, g.Serializer)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		mysqlType = strings.ToLower(mysqlType)
+		for _, t := range g.Tables {
+			for _, c := range t.Table.Columns {
+				if strings.ToLower(c.ColumnType) != mysqlType && c.DataType != mysqlType {
+					continue
+				}
+				if err := g.checkCustomTypeDef(td, c); err != nil {
+					return errors.Wrapf(err, "[dmlgen] WithMySQLTypeRule: Table %q", t.Table.Name)
+				}
+			}
+		}
+		if g.mysqlTypeRules == nil {
+			g.mysqlTypeRules = make(map[string]*TypeDef)
+		}
+		g.mysqlTypeRules[mysqlType] = td
+		return nil
+	}
+	return opt
+}
+
+func (g *Generator) checkCustomTypeDef(td *TypeDef, c *ddl.Column) error {
+	if td.importPath == "" {
+		return nil
+	}
+	switch {
+	case g.Serializer != "":
+		return errors.NotSupported.Newf("[dmlgen] Custom type %q for column %q cannot be used with serializer %q", td.GoNull, c.Field, g.Serializer)
+	case c.IsPK() || c.IsUnique():
+		return errors.NotSupported.Newf("[dmlgen] Custom type %q cannot be used for the key column %q", td.GoNull, c.Field)
+	}
+	return nil
+}
+
+// customTypeDef returns the overridden type of a column or nil.
+func (g *Generator) customTypeDef(c *ddl.Column) *TypeDef {
+	if td, ok := g.columnTypes[c]; ok {
+		return td
+	}
+	if len(g.mysqlTypeRules) == 0 {
+		return nil
+	}
+	if td, ok := g.mysqlTypeRules[strings.ToLower(c.ColumnType)]; ok {
+		return td
+	}
+	return g.mysqlTypeRules[c.DataType]
+}
+
+// isCustomType returns true if the column uses a custom type which implements
+// sql.Scanner and driver.Valuer.
+func (g *Generator) isCustomType(c *ddl.Column) bool {
+	td := g.customTypeDef(c)
+	return td != nil && td.importPath != ""
+}
+
+// customTypeImportPaths returns the import paths of all custom types.
+func (g *Generator) customTypeImportPaths() []string {
+	var paths []string
+	add := func(td *TypeDef) {
+		if td.importPath == "" {
+			return
+		}
+		for _, p := range paths {
+			if p == td.importPath {
+				return
+			}
+		}
+		paths = append(paths, td.importPath)
+	}
+	for _, td := range g.columnTypes {
+		add(td)
+	}
+	for _, td := range g.mysqlTypeRules {
+		add(td)
+	}
+	sort.Strings(paths)
+	return paths
+}
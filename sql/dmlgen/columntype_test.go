@@ -0,0 +1,67 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestGenerator_ColumnType(t *testing.T) {
+	cols := func() ddl.Columns {
+		return ddl.Columns{
+			&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "price", Pos: 2, Null: "YES", DataType: "decimal", ColumnType: "decimal(12,4)"},
+			&ddl.Column{Field: "is_active", Pos: 3, Null: "NO", DataType: "tinyint", ColumnType: "tinyint(4)"},
+			&ddl.Column{Field: "payload", Pos: 4, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)"},
+		}
+	}
+	newGen := func(opts ...Option) (*Generator, error) {
+		return NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			append([]Option{WithTable("catalog_product", cols())}, opts...)...)
+	}
+
+	t.Run("custom type with import path", func(t *testing.T) {
+		g, err := newGen(
+			WithColumnType("catalog_product.price", "github.com/acme/money.Money"),
+			WithMySQLTypeRule("tinyint(4)", "bool"),
+			WithTableConfig("catalog_product", &TableConfig{}),
+		)
+		assert.NoError(t, err)
+		price := g.Tables["catalog_product"].Table.Columns.ByField("price")
+		isActive := g.Tables["catalog_product"].Table.Columns.ByField("is_active")
+		assert.Exactly(t, "money.Money", g.goTypeNull(price))
+		assert.Exactly(t, "ScannerValuer", g.goFuncNull(price))
+		assert.Exactly(t, "bool", g.goTypeNull(isActive))
+		assert.Exactly(t, "Bool", g.goFuncNull(isActive))
+
+		var buf, bufTest bytes.Buffer
+		assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+		have := buf.String()
+		assert.Contains(t, have, `"github.com/acme/money"`)
+		assert.Contains(t, have, "cm.ScannerValuer(&e.Price)")
+	})
+
+	t.Run("unknown column lists known columns", func(t *testing.T) {
+		_, err := newGen(WithColumnType("catalog_product.prize", "github.com/acme/money.Money"))
+		assert.ErrorIsKind(t, errors.NotFound, err)
+		assert.Contains(t, err.Error(), "entity_id, price, is_active, payload")
+	})
+
+	t.Run("unknown table", func(t *testing.T) {
+		_, err := newGen(WithColumnType("catalog_category.price", "github.com/acme/money.Money"))
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+
+	t.Run("unknown go type", func(t *testing.T) {
+		_, err := newGen(WithMySQLTypeRule("tinyint(1)", "boolean"))
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+
+	t.Run("custom type as primary key", func(t *testing.T) {
+		_, err := newGen(WithColumnType("catalog_product.entity_id", "github.com/acme/ids.ID"))
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+}
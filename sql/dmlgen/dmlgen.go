@@ -64,6 +64,10 @@ type Generator struct {
 	// SerializerHeaderOptions defines custom headers to use in the .proto or .fbs file.
 	// For proto, sane defaults are available.
 	SerializerHeaderOptions []string
+	// serializerFieldNumbersFile see SerializerConfig.FieldNumbersFile
+	serializerFieldNumbersFile string
+	// serializerEntityConverters see SerializerConfig.EntityConverters
+	serializerEntityConverters bool
 	// TestSQLDumpGlobPath contains the path and glob pattern to load a SQL dump
 	// containing the table schemas to run integration tests. If empty no dumps
 	// get loaded and the test program assumes that the tables already exists.
@@ -81,6 +85,12 @@ type Generator struct {
 	// "referencedTable.referencedColumn":"mainTable.mainColumn"
 	krsExclude map[string]bool
 	krsInclude map[string]bool
+
+	// columnTypes contains the type overrides of WithColumnType.
+	columnTypes map[*ddl.Column]*TypeDef
+	// mysqlTypeRules contains the type overrides of WithMySQLTypeRule. Key is
+	// the lower case MySQL column or data type.
+	mysqlTypeRules map[string]*TypeDef
 }
 
 // NewGenerator creates a new instance of the SQL table code generator. The order
@@ -98,6 +108,7 @@ func NewGenerator(packageImportPath string, opts ...Option) (*Generator, error)
 			"fmt",
 			"io",
 			"sort",
+			"strconv",
 			"time",
 
 			"github.com/corestoreio/errors",
@@ -109,6 +120,7 @@ func NewGenerator(packageImportPath string, opts ...Option) (*Generator, error)
 		ImportPathsTesting: []string{
 			"testing",
 			"context",
+			"encoding/json",
 			"sort",
 			"time",
 
@@ -236,10 +248,15 @@ func (g *Generator) GenerateGo(wMain, wTest io.Writer) error {
 	for _, t := range tables {
 		t.fnEntityRelationStruct(mainGen, g) // this must go first because to check if a table has relations
 		t.fnEntityStruct(mainGen, g)
+		t.fnEntityEnums(mainGen, g)
 	}
 	g.fnCreateDBM(mainGen, tables)
+	g.fnSoftDeleteWithDeleted(mainGen, tables)
+	g.fnGRPCHelpers(mainGen)
+	g.fnGraphQLHelpers(mainGen)
 	g.fnTestMainOther(testGen, tables)
 	g.fnTestMainDB(testGen, tables)
+	g.fnBenchmarkFastJSON(testGen, tables)
 
 	// deal with random map to guarantee the persistent code generation.
 	for _, t := range tables {
@@ -252,8 +269,10 @@ func (g *Generator) GenerateGo(wMain, wTest io.Writer) error {
 		t.fnEntityDBMapColumns(mainGen, g)
 		t.fnEntityDBMHandler(mainGen, g)
 		t.fnEntityEmpty(mainGen, g)
+		t.fnEntityFastJSON(mainGen, g)
 		t.fnEntityIsSet(mainGen, g)
 		t.fnEntityGetSetPrivateFields(mainGen, g)
+		t.fnEntityProto(mainGen, g)
 		t.fnEntityValidate(mainGen, g)
 		t.fnEntityWriteTo(mainGen, g)
 
@@ -266,17 +285,36 @@ func (g *Generator) GenerateGo(wMain, wTest io.Writer) error {
 		t.fnCollectionDBMHandler(mainGen, g)
 		t.fnCollectionDelete(mainGen, g)
 		t.fnCollectionEach(mainGen, g)
+		t.fnCollectionEnumFilters(mainGen, g)
+		t.fnCollectionFastJSON(mainGen, g)
 		t.fnCollectionFilter(mainGen, g)
+		t.fnCollectionFirstLast(mainGen, g)
 		t.fnCollectionInsert(mainGen, g)
+		t.fnCollectionLoadRelations(mainGen, g)
+		t.fnCollectionSort(mainGen, g)
+		t.fnCollectionSplit(mainGen, g)
 		t.fnCollectionSwap(mainGen, g)
 		t.fnCollectionUniqueGetters(mainGen, g)
 		t.fnCollectionUniquifiedGetters(mainGen, g)
 		t.fnCollectionValidate(mainGen, g)
 		t.fnCollectionWriteTo(mainGen, g)
+
+		t.fnGRPCServer(mainGen, g)
+		t.fnGraphQLResolvers(mainGen, g)
+	}
+
+	importPaths := g.ImportPaths
+	if g.hasEntityConverters() {
+		importPaths = append(importPaths[:len(importPaths):len(importPaths)],
+			g.PackageSerializerImportPath, "google.golang.org/protobuf/types/known/timestamppb")
+	}
+	if g.hasGRPCService() {
+		importPaths = append(importPaths[:len(importPaths):len(importPaths)], grpcImportPaths...)
 	}
+	importPaths = append(importPaths[:len(importPaths):len(importPaths)], g.customTypeImportPaths()...)
 
 	// now figure out all used package names in the buffer.
-	pkgs, err := findUsedPackages(mainGen.Bytes(), g.ImportPaths)
+	pkgs, err := findUsedPackages(mainGen.Bytes(), importPaths)
 	if err != nil {
 		_, _ = wMain.Write(mainGen.Bytes()) // write for debug reasons
 		return errors.WithStack(err)
@@ -287,7 +325,15 @@ func (g *Generator) GenerateGo(wMain, wTest io.Writer) error {
 		return errors.WithStack(err)
 	}
 
-	pkgs, err = findUsedPackages(testGen.Bytes(), g.ImportPathsTesting)
+	importPaths = g.ImportPathsTesting
+	if g.hasEntityConverters() {
+		importPaths = append(importPaths[:len(importPaths):len(importPaths)], "google.golang.org/protobuf/proto")
+	}
+	if g.hasGRPCService() {
+		importPaths = append(importPaths[:len(importPaths):len(importPaths)],
+			"github.com/DATA-DOG/go-sqlmock", "google.golang.org/grpc/codes", "google.golang.org/grpc/status")
+	}
+	pkgs, err = findUsedPackages(testGen.Bytes(), importPaths)
 	if err != nil {
 		_, _ = wMain.Write(testGen.Bytes()) // write for debug reasons
 		return errors.WithStack(err)
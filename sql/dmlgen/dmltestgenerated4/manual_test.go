@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"sort"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/corestoreio/pkg/sql/ddl"
 	"github.com/corestoreio/pkg/sql/dml"
 	"github.com/corestoreio/pkg/sql/dmltest"
@@ -253,3 +255,33 @@ func TestNewDBManager_Manual(t *testing.T) {
 		}, calledEvents)
 	})
 }
+
+func TestCoreConfiguration_ColumnsFromMask(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	tbl := ddl.NewTable(TableNameCoreConfiguration,
+		&ddl.Column{Field: "config_id", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+		&ddl.Column{Field: "scope", ColumnType: "varchar(8)"},
+		&ddl.Column{Field: "scope_id", ColumnType: "int(11)"},
+		&ddl.Column{Field: "expires", ColumnType: "datetime", Null: "YES"},
+		&ddl.Column{Field: "path", ColumnType: "varchar(255)"},
+		&ddl.Column{Field: "value", ColumnType: "text", Null: "YES"},
+	)
+	sel := dml.NewSelect().From(TableNameCoreConfiguration)
+	assert.NoError(t, sel.ColumnsFromMask([]string{"path", "scopeId"}, tbl, dml.FieldMaskCamelCase))
+	sel.Where(dml.Column("config_id").PlaceHolder())
+
+	dbMock.ExpectQuery(regexp.QuoteMeta("SELECT `config_id`, `scope_id`, `path` FROM `core_configuration` WHERE (`config_id` = ?)")).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"config_id", "scope_id", "path"}).AddRow(7, 2, "web/secure/url"))
+
+	var cc CoreConfiguration
+	_, err := dbc.WithQueryBuilder(sel).Load(context.Background(), &cc, 7)
+	assert.NoError(t, err)
+	assert.Exactly(t, CoreConfiguration{
+		ConfigID: 7,
+		ScopeID:  2,
+		Path:     "web/secure/url",
+	}, cc)
+}
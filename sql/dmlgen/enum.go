@@ -0,0 +1,219 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+// enumColumn contains the allowed values of a string column which gets
+// generated as its own type with constants.
+type enumColumn struct {
+	column *ddl.Column
+	values []string
+}
+
+func (to *TableConfig) applyEnumColumns(t *Table) {
+	t.enumColumns = nil
+	if len(to.EnumColumns) == 0 || to.lastErr != nil {
+		return
+	}
+	columns := make([]string, 0, len(to.EnumColumns))
+	for column := range to.EnumColumns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		values := to.EnumColumns[column]
+		c := t.Table.Columns.ByField(column)
+		switch {
+		case c.Field == "":
+			to.lastErr = errors.NotFound.Newf("[dmlgen] WithTableConfig:EnumColumns: For table %q the Column %q cannot be found.",
+				t.Table.Name, column)
+			return
+		case c.IsNull() || (c.DataType != "enum" && c.DataType != "varchar" && c.DataType != "char"):
+			to.lastErr = errors.NotSupported.Newf("[dmlgen] WithTableConfig:EnumColumns: For table %q the Column %q must be a not nullable enum, char or varchar.",
+				t.Table.Name, column)
+			return
+		case len(values) == 0 && c.DataType != "enum":
+			to.lastErr = errors.NotValid.Newf("[dmlgen] WithTableConfig:EnumColumns: For table %q the Column %q requires values because it is not an enum.",
+				t.Table.Name, column)
+			return
+		case len(values) == 0:
+			var err error
+			if values, err = parseEnumValues(c.ColumnType); err != nil {
+				to.lastErr = errors.Wrapf(err, "[dmlgen] WithTableConfig:EnumColumns: Table %q Column %q", t.Table.Name, column)
+				return
+			}
+		}
+		t.enumColumns = append(t.enumColumns, enumColumn{column: c, values: values})
+	}
+}
+
+// parseEnumValues extracts the values of a column type like
+// enum('default','websites','stores').
+func parseEnumValues(columnType string) ([]string, error) {
+	if len(columnType) < 6 || !strings.EqualFold(columnType[:5], "enum(") || columnType[len(columnType)-1] != ')' {
+		return nil, errors.NotValid.Newf("[dmlgen] Column type %q is not an enum", columnType)
+	}
+	def := columnType[5 : len(columnType)-1]
+	var values []string
+	for len(def) > 0 {
+		if def[0] != '\'' {
+			return nil, errors.NotValid.Newf("[dmlgen] Column type %q contains an unquoted value", columnType)
+		}
+		var value strings.Builder
+		i := 1
+		for ; i < len(def); i++ {
+			if def[i] == '\'' {
+				if i+1 < len(def) && def[i+1] == '\'' { // escaped quote
+					value.WriteByte('\'')
+					i++
+					continue
+				}
+				break
+			}
+			value.WriteByte(def[i])
+		}
+		if i >= len(def) {
+			return nil, errors.NotValid.Newf("[dmlgen] Column type %q contains an unterminated value", columnType)
+		}
+		values = append(values, value.String())
+		def = strings.TrimPrefix(def[i+1:], ",")
+	}
+	return values, nil
+}
+
+func (t *Table) enumTypeName(ec enumColumn) string {
+	return t.EntityName() + strs.ToGoCamelCase(ec.column.Field)
+}
+
+func (t *Table) enumConstName(ec enumColumn, value string) string {
+	name := strs.ToGoCamelCase(value)
+	if name == "" {
+		name = "Empty"
+	}
+	return t.enumTypeName(ec) + name
+}
+
+// fnEntityEnums writes for each enum column a string type with its constants
+// and the IsValid method.
+func (t *Table) fnEntityEnums(mainGen *codegen.Go, g *Generator) {
+	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityStruct) {
+		return
+	}
+	for _, ec := range t.enumColumns {
+		typeName := t.enumTypeName(ec)
+		mainGen.C(typeName, `defines the allowed values of column`, t.Table.Name+`.`+ec.column.Field+`. Auto generated.`)
+		mainGen.Pln(`type `, typeName, ` string`)
+
+		mainGen.Pln(`const (`)
+		{
+			mainGen.In()
+			for _, v := range ec.values {
+				mainGen.Pln(t.enumConstName(ec, v), typeName, ` = `, strconv.Quote(v))
+			}
+			mainGen.Out()
+		}
+		mainGen.Pln(`)`)
+
+		mainGen.C(`IsValid reports whether v is one of the allowed values of column`, ec.column.Field+`. Auto generated.`)
+		mainGen.Pln(`func (v `, typeName, `) IsValid() bool {`)
+		{
+			mainGen.In()
+			mainGen.Pln(`switch v {`)
+			consts := make([]string, len(ec.values))
+			for i, v := range ec.values {
+				consts[i] = t.enumConstName(ec, v)
+			}
+			mainGen.Pln(`case `, strings.Join(consts, ", "), `:`)
+			mainGen.Pln(`return true`)
+			mainGen.Pln(`}`)
+			mainGen.Pln(`return false`)
+			mainGen.Out()
+		}
+		mainGen.Pln(`}`)
+	}
+}
+
+// fnCollectionEnumFilters writes for each enum column a FilterBy function
+// which returns a new collection.
+func (t *Table) fnCollectionEnumFilters(mainGen *codegen.Go, g *Generator) {
+	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureCollectionStruct|FeatureEntityStruct, 'a') {
+		return
+	}
+	for _, ec := range t.enumColumns {
+		typeName := t.enumTypeName(ec)
+		fieldName := t.GoCamelMaybePrivate(ec.column.Field)
+		mainGen.C(`FilterBy`+strs.ToGoCamelCase(ec.column.Field), `returns a new collection with the entities whose column`,
+			ec.column.Field, `equals v. Auto generated.`)
+		mainGen.Pln(`func (cc *`+t.CollectionName()+`) FilterBy`+strs.ToGoCamelCase(ec.column.Field)+`(v `+typeName+`) *`+t.CollectionName(), `{`)
+		{
+			mainGen.In()
+			mainGen.Pln(`ret := &`, t.CollectionName(), `{}`)
+			mainGen.Pln(`if cc == nil {	return ret }`)
+			mainGen.Pln(`for _, e := range cc.Data {`)
+			{
+				mainGen.In()
+				mainGen.Pln(`if e != nil && e.`+fieldName, `== string(v) {`)
+				{
+					mainGen.Pln(`ret.Data = append(ret.Data, e)`)
+				}
+				mainGen.Pln(`}`)
+				mainGen.Out()
+			}
+			mainGen.Pln(`}`)
+			mainGen.Pln(`return ret`)
+			mainGen.Out()
+		}
+		mainGen.Pln(`}`)
+	}
+}
+
+// generateTestEnums checks the constants and an invalid value.
+func (t *Table) generateTestEnums(testGen *codegen.Go, g *Generator) (codeWritten int) {
+	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityStruct) {
+		return 0
+	}
+	for _, ec := range t.enumColumns {
+		typeName := t.enumTypeName(ec)
+		testGen.Pln(`t.Run("` + typeName + `_IsValid", func(t *testing.T) {`)
+		{
+			for _, v := range ec.values {
+				testGen.Pln(`assert.True(t, ` + t.enumConstName(ec, v) + `.IsValid())`)
+			}
+			invalid := strconv.Quote("invalid_" + ec.column.Field)
+			testGen.Pln(`assert.False(t, ` + typeName + `(` + invalid + `).IsValid())`)
+			if g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureCollectionStruct) {
+				first := t.enumConstName(ec, ec.values[0])
+				fieldName := t.GoCamelMaybePrivate(ec.column.Field)
+				testGen.Pln(`c := &` + t.CollectionName() + `{Data: []*` + t.EntityName() + `{{` + fieldName + `: string(` + first + `)}, {` +
+					fieldName + `: ` + invalid + `}, nil}}`)
+				testGen.Pln(`assert.Len(t, c.FilterBy` + strs.ToGoCamelCase(ec.column.Field) + `(` + first + `).Data, 1)`)
+			}
+		}
+		testGen.Pln(`})`) // end t.Run
+		codeWritten++
+	}
+	return codeWritten
+}
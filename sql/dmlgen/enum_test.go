@@ -0,0 +1,86 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestParseEnumValues(t *testing.T) {
+	values, err := parseEnumValues("enum('default','websites','it''s','')")
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"default", "websites", "it's", ""}, values)
+
+	_, err = parseEnumValues("varchar(8)")
+	assert.ErrorIsKind(t, errors.NotValid, err)
+	_, err = parseEnumValues("enum('a")
+	assert.ErrorIsKind(t, errors.NotValid, err)
+	_, err = parseEnumValues("enum(a)")
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
+
+func TestGenerator_EnumColumns(t *testing.T) {
+	cols := func() ddl.Columns {
+		return ddl.Columns{
+			&ddl.Column{Field: "config_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "scope", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(8)"},
+			&ddl.Column{Field: "state", Pos: 3, Null: "NO", DataType: "enum", ColumnType: "enum('new','processing')"},
+			&ddl.Column{Field: "path", Pos: 4, Null: "YES", DataType: "varchar", ColumnType: "varchar(255)"},
+		}
+	}
+	generate := func(t *testing.T, opts ...Option) (string, string) {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			append([]Option{WithTable("core_config_data", cols())}, opts...)...,
+		)
+		assert.NoError(t, err)
+		var buf, bufTest bytes.Buffer
+		assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+		return buf.String(), bufTest.String()
+	}
+
+	t.Run("values and introspection", func(t *testing.T) {
+		have, haveTest := generate(t, WithTableConfig("core_config_data", &TableConfig{
+			EnumColumns: map[string][]string{"scope": {"default", "websites", "stores"}, "state": nil},
+		}))
+		assert.Contains(t, have, "type CoreConfigDataScope string")
+		assert.Contains(t, have, `CoreConfigDataScopeWebsites CoreConfigDataScope = "websites"`)
+		assert.Contains(t, have, "case CoreConfigDataScopeDefault, CoreConfigDataScopeWebsites, CoreConfigDataScopeStores:")
+		assert.Contains(t, have, `CoreConfigDataStateProcessing CoreConfigDataState = "processing"`)
+		assert.Contains(t, have, "func (cc *CoreConfigDatas) FilterByScope(v CoreConfigDataScope) *CoreConfigDatas {")
+		assert.Contains(t, have, "Scope string")
+		assert.Contains(t, haveTest, `assert.False(t, CoreConfigDataScope("invalid_scope").IsValid())`)
+		assert.Contains(t, haveTest, "c.FilterByScope(CoreConfigDataScopeDefault).Data")
+	})
+
+	t.Run("no enum columns", func(t *testing.T) {
+		have, _ := generate(t)
+		assert.NotContains(t, have, "IsValid")
+	})
+
+	t.Run("column not found", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config_data", cols()),
+			WithTableConfig("core_config_data", &TableConfig{EnumColumns: map[string][]string{"website": {"a"}}}),
+		)
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+
+	t.Run("column nullable", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config_data", cols()),
+			WithTableConfig("core_config_data", &TableConfig{EnumColumns: map[string][]string{"path": {"a"}}}),
+		)
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+
+	t.Run("values required", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config_data", cols()),
+			WithTableConfig("core_config_data", &TableConfig{EnumColumns: map[string][]string{"scope": nil}}),
+		)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
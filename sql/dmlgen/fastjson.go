@@ -0,0 +1,284 @@
+package dmlgen
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+func (to *TableConfig) applyJSONOmitEmpty(t *Table) {
+	for i := 0; i < len(to.JSONOmitEmpty) && to.lastErr == nil; i++ {
+		cn := to.JSONOmitEmpty[i]
+		if !t.Table.Columns.Contains(cn) {
+			to.lastErr = errors.NotFound.Newf("[dmlgen] WithTableConfig:JSONOmitEmpty: For table %q the Column %q cannot be found.",
+				t.Table.Name, cn)
+			return
+		}
+		if t.jsonOmitEmpty == nil {
+			t.jsonOmitEmpty = make(map[string]bool)
+		}
+		t.jsonOmitEmpty[cn] = true
+	}
+}
+
+// hasFastJSON reports whether the hand written JSON methods must be generated.
+// The features must be explicitly included because the methods replace the
+// default encoding/json behaviour. Tables with an easyjson marshaler are
+// skipped.
+func (t *Table) hasFastJSON(g *Generator, f FeatureToggle) bool {
+	return !t.HasEasyJSONMarshaler && (t.featuresInclude|g.defaultTableConfig.FeaturesInclude)&f != 0 &&
+		g.hasFeature(t.featuresInclude, t.featuresExclude, f)
+}
+
+// jsonField returns the JSON object key of a column, the same as encoding/json
+// would use, and whether the column has been tagged with omitempty.
+func (t *Table) jsonField(c *ddl.Column) (key string, omitEmpty bool) {
+	key = strs.ToGoCamelCase(c.Field)
+	tag, ok := reflect.StructTag(c.StructTag).Lookup("json")
+	if !ok {
+		return key, false
+	}
+	name, opts := tag, ""
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		name, opts = tag[:i], tag[i:]
+	}
+	if name != "" {
+		key = name
+	}
+	return key, strings.Contains(opts, ",omitempty")
+}
+
+func (t *Table) fnEntityFastJSON(mainGen *codegen.Go, g *Generator) {
+	if !t.hasFastJSON(g, FeatureEntityFastJSON) {
+		return
+	}
+
+	mainGen.C(`AppendJSON appends the JSON object of the entity to dst without using reflection. Auto generated.`)
+	mainGen.Pln(`func (e *`, t.EntityName(), `) AppendJSON(dst []byte) ([]byte, error) {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`if e == nil { return append(dst, "null"...), nil }`)
+		mainGen.C(`The first comma gets replaced with the opening brace.`)
+		mainGen.Pln(`start := len(dst)`)
+		t.Table.Columns.Each(func(c *ddl.Column) {
+			if t.IsFieldPrivate(c.Field) {
+				return
+			}
+			key, tagOmitEmpty := t.jsonField(c)
+			if key == "-" {
+				return
+			}
+			f := `e.` + strs.ToGoCamelCase(c.Field)
+			gt := g.goTypeNull(c)
+			omitEmpty := t.jsonOmitEmpty[c.Field]
+
+			var notEmpty, appendFn string
+			switch {
+			case g.isCustomType(c):
+				appendFn = `if raw, err := json.Marshal(` + f + `); err != nil {
+					return nil, errors.WithStack(err)
+				} else {
+					dst = append(dst, raw...)
+				}`
+				omitEmpty = false // custom types cannot be checked for emptiness
+			case strings.HasPrefix(gt, "null."):
+				notEmpty = f + `.Valid`
+				appendFn = `dst = ` + f + `.AppendJSON(dst)`
+			case gt == "time.Time":
+				notEmpty = `!` + f + `.IsZero()`
+				appendFn = `dst = null.AppendJSONTime(dst, ` + f + `)`
+			case gt == "string":
+				notEmpty = f + ` != ""`
+				appendFn = `dst = null.AppendJSONString(dst, ` + f + `)`
+				omitEmpty = omitEmpty || tagOmitEmpty
+			case gt == "[]byte":
+				notEmpty = `len(` + f + `) > 0`
+				appendFn = `dst = null.AppendJSONBytes(dst, ` + f + `)`
+				omitEmpty = omitEmpty || tagOmitEmpty
+			case gt == "bool":
+				notEmpty = f
+				appendFn = `dst = strconv.AppendBool(dst, ` + f + `)`
+				omitEmpty = omitEmpty || tagOmitEmpty
+			case strings.HasPrefix(gt, "float"):
+				notEmpty = f + ` != 0`
+				appendFn = `dst = null.AppendJSONFloat64(dst, float64(` + f + `))`
+				omitEmpty = omitEmpty || tagOmitEmpty
+			case strings.HasPrefix(gt, "uint"):
+				notEmpty = f + ` != 0`
+				appendFn = `dst = strconv.AppendUint(dst, uint64(` + f + `), 10)`
+				omitEmpty = omitEmpty || tagOmitEmpty
+			default: // int types
+				notEmpty = f + ` != 0`
+				appendFn = `dst = strconv.AppendInt(dst, int64(` + f + `), 10)`
+				omitEmpty = omitEmpty || tagOmitEmpty
+			}
+			if omitEmpty {
+				mainGen.Pln(`if`, notEmpty, `{`)
+			}
+			mainGen.Pln(`dst = append(dst, `, strconv.Quote(`,`+strconv.Quote(key)+`:`), `...)`)
+			mainGen.Pln(appendFn)
+			if omitEmpty {
+				mainGen.Pln(`}`)
+			}
+		})
+		if len(t.availableRelationships) > 0 {
+			mainGen.Pln(`if e.Relations != nil {`)
+			mainGen.Pln(`	dst = append(dst, ",\"Relations\":"...)`)
+			mainGen.Pln(`	rel, err := json.Marshal(e.Relations)`)
+			mainGen.Pln(`	if err != nil { return nil, errors.WithStack(err) }`)
+			mainGen.Pln(`	dst = append(dst, rel...)`)
+			mainGen.Pln(`}`)
+		}
+		mainGen.Pln(`if len(dst) == start { return append(dst, '{', '}'), nil }`)
+		mainGen.Pln(`dst[start] = '{'`)
+		mainGen.Pln(`return append(dst, '}'), nil`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	mainGen.C(`MarshalJSON implements json.Marshaler without using reflection. Auto generated.`)
+	mainGen.Pln(`func (e *`, t.EntityName(), `) MarshalJSON() ([]byte, error) {`)
+	mainGen.Pln(`	return e.AppendJSON(make([]byte, 0, 512))`)
+	mainGen.Pln(`}`)
+
+	mainGen.C(`UnmarshalJSON implements json.Unmarshaler. Unknown keys are getting ignored. Auto generated.`)
+	mainGen.Pln(`func (e *`, t.EntityName(), `) UnmarshalJSON(data []byte) error {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`if string(data) == "null" { return nil }`)
+		mainGen.Pln(`var fields map[string]json.RawMessage`)
+		mainGen.Pln(`if err := json.Unmarshal(data, &fields); err != nil { return errors.WithStack(err) }`)
+		mainGen.Pln(`for key, raw := range fields {`)
+		{
+			mainGen.In()
+			mainGen.Pln(`var err error`)
+			mainGen.Pln(`switch key {`)
+			t.Table.Columns.Each(func(c *ddl.Column) {
+				if t.IsFieldPrivate(c.Field) {
+					return
+				}
+				key, _ := t.jsonField(c)
+				if key == "-" {
+					return
+				}
+				mainGen.Pln(`case`, strconv.Quote(key), `:`)
+				mainGen.Pln(`	err = json.Unmarshal(raw, &e.`+strs.ToGoCamelCase(c.Field), `)`)
+			})
+			if len(t.availableRelationships) > 0 {
+				mainGen.Pln(`case "Relations":`)
+				mainGen.Pln(`	err = json.Unmarshal(raw, &e.Relations)`)
+			}
+			mainGen.Pln(`}`)
+			mainGen.Pln(`if err != nil { return errors.Wrapf(err, "[` + t.Package + `] ` + t.EntityName() + `.UnmarshalJSON key %q", key) }`)
+			mainGen.Out()
+		}
+		mainGen.Pln(`}`)
+		mainGen.Pln(`return nil`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+}
+
+func (t *Table) fnCollectionFastJSON(mainGen *codegen.Go, g *Generator) {
+	if !t.hasFastJSON(g, FeatureCollectionFastJSON) || !t.hasFastJSON(g, FeatureEntityFastJSON) {
+		return
+	}
+	mainGen.C(`AppendJSON appends the JSON object of the collection to dst without using reflection. Auto generated.`)
+	mainGen.Pln(`func (cc *`, t.CollectionName(), `) AppendJSON(dst []byte) (_ []byte, err error) {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`if cc == nil { return append(dst, "null"...), nil }`)
+		mainGen.Pln(`if len(cc.Data) == 0 { return append(dst, '{', '}'), nil }`)
+		mainGen.Pln(`dst = append(dst, "{\"data\":["...)`)
+		mainGen.Pln(`for i, e := range cc.Data {`)
+		mainGen.Pln(`	if i > 0 { dst = append(dst, ',') }`)
+		mainGen.Pln(`	if dst, err = e.AppendJSON(dst); err != nil { return nil, errors.WithStack(err) }`)
+		mainGen.Pln(`}`)
+		mainGen.Pln(`return append(dst, ']', '}'), nil`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	mainGen.C(`MarshalJSON implements json.Marshaler without using reflection. Auto generated.`)
+	mainGen.Pln(`func (cc *`, t.CollectionName(), `) MarshalJSON() ([]byte, error) {`)
+	mainGen.Pln(`	if cc == nil { return []byte("null"), nil }`)
+	mainGen.Pln(`	return cc.AppendJSON(make([]byte, 0, 256*(len(cc.Data)+1)))`)
+	mainGen.Pln(`}`)
+}
+
+// generateTestFastJSON proves that the generated JSON methods are equivalent
+// to encoding/json by comparing the output after decoding with the
+// reflection based decoder.
+func (t *Table) generateTestFastJSON(testGen *codegen.Go, g *Generator) (codeWritten int) {
+	if !t.hasFastJSON(g, FeatureEntityFastJSON) {
+		return 0
+	}
+	testGen.Pln(`t.Run("` + t.EntityName() + `_FastJSON", func(t *testing.T) {`)
+	{
+		testGen.Pln(`type reflectionType `, t.EntityName(), ` // drops the JSON methods`)
+		testGen.Pln(`for i := 0; i < 25; i++ {`)
+		{
+			testGen.In()
+			testGen.Pln(`e := new(`, t.EntityName(), `)`)
+			testGen.Pln(`assert.NoError(t, ps.FakeData(e))`)
+			testGen.Pln(`data, err := json.Marshal(e)`)
+			testGen.Pln(`assert.NoError(t, err)`)
+			testGen.Pln(`assert.True(t, json.Valid(data), "%s", data)`)
+
+			testGen.Pln(`e2 := new(`, t.EntityName(), `)`)
+			testGen.Pln(`assert.NoError(t, json.Unmarshal(data, e2))`)
+			testGen.Pln(`data2, err := e2.MarshalJSON()`)
+			testGen.Pln(`assert.NoError(t, err)`)
+			testGen.Pln(`assert.Exactly(t, string(data), string(data2))`)
+
+			testGen.Pln(`e3 := new(reflectionType)`)
+			testGen.Pln(`assert.NoError(t, json.Unmarshal(data, e3))`)
+			testGen.Pln(`data3, err := (*`, t.EntityName(), `)(e3).MarshalJSON()`)
+			testGen.Pln(`assert.NoError(t, err)`)
+			testGen.Pln(`assert.Exactly(t, string(data), string(data3))`)
+			testGen.Out()
+		}
+		testGen.Pln(`}`)
+	}
+	testGen.Pln(`})`) // end t.Run
+	return 1
+}
+
+// fnBenchmarkFastJSON writes a benchmark per table which compares the
+// generated JSON encoder with the reflection based encoding/json.
+func (g *Generator) fnBenchmarkFastJSON(testGen *codegen.Go, tbls tables) {
+	for _, t := range tbls {
+		if !t.hasFastJSON(g, FeatureEntityFastJSON) {
+			continue
+		}
+		testGen.Pln(`func Benchmark` + t.EntityName() + `_FastJSON(b *testing.B) {`)
+		{
+			testGen.In()
+			testGen.Pln(`ps := pseudo.MustNewService(0, &pseudo.Options{Lang: "de",MaxFloatDecimals:6})`)
+			testGen.Pln(`e := new(`, t.EntityName(), `)`)
+			testGen.Pln(`if err := ps.FakeData(e); err != nil { b.Fatal(err) }`)
+			testGen.Pln(`type reflectionType `, t.EntityName(), ` // drops the JSON methods`)
+			testGen.Pln(`b.Run("AppendJSON", func(b *testing.B) {`)
+			testGen.Pln(`	b.ReportAllocs()`)
+			testGen.Pln(`	buf := make([]byte, 0, 4096)`)
+			testGen.Pln(`	for i := 0; i < b.N; i++ {`)
+			testGen.Pln(`		var err error`)
+			testGen.Pln(`		if buf, err = e.AppendJSON(buf[:0]); err != nil { b.Fatal(err) }`)
+			testGen.Pln(`	}`)
+			testGen.Pln(`})`)
+			testGen.Pln(`b.Run("encoding/json", func(b *testing.B) {`)
+			testGen.Pln(`	b.ReportAllocs()`)
+			testGen.Pln(`	for i := 0; i < b.N; i++ {`)
+			testGen.Pln(`		if _, err := json.Marshal((*reflectionType)(e)); err != nil { b.Fatal(err) }`)
+			testGen.Pln(`	}`)
+			testGen.Pln(`})`)
+			testGen.Out()
+		}
+		testGen.Pln(`}`)
+	}
+}
@@ -0,0 +1,56 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestGenerator_FastJSON(t *testing.T) {
+	cols := func() ddl.Columns {
+		return ddl.Columns{
+			&ddl.Column{Field: "config_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "path", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)"},
+			&ddl.Column{Field: "value", Pos: 3, Null: "YES", DataType: "text", ColumnType: "text"},
+		}
+	}
+
+	gen := func(t *testing.T, opts ...Option) string {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			append([]Option{WithTable("core_config", cols())}, opts...)...)
+		assert.NoError(t, err)
+		var buf, bufTest bytes.Buffer
+		assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+		return buf.String() + bufTest.String()
+	}
+
+	t.Run("not included by default", func(t *testing.T) {
+		have := gen(t)
+		assert.NotContains(t, have, "AppendJSON")
+	})
+
+	t.Run("included", func(t *testing.T) {
+		have := gen(t, WithTableConfig("core_config", &TableConfig{
+			StructTags:      []string{"json"},
+			JSONOmitEmpty:   []string{"value"},
+			FeaturesInclude: FeatureEntityStruct | FeatureCollectionStruct | FeatureEntityFastJSON | FeatureCollectionFastJSON,
+		}))
+		assert.Contains(t, have, "func (e *CoreConfig) AppendJSON(dst []byte) ([]byte, error) {")
+		assert.Contains(t, have, "func (cc *CoreConfigs) MarshalJSON() ([]byte, error) {")
+		assert.Contains(t, have, "if e.Value.Valid {")
+		assert.Contains(t, have, `dst = append(dst, ",\"path\":"...)`)
+		assert.Contains(t, have, `t.Run("CoreConfig_FastJSON"`)
+		assert.Contains(t, have, "func BenchmarkCoreConfig_FastJSON(b *testing.B) {")
+	})
+
+	t.Run("unknown omitempty column", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config", cols()),
+			WithTableConfig("core_config", &TableConfig{JSONOmitEmpty: []string{"not_found"}}),
+		)
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+}
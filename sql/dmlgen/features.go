@@ -12,8 +12,12 @@ const (
 	FeatureCollectionCut
 	FeatureCollectionDelete
 	FeatureCollectionEach
+	FeatureCollectionFastJSON // must be explicitly included, requires FeatureEntityFastJSON
 	FeatureCollectionFilter
+	FeatureCollectionFirstLast
 	FeatureCollectionInsert
+	FeatureCollectionSort   // SortBy functions for indexed columns
+	FeatureCollectionSplit  // SplitBy functions for non-unique indexed columns
 	FeatureCollectionStruct // creates the struct type
 	FeatureCollectionSwap
 	FeatureCollectionUniqueGetters
@@ -31,8 +35,10 @@ const (
 	FeatureDBTableColumnNames
 	FeatureEntityCopy
 	FeatureEntityEmpty
+	FeatureEntityFastJSON // must be explicitly included
 	FeatureEntityGetSetPrivateFields
 	FeatureEntityIsSet
+	FeatureEntityProto // ToProto and FromProto, see SerializerConfig.EntityConverters
 	FeatureEntityRelationships
 	FeatureEntityStruct // creates the struct type
 	FeatureEntityValidate
@@ -45,8 +51,12 @@ var featureNames = map[FeatureToggle]string{
 	FeatureCollectionCut:               "FeatureCollectionCut",
 	FeatureCollectionDelete:            "FeatureCollectionDelete",
 	FeatureCollectionEach:              "FeatureCollectionEach",
+	FeatureCollectionFastJSON:          "FeatureCollectionFastJSON",
 	FeatureCollectionFilter:            "FeatureCollectionFilter",
+	FeatureCollectionFirstLast:         "FeatureCollectionFirstLast",
 	FeatureCollectionInsert:            "FeatureCollectionInsert",
+	FeatureCollectionSort:              "FeatureCollectionSort",
+	FeatureCollectionSplit:             "FeatureCollectionSplit",
 	FeatureCollectionStruct:            "FeatureCollectionStruct",
 	FeatureCollectionSwap:              "FeatureCollectionSwap",
 	FeatureCollectionUniqueGetters:     "FeatureCollectionUniqueGetters",
@@ -63,8 +73,10 @@ var featureNames = map[FeatureToggle]string{
 	FeatureDBUpsert:                    "FeatureDBUpsert",
 	FeatureEntityCopy:                  "FeatureEntityCopy",
 	FeatureEntityEmpty:                 "FeatureEntityEmpty",
+	FeatureEntityFastJSON:              "FeatureEntityFastJSON",
 	FeatureEntityGetSetPrivateFields:   "FeatureEntityGetSetPrivateFields",
 	FeatureEntityIsSet:                 "FeatureEntityIsSet",
+	FeatureEntityProto:                 "FeatureEntityProto",
 	FeatureEntityRelationships:         "FeatureEntityRelationships",
 	FeatureEntityStruct:                "FeatureEntityStruct",
 	FeatureEntityValidate:              "FeatureEntityValidate",
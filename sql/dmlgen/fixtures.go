@@ -0,0 +1,343 @@
+package dmlgen
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+// fixtureChunkSize defines the number of rows per generated INSERT statement.
+const fixtureChunkSize = 500
+
+var fixtureImportPaths = []string{
+	"context",
+	"database/sql",
+	"fmt",
+	"io",
+	"github.com/corestoreio/errors",
+	"github.com/corestoreio/pkg/sql/dml",
+	"github.com/corestoreio/pkg/storage/null",
+	"github.com/corestoreio/pkg/util/pseudo",
+}
+
+// fixtureForeignKey describes a foreign key column whose values get taken from
+// the auto increment column of the referenced table.
+type fixtureForeignKey struct {
+	column    *ddl.Column
+	refTable  string
+	refColumn string
+}
+
+func (fk fixtureForeignKey) isSelf(t *Table) bool { return fk.refTable == t.Table.Name }
+
+// fixtureForeignKeys returns all foreign keys of a table which reference an
+// integer auto increment column of a known table.
+func (t *Table) fixtureForeignKeys(g *Generator) []fixtureForeignKey {
+	kcuc, ok := g.kcu[t.Table.Name]
+	if !ok {
+		return nil
+	}
+	var fks []fixtureForeignKey
+	for _, kcuce := range kcuc.Data {
+		if !kcuce.ReferencedTableName.Valid {
+			continue
+		}
+		rt, ok := g.Tables[kcuce.ReferencedTableName.Data]
+		if !ok {
+			continue
+		}
+		c := t.Table.Columns.ByField(kcuce.ColumnName)
+		rc := rt.Table.Columns.ByField(kcuce.ReferencedColumnName.Data)
+		if c.Field == "" || rc.Field == "" || !rc.IsAutoIncrement() || g.isCustomType(c) || !isIntegerGoType(g.goType(c)) {
+			continue
+		}
+		fks = append(fks, fixtureForeignKey{
+			column:    c,
+			refTable:  rt.Table.Name,
+			refColumn: rc.Field,
+		})
+	}
+	return fks
+}
+
+func isIntegerGoType(gt string) bool {
+	return strings.HasPrefix(gt, "int") || strings.HasPrefix(gt, "uint")
+}
+
+// fixtureTables returns all tables for which fixtures can be generated. The
+// tables are sorted by their foreign keys, hence referenced tables come first.
+// Circular references keep the alphabetical order.
+func (g *Generator) fixtureTables() tables {
+	candidates := make(map[string]*Table, len(g.Tables))
+	var names []string
+	for _, tn := range g.sortedTableNames() {
+		t := g.Tables[tn]
+		if t.Table.IsView() || !t.hasFeature(g, FeatureEntityStruct|FeatureCollectionStruct|FeatureDBMapColumns) {
+			continue
+		}
+		candidates[tn] = t
+		names = append(names, tn)
+	}
+
+	sorted := make(tables, 0, len(candidates))
+	visited := make(map[string]bool, len(candidates))
+	var visit func(t *Table)
+	visit = func(t *Table) {
+		if visited[t.Table.Name] {
+			return
+		}
+		visited[t.Table.Name] = true
+		for _, fk := range t.fixtureForeignKeys(g) {
+			if rt, ok := candidates[fk.refTable]; ok && !fk.isSelf(t) {
+				visit(rt)
+			}
+		}
+		sorted = append(sorted, t)
+	}
+	for _, tn := range names {
+		visit(candidates[tn])
+	}
+	return sorted
+}
+
+// fixtureInsertColumns returns the columns of the INSERT statement for the SQL
+// dump. Contrary to ddl.Table.Insert the auto increment column gets included.
+func (t *Table) fixtureInsertColumns() []string {
+	var cols []string
+	for _, c := range t.Table.Columns {
+		if c.IsAutoIncrement() || !(c.IsCurrentTimestamp() || c.IsGenerated() || c.IsSystemVersioned()) {
+			cols = append(cols, strconv.Quote(c.Field))
+		}
+	}
+	return cols
+}
+
+// GenerateFixtures writes Go source code into w which generates seed data for
+// all tables. For each table the file contains a function
+// Seed[EntityName](ctx, dbm, n, ps) which inserts n rows with fake data in
+// chunks. Foreign key columns, which reference an auto increment column, get
+// the values of the referenced table via a pseudo.RecordGraph created by the
+// generated function NewFixtureRecordGraph. The function SeedAll calls all
+// seed functions in the order of the foreign keys. The function SeedAllSQL
+// writes the fake data as INSERT statements into an io.Writer. The fake data
+// gets generated by a pseudo.Service which respects the WithTagFakeFunc
+// options and requires the option pseudo.WithRecordGraph. Create the
+// pseudo.Service with a non-zero seed to generate reproducible data.
+func (g *Generator) GenerateFixtures(w io.Writer) error {
+	tbls := g.fixtureTables()
+	if len(tbls) == 0 {
+		return errors.NotFound.Newf("[dmlgen] GenerateFixtures: No tables found which support fixtures. Requires %s.",
+			FeatureEntityStruct|FeatureCollectionStruct|FeatureDBMapColumns)
+	}
+
+	fixGen := codegen.NewGo(g.Package)
+	fixGen.SecondLineComments = []string{"Generated by sql/dmlgen. DO NOT EDIT."}
+	fixGen.BuildTags = g.BuildTags
+
+	fixGen.C(`fixtureChunkSize defines the number of rows per INSERT statement.`)
+	fixGen.Pln(`const fixtureChunkSize =`, fixtureChunkSize)
+	fixGen.C(`fixtureSQLWriter writes the interpolated statements into w instead of executing them.`)
+	fixGen.Pln(`type fixtureSQLWriter struct {
+		dml.QueryExecPreparer
+		w io.Writer
+	}
+	func (sw fixtureSQLWriter) ExecContext(_ context.Context, query string, _ ...interface{}) (sql.Result, error) {
+		_, err := fmt.Fprintf(sw.w, "%s;\n", query)
+		return dml.StaticSQLResult{}, err
+	}`)
+	fixGen.C(`fixtureIDs returns the numbers from 1 to n.`)
+	fixGen.Pln(`func fixtureIDs(n int) []int64 {
+		ids := make([]int64, n)
+		for i := range ids {
+			ids[i] = int64(i + 1)
+		}
+		return ids
+	}`)
+
+	g.fnFixtureRecordGraph(fixGen, tbls)
+	for _, t := range tbls {
+		t.fnFixtureFake(fixGen)
+		t.fnFixtureSeed(fixGen, g)
+	}
+	g.fnFixtureSeedAll(fixGen, tbls)
+
+	pkgs, err := findUsedPackages(fixGen.Bytes(), append(fixtureImportPaths, g.customTypeImportPaths()...))
+	if err != nil {
+		_, _ = w.Write(fixGen.Bytes()) // write for debug reasons
+		return errors.WithStack(err)
+	}
+	fixGen.AddImports(pkgs...)
+	return errors.WithStack(fixGen.GenerateFile(w))
+}
+
+func (g *Generator) fnFixtureRecordGraph(fixGen *codegen.Go, tbls tables) {
+	fixGen.C(`NewFixtureRecordGraph creates a pseudo.RecordGraph which contains the foreign keys of all tables. Create
+the pseudo.Service for the Seed functions with option pseudo.WithRecordGraph(NewFixtureRecordGraph()). A nullable
+foreign key column which references its own table gets set to NULL.`)
+	fixGen.Pln(`func NewFixtureRecordGraph() *pseudo.RecordGraph {
+		rg := pseudo.NewRecordGraph()`)
+	fixGen.In()
+	for _, t := range tbls {
+		fixGen.Pln(`rg.MapType(`, strconv.Quote(g.Package+"."+t.EntityName()), `, `, strconv.Quote(t.Table.Name), `)`)
+		for _, fk := range t.fixtureForeignKeys(g) {
+			if fk.isSelf(t) && !fk.column.IsNull() {
+				continue
+			}
+			fixGen.Pln(`rg.AddForeignKey(`, strconv.Quote(t.Table.Name), `, `, strconv.Quote(fk.column.Field), `, `,
+				strconv.Quote(fk.refTable), `, `, strconv.Quote(fk.refColumn), `)`)
+		}
+	}
+	fixGen.Pln(`return rg`)
+	fixGen.Out()
+	fixGen.Pln(`}`)
+
+	fixGen.C(`fixtureRecordGraph returns the pseudo.RecordGraph of ps.`)
+	fixGen.Pln(`func fixtureRecordGraph(ps *pseudo.Service) (*pseudo.RecordGraph, error) {
+		rg := ps.RecordGraph()
+		if rg == nil {
+			return nil, errors.NotValid.Newf("[` + g.Package + `] The pseudo.Service requires the option pseudo.WithRecordGraph(NewFixtureRecordGraph())")
+		}
+		return rg, nil
+	}`)
+}
+
+// fnFixtureRecordGraphOf writes the check that ps contains a RecordGraph. If
+// assign is true, the RecordGraph gets assigned to variable rg.
+func fnFixtureRecordGraphOf(fixGen *codegen.Go, assign bool) {
+	if assign {
+		fixGen.Pln(`rg, err := fixtureRecordGraph(ps)
+			if err != nil {
+				return errors.WithStack(err)
+			}`)
+		return
+	}
+	fixGen.Pln(`if _, err := fixtureRecordGraph(ps); err != nil {
+		return errors.WithStack(err)
+	}`)
+}
+
+func (t *Table) fnFixtureFake(fixGen *codegen.Go) {
+	fixGen.C(`fake`+t.CollectionName(), `creates n entities with fake data. The foreign key columns get the values
+registered in the pseudo.RecordGraph of ps.`)
+	fixGen.Pln(`func fake`+t.CollectionName(), `(n int, ps *pseudo.Service) (*`, t.CollectionName(), `, error) {
+		cc := &`, t.CollectionName(), `{Data: make([]*`, t.EntityName(), `, 0, n)}
+		for i := 0; i < n; i++ {
+			e := new(`, t.EntityName(), `)
+			if err := ps.FakeData(e); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			cc.Data = append(cc.Data, e)
+		}
+		return cc, nil
+	}`)
+}
+
+func (t *Table) fnFixtureSeed(fixGen *codegen.Go, g *Generator) {
+	fixGen.C(`Seed`+t.EntityName(), `inserts n rows with fake data into table`, strconv.Quote(t.Table.Name), `in chunks of
+fixtureChunkSize rows. Foreign key columns get the values of the referenced tables, which get registered in the
+pseudo.RecordGraph of ps. Those tables must be seeded first, see SeedAll.`)
+	fixGen.Pln(`func Seed`+t.EntityName(), `(ctx context.Context, dbm *DBM, n int, ps *pseudo.Service) error {`)
+	fixGen.In()
+	var parentFKs []fixtureForeignKey
+	for _, fk := range t.fixtureForeignKeys(g) {
+		if !fk.isSelf(t) {
+			parentFKs = append(parentFKs, fk)
+		}
+	}
+	fnFixtureRecordGraphOf(fixGen, len(parentFKs) > 0)
+	for _, fk := range parentFKs {
+		fixGen.Pln(`{
+			ids, err := dbm.ConnPool.WithQueryBuilder(dml.NewSelect(`, strconv.Quote(fk.refColumn), `).From(`, constTableName(fk.refTable), `).OrderBy(`, strconv.Quote(fk.refColumn), `)).LoadInt64s(ctx, nil)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			rg.Unregister(`, strconv.Quote(fk.refTable), `, `, strconv.Quote(fk.refColumn), `)
+			rg.RegisterInt64s(`, strconv.Quote(fk.refTable), `, `, strconv.Quote(fk.refColumn), `, ids...)
+		}`)
+	}
+	fixGen.Out()
+	fixGen.Pln(`	cc, err := fake`+t.CollectionName(), `(n, ps)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		tbl := dbm.MustTable(`, constTableName(t.Table.Name), `)
+		for data := cc.Data; len(data) > 0; {
+			chunk := data
+			if len(chunk) > fixtureChunkSize {
+				chunk = chunk[:fixtureChunkSize]
+			}
+			data = data[len(chunk):]
+			if _, err := dbm.ConnPool.WithQueryBuilder(tbl.Insert().SetRowCount(len(chunk)).BuildValues()).ExecContext(ctx, &`, t.CollectionName(), `{Data: chunk}); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		return nil
+	}`)
+}
+
+func (g *Generator) fnFixtureSeedAll(fixGen *codegen.Go, tbls tables) {
+	fixGen.C(`SeedAll inserts n rows with fake data into each table. The tables get seeded in the order of their
+foreign keys.`)
+	fixGen.Pln(`func SeedAll(ctx context.Context, dbm *DBM, n int, ps *pseudo.Service) error {`)
+	fixGen.In()
+	for _, t := range tbls {
+		fixGen.Pln(`if err := Seed`+t.EntityName(), `(ctx, dbm, n, ps); err != nil {
+			return errors.WithStack(err)
+		}`)
+	}
+	fixGen.Pln(`return nil`)
+	fixGen.Out()
+	fixGen.Pln(`}`)
+
+	fixGen.C(`SeedAllSQL writes INSERT statements with n rows of fake data per table into w. The auto increment
+columns get the values 1 to n assigned to resolve the foreign keys, hence the tables must be empty when importing
+the statements.`)
+	fixGen.Pln(`func SeedAllSQL(w io.Writer, n int, ps *pseudo.Service) error {
+		ctx := context.Background()
+		sw := fixtureSQLWriter{w: w}`)
+	fixGen.In()
+	referenced := make(map[string]bool)
+	for _, t := range tbls {
+		for _, fk := range t.fixtureForeignKeys(g) {
+			if !fk.isSelf(t) {
+				referenced[fk.refTable+"."+fk.refColumn] = true
+			}
+		}
+	}
+	fnFixtureRecordGraphOf(fixGen, len(referenced) > 0)
+	for _, t := range tbls {
+		fixGen.Pln(`{`)
+		fixGen.In()
+		fixGen.Pln(`cc, err := fake`+t.CollectionName(), `(n, ps)
+			if err != nil {
+				return errors.WithStack(err)
+			}`)
+		if ai := t.Table.Columns.Filter(func(c *ddl.Column) bool { return c.IsAutoIncrement() }).First(); ai != nil {
+			fixGen.Pln(`for i, e := range cc.Data {
+				e.`, t.GoCamelMaybePrivate(ai.Field), ` = `, g.goType(ai), `(i + 1)
+			}`)
+			fixGen.Pln(referenced[t.Table.Name+"."+ai.Field], `rg.Unregister(`, strconv.Quote(t.Table.Name), `, `, strconv.Quote(ai.Field), `)
+			rg.RegisterInt64s(`, strconv.Quote(t.Table.Name), `, `, strconv.Quote(ai.Field), `, fixtureIDs(n)...)`)
+		}
+		fixGen.Pln(`for data := cc.Data; len(data) > 0; {
+				chunk := data
+				if len(chunk) > fixtureChunkSize {
+					chunk = chunk[:fixtureChunkSize]
+				}
+				data = data[len(chunk):]
+				ins := dml.NewInsert(`, constTableName(t.Table.Name), `).AddColumns(`, strings.Join(t.fixtureInsertColumns(), ", "), `).SetRowCount(len(chunk)).BuildValues()
+				if _, err := ins.WithDBR(sw).Interpolate().ExecContext(ctx, &`, t.CollectionName(), `{Data: chunk}); err != nil {
+					return errors.WithStack(err)
+				}
+			}`)
+		fixGen.Out()
+		fixGen.Pln(`}`)
+	}
+	fixGen.Pln(`return nil`)
+	fixGen.Out()
+	fixGen.Pln(`}`)
+}
@@ -0,0 +1,59 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestGenerator_GenerateFixtures(t *testing.T) {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+		WithTable("store", ddl.Columns{
+			&ddl.Column{Field: "store_id", Pos: 1, Null: "NO", DataType: "smallint", ColumnType: "smallint(5) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "code", Pos: 2, Null: "YES", DataType: "varchar", ColumnType: "varchar(32)", Key: "UNI"},
+			&ddl.Column{Field: "website_id", Pos: 3, Null: "NO", DataType: "smallint", ColumnType: "smallint(5) unsigned", Key: "MUL"},
+		}),
+		WithTable("store_website", ddl.Columns{
+			&ddl.Column{Field: "website_id", Pos: 1, Null: "NO", DataType: "smallint", ColumnType: "smallint(5) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "name", Pos: 2, Null: "YES", DataType: "varchar", ColumnType: "varchar(64)"},
+		}),
+	)
+	assert.NoError(t, err)
+	g.kcu = map[string]ddl.KeyColumnUsageCollection{
+		"store": {Data: []*ddl.KeyColumnUsage{{
+			TableName:            "store",
+			ColumnName:           "website_id",
+			ReferencedTableName:  null.MakeString("store_website"),
+			ReferencedColumnName: null.MakeString("website_id"),
+		}}},
+	}
+
+	assert.Exactly(t, []string{"store_website", "store"}, g.fixtureTables().names())
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateFixtures(&buf))
+	have := buf.String()
+	assert.Contains(t, have, "func SeedStore(ctx context.Context, dbm *DBM, n int, ps *pseudo.Service) error {")
+	assert.Contains(t, have, "func SeedStoreWebsite(ctx context.Context, dbm *DBM, n int, ps *pseudo.Service) error {")
+	assert.Contains(t, have, `dml.NewSelect("website_id").From(TableNameStoreWebsite).OrderBy("website_id")`)
+	assert.Contains(t, have, "func NewFixtureRecordGraph() *pseudo.RecordGraph {")
+	assert.Contains(t, have, `rg.MapType("dmltestgenerated.Store", "store")`)
+	assert.Contains(t, have, `rg.AddForeignKey("store", "website_id", "store_website", "website_id")`)
+	assert.Contains(t, have, `rg.RegisterInt64s("store_website", "website_id", ids...)`)
+	assert.Contains(t, have, `rg.RegisterInt64s("store_website", "website_id", fixtureIDs(n)...)`)
+	assert.NotContains(t, have, `rg.RegisterInt64s("store", "store_id"`)
+	assert.Contains(t, have, "func fakeStores(n int, ps *pseudo.Service) (*Stores, error) {")
+	assert.Contains(t, have, "e.StoreID = uint16(i + 1)")
+	assert.Contains(t, have, `dml.NewInsert(TableNameStore).AddColumns("store_id", "code", "website_id")`)
+	assert.Contains(t, have, "func SeedAllSQL(w io.Writer, n int, ps *pseudo.Service) error {")
+
+	t.Run("no tables", func(t *testing.T) {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated")
+		assert.NoError(t, err)
+		assert.ErrorIsKind(t, errors.NotFound, g.GenerateFixtures(&buf))
+	})
+}
@@ -0,0 +1,353 @@
+package dmlgen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+// graphQLMaxFirst defines the default and maximum page size of the generated
+// list resolvers.
+const graphQLMaxFirst = 100
+
+func (t *Table) validateGraphQL() error {
+	if !t.graphQL {
+		return nil
+	}
+	pks := t.Table.Columns.PrimaryKeys()
+	if t.Table.IsView() || pks.Len() != 1 || !isGraphQLIntegerColumn(pks.First()) {
+		return errors.NotSupported.Newf("[dmlgen] Table %q: GraphQL requires a table with exactly one integer primary key column", t.Table.Name)
+	}
+	return nil
+}
+
+func (g *Generator) hasGraphQL() bool {
+	for _, t := range g.Tables {
+		if t.graphQL {
+			return true
+		}
+	}
+	return false
+}
+
+func isGraphQLIntegerColumn(c *ddl.Column) bool {
+	switch c.DataType {
+	case "tinyint", "smallint", "mediumint", "int", "bigint":
+		return !c.IsBool()
+	}
+	return false
+}
+
+// graphQLType maps a column to a GraphQL type. Columns which map to a null
+// type in Go are nullable in GraphQL, all others are non-null.
+func graphQLType(c *ddl.Column) string {
+	var typ string
+	switch {
+	case c.IsBool():
+		typ = "Boolean"
+	case c.IsTime():
+		typ = "Time"
+	case c.DataType == "bigint" || (c.DataType == "int" && c.IsUnsigned()):
+		typ = "Int64" // GraphQL Int is a signed 32-bit integer
+	case isGraphQLIntegerColumn(c):
+		typ = "Int"
+	case c.DataType == "float" || c.DataType == "double":
+		typ = "Float"
+	default:
+		typ = "String" // decimals are strings to keep their precision
+	}
+	if !c.IsNull() {
+		typ += "!"
+	}
+	return typ
+}
+
+// graphQLRelations returns the relations of a table which are part of the
+// GraphQL schema. The target table must have GraphQL enabled and the relation
+// must be loadable via the generated LoadRelations function.
+func (t *Table) graphQLRelations(g *Generator) []relationShipInfo {
+	loadable := make(map[string]bool)
+	for _, n := range t.loadRelationNames(g) {
+		loadable[n] = true
+	}
+	var rels []relationShipInfo
+	for _, rs := range t.availableRelationships {
+		if rt := g.Tables[rs.tableName]; loadable[rs.mappedStructFieldName] && rt != nil && rt.graphQL {
+			rels = append(rels, rs)
+		}
+	}
+	return rels
+}
+
+// isOneRelation reports whether the relation points to a single entity.
+func (g *Generator) isOneRelation(rs relationShipInfo) bool {
+	return rs.structName == g.Tables[rs.tableName].EntityName()
+}
+
+// GenerateGraphQL writes the GraphQL schema definition (SDL) for all tables
+// which have enabled the GraphQL option in their TableConfig. The resolvers
+// get written by GenerateGo.
+func (g *Generator) GenerateGraphQL(w io.Writer) error {
+	if !g.hasGraphQL() {
+		return errors.NotFound.Newf("[dmlgen] GenerateGraphQL: No table has enabled the GraphQL option")
+	}
+	var buf bytes.Buffer
+	buf.WriteString("# Auto generated by dmlgen. DO NOT EDIT.\n\n")
+	buf.WriteString("scalar Int64\nscalar Time\n\n")
+	buf.WriteString("type PageInfo {\n  hasNextPage: Boolean!\n  endCursor: String\n}\n")
+
+	var queries []string
+	for _, tblname := range g.sortedTableNames() {
+		t := g.Tables[tblname]
+		if !t.graphQL {
+			continue
+		}
+		en := t.EntityName()
+		pk := t.Table.Columns.PrimaryKeys().First()
+
+		fmt.Fprintf(&buf, "\n# %s represents a single row of the DB table %s.\n", en, t.Table.Name)
+		fmt.Fprintf(&buf, "type %s {\n", en)
+		for _, c := range t.Table.Columns {
+			if t.IsFieldPrivate(c.Field) {
+				continue
+			}
+			if c.Comment != "" {
+				fmt.Fprintf(&buf, "  # %s\n", strings.ReplaceAll(c.Comment, "\n", " "))
+			}
+			fmt.Fprintf(&buf, "  %s: %s\n", c.Field, graphQLType(c))
+		}
+		for _, rs := range t.graphQLRelations(g) {
+			rt := g.Tables[rs.tableName]
+			if g.isOneRelation(rs) {
+				fmt.Fprintf(&buf, "  %s: %s\n", strs.LcFirst(rs.mappedStructFieldName), rt.EntityName())
+			} else {
+				fmt.Fprintf(&buf, "  %s: [%s!]\n", strs.LcFirst(rs.mappedStructFieldName), rt.EntityName())
+			}
+		}
+		buf.WriteString("}\n")
+
+		fmt.Fprintf(&buf, "\ntype %sEdge {\n  cursor: String!\n  node: %s!\n}\n", en, en)
+		fmt.Fprintf(&buf, "\ntype %sConnection {\n  edges: [%sEdge!]!\n  pageInfo: PageInfo!\n}\n", en, en)
+
+		queries = append(queries,
+			fmt.Sprintf("  %s(%s: %s): %s", t.EntityNameLCFirst(), pk.Field, graphQLType(pk), en),
+			fmt.Sprintf("  %s(first: Int = %d, after: String): %sConnection!", strs.LcFirst(t.CollectionName()), graphQLMaxFirst, en),
+		)
+	}
+	fmt.Fprintf(&buf, "\ntype Query {\n%s\n}\n", strings.Join(queries, "\n"))
+
+	_, err := buf.WriteTo(w)
+	return errors.WithStack(err)
+}
+
+// fnGraphQLHelpers writes the context wiring and the helper functions used by
+// all resolvers.
+func (g *Generator) fnGraphQLHelpers(mainGen *codegen.Go) {
+	if !g.hasGraphQL() {
+		return
+	}
+	mainGen.Pln(`type ctxKeyGraphQLTables struct{}`)
+
+	mainGen.C(`WithGraphQLTables returns a new context which carries the tables and their
+connection pool used by the GraphQL resolvers. Use the Tables field of the DBM
+because nested relations get loaded with its cached queries. Auto generated.`)
+	mainGen.Pln(`func WithGraphQLTables(ctx context.Context, tbls *ddl.Tables) context.Context {
+		return context.WithValue(ctx, ctxKeyGraphQLTables{}, tbls)
+	}`)
+
+	mainGen.Pln(`func graphQLTables(ctx context.Context) (*ddl.Tables, error) {
+		tbls, ok := ctx.Value(ctxKeyGraphQLTables{}).(*ddl.Tables)
+		if !ok || tbls == nil || tbls.ConnPool == nil {
+			return nil, errors.NotFound.Newf("[` + g.Package + `] GraphQL: *ddl.Tables with a ConnPool not found in context, use WithGraphQLTables")
+		}
+		return tbls, nil
+	}`)
+
+	mainGen.C(`graphQLColumns translates the requested GraphQL fields into the list of
+columns to select. Fields which are not a column, like relations, are ignored.
+The primary key is always selected.`)
+	mainGen.Pln(`func graphQLColumns(tbl *ddl.Table, fields []string, pk string) []string {
+		cols := make([]string, 0, len(fields)+1)
+		cols = append(cols, pk)
+		for _, f := range fields {
+			if f != pk && tbl.Columns.Contains(f) {
+				cols = append(cols, f)
+			}
+		}
+		if len(fields) == 0 {
+			return []string{"*"}
+		}
+		return cols
+	}`)
+
+	mainGen.Pln(`func graphQLHasField(fields []string, name string) bool {
+		for _, f := range fields {
+			if f == name {
+				return true
+			}
+		}
+		return len(fields) == 0
+	}`)
+
+	mainGen.C(`GraphQLPageInfo contains the pagination information of a connection. Auto generated.`)
+	mainGen.Pln(`type GraphQLPageInfo struct {
+		HasNextPage bool
+		EndCursor   *string
+	}`)
+}
+
+// fnGraphQLResolvers writes the resolvers of a table: fetching a single row by
+// its primary key, listing rows with keyset pagination and the nested
+// relation resolvers.
+func (t *Table) fnGraphQLResolvers(mainGen *codegen.Go, g *Generator) {
+	if !t.graphQL {
+		return
+	}
+	en := t.EntityName()
+	cn := t.CollectionName()
+	pk := t.Table.Columns.PrimaryKeys().First()
+	pkType := g.goTypeNull(pk)
+	pkFieldName := t.GoCamelMaybePrivate(pk.Field)
+	bitSize := strings.TrimPrefix(strings.TrimPrefix(pkType, "u"), "int")
+	if bitSize == "" {
+		bitSize = "64"
+	}
+	parseFn, formatFn, formatType := "ParseInt", "FormatInt", "int64"
+	if strings.HasPrefix(pkType, "uint") {
+		parseFn, formatFn, formatType = "ParseUint", "FormatUint", "uint64"
+	}
+
+	rels := t.graphQLRelations(g)
+	var relFields strings.Builder // maps GraphQL field name => relation name
+	for _, rs := range rels {
+		fmt.Fprintf(&relFields, "%q: %q,\n", strs.LcFirst(rs.mappedStructFieldName), rs.mappedStructFieldName)
+	}
+
+	mainGen.C(en+`Edge`, `contains a node of the`, en+`Connection`, `and its cursor. Auto generated.`)
+	mainGen.Pln(codegen.SkipWS(`type `, en+`Edge`, ` struct {
+		Cursor string
+		Node   *`, en, `
+	}`))
+	mainGen.C(en+`Connection`, `contains a page of`, en, `edges. Auto generated.`)
+	mainGen.Pln(codegen.SkipWS(`type `, en+`Connection`, ` struct {
+		Edges    []`, en+`Edge`, `
+		PageInfo GraphQLPageInfo
+	}`))
+
+	if len(rels) > 0 {
+		mainGen.Pln(codegen.SkipWS(`var graphQL`, en, `Relations = map[string]string{`, relFields.String(), `}`))
+		mainGen.Pln(codegen.SkipWS(`func graphQLLoad`, en, `Relations(ctx context.Context, tbls *ddl.Tables, cc *`, cn, `, fields []string) error {
+			var names []string
+			for field, name := range graphQL`, en, `Relations {
+				if graphQLHasField(fields, field) {
+					names = append(names, name)
+				}
+			}
+			if len(names) == 0 {
+				return nil
+			}
+			return errors.WithStack(cc.LoadRelations(ctx, &DBM{Tables: tbls}, names...))
+		}`))
+	}
+
+	mainGen.C(`GraphQL`+en, `resolves the query field`, t.EntityNameLCFirst(), `and returns nil if the row cannot be found.
+Argument fields contains the requested GraphQL field names, empty selects all columns.`)
+	mainGen.Pln(codegen.SkipWS(`func GraphQL`, en, `(ctx context.Context, id `, pkType, `, fields []string) (*`, en, `, error) {
+		tbls, err := graphQLTables(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		tbl, err := tbls.Table(`, constTableName(t.Table.Name), `)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		sel := tbl.Select(graphQLColumns(tbl, fields, `, strconv.Quote(pk.Field), `)...).Where(
+			dml.Column(`, strconv.Quote(pk.Field), `).Equal().PlaceHolder(),
+		)
+		e := new(`, en, `)
+		rowCount, err := tbls.ConnPool.WithQueryBuilder(sel).Load(ctx, e, id)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if rowCount == 0 {
+			return nil, nil
+		}`))
+	mainGen.Pln(len(rels) > 0, codegen.SkipWS(`if err := graphQLLoad`, en, `Relations(ctx, tbls, &`, cn, `{Data: []*`, en, `{e}}, fields); err != nil {
+			return nil, errors.WithStack(err)
+		}`))
+	mainGen.Pln(`return e, nil
+	}`)
+
+	mainGen.C(`GraphQL`+cn, `resolves the query field`, strs.LcFirst(cn), `with keyset pagination. The cursor is the
+primary key of a row. Argument fields contains the requested GraphQL field names of the node, empty selects all columns.`)
+	mainGen.Pln(codegen.SkipWS(`func GraphQL`, cn, `(ctx context.Context, first int, after *string, fields []string) (*`, en, `Connection, error) {
+		if first <= 0 || first > `, graphQLMaxFirst, ` {
+			first = `, graphQLMaxFirst, `
+		}
+		var afterID `, pkType, `
+		if after != nil && *after != "" {
+			id, err := strconv.`, parseFn, `(*after, 10, `, bitSize, `)
+			if err != nil {
+				return nil, errors.NotValid.New(err, "[`+t.Package+`] GraphQL`+cn+`: invalid cursor")
+			}
+			afterID = `, pkType, `(id)
+		}
+		tbls, err := graphQLTables(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		tbl, err := tbls.Table(`, constTableName(t.Table.Name), `)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		sel := tbl.Select(graphQLColumns(tbl, fields, `, strconv.Quote(pk.Field), `)...).Where(
+			dml.Column(`, strconv.Quote(pk.Field), `).Greater().PlaceHolder(),
+		).OrderBy(`, strconv.Quote(pk.Field), `)
+		cc := New`, cn, `()
+		if _, err := tbls.ConnPool.WithQueryBuilder(sel).Limit(0, uint64(first)+1).Load(ctx, cc, afterID); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		conn := &`, en, `Connection{}
+		if len(cc.Data) > first {
+			cc.Data = cc.Data[:first]
+			conn.PageInfo.HasNextPage = true
+		}`))
+	mainGen.Pln(len(rels) > 0, codegen.SkipWS(`if err := graphQLLoad`, en, `Relations(ctx, tbls, cc, fields); err != nil {
+			return nil, errors.WithStack(err)
+		}`))
+	mainGen.Pln(codegen.SkipWS(`conn.Edges = make([]`, en, `Edge, 0, len(cc.Data))
+		for _, e := range cc.Data {
+			conn.Edges = append(conn.Edges, `, en, `Edge{
+				Cursor: strconv.`, formatFn, `(`, formatType, `(e.`, pkFieldName, `), 10),
+				Node:   e,
+			})
+		}
+		if n := len(conn.Edges); n > 0 {
+			conn.PageInfo.EndCursor = &conn.Edges[n-1].Cursor
+		}
+		return conn, nil
+	}`))
+
+	for _, rs := range rels {
+		rt := g.Tables[rs.tableName]
+		retType := rt.CollectionName()
+		if g.isOneRelation(rs) {
+			retType = rt.EntityName()
+		}
+		mainGen.C(`GraphQL`+rs.mappedStructFieldName, `resolves the nested field`, strs.LcFirst(rs.mappedStructFieldName),
+			`from the relation data loaded by the query resolvers or by LoadRelations.`)
+		mainGen.Pln(codegen.SkipWS(`func (e *`, en, `) GraphQL`, rs.mappedStructFieldName, `(ctx context.Context) (*`, retType, `, error) {
+			if e == nil || e.Relations == nil {
+				return nil, nil
+			}
+			return e.Relations.`, rs.mappedStructFieldName, `, nil
+		}`))
+	}
+}
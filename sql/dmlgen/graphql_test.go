@@ -0,0 +1,71 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestGenerator_GraphQL(t *testing.T) {
+	cols := func() ddl.Columns {
+		return ddl.Columns{
+			&ddl.Column{Field: "config_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "path", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)", Comment: "Config Path"},
+			&ddl.Column{Field: "value", Pos: 3, Null: "YES", DataType: "text", ColumnType: "text"},
+			&ddl.Column{Field: "version_ts", Pos: 4, Null: "YES", DataType: "timestamp", ColumnType: "timestamp(6)"},
+		}
+	}
+
+	t.Run("no table enabled", func(t *testing.T) {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config", cols()),
+		)
+		assert.NoError(t, err)
+		assert.ErrorIsKind(t, errors.NotFound, g.GenerateGraphQL(&bytes.Buffer{}))
+	})
+
+	t.Run("requires integer primary key", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config", ddl.Columns{
+				&ddl.Column{Field: "code", Pos: 1, Null: "NO", DataType: "varchar", ColumnType: "varchar(32)", Key: "PRI"},
+			}),
+			WithTableConfig("core_config", &TableConfig{GraphQL: true}),
+		)
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+
+	t.Run("writes schema and resolvers", func(t *testing.T) {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config", cols()),
+			WithTable("core_log", cols()),
+			WithTableConfig("core_config", &TableConfig{GraphQL: true}),
+		)
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, g.GenerateGraphQL(&buf))
+		have := buf.String()
+		assert.Contains(t, have, "type CoreConfig {\n  config_id: Int64!\n  # Config Path\n  path: String!\n  value: String\n  version_ts: Time\n}")
+		assert.Contains(t, have, "  coreConfig(config_id: Int64!): CoreConfig\n")
+		assert.Contains(t, have, "  coreConfigs(first: Int = 100, after: String): CoreConfigConnection!\n")
+		assert.NotContains(t, have, "CoreLog")
+
+		buf.Reset()
+		var bufTest bytes.Buffer
+		assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+		have = buf.String()
+		assert.Contains(t, have, "func WithGraphQLTables(ctx context.Context, tbls *ddl.Tables) context.Context {")
+		assert.Contains(t, have, "func GraphQLCoreConfig(ctx context.Context, id uint32, fields []string) (*CoreConfig, error) {")
+		assert.Contains(t, have, "func GraphQLCoreConfigs(ctx context.Context, first int, after *string, fields []string) (*CoreConfigConnection, error) {")
+		assert.Contains(t, have, "id, err := strconv.ParseUint(*after, 10, 32)")
+		assert.Contains(t, have, "type CoreConfigEdge struct {\n\tCursor string\n\tNode   *CoreConfig\n}")
+		assert.Contains(t, have, "type CoreConfigConnection struct {\n\tEdges    []CoreConfigEdge\n\tPageInfo GraphQLPageInfo\n}")
+		assert.Contains(t, have, "conn := &CoreConfigConnection{}")
+		assert.Contains(t, have, "conn.Edges = make([]CoreConfigEdge, 0, len(cc.Data))")
+		assert.Contains(t, have, "conn.Edges = append(conn.Edges, CoreConfigEdge{")
+		assert.NotContains(t, have, "GraphQLCoreLog")
+	})
+}
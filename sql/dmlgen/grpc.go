@@ -0,0 +1,340 @@
+package dmlgen
+
+import (
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+// grpcImportPaths gets added to the import paths of the main Go file once at
+// least one table has a gRPC service.
+var grpcImportPaths = []string{
+	"google.golang.org/grpc/codes",
+	"google.golang.org/grpc/metadata",
+	"google.golang.org/grpc/status",
+	"google.golang.org/protobuf/reflect/protoreflect",
+}
+
+// Incoming gRPC metadata keys which are getting mapped to dml.QueryOptions.
+const (
+	grpcMetadataSkipEvents     = "dml-skip-events"
+	grpcMetadataSkipTimestamps = "dml-skip-timestamps"
+	grpcMetadataSkipRelations  = "dml-skip-relations"
+)
+
+func (t *Table) validateGRPCService(g *Generator) error {
+	if !t.grpcService {
+		return nil
+	}
+	if !g.hasEntityConverters() {
+		return errors.NotSupported.Newf("[dmlgen] Table %q: GRPCService requires WithProtobuf with enabled EntityConverters", t.Table.Name)
+	}
+	if t.Table.IsView() || t.Table.Columns.PrimaryKeys().Len() != 1 {
+		return errors.NotSupported.Newf("[dmlgen] Table %q: GRPCService requires a table with exactly one primary key column", t.Table.Name)
+	}
+	return nil
+}
+
+func (g *Generator) hasGRPCService() bool {
+	for _, t := range g.Tables {
+		if t.grpcService {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Table) grpcServiceName() string { return t.EntityName() + "Service" }
+
+// generateProtoService writes the request/response messages and the service
+// definition for the CRUD RPCs.
+func (t *Table) generateProtoService(proto *codegen.Proto, g *Generator) {
+	if !t.grpcService {
+		return
+	}
+	pk := t.Table.Columns.PrimaryKeys().First()
+	pkType := g.serializerType(pk)
+	pkName := strs.ToGoCamelCase(pk.Field)
+	en := t.EntityName()
+
+	proto.C(en+`GetRequest`, `loads a single row by its primary key. Auto generated.`)
+	proto.Pln(`message`, en+`GetRequest`, `{`)
+	proto.In()
+	proto.Pln(pkType, pkName, `= 1;`)
+	proto.Pln(`repeated string FieldMask = 2;`)
+	proto.Out()
+	proto.Pln(`}`)
+
+	proto.C(en+`ListRequest`, `loads rows with keyset pagination. Set After to the NextAfter value of the previous response. Auto generated.`)
+	proto.Pln(`message`, en+`ListRequest`, `{`)
+	proto.In()
+	proto.Pln(pkType, `After = 1;`)
+	proto.Pln(`uint32 Limit = 2;`)
+	proto.Pln(`repeated string FieldMask = 3;`)
+	proto.Out()
+	proto.Pln(`}`)
+
+	proto.C(en+`ListResponse`, `contains a page of rows and the cursor of the next page. Auto generated.`)
+	proto.Pln(`message`, en+`ListResponse`, `{`)
+	proto.In()
+	proto.Pln(`repeated`, en, `Data = 1;`)
+	proto.Pln(pkType, `NextAfter = 2;`)
+	proto.Pln(`bool HasMore = 3;`)
+	proto.Out()
+	proto.Pln(`}`)
+
+	proto.C(en+`DeleteRequest`, `deletes a single row by its primary key. Auto generated.`)
+	proto.Pln(`message`, en+`DeleteRequest`, `{`)
+	proto.In()
+	proto.Pln(pkType, pkName, `= 1;`)
+	proto.Out()
+	proto.Pln(`}`)
+
+	proto.C(en+`DeleteResponse`, `reports the amount of deleted rows. Auto generated.`)
+	proto.Pln(`message`, en+`DeleteResponse`, `{`)
+	proto.In()
+	proto.Pln(`int64 RowsAffected = 1;`)
+	proto.Out()
+	proto.Pln(`}`)
+
+	proto.C(t.grpcServiceName(), `provides CRUD operations for the`, t.Table.Name, `DB table. Auto generated.`)
+	proto.Pln(`service`, t.grpcServiceName(), `{`)
+	proto.In()
+	proto.Pln(`rpc Get(`, en+`GetRequest`, `) returns (`, en, `);`)
+	proto.Pln(`rpc List(`, en+`ListRequest`, `) returns (`, en+`ListResponse`, `);`)
+	proto.Pln(`rpc Create(`, en, `) returns (`, en, `);`)
+	proto.Pln(`rpc Update(`, en, `) returns (`, en, `);`)
+	proto.Pln(`rpc Delete(`, en+`DeleteRequest`, `) returns (`, en+`DeleteResponse`, `);`)
+	proto.Out()
+	proto.Pln(`}`)
+}
+
+// fnGRPCHelpers writes the package wide helper functions used by all
+// generated gRPC servers.
+func (g *Generator) fnGRPCHelpers(mainGen *codegen.Go) {
+	if !g.hasGRPCService() {
+		return
+	}
+	mainGen.C(`grpcQueryOptions maps the incoming gRPC metadata keys`, grpcMetadataSkipEvents+`,`,
+		grpcMetadataSkipTimestamps, `and`, grpcMetadataSkipRelations, `with value "true" to dml.QueryOptions.`)
+	mainGen.Pln(`func grpcQueryOptions(ctx context.Context) context.Context {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`md, ok := metadata.FromIncomingContext(ctx)`)
+		mainGen.Pln(`if !ok { return ctx }`)
+		mainGen.Pln(`isTrue := func(key string) bool { v := md.Get(key); return len(v) > 0 && v[0] == "true" }`)
+		mainGen.Pln(`qo := dml.FromContextQueryOptions(ctx)`)
+		mainGen.Pln(`qo.SkipEvents = qo.SkipEvents || isTrue("` + grpcMetadataSkipEvents + `")`)
+		mainGen.Pln(`qo.SkipTimestamps = qo.SkipTimestamps || isTrue("` + grpcMetadataSkipTimestamps + `")`)
+		mainGen.Pln(`qo.SkipRelations = qo.SkipRelations || isTrue("` + grpcMetadataSkipRelations + `")`)
+		mainGen.Pln(`return dml.WithContextQueryOptions(ctx, qo)`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	mainGen.C(`grpcError converts an error into a gRPC status error.`)
+	mainGen.Pln(`func grpcError(err error) error {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`switch {`)
+		mainGen.Pln(`case err == nil:`)
+		mainGen.Pln(`	return nil`)
+		mainGen.Pln(`case errors.NotFound.Match(err):`)
+		mainGen.Pln(`	return status.Error(codes.NotFound, err.Error())`)
+		mainGen.Pln(`case errors.NotValid.Match(err), errors.Empty.Match(err):`)
+		mainGen.Pln(`	return status.Error(codes.InvalidArgument, err.Error())`)
+		mainGen.Pln(`case errors.AlreadyExists.Match(err), errors.Duplicated.Match(err):`)
+		mainGen.Pln(`	return status.Error(codes.AlreadyExists, err.Error())`)
+		mainGen.Pln(`}`)
+		mainGen.Pln(`return status.Error(codes.Internal, err.Error())`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	mainGen.C(`grpcApplyFieldMask clears all fields of m which are not listed in fields. An empty fields slice keeps all fields.`)
+	mainGen.Pln(`func grpcApplyFieldMask(m protoreflect.ProtoMessage, fields []string) {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`if len(fields) == 0 { return }`)
+		mainGen.Pln(`keep := make(map[protoreflect.Name]bool, len(fields))`)
+		mainGen.Pln(`for _, f := range fields { keep[protoreflect.Name(f)] = true }`)
+		mainGen.Pln(`pr := m.ProtoReflect()`)
+		mainGen.Pln(`pr.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {`)
+		mainGen.Pln(`	if !keep[fd.Name()] { pr.Clear(fd) }`)
+		mainGen.Pln(`	return true`)
+		mainGen.Pln(`})`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+}
+
+// fnGRPCServer writes the server implementation of the gRPC service. The
+// server uses the statements registered by NewDBManager.
+func (t *Table) fnGRPCServer(mainGen *codegen.Go, g *Generator) {
+	if !t.grpcService {
+		return
+	}
+	pk := t.Table.Columns.PrimaryKeys().First()
+	pkName := strs.ToGoCamelCase(pk.Field)
+	en := t.EntityName()
+	srv := en + "Server"
+	pb := g.protoTypePrefix()
+
+	mainGen.C(srv, `implements the gRPC service`, pb+t.grpcServiceName()+`Server`, `with the
+statements registered by NewDBManager. Incoming metadata gets mapped to
+dml.QueryOptions. Auto generated.`)
+	mainGen.Pln(`type`, srv, `struct {`)
+	{
+		mainGen.In()
+		mainGen.Pln(pb + `Unimplemented` + t.grpcServiceName() + `Server`)
+		mainGen.Pln(`Tables   *ddl.Tables`)
+		mainGen.Pln(`ConnPool *dml.ConnPool`)
+		mainGen.C(`MaxListLimit defines the upper bound of rows returned by List. Default 100.`)
+		mainGen.Pln(`MaxListLimit uint64`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	mainGen.C(`New`+srv, `creates a new gRPC server. Argument cp can be nil and then the ConnPool of tbls gets used.`)
+	mainGen.Pln(`func New`+srv+`(tbls *ddl.Tables, cp *dml.ConnPool) *`+srv, `{`)
+	{
+		mainGen.In()
+		mainGen.Pln(`if cp == nil { cp = tbls.ConnPool }`)
+		mainGen.Pln(`return &`+srv, `{ Tables: tbls, ConnPool: cp, MaxListLimit: 100 }`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	// Get
+	mainGen.C(`Get loads a single row by its primary key.`)
+	mainGen.Pln(`func (s *`+srv, `) Get(ctx context.Context, req *`+pb+en+`GetRequest) (*`+pb+en, `, error) {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`ctx = grpcQueryOptions(ctx)`)
+		mainGen.Pln(`e := new(`, en, `)`)
+		mainGen.Pln(`rowCount, err := s.ConnPool.WithCacheKey("` + en + `SelectByPK").Load(ctx, e, req.Get` + pkName + `())`)
+		mainGen.Pln(`if err != nil { return nil, grpcError(err) }`)
+		mainGen.Pln(`if rowCount == 0 { return nil, grpcError(errors.NotFound.Newf("` + en + ` %v not found", req.Get` + pkName + `())) }`)
+		mainGen.Pln(`x := e.ToProto()`)
+		mainGen.Pln(`grpcApplyFieldMask(x, req.GetFieldMask())`)
+		mainGen.Pln(`return x, nil`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	// List
+	mainGen.C(`List loads the rows with keyset pagination ordered by the primary key.`)
+	mainGen.Pln(`func (s *`+srv, `) List(ctx context.Context, req *`+pb+en+`ListRequest) (*`+pb+en+`ListResponse, error) {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`ctx = grpcQueryOptions(ctx)`)
+		mainGen.Pln(`limit := uint64(req.GetLimit())`)
+		mainGen.Pln(`if limit == 0 || limit > s.MaxListLimit { limit = s.MaxListLimit }`)
+		mainGen.C(`load one more row to figure out if there is a next page.`)
+		mainGen.Pln(`cc := new(`, t.CollectionName(), `)`)
+		mainGen.Pln(`if _, err := s.ConnPool.WithCacheKey("` + en + `SelectKeyset").Limit(0, limit+1).Load(ctx, cc, req.GetAfter()); err != nil {`)
+		mainGen.Pln(`	return nil, grpcError(err)`)
+		mainGen.Pln(`}`)
+		mainGen.Pln(`res := new(` + pb + en + `ListResponse)`)
+		mainGen.Pln(`if uint64(len(cc.Data)) > limit {`)
+		mainGen.Pln(`	res.HasMore = true`)
+		mainGen.Pln(`	cc.Data = cc.Data[:limit]`)
+		mainGen.Pln(`}`)
+		mainGen.Pln(`res.Data = make([]*`+pb+en, `, 0, len(cc.Data))`)
+		mainGen.Pln(`for _, e := range cc.Data {`)
+		mainGen.Pln(`	x := e.ToProto()`)
+		mainGen.Pln(`	grpcApplyFieldMask(x, req.GetFieldMask())`)
+		mainGen.Pln(`	res.Data = append(res.Data, x)`)
+		mainGen.Pln(`	res.NextAfter = e.` + pkName)
+		mainGen.Pln(`}`)
+		mainGen.Pln(`return res, nil`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	// Create
+	mainGen.C(`Create inserts a new row and returns it including the new primary key.`)
+	mainGen.Pln(`func (s *`+srv, `) Create(ctx context.Context, req *`+pb+en, `) (*`+pb+en, `, error) {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`ctx = grpcQueryOptions(ctx)`)
+		mainGen.Pln(`e := new(`, en, `).FromProto(req)`)
+		mainGen.Pln(`res, err := s.ConnPool.WithCacheKey("` + en + `Insert").ExecContext(ctx, e)`)
+		mainGen.Pln(`if err != nil { return nil, grpcError(err) }`)
+		if pk.IsAutoIncrement() {
+			mainGen.Pln(`id, err := res.LastInsertId()`)
+			mainGen.Pln(`if err != nil { return nil, grpcError(err) }`)
+			mainGen.Pln(`e.`+pkName, `=`, g.goType(pk)+`(id)`)
+		} else {
+			mainGen.Pln(`_ = res`)
+		}
+		mainGen.Pln(`return e.ToProto(), nil`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	// Update
+	mainGen.C(`Update writes all columns of the row identified by its primary key.`)
+	mainGen.Pln(`func (s *`+srv, `) Update(ctx context.Context, req *`+pb+en, `) (*`+pb+en, `, error) {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`ctx = grpcQueryOptions(ctx)`)
+		mainGen.Pln(`e := new(`, en, `).FromProto(req)`)
+		mainGen.Pln(`if _, err := s.ConnPool.WithCacheKey("` + en + `UpdateByPK").ExecContext(ctx, e); err != nil {`)
+		mainGen.Pln(`	return nil, grpcError(err)`)
+		mainGen.Pln(`}`)
+		mainGen.Pln(`return e.ToProto(), nil`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	// Delete
+	mainGen.C(`Delete removes the row identified by its primary key.`)
+	mainGen.Pln(`func (s *`+srv, `) Delete(ctx context.Context, req *`+pb+en+`DeleteRequest) (*`+pb+en+`DeleteResponse, error) {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`ctx = grpcQueryOptions(ctx)`)
+		mainGen.Pln(`res, err := s.ConnPool.WithCacheKey("` + en + `DeleteByPK").ExecContext(ctx, req.Get` + pkName + `())`)
+		mainGen.Pln(`if err != nil { return nil, grpcError(err) }`)
+		mainGen.Pln(`ra, err := res.RowsAffected()`)
+		mainGen.Pln(`if err != nil { return nil, grpcError(err) }`)
+		mainGen.Pln(`return &` + pb + en + `DeleteResponse{RowsAffected: ra}, nil`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+}
+
+// generateTestGRPC writes a mock based test for the gRPC server into the non-DB
+// test function.
+func (t *Table) generateTestGRPC(testGen *codegen.Go, g *Generator) (codeWritten int) {
+	if !t.grpcService {
+		return 0
+	}
+	pk := t.Table.Columns.PrimaryKeys().First()
+	pkName := strs.ToGoCamelCase(pk.Field)
+	en := t.EntityName()
+	pb := g.protoTypePrefix()
+
+	testGen.Pln(`t.Run("` + en + `_GRPCServer", func(t *testing.T) {`)
+	{
+		testGen.Pln(`db, mock := dmltest.MockDB(t)`)
+		testGen.Pln(`defer dmltest.MockClose(t, db, mock)`)
+		testGen.Pln(`tbls, err := ddl.NewTables(ddl.WithConnPool(db), ddl.WithQueryDBR(map[string]dml.QueryBuilder{`)
+		testGen.Pln(`	"`+en+`DeleteByPK": dml.NewDelete(`, constTableName(t.Table.Name), `).Where(dml.Column("`+pk.Field+`").Equal().PlaceHolder()),`)
+		testGen.Pln(`	"`+en+`SelectByPK": dml.NewSelect("*").From(`, constTableName(t.Table.Name), `).Where(dml.Column("`+pk.Field+`").Equal().PlaceHolder()),`)
+		testGen.Pln(`}))`)
+		testGen.Pln(`assert.NoError(t, err)`)
+		testGen.Pln(`srv := New` + en + `Server(tbls, nil)`)
+
+		testGen.Pln(`mock.ExpectExec(dmltest.SQLMockQuoteMeta("DELETE FROM")).WillReturnResult(sqlmock.NewResult(0, 1))`)
+		testGen.Pln(`res, err := srv.Delete(context.Background(), &` + pb + en + `DeleteRequest{` + pkName + `: 1})`)
+		testGen.Pln(`assert.NoError(t, err)`)
+		testGen.Pln(`assert.Exactly(t, int64(1), res.RowsAffected)`)
+
+		testGen.Pln(`mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT")).WillReturnRows(sqlmock.NewRows([]string{"` + pk.Field + `"}))`)
+		testGen.Pln(`_, err = srv.Get(context.Background(), &` + pb + en + `GetRequest{` + pkName + `: 1})`)
+		testGen.Pln(`assert.Exactly(t, codes.NotFound, status.Code(err))`)
+	}
+	testGen.Pln(`})`) // end t.Run
+	return 1
+}
@@ -0,0 +1,60 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestGenerator_GRPCService(t *testing.T) {
+	cols := func() ddl.Columns {
+		return ddl.Columns{
+			&ddl.Column{Field: "config_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "path", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)"},
+		}
+	}
+
+	t.Run("requires entity converters", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config", cols()),
+			WithProtobuf(&SerializerConfig{
+				PackageImportPath: "github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated/dmltestgeneratedpb",
+			}),
+			WithTableConfig("core_config", &TableConfig{GRPCService: true}),
+		)
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+
+	t.Run("writes service", func(t *testing.T) {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config", cols()),
+			WithTable("core_log", cols()),
+			WithProtobuf(&SerializerConfig{
+				PackageImportPath: "github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated/dmltestgeneratedpb",
+				EntityConverters:  true,
+			}),
+			WithTableConfig("core_config", &TableConfig{GRPCService: true}),
+		)
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, g.GenerateSerializer(&buf, nil))
+		have := buf.String()
+		assert.Contains(t, have, "service CoreConfigService {")
+		assert.Contains(t, have, "rpc List( CoreConfigListRequest ) returns ( CoreConfigListResponse );")
+		assert.NotContains(t, have, "service CoreLogService")
+
+		buf.Reset()
+		var bufTest bytes.Buffer
+		assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+		have = buf.String()
+		assert.Contains(t, have, "func NewCoreConfigServer(tbls *ddl.Tables, cp *dml.ConnPool) *CoreConfigServer {")
+		assert.Contains(t, have, `"CoreConfigSelectKeyset"`)
+		assert.Contains(t, have, `"github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated/dmltestgeneratedpb"`)
+		assert.NotContains(t, have, "CoreLogServer")
+		assert.Contains(t, bufTest.String(), `t.Run("CoreConfig_GRPCServer"`)
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"strconv"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+// defaultOptimisticLockColumn enables optimistic locking for all tables with a
+// column of this name, unless TableConfig.OptimisticLockColumn is set.
+const defaultOptimisticLockColumn = "version"
+
+// optimisticLockDisabled as TableConfig.OptimisticLockColumn disables the
+// optimistic locking.
+const optimisticLockDisabled = "-"
+
+func isOptimisticLockColumn(c *ddl.Column) bool {
+	if c.IsNull() || c.IsPK() || c.IsAutoIncrement() || c.IsGenerated() {
+		return false
+	}
+	switch c.DataType {
+	case "tinyint", "smallint", "mediumint", "int", "bigint", "datetime", "timestamp":
+		return true
+	}
+	return false
+}
+
+func (to *TableConfig) applyOptimisticLockColumn(t *Table) {
+	t.optimisticLock = to.OptimisticLockColumn
+	if to.OptimisticLockColumn == "" || to.OptimisticLockColumn == optimisticLockDisabled || to.lastErr != nil {
+		return
+	}
+	c := t.Table.Columns.ByField(to.OptimisticLockColumn)
+	switch {
+	case c.Field == "":
+		to.lastErr = errors.NotFound.Newf("[dmlgen] WithTableConfig:OptimisticLockColumn: For table %q the Column %q cannot be found.",
+			t.Table.Name, to.OptimisticLockColumn)
+	case !isOptimisticLockColumn(c):
+		to.lastErr = errors.NotSupported.Newf("[dmlgen] WithTableConfig:OptimisticLockColumn: For table %q the Column %q must be a not nullable integer, datetime or timestamp.",
+			t.Table.Name, to.OptimisticLockColumn)
+	case t.Table.IsView():
+		to.lastErr = errors.NotSupported.Newf("[dmlgen] WithTableConfig:OptimisticLockColumn: Table %q is a view.", t.Table.Name)
+	}
+}
+
+// optimisticLockColumn returns the version column or nil if the table does not
+// use optimistic locking.
+func (t *Table) optimisticLockColumn() *ddl.Column {
+	if t.optimisticLock == optimisticLockDisabled || t.Table.IsView() {
+		return nil
+	}
+	name := t.optimisticLock
+	if name == "" {
+		name = defaultOptimisticLockColumn
+	}
+	if c := t.Table.Columns.ByField(name); c.Field != "" && isOptimisticLockColumn(c) {
+		return c
+	}
+	return nil
+}
+
+// optimisticLockUpdateOption returns the method call which enables the
+// optimistic locking in the UpdateByPK query.
+func (t *Table) optimisticLockUpdateOption() string {
+	c := t.optimisticLockColumn()
+	switch {
+	case c == nil:
+		return ""
+	case c.IsTime():
+		return ".WithOptimisticLockTimestamp(" + strconv.Quote(c.Field) + ")"
+	}
+	return ".WithOptimisticLock(" + strconv.Quote(c.Field) + ")"
+}
+
+// fnOptimisticLockIncrement writes the code which increments the integer
+// version of the entity `varName` after a successful update. Timestamp versions
+// get set by the database and the entity must be reloaded.
+func (t *Table) fnOptimisticLockIncrement(mainGen *codegen.Go, dmlEnabled bool, varName string) {
+	if c := t.optimisticLockColumn(); c != nil && !c.IsTime() {
+		mainGen.Pln(dmlEnabled, codegen.SkipWS(varName, `.`, t.GoCamelMaybePrivate(c.Field), `++`))
+	}
+}
@@ -0,0 +1,67 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestGenerator_OptimisticLockColumn(t *testing.T) {
+	cols := func() ddl.Columns {
+		return ddl.Columns{
+			&ddl.Column{Field: "page_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "title", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(64)"},
+			&ddl.Column{Field: "version", Pos: 3, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned"},
+			&ddl.Column{Field: "modified_at", Pos: 4, Null: "NO", DataType: "datetime", ColumnType: "datetime(6)"},
+			&ddl.Column{Field: "deleted_at", Pos: 5, Null: "YES", DataType: "datetime", ColumnType: "datetime"},
+		}
+	}
+	generate := func(t *testing.T, opts ...Option) string {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			append([]Option{WithTable("cms_page", cols())}, opts...)...,
+		)
+		assert.NoError(t, err)
+		var buf, bufTest bytes.Buffer
+		assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+		return buf.String()
+	}
+
+	t.Run("default version column", func(t *testing.T) {
+		have := generate(t)
+		assert.Contains(t, have, `).WithOptimisticLock("version")),`)
+		assert.Contains(t, have, "e.Version++")
+		assert.Contains(t, have, "c.Version++")
+	})
+
+	t.Run("timestamp column", func(t *testing.T) {
+		have := generate(t, WithTableConfig("cms_page", &TableConfig{OptimisticLockColumn: "modified_at"}))
+		assert.Contains(t, have, `).WithOptimisticLockTimestamp("modified_at")),`)
+		assert.NotContains(t, have, "WithOptimisticLock(")
+		assert.NotContains(t, have, "e.ModifiedAt++")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		have := generate(t, WithTableConfig("cms_page", &TableConfig{OptimisticLockColumn: "-"}))
+		assert.NotContains(t, have, "WithOptimisticLock")
+		assert.NotContains(t, have, "Version++")
+	})
+
+	t.Run("column not found", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("cms_page", cols()),
+			WithTableConfig("cms_page", &TableConfig{OptimisticLockColumn: "revision"}),
+		)
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+
+	t.Run("column nullable", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("cms_page", cols()),
+			WithTableConfig("cms_page", &TableConfig{OptimisticLockColumn: "deleted_at"}),
+		)
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+}
@@ -59,12 +59,26 @@ func WithTableConfig(tableName string, opt *TableConfig) (o Option) {
 		opt.applyComments(t)
 		opt.applyColumnAliases(t)
 		opt.applyUniquifiedColumns(t)
+		opt.applyJSONOmitEmpty(t)
+		opt.applyUpsertExcludeColumns(t, g)
+		opt.applySoftDeleteColumn(t)
+		opt.applyOptimisticLockColumn(t)
+		opt.applyEnumColumns(t)
+		opt.applyRelationNames(t)
 		t.featuresInclude = opt.FeaturesInclude | g.defaultTableConfig.FeaturesInclude
 		t.featuresExclude = opt.FeaturesExclude | g.defaultTableConfig.FeaturesExclude
 		t.fieldMapFn = opt.FieldMapFn
 		if t.fieldMapFn == nil {
 			t.fieldMapFn = defaultFieldMapFn
 		}
+		t.grpcService = opt.GRPCService
+		if err := t.validateGRPCService(g); err != nil {
+			return errors.WithStack(err)
+		}
+		t.graphQL = opt.GraphQL
+		if err := t.validateGraphQL(); err != nil {
+			return errors.WithStack(err)
+		}
 		return opt.lastErr
 	}
 	return o
@@ -287,6 +301,17 @@ func WithTablesFromDB(ctx context.Context, db *dml.ConnPool, tables ...string) (
 type SerializerConfig struct {
 	PackageImportPath string
 	AdditionalHeaders []string
+	// FieldNumbersFile defines the path to a JSON file which stores the
+	// assigned field numbers of all messages. Should be placed next to the
+	// .proto file and committed. Existing numbers are kept, new fields get the
+	// next free number and numbers of removed fields are written as reserved.
+	// If empty, the column position defines the field number.
+	FieldNumbersFile string
+	// EntityConverters generates the ToProto and FromProto methods for each
+	// entity. The proto package defined in PackageImportPath gets imported
+	// into the entity package, hence the proto package must not import the
+	// entity package. Can be switched off per table with FeatureEntityProto.
+	EntityConverters bool
 }
 
 // WithProtobuf enables protocol buffers as a serialization method. Argument
@@ -304,6 +329,8 @@ func WithProtobuf(sc *SerializerConfig) (opt Option) {
 		g.PackageSerializer = pkg
 		g.PackageSerializerImportPath = sc.PackageImportPath
 		g.SerializerHeaderOptions = append(g.SerializerHeaderOptions, sc.AdditionalHeaders...)
+		g.serializerFieldNumbersFile = sc.FieldNumbersFile
+		g.serializerEntityConverters = sc.EntityConverters
 		return nil
 	}
 	return opt
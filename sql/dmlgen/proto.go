@@ -12,6 +12,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/alecthomas/repr"
@@ -227,6 +228,21 @@ func (g *Generator) GenerateSerializer(wMain, wTest io.Writer) error {
 	return nil
 }
 
+// hasEntityConverters reports whether the ToProto and FromProto methods must
+// be generated for the entities.
+func (g *Generator) hasEntityConverters() bool {
+	return g.Serializer == "protobuf" && g.serializerEntityConverters
+}
+
+// protoTypePrefix returns the package qualifier of the generated proto Go
+// types as seen from the entity package.
+func (g *Generator) protoTypePrefix() string {
+	if g.PackageSerializerImportPath == "" || g.PackageSerializerImportPath == g.PackageImportPath {
+		return ""
+	}
+	return g.PackageSerializer + "."
+}
+
 func (g *Generator) generateProto(w io.Writer) error {
 	proto := codegen.NewProto(g.Package)
 
@@ -244,17 +260,16 @@ func (g *Generator) generateProto(w io.Writer) error {
 		proto.Pln(`option go_package = `, fmt.Sprintf("%q;", g.PackageSerializer))
 	}
 
+	fieldNumbers, err := loadProtoFieldNumbers(g.serializerFieldNumbersFile)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	var hasTimestampField bool
 	for _, tblname := range g.sortedTableNames() {
 		t := g.Tables[tblname] // must panic if table name not found
 
-		fieldMapFn := g.defaultTableConfig.FieldMapFn
-		if fieldMapFn == nil {
-			fieldMapFn = t.fieldMapFn
-		}
-		if fieldMapFn == nil {
-			fieldMapFn = defaultFieldMapFn
-		}
+		fieldMapFn := t.getFieldMapFn(g)
 
 		proto.C(t.EntityName(), `represents a single row for`, t.Table.Name, `DB table. Auto generated.`)
 		if t.Table.TableComment != "" {
@@ -263,16 +278,24 @@ func (g *Generator) generateProto(w io.Writer) error {
 		proto.Pln(`message`, t.EntityName(), `{`)
 		{
 			proto.In()
+			usedFields := map[string]bool{}
+			fieldNumber := func(field string, preferred uint64) uint64 {
+				usedFields[field] = true
+				return fieldNumbers.number(t.EntityName(), field, preferred)
+			}
 			var lastColumnPos uint64
 			t.Table.Columns.Each(func(c *ddl.Column) {
 				if t.IsFieldPublic(c.Field) {
 					serType := g.serializerType(c)
-					if !hasTimestampField && strings.HasPrefix(serType, "google.protobuf.Timestamp") {
+					if !hasTimestampField && strings.Contains(serType, "google.protobuf.Timestamp") {
 						hasTimestampField = true
 					}
-
+					if c.Comment != "" {
+						proto.C(c.Comment)
+					}
 					// extend here with a custom code option, if someone needs
-					proto.Pln(serType, strs.ToGoCamelCase(c.Field), `=`, c.Pos, `; //`, c.Comment)
+					fieldName := strs.ToGoCamelCase(c.Field)
+					proto.Pln(serType, fieldName, `=`, fieldNumber(fieldName, c.Pos), `;`)
 					lastColumnPos = c.Pos
 				}
 			})
@@ -296,7 +319,7 @@ func (g *Generator) generateProto(w io.Writer) error {
 						hasTable := g.Tables[kcuce.ReferencedTableName.Data] != nil
 						if isOneToMany && hasTable && isRelationAllowed {
 							proto.Pln(fieldMapFn(pluralize(kcuce.ReferencedTableName.Data)), fieldMapFn(pluralize(kcuce.ReferencedTableName.Data)),
-								"=", lastColumnPos, ";",
+								"=", fieldNumber(fieldMapFn(pluralize(kcuce.ReferencedTableName.Data)), lastColumnPos), ";",
 								"// 1:M", kcuce.TableName+"."+kcuce.ColumnName, "=>", kcuce.ReferencedTableName.Data+"."+kcuce.ReferencedColumnName.Data)
 							lastColumnPos++
 						}
@@ -305,7 +328,7 @@ func (g *Generator) generateProto(w io.Writer) error {
 						isOneToOne := g.krs.IsOneToOne(kcuce.TableName, kcuce.ColumnName, kcuce.ReferencedTableName.Data, kcuce.ReferencedColumnName.Data)
 						if isOneToOne && hasTable && isRelationAllowed {
 							proto.Pln(fieldMapFn(strs.ToGoCamelCase(kcuce.ReferencedTableName.Data)), fieldMapFn(strs.ToGoCamelCase(kcuce.ReferencedTableName.Data)),
-								"=", lastColumnPos, ";",
+								"=", fieldNumber(fieldMapFn(strs.ToGoCamelCase(kcuce.ReferencedTableName.Data)), lastColumnPos), ";",
 								"// 1:1", kcuce.TableName+"."+kcuce.ColumnName, "=>", kcuce.ReferencedTableName.Data+"."+kcuce.ReferencedColumnName.Data)
 							lastColumnPos++
 						}
@@ -338,7 +361,7 @@ func (g *Generator) generateProto(w io.Writer) error {
 						// case ONE-TO-MANY
 						if isRelationAllowed && isOneToMany && hasTable && !relationShipSeenAlready {
 							proto.Pln(fieldMapFn(pluralize(kcuce.ReferencedTableName.Data)), fieldMapFn(pluralize(kcuce.ReferencedTableName.Data)),
-								"=", lastColumnPos, ";",
+								"=", fieldNumber(fieldMapFn(pluralize(kcuce.ReferencedTableName.Data)), lastColumnPos), ";",
 								"// Reversed 1:M", kcuce.TableName+"."+kcuce.ColumnName, "=>", kcuce.ReferencedTableName.Data+"."+kcuce.ReferencedColumnName.Data)
 							relationShipSeen[keySeen] = true
 							lastColumnPos++
@@ -348,7 +371,7 @@ func (g *Generator) generateProto(w io.Writer) error {
 						isOneToOne := g.krs.IsOneToOne(kcuce.TableName, kcuce.ColumnName, kcuce.ReferencedTableName.Data, kcuce.ReferencedColumnName.Data)
 						if isRelationAllowed && isOneToOne && hasTable {
 							proto.Pln(fieldMapFn(strs.ToGoCamelCase(kcuce.ReferencedTableName.Data)), fieldMapFn(strs.ToGoCamelCase(kcuce.ReferencedTableName.Data)),
-								"=", lastColumnPos, ";",
+								"=", fieldNumber(fieldMapFn(strs.ToGoCamelCase(kcuce.ReferencedTableName.Data)), lastColumnPos), ";",
 								"// Reversed 1:1", kcuce.TableName+"."+kcuce.ColumnName, "=>", kcuce.ReferencedTableName.Data+"."+kcuce.ReferencedColumnName.Data)
 							lastColumnPos++
 						}
@@ -366,7 +389,7 @@ func (g *Generator) generateProto(w io.Writer) error {
 						// hasTable shall not be added because usually the link table does not get loaded.
 						if isRelationAllowed && targetTbl != "" && targetColumn != "" {
 							proto.Pln(fieldMapFn(pluralize(targetTbl)), fieldMapFn(pluralize(targetTbl)),
-								"=", lastColumnPos, ";",
+								"=", fieldNumber(fieldMapFn(pluralize(targetTbl)), lastColumnPos), ";",
 								"// Reversed M:N", kcuce.TableName+"."+kcuce.ColumnName, "via", kcuce.ReferencedTableName.Data+"."+kcuce.ReferencedColumnName.Data,
 								"=>", targetTbl+"."+targetColumn,
 							)
@@ -375,6 +398,13 @@ func (g *Generator) generateProto(w io.Writer) error {
 					}
 				}
 			}
+			if rsv := fieldNumbers.reserved(t.EntityName(), usedFields); len(rsv) > 0 {
+				nums := make([]string, len(rsv))
+				for i, n := range rsv {
+					nums[i] = strconv.FormatUint(n, 10)
+				}
+				proto.Pln(`reserved`, strings.Join(nums, ", ")+`;`)
+			}
 			proto.Out()
 		}
 		proto.Pln(`}`)
@@ -387,6 +417,8 @@ func (g *Generator) generateProto(w io.Writer) error {
 			proto.Out()
 		}
 		proto.Pln(`}`)
+
+		t.generateProtoService(proto, g)
 	}
 
 	if !hasTimestampField {
@@ -395,6 +427,9 @@ func (g *Generator) generateProto(w io.Writer) error {
 		proto.Reset()
 		proto.WriteString(removedImport)
 	}
+	if err := fieldNumbers.save(g.serializerFieldNumbersFile); err != nil {
+		return errors.WithStack(err)
+	}
 	return proto.GenerateFile(w)
 }
 
@@ -0,0 +1,85 @@
+package dmlgen
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/corestoreio/errors"
+)
+
+// protoFieldNumbers keeps the field numbers of the generated proto messages
+// stable between generator runs. The outer key is the message name, the inner
+// key the field name. Numbers of removed fields stay in the map so that they
+// never get reused and can be written as reserved.
+type protoFieldNumbers map[string]map[string]uint64
+
+func loadProtoFieldNumbers(file string) (protoFieldNumbers, error) {
+	pfn := protoFieldNumbers{}
+	if file == "" {
+		return pfn, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	switch {
+	case os.IsNotExist(err):
+		return pfn, nil
+	case err != nil:
+		return nil, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(data, &pfn); err != nil {
+		return nil, errors.Wrapf(err, "[dmlgen] Failed to decode proto field numbers file %q", file)
+	}
+	return pfn, nil
+}
+
+func (pfn protoFieldNumbers) save(file string) error {
+	if file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(pfn, "", "\t") // keys are getting sorted
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(ioutil.WriteFile(file, append(data, '\n'), 0o644))
+}
+
+// number returns the already assigned number of a field. A new field gets the
+// preferred number, if that is still free, otherwise the next number after the
+// highest one of the message.
+func (pfn protoFieldNumbers) number(message, field string, preferred uint64) uint64 {
+	fields, ok := pfn[message]
+	if !ok {
+		fields = map[string]uint64{}
+		pfn[message] = fields
+	}
+	if n, ok := fields[field]; ok {
+		return n
+	}
+	var maxNum uint64
+	isUsed := false
+	for _, n := range fields {
+		if n > maxNum {
+			maxNum = n
+		}
+		isUsed = isUsed || n == preferred
+	}
+	if preferred == 0 || isUsed {
+		preferred = maxNum + 1
+	}
+	fields[field] = preferred
+	return preferred
+}
+
+// reserved returns the sorted field numbers of a message which are not part of
+// argument usedFields anymore.
+func (pfn protoFieldNumbers) reserved(message string, usedFields map[string]bool) []uint64 {
+	var ret []uint64
+	for f, n := range pfn[message] {
+		if !usedFields[f] {
+			ret = append(ret, n)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
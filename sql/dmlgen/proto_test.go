@@ -1,8 +1,11 @@
 package dmlgen
 
 import (
+	"bytes"
+	"path/filepath"
 	"testing"
 
+	"github.com/corestoreio/pkg/sql/ddl"
 	"github.com/corestoreio/pkg/util/assert"
 	"github.com/corestoreio/pkg/util/codegen"
 )
@@ -19,3 +22,75 @@ func TestBuildDMLProtoMapper(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestGenerator_generateProto_FieldNumbers(t *testing.T) {
+	fieldNumbersFile := filepath.Join(t.TempDir(), "fieldnumbers.json")
+
+	genProto := func(t *testing.T, cols ddl.Columns) string {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("core_config", cols),
+			WithProtobuf(&SerializerConfig{
+				PackageImportPath: "github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated/dmltestgeneratedpb",
+				FieldNumbersFile:  fieldNumbersFile,
+			}),
+		)
+		assert.NoError(t, err)
+		var buf bytes.Buffer
+		assert.NoError(t, g.GenerateSerializer(&buf, nil))
+		return buf.String()
+	}
+
+	have := genProto(t, ddl.Columns{
+		&ddl.Column{Field: "config_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI"},
+		&ddl.Column{Field: "scope", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(8)", Comment: "Scope of the value"},
+		&ddl.Column{Field: "value", Pos: 3, Null: "YES", DataType: "text", ColumnType: "text"},
+	})
+	assert.Contains(t, have, "// Scope of the value\n\tstring Scope = 2 ;")
+	assert.Contains(t, have, "optional string Value = 3 ;")
+
+	// scope gets removed, path gets inserted at the position of scope.
+	have = genProto(t, ddl.Columns{
+		&ddl.Column{Field: "config_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI"},
+		&ddl.Column{Field: "path", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)"},
+		&ddl.Column{Field: "value", Pos: 3, Null: "YES", DataType: "text", ColumnType: "text"},
+	})
+	assert.Contains(t, have, "uint32 ConfigID = 1 ;")
+	assert.Contains(t, have, "string Path = 4 ;")
+	assert.Contains(t, have, "optional string Value = 3 ;")
+	assert.Contains(t, have, "reserved 2;")
+}
+
+func TestGenerator_EntityProtoConverters(t *testing.T) {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+		WithTable("core_config", ddl.Columns{
+			&ddl.Column{Field: "config_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "path", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)"},
+			&ddl.Column{Field: "value", Pos: 3, Null: "YES", DataType: "text", ColumnType: "text"},
+			&ddl.Column{Field: "created_at", Pos: 4, Null: "NO", DataType: "timestamp", ColumnType: "timestamp"},
+			&ddl.Column{Field: "updated_at", Pos: 5, Null: "YES", DataType: "timestamp", ColumnType: "timestamp"},
+		}),
+		WithProtobuf(&SerializerConfig{
+			PackageImportPath: "github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated/dmltestgeneratedpb",
+			EntityConverters:  true,
+		}),
+	)
+	assert.NoError(t, err)
+	var buf, bufTest bytes.Buffer
+	assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+	have := buf.String()
+	assert.Contains(t, have, "func (e *CoreConfig) ToProto() *dmltestgeneratedpb.CoreConfig {\n"+
+		"\tx := new(dmltestgeneratedpb.CoreConfig)\n"+
+		"\tx.ConfigID = e.ConfigID\n"+
+		"\tx.Path = e.Path\n"+
+		"\tx.Value = e.Value.Ptr()\n"+
+		"\tx.CreatedAt = timestamppb.New(e.CreatedAt)\n"+
+		"\tx.UpdatedAt = e.UpdatedAt.Proto()\n"+
+		"\treturn x\n}")
+	assert.Contains(t, have, "func (e *CoreConfig) FromProto(x *dmltestgeneratedpb.CoreConfig) *CoreConfig {\n"+
+		"\te.ConfigID = x.GetConfigID()\n"+
+		"\te.Path = x.GetPath()\n"+
+		"\te.Value.SetPtr(x.Value)\n"+
+		"\te.CreatedAt = x.GetCreatedAt().AsTime()\n"+
+		"\te.UpdatedAt.SetProto(x.GetUpdatedAt())\n"+
+		"\treturn e\n}")
+}
@@ -0,0 +1,126 @@
+package dmlgen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+func (to *TableConfig) applyRelationNames(t *Table) {
+	if len(to.RelationNames) == 0 {
+		return
+	}
+	if t.relationNames == nil {
+		t.relationNames = make(map[string]string, len(to.RelationNames))
+	}
+	for k, v := range to.RelationNames {
+		t.relationNames[k] = v
+	}
+}
+
+// loadRelationNames returns the names of the relations which can be loaded by
+// the generated LoadRelations function. An empty slice means that the function
+// does not get generated.
+func (t *Table) loadRelationNames(g *Generator) []string {
+	var relNames []string
+	for _, rs := range t.loadableRelations(g) {
+		relNames = append(relNames, rs.mappedStructFieldName)
+	}
+	return relNames
+}
+
+// loadableRelations returns the relations whose parent column is part of the
+// primary key, which also includes composite primary keys. Many-to-many
+// relations are not supported because they require a join over the link
+// table.
+func (t *Table) loadableRelations(g *Generator) []relationShipInfo {
+	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityRelationships) ||
+		!t.hasFeature(g, FeatureCollectionStruct|FeatureDBSelect) || len(t.availableRelationships) == 0 {
+		return nil
+	}
+	pkCols := t.Table.Columns.PrimaryKeys()
+	var rels []relationShipInfo
+	for _, rs := range t.availableRelationships {
+		rt := g.Tables[rs.tableName]
+		if rs.isManyToMany || rt == nil || !pkCols.Contains(rs.parentColumnName) || !rt.Table.Columns.Contains(rs.columnName) {
+			continue
+		}
+		rels = append(rels, rs)
+	}
+	return rels
+}
+
+// fnCollectionLoadRelations writes the eager loading function for all
+// relations of a collection. Each relation gets loaded with one IN query to
+// avoid N+1 queries. With a composite primary key several parents can share
+// the value of the parent column, hence a child gets assigned to all of them.
+func (t *Table) fnCollectionLoadRelations(mainGen *codegen.Go, g *Generator) {
+	rels := t.loadableRelations(g)
+	if len(rels) == 0 {
+		return
+	}
+	relNames := t.loadRelationNames(g)
+
+	mainGen.C(`LoadRelations loads the relations of all entities with one SELECT query per relation instead of one
+query per entity. The argument names restricts the relations to load, if empty all relations get loaded.
+Nothing gets loaded if dml.QueryOptions.SkipRelations has been set. Available names:`, strings.Join(relNames, ", "))
+	mainGen.Pln(`func (cc *`, t.CollectionName(), `) LoadRelations(ctx context.Context, dbm *DBM, names ...string) (err error) {`)
+	mainGen.In()
+	mainGen.Pln(`if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil
+	}
+	isRequested := func(name string) bool {
+		if len(names) == 0 {
+			return true
+		}
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, e := range cc.Data {
+		if e.Relations == nil {
+			e.NewRelations()
+		}
+	}`)
+
+	for _, rs := range rels {
+		rt := g.Tables[rs.tableName]
+		parentCol := t.Table.Columns.ByField(rs.parentColumnName)
+		childCol := rt.Table.Columns.ByField(rs.columnName)
+		parentFieldName := t.GoCamelMaybePrivate(parentCol.Field)
+		field := `e.Relations.` + rs.mappedStructFieldName
+		isOne := rs.structName == rt.EntityName() // 1:1 relations point to an entity
+		mainGen.Pln(`if isRequested(`, strconv.Quote(rs.mappedStructFieldName), `) {`)
+		mainGen.In()
+		mainGen.Pln(`ids := make([]`, g.goTypeNull(parentCol), `, 0, len(cc.Data))
+		parents := make(map[`, g.goTypeNull(childCol), `][]*`, t.EntityName(), `, len(cc.Data))
+		for _, e := range cc.Data {`)
+		mainGen.Pln(!isOne, field, ` = &`, rt.CollectionName(), `{}`)
+		mainGen.Pln(isOne, field, ` = nil`)
+		mainGen.Pln(`key := `, g.convertType(parentCol, childCol, `e.`+parentFieldName), `
+			if _, ok := parents[key]; !ok {
+				ids = append(ids, e.`, parentFieldName, `)
+			}
+			parents[key] = append(parents[key], e)
+		}
+		children := &`, rt.CollectionName(), `{}
+		if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, t.EntityName(), rs.mappedStructFieldName, `SelectByFKs"`), `).Load(ctx, children, ids); err != nil {
+			return errors.WithStack(err)
+		}
+		for _, c := range children.Data {
+			for _, e := range parents[c.`, rt.GoCamelMaybePrivate(rs.columnName), `] {`)
+		mainGen.Pln(!isOne, codegen.SkipWS(field, `.Data`), `= append(`, codegen.SkipWS(field, `.Data`), `, c)`)
+		mainGen.Pln(isOne, field, ` = c`)
+		mainGen.Pln(`}
+		}`)
+		mainGen.Out()
+		mainGen.Pln(`}`)
+	}
+	mainGen.Pln(`return nil`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+}
@@ -0,0 +1,173 @@
+package dmlgen
+
+import (
+	"strconv"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+// softDeleteCacheKeySuffix gets appended to the cache key of the SELECT
+// queries which include soft deleted rows.
+const softDeleteCacheKeySuffix = "WithDeleted"
+
+func (to *TableConfig) applySoftDeleteColumn(t *Table) {
+	t.softDeleteColumn = ""
+	if to.SoftDeleteColumn == "" || to.lastErr != nil {
+		return
+	}
+	c := t.Table.Columns.ByField(to.SoftDeleteColumn)
+	switch {
+	case c.Field == "":
+		to.lastErr = errors.NotFound.Newf("[dmlgen] WithTableConfig:SoftDeleteColumn: For table %q the Column %q cannot be found.",
+			t.Table.Name, to.SoftDeleteColumn)
+	case !c.IsNull() || (c.DataType != "datetime" && c.DataType != "timestamp"):
+		to.lastErr = errors.NotSupported.Newf("[dmlgen] WithTableConfig:SoftDeleteColumn: For table %q the Column %q must be a nullable datetime or timestamp.",
+			t.Table.Name, to.SoftDeleteColumn)
+	case t.Table.IsView():
+		to.lastErr = errors.NotSupported.Newf("[dmlgen] WithTableConfig:SoftDeleteColumn: Table %q is a view.", t.Table.Name)
+	default:
+		t.softDeleteColumn = c.Field
+	}
+}
+
+func (ts tables) hasSoftDelete() bool {
+	for _, t := range ts {
+		if t.softDeleteColumn != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// selectCacheKey returns the Go expression for the cache key of a SELECT
+// query. For soft delete tables the key depends on the variable written by
+// fnSoftDeleteCacheKeySuffix.
+func (t *Table) selectCacheKey(key string) string {
+	if t.softDeleteColumn == "" {
+		return strconv.Quote(key)
+	}
+	return strconv.Quote(key) + " + cacheKeySuffix"
+}
+
+func (t *Table) fnSoftDeleteCacheKeySuffix(mainGen *codegen.Go, dmlEnabled bool) {
+	mainGen.Pln(dmlEnabled && t.softDeleteColumn != "", `var cacheKeySuffix string
+	if qo.WithDeleted {
+		cacheKeySuffix = `, strconv.Quote(softDeleteCacheKeySuffix), `
+	}`)
+}
+
+// deleteFuncName returns the name of the function which removes the rows. For
+// soft delete tables this is the ForceDelete variant.
+func (t *Table) deleteFuncName(name string) string {
+	if t.softDeleteColumn == "" {
+		return name
+	}
+	if name == "DBDelete" {
+		return "DBForceDelete"
+	}
+	return "Force" + name
+}
+
+// fnSoftDeleteWithDeleted writes the context toggle to include soft deleted
+// rows in the Load and DBLoad functions.
+func (g *Generator) fnSoftDeleteWithDeleted(mainGen *codegen.Go, tbls tables) {
+	if !tbls.hasFeature(g, FeatureDBSelect) || !tbls.hasSoftDelete() {
+		return
+	}
+	mainGen.C(`WithDeleted returns a new context which includes soft deleted rows in
+the Load and DBLoad functions of tables with a soft delete column.`)
+	mainGen.Pln(`func WithDeleted(ctx context.Context) context.Context {
+		qo := dml.FromContextQueryOptions(ctx)
+		qo.WithDeleted = true
+		return dml.WithContextQueryOptions(ctx, qo)
+	}`)
+}
+
+// fnSoftDeleteSetColumn writes the code which sets the soft delete column in
+// the entity e. With enabled SkipTimestamps the database sets the timestamp.
+func (t *Table) fnSoftDeleteSetColumn(mainGen *codegen.Go, now string) {
+	mainGen.Pln(`cacheKey := `, strconv.Quote(t.EntityName()+"SoftDeleteByPK"), `
+	if qo.SkipTimestamps {
+		cacheKey = `, strconv.Quote(t.EntityName()+"SoftDeleteByPKNow"), `
+	}`)
+	mainGen.Pln(`setDeletedAt := func(e *`, t.EntityName(), `) {
+		if !qo.SkipTimestamps {
+			e.`, t.GoCamelMaybePrivate(t.softDeleteColumn), ` = null.MakeTime(`, now, `)
+		}
+	}`)
+}
+
+func (t *Table) fnEntitySoftDelete(mainGen *codegen.Go, g *Generator) {
+	if t.softDeleteColumn == "" || !t.hasFeature(g, FeatureDBDelete) {
+		return
+	}
+	entityEventName := codegen.SkipWS(`event`, t.EntityName(), `Func`)
+	funcName := codegen.SkipWS(t.EntityName(), "SoftDeleteByPK")
+
+	mainGen.C(`Delete marks the entity as deleted by setting the column`, t.softDeleteColumn, `to the current time.
+Use ForceDelete to remove the row from the database.`)
+	mainGen.Pln(`func (e *`, t.EntityName(), `) Delete(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
+	mainGen.Pln(t.hasFeature(g, FeatureDBTracing), `	ctx, span := dbm.option.Trace.Start(ctx, `, strconv.Quote(string(funcName)), `)
+			defer func(){ cstrace.Status(span, err, ""); span.End(); }()`)
+	mainGen.Pln(`if e == nil {
+		return nil, errors.NotValid.Newf(`, codegen.SkipWS(`"`, t.EntityName()), `can't be nil")
+	}`)
+	mainGen.Pln(`qo := dml.FromContextQueryOptions(ctx)`)
+	t.fnSoftDeleteSetColumn(mainGen, "time.Now()")
+	mainGen.Pln(`if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeDelete, qo.SkipEvents, nil, e); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		setDeletedAt(e)
+		if res, err = dbm.ConnPool.WithCacheKey(cacheKey, opts...).ExecContext(ctx, e); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err = dbm.`, entityEventName, `(ctx, dml.EventFlagAfterDelete, qo.SkipEvents, nil, e); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return res, nil
+	}`)
+}
+
+func (t *Table) fnCollectionSoftDelete(mainGen *codegen.Go, g *Generator) {
+	if t.softDeleteColumn == "" || !t.hasFeature(g, FeatureDBDelete) {
+		return
+	}
+	entityEventName := codegen.SkipWS(`event`, t.EntityName(), `Func`)
+	funcName := codegen.SkipWS(t.CollectionName(), "SoftDeleteByPK")
+
+	mainGen.C(`DBDelete marks all entities as deleted by setting the column`, t.softDeleteColumn, `to the current time.
+Use DBForceDelete to remove the rows from the database.`)
+	mainGen.Pln(`func (cc *`, t.CollectionName(), `) DBDelete(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
+	mainGen.Pln(t.hasFeature(g, FeatureDBTracing), `	ctx, span := dbm.option.Trace.Start(ctx, `, strconv.Quote(string(funcName)), `)
+			defer func(){ cstrace.Status(span, err, ""); span.End(); }()`)
+	mainGen.Pln(`if cc == nil {
+		return nil, errors.NotValid.Newf(`, codegen.SkipWS(`"`, t.CollectionName()), `can't be nil")
+	}`)
+	mainGen.Pln(`qo := dml.FromContextQueryOptions(ctx)`)
+	mainGen.Pln(`now := time.Now()`)
+	t.fnSoftDeleteSetColumn(mainGen, "now")
+	mainGen.Pln(`if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeDelete, qo.SkipEvents, cc, nil); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		dbr := dbm.ConnPool.WithCacheKey(cacheKey, opts...)
+		dbrStmt, err := dbr.Prepare(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer dbrStmt.Close()
+		var rowCount int64
+		for _, e := range cc.Data {
+			setDeletedAt(e)
+			res, err := dbrStmt.ExecContext(ctx, e)
+			if err := dbr.ResultCheckFn(`, constTableName(t.Table.Name), `, 1, res, err); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			rowCount++
+		}
+		if err = dbm.`, entityEventName, `(ctx, dml.EventFlagAfterDelete, qo.SkipEvents, cc, nil); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return dml.StaticSQLResult{Rows: rowCount}, nil
+	}`)
+}
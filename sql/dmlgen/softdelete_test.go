@@ -0,0 +1,59 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestGenerator_SoftDeleteColumn(t *testing.T) {
+	cols := func() ddl.Columns {
+		return ddl.Columns{
+			&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "name", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(64)"},
+			&ddl.Column{Field: "deleted_at", Pos: 3, Null: "YES", DataType: "datetime", ColumnType: "datetime"},
+		}
+	}
+
+	t.Run("generates soft delete", func(t *testing.T) {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("customer_entity", cols()),
+			WithTableConfig("customer_entity", &TableConfig{SoftDeleteColumn: "deleted_at"}),
+		)
+		assert.NoError(t, err)
+		var buf, bufTest bytes.Buffer
+		assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+		have := buf.String()
+		assert.Contains(t, have, "func WithDeleted(ctx context.Context) context.Context {")
+		assert.Contains(t, have, `"CustomerEntitySelectByPKWithDeleted"`)
+		assert.Contains(t, have, "dml.Column(`deleted_at`).Null(),")
+		assert.Contains(t, have, `"CustomerEntitySoftDeleteByPKNow"`)
+		assert.Contains(t, have, `.Update().SetColumns().AddClauses(dml.Column("deleted_at").Expr("NOW()"))`)
+		assert.Contains(t, have, `"CustomerEntitySelectByPK"+cacheKeySuffix`)
+		assert.Contains(t, have, "func (e *CustomerEntity) Delete(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {")
+		assert.Contains(t, have, "func (e *CustomerEntity) ForceDelete(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {")
+		assert.Contains(t, have, "func (cc *CustomerEntities) DBForceDelete(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {")
+	})
+
+	t.Run("without soft delete", func(t *testing.T) {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("customer_entity", cols()),
+		)
+		assert.NoError(t, err)
+		var buf, bufTest bytes.Buffer
+		assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+		assert.NotContains(t, buf.String(), "WithDeleted")
+		assert.NotContains(t, buf.String(), "ForceDelete")
+	})
+
+	t.Run("column not nullable", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("customer_entity", cols()),
+			WithTableConfig("customer_entity", &TableConfig{SoftDeleteColumn: "name"}),
+		)
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+}
@@ -41,6 +41,14 @@ type Table struct {
 	privateFields          map[string]bool
 	featuresInclude        FeatureToggle
 	featuresExclude        FeatureToggle
+	grpcService            bool
+	graphQL                bool
+	jsonOmitEmpty          map[string]bool
+	upsertExclude          []string // columns not written into ON DUPLICATE KEY UPDATE
+	softDeleteColumn       string
+	optimisticLock         string // version column, see TableConfig.OptimisticLockColumn
+	enumColumns            []enumColumn
+	relationNames          map[string]string
 	fieldMapFn             func(dbIdentifier string) (newName string)
 	customStructTagFields  map[string]string
 	relationshipSeen       map[string]bool // to not print twice a relationship
@@ -53,6 +61,8 @@ type relationShipInfo struct {
 	structName            string
 	mappedStructFieldName string // name of the struct in the relation struct
 	columnName            string
+	parentColumnName      string // column of the current table
+	isManyToMany          bool   // tableName is the link table
 }
 
 func (t *Table) getFieldMapFn(g *Generator) func(dbIdentifier string) (newName string) {
@@ -63,7 +73,15 @@ func (t *Table) getFieldMapFn(g *Generator) func(dbIdentifier string) (newName s
 	if fieldMapFn == nil {
 		fieldMapFn = defaultFieldMapFn
 	}
-	return fieldMapFn
+	if len(t.relationNames) == 0 {
+		return fieldMapFn
+	}
+	return func(dbIdentifier string) string {
+		if n, ok := t.relationNames[dbIdentifier]; ok {
+			return n
+		}
+		return fieldMapFn(dbIdentifier)
+	}
 }
 
 func (t *Table) IsFieldPublic(dbColumnName string) bool {
@@ -244,6 +262,7 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
 				})
 
 				mainGen.Pln(fieldName, " *", name,
@@ -265,6 +284,7 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
 				})
 
 				mainGen.Pln(fieldName, " *", name, t.customStructTagFields[kcuce.ReferencedTableName.Data],
@@ -283,10 +303,12 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 				fieldName := fieldMapFn(name)
 				t.availableRelationships = append(t.availableRelationships, relationShipInfo{
 					isCollection:          true,
+					isManyToMany:          true,
 					tableName:             kcuce.ReferencedTableName.Data,
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
 				})
 
 				mainGen.Pln(fieldName, " *", name, t.customStructTagFields[targetTbl],
@@ -322,6 +344,7 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
 				})
 
 				mainGen.Pln(fieldName, " *", name, t.customStructTagFields[kcuce.ReferencedTableName.Data],
@@ -342,6 +365,7 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
 				})
 
 				mainGen.Pln(fieldName, " *", name, t.customStructTagFields[kcuce.ReferencedTableName.Data],
@@ -367,10 +391,12 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 				fieldName := fieldMapFn(name)
 				t.availableRelationships = append(t.availableRelationships, relationShipInfo{
 					isCollection:          true,
+					isManyToMany:          true,
 					tableName:             kcuce.ReferencedTableName.Data,
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
 				})
 
 				mainGen.Pln(fieldName, " *", name, t.customStructTagFields[targetTbl],
@@ -433,7 +459,7 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 		// </INSERT>
 
 		// <UPDATE>
-		mainGen.Pln(`func (r *customerEntityRelations) `, codegen.SkipWS(`Update`, rs.mappedStructFieldName), `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {`)
+		mainGen.Pln(`func (r *`, t.relationStructName(), `) `, codegen.SkipWS(`Update`, rs.mappedStructFieldName), `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {`)
 		mainGen.Pln(`if r.`, rs.mappedStructFieldName, ` == nil || len(r.`, rs.mappedStructFieldName, `.Data) == 0 {
 			dbr := dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `DeleteByFK`, `"`), `, opts...)
 			res, err := dbr.ExecContext(ctx, r.parent.`, parentPKFieldName, `)
@@ -453,9 +479,9 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 
 		// <SELECT>
 		mainGen.Pln(`func (r *`, t.relationStructName(), `) `, "Load"+rs.mappedStructFieldName, `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (rowCount uint64, err error) {`)
-		mainGen.Pln(`if r.`, rs.mappedStructFieldName, ` == nil { r.`, rs.mappedStructFieldName, ` = &`, rs.mappedStructFieldName, `{} }`)
+		mainGen.Pln(`if r.`, rs.mappedStructFieldName, ` == nil { r.`, rs.mappedStructFieldName, ` = &`, rs.structName, `{} }`)
 		mainGen.Pln(`r.`, rs.mappedStructFieldName, `.Clear()
-			  rowCount, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectByFK"`), `, opts...).Load(ctx, r.`, rs.mappedStructFieldName, `, r.parent.EntityID)
+			  rowCount, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectByFK"`), `, opts...).Load(ctx, r.`, rs.mappedStructFieldName, `, r.parent.`, parentPKFieldName, `)
 				return rowCount, errors.WithStack(err) }`)
 		// </SELECT>
 
@@ -479,6 +505,7 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 
 	// <SELECT_ALL>
 	mainGen.Pln(`func (r *`, t.relationStructName(), `) LoadAll(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {`)
+	mainGen.Pln(`if dml.FromContextQueryOptions(ctx).SkipRelations { return nil }`)
 	for _, rs := range t.availableRelationships {
 		mainGen.Pln(`if _, err = r.`, codegen.SkipWS("Load", rs.mappedStructFieldName), `(ctx, dbm, opts...); err != nil { return errors.WithStack(err) }`)
 	}
@@ -533,6 +560,64 @@ func (t *Table) fnEntityGetSetPrivateFields(mainGen *codegen.Go, g *Generator) {
 	}
 }
 
+func (t *Table) fnEntityProto(mainGen *codegen.Go, g *Generator) {
+	if !g.hasEntityConverters() || !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityProto) {
+		return
+	}
+	pbType := g.protoTypePrefix() + t.EntityName()
+
+	mainGen.C(`ToProto converts the entity into its protocol buffers message. Private fields are not getting exported.`)
+	mainGen.Pln(`func (e *`, t.EntityName(), `) ToProto() *`, pbType, ` {`)
+	{
+		mainGen.In()
+		mainGen.Pln(`x := new(`, pbType, `)`)
+		t.Table.Columns.Each(func(c *ddl.Column) {
+			if t.IsFieldPrivate(c.Field) {
+				return
+			}
+			f := strs.ToGoCamelCase(c.Field)
+			switch gt := g.goTypeNull(c); {
+			case gt == "null.Time":
+				mainGen.Pln(`x.`, f, ` = e.`, f, `.Proto()`)
+			case gt == "time.Time":
+				mainGen.Pln(`x.`, f, ` = timestamppb.New(e.`, f, `)`)
+			case strings.HasPrefix(gt, "null."):
+				mainGen.Pln(`x.`, f, ` = e.`, f, `.Ptr()`)
+			default:
+				mainGen.Pln(`x.`, f, ` = e.`, f)
+			}
+		})
+		mainGen.Pln(`return x`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+
+	mainGen.C(`FromProto applies the data of the protocol buffers message to the entity. Private fields are not getting touched.`)
+	mainGen.Pln(`func (e *`, t.EntityName(), `) FromProto(x *`, pbType, `) *`, t.EntityName(), ` {`)
+	{
+		mainGen.In()
+		t.Table.Columns.Each(func(c *ddl.Column) {
+			if t.IsFieldPrivate(c.Field) {
+				return
+			}
+			f := strs.ToGoCamelCase(c.Field)
+			switch gt := g.goTypeNull(c); {
+			case gt == "null.Time":
+				mainGen.Pln(`e.`, f, `.SetProto(`, codegen.SkipWS(`x.Get`, f, `()`), `)`)
+			case gt == "time.Time":
+				mainGen.Pln(`e.`, f, ` = `, codegen.SkipWS(`x.Get`, f, `()`), `.AsTime()`)
+			case strings.HasPrefix(gt, "null."):
+				mainGen.Pln(`e.`, f, `.SetPtr(x.`, f, `)`)
+			default:
+				mainGen.Pln(`e.`, f, ` = `, codegen.SkipWS(`x.Get`, f, `()`))
+			}
+		})
+		mainGen.Pln(`return e`)
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+}
+
 func (t *Table) fnEntityEmpty(mainGen *codegen.Go, g *Generator) {
 	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityEmpty) {
 		return
@@ -869,6 +954,113 @@ func (t *Table) fnCollectionEach(mainGen *codegen.Go, g *Generator) {
 	mainGen.Pln(`}`)
 }
 
+func (t *Table) fnCollectionFirstLast(mainGen *codegen.Go, g *Generator) {
+	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureCollectionFirstLast) {
+		return
+	}
+	mainGen.C(`First returns the first item or nil if the collection is empty. Auto generated via dmlgen.`)
+	mainGen.Pln(`func (cc *`, t.CollectionName(), `) First() *`, t.EntityName(), ` {
+	if cc == nil || len(cc.Data) == 0 {
+		return nil
+	}
+	return cc.Data[0]
+}`)
+	mainGen.C(`Last returns the last item or nil if the collection is empty. Auto generated via dmlgen.`)
+	mainGen.Pln(`func (cc *`, t.CollectionName(), `) Last() *`, t.EntityName(), ` {
+	if cc == nil || len(cc.Data) == 0 {
+		return nil
+	}
+	return cc.Data[len(cc.Data)-1]
+}`)
+}
+
+// isIndexedColumn reports if a column is part of a primary, unique or
+// non-unique index.
+func isIndexedColumn(c *ddl.Column) bool {
+	return c.IsPK() || c.IsUnique() || c.Key == "MUL"
+}
+
+// sortableColumn returns the Go expressions to compare two entities a and b
+// by column c. Returns empty strings if the column type cannot be ordered.
+func (g *Generator) sortableColumn(c *ddl.Column) (less, greater string) {
+	if g.isCustomType(c) {
+		return "", ""
+	}
+	gt := g.goType(c)
+	f := g.toGoPrimitiveFromNull(c)
+	a, b := `cc.Data[i].`+f, `cc.Data[j].`+f
+	switch {
+	case gt == "time.Time":
+		return a + `.Before(` + b + `)`, a + `.After(` + b + `)`
+	case gt == "string", strings.HasPrefix(gt, "int"), strings.HasPrefix(gt, "uint"), strings.HasPrefix(gt, "float"):
+		return a + ` < ` + b, a + ` > ` + b
+	}
+	return "", ""
+}
+
+func (t *Table) fnCollectionSort(mainGen *codegen.Go, g *Generator) {
+	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureCollectionSort) {
+		return
+	}
+	for _, c := range t.Table.Columns {
+		if !isIndexedColumn(c) {
+			continue
+		}
+		less, greater := g.sortableColumn(c)
+		if less == "" {
+			continue
+		}
+		goCamel := strs.ToGoCamelCase(c.Field)
+		mainGen.C(`SortBy`+goCamel, `sorts the collection in place by column`, strconv.Quote(c.Field),
+			`in ascending or descending order. The sort is stable. Auto generated via dmlgen.`)
+		mainGen.Pln(`func (cc *`, t.CollectionName(), `) SortBy`+goCamel+`(asc bool) *`, t.CollectionName(), ` {
+	if cc == nil {
+		return nil
+	}
+	sort.SliceStable(cc.Data, func(i, j int) bool {
+		if asc {
+			return `, less, `
+		}
+		return `, greater, `
+	})
+	return cc
+}`)
+	}
+}
+
+func (t *Table) fnCollectionSplit(mainGen *codegen.Go, g *Generator) {
+	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureCollectionSplit) {
+		return
+	}
+	for _, c := range t.Table.Columns {
+		if c.Key != "MUL" || g.isCustomType(c) || c.IsFloat() || c.IsTime() || c.IsBlobDataType() {
+			continue
+		}
+		gtn := g.goTypeNull(c)
+		if gtn == "[]byte" || gtn == "null.Decimal" {
+			continue
+		}
+		goCamel := strs.ToGoCamelCase(c.Field)
+		mainGen.C(`SplitBy`+goCamel, `groups the items by the values of column`, strconv.Quote(c.Field),
+			`into new collections. The items are shared with the current collection. Auto generated via dmlgen.`)
+		mainGen.Pln(`func (cc *`, t.CollectionName(), `) SplitBy`+goCamel+`() map[`+gtn+`]*`, t.CollectionName(), ` {
+	if cc == nil {
+		return nil
+	}
+	ret := make(map[`+gtn+`]*`, t.CollectionName(), `)
+	for _, e := range cc.Data {
+		c, ok := ret[e.`+goCamel+`]
+		if !ok {
+			c = &`, t.CollectionName(), `{}
+			ret[e.`+goCamel+`] = c
+		}
+		c.Data = append(c.Data, e)
+	}
+	return ret
+}`)
+	}
+}
+
 // Clear because Reset name is used by gogo protobuf
 func (t *Table) fnCollectionClear(mainGen *codegen.Go, g *Generator) {
 	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureCollectionClear) {
@@ -1135,6 +1327,7 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		defer func(){ cstrace.Status(span, err, ""); span.End(); }()`)
 	mainGen.Pln(dmlEnabled, `cc.Clear()`)
 	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	t.fnSoftDeleteCacheKeySuffix(mainGen, dmlEnabled)
 
 	mainGen.Pln(dmlEnabled, `// put the IDs`, bufPKNames.String(), `into the context as value to search for a cache entry in the event function.
 	if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeSelect, qo.SkipEvents, cc, nil); err != nil {
@@ -1145,7 +1338,7 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 	}`)
 
 	if tblPkCols.Len() > 1 { // for tables with more than one PK
-		mainGen.Pln(dmlEnabled, `	cacheKey := `, codegen.SkipWS(`"`, collectionFuncName, "", `"`), `
+		mainGen.Pln(dmlEnabled, `	cacheKey := `, t.selectCacheKey(string(collectionFuncName)), `
 	var args []interface{}
 	if len(pkIDs) > 0 {
 		args = make([]interface{}, 0, len(pkIDs)*`, tblPkCols.Len(), `)
@@ -1154,7 +1347,7 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 			mainGen.Pln(dmlEnabled, `args = append(args, pk.`, strs.ToGoCamelCase(c.Field), `)`)
 		})
 		mainGen.Pln(dmlEnabled, `}
-		cacheKey = `, codegen.SkipWS(`"`, t.CollectionName(), "SelectByPK", `"`), `
+		cacheKey = `, t.selectCacheKey(t.CollectionName()+"SelectByPK"), `
 	}
 	if _, err = dbm.ConnPool.WithCacheKey(cacheKey, opts...).Load(ctx, cc, args...); err != nil {
 		return errors.WithStack(err)
@@ -1163,14 +1356,14 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		mainGen.Pln(dmlEnabled, `if len(pkIDs) > 0 {`)
 		mainGen.In()
 		{
-			mainGen.Pln(dmlEnabled, `if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, t.CollectionName(), "SelectByPK", `"`), `, opts...).Load(ctx, cc, pkIDs); err != nil {
+			mainGen.Pln(dmlEnabled, `if _, err = dbm.ConnPool.WithCacheKey(`, t.selectCacheKey(t.CollectionName()+"SelectByPK"), `, opts...).Load(ctx, cc, pkIDs); err != nil {
 		return errors.WithStack(err); }`)
 		}
 		mainGen.Out()
 		mainGen.Pln(dmlEnabled, `} else {`)
 		mainGen.In()
 		{
-			mainGen.Pln(dmlEnabled, `if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, collectionFuncName, "", `"`), `, opts...).Load(ctx, cc); err != nil {
+			mainGen.Pln(dmlEnabled, `if _, err = dbm.ConnPool.WithCacheKey(`, t.selectCacheKey(string(collectionFuncName)), `, opts...).Load(ctx, cc); err != nil {
 		return errors.WithStack(err); }`)
 		}
 		mainGen.Out()
@@ -1187,7 +1380,8 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 
 	dmlEnabled = t.hasFeature(g, FeatureDBDelete)
 	collectionFuncName = codegen.SkipWS(t.EntityName(), "DeleteByPK")
-	mainGen.Pln(dmlEnabled, `func (cc `, collectionPTRName, `) DBDelete(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result,err error) {`)
+	t.fnCollectionSoftDelete(mainGen, g)
+	mainGen.Pln(dmlEnabled, `func (cc `, collectionPTRName, `) `, t.deleteFuncName("DBDelete"), `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result,err error) {`)
 	mainGen.Pln(dmlEnabled && tracingEnabled, `	ctx, span := dbm.option.Trace.Start(ctx, `, codegen.SkipWS(`"`, t.CollectionName(), "DeleteByPK", `"`), `)
 			defer func(){ cstrace.Status(span, err, ""); span.End(); }()`)
 	mainGen.Pln(dmlEnabled, `if cc == nil {
@@ -1229,8 +1423,9 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		res, err := dbrStmt.ExecContext(ctx, c)
 		if err := dbr.ResultCheckFn(`, constTableName(t.Table.Name), `, 1, res, err); err != nil {
 			return errors.WithStack(err)
-		}
-	}`)
+		}`)
+	t.fnOptimisticLockIncrement(mainGen, dmlEnabled, "c")
+	mainGen.Pln(dmlEnabled, `}`)
 
 	mainGen.Pln(dmlEnabled, `return errors.WithStack(dbm.`, entityEventName, `(ctx, dml.EventFlagAfterUpdate, qo.SkipEvents,cc, nil))
 	}`)
@@ -1271,7 +1466,8 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		}
 		dbr := dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, collectionFuncName, `"`), `, opts...)
 		res, err := dbr.ExecContext(ctx, dml.Qualify("", cc))
-		if err := dbr.ResultCheckFn(`, constTableName(t.Table.Name), `, len(cc.Data), res, err); err != nil {
+		// MySQL reports two affected rows for an updated and zero for an unchanged row.
+		if err := dbr.ResultCheckFn(`, constTableName(t.Table.Name), `, -1, res, err); err != nil {
 				return errors.WithStack(err)
 		}
 		return errors.WithStack(dbm.`, entityEventName, `(ctx, dml.EventFlagAfterUpsert, qo.SkipEvents,cc, nil))
@@ -1342,6 +1538,7 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 	}`)
 	mainGen.Pln(dmlEnabled && len(t.availableRelationships) > 0, `e.setRelationParent()`)
 	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	t.fnSoftDeleteCacheKeySuffix(mainGen, dmlEnabled)
 
 	mainGen.Pln(dmlEnabled, `// put the IDs`, bufPKNames.String(), `into the context as value to search for a cache entry in the event function.
 	if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeSelect, qo.SkipEvents, nil, e); err != nil {
@@ -1350,7 +1547,7 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 	if e.IsSet() {
 		return nil // might return data from cache
 	}
-	if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, entityFuncName, `"`), `, opts...).Load(ctx, e, `, &bufPKNames, `); err != nil {
+	if _, err = dbm.ConnPool.WithCacheKey(`, t.selectCacheKey(string(entityFuncName)), `, opts...).Load(ctx, e, `, &bufPKNames, `); err != nil {
 		return errors.WithStack(err)
 	}
 	return errors.WithStack(dbm.`, entityEventName, `(ctx, dml.EventFlagAfterSelect, qo.SkipEvents,nil, e))
@@ -1363,7 +1560,8 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 
 	dmlEnabled = t.hasFeature(g, FeatureDBDelete)
 	entityFuncName = codegen.SkipWS(t.EntityName(), "DeleteByPK")
-	mainGen.Pln(dmlEnabled, `func (e `, entityPTRName, `) Delete(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
+	t.fnEntitySoftDelete(mainGen, g)
+	mainGen.Pln(dmlEnabled, `func (e `, entityPTRName, `) `, t.deleteFuncName("Delete"), `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
 	mainGen.Pln(dmlEnabled && tracingEnabled, `	ctx, span := dbm.option.Trace.Start(ctx, `, codegen.SkipWS(`"`, entityFuncName, `"`), `)
 			defer func(){ cstrace.Status(span, err, ""); span.End(); }()`)
 	mainGen.Pln(dmlEnabled, `if e == nil {
@@ -1400,8 +1598,9 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 		}
 		if res, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, entityFuncName, `"`), `, opts...).ExecContext(ctx, e); err != nil {
 			return nil, errors.WithStack(err)
-		}
-		if err = dbm.`, entityEventName, `(ctx, dml.EventFlagAfterUpdate, qo.SkipEvents,nil, e); err != nil {
+		}`)
+	t.fnOptimisticLockIncrement(mainGen, dmlEnabled, "e")
+	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagAfterUpdate, qo.SkipEvents,nil, e); err != nil {
 			return nil, errors.WithStack(err)
 		}
 		return res, nil
@@ -1454,6 +1653,32 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 	}`)
 }
 
+// upsertQueryOptions returns the method chain which turns an INSERT into an
+// upsert. Auto increment primary key columns are not part of the default
+// INSERT columns but needed to detect the duplicate key, hence they get added
+// and excluded from the UPDATE part, like the configured columns.
+func (t *Table) upsertQueryOptions() string {
+	var autoIncCols []string
+	for _, c := range t.Table.Columns {
+		if c.IsAutoIncrement() {
+			autoIncCols = append(autoIncCols, strconv.Quote(c.Field))
+		}
+	}
+	var buf strings.Builder
+	if len(autoIncCols) > 0 {
+		buf.WriteString(".AddColumns(" + strings.Join(autoIncCols, ", ") + ")")
+	}
+	buf.WriteString(".OnDuplicateKey()")
+	exclude := autoIncCols
+	for _, cn := range t.upsertExclude {
+		exclude = append(exclude, strconv.Quote(cn))
+	}
+	if len(exclude) > 0 {
+		buf.WriteString(".AddOnDuplicateKeyExclude(" + strings.Join(exclude, ", ") + ")")
+	}
+	return buf.String()
+}
+
 func (t *Table) fnDBMOptionsSQLBuildQueries(mainGen *codegen.Go, g *Generator) {
 	tblPKLen := t.Table.Columns.PrimaryKeys().Len()
 	tblPK := t.Table.Columns.PrimaryKeys()
@@ -1476,25 +1701,45 @@ func (t *Table) fnDBMOptionsSQLBuildQueries(mainGen *codegen.Go, g *Generator) {
 		pkWhereEQ.WriteString("Tuples(),\n")
 	}
 
-	mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureCollectionStruct),
-		codegen.SkipWS(`"`, t.CollectionName(), `SelectAll"`),
-		`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")),`)
+	writeSelects := func(suffix, extraWhere string) {
+		selectAllWhere := ""
+		if extraWhere != "" {
+			selectAllWhere = ".Where(\n" + extraWhere + ")"
+		}
+		mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureCollectionStruct),
+			codegen.SkipWS(`"`, t.CollectionName(), `SelectAll`, suffix, `"`),
+			`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")`, selectAllWhere, `),`)
 
-	mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureEntityStruct|FeatureCollectionStruct),
-		codegen.SkipWS(`"`, t.CollectionName(), `SelectByPK"`),
-		`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")).Where(`, pkWhereIN.String(), `),`)
+		mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureEntityStruct|FeatureCollectionStruct),
+			codegen.SkipWS(`"`, t.CollectionName(), `SelectByPK`, suffix, `"`),
+			`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")).Where(`, pkWhereIN.String(), extraWhere, `),`)
 
-	mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureEntityStruct|FeatureCollectionStruct),
-		codegen.SkipWS(`"`, t.EntityName(), `SelectByPK"`),
-		`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")).Where(`, pkWhereEQ.String(), `),`)
+		mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureEntityStruct|FeatureCollectionStruct),
+			codegen.SkipWS(`"`, t.EntityName(), `SelectByPK`, suffix, `"`),
+			`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")).Where(`, pkWhereEQ.String(), extraWhere, `),`)
+	}
+	if t.softDeleteColumn == "" {
+		writeSelects("", "")
+	} else {
+		// soft deleted rows are excluded by default, the WithDeleted queries include them.
+		writeSelects("", "dml.Column(`"+t.softDeleteColumn+"`).Null(),\n")
+		writeSelects(softDeleteCacheKeySuffix, "")
+	}
 
 	if t.Table.IsView() {
 		return
 	}
 
+	if t.softDeleteColumn != "" && t.hasFeature(g, FeatureDBDelete|FeatureEntityStruct|FeatureCollectionStruct) {
+		mainGen.Pln(codegen.SkipWS(`"`, t.EntityName(), `SoftDeleteByPK"`),
+			`: tbls.MustTable(`, constTableName(t.Table.Name), `).Update().SetColumns(`, strconv.Quote(t.softDeleteColumn), `).Where(`, pkWhereEQ.String(), `),`)
+		mainGen.Pln(codegen.SkipWS(`"`, t.EntityName(), `SoftDeleteByPKNow"`),
+			`: tbls.MustTable(`, constTableName(t.Table.Name), `).Update().SetColumns().AddClauses(dml.Column(`, strconv.Quote(t.softDeleteColumn), `).Expr("NOW()")).Where(`, pkWhereEQ.String(), `),`)
+	}
+
 	mainGen.Pln(t.hasFeature(g, FeatureDBUpdate|FeatureEntityStruct|FeatureCollectionStruct),
 		codegen.SkipWS(`"`, t.EntityName(), `UpdateByPK"`),
-		`: dbmo.InitUpdateFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Update().Where(`, pkWhereEQ.String(), `)),`)
+		`: dbmo.InitUpdateFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Update().Where(`, pkWhereEQ.String(), `)`, t.optimisticLockUpdateOption(), `),`)
 	mainGen.Pln(t.hasFeature(g, FeatureDBDelete|FeatureEntityStruct|FeatureCollectionStruct),
 		codegen.SkipWS(`"`, t.EntityName(), `DeleteByPK"`),
 		`: dbmo.InitDeleteFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Delete().Where(`, pkWhereIN.String(), `)),`)
@@ -1503,7 +1748,13 @@ func (t *Table) fnDBMOptionsSQLBuildQueries(mainGen *codegen.Go, g *Generator) {
 		`: dbmo.InitInsertFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Insert()),`)
 	mainGen.Pln(t.hasFeature(g, FeatureDBUpsert|FeatureEntityStruct|FeatureCollectionStruct),
 		codegen.SkipWS(`"`, t.EntityName(), `UpsertByPK"`),
-		`: dbmo.InitInsertFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Insert()).OnDuplicateKey(),`)
+		`: dbmo.InitInsertFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Insert())`, t.upsertQueryOptions(), `,`)
+	if t.grpcService {
+		pkField := tblPK.First().Field
+		mainGen.Pln(codegen.SkipWS(`"`, t.EntityName(), `SelectKeyset"`),
+			`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")).Where(`,
+			"\ndml.Column(`"+pkField+"`).Greater().PlaceHolder(),\n", `).OrderBy(`, strconv.Quote(pkField), `),`)
+	}
 
 	// foreign keys
 	if g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityRelationships) && len(t.availableRelationships) > 0 {
@@ -1520,6 +1771,11 @@ func (t *Table) fnDBMOptionsSQLBuildQueries(mainGen *codegen.Go, g *Generator) {
 			mainGen.Pln(codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectByFK"`),
 				`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(rs.tableName), `).Select("*").Where(`, fkWhereEQ.String(), `)),`)
 
+			// SELECT FROM for LoadRelations of a collection
+			mainGen.Pln(!rs.isManyToMany, codegen.SkipWS(`"`, t.EntityName(), rs.mappedStructFieldName, `SelectByFKs"`),
+				`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(rs.tableName), `).Select("*").Where(`,
+				"\ndml.Column(`"+rs.columnName+"`).In().PlaceHolder(),\n", `)),`)
+
 			// UPDATE not needed as it uses the default UPDATE
 
 			fkWhereEQ.Reset()
@@ -1562,11 +1818,24 @@ func (t *Table) generateTestOther(testGen *codegen.Go, g *Generator) (codeWritte
 		testGen.Pln(`})`) // end t.Run
 		codeWritten++
 	}
+	if g.hasEntityConverters() && g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityProto) {
+		testGen.Pln(`t.Run("` + t.EntityName() + `_Proto", func(t *testing.T) {`)
+		{
+			testGen.Pln(`e:= new(`, t.EntityName(), `)`)
+			testGen.Pln(`assert.NoError(t, ps.FakeData(e))`)
+			testGen.Pln(`x := e.ToProto()`)
+			testGen.Pln(`e2 := new(`, t.EntityName(), `).FromProto(x)`)
+			testGen.Pln(`assert.True(t, proto.Equal(x, e2.ToProto()), "round trip via FromProto must not change the data")`)
+		}
+		testGen.Pln(`})`) // end t.Run
+		codeWritten++
+	}
+	codeWritten += t.generateTestEnums(testGen, g)
 	// more feature tests to follow
 	return
 }
 
-func (t *Table) generateTestDB(testGen *codegen.Go) {
+func (t *Table) generateTestDB(testGen *codegen.Go, g *Generator) {
 	testGen.Pln(`t.Run("` + strs.ToGoCamelCase(t.Table.Name) + `_Entity", func(t *testing.T) {`)
 	testGen.Pln(`tbl := tbls.MustTable(TableName`+strs.ToGoCamelCase(t.Table.Name), `)`)
 
@@ -1638,7 +1907,75 @@ func (t *Table) generateTestDB(testGen *codegen.Go) {
 		testGen.Pln(`colInsertDBR := tbls.ConnPool.WithQueryBuilder(tbl.Insert().Replace().SetRowCount(len(entCol.Data)).BuildValues())`)
 		testGen.Pln(`lID := dmltest.CheckLastInsertID(t, "Error: `, t.CollectionName(), `")(colInsertDBR.ExecContext(ctx, dml.Qualify("", entCol)))`)
 		testGen.Pln(`t.Logf("Last insert ID into: %d", lID)`)
+		t.generateTestDBUpsert(testGen, g)
 	}
 
 	testGen.Pln(`})`)
 }
+
+// generateTestDBUpsert writes a test which inserts a row, changes all its
+// columns and upserts it. The row must be updated in place.
+func (t *Table) generateTestDBUpsert(testGen *codegen.Go, g *Generator) {
+	if !t.hasFeature(g, FeatureDBInsert|FeatureDBUpsert|FeatureEntityStruct) || t.Table.IsView() {
+		return
+	}
+	var pkField string
+	for _, c := range t.Table.Columns {
+		if c.IsPK() && c.IsAutoIncrement() {
+			pkField = t.GoCamelMaybePrivate(c.Field)
+		}
+	}
+	if pkField == "" {
+		return
+	}
+	excluded := make(map[string]bool, len(t.upsertExclude))
+	for _, cn := range t.upsertExclude {
+		excluded[cn] = true
+	}
+
+	testGen.Pln(`t.Run("Upsert", func(t *testing.T) {`)
+	{
+		testGen.In()
+		testGen.Pln(`entIn := new(`, t.EntityName(), `)`)
+		testGen.Pln(`assert.NoError(t, ps.FakeData(entIn))`)
+		testGen.Pln(`_, err := entIn.Insert(ctx, tbls)`)
+		testGen.Pln(`assert.NoError(t, err)`)
+		testGen.Pln(`assert.NotEmpty(t, entIn.`, pkField, `)`)
+		if len(excluded) > 0 {
+			// excluded columns get compared with the stored row, which also
+			// contains the values set by the database, like timestamps.
+			testGen.Pln(`entBefore := new(`, t.EntityName(), `)`)
+			testGen.Pln(`_, err = selOneRowDBR.Load(ctx, entBefore, entIn.`, pkField, `)`)
+			testGen.Pln(`assert.NoError(t, err)`)
+		}
+
+		testGen.Pln(`entUp := new(`, t.EntityName(), `)`)
+		testGen.Pln(`assert.NoError(t, ps.FakeData(entUp))`)
+		testGen.Pln(`entUp.`, pkField, ` = entIn.`, pkField)
+		testGen.Pln(`_, err = entUp.Upsert(ctx, tbls)`)
+		testGen.Pln(`assert.NoError(t, err)`)
+		testGen.Pln(`assert.Exactly(t, entIn.`, pkField, `, entUp.`, pkField, `, "Upsert must not create a new row")`)
+
+		testGen.Pln(`entOut := new(`, t.EntityName(), `)`)
+		testGen.Pln(`rowCount, err := selOneRowDBR.Load(ctx, entOut, entIn.`, pkField, `)`)
+		testGen.Pln(`assert.NoError(t, err)`)
+		testGen.Pln(`assert.Exactly(t, uint64(1), rowCount, "RowCount did not match")`)
+		for _, c := range t.Table.Columns {
+			fn := t.GoCamelMaybePrivate(c.Field)
+			switch {
+			case c.IsAutoIncrement():
+				// already checked
+			case excluded[c.Field]:
+				testGen.Pln(`assert.Exactly(t, entBefore.`, fn, `,`, `entOut.`, fn, `,`, codegen.SkipWS(`"`, fn, ` must not be updated"`), `)`)
+			case c.IsTime(), c.IsSystemVersioned(), c.IsGenerated(), c.IsCurrentTimestamp():
+				// not written by the upsert or can't be compared
+			case c.IsChar():
+				testGen.Pln(`assert.ExactlyLength(t,`, c.CharMaxLength.Int64, `, `, `&entUp.`, fn, `,`, `&entOut.`, fn, `,`, `"`, fn, `should match")`)
+			default:
+				testGen.Pln(`assert.Exactly(t, entUp.`, fn, `,`, `entOut.`, fn, `,`, `"`, fn, `should match")`)
+			}
+		}
+		testGen.Out()
+	}
+	testGen.Pln(`})`)
+}
@@ -48,6 +48,42 @@ type TableConfig struct {
 	// accidentally leaking through encoders. Appropriate getter/setter methods
 	// get generated.
 	PrivateFields []string
+	// JSONOmitEmpty lists the columns which are getting omitted by the
+	// generated JSON methods of FeatureEntityFastJSON when their value is
+	// empty. For null types empty means not valid and for time.Time the zero
+	// time. Native Go types also honor the omitempty option of the json struct
+	// tag, like encoding/json.
+	JSONOmitEmpty []string
+	// UpsertExcludeColumns lists the columns which must not be updated by the
+	// ON DUPLICATE KEY UPDATE clause of the generated Upsert and DBUpsert
+	// functions, for example created_at. Auto increment columns are always
+	// excluded. Columns of the default table config are only applied if the
+	// table has them.
+	UpsertExcludeColumns []string
+	// SoftDeleteColumn sets a nullable datetime or timestamp column, like
+	// deleted_at, as soft delete marker. The generated Load and DBLoad
+	// functions skip rows where the column is not NULL unless the context has
+	// been created with the generated function WithDeleted. Delete and DBDelete
+	// set the column to the current time, either client side or via NOW() if
+	// dml.QueryOptions.SkipTimestamps is enabled. ForceDelete and
+	// DBForceDelete remove the rows.
+	SoftDeleteColumn string
+	// OptimisticLockColumn sets a not nullable integer, datetime or timestamp
+	// column as version column for optimistic locking. Tables with a column
+	// named `version` use it by default, "-" disables it. The generated Update
+	// and DBUpdate functions compare the version and return a
+	// dml.ErrStaleObject if the row has been modified concurrently. Integer
+	// versions get incremented, also in the entity. Timestamp versions, like
+	// DATETIME(6), get set to CURRENT_TIMESTAMP(6) and the entity must be
+	// reloaded.
+	OptimisticLockColumn string
+	// EnumColumns generates for a not nullable string column a Go string type
+	// with constants for the allowed values, an IsValid method and a FilterBy
+	// function for the collection. The key is the column name and the value
+	// the list of allowed values. An empty list reads the values of an ENUM
+	// column from its definition. The struct field keeps its type string.
+	//		EnumColumns: map[string][]string{"scope": {"default", "websites", "stores"}}
+	EnumColumns map[string][]string
 	// FeaturesInclude if set includes only those features, otherwise
 	// everything. Some features can only be included on Default level and not
 	// on a per table level.
@@ -60,7 +96,22 @@ type TableConfig struct {
 	// table to a new name. dbIdentifier is in most cases the column name and in
 	// cases of foreign keys, it is the table name.
 	FieldMapFn func(dbIdentifier string) (newName string)
-	lastErr    error
+	// RelationNames overrides the struct field names of relations. The key is
+	// the default field name, which is the Go type name of the related entity
+	// or collection, like StoreWebsite or Stores, and the value the new name.
+	// Takes precedence over FieldMapFn.
+	RelationNames map[string]string
+	// GRPCService generates a gRPC service with Get, List, Create, Update and
+	// Delete RPCs for the table and its server implementation. Must be enabled
+	// per table and requires WithProtobuf with enabled EntityConverters and a
+	// table with exactly one primary key column.
+	GRPCService bool
+	// GraphQL adds the table to the GraphQL schema written by GenerateGraphQL
+	// and generates the resolvers for fetching a row by its primary key,
+	// listing rows with keyset pagination and the nested relations. Requires a
+	// table with exactly one integer primary key column.
+	GraphQL bool
+	lastErr error
 }
 
 func (to *TableConfig) applyEncoders(t *Table, g *Generator) {
@@ -211,6 +262,25 @@ func (to *TableConfig) applyColumnAliases(t *Table) {
 	}
 }
 
+func (to *TableConfig) applyUpsertExcludeColumns(t *Table, g *Generator) {
+	t.upsertExclude = t.upsertExclude[:0]
+	for i := 0; i < len(to.UpsertExcludeColumns) && to.lastErr == nil; i++ {
+		cn := to.UpsertExcludeColumns[i]
+		if !t.Table.Columns.Contains(cn) {
+			to.lastErr = errors.NotFound.Newf("[dmlgen] WithTableConfig:UpsertExcludeColumns: For table %q the Column %q cannot be found.",
+				t.Table.Name, cn)
+			return
+		}
+		t.upsertExclude = append(t.upsertExclude, cn)
+	}
+	// The default config applies to all tables, so a column might not exist.
+	for _, cn := range g.defaultTableConfig.UpsertExcludeColumns {
+		if t.Table.Columns.Contains(cn) {
+			t.upsertExclude = append(t.upsertExclude, cn)
+		}
+	}
+}
+
 // skips text and blob and varbinary and json and geo
 func (to *TableConfig) applyUniquifiedColumns(t *Table) {
 	for i := 0; i < len(to.UniquifiedColumns) && to.lastErr == nil; i++ {
@@ -1,6 +1,16 @@
 package dmlgen
 
-import "testing"
+import (
+	"bytes"
+	"go/format"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/codegen"
+)
 
 func TestFeatureToggle_String(t *testing.T) {
 	tests := []struct {
@@ -20,3 +30,124 @@ func TestFeatureToggle_String(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerator_UpsertExcludeColumns(t *testing.T) {
+	cols := func() ddl.Columns {
+		return ddl.Columns{
+			&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "sku", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(64)", Key: "UNI"},
+			&ddl.Column{Field: "created_at", Pos: 3, Null: "NO", DataType: "datetime", ColumnType: "datetime"},
+			&ddl.Column{Field: "updated_at", Pos: 4, Null: "NO", DataType: "timestamp", ColumnType: "timestamp", Default: null.MakeString("CURRENT_TIMESTAMP")},
+		}
+	}
+
+	t.Run("excludes auto increment and configured columns", func(t *testing.T) {
+		g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("catalog_product_entity", cols()),
+			WithTableConfigDefault(TableConfig{UpsertExcludeColumns: []string{"not_in_all_tables"}}),
+			WithTableConfig("catalog_product_entity", &TableConfig{UpsertExcludeColumns: []string{"created_at", "sku"}}),
+		)
+		assert.NoError(t, err)
+		var buf, bufTest bytes.Buffer
+		assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+		assert.Contains(t, buf.String(),
+			`.Insert()).AddColumns("entity_id").OnDuplicateKey().AddOnDuplicateKeyExclude("entity_id", "created_at", "sku"),`)
+		assert.Contains(t, buf.String(), `func (e *CatalogProductEntity) Upsert(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
+		assert.Contains(t, bufTest.String(), `_, err = selOneRowDBR.Load(ctx, entBefore, entIn.EntityID)`)
+		assert.Contains(t, bufTest.String(), `assert.Exactly(t, entBefore.CreatedAt, entOut.CreatedAt, "CreatedAt must not be updated")`)
+		assert.Contains(t, bufTest.String(), `assert.Exactly(t, entBefore.Sku, entOut.Sku, "Sku must not be updated")`)
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		_, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+			WithTable("catalog_product_entity", cols()),
+			WithTableConfig("catalog_product_entity", &TableConfig{UpsertExcludeColumns: []string{"not_found"}}),
+		)
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+}
+
+func TestTable_getFieldMapFn_RelationNames(t *testing.T) {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+		WithTable("store", ddl.Columns{
+			&ddl.Column{Field: "store_id", Pos: 1, Null: "NO", DataType: "smallint", ColumnType: "smallint(5) unsigned", Key: "PRI", Extra: "auto_increment"},
+		}),
+		WithTableConfig("store", &TableConfig{
+			RelationNames: map[string]string{"StoreWebsite": "Website"},
+		}),
+	)
+	assert.NoError(t, err)
+	fn := g.Tables["store"].getFieldMapFn(g)
+	assert.Exactly(t, "Website", fn("StoreWebsite"))
+	assert.Exactly(t, "StoreGroups", fn("StoreGroups"))
+}
+
+func TestTable_fnCollectionLoadRelations_CompositePK(t *testing.T) {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+		WithTable("customer_grid", ddl.Columns{
+			&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI"},
+			&ddl.Column{Field: "store_id", Pos: 2, Null: "NO", DataType: "smallint", ColumnType: "smallint(5) unsigned", Key: "PRI"},
+			&ddl.Column{Field: "name", Pos: 3, Null: "YES", DataType: "varchar", ColumnType: "varchar(255)"},
+		}),
+		WithTable("customer_address_entity", ddl.Columns{
+			&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "parent_id", Pos: 2, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "MUL"},
+		}),
+	)
+	assert.NoError(t, err)
+	tbl := g.Tables["customer_grid"]
+	tbl.availableRelationships = []relationShipInfo{
+		{
+			isCollection: true, tableName: "customer_address_entity", structName: "CustomerAddressEntities",
+			mappedStructFieldName: "CustomerAddressEntities", columnName: "parent_id", parentColumnName: "entity_id",
+		},
+		{ // parent column is not part of the primary key
+			isCollection: true, tableName: "customer_address_entity", structName: "CustomerAddressEntities",
+			mappedStructFieldName: "AddressesByName", columnName: "parent_id", parentColumnName: "name",
+		},
+	}
+
+	gen := codegen.NewGo("dmltestgenerated")
+	tbl.fnCollectionLoadRelations(gen, g)
+	src, err := format.Source(append([]byte("package dmltestgenerated\n"), gen.Bytes()...))
+	assert.NoError(t, err, "%s", gen.String())
+	have := string(src)
+	assert.Contains(t, have, "names: CustomerAddressEntities\n")
+	assert.NotContains(t, have, "AddressesByName")
+	assert.Contains(t, have, "parents := make(map[uint32][]*CustomerGrid, len(cc.Data))")
+	assert.Contains(t, have, "ids = append(ids, e.EntityID)")
+	assert.Contains(t, have, `WithCacheKey("CustomerGridCustomerAddressEntitiesSelectByFKs").Load(ctx, children, ids)`)
+	assert.Contains(t, have, "for _, e := range parents[c.ParentID] {\n"+
+		"\t\t\t\te.Relations.CustomerAddressEntities.Data = append(e.Relations.CustomerAddressEntities.Data, c)")
+}
+
+func TestGenerator_CollectionHelpers(t *testing.T) {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgenerated",
+		WithTable("core_config", ddl.Columns{
+			&ddl.Column{Field: "config_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "scope", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(8)", Key: "MUL"},
+			&ddl.Column{Field: "scope_id", Pos: 3, Null: "YES", DataType: "int", ColumnType: "int(11)", Key: "MUL"},
+			&ddl.Column{Field: "updated_at", Pos: 4, Null: "NO", DataType: "timestamp", ColumnType: "timestamp", Key: "MUL"},
+			&ddl.Column{Field: "value", Pos: 5, Null: "YES", DataType: "text", ColumnType: "text"},
+		}),
+		WithTableConfig("core_config", &TableConfig{
+			FeaturesInclude: FeatureEntityStruct | FeatureCollectionStruct | FeatureCollectionFirstLast |
+				FeatureCollectionSort | FeatureCollectionSplit,
+		}),
+	)
+	assert.NoError(t, err)
+	var buf, bufTest bytes.Buffer
+	assert.NoError(t, g.GenerateGo(&buf, &bufTest))
+	have := buf.String()
+
+	assert.Contains(t, have, "func (cc *CoreConfigs) First() *CoreConfig {")
+	assert.Contains(t, have, "func (cc *CoreConfigs) Last() *CoreConfig {")
+	assert.Contains(t, have, "func (cc *CoreConfigs) SortByConfigID(asc bool) *CoreConfigs {")
+	assert.Contains(t, have, "return cc.Data[i].ScopeID.Int32 < cc.Data[j].ScopeID.Int32")
+	assert.Contains(t, have, "return cc.Data[i].UpdatedAt.Before(cc.Data[j].UpdatedAt)")
+	assert.NotContains(t, have, "SortByValue")
+	assert.Contains(t, have, "func (cc *CoreConfigs) SplitByScope() map[string]*CoreConfigs {")
+	assert.Contains(t, have, "func (cc *CoreConfigs) SplitByScopeID() map[null.Int32]*CoreConfigs {")
+	assert.NotContains(t, have, "SplitByConfigID")
+	assert.NotContains(t, have, "SplitByUpdatedAt")
+}
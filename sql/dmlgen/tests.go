@@ -22,6 +22,8 @@ func (g *Generator) fnTestMainOther(testGen *codegen.Go, tbls tables) {
 		testGen.Pln(`_ = ps`)
 		for _, t := range tbls {
 			codeWritten += t.generateTestOther(testGen, g)
+			codeWritten += t.generateTestGRPC(testGen, g)
+			codeWritten += t.generateTestFastJSON(testGen, g)
 		}
 	}
 	testGen.Pln(`}`) // end TestNewDBManager
@@ -76,7 +78,7 @@ func (g *Generator) fnTestMainDB(testGen *codegen.Go, tbls tables) {
 		testGen.Pln(`)`)
 
 		for _, t := range tbls {
-			t.generateTestDB(testGen)
+			t.generateTestDB(testGen, g)
 		} // end for tables
 	}
 	testGen.C(`Uncomment the next line for debugging to see all the queries.`)
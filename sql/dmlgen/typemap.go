@@ -38,6 +38,10 @@ type TypeDef struct {
 	SerializerUNotNull string
 	SerializerNull     string
 	SerializerNotNull  string
+
+	// importPath gets set for custom types defined via WithColumnType or
+	// WithMySQLTypeRule. Custom types implement sql.Scanner and driver.Valuer.
+	importPath string
 }
 
 var typeMap = map[string]map[string]*TypeDef{ // immutable
@@ -169,9 +173,9 @@ var typeMap = map[string]map[string]*TypeDef{ // immutable
 	},
 	"float64": {
 		"default": &TypeDef{
-			GoUNull:    "null.Uint8",
+			GoUNull:    "null.Float64",
 			GoUNotNull: "float64",
-			GoNull:     "null.Int8",
+			GoNull:     "null.Float64",
 			GoNotNull:  "float64",
 		},
 		"protobuf": {
@@ -396,6 +400,9 @@ func mustGetTypeDef(mysqlDataType, serializer string) *TypeDef {
 }
 
 func (g *Generator) findType(c *ddl.Column) *TypeDef {
+	if td := g.customTypeDef(c); td != nil {
+		return td
+	}
 	goType := mustGetTypeDef(c.DataType, g.Serializer)
 
 	// The switch block overwrites the already retrieved goType by checking for
@@ -452,6 +459,9 @@ func (g *Generator) toGoPrimitiveFromNull(c *ddl.Column) string {
 }
 
 func (g *Generator) mySQLToGoDmlColumnMap(c *ddl.Column, withNull bool) string {
+	if g.isCustomType(c) {
+		return "ScannerValuer"
+	}
 	gt := g.mySQLToGoType(c, withNull)
 	if gt == "[]byte" {
 		return "Byte"
@@ -66,6 +66,7 @@ func TestToGoTypeNull(t *testing.T) {
 		{&ddl.Column{Field: `weight_252`, DataType: `decimal`, Null: "YES", Default: null.MakeString(`0.0000`)}, "null.Decimal"},
 
 		{&ddl.Column{Field: `weight_263`, DataType: `double`, Default: null.MakeString(`0.0000`)}, "float64"},
+		{&ddl.Column{Field: `weight_264`, DataType: `double`, ColumnType: `double unsigned`, Null: "YES"}, "null.Float64"},
 		{&ddl.Column{Field: `created_at_674`, DataType: `date`, Default: null.MakeString(`0000-00-00`)}, "time.Time"},
 		{&ddl.Column{Field: `created_at_774`, DataType: `date`, Null: "YES", Default: null.MakeString(`0000-00-00`)}, "null.Time"},
 		{&ddl.Column{Field: `created_at_874`, DataType: `datetime`, Null: "NO", Default: null.MakeString(`0000-00-00`)}, "time.Time"},
@@ -77,12 +77,13 @@ func Close(t testing.TB, c io.Closer) {
 	}
 }
 
-// MockDB creates a mocked database connection. Fatals on error.
+// MockDB creates a mocked database connection. Fatals on error. The mock
+// supports additionally ExpectInsertRecord.
 func MockDB(t testing.TB, opts ...dml.ConnPoolOption) (*dml.ConnPool, sqlmock.Sqlmock) {
 	if t != nil { // t can be nil in Example functions
 		t.Helper()
 	}
-	db, sm, err := sqlmock.New()
+	db, sm, err := newMockDB()
 	FatalIfError(t, err)
 	cfg := []dml.ConnPoolOption{dml.WithDB(db)}
 	dbc, err := dml.NewConnPool(append(cfg, opts...)...)
@@ -96,7 +97,7 @@ func MockDBCallBack(t testing.TB, mockCB func(sqlmock.Sqlmock), opts ...dml.Conn
 	if t != nil { // t can be nil in Example functions
 		t.Helper()
 	}
-	db, sm, err := sqlmock.New()
+	db, sm, err := newMockDB()
 	FatalIfError(t, err)
 	if mockCB != nil {
 		mockCB(sm)
@@ -107,15 +108,46 @@ func MockDBCallBack(t testing.TB, mockCB func(sqlmock.Sqlmock), opts ...dml.Conn
 	return dbc, sm
 }
 
-// MockClose for usage in conjunction with defer.
+// MockDBWithReplicas same as MockDB but adds the number of replicas as mocked
+// read replicas to the connection pool. Read queries must be expected on the
+// replica mocks, see dml.NewConnPoolWithReplicas.
+func MockDBWithReplicas(t testing.TB, replicas int, opts ...dml.ConnPoolOption) (*dml.ConnPool, sqlmock.Sqlmock, []sqlmock.Sqlmock) {
+	if t != nil { // t can be nil in Example functions
+		t.Helper()
+	}
+	db, sm, err := newMockDB()
+	FatalIfError(t, err)
+	cfg := []dml.ConnPoolOption{dml.WithDB(db)}
+	replicaMocks := make([]sqlmock.Sqlmock, replicas)
+	for i := range replicaMocks {
+		var rdb *sql.DB
+		rdb, replicaMocks[i], err = newMockDB()
+		FatalIfError(t, err)
+		cfg = append(cfg, dml.WithReplicaDB(rdb))
+	}
+	dbc, err := dml.NewConnPool(append(cfg, opts...)...)
+	FatalIfError(t, err)
+	return dbc, sm, replicaMocks
+}
+
+// MockClose for usage in conjunction with defer. The optional replica mocks
+// get created by MockDBWithReplicas.
 // 		defer dmltest.MockClose(t, db, dbMock)
-func MockClose(t testing.TB, c io.Closer, m sqlmock.Sqlmock) {
+func MockClose(t testing.TB, c io.Closer, m sqlmock.Sqlmock, replicas ...sqlmock.Sqlmock) {
 	if t != nil { // t can be nil in Example functions
 		t.Helper()
 	}
+	defer unregisterRecordMock(m)
+	for _, rm := range replicas {
+		defer unregisterRecordMock(rm)
+		rm.ExpectClose()
+	}
 	m.ExpectClose()
 	FatalIfError(t, c.Close())
 	FatalIfError(t, m.ExpectationsWereMet())
+	for _, rm := range replicas {
+		FatalIfError(t, rm.ExpectationsWereMet())
+	}
 }
 
 // FatalIfError fails the tests if an unexpected error occurred. If the error is
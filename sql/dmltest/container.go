@@ -0,0 +1,352 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/fatih/color"
+)
+
+// EnvDockerHost is the name of the environment variable which contains the
+// address of the Docker daemon, e.g. unix:///var/run/docker.sock or
+// tcp://127.0.0.1:2375.
+const EnvDockerHost = "DOCKER_HOST"
+
+const (
+	defaultDockerHost    = "unix:///var/run/docker.sock"
+	containerPort        = "3306/tcp"
+	containerDatabase    = "dmltest"
+	containerLabel       = "io.corestore.dmltest"
+	defaultStartTimeout  = 2 * time.Minute
+	containerPingRetry   = 500 * time.Millisecond
+	dockerRequestTimeout = 30 * time.Second
+)
+
+var containerID uint64
+
+// ContainerOptions configures StartContainer.
+type ContainerOptions struct {
+	// Image defaults to mariadb. The official mysql image works, too.
+	Image string
+	// Version defaults to 10.5.
+	Version string
+	// InitSQL contains optional SQL statements, separated by semicolons, which
+	// get executed after the database has become ready.
+	InitSQL string
+	// Env contains additional environment variables for the container, e.g.
+	// MYSQL_INITDB_SKIP_TZINFO=1.
+	Env []string
+	// StartTimeout defaults to two minutes and includes the pulling of the
+	// image.
+	StartTimeout time.Duration
+	// ConnPoolOptions get applied to the returned connection pool.
+	ConnPoolOptions []dml.ConnPoolOption
+}
+
+func (co ContainerOptions) imageRef() string {
+	image, version := co.Image, co.Version
+	if image == "" {
+		image = "mariadb"
+	}
+	if version == "" {
+		version = "10.5"
+	}
+	return image + ":" + version
+}
+
+// StartContainer starts a new MySQL or MariaDB container via the API of the
+// local Docker daemon, waits until the database accepts connections, executes
+// the optional InitSQL and returns a connection pool to the database
+// "dmltest". The container publishes its port on a random port of 127.0.0.1,
+// hence parallel tests and test packages each get their own independent
+// container. The container gets removed after the test, unless the
+// environment variable SKIP_CLEANUP=1 has been set.
+//
+// If the Docker daemon is not reachable, StartContainer falls back to
+// MustConnectDB and the DSN of the environment variable CS_DSN. If CS_DSN is
+// empty, too, the test gets skipped. Use the database name "random" in the
+// DSN to avoid clashes between parallel test packages.
+//
+//	db := dmltest.StartContainer(t, dmltest.ContainerOptions{
+//		InitSQL: "CREATE TABLE a (id INT); INSERT INTO a VALUES (1);",
+//	})
+func StartContainer(t testing.TB, co ContainerOptions) *dml.ConnPool {
+	t.Helper()
+	dc, err := newDockerClient(os.Getenv(EnvDockerHost))
+	if err == nil {
+		err = dc.ping(context.Background())
+	}
+	if err != nil {
+		if _, errDSN := getDSN(EnvDSN); errDSN != nil {
+			t.Skip(color.MagentaString("[dmltest] Docker is not available (%s) and %s", err, errDSN))
+		}
+		return connectWithInitSQL(t, co, MustConnectDB(t, co.ConnPoolOptions...))
+	}
+
+	timeout := co.StartTimeout
+	if timeout <= 0 {
+		timeout = defaultStartTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	password := fmt.Sprintf("dmltest%d", time.Now().UnixNano())
+	id, hostPort, err := dc.startContainer(ctx, co, password)
+	if id != "" {
+		t.Cleanup(func() {
+			if os.Getenv("SKIP_CLEANUP") == "1" {
+				t.Logf("[dmltest] Skipping removal of container %s", id)
+				return
+			}
+			rmCtx, rmCancel := context.WithTimeout(context.Background(), dockerRequestTimeout)
+			defer rmCancel()
+			if err := dc.removeContainer(rmCtx, id); err != nil {
+				t.Errorf("[dmltest] Removing container %s failed: %+v", id, err)
+			}
+		})
+	}
+	FatalIfError(t, err)
+
+	dsn := fmt.Sprintf("root:%s@tcp(127.0.0.1:%s)/%s?parseTime=true&loc=UTC", password, hostPort, containerDatabase)
+	db, err := dml.NewConnPool(append([]dml.ConnPoolOption{
+		dml.WithDSN(dsn),
+		dml.WithVerifyConnection(ctx, containerPingRetry),
+	}, co.ConnPoolOptions...)...)
+	if err != nil {
+		t.Fatalf("[dmltest] Container %s with image %q did not become ready within %s: %+v", id, co.imageRef(), timeout, err)
+	}
+	return connectWithInitSQL(t, co, db)
+}
+
+// connectWithInitSQL executes the InitSQL on db and closes db after the test.
+func connectWithInitSQL(t testing.TB, co ContainerOptions, db *dml.ConnPool) *dml.ConnPool {
+	t.Helper()
+	t.Cleanup(func() { Close(t, db) })
+	for _, stmt := range splitSQLStatements(co.InitSQL) {
+		if _, err := db.DB.ExecContext(context.Background(), stmt.SQL); err != nil {
+			t.Fatalf("[dmltest] InitSQL statement in line %d failed: %+v", stmt.Line, err)
+		}
+	}
+	return db
+}
+
+// dockerClient talks to the HTTP API of the Docker daemon.
+type dockerClient struct {
+	client  *http.Client
+	baseURL string
+}
+
+// newDockerClient creates a client for a unix socket or a TCP address. An
+// empty host defaults to the unix socket /var/run/docker.sock.
+func newDockerClient(host string) (*dockerClient, error) {
+	if host == "" {
+		host = defaultDockerHost
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[dmltest] Invalid Docker host %q", host)
+	}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		return &dockerClient{
+			client: &http.Client{
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						var d net.Dialer
+						return d.DialContext(ctx, "unix", socket)
+					},
+				},
+			},
+			baseURL: "http://docker",
+		}, nil
+	case "tcp", "http":
+		return &dockerClient{
+			client:  &http.Client{},
+			baseURL: "http://" + u.Host,
+		}, nil
+	}
+	return nil, errors.NotSupported.Newf("[dmltest] Docker host %q: scheme %q not supported", host, u.Scheme)
+}
+
+// do sends the request and decodes the JSON response into out, if not nil. If
+// out is an io.Writer, the response gets copied into it.
+func (dc *dockerClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, dc.baseURL+path, body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := dc.client.Do(req)
+	if err != nil {
+		return errors.ConnectionFailed.New(err, "[dmltest] Docker request %s %s failed", method, path)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var msg struct{ Message string }
+		data, _ := ioutil.ReadAll(resp.Body)
+		if json.Unmarshal(data, &msg) != nil || msg.Message == "" {
+			msg.Message = strings.TrimSpace(string(data))
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return errors.NotFound.Newf("[dmltest] Docker request %s %s: %s", method, path, msg.Message)
+		}
+		return errors.Fatal.Newf("[dmltest] Docker request %s %s failed with status %d: %s", method, path, resp.StatusCode, msg.Message)
+	}
+	switch w := out.(type) {
+	case nil:
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+		return errors.WithStack(err)
+	case io.Writer:
+		_, err = io.Copy(w, resp.Body)
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(json.NewDecoder(resp.Body).Decode(out))
+}
+
+func (dc *dockerClient) ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return dc.do(ctx, http.MethodGet, "/_ping", nil, nil)
+}
+
+// pullImage pulls the image if it does not exist locally.
+func (dc *dockerClient) pullImage(ctx context.Context, image string) error {
+	err := dc.do(ctx, http.MethodGet, "/images/"+image+"/json", nil, nil)
+	if !errors.NotFound.Match(err) {
+		return err
+	}
+	ref := image
+	tag := "latest"
+	if i := strings.LastIndexByte(image, ':'); i > strings.LastIndexByte(image, '/') {
+		ref, tag = image[:i], image[i+1:]
+	}
+	q := url.Values{"fromImage": {ref}, "tag": {tag}}
+	// The progress gets streamed as JSON objects. Errors show up in the stream
+	// while the status code is still 200.
+	var stream bytes.Buffer
+	if err := dc.do(ctx, http.MethodPost, "/images/create?"+q.Encode(), nil, &stream); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(&stream)
+	for {
+		var msg struct{ Error string }
+		if err := dec.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.BadEncoding.New(err, "[dmltest] Pulling image %q: invalid progress stream", image)
+		}
+		if msg.Error != "" {
+			return errors.NotFound.Newf("[dmltest] Pulling image %q failed: %s", image, msg.Error)
+		}
+	}
+}
+
+// startContainer creates and starts the container and returns its ID and the
+// published host port. The ID gets also returned if starting fails, so the
+// caller can remove the container.
+func (dc *dockerClient) startContainer(ctx context.Context, co ContainerOptions, password string) (id, hostPort string, err error) {
+	image := co.imageRef()
+	if err := dc.pullImage(ctx, image); err != nil {
+		return "", "", err
+	}
+
+	name := fmt.Sprintf("dmltest-%d-%d-%d", os.Getpid(), atomic.AddUint64(&containerID, 1), time.Now().UnixNano())
+	cfg := containerConfig{
+		Image: image,
+		Env: append([]string{
+			"MYSQL_ROOT_PASSWORD=" + password,
+			"MARIADB_ROOT_PASSWORD=" + password,
+			"MYSQL_DATABASE=" + containerDatabase,
+			"MARIADB_DATABASE=" + containerDatabase,
+		}, co.Env...),
+		Labels:       map[string]string{containerLabel: "1"},
+		ExposedPorts: map[string]struct{}{containerPort: {}},
+	}
+	cfg.HostConfig.PortBindings = map[string][]portBinding{containerPort: {{HostIP: "127.0.0.1"}}}
+
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := dc.do(ctx, http.MethodPost, "/containers/create?name="+url.QueryEscape(name), cfg, &created); err != nil {
+		return "", "", err
+	}
+	id = created.ID
+	if err := dc.do(ctx, http.MethodPost, "/containers/"+id+"/start", nil, nil); err != nil {
+		return id, "", err
+	}
+
+	var inspect struct {
+		NetworkSettings struct {
+			Ports map[string][]portBinding
+		}
+	}
+	if err := dc.do(ctx, http.MethodGet, "/containers/"+id+"/json", nil, &inspect); err != nil {
+		return id, "", err
+	}
+	for _, pb := range inspect.NetworkSettings.Ports[containerPort] {
+		if pb.HostPort != "" {
+			return id, pb.HostPort, nil
+		}
+	}
+	return id, "", errors.NotFound.Newf("[dmltest] Container %s has no published port for %s", id, containerPort)
+}
+
+func (dc *dockerClient) removeContainer(ctx context.Context, id string) error {
+	return dc.do(ctx, http.MethodDelete, "/containers/"+id+"?force=true&v=true", nil, nil)
+}
+
+type portBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string
+}
+
+type containerConfig struct {
+	Image        string
+	Env          []string
+	Labels       map[string]string
+	ExposedPorts map[string]struct{}
+	HostConfig   struct {
+		PortBindings map[string][]portBinding
+	}
+}
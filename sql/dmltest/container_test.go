@@ -0,0 +1,165 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// fakeDocker implements the parts of the Docker API used by StartContainer.
+type fakeDocker struct {
+	mu         sync.Mutex
+	calls      []string
+	imageFound bool
+	pullError  string
+	created    containerConfig
+}
+
+func (fd *fakeDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	fd.calls = append(fd.calls, r.Method+" "+r.URL.Path)
+	switch {
+	case r.URL.Path == "/_ping":
+		fmt.Fprint(w, "OK")
+	case strings.HasPrefix(r.URL.Path, "/images/") && r.Method == http.MethodGet:
+		if !fd.imageFound {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"No such image"}`)
+		}
+	case r.URL.Path == "/images/create":
+		fmt.Fprintf(w, "{\"status\":\"Pulling from library/%s\"}\n", r.URL.Query().Get("fromImage"))
+		if fd.pullError != "" {
+			fmt.Fprintf(w, "{\"error\":%q}\n", fd.pullError)
+		}
+	case r.URL.Path == "/containers/create":
+		if err := json.NewDecoder(r.Body).Decode(&fd.created); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"Id":"c0ffee"}`)
+	case r.URL.Path == "/containers/c0ffee/start":
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Path == "/containers/c0ffee/json":
+		fmt.Fprint(w, `{"NetworkSettings":{"Ports":{"3306/tcp":[{"HostIp":"127.0.0.1","HostPort":"49153"}]}}}`)
+	case r.URL.Path == "/containers/c0ffee" && r.Method == http.MethodDelete:
+		if r.URL.Query().Get("force") != "true" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"message":"unexpected request"}`)
+	}
+}
+
+func TestDockerClient(t *testing.T) {
+	t.Run("start and remove", func(t *testing.T) {
+		fd := &fakeDocker{}
+		srv := httptest.NewServer(fd)
+		defer srv.Close()
+
+		dc, err := newDockerClient("tcp://" + srv.Listener.Addr().String())
+		assert.NoError(t, err)
+		assert.NoError(t, dc.ping(context.Background()))
+
+		id, port, err := dc.startContainer(context.Background(), ContainerOptions{
+			Image:   "mysql",
+			Version: "8.0",
+			Env:     []string{"TZ=UTC"},
+		}, "secret")
+		assert.NoError(t, err)
+		assert.Exactly(t, "c0ffee", id)
+		assert.Exactly(t, "49153", port)
+		assert.NoError(t, dc.removeContainer(context.Background(), id))
+
+		assert.Exactly(t, []string{
+			"GET /_ping",
+			"GET /images/mysql:8.0/json",
+			"POST /images/create",
+			"POST /containers/create",
+			"POST /containers/c0ffee/start",
+			"GET /containers/c0ffee/json",
+			"DELETE /containers/c0ffee",
+		}, fd.calls)
+		assert.Exactly(t, "mysql:8.0", fd.created.Image)
+		assert.Contains(t, fd.created.Env, "MYSQL_ROOT_PASSWORD=secret")
+		assert.Contains(t, fd.created.Env, "TZ=UTC")
+		assert.Exactly(t, []portBinding{{HostIP: "127.0.0.1"}}, fd.created.HostConfig.PortBindings[containerPort])
+	})
+
+	t.Run("image exists", func(t *testing.T) {
+		fd := &fakeDocker{imageFound: true}
+		srv := httptest.NewServer(fd)
+		defer srv.Close()
+
+		dc, err := newDockerClient("tcp://" + srv.Listener.Addr().String())
+		assert.NoError(t, err)
+		_, _, err = dc.startContainer(context.Background(), ContainerOptions{}, "secret")
+		assert.NoError(t, err)
+		assert.Exactly(t, "GET /images/mariadb:10.5/json", fd.calls[0])
+		assert.Exactly(t, "POST /containers/create", fd.calls[1])
+	})
+
+	t.Run("pull error in stream", func(t *testing.T) {
+		fd := &fakeDocker{pullError: "manifest unknown"}
+		srv := httptest.NewServer(fd)
+		defer srv.Close()
+
+		dc, err := newDockerClient("tcp://" + srv.Listener.Addr().String())
+		assert.NoError(t, err)
+		id, _, err := dc.startContainer(context.Background(), ContainerOptions{Version: "0.1"}, "secret")
+		assert.Empty(t, id)
+		assert.True(t, errors.NotFound.Match(err), "%+v", err)
+		assert.Contains(t, err.Error(), "manifest unknown")
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		_, err := newDockerClient("npipe:////./pipe/docker_engine")
+		assert.True(t, errors.NotSupported.Match(err), "%+v", err)
+	})
+}
+
+func TestStartContainer_Skip(t *testing.T) {
+	if os.Getenv(EnvDSN) != "" {
+		t.Skipf("%s is set", EnvDSN)
+	}
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close() // Docker is not reachable
+
+	prevHost := os.Getenv(EnvDockerHost)
+	assert.NoError(t, os.Setenv(EnvDockerHost, "tcp://"+srv.Listener.Addr().String()))
+	defer os.Setenv(EnvDockerHost, prevHost)
+
+	var skipped bool
+	t.Run("inner", func(t *testing.T) {
+		defer func() { skipped = t.Skipped() }()
+		StartContainer(t, ContainerOptions{})
+		t.Error("should have been skipped")
+	})
+	assert.True(t, skipped)
+}
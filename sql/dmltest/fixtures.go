@@ -0,0 +1,429 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+)
+
+const (
+	// fixtureBatchSize defines the number of CSV rows per INSERT statement.
+	fixtureBatchSize = 500
+	// fixtureNull marks a NULL value in a CSV file. `\N` gets accepted, too.
+	fixtureNull = "NULL"
+)
+
+// fixtureTimeLayouts get tried in order to parse date, datetime and timestamp
+// columns of a CSV file.
+var fixtureTimeLayouts = [...]string{
+	"2006-01-02 15:04:05.999999999",
+	time.RFC3339Nano,
+	"2006-01-02",
+}
+
+// LoadFixtures loads all *.sql and *.csv files of directory dir in lexical
+// order into the database. SQL files can contain many statements separated by
+// semicolons; DELIMITER commands are not supported. A CSV file must be named
+// after its table, e.g. customer_entity.csv, and its first row contains the
+// column names. The CSV values get converted according to the column types of
+// the table: NULL or \N for NULL, true/false for boolean columns and dates in
+// the format 2006-01-02 15:04:05 or RFC3339. An empty value of a nullable
+// non-text column becomes NULL. The test fails with the file and the line or
+// row of the first error. Use SnapshotTable to create a CSV file.
+//
+//	testdata/fixtures/01_schema.sql
+//	testdata/fixtures/customer_entity.csv
+//	dmltest.LoadFixtures(t, db, "testdata/fixtures")
+func LoadFixtures(t testing.TB, db *dml.ConnPool, dir string) {
+	t.Helper()
+	FatalIfError(t, loadFixtures(context.Background(), db, dir))
+}
+
+func loadFixtures(ctx context.Context, db *dml.ConnPool, dir string) error {
+	files, err := ioutil.ReadDir(dir) // sorted by name
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, fi := range files {
+		path := filepath.Join(dir, fi.Name())
+		switch {
+		case fi.IsDir():
+		case strings.HasSuffix(fi.Name(), ".sql"):
+			err = loadSQLFixture(ctx, db, path)
+		case strings.HasSuffix(fi.Name(), ".csv"):
+			err = loadCSVFixture(ctx, db, path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadSQLFixture(ctx context.Context, db *dml.ConnPool, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, stmt := range splitSQLStatements(string(data)) {
+		if _, err := db.DB.ExecContext(ctx, stmt.SQL); err != nil {
+			return errors.Wrapf(err, "[dmltest] LoadFixtures %s:%d", path, stmt.Line)
+		}
+	}
+	return nil
+}
+
+func loadCSVFixture(ctx context.Context, db *dml.ConnPool, path string) error {
+	table := strings.TrimSuffix(filepath.Base(path), ".csv")
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err != nil {
+		return errors.BadEncoding.New(err, "[dmltest] LoadFixtures %s: cannot read the header", path)
+	}
+	tc, err := loadFixtureColumns(ctx, db, table)
+	if err != nil {
+		return errors.Wrapf(err, "[dmltest] LoadFixtures %s", path)
+	}
+	cols := make([]*fixtureColumn, len(header))
+	for i, h := range header {
+		header[i] = strings.TrimSpace(h)
+		if cols[i] = tc.byField(header[i]); cols[i] == nil {
+			return errors.NotFound.Newf("[dmltest] LoadFixtures %s: column %q does not exist in table %q", path, header[i], table)
+		}
+	}
+
+	var args []interface{}
+	var rows, firstRow int
+	flush := func(row int) error {
+		if rows == 0 {
+			return nil
+		}
+		ins := dml.NewInsert(table).AddColumns(header...).SetRowCount(rows).BuildValues()
+		if _, err := db.WithQueryBuilder(ins).ExecContext(ctx, args...); err != nil {
+			return errors.Wrapf(err, "[dmltest] LoadFixtures %s rows %d-%d", path, firstRow, row)
+		}
+		args, rows = args[:0], 0
+		return nil
+	}
+
+	for row := 1; ; row++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return flush(row - 1)
+		}
+		if err != nil {
+			return errors.BadEncoding.New(err, "[dmltest] LoadFixtures %s row %d", path, row)
+		}
+		if rows == 0 {
+			firstRow = row
+		}
+		for i, s := range rec {
+			v, err := csvFixtureValue(cols[i], s)
+			if err != nil {
+				return errors.NotValid.New(err, "[dmltest] LoadFixtures %s row %d column %q", path, row, cols[i].Field)
+			}
+			args = append(args, v)
+		}
+		if rows++; rows == fixtureBatchSize {
+			if err := flush(row); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// csvFixtureValue converts a value of a CSV file to the Go type of the column.
+func csvFixtureValue(c *fixtureColumn, s string) (interface{}, error) {
+	if s == fixtureNull || s == `\N` || (s == "" && c.isNull() && !c.isText()) {
+		return nil, nil
+	}
+	switch c.DataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "year", "bit":
+		if c.isUnsigned() || c.DataType == "bit" {
+			if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+				return u, nil
+			}
+		} else if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, errors.NotValid.Newf("invalid integer or boolean %q", s)
+		}
+		if b {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case "float", "double":
+		return strconv.ParseFloat(s, 64)
+	case "date", "datetime", "timestamp":
+		if strings.HasPrefix(s, "0000-00-00") {
+			return s, nil
+		}
+		for _, layout := range fixtureTimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return nil, errors.NotValid.Newf("invalid date %q", s)
+	}
+	return s, nil
+}
+
+// SnapshotTable writes all rows of table as CSV to w, ordered by the primary
+// key. The output can be loaded with LoadFixtures, after it has been saved in
+// a file named after the table. Generated columns get skipped.
+//
+//	f, _ := os.Create("testdata/fixtures/customer_entity.csv")
+//	dmltest.SnapshotTable(t, db, "customer_entity", f)
+func SnapshotTable(t testing.TB, db *dml.ConnPool, table string, w io.Writer) {
+	t.Helper()
+	FatalIfError(t, snapshotTable(context.Background(), db, table, w))
+}
+
+func snapshotTable(ctx context.Context, db *dml.ConnPool, table string, w io.Writer) error {
+	tc, err := loadFixtureColumns(ctx, db, table)
+	if err != nil {
+		return errors.Wrapf(err, "[dmltest] SnapshotTable %q", table)
+	}
+	cols := tc.filter(func(c *fixtureColumn) bool { return !c.isGenerated() })
+	sel := dml.NewSelect(cols.fieldNames()...).From(table).OrderBy(cols.filter((*fixtureColumn).isPK).fieldNames()...)
+	rows, err := db.WithQueryBuilder(sel).QueryContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "[dmltest] SnapshotTable %q", table)
+	}
+	defer rows.Close()
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cols.fieldNames()); err != nil {
+		return errors.WithStack(err)
+	}
+	values := make([]interface{}, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	rec := make([]string, len(cols))
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return errors.Wrapf(err, "[dmltest] SnapshotTable %q", table)
+		}
+		for i, v := range values {
+			rec[i] = csvFixtureString(cols[i], v)
+		}
+		if err := cw.Write(rec); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrapf(err, "[dmltest] SnapshotTable %q", table)
+	}
+	cw.Flush()
+	return errors.WithStack(cw.Error())
+}
+
+// csvFixtureString formats a value of the database in the format expected by
+// csvFixtureValue.
+func csvFixtureString(c *fixtureColumn, v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return fixtureNull
+	case []byte:
+		return string(v)
+	case time.Time:
+		if c.DataType == "date" {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(fixtureTimeLayouts[0])
+	}
+	return fmt.Sprint(v)
+}
+
+// selFixtureColumns selects the column attributes required to convert CSV
+// values. The package ddl cannot be used because its tests import dmltest.
+const selFixtureColumns = `SELECT COLUMN_NAME, IS_NULLABLE, DATA_TYPE, CHARACTER_MAXIMUM_LENGTH, COLUMN_TYPE, COLUMN_KEY,
+ IS_GENERATED, GENERATION_EXPRESSION FROM information_schema.COLUMNS WHERE TABLE_SCHEMA=DATABASE() AND TABLE_NAME=?
+ ORDER BY ORDINAL_POSITION`
+
+// fixtureColumn contains the parts of the information_schema.COLUMNS table
+// used by the fixtures.
+type fixtureColumn struct {
+	Field                string
+	Null                 string
+	DataType             string
+	CharMaxLength        sql.NullInt64
+	ColumnType           string
+	Key                  string
+	Generated            sql.NullString
+	GenerationExpression sql.NullString
+}
+
+func (c *fixtureColumn) isNull() bool     { return c.Null == "YES" }
+func (c *fixtureColumn) isPK() bool       { return c.Key == "PRI" }
+func (c *fixtureColumn) isUnsigned() bool { return strings.Contains(c.ColumnType, "unsigned") }
+func (c *fixtureColumn) isGenerated() bool {
+	return c.Generated.String == "ALWAYS" || c.GenerationExpression.Valid
+}
+
+// isText returns true for char, blob, text, binary and json columns.
+func (c *fixtureColumn) isText() bool {
+	return (c.CharMaxLength.Valid && c.CharMaxLength.Int64 > 0) ||
+		strings.Contains(c.DataType, "blob") || strings.Contains(c.DataType, "text") ||
+		strings.Contains(c.DataType, "binary") || strings.Contains(c.DataType, "json")
+}
+
+type fixtureColumns []*fixtureColumn
+
+func loadFixtureColumns(ctx context.Context, db *dml.ConnPool, table string) (_ fixtureColumns, err error) {
+	rows, err := db.DB.QueryContext(ctx, selFixtureColumns, table)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() {
+		if err2 := rows.Close(); err2 != nil && err == nil {
+			err = errors.WithStack(err2)
+		}
+	}()
+	var cols fixtureColumns
+	for rows.Next() {
+		c := new(fixtureColumn)
+		if err := rows.Scan(&c.Field, &c.Null, &c.DataType, &c.CharMaxLength, &c.ColumnType, &c.Key, &c.Generated, &c.GenerationExpression); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		c.DataType = strings.ToLower(c.DataType)
+		cols = append(cols, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(cols) == 0 {
+		return nil, errors.NotFound.Newf("[dmltest] Table %q not found", table)
+	}
+	return cols, nil
+}
+
+func (cs fixtureColumns) byField(field string) *fixtureColumn {
+	for _, c := range cs {
+		if c.Field == field {
+			return c
+		}
+	}
+	return nil
+}
+
+func (cs fixtureColumns) filter(fn func(*fixtureColumn) bool) fixtureColumns {
+	var cols fixtureColumns
+	for _, c := range cs {
+		if fn(c) {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+func (cs fixtureColumns) fieldNames() []string {
+	names := make([]string, len(cs))
+	for i, c := range cs {
+		names[i] = c.Field
+	}
+	return names
+}
+
+// sqlStatement is a statement of a SQL file and the line where it starts.
+type sqlStatement struct {
+	Line int
+	SQL  string
+}
+
+// splitSQLStatements splits sqlStr at semicolons outside of quoted strings,
+// identifiers and comments. Statements consisting only of comments get
+// dropped.
+func splitSQLStatements(sqlStr string) []sqlStatement {
+	var stmts []sqlStatement
+	var quote byte
+	var escaped, lineComment, blockComment bool
+	line, stmtLine, start := 1, 0, 0
+	flush := func(end int) {
+		if stmtLine > 0 {
+			stmts = append(stmts, sqlStatement{Line: stmtLine, SQL: strings.TrimSpace(sqlStr[start:end])})
+		}
+		start, stmtLine = end+1, 0
+	}
+	for i := 0; i < len(sqlStr); i++ {
+		c := sqlStr[i]
+		switch {
+		case lineComment:
+			lineComment = c != '\n'
+		case blockComment:
+			if c == '*' && i+1 < len(sqlStr) && sqlStr[i+1] == '/' {
+				blockComment = false
+				i++
+			}
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\' && quote != '`':
+				escaped = true
+			case c == quote:
+				quote = 0
+			}
+		case c == '#' || (strings.HasPrefix(sqlStr[i:], "--") && (i+2 == len(sqlStr) || isSQLSpace(sqlStr[i+2]))):
+			lineComment = true
+		case strings.HasPrefix(sqlStr[i:], "/*") && !strings.HasPrefix(sqlStr[i:], "/*!"):
+			blockComment = true
+			i++
+		case c == ';':
+			flush(i)
+		case isSQLSpace(c):
+		default:
+			if stmtLine == 0 {
+				stmtLine = line
+			}
+			if c == '\'' || c == '"' || c == '`' {
+				quote = c
+			}
+		}
+		if c == '\n' {
+			line++
+		}
+	}
+	flush(len(sqlStr))
+	return stmts
+}
+
+func isSQLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
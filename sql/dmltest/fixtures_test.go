@@ -0,0 +1,120 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+const fixtureColumnsQuery = "SELECT.+FROM information_schema.COLUMNS WHERE"
+
+func TestLoadFixtures(t *testing.T) {
+	t.Run("sql and csv", func(t *testing.T) {
+		dbc, dbMock := MockDB(t)
+		defer MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectExec(SQLMockQuoteMeta("DROP TABLE IF EXISTS `customer_entity`")).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("(?s)^/\\* The table gets.+CREATE TABLE `customer_entity`.+'E-Mail; unique'.+utf8mb4$").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectQuery(fixtureColumnsQuery).WillReturnRows(MustMockRows(WithFile("testdata", "fixtures_columns.csv")))
+		dbMock.ExpectExec(SQLMockQuoteMeta("INSERT INTO `customer_entity` (`entity_id`,`email`,`is_active`,`dob`,`created_at`) VALUES (?,?,?,?,?),(?,?,?,?,?)")).
+			WithArgs(
+				1, "alice@example.com", 1, time.Date(1980, 2, 3, 0, 0, 0, 0, time.UTC), time.Date(2021, 4, 5, 6, 7, 8, 0, time.UTC),
+				2, nil, 0, nil, time.Date(2021, 4, 5, 6, 7, 8, 5e8, time.UTC),
+			).WillReturnResult(sqlmock.NewResult(0, 2))
+
+		assert.NoError(t, loadFixtures(context.Background(), dbc, filepath.Join("testdata", "fixtures")))
+	})
+
+	t.Run("reports the line of the failing statement", func(t *testing.T) {
+		dbc, dbMock := MockDB(t)
+		defer MockClose(t, dbc, dbMock)
+		dbMock.ExpectExec("DROP TABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("CREATE TABLE").WillReturnError(errors.AlreadyExists.Newf("table exists"))
+
+		err := loadFixtures(context.Background(), dbc, filepath.Join("testdata", "fixtures"))
+		assert.True(t, errors.AlreadyExists.Match(err), "%+v", err)
+		assert.Contains(t, err.Error(), filepath.Join("testdata", "fixtures", "01_schema.sql")+":6")
+	})
+
+	t.Run("reports the row of an invalid value", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "dmltest")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "customer_entity.csv"),
+			[]byte("entity_id,dob\n1,2020-01-01\n2,yesterday\n"), 0644))
+
+		dbc, dbMock := MockDB(t)
+		defer MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery(fixtureColumnsQuery).WillReturnRows(MustMockRows(WithFile("testdata", "fixtures_columns.csv")))
+
+		err = loadFixtures(context.Background(), dbc, dir)
+		assert.True(t, errors.NotValid.Match(err), "%+v", err)
+		assert.Contains(t, err.Error(), "customer_entity.csv row 2 column \"dob\"")
+		assert.Contains(t, err.Error(), "invalid date \"yesterday\"")
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "dmltest")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "customer_entity.csv"), []byte("entity_id,lastname\n"), 0644))
+
+		dbc, dbMock := MockDB(t)
+		defer MockClose(t, dbc, dbMock)
+		dbMock.ExpectQuery(fixtureColumnsQuery).WillReturnRows(MustMockRows(WithFile("testdata", "fixtures_columns.csv")))
+
+		err = loadFixtures(context.Background(), dbc, dir)
+		assert.True(t, errors.NotFound.Match(err), "%+v", err)
+		assert.Contains(t, err.Error(), "column \"lastname\" does not exist")
+	})
+}
+
+func TestSnapshotTable(t *testing.T) {
+	dbc, dbMock := MockDB(t)
+	defer MockClose(t, dbc, dbMock)
+	dbMock.ExpectQuery(fixtureColumnsQuery).WillReturnRows(MustMockRows(WithFile("testdata", "fixtures_columns.csv")))
+	dbMock.ExpectQuery(SQLMockQuoteMeta("SELECT `entity_id`, `email`, `is_active`, `dob`, `created_at` FROM `customer_entity` ORDER BY `entity_id`")).
+		WillReturnRows(sqlmock.NewRows([]string{"entity_id", "email", "is_active", "dob", "created_at"}).
+			AddRow(int64(1), []byte("alice@example.com"), int64(1), time.Date(1980, 2, 3, 0, 0, 0, 0, time.UTC), time.Date(2021, 4, 5, 6, 7, 8, 0, time.UTC)).
+			AddRow(int64(2), nil, int64(0), nil, time.Date(2021, 4, 5, 6, 7, 8, 5e8, time.UTC)))
+
+	var buf bytes.Buffer
+	SnapshotTable(t, dbc, "customer_entity", &buf)
+	assert.Exactly(t, "entity_id,email,is_active,dob,created_at\n"+
+		"1,alice@example.com,1,1980-02-03,2021-04-05 06:07:08\n"+
+		"2,NULL,0,NULL,2021-04-05 06:07:08.5\n", buf.String())
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	assert.Exactly(t, []sqlStatement{
+		{Line: 1, SQL: "CREATE TABLE `a;b` (id INT)"},
+		{Line: 3, SQL: "INSERT INTO `a;b` VALUES (1, 'x;\\';y')"},
+		{Line: 3, SQL: "SELECT \";\" -- a; comment\n/* b; */ FROM dual"},
+		{Line: 6, SQL: "# only; a comment\n/*!40101 SET NAMES utf8 */"},
+		{Line: 7, SQL: "SELECT 1--1\n--"},
+	}, splitSQLStatements(" CREATE TABLE `a;b` (id INT);\n\nINSERT INTO `a;b` VALUES (1, 'x;\\';y');;SELECT \";\" -- a; comment\n/* b; */ FROM dual;\n"+
+		"# only; a comment\n/*!40101 SET NAMES utf8 */;\nSELECT 1--1\n--"))
+	assert.Nil(t, splitSQLStatements(" ; \n-- comment"))
+}
@@ -0,0 +1,250 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// EnvUpdateGolden is the name of the environment variable which enables the
+// rewriting of the golden files in AssertSQLGolden, e.g. CS_UPDATE_GOLDEN=1.
+const EnvUpdateGolden = "CS_UPDATE_GOLDEN"
+
+const (
+	goldenSection      = "=== "
+	goldenPlaceholders = "-- sql --"
+	goldenInterpolated = "-- interpolated --"
+	goldenArgs         = "-- args --"
+)
+
+// goldenMu serializes the rewriting of golden files by parallel tests.
+var goldenMu sync.Mutex
+
+// AssertSQLGolden compares the SQL with placeholders, the interpolated SQL and
+// the arguments of qb with the section of the golden file named after the
+// test. Sub tests can therefore store all their statements in one file. A
+// *dml.DBR gets its arguments from args, other query builders get args
+// appended to their own arguments. Whitespace outside of quoted strings gets
+// normalized, hence the SQL in the golden file can be formatted by hand.
+//
+// The golden file gets rewritten if the boolean flag -update, defined by the
+// test package, or the environment variable of EnvUpdateGolden is set.
+//
+//	t.Run("insert", func(t *testing.T) {
+//		dmltest.AssertSQLGolden(t, dml.NewInsert("customer_entity").AddColumns("email"),
+//			"testdata/customer_entity.golden", "a@b.c")
+//	})
+//
+// The golden file contains a section per test:
+//
+//	=== TestCustomerEntity/insert
+//	-- sql --
+//	INSERT INTO `customer_entity` (`email`) VALUES (?)
+//	-- interpolated --
+//	INSERT INTO `customer_entity` (`email`) VALUES ('a@b.c')
+//	-- args --
+//	"a@b.c"
+func AssertSQLGolden(t testing.TB, qb dml.QueryBuilder, goldenPath string, args ...interface{}) bool {
+	t.Helper()
+	have, err := renderGoldenSection(qb, args)
+	if err != nil {
+		t.Errorf("[dmltest] AssertSQLGolden %q: %+v", t.Name(), err)
+		return false
+	}
+
+	goldenMu.Lock()
+	defer goldenMu.Unlock()
+
+	sections, err := readGoldenFile(goldenPath)
+	if err != nil && !(os.IsNotExist(err) && updateGolden()) {
+		t.Errorf("[dmltest] AssertSQLGolden %q: %+v\nSet the environment variable %s=1 to create the file.", t.Name(), err, EnvUpdateGolden)
+		return false
+	}
+
+	if updateGolden() {
+		sections[t.Name()] = have
+		if err := writeGoldenFile(goldenPath, sections); err != nil {
+			t.Errorf("[dmltest] AssertSQLGolden %q: %+v", t.Name(), err)
+			return false
+		}
+		return true
+	}
+
+	want, ok := sections[t.Name()]
+	if !ok {
+		t.Errorf("[dmltest] AssertSQLGolden: Section %q not found in golden file %q.\nSet the environment variable %s=1 to add it.", t.Name(), goldenPath, EnvUpdateGolden)
+		return false
+	}
+	if want != have {
+		t.Errorf("[dmltest] AssertSQLGolden: Section %q of golden file %q does not match.\n%s", t.Name(), goldenPath, assert.DiffValues(want, have))
+		return false
+	}
+	return true
+}
+
+// updateGolden reports whether the test binary has been started with -update
+// or the environment variable of EnvUpdateGolden is set.
+func updateGolden() bool {
+	if f := flag.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			if b, ok := g.Get().(bool); ok && b {
+				return true
+			}
+		}
+	}
+	b, _ := strconv.ParseBool(os.Getenv(EnvUpdateGolden))
+	return b
+}
+
+// renderGoldenSection renders the placeholder SQL, the interpolated SQL and
+// the arguments of qb into the normalized form of a golden file section.
+func renderGoldenSection(qb dml.QueryBuilder, args []interface{}) (string, error) {
+	var sqlStr, sqlInterpolated string
+	var qbArgs []interface{}
+	var err error
+	if dbr, ok := qb.(*dml.DBR); ok {
+		prevOptions := dbr.Options
+		defer func() { dbr.Options = prevOptions }()
+		tqb := dbr.TestWithArgs(args...) // 1st call placeholders, 2nd call interpolated
+		if sqlStr, qbArgs, err = tqb.ToSQL(); err != nil {
+			return "", err
+		}
+		qbArgs = append([]interface{}(nil), qbArgs...) // 2nd call reuses the slice
+		if sqlInterpolated, _, err = tqb.ToSQL(); err != nil {
+			return "", err
+		}
+	} else {
+		if sqlStr, qbArgs, err = qb.ToSQL(); err != nil {
+			return "", err
+		}
+		qbArgs = append(qbArgs, args...)
+		if sqlInterpolated, _, err = dml.Interpolate(sqlStr).Unsafe(append([]interface{}(nil), qbArgs...)...).ToSQL(); err != nil {
+			return "", err
+		}
+	}
+
+	var buf strings.Builder
+	buf.WriteString(goldenPlaceholders + "\n" + NormalizeSQL(sqlStr) + "\n")
+	buf.WriteString(goldenInterpolated + "\n" + NormalizeSQL(sqlInterpolated) + "\n")
+	buf.WriteString(goldenArgs + "\n")
+	for _, a := range qbArgs {
+		fmt.Fprintf(&buf, "%#v\n", a)
+	}
+	return buf.String(), nil
+}
+
+// NormalizeSQL replaces all whitespace outside of quoted strings and
+// identifiers with a single space and trims the SQL.
+func NormalizeSQL(sqlStr string) string {
+	var buf strings.Builder
+	buf.Grow(len(sqlStr))
+	var quote rune
+	var escaped, space bool
+	for _, r := range sqlStr {
+		switch {
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\' && quote != '`':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			space = true
+			continue
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		}
+		if space && buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		space = false
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// readGoldenFile parses the sections of a golden file and normalizes the SQL.
+// It returns an empty map and the error if the file cannot be read.
+func readGoldenFile(path string) (map[string]string, error) {
+	sections := map[string]string{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return sections, err
+	}
+	var name, part string
+	parts := map[string]*strings.Builder{}
+	flush := func() {
+		if name == "" {
+			return
+		}
+		var buf strings.Builder
+		buf.WriteString(goldenPlaceholders + "\n" + NormalizeSQL(parts[goldenPlaceholders].String()) + "\n")
+		buf.WriteString(goldenInterpolated + "\n" + NormalizeSQL(parts[goldenInterpolated].String()) + "\n")
+		buf.WriteString(goldenArgs + "\n" + parts[goldenArgs].String())
+		sections[name] = buf.String()
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case strings.HasPrefix(line, goldenSection):
+			flush()
+			name, part = strings.TrimSpace(line[len(goldenSection):]), ""
+			parts = map[string]*strings.Builder{
+				goldenPlaceholders: {},
+				goldenInterpolated: {},
+				goldenArgs:         {},
+			}
+		case name != "" && parts[strings.TrimSpace(line)] != nil:
+			part = strings.TrimSpace(line)
+		case part == goldenArgs && strings.TrimSpace(line) != "":
+			parts[part].WriteString(strings.TrimSpace(line) + "\n")
+		case part != "" && part != goldenArgs:
+			parts[part].WriteString(line + "\n")
+		}
+	}
+	flush()
+	return sections, nil
+}
+
+// writeGoldenFile writes the sections sorted by name.
+func writeGoldenFile(path string, sections map[string]string) error {
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(goldenSection + name + "\n" + sections[name])
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
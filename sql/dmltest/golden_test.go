@@ -0,0 +1,103 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestAssertSQLGolden(t *testing.T) {
+	const golden = "testdata/customer_entity.golden"
+
+	t.Run("insert", func(t *testing.T) {
+		dmltest.AssertSQLGolden(t, dml.NewInsert("customer_entity").AddColumns("email", "group_id").BuildValues(),
+			golden, "a@b.c", null.MakeInt64(3))
+	})
+	t.Run("select", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dmltest.AssertSQLGolden(t, dbc.WithQueryBuilder(
+			dml.NewSelect("entity_id", "email").From("customer_entity").
+				Where(dml.Column("email").Like().PlaceHolder(), dml.Column("group_id").In().Int64s(1, 2)).
+				OrderBy("email"),
+		), golden, "%@b.c")
+	})
+	t.Run("update", func(t *testing.T) {
+		dmltest.AssertSQLGolden(t, dml.NewUpdate("customer_entity").AddClauses(
+			dml.Column("email").Str("x  y"),
+		).Where(dml.Column("entity_id").Int64(33)), golden)
+	})
+
+	t.Run("missing section", func(t *testing.T) {
+		rec := &recordTB{TB: t}
+		assert.False(t, dmltest.AssertSQLGolden(rec, dml.NewInsert("customer_entity").AddColumns("email").BuildValues(), golden, "a@b.c"))
+		assert.Len(t, rec.errors, 1)
+		assert.Contains(t, rec.errors[0], "not found in golden file")
+	})
+
+	t.Run("update golden file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "dmltest")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "new.golden")
+
+		for _, table := range []string{"b_table", "a_table"} {
+			t.Run(table, func(t *testing.T) {
+				qb := dml.NewDelete(table).Where(dml.Column("id").PlaceHolder())
+				assert.NoError(t, os.Setenv(dmltest.EnvUpdateGolden, "1"))
+				dmltest.AssertSQLGolden(t, qb, path, 4711)
+				assert.NoError(t, os.Unsetenv(dmltest.EnvUpdateGolden))
+
+				assert.True(t, dmltest.AssertSQLGolden(t, qb, path, 4711))
+				rec := &recordTB{TB: t}
+				assert.False(t, dmltest.AssertSQLGolden(rec, qb, path, 4712))
+				assert.Len(t, rec.errors, 1)
+				assert.Contains(t, rec.errors[0], "does not match")
+			})
+		}
+
+		data, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Exactly(t, "=== TestAssertSQLGolden/update_golden_file/a_table\n"+
+			"-- sql --\nDELETE FROM `a_table` WHERE (`id` = ?)\n"+
+			"-- interpolated --\nDELETE FROM `a_table` WHERE (`id` = 4711)\n"+
+			"-- args --\n4711\n"+
+			"\n=== TestAssertSQLGolden/update_golden_file/b_table\n"+
+			"-- sql --\nDELETE FROM `b_table` WHERE (`id` = ?)\n"+
+			"-- interpolated --\nDELETE FROM `b_table` WHERE (`id` = 4711)\n"+
+			"-- args --\n4711\n", string(data))
+	})
+}
+
+func TestNormalizeSQL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"  SELECT *\n\tFROM  `a  b`\r\n WHERE x = 'a  \\'  b' ", "SELECT * FROM `a  b` WHERE x = 'a  \\'  b'"},
+		{"SELECT \"x\n  y\"  ,1", "SELECT \"x\n  y\" ,1"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		assert.Exactly(t, test.want, dmltest.NormalizeSQL(test.in), "%q", test.in)
+	}
+}
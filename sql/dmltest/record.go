@@ -0,0 +1,342 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+)
+
+var mockDSNCounter uint64
+
+// recordMocks maps the mocks created by MockDB to their record expectations.
+var recordMocks = struct {
+	sync.Mutex
+	m map[sqlmock.Sqlmock]*recordMock
+}{
+	m: make(map[sqlmock.Sqlmock]*recordMock),
+}
+
+// recordMock contains the pending record expectations of a mock in the order
+// of their creation.
+type recordMock struct {
+	mu      sync.Mutex
+	inserts []*ExpectedRecord
+}
+
+// newMockDB creates a sqlmock database whose connections pass the arguments of
+// an INSERT to the record expectations before they reach the mock.
+func newMockDB() (*sql.DB, sqlmock.Sqlmock, error) {
+	dsn := fmt.Sprintf("dmltest_mock_%d", atomic.AddUint64(&mockDSNCounter, 1))
+	mdb, sm, err := sqlmock.NewWithDSN(dsn)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	rm := new(recordMock)
+	db := sql.OpenDB(recordConnector{drv: mdb.Driver(), dsn: dsn, rm: rm})
+	if err := db.Ping(); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	// The connection of db keeps the mock registered in the sqlmock driver.
+	// Close returns an error because ExpectClose has not been set.
+	_ = mdb.Close()
+
+	recordMocks.Lock()
+	recordMocks.m[sm] = rm
+	recordMocks.Unlock()
+	return db, sm, nil
+}
+
+func unregisterRecordMock(sm sqlmock.Sqlmock) {
+	recordMocks.Lock()
+	delete(recordMocks.m, sm)
+	recordMocks.Unlock()
+}
+
+// ExpectedRecord expects an INSERT statement into a table whose rows, mapped
+// back to an entity, must satisfy the record matchers. All functions of
+// sqlmock.ExpectedExec can be used to define the result.
+type ExpectedRecord struct {
+	*sqlmock.ExpectedExec
+	t        testing.TB
+	table    string
+	re       *regexp.Regexp
+	matchers []reflect.Value
+}
+
+// ExpectInsertRecord expects an INSERT or REPLACE statement into table. The
+// mock must have been created by MockDB or MockDBCallBack. The arguments of
+// the statement get mapped back to the column names of the statement and
+// scanned into the entity of the matchers, see WithRecordMatching. Interpolated
+// statements are not supported.
+//
+//	dmltest.ExpectInsertRecord(t, dbMock, "core_configuration").
+//		WithRecordMatching(func(cc *CoreConfiguration) bool {
+//			return cc.Path == "general/locale/code"
+//		}).
+//		WillReturnResult(sqlmock.NewResult(1, 1))
+func ExpectInsertRecord(t testing.TB, mock sqlmock.Sqlmock, table string) *ExpectedRecord {
+	t.Helper()
+	recordMocks.Lock()
+	rm, ok := recordMocks.m[mock]
+	recordMocks.Unlock()
+	if !ok {
+		t.Fatalf("[dmltest] ExpectInsertRecord: the mock must be created by MockDB or MockDBCallBack")
+	}
+	pattern := `^(?:INSERT|REPLACE)(?: IGNORE)? INTO ` + regexp.QuoteMeta(dml.Quoter.Name(table)) + ` `
+	er := &ExpectedRecord{
+		ExpectedExec: mock.ExpectExec(pattern),
+		t:            t,
+		table:        table,
+		re:           regexp.MustCompile(pattern),
+	}
+	rm.mu.Lock()
+	rm.inserts = append(rm.inserts, er)
+	rm.mu.Unlock()
+	return er
+}
+
+var (
+	typeColumnMapper = reflect.TypeOf((*dml.ColumnMapper)(nil)).Elem()
+	typeBool         = reflect.TypeOf(true)
+)
+
+// WithRecordMatching adds a matcher of type `func(*T) bool` where *T implements
+// dml.ColumnMapper. Each inserted row gets scanned into a new *T and must
+// satisfy all matchers, otherwise the execution of the statement fails.
+func (er *ExpectedRecord) WithRecordMatching(matcher interface{}) *ExpectedRecord {
+	er.t.Helper()
+	fn := reflect.ValueOf(matcher)
+	ft := fn.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 1 || ft.NumOut() != 1 || ft.Out(0) != typeBool ||
+		ft.In(0).Kind() != reflect.Ptr || !ft.In(0).Implements(typeColumnMapper) {
+		er.t.Fatalf("[dmltest] WithRecordMatching: matcher must be of type func(*T) bool and *T must implement dml.ColumnMapper, have %T", matcher)
+	}
+	er.matchers = append(er.matchers, fn)
+	return er
+}
+
+// matchRecords scans the rows of the INSERT statement and runs the matchers.
+func (er *ExpectedRecord) matchRecords(query string, args []driver.NamedValue) error {
+	columns := insertColumns(query)
+	if len(columns) == 0 || len(args) < len(columns) {
+		return errors.NotSupported.Newf("[dmltest] ExpectInsertRecord %q: cannot map the %d arguments to the columns of query %q", er.table, len(args), query)
+	}
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	for row := 0; (row+1)*len(columns) <= len(values); row++ {
+		rowValues := values[row*len(columns) : (row+1)*len(columns)]
+		for _, fn := range er.matchers {
+			rec := reflect.New(fn.Type().In(0).Elem())
+			if err := scanRecord(columns, rowValues, rec.Interface().(dml.ColumnMapper)); err != nil {
+				return errors.Wrapf(err, "[dmltest] ExpectInsertRecord %q row %d", er.table, row)
+			}
+			if !fn.Call([]reflect.Value{rec})[0].Bool() {
+				return errors.Mismatch.Newf("[dmltest] ExpectInsertRecord %q: row %d does not match the record matcher %s\n%s",
+					er.table, row, fn.Type(), formatRecordRow(columns, rowValues))
+			}
+		}
+	}
+	return nil
+}
+
+func formatRecordRow(columns []string, values []driver.Value) string {
+	var buf strings.Builder
+	for i, c := range columns {
+		fmt.Fprintf(&buf, "%s: %#v\n", c, values[i])
+	}
+	return buf.String()
+}
+
+var reInsertColumns = regexp.MustCompile(`^(?:INSERT|REPLACE)[^(]+\(([^)]*)\)\s*VALUES`)
+
+// insertColumns returns the unquoted column names of an INSERT statement.
+func insertColumns(query string) []string {
+	m := reInsertColumns.FindStringSubmatch(query)
+	if m == nil {
+		return nil
+	}
+	columns := strings.Split(m[1], ",")
+	for i, c := range columns {
+		columns[i] = strings.Trim(strings.TrimSpace(c), "`")
+	}
+	return columns
+}
+
+// scanRecord scans the values into rec like DBR.Load does.
+func scanRecord(columns []string, values []driver.Value, rec dml.ColumnMapper) error {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("").WillReturnRows(sqlmock.NewRows(columns).AddRow(values...))
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer rows.Close()
+	cm := dml.NewColumnMap(0)
+	for rows.Next() {
+		if err := cm.Scan(rows); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := rec.MapColumns(cm); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(rows.Err())
+}
+
+// ExpectSelectRecords expects a query matching the regular expression sqlRegex
+// and returns the records as rows with the given columns. The values of the
+// rows get collected via the MapColumns function of the records. A record can
+// be an entity or a collection of entities.
+//
+//	dmltest.ExpectSelectRecords(t, dbMock, "SELECT .+ FROM `core_configuration`",
+//		[]string{"config_id", "path", "value"}, &CoreConfiguration{ConfigID: 1, Path: "a/b/c"})
+func ExpectSelectRecords(t testing.TB, mock sqlmock.Sqlmock, sqlRegex string, columns []string, records ...dml.ColumnMapper) *sqlmock.ExpectedQuery {
+	t.Helper()
+	rows := sqlmock.NewRows(columns)
+	for _, rec := range records {
+		values, err := recordValues(columns, rec)
+		FatalIfError(t, err)
+		for len(values) >= len(columns) {
+			rows.AddRow(values[:len(columns)]...)
+			values = values[len(columns):]
+		}
+	}
+	return mock.ExpectQuery(sqlRegex).WillReturnRows(rows)
+}
+
+// recordValues collects the values of the columns from rec and converts them
+// into driver values.
+func recordValues(columns []string, rec dml.ColumnMapper) ([]driver.Value, error) {
+	_, args, err := dml.NewInsert("dmltest").AddColumns(columns...).WithDBR(nil).
+		TestWithArgs(dml.Qualify("", rec)).ToSQL()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(args)%len(columns) != 0 {
+		return nil, errors.Mismatch.Newf("[dmltest] ExpectSelectRecords: %T returned %d values for %d columns", rec, len(args), len(columns))
+	}
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		if values[i], err = driver.DefaultParameterConverter.ConvertValue(a); err != nil {
+			return nil, errors.Wrapf(err, "[dmltest] ExpectSelectRecords: column %q of %T", columns[i%len(columns)], rec)
+		}
+	}
+	return values, nil
+}
+
+// checkInsert runs the next record expectation matching the query.
+func (rm *recordMock) checkInsert(query string, args []driver.NamedValue) error {
+	rm.mu.Lock()
+	var er *ExpectedRecord
+	for i, e := range rm.inserts {
+		if e.re.MatchString(query) {
+			er = e
+			rm.inserts = append(rm.inserts[:i], rm.inserts[i+1:]...)
+			break
+		}
+	}
+	rm.mu.Unlock()
+	if er == nil {
+		return nil
+	}
+	return er.matchRecords(query, args)
+}
+
+// recordConnector opens connections of the sqlmock driver.
+type recordConnector struct {
+	drv driver.Driver
+	dsn string
+	rm  *recordMock
+}
+
+func (rc recordConnector) Connect(_ context.Context) (driver.Conn, error) {
+	conn, err := rc.drv.Open(rc.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return recordConn{mockConn: conn.(mockConn), rm: rc.rm}, nil
+}
+
+func (rc recordConnector) Driver() driver.Driver { return rc.drv }
+
+// mockConn defines the interfaces implemented by a sqlmock connection.
+type mockConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.NamedValueChecker
+}
+
+type recordConn struct {
+	mockConn
+	rm *recordMock
+}
+
+func (c recordConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.rm.checkInsert(query, args); err != nil {
+		return nil, err
+	}
+	return c.mockConn.ExecContext(ctx, query, args)
+}
+
+func (c recordConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.mockConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return recordStmt{mockStmt: stmt.(mockStmt), query: query, rm: c.rm}, nil
+}
+
+// mockStmt defines the interfaces implemented by a sqlmock statement.
+type mockStmt interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+}
+
+type recordStmt struct {
+	mockStmt
+	query string
+	rm    *recordMock
+}
+
+func (s recordStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := s.rm.checkInsert(s.query, args); err != nil {
+		return nil, err
+	}
+	return s.mockStmt.ExecContext(ctx, args)
+}
@@ -0,0 +1,168 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type coreConfiguration struct {
+	ConfigID int64
+	Scope    string
+	ScopeID  int64
+	Path     string
+	Value    null.String
+}
+
+func (cc *coreConfiguration) MapColumns(cm *dml.ColumnMap) error {
+	for cm.Next(5) {
+		switch c := cm.Column(); c {
+		case "config_id", "0":
+			cm.Int64(&cc.ConfigID)
+		case "scope", "1":
+			cm.String(&cc.Scope)
+		case "scope_id", "2":
+			cm.Int64(&cc.ScopeID)
+		case "path", "3":
+			cm.String(&cc.Path)
+		case "value", "4":
+			cm.NullString(&cc.Value)
+		default:
+			return errors.NotFound.Newf("[dmltest_test] coreConfiguration Column %q not found", c)
+		}
+	}
+	return cm.Err()
+}
+
+type coreConfigurations struct {
+	Data []*coreConfiguration
+}
+
+func (cc *coreConfigurations) MapColumns(cm *dml.ColumnMap) error {
+	switch m := cm.Mode(); m {
+	case dml.ColumnMapEntityReadAll, dml.ColumnMapEntityReadSet:
+		for _, c := range cc.Data {
+			if err := c.MapColumns(cm); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	case dml.ColumnMapScan:
+		if cm.Count == 0 {
+			cc.Data = cc.Data[:0]
+		}
+		c := new(coreConfiguration)
+		if err := c.MapColumns(cm); err != nil {
+			return errors.WithStack(err)
+		}
+		cc.Data = append(cc.Data, c)
+	default:
+		return errors.NotSupported.Newf("[dmltest_test] Unknown Mode: %q", string(m))
+	}
+	return cm.Err()
+}
+
+func TestExpectInsertRecord(t *testing.T) {
+	insert := dml.NewInsert("core_configuration").AddColumns("scope", "scope_id", "path", "value")
+	rec := &coreConfiguration{Scope: "stores", ScopeID: 2, Path: "general/locale/code", Value: null.MakeString("de_CH")}
+
+	t.Run("matches", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		var calls int
+		dmltest.ExpectInsertRecord(t, dbMock, "core_configuration").
+			WithRecordMatching(func(cc *coreConfiguration) bool {
+				calls++
+				return cc.Scope == "stores" && cc.ScopeID == 2 && cc.Path == "general/locale/code" && cc.Value.Data == "de_CH"
+			}).
+			WillReturnResult(sqlmock.NewResult(4711, 1))
+
+		res, err := dbc.WithQueryBuilder(insert.Clone()).ExecContext(context.Background(), dml.Qualify("", rec))
+		assert.NoError(t, err)
+		lid, err := res.LastInsertId()
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(4711), lid)
+		assert.Exactly(t, 1, calls)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dbc.Close()
+
+		dmltest.ExpectInsertRecord(t, dbMock, "core_configuration").
+			WithRecordMatching(func(cc *coreConfiguration) bool { return cc.Value.Valid && cc.Value.Data == "en_US" }).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		_, err := dbc.WithQueryBuilder(insert.Clone()).ExecContext(context.Background(), dml.Qualify("", rec))
+		assert.True(t, errors.Mismatch.Match(err), "%+v", err)
+		assert.Contains(t, err.Error(), `value: "de_CH"`)
+		assert.Error(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("prepared statement with many rows", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+
+		dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("INSERT INTO `core_configuration` (`scope`,`scope_id`,`path`,`value`) VALUES (?,?,?,?),(?,?,?,?)"))
+		var paths []string
+		dmltest.ExpectInsertRecord(t, dbMock, "core_configuration").
+			WithRecordMatching(func(cc *coreConfiguration) bool {
+				paths = append(paths, cc.Path)
+				return cc.Scope == "default"
+			}).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		stmt := dbc.WithPrepare(context.Background(), insert.Clone().BuildValues().SetRowCount(2))
+		_, err := stmt.ExecContext(context.Background(), dml.Qualify("", &coreConfigurations{Data: []*coreConfiguration{
+			{Scope: "default", Path: "a/b/c"},
+			{Scope: "default", Path: "d/e/f"},
+		}}))
+		assert.NoError(t, err)
+		assert.Exactly(t, []string{"a/b/c", "d/e/f"}, paths)
+	})
+}
+
+func TestExpectSelectRecords(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	dmltest.ExpectSelectRecords(t, dbMock, "SELECT .+ FROM `core_configuration`",
+		[]string{"config_id", "path", "value"},
+		&coreConfiguration{ConfigID: 1, Path: "a/b/c"},
+		&coreConfigurations{Data: []*coreConfiguration{
+			{ConfigID: 2, Path: "d/e/f", Value: null.MakeString("x")},
+			{ConfigID: 3, Path: "g/h/i", Value: null.MakeString("y")},
+		}},
+	)
+
+	var ccs coreConfigurations
+	rows, err := dbc.WithQueryBuilder(dml.NewSelect("config_id", "path", "value").From("core_configuration")).
+		Load(context.Background(), &ccs)
+	assert.NoError(t, err)
+	assert.Exactly(t, uint64(3), rows)
+	assert.Exactly(t, []*coreConfiguration{
+		{ConfigID: 1, Path: "a/b/c"},
+		{ConfigID: 2, Path: "d/e/f", Value: null.MakeString("x")},
+		{ConfigID: 3, Path: "g/h/i", Value: null.MakeString("y")},
+	}, ccs.Data)
+}
@@ -0,0 +1,14 @@
+-- Schema of the customer fixture; semicolons in comments; are ignored.
+DROP TABLE IF EXISTS `customer_entity`;
+
+/* The table gets
+   filled by customer_entity.csv */
+CREATE TABLE `customer_entity` (
+  `entity_id` INT(10) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `email` VARCHAR(255) DEFAULT NULL COMMENT 'E-Mail; unique',
+  `is_active` SMALLINT(5) UNSIGNED NOT NULL DEFAULT 1,
+  `dob` DATE DEFAULT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`entity_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+# trailing comment
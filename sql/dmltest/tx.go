@@ -0,0 +1,99 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/corestoreio/pkg/sql/dml"
+)
+
+var savepointID uint64
+
+// WithTx begins a transaction on db and rolls it back after the test and all
+// its sub tests have finished, hence the test leaves no data behind. All
+// queries of the test must run within the returned transaction. If the test
+// commits or rolls back the transaction itself, WithTx reports an error,
+// because the data might have been left behind.
+//
+// A statement prepared on the connection pool cannot see the uncommitted data
+// of the transaction. Prepare the statements on the transaction or rebind
+// them with RebindPrepared.
+//
+//	tx := dmltest.WithTx(t, db)
+//	_, err := tx.WithQueryBuilder(dml.NewInsert("core_config_data")...).ExecContext(ctx)
+func WithTx(t testing.TB, db *dml.ConnPool) *dml.Tx {
+	t.Helper()
+	tx, err := db.BeginTx(context.Background(), nil)
+	FatalIfError(t, err)
+	t.Cleanup(func() {
+		switch err := tx.Rollback(); {
+		case err == sql.ErrTxDone:
+			t.Errorf("[dmltest] The test has committed or rolled back the transaction of WithTx. Data might have been left behind.")
+		case err != nil:
+			t.Errorf("[dmltest] Rollback of WithTx failed: %+v", err)
+		}
+	})
+	return tx
+}
+
+// RunInSavepoint runs fn within a savepoint of tx and rolls back to the
+// savepoint afterwards, even if fn calls t.Fatal. Changes of fn are therefore
+// invisible to the next sub test, while the data of the surrounding test stays.
+// Calls can be nested. The sub tests must not run in parallel on the same
+// transaction.
+//
+//	tx := dmltest.WithTx(t, db)
+//	t.Run("insert", func(t *testing.T) {
+//		dmltest.RunInSavepoint(t, tx, func() {
+//			// queries via tx
+//		})
+//	})
+func RunInSavepoint(t testing.TB, tx *dml.Tx, fn func()) {
+	t.Helper()
+	name := fmt.Sprintf("dmltest_sp%d", atomic.AddUint64(&savepointID, 1))
+	ctx := context.Background()
+	_, err := tx.DB.ExecContext(ctx, "SAVEPOINT "+name)
+	FatalIfError(t, err)
+	defer func() {
+		if _, err := tx.DB.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); err != nil {
+			t.Errorf("[dmltest] Rollback to savepoint %q failed: %+v", name, err)
+			return
+		}
+		if _, err := tx.DB.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+			t.Errorf("[dmltest] Release of savepoint %q failed: %+v", name, err)
+		}
+	}()
+	fn()
+}
+
+// RebindPrepared returns a copy of a prepared DBR of the connection pool which
+// runs within tx and can therefore see the uncommitted data. The statement of
+// the connection pool gets closed after the test.
+func RebindPrepared(t testing.TB, tx *dml.Tx, dbr *dml.DBR) *dml.DBR {
+	t.Helper()
+	FatalIfError(t, dbr.PreviousError())
+	t.Cleanup(func() {
+		if err := dbr.Close(); err != nil {
+			t.Errorf("[dmltest] Closing the prepared statement failed: %+v", err)
+		}
+	})
+	rebound := *dbr
+	return rebound.WithTx(tx)
+}
@@ -0,0 +1,113 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmltest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// recordTB records the errors instead of failing the test.
+type recordTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestWithTx(t *testing.T) {
+	t.Run("rollback after test", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("DELETE FROM `core_config_data`")).WillReturnResult(sqlmock.NewResult(0, 3))
+		dbMock.ExpectRollback()
+
+		t.Run("inner", func(t *testing.T) {
+			tx := dmltest.WithTx(t, dbc)
+			_, err := tx.WithQueryBuilder(dml.NewDelete("core_config_data")).ExecContext(context.Background())
+			assert.NoError(t, err)
+		})
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("detects commit", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectBegin()
+		dbMock.ExpectCommit()
+
+		var rec *recordTB
+		t.Run("inner", func(t *testing.T) {
+			rec = &recordTB{TB: t}
+			tx := dmltest.WithTx(rec, dbc)
+			assert.NoError(t, tx.Commit())
+		})
+		assert.Len(t, rec.errors, 1)
+		assert.Contains(t, rec.errors[0], "has committed or rolled back the transaction")
+	})
+
+	t.Run("RunInSavepoint nested", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("SAVEPOINT dmltest_sp[0-9]+").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("SAVEPOINT dmltest_sp[0-9]+").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("ROLLBACK TO SAVEPOINT dmltest_sp[0-9]+").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("RELEASE SAVEPOINT dmltest_sp[0-9]+").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("ROLLBACK TO SAVEPOINT dmltest_sp[0-9]+").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("RELEASE SAVEPOINT dmltest_sp[0-9]+").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectRollback()
+
+		var calls int
+		t.Run("inner", func(t *testing.T) {
+			tx := dmltest.WithTx(t, dbc)
+			dmltest.RunInSavepoint(t, tx, func() {
+				calls++
+				dmltest.RunInSavepoint(t, tx, func() {
+					calls++
+				})
+			})
+		})
+		assert.Exactly(t, 2, calls)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("RebindPrepared", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		prep := dbMock.ExpectPrepare(dmltest.SQLMockQuoteMeta("DELETE FROM `core_config_data` WHERE (`config_id` = ?)"))
+		dbMock.ExpectBegin()
+		prep.ExpectExec().WithArgs(4711).WillReturnResult(sqlmock.NewResult(0, 1))
+		prep.WillBeClosed()
+		dbMock.ExpectRollback()
+
+		t.Run("inner", func(t *testing.T) {
+			dbr := dbc.WithPrepare(context.Background(), dml.NewDelete("core_config_data").Where(dml.Column("config_id").PlaceHolder()))
+			tx := dmltest.WithTx(t, dbc)
+			_, err := dmltest.RebindPrepared(t, tx, dbr).ExecContext(context.Background(), 4711)
+			assert.NoError(t, err)
+		})
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
@@ -45,7 +45,7 @@ const (
 // Canal can sync your MySQL data. MySQL must use the binlog format ROW.
 type Canal struct {
 	opts                      Options
-	configPathBackendPosition *config.Path
+	configPathBackendPosition config.Path
 	// mclose acts only during the call to Close().
 	mclose sync.Mutex
 	// DSN contains the parsed DSN
@@ -93,8 +93,15 @@ type DBConFactory func(dsn string) (*dml.ConnPool, error)
 // from the provided DSN.
 func WithMySQL() DBConFactory {
 	return func(dsn string) (*dml.ConnPool, error) {
-		dbc, err := dml.NewConnPool(dml.WithDSN(dsn), dml.WithVerifyConnection())
-		return dbc, errors.WithStack(err)
+		dbc, err := dml.NewConnPool(dml.WithDSN(dsn))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err := dbc.DB.Ping(); err != nil {
+			_ = dbc.Close()
+			return nil, errors.WithStack(err)
+		}
+		return dbc, nil
 	}
 }
 
@@ -151,12 +158,31 @@ func withExcludeTables(regexes []string) func(c *Canal) error {
 // from the current master position. See startSyncBinlog
 func withUpdateBinlogStart(c *Canal) error {
 
+	if c.opts.BinlogStartGTID != "" {
+		gset, err := simysql.ParseGTIDSet(c.opts.Flavor, c.opts.BinlogStartGTID)
+		if err != nil {
+			return errors.NotValid.New(err, "[mycanal] withUpdateBinlogStart failed to parse GTID set %q", c.opts.BinlogStartGTID)
+		}
+		c.masterGTID = gset
+	}
+
 	if c.opts.BinlogStartFile != "" && c.opts.BinlogStartPosition > 0 {
 		c.masterStatus.File = c.opts.BinlogStartFile
 		c.masterStatus.Position = uint(c.opts.BinlogStartPosition)
 		return nil
 	}
 
+	// continue sync from the last stored master position, see masterSave.
+	if c.opts.ConfigScoped.IsValid() {
+		v, ok, err := c.opts.ConfigScoped.Get(scope.Default, ConfigPathBackendPosition).Str()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if ok && v != "" {
+			return errors.WithStack(c.masterStatus.FromString(v))
+		}
+	}
+
 	if c.opts.MasterStatusQueryTimeout == 0 {
 		c.opts.MasterStatusQueryTimeout = time.Second * 20
 	}
@@ -238,10 +264,22 @@ type Options struct {
 	// Set to change the maximum number of attempts to re-establish a broken
 	// connection
 	MaxReconnectAttempts int
+	// RestartDelay defines the initial waiting time before the binlog stream
+	// gets restarted after an error. The delay doubles with each failed
+	// attempt up to one minute. Defaults to one second.
+	RestartDelay time.Duration
+	// OnReplicationUnavailable gets called when the binlog stream cannot be
+	// started or gets aborted because the user lacks the REPLICATION SLAVE or
+	// REPLICATION CLIENT privilege. Canal stops syncing and the callback can
+	// switch to polling, for example via config/storage.DBPoller.
+	OnReplicationUnavailable func(error)
 
 	BinlogStartFile     string
 	BinlogStartPosition uint64
-	BinlogSlaveId       uint64
+	// BinlogStartGTID starts the stream from the GTID set, in the format of
+	// the configured Flavor. Takes precedence over the file and position.
+	BinlogStartGTID string
+	BinlogSlaveId   uint64
 	// Flavor defines if `mariadb` or `mysql` should be used. Defaults to
 	// `mariadb`.
 	Flavor                   string
@@ -360,8 +398,6 @@ func NewCanal(dsn string, db DBConFactory, opt *Options) (*Canal, error) {
 	return c, nil
 }
 
-// TODO continue sync from last stored master position
-
 func (c *Canal) masterSave(fileName string, pos uint) error {
 	c.masterMu.Lock()
 	defer c.masterMu.Unlock()
@@ -399,6 +435,17 @@ func (c *Canal) masterSave(fileName string, pos uint) error {
 	return nil
 }
 
+// masterUpdateGTIDSet stores the GTID set to resume from after a restart of the
+// binlog stream. A nil gset, as sent when syncing by position, gets ignored.
+func (c *Canal) masterUpdateGTIDSet(gset simysql.GTIDSet) {
+	if gset == nil {
+		return
+	}
+	c.masterMu.Lock()
+	c.masterGTID = gset
+	c.masterMu.Unlock()
+}
+
 // SyncedPosition returns the current synced position as retrieved from the SQl
 // server.
 func (c *Canal) SyncedPosition() ddl.MasterStatus {
@@ -410,20 +457,107 @@ func (c *Canal) SyncedPosition() ddl.MasterStatus {
 // Start starts the sync process in the background as a goroutine. You can stop
 // the goroutine via the context.
 func (c *Canal) Start(ctx context.Context) error {
+	c.wg.Add(1)
 	go c.run(ctx)
 	return nil
 }
 
-// run gets executed in its own goroutine
+const maxRestartDelay = time.Minute
+
+// run gets executed in its own goroutine. It restarts the binlog stream after
+// an error from the last GTID set or master position until the context gets
+// cancelled, Canal gets closed or the replication privileges are missing. The
+// restart delay starts again with RestartDelay once the stream delivers events.
 func (c *Canal) run(ctx context.Context) {
-	// refactor for better error handling
 	defer c.wg.Done()
-	c.wg.Add(1)
-	if err := c.startSyncBinlog(ctx); err != nil {
-		if !c.isClosed() && c.opts.Log.IsInfo() {
-			c.opts.Log.Info("[mycanal] Canal start has encountered a sync binlog error", log.Err(err))
+
+	initialDelay := c.opts.RestartDelay
+	if initialDelay <= 0 {
+		initialDelay = time.Second
+	}
+	delay := initialDelay
+	resetDelay := func() { delay = initialDelay }
+
+	for {
+		err := c.startSyncBinlog(ctx, resetDelay)
+		if err == nil || c.isClosed() || ctx.Err() != nil {
+			return
 		}
+		if isReplicationDenied(err) {
+			if c.opts.Log.IsInfo() {
+				c.opts.Log.Info("[mycanal] Canal stops because of missing replication privileges", log.Err(err))
+			}
+			if c.opts.OnReplicationUnavailable != nil {
+				c.opts.OnReplicationUnavailable(err)
+			}
+			return
+		}
+		if c.opts.Log.IsInfo() {
+			c.opts.Log.Info("[mycanal] Canal has encountered a sync binlog error and restarts", log.Err(err),
+				log.Duration("restart_delay", delay), log.Stringer("position", c.SyncedPosition()))
+		}
+
+		// a failed restart of the syncer gets retried with the same backoff.
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			if delay *= 2; delay > maxRestartDelay {
+				delay = maxRestartDelay
+			}
+
+			err := c.restartSyncer()
+			if err == nil {
+				break
+			}
+			if c.isClosed() {
+				return
+			}
+			if c.opts.Log.IsInfo() {
+				c.opts.Log.Info("[mycanal] Canal failed to restart the syncer and retries", log.Err(err),
+					log.Duration("restart_delay", delay))
+			}
+		}
+	}
+}
+
+// restartSyncer replaces the syncer because a BinlogSyncer cannot be started
+// twice.
+func (c *Canal) restartSyncer() error {
+	c.mclose.Lock()
+	defer c.mclose.Unlock()
+
+	if c.isClosed() {
+		return errors.AlreadyClosed.Newf("[mycanal] Canal already closed")
+	}
+	if c.syncer != nil {
+		if err := c.syncer.Close(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(withPrepareSyncer(c))
+}
+
+// isReplicationDenied reports whether the error has been caused by missing
+// privileges: 1045 access denied, 1142 command denied and 1227 specific access
+// denied, like REPLICATION SLAVE.
+func isReplicationDenied(err error) bool {
+	var code uint16
+	switch e := errors.Cause(err).(type) {
+	case *simysql.MyError:
+		code = e.Code
+	case *mysql.MySQLError:
+		code = e.Number
+	default:
+		return false
+	}
+	switch code {
+	case 1045, 1142, 1227:
+		return true
 	}
+	return false
 }
 
 func (c *Canal) isClosed() bool {
@@ -0,0 +1,138 @@
+package mycanal
+
+import (
+	"context"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/conv"
+)
+
+// RowEvent describes the change of a single row. The column indexes of the
+// binlog rows get mapped to the column names of the table structure.
+type RowEvent struct {
+	Table string
+	// Action is one of InsertAction, UpdateAction or DeleteAction.
+	Action string
+	// Before contains the row before an update or a delete. Nil for inserts.
+	Before map[string]interface{}
+	// After contains the row after an insert or an update. Nil for deletes.
+	After map[string]interface{}
+}
+
+// RowEventSubscriber gets called for each changed row. Same error rules apply
+// as for RowsEventHandler.Do.
+type RowEventSubscriber func(ctx context.Context, ev RowEvent) error
+
+// SubscribeRowEvents registers a subscriber, which receives typed row events,
+// for the tables. An empty tableNames slice subscribes to all allowed tables,
+// see RegisterRowsEventHandler. The name identifies the subscriber in logs.
+func (c *Canal) SubscribeRowEvents(name string, tableNames []string, fn RowEventSubscriber) {
+	c.RegisterRowsEventHandler(tableNames, rowEventHandler{name: name, fn: fn})
+}
+
+// rowEventHandler adapts a RowEventSubscriber to a RowsEventHandler.
+type rowEventHandler struct {
+	name string
+	fn   RowEventSubscriber
+}
+
+func (h rowEventHandler) Do(ctx context.Context, action string, t *ddl.Table, rows [][]interface{}) error {
+	evs, err := newRowEvents(action, t, rows)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, ev := range evs {
+		if err := h.fn(ctx, ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h rowEventHandler) Complete(context.Context) error { return nil }
+
+func (h rowEventHandler) String() string { return h.name }
+
+// newRowEvents creates one event per row. The rows of an update event come in
+// pairs of the before and the after image.
+func newRowEvents(action string, t *ddl.Table, rows [][]interface{}) ([]RowEvent, error) {
+	step := 1
+	if action == UpdateAction {
+		step = 2
+		if len(rows)%2 != 0 {
+			return nil, errors.NotValid.Newf("[mycanal] Table %q: Update event must contain an even number of rows, got %d", t.Name, len(rows))
+		}
+	}
+	evs := make([]RowEvent, 0, len(rows)/step)
+	for i := 0; i < len(rows); i += step {
+		ev := RowEvent{Table: t.Name, Action: action}
+		first, err := mapRowColumns(t, rows[i])
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		switch action {
+		case InsertAction:
+			ev.After = first
+		case DeleteAction:
+			ev.Before = first
+		case UpdateAction:
+			ev.Before = first
+			if ev.After, err = mapRowColumns(t, rows[i+1]); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		default:
+			return nil, errors.NotSupported.Newf("[mycanal] Table %q: Action %q not supported", t.Name, action)
+		}
+		evs = append(evs, ev)
+	}
+	return evs, nil
+}
+
+// mapRowColumns uses the position of the columns in the table structure. A
+// row with more values than columns indicates an outdated table structure.
+func mapRowColumns(t *ddl.Table, row []interface{}) (map[string]interface{}, error) {
+	if len(row) > len(t.Columns) {
+		return nil, errors.Mismatch.Newf("[mycanal] Table %q: Row has %d values but the table structure %d columns", t.Name, len(row), len(t.Columns))
+	}
+	m := make(map[string]interface{}, len(row))
+	for i, v := range row {
+		m[t.Columns[i].Field] = v
+	}
+	return m, nil
+}
+
+// NewTableInvalidator bumps the table generation of the changed table, so that
+// all results loaded via dml.DBR.LoadCached from that table get invalidated.
+// For example an objcache.Service can be used as dml.ResultCacher.
+func NewTableInvalidator(rc dml.ResultCacher) RowEventSubscriber {
+	return func(ctx context.Context, ev RowEvent) error {
+		return errors.WithStack(dml.InvalidateTable(ctx, rc, ev.Table))
+	}
+}
+
+// NewConfigPathNotifier converts the row events of the table
+// core_configuration into calls of fn, which has the signature of
+// config/storage.DBPollerSubscriber. A deleted row results in an invalid value.
+// Changes get delivered immediately instead of waiting for the next polling
+// interval. Subscribe it only for the core_configuration table.
+func NewConfigPathNotifier(fn func(scope string, scopeID uint32, path string, value null.String)) RowEventSubscriber {
+	return func(_ context.Context, ev RowEvent) error {
+		row := ev.After
+		if ev.Action == DeleteAction {
+			row = ev.Before
+		}
+		path, ok := row["path"]
+		if !ok {
+			return errors.NotFound.Newf("[mycanal] Table %q: Column %q not found", ev.Table, "path")
+		}
+		var value null.String
+		if ev.Action != DeleteAction && row["value"] != nil {
+			value = null.MakeString(conv.ToString(row["value"]))
+		}
+		fn(conv.ToString(row["scope"]), uint32(conv.ToUint(row["scope_id"])), conv.ToString(path), value)
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mycanal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/go-sql-driver/mysql"
+	simysql "github.com/siddontang/go-mysql/mysql"
+)
+
+func newTestConfigTable() *ddl.Table {
+	return ddl.NewTable("core_configuration",
+		&ddl.Column{Field: "config_id"},
+		&ddl.Column{Field: "scope"},
+		&ddl.Column{Field: "scope_id"},
+		&ddl.Column{Field: "path"},
+		&ddl.Column{Field: "value"},
+	)
+}
+
+func TestNewRowEvents(t *testing.T) {
+	t.Parallel()
+	tbl := newTestConfigTable()
+
+	t.Run("insert", func(t *testing.T) {
+		evs, err := newRowEvents(InsertAction, tbl, [][]interface{}{
+			{int32(1), "default", int32(0), "a/b/c", []byte("1")},
+			{int32(2), "stores", int32(2), "a/b/d", nil},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, evs, 2)
+		assert.Nil(t, evs[0].Before)
+		assert.Exactly(t, "core_configuration", evs[0].Table)
+		assert.Exactly(t, InsertAction, evs[0].Action)
+		assert.Exactly(t, "a/b/d", evs[1].After["path"])
+		assert.Exactly(t, int32(2), evs[1].After["scope_id"])
+	})
+	t.Run("update pairs", func(t *testing.T) {
+		evs, err := newRowEvents(UpdateAction, tbl, [][]interface{}{
+			{int32(1), "default", int32(0), "a/b/c", []byte("1")},
+			{int32(1), "default", int32(0), "a/b/c", []byte("2")},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, evs, 1)
+		assert.Exactly(t, []byte("1"), evs[0].Before["value"])
+		assert.Exactly(t, []byte("2"), evs[0].After["value"])
+	})
+	t.Run("update odd rows", func(t *testing.T) {
+		_, err := newRowEvents(UpdateAction, tbl, [][]interface{}{{int32(1)}})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("delete", func(t *testing.T) {
+		evs, err := newRowEvents(DeleteAction, tbl, [][]interface{}{{int32(1), "default", int32(0), "a/b/c", nil}})
+		assert.NoError(t, err)
+		assert.Nil(t, evs[0].After)
+		assert.Exactly(t, "a/b/c", evs[0].Before["path"])
+	})
+	t.Run("outdated table structure", func(t *testing.T) {
+		_, err := newRowEvents(InsertAction, tbl, [][]interface{}{{1, 2, 3, 4, 5, 6}})
+		assert.ErrorIsKind(t, errors.Mismatch, err)
+	})
+}
+
+func TestCanal_SubscribeRowEvents(t *testing.T) {
+	t.Parallel()
+	c := &Canal{}
+	var got []RowEvent
+	c.SubscribeRowEvents("collector", []string{"core_configuration"}, func(_ context.Context, ev RowEvent) error {
+		got = append(got, ev)
+		return nil
+	})
+	hs := c.rsHandlers["core_configuration"]
+	assert.Len(t, hs, 1)
+	assert.Exactly(t, "collector", hs[0].String())
+
+	err := hs[0].Do(context.Background(), InsertAction, newTestConfigTable(), [][]interface{}{{1}, {2}})
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Exactly(t, 2, got[1].After["config_id"])
+	assert.NoError(t, hs[0].Complete(context.Background()))
+}
+
+type resultCacherMock map[string]interface{}
+
+func (rc resultCacherMock) Get(ctx context.Context, key string, dst interface{}) error {
+	return errors.NotImplemented.Newf("not needed")
+}
+
+func (rc resultCacherMock) Set(ctx context.Context, key string, src interface{}, expires time.Duration) error {
+	rc[key] = src
+	return nil
+}
+
+func TestNewTableInvalidator(t *testing.T) {
+	t.Parallel()
+	rc := resultCacherMock{}
+	fn := NewTableInvalidator(rc)
+	assert.NoError(t, fn(context.Background(), RowEvent{Table: "catalog_product_entity", Action: UpdateAction}))
+	assert.Len(t, rc, 1)
+	for k := range rc {
+		assert.True(t, strings.HasSuffix(k, "catalog_product_entity"), "%q", k)
+	}
+}
+
+func TestNewConfigPathNotifier(t *testing.T) {
+	t.Parallel()
+	var got []string
+	fn := NewConfigPathNotifier(func(scope string, scopeID uint32, path string, value null.String) {
+		got = append(got, fmt.Sprintf("%s/%d/%s=%s", scope, scopeID, path, value.GoString()))
+	})
+	ctx := context.Background()
+	assert.NoError(t, fn(ctx, RowEvent{Action: InsertAction, After: map[string]interface{}{
+		"scope": "stores", "scope_id": int32(3), "path": "a/b/c", "value": []byte("x"),
+	}}))
+	assert.NoError(t, fn(ctx, RowEvent{Action: UpdateAction, After: map[string]interface{}{
+		"scope": "default", "scope_id": int32(0), "path": "a/b/d", "value": nil,
+	}}))
+	assert.NoError(t, fn(ctx, RowEvent{Action: DeleteAction, Before: map[string]interface{}{
+		"scope": "websites", "scope_id": int32(1), "path": "a/b/e", "value": []byte("y"),
+	}}))
+	assert.Exactly(t, []string{
+		"stores/3/a/b/c=null.MakeString(`x`)",
+		`default/0/a/b/d=null.String{}`,
+		`websites/1/a/b/e=null.String{}`,
+	}, got)
+
+	err := fn(ctx, RowEvent{Table: "core_configuration", Action: InsertAction, After: map[string]interface{}{}})
+	assert.ErrorIsKind(t, errors.NotFound, err)
+}
+
+func TestIsReplicationDenied(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isReplicationDenied(errors.WithStack(&simysql.MyError{Code: 1227})))
+	assert.True(t, isReplicationDenied(&mysql.MySQLError{Number: 1045}))
+	assert.False(t, isReplicationDenied(&mysql.MySQLError{Number: 1146}))
+	assert.False(t, isReplicationDenied(errors.ConnectionFailed.Newf("broken pipe")))
+	assert.False(t, isReplicationDenied(nil))
+}
@@ -88,7 +88,9 @@ func (c *Canal) startStream() (s *myreplicator.BinlogStream, err error) {
 	return
 }
 
-func (c *Canal) startSyncBinlog(ctxArg context.Context) error {
+// startSyncBinlog blocks and processes the binlog events. The optional started
+// function gets called once after the first event has been received.
+func (c *Canal) startSyncBinlog(ctxArg context.Context, started func()) error {
 
 	s, err := c.startStream()
 	if err != nil {
@@ -109,6 +111,10 @@ func (c *Canal) startSyncBinlog(ctxArg context.Context) error {
 			}
 			return errors.WithStack(err)
 		}
+		if started != nil {
+			started()
+			started = nil
+		}
 
 		currentPos := pos
 		//next binlog pos
@@ -147,10 +153,7 @@ func (c *Canal) startSyncBinlog(ctxArg context.Context) error {
 				continue // to not save the master position, not necessary.
 			}
 		case *myreplicator.XIDEvent:
-			// TODO implement
-			// if e.GSet != nil {
-			// 	c.master.UpdateGTIDSet(e.GSet)
-			// }
+			c.masterUpdateGTIDSet(e.GSet)
 
 		case *myreplicator.MariadbGTIDEvent:
 			// TODO implement
@@ -174,10 +177,7 @@ func (c *Canal) startSyncBinlog(ctxArg context.Context) error {
 			// }
 
 		case *myreplicator.QueryEvent:
-			// TODO implement GTID set but review if it makes sense to import siddontang/go-mysql/mysql
-			// if e.GSet != nil {
-			// 	c.master.UpdateGTIDSet(e.GSet)
-			// }
+			c.masterUpdateGTIDSet(e.GSet)
 
 			// handle alert table query
 			c.clearTableCacheOnDDLStmt(e.Schema, e.Query)
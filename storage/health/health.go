@@ -0,0 +1,229 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health aggregates the health checks of several components, like
+// the database connection pool, the object cache and the configuration
+// storage, into a single readiness answer with details per component.
+//
+//		agg := health.NewAggregator(2*time.Second, map[string]health.Checker{
+//			"mysql":  connPool,
+//			"redis":  objcacheService,
+//			"config": configDB,
+//		}).FailAfter(3)
+//		http.Handle("/readyz", agg)
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// DefaultTimeout bounds the duration of a single check, if the Aggregator has
+// been created without a timeout.
+const DefaultTimeout = 5 * time.Second
+
+// Checker gets implemented by components which can report their health.
+// HealthCheck must return nil if the component is ready to serve requests.
+// Types *dml.ConnPool, *objcache.Service and the storages of package
+// config/storage implement this interface.
+type Checker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// CheckerFunc is an adapter to use an ordinary function as a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// HealthCheck calls f(ctx).
+func (f CheckerFunc) HealthCheck(ctx context.Context) error { return f(ctx) }
+
+// Status defines the state of a component or of the whole report.
+type Status string
+
+// The states ordered by their severity.
+const (
+	// StatusUp reports a successful check.
+	StatusUp Status = "up"
+	// StatusDegraded reports a failed check whose consecutive failures have
+	// not yet reached the threshold of Aggregator.FailAfter.
+	StatusDegraded Status = "degraded"
+	// StatusDown reports a component which is not ready.
+	StatusDown Status = "down"
+)
+
+func (s Status) severity() int {
+	switch s {
+	case StatusUp:
+		return 0
+	case StatusDegraded:
+		return 1
+	}
+	return 2
+}
+
+// Component contains the result of a single check.
+type Component struct {
+	Status Status `json:"status"`
+	// Latency in nanoseconds.
+	Latency             time.Duration `json:"latency_ns"`
+	Error               string        `json:"error,omitempty"`
+	ConsecutiveFailures int           `json:"consecutive_failures,omitempty"`
+}
+
+// Report contains the aggregated status, which is the worst status of all
+// components, and the result per component. It is suitable for JSON encoding.
+type Report struct {
+	Status     Status               `json:"status"`
+	Components map[string]Component `json:"components"`
+}
+
+// Ready reports whether no component is down.
+func (r Report) Ready() bool { return r.Status != StatusDown }
+
+// Aggregator runs the checks of several components concurrently. It is safe
+// for concurrent use.
+type Aggregator struct {
+	timeout time.Duration
+	names   []string
+	checks  []Checker
+
+	mu        sync.Mutex
+	failAfter int
+	failures  []int
+}
+
+// NewAggregator creates a new Aggregator. Argument timeout bounds each check
+// individually, if zero DefaultTimeout applies.
+func NewAggregator(timeout time.Duration, checks map[string]Checker) *Aggregator {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	a := &Aggregator{
+		timeout:   timeout,
+		names:     make([]string, 0, len(checks)),
+		checks:    make([]Checker, 0, len(checks)),
+		failAfter: 1,
+		failures:  make([]int, len(checks)),
+	}
+	for name := range checks {
+		a.names = append(a.names, name)
+	}
+	sort.Strings(a.names)
+	for _, name := range a.names {
+		a.checks = append(a.checks, checks[name])
+	}
+	return a
+}
+
+// FailAfter reports a component as down only after n consecutive failed
+// checks, to protect against flapping. Failed checks below the threshold
+// report the component as degraded. Default 1, which reports the first failure
+// as down.
+func (a *Aggregator) FailAfter(n int) *Aggregator {
+	if n < 1 {
+		n = 1
+	}
+	a.mu.Lock()
+	a.failAfter = n
+	a.mu.Unlock()
+	return a
+}
+
+// Check runs all checks concurrently and waits until all of them have
+// returned or timed out. A check which does not return within the timeout
+// gets reported as failed, its goroutine returns in the background.
+func (a *Aggregator) Check(ctx context.Context) Report {
+	results := make([]Component, len(a.checks))
+	errs := make([]error, len(a.checks))
+	var wg sync.WaitGroup
+	wg.Add(len(a.checks))
+	for i, c := range a.checks {
+		go func(i int, c Checker) {
+			defer wg.Done()
+			start := time.Now()
+			errs[i] = a.runCheck(ctx, c)
+			results[i].Latency = time.Since(start)
+		}(i, c)
+	}
+	wg.Wait()
+
+	r := Report{
+		Status:     StatusUp,
+		Components: make(map[string]Component, len(a.checks)),
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, err := range errs {
+		c := results[i]
+		switch {
+		case err == nil:
+			a.failures[i] = 0
+			c.Status = StatusUp
+		default:
+			a.failures[i]++
+			c.Error = err.Error()
+			c.ConsecutiveFailures = a.failures[i]
+			c.Status = StatusDegraded
+			if a.failures[i] >= a.failAfter {
+				c.Status = StatusDown
+			}
+		}
+		if c.Status.severity() > r.Status.severity() {
+			r.Status = c.Status
+		}
+		r.Components[a.names[i]] = c
+	}
+	return r
+}
+
+func (a *Aggregator) runCheck(ctx context.Context, c Checker) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	errC := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errC <- errors.Fatal.Newf("[health] Check panicked: %v", r)
+			}
+		}()
+		errC <- c.HealthCheck(ctx)
+	}()
+	select {
+	case err = <-errC:
+		return err
+	case <-ctx.Done():
+		return errors.Timeout.New(ctx.Err(), "[health] Check did not finish within %s", a.timeout)
+	}
+}
+
+// ServeHTTP runs Check and writes the report as JSON. The status code is 503
+// if a component is down, otherwise 200. Suitable for Kubernetes readiness
+// probes.
+func (a *Aggregator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rep := a.Check(r.Context())
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if rep.Ready() {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(rep)
+}
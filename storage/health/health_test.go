@@ -0,0 +1,107 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/health"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestAggregator_Check(t *testing.T) {
+	agg := health.NewAggregator(50*time.Millisecond, map[string]health.Checker{
+		"db": health.CheckerFunc(func(ctx context.Context) error { return nil }),
+		"slow": health.CheckerFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		"blocking": health.CheckerFunc(func(ctx context.Context) error {
+			time.Sleep(200 * time.Millisecond) // ignores the context
+			return nil
+		}),
+		"panic": health.CheckerFunc(func(ctx context.Context) error {
+			panic("ups")
+		}),
+	})
+
+	start := time.Now()
+	rep := agg.Check(context.Background())
+	assert.True(t, time.Since(start) < 150*time.Millisecond, "checks must run concurrently with individual timeouts")
+	assert.Exactly(t, health.StatusDown, rep.Status)
+	assert.False(t, rep.Ready())
+	assert.Exactly(t, health.StatusUp, rep.Components["db"].Status)
+	assert.Exactly(t, health.StatusDown, rep.Components["slow"].Status)
+	assert.Exactly(t, health.StatusDown, rep.Components["blocking"].Status)
+	assert.Contains(t, rep.Components["blocking"].Error, "did not finish within 50ms")
+	assert.Exactly(t, health.StatusDown, rep.Components["panic"].Status)
+	assert.Contains(t, rep.Components["panic"].Error, "ups")
+}
+
+func TestAggregator_FailAfter(t *testing.T) {
+	var dbErr error
+	agg := health.NewAggregator(0, map[string]health.Checker{
+		"db": health.CheckerFunc(func(ctx context.Context) error { return dbErr }),
+	}).FailAfter(3)
+
+	dbErr = errors.ConnectionFailed.Newf("connection refused")
+	for i := 1; i <= 2; i++ {
+		rep := agg.Check(context.Background())
+		assert.Exactly(t, health.StatusDegraded, rep.Status)
+		assert.True(t, rep.Ready())
+		assert.Exactly(t, i, rep.Components["db"].ConsecutiveFailures)
+		assert.Contains(t, rep.Components["db"].Error, "connection refused")
+	}
+	rep := agg.Check(context.Background())
+	assert.Exactly(t, health.StatusDown, rep.Status)
+	assert.Exactly(t, 3, rep.Components["db"].ConsecutiveFailures)
+
+	dbErr = nil
+	rep = agg.Check(context.Background())
+	assert.Exactly(t, health.StatusUp, rep.Status)
+	assert.Exactly(t, 0, rep.Components["db"].ConsecutiveFailures)
+
+	dbErr = errors.ConnectionFailed.Newf("connection refused")
+	rep = agg.Check(context.Background())
+	assert.Exactly(t, health.StatusDegraded, rep.Status, "counter must be reset after a successful check")
+}
+
+func TestAggregator_ServeHTTP(t *testing.T) {
+	var dbErr error
+	agg := health.NewAggregator(time.Second, map[string]health.Checker{
+		"db": health.CheckerFunc(func(ctx context.Context) error { return dbErr }),
+	})
+
+	rec := httptest.NewRecorder()
+	agg.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.Exactly(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	var rep health.Report
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rep))
+	assert.Exactly(t, health.StatusUp, rep.Components["db"].Status)
+
+	dbErr = errors.ConnectionFailed.Newf("connection refused")
+	rec = httptest.NewRecorder()
+	agg.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Exactly(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"down"`)
+	assert.Contains(t, rec.Body.String(), `"error":"connection refused"`)
+}
@@ -27,6 +27,7 @@ import (
 	"container/list"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -35,15 +36,59 @@ import (
 // the cache. Note the capacity is not the number of items, but the
 // total sum of the Size() of each item.
 type Cache struct {
+	// counters must be the first field for the 64-bit alignment of the
+	// atomic operations.
+	counters struct {
+		hits, misses, sets, deletes, evictions, expirations uint64
+	}
 	mu sync.Mutex
 
 	// list & table contain *entry objects.
 	list  *list.List
 	table map[string]*list.Element
 
-	size      int64
-	capacity  int64
-	evictions int64
+	size     int64
+	capacity int64
+	now      func() time.Time
+
+	onEvicted  func(key string, value Value)
+	onReplaced func(key string, oldValue Value)
+	// evicted and replaced collect the removed entries while holding the
+	// mutex. unlock passes them to the callbacks.
+	evicted  []Item
+	replaced []Item
+}
+
+// Stats contains a snapshot of the counters and the current state of a Cache.
+type Stats struct {
+	Hits    uint64
+	Misses  uint64
+	Sets    uint64
+	Deletes uint64
+	// Evictions counts the entries removed due to the capacity.
+	Evictions uint64
+	// Expirations counts the entries removed due to an expired TTL.
+	Expirations uint64
+	Length      int64
+	Size        int64
+	Capacity    int64
+	// Oldest contains the last access time of the least recently used entry.
+	Oldest time.Time
+}
+
+// Options configures NewWithOptions.
+type Options struct {
+	// Capacity defines the maximum total sum of the Size() of each item.
+	Capacity int64
+	// OnEvicted, if set, gets called for each entry removed due to the
+	// capacity, Delete, Clear or an expired TTL. It does not get called when
+	// Set overwrites an entry.
+	OnEvicted func(key string, value Value)
+	// OnReplaced, if set, gets called with the old value when Set overwrites
+	// an entry.
+	OnReplaced func(key string, oldValue Value)
+	// Clock returns the current time. Defaults to time.Now.
+	Clock func() time.Time
 }
 
 // Value is the interface values that go into Cache need to satisfy
@@ -64,52 +109,146 @@ type entry struct {
 	value        Value
 	size         int64
 	timeAccessed time.Time
+	// expires in Unix nano seconds. Zero means never expires.
+	expires int64
+}
+
+func (e *entry) isExpired(now int64) bool {
+	return e.expires != 0 && e.expires <= now
 }
 
 // New creates a new empty cache with the given capacity.
 func New(capacity int64) *Cache {
+	return NewWithOptions(Options{Capacity: capacity})
+}
+
+// NewWithOptions creates a new empty cache. The callbacks get called
+// synchronously after the internal mutex has been released, hence they can
+// access the cache.
+func NewWithOptions(o Options) *Cache {
+	if o.Clock == nil {
+		o.Clock = time.Now
+	}
 	return &Cache{
-		list:     list.New(),
-		table:    make(map[string]*list.Element, capacity),
-		capacity: capacity,
+		list:       list.New(),
+		table:      make(map[string]*list.Element, o.Capacity),
+		capacity:   o.Capacity,
+		now:        o.Clock,
+		onEvicted:  o.OnEvicted,
+		onReplaced: o.OnReplaced,
+	}
+}
+
+// unlock releases the mutex and afterwards calls the callbacks for the
+// entries removed or replaced while holding the mutex.
+func (lru *Cache) unlock() {
+	evicted, replaced := lru.evicted, lru.replaced
+	onEvicted, onReplaced := lru.onEvicted, lru.onReplaced
+	lru.evicted, lru.replaced = nil, nil
+	lru.mu.Unlock()
+
+	for _, itm := range evicted {
+		onEvicted(itm.Key, itm.Value)
 	}
+	for _, itm := range replaced {
+		onReplaced(itm.Key, itm.Value)
+	}
+}
+
+// SetClock replaces the function which returns the current time, mainly for
+// testing. Defaults to time.Now.
+func (lru *Cache) SetClock(now func() time.Time) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	lru.now = now
 }
 
 // Get returns a value from the cache, and marks the entry as most
-// recently used.
+// recently used. An expired entry gets removed and reported as absent.
 func (lru *Cache) Get(key string) (v Value, ok bool) {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
+	if element := lru.lookup(key); element != nil {
+		lru.moveToFront(element)
+		v, ok = element.Value.(*entry).value, true
+	}
+	lru.unlock()
 
-	element, ok := lru.table[key]
-	if element == nil {
-		return nil, false
+	if ok {
+		atomic.AddUint64(&lru.counters.hits, 1)
+	} else {
+		atomic.AddUint64(&lru.counters.misses, 1)
 	}
-	lru.moveToFront(element)
-	return element.Value.(*entry).value, true
+	return v, ok
 }
 
 // Peek returns a value from the cache without changing the LRU order.
 func (lru *Cache) Peek(key string) (v Value, ok bool) {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
+	defer lru.unlock()
 
-	element := lru.table[key]
+	element := lru.lookup(key)
 	if element == nil {
 		return nil, false
 	}
 	return element.Value.(*entry).value, true
 }
 
-// Set sets a value in the cache.
+// Contains reports whether the key exists in the cache without changing the
+// LRU order.
+func (lru *Cache) Contains(key string) bool {
+	lru.mu.Lock()
+	defer lru.unlock()
+	return lru.lookup(key) != nil
+}
+
+// Range calls fn for each entry, ordered from most recently used to last
+// recently used, until fn returns false. The keys get copied under the lock
+// and fn runs outside of it, hence fn can access the cache. Entries deleted
+// during the iteration get skipped. Range does not change the LRU order.
+func (lru *Cache) Range(fn func(key string, v Value) bool) {
+	for _, key := range lru.Keys() {
+		if v, ok := lru.Peek(key); ok && !fn(key, v) {
+			return
+		}
+	}
+}
+
+// lookup returns the element of key or nil. An expired element gets removed.
+func (lru *Cache) lookup(key string) *list.Element {
+	element := lru.table[key]
+	if element == nil {
+		return nil
+	}
+	if e := element.Value.(*entry); e.expires != 0 && e.isExpired(lru.now().UnixNano()) {
+		lru.remove(element)
+		atomic.AddUint64(&lru.counters.expirations, 1)
+		return nil
+	}
+	return element
+}
+
+// Set sets a value in the cache. The entry never expires.
 func (lru *Cache) Set(key string, value Value) {
+	lru.SetWithTTL(key, value, 0)
+}
+
+// SetWithTTL sets a value in the cache which expires after ttl. A ttl less or
+// equal zero means the entry never expires. Expired entries get removed while
+// accessing them or by calling EvictExpired.
+func (lru *Cache) SetWithTTL(key string, value Value, ttl time.Duration) {
+	atomic.AddUint64(&lru.counters.sets, 1)
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
+	defer lru.unlock()
 
-	if element := lru.table[key]; element != nil {
+	var expires int64
+	if ttl > 0 {
+		expires = lru.now().Add(ttl).UnixNano()
+	}
+	if element := lru.lookup(key); element != nil {
+		element.Value.(*entry).expires = expires
 		lru.updateInplace(element, value)
 	} else {
-		lru.addNew(key, value)
+		lru.addNew(key, value, expires)
 	}
 }
 
@@ -117,36 +256,75 @@ func (lru *Cache) Set(key string, value Value) {
 // value exists in the cache, we don't set it.
 func (lru *Cache) SetIfAbsent(key string, value Value) {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
-
-	if element := lru.table[key]; element != nil {
+	element := lru.lookup(key)
+	if element != nil {
 		lru.moveToFront(element)
 	} else {
-		lru.addNew(key, value)
+		lru.addNew(key, value, 0)
+	}
+	lru.unlock()
+
+	if element == nil {
+		atomic.AddUint64(&lru.counters.sets, 1)
 	}
 }
 
 // Delete removes an entry from the cache, and returns if the entry existed.
 func (lru *Cache) Delete(key string) bool {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
-
 	element := lru.table[key]
+	if element != nil {
+		lru.remove(element)
+	}
+	lru.unlock()
+
 	if element == nil {
 		return false
 	}
+	atomic.AddUint64(&lru.counters.deletes, 1)
+	return true
+}
+
+// EvictExpired removes all expired entries and returns their count.
+func (lru *Cache) EvictExpired() int {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	now := lru.now().UnixNano()
+	var n int
+	for element := lru.list.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*entry).isExpired(now) {
+			lru.remove(element)
+			n++
+		}
+		element = next
+	}
+	atomic.AddUint64(&lru.counters.expirations, uint64(n))
+	return n
+}
 
+func (lru *Cache) remove(element *list.Element) {
+	e := element.Value.(*entry)
 	lru.list.Remove(element)
-	delete(lru.table, key)
-	lru.size -= element.Value.(*entry).size
-	return true
+	delete(lru.table, e.key)
+	lru.size -= e.size
+	if lru.onEvicted != nil {
+		lru.evicted = append(lru.evicted, Item{Key: e.key, Value: e.value})
+	}
 }
 
 // Clear will clear the entire cache.
 func (lru *Cache) Clear() {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
+	defer lru.unlock()
 
+	if lru.onEvicted != nil {
+		for e := lru.list.Front(); e != nil; e = e.Next() {
+			v := e.Value.(*entry)
+			lru.evicted = append(lru.evicted, Item{Key: v.key, Value: v.value})
+		}
+	}
 	lru.list.Init()
 	lru.table = make(map[string]*list.Element, lru.capacity)
 	lru.size = 0
@@ -157,20 +335,44 @@ func (lru *Cache) Clear() {
 // will be shrank.
 func (lru *Cache) SetCapacity(capacity int64) {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
+	defer lru.unlock()
 
 	lru.capacity = capacity
 	lru.checkCapacity()
 }
 
-// Stats returns a few stats on the cache.
-func (lru *Cache) Stats() (length, size, capacity, evictions int64, oldest time.Time) {
+// Stats returns a snapshot of the counters and the current state. The
+// counters get updated atomically outside of the mutex, hence they might be
+// slightly ahead or behind the length and size.
+func (lru *Cache) Stats() Stats {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
+	st := Stats{
+		Length:   int64(lru.list.Len()),
+		Size:     lru.size,
+		Capacity: lru.capacity,
+	}
 	if lastElem := lru.list.Back(); lastElem != nil {
-		oldest = lastElem.Value.(*entry).timeAccessed
+		st.Oldest = lastElem.Value.(*entry).timeAccessed
 	}
-	return int64(lru.list.Len()), lru.size, lru.capacity, lru.evictions, oldest
+	lru.mu.Unlock()
+
+	st.Hits = atomic.LoadUint64(&lru.counters.hits)
+	st.Misses = atomic.LoadUint64(&lru.counters.misses)
+	st.Sets = atomic.LoadUint64(&lru.counters.sets)
+	st.Deletes = atomic.LoadUint64(&lru.counters.deletes)
+	st.Evictions = atomic.LoadUint64(&lru.counters.evictions)
+	st.Expirations = atomic.LoadUint64(&lru.counters.expirations)
+	return st
+}
+
+// ResetStats sets all counters to zero.
+func (lru *Cache) ResetStats() {
+	atomic.StoreUint64(&lru.counters.hits, 0)
+	atomic.StoreUint64(&lru.counters.misses, 0)
+	atomic.StoreUint64(&lru.counters.sets, 0)
+	atomic.StoreUint64(&lru.counters.deletes, 0)
+	atomic.StoreUint64(&lru.counters.evictions, 0)
+	atomic.StoreUint64(&lru.counters.expirations, 0)
 }
 
 // StatsJSON returns stats as a JSON object in a string.
@@ -178,8 +380,9 @@ func (lru *Cache) StatsJSON() string {
 	if lru == nil {
 		return "{}"
 	}
-	l, s, c, e, o := lru.Stats()
-	return fmt.Sprintf("{\"Length\": %v, \"Size\": %v, \"Capacity\": %v, \"Evictions\": %v, \"OldestAccess\": \"%v\"}", l, s, c, e, o)
+	st := lru.Stats()
+	return fmt.Sprintf("{\"Length\": %v, \"Size\": %v, \"Capacity\": %v, \"Evictions\": %v, \"Expirations\": %v, \"Hits\": %v, \"Misses\": %v, \"OldestAccess\": \"%v\"}",
+		st.Length, st.Size, st.Capacity, st.Evictions, st.Expirations, st.Hits, st.Misses, st.Oldest)
 }
 
 // Length returns how many elements are in the cache
@@ -205,9 +408,22 @@ func (lru *Cache) Capacity() int64 {
 
 // Evictions returns the eviction count.
 func (lru *Cache) Evictions() int64 {
+	return int64(atomic.LoadUint64(&lru.counters.evictions))
+}
+
+// OldestEntry returns the least recently used entry which gets evicted next,
+// without changing the LRU order. Expired entries get skipped. ok is false if
+// the cache is empty.
+func (lru *Cache) OldestEntry() (key string, v Value, ok bool) {
 	lru.mu.Lock()
 	defer lru.mu.Unlock()
-	return lru.evictions
+	now := lru.now().UnixNano()
+	for e := lru.list.Back(); e != nil; e = e.Prev() {
+		if en := e.Value.(*entry); !en.isExpired(now) {
+			return en.key, en.value, true
+		}
+	}
+	return "", nil, false
 }
 
 // Oldest returns the insertion time of the oldest element in the cache,
@@ -251,6 +467,9 @@ func (lru *Cache) Items() []Item {
 func (lru *Cache) updateInplace(element *list.Element, value Value) {
 	valueSize := int64(value.Size())
 	sizeDiff := valueSize - element.Value.(*entry).size
+	if lru.onReplaced != nil {
+		lru.replaced = append(lru.replaced, Item{Key: element.Value.(*entry).key, Value: element.Value.(*entry).value})
+	}
 	element.Value.(*entry).value = value
 	element.Value.(*entry).size = valueSize
 	lru.size += sizeDiff
@@ -260,11 +479,11 @@ func (lru *Cache) updateInplace(element *list.Element, value Value) {
 
 func (lru *Cache) moveToFront(element *list.Element) {
 	lru.list.MoveToFront(element)
-	element.Value.(*entry).timeAccessed = time.Now()
+	element.Value.(*entry).timeAccessed = lru.now()
 }
 
-func (lru *Cache) addNew(key string, value Value) {
-	newEntry := &entry{key, value, int64(value.Size()), time.Now()}
+func (lru *Cache) addNew(key string, value Value, expires int64) {
+	newEntry := &entry{key, value, int64(value.Size()), lru.now(), expires}
 	element := lru.list.PushFront(newEntry)
 	lru.table[key] = element
 	lru.size += newEntry.size
@@ -272,14 +491,8 @@ func (lru *Cache) addNew(key string, value Value) {
 }
 
 func (lru *Cache) checkCapacity() {
-	// Partially duplicated from Delete
 	for lru.size > lru.capacity {
-		delElem := lru.list.Back()
-		delValue := delElem.Value.(*entry)
-		lru.list.Remove(delElem)
-
-		delete(lru.table, delValue.key)
-		lru.size -= delValue.size
-		lru.evictions++
+		lru.remove(lru.list.Back())
+		atomic.AddUint64(&lru.counters.evictions, 1)
 	}
 }
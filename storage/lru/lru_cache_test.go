@@ -18,6 +18,10 @@ package lru
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -32,18 +36,18 @@ func (cv *CacheValue) Size() int {
 
 func TestInitialState(t *testing.T) {
 	cache := New(5)
-	l, sz, c, e, _ := cache.Stats()
-	if l != 0 {
-		t.Errorf("length = %v, want 0", l)
+	st := cache.Stats()
+	if st.Length != 0 {
+		t.Errorf("length = %v, want 0", st.Length)
 	}
-	if sz != 0 {
-		t.Errorf("size = %v, want 0", sz)
+	if st.Size != 0 {
+		t.Errorf("size = %v, want 0", st.Size)
 	}
-	if c != 5 {
-		t.Errorf("capacity = %v, want 5", c)
+	if st.Capacity != 5 {
+		t.Errorf("capacity = %v, want 5", st.Capacity)
 	}
-	if e != 0 {
-		t.Errorf("evictions = %v, want 0", c)
+	if st.Evictions != 0 {
+		t.Errorf("evictions = %v, want 0", st.Evictions)
 	}
 }
 
@@ -109,13 +113,13 @@ func TestSetUpdatesSize(t *testing.T) {
 	emptyValue := &CacheValue{0}
 	key := "key1"
 	cache.Set(key, emptyValue)
-	if _, sz, _, _, _ := cache.Stats(); sz != 0 {
+	if sz := cache.Stats().Size; sz != 0 {
 		t.Errorf("cache.Size() = %v, expected 0", sz)
 	}
 	someValue := &CacheValue{20}
 	key = "key2"
 	cache.Set(key, someValue)
-	if _, sz, _, _, _ := cache.Stats(); sz != 20 {
+	if sz := cache.Stats().Size; sz != 20 {
 		t.Errorf("cache.Size() = %v, expected 20", sz)
 	}
 }
@@ -140,14 +144,14 @@ func TestSetWithOldKeyUpdatesSize(t *testing.T) {
 	key := "key1"
 	cache.Set(key, emptyValue)
 
-	if _, sz, _, _, _ := cache.Stats(); sz != 0 {
+	if sz := cache.Stats().Size; sz != 0 {
 		t.Errorf("cache.Size() = %v, expected %v", sz, 0)
 	}
 
 	someValue := &CacheValue{20}
 	cache.Set(key, someValue)
 	expected := int64(someValue.size)
-	if _, sz, _, _, _ := cache.Stats(); sz != expected {
+	if sz := cache.Stats().Size; sz != expected {
 		t.Errorf("cache.Size() = %v, expected %v", sz, expected)
 	}
 }
@@ -194,7 +198,7 @@ func TestDelete(t *testing.T) {
 		t.Error("Expected item to be in cache.")
 	}
 
-	if _, sz, _, _, _ := cache.Stats(); sz != 0 {
+	if sz := cache.Stats().Size; sz != 0 {
 		t.Errorf("cache.Size() = %v, expected 0", sz)
 	}
 
@@ -211,7 +215,7 @@ func TestClear(t *testing.T) {
 	cache.Set(key, value)
 	cache.Clear()
 
-	if _, sz, _, _, _ := cache.Stats(); sz != 0 {
+	if sz := cache.Stats().Size; sz != 0 {
 		t.Errorf("cache.Size() = %v, expected 0 after Clear()", sz)
 	}
 }
@@ -226,17 +230,17 @@ func TestCapacityIsObeyed(t *testing.T) {
 	cache.Set("key1", value)
 	cache.Set("key2", value)
 	cache.Set("key3", value)
-	if _, sz, _, _, _ := cache.Stats(); sz != size {
+	if sz := cache.Stats().Size; sz != size {
 		t.Errorf("cache.Size() = %v, expected %v", sz, size)
 	}
 	// Insert one more; something should be evicted to make room.
 	cache.Set("key4", value)
-	_, sz, _, evictions, _ := cache.Stats()
-	if sz != size {
-		t.Errorf("post-evict cache.Size() = %v, expected %v", sz, size)
+	st := cache.Stats()
+	if st.Size != size {
+		t.Errorf("post-evict cache.Size() = %v, expected %v", st.Size, size)
 	}
-	if evictions != 1 {
-		t.Errorf("post-evict cache.evictions = %v, expected 1", evictions)
+	if st.Evictions != 1 {
+		t.Errorf("post-evict cache.evictions = %v, expected 1", st.Evictions)
 	}
 
 	// Check json stats
@@ -301,3 +305,268 @@ func TestLRUIsEvicted(t *testing.T) {
 		t.Errorf("evictions: %d, want: %d", e, want)
 	}
 }
+
+func TestPeekContainsRangeKeepOrder(t *testing.T) {
+	cache := New(3)
+	cache.Set("key1", &CacheValue{1})
+	cache.Set("key2", &CacheValue{1})
+	cache.Set("key3", &CacheValue{1})
+	// lru: [key3, key2, key1]
+
+	if _, ok := cache.Peek("key1"); !ok {
+		t.Error("key1 not found")
+	}
+	if !cache.Contains("key1") || cache.Contains("missing") {
+		t.Error("Contains reports the wrong existence")
+	}
+	var keys []string
+	cache.Range(func(key string, v Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if have, want := fmt.Sprint(keys), "[key3 key2 key1]"; have != want {
+		t.Errorf("Range keys: %s, want %s", have, want)
+	}
+	if key, _, ok := cache.OldestEntry(); !ok || key != "key1" {
+		t.Errorf("cache.OldestEntry() = %q, %t, want key1", key, ok)
+	}
+
+	cache.Set("key4", &CacheValue{1})
+	if cache.Contains("key1") {
+		t.Error("key1 should have been evicted first")
+	}
+	if s := cache.Stats(); s.Hits != 0 || s.Misses != 0 {
+		t.Errorf("Peek, Contains and Range must not count hits or misses: %+v", s)
+	}
+}
+
+func TestRangeModify(t *testing.T) {
+	cache := New(10)
+	for i := 0; i < 5; i++ {
+		cache.Set("key"+strconv.Itoa(i), &CacheValue{1})
+	}
+	var keys []string
+	cache.Range(func(key string, v Value) bool {
+		keys = append(keys, key)
+		cache.Delete("key2") // fn runs outside of the lock
+		cache.Set("key9", &CacheValue{1})
+		return key != "key1"
+	})
+	if have, want := fmt.Sprint(keys), "[key4 key3 key1]"; have != want {
+		t.Errorf("Range keys: %s, want %s", have, want)
+	}
+	if _, _, ok := New(1).OldestEntry(); ok {
+		t.Error("OldestEntry of an empty cache should return false")
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time { return fc.now }
+
+func TestSetWithTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1500000000, 0)}
+	cache := New(100)
+	cache.SetClock(clock.Now)
+
+	cache.SetWithTTL("short", &CacheValue{10}, time.Second)
+	cache.SetWithTTL("long", &CacheValue{20}, time.Hour)
+	cache.Set("forever", &CacheValue{30})
+
+	if _, ok := cache.Get("short"); !ok {
+		t.Error("short should not be expired")
+	}
+	clock.now = clock.now.Add(2 * time.Second)
+
+	if v, ok := cache.Peek("short"); ok {
+		t.Errorf("short received: %v, want absent", v)
+	}
+	if _, ok := cache.Get("short"); ok {
+		t.Error("short should be expired")
+	}
+	if sz := cache.Size(); sz != 50 {
+		t.Errorf("cache.Size() = %v, expected 50", sz)
+	}
+	if _, ok := cache.Get("long"); !ok {
+		t.Error("long should not be expired")
+	}
+
+	// Set resets the TTL to infinite lifetime.
+	cache.Set("long", &CacheValue{25})
+	clock.now = clock.now.Add(2 * time.Hour)
+	if _, ok := cache.Get("long"); !ok {
+		t.Error("long should not expire after Set")
+	}
+	if _, ok := cache.Get("forever"); !ok {
+		t.Error("forever should never expire")
+	}
+}
+
+func TestStats(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1500000000, 0)}
+	cache := New(2)
+	cache.SetClock(clock.Now)
+
+	cache.Set("key1", &CacheValue{1})
+	cache.SetWithTTL("key2", &CacheValue{1}, time.Second)
+	cache.SetIfAbsent("key1", &CacheValue{1}) // no set
+	cache.Get("key1")
+	cache.Get("missing")
+	cache.Peek("key1") // not counted
+	clock.now = clock.now.Add(2 * time.Second)
+	cache.Get("key2") // expired, miss
+	cache.Set("key3", &CacheValue{1})
+	cache.Set("key4", &CacheValue{1}) // evicts key1
+	cache.Delete("key3")
+	cache.Delete("key3") // no delete
+
+	want := Stats{
+		Hits: 1, Misses: 2, Sets: 4, Deletes: 1, Evictions: 1, Expirations: 1,
+		Length: 1, Size: 1, Capacity: 2, Oldest: clock.now,
+	}
+	have := cache.Stats()
+	if have != want {
+		t.Errorf("cache.Stats()\nhave %+v\nwant %+v", have, want)
+	}
+
+	cache.ResetStats()
+	want = Stats{Length: 1, Size: 1, Capacity: 2, Oldest: clock.now}
+	have = cache.Stats()
+	if have != want {
+		t.Errorf("cache.Stats() after reset\nhave %+v\nwant %+v", have, want)
+	}
+}
+
+func TestStatsConcurrent(t *testing.T) {
+	cache := NewSharded(1000, 4)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				key := "key" + strconv.Itoa(g) + "_" + strconv.Itoa(i)
+				cache.Set(key, &CacheValue{1})
+				cache.Get(key)
+				cache.Get(key + "_missing")
+				_ = cache.Stats()
+			}
+		}(g)
+	}
+	wg.Wait()
+	st := cache.Stats()
+	if st.Hits != 800 || st.Misses != 800 || st.Sets != 800 || st.Length != 800 {
+		t.Errorf("cache.Stats() = %+v, want 800 hits, misses, sets and length", st)
+	}
+}
+
+func TestEvictExpired(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1500000000, 0)}
+	cache := New(100)
+	cache.SetClock(clock.Now)
+
+	cache.SetWithTTL("key1", &CacheValue{10}, time.Second)
+	cache.SetWithTTL("key2", &CacheValue{20}, time.Second)
+	cache.SetWithTTL("key3", &CacheValue{30}, time.Minute)
+	cache.Set("key4", &CacheValue{40})
+
+	if n := cache.EvictExpired(); n != 0 {
+		t.Errorf("EvictExpired() = %v, expected 0", n)
+	}
+	clock.now = clock.now.Add(time.Second)
+	if n := cache.EvictExpired(); n != 2 {
+		t.Errorf("EvictExpired() = %v, expected 2", n)
+	}
+	if l, sz := cache.Length(), cache.Size(); l != 2 || sz != 70 {
+		t.Errorf("cache.Length() = %v, cache.Size() = %v, expected 2 and 70", l, sz)
+	}
+
+	// An expired entry counts as absent.
+	clock.now = clock.now.Add(time.Minute)
+	cache.SetIfAbsent("key3", &CacheValue{5})
+	if v, ok := cache.Get("key3"); !ok || v.(*CacheValue).size != 5 {
+		t.Errorf("key3 received: %v, want new value", v)
+	}
+	if sz := cache.Size(); sz != 45 {
+		t.Errorf("cache.Size() = %v, expected 45", sz)
+	}
+}
+
+func TestOnEvicted(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1500000000, 0)}
+	var evicted, replaced []string
+	cache := NewWithOptions(Options{
+		Capacity: 3,
+		OnEvicted: func(key string, value Value) {
+			evicted = append(evicted, key)
+		},
+		Clock: clock.Now,
+	})
+
+	cache.Set("key1", &CacheValue{1})
+	cache.Set("key2", &CacheValue{1})
+	cache.Set("key3", &CacheValue{1})
+	cache.Set("key3", &CacheValue{1}) // overwrite
+	cache.Set("key4", &CacheValue{1}) // evicts key1
+	cache.Delete("key2")
+	cache.SetWithTTL("key5", &CacheValue{1}, time.Second)
+	clock.now = clock.now.Add(time.Second)
+	cache.Get("key5")
+	cache.Clear()
+
+	want := "[key1 key2 key5 key4 key3]"
+	if have := fmt.Sprint(evicted); have != want {
+		t.Errorf("evicted keys: %s, want %s", have, want)
+	}
+
+	cache = NewWithOptions(Options{
+		Capacity: 3,
+		OnReplaced: func(key string, oldValue Value) {
+			replaced = append(replaced, fmt.Sprintf("%s:%d", key, oldValue.(*CacheValue).size))
+		},
+	})
+	cache.Set("key1", &CacheValue{1})
+	cache.Set("key1", &CacheValue{2})
+	cache.Delete("key1")
+	if have, want := fmt.Sprint(replaced), "[key1:1]"; have != want {
+		t.Errorf("replaced keys: %s, want %s", have, want)
+	}
+}
+
+func TestOnEvictedReentrant(t *testing.T) {
+	var cache *Cache
+	cache = NewWithOptions(Options{
+		Capacity: 10,
+		OnEvicted: func(key string, value Value) {
+			// Write back to another tier, here the cache itself.
+			if !strings.HasPrefix(key, "evicted_") {
+				cache.Set("evicted_"+key, value)
+			}
+		},
+		OnReplaced: func(key string, oldValue Value) {
+			cache.Get(key)
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Set("key1", &CacheValue{1})
+		cache.Set("key1", &CacheValue{1})
+		cache.Set("key2", &CacheValue{1})
+		cache.Delete("key2")
+		cache.Clear()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock: callback accessing the cache blocked")
+	}
+	// Clear has removed evicted_key2 and written back key1.
+	want := "[evicted_key1]"
+	if have := fmt.Sprint(cache.Keys()); have != want {
+		t.Errorf("keys: %s, want %s", have, want)
+	}
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package lru
 
 import (
+	"runtime"
+	"strconv"
 	"testing"
+	"time"
 )
 
 type MyValue []byte
@@ -33,6 +36,23 @@ func BenchmarkGet(b *testing.B) {
 	value2 := make(MyValue, 1100)
 	cache.Set("stuff2", value2)
 
+	cache.SetWithTTL("stuff4", value, time.Hour)
+
+	b.Run("serial one key", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, ok := cache.Get("stuff1"); !ok {
+				b.Fatal("error")
+			}
+		}
+	})
+	b.Run("serial one key with TTL", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, ok := cache.Get("stuff4"); !ok {
+				b.Fatal("error")
+			}
+		}
+	})
+
 	b.Run("one key", func(b *testing.B) {
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
@@ -83,3 +103,44 @@ func BenchmarkGet(b *testing.B) {
 		})
 	})
 }
+
+// BenchmarkParallel_32Goroutines compares the single lock cache with the
+// sharded cache.
+func BenchmarkParallel_32Goroutines(b *testing.B) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	value := make(MyValue, 10)
+	parallelism := 32 / runtime.GOMAXPROCS(0)
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	run := func(get func(string) (Value, bool), set func(string, Value)) func(b *testing.B) {
+		return func(b *testing.B) {
+			for _, k := range keys {
+				set(k, value)
+			}
+			b.SetParallelism(parallelism)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					k := keys[i%len(keys)]
+					if i%8 == 0 {
+						set(k, value)
+					} else if _, ok := get(k); !ok {
+						panic("error")
+					}
+					i++
+				}
+			})
+		}
+	}
+
+	single := New(1 << 16)
+	b.Run("single lock", run(single.Get, single.Set))
+	sharded := NewSharded(1<<16, 32)
+	b.Run("sharded 32", run(sharded.Get, sharded.Set))
+}
@@ -0,0 +1,205 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import "time"
+
+// Sharded splits the keyspace over several independent caches by the FNV-1a
+// hash of the key, which reduces the mutex contention. Each shard has its own
+// LRU order and the capacity divided by the number of shards, hence the least
+// recently used entry gets evicted per shard and not globally. Methods
+// aggregating all shards, like Length, Size, Keys and Clear, lock one shard
+// after another and do not provide a consistent snapshot. Good enough for
+// monitoring.
+type Sharded struct {
+	shards []*Cache
+	mask   uint32
+}
+
+// NewSharded creates a new sharded cache. The number of shards gets rounded up
+// to the next power of two, default 16.
+func NewSharded(capacity int64, shards int) *Sharded {
+	return NewShardedWithOptions(Options{Capacity: capacity}, shards)
+}
+
+// NewShardedWithOptions creates a new sharded cache. Each shard receives the
+// options with the capacity divided by the number of shards.
+func NewShardedWithOptions(o Options, shards int) *Sharded {
+	if shards < 1 {
+		shards = 16
+	}
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+	capacity := o.Capacity
+	o.Capacity = capacity / int64(n)
+	if o.Capacity < 1 && capacity > 0 {
+		o.Capacity = 1
+	}
+	s := &Sharded{
+		shards: make([]*Cache, n),
+		mask:   uint32(n - 1),
+	}
+	for i := range s.shards {
+		s.shards[i] = NewWithOptions(o)
+	}
+	return s
+}
+
+func (s *Sharded) shard(key string) *Cache {
+	// inlined FNV-1a to avoid allocations
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return s.shards[h&s.mask]
+}
+
+// Get returns a value from the cache, and marks the entry as most recently
+// used in its shard.
+func (s *Sharded) Get(key string) (v Value, ok bool) { return s.shard(key).Get(key) }
+
+// Peek returns a value from the cache without changing the LRU order.
+func (s *Sharded) Peek(key string) (v Value, ok bool) { return s.shard(key).Peek(key) }
+
+// Contains reports whether the key exists without changing the LRU order.
+func (s *Sharded) Contains(key string) bool { return s.shard(key).Contains(key) }
+
+// Range calls fn for each entry of all shards until fn returns false. The
+// entries are ordered from most recently used to last recently used only
+// within a shard. Range does not change the LRU order.
+func (s *Sharded) Range(fn func(key string, v Value) bool) {
+	for _, c := range s.shards {
+		stop := false
+		c.Range(func(key string, v Value) bool {
+			stop = !fn(key, v)
+			return !stop
+		})
+		if stop {
+			return
+		}
+	}
+}
+
+// Set sets a value in the cache. The entry never expires.
+func (s *Sharded) Set(key string, value Value) { s.shard(key).Set(key, value) }
+
+// SetWithTTL sets a value in the cache which expires after ttl.
+func (s *Sharded) SetWithTTL(key string, value Value, ttl time.Duration) {
+	s.shard(key).SetWithTTL(key, value, ttl)
+}
+
+// SetIfAbsent sets the value in the cache if not present.
+func (s *Sharded) SetIfAbsent(key string, value Value) { s.shard(key).SetIfAbsent(key, value) }
+
+// Delete removes an entry from the cache, and returns if the entry existed.
+func (s *Sharded) Delete(key string) bool { return s.shard(key).Delete(key) }
+
+// EvictExpired removes all expired entries of all shards and returns their
+// count.
+func (s *Sharded) EvictExpired() (n int) {
+	for _, c := range s.shards {
+		n += c.EvictExpired()
+	}
+	return n
+}
+
+// Clear clears all shards.
+func (s *Sharded) Clear() {
+	for _, c := range s.shards {
+		c.Clear()
+	}
+}
+
+// SetClock replaces the function which returns the current time in all shards.
+func (s *Sharded) SetClock(now func() time.Time) {
+	for _, c := range s.shards {
+		c.SetClock(now)
+	}
+}
+
+// Length returns how many elements are in all shards.
+func (s *Sharded) Length() (n int64) {
+	for _, c := range s.shards {
+		n += c.Length()
+	}
+	return n
+}
+
+// Size returns the sum of the objects' Size() method of all shards.
+func (s *Sharded) Size() (n int64) {
+	for _, c := range s.shards {
+		n += c.Size()
+	}
+	return n
+}
+
+// Capacity returns the sum of the capacities of all shards.
+func (s *Sharded) Capacity() (n int64) {
+	for _, c := range s.shards {
+		n += c.Capacity()
+	}
+	return n
+}
+
+// Keys returns the keys of all shards. The keys are ordered from most recently
+// used to last recently used only within a shard.
+func (s *Sharded) Keys() []string {
+	var keys []string
+	for _, c := range s.shards {
+		keys = append(keys, c.Keys()...)
+	}
+	return keys
+}
+
+// Items returns the items of all shards. The items are ordered from most
+// recently used to last recently used only within a shard.
+func (s *Sharded) Items() []Item {
+	var items []Item
+	for _, c := range s.shards {
+		items = append(items, c.Items()...)
+	}
+	return items
+}
+
+// Stats returns the sum of the stats of all shards. Oldest contains the
+// oldest access time of all shards.
+func (s *Sharded) Stats() (st Stats) {
+	for _, c := range s.shards {
+		cs := c.Stats()
+		st.Hits += cs.Hits
+		st.Misses += cs.Misses
+		st.Sets += cs.Sets
+		st.Deletes += cs.Deletes
+		st.Evictions += cs.Evictions
+		st.Expirations += cs.Expirations
+		st.Length += cs.Length
+		st.Size += cs.Size
+		st.Capacity += cs.Capacity
+		if !cs.Oldest.IsZero() && (st.Oldest.IsZero() || cs.Oldest.Before(st.Oldest)) {
+			st.Oldest = cs.Oldest
+		}
+	}
+	return st
+}
+
+// ResetStats sets the counters of all shards to zero.
+func (s *Sharded) ResetStats() {
+	for _, c := range s.shards {
+		c.ResetStats()
+	}
+}
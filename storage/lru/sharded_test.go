@@ -0,0 +1,85 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestNewSharded(t *testing.T) {
+	cache := NewSharded(100, 5)
+	if l := len(cache.shards); l != 8 {
+		t.Errorf("shards = %v, want 8", l)
+	}
+	if c := cache.Capacity(); c != 96 {
+		t.Errorf("cache.Capacity() = %v, want 96", c)
+	}
+
+	for i := 0; i < 50; i++ {
+		cache.Set("key"+strconv.Itoa(i), &CacheValue{1})
+	}
+	if l, sz := cache.Length(), cache.Size(); l != 50 || sz != 50 {
+		t.Errorf("cache.Length() = %v, cache.Size() = %v, want 50", l, sz)
+	}
+	if k := cache.Keys(); len(k) != 50 {
+		t.Errorf("cache.Keys() returned %d keys, want 50", len(k))
+	}
+	for i := 0; i < 50; i++ {
+		if _, ok := cache.Get("key" + strconv.Itoa(i)); !ok {
+			t.Errorf("key%d not found", i)
+		}
+	}
+	if !cache.Delete("key1") || cache.Delete("key1") {
+		t.Error("Delete should report the existence of key1 only once")
+	}
+	cache.Clear()
+	if l, sz := cache.Length(), cache.Size(); l != 0 || sz != 0 {
+		t.Errorf("cache.Length() = %v, cache.Size() = %v after Clear, want 0", l, sz)
+	}
+}
+
+func TestShardedCapacityIsObeyed(t *testing.T) {
+	cache := NewSharded(64, 4)
+	for i := 0; i < 1000; i++ {
+		cache.Set("key"+strconv.Itoa(i), &CacheValue{1})
+	}
+	for i, c := range cache.shards {
+		if sz := c.Size(); sz > 16 {
+			t.Errorf("shard %d has size %d, want <= 16", i, sz)
+		}
+	}
+	if sz := cache.Size(); sz > 64 {
+		t.Errorf("cache.Size() = %v, want <= 64", sz)
+	}
+}
+
+func TestShardedRange(t *testing.T) {
+	cache := NewSharded(100, 4)
+	for i := 0; i < 20; i++ {
+		cache.Set("key"+strconv.Itoa(i), &CacheValue{1})
+	}
+	n := 0
+	cache.Range(func(key string, v Value) bool {
+		if !cache.Contains(key) {
+			t.Errorf("Range returned unknown key %q", key)
+		}
+		n++
+		return n < 15
+	})
+	if n != 15 {
+		t.Errorf("Range called fn %d times, want 15", n)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package null
+
+import (
+	"github.com/corestoreio/errors"
+)
+
+// The binary encoding of all null types, used for example by encoding/gob,
+// starts with a header of two bytes: the version of the format and the
+// validity byte 0 for NULL or 1 for a valid value. The value follows in its
+// binary form: big endian for numbers and fixed size types, raw bytes for
+// strings. A NULL type with a fixed size writes zero bytes for its value. The
+// protobuf encoding stays available via Marshal and Unmarshal.
+const (
+	binaryVersion01 byte = 1
+	binaryHeaderLen      = 2
+)
+
+// marshalBinaryHeader allocates the binary form of a null type with the
+// header and size bytes for the value.
+func marshalBinaryHeader(valid bool, size int) []byte {
+	data := make([]byte, binaryHeaderLen+size)
+	data[0] = binaryVersion01
+	if valid {
+		data[1] = 1
+	}
+	return data
+}
+
+// unmarshalBinaryHeader validates the header and returns the binary form of
+// the value. A negative size allows values of any length, but then a NULL must
+// not have a value. Empty data represents NULL.
+func unmarshalBinaryHeader(typeName string, data []byte, size int) (value []byte, valid bool, err error) {
+	if len(data) == 0 {
+		return nil, false, nil
+	}
+	if data[0] != binaryVersion01 {
+		return nil, false, errors.NotSupported.Newf("[null] %s.UnmarshalBinary: Unknown binary version %d", typeName, data[0])
+	}
+	if len(data) < binaryHeaderLen || data[1] > 1 {
+		return nil, false, errors.NotValid.Newf("[null] %s.UnmarshalBinary: Invalid validity byte in %x", typeName, data)
+	}
+	value, valid = data[binaryHeaderLen:], data[1] == 1
+	if (size >= 0 && len(value) != size) || (size < 0 && !valid && len(value) > 0) {
+		return nil, false, errors.NotValid.Newf("[null] %s.UnmarshalBinary: Invalid length %d of data %x", typeName, len(data), data)
+	}
+	return value, valid, nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package null
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/gob"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestBinaryEncoding(t *testing.T) {
+	runner := func(v encoding.BinaryMarshaler, want []byte) func(*testing.T) {
+		return func(t *testing.T) {
+			data, err := v.MarshalBinary()
+			assert.NoError(t, err)
+			assert.Exactly(t, want, data, "%x", data)
+
+			decoded := reflect.New(reflect.TypeOf(v))
+			assert.NoError(t, decoded.Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(data))
+			assert.Exactly(t, v, decoded.Elem().Interface())
+		}
+	}
+	t.Run("Bool", runner(MakeBool(true), []byte{1, 1, 1}))
+	t.Run("Bool false", runner(MakeBool(false), []byte{1, 1, 0}))
+	t.Run("Bool null", runner(Bool{}, []byte{1, 0, 0}))
+	t.Run("Float64", runner(MakeFloat64(-2.5), []byte{1, 1, 0xc0, 0x04, 0, 0, 0, 0, 0, 0}))
+	t.Run("Float64 null", runner(Float64{}, []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0}))
+	t.Run("Int8", runner(MakeInt8(math.MinInt8), []byte{1, 1, 0x80}))
+	t.Run("Int16", runner(MakeInt16(-2), []byte{1, 1, 0xff, 0xfe}))
+	t.Run("Int32", runner(MakeInt32(math.MaxInt32), []byte{1, 1, 0x7f, 0xff, 0xff, 0xff}))
+	t.Run("Int64", runner(MakeInt64(258), []byte{1, 1, 0, 0, 0, 0, 0, 0, 1, 2}))
+	t.Run("Int64 null", runner(Int64{}, []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0}))
+	t.Run("Uint8", runner(MakeUint8(math.MaxUint8), []byte{1, 1, 0xff}))
+	t.Run("Uint16", runner(MakeUint16(258), []byte{1, 1, 1, 2}))
+	t.Run("Uint32", runner(MakeUint32(1), []byte{1, 1, 0, 0, 0, 1}))
+	t.Run("Uint64", runner(MakeUint64(math.MaxUint64), []byte{1, 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}))
+	t.Run("String", runner(MakeString("Gö"), []byte{1, 1, 'G', 0xc3, 0xb6}))
+	t.Run("String empty", runner(MakeString(""), []byte{1, 1}))
+	t.Run("String null", runner(String{}, []byte{1, 0}))
+	t.Run("Decimal", runner(Decimal{Precision: 123456, Scale: 2, Negative: true, Valid: true, Quote: true}, []byte("\x01\x01\x03\x00\x00\x00\x02123456")))
+	t.Run("Decimal trailing zeros", runner(MakeDecimalInt64(471100, 2), []byte("\x01\x01\x00\x00\x00\x00\x02471100")))
+	t.Run("Decimal large", runner(Decimal{PrecisionStr: "123456789012345678901234567890", Valid: true}, []byte("\x01\x01\x00\x00\x00\x00\x00123456789012345678901234567890")))
+	t.Run("Decimal null", runner(Decimal{}, []byte{1, 0}))
+	t.Run("Time UTC", runner(MakeTime(time.Unix(1, 999999999).UTC()),
+		[]byte{1, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0x3b, 0x9a, 0xc9, 0xff, 0xff, 0xff}))
+	t.Run("Time zone", func(t *testing.T) {
+		tm := time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.FixedZone("IST", 5*3600+30*60))
+		nt := MakeTime(tm)
+		data, err := nt.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Len(t, data, binaryHeaderLen+timeBinaryLen)
+		assert.Exactly(t, []byte{0x01, 0x4a}, data[14:], "offset 330 minutes")
+
+		var decoded Time
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.True(t, decoded.Time.Equal(tm), "%s", decoded.Time)
+		_, offset := decoded.Time.Zone()
+		assert.Exactly(t, 5*3600+30*60, offset)
+	})
+	t.Run("Time null", runner(Time{}, append([]byte{1, 0}, make([]byte, timeBinaryLen)...)))
+}
+
+func TestBinaryDecoding_Errors(t *testing.T) {
+	t.Run("empty data is null", func(t *testing.T) {
+		i := MakeInt64(4711)
+		assert.NoError(t, i.UnmarshalBinary(nil))
+		assert.Exactly(t, Int64{}, i)
+	})
+	t.Run("unknown version", func(t *testing.T) {
+		var i Int16
+		assert.ErrorIsKind(t, errors.NotSupported, i.UnmarshalBinary([]byte{2, 1, 0, 1}))
+	})
+	t.Run("invalid validity byte", func(t *testing.T) {
+		var b Bool
+		assert.ErrorIsKind(t, errors.NotValid, b.UnmarshalBinary([]byte{1, 2, 1}))
+		assert.ErrorIsKind(t, errors.NotValid, b.UnmarshalBinary([]byte{1}))
+	})
+	t.Run("invalid length", func(t *testing.T) {
+		var i Int32
+		assert.ErrorIsKind(t, errors.NotValid, i.UnmarshalBinary([]byte{1, 1, 0, 1}))
+		var s String
+		assert.ErrorIsKind(t, errors.NotValid, s.UnmarshalBinary([]byte{1, 0, 'a'}))
+		var d Decimal
+		assert.ErrorIsKind(t, errors.NotValid, d.UnmarshalBinary([]byte{1, 1, 0}))
+		assert.ErrorIsKind(t, errors.NotValid, d.UnmarshalBinary([]byte("\x01\x01\x00\x00\x00\x00\x001x")))
+		var tm Time
+		assert.ErrorIsKind(t, errors.NotValid, tm.UnmarshalBinary([]byte{1, 1, 0, 0}))
+	})
+	t.Run("fractional minute zone offset", func(t *testing.T) {
+		_, err := MakeTime(time.Date(1900, 1, 1, 0, 0, 0, 0, time.FixedZone("LMT", 3208))).MarshalBinary()
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+}
+
+type allNullTypes struct {
+	Bool    Bool
+	Decimal Decimal
+	Float64 Float64
+	Int8    Int8
+	Int16   Int16
+	Int32   Int32
+	Int64   Int64
+	String  String
+	Time    Time
+	Uint8   Uint8
+	Uint16  Uint16
+	Uint32  Uint32
+	Uint64  Uint64
+}
+
+func makeAllNullTypes() allNullTypes {
+	return allNullTypes{
+		Bool:    MakeBool(true),
+		Decimal: MakeDecimalInt64(-471123, 2),
+		Float64: MakeFloat64(math.Pi),
+		Int8:    MakeInt8(math.MinInt8),
+		Int16:   MakeInt16(math.MinInt16),
+		Int32:   MakeInt32(math.MinInt32),
+		Int64:   MakeInt64(math.MinInt64),
+		String:  MakeString("Gopher"),
+		Time:    MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.UTC)),
+		Uint8:   MakeUint8(math.MaxUint8),
+		Uint16:  MakeUint16(math.MaxUint16),
+		Uint32:  MakeUint32(math.MaxUint32),
+		Uint64:  MakeUint64(math.MaxUint64),
+	}
+}
+
+func TestGob_AllTypes(t *testing.T) {
+	runner := func(want allNullTypes) func(*testing.T) {
+		return func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, gob.NewEncoder(&buf).Encode(want))
+
+			var have allNullTypes
+			assert.NoError(t, gob.NewDecoder(&buf).Decode(&have))
+			assert.Exactly(t, want, have)
+		}
+	}
+	t.Run("valid", runner(makeAllNullTypes()))
+	t.Run("null", runner(allNullTypes{}))
+}
+
+func TestCSV_AllTypes(t *testing.T) {
+	marshalRecord := func(v allNullTypes) (record []string) {
+		rv := reflect.ValueOf(v)
+		for i := 0; i < rv.NumField(); i++ {
+			text, err := rv.Field(i).Interface().(encoding.TextMarshaler).MarshalText()
+			assert.NoError(t, err)
+			record = append(record, string(text))
+		}
+		return record
+	}
+	unmarshalRecord := func(record []string) (v allNullTypes) {
+		rv := reflect.ValueOf(&v).Elem()
+		for i, text := range record {
+			assert.NoError(t, rv.Field(i).Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)))
+		}
+		return v
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	assert.NoError(t, w.Write(marshalRecord(makeAllNullTypes())))
+	assert.NoError(t, w.Write(marshalRecord(allNullTypes{})))
+	w.Flush()
+	assert.NoError(t, w.Error())
+	assert.Exactly(t,
+		"true,-4711.23,3.141592653589793,-128,-32768,-2147483648,-9223372036854775808,Gopher,2021-03-04T05:06:07.123456789Z,255,65535,4294967295,18446744073709551615\n"+
+			",,,,,,,,,,,,\n",
+		buf.String())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Exactly(t, makeAllNullTypes(), unmarshalRecord(records[0]))
+	assert.Exactly(t, allNullTypes{}, unmarshalRecord(records[1]))
+}
@@ -170,44 +170,27 @@ func (a Bool) MarshalText() ([]byte, error) {
 	return bTextTrueLC, nil
 }
 
-// SetValid changes this Bool's value and also sets it to be non-null.
-func (a *Bool) SetValid(v bool) { a.Bool = v; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Bool) Reset() { *a = Bool{} }
-
-// Ptr returns a pointer to this Bool's value, or a nil pointer if this
-// Bool is null.
-func (a Bool) Ptr() *bool {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Bool
-}
-
-// SetPtr sets v according to the rules.
-func (a *Bool) SetPtr(v *bool) {
-	a.Valid = v != nil
-	a.Bool = false
-	if v != nil {
-		a.Bool = *v
-	}
-}
-
-// IsZero returns true for invalid Bools, for future omitempty support (Go 1.4?)
-// A non-null Bool with a 0 value will not be considered zero.
-func (a Bool) IsZero() bool {
-	return !a.Valid
-}
-
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Bool) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Bool", data, 1)
+	if err != nil {
+		return err
+	}
+	*a = Bool{}
+	if valid {
+		a.SetValid(v[0] == 1)
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written with 1 byte after the version and validity byte.
 func (a Bool) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 1)
+	if a.Valid && a.Bool {
+		data[binaryHeaderLen] = 1
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
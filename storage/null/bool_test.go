@@ -149,18 +149,15 @@ func TestNullBool_MarshalText(t *testing.T) {
 	assertJSONEquals(t, data, "", "null text marshal")
 }
 
-func TestNullBool_BinaryEncoding(t *testing.T) {
+func TestNullBool_ProtoEncoding(t *testing.T) {
 	runner := func(b Bool, want []byte) func(*testing.T) {
 		return func(t *testing.T) {
-			data, err := b.MarshalBinary()
-			assert.NoError(t, err)
-			assert.Exactly(t, want, data, "MarshalBinary")
-			data, err = b.Marshal()
+			data, err := b.Marshal()
 			assert.NoError(t, err)
 			assert.Exactly(t, want, data, "Marshal")
 
 			var decoded Bool
-			assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary")
+			assert.NoError(t, decoded.Unmarshal(data), "Unmarshal")
 			assert.Exactly(t, b, decoded)
 		}
 	}
@@ -169,12 +166,10 @@ func TestNullBool_BinaryEncoding(t *testing.T) {
 	t.Run("null", runner(Bool{}, []byte("")))
 }
 
-func TestNullBool_BinaryDecoding(t *testing.T) {
+func TestNullBool_ProtoDecoding(t *testing.T) {
 	runner := func(data []byte, want Bool) func(*testing.T) {
 		return func(t *testing.T) {
 			var have Bool
-			assert.NoError(t, have.UnmarshalBinary(data), "UnmarshalBinary")
-			assert.Exactly(t, want, have, "UnmarshalBinary")
 			assert.NoError(t, have.Unmarshal(data), "Unmarshal")
 			assert.Exactly(t, want, have, "Unmarshal")
 		}
@@ -184,7 +179,7 @@ func TestNullBool_BinaryDecoding(t *testing.T) {
 	t.Run("null", runner([]byte(""), Bool{}))
 	t.Run("junk", func(t *testing.T) {
 		var have Bool
-		err := have.UnmarshalBinary([]byte{2, 1, 3})
+		err := have.Unmarshal([]byte{2, 1, 3})
 		assert.EqualError(t, err, "proto: Bool: illegal tag 0 (wire type 2)")
 	})
 }
@@ -20,6 +20,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -34,12 +35,14 @@ import (
 
 // Flags get binary encoded in the marshalers
 const (
-	decimalFlagNegative = 1 << iota
-	decimalFlagValid
-	decimalFlagQuote
-	decimalBinaryVersion01
+	decimalBinaryNegative = 1 << iota
+	decimalBinaryQuote
 )
 
+// DecimalMaxDigits defines the maximum number of digits of a MySQL/MariaDB
+// DECIMAL. Larger numbers return an Overflowed error.
+const DecimalMaxDigits = 65
+
 var bytesZero = []byte(`0`)
 
 // Decimal defines a container type for any MySQL/MariaDB
@@ -135,6 +138,10 @@ func MakeDecimalBytes(b []byte) (d Decimal, err error) {
 		digits = append(digits[:dotPos], digits[dotPos+1:]...)
 	}
 
+	if n := decimalDigits(len(bytes.TrimLeftFunc(digits, isZero)), d.Scale); n > DecimalMaxDigits {
+		return Decimal{}, errors.Overflowed.Newf("[null] Decimal %q has %d digits, allowed are %d", b, n, DecimalMaxDigits)
+	}
+
 	d.Precision, d.Valid, err = byteconv.ParseUint(digits, 10, 64)
 	if se, ok := err.(*strconv.NumError); ok && se.Err == strconv.ErrRange {
 		err = nil
@@ -158,6 +165,15 @@ func isZero(r rune) bool {
 	return r == '0'
 }
 
+// decimalDigits returns the number of digits of a DECIMAL column type which
+// can store a value with n significant digits and the scale.
+func decimalDigits(n int, scale int32) int {
+	if int(scale) > n {
+		return int(scale)
+	}
+	return n
+}
+
 // Scan implements the Scanner interface. Approx. >3x times faster than
 // database/sql.convertAssign.
 func (d *Decimal) Scan(value interface{}) (err error) {
@@ -393,53 +409,84 @@ func (d Decimal) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. As a string representation
-// is already used when encoding to text, this method stores that string as []byte
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (d *Decimal) UnmarshalBinary(data []byte) error {
-	const validLength = 19
-	ld := len(data)
-	if ld == 0 {
-		*d = Decimal{}
-		return nil
-	}
-	if ld != validLength {
-		return errors.NotValid.Newf("[dml] Decimal.UnmarshalBinary Invalid length of input data. Should be %d but have %d", validLength, len(data))
+	v, valid, err := unmarshalBinaryHeader("Decimal", data, -1)
+	if err != nil {
+		return err
 	}
-	d.Precision = binary.BigEndian.Uint64(data[:8])
-	d.Scale = int32(binary.BigEndian.Uint32(data[8:12]))
-	flags := binary.BigEndian.Uint16(data[12:14])
-
-	if flags&decimalFlagNegative != 0 {
-		d.Negative = true
+	*d = Decimal{}
+	if !valid {
+		return nil
 	}
-	if flags&decimalFlagValid != 0 {
-		d.Valid = true
+	if len(v) < 6 || v[0]&^(decimalBinaryNegative|decimalBinaryQuote) != 0 {
+		return errors.NotValid.Newf("[null] Decimal.UnmarshalBinary: Invalid data %x", data)
 	}
-	if flags&decimalFlagQuote != 0 {
-		d.Quote = true
+	d.Negative = v[0]&decimalBinaryNegative != 0
+	d.Quote = v[0]&decimalBinaryQuote != 0
+	d.Scale = int32(binary.BigEndian.Uint32(v[1:]))
+	digits := v[5:]
+	var ok bool
+	d.Precision, ok, err = byteconv.ParseUint(digits, 10, 64)
+	if se, isNumErr := err.(*strconv.NumError); isNumErr && se.Err == strconv.ErrRange && len(bytes.Trim(digits, "0123456789")) == 0 {
+		d.Precision, d.PrecisionStr, ok, err = 0, string(digits), true, nil
 	}
-	if flags&decimalBinaryVersion01 == 0 {
-		return errors.NotValid.Newf("[dml] Decimal.UnmarshalBinary invalid binary version")
+	if err != nil || !ok {
+		return errors.NotValid.Newf("[null] Decimal.UnmarshalBinary: Invalid digits %q", digits)
 	}
+	d.Valid = true
 	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. After the
+// version and validity byte follow the flags, the big endian scale and the
+// digits as text, hence numbers larger than an uint64 are supported.
 func (d Decimal) MarshalBinary() (data []byte, err error) {
 	if !d.Valid {
-		return nil, nil
+		return marshalBinaryHeader(false, 0), nil
+	}
+	data = marshalBinaryHeader(true, 5)
+	v := data[binaryHeaderLen:]
+	if d.Negative {
+		v[0] |= decimalBinaryNegative
+	}
+	if d.Quote {
+		v[0] |= decimalBinaryQuote
+	}
+	binary.BigEndian.PutUint32(v[1:], uint32(d.Scale))
+	if d.PrecisionStr != "" {
+		return append(data, d.PrecisionStr...), nil
 	}
-	var v0 [19]byte
-	_, err = d.MarshalTo(v0[:])
-	return v0[:], err
+	return strconv.AppendUint(data, d.Precision, 10), nil
 }
 
 // Value implements the driver.Valuer interface for database serialization. It
-// stores a string in driver.Value.
+// stores a string in driver.Value or nil if not valid.
 func (d Decimal) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
 	return d.String(), nil
 }
 
+// WriteTo uses a special dialect to encode the value and write it into w. The
+// number gets written without quotes and never in exponent notation. w cannot
+// be replaced by io.Writer and shall not be replaced by an interface because
+// of inlining features of the compiler.
+func (d Decimal) WriteTo(_ Dialecter, w *bytes.Buffer) (err error) {
+	d.string(w)
+	return nil
+}
+
+// Append appends the value as a string or its nil type to the interface
+// slice.
+func (d Decimal) Append(args []interface{}) []interface{} {
+	if d.Valid {
+		return append(args, d.String())
+	}
+	return append(args, nil)
+}
+
 // UnmarshalText implements the encoding.TextUnmarshaler interface for XML
 // deserialization.
 func (d *Decimal) UnmarshalText(text []byte) (err error) {
@@ -453,6 +500,9 @@ func (d *Decimal) UnmarshalText(text []byte) (err error) {
 // serialization. Does not support quoting. An invalid type returns an empty
 // string.
 func (d Decimal) MarshalText() (text []byte, err error) {
+	if !d.Valid {
+		return []byte{}, nil
+	}
 	var buf bytes.Buffer
 	d.string(&buf)
 	return buf.Bytes(), nil
@@ -468,3 +518,205 @@ func (d *Decimal) Fake(fieldName string) (hasFakeDataApplied bool, err error) {
 	}
 	return false, nil
 }
+
+// RoundingMode defines how Div and Round discard digits.
+type RoundingMode uint8
+
+// Rounding modes. RoundHalfUp is the default of MySQL/MariaDB.
+const (
+	// RoundHalfUp rounds to the nearest neighbour and a tie away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest neighbour and a tie to the even
+	// neighbour, also known as banker's rounding.
+	RoundHalfEven
+	// RoundDown rounds towards zero, it truncates.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling
+)
+
+// mantissa returns the signed unscaled value.
+func (d Decimal) mantissa() *big.Int {
+	m := new(big.Int)
+	if d.PrecisionStr != "" {
+		m.SetString(d.PrecisionStr, 10) // already validated
+	} else {
+		m.SetUint64(d.Precision)
+	}
+	if d.Negative {
+		m.Neg(m)
+	}
+	return m
+}
+
+var bigTen = big.NewInt(10)
+
+// pow10 returns 10^n.
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// makeDecimalBig creates a Decimal from a signed unscaled value and returns an
+// Overflowed error if the value has more than DecimalMaxDigits digits.
+func makeDecimalBig(m *big.Int, scale int32, quote bool) (Decimal, error) {
+	abs := new(big.Int).Abs(m)
+	digits := abs.String()
+	if n := decimalDigits(len(digits), scale); digits != "0" && n > DecimalMaxDigits {
+		return Decimal{}, errors.Overflowed.Newf("[null] Decimal result has %d digits, allowed are %d", n, DecimalMaxDigits)
+	}
+	d := Decimal{
+		Scale:    scale,
+		Negative: m.Sign() < 0,
+		Valid:    true,
+		Quote:    quote,
+	}
+	if abs.IsUint64() {
+		d.Precision = abs.Uint64()
+	} else {
+		d.PrecisionStr = digits
+	}
+	return d, nil
+}
+
+// alignScale returns both mantissas with the larger scale.
+func (d Decimal) alignScale(d2 Decimal) (m1, m2 *big.Int, scale int32) {
+	m1, m2, scale = d.mantissa(), d2.mantissa(), d.Scale
+	switch {
+	case d.Scale > d2.Scale:
+		m2.Mul(m2, pow10(d.Scale-d2.Scale))
+	case d.Scale < d2.Scale:
+		m1.Mul(m1, pow10(d2.Scale-d.Scale))
+		scale = d2.Scale
+	}
+	return m1, m2, scale
+}
+
+// Add returns d+d2 with the larger scale of both. If one value is NULL, the
+// result is NULL, like in SQL.
+func (d Decimal) Add(d2 Decimal) (Decimal, error) {
+	if !d.Valid || !d2.Valid {
+		return Decimal{}, nil
+	}
+	m1, m2, scale := d.alignScale(d2)
+	return makeDecimalBig(m1.Add(m1, m2), scale, d.Quote)
+}
+
+// Sub returns d-d2 with the larger scale of both. If one value is NULL, the
+// result is NULL, like in SQL.
+func (d Decimal) Sub(d2 Decimal) (Decimal, error) {
+	if !d.Valid || !d2.Valid {
+		return Decimal{}, nil
+	}
+	m1, m2, scale := d.alignScale(d2)
+	return makeDecimalBig(m1.Sub(m1, m2), scale, d.Quote)
+}
+
+// Mul returns d*d2 with the sum of both scales. If one value is NULL, the
+// result is NULL, like in SQL.
+func (d Decimal) Mul(d2 Decimal) (Decimal, error) {
+	if !d.Valid || !d2.Valid {
+		return Decimal{}, nil
+	}
+	m := d.mantissa()
+	return makeDecimalBig(m.Mul(m, d2.mantissa()), d.Scale+d2.Scale, d.Quote)
+}
+
+// Div returns d/d2 rounded to scale digits after the dot. If one value is
+// NULL, the result is NULL, like in SQL. A division by zero returns a NotValid
+// error.
+func (d Decimal) Div(d2 Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if !d.Valid || !d2.Valid {
+		return Decimal{}, nil
+	}
+	den := d2.mantissa()
+	if den.Sign() == 0 {
+		return Decimal{}, errors.NotValid.Newf("[null] Decimal division by zero: %s / %s", d, d2)
+	}
+	// d/d2 = m1*10^-s1 / (m2*10^-s2), scaled by 10^scale.
+	num := d.mantissa()
+	if exp := scale + d2.Scale - d.Scale; exp >= 0 {
+		num.Mul(num, pow10(exp))
+	} else {
+		den.Mul(den, pow10(-exp))
+	}
+	return makeDecimalBig(quoRound(num, den, mode), scale, d.Quote)
+}
+
+// Round returns d rounded to scale digits after the dot. A larger scale than
+// the current one appends zeros.
+func (d Decimal) Round(scale int32, mode RoundingMode) (Decimal, error) {
+	if !d.Valid {
+		return Decimal{}, nil
+	}
+	m := d.mantissa()
+	if scale >= d.Scale {
+		return makeDecimalBig(m.Mul(m, pow10(scale-d.Scale)), scale, d.Quote)
+	}
+	return makeDecimalBig(quoRound(m, pow10(d.Scale-scale), mode), scale, d.Quote)
+}
+
+// quoRound returns num/den rounded according to mode.
+func quoRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	sign := num.Sign() * den.Sign()
+	var awayFromZero bool
+	switch mode {
+	case RoundUp:
+		awayFromZero = true
+	case RoundFloor:
+		awayFromZero = sign < 0
+	case RoundCeiling:
+		awayFromZero = sign > 0
+	case RoundHalfUp, RoundHalfEven:
+		// compare 2*|r| with |den|
+		c := new(big.Int).Abs(r)
+		c.Lsh(c, 1)
+		switch cmp := c.Cmp(new(big.Int).Abs(den)); {
+		case cmp > 0:
+			awayFromZero = true
+		case cmp == 0:
+			awayFromZero = mode == RoundHalfUp || q.Bit(0) == 1
+		}
+	}
+	if awayFromZero {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+// Cmp compares d and d2 and returns -1 if d < d2, 0 if d == d2 and +1 if
+// d > d2. Different scales get respected, 1.50 equals 1.5. NULL is less than
+// any valid value and equal to NULL.
+func (d Decimal) Cmp(d2 Decimal) int {
+	switch {
+	case !d.Valid && !d2.Valid:
+		return 0
+	case !d.Valid:
+		return -1
+	case !d2.Valid:
+		return 1
+	}
+	m1, m2, _ := d.alignScale(d2)
+	return m1.Cmp(m2)
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	if d.Valid && (d.Precision != 0 || d.PrecisionStr != "") {
+		d.Negative = !d.Negative
+	}
+	return d
+}
+
+// Abs returns the absolute value of d.
+func (d Decimal) Abs() Decimal {
+	d.Negative = false
+	return d
+}
@@ -15,12 +15,14 @@
 package null
 
 import (
+	"bytes"
 	"database/sql"
 	"database/sql/driver"
 	"encoding"
 	"encoding/json"
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/corestoreio/errors"
@@ -77,7 +79,7 @@ func TestMakeDecimalBytes(t *testing.T) {
 		{"-0.184467440737095516151", 0, 21, true, nil, "-0.184467440737095516151", true},
 		{"0.0123456789012345678912345", 0, 25, false, nil, "0.0123456789012345678912345", true},
 		{"123456789012345678912345678901234", 0, 0, false, nil, "123456789012345678912345678901234", true},
-		{"123456789012345678912345678901234.123456789012345678912345678901234", 0, 33, false, nil, "123456789012345678912345678901234.123456789012345678912345678901234", true},
+		{"12345678901234567891234567890123.123456789012345678912345678901234", 0, 33, false, nil, "12345678901234567891234567890123.123456789012345678912345678901234", true},
 	}
 	for i, test := range tests {
 		haveD, haveErr := MakeDecimalBytes([]byte(test.data))
@@ -239,8 +241,13 @@ func TestDecimal_String(t *testing.T) {
 		}, "-0.000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000018446744073709551615"},
 	}
 	for i, test := range tests {
+		assert.Exactly(t, test.want, test.have.String(), "Index %d", i)
 		val, err := test.have.Value()
 		assert.NoError(t, err, "Index %d", i)
+		if !test.have.Valid {
+			assert.Nil(t, val, "Index %d", i)
+			continue
+		}
 		assert.Exactly(t, test.want, val, "Index %d", i)
 
 	}
@@ -325,7 +332,7 @@ func TestDecimal_MarshalText(t *testing.T) {
 
 	// TODO: Fuzzy testing
 
-	t.Run("not valid", runner(Decimal{}, "NULL"))
+	t.Run("not valid", runner(Decimal{}, ""))
 
 	t.Run("quoted", runner(Decimal{
 		Valid:     true,
@@ -487,3 +494,150 @@ func TestDecimal_Fake(t *testing.T) {
 		assert.False(t, hasFakeDataApplied)
 	})
 }
+
+func TestDecimal_Overflow(t *testing.T) {
+	_, err := MakeDecimalBytes([]byte("123456789012345678912345678901234.123456789012345678912345678901234"))
+	assert.ErrorIsKind(t, errors.Overflowed, err)
+	_, err = MakeDecimalBytes([]byte("0." + strings.Repeat("1", 66)))
+	assert.ErrorIsKind(t, errors.Overflowed, err)
+	_, err = MakeDecimalBytes([]byte("-000" + strings.Repeat("9", 65)))
+	assert.NoError(t, err)
+
+	max := MustMakeDecimalBytes([]byte(strings.Repeat("9", 65)))
+	_, err = max.Add(MakeDecimalInt64(1, 0))
+	assert.ErrorIsKind(t, errors.Overflowed, err)
+	_, err = max.Mul(max)
+	assert.ErrorIsKind(t, errors.Overflowed, err)
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	dec := func(s string) Decimal { return MustMakeDecimalBytes([]byte(s)) }
+
+	tests := []struct {
+		op   string
+		a, b string
+		want string
+	}{
+		{"+", "0.1", "0.2", "0.3"},
+		{"+", "2681.7000", "-0.0001", "2681.6999"},
+		{"+", "-10.55", "10.55", "0"},
+		{"+", "18446744073709551615", "1", "18446744073709551616"},
+		{"-", "0.3", "0.1", "0.2"},
+		{"-", "1", "1234.5678", "-1233.5678"},
+		{"-", "-99999999999999999999.9999", "0.0001", "-100000000000000000000"},
+		{"*", "19.99", "3", "59.97"},
+		{"*", "-1.5", "-1.5", "2.25"},
+		{"*", "0.0001", "0.0001", "0.00000001"},
+		{"*", "12345678901234567890.1234", "100", "1234567890123456789012.34"},
+	}
+	for _, test := range tests {
+		var have Decimal
+		var err error
+		switch test.op {
+		case "+":
+			have, err = dec(test.a).Add(dec(test.b))
+		case "-":
+			have, err = dec(test.a).Sub(dec(test.b))
+		case "*":
+			have, err = dec(test.a).Mul(dec(test.b))
+		}
+		assert.NoError(t, err)
+		assert.Exactly(t, test.want, have.String(), "%s %s %s", test.a, test.op, test.b)
+	}
+
+	t.Run("NULL", func(t *testing.T) {
+		have, err := dec("1.5").Add(Decimal{})
+		assert.NoError(t, err)
+		assert.False(t, have.Valid)
+		have, err = Decimal{}.Div(dec("1.5"), 2, RoundHalfUp)
+		assert.NoError(t, err)
+		assert.False(t, have.Valid)
+	})
+
+	t.Run("sum without rounding errors", func(t *testing.T) {
+		sum := MakeDecimalInt64(0, 4)
+		for i := 0; i < 1000; i++ {
+			var err error
+			sum, err = sum.Add(dec("0.1"))
+			assert.NoError(t, err)
+		}
+		assert.Exactly(t, "100", sum.String())
+		assert.Exactly(t, int32(4), sum.Scale)
+	})
+}
+
+func TestDecimal_Div(t *testing.T) {
+	dec := func(s string) Decimal { return MustMakeDecimalBytes([]byte(s)) }
+
+	tests := []struct {
+		a, b  string
+		scale int32
+		mode  RoundingMode
+		want  string
+	}{
+		{"10", "3", 4, RoundHalfUp, "3.3333"},
+		{"20", "3", 4, RoundHalfUp, "6.6667"},
+		{"-20", "3", 4, RoundHalfUp, "-6.6667"},
+		{"20", "3", 4, RoundDown, "6.6666"},
+		{"-20", "3", 4, RoundDown, "-6.6666"},
+		{"10", "3", 0, RoundUp, "4"},
+		{"-10", "3", 0, RoundUp, "-4"},
+		{"-10", "3", 0, RoundFloor, "-4"},
+		{"10", "3", 0, RoundFloor, "3"},
+		{"10", "3", 0, RoundCeiling, "4"},
+		{"-10", "3", 0, RoundCeiling, "-3"},
+		{"2.5", "1", 0, RoundHalfUp, "3"},
+		{"-2.5", "1", 0, RoundHalfUp, "-3"},
+		{"2.5", "1", 0, RoundHalfEven, "2"},
+		{"3.5", "1", 0, RoundHalfEven, "4"},
+		{"-2.5", "1", 0, RoundHalfEven, "-2"},
+		{"1", "0.0008", 2, RoundHalfUp, "1250"},
+		{"123.456", "-0.01", 1, RoundHalfUp, "-12345.6"},
+		{"1", "8", 3, RoundHalfEven, "0.125"},
+	}
+	for _, test := range tests {
+		have, err := dec(test.a).Div(dec(test.b), test.scale, test.mode)
+		assert.NoError(t, err)
+		assert.Exactly(t, test.want, have.String(), "%s / %s mode %d", test.a, test.b, test.mode)
+		assert.Exactly(t, test.scale, have.Scale)
+	}
+
+	_, err := dec("1").Div(dec("0.000"), 2, RoundHalfUp)
+	assert.ErrorIsKind(t, errors.NotValid, err)
+
+	have, err := dec("2.345").Round(2, RoundHalfEven)
+	assert.NoError(t, err)
+	assert.Exactly(t, "2.34", have.String())
+	have, err = dec("2.5").Round(3, RoundHalfUp)
+	assert.NoError(t, err)
+	assert.Exactly(t, "2.5", have.String())
+	assert.Exactly(t, int32(3), have.Scale)
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	dec := func(s string) Decimal { return MustMakeDecimalBytes([]byte(s)) }
+
+	assert.Exactly(t, 0, dec("1.50").Cmp(dec("1.5")))
+	assert.Exactly(t, -1, dec("-1.5").Cmp(dec("1.5")))
+	assert.Exactly(t, 1, dec("123456789012345678901234567890").Cmp(dec("123456789012345678901234567889.99")))
+	assert.Exactly(t, -1, Decimal{}.Cmp(dec("-1")))
+	assert.Exactly(t, 1, dec("-1").Cmp(Decimal{}))
+	assert.Exactly(t, 0, Decimal{}.Cmp(Decimal{}))
+
+	assert.Exactly(t, "-47.11", dec("47.11").Neg().String())
+	assert.Exactly(t, "47.11", dec("-47.11").Neg().String())
+	assert.Exactly(t, "0", dec("0").Neg().String())
+	assert.Exactly(t, "NULL", Decimal{}.Neg().String())
+	assert.Exactly(t, "47.11", dec("-47.11").Abs().String())
+}
+
+func TestDecimal_WriteTo(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("x=")
+	assert.NoError(t, MustMakeDecimalBytes([]byte("-0.00000000000000000001")).WriteTo(nil, &buf))
+	buf.WriteString(",y=")
+	assert.NoError(t, Decimal{}.WriteTo(nil, &buf))
+	assert.Exactly(t, "x=-0.00000000000000000001,y=NULL", buf.String())
+
+	assert.Exactly(t, []interface{}{"12.5", nil}, Decimal{}.Append(MakeDecimalInt64(125, 1).Append(nil)))
+}
@@ -18,6 +18,8 @@
 // can occur everywhere hence we want to keep the deps minimal.
 package null
 
+//go:generate go run methods_main.go
+
 import (
 	"bytes"
 	"strconv"
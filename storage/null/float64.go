@@ -17,7 +17,9 @@ package null
 import (
 	"bytes"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
+	"math"
 	"strconv"
 
 	"github.com/corestoreio/errors"
@@ -174,43 +176,27 @@ func (a Float64) MarshalText() ([]byte, error) {
 	return strconv.AppendFloat([]byte{}, a.Float64, 'f', -1, 64), nil
 }
 
-// SetValid changes this Float64's value and also sets it to be non-null.
-func (a *Float64) SetValid(n float64) { a.Float64 = n; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Float64) Reset() { *a = Float64{} }
-
-// Ptr returns a pointer to this Float64's value, or a nil pointer if this Float64 is null.
-func (a Float64) Ptr() *float64 {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Float64
-}
-
-// SetPtr sets v according to the rules.
-func (a *Float64) SetPtr(v *float64) {
-	a.Valid = v != nil
-	a.Float64 = 0
-	if v != nil {
-		a.Float64 = *v
-	}
-}
-
-// IsZero returns true for invalid Float64s, for future omitempty support (Go 1.4?)
-// A non-null Float64 with a 0 value will not be considered zero.
-func (a Float64) IsZero() bool {
-	return !a.Valid
-}
-
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Float64) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Float64", data, 8)
+	if err != nil {
+		return err
+	}
+	*a = Float64{}
+	if valid {
+		a.SetValid(math.Float64frombits(binary.BigEndian.Uint64(v)))
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written big endian with 8 bytes after the version and validity byte.
 func (a Float64) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 8)
+	if a.Valid {
+		binary.BigEndian.PutUint64(data[binaryHeaderLen:], math.Float64bits(a.Float64))
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
@@ -140,18 +140,15 @@ func TestNullFloat64_MarshalText(t *testing.T) {
 	assertJSONEquals(t, data, "", "null text marshal")
 }
 
-func TestNullFloat64_BinaryEncoding(t *testing.T) {
+func TestNullFloat64_ProtoEncoding(t *testing.T) {
 	runner := func(b Float64, want []byte) func(*testing.T) {
 		return func(t *testing.T) {
-			data, err := b.MarshalBinary()
-			assert.NoError(t, err)
-			assert.Exactly(t, want, data, t.Name()+": MarshalBinary")
-			data, err = b.Marshal()
+			data, err := b.Marshal()
 			assert.NoError(t, err)
 			assert.Exactly(t, want, data, t.Name()+": Marshal")
 
 			var decoded Float64
-			assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary")
+			assert.NoError(t, decoded.Unmarshal(data), "Unmarshal")
 			assert.Exactly(t, b, decoded)
 		}
 	}
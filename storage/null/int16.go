@@ -17,6 +17,7 @@ package null
 import (
 	"bytes"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"math"
 	"strconv"
@@ -171,35 +172,6 @@ func (a Int16) MarshalText() ([]byte, error) {
 	return strconv.AppendInt([]byte{}, int64(a.Int16), 10), nil
 }
 
-// SetValid changes this Int16's value and also sets it to be non-null.
-func (a *Int16) SetValid(n int16) { a.Int16 = n; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Int16) Reset() { *a = Int16{} }
-
-// Ptr returns a pointer to this Int16's value, or a nil pointer if this Int16 is null.
-func (a Int16) Ptr() *int16 {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Int16
-}
-
-// SetPtr sets v according to the rules.
-func (a *Int16) SetPtr(v *int16) {
-	a.Valid = v != nil
-	a.Int16 = 0
-	if v != nil {
-		a.Int16 = *v
-	}
-}
-
-// IsZero returns true for invalid Int16's, for future omitempty support (Go 1.4?)
-// A non-null Int16 with a 0 value will not be considered zero.
-func (a Int16) IsZero() bool {
-	return !a.Valid
-}
-
 // Value implements the driver.Valuer interface.
 func (a Int16) Value() (driver.Value, error) {
 	if !a.Valid {
@@ -210,12 +182,25 @@ func (a Int16) Value() (driver.Value, error) {
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Int16) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Int16", data, 2)
+	if err != nil {
+		return err
+	}
+	*a = Int16{}
+	if valid {
+		a.SetValid(int16(binary.BigEndian.Uint16(v)))
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written big endian with 2 bytes after the version and validity byte.
 func (a Int16) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 2)
+	if a.Valid {
+		binary.BigEndian.PutUint16(data[binaryHeaderLen:], uint16(a.Int16))
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
@@ -17,6 +17,7 @@ package null
 import (
 	"bytes"
 	"database/sql"
+	"encoding/binary"
 	"encoding/json"
 	"math"
 	"strconv"
@@ -169,43 +170,27 @@ func (a Int32) MarshalText() ([]byte, error) {
 	return strconv.AppendInt([]byte{}, int64(a.Int32), 10), nil
 }
 
-// SetValid changes this Int32's value and also sets it to be non-null.
-func (a *Int32) SetValid(n int32) { a.Int32 = n; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Int32) Reset() { *a = Int32{} }
-
-// Ptr returns a pointer to this Int32's value, or a nil pointer if this Int32 is null.
-func (a Int32) Ptr() *int32 {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Int32
-}
-
-// SetPtr sets v according to the rules.
-func (a *Int32) SetPtr(v *int32) {
-	a.Valid = v != nil
-	a.Int32 = 0
-	if v != nil {
-		a.Int32 = *v
-	}
-}
-
-// IsZero returns true for invalid Int32's, for future omitempty support (Go 1.4?)
-// A non-null Int32 with a 0 value will not be considered zero.
-func (a Int32) IsZero() bool {
-	return !a.Valid
-}
-
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Int32) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Int32", data, 4)
+	if err != nil {
+		return err
+	}
+	*a = Int32{}
+	if valid {
+		a.SetValid(int32(binary.BigEndian.Uint32(v)))
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written big endian with 4 bytes after the version and validity byte.
 func (a Int32) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 4)
+	if a.Valid {
+		binary.BigEndian.PutUint32(data[binaryHeaderLen:], uint32(a.Int32))
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
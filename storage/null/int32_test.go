@@ -176,18 +176,15 @@ func TestNullInt32_MarshalText(t *testing.T) {
 	assertJSONEquals(t, data, "", "null text marshal")
 }
 
-func TestNullInt32_BinaryEncoding(t *testing.T) {
+func TestNullInt32_ProtoEncoding(t *testing.T) {
 	runner := func(b Int32, want []byte) func(*testing.T) {
 		return func(t *testing.T) {
-			data, err := b.MarshalBinary()
-			assert.NoError(t, err)
-			assert.Exactly(t, want, data, t.Name()+": MarshalBinary: %q", data)
-			data, err = b.Marshal()
+			data, err := b.Marshal()
 			assert.NoError(t, err)
 			assert.Exactly(t, want, data, t.Name()+": Marshal: %q", data)
 
 			var decoded Int32
-			assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary")
+			assert.NoError(t, decoded.Unmarshal(data), "Unmarshal")
 			assert.Exactly(t, b, decoded)
 		}
 	}
@@ -17,6 +17,7 @@ package null
 import (
 	"bytes"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"strconv"
 
@@ -166,35 +167,6 @@ func (a Int64) MarshalText() ([]byte, error) {
 	return strconv.AppendInt([]byte{}, a.Int64, 10), nil
 }
 
-// SetValid changes this Int64's value and also sets it to be non-null.
-func (a *Int64) SetValid(n int64) { a.Int64 = n; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Int64) Reset() { *a = Int64{} }
-
-// Ptr returns a pointer to this value, or a nil pointer if value is null.
-func (a Int64) Ptr() *int64 {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Int64
-}
-
-// SetPtr sets v according to the rules.
-func (a *Int64) SetPtr(v *int64) {
-	a.Valid = v != nil
-	a.Int64 = 0
-	if v != nil {
-		a.Int64 = *v
-	}
-}
-
-// IsZero returns true for invalid Int64's, for future omitempty support (Go 1.4?)
-// A non-null Int64 with a 0 value will not be considered zero.
-func (a Int64) IsZero() bool {
-	return !a.Valid
-}
-
 // Value implements the driver.Valuer interface.
 func (a Int64) Value() (driver.Value, error) {
 	if !a.Valid {
@@ -205,12 +177,25 @@ func (a Int64) Value() (driver.Value, error) {
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Int64) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Int64", data, 8)
+	if err != nil {
+		return err
+	}
+	*a = Int64{}
+	if valid {
+		a.SetValid(int64(binary.BigEndian.Uint64(v)))
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written big endian with 8 bytes after the version and validity byte.
 func (a Int64) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 8)
+	if a.Valid {
+		binary.BigEndian.PutUint64(data[binaryHeaderLen:], uint64(a.Int64))
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
@@ -172,35 +172,6 @@ func (a Int8) MarshalText() ([]byte, error) {
 	return strconv.AppendInt([]byte{}, int64(a.Int8), 10), nil
 }
 
-// SetValid changes this Int8's value and also sets it to be non-null.
-func (a *Int8) SetValid(n int8) { a.Int8 = n; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Int8) Reset() { *a = Int8{} }
-
-// Ptr returns a pointer to this Int8's value, or a nil pointer if this Int8 is null.
-func (a Int8) Ptr() *int8 {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Int8
-}
-
-// SetPtr sets v according to the rules.
-func (a *Int8) SetPtr(v *int8) {
-	a.Valid = v != nil
-	a.Int8 = 0
-	if v != nil {
-		a.Int8 = *v
-	}
-}
-
-// IsZero returns true for invalid Int8's, for future omitempty support (Go 1.4?)
-// A non-null Int8 with a 0 value will not be considered zero.
-func (a Int8) IsZero() bool {
-	return !a.Valid
-}
-
 // Value implements the driver.Valuer interface.
 func (a Int8) Value() (driver.Value, error) {
 	if !a.Valid {
@@ -211,12 +182,25 @@ func (a Int8) Value() (driver.Value, error) {
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Int8) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Int8", data, 1)
+	if err != nil {
+		return err
+	}
+	*a = Int8{}
+	if valid {
+		a.SetValid(int8(v[0]))
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written with 1 byte after the version and validity byte.
 func (a Int8) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 1)
+	if a.Valid {
+		data[binaryHeaderLen] = byte(a.Int8)
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
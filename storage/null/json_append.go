@@ -0,0 +1,212 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package null
+
+import (
+	"encoding/base64"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// The AppendJSON functions and methods append the JSON representation of a
+// value to dst and return the extended buffer. They produce the same output as
+// the MarshalJSON methods but without allocating an intermediate slice. Mostly
+// used in generated code of package dmlgen.
+
+const hexDigits = "0123456789abcdef"
+
+// AppendJSONString appends s as a quoted and escaped JSON string to dst. The
+// escaping follows encoding/json, including the HTML characters <, > and &.
+func AppendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '\\', '"':
+				dst = append(dst, '\\', b)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 are valid JSON but break JSONP, encoding/json escapes them.
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// AppendJSONFloat64 appends f in the same format as Float64.MarshalJSON.
+func AppendJSONFloat64(dst []byte, f float64) []byte {
+	return strconv.AppendFloat(dst, f, 'f', -1, 64)
+}
+
+// AppendJSONTime appends t as a quoted RFC 3339 string with sub-second
+// precision, like time.Time.MarshalJSON.
+func AppendJSONTime(dst []byte, t time.Time) []byte {
+	dst = append(dst, '"')
+	dst = t.AppendFormat(dst, time.RFC3339Nano)
+	return append(dst, '"')
+}
+
+// AppendJSONBytes appends b as a quoted standard base64 string, like
+// encoding/json does for a byte slice. A nil slice results in null.
+func AppendJSONBytes(dst []byte, b []byte) []byte {
+	if b == nil {
+		return append(dst, bTextNullLC...)
+	}
+	dst = append(dst, '"')
+	n := base64.StdEncoding.EncodedLen(len(b))
+	if cap(dst)-len(dst) < n {
+		ndst := make([]byte, len(dst), len(dst)+n+1)
+		copy(ndst, dst)
+		dst = ndst
+	}
+	base64.StdEncoding.Encode(dst[len(dst):len(dst)+n], b)
+	dst = dst[:len(dst)+n]
+	return append(dst, '"')
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Bool) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return strconv.AppendBool(dst, a.Bool)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (d Decimal) AppendJSON(dst []byte) []byte {
+	b, _ := d.MarshalJSON() // never returns an error
+	return append(dst, b...)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Float64) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return AppendJSONFloat64(dst, a.Float64)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Int8) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return strconv.AppendInt(dst, int64(a.Int8), 10)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Int16) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return strconv.AppendInt(dst, int64(a.Int16), 10)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Int32) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return strconv.AppendInt(dst, int64(a.Int32), 10)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Int64) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return strconv.AppendInt(dst, a.Int64, 10)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Uint8) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return strconv.AppendUint(dst, uint64(a.Uint8), 10)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Uint16) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return strconv.AppendUint(dst, uint64(a.Uint16), 10)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Uint32) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return strconv.AppendUint(dst, uint64(a.Uint32), 10)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Uint64) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return strconv.AppendUint(dst, a.Uint64, 10)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a String) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return AppendJSONString(dst, a.Data)
+}
+
+// AppendJSON appends the JSON representation to dst.
+func (a Time) AppendJSON(dst []byte) []byte {
+	if !a.Valid {
+		return append(dst, bTextNullLC...)
+	}
+	return AppendJSONTime(dst, a.Time)
+}
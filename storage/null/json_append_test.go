@@ -0,0 +1,69 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package null
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestAppendJSONString(t *testing.T) {
+	for _, s := range []string{
+		"", "abc", `quo"te`, `back\slash`, "tab\tnew\nline\rret", "\x00\x1f",
+		"<html>&amp;", "ÄÖÜ 日本", "bad\xffutf8", "line\u2028sep\u2029par",
+	} {
+		want, err := json.Marshal(s)
+		assert.NoError(t, err)
+		assert.Exactly(t, string(want), string(AppendJSONString(nil, s)), "%q", s)
+	}
+}
+
+func TestAppendJSONBytes(t *testing.T) {
+	for _, b := range [][]byte{nil, {}, []byte("x"), []byte("Hello World")} {
+		want, err := json.Marshal(b)
+		assert.NoError(t, err)
+		assert.Exactly(t, string(want), string(AppendJSONBytes([]byte{}, b)))
+	}
+}
+
+func TestAppendJSON_EqualsMarshalJSON(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 6, 7, 891011, time.UTC)
+	tests := []interface {
+		json.Marshaler
+		AppendJSON([]byte) []byte
+	}{
+		Bool{}, MakeBool(true), MakeBool(false),
+		Decimal{}, MakeDecimalInt64(-12345, 2),
+		Float64{}, MakeFloat64(3.1415),
+		Int8{}, MakeInt8(-8),
+		Int16{}, MakeInt16(-16),
+		Int32{}, MakeInt32(-32),
+		Int64{}, MakeInt64(-64),
+		Uint8{}, MakeUint8(8),
+		Uint16{}, MakeUint16(16),
+		Uint32{}, MakeUint32(32),
+		Uint64{}, MakeUint64(64),
+		String{}, MakeString(`a "quoted" <string>`),
+		Time{}, MakeTime(now),
+	}
+	for _, v := range tests {
+		want, err := v.MarshalJSON()
+		assert.NoError(t, err)
+		assert.Exactly(t, string(want), string(v.AppendJSON([]byte{})), "%#v", v)
+	}
+}
@@ -0,0 +1,832 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by methods_main.go via go generate. DO NOT EDIT.
+
+package null
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// MakeBoolFromPtr creates a new Bool from a pointer. A nil pointer
+// results in NULL.
+func MakeBoolFromPtr(v *bool) (a Bool) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Bool's value and also sets it to be non-null.
+func (a *Bool) SetValid(v bool) { a.Bool = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Bool) Reset() { *a = Bool{} }
+
+// Ptr returns a pointer to this Bool's value, or a nil pointer if this
+// Bool is null.
+func (a Bool) Ptr() *bool {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Bool
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Bool) SetPtr(v *bool) {
+	*a = Bool{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Bool is null.
+func (a Bool) Or(def bool) bool {
+	if !a.Valid {
+		return def
+	}
+	return a.Bool
+}
+
+// IsZero returns true for a null Bool, useful for omitempty
+// style logic. A non-null Bool with a zero value will not be considered
+// zero.
+func (a Bool) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Bools are equal. Use SQLEqual for the semantics of the = operator.
+func (a Bool) Equal(b Bool) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Bool == b.Bool)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Bool
+// is never equal to another Bool.
+func (a Bool) SQLEqual(b Bool) bool {
+	return a.Valid && b.Valid && a.Bool == b.Bool
+}
+
+// SetProto sets the value of the protobuf wrapper type. A nil v results in
+// NULL.
+func (a *Bool) SetProto(v *wrapperspb.BoolValue) {
+	*a = Bool{}
+	if v != nil {
+		a.SetValid(v.GetValue())
+	}
+}
+
+// Proto returns the value as protobuf wrapper type or nil if this Bool
+// is null.
+func (a *Bool) Proto() *wrapperspb.BoolValue {
+	if a == nil || !a.Valid {
+		return nil
+	}
+	return wrapperspb.Bool(a.Bool)
+}
+
+// MakeFloat64FromPtr creates a new Float64 from a pointer. A nil pointer
+// results in NULL.
+func MakeFloat64FromPtr(v *float64) (a Float64) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Float64's value and also sets it to be non-null.
+func (a *Float64) SetValid(v float64) { a.Float64 = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Float64) Reset() { *a = Float64{} }
+
+// Ptr returns a pointer to this Float64's value, or a nil pointer if this
+// Float64 is null.
+func (a Float64) Ptr() *float64 {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Float64
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Float64) SetPtr(v *float64) {
+	*a = Float64{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Float64 is null.
+func (a Float64) Or(def float64) float64 {
+	if !a.Valid {
+		return def
+	}
+	return a.Float64
+}
+
+// IsZero returns true for a null Float64, useful for omitempty
+// style logic. A non-null Float64 with a zero value will not be considered
+// zero.
+func (a Float64) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Float64s are equal. Use SQLEqual for the semantics of the = operator.
+func (a Float64) Equal(b Float64) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Float64 == b.Float64)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Float64
+// is never equal to another Float64.
+func (a Float64) SQLEqual(b Float64) bool {
+	return a.Valid && b.Valid && a.Float64 == b.Float64
+}
+
+// SetProto sets the value of the protobuf wrapper type. A nil v results in
+// NULL.
+func (a *Float64) SetProto(v *wrapperspb.DoubleValue) {
+	*a = Float64{}
+	if v != nil {
+		a.SetValid(v.GetValue())
+	}
+}
+
+// Proto returns the value as protobuf wrapper type or nil if this Float64
+// is null.
+func (a *Float64) Proto() *wrapperspb.DoubleValue {
+	if a == nil || !a.Valid {
+		return nil
+	}
+	return wrapperspb.Double(a.Float64)
+}
+
+// MakeInt8FromPtr creates a new Int8 from a pointer. A nil pointer
+// results in NULL.
+func MakeInt8FromPtr(v *int8) (a Int8) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Int8's value and also sets it to be non-null.
+func (a *Int8) SetValid(v int8) { a.Int8 = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Int8) Reset() { *a = Int8{} }
+
+// Ptr returns a pointer to this Int8's value, or a nil pointer if this
+// Int8 is null.
+func (a Int8) Ptr() *int8 {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Int8
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Int8) SetPtr(v *int8) {
+	*a = Int8{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Int8 is null.
+func (a Int8) Or(def int8) int8 {
+	if !a.Valid {
+		return def
+	}
+	return a.Int8
+}
+
+// IsZero returns true for a null Int8, useful for omitempty
+// style logic. A non-null Int8 with a zero value will not be considered
+// zero.
+func (a Int8) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Int8s are equal. Use SQLEqual for the semantics of the = operator.
+func (a Int8) Equal(b Int8) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Int8 == b.Int8)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Int8
+// is never equal to another Int8.
+func (a Int8) SQLEqual(b Int8) bool {
+	return a.Valid && b.Valid && a.Int8 == b.Int8
+}
+
+// MakeInt16FromPtr creates a new Int16 from a pointer. A nil pointer
+// results in NULL.
+func MakeInt16FromPtr(v *int16) (a Int16) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Int16's value and also sets it to be non-null.
+func (a *Int16) SetValid(v int16) { a.Int16 = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Int16) Reset() { *a = Int16{} }
+
+// Ptr returns a pointer to this Int16's value, or a nil pointer if this
+// Int16 is null.
+func (a Int16) Ptr() *int16 {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Int16
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Int16) SetPtr(v *int16) {
+	*a = Int16{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Int16 is null.
+func (a Int16) Or(def int16) int16 {
+	if !a.Valid {
+		return def
+	}
+	return a.Int16
+}
+
+// IsZero returns true for a null Int16, useful for omitempty
+// style logic. A non-null Int16 with a zero value will not be considered
+// zero.
+func (a Int16) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Int16s are equal. Use SQLEqual for the semantics of the = operator.
+func (a Int16) Equal(b Int16) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Int16 == b.Int16)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Int16
+// is never equal to another Int16.
+func (a Int16) SQLEqual(b Int16) bool {
+	return a.Valid && b.Valid && a.Int16 == b.Int16
+}
+
+// MakeInt32FromPtr creates a new Int32 from a pointer. A nil pointer
+// results in NULL.
+func MakeInt32FromPtr(v *int32) (a Int32) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Int32's value and also sets it to be non-null.
+func (a *Int32) SetValid(v int32) { a.Int32 = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Int32) Reset() { *a = Int32{} }
+
+// Ptr returns a pointer to this Int32's value, or a nil pointer if this
+// Int32 is null.
+func (a Int32) Ptr() *int32 {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Int32
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Int32) SetPtr(v *int32) {
+	*a = Int32{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Int32 is null.
+func (a Int32) Or(def int32) int32 {
+	if !a.Valid {
+		return def
+	}
+	return a.Int32
+}
+
+// IsZero returns true for a null Int32, useful for omitempty
+// style logic. A non-null Int32 with a zero value will not be considered
+// zero.
+func (a Int32) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Int32s are equal. Use SQLEqual for the semantics of the = operator.
+func (a Int32) Equal(b Int32) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Int32 == b.Int32)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Int32
+// is never equal to another Int32.
+func (a Int32) SQLEqual(b Int32) bool {
+	return a.Valid && b.Valid && a.Int32 == b.Int32
+}
+
+// SetProto sets the value of the protobuf wrapper type. A nil v results in
+// NULL.
+func (a *Int32) SetProto(v *wrapperspb.Int32Value) {
+	*a = Int32{}
+	if v != nil {
+		a.SetValid(v.GetValue())
+	}
+}
+
+// Proto returns the value as protobuf wrapper type or nil if this Int32
+// is null.
+func (a *Int32) Proto() *wrapperspb.Int32Value {
+	if a == nil || !a.Valid {
+		return nil
+	}
+	return wrapperspb.Int32(a.Int32)
+}
+
+// MakeInt64FromPtr creates a new Int64 from a pointer. A nil pointer
+// results in NULL.
+func MakeInt64FromPtr(v *int64) (a Int64) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Int64's value and also sets it to be non-null.
+func (a *Int64) SetValid(v int64) { a.Int64 = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Int64) Reset() { *a = Int64{} }
+
+// Ptr returns a pointer to this Int64's value, or a nil pointer if this
+// Int64 is null.
+func (a Int64) Ptr() *int64 {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Int64
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Int64) SetPtr(v *int64) {
+	*a = Int64{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Int64 is null.
+func (a Int64) Or(def int64) int64 {
+	if !a.Valid {
+		return def
+	}
+	return a.Int64
+}
+
+// IsZero returns true for a null Int64, useful for omitempty
+// style logic. A non-null Int64 with a zero value will not be considered
+// zero.
+func (a Int64) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Int64s are equal. Use SQLEqual for the semantics of the = operator.
+func (a Int64) Equal(b Int64) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Int64 == b.Int64)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Int64
+// is never equal to another Int64.
+func (a Int64) SQLEqual(b Int64) bool {
+	return a.Valid && b.Valid && a.Int64 == b.Int64
+}
+
+// SetProto sets the value of the protobuf wrapper type. A nil v results in
+// NULL.
+func (a *Int64) SetProto(v *wrapperspb.Int64Value) {
+	*a = Int64{}
+	if v != nil {
+		a.SetValid(v.GetValue())
+	}
+}
+
+// Proto returns the value as protobuf wrapper type or nil if this Int64
+// is null.
+func (a *Int64) Proto() *wrapperspb.Int64Value {
+	if a == nil || !a.Valid {
+		return nil
+	}
+	return wrapperspb.Int64(a.Int64)
+}
+
+// MakeStringFromPtr creates a new String from a pointer. A nil pointer
+// results in NULL.
+func MakeStringFromPtr(v *string) (a String) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this String's value and also sets it to be non-null.
+func (a *String) SetValid(v string) { a.Data = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *String) Reset() { *a = String{} }
+
+// Ptr returns a pointer to this String's value, or a nil pointer if this
+// String is null.
+func (a String) Ptr() *string {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Data
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *String) SetPtr(v *string) {
+	*a = String{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this String is null.
+func (a String) Or(def string) string {
+	if !a.Valid {
+		return def
+	}
+	return a.Data
+}
+
+// IsZero returns true for a null String, useful for omitempty
+// style logic. A non-null String with a zero value will not be considered
+// zero.
+func (a String) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Strings are equal. Use SQLEqual for the semantics of the = operator.
+func (a String) Equal(b String) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Data == b.Data)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null String
+// is never equal to another String.
+func (a String) SQLEqual(b String) bool {
+	return a.Valid && b.Valid && a.Data == b.Data
+}
+
+// SetProto sets the value of the protobuf wrapper type. A nil v results in
+// NULL.
+func (a *String) SetProto(v *wrapperspb.StringValue) {
+	*a = String{}
+	if v != nil {
+		a.SetValid(v.GetValue())
+	}
+}
+
+// Proto returns the value as protobuf wrapper type or nil if this String
+// is null.
+func (a *String) Proto() *wrapperspb.StringValue {
+	if a == nil || !a.Valid {
+		return nil
+	}
+	return wrapperspb.String(a.Data)
+}
+
+// MakeTimeFromPtr creates a new Time from a pointer. A nil pointer
+// results in NULL.
+func MakeTimeFromPtr(v *time.Time) (a Time) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Time's value and also sets it to be non-null.
+func (a *Time) SetValid(v time.Time) { a.Time = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Time) Reset() { *a = Time{} }
+
+// Ptr returns a pointer to this Time's value, or a nil pointer if this
+// Time is null.
+func (a Time) Ptr() *time.Time {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Time
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Time) SetPtr(v *time.Time) {
+	*a = Time{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Time is null.
+func (a Time) Or(def time.Time) time.Time {
+	if !a.Valid {
+		return def
+	}
+	return a.Time
+}
+
+// IsZero returns true for a null Time or a zero value, useful for omitempty
+// style logic.
+func (a Time) IsZero() bool {
+	return !a.Valid || a.Time.IsZero()
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Times are equal. Use SQLEqual for the semantics of the = operator.
+func (a Time) Equal(b Time) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Time.Equal(b.Time))
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Time
+// is never equal to another Time.
+func (a Time) SQLEqual(b Time) bool {
+	return a.Valid && b.Valid && a.Time.Equal(b.Time)
+}
+
+// MakeUint8FromPtr creates a new Uint8 from a pointer. A nil pointer
+// results in NULL.
+func MakeUint8FromPtr(v *uint8) (a Uint8) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Uint8's value and also sets it to be non-null.
+func (a *Uint8) SetValid(v uint8) { a.Uint8 = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Uint8) Reset() { *a = Uint8{} }
+
+// Ptr returns a pointer to this Uint8's value, or a nil pointer if this
+// Uint8 is null.
+func (a Uint8) Ptr() *uint8 {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Uint8
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Uint8) SetPtr(v *uint8) {
+	*a = Uint8{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Uint8 is null.
+func (a Uint8) Or(def uint8) uint8 {
+	if !a.Valid {
+		return def
+	}
+	return a.Uint8
+}
+
+// IsZero returns true for a null Uint8, useful for omitempty
+// style logic. A non-null Uint8 with a zero value will not be considered
+// zero.
+func (a Uint8) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Uint8s are equal. Use SQLEqual for the semantics of the = operator.
+func (a Uint8) Equal(b Uint8) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Uint8 == b.Uint8)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Uint8
+// is never equal to another Uint8.
+func (a Uint8) SQLEqual(b Uint8) bool {
+	return a.Valid && b.Valid && a.Uint8 == b.Uint8
+}
+
+// MakeUint16FromPtr creates a new Uint16 from a pointer. A nil pointer
+// results in NULL.
+func MakeUint16FromPtr(v *uint16) (a Uint16) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Uint16's value and also sets it to be non-null.
+func (a *Uint16) SetValid(v uint16) { a.Uint16 = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Uint16) Reset() { *a = Uint16{} }
+
+// Ptr returns a pointer to this Uint16's value, or a nil pointer if this
+// Uint16 is null.
+func (a Uint16) Ptr() *uint16 {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Uint16
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Uint16) SetPtr(v *uint16) {
+	*a = Uint16{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Uint16 is null.
+func (a Uint16) Or(def uint16) uint16 {
+	if !a.Valid {
+		return def
+	}
+	return a.Uint16
+}
+
+// IsZero returns true for a null Uint16, useful for omitempty
+// style logic. A non-null Uint16 with a zero value will not be considered
+// zero.
+func (a Uint16) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Uint16s are equal. Use SQLEqual for the semantics of the = operator.
+func (a Uint16) Equal(b Uint16) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Uint16 == b.Uint16)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Uint16
+// is never equal to another Uint16.
+func (a Uint16) SQLEqual(b Uint16) bool {
+	return a.Valid && b.Valid && a.Uint16 == b.Uint16
+}
+
+// MakeUint32FromPtr creates a new Uint32 from a pointer. A nil pointer
+// results in NULL.
+func MakeUint32FromPtr(v *uint32) (a Uint32) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Uint32's value and also sets it to be non-null.
+func (a *Uint32) SetValid(v uint32) { a.Uint32 = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Uint32) Reset() { *a = Uint32{} }
+
+// Ptr returns a pointer to this Uint32's value, or a nil pointer if this
+// Uint32 is null.
+func (a Uint32) Ptr() *uint32 {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Uint32
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Uint32) SetPtr(v *uint32) {
+	*a = Uint32{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Uint32 is null.
+func (a Uint32) Or(def uint32) uint32 {
+	if !a.Valid {
+		return def
+	}
+	return a.Uint32
+}
+
+// IsZero returns true for a null Uint32, useful for omitempty
+// style logic. A non-null Uint32 with a zero value will not be considered
+// zero.
+func (a Uint32) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Uint32s are equal. Use SQLEqual for the semantics of the = operator.
+func (a Uint32) Equal(b Uint32) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Uint32 == b.Uint32)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Uint32
+// is never equal to another Uint32.
+func (a Uint32) SQLEqual(b Uint32) bool {
+	return a.Valid && b.Valid && a.Uint32 == b.Uint32
+}
+
+// SetProto sets the value of the protobuf wrapper type. A nil v results in
+// NULL.
+func (a *Uint32) SetProto(v *wrapperspb.UInt32Value) {
+	*a = Uint32{}
+	if v != nil {
+		a.SetValid(v.GetValue())
+	}
+}
+
+// Proto returns the value as protobuf wrapper type or nil if this Uint32
+// is null.
+func (a *Uint32) Proto() *wrapperspb.UInt32Value {
+	if a == nil || !a.Valid {
+		return nil
+	}
+	return wrapperspb.UInt32(a.Uint32)
+}
+
+// MakeUint64FromPtr creates a new Uint64 from a pointer. A nil pointer
+// results in NULL.
+func MakeUint64FromPtr(v *uint64) (a Uint64) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this Uint64's value and also sets it to be non-null.
+func (a *Uint64) SetValid(v uint64) { a.Uint64 = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *Uint64) Reset() { *a = Uint64{} }
+
+// Ptr returns a pointer to this Uint64's value, or a nil pointer if this
+// Uint64 is null.
+func (a Uint64) Ptr() *uint64 {
+	if !a.Valid {
+		return nil
+	}
+	return &a.Uint64
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *Uint64) SetPtr(v *uint64) {
+	*a = Uint64{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this Uint64 is null.
+func (a Uint64) Or(def uint64) uint64 {
+	if !a.Valid {
+		return def
+	}
+	return a.Uint64
+}
+
+// IsZero returns true for a null Uint64, useful for omitempty
+// style logic. A non-null Uint64 with a zero value will not be considered
+// zero.
+func (a Uint64) IsZero() bool {
+	return !a.Valid
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null Uint64s are equal. Use SQLEqual for the semantics of the = operator.
+func (a Uint64) Equal(b Uint64) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Uint64 == b.Uint64)
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null Uint64
+// is never equal to another Uint64.
+func (a Uint64) SQLEqual(b Uint64) bool {
+	return a.Valid && b.Valid && a.Uint64 == b.Uint64
+}
+
+// SetProto sets the value of the protobuf wrapper type. A nil v results in
+// NULL.
+func (a *Uint64) SetProto(v *wrapperspb.UInt64Value) {
+	*a = Uint64{}
+	if v != nil {
+		a.SetValid(v.GetValue())
+	}
+}
+
+// Proto returns the value as protobuf wrapper type or nil if this Uint64
+// is null.
+func (a *Uint64) Proto() *wrapperspb.UInt64Value {
+	if a == nil || !a.Valid {
+		return nil
+	}
+	return wrapperspb.UInt64(a.Uint64)
+}
@@ -0,0 +1,258 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"text/template"
+)
+
+// This file generates the common methods and their tests of all null types via
+// go:generate, so that the types cannot drift apart. Decimal has its own
+// implementation because it does not wrap a single Go value.
+
+type nullType struct {
+	Name   string // Name of the null type
+	Field  string // Field which contains the value
+	GoType string // GoType of the Field
+	// IsZero optional expression, defaults to !a.Valid
+	IsZero string
+	// Equal optional expression to compare a and b, defaults to ==
+	Equal string
+	// Sample and Other are two different non-zero values for the tests.
+	Sample string
+	Other  string
+	// Proto optional name of the wrapper type and its constructor in package
+	// wrapperspb.
+	Proto, ProtoFn string
+}
+
+var nullTypes = []nullType{
+	{Name: "Bool", Field: "Bool", GoType: "bool", Sample: "true", Other: "false", Proto: "BoolValue", ProtoFn: "Bool"},
+	{Name: "Float64", Field: "Float64", GoType: "float64", Sample: "3.14159", Other: "-2.7182", Proto: "DoubleValue", ProtoFn: "Double"},
+	{Name: "Int8", Field: "Int8", GoType: "int8", Sample: "math.MaxInt8", Other: "math.MinInt8"},
+	{Name: "Int16", Field: "Int16", GoType: "int16", Sample: "math.MaxInt16", Other: "math.MinInt16"},
+	{Name: "Int32", Field: "Int32", GoType: "int32", Sample: "math.MaxInt32", Other: "math.MinInt32", Proto: "Int32Value", ProtoFn: "Int32"},
+	{Name: "Int64", Field: "Int64", GoType: "int64", Sample: "math.MaxInt64", Other: "math.MinInt64", Proto: "Int64Value", ProtoFn: "Int64"},
+	{Name: "String", Field: "Data", GoType: "string", Sample: `"Gopher"`, Other: `"Ferris"`, Proto: "StringValue", ProtoFn: "String"},
+	{
+		Name: "Time", Field: "Time", GoType: "time.Time",
+		IsZero: "!a.Valid || a.Time.IsZero()",
+		Equal:  "a.Time.Equal(b.Time)",
+		Sample: "time.Unix(1600000000, 123456000).UTC()", Other: "time.Unix(1500000000, 0).UTC()",
+	},
+	{Name: "Uint8", Field: "Uint8", GoType: "uint8", Sample: "math.MaxUint8", Other: "1"},
+	{Name: "Uint16", Field: "Uint16", GoType: "uint16", Sample: "math.MaxUint16", Other: "1"},
+	{Name: "Uint32", Field: "Uint32", GoType: "uint32", Sample: "math.MaxUint32", Other: "1", Proto: "UInt32Value", ProtoFn: "UInt32"},
+	{Name: "Uint64", Field: "Uint64", GoType: "uint64", Sample: "math.MaxUint64", Other: "1", Proto: "UInt64Value", ProtoFn: "UInt64"},
+}
+
+const header = `// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by methods_main.go via go generate. DO NOT EDIT.
+
+package null
+`
+
+const tplMethods = header + `
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+{{ range . }}
+// Make{{.Name}}FromPtr creates a new {{.Name}} from a pointer. A nil pointer
+// results in NULL.
+func Make{{.Name}}FromPtr(v *{{.GoType}}) (a {{.Name}}) {
+	a.SetPtr(v)
+	return a
+}
+
+// SetValid changes this {{.Name}}'s value and also sets it to be non-null.
+func (a *{{.Name}}) SetValid(v {{.GoType}}) { a.{{.Field}} = v; a.Valid = true }
+
+// Reset sets the value to Go's default value and Valid to false.
+func (a *{{.Name}}) Reset() { *a = {{.Name}}{} }
+
+// Ptr returns a pointer to this {{.Name}}'s value, or a nil pointer if this
+// {{.Name}} is null.
+func (a {{.Name}}) Ptr() *{{.GoType}} {
+	if !a.Valid {
+		return nil
+	}
+	return &a.{{.Field}}
+}
+
+// SetPtr sets the value of v and Valid to true. A nil v results in NULL.
+func (a *{{.Name}}) SetPtr(v *{{.GoType}}) {
+	*a = {{.Name}}{}
+	if v != nil {
+		a.SetValid(*v)
+	}
+}
+
+// Or returns the value or def if this {{.Name}} is null.
+func (a {{.Name}}) Or(def {{.GoType}}) {{.GoType}} {
+	if !a.Valid {
+		return def
+	}
+	return a.{{.Field}}
+}
+
+// IsZero returns true for a null {{.Name}}{{ if .IsZero }} or a zero value{{ end }}, useful for omitempty
+// style logic.{{ if not .IsZero }} A non-null {{.Name}} with a zero value will not be considered
+// zero.{{ end }}
+func (a {{.Name}}) IsZero() bool {
+	return {{ if .IsZero }}{{ .IsZero }}{{ else }}!a.Valid{{ end }}
+}
+
+// Equal compares the values like the NULL-safe SQL operator <=>, hence two
+// null {{.Name}}s are equal. Use SQLEqual for the semantics of the = operator.
+func (a {{.Name}}) Equal(b {{.Name}}) bool {
+	return a.Valid == b.Valid && (!a.Valid || {{ if .Equal }}{{ .Equal }}{{ else }}a.{{.Field}} == b.{{.Field}}{{ end }})
+}
+
+// SQLEqual compares the values like the SQL operator =, hence a null {{.Name}}
+// is never equal to another {{.Name}}.
+func (a {{.Name}}) SQLEqual(b {{.Name}}) bool {
+	return a.Valid && b.Valid && {{ if .Equal }}{{ .Equal }}{{ else }}a.{{.Field}} == b.{{.Field}}{{ end }}
+}
+{{ if .Proto }}
+// SetProto sets the value of the protobuf wrapper type. A nil v results in
+// NULL.
+func (a *{{.Name}}) SetProto(v *wrapperspb.{{.Proto}}) {
+	*a = {{.Name}}{}
+	if v != nil {
+		a.SetValid(v.GetValue())
+	}
+}
+
+// Proto returns the value as protobuf wrapper type or nil if this {{.Name}}
+// is null.
+func (a *{{.Name}}) Proto() *wrapperspb.{{.Proto}} {
+	if a == nil || !a.Valid {
+		return nil
+	}
+	return wrapperspb.{{.ProtoFn}}(a.{{.Field}})
+}
+{{ end }}{{ end }}`
+
+const tplTests = header + `
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+{{ range . }}
+func Test{{.Name}}_Methods(t *testing.T) {
+	sample := {{.GoType}}({{.Sample}})
+	other := {{.GoType}}({{.Other}})
+
+	t.Run("From", func(t *testing.T) {
+		assert.Exactly(t, Make{{.Name}}(sample), Make{{.Name}}FromPtr(&sample))
+		assert.Exactly(t, {{.Name}}{}, Make{{.Name}}FromPtr(nil))
+	})
+	t.Run("Ptr", func(t *testing.T) {
+		assert.Exactly(t, sample, *Make{{.Name}}(sample).Ptr())
+		assert.Nil(t, {{.Name}}{}.Ptr())
+	})
+	t.Run("SetPtr", func(t *testing.T) {
+		a := Make{{.Name}}(other)
+		a.SetPtr(&sample)
+		assert.Exactly(t, Make{{.Name}}(sample), a)
+		a.SetPtr(nil)
+		assert.Exactly(t, {{.Name}}{}, a)
+	})
+	t.Run("SetValid and Reset", func(t *testing.T) {
+		var a {{.Name}}
+		a.SetValid(sample)
+		assert.Exactly(t, Make{{.Name}}(sample), a)
+		a.Reset()
+		assert.Exactly(t, {{.Name}}{}, a)
+	})
+	t.Run("Or", func(t *testing.T) {
+		assert.Exactly(t, sample, Make{{.Name}}(sample).Or(other))
+		assert.Exactly(t, other, {{.Name}}{}.Or(other))
+	})
+	t.Run("IsZero", func(t *testing.T) {
+		assert.True(t, {{.Name}}{}.IsZero())
+		assert.False(t, Make{{.Name}}(sample).IsZero())
+	})
+	t.Run("Equal", func(t *testing.T) {
+		assert.True(t, Make{{.Name}}(sample).Equal(Make{{.Name}}(sample)))
+		assert.False(t, Make{{.Name}}(sample).Equal(Make{{.Name}}(other)))
+		assert.False(t, Make{{.Name}}(sample).Equal({{.Name}}{}))
+		assert.True(t, {{.Name}}{}.Equal({{.Name}}{}))
+	})
+	t.Run("SQLEqual", func(t *testing.T) {
+		assert.True(t, Make{{.Name}}(sample).SQLEqual(Make{{.Name}}(sample)))
+		assert.False(t, Make{{.Name}}(sample).SQLEqual(Make{{.Name}}(other)))
+		assert.False(t, Make{{.Name}}(sample).SQLEqual({{.Name}}{}))
+		assert.False(t, {{.Name}}{}.SQLEqual({{.Name}}{}))
+	}){{ if .Proto }}
+	t.Run("Proto", func(t *testing.T) {
+		a := Make{{.Name}}(sample)
+		assert.Exactly(t, sample, a.Proto().GetValue())
+		assert.Nil(t, (&{{.Name}}{}).Proto())
+		assert.Nil(t, (*{{.Name}})(nil).Proto())
+
+		b := Make{{.Name}}(other)
+		b.SetProto(a.Proto())
+		assert.Exactly(t, a, b)
+		b.SetProto(nil)
+		assert.Exactly(t, {{.Name}}{}, b)
+	}){{ end }}
+}
+{{ end }}`
+
+func main() {
+	write("methods_gen.go", tplMethods)
+	write("methods_gen_test.go", tplTests)
+}
+
+func write(fileName, tpl string) {
+	var buf bytes.Buffer
+	if err := template.Must(template.New(fileName).Parse(tpl)).Execute(&buf, nullTypes); err != nil {
+		log.Fatalf("%s: %s", fileName, err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("%s: %s\n%s", fileName, err, buf.Bytes())
+	}
+	if err := ioutil.WriteFile(fileName, src, 0644); err != nil {
+		log.Fatalf("%s: %s", fileName, err)
+	}
+}
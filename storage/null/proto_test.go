@@ -0,0 +1,102 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package null
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/pseudo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type protoTypes struct {
+	Bool    Bool
+	Float64 Float64
+	Int32   Int32
+	Int64   Int64
+	String  String
+	Time    Time
+	Uint32  Uint32
+	Uint64  Uint64
+}
+
+// protoRoundTrip converts all fields to their protobuf type and back.
+func (p protoTypes) protoRoundTrip() (r protoTypes) {
+	r.Bool.SetProto(p.Bool.Proto())
+	r.Float64.SetProto(p.Float64.Proto())
+	r.Int32.SetProto(p.Int32.Proto())
+	r.Int64.SetProto(p.Int64.Proto())
+	r.String.SetProto(p.String.Proto())
+	r.Time.SetProto(p.Time.Proto())
+	r.Uint32.SetProto(p.Uint32.Proto())
+	r.Uint64.SetProto(p.Uint64.Proto())
+	return r
+}
+
+func (p protoTypes) assertEqual(t *testing.T, r protoTypes) {
+	assert.True(t, p.Bool.Equal(r.Bool), "Bool %v %v", p.Bool, r.Bool)
+	assert.True(t, p.Float64.Equal(r.Float64), "Float64 %v %v", p.Float64, r.Float64)
+	assert.True(t, p.Int32.Equal(r.Int32), "Int32 %v %v", p.Int32, r.Int32)
+	assert.True(t, p.Int64.Equal(r.Int64), "Int64 %v %v", p.Int64, r.Int64)
+	assert.True(t, p.String.Equal(r.String), "String %v %v", p.String, r.String)
+	assert.True(t, p.Time.Equal(r.Time), "Time %v %v", p.Time, r.Time)
+	assert.True(t, p.Uint32.Equal(r.Uint32), "Uint32 %v %v", p.Uint32, r.Uint32)
+	assert.True(t, p.Uint64.Equal(r.Uint64), "Uint64 %v %v", p.Uint64, r.Uint64)
+}
+
+func TestProto_RoundTrip(t *testing.T) {
+	t.Run("random values", func(t *testing.T) {
+		ps := pseudo.MustNewService(0, &pseudo.Options{RespectValidField: true})
+		for i := 0; i < 1000; i++ {
+			var p protoTypes
+			assert.NoError(t, ps.FakeData(&p))
+			// sub-second precision, pseudo generates only full seconds.
+			if p.Time.Valid {
+				p.Time.Time = p.Time.Time.Add(time.Duration(p.Int64.Int64 % int64(time.Second)))
+			}
+			p.assertEqual(t, p.protoRoundTrip())
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var p protoTypes
+		assert.Exactly(t, p, p.protoRoundTrip())
+		assert.Nil(t, p.Time.Proto())
+		assert.Nil(t, (*Time)(nil).Proto())
+	})
+
+	t.Run("time", func(t *testing.T) {
+		for _, tm := range []time.Time{
+			{},
+			time.Unix(0, 1).UTC(),
+			time.Unix(-1, 999999999).UTC(),
+			time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+			time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.FixedZone("CET", 3600)),
+		} {
+			p := protoTypes{Time: MakeTime(tm)}
+			pb := p.Time.Proto()
+			assert.NoError(t, pb.CheckValid(), "%s", tm)
+			r := p.protoRoundTrip()
+			assert.True(t, r.Time.Valid, "%s", tm)
+			assert.True(t, tm.Equal(r.Time.Time), "want %s have %s", tm, r.Time.Time)
+			assert.Exactly(t, tm.Nanosecond(), r.Time.Time.Nanosecond())
+		}
+		var nt Time
+		nt.SetProto(&timestamppb.Timestamp{})
+		assert.Exactly(t, MakeTime(time.Unix(0, 0).UTC()), nt)
+	})
+}
@@ -160,50 +160,34 @@ func (a String) MarshalText() ([]byte, error) {
 // UnmarshalText implements encoding.TextUnmarshaler.
 // It will unmarshal to a null String if the input is a blank string.
 func (a *String) UnmarshalText(text []byte) error {
-	if text != nil && !utf8.Valid(text) {
+	if !utf8.Valid(text) {
 		return errors.NotValid.Newf("[dml] Input bytes are not valid UTF-8 encoded.")
 	}
 	a.Data = string(text)
-	a.Valid = text != nil
+	a.Valid = len(text) > 0
 	return nil
 }
 
-// SetValid changes this String's value and also sets it to be non-null.
-func (a *String) SetValid(v string) { a.Data = v; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *String) Reset() { *a = String{} }
-
-// Ptr returns a pointer to this String's value, or a nil pointer if this String is null.
-func (a String) Ptr() *string {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Data
-}
-
-// SetPtr sets v according to the rules.
-func (a *String) SetPtr(v *string) {
-	a.Valid = v != nil
-	a.Data = ""
-	if v != nil {
-		a.Data = *v
-	}
-}
-
-// IsZero returns true for null strings, for potential future omitempty support.
-func (a String) IsZero() bool {
-	return !a.Valid
-}
-
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *String) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("String", data, -1)
+	if err != nil {
+		return err
+	}
+	*a = String{Data: string(v), Valid: valid}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The raw
+// bytes of the string follow the version and validity byte. In contrast to
+// the text encoding an empty string stays valid.
 func (a String) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	if !a.Valid {
+		return marshalBinaryHeader(false, 0), nil
+	}
+	data = marshalBinaryHeader(true, len(a.Data))
+	copy(data[binaryHeaderLen:], a.Data)
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
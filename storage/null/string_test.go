@@ -101,7 +101,7 @@ func TestNullString_TextUnmarshal(t *testing.T) {
 	var null String
 	err = null.UnmarshalText([]byte(""))
 	maybePanic(err)
-	assertEmptyStr(t, null, "UnmarshalText() empty string")
+	assertNullStr(t, null, "UnmarshalText() empty string")
 
 	var null2 String
 	err = null2.UnmarshalText(nil)
@@ -137,18 +137,15 @@ func TestNullString_MarshalText(t *testing.T) {
 	assert.Nil(t, data)
 }
 
-func TestNullString_BinaryEncoding(t *testing.T) {
+func TestNullString_ProtoEncoding(t *testing.T) {
 	runner := func(b String, want []byte) func(*testing.T) {
 		return func(t *testing.T) {
-			data, err := b.MarshalBinary()
-			assert.NoError(t, err)
-			assert.Exactly(t, want, data, t.Name()+": MarshalBinary %q", data)
-			data, err = b.Marshal()
+			data, err := b.Marshal()
 			assert.NoError(t, err)
 			assert.Exactly(t, want, data, t.Name()+": Marshal %q", data)
 
 			var decoded String
-			assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary")
+			assert.NoError(t, decoded.Unmarshal(data), "Unmarshal")
 			assert.Exactly(t, b, decoded)
 		}
 	}
@@ -17,6 +17,8 @@ package null
 import (
 	"bytes"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -56,12 +58,13 @@ func (a Time) GoString() string {
 }
 
 // MarshalJSON implements json.Marshaler.
-// It will encode null if this time is null.
+// It will encode null if this time is null. The time gets encoded as
+// RFC3339Nano.
 func (a Time) MarshalJSON() ([]byte, error) {
 	if !a.Valid {
 		return bTextNullLC, nil
 	}
-	return a.Time.MarshalJSON()
+	return AppendJSONTime(nil, a.Time), nil
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
@@ -80,14 +83,14 @@ func (a *Time) UnmarshalJSON(data []byte) error {
 	}
 	switch x := v.(type) {
 	case string:
-		err = a.Time.UnmarshalJSON(data)
+		a.Time, err = time.Parse(time.RFC3339Nano, x)
 	case map[string]interface{}:
 		ti, tiOK := x["Time"].(string)
 		valid, validOK := x["Valid"].(bool)
 		if !tiOK || !validOK {
 			return errors.NotValid.Newf(`[dml] json: unmarshalling object into Go value of type null.Time requires key "Time" to be of type string and key "Valid" to be of type bool; found %T and %T, respectively`, x["Time"], x["Valid"])
 		}
-		err = a.Time.UnmarshalText([]byte(ti))
+		a.Time, err = time.Parse(time.RFC3339Nano, ti)
 		a.Valid = valid
 		return err
 	case nil:
@@ -100,71 +103,99 @@ func (a *Time) UnmarshalJSON(data []byte) error {
 	return err
 }
 
-// MarshalText transforms the time type into a byte slice.
+// Value implements the driver Valuer interface. The time keeps its fractional
+// seconds.
+func (a Time) Value() (driver.Value, error) {
+	if !a.Valid {
+		return nil, nil
+	}
+	return a.Time, nil
+}
+
+// MarshalText implements encoding.TextMarshaler and formats the time as
+// RFC3339Nano. It will encode a blank string if this Time is null.
 func (a Time) MarshalText() ([]byte, error) {
 	if !a.Valid {
-		return []byte(sqlStrNullLC), nil
+		return []byte{}, nil
 	}
-	return a.Time.MarshalText()
+	return a.Time.AppendFormat(make([]byte, 0, len(time.RFC3339Nano)), time.RFC3339Nano), nil
 }
 
-// UnmarshalText parses the byte slice to create a time type.
+// UnmarshalText implements encoding.TextUnmarshaler and parses RFC3339Nano.
+// A blank string or "null" results in a null Time.
 func (a *Time) UnmarshalText(text []byte) error {
+	*a = Time{}
 	if len(text) == 0 || bytes.Equal(bTextNullLC, text) {
-		a.Valid = false
-		a.Time = time.Time{}
 		return nil
 	}
-	if err := a.Time.UnmarshalText(text); err != nil {
-		return err
+	t, err := time.Parse(time.RFC3339Nano, string(text))
+	if err != nil {
+		return errors.NotValid.New(err, "[null] Time.UnmarshalText: Invalid time %q", text)
 	}
-	a.Valid = true
+	a.SetValid(t)
 	return nil
 }
 
-// MarshalBinary transforms the time type into a byte slice.
+// timeBinaryLen defines the fixed length of the binary time: seconds since
+// the Unix epoch, nanoseconds and the zone offset in minutes.
+const timeBinaryLen = 8 + 4 + 2
+
+// timeBinaryUTC marks the UTC location in the binary zone offset.
+const timeBinaryUTC = -1
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The time
+// gets written with a fixed width of 14 bytes after the version and validity
+// byte: the seconds since the Unix epoch, the nanoseconds and the zone offset
+// in minutes, all big endian. Only the zone offset gets preserved, not the
+// name of the location, like time.Time.MarshalBinary does.
 func (a Time) MarshalBinary() (data []byte, err error) {
+	data = marshalBinaryHeader(a.Valid, timeBinaryLen)
 	if !a.Valid {
 		return data, nil
 	}
-	return a.NullTime.Time.MarshalBinary()
+	offsetMin := int16(timeBinaryUTC)
+	if a.Time.Location() != time.UTC {
+		_, offset := a.Time.Zone()
+		if offset%60 != 0 {
+			return nil, errors.NotSupported.Newf("[null] Time.MarshalBinary: Zone offset %ds has fractional minutes", offset)
+		}
+		offsetMin = int16(offset / 60)
+	}
+	v := data[binaryHeaderLen:]
+	binary.BigEndian.PutUint64(v, uint64(a.Time.Unix()))
+	binary.BigEndian.PutUint32(v[8:], uint32(a.Time.Nanosecond()))
+	binary.BigEndian.PutUint16(v[12:], uint16(offsetMin))
+	return data, nil
 }
 
-// UnmarshalBinary parses the byte slice to create a time type.
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. If the
+// zone offset matches the offset of time.Local, the location gets set to
+// time.Local.
 func (a *Time) UnmarshalBinary(data []byte) error {
-	if len(data) == 0 {
-		a.Valid = false
-		return nil
+	v, valid, err := unmarshalBinaryHeader("Time", data, timeBinaryLen)
+	if err != nil {
+		return err
 	}
-	err := a.Time.UnmarshalBinary(data)
-	a.Valid = err == nil
-	return err
-}
-
-// SetValid changes this Time's value and sets it to be non-null.
-func (a *Time) SetValid(v time.Time) { a.Time = v; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Time) Reset() { *a = Time{} }
-
-// Ptr returns a pointer to this Time's value, or a nil pointer if this Time is
-// null.
-func (a Time) Ptr() *time.Time {
-	if !a.Valid {
+	*a = Time{}
+	if !valid {
 		return nil
 	}
-	return &a.Time
-}
-
-// SetPtr sets v according to the rules.
-func (a *Time) SetPtr(v *time.Time) {
-	a.Valid = v != nil
-	a.Time = time.Time{}
-	if v != nil {
-		a.Time = *v
+	t := time.Unix(int64(binary.BigEndian.Uint64(v)), int64(binary.BigEndian.Uint32(v[8:])))
+	offsetMin := int16(binary.BigEndian.Uint16(v[12:]))
+	switch _, localOffset := t.In(time.Local).Zone(); {
+	case offsetMin == timeBinaryUTC:
+		t = t.UTC()
+	case localOffset == int(offsetMin)*60:
+		t = t.In(time.Local)
+	default:
+		t = t.In(time.FixedZone("", int(offsetMin)*60))
 	}
+	a.SetValid(t)
+	return nil
 }
 
+// SetProto sets the value of the protobuf timestamp. A nil v results in NULL.
+// The time gets set in UTC.
 func (a *Time) SetProto(v *timestamppb.Timestamp) {
 	a.Valid = v != nil
 	a.Time = time.Time{}
@@ -173,6 +204,8 @@ func (a *Time) SetProto(v *timestamppb.Timestamp) {
 	}
 }
 
+// Proto returns the value as protobuf timestamp or nil if this Time is null.
+// The nanoseconds get preserved, the location not.
 func (a *Time) Proto() *timestamppb.Timestamp {
 	if a == nil || !a.Valid {
 		return nil
@@ -38,9 +38,25 @@ type Time struct {
 	sql.NullTime
 }
 
+// TimeLocation defines the location used by Time.Scan to parse date time
+// strings without a time zone, e.g. a DATETIME(6) column without parseTime=true
+// in the DSN. It must match the loc parameter of the DSN. Set it once during
+// the initialization of the program.
+var TimeLocation = time.UTC
+
+const (
+	zeroDateTime   = "0000-00-00 00:00:00.000000000"
+	timeLayoutDate = "2006-01-02"
+	// timeLayoutNaive parses also fractional seconds.
+	timeLayoutNaive = "2006-01-02 15:04:05"
+	timeLayoutZone  = "2006-01-02 15:04:05Z07:00"
+)
+
 // Scan implements the Scanner interface. The value type must be time.Time or
 // string / []byte (formatted time-string), otherwise Scan fails. It supports
-// more input data than database/sql.NullTime.Scan
+// more input data than database/sql.NullTime.Scan. Fractional seconds get
+// preserved. Strings without a time zone get parsed in TimeLocation. A zero
+// date like 0000-00-00 results in NULL.
 func (a *Time) Scan(value interface{}) (err error) {
 	a.Time, a.Valid = time.Time{}, false
 	if value == nil {
@@ -49,53 +65,47 @@ func (a *Time) Scan(value interface{}) (err error) {
 
 	switch v := value.(type) {
 	case time.Time:
-		a.Time = v
+		a.Time, a.Valid = v, true
 	case []byte:
 		if v == nil {
 			return
 		}
-		*a, err = ParseDateTime(string(v), time.UTC)
+		*a, err = ParseDateTime(string(v), TimeLocation)
 	case string:
 		if v == "" {
 			return
 		}
-		*a, err = ParseDateTime(v, time.UTC)
+		*a, err = ParseDateTime(v, TimeLocation)
 	default:
 		err = errors.NotValid.Newf("[dml] Can't convert %T to time.Time. Maybe not yet implemented.", value)
 	}
-	a.Valid = err == nil
 	return
 }
 
-// ParseDateTime parses a string into a Time type. Empty string is considered NULL.
+// ParseDateTime parses a string into a Time type. Empty string and the zero
+// date 0000-00-00 with optional time are considered NULL. Strings without a
+// time zone get parsed in loc, a nil loc means UTC. Fractional seconds get
+// preserved.
 func ParseDateTime(str string, loc *time.Location) (t Time, err error) {
-	if str == "" {
+	if str == "" || (len(str) <= len(zeroDateTime) && str == zeroDateTime[:len(str)]) {
 		return
 	}
-	zeroBase := "0000-00-00 00:00:00.000000000+00:00"
-	base := "2006-01-02 15:04:05.999999999 07:00"
-	if strings.IndexByte(str, 'T') > 0 {
-		base = time.RFC3339Nano
+	if loc == nil {
+		loc = time.UTC
 	}
 
-	switch lStr := len(str); lStr {
-	case 10, 19, 21, 22, 23, 24, 25, 26, 27, 28, 29, 35: // up to "YYYY-MM-DD HH:MM:SS.MMMMMMM+HH:II"
-		if str == zeroBase[:lStr] {
-			return
-		}
-		t.Time, err = time.Parse(base[:lStr], str) // time.RFC3339Nano cannot be used due to the T
-		t.Valid = err == nil
-	default:
-		err = errors.NotValid.Newf("invalid time string: %q", str)
-		return
+	layout := timeLayoutNaive
+	switch {
+	case len(str) == len(timeLayoutDate):
+		layout = timeLayoutDate
+	case strings.IndexByte(str, 'T') > 0:
+		layout = time.RFC3339Nano
+	case len(str) > len(timeLayoutNaive) && strings.ContainsAny(str[len(timeLayoutNaive):], "Z+-"):
+		layout = timeLayoutZone
 	}
-
-	// Adjust location
-	if err == nil && loc != time.UTC {
-		y, mo, d := t.Time.Date()
-		h, mi, s := t.Time.Clock()
-		t.Time, err = time.Date(y, mo, d, h, mi, s, t.Time.Nanosecond(), loc), nil
+	if t.Time, err = time.ParseInLocation(layout, str, loc); err != nil {
+		return Time{}, errors.NotValid.New(err, "[null] Invalid time string: %q", str)
 	}
-
+	t.Valid = true
 	return
 }
@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/util/assert"
 )
 
@@ -108,7 +109,7 @@ func TestNullTime_UnmarshalText(t *testing.T) {
 	assertNullTime(t, null, "unmarshal null text")
 	txt, err = null.MarshalText()
 	maybePanic(err)
-	assertJSONEquals(t, txt, string(nullJSON), "marshal null text")
+	assertJSONEquals(t, txt, "", "marshal null text")
 
 	var invalid Time
 	err = invalid.UnmarshalText([]byte("hello world"))
@@ -130,18 +131,15 @@ func TestNullTime_JsonMarshal(t *testing.T) {
 	assertJSONEquals(t, data, string(nullJSON), "null json marshal")
 }
 
-func TestNullTime_BinaryEncoding(t *testing.T) {
-	runner := func(nv Time, wantMB, wantM []byte) func(*testing.T) {
+func TestNullTime_ProtoEncoding(t *testing.T) {
+	runner := func(nv Time, want []byte) func(*testing.T) {
 		return func(t *testing.T) {
-			dataMB, err := nv.MarshalBinary()
+			data, err := nv.Marshal()
 			assert.NoError(t, err)
-			assert.Exactly(t, wantMB, dataMB, t.Name()+": MarshalBinary %q", dataMB)
-			dataM, err := nv.Marshal()
-			assert.NoError(t, err)
-			assert.Exactly(t, wantM, dataM, t.Name()+": Marshal %q", dataM)
+			assert.Exactly(t, want, data, t.Name()+": Marshal %q", data)
 
 			var decoded Time
-			assert.NoError(t, decoded.UnmarshalBinary(dataMB), "UnmarshalBinary")
+			assert.NoError(t, decoded.Unmarshal(data), "Unmarshal")
 
 			haveS := nv.String()
 			wantS := decoded.String()
@@ -158,10 +156,9 @@ func TestNullTime_BinaryEncoding(t *testing.T) {
 	}
 	t.Run("now fixed", runner(
 		MakeTime(now()),
-		[]byte("\x01\x00\x00\x00\x0e\xbbK7\xe5\x00\x00\x00\x02\x00\x00"),
 		[]byte{0x1, 0x0, 0x0, 0x0, 0xe, 0xbb, 0x4b, 0x37, 0xe5, 0x0, 0x0, 0x0, 0x2, 0x0, 0x0},
 	))
-	t.Run("null", runner(Time{}, nil, nil))
+	t.Run("null", runner(Time{}, nil))
 }
 
 func TestTimeFrom(t *testing.T) {
@@ -245,3 +242,60 @@ func TestNewNullTime(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, test, v)
 }
+
+func TestParseDateTime(t *testing.T) {
+	defer func(loc *time.Location) { TimeLocation = loc }(TimeLocation)
+	cest := time.FixedZone("CEST", 2*3600)
+
+	tests := []struct {
+		str  string
+		loc  *time.Location
+		want Time
+	}{
+		{"", nil, Time{}},
+		{"0000-00-00", nil, Time{}},
+		{"0000-00-00 00:00:00", nil, Time{}},
+		{"0000-00-00 00:00:00.000000", nil, Time{}},
+		{"2021-03-04", nil, MakeTime(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC))},
+		{"2021-03-04 05:06:07", nil, MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))},
+		{"2021-03-04 05:06:07.1", nil, MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 100000000, time.UTC))},
+		{"2021-03-04 05:06:07.123456", nil, MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 123456000, time.UTC))},
+		{"2021-03-04 05:06:07.123456", cest, MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 123456000, cest))},
+		{"2021-03-04 05:06:07.123456+02:00", time.UTC, MakeTime(time.Date(2021, 3, 4, 3, 6, 7, 123456000, time.UTC))},
+		{"2021-03-04T05:06:07.123456789Z", cest, MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.UTC))},
+	}
+	for _, test := range tests {
+		have, err := ParseDateTime(test.str, test.loc)
+		assert.NoError(t, err, "%q", test.str)
+		assert.Exactly(t, test.want.Valid, have.Valid, "%q", test.str)
+		assert.True(t, test.want.Time.Equal(have.Time), "%q: want %s have %s", test.str, test.want.Time, have.Time)
+	}
+
+	_, err := ParseDateTime("2021-13-04 05:06:07", nil)
+	assert.ErrorIsKind(t, errors.NotValid, err)
+
+	t.Run("Scan with TimeLocation", func(t *testing.T) {
+		TimeLocation = cest
+		var nt Time
+		assert.NoError(t, nt.Scan([]byte("2021-03-04 05:06:07.000001")))
+		assert.Exactly(t, time.Date(2021, 3, 4, 5, 6, 7, 1000, cest).String(), nt.Time.String())
+		assert.True(t, nt.Valid)
+		assert.NoError(t, nt.Scan("0000-00-00 00:00:00"))
+		assert.False(t, nt.Valid)
+
+		v, err := MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 1000, cest)).Value()
+		assert.NoError(t, err)
+		assert.Exactly(t, 1000, v.(time.Time).Nanosecond())
+	})
+}
+
+func TestTime_JSONNano(t *testing.T) {
+	want := MakeTime(time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.UTC))
+	data, err := json.Marshal(want)
+	assert.NoError(t, err)
+	assert.Exactly(t, `"2021-03-04T05:06:07.123456789Z"`, string(data))
+
+	var have Time
+	assert.NoError(t, json.Unmarshal(data, &have))
+	assert.Exactly(t, want, have)
+}
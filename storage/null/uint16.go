@@ -17,6 +17,7 @@ package null
 import (
 	"bytes"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"math"
 	"strconv"
@@ -56,27 +57,12 @@ func MakeUint16FromByte(data []byte) (nv Uint16, err error) {
 }
 
 // Scan implements the Scanner interface. Approx. >3x times faster than
-// database/sql.convertAssign
+// database/sql.convertAssign. It accepts the integer, []byte and string
+// representations of the driver. Negative values return an error.
 func (a *Uint16) Scan(value interface{}) (err error) {
-	if value == nil {
-		a.Uint16, a.Valid = 0, false
-		return nil
-	}
-	switch v := value.(type) {
-	case []byte:
-		var i64 uint64
-		i64, a.Valid, err = byteconv.ParseUint(v, 10, 16)
-		a.Valid = err == nil
-		a.Uint16 = uint16(i64)
-	case int16:
-		a.Uint16 = uint16(v)
-		a.Valid = true
-	case int:
-		a.Uint16 = uint16(v)
-		a.Valid = true
-	default:
-		err = errors.NotSupported.Newf("[dml] Type %T not supported in Uint16.Scan", value)
-	}
+	var n uint64
+	n, a.Valid, err = scanUint(value, 16, "Uint16")
+	a.Uint16 = uint16(n)
 	return
 }
 
@@ -171,56 +157,35 @@ func (a Uint16) MarshalText() ([]byte, error) {
 	return strconv.AppendUint([]byte{}, uint64(a.Uint16), 10), nil
 }
 
-// SetValid changes this Uint16's value and also sets it to be non-null.
-func (a *Uint16) SetValid(n uint16) { a.Uint16 = n; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Uint16) Reset() { *a = Uint16{} }
-
-// Ptr returns a pointer to this Uint16's value, or a nil pointer if this Uint16 is null.
-func (a Uint16) Ptr() *uint16 {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Uint16
-}
-
-// SetPtr sets v according to the rules.
-func (a *Uint16) SetPtr(v *uint16) {
-	a.Valid = v != nil
-	a.Uint16 = 0
-	if v != nil {
-		a.Uint16 = *v
-	}
-}
-
-// IsZero returns true for invalid Uint16's, for future omitempty support (Go 1.4?)
-// A non-null Uint16 with a 0 value will not be considered zero.
-func (a Uint16) IsZero() bool {
-	return !a.Valid
-}
-
 // Value implements the driver.Valuer interface.
 func (a Uint16) Value() (driver.Value, error) {
 	if !a.Valid {
 		return nil, nil
 	}
-
-	const maxInt16 = 1<<16 - 1
-	if a.Uint16 < maxInt16 {
-		return int16(a.Uint16), nil
-	}
-	return strconv.AppendUint([]byte{}, uint64(a.Uint16), 10), nil
+	return int64(a.Uint16), nil
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Uint16) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Uint16", data, 2)
+	if err != nil {
+		return err
+	}
+	*a = Uint16{}
+	if valid {
+		a.SetValid(binary.BigEndian.Uint16(v))
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written big endian with 2 bytes after the version and validity byte.
 func (a Uint16) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 2)
+	if a.Valid {
+		binary.BigEndian.PutUint16(data[binaryHeaderLen:], a.Uint16)
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
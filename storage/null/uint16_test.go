@@ -167,18 +167,15 @@ func TestNullUint16_MarshalText(t *testing.T) {
 	assertJSONEquals(t, data, "", "null text marshal")
 }
 
-func TestNullUint16_BinaryEncoding(t *testing.T) {
+func TestNullUint16_ProtoEncoding(t *testing.T) {
 	runner := func(b Uint16, want []byte) func(*testing.T) {
 		return func(t *testing.T) {
-			data, err := b.MarshalBinary()
-			assert.NoError(t, err)
-			assert.Exactly(t, want, data, t.Name()+": MarshalBinary: %q", data)
-			data, err = b.Marshal()
+			data, err := b.Marshal()
 			assert.NoError(t, err)
 			assert.Exactly(t, want, data, t.Name()+": Marshal: %q", data)
 
 			var decoded Uint16
-			assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary")
+			assert.NoError(t, decoded.Unmarshal(data), "Unmarshal")
 			assert.Exactly(t, b, decoded)
 		}
 	}
@@ -289,9 +286,24 @@ func TestNullUint16_Scan(t *testing.T) {
 		assert.NoError(t, nv.Scan(int(math.MaxInt16)))
 		assert.Exactly(t, MakeUint16(math.MaxInt16), nv)
 	})
-	t.Run("string unsupported", func(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		var nv Uint16
+		assert.NoError(t, nv.Scan("65535"))
+		assert.Exactly(t, MakeUint16(math.MaxUint16), nv)
+	})
+	t.Run("int64 negative", func(t *testing.T) {
+		var nv Uint16
+		assert.ErrorIsKind(t, errors.Overflowed, nv.Scan(int64(-1)))
+		assert.Exactly(t, Uint16{}, nv)
+	})
+	t.Run("overflow", func(t *testing.T) {
+		var nv Uint16
+		assert.Error(t, nv.Scan(int64(math.MaxUint16+1)))
+		assert.Exactly(t, Uint16{}, nv)
+	})
+	t.Run("float64 unsupported", func(t *testing.T) {
 		var nv Uint16
-		err := nv.Scan(`1234567`)
+		err := nv.Scan(1.5)
 		assert.True(t, errors.MatchKind(err, errors.NotSupported), "Error behaviour should be errors.NotSupported")
 		assert.Exactly(t, Uint16{}, nv)
 	})
@@ -17,6 +17,7 @@ package null
 import (
 	"bytes"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"math"
 	"strconv"
@@ -56,27 +57,12 @@ func MakeUint32FromByte(data []byte) (nv Uint32, err error) {
 }
 
 // Scan implements the Scanner interface. Approx. >3x times faster than
-// database/sql.convertAssign
+// database/sql.convertAssign. It accepts the integer, []byte and string
+// representations of the driver. Negative values return an error.
 func (a *Uint32) Scan(value interface{}) (err error) {
-	if value == nil {
-		a.Uint32, a.Valid = 0, false
-		return nil
-	}
-	switch v := value.(type) {
-	case []byte:
-		var i64 uint64
-		i64, a.Valid, err = byteconv.ParseUint(v, 10, 32)
-		a.Valid = err == nil
-		a.Uint32 = uint32(i64)
-	case int32:
-		a.Uint32 = uint32(v)
-		a.Valid = true
-	case int:
-		a.Uint32 = uint32(v)
-		a.Valid = true
-	default:
-		err = errors.NotSupported.Newf("[dml] Type %T not supported in Uint32.Scan", value)
-	}
+	var n uint64
+	n, a.Valid, err = scanUint(value, 32, "Uint32")
+	a.Uint32 = uint32(n)
 	return
 }
 
@@ -171,55 +157,35 @@ func (a Uint32) MarshalText() ([]byte, error) {
 	return strconv.AppendUint([]byte{}, uint64(a.Uint32), 10), nil
 }
 
-// SetValid changes this Uint32's value and also sets it to be non-null.
-func (a *Uint32) SetValid(n uint32) { a.Uint32 = n; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Uint32) Reset() { *a = Uint32{} }
-
-// Ptr returns a pointer to this Uint32's value, or a nil pointer if this Uint32 is null.
-func (a Uint32) Ptr() *uint32 {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Uint32
-}
-
-// SetPtr sets v according to the rules.
-func (a *Uint32) SetPtr(v *uint32) {
-	a.Valid = v != nil
-	a.Uint32 = 0
-	if v != nil {
-		a.Uint32 = *v
-	}
-}
-
-// IsZero returns true for invalid Uint32's, for future omitempty support (Go 1.4?)
-// A non-null Uint32 with a 0 value will not be considered zero.
-func (a Uint32) IsZero() bool {
-	return !a.Valid
-}
-
 // Value implements the driver.Valuer interface.
 func (a Uint32) Value() (driver.Value, error) {
 	if !a.Valid {
 		return nil, nil
 	}
-	const maxInt32 = 1<<31 - 1
-	if a.Uint32 < maxInt32 {
-		return int32(a.Uint32), nil
-	}
-	return strconv.AppendUint([]byte{}, uint64(a.Uint32), 10), nil
+	return int64(a.Uint32), nil
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Uint32) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Uint32", data, 4)
+	if err != nil {
+		return err
+	}
+	*a = Uint32{}
+	if valid {
+		a.SetValid(binary.BigEndian.Uint32(v))
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written big endian with 4 bytes after the version and validity byte.
 func (a Uint32) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 4)
+	if a.Valid {
+		binary.BigEndian.PutUint32(data[binaryHeaderLen:], a.Uint32)
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
@@ -167,18 +167,15 @@ func TestNullUint32_MarshalText(t *testing.T) {
 	assertJSONEquals(t, data, "", "null text marshal")
 }
 
-func TestNullUint32_BinaryEncoding(t *testing.T) {
+func TestNullUint32_ProtoEncoding(t *testing.T) {
 	runner := func(b Uint32, want []byte) func(*testing.T) {
 		return func(t *testing.T) {
-			data, err := b.MarshalBinary()
-			assert.NoError(t, err)
-			assert.Exactly(t, want, data, t.Name()+": MarshalBinary: %q", data)
-			data, err = b.Marshal()
+			data, err := b.Marshal()
 			assert.NoError(t, err)
 			assert.Exactly(t, want, data, t.Name()+": Marshal: %q", data)
 
 			var decoded Uint32
-			assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary")
+			assert.NoError(t, decoded.Unmarshal(data), "Unmarshal")
 			assert.Exactly(t, b, decoded)
 		}
 	}
@@ -289,9 +286,24 @@ func TestNullUint32_Scan(t *testing.T) {
 		assert.NoError(t, nv.Scan(int(math.MaxInt32)))
 		assert.Exactly(t, MakeUint32(math.MaxInt32), nv)
 	})
-	t.Run("string unsupported", func(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		var nv Uint32
+		assert.NoError(t, nv.Scan("4294967295"))
+		assert.Exactly(t, MakeUint32(math.MaxUint32), nv)
+	})
+	t.Run("int64 negative", func(t *testing.T) {
+		var nv Uint32
+		assert.ErrorIsKind(t, errors.Overflowed, nv.Scan(int64(-1)))
+		assert.Exactly(t, Uint32{}, nv)
+	})
+	t.Run("overflow", func(t *testing.T) {
+		var nv Uint32
+		assert.Error(t, nv.Scan(int64(math.MaxUint32+1)))
+		assert.Exactly(t, Uint32{}, nv)
+	})
+	t.Run("float64 unsupported", func(t *testing.T) {
 		var nv Uint32
-		err := nv.Scan(`1234567`)
+		err := nv.Scan(1.5)
 		assert.True(t, errors.MatchKind(err, errors.NotSupported), "Error behaviour should be errors.NotSupported")
 		assert.Exactly(t, Uint32{}, nv)
 	})
@@ -17,6 +17,7 @@ package null
 import (
 	"bytes"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"math"
 	"strconv"
@@ -54,26 +55,64 @@ func MakeUint64FromByte(data []byte) (nv Uint64, err error) {
 }
 
 // Scan implements the Scanner interface. Approx. >3x times faster than
-// database/sql.convertAssign
+// database/sql.convertAssign. It accepts the integer, []byte and string
+// representations of the driver. Negative values return an error.
 func (a *Uint64) Scan(value interface{}) (err error) {
-	if value == nil {
-		a.Uint64, a.Valid = 0, false
-		return nil
-	}
+	a.Uint64, a.Valid, err = scanUint(value, 64, "Uint64")
+	return
+}
+
+// scanUint converts a driver value to an unsigned integer of bitSize. A nil
+// value returns false. Negative values or values exceeding bitSize return an
+// Overflowed error.
+func scanUint(value interface{}, bitSize int, typeName string) (n uint64, valid bool, err error) {
+	var i64 int64
 	switch v := value.(type) {
+	case nil:
+		return 0, false, nil
 	case []byte:
-		a.Uint64, a.Valid, err = byteconv.ParseUint(v, 10, 64)
-		a.Valid = err == nil
+		if n, _, err = byteconv.ParseUint(v, 10, bitSize); err != nil {
+			return 0, false, err
+		}
+		return n, true, nil
+	case string:
+		if n, err = strconv.ParseUint(v, 10, bitSize); err != nil {
+			return 0, false, err
+		}
+		return n, true, nil
+	case uint64:
+		n = v
+	case uint32:
+		n = uint64(v)
+	case uint16:
+		n = uint64(v)
+	case uint8:
+		n = uint64(v)
+	case uint:
+		n = uint64(v)
 	case int64:
-		a.Uint64 = uint64(v)
-		a.Valid = true
+		i64 = v
+	case int32:
+		i64 = int64(v)
+	case int16:
+		i64 = int64(v)
+	case int8:
+		i64 = int64(v)
 	case int:
-		a.Uint64 = uint64(v)
-		a.Valid = true
+		i64 = int64(v)
 	default:
-		err = errors.NotSupported.Newf("[dml] Type %T not supported in Uint64.Scan", value)
+		return 0, false, errors.NotSupported.Newf("[dml] Type %T not supported in %s.Scan", value, typeName)
 	}
-	return
+	if i64 < 0 {
+		return 0, false, errors.Overflowed.Newf("[null] %s.Scan: negative value %d", typeName, i64)
+	}
+	if n == 0 {
+		n = uint64(i64)
+	}
+	if bitSize < 64 && n > 1<<uint(bitSize)-1 {
+		return 0, false, errors.Overflowed.Newf("[null] %s.Scan: value %d overflows uint%d", typeName, n, bitSize)
+	}
+	return n, true, nil
 }
 
 // String returns the string representation of the int or null.
@@ -117,11 +156,11 @@ func (a *Uint64) UnmarshalJSON(data []byte) error {
 		err = json.Unmarshal(data, &a.Uint64)
 	case map[string]interface{}:
 		dto := &struct {
-			Uint64 int64
+			Uint64 uint64
 			Valid  bool
 		}{}
 		err = json.Unmarshal(data, dto)
-		a.Uint64 = uint64(dto.Uint64)
+		a.Uint64 = dto.Uint64
 		a.Valid = dto.Valid
 	case nil:
 		a.Valid = false
@@ -164,42 +203,13 @@ func (a Uint64) MarshalText() ([]byte, error) {
 	return strconv.AppendUint([]byte{}, a.Uint64, 10), nil
 }
 
-// SetValid changes this Uint64's value and also sets it to be non-null.
-func (a *Uint64) SetValid(n uint64) { a.Uint64 = n; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Uint64) Reset() { *a = Uint64{} }
-
-// Ptr returns a pointer to this Uint64's value, or a nil pointer if this Uint64 is null.
-func (a Uint64) Ptr() *uint64 {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Uint64
-}
-
-// SetPtr sets v according to the rules.
-func (a *Uint64) SetPtr(v *uint64) {
-	a.Valid = v != nil
-	a.Uint64 = 0
-	if v != nil {
-		a.Uint64 = *v
-	}
-}
-
-// IsZero returns true for invalid Uint64's, for future omitempty support (Go 1.4?)
-// A non-null Uint64 with a 0 value will not be considered zero.
-func (a Uint64) IsZero() bool {
-	return !a.Valid
-}
-
-// Value implements the driver.Valuer interface.
+// Value implements the driver.Valuer interface. Values above math.MaxInt64
+// get returned as text because driver.Value does not support uint64.
 func (a Uint64) Value() (driver.Value, error) {
 	if !a.Valid {
 		return nil, nil
 	}
-	const maxInt64 = 1<<63 - 1
-	if a.Uint64 < maxInt64 {
+	if a.Uint64 <= math.MaxInt64 {
 		return int64(a.Uint64), nil
 	}
 	return strconv.AppendUint([]byte{}, a.Uint64, 10), nil
@@ -207,12 +217,25 @@ func (a Uint64) Value() (driver.Value, error) {
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Uint64) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Uint64", data, 8)
+	if err != nil {
+		return err
+	}
+	*a = Uint64{}
+	if valid {
+		a.SetValid(binary.BigEndian.Uint64(v))
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written big endian with 8 bytes after the version and validity byte.
 func (a Uint64) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 8)
+	if a.Valid {
+		binary.BigEndian.PutUint64(data[binaryHeaderLen:], a.Uint64)
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
@@ -162,18 +162,15 @@ func TestNullUint64_MarshalText(t *testing.T) {
 	assertJSONEquals(t, data, "", "null text marshal")
 }
 
-func TestNullUint64_BinaryEncoding(t *testing.T) {
+func TestNullUint64_ProtoEncoding(t *testing.T) {
 	runner := func(b Uint64, want []byte) func(*testing.T) {
 		return func(t *testing.T) {
-			data, err := b.MarshalBinary()
-			assert.NoError(t, err)
-			assert.Exactly(t, want, data, t.Name()+": MarshalBinary %q", data)
-			data, err = b.Marshal()
+			data, err := b.Marshal()
 			assert.NoError(t, err)
 			assert.Exactly(t, want, data, t.Name()+": Marshal %q", data)
 
 			var decoded Uint64
-			assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary %q", data)
+			assert.NoError(t, decoded.Unmarshal(data), "Unmarshal %q", data)
 			assert.Exactly(t, b, decoded)
 		}
 	}
@@ -284,9 +281,24 @@ func TestNullUint64_Scan(t *testing.T) {
 		assert.NoError(t, nv.Scan(int(12345678912)))
 		assert.Exactly(t, MakeUint64(12345678912), nv)
 	})
-	t.Run("string unsupported", func(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		var nv Uint64
+		assert.NoError(t, nv.Scan("18446744073709551615"))
+		assert.Exactly(t, MakeUint64(math.MaxUint64), nv)
+	})
+	t.Run("int64 negative", func(t *testing.T) {
+		var nv Uint64
+		assert.ErrorIsKind(t, errors.Overflowed, nv.Scan(int64(-1)))
+		assert.Exactly(t, Uint64{}, nv)
+	})
+	t.Run("overflow", func(t *testing.T) {
+		var nv Uint64
+		assert.Error(t, nv.Scan(`18446744073709551616`))
+		assert.Exactly(t, Uint64{}, nv)
+	})
+	t.Run("float64 unsupported", func(t *testing.T) {
 		var nv Uint64
-		err := nv.Scan(`1234567`)
+		err := nv.Scan(1.5)
 		assert.True(t, errors.MatchKind(err, errors.NotSupported), "Error behaviour should be errors.NotSupported")
 		assert.Exactly(t, Uint64{}, nv)
 	})
@@ -171,35 +171,6 @@ func (a Uint8) MarshalText() ([]byte, error) {
 	return strconv.AppendUint([]byte{}, uint64(a.Uint8), 10), nil
 }
 
-// SetValid changes this Uint8's value and also sets it to be non-null.
-func (a *Uint8) SetValid(n uint8) { a.Uint8 = n; a.Valid = true }
-
-// Reset sets the value to Go's default value and Valid to false.
-func (a *Uint8) Reset() { *a = Uint8{} }
-
-// Ptr returns a pointer to this Uint8's value, or a nil pointer if this Uint8 is null.
-func (a Uint8) Ptr() *uint8 {
-	if !a.Valid {
-		return nil
-	}
-	return &a.Uint8
-}
-
-// SetPtr sets v according to the rules.
-func (a *Uint8) SetPtr(v *uint8) {
-	a.Valid = v != nil
-	a.Uint8 = 0
-	if v != nil {
-		a.Uint8 = *v
-	}
-}
-
-// IsZero returns true for invalid Uint8's, for future omitempty support (Go 1.4?)
-// A non-null Uint8 with a 0 value will not be considered zero.
-func (a Uint8) IsZero() bool {
-	return !a.Valid
-}
-
 // Value implements the driver.Valuer interface.
 func (a Uint8) Value() (driver.Value, error) {
 	if !a.Valid {
@@ -215,12 +186,25 @@ func (a Uint8) Value() (driver.Value, error) {
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (a *Uint8) UnmarshalBinary(data []byte) error {
-	return a.Unmarshal(data)
+	v, valid, err := unmarshalBinaryHeader("Uint8", data, 1)
+	if err != nil {
+		return err
+	}
+	*a = Uint8{}
+	if valid {
+		a.SetValid(v[0])
+	}
+	return nil
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The value
+// gets written with 1 byte after the version and validity byte.
 func (a Uint8) MarshalBinary() (data []byte, err error) {
-	return a.Marshal()
+	data = marshalBinaryHeader(a.Valid, 1)
+	if a.Valid {
+		data[binaryHeaderLen] = a.Uint8
+	}
+	return data, nil
 }
 
 // WriteTo uses a special dialect to encode the value and write it into w. w
@@ -0,0 +1,199 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// BatchOptions configures NewBatched.
+type BatchOptions struct {
+	// BatchSize defines the maximum number of keys per request to the inner
+	// storage. Default 50.
+	BatchSize int
+	// MaxConcurrentBatches limits the number of batches running in parallel.
+	// Default 4.
+	MaxConcurrentBatches int
+	// PartialResults lets Get return the values of the successful batches
+	// together with a *BatchError. Otherwise Get returns no values if one batch
+	// fails.
+	PartialResults bool
+}
+
+// BatchError gets returned by a Storager created with NewBatched if at least
+// one batch has failed. The values of the failed keys are nil.
+type BatchError struct {
+	// Keys contains the keys of all failed batches in input order.
+	Keys []string
+	// Errors contains one error per failed batch.
+	Errors []error
+}
+
+func (be *BatchError) Error() string {
+	msgs := make([]string, len(be.Errors))
+	for i, err := range be.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("[objcache] %d batch(es) failed with keys %v: %s", len(be.Errors), be.Keys, strings.Join(msgs, "; "))
+}
+
+// NewBatched splits multi-key Get, Set and Delete calls into batches of
+// BatchSize keys and sends them concurrently to inner. The order of the
+// returned values always matches the order of the keys. Useful for remote
+// backends where one large request waits for the slowest shard.
+func NewBatched(inner NewStorageFn, o BatchOptions) NewStorageFn {
+	if o.BatchSize < 1 {
+		o.BatchSize = 50
+	}
+	if o.MaxConcurrentBatches < 1 {
+		o.MaxConcurrentBatches = 4
+	}
+	return func() (Storager, error) {
+		s, err := inner()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return batched{inner: s, opt: o}, nil
+	}
+}
+
+type batched struct {
+	inner Storager
+	opt   BatchOptions
+}
+
+type batchFailure struct {
+	lo, hi int
+	err    error
+}
+
+// run calls fn for each batch with at most MaxConcurrentBatches goroutines.
+// Batches not started due to a canceled context count as failed.
+func (b batched) run(ctx context.Context, keys []string, fn func(lo, hi int) error) error {
+	if len(keys) <= b.opt.BatchSize {
+		if err := fn(0, len(keys)); err != nil {
+			return &BatchError{Keys: keys, Errors: []error{err}}
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failures []batchFailure
+	sem := make(chan struct{}, b.opt.MaxConcurrentBatches)
+
+Loop:
+	for lo := 0; lo < len(keys); lo += b.opt.BatchSize {
+		hi := lo + b.opt.BatchSize
+		if hi > len(keys) {
+			hi = len(keys)
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			failures = append(failures, batchFailure{lo: lo, hi: len(keys), err: errors.WithStack(ctx.Err())})
+			mu.Unlock()
+			break Loop
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := errors.WithStack(ctx.Err())
+			if err == nil {
+				err = fn(lo, hi)
+			}
+			if err != nil {
+				mu.Lock()
+				failures = append(failures, batchFailure{lo: lo, hi: hi, err: err})
+				mu.Unlock()
+			}
+		}(lo, hi)
+	}
+	wg.Wait()
+
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].lo < failures[j].lo })
+	be := &BatchError{Errors: make([]error, len(failures))}
+	for i, f := range failures {
+		be.Keys = append(be.Keys, keys[f.lo:f.hi]...)
+		be.Errors[i] = f.err
+	}
+	return be
+}
+
+func (b batched) Set(ctx context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	return b.run(ctx, keys, func(lo, hi int) error {
+		var exp []time.Duration
+		switch {
+		case hi <= len(expirations):
+			exp = expirations[lo:hi]
+		case lo < len(expirations):
+			exp = expirations[lo:]
+		}
+		return b.inner.Set(ctx, keys[lo:hi], values[lo:hi], exp)
+	})
+}
+
+// Get returns the values in the order of the keys. If PartialResults has been
+// enabled, the values of the successful batches get returned together with a
+// *BatchError.
+func (b batched) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	err := b.run(ctx, keys, func(lo, hi int) error {
+		vals, err := b.inner.Get(ctx, keys[lo:hi])
+		if err != nil {
+			return err
+		}
+		if len(vals) != hi-lo {
+			return errors.Mismatch.Newf("[objcache] Batched.Get: Length of keys (%d) does not match length of returned bytes (%d)", hi-lo, len(vals))
+		}
+		copy(values[lo:hi], vals)
+		return nil
+	})
+	if err != nil {
+		if b.opt.PartialResults {
+			return values, err
+		}
+		return nil, err
+	}
+	return values, nil
+}
+
+func (b batched) Delete(ctx context.Context, keys []string) error {
+	return b.run(ctx, keys, func(lo, hi int) error {
+		return b.inner.Delete(ctx, keys[lo:hi])
+	})
+}
+
+func (b batched) DeleteByPrefix(ctx context.Context, prefix string) error {
+	return deleteByPrefix(ctx, b.inner, prefix)
+}
+
+func (b batched) Truncate(ctx context.Context) error { return b.inner.Truncate(ctx) }
+
+func (b batched) Close() error { return b.inner.Close() }
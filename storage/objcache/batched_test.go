@@ -0,0 +1,181 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache_test
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// latencyStorage simulates a remote backend. Each call takes latency plus
+// perKey for each key and fails if it contains the failKey.
+type latencyStorage struct {
+	objcache.Storager
+	latency  time.Duration
+	perKey   time.Duration
+	failKey  string
+	inFlight int32
+	maxIn    int32
+}
+
+func (ls *latencyStorage) wait(keys []string) error {
+	n := atomic.AddInt32(&ls.inFlight, 1)
+	defer atomic.AddInt32(&ls.inFlight, -1)
+	for {
+		m := atomic.LoadInt32(&ls.maxIn)
+		if n <= m || atomic.CompareAndSwapInt32(&ls.maxIn, m, n) {
+			break
+		}
+	}
+	time.Sleep(ls.latency + time.Duration(len(keys))*ls.perKey)
+	for _, k := range keys {
+		if ls.failKey != "" && k == ls.failKey {
+			return errors.ConnectionFailed.Newf("shard down for key %q", k)
+		}
+	}
+	return nil
+}
+
+func (ls *latencyStorage) Set(ctx context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	if err := ls.wait(keys); err != nil {
+		return err
+	}
+	return ls.Storager.Set(ctx, keys, values, expirations)
+}
+
+func (ls *latencyStorage) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	if err := ls.wait(keys); err != nil {
+		return nil, err
+	}
+	return ls.Storager.Get(ctx, keys)
+}
+
+func newLatencyStorage(latency, perKey time.Duration) *latencyStorage {
+	s, _ := objcache.NewCacheSimpleInmemory()
+	return &latencyStorage{Storager: s, latency: latency, perKey: perKey}
+}
+
+func batchTestData(n int) (keys []string, values [][]byte) {
+	keys = make([]string, n)
+	values = make([][]byte, n)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+		values[i] = []byte("value" + strconv.Itoa(i))
+	}
+	return keys, values
+}
+
+func TestNewBatched(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("preserves order and limits concurrency", func(t *testing.T) {
+		ls := newLatencyStorage(time.Millisecond, 0)
+		s, err := objcache.NewBatched(staticStorage(ls), objcache.BatchOptions{BatchSize: 7, MaxConcurrentBatches: 3})()
+		assert.NoError(t, err)
+
+		keys, values := batchTestData(100)
+		assert.NoError(t, s.Set(ctx, keys, values, nil))
+		vals, err := s.Get(ctx, append(keys, "missing"))
+		assert.NoError(t, err)
+		assert.Exactly(t, append(values, nil), vals)
+		assert.True(t, atomic.LoadInt32(&ls.maxIn) <= 3, "max in flight %d", ls.maxIn)
+
+		assert.NoError(t, s.Delete(ctx, keys[:50]))
+		vals, err = s.Get(ctx, keys[48:52])
+		assert.NoError(t, err)
+		assert.Exactly(t, [][]byte{nil, nil, values[50], values[51]}, vals)
+	})
+
+	t.Run("partial results", func(t *testing.T) {
+		ls := newLatencyStorage(0, 0)
+		keys, values := batchTestData(20)
+		assert.NoError(t, ls.Set(ctx, keys, values, nil))
+		ls.failKey = "key12"
+
+		s, err := objcache.NewBatched(staticStorage(ls), objcache.BatchOptions{BatchSize: 5, PartialResults: true})()
+		assert.NoError(t, err)
+		vals, err := s.Get(ctx, keys)
+		be, ok := errors.Cause(err).(*objcache.BatchError)
+		assert.True(t, ok, "%+v", err)
+		assert.Exactly(t, keys[10:15], be.Keys)
+		assert.Len(t, be.Errors, 1)
+		assert.ErrorIsKind(t, errors.ConnectionFailed, be.Errors[0])
+		want := append([][]byte{}, values...)
+		for i := 10; i < 15; i++ {
+			want[i] = nil
+		}
+		assert.Exactly(t, want, vals)
+
+		s, err = objcache.NewBatched(staticStorage(ls), objcache.BatchOptions{BatchSize: 5})()
+		assert.NoError(t, err)
+		vals, err = s.Get(ctx, keys)
+		assert.Error(t, err)
+		assert.Nil(t, vals)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		ls := newLatencyStorage(0, 0)
+		s, err := objcache.NewBatched(staticStorage(ls), objcache.BatchOptions{BatchSize: 1, MaxConcurrentBatches: 1})()
+		assert.NoError(t, err)
+		keys, values := batchTestData(10)
+		err = s.Set(ctx, keys, values, nil)
+		be, ok := errors.Cause(err).(*objcache.BatchError)
+		assert.True(t, ok, "%+v", err)
+		assert.Exactly(t, keys, be.Keys)
+	})
+}
+
+func benchmarkBatched(newFn objcache.NewStorageFn) func(b *testing.B) {
+	return func(b *testing.B) {
+		ctx := context.TODO()
+		s, err := newFn()
+		if err != nil {
+			b.Fatal(err)
+		}
+		keys, values := batchTestData(200)
+		if err := s.Set(ctx, keys, values, nil); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			vals, err := s.Get(ctx, keys)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(vals) != len(keys) {
+				b.Fatalf("Expected %d values but got %d", len(keys), len(vals))
+			}
+		}
+	}
+}
+
+// BenchmarkNewBatched simulates a remote backend with a round trip of 500µs
+// and 10µs per key. 200 keys take ~2.5ms serial and ~0.8ms in four batches.
+func BenchmarkNewBatched(b *testing.B) {
+	b.Run("serial", benchmarkBatched(staticStorage(newLatencyStorage(500*time.Microsecond, 10*time.Microsecond))))
+	b.Run("batched 50x4", benchmarkBatched(objcache.NewBatched(
+		staticStorage(newLatencyStorage(500*time.Microsecond, 10*time.Microsecond)),
+		objcache.BatchOptions{BatchSize: 50, MaxConcurrentBatches: 4},
+	)))
+}
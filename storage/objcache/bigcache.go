@@ -18,6 +18,7 @@ package objcache
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/allegro/bigcache"
@@ -87,6 +88,28 @@ func (w bigCacheWrapper) Delete(_ context.Context, keys []string) (err error) {
 	return
 }
 
+// DeleteByPrefix iterates over all entries and removes the keys starting with
+// prefix.
+func (w bigCacheWrapper) DeleteByPrefix(_ context.Context, prefix string) error {
+	var keys []string
+	it := w.BigCache.Iterator()
+	for it.SetNext() {
+		e, err := it.Value()
+		if err != nil {
+			return errors.Wrapf(err, "[objcache] DeleteByPrefix with prefix %q", prefix)
+		}
+		if k := e.Key(); strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		if err := w.BigCache.Delete(k); err != nil && err != bigcache.ErrEntryNotFound {
+			return errors.Wrapf(err, "[objcache] DeleteByPrefix with key %q", k)
+		}
+	}
+	return nil
+}
+
 func (w bigCacheWrapper) Truncate(ctx context.Context) (err error) {
 	return w.BigCache.Reset()
 }
@@ -0,0 +1,207 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// CompressionAlgo defines the algorithm used by NewCompressed.
+type CompressionAlgo uint8
+
+// Supported compression algorithms. The values get written into the header of
+// each value and must not change.
+const (
+	compressionNone CompressionAlgo = iota
+	Gzip
+	Flate
+)
+
+// compressionMagic gets written as the first byte of each value which has
+// been handled by NewCompressed. Values without it are legacy values and get
+// returned as they are.
+const compressionMagic = 0xc5
+
+// CompressionOptions configures NewCompressed.
+type CompressionOptions struct {
+	// Algo defaults to Gzip.
+	Algo CompressionAlgo
+	// Level defaults to flate.DefaultCompression.
+	Level int
+	// MinSize defines the minimum length of a value in bytes to get
+	// compressed. Defaults to 1024.
+	MinSize int
+}
+
+type compressed struct {
+	inner        Storager
+	opt          CompressionOptions
+	writers      sync.Pool
+	gzipReaders  sync.Pool
+	flateReaders sync.Pool
+}
+
+// NewCompressed wraps the Storager created by inner and compresses all values
+// with a length of at least MinSize. Get decompresses the values
+// transparently. Values stored before enabling the compression can still be
+// read. Encoders and decoders get reused.
+func NewCompressed(inner NewStorageFn, o CompressionOptions) NewStorageFn {
+	if o.Algo == compressionNone {
+		o.Algo = Gzip
+	}
+	if o.Level == 0 {
+		o.Level = flate.DefaultCompression
+	}
+	if o.MinSize == 0 {
+		o.MinSize = 1024
+	}
+	return func() (Storager, error) {
+		if o.Algo > Flate {
+			return nil, errors.NotSupported.Newf("[objcache] NewCompressed: Algorithm %d not supported", o.Algo)
+		}
+		if o.Level < flate.HuffmanOnly || o.Level > flate.BestCompression {
+			return nil, errors.NotValid.Newf("[objcache] NewCompressed: Invalid compression level %d", o.Level)
+		}
+		s, err := inner()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return &compressed{inner: s, opt: o}, nil
+	}
+}
+
+// compress writes the header and the compressed v into buf.
+func (c *compressed) compress(buf *bytes.Buffer, v []byte) (err error) {
+	buf.WriteByte(compressionMagic)
+	buf.WriteByte(byte(c.opt.Algo))
+	switch c.opt.Algo {
+	case Gzip:
+		zw, _ := c.writers.Get().(*gzip.Writer)
+		if zw == nil {
+			zw, _ = gzip.NewWriterLevel(buf, c.opt.Level) // level already validated
+		} else {
+			zw.Reset(buf)
+		}
+		if _, err = zw.Write(v); err == nil {
+			err = zw.Close()
+		}
+		zw.Reset(ioutil.Discard) // release buf
+		c.writers.Put(zw)
+	case Flate:
+		fw, _ := c.writers.Get().(*flate.Writer)
+		if fw == nil {
+			fw, _ = flate.NewWriter(buf, c.opt.Level)
+		} else {
+			fw.Reset(buf)
+		}
+		if _, err = fw.Write(v); err == nil {
+			err = fw.Close()
+		}
+		fw.Reset(ioutil.Discard) // release buf
+		c.writers.Put(fw)
+	}
+	return err
+}
+
+func (c *compressed) decompress(algo CompressionAlgo, v []byte) (_ []byte, err error) {
+	if algo == compressionNone {
+		return v, nil
+	}
+	br := bytes.NewReader(v)
+	var r io.Reader
+	switch algo {
+	case Gzip:
+		zr, _ := c.gzipReaders.Get().(*gzip.Reader)
+		if zr == nil {
+			if zr, err = gzip.NewReader(br); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		} else if err = zr.Reset(br); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer c.gzipReaders.Put(zr)
+		r = zr
+	case Flate:
+		fr, _ := c.flateReaders.Get().(io.ReadCloser)
+		if fr == nil {
+			fr = flate.NewReader(br)
+		} else if err = fr.(flate.Resetter).Reset(br, nil); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer c.flateReaders.Put(fr)
+		r = fr
+	default:
+		return nil, errors.NotSupported.Newf("[objcache] Compressed: Algorithm %d not supported", algo)
+	}
+	return ioutil.ReadAll(r)
+}
+
+func (c *compressed) Set(ctx context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	cValues := make([][]byte, len(values))
+	for i, v := range values {
+		switch {
+		case len(v) >= c.opt.MinSize:
+			var buf bytes.Buffer
+			buf.Grow(len(v)/2 + 2)
+			if err := c.compress(&buf, v); err != nil {
+				return errors.Wrapf(err, "[objcache] Compressed.Set with key %q", keys[i])
+			}
+			cValues[i] = buf.Bytes()
+		case len(v) > 0 && v[0] == compressionMagic:
+			// the value would be mistaken as compressed.
+			cValues[i] = append([]byte{compressionMagic, byte(compressionNone)}, v...)
+		default:
+			cValues[i] = v
+		}
+	}
+	return c.inner.Set(ctx, keys, cValues, expirations)
+}
+
+func (c *compressed) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	values, err := c.inner.Get(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range values {
+		if len(v) < 2 || v[0] != compressionMagic {
+			continue
+		}
+		if values[i], err = c.decompress(CompressionAlgo(v[1]), v[2:]); err != nil {
+			return nil, errors.Wrapf(err, "[objcache] Compressed.Get with key %q", keys[i])
+		}
+	}
+	return values, nil
+}
+
+func (c *compressed) Delete(ctx context.Context, keys []string) error {
+	return c.inner.Delete(ctx, keys)
+}
+
+func (c *compressed) DeleteByPrefix(ctx context.Context, prefix string) error {
+	return deleteByPrefix(ctx, c.inner, prefix)
+}
+
+func (c *compressed) Truncate(ctx context.Context) error { return c.inner.Truncate(ctx) }
+
+func (c *compressed) Close() error { return c.inner.Close() }
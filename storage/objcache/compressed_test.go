@@ -0,0 +1,114 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestNewCompressed(t *testing.T) {
+	ctx := context.TODO()
+	large := bytes.Repeat([]byte(`{"store_id":1,"code":"de"},`), 100)
+	small := []byte("small")
+	magic := []byte{0xc5, 0x01, 0x02}
+
+	for _, algo := range []objcache.CompressionAlgo{objcache.Gzip, objcache.Flate} {
+		t.Run(fmt.Sprintf("algo %d", algo), func(t *testing.T) {
+			inner, err := objcache.NewCacheSimpleInmemory()
+			assert.NoError(t, err)
+			assert.NoError(t, inner.Set(ctx, []string{"legacy"}, [][]byte{[]byte("legacy")}, nil))
+
+			s, err := objcache.NewCompressed(staticStorage(inner), objcache.CompressionOptions{
+				Algo:  algo,
+				Level: flate.BestSpeed,
+			})()
+			assert.NoError(t, err)
+
+			assert.NoError(t, s.Set(ctx, []string{"large", "small", "magic", "empty"}, [][]byte{large, small, magic, {}}, nil))
+
+			raw, err := inner.Get(ctx, []string{"large", "small"})
+			assert.NoError(t, err)
+			assert.True(t, len(raw[0]) < len(large)/4, "compressed length %d", len(raw[0]))
+			assert.Exactly(t, small, raw[1])
+
+			vals, err := s.Get(ctx, []string{"large", "small", "legacy", "magic", "empty", "missing"})
+			assert.NoError(t, err)
+			assert.Exactly(t, [][]byte{large, small, []byte("legacy"), magic, {}, nil}, vals)
+		})
+	}
+
+	t.Run("corrupt value", func(t *testing.T) {
+		inner, err := objcache.NewCacheSimpleInmemory()
+		assert.NoError(t, err)
+		assert.NoError(t, inner.Set(ctx, []string{"k"}, [][]byte{{0xc5, 0x01, 'x'}}, nil))
+		s, err := objcache.NewCompressed(staticStorage(inner), objcache.CompressionOptions{})()
+		assert.NoError(t, err)
+		_, err = s.Get(ctx, []string{"k"})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := objcache.NewCompressed(objcache.NewCacheSimpleInmemory, objcache.CompressionOptions{Level: 42})()
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		_, err = objcache.NewCompressed(objcache.NewCacheSimpleInmemory, objcache.CompressionOptions{Algo: 9})()
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+}
+
+func benchmarkCompressed(size int, algo objcache.CompressionAlgo) func(b *testing.B) {
+	return func(b *testing.B) {
+		ctx := context.TODO()
+		s, err := objcache.NewCompressed(objcache.NewCacheSimpleInmemory, objcache.CompressionOptions{Algo: algo})()
+		if err != nil {
+			b.Fatal(err)
+		}
+		chunk := []byte(`{"entity_id":123,"sku":"ABC-123","name":"Coffee Mug","price":9.95},`)
+		value := bytes.Repeat(chunk, size/len(chunk)+1)[:size]
+		keys := []string{"key"}
+		values := [][]byte{value}
+		b.SetBytes(int64(size))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := s.Set(ctx, keys, values, nil); err != nil {
+				b.Fatal(err)
+			}
+			vals, err := s.Get(ctx, keys)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(vals[0]) != size {
+				b.Fatalf("Expected length %d but got %d", size, len(vals[0]))
+			}
+		}
+	}
+}
+
+func BenchmarkNewCompressed(b *testing.B) {
+	b.Run("gzip 1KB", benchmarkCompressed(1<<10, objcache.Gzip))
+	b.Run("gzip 64KB", benchmarkCompressed(64<<10, objcache.Gzip))
+	b.Run("gzip 1MB", benchmarkCompressed(1<<20, objcache.Gzip))
+	b.Run("flate 1KB", benchmarkCompressed(1<<10, objcache.Flate))
+	b.Run("flate 64KB", benchmarkCompressed(64<<10, objcache.Flate))
+	b.Run("flate 1MB", benchmarkCompressed(1<<20, objcache.Flate))
+}
@@ -16,8 +16,13 @@
 // a cache reducing GC.
 //
 // A Cache can be either in memory or a persistent one. Cache adapters are
-// available for bigcache or Redis. To enable the cache adapter use build tags
-// "bigcache" or "redis" or "csall". More cache adapters might follow.
+// available for bigcache, Redis or memcached. To enable the cache adapter use
+// build tags "bigcache", "redis", "memcache" or "csall". More cache adapters
+// might follow.
+//
+// NewSegmentCache provides an in-memory cache without further dependencies.
+// It stores all entries in pre-allocated byte segments, hence the GC does not
+// need to scan millions of cached values.
 //
 // Use case: Caching millions of Go types as a byte slice reduces the pressure
 // to the GC.
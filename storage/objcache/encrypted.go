@@ -0,0 +1,188 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// encryptedVersionAESGCM identifies values encrypted with AES-256-GCM. The
+// version byte allows to change the algorithm in the future.
+const encryptedVersionAESGCM byte = 0x01
+
+// EncryptionOptions configures NewEncryptedStorage.
+type EncryptionOptions struct {
+	// HashKeys replaces each cache key with its hex encoded HMAC-SHA256, hence
+	// the key names in the inner storage don't reveal any business data.
+	// DeleteByPrefix is then not supported.
+	HashKeys bool
+	// HashSecret defines the HMAC secret. Defaults to the first encryption
+	// key. Changing the secret makes all existing entries unreachable.
+	HashSecret []byte
+}
+
+type encrypted struct {
+	inner Storager
+	// aeads contains all keys. The first key encrypts, all keys decrypt.
+	aeads      []cipher.AEAD
+	hashKeys   bool
+	hashSecret []byte
+}
+
+// NewEncryptedStorage encrypts the values with AES-256-GCM before writing them
+// into inner and decrypts them while reading. Each key must have a length of 32
+// bytes. The first key encrypts, all keys get tried for decryption, which
+// allows a key rotation: prepend the new key and remove the old key after the
+// longest expiration. The stored bytes have the format: version byte, nonce
+// and the sealed value. The cache key gets authenticated as additional data,
+// hence a value copied to another key fails to decrypt. Argument o can be nil.
+func NewEncryptedStorage(inner NewStorageFn, keys [][]byte, o *EncryptionOptions) NewStorageFn {
+	if o == nil {
+		o = &EncryptionOptions{}
+	}
+	return func() (Storager, error) {
+		if len(keys) == 0 {
+			return nil, errors.Empty.Newf("[objcache] NewEncryptedStorage: keys cannot be empty")
+		}
+		e := &encrypted{
+			aeads:      make([]cipher.AEAD, 0, len(keys)),
+			hashKeys:   o.HashKeys,
+			hashSecret: o.HashSecret,
+		}
+		for i, k := range keys {
+			if len(k) != 32 {
+				return nil, errors.NotValid.Newf("[objcache] NewEncryptedStorage: Key at index %d must have a length of 32 bytes, have %d", i, len(k))
+			}
+			block, err := aes.NewCipher(k)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			aead, err := cipher.NewGCM(block)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			e.aeads = append(e.aeads, aead)
+		}
+		if len(e.hashSecret) == 0 {
+			e.hashSecret = keys[0]
+		}
+		s, err := inner()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		e.inner = s
+		return e, nil
+	}
+}
+
+// keys returns the HMAC hashed keys if enabled.
+func (e *encrypted) keys(keys []string) []string {
+	if !e.hashKeys {
+		return keys
+	}
+	hKeys := make([]string, len(keys))
+	mac := hmac.New(sha256.New, e.hashSecret)
+	for i, k := range keys {
+		mac.Reset()
+		_, _ = mac.Write([]byte(k))
+		hKeys[i] = hex.EncodeToString(mac.Sum(nil))
+	}
+	return hKeys
+}
+
+func (e *encrypted) Set(ctx context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	aead := e.aeads[0]
+	encValues := make([][]byte, len(values))
+	for i, v := range values {
+		buf := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(v)+aead.Overhead())
+		buf[0] = encryptedVersionAESGCM
+		nonce := buf[1:]
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return errors.WithStack(err)
+		}
+		encValues[i] = aead.Seal(buf, nonce, v, []byte(keys[i]))
+	}
+	return e.inner.Set(ctx, e.keys(keys), encValues, expirations)
+}
+
+// Get decrypts the values. If a value can't be decrypted with any key, Get
+// returns the remaining values and an error with kind DecryptionFailed. The
+// failed values are nil, hence callers can treat them as cache misses.
+func (e *encrypted) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	values, err := e.inner.Get(ctx, e.keys(keys))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var failed []string
+	for i, v := range values {
+		if v == nil || i >= len(keys) {
+			continue
+		}
+		if values[i] = e.decrypt(keys[i], v); values[i] == nil {
+			failed = append(failed, keys[i])
+		}
+	}
+	if len(failed) > 0 {
+		return values, errors.DecryptionFailed.Newf("[objcache] EncryptedStorage.Get: Failed to decrypt values of keys %v", failed)
+	}
+	return values, nil
+}
+
+// decrypt returns nil if the value has an unknown format or no key matches.
+func (e *encrypted) decrypt(key string, v []byte) []byte {
+	if len(v) == 0 || v[0] != encryptedVersionAESGCM {
+		return nil
+	}
+	for _, aead := range e.aeads {
+		ns := 1 + aead.NonceSize()
+		if len(v) < ns+aead.Overhead() {
+			break
+		}
+		if plain, err := aead.Open(nil, v[1:ns], v[ns:], []byte(key)); err == nil {
+			if plain == nil {
+				plain = []byte{}
+			}
+			return plain
+		}
+	}
+	return nil
+}
+
+func (e *encrypted) Delete(ctx context.Context, keys []string) error {
+	return e.inner.Delete(ctx, e.keys(keys))
+}
+
+// DeleteByPrefix passes the prefix to inner. It returns a NotSupported error if
+// the keys get hashed.
+func (e *encrypted) DeleteByPrefix(ctx context.Context, prefix string) error {
+	if e.hashKeys {
+		return errors.NotSupported.Newf("[objcache] EncryptedStorage.DeleteByPrefix not supported with option HashKeys")
+	}
+	return deleteByPrefix(ctx, e.inner, prefix)
+}
+
+func (e *encrypted) Truncate(ctx context.Context) error { return e.inner.Truncate(ctx) }
+
+func (e *encrypted) Close() error { return e.inner.Close() }
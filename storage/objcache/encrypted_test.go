@@ -0,0 +1,99 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestNewEncryptedStorage(t *testing.T) {
+	ctx := context.TODO()
+	keyOld := bytes.Repeat([]byte{'o'}, 32)
+	keyNew := bytes.Repeat([]byte{'n'}, 32)
+	session := []byte(`{"customer_id":4711,"token":"secret"}`)
+
+	t.Run("encrypts values and rotates keys", func(t *testing.T) {
+		inner, err := objcache.NewCacheSimpleInmemory()
+		assert.NoError(t, err)
+
+		sOld, err := objcache.NewEncryptedStorage(staticStorage(inner), [][]byte{keyOld}, nil)()
+		assert.NoError(t, err)
+		assert.NoError(t, sOld.Set(ctx, []string{"session_1", "empty"}, [][]byte{session, {}}, nil))
+
+		raw, err := inner.Get(ctx, []string{"session_1"})
+		assert.NoError(t, err)
+		assert.False(t, bytes.Contains(raw[0], []byte("secret")), "value is not encrypted: %q", raw[0])
+
+		sNew, err := objcache.NewEncryptedStorage(staticStorage(inner), [][]byte{keyNew, keyOld}, nil)()
+		assert.NoError(t, err)
+		vals, err := sNew.Get(ctx, []string{"session_1", "empty", "missing"})
+		assert.NoError(t, err)
+		assert.Exactly(t, [][]byte{session, {}, nil}, vals)
+
+		assert.NoError(t, sNew.Set(ctx, []string{"session_2"}, [][]byte{session}, nil))
+		_, err = sOld.Get(ctx, []string{"session_2"})
+		assert.ErrorIsKind(t, errors.DecryptionFailed, err)
+	})
+
+	t.Run("value bound to key", func(t *testing.T) {
+		inner, err := objcache.NewCacheSimpleInmemory()
+		assert.NoError(t, err)
+		s, err := objcache.NewEncryptedStorage(staticStorage(inner), [][]byte{keyNew}, nil)()
+		assert.NoError(t, err)
+		assert.NoError(t, s.Set(ctx, []string{"a"}, [][]byte{session}, nil))
+		raw, err := inner.Get(ctx, []string{"a"})
+		assert.NoError(t, err)
+		assert.NoError(t, inner.Set(ctx, []string{"b", "plain"}, [][]byte{raw[0], []byte("plain")}, nil))
+
+		vals, err := s.Get(ctx, []string{"a", "b", "plain"})
+		assert.ErrorIsKind(t, errors.DecryptionFailed, err)
+		assert.Exactly(t, [][]byte{session, nil, nil}, vals)
+	})
+
+	t.Run("hash keys", func(t *testing.T) {
+		inner, err := objcache.NewCacheSimpleInmemory()
+		assert.NoError(t, err)
+		s, err := objcache.NewEncryptedStorage(staticStorage(inner), [][]byte{keyNew}, &objcache.EncryptionOptions{HashKeys: true})()
+		assert.NoError(t, err)
+		assert.NoError(t, s.Set(ctx, []string{"customer_4711"}, [][]byte{session}, nil))
+
+		raw, err := inner.Get(ctx, []string{"customer_4711"})
+		assert.NoError(t, err)
+		assert.Nil(t, raw[0])
+
+		vals, err := s.Get(ctx, []string{"customer_4711"})
+		assert.NoError(t, err)
+		assert.Exactly(t, session, vals[0])
+
+		assert.NoError(t, s.Delete(ctx, []string{"customer_4711"}))
+		vals, err = s.Get(ctx, []string{"customer_4711"})
+		assert.NoError(t, err)
+		assert.Nil(t, vals[0])
+		assert.ErrorIsKind(t, errors.NotSupported, s.(objcache.PrefixDeleter).DeleteByPrefix(ctx, "customer_"))
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := objcache.NewEncryptedStorage(objcache.NewCacheSimpleInmemory, nil, nil)()
+		assert.ErrorIsKind(t, errors.Empty, err)
+		_, err = objcache.NewEncryptedStorage(objcache.NewCacheSimpleInmemory, [][]byte{[]byte("short")}, nil)()
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
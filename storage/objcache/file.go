@@ -20,15 +20,52 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha1"
+	// alias due to the binary type in options.go
+	encbinary "encoding/binary"
 	"encoding/hex"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/corestoreio/errors"
 )
 
+// fsMagic prefixes each cache file, followed by the expiration as Unix nano
+// seconds in big endian, the length of the key as uint16 in big endian and the
+// key itself. An expiration of zero means never expires. The key allows
+// DeleteByPrefix to find the files.
+const (
+	fsMagic      = "CSOC\x02"
+	fsHeaderLen  = len(fsMagic) + 8 + 2 // without the key
+	fsMaxKeyLen  = 1<<16 - 1
+	fsTempPrefix = ".tmp-"
+)
+
+// FSOptions allows to overwrite the default values of NewFileSystem.
+type FSOptions struct {
+	// FileSuffix of a cache file, default ".bin"
+	FileSuffix string
+	// DirectoryLevel either 1 or 2 levels deep. Default 2. A negative value
+	// stores all files in the root directory.
+	DirectoryLevel int
+	DirectoryMode  os.FileMode // default 0700
+	FileMode       os.FileMode // default 0600
+	// JanitorInterval, if greater zero, starts a background goroutine which
+	// removes expired files and enforces MaxTotalSize. Close stops it.
+	JanitorInterval time.Duration
+	// MaxTotalSize defines the maximum size of all cache files in bytes. The
+	// janitor removes the least recently accessed files until the total size
+	// fits. Zero means unlimited.
+	MaxTotalSize int64
+	// CleanOnClose removes all cache files when calling Close.
+	CleanOnClose bool
+}
+
 // FileSystemConfig allows to overwrite default values.
 type FileSystemConfig struct {
 	Path string
@@ -38,12 +75,14 @@ type FileSystemConfig struct {
 	DirectoryLevel int
 	DirectoryMode  os.FileMode // default 0700
 	FileMode       os.FileMode // default 0600
-	Location       *time.Location
-	CleanOnClose   bool
+	// Location has no effect anymore because expirations get stored as Unix
+	// time.
+	Location     *time.Location
+	CleanOnClose bool
 }
 
 // NewFileSystemClient creates a new file system client. Argument `c` can be nil.
-// Warning: do not change manually the files mtime and atime.
+// Default path is "testdata/fs". See NewFileSystem.
 func NewFileSystemClient(c *FileSystemConfig) NewStorageFn {
 	if c == nil {
 		c = &FileSystemConfig{}
@@ -51,201 +90,351 @@ func NewFileSystemClient(c *FileSystemConfig) NewStorageFn {
 	if c.Path == "" {
 		c.Path = "testdata/fs"
 	}
-	if c.FileSuffix == "" {
-		c.FileSuffix = ".bin"
-	}
-	if c.DirectoryLevel == 0 {
-		c.DirectoryLevel = 2
+	return NewFileSystem(c.Path, FSOptions{
+		FileSuffix:     c.FileSuffix,
+		DirectoryLevel: c.DirectoryLevel,
+		DirectoryMode:  c.DirectoryMode,
+		FileMode:       c.FileMode,
+		CleanOnClose:   c.CleanOnClose,
+	})
+}
+
+// NewFileSystem creates a file system Storager in directory dir which survives
+// restarts. The SHA1 of a key defines the file name and the directory fanout.
+// Values get written into a temporary file and renamed to the final name, so
+// concurrent writers, even from other processes, never produce torn reads. The
+// expiration gets stored in a header of each file and checked by Get. Get
+// updates the modification time of a file to track the last access for the
+// MaxTotalSize enforcement of the janitor.
+func NewFileSystem(dir string, o FSOptions) NewStorageFn {
+	if o.FileSuffix == "" {
+		o.FileSuffix = ".bin"
 	}
-	if c.DirectoryMode == 0 {
-		c.DirectoryMode = 0700
+	if o.DirectoryLevel == 0 {
+		o.DirectoryLevel = 2
 	}
-	if c.FileMode == 0 {
-		c.FileMode = 0600
+	if o.DirectoryMode == 0 {
+		o.DirectoryMode = 0700
 	}
-	if c.Location == nil {
-		c.Location = time.Local
+	if o.FileMode == 0 {
+		o.FileMode = 0600
 	}
 	return func() (Storager, error) {
-		return &fileStorage{
-			cfg:           *c,
-			keyToFileName: make(map[string]string),
-		}, nil
+		if dir == "" {
+			return nil, errors.Empty.Newf("[objcache] NewFileSystem: Directory cannot be empty")
+		}
+		if err := os.MkdirAll(dir, o.DirectoryMode); err != nil {
+			return nil, errors.Wrapf(err, "[objcache] NewFileSystem: Directory %q", dir)
+		}
+		fs := &fileStorage{
+			dir:  filepath.Clean(dir),
+			opt:  o,
+			done: make(chan struct{}),
+		}
+		if o.JanitorInterval > 0 {
+			go fs.janitor(o.JanitorInterval)
+		}
+		return fs, nil
 	}
 }
 
 type fileStorage struct {
-	cfg FileSystemConfig
+	dir       string
+	opt       FSOptions
+	done      chan struct{}
+	closeOnce sync.Once
+}
 
-	mu            sync.Mutex
-	keyToFileName map[string]string
+func (fs *fileStorage) fileName(key string) (dir, fileName string) {
+	h := sha1.Sum([]byte(key))
+	name := hex.EncodeToString(h[:])
+	dir = fs.dir
+	switch {
+	case fs.opt.DirectoryLevel >= 2:
+		dir = filepath.Join(dir, name[0:2], name[2:4])
+	case fs.opt.DirectoryLevel == 1:
+		dir = filepath.Join(dir, name[0:2])
+	}
+	return dir, filepath.Join(dir, name+fs.opt.FileSuffix)
 }
 
-func (fs *fileStorage) writeFileContent(fileName string, data []byte, expires time.Duration) (err error) {
-	var f *os.File
-	f, err = os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fs.cfg.FileMode)
+// writeFile writes the value atomically via a temporary file in the same
+// directory.
+func (fs *fileStorage) writeFile(dir, fileName, key string, value []byte, expires time.Duration) (err error) {
+	if len(key) > fsMaxKeyLen {
+		return errors.NotValid.Newf("[objcache] Key length %d exceeds maximum of %d bytes", len(key), fsMaxKeyLen)
+	}
+	if err = os.MkdirAll(dir, fs.opt.DirectoryMode); err != nil {
+		return errors.WithStack(err)
+	}
+	f, err := ioutil.TempFile(dir, fsTempPrefix)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	defer func() {
-		n := time.Unix(0, 0)
-		if expires != 0 {
-			n = now().In(fs.cfg.Location).Add(expires)
-		}
-		if err2 := os.Chtimes(fileName, n, n); err == nil && err2 != nil {
-			err = errors.WithStack(err2)
-		}
-		if err2 := f.Close(); err == nil && err2 != nil {
-			err = errors.WithStack(err2)
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
 		}
 	}()
 
-	if _, err = f.Write(data); err != nil {
+	hdr := make([]byte, fsHeaderLen, fsHeaderLen+len(key))
+	copy(hdr, fsMagic)
+	if expires > 0 {
+		encbinary.BigEndian.PutUint64(hdr[len(fsMagic):], uint64(now().Add(expires).UnixNano()))
+	}
+	encbinary.BigEndian.PutUint16(hdr[len(fsMagic)+8:], uint16(len(key)))
+	hdr = append(hdr, key...)
+	if _, err = f.Write(hdr); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err = f.Write(value); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = f.Chmod(fs.opt.FileMode); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = f.Sync(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = f.Close(); err != nil {
 		return errors.WithStack(err)
 	}
-	err = f.Sync()
-	return
+	return errors.WithStack(os.Rename(f.Name(), fileName))
 }
 
-// Set writes the values into a file by using the keys as their filename. The
-// keys will be sha1ed. If no duration gets set, the file atime and mtime
-// applies to 1970-01-01 which means that the content never expires. once the
-// mtime and atime is greater than the year 1970, the expiration gets checked.
-func (fs *fileStorage) Set(_ context.Context, keys []string, values [][]byte, expires []time.Duration) (err error) {
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
+// Set writes the values into files. An expiration of zero means the file never
+// expires.
+func (fs *fileStorage) Set(_ context.Context, keys []string, values [][]byte, expires []time.Duration) error {
 	for i, key := range keys {
-		kfn, err := fs.getCacheFileName(key)
-		if err != nil {
-			return errors.WithStack(err)
-		}
 		var e time.Duration
-		if len(expires) > 0 {
+		if i < len(expires) {
 			e = expires[i]
 		}
-		if err := fs.writeFileContent(kfn, values[i], e); err != nil {
-			return errors.WithStack(err)
+		dir, fileName := fs.fileName(key)
+		if err := fs.writeFile(dir, fileName, key, values[i], e); err != nil {
+			return errors.Wrapf(err, "[objcache] FileSystem.Set with key %q", key)
 		}
 	}
 	return nil
 }
 
-func (fs *fileStorage) lookupFileContent(fileName string, values [][]byte) ([][]byte, error) {
-	if ok, err := fileExists(fileName); !ok {
-		return append(values, nil), err
+// parseFSHeader returns the expiration in Unix nano seconds and the length of
+// the key. ok is false if the header is invalid.
+func parseFSHeader(data []byte) (expires int64, keyLen int, ok bool) {
+	if len(data) < fsHeaderLen || !bytes.HasPrefix(data, []byte(fsMagic)) {
+		return 0, 0, false
 	}
+	expires = int64(encbinary.BigEndian.Uint64(data[len(fsMagic):]))
+	keyLen = int(encbinary.BigEndian.Uint16(data[len(fsMagic)+8:]))
+	return expires, keyLen, true
+}
 
-	f, err := os.Open(fileName)
-	if err != nil {
-		return nil, errors.WithStack(err)
+func (fs *fileStorage) readFile(fileName string) ([]byte, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return nil, nil
 	}
-	defer f.Close()
-	fi, err := f.Stat()
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-
-	if mt := fi.ModTime(); mt.Year() > 1970 && mt.Before(now()) { // too old, delete the file which cleans up the cache
-		err := os.Remove(fileName)
-		values = append(values, nil)
-		return values, err
+	expires, keyLen, ok := parseFSHeader(data)
+	ok = ok && len(data) >= fsHeaderLen+keyLen
+	n := now()
+	if !ok || (expires > 0 && expires <= n.UnixNano()) {
+		// invalid or expired files get removed which cleans up the cache.
+		if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+			return nil, errors.WithStack(err)
+		}
+		return nil, nil
 	}
+	_ = os.Chtimes(fileName, n, n) // best effort to track the last access
+	return data[fsHeaderLen+keyLen:], nil
+}
 
-	var buf bytes.Buffer
-	if _, err := buf.ReadFrom(f); err != nil {
-		return nil, errors.WithStack(err)
+// Get returns the values of the not expired files.
+func (fs *fileStorage) Get(_ context.Context, keys []string) (values [][]byte, err error) {
+	values = make([][]byte, len(keys))
+	for i, key := range keys {
+		_, fileName := fs.fileName(key)
+		if values[i], err = fs.readFile(fileName); err != nil {
+			return nil, errors.Wrapf(err, "[objcache] FileSystem.Get with key %q", key)
+		}
 	}
-	values = append(values, buf.Bytes())
 	return values, nil
 }
 
-func (fs *fileStorage) Get(_ context.Context, keys []string) (values [][]byte, err error) {
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
+// Delete removes the files of the keys. Non existing keys get ignored.
+func (fs *fileStorage) Delete(_ context.Context, keys []string) error {
 	for _, key := range keys {
-		kfn, err := fs.getCacheFileName(key)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		values, err = fs.lookupFileContent(kfn, values)
-		if err != nil {
-			return nil, errors.WithStack(err)
+		_, fileName := fs.fileName(key)
+		if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "[objcache] FileSystem.Delete with key %q", key)
 		}
 	}
+	return nil
+}
 
-	return values, nil
+// DeleteByPrefix walks all cache files and removes those whose key, stored in
+// the header, starts with prefix.
+func (fs *fileStorage) DeleteByPrefix(_ context.Context, prefix string) error {
+	err := fs.walkHeaders(func(path string, _ os.FileInfo, _ int64, key string, ok bool) error {
+		if !ok || !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	})
+	return errors.Wrapf(err, "[objcache] FileSystem.DeleteByPrefix with prefix %q", prefix)
 }
 
-func (fs *fileStorage) Delete(_ context.Context, keys []string) error {
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
-	for _, key := range keys {
-		kfn, err := fs.getCacheFileName(key)
+// walkHeaders calls fn for each cache file with the parsed header. ok is false
+// if the header is invalid.
+func (fs *fileStorage) walkHeaders(fn func(path string, fi os.FileInfo, expires int64, key string, ok bool) error) error {
+	return filepath.Walk(fs.dir, func(path string, fi os.FileInfo, err error) error {
 		if err != nil {
-			return errors.WithStack(err)
+			if os.IsNotExist(err) {
+				return nil // removed concurrently
+			}
+			return err
 		}
-		if err = os.Remove(kfn); err != nil {
-			return errors.WithStack(err)
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), fs.opt.FileSuffix) {
+			return nil
 		}
+		expires, key, ok, err := readFSHeader(path)
+		if err != nil {
+			return err
+		}
+		return fn(path, fi, expires, key, ok)
+	})
+}
+
+// isCacheEntry reports whether the directory entry in the root directory has
+// been created by this storage. Other files stay untouched.
+func (fs *fileStorage) isCacheEntry(fi os.FileInfo) bool {
+	name := fi.Name()
+	if fi.IsDir() {
+		_, err := hex.DecodeString(name)
+		return len(name) == 2 && err == nil
 	}
-	return nil
+	return strings.HasSuffix(name, fs.opt.FileSuffix) || strings.HasPrefix(name, fsTempPrefix)
 }
 
-func (fs *fileStorage) Truncate(ctx context.Context) (err error) {
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
-	fs.keyToFileName = make(map[string]string)
-	return os.RemoveAll(fs.cfg.Path)
+// Truncate removes all fanout directories and cache files but never the root
+// directory or unrelated files in it.
+func (fs *fileStorage) Truncate(_ context.Context) error {
+	fis, err := ioutil.ReadDir(fs.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "[objcache] FileSystem.Truncate directory %q", fs.dir)
+	}
+	for _, fi := range fis {
+		if !fs.isCacheEntry(fi) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(fs.dir, fi.Name())); err != nil {
+			return errors.Wrapf(err, "[objcache] FileSystem.Truncate directory %q", fs.dir)
+		}
+	}
+	return nil
 }
 
+// Close stops the janitor and removes all files if CleanOnClose has been set.
 func (fs *fileStorage) Close() (err error) {
-	if fs.cfg.CleanOnClose {
+	fs.closeOnce.Do(func() { close(fs.done) })
+	if fs.opt.CleanOnClose {
 		err = fs.Truncate(context.Background())
 	}
-	return nil
+	return err
 }
 
-func (fs *fileStorage) getCacheFileName(key string) (string, error) {
-	if fileName, ok := fs.keyToFileName[key]; ok {
-		return fileName, nil
+func (fs *fileStorage) janitor(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-fs.done:
+			return
+		case <-t.C:
+			_ = fs.sweep() // errors can't be reported, try again next time
+		}
 	}
+}
 
-	m := sha1.New()
-	m.Write([]byte(key))
-	keyMd5 := hex.EncodeToString(m.Sum(nil))
-	cachePath := fs.cfg.Path
-	switch fs.cfg.DirectoryLevel {
-	case 2:
-		cachePath = filepath.Join(cachePath, keyMd5[0:2], keyMd5[2:4])
-	case 1:
-		cachePath = filepath.Join(cachePath, keyMd5[0:2])
-	}
+type fsEntry struct {
+	path       string
+	size       int64
+	lastAccess time.Time
+}
 
-	ok, err := fileExists(cachePath)
+// sweep removes expired files and, if MaxTotalSize has been set, the least
+// recently accessed files until the total size fits.
+func (fs *fileStorage) sweep() error {
+	n := now().UnixNano()
+	var entries []fsEntry
+	var total int64
+	err := fs.walkHeaders(func(path string, fi os.FileInfo, expires int64, _ string, ok bool) error {
+		if !ok || (expires > 0 && expires <= n) {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return nil
+		}
+		entries = append(entries, fsEntry{path: path, size: fi.Size(), lastAccess: fi.ModTime()})
+		total += fi.Size()
+		return nil
+	})
 	if err != nil {
-		return "", errors.Wrapf(err, "[objcache] FileStorage CachePath %q", cachePath)
+		return errors.Wrapf(err, "[objcache] FileSystem janitor in directory %q", fs.dir)
 	}
-	if !ok {
-		err = os.MkdirAll(cachePath, fs.cfg.DirectoryMode)
-		if err != nil {
-			return "", errors.Wrapf(err, "[objcache] FileStorage CachePath %q", cachePath)
+	if fs.opt.MaxTotalSize <= 0 || total <= fs.opt.MaxTotalSize {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].lastAccess.Before(entries[j].lastAccess) })
+	for _, e := range entries {
+		if total <= fs.opt.MaxTotalSize {
+			break
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "[objcache] FileSystem janitor file %q", e.path)
 		}
+		total -= e.size
 	}
-	fileName := filepath.Join(cachePath, keyMd5+fs.cfg.FileSuffix)
-	fs.keyToFileName[key] = fileName
-	return fileName, nil
+	return nil
 }
 
-func fileExists(path string) (bool, error) {
-	_, err := os.Stat(path)
-	if err == nil {
-		return true, nil
-	}
+// readFSHeader reads only the header of a cache file. ok is false for missing,
+// too short or invalid files.
+func readFSHeader(path string) (expires int64, key string, ok bool, err error) {
+	f, err := os.Open(path)
 	if os.IsNotExist(err) {
-		return false, nil
+		return 0, "", false, nil // removed concurrently
 	}
 	if err != nil {
-		err = errors.WithStack(err)
+		return 0, "", false, err
+	}
+	defer f.Close()
+	hdr := make([]byte, fsHeaderLen)
+	if _, err = io.ReadFull(f, hdr); err != nil {
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			return 0, "", false, nil // too short
+		}
+		return 0, "", false, err
+	}
+	expires, keyLen, ok := parseFSHeader(hdr)
+	if !ok {
+		return 0, "", false, nil
+	}
+	bKey := make([]byte, keyLen)
+	if _, err = io.ReadFull(f, bKey); err != nil {
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			return 0, "", false, nil
+		}
+		return 0, "", false, err
 	}
-	return false, err
+	return expires, string(bKey), true, nil
 }
@@ -17,10 +17,17 @@
 package objcache_test
 
 import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
 )
 
 func TestNewFileSystemClient_Delete(t *testing.T) {
@@ -51,3 +58,94 @@ func TestNewFileSystemClient_ComplexParallel(t *testing.T) {
 	})
 
 }
+
+func newFileSystemTest(t *testing.T, o objcache.FSOptions) (_ objcache.Storager, dir string, cleanUp func()) {
+	dir, err := ioutil.TempDir("", "objcache_fs")
+	assert.NoError(t, err)
+	s, err := objcache.NewFileSystem(dir, o)()
+	assert.NoError(t, err)
+	return s, dir, func() {
+		assert.NoError(t, s.Close())
+		assert.NoError(t, os.RemoveAll(dir))
+	}
+}
+
+func TestNewFileSystem_SetGet(t *testing.T) {
+	ctx := context.TODO()
+	s, dir, cleanUp := newFileSystemTest(t, objcache.FSOptions{})
+	defer cleanUp()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "unrelated.txt"), []byte("x"), 0600))
+
+	assert.NoError(t, s.Set(ctx, []string{"a", "b", "empty"}, [][]byte{[]byte("A"), []byte("BB"), {}}, []time.Duration{50 * time.Millisecond, 0, 0}))
+	vals, err := s.Get(ctx, []string{"a", "b", "empty", "missing"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("A"), []byte("BB"), {}, nil}, vals)
+
+	time.Sleep(60 * time.Millisecond)
+	vals, err = s.Get(ctx, []string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, []byte("BB")}, vals)
+
+	assert.NoError(t, s.Delete(ctx, []string{"b", "missing"}))
+	vals, err = s.Get(ctx, []string{"b"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil}, vals)
+
+	assert.NoError(t, s.Truncate(ctx))
+	fis, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, fis, 1)
+	assert.Exactly(t, "unrelated.txt", fis[0].Name(), "Truncate must not remove unrelated files")
+}
+
+func TestNewFileSystem_NoTornReads(t *testing.T) {
+	ctx := context.TODO()
+	s, dir, cleanUp := newFileSystemTest(t, objcache.FSOptions{})
+	defer cleanUp()
+	// a second instance simulates another process
+	s2, err := objcache.NewFileSystem(dir, objcache.FSOptions{})()
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int, s objcache.Storager) {
+			defer wg.Done()
+			value := bytes.Repeat([]byte{byte('a' + i)}, 64<<10)
+			for j := 0; j < 20; j++ {
+				assert.NoError(t, s.Set(ctx, []string{"key"}, [][]byte{value}, nil))
+				vals, err := s.Get(ctx, []string{"key"})
+				assert.NoError(t, err)
+				v := vals[0]
+				assert.Len(t, v, 64<<10)
+				assert.True(t, len(v) == 0 || bytes.Count(v, v[:1]) == len(v), "torn read")
+			}
+		}(i, []objcache.Storager{s, s2}[i%2])
+	}
+	wg.Wait()
+}
+
+func TestNewFileSystem_Janitor(t *testing.T) {
+	ctx := context.TODO()
+	s, _, cleanUp := newFileSystemTest(t, objcache.FSOptions{
+		JanitorInterval: 10 * time.Millisecond,
+		MaxTotalSize:    2500,
+	})
+	defer cleanUp()
+	value := bytes.Repeat([]byte("x"), 1000)
+	assert.NoError(t, s.Set(ctx, []string{"expires"}, [][]byte{value}, []time.Duration{time.Millisecond}))
+	assert.NoError(t, s.Set(ctx, []string{"oldest"}, [][]byte{value}, nil))
+	time.Sleep(5 * time.Millisecond)
+	assert.NoError(t, s.Set(ctx, []string{"newer", "newest"}, [][]byte{value, value}, nil))
+	time.Sleep(50 * time.Millisecond)
+
+	vals, err := s.Get(ctx, []string{"expires", "oldest", "newer", "newest"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, nil, value, value}, vals)
+}
+
+func TestNewFileSystem_DeleteByPrefix(t *testing.T) {
+	s, _, cleanUp := newFileSystemTest(t, objcache.FSOptions{})
+	defer cleanUp()
+	testDeleteByPrefix(t, s)
+}
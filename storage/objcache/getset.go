@@ -0,0 +1,163 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"golang.org/x/sync/singleflight"
+)
+
+// negativeCachePrefix marks a cached loader error.
+const negativeCachePrefix = "\x00\xffobjcache:loader-error:"
+
+// flightDo executes fn only once per key at a time. All concurrent callers
+// receive the same result. A caller whose context gets cancelled returns early
+// while fn keeps running for the others.
+func (tr *Service) flightDo(ctx context.Context, key string, fn func() ([]byte, error)) ([]byte, error) {
+	select {
+	case res := <-tr.flightStart(key, fn):
+		raw, _ := res.Val.([]byte)
+		return raw, res.Err
+	case <-ctx.Done():
+		return nil, errors.WithStack(ctx.Err())
+	}
+}
+
+// flightStart executes fn in the background unless a call for key is already
+// in flight.
+func (tr *Service) flightStart(key string, fn func() ([]byte, error)) <-chan singleflight.Result {
+	return tr.flight.DoChan(key, func() (interface{}, error) {
+		return callRecover(key, fn)
+	})
+}
+
+// callRecover returns a panic in fn as an error because DoChan cannot
+// propagate it to the callers.
+func callRecover(key string, fn func() ([]byte, error)) (_ []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Fatal.Newf("[objcache] Loader with key %q panicked: %v", key, r)
+		}
+	}()
+	return fn()
+}
+
+// detachedContext keeps the values of its parent but never gets cancelled.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) { return }
+func (detachedContext) Done() <-chan struct{}                   { return nil }
+func (detachedContext) Err() error                              { return nil }
+func (dc detachedContext) Value(key interface{}) interface{}    { return dc.parent.Value(key) }
+
+// getRaw returns the raw bytes of the first level which contains the key. A
+// nil slice indicates a miss.
+func (tr *Service) getRaw(ctx context.Context, key string) ([]byte, error) {
+	keys := [1]string{key}
+	if tr.level1 != nil {
+		vals, err := tr.level1.Get(ctx, keys[:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "[objcache] Level1 with key %q", key)
+		}
+		if len(vals) == 1 && vals[0] != nil {
+			return vals[0], nil
+		}
+	}
+	vals, err := tr.level2.Get(ctx, keys[:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "[objcache] Level2 with key %q", key)
+	}
+	if len(vals) == 1 {
+		return vals[0], nil
+	}
+	return nil, nil
+}
+
+func (tr *Service) setRaw(ctx context.Context, key string, value []byte, expires time.Duration) error {
+	keys := [1]string{key}
+	values := [1][]byte{value}
+	exp := [1]time.Duration{expires}
+	if tr.level1 != nil {
+		if err := tr.level1.Set(ctx, keys[:], values[:], exp[:]); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(tr.level2.Set(ctx, keys[:], values[:], exp[:]))
+}
+
+// GetSet decodes the cached value of key into dst. On a cache miss the loader
+// gets called, its result encoded, written with the ttl to all cache levels
+// and decoded into dst. Concurrent calls for the same key share one loader
+// call, hence only one backend write happens. The loader receives a context
+// which does not get cancelled when a waiting caller gives up. Loader errors
+// are not cached unless ServiceOptions.NegativeCacheTTL has been set.
+func (tr *Service) GetSet(ctx context.Context, key string, dst interface{}, ttl time.Duration, loader func(ctx context.Context) (interface{}, error)) error {
+	raw, err := tr.getRaw(ctx, key)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if raw == nil {
+		raw, err = tr.flightDo(ctx, key, func() ([]byte, error) {
+			dctx := detachedContext{parent: ctx}
+			// A previous flight might have finished after our lookup.
+			if raw, err := tr.getRaw(dctx, key); err != nil || raw != nil {
+				return raw, err
+			}
+			return tr.load(dctx, key, ttl, loader, nil)
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if bytes.HasPrefix(raw, []byte(negativeCachePrefix)) {
+		return errors.NotFound.Newf("[objcache] Cached loader error for key %q: %s", key, raw[len(negativeCachePrefix):])
+	}
+	return errors.WithStack(decodeOne(tr.so.Codec, raw, key, dst))
+}
+
+// load calls the loader and writes the encoded result with the ttl. wrap, if
+// not nil, can add a header to the encoded value before writing.
+func (tr *Service) load(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (interface{}, error), wrap func(encoded []byte) []byte) ([]byte, error) {
+	if ttl == 0 {
+		ttl = tr.defaultExpiration
+	}
+	src, err := loader(ctx)
+	if err != nil {
+		if nt := tr.so.NegativeCacheTTL; nt > 0 {
+			if err2 := tr.setRaw(ctx, key, []byte(negativeCachePrefix+err.Error()), nt); err2 != nil {
+				return nil, errors.WithStack(err2)
+			}
+		}
+		return nil, errors.Wrapf(err, "[objcache] Loader with key %q", key)
+	}
+	var buf bytes.Buffer
+	if err := encodeOne(tr.so.Codec, &buf, key, src); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	raw := buf.Bytes()
+	if wrap != nil {
+		raw = wrap(raw)
+	}
+	if err := tr.setRaw(ctx, key, raw, ttl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// countingStorage counts the Set calls to the wrapped Storager.
+type countingStorage struct {
+	objcache.Storager
+	sets int32
+}
+
+func (cs *countingStorage) Set(ctx context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	atomic.AddInt32(&cs.sets, 1)
+	return cs.Storager.Set(ctx, keys, values, expirations)
+}
+
+func newCountingService(t testing.TB, so *objcache.ServiceOptions) (*objcache.Service, *countingStorage) {
+	cs := &countingStorage{}
+	p, err := objcache.NewService(nil, func() (objcache.Storager, error) {
+		s, err := objcache.NewCacheSimpleInmemory()
+		cs.Storager = s
+		return cs, err
+	}, so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, cs
+}
+
+func TestService_GetSet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("concurrent misses share one load", func(t *testing.T) {
+		p, cs := newCountingService(t, nil)
+		var loads int32
+		release := make(chan struct{})
+		loader := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&loads, 1)
+			<-release
+			return &myString{data: "loaded"}, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var dst myString
+				assert.NoError(t, p.GetSet(context.TODO(), "hot", &dst, time.Minute, loader))
+				assert.Exactly(t, "loaded", dst.data)
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Exactly(t, int32(1), atomic.LoadInt32(&loads))
+		assert.Exactly(t, int32(1), atomic.LoadInt32(&cs.sets))
+
+		// cache hit does not call the loader
+		var dst myString
+		assert.NoError(t, p.GetSet(context.TODO(), "hot", &dst, time.Minute, loader))
+		assert.Exactly(t, "loaded", dst.data)
+		assert.Exactly(t, int32(1), atomic.LoadInt32(&loads))
+	})
+
+	t.Run("cancelled waiter does not cancel the load", func(t *testing.T) {
+		p, _ := newCountingService(t, nil)
+		release := make(chan struct{})
+		loaderCtxErr := make(chan error, 1)
+		loader := func(ctx context.Context) (interface{}, error) {
+			<-release
+			loaderCtxErr <- ctx.Err()
+			return &myString{data: "loaded"}, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errc := make(chan error)
+		go func() {
+			var dst myString
+			errc <- p.GetSet(ctx, "key", &dst, 0, loader)
+		}()
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		assert.True(t, errors.Cause(<-errc) == context.Canceled)
+		close(release)
+		assert.NoError(t, <-loaderCtxErr)
+
+		var dst myString
+		assert.NoError(t, p.GetSet(context.TODO(), "key", &dst, 0, loader))
+		assert.Exactly(t, "loaded", dst.data)
+	})
+
+	t.Run("loader errors not cached", func(t *testing.T) {
+		p, cs := newCountingService(t, nil)
+		var loads int32
+		loader := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&loads, 1)
+			return nil, errors.ConnectionFailed.Newf("DB down")
+		}
+		var dst myString
+		assert.ErrorIsKind(t, errors.ConnectionFailed, p.GetSet(context.TODO(), "key", &dst, 0, loader))
+		assert.ErrorIsKind(t, errors.ConnectionFailed, p.GetSet(context.TODO(), "key", &dst, 0, loader))
+		assert.Exactly(t, int32(2), atomic.LoadInt32(&loads))
+		assert.Exactly(t, int32(0), atomic.LoadInt32(&cs.sets))
+	})
+
+	t.Run("negative caching", func(t *testing.T) {
+		p, _ := newCountingService(t, &objcache.ServiceOptions{NegativeCacheTTL: time.Minute})
+		var loads int32
+		loader := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&loads, 1)
+			return nil, errors.ConnectionFailed.Newf("DB down")
+		}
+		var dst myString
+		assert.ErrorIsKind(t, errors.ConnectionFailed, p.GetSet(context.TODO(), "key", &dst, 0, loader))
+		err := p.GetSet(context.TODO(), "key", &dst, 0, loader)
+		assert.ErrorIsKind(t, errors.NotFound, err)
+		assert.Contains(t, err.Error(), "DB down")
+		assert.Exactly(t, int32(1), atomic.LoadInt32(&loads))
+	})
+
+	t.Run("loader panic returns an error", func(t *testing.T) {
+		p, cs := newCountingService(t, nil)
+		loader := func(ctx context.Context) (interface{}, error) {
+			panic("boom")
+		}
+		var dst myString
+		err := p.GetSet(context.TODO(), "key", &dst, 0, loader)
+		assert.ErrorIsKind(t, errors.Fatal, err)
+		assert.Contains(t, err.Error(), "boom")
+		assert.Exactly(t, int32(0), atomic.LoadInt32(&cs.sets))
+	})
+}
+
+func BenchmarkService_GetSet_100ConcurrentMisses(b *testing.B) {
+	loader := func(ctx context.Context) (interface{}, error) {
+		time.Sleep(time.Millisecond) // expensive backend call
+		return &myString{data: "loaded"}, nil
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p, cs := newCountingService(b, nil)
+		var wg sync.WaitGroup
+		for j := 0; j < 100; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var dst myString
+				if err := p.GetSet(context.TODO(), "hot", &dst, time.Minute, loader); err != nil {
+					b.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+		if sets := atomic.LoadInt32(&cs.sets); sets != 1 {
+			b.Fatalf("Expected one backend call but got %d", sets)
+		}
+	}
+}
@@ -16,6 +16,8 @@ package objcache
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/corestoreio/pkg/storage/lru"
@@ -28,16 +30,51 @@ type LRUOptions struct {
 	Capacity           int64 // default 5000 objects
 	TrackBySize        bool
 	TrackByObjectCount bool // default
-	LRUCache           *lru.Cache
+	// LRUCache optionally provides the cache, either an *lru.Cache or an
+	// *lru.Sharded.
+	LRUCache LRUCacher
+	// Shards, if greater one and LRUCache is nil, creates an *lru.Sharded with
+	// this number of shards to reduce the mutex contention.
+	Shards int
+	// SweepInterval, if greater zero, starts a background goroutine which
+	// removes expired entries periodically. Otherwise expired entries get
+	// only removed while accessing them via Get. The goroutine stops with
+	// Close.
+	SweepInterval time.Duration
+	// Clock returns the current time. Defaults to time.Now. Mainly used for
+	// testing.
+	Clock func() time.Time
+}
+
+// LRUCacher defines the functions of the underlying LRU cache. Implemented by
+// *lru.Cache and *lru.Sharded.
+type LRUCacher interface {
+	Get(key string) (lru.Value, bool)
+	SetWithTTL(key string, value lru.Value, ttl time.Duration)
+	Delete(key string) bool
+	Clear()
+	Keys() []string
+	EvictExpired() int
+	SetClock(now func() time.Time)
+	Stats() lru.Stats
+}
+
+// LRUStatser gets implemented by the LRU Storager and the metrics decorator.
+// LRUStats returns the statistics of the underlying lru cache. ok reports
+// false if the Storager is not backed by an LRU cache.
+type LRUStatser interface {
+	LRUStats() (st lru.Stats, ok bool)
 }
 
 // lruCache is an LRU cache. It is safe for concurrent access.
 type lruCache struct {
-	opt LRUOptions
+	opt       LRUOptions
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
-// NewLRU creates a new LRU Storage. Expirations are not supported. Argument `o`
-// can be nil, if so default values get applied.
+// NewLRU creates a new LRU Storage. An expiration of zero means the entry never
+// expires. Argument `o` can be nil, if so default values get applied.
 func NewLRU(o *LRUOptions) NewStorageFn {
 	if o == nil {
 		o = &LRUOptions{}
@@ -53,67 +90,117 @@ func NewLRU(o *LRUOptions) NewStorageFn {
 
 	default:
 		o.TrackByObjectCount = true
-		o.Capacity = 5000
+		if o.Capacity == 0 {
+			o.Capacity = 5000
+		}
 	}
-	if o.LRUCache == nil {
+	switch {
+	case o.LRUCache != nil:
+	case o.Shards > 1:
+		o.LRUCache = lru.NewSharded(o.Capacity, o.Shards)
+	default:
 		o.LRUCache = lru.New(o.Capacity)
 	}
+	if o.Clock != nil {
+		o.LRUCache.SetClock(o.Clock)
+	}
 	return func() (Storager, error) {
-		return lruCache{
-			opt: *o,
-		}, nil
+		c := &lruCache{
+			opt:  *o,
+			done: make(chan struct{}),
+		}
+		if o.SweepInterval > 0 {
+			go c.sweeper(o.SweepInterval)
+		}
+		return c, nil
 	}
 }
 
-type itemBySize []byte
+// lruItemOverhead gets added to the size of each item when tracking by size,
+// to account for the expiration stored in the lru.Cache.
+const lruItemOverhead = 8
 
-func (li itemBySize) Size() int { return len(li) }
+type lruItem struct {
+	value  []byte
+	bySize bool
+}
 
-type itemByCount []byte
+func (li lruItem) Size() int {
+	if li.bySize {
+		return len(li.value) + lruItemOverhead
+	}
+	return 1
+}
 
-func (li itemByCount) Size() int { return 1 }
+// LRUStats returns the statistics of the underlying lru cache.
+func (c *lruCache) LRUStats() (lru.Stats, bool) { return c.opt.LRUCache.Stats(), true }
+
+func (c *lruCache) sweeper(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+			c.opt.LRUCache.EvictExpired()
+		}
+	}
+}
 
-func (c lruCache) Set(_ context.Context, keys []string, values [][]byte, _ []time.Duration) (err error) {
+func (c *lruCache) Set(_ context.Context, keys []string, values [][]byte, expirations []time.Duration) (err error) {
+	lenExp := len(expirations)
 	for i, key := range keys {
-		var v lru.Value = itemByCount(values[i])
-		if c.opt.TrackBySize {
-			v = itemBySize(values[i])
+		var e time.Duration
+		if i < lenExp {
+			e = expirations[i]
 		}
-		c.opt.LRUCache.Set(key, v)
+		c.opt.LRUCache.SetWithTTL(key, lruItem{
+			value:  values[i],
+			bySize: c.opt.TrackBySize,
+		}, e)
 	}
 	return nil
 }
 
-// Get looks up a key's value from the cache.
-func (c lruCache) Get(_ context.Context, keys []string) (values [][]byte, err error) {
+// Get looks up a key's value from the cache. Expired entries count as a miss
+// and get deleted by the lru.Cache.
+func (c *lruCache) Get(_ context.Context, keys []string) (values [][]byte, err error) {
 	for _, key := range keys {
 		itm, ok := c.opt.LRUCache.Get(key)
-		if ok {
-			if c.opt.TrackByObjectCount {
-				values = append(values, []byte(itm.(itemByCount)))
-			} else {
-				values = append(values, []byte(itm.(itemBySize)))
-			}
-		} else {
+		if !ok {
 			values = append(values, nil)
+			continue
 		}
+		values = append(values, itm.(lruItem).value)
 	}
 	return
 }
 
-func (c lruCache) Truncate(_ context.Context) (err error) {
+// DeleteByPrefix removes all keys starting with prefix.
+func (c *lruCache) DeleteByPrefix(_ context.Context, prefix string) error {
+	for _, key := range c.opt.LRUCache.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			c.opt.LRUCache.Delete(key)
+		}
+	}
+	return nil
+}
+
+func (c *lruCache) Truncate(_ context.Context) (err error) {
 	c.opt.LRUCache.Clear()
 	return nil
 }
 
-func (c lruCache) Delete(_ context.Context, keys []string) (err error) {
+func (c *lruCache) Delete(_ context.Context, keys []string) (err error) {
 	for _, key := range keys {
 		c.opt.LRUCache.Delete(key)
 	}
 	return nil
 }
 
-func (c lruCache) Close() error {
+func (c *lruCache) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
 	c.opt.LRUCache.Clear()
 	return nil
 }
@@ -15,9 +15,14 @@
 package objcache_test
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/corestoreio/pkg/storage/lru"
 	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
 )
 
 func TestNewCacheLRU_Delete(t *testing.T) {
@@ -35,3 +40,96 @@ func TestNewCacheLRU_ComplexParallel(t *testing.T) {
 		})
 	})
 }
+
+func TestNewCacheLRU_Sharded(t *testing.T) {
+	newServiceComplexParallelTest(t, objcache.NewLRU(&objcache.LRUOptions{Shards: 8}), &objcache.ServiceOptions{
+		Codec: JSONCodec{},
+	})
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) Add(d time.Duration) {
+	fc.mu.Lock()
+	fc.now = fc.now.Add(d)
+	fc.mu.Unlock()
+}
+
+func TestNewCacheLRU_Expiration(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1500000000, 0)}
+	lc := lru.New(1000)
+	s, err := objcache.NewLRU(&objcache.LRUOptions{
+		TrackBySize: true,
+		LRUCache:    lc,
+		Clock:       clock.Now,
+	})()
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	ctx := context.TODO()
+	assert.NoError(t, s.Set(ctx,
+		[]string{"short", "long", "forever"},
+		[][]byte{[]byte("a"), []byte("bb"), []byte("ccc")},
+		[]time.Duration{time.Second, time.Minute, 0},
+	))
+	// 6 bytes of data plus 8 bytes overhead per item
+	assert.Exactly(t, int64(6+3*8), lc.Size())
+
+	vals, err := s.Get(ctx, []string{"short", "long", "forever"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("a"), []byte("bb"), []byte("ccc")}, vals)
+
+	clock.Add(time.Second)
+	vals, err = s.Get(ctx, []string{"short", "long", "forever"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, []byte("bb"), []byte("ccc")}, vals)
+	assert.Exactly(t, int64(2), lc.Length(), "expired entry must be deleted")
+	assert.Exactly(t, int64(5+2*8), lc.Size())
+
+	clock.Add(24 * time.Hour)
+	vals, err = s.Get(ctx, []string{"long", "forever"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, []byte("ccc")}, vals)
+
+	// nil expirations never expire
+	assert.NoError(t, s.Set(ctx, []string{"nil"}, [][]byte{[]byte("d")}, nil))
+	clock.Add(24 * time.Hour)
+	vals, err = s.Get(ctx, []string{"nil"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("d")}, vals)
+}
+
+func TestNewCacheLRU_Sweeper(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1500000000, 0)}
+	lc := lru.New(10)
+	s, err := objcache.NewLRU(&objcache.LRUOptions{
+		LRUCache:      lc,
+		SweepInterval: time.Millisecond,
+		Clock:         clock.Now,
+	})()
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Set(context.TODO(),
+		[]string{"a", "b"},
+		[][]byte{[]byte("a"), []byte("b")},
+		[]time.Duration{time.Second, 0},
+	))
+	clock.Add(2 * time.Second)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for lc.Length() != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Exactly(t, []string{"b"}, lc.Keys())
+	assert.NoError(t, s.Close())
+	assert.NoError(t, s.Close(), "Close must be idempotent")
+}
@@ -0,0 +1,226 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build memcache csall
+
+package objcache
+
+import (
+	"context"
+	"hash/crc32"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/corestoreio/errors"
+)
+
+// memcachedMaxRelativeTTL defines the 30 days limit of memcached. Longer
+// expirations must be sent as an absolute Unix timestamp.
+const memcachedMaxRelativeTTL = 30 * 24 * time.Hour
+
+// MemcachedOptions applies several options for the memcached client.
+type MemcachedOptions struct {
+	// Servers contains the addresses of the memcached servers, either
+	// host:port or a path to a unix socket.
+	Servers []string
+	// Timeout specifies the socket read/write timeout. Defaults to 100ms.
+	Timeout time.Duration
+	// MaxIdleConns per server. Defaults to 2.
+	MaxIdleConns int
+	// VirtualNodes defines the number of points per server in the consistent
+	// hash ring. Defaults to 160.
+	VirtualNodes int
+	// KeyPrefix gets prepended to each key.
+	KeyPrefix string
+	// AllowTruncate enables Truncate to send flush_all to all servers, which
+	// removes also the keys of other applications. Otherwise Truncate
+	// returns a NotSupported error.
+	AllowTruncate bool
+}
+
+// NewMemcached creates a new memcached Storager. Keys get distributed across the
+// servers with consistent hashing, hence adding or removing a server moves only
+// a small fraction of the keys.
+func NewMemcached(o MemcachedOptions) NewStorageFn {
+	return func() (Storager, error) {
+		if len(o.Servers) == 0 {
+			return nil, errors.Empty.Newf("[objcache] NewMemcached: Servers cannot be empty")
+		}
+		ring, err := newMemcachedRing(o.Servers, o.VirtualNodes)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		c := memcache.NewFromSelector(ring)
+		if o.Timeout > 0 {
+			c.Timeout = o.Timeout
+		}
+		if o.MaxIdleConns > 0 {
+			c.MaxIdleConns = o.MaxIdleConns
+		}
+		return memcachedWrapper{client: c, opt: o}, nil
+	}
+}
+
+// memcachedRing implements memcache.ServerSelector with consistent hashing.
+type memcachedRing struct {
+	addrs  []net.Addr
+	points []uint32 // sorted
+	owners []int    // index into addrs for each point
+}
+
+func newMemcachedRing(servers []string, virtualNodes int) (*memcachedRing, error) {
+	if virtualNodes < 1 {
+		virtualNodes = 160
+	}
+	r := &memcachedRing{
+		addrs: make([]net.Addr, len(servers)),
+	}
+	type point struct {
+		hash  uint32
+		owner int
+	}
+	points := make([]point, 0, len(servers)*virtualNodes)
+	for i, srv := range servers {
+		var err error
+		if strings.Contains(srv, "/") {
+			r.addrs[i], err = net.ResolveUnixAddr("unix", srv)
+		} else {
+			r.addrs[i], err = net.ResolveTCPAddr("tcp", srv)
+		}
+		if err != nil {
+			return nil, errors.NotValid.New(err, "[objcache] Memcached server address %q", srv)
+		}
+		for v := 0; v < virtualNodes; v++ {
+			points = append(points, point{
+				hash:  crc32.ChecksumIEEE([]byte(srv + "-" + strconv.Itoa(v))),
+				owner: i,
+			})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].hash < points[j].hash })
+	r.points = make([]uint32, len(points))
+	r.owners = make([]int, len(points))
+	for i, p := range points {
+		r.points[i] = p.hash
+		r.owners[i] = p.owner
+	}
+	return r, nil
+}
+
+// PickServer returns the server owning the first point clockwise of the hash
+// of the key.
+func (r *memcachedRing) PickServer(key string) (net.Addr, error) {
+	h := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.addrs[r.owners[i]], nil
+}
+
+// Each iterates over all servers.
+func (r *memcachedRing) Each(fn func(net.Addr) error) error {
+	for _, a := range r.addrs {
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type memcachedWrapper struct {
+	client *memcache.Client
+	opt    MemcachedOptions
+}
+
+// memcachedExpiration converts d into the memcached format. Zero means never
+// expires.
+func memcachedExpiration(d time.Duration) int32 {
+	switch {
+	case d <= 0:
+		return 0
+	case d > memcachedMaxRelativeTTL:
+		return int32(now().Add(d).Unix())
+	case d < time.Second:
+		return 1
+	}
+	return int32(d / time.Second)
+}
+
+func (w memcachedWrapper) Set(_ context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	for i, key := range keys {
+		var e time.Duration
+		if i < len(expirations) {
+			e = expirations[i]
+		}
+		if err := w.client.Set(&memcache.Item{
+			Key:        w.opt.KeyPrefix + key,
+			Value:      values[i],
+			Expiration: memcachedExpiration(e),
+		}); err != nil {
+			return errors.Wrapf(err, "[objcache] Memcached.Set with key %q", key)
+		}
+	}
+	return nil
+}
+
+// Get uses the multi-get protocol to fetch several keys at once.
+func (w memcachedWrapper) Get(_ context.Context, keys []string) ([][]byte, error) {
+	pKeys := keys
+	if w.opt.KeyPrefix != "" {
+		pKeys = make([]string, len(keys))
+		for i, k := range keys {
+			pKeys[i] = w.opt.KeyPrefix + k
+		}
+	}
+	items, err := w.client.GetMulti(pKeys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[objcache] Memcached.Get with keys %v", keys)
+	}
+	values := make([][]byte, len(keys))
+	for i, k := range pKeys {
+		if itm, ok := items[k]; ok {
+			values[i] = itm.Value
+			if values[i] == nil {
+				values[i] = []byte{}
+			}
+		}
+	}
+	return values, nil
+}
+
+func (w memcachedWrapper) Delete(_ context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := w.client.Delete(w.opt.KeyPrefix + key); err != nil && err != memcache.ErrCacheMiss {
+			return errors.Wrapf(err, "[objcache] Memcached.Delete with key %q", key)
+		}
+	}
+	return nil
+}
+
+// Truncate flushes all servers, if the option AllowTruncate has been set.
+// Best effort: memcached can't flush only the keys with the KeyPrefix.
+func (w memcachedWrapper) Truncate(_ context.Context) error {
+	if !w.opt.AllowTruncate {
+		return errors.NotSupported.Newf("[objcache] Memcached.Truncate not allowed. Set option AllowTruncate to flush all servers.")
+	}
+	return errors.Wrap(w.client.FlushAll(), "[objcache] Memcached.Truncate")
+}
+
+// Close is a no-op because the client closes idle connections itself.
+func (w memcachedWrapper) Close() error { return nil }
@@ -0,0 +1,85 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build memcache csall
+
+package objcache_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func lookupMemcacheEnv(t testing.TB) []string {
+	servers := os.Getenv("CS_MEMCACHE_TEST")
+	if servers == "" {
+		t.Skip(`Skipping live test because environment CS_MEMCACHE_TEST variable not found.
+	export CS_MEMCACHE_TEST="127.0.0.1:11211,127.0.0.1:11212"
+		`)
+	}
+	return strings.Split(servers, ",")
+}
+
+func TestNewMemcached_Integration(t *testing.T) {
+	servers := lookupMemcacheEnv(t)
+
+	t.Run("expiration", func(t *testing.T) {
+		testExpiration(t, func() {
+			time.Sleep(time.Second * 2)
+		}, objcache.NewMemcached(objcache.MemcachedOptions{Servers: servers, KeyPrefix: "cs_test_"}), newSrvOpt(JSONCodec{}))
+	})
+
+	t.Run("multi get delete truncate", func(t *testing.T) {
+		ctx := context.TODO()
+		s, err := objcache.NewMemcached(objcache.MemcachedOptions{Servers: servers, KeyPrefix: "cs_test_"})()
+		assert.NoError(t, err)
+		defer func() { assert.NoError(t, s.Close()) }()
+
+		assert.NoError(t, s.Set(ctx, []string{"a", "b", "c"}, [][]byte{[]byte("1"), []byte("2"), []byte("3")}, []time.Duration{time.Minute, 0, 40 * 24 * time.Hour}))
+		vals, err := s.Get(ctx, []string{"c", "x", "a", "b"})
+		assert.NoError(t, err)
+		assert.Exactly(t, [][]byte{[]byte("3"), nil, []byte("1"), []byte("2")}, vals)
+
+		assert.NoError(t, s.Delete(ctx, []string{"a", "x"}))
+		vals, err = s.Get(ctx, []string{"a", "b"})
+		assert.NoError(t, err)
+		assert.Exactly(t, [][]byte{nil, []byte("2")}, vals)
+
+		assert.ErrorIsKind(t, errors.NotSupported, s.Truncate(ctx))
+	})
+}
+
+func TestNewMemcached_ConnectionFailure(t *testing.T) {
+	s, err := objcache.NewMemcached(objcache.MemcachedOptions{
+		Servers: []string{"127.0.0.1:53345"}, // random port
+		Timeout: 50 * time.Millisecond,
+	})()
+	assert.NoError(t, err)
+	err = s.Set(context.TODO(), []string{"my_key"}, [][]byte{[]byte("1")}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `"my_key"`)
+	_, err = s.Get(context.TODO(), []string{"my_key", "other_key"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "[my_key other_key]")
+
+	_, err = objcache.NewMemcached(objcache.MemcachedOptions{})()
+	assert.ErrorIsKind(t, errors.Empty, err)
+}
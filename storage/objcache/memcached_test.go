@@ -0,0 +1,69 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build memcache csall
+
+package objcache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var _ Storager = (*memcachedWrapper)(nil)
+
+func TestMemcachedRing(t *testing.T) {
+	servers := []string{"127.0.0.1:11211", "127.0.0.1:11212", "127.0.0.1:11213"}
+	r3, err := newMemcachedRing(servers, 0)
+	assert.NoError(t, err)
+	r4, err := newMemcachedRing(append(servers, "127.0.0.1:11214"), 0)
+	assert.NoError(t, err)
+
+	perServer := map[string]int{}
+	moved := 0
+	const keys = 10000
+	for i := 0; i < keys; i++ {
+		key := "key_" + strconv.Itoa(i)
+		a3, err := r3.PickServer(key)
+		assert.NoError(t, err)
+		a4, err := r4.PickServer(key)
+		assert.NoError(t, err)
+		perServer[a3.String()]++
+		if a3.String() != a4.String() {
+			moved++
+			assert.Exactly(t, "127.0.0.1:11214", a4.String(), "keys must only move to the new server")
+		}
+	}
+	for srv, n := range perServer {
+		assert.True(t, n > keys/5, "Server %q has only %d keys", srv, n)
+	}
+	assert.True(t, moved < keys/2, "Too many keys moved: %d", moved)
+
+	_, err = newMemcachedRing([]string{"127.0.0.1:port"}, 0)
+	assert.Error(t, err)
+}
+
+func TestMemcachedExpiration(t *testing.T) {
+	defer func(n func() time.Time) { now = n }(now)
+	now = func() time.Time { return time.Unix(1500000000, 0) }
+
+	assert.Exactly(t, int32(0), memcachedExpiration(0))
+	assert.Exactly(t, int32(1), memcachedExpiration(time.Millisecond))
+	assert.Exactly(t, int32(90), memcachedExpiration(90*time.Second))
+	assert.Exactly(t, int32(memcachedMaxRelativeTTL/time.Second), memcachedExpiration(memcachedMaxRelativeTTL))
+	assert.Exactly(t, int32(1500000000+31*24*3600), memcachedExpiration(31*24*time.Hour))
+}
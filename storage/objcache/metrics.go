@@ -0,0 +1,231 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/lru"
+)
+
+// Operation names passed to the MetricsCollector.
+const (
+	MetricsOpGet      = "get"
+	MetricsOpSet      = "set"
+	MetricsOpDelete   = "delete"
+	MetricsOpTruncate = "truncate"
+)
+
+// MetricsCollector receives the measurements of a Storager created with
+// NewStorageMetrics. Implementations must be safe for concurrent use and can
+// forward the data to Prometheus, expvar or any other system.
+type MetricsCollector interface {
+	// CountHit gets called for each key found by Get.
+	CountHit(key string)
+	// CountMiss gets called for each key not found by Get.
+	CountMiss(key string)
+	// ObserveLatency receives the duration of one operation, see the
+	// MetricsOp* constants.
+	ObserveLatency(op string, d time.Duration)
+	// ObserveValueSize receives the length of each value read by Get or
+	// written by Set.
+	ObserveValueSize(op string, size int)
+}
+
+type storageMetrics struct {
+	inner Storager
+	mc    MetricsCollector
+}
+
+// NewStorageMetrics wraps the Storager created by inner and reports hits,
+// misses, latencies and value sizes to the collector. Close gets not measured.
+func NewStorageMetrics(inner NewStorageFn, collector MetricsCollector) NewStorageFn {
+	return func() (Storager, error) {
+		s, err := inner()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return storageMetrics{inner: s, mc: collector}, nil
+	}
+}
+
+func (sm storageMetrics) Set(ctx context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	start := time.Now()
+	err := sm.inner.Set(ctx, keys, values, expirations)
+	sm.mc.ObserveLatency(MetricsOpSet, time.Since(start))
+	for _, v := range values {
+		sm.mc.ObserveValueSize(MetricsOpSet, len(v))
+	}
+	return err
+}
+
+func (sm storageMetrics) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	start := time.Now()
+	values, err := sm.inner.Get(ctx, keys)
+	sm.mc.ObserveLatency(MetricsOpGet, time.Since(start))
+	if err != nil {
+		return values, err
+	}
+	for i, key := range keys {
+		if i < len(values) && values[i] != nil {
+			sm.mc.CountHit(key)
+			sm.mc.ObserveValueSize(MetricsOpGet, len(values[i]))
+		} else {
+			sm.mc.CountMiss(key)
+		}
+	}
+	return values, nil
+}
+
+func (sm storageMetrics) Delete(ctx context.Context, keys []string) error {
+	start := time.Now()
+	err := sm.inner.Delete(ctx, keys)
+	sm.mc.ObserveLatency(MetricsOpDelete, time.Since(start))
+	return err
+}
+
+func (sm storageMetrics) DeleteByPrefix(ctx context.Context, prefix string) error {
+	start := time.Now()
+	err := deleteByPrefix(ctx, sm.inner, prefix)
+	sm.mc.ObserveLatency(MetricsOpDelete, time.Since(start))
+	return err
+}
+
+func (sm storageMetrics) Truncate(ctx context.Context) error {
+	start := time.Now()
+	err := sm.inner.Truncate(ctx)
+	sm.mc.ObserveLatency(MetricsOpTruncate, time.Since(start))
+	return err
+}
+
+func (sm storageMetrics) Close() error { return sm.inner.Close() }
+
+// LRUStats forwards to the inner Storager, if it implements LRUStatser.
+func (sm storageMetrics) LRUStats() (lru.Stats, bool) {
+	if ls, ok := sm.inner.(LRUStatser); ok {
+		return ls.LRUStats()
+	}
+	return lru.Stats{}, false
+}
+
+// MetricsSnapshot contains the values of a MetricsCounter at one point in time.
+// Latencies are the summed up durations of all calls of an operation.
+type MetricsSnapshot struct {
+	Hits            uint64
+	Misses          uint64
+	GetCalls        uint64
+	SetCalls        uint64
+	DeleteCalls     uint64
+	TruncateCalls   uint64
+	GetLatency      time.Duration
+	SetLatency      time.Duration
+	DeleteLatency   time.Duration
+	TruncateLatency time.Duration
+	BytesRead       uint64
+	BytesWritten    uint64
+	// FreshHits, StaleHits and BlockingLoads count the results of
+	// Service.GetStale.
+	FreshHits     uint64
+	StaleHits     uint64
+	BlockingLoads uint64
+}
+
+// HitRatio returns the hits divided by all looked up keys.
+func (ms MetricsSnapshot) HitRatio() float64 {
+	if total := ms.Hits + ms.Misses; total > 0 {
+		return float64(ms.Hits) / float64(total)
+	}
+	return 0
+}
+
+// MetricsCounter implements MetricsCollector with atomic counters. The zero
+// value is ready to use.
+type MetricsCounter struct {
+	hits, misses                                           uint64
+	getCalls, setCalls, deleteCalls, truncateCalls         uint64
+	getLatency, setLatency, deleteLatency, truncateLatency int64
+	bytesRead, bytesWritten                                uint64
+	freshHits, staleHits, blockingLoads                    uint64
+}
+
+// CountHit implements MetricsCollector.
+func (mc *MetricsCounter) CountHit(_ string) { atomic.AddUint64(&mc.hits, 1) }
+
+// CountMiss implements MetricsCollector.
+func (mc *MetricsCounter) CountMiss(_ string) { atomic.AddUint64(&mc.misses, 1) }
+
+// ObserveLatency implements MetricsCollector.
+func (mc *MetricsCounter) ObserveLatency(op string, d time.Duration) {
+	switch op {
+	case MetricsOpGet:
+		atomic.AddUint64(&mc.getCalls, 1)
+		atomic.AddInt64(&mc.getLatency, int64(d))
+	case MetricsOpSet:
+		atomic.AddUint64(&mc.setCalls, 1)
+		atomic.AddInt64(&mc.setLatency, int64(d))
+	case MetricsOpDelete:
+		atomic.AddUint64(&mc.deleteCalls, 1)
+		atomic.AddInt64(&mc.deleteLatency, int64(d))
+	case MetricsOpTruncate:
+		atomic.AddUint64(&mc.truncateCalls, 1)
+		atomic.AddInt64(&mc.truncateLatency, int64(d))
+	}
+}
+
+// ObserveValueSize implements MetricsCollector.
+func (mc *MetricsCounter) ObserveValueSize(op string, size int) {
+	switch op {
+	case MetricsOpGet:
+		atomic.AddUint64(&mc.bytesRead, uint64(size))
+	case MetricsOpSet:
+		atomic.AddUint64(&mc.bytesWritten, uint64(size))
+	}
+}
+
+// ObserveStale implements StaleObserver.
+func (mc *MetricsCounter) ObserveStale(_ string, r StaleResult) {
+	switch r {
+	case StaleResultFresh:
+		atomic.AddUint64(&mc.freshHits, 1)
+	case StaleResultStale:
+		atomic.AddUint64(&mc.staleHits, 1)
+	case StaleResultLoad:
+		atomic.AddUint64(&mc.blockingLoads, 1)
+	}
+}
+
+// Snapshot returns the current values of all counters.
+func (mc *MetricsCounter) Snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		Hits:            atomic.LoadUint64(&mc.hits),
+		Misses:          atomic.LoadUint64(&mc.misses),
+		GetCalls:        atomic.LoadUint64(&mc.getCalls),
+		SetCalls:        atomic.LoadUint64(&mc.setCalls),
+		DeleteCalls:     atomic.LoadUint64(&mc.deleteCalls),
+		TruncateCalls:   atomic.LoadUint64(&mc.truncateCalls),
+		GetLatency:      time.Duration(atomic.LoadInt64(&mc.getLatency)),
+		SetLatency:      time.Duration(atomic.LoadInt64(&mc.setLatency)),
+		DeleteLatency:   time.Duration(atomic.LoadInt64(&mc.deleteLatency)),
+		TruncateLatency: time.Duration(atomic.LoadInt64(&mc.truncateLatency)),
+		BytesRead:       atomic.LoadUint64(&mc.bytesRead),
+		BytesWritten:    atomic.LoadUint64(&mc.bytesWritten),
+		FreshHits:       atomic.LoadUint64(&mc.freshHits),
+		StaleHits:       atomic.LoadUint64(&mc.staleHits),
+		BlockingLoads:   atomic.LoadUint64(&mc.blockingLoads),
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var _ objcache.MetricsCollector = (*objcache.MetricsCounter)(nil)
+
+func TestNewStorageMetrics_Stacked(t *testing.T) {
+	ctx := context.TODO()
+	var outer, l1, l2 objcache.MetricsCounter
+
+	s, err := objcache.NewStorageMetrics(
+		objcache.NewTwoLevel(
+			objcache.NewStorageMetrics(objcache.NewLRU(nil), &l1),
+			objcache.NewStorageMetrics(objcache.NewCacheSimpleInmemory, &l2),
+			objcache.TwoLevelOptions{},
+		),
+		&outer,
+	)()
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Set(ctx, []string{"a", "b"}, [][]byte{[]byte("12"), []byte("345")}, nil))
+	assert.NoError(t, s.Delete(ctx, []string{"a"})) // removes a from both levels
+	vals, err := s.Get(ctx, []string{"a", "b", "c"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, []byte("345"), nil}, vals)
+
+	so := outer.Snapshot()
+	assert.Exactly(t, uint64(1), so.Hits)
+	assert.Exactly(t, uint64(2), so.Misses)
+	assert.Exactly(t, uint64(1), so.GetCalls)
+	assert.Exactly(t, uint64(1), so.SetCalls)
+	assert.Exactly(t, uint64(1), so.DeleteCalls)
+	assert.Exactly(t, uint64(5), so.BytesWritten)
+	assert.Exactly(t, uint64(3), so.BytesRead)
+	assert.Exactly(t, 1.0/3.0, so.HitRatio())
+
+	s1 := l1.Snapshot()
+	assert.Exactly(t, uint64(1), s1.Hits, "L1 hits")
+	assert.Exactly(t, uint64(2), s1.Misses, "L1 misses")
+	assert.Exactly(t, uint64(1), s1.SetCalls, "L1 has not been backfilled")
+
+	s2 := l2.Snapshot()
+	assert.Exactly(t, uint64(0), s2.Hits, "L2 hits")
+	assert.Exactly(t, uint64(2), s2.Misses, "L2 gets only requested for the L1 misses")
+	assert.Exactly(t, uint64(1), s2.GetCalls)
+
+	assert.NoError(t, s.Truncate(ctx))
+	assert.Exactly(t, uint64(1), outer.Snapshot().TruncateCalls)
+	assert.Exactly(t, uint64(1), l1.Snapshot().TruncateCalls)
+	assert.Exactly(t, uint64(1), l2.Snapshot().TruncateCalls)
+	assert.NoError(t, s.Close())
+}
+
+func TestNewStorageMetrics_LRUStats(t *testing.T) {
+	ctx := context.TODO()
+	var mc objcache.MetricsCounter
+	s, err := objcache.NewStorageMetrics(objcache.NewLRU(&objcache.LRUOptions{Capacity: 2}), &mc)()
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Set(ctx, []string{"a", "b", "c"}, [][]byte{[]byte("1"), []byte("2"), []byte("3")}, nil))
+	_, err = s.Get(ctx, []string{"a", "b", "c"})
+	assert.NoError(t, err)
+
+	st, ok := s.(objcache.LRUStatser).LRUStats()
+	assert.True(t, ok)
+	ms := mc.Snapshot()
+	assert.Exactly(t, ms.Hits, st.Hits)
+	assert.Exactly(t, ms.Misses, st.Misses)
+	assert.Exactly(t, uint64(3), st.Sets)
+	assert.Exactly(t, uint64(1), st.Evictions)
+	assert.Exactly(t, int64(2), st.Length)
+
+	s, err = objcache.NewStorageMetrics(objcache.NewCacheSimpleInmemory, &mc)()
+	assert.NoError(t, err)
+	_, ok = s.(objcache.LRUStatser).LRUStats()
+	assert.False(t, ok)
+}
+
+func TestMetricsSnapshot_HitRatio(t *testing.T) {
+	assert.Exactly(t, 0.0, objcache.MetricsSnapshot{}.HitRatio())
+	assert.Exactly(t, 0.75, objcache.MetricsSnapshot{Hits: 3, Misses: 1}.HitRatio())
+}
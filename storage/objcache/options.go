@@ -16,8 +16,11 @@ package objcache
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/corestoreio/log"
 )
 
 var now = time.Now
@@ -35,6 +38,15 @@ type ServiceOptions struct {
 	// information in the cache.
 	PrimeObjects   []interface{}
 	DefaultExpires time.Duration
+	// NegativeCacheTTL if greater zero, caches loader errors in GetSet for
+	// this duration. Further calls to GetSet return an error with kind
+	// NotFound until the entry expires. Should be short.
+	NegativeCacheTTL time.Duration
+	// StaleObserver optionally receives the result of each GetStale call.
+	StaleObserver StaleObserver
+	// Log optionally logs the errors of the background refresh in GetStale
+	// with level info.
+	Log log.Logger
 }
 
 // NewCacheSimpleInmemory creates an in-memory map map[string]string as cache
@@ -87,6 +99,18 @@ func (mc *mapCache) Delete(_ context.Context, keys []string) (err error) {
 	}
 	return nil
 }
+
+// DeleteByPrefix removes all keys starting with prefix.
+func (mc *mapCache) DeleteByPrefix(_ context.Context, prefix string) error {
+	mc.items.Range(func(key, _ interface{}) bool {
+		if strings.HasPrefix(key.(string), prefix) {
+			mc.items.Delete(key)
+		}
+		return true
+	})
+	return nil
+}
+
 func (mc *mapCache) Truncate(ctx context.Context) (err error) {
 	mc.items.Range(func(key, value interface{}) bool {
 		value = nil
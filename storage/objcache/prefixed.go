@@ -0,0 +1,91 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"context"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// PrefixDeleter gets implemented by a Storager which can remove all keys
+// starting with a prefix. Remote backends scan the keys in batches.
+type PrefixDeleter interface {
+	DeleteByPrefix(ctx context.Context, prefix string) error
+}
+
+// deleteByPrefix calls DeleteByPrefix of s or returns a NotSupported error.
+func deleteByPrefix(ctx context.Context, s Storager, prefix string) error {
+	pd, ok := s.(PrefixDeleter)
+	if !ok {
+		return errors.NotSupported.Newf("[objcache] Storage %T does not support DeleteByPrefix", s)
+	}
+	return pd.DeleteByPrefix(ctx, prefix)
+}
+
+// NewPrefixed isolates the keys of inner within a namespace. All keys get
+// prefixed with `prefix` before passing them to inner, hence errors and metrics
+// of the inner storage contain the full keys. Truncate removes only the keys
+// of the namespace and requires that inner implements PrefixDeleter.
+func NewPrefixed(prefix string, inner NewStorageFn) NewStorageFn {
+	return func() (Storager, error) {
+		if prefix == "" {
+			return nil, errors.Empty.Newf("[objcache] NewPrefixed: prefix cannot be empty")
+		}
+		s, err := inner()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return prefixed{prefix: prefix, inner: s}, nil
+	}
+}
+
+type prefixed struct {
+	prefix string
+	inner  Storager
+}
+
+func (p prefixed) keys(keys []string) []string {
+	pKeys := make([]string, len(keys))
+	for i, k := range keys {
+		pKeys[i] = p.prefix + k
+	}
+	return pKeys
+}
+
+func (p prefixed) Set(ctx context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	return p.inner.Set(ctx, p.keys(keys), values, expirations)
+}
+
+func (p prefixed) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	return p.inner.Get(ctx, p.keys(keys))
+}
+
+func (p prefixed) Delete(ctx context.Context, keys []string) error {
+	return p.inner.Delete(ctx, p.keys(keys))
+}
+
+// DeleteByPrefix removes all keys of the namespace starting with subPrefix.
+func (p prefixed) DeleteByPrefix(ctx context.Context, subPrefix string) error {
+	return deleteByPrefix(ctx, p.inner, p.prefix+subPrefix)
+}
+
+// Truncate removes all keys of the namespace.
+func (p prefixed) Truncate(ctx context.Context) error {
+	return p.DeleteByPrefix(ctx, "")
+}
+
+func (p prefixed) Close() error { return p.inner.Close() }
@@ -0,0 +1,100 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// testDeleteByPrefix verifies that two namespaces sharing the same inner
+// storage don't affect each other.
+func testDeleteByPrefix(t *testing.T, inner objcache.Storager) {
+	ctx := context.TODO()
+	sA, err := objcache.NewPrefixed("tenantA:", staticStorage(inner))()
+	assert.NoError(t, err)
+	sB, err := objcache.NewPrefixed("tenantB:", staticStorage(inner))()
+	assert.NoError(t, err)
+
+	keys := []string{"product_42_a", "product_42_b", "product_7_a"}
+	values := [][]byte{[]byte("1"), []byte("2"), []byte("3")}
+	assert.NoError(t, sA.Set(ctx, keys, values, nil))
+	assert.NoError(t, sB.Set(ctx, keys, values, nil))
+
+	vals, err := inner.Get(ctx, []string{"tenantA:product_42_a", "product_42_a"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("1"), nil}, vals)
+
+	assert.NoError(t, sA.(objcache.PrefixDeleter).DeleteByPrefix(ctx, "product_42_"))
+	vals, err = sA.Get(ctx, keys)
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, nil, []byte("3")}, vals)
+
+	assert.NoError(t, sB.Truncate(ctx))
+	vals, err = sB.Get(ctx, keys)
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, nil, nil}, vals)
+
+	vals, err = sA.Get(ctx, keys)
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, nil, []byte("3")}, vals)
+}
+
+func TestNewPrefixed(t *testing.T) {
+	t.Run("map", func(t *testing.T) {
+		inner, err := objcache.NewCacheSimpleInmemory()
+		assert.NoError(t, err)
+		testDeleteByPrefix(t, inner)
+	})
+	t.Run("lru", func(t *testing.T) {
+		inner, err := objcache.NewLRU(&objcache.LRUOptions{Capacity: 100})()
+		assert.NoError(t, err)
+		testDeleteByPrefix(t, inner)
+	})
+	t.Run("empty prefix", func(t *testing.T) {
+		_, err := objcache.NewPrefixed("", objcache.NewCacheSimpleInmemory)()
+		assert.ErrorIsKind(t, errors.Empty, err)
+	})
+	t.Run("inner not supported", func(t *testing.T) {
+		s, err := objcache.NewPrefixed("ns:", objcache.NewBlackHoleClient(nil))()
+		assert.NoError(t, err)
+		assert.ErrorIsKind(t, errors.NotSupported, s.Truncate(context.TODO()))
+	})
+}
+
+func TestService_DeleteByPrefix(t *testing.T) {
+	ctx := context.TODO()
+	srv, err := objcache.NewService(
+		objcache.NewLRU(&objcache.LRUOptions{Capacity: 100}),
+		objcache.NewPrefixed("ns:", objcache.NewCacheSimpleInmemory),
+		newSrvOpt(JSONCodec{}),
+	)
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, srv.Close()) }()
+
+	assert.NoError(t, srv.Set(ctx, "product_42_price", 9.95, 0))
+	assert.NoError(t, srv.Set(ctx, "product_7_price", 1.95, 0))
+	assert.NoError(t, srv.DeleteByPrefix(ctx, "product_42_"))
+
+	var price float64
+	assert.NoError(t, srv.Get(ctx, "product_42_price", &price))
+	assert.Exactly(t, 0.0, price)
+	assert.NoError(t, srv.Get(ctx, "product_7_price", &price))
+	assert.Exactly(t, 1.95, price)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	gourl "net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/corestoreio/errors"
@@ -82,8 +83,29 @@ func parseInt(keys []string, ds []*int, params gourl.Values) (err error) {
 // For example:
 // 		redis://localhost:6379/?db=3
 // 		redis://localhost:6379/?max_active=50&max_idle=5&idle_timeout=10s&max_conn_lifetime=1m&key_prefix=xcache_
+//
+// The schemes "redis-cluster" and "redis-sentinel" accept a comma separated
+// list of hosts and create a Storager via NewRedisCluster or
+// NewRedisSentinel. The path of a sentinel URL defines the master name.
+// 		redis-cluster://node1:7000,node2:7001/?key_prefix=xcache_&read_timeout=1s
+// 		redis-sentinel://:password@sentinel1:26379,sentinel2:26379/mymaster?db=3
 func NewRedisByURLClient(rawURL string) NewStorageFn {
 	return func() (Storager, error) {
+		switch {
+		case strings.HasPrefix(rawURL, redisClusterScheme+"://"):
+			o, err := newRedisClusterByURL(rawURL)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			return NewRedisCluster(o)()
+		case strings.HasPrefix(rawURL, redisSentinelScheme+"://"):
+			o, err := newRedisSentinelByURL(rawURL)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			return NewRedisSentinel(o)()
+		}
+
 		addr, _, password, params, err := url.ParseConnection(rawURL)
 		if err != nil {
 			return nil, errors.Wrapf(err, "[objcache] Redis error parsing URL %q", rawURL)
@@ -178,7 +200,20 @@ type redisWrapper struct {
 	// ifp  *sync.Pool
 }
 
+// prefixKeys prepends the key prefix to each key.
+func (w redisWrapper) prefixKeys(keys []string) []string {
+	if w.keyPrefix == "" {
+		return keys
+	}
+	pKeys := make([]string, len(keys))
+	for i, k := range keys {
+		pKeys[i] = w.keyPrefix + k
+	}
+	return pKeys
+}
+
 func (w redisWrapper) Set(_ context.Context, keys []string, values [][]byte, expirations []time.Duration) (err error) {
+	keys = w.prefixKeys(keys)
 	conn := w.Pool.Get()
 	defer func() {
 		if err2 := conn.Close(); err == nil && err2 != nil {
@@ -188,12 +223,15 @@ func (w redisWrapper) Set(_ context.Context, keys []string, values [][]byte, exp
 
 	args := make([]interface{}, 0, len(keys)*3)
 	for i, key := range keys {
-		e := expirations[i].Seconds() // e = expires in x seconds
+		var e float64 // e = expires in x seconds
+		if i < len(expirations) {
+			e = expirations[i].Seconds()
+		}
 		if e < 1 {
 			args = append(args, key, values[i])
 		} else {
 			if _, err2 := conn.Do("SETEX", key, e, values[i]); err2 != nil {
-				err = errors.Wrapf(err2, "[objcache] With key %q", key)
+				err = wrapRedisErr(err2, "[objcache] With key %q", key)
 				return
 			}
 		}
@@ -201,7 +239,7 @@ func (w redisWrapper) Set(_ context.Context, keys []string, values [][]byte, exp
 
 	if la := len(args); la > 0 && la%2 == 0 {
 		if _, err2 := conn.Do("MSET", args...); err2 != nil {
-			err = errors.Wrapf(err2, "[objcache] With keys %v", keys)
+			err = wrapRedisErr(err2, "[objcache] With keys %v", keys)
 			return
 		}
 	}
@@ -210,6 +248,7 @@ func (w redisWrapper) Set(_ context.Context, keys []string, values [][]byte, exp
 }
 
 func (w redisWrapper) Get(_ context.Context, keys []string) (values [][]byte, err error) {
+	keys = w.prefixKeys(keys)
 	conn := w.Pool.Get()
 	defer func() {
 		if err2 := conn.Close(); err == nil && err2 != nil {
@@ -225,7 +264,7 @@ func (w redisWrapper) Get(_ context.Context, keys []string) (values [][]byte, er
 				err = nil
 				val = nil
 			} else {
-				return nil, errors.Wrapf(err, "[objcache] With keys %v", keys)
+				return nil, wrapRedisErr(err, "[objcache] With keys %v", keys)
 			}
 		}
 		values = append(values, val)
@@ -234,7 +273,7 @@ func (w redisWrapper) Get(_ context.Context, keys []string) (values [][]byte, er
 
 	values, err = redis.ByteSlices(conn.Do("MGET", strSliceToIFaces(nil, keys)...))
 	if err != nil {
-		err = errors.Wrapf(err, "[objcache] With keys %v", keys)
+		err = wrapRedisErr(err, "[objcache] With keys %v", keys)
 		return
 	}
 	if lk, lv := len(keys), len(values); lk != lv {
@@ -255,6 +294,7 @@ func strSliceToIFaces(ret []interface{}, sl []string) []interface{} {
 }
 
 func (w redisWrapper) Delete(_ context.Context, keys []string) (err error) {
+	keys = w.prefixKeys(keys)
 	conn := w.Pool.Get()
 	defer func() {
 		if err2 := conn.Close(); err == nil && err2 != nil {
@@ -262,14 +302,76 @@ func (w redisWrapper) Delete(_ context.Context, keys []string) (err error) {
 		}
 	}()
 	if _, err = conn.Do("DEL", strSliceToIFaces(nil, keys)...); err != nil {
-		err = errors.Wrapf(err, "[objcache] With keys %v", keys)
+		err = wrapRedisErr(err, "[objcache] With keys %v", keys)
 	}
 	return
 }
 
-func (w redisWrapper) Truncate(ctx context.Context) (err error) {
-	// TODO flush redis by key prefix
-	return nil
+// DeleteByPrefix removes all keys starting with the key prefix and prefix in
+// batches via SCAN and DEL.
+func (w redisWrapper) DeleteByPrefix(_ context.Context, prefix string) (err error) {
+	conn := w.Pool.Get()
+	defer func() {
+		if err2 := conn.Close(); err == nil && err2 != nil {
+			err = err2
+		}
+	}()
+	match := w.keyPrefix + prefix + "*"
+	if err = redisScanDelete(conn, match, nil); err != nil {
+		err = wrapRedisErr(err, "[objcache] DeleteByPrefix with pattern %q", match)
+	}
+	return err
+}
+
+// redisScanDelete deletes all keys matching the pattern in batches. If
+// groupFn is not nil, it splits the keys of one batch into groups which get
+// deleted with one command each.
+func redisScanDelete(conn redis.Conn, match string, groupFn func(keys []string) [][]int) error {
+	cursor := "0"
+	for {
+		reply, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", match, "COUNT", 1000))
+		if err != nil {
+			return err
+		}
+		if len(reply) != 2 {
+			return errors.Mismatch.Newf("[objcache] Unexpected SCAN reply length %d", len(reply))
+		}
+		if cursor, err = redis.String(reply[0], nil); err != nil {
+			return err
+		}
+		keys, err := redis.Strings(reply[1], nil)
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			groups := [][]int{nil}
+			if groupFn != nil {
+				groups = groupFn(keys)
+			} else {
+				for i := range keys {
+					groups[0] = append(groups[0], i)
+				}
+			}
+			for _, group := range groups {
+				args := make([]interface{}, len(group))
+				for j, i := range group {
+					args[j] = keys[i]
+				}
+				if _, err := conn.Do("DEL", args...); err != nil {
+					return err
+				}
+			}
+		}
+		if cursor == "0" {
+			return nil
+		}
+	}
+}
+
+// Truncate removes only the keys with the key prefix. Without a key prefix all
+// keys of the selected database get removed.
+func (w redisWrapper) Truncate(ctx context.Context) error {
+	return w.DeleteByPrefix(ctx, "")
 }
 
 func (w redisWrapper) Close() error {
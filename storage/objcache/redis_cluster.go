@@ -0,0 +1,437 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build redis csall
+
+package objcache
+
+import (
+	"context"
+	"io"
+	"net"
+	gourl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FZambia/sentinel"
+	"github.com/corestoreio/errors"
+	"github.com/gomodule/redigo/redis"
+	"github.com/mna/redisc"
+)
+
+// Additional URL schemes supported by NewRedisByURLClient.
+const (
+	redisClusterScheme  = "redis-cluster"
+	redisSentinelScheme = "redis-sentinel"
+)
+
+// isRedisRetryable reports whether the error has been caused by a failover, a
+// resharding or a lost connection. Retrying the command later might succeed.
+func isRedisRetryable(err error) bool {
+	err = errors.Cause(err)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	if re, ok := err.(redis.Error); ok {
+		for _, prefix := range [...]string{"READONLY", "LOADING", "TRYAGAIN", "CLUSTERDOWN", "MASTERDOWN"} {
+			if strings.HasPrefix(string(re), prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wrapRedisErr wraps err with kind Temporary if the command can be retried.
+func wrapRedisErr(err error, format string, args ...interface{}) error {
+	if isRedisRetryable(err) {
+		return errors.Temporary.New(err, format, args...)
+	}
+	return errors.Wrapf(err, format, args...)
+}
+
+// RedisClusterOptions configures NewRedisCluster.
+type RedisClusterOptions struct {
+	// StartupNodes contains at least one host:port of the cluster. The
+	// remaining topology gets discovered.
+	StartupNodes []string
+	Password     string
+	// KeyPrefix gets prepended to each key and defines the namespace removed
+	// by Truncate. Truncate requires a KeyPrefix.
+	KeyPrefix string
+	// HashTag, if set, gets prepended as "{HashTag}" to each key. All keys
+	// map then to the same hash slot, which allows multi-key commands in one
+	// request but puts all keys on one master.
+	HashTag      string
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	MaxIdle      int
+	MaxActive    int
+	IdleTimeout  time.Duration
+	// MaxAttempts defines how often a command follows MOVED and ASK
+	// redirects or retries after a TRYAGAIN error. Default 3.
+	MaxAttempts int
+	// TryAgainDelay defaults to 100ms.
+	TryAgainDelay time.Duration
+}
+
+func (o RedisClusterOptions) dialOptions() []redis.DialOption {
+	var dos []redis.DialOption
+	if o.Password != "" {
+		dos = append(dos, redis.DialPassword(o.Password))
+	}
+	if o.DialTimeout > 0 {
+		dos = append(dos, redis.DialConnectTimeout(o.DialTimeout))
+	}
+	if o.ReadTimeout > 0 {
+		dos = append(dos, redis.DialReadTimeout(o.ReadTimeout))
+	}
+	if o.WriteTimeout > 0 {
+		dos = append(dos, redis.DialWriteTimeout(o.WriteTimeout))
+	}
+	return dos
+}
+
+// NewRedisCluster creates a Storager for a Redis Cluster. Multi-key commands get
+// split per hash slot to avoid CROSSSLOT errors. MOVED and ASK redirects get
+// followed. Errors caused by a failover or resharding have the kind Temporary.
+func NewRedisCluster(o RedisClusterOptions) NewStorageFn {
+	if o.MaxAttempts < 1 {
+		o.MaxAttempts = 3
+	}
+	if o.TryAgainDelay == 0 {
+		o.TryAgainDelay = 100 * time.Millisecond
+	}
+	return func() (Storager, error) {
+		if len(o.StartupNodes) == 0 {
+			return nil, errors.Empty.Newf("[objcache] NewRedisCluster: StartupNodes cannot be empty")
+		}
+		c := &redisc.Cluster{
+			StartupNodes: o.StartupNodes,
+			DialOptions:  o.dialOptions(),
+			CreatePool: func(addr string, opts ...redis.DialOption) (*redis.Pool, error) {
+				return &redis.Pool{
+					MaxIdle:     o.MaxIdle,
+					MaxActive:   o.MaxActive,
+					IdleTimeout: o.IdleTimeout,
+					Dial: func() (redis.Conn, error) {
+						return redis.Dial("tcp", addr, opts...)
+					},
+				}, nil
+			},
+		}
+		if err := c.Refresh(); err != nil {
+			_ = c.Close()
+			return nil, errors.ConnectionFailed.New(err, "[objcache] NewRedisCluster failed to load the topology from %v", o.StartupNodes)
+		}
+		return &redisCluster{cluster: c, opt: o}, nil
+	}
+}
+
+type redisCluster struct {
+	cluster *redisc.Cluster
+	opt     RedisClusterOptions
+}
+
+func (rc *redisCluster) key(k string) string {
+	if rc.opt.HashTag != "" {
+		return "{" + rc.opt.HashTag + "}" + rc.opt.KeyPrefix + k
+	}
+	return rc.opt.KeyPrefix + k
+}
+
+// slotGroups returns the indexes of the keys grouped by their hash slot. The
+// order of the groups follows the first occurrence in keys.
+func slotGroups(keys []string) [][]int {
+	var groups [][]int
+	slotIdx := make(map[int]int, len(keys))
+	for i, k := range keys {
+		s := redisc.Slot(k)
+		gi, ok := slotIdx[s]
+		if !ok {
+			gi = len(groups)
+			slotIdx[s] = gi
+			groups = append(groups, nil)
+		}
+		groups[gi] = append(groups[gi], i)
+	}
+	return groups
+}
+
+// do executes one command on a connection which follows redirects.
+func (rc *redisCluster) do(cmd string, args ...interface{}) (reply interface{}, err error) {
+	conn, err := redisc.RetryConn(rc.cluster.Get(), rc.opt.MaxAttempts, rc.opt.TryAgainDelay)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err2 := conn.Close(); err == nil && err2 != nil {
+			err = err2
+		}
+	}()
+	return conn.Do(cmd, args...)
+}
+
+// Set writes the keys without expiration per hash slot via MSET and the others
+// via SET with PX.
+func (rc *redisCluster) Set(_ context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	pKeys := make([]string, len(keys))
+	for i, k := range keys {
+		pKeys[i] = rc.key(k)
+	}
+	for _, group := range slotGroups(pKeys) {
+		args := make([]interface{}, 0, len(group)*2)
+		for _, i := range group {
+			var e time.Duration
+			if i < len(expirations) {
+				e = expirations[i]
+			}
+			if e <= 0 {
+				args = append(args, pKeys[i], values[i])
+				continue
+			}
+			ms := int64(e / time.Millisecond)
+			if ms < 1 {
+				ms = 1
+			}
+			if _, err := rc.do("SET", pKeys[i], values[i], "PX", ms); err != nil {
+				return wrapRedisErr(err, "[objcache] RedisCluster.Set with key %q", keys[i])
+			}
+		}
+		if len(args) > 0 {
+			if _, err := rc.do("MSET", args...); err != nil {
+				return wrapRedisErr(err, "[objcache] RedisCluster.Set with keys %v", keys)
+			}
+		}
+	}
+	return nil
+}
+
+// Get requests the keys per hash slot via MGET.
+func (rc *redisCluster) Get(_ context.Context, keys []string) ([][]byte, error) {
+	pKeys := make([]string, len(keys))
+	for i, k := range keys {
+		pKeys[i] = rc.key(k)
+	}
+	values := make([][]byte, len(keys))
+	for _, group := range slotGroups(pKeys) {
+		args := make([]interface{}, len(group))
+		for j, i := range group {
+			args[j] = pKeys[i]
+		}
+		vals, err := redis.ByteSlices(rc.do("MGET", args...))
+		if err != nil {
+			return nil, wrapRedisErr(err, "[objcache] RedisCluster.Get with keys %v", keys)
+		}
+		if len(vals) != len(group) {
+			return nil, errors.Mismatch.Newf("[objcache] RedisCluster.Get: Length of keys (%d) does not match length of returned bytes (%d). Keys: %v", len(group), len(vals), keys)
+		}
+		for j, i := range group {
+			values[i] = vals[j]
+		}
+	}
+	return values, nil
+}
+
+// Delete removes the keys per hash slot.
+func (rc *redisCluster) Delete(_ context.Context, keys []string) error {
+	pKeys := make([]string, len(keys))
+	for i, k := range keys {
+		pKeys[i] = rc.key(k)
+	}
+	for _, group := range slotGroups(pKeys) {
+		args := make([]interface{}, len(group))
+		for j, i := range group {
+			args[j] = pKeys[i]
+		}
+		if _, err := rc.do("DEL", args...); err != nil {
+			return wrapRedisErr(err, "[objcache] RedisCluster.Delete with keys %v", keys)
+		}
+	}
+	return nil
+}
+
+// DeleteByPrefix iterates all masters and removes the keys starting with the
+// namespace and prefix via SCAN and DEL. FLUSHALL gets never used.
+func (rc *redisCluster) DeleteByPrefix(_ context.Context, prefix string) error {
+	match := rc.key(prefix) + "*"
+	err := rc.cluster.EachNode(false, func(_ string, conn redis.Conn) error {
+		return redisScanDelete(conn, match, slotGroups)
+	})
+	if err != nil {
+		return wrapRedisErr(err, "[objcache] RedisCluster.DeleteByPrefix with pattern %q", match)
+	}
+	return nil
+}
+
+// Truncate removes all keys of the namespace.
+func (rc *redisCluster) Truncate(ctx context.Context) error {
+	if rc.opt.KeyPrefix == "" && rc.opt.HashTag == "" {
+		return errors.NotSupported.Newf("[objcache] RedisCluster.Truncate requires a KeyPrefix or HashTag")
+	}
+	return rc.DeleteByPrefix(ctx, "")
+}
+
+func (rc *redisCluster) Close() error {
+	return errors.WithStack(rc.cluster.Close())
+}
+
+// RedisSentinelOptions configures NewRedisSentinel.
+type RedisSentinelOptions struct {
+	// Sentinels contains the host:port addresses of the sentinels.
+	Sentinels   []string
+	MasterName  string
+	Password    string
+	DB          int
+	KeyPrefix   string
+	DialTimeout time.Duration
+	MaxIdle     int
+	MaxActive   int
+	IdleTimeout time.Duration
+}
+
+// NewRedisSentinel creates a Storager which asks the sentinels for the current
+// master. After a failover the pool connects to the new master. Errors caused
+// by the failover have the kind Temporary.
+func NewRedisSentinel(o RedisSentinelOptions) NewStorageFn {
+	return func() (Storager, error) {
+		if len(o.Sentinels) == 0 || o.MasterName == "" {
+			return nil, errors.Empty.Newf("[objcache] NewRedisSentinel: Sentinels and MasterName cannot be empty")
+		}
+		var dos []redis.DialOption
+		if o.DialTimeout > 0 {
+			dos = append(dos, redis.DialConnectTimeout(o.DialTimeout))
+		}
+		sntnl := &sentinel.Sentinel{
+			Addrs:      o.Sentinels,
+			MasterName: o.MasterName,
+			Dial: func(addr string) (redis.Conn, error) {
+				return redis.Dial("tcp", addr, dos...)
+			},
+		}
+		pool := &redis.Pool{
+			MaxIdle:     o.MaxIdle,
+			MaxActive:   o.MaxActive,
+			IdleTimeout: o.IdleTimeout,
+			Dial: func() (redis.Conn, error) {
+				addr, err := sntnl.MasterAddr()
+				if err != nil {
+					return nil, errors.Temporary.New(err, "[objcache] Redis Sentinel failed to discover master %q", o.MasterName)
+				}
+				return redis.Dial("tcp", addr, append(dos, redis.DialPassword(o.Password), redis.DialDatabase(o.DB))...)
+			},
+			TestOnBorrow: func(c redis.Conn, _ time.Time) error {
+				if !sentinel.TestRole(c, "master") {
+					return errors.Temporary.Newf("[objcache] Redis Sentinel: Connection does not point to master %q", o.MasterName)
+				}
+				return nil
+			},
+		}
+		s, err := NewRedisClient(pool, &RedisOption{KeyPrefix: o.KeyPrefix})()
+		if err != nil {
+			_ = sntnl.Close()
+			return nil, errors.WithStack(err)
+		}
+		return redisSentinel{Storager: s, sntnl: sntnl}, nil
+	}
+}
+
+type redisSentinel struct {
+	Storager
+	sntnl *sentinel.Sentinel
+}
+
+func (rs redisSentinel) Close() error {
+	err := rs.Storager.Close()
+	if err2 := rs.sntnl.Close(); err == nil {
+		err = err2
+	}
+	return errors.WithStack(err)
+}
+
+// parseRedisMultiHostURL parses the URL of the schemes redis-cluster and
+// redis-sentinel which contain a comma separated list of hosts.
+func parseRedisMultiHostURL(rawURL string) (hosts []string, password, path string, params gourl.Values, err error) {
+	u, err := gourl.Parse(rawURL)
+	if err != nil {
+		return nil, "", "", nil, errors.NotValid.New(err, "[objcache] Redis error parsing URL")
+	}
+	for _, h := range strings.Split(u.Host, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	if u.User != nil {
+		password, _ = u.User.Password()
+	}
+	return hosts, password, strings.Trim(u.Path, "/"), u.Query(), nil
+}
+
+// newRedisClusterByURL parses for example:
+//		redis-cluster://node1:7000,node2:7001/?key_prefix=cs_&hash_tag=cs&max_idle=5&read_timeout=1s
+func newRedisClusterByURL(rawURL string) (RedisClusterOptions, error) {
+	hosts, password, _, params, err := parseRedisMultiHostURL(rawURL)
+	if err != nil {
+		return RedisClusterOptions{}, errors.WithStack(err)
+	}
+	o := RedisClusterOptions{
+		StartupNodes: hosts,
+		Password:     password,
+		KeyPrefix:    params.Get("key_prefix"),
+		HashTag:      params.Get("hash_tag"),
+	}
+	if err := parseDuration([]string{
+		"dial_timeout", "read_timeout", "write_timeout", "idle_timeout", "try_again_delay",
+	}, []*time.Duration{
+		&o.DialTimeout, &o.ReadTimeout, &o.WriteTimeout, &o.IdleTimeout, &o.TryAgainDelay,
+	}, params); err != nil {
+		return o, errors.WithStack(err)
+	}
+	err = parseInt([]string{
+		"max_idle", "max_active", "max_attempts",
+	}, []*int{
+		&o.MaxIdle, &o.MaxActive, &o.MaxAttempts,
+	}, params)
+	return o, errors.WithStack(err)
+}
+
+// newRedisSentinelByURL parses for example:
+//		redis-sentinel://:password@sentinel1:26379,sentinel2:26379/mymaster?db=3&key_prefix=cs_
+func newRedisSentinelByURL(rawURL string) (RedisSentinelOptions, error) {
+	hosts, password, masterName, params, err := parseRedisMultiHostURL(rawURL)
+	if err != nil {
+		return RedisSentinelOptions{}, errors.WithStack(err)
+	}
+	o := RedisSentinelOptions{
+		Sentinels:  hosts,
+		MasterName: masterName,
+		Password:   password,
+		KeyPrefix:  params.Get("key_prefix"),
+	}
+	if db := params.Get("db"); db != "" {
+		if o.DB, err = strconv.Atoi(db); err != nil {
+			return o, errors.NotValid.New(err, "[objcache] NewRedisByURLClient Parameter %q with value %q is invalid", "db", db)
+		}
+	}
+	if err := parseDuration([]string{"dial_timeout", "idle_timeout"}, []*time.Duration{&o.DialTimeout, &o.IdleTimeout}, params); err != nil {
+		return o, errors.WithStack(err)
+	}
+	err = parseInt([]string{"max_idle", "max_active"}, []*int{&o.MaxIdle, &o.MaxActive}, params)
+	return o, errors.WithStack(err)
+}
@@ -0,0 +1,75 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build redis csall
+
+package objcache_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func lookupRedisClusterEnv(t testing.TB) string {
+	redConURL := os.Getenv("CS_REDIS_CLUSTER_TEST")
+	if redConURL == "" {
+		t.Skip(`Skipping live test because environment CS_REDIS_CLUSTER_TEST variable not found.
+	export CS_REDIS_CLUSTER_TEST="redis-cluster://127.0.0.1:7000,127.0.0.1:7001/?key_prefix=cs_test_"
+		`)
+	}
+	return redConURL
+}
+
+func TestNewRedisCluster_Integration(t *testing.T) {
+	redConURL := lookupRedisClusterEnv(t)
+	ctx := context.TODO()
+
+	s, err := objcache.NewRedisByURLClient(redConURL)()
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	// keys spread over several hash slots
+	keys := []string{"alpha", "beta", "gamma", "delta", "epsilon"}
+	values := [][]byte{[]byte("1"), []byte("2"), []byte("3"), []byte("4"), []byte("5")}
+	assert.NoError(t, s.Set(ctx, keys, values, []time.Duration{0, time.Minute, 0, 0, time.Hour}))
+
+	vals, err := s.Get(ctx, append(keys, "missing"))
+	assert.NoError(t, err)
+	assert.Exactly(t, append(values, nil), vals)
+
+	assert.NoError(t, s.Delete(ctx, []string{"alpha", "gamma"}))
+	vals, err = s.Get(ctx, []string{"alpha", "beta", "gamma"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, []byte("2"), nil}, vals)
+
+	assert.NoError(t, s.Truncate(ctx))
+	vals, err = s.Get(ctx, keys)
+	assert.NoError(t, err)
+	assert.Exactly(t, make([][]byte, len(keys)), vals)
+}
+
+func TestNewRedisSentinel_Integration(t *testing.T) {
+	redConURL := os.Getenv("CS_REDIS_SENTINEL_TEST")
+	if redConURL == "" {
+		t.Skip(`Skipping live test because environment CS_REDIS_SENTINEL_TEST variable not found.
+	export CS_REDIS_SENTINEL_TEST="redis-sentinel://127.0.0.1:26379/mymaster?db=3"
+		`)
+	}
+	newTestServiceDelete(t, objcache.NewRedisByURLClient(redConURL))
+}
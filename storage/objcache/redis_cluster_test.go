@@ -0,0 +1,94 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build redis csall
+
+package objcache
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/gomodule/redigo/redis"
+	"github.com/mna/redisc"
+)
+
+var _ Storager = (*redisCluster)(nil)
+
+func TestSlotGroups(t *testing.T) {
+	keys := []string{"{a}1", "{b}1", "{a}2", "{c}1", "{b}2"}
+	groups := slotGroups(keys)
+	assert.Exactly(t, [][]int{{0, 2}, {1, 4}, {3}}, groups)
+	for _, g := range groups {
+		for _, i := range g {
+			assert.Exactly(t, redisc.Slot(keys[g[0]]), redisc.Slot(keys[i]))
+		}
+	}
+	assert.Len(t, slotGroups(nil), 0)
+}
+
+func TestRedisCluster_Key(t *testing.T) {
+	rc := &redisCluster{opt: RedisClusterOptions{KeyPrefix: "cs_"}}
+	assert.Exactly(t, "cs_key", rc.key("key"))
+	rc.opt.HashTag = "tag"
+	assert.Exactly(t, "{tag}cs_key", rc.key("key"))
+	assert.Len(t, slotGroups([]string{rc.key("a"), rc.key("b"), rc.key("c")}), 1)
+}
+
+func TestIsRedisRetryable(t *testing.T) {
+	assert.True(t, isRedisRetryable(io.EOF))
+	assert.True(t, isRedisRetryable(errors.WithStack(io.ErrUnexpectedEOF)))
+	assert.True(t, isRedisRetryable(redis.Error("READONLY You can't write against a read only replica.")))
+	assert.True(t, isRedisRetryable(redis.Error("CLUSTERDOWN The cluster is down")))
+	assert.False(t, isRedisRetryable(redis.Error("ERR wrong number of arguments")))
+	assert.False(t, isRedisRetryable(nil))
+
+	assert.ErrorIsKind(t, errors.Temporary, wrapRedisErr(redis.Error("LOADING"), "key %q", "a"))
+	assert.NoError(t, wrapRedisErr(nil, "key %q", "a"))
+}
+
+func TestNewRedisClusterByURL(t *testing.T) {
+	o, err := newRedisClusterByURL("redis-cluster://:secret@node1:7000,node2:7001/?key_prefix=cs_&hash_tag=tag&max_idle=5&read_timeout=1s")
+	assert.NoError(t, err)
+	assert.Exactly(t, RedisClusterOptions{
+		StartupNodes: []string{"node1:7000", "node2:7001"},
+		Password:     "secret",
+		KeyPrefix:    "cs_",
+		HashTag:      "tag",
+		ReadTimeout:  time.Second,
+		MaxIdle:      5,
+	}, o)
+
+	_, err = newRedisClusterByURL("redis-cluster://node1:7000/?max_idle=x")
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
+
+func TestNewRedisSentinelByURL(t *testing.T) {
+	o, err := newRedisSentinelByURL("redis-sentinel://:secret@s1:26379,s2:26379/mymaster?db=3&key_prefix=cs_&dial_timeout=2s")
+	assert.NoError(t, err)
+	assert.Exactly(t, RedisSentinelOptions{
+		Sentinels:   []string{"s1:26379", "s2:26379"},
+		MasterName:  "mymaster",
+		Password:    "secret",
+		DB:          3,
+		KeyPrefix:   "cs_",
+		DialTimeout: 2 * time.Second,
+	}, o)
+
+	_, err = newRedisSentinelByURL("redis-sentinel://s1:26379/mymaster?db=x")
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
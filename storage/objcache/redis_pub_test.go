@@ -148,3 +148,17 @@ func TestWithRedisURLReal_Delete(t *testing.T) {
 	redConURL := lookupRedisEnv(t)
 	newTestServiceDelete(t, objcache.NewRedisByURLClient(redConURL))
 }
+
+func TestWithRedisURLMock_DeleteByPrefix(t *testing.T) {
+	mr := miniredis.NewMiniRedis()
+	assert.NoError(t, mr.Start())
+	defer mr.Close()
+	s, err := objcache.NewRedisByURLClient(fmt.Sprintf("redis://%s/?key_prefix=app:", mr.Addr()))()
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, s.Close()) }()
+	assert.NoError(t, mr.Set("other", "value"))
+
+	testDeleteByPrefix(t, s)
+	assert.True(t, mr.Exists("app:tenantA:product_7_a"), "key should have the prefix")
+	assert.True(t, mr.Exists("other"), "key outside of the prefix must not be deleted")
+}
@@ -0,0 +1,310 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"bytes"
+	"context"
+	encbinary "encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// SegmentOptions configures the GC friendly in-memory storage created with
+// NewSegmentCache.
+type SegmentOptions struct {
+	// MaxBytes defines the approximate upper limit of memory used by all
+	// segments together. The memory gets allocated up front. Default 64MB.
+	MaxBytes int
+	// Shards defines the number of segments, each protected by its own mutex.
+	// Must be a power of two. Default 256.
+	Shards int
+	// Clock returns the current time. Defaults to time.Now. Mainly used for
+	// testing.
+	Clock func() time.Time
+}
+
+// NewSegmentCache creates an in-memory storage which does not put pressure on
+// the GC. Keys and values get copied into large pre-allocated byte segments,
+// used as ring buffers. The index of each segment maps the hash of a key to its
+// offset and contains no pointers, hence the GC does not need to scan it. The
+// full key gets stored next to the value to detect hash collisions.
+//
+// When a segment runs out of space, the oldest entries get evicted in FIFO
+// order. Overwritten, deleted and expired entries occupy their space until the
+// ring buffer wraps around. An entry larger than a segment returns a TooLarge
+// error. Argument `o` can be nil, if so default values get applied.
+func NewSegmentCache(o *SegmentOptions) NewStorageFn {
+	if o == nil {
+		o = &SegmentOptions{}
+	}
+	return func() (Storager, error) {
+		opt := *o
+		if opt.MaxBytes == 0 {
+			opt.MaxBytes = 1 << 26 // 64MB
+		}
+		if opt.Shards == 0 {
+			opt.Shards = 256
+		}
+		if opt.Clock == nil {
+			opt.Clock = time.Now
+		}
+		if opt.Shards < 0 || opt.Shards&(opt.Shards-1) != 0 {
+			return nil, errors.NotValid.Newf("[objcache] NewSegmentCache: Shards %d must be a power of two", opt.Shards)
+		}
+		segSize := opt.MaxBytes / opt.Shards
+		if segSize < segmentHeaderSize || int64(segSize) > int64(maxSegmentSize) {
+			return nil, errors.NotValid.Newf("[objcache] NewSegmentCache: Segment size %d (MaxBytes %d / Shards %d) out of range", segSize, opt.MaxBytes, opt.Shards)
+		}
+		sc := &segmentCache{
+			segments: make([]segment, opt.Shards),
+			mask:     uint64(opt.Shards - 1),
+			now:      opt.Clock,
+		}
+		for i := range sc.segments {
+			sc.segments[i].buf = make([]byte, segSize)
+			sc.segments[i].reset()
+		}
+		return sc, nil
+	}
+}
+
+// segmentHeaderSize defines the bytes written in front of each key/value
+// pair: hash uint64, expires unix nano int64, key length uint16 and value
+// length uint32.
+const segmentHeaderSize = 8 + 8 + 2 + 4
+
+const (
+	maxSegmentSize = 1<<32 - 1
+	maxKeyLength   = 1<<16 - 1
+)
+
+type segmentCache struct {
+	segments []segment
+	mask     uint64
+	now      func() time.Time
+}
+
+// segment stores entries in a ring buffer. head points to the oldest entry,
+// tail to the next free byte and used counts the occupied bytes. When an entry
+// does not fit at the end of buf, the remaining bytes starting at wrap get
+// skipped and writing continues at offset zero.
+type segment struct {
+	mu    sync.RWMutex
+	buf   []byte
+	index map[uint64]uint32
+	head  int
+	tail  int
+	used  int
+	wrap  int
+}
+
+func (s *segment) reset() {
+	s.index = make(map[uint64]uint32)
+	s.head, s.tail, s.used = 0, 0, 0
+	s.wrap = len(s.buf)
+}
+
+// evictHead removes the oldest entry or skips the unused bytes at the end of
+// the buffer.
+func (s *segment) evictHead() {
+	if s.head == s.wrap {
+		s.used -= len(s.buf) - s.head
+		s.head = 0
+		s.wrap = len(s.buf)
+		return
+	}
+	h := encbinary.LittleEndian.Uint64(s.buf[s.head:])
+	if off, ok := s.index[h]; ok && int(off) == s.head {
+		delete(s.index, h)
+	}
+	n := s.entrySize(s.head)
+	s.head += n
+	s.used -= n
+}
+
+func (s *segment) entrySize(off int) int {
+	kl := int(encbinary.LittleEndian.Uint16(s.buf[off+16:]))
+	vl := int(encbinary.LittleEndian.Uint32(s.buf[off+18:]))
+	return segmentHeaderSize + kl + vl
+}
+
+// alloc evicts the oldest entries until n contiguous bytes are free and
+// returns their offset.
+func (s *segment) alloc(n int) int {
+	for {
+		if s.used == 0 {
+			s.head, s.tail = 0, 0
+			s.wrap = len(s.buf)
+		}
+		if s.tail > s.head || s.used == 0 {
+			if len(s.buf)-s.tail >= n {
+				return s.tail
+			}
+			s.wrap = s.tail
+			s.used += len(s.buf) - s.tail
+			s.tail = 0
+			continue
+		}
+		if s.head-s.tail >= n {
+			return s.tail
+		}
+		s.evictHead()
+	}
+}
+
+func (s *segment) set(h uint64, key string, value []byte, expires int64) {
+	n := segmentHeaderSize + len(key) + len(value)
+	off := s.alloc(n)
+	b := s.buf[off : off+n]
+	encbinary.LittleEndian.PutUint64(b, h)
+	encbinary.LittleEndian.PutUint64(b[8:], uint64(expires))
+	encbinary.LittleEndian.PutUint16(b[16:], uint16(len(key)))
+	encbinary.LittleEndian.PutUint32(b[18:], uint32(len(value)))
+	copy(b[segmentHeaderSize:], key)
+	copy(b[segmentHeaderSize+len(key):], value)
+	s.index[h] = uint32(off)
+	s.tail = off + n
+	s.used += n
+}
+
+// key returns the key of the entry at offset off without allocating.
+func (s *segment) key(off int) []byte {
+	kl := int(encbinary.LittleEndian.Uint16(s.buf[off+16:]))
+	return s.buf[off+segmentHeaderSize : off+segmentHeaderSize+kl]
+}
+
+// get returns a copy of the value. An expired entry or a different key with
+// the same hash count as a miss.
+func (s *segment) get(h uint64, key string, now int64) []byte {
+	off, ok := s.index[h]
+	if !ok {
+		return nil
+	}
+	o := int(off)
+	if string(s.key(o)) != key {
+		return nil
+	}
+	if exp := int64(encbinary.LittleEndian.Uint64(s.buf[o+8:])); exp != 0 && exp <= now {
+		return nil
+	}
+	kl := int(encbinary.LittleEndian.Uint16(s.buf[o+16:]))
+	vl := int(encbinary.LittleEndian.Uint32(s.buf[o+18:]))
+	start := o + segmentHeaderSize + kl
+	v := make([]byte, vl)
+	copy(v, s.buf[start:start+vl])
+	return v
+}
+
+// hashKey implements the 64-bit FNV-1a hash without allocations.
+func hashKey(key string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= prime64
+	}
+	return h
+}
+
+func (sc *segmentCache) segment(h uint64) *segment {
+	return &sc.segments[h&sc.mask]
+}
+
+func (sc *segmentCache) Set(_ context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	lenExp := len(expirations)
+	for i, key := range keys {
+		if len(key) > maxKeyLength {
+			return errors.TooLarge.Newf("[objcache] SegmentCache.Set: Key %q exceeds the maximum length of %d", key[:32], maxKeyLength)
+		}
+		var expires int64
+		if i < lenExp && expirations[i] > 0 {
+			expires = sc.now().Add(expirations[i]).UnixNano()
+		}
+		h := hashKey(key)
+		s := sc.segment(h)
+		if n := segmentHeaderSize + len(key) + len(values[i]); n > len(s.buf) {
+			return errors.TooLarge.Newf("[objcache] SegmentCache.Set: Entry with key %q and size %d exceeds the segment size of %d", key, n, len(s.buf))
+		}
+		s.mu.Lock()
+		s.set(h, key, values[i], expires)
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// Get returns copies of the values. Expired entries count as a miss.
+func (sc *segmentCache) Get(_ context.Context, keys []string) (values [][]byte, err error) {
+	now := sc.now().UnixNano()
+	values = make([][]byte, len(keys))
+	for i, key := range keys {
+		h := hashKey(key)
+		s := sc.segment(h)
+		s.mu.RLock()
+		values[i] = s.get(h, key, now)
+		s.mu.RUnlock()
+	}
+	return values, nil
+}
+
+func (sc *segmentCache) Delete(_ context.Context, keys []string) error {
+	for _, key := range keys {
+		h := hashKey(key)
+		s := sc.segment(h)
+		s.mu.Lock()
+		if off, ok := s.index[h]; ok && string(s.key(int(off))) == key {
+			delete(s.index, h)
+		}
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// DeleteByPrefix iterates over all entries and removes the keys starting with
+// prefix.
+func (sc *segmentCache) DeleteByPrefix(_ context.Context, prefix string) error {
+	p := []byte(prefix)
+	for i := range sc.segments {
+		s := &sc.segments[i]
+		s.mu.Lock()
+		for h, off := range s.index {
+			if bytes.HasPrefix(s.key(int(off)), p) {
+				delete(s.index, h)
+			}
+		}
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// Truncate resets all segments. The allocated memory gets reused.
+func (sc *segmentCache) Truncate(_ context.Context) error {
+	for i := range sc.segments {
+		s := &sc.segments[i]
+		s.mu.Lock()
+		s.reset()
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+func (sc *segmentCache) Close() error {
+	return sc.Truncate(context.Background())
+}
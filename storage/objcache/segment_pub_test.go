@@ -0,0 +1,193 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache_test
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestNewSegmentCache_Delete(t *testing.T) {
+	newTestServiceDelete(t, objcache.NewSegmentCache(nil))
+}
+
+func TestNewSegmentCache_ComplexParallel(t *testing.T) {
+	newServiceComplexParallelTest(t, objcache.NewSegmentCache(&objcache.SegmentOptions{MaxBytes: 1 << 20, Shards: 4}), nil)
+}
+
+func TestNewSegmentCache_Options(t *testing.T) {
+	s, err := objcache.NewSegmentCache(&objcache.SegmentOptions{Shards: 3})()
+	assert.Nil(t, s)
+	assert.True(t, errors.NotValid.Match(err), "%+v", err)
+
+	s, err = objcache.NewSegmentCache(&objcache.SegmentOptions{MaxBytes: 16, Shards: 1})()
+	assert.Nil(t, s)
+	assert.True(t, errors.NotValid.Match(err), "%+v", err)
+}
+
+func TestNewSegmentCache_Expiration(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1500000000, 0)}
+	s, err := objcache.NewSegmentCache(&objcache.SegmentOptions{Clock: clock.Now})()
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	ctx := context.TODO()
+	assert.NoError(t, s.Set(ctx,
+		[]string{"short", "long", "forever"},
+		[][]byte{[]byte("a"), []byte("bb"), []byte("ccc")},
+		[]time.Duration{time.Second, time.Minute, 0},
+	))
+	vals, err := s.Get(ctx, []string{"short", "long", "forever", "missing"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("a"), []byte("bb"), []byte("ccc"), nil}, vals)
+
+	clock.Add(time.Second)
+	vals, err = s.Get(ctx, []string{"short", "long", "forever"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, []byte("bb"), []byte("ccc")}, vals)
+
+	clock.Add(24 * time.Hour)
+	vals, err = s.Get(ctx, []string{"long", "forever"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, []byte("ccc")}, vals)
+}
+
+func TestNewSegmentCache_Eviction(t *testing.T) {
+	// one segment of 1000 bytes, each entry requires 22+4+70 bytes.
+	s, err := objcache.NewSegmentCache(&objcache.SegmentOptions{MaxBytes: 1000, Shards: 1})()
+	assert.NoError(t, err)
+	ctx := context.TODO()
+	val := make([]byte, 70)
+
+	keys := make([]string, 50)
+	for i := range keys {
+		keys[i] = "k" + strconv.Itoa(100+i)
+		val[0] = byte(i)
+		assert.NoError(t, s.Set(ctx, keys[i:i+1], [][]byte{val}, nil))
+	}
+	vals, err := s.Get(ctx, keys)
+	assert.NoError(t, err)
+	var found int
+	for i, v := range vals {
+		if v != nil {
+			found++
+			assert.Exactly(t, byte(i), v[0], "Index %d", i)
+		}
+	}
+	assert.Exactly(t, 10, found, "only the newest entries fit into the segment")
+	assert.NotNil(t, vals[49])
+	assert.Nil(t, vals[0])
+
+	// Overwriting the same key reclaims the space of the previous entries.
+	for i := 0; i < 100; i++ {
+		val[0] = byte(i)
+		assert.NoError(t, s.Set(ctx, []string{"same"}, [][]byte{val}, nil))
+	}
+	vals, err = s.Get(ctx, []string{"same"})
+	assert.NoError(t, err)
+	assert.Exactly(t, byte(99), vals[0][0])
+
+	err = s.Set(ctx, []string{"large"}, [][]byte{make([]byte, 1000)}, nil)
+	assert.True(t, errors.TooLarge.Match(err), "%+v", err)
+}
+
+func TestNewSegmentCache_DeleteByPrefix_Truncate(t *testing.T) {
+	s, err := objcache.NewSegmentCache(&objcache.SegmentOptions{MaxBytes: 1 << 16, Shards: 8})()
+	assert.NoError(t, err)
+	ctx := context.TODO()
+	keys := []string{"cfg_a", "cfg_b", "cat_a", "cat_b"}
+	assert.NoError(t, s.Set(ctx, keys, [][]byte{[]byte("1"), []byte("2"), []byte("3"), []byte("4")}, nil))
+
+	assert.NoError(t, s.(interface {
+		DeleteByPrefix(ctx context.Context, prefix string) error
+	}).DeleteByPrefix(ctx, "cfg_"))
+	vals, err := s.Get(ctx, keys)
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, nil, []byte("3"), []byte("4")}, vals)
+
+	assert.NoError(t, s.Truncate(ctx))
+	vals, err = s.Get(ctx, keys)
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, nil, nil, nil}, vals)
+
+	assert.NoError(t, s.Set(ctx, keys[:1], [][]byte{[]byte("5")}, nil))
+	vals, err = s.Get(ctx, keys[:1])
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("5")}, vals)
+}
+
+// benchmarkStorage1M fills the storage with one million entries and measures
+// Set and Get of existing keys and the duration of a full GC cycle with all
+// entries in memory. The keys get created on the fly to keep them out of the
+// live heap.
+func benchmarkStorage1M(newStorage objcache.NewStorageFn) func(b *testing.B) {
+	return func(b *testing.B) {
+		const entries = 1000000
+		s, err := newStorage()
+		if err != nil {
+			b.Fatal(err)
+		}
+		ctx := context.Background()
+		val := make([]byte, 64)
+		key := func(i int) []string { return []string{"product_" + strconv.Itoa(i%entries)} }
+		for i := 0; i < entries; i++ {
+			if err := s.Set(ctx, key(i), [][]byte{val}, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.Run("Set", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := s.Set(ctx, key(i), [][]byte{val}, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run("Get", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Get(ctx, key(i)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run("GC", func(b *testing.B) {
+			var ms runtime.MemStats
+			runtime.ReadMemStats(&ms)
+			pauseStart := ms.PauseTotalNs
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+			}
+			runtime.ReadMemStats(&ms)
+			b.ReportMetric(float64(ms.PauseTotalNs-pauseStart)/float64(b.N), "pause-ns/gc")
+		})
+		if err := s.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_Segment_LRU_1M(b *testing.B) {
+	b.Run("Segment", benchmarkStorage1M(objcache.NewSegmentCache(&objcache.SegmentOptions{MaxBytes: 1 << 28})))
+	b.Run("LRU", benchmarkStorage1M(objcache.NewLRU(&objcache.LRUOptions{Capacity: 1 << 21})))
+}
@@ -0,0 +1,71 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"testing"
+
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestSegment_HashCollision(t *testing.T) {
+	s := &segment{buf: make([]byte, 256)}
+	s.reset()
+
+	const h = 4711
+	s.set(h, "key_a", []byte("a"), 0)
+	assert.Exactly(t, []byte("a"), s.get(h, "key_a", 0))
+	assert.Nil(t, s.get(h, "key_b", 0), "a different key with the same hash must not return the value")
+
+	s.set(h, "key_b", []byte("b"), 0)
+	assert.Exactly(t, []byte("b"), s.get(h, "key_b", 0))
+	assert.Nil(t, s.get(h, "key_a", 0), "the colliding key replaces the previous entry")
+}
+
+func TestSegment_Wrap(t *testing.T) {
+	s := &segment{buf: make([]byte, 100)}
+	s.reset()
+
+	// each entry requires 30 bytes, the fourth entry does not fit at the end.
+	for i, k := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		s.set(hashKey(k), k, []byte("123456"), 0)
+		assert.True(t, s.used <= len(s.buf), "Index %d", i)
+	}
+	assert.Exactly(t, 60, s.tail)
+	assert.Exactly(t, 60, s.head)
+	assert.Exactly(t, 90, s.wrap)
+	assert.Exactly(t, 100, s.used)
+	assert.Len(t, s.index, 3)
+	assert.Nil(t, s.get(hashKey("k2"), "k2", 0))
+	for _, k := range []string{"k3", "k4", "k5"} {
+		assert.Exactly(t, []byte("123456"), s.get(hashKey(k), k, 0), "Key %q", k)
+	}
+
+	s.set(hashKey("k6"), "k6", []byte("123456"), 0)
+	assert.Exactly(t, 90, s.head)
+	assert.Exactly(t, 90, s.tail)
+	assert.Nil(t, s.get(hashKey("k3"), "k3", 0))
+
+	// the unused bytes at the end get skipped and k4 gets evicted.
+	s.set(hashKey("k7"), "k7", []byte("123456"), 0)
+	assert.Exactly(t, 30, s.head)
+	assert.Exactly(t, 30, s.tail)
+	assert.Exactly(t, 90, s.wrap)
+	assert.Exactly(t, 100, s.used)
+	assert.Nil(t, s.get(hashKey("k4"), "k4", 0))
+	for _, k := range []string{"k5", "k6", "k7"} {
+		assert.Exactly(t, []byte("123456"), s.get(hashKey(k), k, 0), "Key %q", k)
+	}
+}
@@ -19,11 +19,14 @@ import (
 	"context"
 	"encoding"
 	"io"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/util/bufferpool"
+	"golang.org/x/sync/singleflight"
 )
 
 // Storager defines a custom backend cache type to be used as underlying storage
@@ -190,6 +193,7 @@ type Service struct {
 	level2            Storager
 	defaultExpiration time.Duration // in seconds
 	rawItemsPool      sync.Pool
+	flight            singleflight.Group
 }
 
 func (tr *Service) poolGetRawItems() *rawItems {
@@ -414,6 +418,60 @@ func (tr *Service) Delete(ctx context.Context, key ...string) error {
 	return nil
 }
 
+// DeleteByPrefix removes all keys starting with subPrefix from all cache
+// levels, for example "product_42_". All levels must implement PrefixDeleter.
+func (tr *Service) DeleteByPrefix(ctx context.Context, subPrefix string) error {
+	if tr.level1 != nil {
+		if err := deleteByPrefix(ctx, tr.level1, subPrefix); err != nil {
+			return errors.Wrapf(err, "[objcache] Level1 with prefix %q", subPrefix)
+		}
+	}
+	if err := deleteByPrefix(ctx, tr.level2, subPrefix); err != nil {
+		return errors.Wrapf(err, "[objcache] Level2 with prefix %q", subPrefix)
+	}
+	return nil
+}
+
+// healthProbeCounter makes the probe keys of concurrent health checks unique.
+var healthProbeCounter uint64
+
+// HealthCheck writes a probe key through all cache levels, reads it back and
+// deletes it. A storage which does not return the written value, like the
+// black hole client, fails the check. Suitable for readiness probes, see
+// package storage/health.
+func (tr *Service) HealthCheck(ctx context.Context) error {
+	key := "objcache_health_" + strconv.FormatInt(time.Now().UnixNano(), 36) + "_" +
+		strconv.FormatUint(atomic.AddUint64(&healthProbeCounter, 1), 36)
+	if tr.level1 != nil {
+		if err := healthCheckStorage(ctx, tr.level1, key); err != nil {
+			return errors.Wrapf(err, "[objcache] HealthCheck Level1")
+		}
+	}
+	if err := healthCheckStorage(ctx, tr.level2, key); err != nil {
+		return errors.Wrapf(err, "[objcache] HealthCheck Level2")
+	}
+	return nil
+}
+
+func healthCheckStorage(ctx context.Context, s Storager, key string) error {
+	keys := []string{key}
+	value := []byte(key)
+	if err := s.Set(ctx, keys, [][]byte{value}, []time.Duration{time.Minute}); err != nil {
+		return errors.WithStack(err)
+	}
+	values, err := s.Get(ctx, keys)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := s.Delete(ctx, keys); err != nil {
+		return errors.WithStack(err)
+	}
+	if len(values) != 1 || !bytes.Equal(values[0], value) {
+		return errors.Mismatch.Newf("[objcache] Probe key %q returned an unexpected value", key)
+	}
+	return nil
+}
+
 // Close closes the underlying storage engines.
 func (tr *Service) Close() error {
 	if tr.level1 != nil {
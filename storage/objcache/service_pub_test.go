@@ -27,13 +27,17 @@ import (
 	"time"
 
 	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
 	"github.com/corestoreio/pkg/storage/objcache"
 	"github.com/corestoreio/pkg/util/assert"
 	"github.com/corestoreio/pkg/util/strs"
 	"golang.org/x/sync/errgroup"
 )
 
-var _ io.Closer = (*objcache.Service)(nil)
+var (
+	_ io.Closer        = (*objcache.Service)(nil)
+	_ dml.ResultCacher = (*objcache.Service)(nil)
+)
 
 func TestNewProcessor_EncoderError(t *testing.T) {
 	t.Parallel()
@@ -402,3 +406,23 @@ func testExpiration(t *testing.T, cb func(), level2 objcache.NewStorageFn, so *o
 	assert.NoError(t, err, "%+v", err)
 	assert.Empty(t, newVal)
 }
+
+func TestService_HealthCheck(t *testing.T) {
+	t.Run("in memory", func(t *testing.T) {
+		p, err := objcache.NewService(objcache.NewCacheSimpleInmemory, objcache.NewCacheSimpleInmemory, nil)
+		assert.NoError(t, err)
+		defer func() { assert.NoError(t, p.Close()) }()
+		assert.NoError(t, p.HealthCheck(context.TODO()))
+	})
+	t.Run("level2 error", func(t *testing.T) {
+		p, err := objcache.NewService(nil, objcache.NewBlackHoleClient(errors.ConnectionFailed.Newf("ups")), nil)
+		assert.NoError(t, err)
+		assert.ErrorIsKind(t, errors.ConnectionFailed, p.HealthCheck(context.TODO()))
+	})
+	t.Run("value lost", func(t *testing.T) {
+		p, err := objcache.NewService(objcache.NewCacheSimpleInmemory, objcache.NewBlackHoleClient(nil), nil)
+		assert.NoError(t, err)
+		defer func() { assert.NoError(t, p.Close()) }()
+		assert.ErrorIsKind(t, errors.Mismatch, p.HealthCheck(context.TODO()))
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"bytes"
+	"context"
+	// alias due to the binary type in options.go
+	encbinary "encoding/binary"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+)
+
+// staleEnvelopePrefix marks a value written by GetStale. It gets followed by
+// the soft deadline as Unix nano seconds in big endian and the encoded value.
+const (
+	staleEnvelopePrefix = "\x00\xfeobjcache:stale:"
+	staleEnvelopeLen    = len(staleEnvelopePrefix) + 8
+)
+
+// StaleResult describes how GetStale has served a value.
+type StaleResult uint8
+
+// StaleResult constants passed to the StaleObserver.
+const (
+	// StaleResultFresh value found within the soft TTL.
+	StaleResultFresh StaleResult = iota + 1
+	// StaleResultStale value found between the soft and hard TTL and a
+	// background refresh has been triggered.
+	StaleResultStale
+	// StaleResultLoad value not found, the caller waited for the loader.
+	StaleResultLoad
+)
+
+func (r StaleResult) String() string {
+	switch r {
+	case StaleResultFresh:
+		return "fresh"
+	case StaleResultStale:
+		return "stale"
+	case StaleResultLoad:
+		return "load"
+	}
+	return "unknown"
+}
+
+// StaleObserver receives the result of each GetStale call, for example to
+// count fresh hits, stale hits and blocking loads.
+type StaleObserver interface {
+	ObserveStale(key string, r StaleResult)
+}
+
+// StaleOptions configures one GetStale call.
+type StaleOptions struct {
+	// SoftTTL defines how long a value counts as fresh. Required.
+	SoftTTL time.Duration
+	// HardTTL defines the expiration in the cache levels. Between SoftTTL and
+	// HardTTL the stale value gets returned while a refresh runs in the
+	// background. Must be greater or equal SoftTTL. Zero applies
+	// ServiceOptions.DefaultExpires.
+	HardTTL time.Duration
+	// RefreshTimeout limits the duration of the background refresh. Default
+	// 30s.
+	RefreshTimeout time.Duration
+}
+
+func wrapStaleEnvelope(softDeadline time.Time, encoded []byte) []byte {
+	buf := make([]byte, staleEnvelopeLen, staleEnvelopeLen+len(encoded))
+	copy(buf, staleEnvelopePrefix)
+	encbinary.BigEndian.PutUint64(buf[len(staleEnvelopePrefix):], uint64(softDeadline.UnixNano()))
+	return append(buf, encoded...)
+}
+
+// parseStaleEnvelope returns the soft deadline and the encoded value. ok is
+// false if raw has not been written by GetStale.
+func parseStaleEnvelope(raw []byte) (softDeadline int64, encoded []byte, ok bool) {
+	if len(raw) < staleEnvelopeLen || !bytes.HasPrefix(raw, []byte(staleEnvelopePrefix)) {
+		return 0, raw, false
+	}
+	return int64(encbinary.BigEndian.Uint64(raw[len(staleEnvelopePrefix):])), raw[staleEnvelopeLen:], true
+}
+
+// GetStale implements stale-while-revalidate. Within the SoftTTL the cached
+// value gets decoded into dst. Between the SoftTTL and the HardTTL the stale
+// value gets decoded and exactly one background refresh calls the loader with
+// a detached context limited by RefreshTimeout. A failed refresh gets logged
+// with ServiceOptions.Log and the stale value stays in the cache. After the
+// HardTTL the caller waits for the loader like in GetSet. A key must not be
+// used with GetSet and GetStale at the same time.
+func (tr *Service) GetStale(ctx context.Context, key string, dst interface{}, opts StaleOptions, loader func(ctx context.Context) (interface{}, error)) error {
+	if opts.SoftTTL <= 0 || (opts.HardTTL > 0 && opts.HardTTL < opts.SoftTTL) {
+		return errors.NotValid.Newf("[objcache] GetStale with key %q: SoftTTL %s must be greater zero and less or equal HardTTL %s", key, opts.SoftTTL, opts.HardTTL)
+	}
+	if opts.RefreshTimeout <= 0 {
+		opts.RefreshTimeout = 30 * time.Second
+	}
+
+	raw, err := tr.getRaw(ctx, key)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	softDeadline, _, isEnvelope := parseStaleEnvelope(raw)
+	result := StaleResultFresh
+	switch {
+	case raw == nil:
+		result = StaleResultLoad
+	case isEnvelope && softDeadline <= now().UnixNano():
+		result = StaleResultStale
+	}
+	if so := tr.so.StaleObserver; so != nil {
+		so.ObserveStale(key, result)
+	}
+
+	switch result {
+	case StaleResultLoad:
+		raw, err = tr.flightDo(ctx, key, func() ([]byte, error) {
+			dctx := detachedContext{parent: ctx}
+			// A previous flight might have finished after our lookup.
+			if raw, err := tr.getRaw(dctx, key); err != nil || raw != nil {
+				return raw, err
+			}
+			return tr.loadStale(dctx, key, opts, loader)
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	case StaleResultStale:
+		tr.flightStart(key, func() ([]byte, error) {
+			raw, err := callRecover(key, func() ([]byte, error) {
+				return tr.refreshStale(ctx, key, opts, loader)
+			})
+			if err != nil && tr.so.Log != nil && tr.so.Log.IsInfo() {
+				tr.so.Log.Info("objcache.Service.GetStale.refresh", log.String("key", key), log.Err(err))
+			}
+			return raw, err
+		})
+	}
+
+	if bytes.HasPrefix(raw, []byte(negativeCachePrefix)) {
+		return errors.NotFound.Newf("[objcache] Cached loader error for key %q: %s", key, raw[len(negativeCachePrefix):])
+	}
+	_, encoded, _ := parseStaleEnvelope(raw)
+	return errors.WithStack(decodeOne(tr.so.Codec, encoded, key, dst))
+}
+
+// refreshStale runs in the background and reloads the value unless a previous
+// refresh has already finished.
+func (tr *Service) refreshStale(ctx context.Context, key string, opts StaleOptions, loader func(ctx context.Context) (interface{}, error)) ([]byte, error) {
+	dctx, cancel := context.WithTimeout(detachedContext{parent: ctx}, opts.RefreshTimeout)
+	defer cancel()
+	raw, err := tr.getRaw(dctx, key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if sd, _, ok := parseStaleEnvelope(raw); ok && sd > now().UnixNano() {
+		return raw, nil
+	}
+	return tr.loadStale(dctx, key, opts, loader)
+}
+
+func (tr *Service) loadStale(ctx context.Context, key string, opts StaleOptions, loader func(ctx context.Context) (interface{}, error)) ([]byte, error) {
+	return tr.load(ctx, key, opts.HardTTL, loader, func(encoded []byte) []byte {
+		return wrapStaleEnvelope(now().Add(opts.SoftTTL), encoded)
+	})
+}
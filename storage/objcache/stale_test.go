@@ -0,0 +1,134 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var _ objcache.StaleObserver = (*objcache.MetricsCounter)(nil)
+
+// chanLogger sends the message and the number of fields of each Info call to
+// msgs.
+type chanLogger struct {
+	log.BlackHole
+	msgs chan string
+}
+
+func (cl *chanLogger) Info(msg string, fields ...log.Field) {
+	cl.msgs <- msg + " " + strconv.Itoa(len(fields))
+}
+
+func TestService_GetStale(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fresh, stale and blocking load", func(t *testing.T) {
+		var mc objcache.MetricsCounter
+		p, _ := newCountingService(t, &objcache.ServiceOptions{StaleObserver: &mc})
+		opts := objcache.StaleOptions{SoftTTL: 50 * time.Millisecond, HardTTL: time.Minute}
+
+		var loads int32
+		release := make(chan struct{}, 1)
+		loaderCtxErr := make(chan error, 1)
+		loader := func(ctx context.Context) (interface{}, error) {
+			n := atomic.AddInt32(&loads, 1)
+			if n > 1 {
+				<-release
+				loaderCtxErr <- ctx.Err()
+			}
+			return &myString{data: "v" + strconv.Itoa(int(n))}, nil
+		}
+
+		var dst myString
+		assert.NoError(t, p.GetStale(context.TODO(), "dashboard", &dst, opts, loader))
+		assert.Exactly(t, "v1", dst.data)
+		assert.NoError(t, p.GetStale(context.TODO(), "dashboard", &dst, opts, loader))
+		assert.Exactly(t, "v1", dst.data)
+
+		time.Sleep(60 * time.Millisecond)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel() // must not cancel the background refresh
+				var dst myString
+				assert.NoError(t, p.GetStale(ctx, "dashboard", &dst, opts, loader))
+				assert.Exactly(t, "v1", dst.data)
+			}()
+		}
+		wg.Wait()
+		release <- struct{}{}
+		assert.NoError(t, <-loaderCtxErr)
+
+		for i := 0; i < 100 && dst.data != "v2"; i++ {
+			time.Sleep(5 * time.Millisecond)
+			assert.NoError(t, p.GetStale(context.TODO(), "dashboard", &dst, opts, loader))
+		}
+		assert.Exactly(t, "v2", dst.data)
+		assert.Exactly(t, int32(2), atomic.LoadInt32(&loads))
+
+		ms := mc.Snapshot()
+		assert.Exactly(t, uint64(1), ms.BlockingLoads)
+		assert.True(t, ms.StaleHits >= 20, "stale hits %d", ms.StaleHits)
+		assert.True(t, ms.FreshHits >= 2, "fresh hits %d", ms.FreshHits)
+	})
+
+	t.Run("failed refresh gets logged", func(t *testing.T) {
+		lg := &chanLogger{BlackHole: log.BlackHole{EnableInfo: true}, msgs: make(chan string, 1)}
+		p, _ := newCountingService(t, &objcache.ServiceOptions{Log: lg})
+		opts := objcache.StaleOptions{SoftTTL: 10 * time.Millisecond, HardTTL: time.Minute}
+
+		var loads int32
+		loader := func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&loads, 1) > 1 {
+				return nil, errors.ConnectionFailed.Newf("DB down")
+			}
+			return &myString{data: "v1"}, nil
+		}
+		var dst myString
+		assert.NoError(t, p.GetStale(context.TODO(), "k", &dst, opts, loader))
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, p.GetStale(context.TODO(), "k", &dst, opts, loader))
+		assert.Exactly(t, "v1", dst.data)
+
+		select {
+		case msg := <-lg.msgs:
+			assert.Exactly(t, "objcache.Service.GetStale.refresh 2", msg)
+		case <-time.After(time.Second):
+			t.Fatal("refresh error has not been logged")
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		p, _ := newCountingService(t, nil)
+		var dst myString
+		loader := func(ctx context.Context) (interface{}, error) { return &myString{}, nil }
+		assert.ErrorIsKind(t, errors.NotValid, p.GetStale(context.TODO(), "k", &dst, objcache.StaleOptions{}, loader))
+		assert.ErrorIsKind(t, errors.NotValid, p.GetStale(context.TODO(), "k", &dst, objcache.StaleOptions{SoftTTL: time.Minute, HardTTL: time.Second}, loader))
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache
+
+import (
+	"context"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// Invalidator distributes invalidation messages between several instances of a
+// two level cache, for example via Redis Pub/Sub. Keys with length zero mean
+// that all keys should be removed.
+type Invalidator interface {
+	// Publish sends the keys to all subscribers, including the own instance.
+	Publish(ctx context.Context, keys []string) error
+	// Subscribe registers fn to receive the keys of all published messages.
+	// The returned function removes the subscription.
+	Subscribe(fn func(keys []string)) (unsubscribe func(), err error)
+}
+
+// TwoLevelOptions configures NewTwoLevel.
+type TwoLevelOptions struct {
+	// L1TTL defines the maximum expiration of an entry in L1. It applies when
+	// L1 gets backfilled after an L2 hit and caps the expiration in Set. Zero
+	// means no limit, backfilled entries never expire.
+	L1TTL time.Duration
+	// Invalidator optionally publishes Delete and Truncate calls to other
+	// instances, which then clear their L1.
+	Invalidator Invalidator
+}
+
+type twoLevel struct {
+	l1          Storager
+	l2          Storager
+	opt         TwoLevelOptions
+	unsubscribe func()
+}
+
+// NewTwoLevel creates a Storager which uses l1, usually an in-memory cache like
+// the LRU, in front of l2, usually a remote cache like Redis. Get reads from L1
+// and requests only the missing keys from L2. Set, Delete, Truncate and Close
+// get applied to both levels.
+func NewTwoLevel(l1, l2 NewStorageFn, opts TwoLevelOptions) NewStorageFn {
+	return func() (Storager, error) {
+		s1, err := l1()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		s2, err := l2()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		tl := &twoLevel{
+			l1:  s1,
+			l2:  s2,
+			opt: opts,
+		}
+		if opts.Invalidator != nil {
+			if tl.unsubscribe, err = opts.Invalidator.Subscribe(tl.invalidate); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		return tl, nil
+	}
+}
+
+// invalidate removes the keys from L1. Errors can't be reported and L1 is
+// usually an in-memory cache, hence they get ignored.
+func (tl *twoLevel) invalidate(keys []string) {
+	if len(keys) == 0 {
+		_ = tl.l1.Truncate(context.Background())
+		return
+	}
+	_ = tl.l1.Delete(context.Background(), keys)
+}
+
+func (tl *twoLevel) l1Expiration(e time.Duration) time.Duration {
+	if tl.opt.L1TTL > 0 && (e == 0 || e > tl.opt.L1TTL) {
+		return tl.opt.L1TTL
+	}
+	return e
+}
+
+// Set writes through to L1 and L2.
+func (tl *twoLevel) Set(ctx context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	l1Exp := expirations
+	if tl.opt.L1TTL > 0 {
+		l1Exp = make([]time.Duration, len(keys))
+		for i := range keys {
+			var e time.Duration
+			if i < len(expirations) {
+				e = expirations[i]
+			}
+			l1Exp[i] = tl.l1Expiration(e)
+		}
+	}
+	if err := tl.l1.Set(ctx, keys, values, l1Exp); err != nil {
+		return errors.Wrapf(err, "[objcache] TwoLevel.Set L1 with keys %v", keys)
+	}
+	if err := tl.l2.Set(ctx, keys, values, expirations); err != nil {
+		return errors.Wrapf(err, "[objcache] TwoLevel.Set L2 with keys %v", keys)
+	}
+	return nil
+}
+
+// Get returns the values from L1 and requests the missing keys from L2. L2 hits
+// get written back to L1.
+func (tl *twoLevel) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	values, err := tl.l1.Get(ctx, keys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[objcache] TwoLevel.Get L1 with keys %v", keys)
+	}
+	if len(values) != len(keys) {
+		values = make([][]byte, len(keys))
+	}
+
+	var missIdx []int
+	var missKeys []string
+	for i, v := range values {
+		if v == nil {
+			missIdx = append(missIdx, i)
+			missKeys = append(missKeys, keys[i])
+		}
+	}
+	if len(missKeys) == 0 {
+		return values, nil
+	}
+
+	l2Values, err := tl.l2.Get(ctx, missKeys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[objcache] TwoLevel.Get L2 with keys %v", missKeys)
+	}
+
+	var bfKeys []string
+	var bfValues [][]byte
+	for j, v := range l2Values {
+		if j >= len(missIdx) || v == nil {
+			continue
+		}
+		values[missIdx[j]] = v
+		bfKeys = append(bfKeys, missKeys[j])
+		bfValues = append(bfValues, v)
+	}
+	if len(bfKeys) > 0 {
+		bfExp := make([]time.Duration, len(bfKeys))
+		for i := range bfExp {
+			bfExp[i] = tl.opt.L1TTL
+		}
+		if err := tl.l1.Set(ctx, bfKeys, bfValues, bfExp); err != nil {
+			return nil, errors.Wrapf(err, "[objcache] TwoLevel.Get backfill L1 with keys %v", bfKeys)
+		}
+	}
+	return values, nil
+}
+
+// Delete removes the keys from both levels and publishes an invalidation
+// message.
+func (tl *twoLevel) Delete(ctx context.Context, keys []string) error {
+	if err := tl.l1.Delete(ctx, keys); err != nil {
+		return errors.Wrapf(err, "[objcache] TwoLevel.Delete L1 with keys %v", keys)
+	}
+	if err := tl.l2.Delete(ctx, keys); err != nil {
+		return errors.Wrapf(err, "[objcache] TwoLevel.Delete L2 with keys %v", keys)
+	}
+	if tl.opt.Invalidator != nil && len(keys) > 0 {
+		if err := tl.opt.Invalidator.Publish(ctx, keys); err != nil {
+			return errors.Wrapf(err, "[objcache] TwoLevel.Delete publish with keys %v", keys)
+		}
+	}
+	return nil
+}
+
+// DeleteByPrefix removes the keys starting with prefix from both levels. Other
+// instances truncate their L1 because the Invalidator transports only keys.
+func (tl *twoLevel) DeleteByPrefix(ctx context.Context, prefix string) error {
+	if err := deleteByPrefix(ctx, tl.l1, prefix); err != nil {
+		return errors.Wrapf(err, "[objcache] TwoLevel.DeleteByPrefix L1 with prefix %q", prefix)
+	}
+	if err := deleteByPrefix(ctx, tl.l2, prefix); err != nil {
+		return errors.Wrapf(err, "[objcache] TwoLevel.DeleteByPrefix L2 with prefix %q", prefix)
+	}
+	if tl.opt.Invalidator != nil {
+		if err := tl.opt.Invalidator.Publish(ctx, nil); err != nil {
+			return errors.Wrapf(err, "[objcache] TwoLevel.DeleteByPrefix publish with prefix %q", prefix)
+		}
+	}
+	return nil
+}
+
+// Truncate clears both levels and publishes an invalidation message.
+func (tl *twoLevel) Truncate(ctx context.Context) error {
+	if err := tl.l1.Truncate(ctx); err != nil {
+		return errors.Wrap(err, "[objcache] TwoLevel.Truncate L1")
+	}
+	if err := tl.l2.Truncate(ctx); err != nil {
+		return errors.Wrap(err, "[objcache] TwoLevel.Truncate L2")
+	}
+	if tl.opt.Invalidator != nil {
+		if err := tl.opt.Invalidator.Publish(ctx, nil); err != nil {
+			return errors.Wrap(err, "[objcache] TwoLevel.Truncate publish")
+		}
+	}
+	return nil
+}
+
+// Close unsubscribes from the Invalidator and closes both levels.
+func (tl *twoLevel) Close() error {
+	if tl.unsubscribe != nil {
+		tl.unsubscribe()
+	}
+	err1 := tl.l1.Close()
+	err2 := tl.l2.Close()
+	if err1 != nil {
+		return errors.Wrap(err1, "[objcache] TwoLevel.Close L1")
+	}
+	return errors.Wrap(err2, "[objcache] TwoLevel.Close L2")
+}
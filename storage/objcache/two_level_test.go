@@ -0,0 +1,167 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objcache_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// recordingStorage records the keys requested via Get.
+type recordingStorage struct {
+	objcache.Storager
+	mu      sync.Mutex
+	getKeys [][]string
+}
+
+func (rs *recordingStorage) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	rs.mu.Lock()
+	rs.getKeys = append(rs.getKeys, append([]string(nil), keys...))
+	rs.mu.Unlock()
+	return rs.Storager.Get(ctx, keys)
+}
+
+func staticStorage(s objcache.Storager) objcache.NewStorageFn {
+	return func() (objcache.Storager, error) { return s, nil }
+}
+
+type localInvalidator struct {
+	mu   sync.Mutex
+	subs map[int]func([]string)
+	id   int
+}
+
+func (li *localInvalidator) Publish(_ context.Context, keys []string) error {
+	li.mu.Lock()
+	defer li.mu.Unlock()
+	for _, fn := range li.subs {
+		fn(keys)
+	}
+	return nil
+}
+
+func (li *localInvalidator) Subscribe(fn func(keys []string)) (func(), error) {
+	li.mu.Lock()
+	defer li.mu.Unlock()
+	if li.subs == nil {
+		li.subs = make(map[int]func([]string))
+	}
+	li.id++
+	id := li.id
+	li.subs[id] = fn
+	return func() {
+		li.mu.Lock()
+		delete(li.subs, id)
+		li.mu.Unlock()
+	}, nil
+}
+
+func TestNewTwoLevel_Get_PartialMiss(t *testing.T) {
+	ctx := context.TODO()
+	l1, err := objcache.NewCacheSimpleInmemory()
+	assert.NoError(t, err)
+	l2Map, err := objcache.NewCacheSimpleInmemory()
+	assert.NoError(t, err)
+	l2 := &recordingStorage{Storager: l2Map}
+
+	tl, err := objcache.NewTwoLevel(staticStorage(l1), staticStorage(l2), objcache.TwoLevelOptions{})()
+	assert.NoError(t, err)
+
+	assert.NoError(t, l1.Set(ctx, []string{"b"}, [][]byte{[]byte("l1-b")}, nil))
+	assert.NoError(t, l2Map.Set(ctx, []string{"a", "c"}, [][]byte{[]byte("l2-a"), []byte("l2-c")}, nil))
+
+	vals, err := tl.Get(ctx, []string{"a", "b", "c", "d"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("l2-a"), []byte("l1-b"), []byte("l2-c"), nil}, vals)
+	assert.Exactly(t, [][]string{{"a", "c", "d"}}, l2.getKeys)
+
+	// backfilled
+	vals, err = l1.Get(ctx, []string{"a", "c"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("l2-a"), []byte("l2-c")}, vals)
+
+	vals, err = tl.Get(ctx, []string{"c", "a", "b"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("l2-c"), []byte("l2-a"), []byte("l1-b")}, vals)
+	assert.Len(t, l2.getKeys, 1, "L2 must not be requested when all keys are in L1")
+}
+
+func TestNewTwoLevel_WriteThrough(t *testing.T) {
+	ctx := context.TODO()
+	l1, err := objcache.NewCacheSimpleInmemory()
+	assert.NoError(t, err)
+	l2, err := objcache.NewCacheSimpleInmemory()
+	assert.NoError(t, err)
+	tl, err := objcache.NewTwoLevel(staticStorage(l1), staticStorage(l2), objcache.TwoLevelOptions{})()
+	assert.NoError(t, err)
+
+	assert.NoError(t, tl.Set(ctx, []string{"a", "b"}, [][]byte{[]byte("1"), []byte("2")}, nil))
+	for _, s := range []objcache.Storager{l1, l2} {
+		vals, err := s.Get(ctx, []string{"a", "b"})
+		assert.NoError(t, err)
+		assert.Exactly(t, [][]byte{[]byte("1"), []byte("2")}, vals)
+	}
+
+	assert.NoError(t, tl.Delete(ctx, []string{"a"}))
+	for _, s := range []objcache.Storager{l1, l2} {
+		vals, err := s.Get(ctx, []string{"a", "b"})
+		assert.NoError(t, err)
+		assert.Exactly(t, [][]byte{nil, []byte("2")}, vals)
+	}
+
+	assert.NoError(t, tl.Truncate(ctx))
+	for _, s := range []objcache.Storager{l1, l2} {
+		vals, err := s.Get(ctx, []string{"b"})
+		assert.NoError(t, err)
+		assert.Exactly(t, [][]byte{nil}, vals)
+	}
+	assert.NoError(t, tl.Close())
+}
+
+func TestNewTwoLevel_Invalidator(t *testing.T) {
+	ctx := context.TODO()
+	inv := &localInvalidator{}
+	l2, err := objcache.NewCacheSimpleInmemory()
+	assert.NoError(t, err)
+
+	instance1, err := objcache.NewTwoLevel(objcache.NewLRU(nil), staticStorage(l2), objcache.TwoLevelOptions{Invalidator: inv})()
+	assert.NoError(t, err)
+	instance2L1, err := objcache.NewLRU(nil)()
+	assert.NoError(t, err)
+	instance2, err := objcache.NewTwoLevel(staticStorage(instance2L1), staticStorage(l2), objcache.TwoLevelOptions{Invalidator: inv})()
+	assert.NoError(t, err)
+
+	assert.NoError(t, instance1.Set(ctx, []string{"a", "b"}, [][]byte{[]byte("1"), []byte("2")}, nil))
+	vals, err := instance2.Get(ctx, []string{"a", "b"}) // backfills instance2 L1
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{[]byte("1"), []byte("2")}, vals)
+
+	assert.NoError(t, instance1.Delete(ctx, []string{"a"}))
+	vals, err = instance2L1.Get(ctx, []string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil, []byte("2")}, vals)
+
+	assert.NoError(t, instance1.Truncate(ctx))
+	vals, err = instance2L1.Get(ctx, []string{"b"})
+	assert.NoError(t, err)
+	assert.Exactly(t, [][]byte{nil}, vals)
+
+	assert.NoError(t, instance1.Close())
+	assert.Len(t, inv.subs, 1)
+}
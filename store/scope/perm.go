@@ -25,10 +25,12 @@ import (
 // Uint16 should be big enough.
 type Perm uint16
 
-// PermStore convenient helper contains all scope permission levels. The
-// official core_config_data table and its classes to not support the GroupID
-// scope, so that is the reason why PermStore does not have a GroupID.
-const PermStore Perm = 1<<Default | 1<<Website | 1<<Store
+// PermStore convenient helper contains all scope permission levels.
+const PermStore Perm = 1<<Default | 1<<Website | 1<<Group | 1<<Store
+
+// PermGroup convenient helper contains default, website and group scope
+// permission levels.
+const PermGroup Perm = 1<<Default | 1<<Website | 1<<Group
 
 // PermWebsite convenient helper contains default and website scope permission levels.
 const PermWebsite Perm = 1<<Default | 1<<Website
@@ -40,20 +42,26 @@ const PermDefault Perm = 1 << Default
 // config.Scoped implementation.
 const PermStoreReverse Perm = 1 << Store
 
+// PermGroupReverse convenient helper to enforce hierarchy levels. Only used in
+// config.Scoped implementation.
+const PermGroupReverse Perm = 1<<Store | 1<<Group
+
 // PermWebsiteReverse convenient helper to enforce hierarchy levels. Only used in
 // config.Scoped implementation.
-const PermWebsiteReverse Perm = 1<<Store | 1<<Website
+const PermWebsiteReverse Perm = 1<<Store | 1<<Group | 1<<Website
 
 // MakePerm creates a Perm type based on the input argument which can be either:
 // "default","d" or "" for PermDefault, "websites", "website" or "w" for
-// PermWebsite OR "stores", "store" or "s" for PermStore. Any other argument
-// triggers a NotSupported error.
+// PermWebsite, "group" for PermGroup OR "stores", "store" or "s" for
+// PermStore. Any other argument triggers a NotSupported error.
 func MakePerm(name string) (p Perm, err error) {
 	switch name {
 	case strDefault, "d", "":
 		p = PermDefault
 	case strWebsites, "w":
 		p = PermWebsite
+	case strGroup:
+		p = PermGroup
 	case strStores, "s":
 		p = PermStore
 	default:
@@ -62,9 +70,9 @@ func MakePerm(name string) (p Perm, err error) {
 	return
 }
 
-// All applies DefaultID, WebsiteID and StoreID scopes
+// All applies DefaultID, WebsiteID, GroupID and StoreID scopes
 func (bits Perm) All() Perm {
-	return bits.Set(Default, Website, Store)
+	return bits.Set(Default, Website, Group, Store)
 }
 
 // Set takes a variadic amount of Group to set them to Bits
@@ -75,13 +83,15 @@ func (bits Perm) Set(scopes ...Type) Perm {
 	return bits
 }
 
-// Top returns the highest stored scope within a Perm. A Perm can consists of 3
-// scopes: 1. Default -> 2. Website -> 3. Store Highest scope for a Perm with
-// all scopes is: Store.
+// Top returns the highest stored scope within a Perm. A Perm can consists of 4
+// scopes: 1. Default -> 2. Website -> 3. Group -> 4. Store Highest scope for a
+// Perm with all scopes is: Store.
 func (bits Perm) Top() Type {
 	switch {
 	case bits.Has(Store):
 		return Store
+	case bits.Has(Group):
+		return Group
 	case bits.Has(Website):
 		return Website
 	}
@@ -113,6 +123,8 @@ func (bits Perm) String() string {
 	switch {
 	case bits.Has(Store):
 		return strStores
+	case bits.Has(Group):
+		return strGroup
 	case bits.Has(Website):
 		return strWebsites
 	}
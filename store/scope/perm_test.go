@@ -33,14 +33,18 @@ func TestPermAll(t *testing.T) {
 	pa := p.All()
 	assert.True(t, pa.Has(scope.Default))
 	assert.True(t, pa.Has(scope.Website))
+	assert.True(t, pa.Has(scope.Group))
 	assert.True(t, pa.Has(scope.Store))
 }
 
 func TestPermTop(t *testing.T) {
 	assert.Exactly(t, scope.Website, scope.PermWebsite.Top())
 	assert.Exactly(t, scope.Store, scope.PermStore.Top())
+	assert.Exactly(t, scope.Group, scope.PermGroup.Top())
+	assert.Exactly(t, scope.Store, scope.PermGroupReverse.Top())
 	assert.Exactly(t, scope.Default, scope.PermDefault.Top())
-	assert.Exactly(t, scope.Website, scope.Perm(44).Top())
+	assert.Exactly(t, scope.Group, scope.Perm(44).Top())
+	assert.Exactly(t, scope.Website, scope.Perm(36).Top())
 	assert.Exactly(t, scope.Store, scope.PermWebsiteReverse.Top())
 	assert.Exactly(t, scope.Store, scope.PermStoreReverse.Top())
 }
@@ -91,6 +95,8 @@ func TestMakePerm(t *testing.T) {
 		{"stores", scope.PermStore, errors.NoKind},
 		{"s", scope.PermStore, errors.NoKind},
 
+		{"group", scope.PermGroup, errors.NoKind},
+
 		{"g", 0, errors.NotSupported},
 	}
 	for i, test := range tests {
@@ -125,6 +131,7 @@ func TestPerm(t *testing.T) {
 		{[]scope.Type{scope1, scope2}, scope2, scope3, []string{"Default", "Website"}, "websites"},
 		{[]scope.Type{scope3, scope4}, scope3, scope2, []string{"Group", "Store"}, "stores"},
 		{[]scope.Type{scope4, scope5}, scope4, scope2, []string{"Store"}, "stores"},
+		{[]scope.Type{scope2, scope3}, scope3, scope4, []string{"Website", "Group"}, "group"},
 	}
 
 	for _, test := range tests {
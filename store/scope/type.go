@@ -64,7 +64,7 @@ func (s Type) String() string {
 	return _TypeName[_TypeIndex[s]:_TypeIndex[s+1]]
 }
 
-// StrType converts the underlying Type to one of the three available type
+// StrType converts the underlying Type to one of the four available type
 // strings from the database table `core_config_data`.
 func (s Type) StrType() string {
 	return FromType(s).String()
@@ -99,6 +99,8 @@ func (s Type) StrBytes() []byte {
 	switch s {
 	case Website:
 		return bWebsites
+	case Group:
+		return bGroup
 	case Store:
 		return bStores
 	}
@@ -132,22 +134,26 @@ type TypeStr string
 const (
 	strDefault  = "default"
 	strWebsites = "websites"
+	strGroup    = "group"
 	strStores   = "stores"
 )
 
 var (
 	bDefault  = []byte(strDefault)
 	bWebsites = []byte(strWebsites)
+	bGroup    = []byte(strGroup)
 	bStores   = []byte(strStores)
 )
 
 // Str* constants are used in the database table core_config_data. StrDefault
 // defines the global scope. StrWebsites defines the website scope which has
-// default as parent and stores as child. StrStores defines the store scope
-// which has default and websites as parent.
+// default as parent and stores as child. StrGroup defines the store group
+// scope which has websites as parent and stores as child. StrStores defines the
+// store scope which has default, websites and optionally group as parent.
 const (
 	StrDefault  TypeStr = strDefault
 	StrWebsites TypeStr = strWebsites
+	StrGroup    TypeStr = strGroup
 	StrStores   TypeStr = strStores
 )
 
@@ -161,18 +167,22 @@ func (s TypeStr) Type() Type {
 	switch s {
 	case StrWebsites:
 		return Website
+	case StrGroup:
+		return Group
 	case StrStores:
 		return Store
 	}
 	return Default
 }
 
-// FromString returns the Type from a string: default, websites or stores.
-// Opposite of FromType.
+// FromString returns the Type from a string: default, websites, group or
+// stores. Opposite of FromType.
 func FromString(s string) Type {
 	switch TypeStr(s) {
 	case StrWebsites:
 		return Website
+	case StrGroup:
+		return Group
 	case StrStores:
 		return Store
 	}
@@ -185,28 +195,33 @@ func FromType(scopeID Type) TypeStr {
 	switch scopeID {
 	case Website:
 		return StrWebsites
+	case Group:
+		return StrGroup
 	case Store:
 		return StrStores
 	}
 	return StrDefault
 }
 
-// Valid checks if s is a valid StrScope of either StrDefault, StrWebsites or
-// StrStores. Case-sensitive. Input should all be lowercase.
+// Valid checks if s is a valid StrScope of either StrDefault, StrWebsites,
+// StrGroup or StrStores. Case-sensitive. Input should all be lowercase.
 func Valid(s string) bool {
 	switch s {
-	case strWebsites, strStores, strDefault:
+	case strWebsites, strGroup, strStores, strDefault:
 		return true
 	}
 	return false
 }
 
 // FromBytes returns the Type from a byte slice. Supported values are
-// default, websites, stores, Default, Website, Group and store. Case sensitive.
+// default, websites, group, stores, Default, Website, Group and store. Case
+// sensitive.
 func FromBytes(b []byte) Type {
 	switch {
 	case bytes.Equal(bWebsites, b):
 		return Website
+	case bytes.Equal(bGroup, b):
+		return Group
 	case bytes.Equal(bStores, b):
 		return Store
 
@@ -228,15 +243,17 @@ func FromBytes(b []byte) Type {
 }
 
 // ValidBytes checks if b is a valid byte Type of either StrDefault,
-// StrWebsites or StrStores. Case-sensitive.
+// StrWebsites, StrGroup or StrStores. Case-sensitive.
 func ValidBytes(b []byte) bool {
-	return bytes.Equal(bDefault, b) || bytes.Equal(bWebsites, b) || bytes.Equal(bStores, b)
+	return bytes.Equal(bDefault, b) || bytes.Equal(bWebsites, b) || bytes.Equal(bGroup, b) || bytes.Equal(bStores, b)
 }
 
 // ValidParent validates if the parent scope is within the hierarchical chain:
-// default -> website -> store.
+// default -> website -> [group ->] store. The group is optional.
 func ValidParent(current Type, parent Type) bool {
 	return (parent == Default && current == Default) ||
 		(parent == Default && current == Website) ||
-		(parent == Website && current == Store)
+		(parent == Website && current == Group) ||
+		(parent == Website && current == Store) ||
+		(parent == Group && current == Store)
 }
@@ -34,6 +34,7 @@ func TestFromString(t *testing.T) {
 		{"asdasd", Default},
 		{strDefault, Default},
 		{strWebsites, Website},
+		{strGroup, Group},
 		{strStores, Store},
 	}
 	for _, test := range tests {
@@ -49,7 +50,7 @@ func TestFromType(t *testing.T) {
 	}{
 		{Default, StrDefault},
 		{Absent, StrDefault},
-		{Group, StrDefault},
+		{Group, StrGroup},
 		{Website, StrWebsites},
 		{Store, StrStores},
 	}
@@ -63,10 +64,12 @@ func TestStrType(t *testing.T) {
 
 	assert.Equal(t, strDefault, StrDefault.String())
 	assert.Equal(t, strWebsites, StrWebsites.String())
+	assert.Equal(t, strGroup, StrGroup.String())
 	assert.Equal(t, strStores, StrStores.String())
 
 	assert.Exactly(t, Default, StrDefault.Type())
 	assert.Exactly(t, Website, StrWebsites.Type())
+	assert.Exactly(t, Group, StrGroup.Type())
 	assert.Exactly(t, Store, StrStores.Type())
 }
 
@@ -80,6 +83,8 @@ func TestValid(t *testing.T) {
 		{"default", true},
 		{"website", false},
 		{"websites", true},
+		{"group", true},
+		{"groups", false},
 		{"stores", true},
 		{"Stores", false},
 	}
@@ -96,6 +101,7 @@ func TestFromBytes(t *testing.T) {
 		{[]byte("asdasd"), Default},
 		{[]byte(strDefault), Default},
 		{[]byte(strWebsites), Website},
+		{[]byte(strGroup), Group},
 		{[]byte(strStores), Store},
 	}
 	for _, test := range tests {
@@ -112,6 +118,7 @@ func TestValidBytes(t *testing.T) {
 		{[]byte("default"), true},
 		{[]byte("website"), false},
 		{[]byte("websites"), true},
+		{[]byte("group"), true},
 		{[]byte("stores"), true},
 		{[]byte("Stores"), false},
 	}
@@ -126,6 +133,7 @@ func TestStrTypeBytes(t *testing.T) {
 	}{
 		{Default},
 		{Website},
+		{Group},
 		{Store},
 		{44},
 	}
@@ -143,6 +151,10 @@ func TestValidParent(t *testing.T) {
 		{Default, Default, true},
 		{Website, Default, true},
 		{Store, Website, true},
+		{Group, Website, true},
+		{Store, Group, true},
+		{Group, Default, false},
+		{Website, Group, false},
 		{Default, Website, false},
 		{Absent, Absent, false},
 		{Absent, Default, false},
@@ -86,17 +86,17 @@ func (t TypeID) AppendBytes(dst []byte) (text []byte) {
 	return strconv.AppendUint(dst, t.ToUint64(), 10)
 }
 
-// AppendHuman appends to dst the human textual representation of a Websites or
-// Stores scope and their IDs. Default, Group and invalid scopes won't get
+// AppendHuman appends to dst the human textual representation of a Websites,
+// Group or Stores scope and their IDs. Default and invalid scopes won't get
 // appended. Will write:
 //		scope.Websites.WithID(1) => websites/1
+//		scope.Group.WithID(4) => group/4
 //		scope.Stores.WithID(2) => stores/2
 //		scope.DefaultTypeID => "" <- returns dst unchanged.
-//		scope.Group.WithID(4) => "" <- returns dst unchanged.
 // This function gets used in the config package to write a path depending on
 // the paths scope.
 func (t TypeID) AppendHuman(dst []byte, separator byte) (text []byte) {
-	if s, id := t.Unpack(); s.IsWebSiteOrStore() {
+	if s, id := t.Unpack(); s.IsWebSiteOrStore() || s == Group {
 		dst = append(dst, s.StrBytes()...)
 		dst = append(dst, separator)
 		dst = strconv.AppendUint(dst, uint64(id), 10)
@@ -192,14 +192,17 @@ func (t TypeID) ID() (uint32, error) {
 }
 
 // ValidParent validates if the parent Type is within the hierarchical chain:
-// default -> website -> store. Returns also true when parent is zero.
+// default -> website -> [group ->] store. Returns also true when parent is
+// zero.
 func (t TypeID) ValidParent(parent TypeID) bool {
 	p, pID := parent.Unpack()
 	c, cID := t.Unpack()
 	return (p == Absent && pID == 0) ||
 		(p == Default && pID == 0 && c == Default && cID == 0) ||
 		(p == Default && pID == 0 && c == Website && cID >= 0) ||
-		(p == Website && pID >= 0 && c == Store && cID >= 0)
+		(p == Website && pID >= 0 && c == Group && cID >= 0) ||
+		(p == Website && pID >= 0 && c == Store && cID >= 0) ||
+		(p == Group && pID >= 0 && c == Store && cID >= 0)
 }
 
 // IsValid checks if the scope and its ID are valid.
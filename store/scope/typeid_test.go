@@ -330,6 +330,10 @@ func TestTypeID_ValidParent(t *testing.T) {
 		{scope.MakeTypeID(scope.Store, 1), scope.MakeTypeID(scope.Website, 1), true},
 		{scope.MakeTypeID(scope.Store, 1), scope.MakeTypeID(scope.Website, 0), true},
 		{scope.MakeTypeID(scope.Store, 0), scope.MakeTypeID(scope.Website, 0), true},
+		{scope.MakeTypeID(scope.Group, 2), scope.MakeTypeID(scope.Website, 1), true},
+		{scope.MakeTypeID(scope.Store, 1), scope.MakeTypeID(scope.Group, 2), true},
+		{scope.MakeTypeID(scope.Group, 2), scope.DefaultTypeID, false},
+		{scope.MakeTypeID(scope.Website, 1), scope.MakeTypeID(scope.Group, 2), false},
 		{scope.DefaultTypeID, scope.MakeTypeID(scope.Website, 1), false},
 		{0, 0, true},
 		{0, scope.DefaultTypeID, false},
@@ -491,7 +495,7 @@ func TestTypeID_AppendHuman(t *testing.T) {
 		want string
 	}{
 		{scope.DefaultTypeID, ""},
-		{scope.Group.WithID(13), ""},
+		{scope.Group.WithID(13), "group/13"},
 		{scope.Website.WithID(13), "websites/13"},
 		{scope.Website.WithID(0), "websites/0"},
 		{scope.Store.WithID(13), "stores/13"},
@@ -0,0 +1,178 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/conv"
+)
+
+const (
+	claimJTI = "jti"
+	claimExp = "exp"
+)
+
+// BlacklistOptions configures NewBlacklistObjcache.
+type BlacklistOptions struct {
+	// Leeway gets added to the remaining lifetime of a token. It must match the
+	// clock skew tolerance of the claims validation, otherwise a revoked token
+	// might expire in the blacklist while the validator still accepts it.
+	Leeway time.Duration
+	// MaxTTL applies to tokens without exp claim and to Set calls without
+	// expiration. Default 24h.
+	MaxTTL time.Duration
+	// LRUFront, if not nil, puts an in-process LRU cache in front of the
+	// storage, see objcache.NewTwoLevel. Revoked tokens get then answered from
+	// memory, unknown tokens still query the storage.
+	LRUFront *objcache.LRUOptions
+	// LRUFrontTTL caps the lifetime of an entry in the LRU front. Zero uses
+	// the lifetime of the token.
+	LRUFrontTTL time.Duration
+}
+
+// BlacklistObjcache stores revoked tokens in an objcache.Storager, for example
+// Redis, to share them between several app instances. Each entry expires with
+// the token. Safe for concurrent use. BlacklistObjcache implements the
+// Blocklister interface of package net/jwt.
+type BlacklistObjcache struct {
+	s      objcache.Storager
+	prefix string
+	o      BlacklistOptions
+}
+
+// NewBlacklistObjcache creates a new token blacklist. The keyPrefix gets
+// prepended to each token ID. Argument o can be nil.
+func NewBlacklistObjcache(storage objcache.Storager, keyPrefix string, o *BlacklistOptions) (*BlacklistObjcache, error) {
+	if storage == nil {
+		return nil, errors.Empty.Newf("[csjwt] NewBlacklistObjcache: storage cannot be nil")
+	}
+	bl := &BlacklistObjcache{
+		s:      storage,
+		prefix: keyPrefix,
+	}
+	if o != nil {
+		bl.o = *o
+	}
+	if bl.o.MaxTTL <= 0 {
+		bl.o.MaxTTL = 24 * time.Hour
+	}
+	if bl.o.LRUFront != nil {
+		s, err := objcache.NewTwoLevel(
+			objcache.NewLRU(bl.o.LRUFront),
+			func() (objcache.Storager, error) { return storage, nil },
+			objcache.TwoLevelOptions{L1TTL: bl.o.LRUFrontTTL},
+		)()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		bl.s = s
+	}
+	return bl, nil
+}
+
+// tokenID returns the jti claim or, as fallback, the SHA-256 hash of the
+// signature.
+func tokenID(tk *Token) (string, error) {
+	if tk.Claims != nil {
+		if jti, _ := tk.Claims.Get(claimJTI); jti != nil {
+			if id := conv.ToString(jti); id != "" {
+				return claimJTI + ":" + id, nil
+			}
+		}
+	}
+	if len(tk.Signature) == 0 {
+		return "", errors.Empty.Newf("[csjwt] Token has neither a jti claim nor a signature")
+	}
+	h := sha256.Sum256(tk.Signature)
+	return "sig:" + hex.EncodeToString(h[:]), nil
+}
+
+// ttl returns the duration an entry must stay in the blacklist, rounded up to
+// seconds. Zero means the token has already expired including the Leeway.
+func (bl *BlacklistObjcache) ttl(remaining time.Duration) time.Duration {
+	remaining += bl.o.Leeway
+	if remaining <= 0 {
+		return 0
+	}
+	if r := remaining % time.Second; r > 0 {
+		remaining += time.Second - r
+	}
+	return remaining
+}
+
+func (bl *BlacklistObjcache) set(ctx context.Context, id string, ttl time.Duration) error {
+	return errors.WithStack(bl.s.Set(ctx, []string{bl.prefix + id}, [][]byte{{1}}, []time.Duration{ttl}))
+}
+
+func (bl *BlacklistObjcache) has(ctx context.Context, id string) (bool, error) {
+	vals, err := bl.s.Get(ctx, []string{bl.prefix + id})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return len(vals) == 1 && vals[0] != nil, nil
+}
+
+// Revoke adds the token to the blacklist until it expires. Tokens without exp
+// claim stay MaxTTL in the blacklist.
+func (bl *BlacklistObjcache) Revoke(ctx context.Context, tk *Token) error {
+	id, err := tokenID(tk)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	ttl := bl.o.MaxTTL
+	if tk.Claims != nil {
+		rawExp, _ := tk.Claims.Get(claimExp)
+		if exp := conv.ToInt64(rawExp); exp > 0 {
+			if ttl = bl.ttl(time.Until(time.Unix(exp, 0))); ttl == 0 {
+				return nil // already invalid
+			}
+		}
+	}
+	return errors.Wrapf(bl.set(ctx, id, ttl), "[csjwt] BlacklistObjcache.Revoke with ID %q", id)
+}
+
+// IsRevoked checks if the token has been added to the blacklist.
+func (bl *BlacklistObjcache) IsRevoked(ctx context.Context, tk *Token) (bool, error) {
+	id, err := tokenID(tk)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	ok, err := bl.has(ctx, id)
+	return ok, errors.Wrapf(err, "[csjwt] BlacklistObjcache.IsRevoked with ID %q", id)
+}
+
+// Set adds the token ID (jti claim) to the blacklist for the expires duration
+// plus the Leeway. An expiration of zero applies MaxTTL.
+func (bl *BlacklistObjcache) Set(id []byte, expires time.Duration) error {
+	ttl := bl.o.MaxTTL
+	if expires > 0 {
+		ttl = bl.ttl(expires)
+	}
+	return errors.Wrapf(bl.set(context.Background(), claimJTI+":"+string(id), ttl), "[csjwt] BlacklistObjcache.Set with ID %q", id)
+}
+
+// Has checks if the token ID (jti claim) has been added to the blacklist. An
+// error of the storage reports the ID as blacklisted. Use IsRevoked to receive
+// the error.
+func (bl *BlacklistObjcache) Has(id []byte) bool {
+	ok, err := bl.has(context.Background(), claimJTI+":"+string(id))
+	return ok || err != nil
+}
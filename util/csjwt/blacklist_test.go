@@ -0,0 +1,159 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/csjwt"
+	"github.com/corestoreio/pkg/util/csjwt/jwtclaim"
+)
+
+// blocklister equals the interface of package net/jwt.
+type blocklister interface {
+	Set(id []byte, expires time.Duration) error
+	Has(id []byte) bool
+}
+
+var _ blocklister = (*csjwt.BlacklistObjcache)(nil)
+
+type recordingStorage struct {
+	objcache.Storager
+	mu   sync.Mutex
+	ttls map[string]time.Duration
+	err  error
+}
+
+func newRecordingStorage(t *testing.T) *recordingStorage {
+	s, err := objcache.NewCacheSimpleInmemory()
+	assert.NoError(t, err)
+	return &recordingStorage{Storager: s, ttls: map[string]time.Duration{}}
+}
+
+func (rs *recordingStorage) Set(ctx context.Context, keys []string, values [][]byte, expirations []time.Duration) error {
+	rs.mu.Lock()
+	for i, k := range keys {
+		rs.ttls[k] = expirations[i]
+	}
+	rs.mu.Unlock()
+	return rs.Storager.Set(ctx, keys, values, expirations)
+}
+
+func (rs *recordingStorage) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	if rs.err != nil {
+		return nil, rs.err
+	}
+	return rs.Storager.Get(ctx, keys)
+}
+
+func TestBlacklistObjcache(t *testing.T) {
+	ctx := context.TODO()
+	newToken := func(jti string, exp time.Duration) *csjwt.Token {
+		sc := &jwtclaim.Standard{ID: jti}
+		if exp != 0 {
+			sc.ExpiresAt = time.Now().Add(exp).Unix()
+		}
+		return &csjwt.Token{Claims: sc, Signature: []byte("signature of " + jti)}
+	}
+
+	t.Run("TTL from exp and leeway", func(t *testing.T) {
+		rs := newRecordingStorage(t)
+		bl, err := csjwt.NewBlacklistObjcache(rs, "bl_", &csjwt.BlacklistOptions{Leeway: 30 * time.Second, MaxTTL: 2 * time.Hour})
+		assert.NoError(t, err)
+
+		tkLogout := newToken("logout", time.Hour)
+		assert.NoError(t, bl.Revoke(ctx, tkLogout))
+		ttl := rs.ttls["bl_jti:logout"]
+		assert.True(t, ttl > time.Hour && ttl <= time.Hour+31*time.Second, "TTL %s", ttl)
+
+		ok, err := bl.IsRevoked(ctx, tkLogout)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = bl.IsRevoked(ctx, newToken("other", time.Hour))
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		assert.NoError(t, bl.Revoke(ctx, newToken("", time.Hour)))
+		ok, err = bl.IsRevoked(ctx, newToken("", time.Hour))
+		assert.NoError(t, err)
+		assert.True(t, ok, "falls back to the signature hash")
+
+		assert.NoError(t, bl.Revoke(ctx, newToken("noexp", 0)))
+		assert.Exactly(t, 2*time.Hour, rs.ttls["bl_jti:noexp"])
+
+		assert.NoError(t, bl.Revoke(ctx, newToken("within_leeway", -10*time.Second)))
+		ttl = rs.ttls["bl_jti:within_leeway"]
+		assert.True(t, ttl > 0 && ttl <= 21*time.Second, "TTL %s", ttl)
+
+		assert.NoError(t, bl.Revoke(ctx, newToken("expired", -time.Minute)))
+		_, ok = rs.ttls["bl_jti:expired"]
+		assert.False(t, ok, "expired token must not be stored")
+
+		_, err = bl.IsRevoked(ctx, &csjwt.Token{Claims: &jwtclaim.Standard{}})
+		assert.ErrorIsKind(t, errors.Empty, err)
+	})
+
+	t.Run("Set and Has", func(t *testing.T) {
+		rs := newRecordingStorage(t)
+		bl, err := csjwt.NewBlacklistObjcache(rs, "", nil)
+		assert.NoError(t, err)
+		assert.NoError(t, bl.Set([]byte("logout"), 0))
+		assert.Exactly(t, 24*time.Hour, rs.ttls["jti:logout"])
+		assert.NoError(t, bl.Set([]byte("single_use"), 1500*time.Millisecond))
+		assert.Exactly(t, 2*time.Second, rs.ttls["jti:single_use"])
+
+		assert.True(t, bl.Has([]byte("logout")))
+		ok, err := bl.IsRevoked(ctx, newToken("single_use", time.Hour))
+		assert.NoError(t, err)
+		assert.True(t, ok, "Set and Revoke share the key")
+		assert.False(t, bl.Has([]byte("unknown")))
+
+		rs.err = errors.ConnectionFailed.Newf("storage offline")
+		assert.True(t, bl.Has([]byte("unknown")), "errors must fail closed")
+		_, err = bl.IsRevoked(ctx, newToken("unknown", time.Hour))
+		assert.ErrorIsKind(t, errors.ConnectionFailed, err)
+	})
+
+	t.Run("LRU front shares revocations between instances", func(t *testing.T) {
+		rs := newRecordingStorage(t)
+		opts := &csjwt.BlacklistOptions{LRUFront: &objcache.LRUOptions{Capacity: 100}, LRUFrontTTL: time.Minute}
+		bl1, err := csjwt.NewBlacklistObjcache(rs, "bl_", opts)
+		assert.NoError(t, err)
+		bl2, err := csjwt.NewBlacklistObjcache(rs, "bl_", opts)
+		assert.NoError(t, err)
+
+		tk := newToken("logout", time.Hour)
+		assert.NoError(t, bl1.Revoke(ctx, tk))
+		ok, err := bl2.IsRevoked(ctx, tk)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		rs.err = errors.ConnectionFailed.Newf("storage offline")
+		ok, err = bl2.IsRevoked(ctx, tk)
+		assert.NoError(t, err, "answered by the LRU front")
+		assert.True(t, ok)
+	})
+
+	t.Run("nil storage", func(t *testing.T) {
+		_, err := csjwt.NewBlacklistObjcache(nil, "", nil)
+		assert.ErrorIsKind(t, errors.Empty, err)
+	})
+}
@@ -0,0 +1,320 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/conv"
+)
+
+// ClaimsRaw decodes only the registered claims aud, exp, jti, iat, iss, nbf
+// and sub eagerly and keeps all other claims as raw JSON sections. A section
+// gets decoded on demand with DecodeSection. Useful for tokens with large
+// claims which most requests never read. ClaimsRaw must be used as a pointer
+// and is not safe for concurrent use.
+type ClaimsRaw struct {
+	Audience  []string
+	ExpiresAt int64
+	ID        string
+	IssuedAt  int64
+	Issuer    string
+	NotBefore int64
+	Subject   string
+	sections  map[string]json.RawMessage
+	decoded   map[string]interface{}
+}
+
+func isRegisteredClaim(key string) bool {
+	switch key {
+	case claimAudience, claimExpiresAt, claimID, claimIssuedAt, claimIssuer, claimNotBefore, claimSubject:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON decodes the registered claims and keeps the raw bytes of all
+// other claims. Error behaviour: BadEncoding, NotValid.
+func (c *ClaimsRaw) UnmarshalJSON(data []byte) error {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return errors.BadEncoding.New(err, "[csjwt] ClaimsRaw.UnmarshalJSON")
+	}
+	*c = ClaimsRaw{}
+	for key, raw := range all {
+		if !isRegisteredClaim(key) {
+			continue
+		}
+		if err := c.unmarshalRegistered(key, raw); err != nil {
+			return errors.WithStack(err)
+		}
+		delete(all, key)
+	}
+	c.sections = all
+	return nil
+}
+
+func (c *ClaimsRaw) unmarshalRegistered(key string, raw json.RawMessage) error {
+	if string(raw) == "null" {
+		return nil
+	}
+	var err error
+	switch key {
+	case claimAudience:
+		var aud string
+		if err = json.Unmarshal(raw, &aud); err == nil {
+			c.Audience = nil
+			if aud != "" {
+				c.Audience = []string{aud}
+			}
+			return nil
+		}
+		err = json.Unmarshal(raw, &c.Audience)
+	case claimExpiresAt, claimIssuedAt, claimNotBefore:
+		var n json.Number
+		if err = json.Unmarshal(raw, &n); err == nil {
+			var f float64
+			if f, err = n.Float64(); err == nil {
+				err = c.Set(key, f)
+			}
+		}
+	case claimID:
+		err = json.Unmarshal(raw, &c.ID)
+	case claimIssuer:
+		err = json.Unmarshal(raw, &c.Issuer)
+	case claimSubject:
+		err = json.Unmarshal(raw, &c.Subject)
+	}
+	if err != nil {
+		return errors.NotValid.New(err, "[csjwt] ClaimsRaw claim %q", key)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the registered claims and appends the raw sections
+// sorted by key without re-encoding them.
+func (c *ClaimsRaw) MarshalJSON() ([]byte, error) {
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	var tmp [32]byte
+	buf.WriteByte('{')
+	writeKey := func(key string) {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(strconv.AppendQuote(tmp[:0], key))
+		buf.WriteByte(':')
+	}
+	writeJSON := func(key string, v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return errors.BadEncoding.New(err, "[csjwt] ClaimsRaw.MarshalJSON claim %q", key)
+		}
+		writeKey(key)
+		buf.Write(data)
+		return nil
+	}
+	writeInt := func(key string, v int64) {
+		if v != 0 {
+			writeKey(key)
+			buf.Write(strconv.AppendInt(tmp[:0], v, 10))
+		}
+	}
+
+	switch len(c.Audience) {
+	case 0:
+	case 1:
+		if err := writeJSON(claimAudience, c.Audience[0]); err != nil {
+			return nil, err
+		}
+	default:
+		if err := writeJSON(claimAudience, c.Audience); err != nil {
+			return nil, err
+		}
+	}
+	writeInt(claimExpiresAt, c.ExpiresAt)
+	writeInt(claimIssuedAt, c.IssuedAt)
+	for _, kv := range [...][2]string{{claimID, c.ID}, {claimIssuer, c.Issuer}, {claimSubject, c.Subject}} {
+		if kv[1] != "" {
+			if err := writeJSON(kv[0], kv[1]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	writeInt(claimNotBefore, c.NotBefore)
+
+	for _, key := range c.sectionKeys() {
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, errors.BadEncoding.New(err, "[csjwt] ClaimsRaw.MarshalJSON key %q", key)
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		buf.Write(c.sections[key])
+	}
+	buf.WriteByte('}')
+	return append([]byte(nil), buf.Bytes()...), nil
+}
+
+func (c *ClaimsRaw) sectionKeys() []string {
+	keys := make([]string, 0, len(c.sections))
+	for key := range c.sections {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DecodeSection decodes the raw JSON of the unregistered claim name into dst.
+// The decoded value gets memoized and a subsequent call with the same type of
+// dst receives a shallow copy without decoding again. Error behaviour:
+// NotFound, NotSupported, BadEncoding.
+func (c *ClaimsRaw) DecodeSection(name string, dst interface{}) error {
+	if isRegisteredClaim(name) {
+		return errors.NotSupported.Newf("[csjwt] ClaimsRaw.DecodeSection registered claim %q is already decoded", name)
+	}
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.NotSupported.Newf("[csjwt] ClaimsRaw.DecodeSection requires a non-nil pointer, got %T", dst)
+	}
+	if v, ok := c.decoded[name]; ok && reflect.TypeOf(v) == rv.Type() {
+		rv.Elem().Set(reflect.ValueOf(v).Elem())
+		return nil
+	}
+
+	raw, ok := c.sections[name]
+	if !ok {
+		return errors.NotFound.Newf("[csjwt] ClaimsRaw.DecodeSection claim %q not found", name)
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return errors.BadEncoding.New(err, "[csjwt] ClaimsRaw.DecodeSection claim %q", name)
+	}
+
+	memo := reflect.New(rv.Elem().Type())
+	memo.Elem().Set(rv.Elem())
+	if c.decoded == nil {
+		c.decoded = make(map[string]interface{})
+	}
+	c.decoded[name] = memo.Interface()
+	return nil
+}
+
+// Valid validates the time based claims exp, iat and nbf. There is no
+// accounting for clock skew, use ValidationOptions for a leeway. Missing
+// claims are considered valid. Error behaviour: NotValid.
+func (c *ClaimsRaw) Valid() error {
+	now := TimeFunc()
+	switch {
+	case c.ExpiresAt != 0 && now.Unix() > c.ExpiresAt:
+		return errors.NotValid.Newf("[csjwt] token is expired %s ago", now.Sub(time.Unix(c.ExpiresAt, 0)))
+	case c.IssuedAt != 0 && now.Unix() < c.IssuedAt:
+		return errors.NotValid.Newf("[csjwt] token used before issued, clock skew issue? Diff %s", time.Unix(c.IssuedAt, 0).Sub(now))
+	case c.NotBefore != 0 && now.Unix() < c.NotBefore:
+		return errors.NotValid.Newf("[csjwt] token is not valid yet. Diff %s", time.Unix(c.NotBefore, 0).Sub(now))
+	}
+	return nil
+}
+
+// Expires duration when a token expires.
+func (c *ClaimsRaw) Expires() (exp time.Duration) {
+	if c.ExpiresAt > 0 {
+		if remainer := time.Unix(c.ExpiresAt, 0).Sub(TimeFunc()); remainer > 0 {
+			exp = remainer
+		}
+	}
+	return
+}
+
+// Set sets a registered claim or encodes value as the raw JSON of an
+// unregistered claim. A json.RawMessage gets stored as is. Error behaviour:
+// NotValid, BadEncoding.
+func (c *ClaimsRaw) Set(key string, value interface{}) (err error) {
+	switch key {
+	case claimAudience:
+		if s, ok := value.(string); ok {
+			c.Audience = []string{s}
+			return nil
+		}
+		c.Audience, err = conv.ToStringSliceE(value)
+	case claimExpiresAt:
+		c.ExpiresAt, err = conv.ToInt64E(value)
+	case claimID:
+		c.ID, err = conv.ToStringE(value)
+	case claimIssuedAt:
+		c.IssuedAt, err = conv.ToInt64E(value)
+	case claimIssuer:
+		c.Issuer, err = conv.ToStringE(value)
+	case claimNotBefore:
+		c.NotBefore, err = conv.ToInt64E(value)
+	case claimSubject:
+		c.Subject, err = conv.ToStringE(value)
+	default:
+		raw, ok := value.(json.RawMessage)
+		if !ok {
+			if raw, err = json.Marshal(value); err != nil {
+				return errors.BadEncoding.New(err, "[csjwt] ClaimsRaw.Set claim %q", key)
+			}
+		}
+		if c.sections == nil {
+			c.sections = make(map[string]json.RawMessage)
+		}
+		c.sections[key] = raw
+		delete(c.decoded, key)
+		return nil
+	}
+	return errors.NotValid.New(err, "[csjwt] ClaimsRaw.Set claim %q", key)
+}
+
+// Get returns a registered claim or the json.RawMessage of an unregistered
+// claim. Error behaviour: NotFound.
+func (c *ClaimsRaw) Get(key string) (interface{}, error) {
+	switch key {
+	case claimAudience:
+		return c.Audience, nil
+	case claimExpiresAt:
+		return c.ExpiresAt, nil
+	case claimID:
+		return c.ID, nil
+	case claimIssuedAt:
+		return c.IssuedAt, nil
+	case claimIssuer:
+		return c.Issuer, nil
+	case claimNotBefore:
+		return c.NotBefore, nil
+	case claimSubject:
+		return c.Subject, nil
+	}
+	if raw, ok := c.sections[key]; ok {
+		return raw, nil
+	}
+	return nil, errors.NotFound.Newf("[csjwt] ClaimsRaw.Get claim %q not found", key)
+}
+
+// Keys returns the registered claims followed by the sorted keys of the raw
+// sections.
+func (c *ClaimsRaw) Keys() []string {
+	keys := make([]string, 0, 7+len(c.sections))
+	keys = append(keys, claimAudience, claimExpiresAt, claimID, claimIssuedAt, claimIssuer, claimNotBefore, claimSubject)
+	return append(keys, c.sectionKeys()...)
+}
@@ -0,0 +1,177 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/csjwt"
+	"github.com/corestoreio/pkg/util/csjwt/jwtclaim"
+)
+
+var _ csjwt.Claimer = (*csjwt.ClaimsRaw)(nil)
+
+type resourceAccess map[string]struct {
+	Roles []string `json:"roles"`
+}
+
+var resourceAccessDecodes int32
+
+type countingSection struct {
+	Roles []string `json:"roles"`
+}
+
+func (cs *countingSection) UnmarshalJSON(data []byte) error {
+	atomic.AddInt32(&resourceAccessDecodes, 1)
+	type plain countingSection
+	return json.Unmarshal(data, (*plain)(cs))
+}
+
+// newLargeToken creates a signed token with a resource_access claim of about
+// 4KB.
+func newLargeToken(tb testing.TB, m csjwt.Signer, key csjwt.Key) []byte {
+	ra := make(resourceAccess)
+	for i := 0; len(ra) < 42; i++ {
+		ra[fmt.Sprintf("client-%02d", i)] = struct {
+			Roles []string `json:"roles"`
+		}{Roles: []string{"view-profile", "manage-account", "read-orders"}}
+	}
+	claims := jwtclaim.Map{
+		"aud":             []string{"shop", "api"},
+		"exp":             time.Now().Add(time.Hour).Unix(),
+		"iss":             "https://idp.example.com",
+		"sub":             "gopher",
+		"resource_access": ra,
+		"profile":         map[string]string{"name": "Gopher"},
+	}
+	raw, err := csjwt.NewToken(claims).SignedString(m, key)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return raw
+}
+
+func TestClaimsRaw(t *testing.T) {
+	hs256 := csjwt.NewSigningMethodHS256()
+	pw := csjwt.WithPasswordRandom()
+	raw := newLargeToken(t, hs256, pw)
+	assert.True(t, len(raw) > 4096, "token length %d", len(raw))
+
+	var claims csjwt.ClaimsRaw
+	tk := csjwt.NewToken(&claims)
+	vf := csjwt.NewVerification(hs256)
+	vf.ValidationOptions = &csjwt.ValidationOptions{ExpectedAudiences: []string{"api"}, ExpectedIssuer: "https://idp.example.com"}
+	assert.NoError(t, vf.Parse(tk, raw, csjwt.NewKeyFunc(hs256, pw)))
+	assert.True(t, tk.Valid)
+
+	assert.Exactly(t, []string{"shop", "api"}, claims.Audience)
+	assert.Exactly(t, "gopher", claims.Subject)
+	assert.True(t, claims.Expires() > 59*time.Minute)
+	assert.Exactly(t, []string{"aud", "exp", "jti", "iat", "iss", "nbf", "sub", "profile", "resource_access"}, claims.Keys())
+
+	t.Run("DecodeSection memoized", func(t *testing.T) {
+		atomic.StoreInt32(&resourceAccessDecodes, 0)
+		var ra map[string]*countingSection
+		assert.NoError(t, claims.DecodeSection("resource_access", &ra))
+		assert.Len(t, ra, 42)
+		assert.Exactly(t, []string{"view-profile", "manage-account", "read-orders"}, ra["client-07"].Roles)
+		assert.Exactly(t, int32(42), atomic.LoadInt32(&resourceAccessDecodes))
+
+		var ra2 map[string]*countingSection
+		assert.NoError(t, claims.DecodeSection("resource_access", &ra2))
+		assert.Len(t, ra2, 42)
+		assert.Exactly(t, int32(42), atomic.LoadInt32(&resourceAccessDecodes))
+
+		// Another type decodes again.
+		var ra3 resourceAccess
+		assert.NoError(t, claims.DecodeSection("resource_access", &ra3))
+		assert.Len(t, ra3, 42)
+
+		assert.ErrorIsKind(t, errors.NotFound, claims.DecodeSection("realm_access", &ra3))
+		assert.ErrorIsKind(t, errors.NotSupported, claims.DecodeSection("aud", &ra3))
+		assert.ErrorIsKind(t, errors.NotSupported, claims.DecodeSection("profile", ra3))
+	})
+
+	t.Run("Set and Get raw sections", func(t *testing.T) {
+		profile, err := claims.Get("profile")
+		assert.NoError(t, err)
+		assert.Exactly(t, json.RawMessage(`{"name":"Gopher"}`), profile)
+		_, err = claims.Get("realm_access")
+		assert.ErrorIsKind(t, errors.NotFound, err)
+
+		assert.NoError(t, claims.Set("profile", map[string]string{"name": "Gophine"}))
+		var p map[string]string
+		assert.NoError(t, claims.DecodeSection("profile", &p))
+		assert.Exactly(t, "Gophine", p["name"])
+		assert.NoError(t, claims.Set("flags", json.RawMessage(`[1,2]`)))
+		assert.NoError(t, claims.Set("aud", "shop"))
+
+		data, err := json.Marshal(&claims)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(data), `{"aud":"shop","exp":`), "%s", data)
+		assert.Contains(t, string(data), `"flags":[1,2],"profile":{"name":"Gophine"},"resource_access":{"client-00":{"roles":["view-profile"`)
+
+		var c2 csjwt.ClaimsRaw
+		assert.NoError(t, json.Unmarshal(data, &c2))
+		assert.Exactly(t, claims.Keys(), c2.Keys())
+		assert.Exactly(t, []string{"shop"}, c2.Audience)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var c csjwt.ClaimsRaw
+		assert.ErrorIsKind(t, errors.BadEncoding, c.UnmarshalJSON([]byte(`[1]`)))
+		assert.ErrorIsKind(t, errors.NotValid, c.UnmarshalJSON([]byte(`{"exp":"tomorrow"}`)))
+		assert.ErrorIsKind(t, errors.NotValid, c.UnmarshalJSON([]byte(`{"aud":{"a":1}}`)))
+		assert.NoError(t, c.UnmarshalJSON([]byte(`{"exp":1.5e9,"nbf":null}`)))
+		assert.Exactly(t, int64(1500000000), c.ExpiresAt)
+		assert.ErrorIsKind(t, errors.NotValid, c.Valid())
+	})
+}
+
+// BenchmarkClaimsRaw_4KB/Map         	    2000	    204879 ns/op	   36405 B/op	     765 allocs/op
+// BenchmarkClaimsRaw_4KB/ClaimsRaw   	    2000	     46082 ns/op	    7673 B/op	      31 allocs/op
+func BenchmarkClaimsRaw_4KB(b *testing.B) {
+	hs256, err := csjwt.NewSigningMethodHS256Fast(csjwt.WithPassword([]byte(`csjwt.SigningMethodHS256!`)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	raw := newLargeToken(b, hs256, csjwt.Key{})
+	kf := csjwt.NewKeyFunc(hs256, csjwt.Key{})
+	vf := csjwt.NewVerification(hs256)
+
+	b.Run("Map", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := vf.Parse(csjwt.NewToken(&jwtclaim.Map{}), raw, kf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ClaimsRaw", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := vf.Parse(csjwt.NewToken(&csjwt.ClaimsRaw{}), raw, kf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
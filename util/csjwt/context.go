@@ -40,3 +40,12 @@ func FromContextToken(ctx context.Context) (*Token, bool) {
 	}
 	return wrp.Token, ok
 }
+
+// FromContext returns a copy of the token in ctx and true if it exists.
+func FromContext(ctx context.Context) (Token, bool) {
+	tk, ok := FromContextToken(ctx)
+	if !ok || tk == nil {
+		return Token{}, false
+	}
+	return *tk, true
+}
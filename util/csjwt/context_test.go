@@ -35,3 +35,14 @@ func TestContextWithError(t *testing.T) {
 	assert.Nil(t, cntry)
 	assert.False(t, ok)
 }
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.TODO())
+	assert.False(t, ok)
+
+	tk := NewToken(nil)
+	tk.Valid = true
+	have, ok := FromContext(WithContextToken(context.TODO(), tk))
+	assert.True(t, ok)
+	assert.True(t, have.Valid)
+}
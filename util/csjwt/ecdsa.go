@@ -58,7 +58,9 @@ func (m *SigningMethodECDSA) Verify(signingString, signature []byte, key Key) er
 	}
 
 	// Decode the signature
-	sig, err := DecodeSegment(signature)
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+	sig, err := DecodeSegmentTo(buf.Bytes(), signature)
 	if err != nil {
 		return errors.Wrap(err, "[csjwt] SigningMethodECDSA.Verify.DecodeSegment")
 	}
@@ -81,7 +83,8 @@ func (m *SigningMethodECDSA) Verify(signingString, signature []byte, key Key) er
 	}
 
 	// Verify the signature
-	if !ecdsa.Verify(key.ecdsaKeyPub, hasher.Sum(nil), r, s) {
+	var sum [64]byte
+	if !ecdsa.Verify(key.ecdsaKeyPub, hasher.Sum(sum[:0]), r, s) {
 		return errors.NotValid.Newf(errECDSAVerification)
 	}
 	return nil
@@ -109,7 +112,8 @@ func (m *SigningMethodECDSA) Sign(signingString []byte, key Key) ([]byte, error)
 	}
 
 	// Sign the string and return r, s
-	r, s, err := ecdsa.Sign(rand.Reader, key.ecdsaKeyPriv, hasher.Sum(nil))
+	var sum [64]byte
+	r, s, err := ecdsa.Sign(rand.Reader, key.ecdsaKeyPriv, hasher.Sum(sum[:0]))
 	if err != nil {
 		return nil, errors.NotValid.New(err, "[csjwt] SigningMethodECDSA.Sign.ecdsa.Sign")
 	}
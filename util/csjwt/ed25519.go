@@ -0,0 +1,71 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"github.com/corestoreio/errors"
+	"golang.org/x/crypto/ed25519"
+)
+
+// SigningMethodEd25519 implements the EdDSA signing method with the Ed25519
+// curve, see RFC 8037.
+type SigningMethodEd25519 struct {
+	Name string
+}
+
+// NewSigningMethodEdDSA creates a new Ed25519 instance.
+func NewSigningMethodEdDSA() *SigningMethodEd25519 {
+	return &SigningMethodEd25519{Name: EdDSA}
+}
+
+// Alg returns the name of the underlying algorithm.
+func (m *SigningMethodEd25519) Alg() string {
+	return m.Name
+}
+
+// Verify implements the Verify method from SigningMethod interface. For the key
+// you can use any of the WithEd25519*Key() functions. Error behaviour: Empty,
+// NotValid.
+func (m *SigningMethodEd25519) Verify(signingString, signature []byte, key Key) error {
+	if key.Error != nil {
+		return errors.WithStack(key.Error)
+	}
+	if len(key.ed25519KeyPub) != ed25519.PublicKeySize {
+		return errors.Empty.Newf(errEd25519PublicKeyEmpty)
+	}
+
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+	sig, err := DecodeSegmentTo(buf.Bytes(), signature)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !ed25519.Verify(key.ed25519KeyPub, signingString, sig) {
+		return errors.NotValid.Newf(errEd25519Verification)
+	}
+	return nil
+}
+
+// Sign implements the Sign method from SigningMethod interface. For the key you
+// can use the WithEd25519PrivateKey() function. Error behaviour: Empty.
+func (m *SigningMethodEd25519) Sign(signingString []byte, key Key) ([]byte, error) {
+	if key.Error != nil {
+		return nil, errors.WithStack(key.Error)
+	}
+	if len(key.ed25519KeyPriv) != ed25519.PrivateKeySize {
+		return nil, errors.Empty.Newf(errEd25519PrivateKeyEmpty)
+	}
+	return EncodeSegment(ed25519.Sign(key.ed25519KeyPriv, signingString)), nil
+}
@@ -124,18 +124,46 @@ func (e *gobEncoding) Deserialize(src []byte, dst interface{}) error {
 // EncodeSegment encodes JWT specific base64url encoding with padding stripped.
 // Returns a new byte slice.
 func EncodeSegment(seg []byte) []byte {
-	dbuf := make([]byte, base64.RawURLEncoding.EncodedLen(len(seg)))
-	base64.RawURLEncoding.Encode(dbuf, seg)
-	return dbuf
+	return EncodeSegmentTo(make([]byte, 0, base64.RawURLEncoding.EncodedLen(len(seg))), seg)
+}
+
+// EncodeSegmentTo appends the JWT specific base64url encoding with padding
+// stripped of src to dst and returns the extended buffer. It does not allocate
+// if dst has enough capacity.
+func EncodeSegmentTo(dst, src []byte) []byte {
+	l := len(dst)
+	dst = growBytes(dst, base64.RawURLEncoding.EncodedLen(len(src)))
+	base64.RawURLEncoding.Encode(dst[l:], src)
+	return dst
 }
 
 // DecodeSegment decodes JWT specific base64url encoding with padding stripped.
 // Returns a new byte slice. Error behaviour: NotValid.
 func DecodeSegment(seg []byte) ([]byte, error) {
-	dbuf := make([]byte, base64.RawURLEncoding.DecodedLen(len(seg)))
-	n, err := base64.RawURLEncoding.Decode(dbuf, seg)
+	return DecodeSegmentTo(make([]byte, 0, base64.RawURLEncoding.DecodedLen(len(seg))), seg)
+}
+
+// DecodeSegmentTo appends the decoded JWT specific base64url encoding with
+// padding stripped of src to dst and returns the extended buffer. It does not
+// allocate if dst has enough capacity. Padding characters and the standard
+// base64 alphabet get rejected like in DecodeSegment. Error behaviour:
+// NotValid.
+func DecodeSegmentTo(dst, src []byte) ([]byte, error) {
+	l := len(dst)
+	dst = growBytes(dst, base64.RawURLEncoding.DecodedLen(len(src)))
+	n, err := base64.RawURLEncoding.Decode(dst[l:], src)
 	if err != nil {
 		return nil, errors.NotValid.New(err, "[csjwt] DecodeSegment")
 	}
-	return dbuf[:n], nil
+	return dst[:l+n], nil
+}
+
+// growBytes extends the length of buf by n bytes.
+func growBytes(buf []byte, n int) []byte {
+	if l := len(buf) + n; l <= cap(buf) {
+		return buf[:l]
+	}
+	nb := make([]byte, len(buf)+n, 2*cap(buf)+n)
+	copy(nb, buf)
+	return nb
 }
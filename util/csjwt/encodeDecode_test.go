@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/util/assert"
 	"github.com/corestoreio/pkg/util/csjwt"
 	"github.com/corestoreio/pkg/util/csjwt/jwtclaim"
@@ -74,6 +75,40 @@ func TestGobEncoding(t *testing.T) {
 	assert.Exactly(t, "2342-234345-234234-23435", haveStoreClaim.ID)
 }
 
+func TestSegmentTo(t *testing.T) {
+	t.Run("encode appends", func(t *testing.T) {
+		dst := csjwt.EncodeSegmentTo([]byte("head."), []byte{0xfb, 0xff, 0xbf})
+		assert.Exactly(t, "head.-_-_", string(dst))
+		assert.Exactly(t, []byte("-_-_"), csjwt.EncodeSegment([]byte{0xfb, 0xff, 0xbf}))
+	})
+	t.Run("decode appends", func(t *testing.T) {
+		dst, err := csjwt.DecodeSegmentTo([]byte("x"), []byte("-_-_"))
+		assert.NoError(t, err)
+		assert.Exactly(t, []byte{'x', 0xfb, 0xff, 0xbf}, dst)
+	})
+	t.Run("decode does not allocate with capacity", func(t *testing.T) {
+		buf := make([]byte, 0, 64)
+		allocs := testing.AllocsPerRun(10, func() {
+			var err error
+			if buf, err = csjwt.DecodeSegmentTo(buf[:0], []byte("Z29waGVy")); err != nil {
+				t.Fatal(err)
+			}
+		})
+		assert.Exactly(t, float64(0), allocs)
+		assert.Exactly(t, "gopher", string(buf))
+	})
+	for _, seg := range []string{"Z29waGVyIQ==", "Z29waGVyIQ=", "-_+/", "+/+/", "a"} {
+		t.Run("invalid "+seg, func(t *testing.T) {
+			dst, err := csjwt.DecodeSegmentTo(make([]byte, 0, 64), []byte(seg))
+			assert.ErrorIsKind(t, errors.NotValid, err)
+			assert.Nil(t, dst)
+			dst, err = csjwt.DecodeSegment([]byte(seg))
+			assert.ErrorIsKind(t, errors.NotValid, err)
+			assert.Nil(t, dst)
+		})
+	}
+}
+
 func BenchmarkTokenDecode(b *testing.B) {
 	testRunner := func(b *testing.B, encDec interface {
 		csjwt.Serializer
@@ -150,3 +185,84 @@ func BenchmarkBase64(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkParse_800B before decoding into pooled buffers:
+// HS256       22722 ns/op    3608 B/op    42 allocs/op
+// HS256Fast   18070 ns/op    3136 B/op    38 allocs/op
+// RS256       62230 ns/op    4856 B/op    47 allocs/op
+// after:
+// HS256       14109 ns/op    3000 B/op    39 allocs/op
+// HS256Fast   17632 ns/op    2528 B/op    35 allocs/op
+// RS256       53516 ns/op    4024 B/op    44 allocs/op
+func BenchmarkParse_800B(b *testing.B) {
+	claims := &csjwt.ClaimsRaw{
+		Audience:  []string{"shop", "api"},
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Issuer:    "https://idp.example.com/auth/realms/shop",
+		Subject:   "4f2d8a9e-1c3b-4e5f-8a7d-9b0c1d2e3f4a",
+	}
+	if err := claims.Set("resource_access", map[string][]string{
+		"account":  {"manage-account", "manage-account-links", "view-profile"},
+		"checkout": {"read-orders", "write-orders", "read-invoices", "write-invoices"},
+		"catalog":  {"read-products", "write-products", "read-prices", "write-prices"},
+	}); err != nil {
+		b.Fatal(err)
+	}
+	for k, v := range map[string]interface{}{
+		"scope":        "openid email profile offline_access",
+		"email":        "gopher.gophersson@example.com",
+		"name":         "Gopher Gophersson",
+		"realm_access": map[string][]string{"roles": {"offline_access", "uma_authorization"}},
+	} {
+		if err := claims.Set(k, v); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	run := func(b *testing.B, m csjwt.Signer, key csjwt.Key) {
+		raw, err := csjwt.NewToken(claims).SignedString(m, key)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if l := len(raw); l < 750 || l > 850 {
+			b.Fatalf("token length %d", l)
+		}
+		vf := csjwt.NewVerification(m)
+		kf := csjwt.NewKeyFunc(m, key)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := vf.Parse(csjwt.NewToken(&csjwt.ClaimsRaw{}), raw, kf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	pw := csjwt.WithPassword([]byte(`csjwt.SigningMethodHS256!`))
+	b.Run("HS256", func(b *testing.B) {
+		run(b, csjwt.NewSigningMethodHS256(), pw)
+	})
+	b.Run("HS256Fast", func(b *testing.B) {
+		hf, err := csjwt.NewSigningMethodHS256Fast(pw)
+		if err != nil {
+			b.Fatal(err)
+		}
+		run(b, hf, csjwt.Key{})
+	})
+	b.Run("RS256", func(b *testing.B) {
+		m := csjwt.NewSigningMethodRS256()
+		raw, err := csjwt.NewToken(claims).SignedString(m, csjwt.WithRSAPrivateKeyFromFile("test/sample_key"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		vf := csjwt.NewVerification(m)
+		kf := csjwt.NewKeyFunc(m, csjwt.WithRSAPublicKeyFromFile("test/sample_key.pub"))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := vf.Parse(csjwt.NewToken(&csjwt.ClaimsRaw{}), raw, kf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
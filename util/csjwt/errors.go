@@ -54,6 +54,10 @@ const (
 	errHmacHashUnavailable  = `[csjwt] HMAC-SHA Hash unavaiable`
 	errHmacSignatureInvalid = `[csjwt] HMAC-SHA Signature invalid`
 
+	errEd25519PublicKeyEmpty  = `[csjwt] Ed25519 Public Key not provided`
+	errEd25519PrivateKeyEmpty = `[csjwt] Ed25519 Private Key not provided`
+	errEd25519Verification    = `[csjwt] Ed25519 Signature invalid`
+
 	errRSAPublicKeyEmpty  = `[csjwt] RSA Public Key not provided`
 	errRSAPrivateKeyEmpty = `[csjwt] RSA Private Key not provided`
 	errRSAHashUnavailable = `[csjwt] RSA Hash unavaiable`
@@ -20,7 +20,6 @@ import (
 	"hash"
 
 	"github.com/corestoreio/errors"
-	"github.com/corestoreio/pkg/util/hashpool"
 	_ "golang.org/x/crypto/blake2b"
 )
 
@@ -28,34 +27,24 @@ import (
 // password and 32-byte checksum. Blake2b uses SIMD optimizations via ASM code.
 // https://blake2.net/
 func NewSigningMethodBlake2b256(key Key) (Signer, error) {
-	if key.Error != nil {
-		return nil, errors.Wrap(key.Error, "[csjwt] NewBlake2b256.key")
+	m, err := newHSFastMethod(Blake2b256, func(password []byte) hash.Hash {
+		return hmac.New(crypto.BLAKE2b_256.New, password)
+	}, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "[csjwt] NewBlake2b256.key")
 	}
-	if len(key.hmacPassword) == 0 {
-		return nil, errors.Empty.Newf(errHmacPasswordEmpty)
-	}
-
-	return &SigningMethodHSFast{
-		Name: Blake2b256,
-		ht: hashpool.New(func() hash.Hash {
-			return hmac.New(crypto.BLAKE2b_256.New, key.hmacPassword)
-		}),
-	}, nil
+	return m, nil
 }
 
 // NewSigningMethodBlake2b512 creates a new HMAC-Blake2b hash with a preset
 // password and 64-byte checksum. Blake2b uses SIMD optimizations via ASM code.
 // https://blake2.net/
 func NewSigningMethodBlake2b512(key Key) (Signer, error) {
-	if key.Error != nil {
-		return nil, errors.Wrap(key.Error, "[csjwt] NewBlake2b512.key")
+	m, err := newHSFastMethod(Blake2b512, func([]byte) hash.Hash {
+		return crypto.BLAKE2b_512.New()
+	}, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "[csjwt] NewBlake2b512.key")
 	}
-	if len(key.hmacPassword) == 0 {
-		return nil, errors.Empty.Newf(errHmacPasswordEmpty)
-	}
-
-	return &SigningMethodHSFast{
-		Name: Blake2b512,
-		ht:   hashpool.New(crypto.BLAKE2b_512.New),
-	}, nil
+	return m, nil
 }
@@ -0,0 +1,110 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"crypto"
+	"sort"
+
+	"github.com/corestoreio/errors"
+)
+
+// SigningMethodHSFastSet routes to one pre-warmed HMAC-SHA signing method per
+// key ID, to rotate the passwords. It signs always with the current key and
+// verifies with the key selected by the kid of the token.
+type SigningMethodHSFastSet struct {
+	Name    string
+	current string
+	methods map[string]Signer
+	// kids sorted, to try the methods in a deterministic order.
+	kids []string
+}
+
+// NewHMACFastSet creates a new pre-warmed HMAC-SHA signing method for each key.
+// Argument alg must be one of HS256, HS384 or HS512. Use together with a
+// Keyfunc which returns WithKeySet(currentKID, keys), or any key, because the
+// kid header selects the method. A token without kid gets verified with at
+// most three keys.
+func NewHMACFastSet(alg, currentKID string, keys map[string]Key) (*SigningMethodHSFastSet, error) {
+	var h crypto.Hash
+	switch alg {
+	case HS256:
+		h = crypto.SHA256
+	case HS384:
+		h = crypto.SHA384
+	case HS512:
+		h = crypto.SHA512
+	default:
+		return nil, errors.NotSupported.Newf("[csjwt] NewHMACFastSet: algorithm %q not supported", alg)
+	}
+	if _, ok := keys[currentKID]; !ok {
+		return nil, errors.NotFound.Newf("[csjwt] NewHMACFastSet: current kid %q not found", currentKID)
+	}
+	m := &SigningMethodHSFastSet{
+		Name:    alg,
+		current: currentKID,
+		methods: make(map[string]Signer, len(keys)),
+		kids:    make([]string, 0, len(keys)),
+	}
+	for kid, key := range keys {
+		s, err := newHSFast(alg, h, key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[csjwt] NewHMACFastSet: key with kid %q", kid)
+		}
+		m.methods[kid] = s
+		m.kids = append(m.kids, kid)
+	}
+	sort.Strings(m.kids)
+	return m, nil
+}
+
+// Alg returns the name of the underlying algorithm.
+func (m *SigningMethodHSFastSet) Alg() string {
+	return m.Name
+}
+
+// KeyID returns the kid of the current key.
+func (m *SigningMethodHSFastSet) KeyID() string {
+	return m.current
+}
+
+// Verify the signature of HSXXX tokens with the method of the key ID. Returns
+// nil if the signature is valid. Error behaviour: NotFound, NotImplemented,
+// WriteFailed, NotValid
+func (m *SigningMethodHSFastSet) Verify(signingString, signature []byte, key Key) error {
+	if key.kid != "" {
+		s, ok := m.methods[key.kid]
+		if !ok {
+			return errors.NotFound.Newf("[csjwt] SigningMethodHSFastSet: kid %q not found", key.kid)
+		}
+		return s.Verify(signingString, signature, key)
+	}
+	if len(m.kids) > maxKeySetCandidates {
+		return errors.NotValid.Newf("[csjwt] SigningMethodHSFastSet: token without kid cannot be verified with %d keys, maximum %d", len(m.kids), maxKeySetCandidates)
+	}
+	var err error
+	for _, kid := range m.kids {
+		if err = m.methods[kid].Verify(signingString, signature, key); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Sign implements the Sign method from SigningMethod interface. It signs always
+// with the current key. Error behaviour: WriteFailed
+func (m *SigningMethodHSFastSet) Sign(signingString []byte, key Key) ([]byte, error) {
+	return m.methods[m.current].Sign(signingString, key)
+}
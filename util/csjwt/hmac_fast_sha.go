@@ -18,6 +18,9 @@ import (
 	"crypto"
 	"crypto/hmac"
 	"hash"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/util/hashpool"
@@ -25,29 +28,68 @@ import (
 
 // SigningMethodHSFast implements the HMAC-SHA family of pre-warmed signing
 // methods. Less allocations, bytes and a little bit faster but maybe the
-// underlying mutex can become the bottleneck.
+// underlying mutex can become the bottleneck. The key can be rotated at runtime
+// with SetKey.
 type SigningMethodHSFast struct {
 	Name string
-	ht   hashpool.Tank
+	// GracePeriod if greater zero, Verify falls back to the previous key for
+	// this duration after a SetKey call. Must be set before calling SetKey.
+	GracePeriod time.Duration
+	newHash     func(key []byte) hash.Hash
+	mu          sync.Mutex   // serializes SetKey
+	gen         atomic.Value // *hsFastGen
 }
 
-func newHSFast(a string, h crypto.Hash, key Key) (Signer, error) {
+// hsFastGen contains the pooled hashes created with the same key. A hash gets
+// always returned to the generation it has been taken from, so hashes of an
+// old key never get reused after a key rotation.
+type hsFastGen struct {
+	ht hashpool.Tank
+	// prev generation used by Verify until prevUntil as Unix nano seconds.
+	prev      *hsFastGen
+	prevUntil int64
+}
+
+func newHSFastGen(newHash func(key []byte) hash.Hash, key Key) (*hsFastGen, error) {
 	if key.Error != nil {
 		return nil, errors.WithStack(key.Error)
 	}
 	if len(key.hmacPassword) == 0 {
 		return nil, errors.Empty.Newf(errHmacPasswordEmpty)
 	}
+	password := key.hmacPassword
+	return &hsFastGen{
+		ht: hashpool.New(func() hash.Hash {
+			return newHash(password)
+		}),
+	}, nil
+}
+
+func newHSFastMethod(name string, newHash func(key []byte) hash.Hash, key Key) (*SigningMethodHSFast, error) {
+	g, err := newHSFastGen(newHash, key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	m := &SigningMethodHSFast{
+		Name:    name,
+		newHash: newHash,
+	}
+	m.gen.Store(g)
+	return m, nil
+}
+
+func newHSFast(a string, h crypto.Hash, key Key) (Signer, error) {
 	// Can we use the specified hashing method?
 	if !h.Available() {
 		return nil, errors.NotImplemented.Newf(errHmacHashUnavailable)
 	}
-	return &SigningMethodHSFast{
-		Name: a,
-		ht: hashpool.New(func() hash.Hash {
-			return hmac.New(h.New, key.hmacPassword)
-		}),
-	}, nil
+	m, err := newHSFastMethod(a, func(password []byte) hash.Hash {
+		return hmac.New(h.New, password)
+	}, key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return m, nil
 }
 
 // NewSigningMethodHS256Fast creates a new HMAC-SHA hash with a preset password
@@ -72,26 +114,62 @@ func (m *SigningMethodHSFast) Alg() string {
 	return m.Name
 }
 
+func (m *SigningMethodHSFast) loadGen() *hsFastGen {
+	return m.gen.Load().(*hsFastGen)
+}
+
+// SetKey rotates the key at runtime without rebuilding the method. Concurrent
+// Sign and Verify calls use either the old or the new key. With a GracePeriod
+// Verify accepts tokens signed with the previous key until the period has
+// elapsed. Error behaviour: Empty.
+func (m *SigningMethodHSFast) SetKey(key Key) error {
+	g, err := newHSFastGen(m.newHash, key)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GracePeriod > 0 {
+		// Only one previous key, the chain must not grow with each rotation.
+		g.prev = &hsFastGen{ht: m.loadGen().ht}
+		g.prevUntil = TimeFunc().Add(m.GracePeriod).UnixNano()
+	}
+	m.gen.Store(g)
+	return nil
+}
+
 // Verify the signature of HSXXX tokens.  Returns nil if the signature is valid.
 // Error behaviour: NotImplemented, WriteFailed, NotValid
 func (m *SigningMethodHSFast) Verify(signingString, signature []byte, _ Key) error {
 	// Decode signature, for comparison
-	sig, err := DecodeSegment(signature)
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+	sig, err := DecodeSegmentTo(buf.Bytes(), signature)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
+	g := m.loadGen()
+	err = g.verify(signingString, sig)
+	if errors.NotValid.Match(err) && g.prev != nil && TimeFunc().UnixNano() < g.prevUntil {
+		err = g.prev.verify(signingString, sig)
+	}
+	return err
+}
+
+func (g *hsFastGen) verify(signingString, sig []byte) error {
 	// This signing method is symmetric, so we validate the signature by
 	// reproducing the signature from the signing string and key, then comparing
 	// that against the provided signature.
-	hasher := m.ht.Get()
-	defer m.ht.Put(hasher)
+	hasher := g.ht.Get()
+	defer g.ht.Put(hasher)
 
 	if _, err := hasher.Write(signingString); err != nil {
 		return errors.WriteFailed.New(err, "[csjwt] SigningMethodHMACFast.Verify.hasher.Write")
 	}
 
-	if !hmac.Equal(sig, hasher.Sum(nil)) {
+	var sum [64]byte
+	if !hmac.Equal(sig, hasher.Sum(sum[:0])) {
 		return errors.NotValid.Newf(errHmacSignatureInvalid)
 	}
 
@@ -102,12 +180,14 @@ func (m *SigningMethodHSFast) Verify(signingString, signature []byte, _ Key) err
 // Sign implements the Sign method from SigningMethod interface.
 // Error behaviour: WriteFailed
 func (m *SigningMethodHSFast) Sign(signingString []byte, _ Key) ([]byte, error) {
-	hasher := m.ht.Get()
-	defer m.ht.Put(hasher)
+	g := m.loadGen()
+	hasher := g.ht.Get()
+	defer g.ht.Put(hasher)
 
 	if _, err := hasher.Write(signingString); err != nil {
 		return nil, errors.WriteFailed.New(err, "[csjwt] SigningMethodHMACFast.Sign.hasher.Write")
 	}
 
-	return EncodeSegment(hasher.Sum(nil)), nil
+	var sum [64]byte
+	return EncodeSegment(hasher.Sum(sum[:0])), nil
 }
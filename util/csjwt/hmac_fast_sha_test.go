@@ -16,8 +16,11 @@ package csjwt_test
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/util/assert"
@@ -180,3 +183,112 @@ func TestNewBlake2b512(t *testing.T) {
 	assert.Nil(t, s)
 	assert.True(t, errors.Empty.Match(err))
 }
+
+func TestSigningMethodHSFast_SetKey(t *testing.T) {
+	keyA := csjwt.WithPassword([]byte(`Password A`))
+	keyB := csjwt.WithPassword([]byte(`Password B`))
+	signing := []byte(`eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJnb3BoZXIifQ`)
+
+	t.Run("without grace period", func(t *testing.T) {
+		s, err := csjwt.NewSigningMethodHS256Fast(keyA)
+		assert.NoError(t, err)
+		hf := s.(*csjwt.SigningMethodHSFast)
+		sigA, err := hf.Sign(signing, csjwt.Key{})
+		assert.NoError(t, err)
+
+		assert.NoError(t, hf.SetKey(keyB))
+		assert.ErrorIsKind(t, errors.NotValid, hf.Verify(signing, sigA, csjwt.Key{}))
+		sigB, err := hf.Sign(signing, csjwt.Key{})
+		assert.NoError(t, err)
+		assert.NoError(t, csjwt.NewSigningMethodHS256().Verify(signing, sigB, keyB))
+
+		assert.ErrorIsKind(t, errors.Empty, hf.SetKey(csjwt.Key{}))
+		assert.NoError(t, hf.Verify(signing, sigB, csjwt.Key{}), "failed SetKey must keep the key")
+	})
+
+	t.Run("grace period", func(t *testing.T) {
+		s, err := csjwt.NewSigningMethodHS256Fast(keyA)
+		assert.NoError(t, err)
+		hf := s.(*csjwt.SigningMethodHSFast)
+		hf.GracePeriod = 50 * time.Millisecond
+		sigA, err := hf.Sign(signing, csjwt.Key{})
+		assert.NoError(t, err)
+
+		assert.NoError(t, hf.SetKey(keyB))
+		assert.NoError(t, hf.Verify(signing, sigA, csjwt.Key{}))
+		time.Sleep(60 * time.Millisecond)
+		assert.ErrorIsKind(t, errors.NotValid, hf.Verify(signing, sigA, csjwt.Key{}))
+
+		// Only the direct predecessor gets accepted.
+		keyC := csjwt.WithPassword([]byte(`Password C`))
+		assert.NoError(t, hf.SetKey(keyC))
+		assert.NoError(t, hf.SetKey(keyA))
+		sigB, err := csjwt.NewSigningMethodHS256().Sign(signing, keyB)
+		assert.NoError(t, err)
+		assert.ErrorIsKind(t, errors.NotValid, hf.Verify(signing, sigB, csjwt.Key{}))
+	})
+
+	t.Run("concurrent rotation", func(t *testing.T) {
+		s, err := csjwt.NewSigningMethodHS256Fast(keyA)
+		assert.NoError(t, err)
+		hf := s.(*csjwt.SigningMethodHSFast)
+		hf.GracePeriod = time.Minute
+
+		hs256 := csjwt.NewSigningMethodHS256()
+		wantA, err := hs256.Sign(signing, keyA)
+		assert.NoError(t, err)
+		wantB, err := hs256.Sign(signing, keyB)
+		assert.NoError(t, err)
+
+		// The test mutex ensures that key A and B alternate, so the previous
+		// key always verifies a signature created before a rotation.
+		var rotMu sync.Mutex
+		rotations := 0
+		rotate := func() error {
+			rotMu.Lock()
+			defer rotMu.Unlock()
+			key := keyB
+			if rotations%2 == 1 {
+				key = keyA
+			}
+			rotations++
+			return hf.SetKey(key)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 50)
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 40; j++ {
+					if j%10 == 0 {
+						if err := rotate(); err != nil {
+							errs <- err
+							return
+						}
+					}
+					sig, err := hf.Sign(signing, csjwt.Key{})
+					if err != nil {
+						errs <- err
+						return
+					}
+					if !bytes.Equal(sig, wantA) && !bytes.Equal(sig, wantB) {
+						errs <- fmt.Errorf("torn signature %q", sig)
+						return
+					}
+					if err := hf.Verify(signing, sig, csjwt.Key{}); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Exactly(t, 200, rotations)
+		close(errs)
+		for err := range errs {
+			t.Error(err)
+		}
+	})
+}
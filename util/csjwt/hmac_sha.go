@@ -54,7 +54,9 @@ func (m *SigningMethodHMAC) Verify(signingString, signature []byte, key Key) err
 	}
 
 	// Decode signature, for comparison
-	sig, err := DecodeSegment(signature)
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+	sig, err := DecodeSegmentTo(buf.Bytes(), signature)
 	if err != nil {
 		return errors.Wrap(err, "[csjwt] SigningMethodHMAC.Verify.DecodeSegment")
 	}
@@ -72,7 +74,8 @@ func (m *SigningMethodHMAC) Verify(signingString, signature []byte, key Key) err
 		return errors.WriteFailed.New(err, "[csjwt] SigningMethodHMAC.Verify.hasher.Write")
 	}
 
-	if !hmac.Equal(sig, hasher.Sum(nil)) {
+	var sum [64]byte
+	if !hmac.Equal(sig, hasher.Sum(sum[:0])) {
 		return errors.NotValid.Newf(errHmacSignatureInvalid)
 	}
 
@@ -100,5 +103,6 @@ func (m *SigningMethodHMAC) Sign(signingString []byte, key Key) ([]byte, error)
 		return nil, errors.WriteFailed.New(err, "[csjwt] SigningMethodHMAC.Sign.hasher.Write")
 	}
 
-	return EncodeSegment(hasher.Sum(nil)), nil
+	var sum [64]byte
+	return EncodeSegment(hasher.Sum(sum[:0])), nil
 }
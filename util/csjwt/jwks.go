@@ -0,0 +1,367 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"golang.org/x/crypto/ed25519"
+)
+
+// headerKID defines the key ID header parameter.
+const headerKID = "kid"
+
+// jwksMaxBodySize limits the size of a fetched JSON Web Key Set.
+const jwksMaxBodySize = 1 << 20
+
+// JWKSOptions configures NewKeyFuncJWKS.
+type JWKSOptions struct {
+	// HTTPClient fetches the key set. Default: a client with a timeout of 10s.
+	HTTPClient *http.Client
+	// TTL defines how long a fetched key set counts as current. After the TTL
+	// the key set gets refreshed in the background while the previous keys
+	// are still in use. Default 1h.
+	TTL time.Duration
+	// MinRefreshInterval limits how often a token with an unknown kid or a
+	// failed fetch can trigger another request to the issuer. Default 10s.
+	MinRefreshInterval time.Duration
+	// ErrorHandler receives the errors of the background refresh. Optional.
+	ErrorHandler func(error)
+}
+
+// jwk represents one JSON Web Key, RFC 7517, RFC 7518 and RFC 8037.
+type jwk struct {
+	Kty    string   `json:"kty"`
+	Kid    string   `json:"kid"`
+	Use    string   `json:"use"`
+	KeyOps []string `json:"key_ops"`
+	Alg    string   `json:"alg"`
+	Crv    string   `json:"crv"`
+	N      string   `json:"n"`
+	E      string   `json:"e"`
+	X      string   `json:"x"`
+	Y      string   `json:"y"`
+}
+
+// jwkKey contains a parsed public key and the properties to check against the
+// token header.
+type jwkKey struct {
+	kty string
+	crv string
+	alg string
+	key Key
+}
+
+type jwksCache struct {
+	url string
+	o   JWKSOptions
+	// refreshing gets set atomically to 1 while the background refresh runs.
+	refreshing int32
+	// fetchMu serializes the requests to the issuer.
+	fetchMu sync.Mutex
+
+	mu          sync.RWMutex
+	keys        map[string]jwkKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	lastErr     error
+}
+
+// NewKeyFuncJWKS creates a Keyfunc which verifies tokens with the public keys
+// of a JSON Web Key Set, as published by OpenID Connect providers like
+// Keycloak or Auth0. The key gets selected by the kid header of the token. A
+// token without kid can only be verified when the set contains exactly one
+// key. RSA, EC and OKP (Ed25519) keys are supported; keys whose "use" or
+// "key_ops" do not allow signature verification get ignored. The key set gets
+// fetched with the first token, cached for the TTL and refreshed in the
+// background. An unknown kid triggers one immediate refresh, limited by
+// MinRefreshInterval. The header of the token must support the kid via Get,
+// like jwtclaim.HeadSegments. Error behaviour: ConnectionFailed, NotFound,
+// NotSupported, NotValid, Empty.
+func NewKeyFuncJWKS(url string, o JWKSOptions) Keyfunc {
+	if o.HTTPClient == nil {
+		o.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if o.TTL <= 0 {
+		o.TTL = time.Hour
+	}
+	if o.MinRefreshInterval <= 0 {
+		o.MinRefreshInterval = 10 * time.Second
+	}
+	jc := &jwksCache{url: url, o: o}
+	return jc.keyFunc
+}
+
+func (jc *jwksCache) keyFunc(t *Token) (Key, error) {
+	// A header without support for the kid behaves like a missing kid.
+	kid, _ := t.Header.Get(headerKID)
+
+	jc.mu.RLock()
+	keys, fetchedAt, attemptedAt, lastErr := jc.keys, jc.fetchedAt, jc.attemptedAt, jc.lastErr
+	jc.mu.RUnlock()
+
+	canRefresh := time.Since(attemptedAt) >= jc.o.MinRefreshInterval
+	switch {
+	case keys == nil && !canRefresh:
+		return Key{}, errors.WithStack(lastErr)
+	case keys == nil:
+		var err error
+		if keys, err = jc.refresh(attemptedAt); err != nil {
+			return Key{}, errors.WithStack(err)
+		}
+		canRefresh = false
+	case time.Since(fetchedAt) > jc.o.TTL && atomic.CompareAndSwapInt32(&jc.refreshing, 0, 1):
+		go func() {
+			defer atomic.StoreInt32(&jc.refreshing, 0)
+			if _, err := jc.refresh(attemptedAt); err != nil && jc.o.ErrorHandler != nil {
+				jc.o.ErrorHandler(err)
+			}
+		}()
+	}
+
+	jk, ok := selectJWK(keys, kid)
+	if !ok && canRefresh {
+		// The issuer might have rotated its keys.
+		var err error
+		if keys, err = jc.refresh(attemptedAt); err != nil {
+			return Key{}, errors.WithStack(err)
+		}
+		jk, ok = selectJWK(keys, kid)
+	}
+	if !ok {
+		return Key{}, errors.NotFound.Newf("[csjwt] JWKS %q does not contain a key for kid %q", jc.url, kid)
+	}
+	if err := jk.checkAlg(t.Alg()); err != nil {
+		return Key{}, errors.WithStack(err)
+	}
+	return jk.key, nil
+}
+
+// refresh fetches the key set, unless another goroutine has already tried it
+// after attemptedAt.
+func (jc *jwksCache) refresh(attemptedAt time.Time) (map[string]jwkKey, error) {
+	jc.fetchMu.Lock()
+	defer jc.fetchMu.Unlock()
+
+	jc.mu.RLock()
+	if !jc.attemptedAt.Equal(attemptedAt) {
+		keys, err := jc.keys, jc.lastErr
+		jc.mu.RUnlock()
+		if keys == nil {
+			return nil, errors.WithStack(err)
+		}
+		return keys, nil
+	}
+	jc.mu.RUnlock()
+
+	keys, err := jc.fetch()
+
+	jc.mu.Lock()
+	defer jc.mu.Unlock()
+	jc.attemptedAt = time.Now()
+	jc.lastErr = err
+	if err != nil {
+		// Keep the previous keys, if any, until the issuer is back.
+		return jc.keys, errors.WithStack(err)
+	}
+	jc.keys = keys
+	jc.fetchedAt = jc.attemptedAt
+	return keys, nil
+}
+
+func (jc *jwksCache) fetch() (map[string]jwkKey, error) {
+	req, err := http.NewRequest(http.MethodGet, jc.url, nil)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[csjwt] Invalid JWKS URL %q", jc.url)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := jc.o.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.ConnectionFailed.New(err, "[csjwt] Failed to fetch JWKS %q", jc.url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.NotValid.Newf("[csjwt] Failed to fetch JWKS %q: status code %d", jc.url, resp.StatusCode)
+	}
+	keys, err := parseJWKS(io.LimitReader(resp.Body, jwksMaxBodySize))
+	return keys, errors.Wrapf(err, "[csjwt] Failed to parse JWKS %q", jc.url)
+}
+
+// parseJWKS decodes a JSON Web Key Set and returns the keys usable for the
+// signature verification by their kid.
+func parseJWKS(r io.Reader) (map[string]jwkKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(r).Decode(&set); err != nil {
+		return nil, errors.NotValid.New(err, "[csjwt] Malformed JWKS")
+	}
+	keys := make(map[string]jwkKey, len(set.Keys))
+	for _, j := range set.Keys {
+		if !j.canVerify() {
+			continue
+		}
+		k, err := j.publicKey()
+		if errors.NotSupported.Match(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "[csjwt] Invalid JWK with kid %q", j.Kid)
+		}
+		keys[j.Kid] = jwkKey{kty: j.Kty, crv: j.Crv, alg: j.Alg, key: k}
+	}
+	if len(keys) == 0 {
+		return nil, errors.Empty.Newf("[csjwt] JWKS does not contain any key for the signature verification")
+	}
+	return keys, nil
+}
+
+// canVerify reports whether the "use" and "key_ops" parameters allow the
+// signature verification.
+func (j jwk) canVerify() bool {
+	if j.Use != "" && j.Use != "sig" {
+		return false
+	}
+	if len(j.KeyOps) == 0 {
+		return true
+	}
+	for _, op := range j.KeyOps {
+		if op == "verify" {
+			return true
+		}
+	}
+	return false
+}
+
+// publicKey parses the key material. Unsupported key types or curves return
+// a NotSupported error.
+func (j jwk) publicKey() (Key, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(j.N)
+		if err != nil {
+			return Key{}, errors.WithStack(err)
+		}
+		e, err := decodeJWKInt(j.E)
+		if err != nil {
+			return Key{}, errors.WithStack(err)
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 || n.Sign() <= 0 {
+			return Key{}, errors.NotValid.Newf("[csjwt] Invalid RSA public key")
+		}
+		return WithRSAPublicKey(&rsa.PublicKey{N: n, E: int(e.Int64())}), nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return Key{}, errors.NotSupported.Newf("[csjwt] Unsupported EC curve %q", j.Crv)
+		}
+		x, err := decodeJWKInt(j.X)
+		if err != nil {
+			return Key{}, errors.WithStack(err)
+		}
+		y, err := decodeJWKInt(j.Y)
+		if err != nil {
+			return Key{}, errors.WithStack(err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return Key{}, errors.NotValid.Newf("[csjwt] EC point is not on curve %q", j.Crv)
+		}
+		return WithECPublicKey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y}), nil
+
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return Key{}, errors.NotSupported.Newf("[csjwt] Unsupported OKP curve %q", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return Key{}, errors.NotValid.New(err, "[csjwt] Invalid base64url encoding")
+		}
+		k := WithEd25519PublicKey(ed25519.PublicKey(x))
+		return k, errors.WithStack(k.Error)
+	}
+	return Key{}, errors.NotSupported.Newf("[csjwt] Unsupported key type %q", j.Kty)
+}
+
+func decodeJWKInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[csjwt] Invalid base64url encoding")
+	}
+	if len(b) == 0 {
+		return nil, errors.Empty.Newf("[csjwt] Empty JWK parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// selectJWK returns the key by kid. Without a kid the key gets only returned if
+// it is the only one.
+func selectJWK(keys map[string]jwkKey, kid string) (jwkKey, bool) {
+	if kid != "" {
+		jk, ok := keys[kid]
+		return jk, ok
+	}
+	if len(keys) == 1 {
+		for _, jk := range keys {
+			return jk, true
+		}
+	}
+	return jwkKey{}, false
+}
+
+// checkAlg prevents algorithm confusion, for example verifying an HS256 token
+// with the public RSA key as HMAC secret.
+func (jk jwkKey) checkAlg(alg string) error {
+	if jk.alg != "" && jk.alg != alg {
+		return errors.NotValid.Newf("[csjwt] Token algorithm %q does not match JWK algorithm %q", alg, jk.alg)
+	}
+	var kty, crv string
+	switch alg {
+	case RS256, RS384, RS512, PS256, PS384, PS512:
+		kty = "RSA"
+	case ES256:
+		kty, crv = "EC", "P-256"
+	case ES384:
+		kty, crv = "EC", "P-384"
+	case ES512:
+		kty, crv = "EC", "P-521"
+	case EdDSA:
+		kty, crv = "OKP", "Ed25519"
+	default:
+		return errors.NotSupported.Newf("[csjwt] Token algorithm %q not supported with JWKS", alg)
+	}
+	if jk.kty != kty || (crv != "" && jk.crv != crv) {
+		return errors.NotValid.Newf("[csjwt] Token algorithm %q does not match JWK type %q with curve %q", alg, jk.kty, jk.crv)
+	}
+	return nil
+}
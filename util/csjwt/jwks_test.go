@@ -0,0 +1,225 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/csjwt"
+	"github.com/corestoreio/pkg/util/csjwt/jwtclaim"
+	"golang.org/x/crypto/ed25519"
+)
+
+type jwksServer struct {
+	requests int32
+	mu       sync.Mutex
+	status   int
+	keys     []map[string]interface{}
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.requests, 1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+}
+
+func (s *jwksServer) setKeys(status int, keys ...map[string]interface{}) {
+	s.mu.Lock()
+	s.status = status
+	s.keys = keys
+	s.mu.Unlock()
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func rsaJWK(kid string, pk *rsa.PrivateKey) map[string]interface{} {
+	return map[string]interface{}{
+		"kty": "RSA", "kid": kid, "use": "sig",
+		"n": b64(pk.N.Bytes()), "e": b64(big.NewInt(int64(pk.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, pk *ecdsa.PrivateKey) map[string]interface{} {
+	return map[string]interface{}{
+		"kty": "EC", "kid": kid, "crv": pk.Curve.Params().Name,
+		"x": b64(pk.X.Bytes()), "y": b64(pk.Y.Bytes()),
+	}
+}
+
+func okpJWK(kid string, pk ed25519.PrivateKey) map[string]interface{} {
+	return map[string]interface{}{
+		"kty": "OKP", "kid": kid, "crv": "Ed25519", "key_ops": []string{"verify"},
+		"x": b64(pk.Public().(ed25519.PublicKey)),
+	}
+}
+
+func signWithKID(t *testing.T, kid string, m csjwt.Signer, key csjwt.Key) []byte {
+	hs := jwtclaim.NewHeadSegments()
+	hs.KID = kid
+	tk := csjwt.NewToken(&jwtclaim.Standard{Subject: "gopher", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	tk.Header = hs
+	raw, err := tk.SignedString(m, key)
+	assert.NoError(t, err)
+	return raw
+}
+
+func verifyJWKS(kf csjwt.Keyfunc, raw []byte) error {
+	tk := csjwt.NewToken(&jwtclaim.Standard{})
+	tk.Header = jwtclaim.NewHeadSegments()
+	vf := csjwt.NewVerification(csjwt.NewSigningMethodRS256(), csjwt.NewSigningMethodES256(), csjwt.NewSigningMethodEdDSA())
+	return vf.Parse(tk, raw, kf)
+}
+
+func TestNewKeyFuncJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	rsaToken := signWithKID(t, "rsa-1", csjwt.NewSigningMethodRS256(), csjwt.WithRSAPrivateKey(rsaKey))
+	ecToken := signWithKID(t, "ec-1", csjwt.NewSigningMethodES256(), csjwt.WithECPrivateKey(ecKey))
+	edToken := signWithKID(t, "ed-1", csjwt.NewSigningMethodEdDSA(), csjwt.WithEd25519PrivateKey(edKey))
+
+	t.Run("select by kid and rotate", func(t *testing.T) {
+		srv := &jwksServer{}
+		encKey := rsaJWK("rsa-enc", rsaKey)
+		encKey["use"] = "enc"
+		srv.setKeys(0, rsaJWK("rsa-1", rsaKey), ecJWK("ec-1", ecKey), encKey,
+			map[string]interface{}{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"})
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+
+		kf := csjwt.NewKeyFuncJWKS(ts.URL, csjwt.JWKSOptions{
+			HTTPClient:         ts.Client(),
+			MinRefreshInterval: time.Millisecond,
+		})
+		assert.NoError(t, verifyJWKS(kf, rsaToken))
+		assert.NoError(t, verifyJWKS(kf, ecToken))
+		assert.Exactly(t, int32(1), atomic.LoadInt32(&srv.requests), "key set must be cached")
+
+		// The issuer rotates the RSA key to an Ed25519 key.
+		srv.setKeys(0, okpJWK("ed-1", edKey), ecJWK("ec-1", ecKey))
+		time.Sleep(2 * time.Millisecond)
+		assert.NoError(t, verifyJWKS(kf, edToken))
+		assert.Exactly(t, int32(2), atomic.LoadInt32(&srv.requests), "unknown kid forces one refresh")
+		assert.NoError(t, verifyJWKS(kf, ecToken))
+		assert.Exactly(t, int32(2), atomic.LoadInt32(&srv.requests))
+
+		time.Sleep(2 * time.Millisecond)
+		_, err := kf(&csjwt.Token{Header: &jwtclaim.HeadSegments{Algorithm: csjwt.RS256, KID: "rsa-1"}})
+		assert.ErrorIsKind(t, errors.NotFound, err)
+		assert.Exactly(t, int32(3), atomic.LoadInt32(&srv.requests))
+		_, err = kf(&csjwt.Token{Header: &jwtclaim.HeadSegments{Algorithm: csjwt.RS256, KID: "rsa-enc"}})
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+
+	t.Run("background refresh after TTL", func(t *testing.T) {
+		srv := &jwksServer{}
+		srv.setKeys(0, ecJWK("ec-1", ecKey))
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+
+		kf := csjwt.NewKeyFuncJWKS(ts.URL, csjwt.JWKSOptions{
+			HTTPClient:         ts.Client(),
+			TTL:                20 * time.Millisecond,
+			MinRefreshInterval: time.Hour, // no forced refresh
+		})
+		assert.NoError(t, verifyJWKS(kf, ecToken))
+		srv.setKeys(0, ecJWK("ec-1", ecKey), okpJWK("ed-1", edKey))
+		assert.Error(t, verifyJWKS(kf, edToken))
+
+		time.Sleep(30 * time.Millisecond)
+		assert.NoError(t, verifyJWKS(kf, ecToken), "stale keys must be used during the refresh")
+		err := verifyJWKS(kf, edToken)
+		for i := 0; i < 100 && err != nil; i++ {
+			time.Sleep(10 * time.Millisecond)
+			err = verifyJWKS(kf, edToken)
+		}
+		assert.NoError(t, err)
+		assert.Exactly(t, int32(2), atomic.LoadInt32(&srv.requests))
+	})
+
+	t.Run("algorithm mismatch", func(t *testing.T) {
+		srv := &jwksServer{}
+		rsaPinned := rsaJWK("rsa-pinned", rsaKey)
+		rsaPinned["alg"] = csjwt.RS512
+		srv.setKeys(0, rsaJWK("rsa-1", rsaKey), ecJWK("ec-1", ecKey), rsaPinned)
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		kf := csjwt.NewKeyFuncJWKS(ts.URL, csjwt.JWKSOptions{HTTPClient: ts.Client()})
+
+		tests := []struct {
+			alg, kid string
+			kind     errors.Kind
+		}{
+			{csjwt.HS256, "rsa-1", errors.NotSupported},
+			{csjwt.ES256, "rsa-1", errors.NotValid},
+			{csjwt.ES384, "ec-1", errors.NotValid},
+			{csjwt.RS256, "ec-1", errors.NotValid},
+			{csjwt.RS256, "rsa-pinned", errors.NotValid},
+			{csjwt.RS256, "", errors.NotFound}, // no kid and more than one key
+		}
+		for i, test := range tests {
+			_, err := kf(&csjwt.Token{Header: &jwtclaim.HeadSegments{Algorithm: test.alg, KID: test.kid}})
+			assert.ErrorIsKind(t, test.kind, err, "Index %d", i)
+		}
+		k, err := kf(&csjwt.Token{Header: &jwtclaim.HeadSegments{Algorithm: csjwt.PS256, KID: "rsa-1"}})
+		assert.NoError(t, err)
+		assert.Exactly(t, csjwt.RS, k.Algorithm())
+	})
+
+	t.Run("fetch errors", func(t *testing.T) {
+		srv := &jwksServer{}
+		srv.setKeys(http.StatusInternalServerError)
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		kf := csjwt.NewKeyFuncJWKS(ts.URL, csjwt.JWKSOptions{HTTPClient: ts.Client()})
+
+		_, err := kf(&csjwt.Token{Header: &jwtclaim.HeadSegments{Algorithm: csjwt.ES256, KID: "ec-1"}})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		// Rate limited, the issuer does not get hammered.
+		srv.setKeys(0, ecJWK("ec-1", ecKey))
+		_, err = kf(&csjwt.Token{Header: &jwtclaim.HeadSegments{Algorithm: csjwt.ES256, KID: "ec-1"}})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.Exactly(t, int32(1), atomic.LoadInt32(&srv.requests))
+
+		srv.setKeys(0, map[string]interface{}{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": "AQ", "y": "AQ"})
+		kf = csjwt.NewKeyFuncJWKS(ts.URL, csjwt.JWKSOptions{HTTPClient: ts.Client()})
+		_, err = kf(&csjwt.Token{Header: &jwtclaim.HeadSegments{Algorithm: csjwt.ES256, KID: "ec-1"}})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
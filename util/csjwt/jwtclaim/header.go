@@ -10,6 +10,7 @@ import "github.com/corestoreio/errors"
 const (
 	HeaderAlg = "alg"
 	HeaderTyp = "typ"
+	HeaderKID = "kid"
 )
 
 // ContentTypeJWT defines the content type of a token. At the moment only JWT is
@@ -87,6 +88,8 @@ func (s *HeadSegments) Set(key, value string) (err error) {
 		s.Algorithm = value
 	case HeaderTyp:
 		s.Type = value
+	case HeaderKID:
+		s.KID = value
 	default:
 		return errors.NotSupported.Newf(errHeaderKeyNotSupported, key)
 	}
@@ -101,6 +104,8 @@ func (s *HeadSegments) Get(key string) (value string, err error) {
 		return s.Algorithm, nil
 	case HeaderTyp:
 		return s.Type, nil
+	case HeaderKID:
+		return s.KID, nil
 	}
 	return "", errors.NotSupported.Newf(errHeaderKeyNotSupported, key)
 }
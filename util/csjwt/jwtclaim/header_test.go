@@ -62,6 +62,7 @@ func TestHeadSegmentsGetSet(t *testing.T) {
 	}{
 		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderAlg, "", errors.NoKind, errors.NoKind},
 		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderTyp, "Go", errors.NoKind, errors.NoKind},
+		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderKID, "key-2019", errors.NoKind, errors.NoKind},
 		{&jwtclaim.HeadSegments{}, "ext", "Test", errors.NotSupported, errors.NotSupported},
 	}
 	for i, test := range tests {
@@ -0,0 +1,160 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwthttp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/csjwt"
+	"github.com/corestoreio/pkg/util/csjwt/jwtclaim"
+)
+
+// Extractor returns the raw token of a request. An empty token without an
+// error means that the token has not been found.
+type Extractor func(r *http.Request) (string, error)
+
+// ExtractBearer extracts the token from the bearer Authorization header. Other
+// authorization schemes get skipped and whitespaces get trimmed. Several
+// Authorization headers with different bearer tokens return a NotValid error.
+func ExtractBearer() Extractor {
+	return func(r *http.Request) (string, error) {
+		var token string
+		for _, ah := range r.Header[HTTPHeaderAuthorization] {
+			ah = strings.TrimSpace(ah)
+			if !csjwt.StartsWithBearer([]byte(ah)) {
+				continue
+			}
+			t := strings.TrimSpace(ah[len("bearer "):])
+			if token != "" && t != token {
+				return "", errors.NotValid.Newf("[jwthttp] Request contains several bearer tokens")
+			}
+			token = t
+		}
+		return token, nil
+	}
+}
+
+// ExtractCookie extracts the token from the cookie name.
+func ExtractCookie(name string) Extractor {
+	return func(r *http.Request) (string, error) {
+		c, err := r.Cookie(name)
+		if err != nil {
+			return "", nil // http.ErrNoCookie
+		}
+		return strings.TrimSpace(c.Value), nil
+	}
+}
+
+// ExtractQuery extracts the token from the URL query parameter name.
+func ExtractQuery(name string) Extractor {
+	return func(r *http.Request) (string, error) {
+		return strings.TrimSpace(r.URL.Query().Get(name)), nil
+	}
+}
+
+// Revoker checks if a token has been revoked, for example
+// csjwt.BlacklistObjcache.
+type Revoker interface {
+	IsRevoked(ctx context.Context, tk *csjwt.Token) (bool, error)
+}
+
+// MiddlewareOptions configures NewMiddleware.
+type MiddlewareOptions struct {
+	// KeyFunc returns the key to verify the token. Required.
+	KeyFunc csjwt.Keyfunc
+	// Extractors get tried in order until one returns a token. Default
+	// ExtractBearer.
+	Extractors []Extractor
+	// NewToken creates the template token to parse into. Default a token with
+	// jwtclaim.Map claims.
+	NewToken func() *csjwt.Token
+	// Revoker if set rejects revoked tokens. An error of the Revoker rejects
+	// the request.
+	Revoker Revoker
+	// Optional passes requests without a token or with an invalid token to the
+	// next handler. The context contains then no token. Use for routes
+	// with optional authentication.
+	Optional bool
+	// ErrorHandler writes the response for a rejected request. Default: Status
+	// 401 with a WWW-Authenticate header.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, _ error) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+// NewMiddleware creates a middleware which extracts and verifies the token and
+// adds it to the request context. Use csjwt.FromContext to retrieve the token.
+func NewMiddleware(vf *csjwt.Verification, o MiddlewareOptions) func(http.Handler) http.Handler {
+	if len(o.Extractors) == 0 {
+		o.Extractors = []Extractor{ExtractBearer()}
+	}
+	if o.NewToken == nil {
+		o.NewToken = func() *csjwt.Token { return csjwt.NewToken(&jwtclaim.Map{}) }
+	}
+	if o.ErrorHandler == nil {
+		o.ErrorHandler = defaultErrorHandler
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tk, err := o.verify(vf, r)
+			switch {
+			case err == nil:
+				next.ServeHTTP(w, r.WithContext(csjwt.WithContextToken(r.Context(), tk)))
+			case o.Optional:
+				next.ServeHTTP(w, r)
+			default:
+				o.ErrorHandler(w, r, err)
+			}
+		})
+	}
+}
+
+func (o MiddlewareOptions) verify(vf *csjwt.Verification, r *http.Request) (*csjwt.Token, error) {
+	var raw string
+	for _, ex := range o.Extractors {
+		var err error
+		if raw, err = ex(r); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if raw != "" {
+			break
+		}
+	}
+	if raw == "" {
+		return nil, errors.NotFound.Newf(errTokenNotInRequest)
+	}
+
+	tk := o.NewToken()
+	if err := vf.Parse(tk, []byte(raw), o.KeyFunc); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if o.Revoker != nil {
+		revoked, err := o.Revoker.IsRevoked(r.Context(), tk)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if revoked {
+			return nil, errors.Revoked.Newf("[jwthttp] Token has been revoked")
+		}
+	}
+	return tk, nil
+}
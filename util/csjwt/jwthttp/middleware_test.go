@@ -0,0 +1,164 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwthttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/objcache"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/csjwt"
+	"github.com/corestoreio/pkg/util/csjwt/jwtclaim"
+	"github.com/corestoreio/pkg/util/csjwt/jwthttp"
+)
+
+func TestNewMiddleware(t *testing.T) {
+	hs256 := csjwt.NewSigningMethodHS256()
+	key := csjwt.WithPassword([]byte(`csjwt.SigningMethodHS256!`))
+	sign := func(c jwtclaim.Map) string {
+		raw, err := csjwt.NewToken(c).SignedString(hs256, key)
+		assert.NoError(t, err)
+		return string(raw)
+	}
+	valid := sign(jwtclaim.Map{"sub": "gopher", "jti": "t1", "exp": time.Now().Add(time.Hour).Unix()})
+	revoked := sign(jwtclaim.Map{"sub": "gopher", "jti": "t2", "exp": time.Now().Add(time.Hour).Unix()})
+	expired := sign(jwtclaim.Map{"sub": "gopher", "exp": time.Now().Add(-time.Hour).Unix()})
+
+	storage, err := objcache.NewCacheSimpleInmemory()
+	assert.NoError(t, err)
+	bl, err := csjwt.NewBlacklistObjcache(storage, "bl_", nil)
+	assert.NoError(t, err)
+	revokedToken := csjwt.NewToken(&jwtclaim.Map{})
+	assert.NoError(t, csjwt.NewVerification(hs256).Parse(revokedToken, []byte(revoked), csjwt.NewKeyFunc(hs256, key)))
+	assert.NoError(t, bl.Revoke(context.TODO(), revokedToken))
+
+	var lastErr error
+	newServer := func(optional bool, ex ...jwthttp.Extractor) http.Handler {
+		mw := jwthttp.NewMiddleware(csjwt.NewVerification(hs256), jwthttp.MiddlewareOptions{
+			KeyFunc:    csjwt.NewKeyFunc(hs256, key),
+			Extractors: ex,
+			Revoker:    bl,
+			Optional:   optional,
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				lastErr = err
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+		})
+		return mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tk, ok := csjwt.FromContext(r.Context())
+			if !ok {
+				_, _ = w.Write([]byte("anonymous"))
+				return
+			}
+			sub, _ := tk.Claims.Get("sub")
+			_, _ = w.Write([]byte(sub.(string)))
+		}))
+	}
+
+	serve := func(h http.Handler, r *http.Request) (int, string) {
+		lastErr = nil
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec.Code, rec.Body.String()
+	}
+	withAuth := func(values ...string) *http.Request {
+		r := httptest.NewRequest("GET", "/", nil)
+		for _, v := range values {
+			r.Header.Add("Authorization", v)
+		}
+		return r
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		code, body := serve(newServer(false), withAuth("Bearer "+valid+" \t"))
+		assert.Exactly(t, http.StatusOK, code)
+		assert.Exactly(t, "gopher", body)
+	})
+	t.Run("several authorization headers", func(t *testing.T) {
+		code, body := serve(newServer(false), withAuth("Basic Z29waGVyOnNlY3JldA==", "bearer "+valid))
+		assert.Exactly(t, http.StatusOK, code)
+		assert.Exactly(t, "gopher", body)
+
+		code, _ = serve(newServer(false), withAuth("Bearer "+valid, "Bearer "+expired))
+		assert.Exactly(t, http.StatusUnauthorized, code)
+		assert.ErrorIsKind(t, errors.NotValid, lastErr)
+	})
+	t.Run("expired", func(t *testing.T) {
+		code, _ := serve(newServer(false), withAuth("Bearer "+expired))
+		assert.Exactly(t, http.StatusUnauthorized, code)
+		assert.ErrorIsKind(t, errors.NotValid, lastErr)
+	})
+	t.Run("revoked", func(t *testing.T) {
+		code, _ := serve(newServer(false), withAuth("Bearer "+revoked))
+		assert.Exactly(t, http.StatusUnauthorized, code)
+		assert.ErrorIsKind(t, errors.Revoked, lastErr)
+	})
+	t.Run("missing", func(t *testing.T) {
+		code, _ := serve(newServer(false), withAuth())
+		assert.Exactly(t, http.StatusUnauthorized, code)
+		assert.ErrorIsKind(t, errors.NotFound, lastErr)
+	})
+	t.Run("optional", func(t *testing.T) {
+		for _, r := range []*http.Request{withAuth(), withAuth("Bearer " + expired), withAuth("Bearer " + revoked)} {
+			code, body := serve(newServer(true), r)
+			assert.Exactly(t, http.StatusOK, code)
+			assert.Exactly(t, "anonymous", body)
+			assert.NoError(t, lastErr)
+		}
+		code, body := serve(newServer(true), withAuth("Bearer "+valid))
+		assert.Exactly(t, http.StatusOK, code)
+		assert.Exactly(t, "gopher", body)
+	})
+	t.Run("extractors in order", func(t *testing.T) {
+		h := newServer(false,
+			jwthttp.ExtractCookie("jwt"),
+			jwthttp.ExtractQuery("access_token"),
+			func(r *http.Request) (string, error) { return r.Header.Get("X-Token"), nil },
+		)
+
+		r := httptest.NewRequest("GET", "/?access_token="+expired, nil)
+		r.AddCookie(&http.Cookie{Name: "jwt", Value: valid})
+		code, body := serve(h, r)
+		assert.Exactly(t, http.StatusOK, code)
+		assert.Exactly(t, "gopher", body)
+
+		r = httptest.NewRequest("GET", "/?access_token="+valid, nil)
+		code, _ = serve(h, r)
+		assert.Exactly(t, http.StatusOK, code)
+
+		r = httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-Token", revoked)
+		code, _ = serve(h, r)
+		assert.Exactly(t, http.StatusUnauthorized, code)
+		assert.ErrorIsKind(t, errors.Revoked, lastErr)
+
+		// The bearer extractor is not configured.
+		code, _ = serve(h, withAuth("Bearer "+valid))
+		assert.Exactly(t, http.StatusUnauthorized, code)
+		assert.ErrorIsKind(t, errors.NotFound, lastErr)
+	})
+	t.Run("default error handler", func(t *testing.T) {
+		h := jwthttp.NewMiddleware(csjwt.NewVerification(hs256), jwthttp.MiddlewareOptions{KeyFunc: csjwt.NewKeyFunc(hs256, key)})(http.NotFoundHandler())
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, withAuth("Bearer "+expired))
+		assert.Exactly(t, http.StatusUnauthorized, rec.Code)
+		assert.Exactly(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+	})
+}
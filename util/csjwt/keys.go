@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 
 	"github.com/corestoreio/errors"
+	"golang.org/x/crypto/ed25519"
 )
 
 // PrivateKeyBits used when auto generating a private key
@@ -35,12 +36,18 @@ type Keyfunc func(*Token) (Key, error)
 // Key defines a container for the HMAC password, RSA and ECDSA public and
 // private keys. The Error fields gets filled out when loading/parsing the keys.
 type Key struct {
-	hmacPassword []byte
-	ecdsaKeyPub  *ecdsa.PublicKey
-	ecdsaKeyPriv *ecdsa.PrivateKey
-	rsaKeyPub    *rsa.PublicKey
-	rsaKeyPriv   *rsa.PrivateKey
-	Error        error
+	hmacPassword   []byte
+	ecdsaKeyPub    *ecdsa.PublicKey
+	ecdsaKeyPriv   *ecdsa.PrivateKey
+	rsaKeyPub      *rsa.PublicKey
+	rsaKeyPriv     *rsa.PrivateKey
+	ed25519KeyPub  ed25519.PublicKey
+	ed25519KeyPriv ed25519.PrivateKey
+	// kid identifies the key via the kid header, see WithKeyID.
+	kid string
+	// set contains the candidate keys, see WithKeySet.
+	set   *keySet
+	Error error
 }
 
 // NewKeyFunc creates a new function for token validation and specific key
@@ -75,13 +82,16 @@ func (k Key) String() string {
 // IsEmpty returns true when no field has been used in the Key struct. Error is
 // excluded from the check.
 func (k Key) IsEmpty() bool {
-	return k.hmacPassword == nil && k.ecdsaKeyPub == nil && k.ecdsaKeyPriv == nil && k.rsaKeyPub == nil && k.rsaKeyPriv == nil
+	return k.hmacPassword == nil && k.ecdsaKeyPub == nil && k.ecdsaKeyPriv == nil && k.rsaKeyPub == nil && k.rsaKeyPriv == nil &&
+		k.ed25519KeyPub == nil && k.ed25519KeyPriv == nil && k.set == nil
 }
 
 // Algorithm returns the supported algorithm but not the bit size. Returns 0 on
-// error, or one of the constants: ES, HS or RS.
+// error, or one of the constants: ES, HS, RS or EdDSA.
 func (k Key) Algorithm() (a string) {
 	switch {
+	case k.set != nil:
+		a = k.set.keys[k.set.current].Algorithm()
 	case len(k.hmacPassword) > 0:
 		a = HS
 	case k.rsaKeyPriv != nil:
@@ -92,6 +102,8 @@ func (k Key) Algorithm() (a string) {
 		a = RS // also matches RSA-PSS
 	case k.ecdsaKeyPub != nil:
 		a = ES
+	case k.ed25519KeyPriv != nil || k.ed25519KeyPub != nil:
+		a = EdDSA
 	}
 	return a
 }
@@ -258,3 +270,25 @@ func WithECPrivateKey(privateKey *ecdsa.PrivateKey) (k Key) {
 	k.ecdsaKeyPub = &privateKey.PublicKey
 	return
 }
+
+// WithEd25519PublicKey sets the Ed25519 public key.
+func WithEd25519PublicKey(publicKey ed25519.PublicKey) (k Key) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		k.Error = errors.NotValid.Newf("[csjwt] WithEd25519PublicKey: invalid key length %d", len(publicKey))
+		return
+	}
+	k.ed25519KeyPub = publicKey
+	return
+}
+
+// WithEd25519PrivateKey sets the Ed25519 private key. Public key will be
+// derived from the private key.
+func WithEd25519PrivateKey(privateKey ed25519.PrivateKey) (k Key) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		k.Error = errors.NotValid.Newf("[csjwt] WithEd25519PrivateKey: invalid key length %d", len(privateKey))
+		return
+	}
+	k.ed25519KeyPriv = privateKey
+	k.ed25519KeyPub = privateKey.Public().(ed25519.PublicKey)
+	return
+}
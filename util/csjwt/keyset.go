@@ -0,0 +1,116 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"sort"
+
+	"github.com/corestoreio/errors"
+)
+
+// maxKeySetCandidates limits the keys of a key set which get tried to verify a
+// token without kid header.
+const maxKeySetCandidates = 3
+
+type keySet struct {
+	current string
+	keys    map[string]Key
+	// kids sorted, to try the keys in a deterministic order.
+	kids []string
+}
+
+// WithKeyID assigns the key ID to a key. Token.SignedString writes the ID
+// into the kid header and Verification.Parse rejects tokens with a different
+// kid. The header must support the kid, like jwtclaim.HeadSegments.
+func WithKeyID(kid string, k Key) Key {
+	k.kid = kid
+	return k
+}
+
+// WithKeySet creates a key from several keys, identified by their kid, for
+// example to rotate HMAC passwords. Token.SignedString signs always with the
+// key of currentKID and writes it into the kid header. Verification.Parse
+// selects the key by the kid header of the token. Without a kid header at most
+// three keys get tried. A Keyfunc can return the key set.
+func WithKeySet(currentKID string, keys map[string]Key) Key {
+	if _, ok := keys[currentKID]; !ok {
+		return Key{Error: errors.NotFound.Newf("[csjwt] WithKeySet: current kid %q not found in key set", currentKID)}
+	}
+	ks := &keySet{
+		current: currentKID,
+		keys:    make(map[string]Key, len(keys)),
+		kids:    make([]string, 0, len(keys)),
+	}
+	for kid, k := range keys {
+		if k.Error != nil {
+			return Key{Error: errors.Wrapf(k.Error, "[csjwt] WithKeySet: key with kid %q", kid)}
+		}
+		if k.set != nil {
+			return Key{Error: errors.NotSupported.Newf("[csjwt] WithKeySet: key with kid %q must not be a key set", kid)}
+		}
+		k.kid = kid
+		ks.keys[kid] = k
+		ks.kids = append(ks.kids, kid)
+	}
+	sort.Strings(ks.kids)
+	return Key{set: ks}
+}
+
+// ID returns the key ID. For a key set the ID of the current key.
+func (k Key) ID() string {
+	if k.set != nil {
+		return k.set.current
+	}
+	return k.kid
+}
+
+// signingKey returns the current key of a key set or the key itself.
+func (k Key) signingKey() Key {
+	if k.set != nil {
+		return k.set.keys[k.set.current]
+	}
+	return k
+}
+
+// verificationKeys writes the candidate keys for the kid header into dst and
+// returns their count.
+func (k Key) verificationKeys(kid string, dst *[maxKeySetCandidates]Key) (int, error) {
+	if k.set == nil {
+		switch {
+		case k.kid == "":
+			// Signers like SigningMethodHSFastSet need the kid of the token.
+			k.kid = kid
+		case kid != "" && kid != k.kid:
+			return 0, errors.NotValid.Newf("[csjwt] Token kid %q does not match key ID %q", kid, k.kid)
+		}
+		dst[0] = k
+		return 1, nil
+	}
+	if kid != "" {
+		sk, ok := k.set.keys[kid]
+		if !ok {
+			return 0, errors.NotFound.Newf("[csjwt] Token kid %q not found in key set", kid)
+		}
+		dst[0] = sk
+		return 1, nil
+	}
+	if len(k.set.kids) > maxKeySetCandidates {
+		return 0, errors.NotValid.Newf("[csjwt] Token without kid header cannot be verified with %d keys, maximum %d", len(k.set.kids), maxKeySetCandidates)
+	}
+	for i, id := range k.set.kids {
+		dst[i] = k.set.keys[id]
+	}
+	return len(k.set.kids), nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/csjwt"
+	"github.com/corestoreio/pkg/util/csjwt/jwtclaim"
+)
+
+func newKIDToken() *csjwt.Token {
+	tk := csjwt.NewToken(&jwtclaim.Standard{Subject: "gopher", ExpiresAt: 4102444800}) // 2100-01-01
+	tk.Header = jwtclaim.NewHeadSegments()
+	return tk
+}
+
+func parseKID(t *testing.T, m csjwt.Signer, kf csjwt.Keyfunc, raw []byte) (kid string, err error) {
+	tk := newKIDToken()
+	err = csjwt.NewVerification(m).Parse(tk, raw, kf)
+	kid, _ = tk.Header.Get(jwtclaim.HeaderKID)
+	return kid, err
+}
+
+func TestWithKeySet(t *testing.T) {
+	pwOld := csjwt.WithPassword([]byte(`Rotated password 2018`))
+	pwNew := csjwt.WithPassword([]byte(`Current password 2019`))
+	hs256 := csjwt.NewSigningMethodHS256()
+
+	setOld := csjwt.WithKeySet("2018", map[string]csjwt.Key{"2018": pwOld})
+	setNew := csjwt.WithKeySet("2019", map[string]csjwt.Key{"2018": pwOld, "2019": pwNew})
+	assert.NoError(t, setNew.Error)
+	assert.Exactly(t, "2019", setNew.ID())
+	assert.Exactly(t, csjwt.HS, setNew.Algorithm())
+
+	rawOld, err := newKIDToken().SignedString(hs256, setOld)
+	assert.NoError(t, err)
+	rawNew, err := newKIDToken().SignedString(hs256, setNew)
+	assert.NoError(t, err)
+	rawNoKID, err := newKIDToken().SignedString(hs256, pwOld)
+	assert.NoError(t, err)
+
+	kf := csjwt.NewKeyFunc(hs256, setNew)
+	kid, err := parseKID(t, hs256, kf, rawOld)
+	assert.NoError(t, err)
+	assert.Exactly(t, "2018", kid)
+	kid, err = parseKID(t, hs256, kf, rawNew)
+	assert.NoError(t, err)
+	assert.Exactly(t, "2019", kid)
+	kid, err = parseKID(t, hs256, kf, rawNoKID)
+	assert.NoError(t, err, "fallback to all keys without kid")
+	assert.Exactly(t, "", kid)
+
+	// Old set does not know the new password.
+	_, err = parseKID(t, hs256, csjwt.NewKeyFunc(hs256, setOld), rawNew)
+	assert.ErrorIsKind(t, errors.NotValid, err)
+
+	// Too many candidates for a token without kid.
+	setMany := csjwt.WithKeySet("2019", map[string]csjwt.Key{
+		"2016": csjwt.WithPasswordRandom(), "2017": csjwt.WithPasswordRandom(), "2018": pwOld, "2019": pwNew,
+	})
+	_, err = parseKID(t, hs256, csjwt.NewKeyFunc(hs256, setMany), rawNoKID)
+	assert.ErrorIsKind(t, errors.NotValid, err)
+	_, err = parseKID(t, hs256, csjwt.NewKeyFunc(hs256, setMany), rawOld)
+	assert.NoError(t, err)
+
+	// A single key with an ID rejects other kids.
+	_, err = parseKID(t, hs256, csjwt.NewKeyFunc(hs256, csjwt.WithKeyID("2019", pwOld)), rawOld)
+	assert.ErrorIsKind(t, errors.NotValid, err)
+
+	assert.ErrorIsKind(t, errors.NotFound, csjwt.WithKeySet("2020", map[string]csjwt.Key{"2019": pwNew}).Error)
+	assert.ErrorIsKind(t, errors.Empty, csjwt.WithKeySet("2019", map[string]csjwt.Key{"2019": csjwt.WithPassword(nil)}).Error)
+
+	// csjwt.Head does not support the kid header.
+	_, err = csjwt.NewToken(&jwtclaim.Standard{}).SignedString(hs256, setNew)
+	assert.ErrorIsKind(t, errors.NotSupported, err)
+}
+
+func TestNewHMACFastSet(t *testing.T) {
+	pwOld := csjwt.WithPassword([]byte(`Rotated password 2018`))
+	pwNew := csjwt.WithPassword([]byte(`Current password 2019`))
+
+	fastOld, err := csjwt.NewHMACFastSet(csjwt.HS256, "2018", map[string]csjwt.Key{"2018": pwOld})
+	assert.NoError(t, err)
+	fastNew, err := csjwt.NewHMACFastSet(csjwt.HS256, "2019", map[string]csjwt.Key{"2018": pwOld, "2019": pwNew})
+	assert.NoError(t, err)
+
+	rawOld, err := newKIDToken().SignedString(fastOld, csjwt.Key{})
+	assert.NoError(t, err)
+	// The current key gets used even when another key has been passed.
+	rawNew, err := newKIDToken().SignedString(fastNew, csjwt.WithKeyID("2018", pwOld))
+	assert.NoError(t, err)
+
+	// Compatible with the pool free HS256 signing method.
+	hs256 := csjwt.NewSigningMethodHS256()
+	rawNewSlow, err := newKIDToken().SignedString(hs256, csjwt.WithKeyID("2019", pwNew))
+	assert.NoError(t, err)
+	assert.Exactly(t, string(rawNewSlow), string(rawNew))
+	rawNoKID, err := newKIDToken().SignedString(hs256, pwOld)
+	assert.NoError(t, err)
+
+	kf := csjwt.NewKeyFunc(fastNew, csjwt.Key{})
+	for i, raw := range [][]byte{rawOld, rawNew, rawNoKID} {
+		_, err = parseKID(t, fastNew, kf, raw)
+		assert.NoError(t, err, "Index %d", i)
+	}
+	_, err = parseKID(t, fastOld, csjwt.NewKeyFunc(fastOld, csjwt.Key{}), rawNew)
+	assert.ErrorIsKind(t, errors.NotValid, err)
+
+	_, err = csjwt.NewHMACFastSet(csjwt.ES256, "2019", map[string]csjwt.Key{"2019": pwNew})
+	assert.ErrorIsKind(t, errors.NotSupported, err)
+	_, err = csjwt.NewHMACFastSet(csjwt.HS256, "2020", map[string]csjwt.Key{"2019": pwNew})
+	assert.ErrorIsKind(t, errors.NotFound, err)
+}
@@ -29,6 +29,9 @@ type Verification struct {
 	// Decoder interface to pass in a custom decoder parser. Can be nil, falls
 	// back to JSON.
 	Deserializer
+	// ValidationOptions if set replace the Claimer.Valid check of the token
+	// claims. Add Claimer.Valid to the Custom validators to run both.
+	ValidationOptions *ValidationOptions
 }
 
 // NewVerification creates new verification parser with the default signing
@@ -40,8 +43,12 @@ func NewVerification(availableSigners ...Signer) *Verification {
 	}
 }
 
+// unmarshal decodes the segment into a pooled buffer, hence the decoders must
+// copy the data if they wish to retain it.
 func (vf *Verification) unmarshal(src []byte, dst interface{}) error {
-	src, err := DecodeSegment(src)
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+	src, err := DecodeSegmentTo(buf.Bytes(), src)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -84,7 +91,8 @@ func (vf *Verification) unmarshal(src []byte, dst interface{}) error {
 // You must make sure to set the correct expected headers and claims in the
 // template Token. The Header and Claims field in the destination token must be
 // a pointer as the token itself. Error behaviour: Empty, NotFound, NotValid.
-// Parse supports custom binary, text, json, protobuf decoding.
+// Parse supports custom binary, text, json, protobuf decoding. The key returned
+// by the keyFunc can be a key set, see WithKeySet.
 func (vf *Verification) Parse(dst *Token, rawToken []byte, keyFunc Keyfunc) error {
 	pos, valid := dotPositions(rawToken)
 	if !valid {
@@ -108,8 +116,8 @@ func (vf *Verification) Parse(dst *Token, rawToken []byte, keyFunc Keyfunc) erro
 		return errors.WithStack(err)
 	}
 
-	if err := dst.Claims.Valid(); err != nil {
-		return errors.Wrap(err, errValidationClaimsInvalid)
+	if err := vf.validate(dst.Claims); err != nil {
+		return errors.WithStack(err)
 	}
 
 	if keyFunc == nil {
@@ -125,13 +133,22 @@ func (vf *Verification) Parse(dst *Token, rawToken []byte, keyFunc Keyfunc) erro
 		return errors.Wrap(err, "[csjwt] Verification.Parse.getMethod")
 	}
 
-	dst.Signature = dst.Raw[pos[1]+1:]
-	if err := method.Verify(dst.Raw[:pos[1]], dst.Signature, key); err != nil {
-		return errors.NotValid.Newf(errSignatureInvalid, err, dst)
+	// A Header without support for the kid behaves like a missing kid.
+	kid, _ := dst.Header.Get(headerKID)
+	var keys [maxKeySetCandidates]Key
+	n, err := key.verificationKeys(kid, &keys)
+	if err != nil {
+		return errors.NotValid.Newf(errTokenUnverifiable, err)
 	}
 
-	dst.Valid = true
-	return nil
+	dst.Signature = dst.Raw[pos[1]+1:]
+	for i := 0; i < n; i++ {
+		if err = method.Verify(dst.Raw[:pos[1]], dst.Signature, keys[i]); err == nil {
+			dst.Valid = true
+			return nil
+		}
+	}
+	return errors.NotValid.Newf(errSignatureInvalid, err, dst)
 }
 
 // ParseUnverified parses a rawToken into the unverified destination token and
@@ -159,8 +176,8 @@ func (vf *Verification) ParseUnverified(dst *Token, rawToken []byte) error {
 		return errors.WithStack(err)
 	}
 
-	if err := dst.Claims.Valid(); err != nil {
-		return errors.Wrap(err, errValidationClaimsInvalid)
+	if err := vf.validate(dst.Claims); err != nil {
+		return errors.WithStack(err)
 	}
 
 	// Lookup signature method
@@ -175,6 +192,13 @@ func (vf *Verification) ParseUnverified(dst *Token, rawToken []byte) error {
 	return nil
 }
 
+func (vf *Verification) validate(claims Claimer) error {
+	if vf.ValidationOptions != nil {
+		return vf.ValidationOptions.Validate(claims)
+	}
+	return errors.Wrap(claims.Valid(), errValidationClaimsInvalid)
+}
+
 func (vf *Verification) getMethod(t *Token) (Signer, error) {
 	if len(vf.Methods) == 0 {
 		return nil, errors.Empty.Newf(errVerificationMethodsEmpty)
@@ -49,7 +49,9 @@ func (m *SigningMethodRSA) Verify(signingString, signature []byte, key Key) erro
 	}
 
 	// Decode the signature
-	sig, err := DecodeSegment(signature)
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+	sig, err := DecodeSegmentTo(buf.Bytes(), signature)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -64,7 +66,8 @@ func (m *SigningMethodRSA) Verify(signingString, signature []byte, key Key) erro
 	}
 
 	// Verify the signature
-	return errors.NotValid.New(rsa.VerifyPKCS1v15(key.rsaKeyPub, m.Hash, hasher.Sum(nil), sig), "[csjwt] SigningMethodRSA.Verify.VerifyPKCS1v15")
+	var sum [64]byte
+	return errors.NotValid.New(rsa.VerifyPKCS1v15(key.rsaKeyPub, m.Hash, hasher.Sum(sum[:0]), sig), "[csjwt] SigningMethodRSA.Verify.VerifyPKCS1v15")
 }
 
 // Sign implements the Sign method from SigningMethod interface. For the key you
@@ -89,7 +92,8 @@ func (m *SigningMethodRSA) Sign(signingString []byte, key Key) ([]byte, error) {
 	}
 
 	// Sign the string and return the encoded bytes
-	sigBytes, err := rsa.SignPKCS1v15(rand.Reader, key.rsaKeyPriv, m.Hash, hasher.Sum(nil))
+	var sum [64]byte
+	sigBytes, err := rsa.SignPKCS1v15(rand.Reader, key.rsaKeyPriv, m.Hash, hasher.Sum(sum[:0]))
 	if err != nil {
 		return nil, errors.NotValid.New(err, "[csjwt] SigningMethodRSA.Sign.SignPKCS1v15")
 	}
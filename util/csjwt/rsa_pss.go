@@ -4,28 +4,44 @@ import (
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"hash"
 
 	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/hashpool"
 )
 
 // SigningMethodRSAPSS implements the RSAPSS family of signing methods signing
-// methods.
+// methods. The message digests get calculated with pooled hashes.
 type SigningMethodRSAPSS struct {
 	SigningMethodRSA
+	// Options used for signing. The salt length equals the hash size as
+	// recommended by RFC 7518.
 	Options rsa.PSSOptions
+	// VerifyOptions used for verifying. By default the salt length must equal
+	// the hash size, see VerifySaltLengthAuto.
+	VerifyOptions rsa.PSSOptions
+	ht            hashpool.Tank
 }
 
 func newSigningMethodRSAPSS(n string, h crypto.Hash) *SigningMethodRSAPSS {
-	return &SigningMethodRSAPSS{
-		SigningMethodRSA{
+	m := &SigningMethodRSAPSS{
+		SigningMethodRSA: SigningMethodRSA{
 			Name: n,
 			Hash: h,
 		},
-		rsa.PSSOptions{
-			SaltLength: rsa.PSSSaltLengthAuto,
+		Options: rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
 			Hash:       h,
 		},
+		VerifyOptions: rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+			Hash:       h,
+		},
+	}
+	if h.Available() {
+		m.ht = hashpool.New(func() hash.Hash { return h.New() })
 	}
+	return m
 }
 
 // NewSigningMethodPS256 creates a new 256bit RSAPSS SHA instance and registers
@@ -46,9 +62,24 @@ func NewSigningMethodPS512() *SigningMethodRSAPSS {
 	return newSigningMethodRSAPSS(PS512, crypto.SHA512)
 }
 
+// VerifySaltLengthAuto accepts signatures with any salt length, for example
+// from issuers signing with the maximum salt length. Returns the same pointer.
+func (m *SigningMethodRSAPSS) VerifySaltLengthAuto() *SigningMethodRSAPSS {
+	m.VerifyOptions.SaltLength = rsa.PSSSaltLengthAuto
+	return m
+}
+
+// digest appends the hash of the signing string to buf.
+func (m *SigningMethodRSAPSS) digest(signingString, buf []byte) ([]byte, error) {
+	if !m.Hash.Available() {
+		return nil, errors.NotImplemented.Newf(errRSAHashUnavailable)
+	}
+	return m.ht.Sum(signingString, buf), nil
+}
+
 // Verify implements the Verify method from SigningMethod interface. For the key
 // you can use any of the WithRSA*Key*() functions. Error behaviour: Empty,
-// NotImplemented, WriteFailed, NotValid
+// NotImplemented, NotValid
 func (m *SigningMethodRSAPSS) Verify(signingString, signature []byte, key Key) error {
 	if key.Error != nil {
 		return errors.WithStack(key.Error)
@@ -58,21 +89,20 @@ func (m *SigningMethodRSAPSS) Verify(signingString, signature []byte, key Key) e
 	}
 
 	// Decode the signature
-	sig, err := DecodeSegment(signature)
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+	sig, err := DecodeSegmentTo(buf.Bytes(), signature)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	// Create hasher
-	if !m.Hash.Available() {
-		return errors.NotImplemented.Newf(errRSAHashUnavailable)
-	}
-	hasher := m.Hash.New()
-	if _, err := hasher.Write(signingString); err != nil {
-		return errors.WriteFailed.New(err, "[csjwt] SigningMethodRSA.Verify.hasher.Write")
+	var sum [64]byte // large enough for SHA512
+	digest, err := m.digest(signingString, sum[:0])
+	if err != nil {
+		return errors.WithStack(err)
 	}
 
-	if err := rsa.VerifyPSS(key.rsaKeyPub, m.Hash, hasher.Sum(nil), sig, &m.Options); err != nil {
+	if err := rsa.VerifyPSS(key.rsaKeyPub, m.Hash, digest, sig, &m.VerifyOptions); err != nil {
 		return errors.NotValid.New(err, "[csjwt] SigningMethodRSAPSS.Verify.VerifyPSS")
 	}
 	return nil
@@ -80,7 +110,7 @@ func (m *SigningMethodRSAPSS) Verify(signingString, signature []byte, key Key) e
 
 // Sign implements the Sign method from SigningMethod interface. For the key you
 // can use any of the WithRSAPrivateKey*() functions. Error behaviour: Empty,
-// NotImplemented, WriteFailed, NotValid.
+// NotImplemented, NotValid.
 func (m *SigningMethodRSAPSS) Sign(signingString []byte, key Key) ([]byte, error) {
 	if key.Error != nil {
 		return nil, errors.Wrap(key.Error, "[csjwt] SigningMethodRSAPSS.Sign.key")
@@ -89,18 +119,14 @@ func (m *SigningMethodRSAPSS) Sign(signingString []byte, key Key) ([]byte, error
 		return nil, errors.Empty.Newf(errRSAPrivateKeyEmpty)
 	}
 
-	// Create the hasher
-	if !m.Hash.Available() {
-		return nil, errors.NotImplemented.Newf(errRSAHashUnavailable)
-	}
-
-	hasher := m.Hash.New()
-	if _, err := hasher.Write(signingString); err != nil {
-		return nil, errors.WriteFailed.New(err, "[csjwt] SigningMethodRSA.Sign.hasher.Write")
+	var sum [64]byte // large enough for SHA512
+	digest, err := m.digest(signingString, sum[:0])
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
 
 	// Sign the string and return the encoded bytes
-	sigBytes, err := rsa.SignPSS(rand.Reader, key.rsaKeyPriv, m.Hash, hasher.Sum(nil), &m.Options)
+	sigBytes, err := rsa.SignPSS(rand.Reader, key.rsaKeyPriv, m.Hash, digest, &m.Options)
 	if err != nil {
 		return nil, errors.NotValid.New(err, "[csjwt] SigningMethodRSAPSS.Sign.SignPSS")
 	}
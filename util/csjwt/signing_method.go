@@ -30,11 +30,19 @@ type Signer interface {
 	Alg() string
 }
 
+// KeyIDer gets implemented by Signers which choose the signing key on their
+// own, like SigningMethodHSFastSet. Token.SignedString writes the returned ID
+// into the kid header.
+type KeyIDer interface {
+	KeyID() string
+}
+
 // All available algorithms which are supported by this package.
 const (
 	ES256      = `ES256`
 	ES384      = `ES384`
 	ES512      = `ES512`
+	EdDSA      = `EdDSA`
 	HS256      = `HS256`
 	HS384      = `HS384`
 	HS512      = `HS512`
@@ -53,8 +61,8 @@ const (
 )
 
 // SigningMethodFactory creates a new signing method by an algorithm. Supported
-// algorithms are: ES, HS, PS and RS, all within 256-512 and they do not need a
-// symmetric key. Returns an error for an unknown signing method.
+// algorithms are: ES, HS, PS and RS, all within 256-512, and EdDSA. They do not
+// need a symmetric key. Returns an error for an unknown signing method.
 func SigningMethodFactory(alg string) (s Signer, err error) {
 	switch alg {
 
@@ -65,6 +73,9 @@ func SigningMethodFactory(alg string) (s Signer, err error) {
 	case ES512:
 		s = NewSigningMethodES512()
 
+	case EdDSA:
+		s = NewSigningMethodEdDSA()
+
 	case HS256:
 		s = NewSigningMethodHS256()
 	case HS384:
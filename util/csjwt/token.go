@@ -68,14 +68,35 @@ func (t *Token) Alg() string {
 	return conv.ToString(h)
 }
 
+// Kid returns the key ID of the kid header. Returns an empty string if the
+// header does not support the kid.
+func (t *Token) Kid() string {
+	if t.Header == nil {
+		return ""
+	}
+	kid, _ := t.Header.Get(headerKID)
+	return kid
+}
+
 // SignedString gets the complete, signed token. Sets the header alg to the
 // provided Signer.Alg() value. Returns a byte slice, save for further
 // processing. This functions allows to sign a token with different signing
-// methods.
+// methods. A key set signs with its current key. The key ID, of the Signer if
+// it implements KeyIDer or of the key, gets written into the kid header.
 func (t *Token) SignedString(method Signer, key Key) ([]byte, error) {
 	if err := t.Header.Set(headerAlg, method.Alg()); err != nil {
 		return nil, errors.WithStack(err)
 	}
+	key = key.signingKey()
+	kid := key.kid
+	if ki, ok := method.(KeyIDer); ok {
+		kid = ki.KeyID()
+	}
+	if kid != "" {
+		if err := t.Header.Set(headerKID, kid); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
 
 	buf, err := t.SigningString(make([]byte, 0, 512))
 	if err != nil {
@@ -90,46 +111,36 @@ func (t *Token) SignedString(method Signer, key Key) ([]byte, error) {
 	return buf, nil
 }
 
-func (t *Token) marshal(dst interface{}) (data []byte, err error) {
+// marshal appends the base64url encoded serialization of v to buf.
+func (t *Token) marshal(buf []byte, v interface{}) (_ []byte, err error) {
+	var data []byte
 	if t.Serializer != nil {
-		data, err = t.Serializer.Serialize(dst)
+		data, err = t.Serializer.Serialize(v)
 		if err != nil {
-			err = errors.WithStack(err)
+			return nil, errors.WithStack(err)
 		}
-		return EncodeSegment(data), err
+		return EncodeSegmentTo(buf, data), nil
 	}
 
 	// the order of the cases is important as JSON can be embedded but main type
 	// has e.g. TextMarshaler.
-	switch dt := dst.(type) {
+	switch dt := v.(type) {
 	case encoding.BinaryMarshaler:
 		data, err = dt.MarshalBinary()
-		if err != nil {
-			err = errors.WithStack(err)
-		}
 	case encoding.TextMarshaler:
 		data, err = dt.MarshalText()
-		if err != nil {
-			err = errors.WithStack(err)
-		}
 	case interface{ Marshal() ([]byte, error) }:
 		data, err = dt.Marshal()
-		if err != nil {
-			err = errors.WithStack(err)
-		}
 	case interface{ MarshalJSON() ([]byte, error) }:
 		data, err = dt.MarshalJSON()
-		if err != nil {
-			err = errors.WithStack(err)
-		}
 	default:
 		enc := jsonEncoding{}
-		data, err = enc.Serialize(dst)
-		if err != nil {
-			err = errors.WithStack(err)
-		}
+		data, err = enc.Serialize(v)
 	}
-	return EncodeSegment(data), err
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return EncodeSegmentTo(buf, data), nil
 }
 
 // SigningString generates the signing string. This is the most expensive part
@@ -138,19 +149,13 @@ func (t *Token) marshal(dst interface{}) (data []byte, err error) {
 // modifications.
 // SigningString supports custom binary, text, json, protobuf encoding.
 func (t *Token) SigningString(buf []byte) ([]byte, error) {
-	data, err := t.marshal(t.Header)
+	buf, err := t.marshal(buf, t.Header)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	buf = append(buf, data...)
 	buf = append(buf, '.')
-
-	data, err = t.marshal(t.Claims)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-	buf = append(buf, data...)
-	return buf, nil
+	buf, err = t.marshal(buf, t.Claims)
+	return buf, errors.WithStack(err)
 }
 
 // MarshalLog marshals the token into an unsigned log.Field. It uses the function
@@ -0,0 +1,106 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/corestoreio/errors"
+)
+
+// unverifiedHead gets used by ParseUnverified to expose the header parameters
+// required to select a verifier or key set.
+type unverifiedHead struct {
+	Algorithm string `json:"alg,omitempty"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+func (h *unverifiedHead) Alg() string { return h.Algorithm }
+func (h *unverifiedHead) Typ() string { return h.Type }
+
+// Set returns always NotSupported because the header is read only.
+func (h *unverifiedHead) Set(key, _ string) error {
+	return errors.NotSupported.Newf("[csjwt] Header of an unverified token is read only, key %q", key)
+}
+
+func (h *unverifiedHead) Get(key string) (string, error) {
+	switch key {
+	case headerAlg:
+		return h.Algorithm, nil
+	case headerTyp:
+		return h.Type, nil
+	case headerKID:
+		return h.KeyID, nil
+	}
+	return "", errors.NotSupported.Newf(errHeaderKeyNotSupported, key)
+}
+
+// ParseUnverified decodes the header and the claims of rawToken WITHOUT
+// VERIFYING THE SIGNATURE. Token.Valid is always false.
+//
+// NEVER USE THE RETURNED TOKEN FOR AUTHORIZATION DECISIONS. Anyone can create
+// a token with arbitrary claims. The only purpose is routing, for example to
+// select the Verification or the key set by Token.Alg, Token.Kid or the issuer
+// claim. Afterwards the token must be parsed again with Verification.Parse.
+//
+// The claims argument must be a pointer and gets decoded as JSON. No signing
+// method gets touched. Error behaviour: Empty, NotValid for a wrong number of
+// segments, BadEncoding for invalid base64 and CorruptData for invalid JSON.
+func ParseUnverified(rawToken []byte, claims Claimer) (Token, error) {
+	if claims == nil {
+		return Token{}, errors.Empty.Newf(errTokenBaseNil)
+	}
+	if StartsWithBearer(rawToken) {
+		return Token{}, errors.NotValid.Newf(errTokenShouldNotContainBearer)
+	}
+	pos, valid := dotPositions(rawToken)
+	if !valid {
+		return Token{}, errors.NotValid.Newf(errTokenInvalidSegmentCounts)
+	}
+
+	head := new(unverifiedHead)
+	if err := decodeUnverifiedSegment(rawToken[:pos[0]], head, "header"); err != nil {
+		return Token{}, errors.WithStack(err)
+	}
+	if err := decodeUnverifiedSegment(rawToken[pos[0]+1:pos[1]], claims, "claims"); err != nil {
+		return Token{}, errors.WithStack(err)
+	}
+	sig := rawToken[pos[1]+1:]
+	if _, err := base64.RawURLEncoding.DecodeString(string(sig)); err != nil {
+		return Token{}, errors.BadEncoding.New(err, "[csjwt] ParseUnverified signature")
+	}
+
+	return Token{
+		Raw:       rawToken,
+		Header:    head,
+		Claims:    claims,
+		Signature: sig,
+		Valid:     false,
+	}, nil
+}
+
+func decodeUnverifiedSegment(seg []byte, dst interface{}, name string) error {
+	data := make([]byte, base64.RawURLEncoding.DecodedLen(len(seg)))
+	n, err := base64.RawURLEncoding.Decode(data, seg)
+	if err != nil {
+		return errors.BadEncoding.New(err, "[csjwt] ParseUnverified %s", name)
+	}
+	if err := json.Unmarshal(data[:n], dst); err != nil {
+		return errors.CorruptData.New(err, "[csjwt] ParseUnverified %s", name)
+	}
+	return nil
+}
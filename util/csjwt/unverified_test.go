@@ -0,0 +1,73 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/csjwt"
+	"github.com/corestoreio/pkg/util/csjwt/jwtclaim"
+)
+
+func TestParseUnverified(t *testing.T) {
+	pw := csjwt.WithKeyID("2019", csjwt.WithPassword([]byte(`Current password 2019`)))
+	tk := csjwt.NewToken(&jwtclaim.Standard{Issuer: "https://idp.example.com", ExpiresAt: 1})
+	tk.Header = jwtclaim.NewHeadSegments()
+	raw, err := tk.SignedString(csjwt.NewSigningMethodHS384(), pw)
+	assert.NoError(t, err)
+
+	t.Run("valid stays false", func(t *testing.T) {
+		var claims jwtclaim.Standard
+		utk, err := csjwt.ParseUnverified(raw, &claims)
+		assert.NoError(t, err)
+		assert.False(t, utk.Valid)
+		assert.Exactly(t, csjwt.HS384, utk.Alg())
+		assert.Exactly(t, "2019", utk.Kid())
+		assert.Exactly(t, "https://idp.example.com", claims.Issuer)
+		assert.Exactly(t, raw, utk.Raw)
+		assert.ErrorIsKind(t, errors.NotSupported, utk.Header.Set("kid", "2020"))
+	})
+
+	t.Run("tampered signature", func(t *testing.T) {
+		tampered := append(append([]byte{}, raw[:len(raw)-4]...), "AAAA"...)
+		utk, err := csjwt.ParseUnverified(tampered, &jwtclaim.Standard{})
+		assert.NoError(t, err)
+		assert.False(t, utk.Valid)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		tests := []struct {
+			raw  string
+			kind errors.Kind
+		}{
+			{"eyJhbGciOiJIUzI1NiJ9.e30", errors.NotValid},
+			{"eyJhbGciOiJIUzI1NiJ9.e30.sig.x", errors.NotValid},
+			{"Bearer eyJhbGciOiJIUzI1NiJ9.e30.c2ln", errors.NotValid},
+			{"eyJhbGciOiJIUzI1NiJ9.e30$.c2ln", errors.BadEncoding},
+			{"eyJhbGciOiJIUzI1NiJ9.e30.c2ln=", errors.BadEncoding},
+			{"eyJhbGciOiJIUzI1NiJ9.eyJpc3MiOjF9.c2ln", errors.CorruptData}, // {"iss":1}
+			{"eyJhbGciOiJIUzI1NiJ.e30.c2ln", errors.CorruptData},
+		}
+		for _, test := range tests {
+			utk, err := csjwt.ParseUnverified([]byte(test.raw), &jwtclaim.Standard{})
+			assert.ErrorIsKind(t, test.kind, err, "%q", test.raw)
+			assert.False(t, utk.Valid)
+		}
+		_, err := csjwt.ParseUnverified(raw, nil)
+		assert.ErrorIsKind(t, errors.Empty, err)
+	})
+}
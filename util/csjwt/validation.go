@@ -0,0 +1,225 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt
+
+import (
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/conv"
+)
+
+// Registered claim names checked by ValidationOptions and decoded by
+// ClaimsRaw.
+const (
+	claimAudience  = "aud"
+	claimExpiresAt = "exp"
+	claimID        = "jti"
+	claimIssuedAt  = "iat"
+	claimIssuer    = "iss"
+	claimNotBefore = "nbf"
+	claimSubject   = "sub"
+	claimCustom    = "custom"
+)
+
+// ClaimError describes a failed check of a single claim.
+type ClaimError struct {
+	// Claim contains the name of the claim, like "exp" or "aud". Errors of the
+	// custom validators without a claim name use "custom".
+	Claim string
+	Err   error
+}
+
+func (ce *ClaimError) Error() string {
+	return ce.Claim + ": " + ce.Err.Error()
+}
+
+// ClaimErrors contains all failed checks of ValidationOptions.Validate. Use
+// errors.Cause to retrieve it from the returned error.
+type ClaimErrors []*ClaimError
+
+func (ce ClaimErrors) Error() string {
+	var buf strings.Builder
+	for i, e := range ce {
+		if i > 0 {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(e.Error())
+	}
+	return buf.String()
+}
+
+// Has reports whether a check of the claim has failed.
+func (ce ClaimErrors) Has(claim string) bool {
+	for _, e := range ce {
+		if e.Claim == claim {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationOptions replaces the Claimer.Valid check of the Verification. The
+// time based claims get read via Claimer.Get and compared against TimeFunc.
+// All checks run and the failed ones get aggregated in ClaimErrors.
+type ValidationOptions struct {
+	// Leeway gets applied to the exp, nbf and iat checks to account for clock
+	// skew between the signer and the verifier.
+	Leeway time.Duration
+	// ExpectedAudiences if not empty, at least one of the audiences must be
+	// contained in the aud claim. The aud claim can be a string or an array of
+	// strings.
+	ExpectedAudiences []string
+	// ExpectedIssuer if not empty must be equal to the iss claim.
+	ExpectedIssuer string
+	// RequireExpiration rejects tokens without an exp claim.
+	RequireExpiration bool
+	// Custom validators run after the built-in checks. An error of type
+	// *ClaimError or ClaimErrors gets added as is, all other errors get
+	// tagged with the claim name "custom".
+	Custom []func(claims Claimer) error
+}
+
+// Validate checks the claims and returns all failed checks. Error behaviour:
+// NotValid.
+func (vo *ValidationOptions) Validate(claims Claimer) error {
+	var errs ClaimErrors
+	addErr := func(claim string, err error) {
+		errs = append(errs, &ClaimError{Claim: claim, Err: err})
+	}
+
+	now := TimeFunc()
+	leeway := int64(vo.Leeway / time.Second)
+
+	switch exp, ok, err := claimInt64(claims, claimExpiresAt); {
+	case err != nil:
+		addErr(claimExpiresAt, err)
+	case !ok && vo.RequireExpiration:
+		addErr(claimExpiresAt, errors.NotFound.Newf("[csjwt] token has no expiration"))
+	case ok && now.Unix() > exp+leeway:
+		addErr(claimExpiresAt, errors.NotValid.Newf("[csjwt] token is expired %s ago", now.Sub(time.Unix(exp, 0))))
+	}
+
+	switch nbf, ok, err := claimInt64(claims, claimNotBefore); {
+	case err != nil:
+		addErr(claimNotBefore, err)
+	case ok && now.Unix()+leeway < nbf:
+		addErr(claimNotBefore, errors.NotValid.Newf("[csjwt] token is not valid yet. Diff %s", time.Unix(nbf, 0).Sub(now)))
+	}
+
+	switch iat, ok, err := claimInt64(claims, claimIssuedAt); {
+	case err != nil:
+		addErr(claimIssuedAt, err)
+	case ok && now.Unix()+leeway < iat:
+		addErr(claimIssuedAt, errors.NotValid.Newf("[csjwt] token used before issued, clock skew issue? Diff %s", time.Unix(iat, 0).Sub(now)))
+	}
+
+	if vo.ExpectedIssuer != "" {
+		iss, err := claimStrings(claims, claimIssuer)
+		switch {
+		case err != nil:
+			addErr(claimIssuer, err)
+		case len(iss) != 1 || !equalConstantTime(iss[0], vo.ExpectedIssuer):
+			addErr(claimIssuer, errors.NotValid.Newf("[csjwt] token issuer %q does not match", iss))
+		}
+	}
+
+	if len(vo.ExpectedAudiences) > 0 {
+		auds, err := claimStrings(claims, claimAudience)
+		switch {
+		case err != nil:
+			addErr(claimAudience, err)
+		case !containsAny(auds, vo.ExpectedAudiences):
+			addErr(claimAudience, errors.NotValid.Newf("[csjwt] token audience %q does not match", auds))
+		}
+	}
+
+	for _, fn := range vo.Custom {
+		switch err := fn(claims).(type) {
+		case nil:
+		case *ClaimError:
+			errs = append(errs, err)
+		case ClaimErrors:
+			errs = append(errs, err...)
+		default:
+			addErr(claimCustom, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.NotValid.New(errs, errValidationClaimsInvalid)
+	}
+	return nil
+}
+
+// claimInt64 returns the numeric claim. ok is false if the claim does not
+// exist or is zero, like the jwtclaim types treat it.
+func claimInt64(claims Claimer, key string) (v int64, ok bool, err error) {
+	raw, err := claims.Get(key)
+	if err != nil || raw == nil {
+		// NotSupported by the Claimer or not set.
+		return 0, false, nil
+	}
+	if v, err = conv.ToInt64E(raw); err != nil {
+		return 0, false, errors.NotValid.New(err, "[csjwt] claim %q is not a number", key)
+	}
+	return v, v != 0, nil
+}
+
+// claimStrings returns a string claim or an array of strings. An empty
+// slice gets returned if the claim does not exist.
+func claimStrings(claims Claimer, key string) ([]string, error) {
+	raw, err := claims.Get(key)
+	if err != nil || raw == nil {
+		return nil, nil
+	}
+	switch v := raw.(type) {
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		ss := make([]string, 0, len(v))
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return nil, errors.NotValid.Newf("[csjwt] claim %q contains a non-string value: %#v", key, e)
+			}
+			ss = append(ss, s)
+		}
+		return ss, nil
+	}
+	return nil, errors.NotValid.Newf("[csjwt] claim %q must be a string or an array of strings, got %T", key, raw)
+}
+
+func containsAny(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if equalConstantTime(h, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func equalConstantTime(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,119 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csjwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/csjwt"
+	"github.com/corestoreio/pkg/util/csjwt/jwtclaim"
+)
+
+func claimErrors(t *testing.T, err error) csjwt.ClaimErrors {
+	assert.ErrorIsKind(t, errors.NotValid, err)
+	ce, ok := errors.Cause(err).(csjwt.ClaimErrors)
+	assert.True(t, ok, "%T is not ClaimErrors", errors.Cause(err))
+	return ce
+}
+
+func TestValidationOptions_Validate(t *testing.T) {
+	now := time.Now().Unix()
+
+	t.Run("leeway", func(t *testing.T) {
+		claims := jwtclaim.Map{"exp": float64(now - 3), "nbf": float64(now + 3), "iat": float64(now + 3)}
+		vo := &csjwt.ValidationOptions{Leeway: 10 * time.Second}
+		assert.NoError(t, vo.Validate(claims))
+
+		ce := claimErrors(t, (&csjwt.ValidationOptions{}).Validate(claims))
+		assert.Len(t, ce, 3)
+		assert.True(t, ce.Has("exp"))
+		assert.True(t, ce.Has("nbf"))
+		assert.True(t, ce.Has("iat"))
+	})
+
+	t.Run("audiences string and array", func(t *testing.T) {
+		vo := &csjwt.ValidationOptions{ExpectedAudiences: []string{"shop", "admin"}}
+		assert.NoError(t, vo.Validate(&jwtclaim.Standard{Audience: "admin"}))
+		assert.NoError(t, vo.Validate(jwtclaim.Map{"aud": []interface{}{"api", "shop"}}))
+		assert.True(t, claimErrors(t, vo.Validate(jwtclaim.Map{"aud": []interface{}{"api"}})).Has("aud"))
+		assert.True(t, claimErrors(t, vo.Validate(jwtclaim.Map{"aud": []interface{}{"shop", 1.0}})).Has("aud"))
+		assert.True(t, claimErrors(t, vo.Validate(&jwtclaim.Standard{})).Has("aud"))
+	})
+
+	t.Run("aggregated errors", func(t *testing.T) {
+		vo := &csjwt.ValidationOptions{
+			ExpectedIssuer:    "https://idp.example.com",
+			RequireExpiration: true,
+			Custom: []func(csjwt.Claimer) error{
+				func(c csjwt.Claimer) error {
+					if sub, _ := c.Get("sub"); sub != "gopher" {
+						return &csjwt.ClaimError{Claim: "sub", Err: errors.NotValid.Newf("unknown subject %q", sub)}
+					}
+					return nil
+				},
+				func(c csjwt.Claimer) error { return c.Valid() },
+			},
+		}
+		ce := claimErrors(t, vo.Validate(&jwtclaim.Standard{Issuer: "https://evil.example.com", Subject: "bob"}))
+		assert.Len(t, ce, 3)
+		assert.Exactly(t, "exp", ce[0].Claim)
+		assert.Exactly(t, "iss", ce[1].Claim)
+		assert.Exactly(t, "sub", ce[2].Claim)
+		assert.Contains(t, ce.Error(), `unknown subject "bob"`)
+
+		ce = claimErrors(t, vo.Validate(jwtclaim.Map{"iss": "https://idp.example.com", "sub": "gopher", "exp": float64(now - 60)}))
+		assert.Len(t, ce, 2)
+		assert.Exactly(t, "exp", ce[0].Claim)
+		assert.Exactly(t, "custom", ce[1].Claim)
+
+		assert.NoError(t, vo.Validate(jwtclaim.Map{"iss": "https://idp.example.com", "sub": "gopher", "exp": float64(now + 60)}))
+	})
+
+	t.Run("invalid claim types", func(t *testing.T) {
+		ce := claimErrors(t, (&csjwt.ValidationOptions{ExpectedIssuer: "a"}).Validate(jwtclaim.Map{"exp": "tomorrow", "iss": 1.0}))
+		assert.True(t, ce.Has("exp"))
+		assert.True(t, ce.Has("iss"))
+	})
+}
+
+func TestVerification_ValidationOptions(t *testing.T) {
+	hs256 := csjwt.NewSigningMethodHS256()
+	pw := csjwt.WithPasswordRandom()
+	raw, err := csjwt.NewToken(jwtclaim.Map{
+		"aud": []string{"api", "shop"},
+		"exp": time.Now().Add(-2 * time.Second).Unix(),
+	}).SignedString(hs256, pw)
+	assert.NoError(t, err)
+
+	vf := csjwt.NewVerification(hs256)
+	err = vf.Parse(csjwt.NewToken(&jwtclaim.Map{}), raw, csjwt.NewKeyFunc(hs256, pw))
+	assert.ErrorIsKind(t, errors.NotValid, err)
+
+	vf.ValidationOptions = &csjwt.ValidationOptions{
+		Leeway:            5 * time.Second,
+		ExpectedAudiences: []string{"shop"},
+		RequireExpiration: true,
+	}
+	tk := csjwt.NewToken(&jwtclaim.Map{})
+	assert.NoError(t, vf.Parse(tk, raw, csjwt.NewKeyFunc(hs256, pw)))
+	assert.True(t, tk.Valid)
+
+	vf.ValidationOptions.ExpectedAudiences = []string{"admin"}
+	err = vf.Parse(csjwt.NewToken(&jwtclaim.Map{}), raw, csjwt.NewKeyFunc(hs256, pw))
+	assert.True(t, claimErrors(t, err).Has("aud"))
+}
@@ -1,6 +1,8 @@
 package pseudo
 
 import (
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/corestoreio/pkg/util/assert"
@@ -49,3 +51,46 @@ func TestAddresses(t *testing.T) {
 
 	}
 }
+
+func TestLocales(t *testing.T) {
+	tests := []struct {
+		lang  string
+		zip   string
+		phone string
+	}{
+		{"fr", `^\d{5}$`, `^(0[1-7]( \d{2}){4}|\+33 [167]( \d{2}){4})$`},
+		{"it", `^\d{5}$`, `^\+39 (3\d{2} \d{3} \d{4}|0\d \d{4} \d{4}|0\d{2} \d{3} \d{4})$`},
+		{"es", `^(0[1-9]|[1-4]\d|5[0-2])\d{3}$`, `^\+34 [679]\d{2} \d{3} \d{3}$`},
+		{"nl", `^[1-9]\d{3} [A-Z]{2}$`, `^\+31 (6 \d{8}|[1-4]0 \d{3} \d{4})$`},
+		{"pl", `^\d{2}-\d{3}$`, `^\+48 ([5-8]\d{2} \d{3} \d{3}|22 \d{3} \d{2} \d{2})$`},
+		{"pt", `^[1-48]\d{3}-\d{3}$`, `^\+351 (9[136]|2[12])\d \d{3} \d{3}$`},
+		{"pt_BR", `^\d{5}-\d{3}$`, `^\+55 [1-8]1 9\d{4}-\d{4}$`},
+	}
+	for _, test := range tests {
+		s := MustNewService(0, &Options{Lang: test.lang, EnFallback: true})
+		zipRE := regexp.MustCompile(test.zip)
+		phoneRE := regexp.MustCompile(test.phone)
+		for i := 0; i < 100; i++ {
+			v := s.Zip()
+			assert.True(t, zipRE.MatchString(v), "Zip %q failed with lang %s", v, test.lang)
+			v = s.Phone()
+			assert.True(t, phoneRE.MatchString(v), "Phone %q failed with lang %s", v, test.lang)
+		}
+		assert.NotEmpty(t, s.FullName(), "FullName failed with lang %s", test.lang)
+		assert.NotEmpty(t, s.StreetAddress(), "StreetAddress failed with lang %s", test.lang)
+	}
+
+	t.Run("region falls back to base language", func(t *testing.T) {
+		s := MustNewService(0, &Options{Lang: "pt_BR"})
+		data, err := Asset("data/pt/male_first_names.txt")
+		assert.NoError(t, err)
+		names := strings.Split(strings.TrimSpace(string(data)), "\n")
+		for i := 0; i < 20; i++ {
+			assert.Contains(t, names, s.MaleFirstName())
+		}
+		assert.Empty(t, s.Brand(), "pt_BR must not fall back to en")
+
+		s = MustNewService(0, &Options{Lang: "pt_BR", EnFallback: true})
+		assert.NotEmpty(t, s.Brand())
+	})
+}
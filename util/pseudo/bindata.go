@@ -83,6 +83,83 @@
 // data/en/weekdays_short.txt
 // data/en/words.txt
 // data/en/zips_format.txt
+// data/es/cities.txt
+// data/es/female_first_names.txt
+// data/es/female_last_names.txt
+// data/es/female_patronymics.txt
+// data/es/male_first_names.txt
+// data/es/male_last_names.txt
+// data/es/male_patronymics.txt
+// data/es/phones_format.txt
+// data/es/states.txt
+// data/es/street_suffixes.txt
+// data/es/streets.txt
+// data/es/zips_format.txt
+// data/fr/cities.txt
+// data/fr/female_first_names.txt
+// data/fr/female_last_names.txt
+// data/fr/female_patronymics.txt
+// data/fr/male_first_names.txt
+// data/fr/male_last_names.txt
+// data/fr/male_patronymics.txt
+// data/fr/phones_format.txt
+// data/fr/states.txt
+// data/fr/street_suffixes.txt
+// data/fr/streets.txt
+// data/fr/zips_format.txt
+// data/it/cities.txt
+// data/it/female_first_names.txt
+// data/it/female_last_names.txt
+// data/it/female_patronymics.txt
+// data/it/male_first_names.txt
+// data/it/male_last_names.txt
+// data/it/male_patronymics.txt
+// data/it/phones_format.txt
+// data/it/states.txt
+// data/it/street_suffixes.txt
+// data/it/streets.txt
+// data/it/zips_format.txt
+// data/nl/cities.txt
+// data/nl/female_first_names.txt
+// data/nl/female_last_names.txt
+// data/nl/female_patronymics.txt
+// data/nl/male_first_names.txt
+// data/nl/male_last_names.txt
+// data/nl/male_patronymics.txt
+// data/nl/phones_format.txt
+// data/nl/states.txt
+// data/nl/street_suffixes.txt
+// data/nl/streets.txt
+// data/nl/zips_format.txt
+// data/pl/cities.txt
+// data/pl/female_first_names.txt
+// data/pl/female_last_names.txt
+// data/pl/female_patronymics.txt
+// data/pl/male_first_names.txt
+// data/pl/male_last_names.txt
+// data/pl/male_patronymics.txt
+// data/pl/phones_format.txt
+// data/pl/states.txt
+// data/pl/street_suffixes.txt
+// data/pl/streets.txt
+// data/pl/zips_format.txt
+// data/pt/cities.txt
+// data/pt/female_first_names.txt
+// data/pt/female_last_names.txt
+// data/pt/female_patronymics.txt
+// data/pt/male_first_names.txt
+// data/pt/male_last_names.txt
+// data/pt/male_patronymics.txt
+// data/pt/phones_format.txt
+// data/pt/states.txt
+// data/pt/street_suffixes.txt
+// data/pt/streets.txt
+// data/pt/zips_format.txt
+// data/pt_BR/cities.txt
+// data/pt_BR/phones_format.txt
+// data/pt_BR/states.txt
+// data/pt_BR/streets.txt
+// data/pt_BR/zips_format.txt
 // data/ru/characters.txt
 // data/ru/cities.txt
 // data/ru/colors.txt
@@ -5298,6 +5375,2535 @@ func bindataDataEnZipsformattxt() (*asset, error) {
 	return a, nil
 }
 
+var _bindataDataEsCitiestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8e\xb1\x0d\x83\x40\x10\x04\xf3\xab\xe2\x1b\x70\x11\x40\x40\x62\x24" +
+		"\x4b\x48\x04\xce\x96\xff\x17\x3a\xeb\xf8\xb3\x0e\x70\x40\x37\x2e\xc1\xa2\x84\x6f\xcc\x0f\xd1\xad\x74\xbb\x3b\xdb" +
+		"\x21\x18\x07\xaa\x61\x3e\x8a\x26\xd0\x00\x89\xc9\x33\xa8\x8f\x1f\x16\x01\x3d\x61\x98\x74\x07\x75\xf9\x2b\x98\xca" +
+		"\xdd\xec\xfc\x3f\x20\x33\xe8\x8e\xc5\x5d\x6a\x71\x21\xba\xd6\x90\x5c\x83\x04\x2b\x86\x9a\x65\x84\x52\x25\xec\x91" +
+		"\xd6\x48\x4d\x3e\x2c\xe8\x78\x21\x04\x41\xa5\x70\x07\x9e\x94\x5a\x7e\xe5\x23\xd1\x19\x46\x00\x55\xae\x51\xdb\xf2" +
+		"\xaf\x18\x79\xd5\xd2\x74\x6b\xb1\xac\x91\x77\xea\x4b\x0f\x52\x88\x56\xe0\xf3\xfb\x9a\xfb\x07\x46\x0f\xdf\xeb\xc0" +
+		"\x00\x00\x00")
+
+func bindataDataEsCitiestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsCitiestxt,
+		"data/es/cities.txt",
+	)
+}
+
+func bindataDataEsCitiestxt() (*asset, error) {
+	bytes, err := bindataDataEsCitiestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/cities.txt",
+		size:        192,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsFemalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8c\x41\x0a\xc3\x30\x0c\x04\xef\xfb\xab\x50\x72\x70\x89\x43\x48\x5f" +
+		"\xb0\x29\x0a\x18\x14\x87\xca\xd6\xb3\xfa\x8a\x7e\xac\xf2\x75\x76\x66\x33\xed\xf7\x25\x1e\xb4\x4b\x2a\xa6\x4a\xa4" +
+		"\xc6\x43\x14\x0b\xdd\x82\x5b\x69\xbd\x04\xcd\xb4\x4e\x2c\xfe\x1e\xf6\xac\x12\x68\xa3\x2b\xf1\x62\x68\x3b\x3f\x1e" +
+		"\xcd\x56\x94\x86\xfd\x6e\xc4\xea\x56\x62\xbc\xcf\xe1\x8f\x78\x9c\x3c\x5d\x03\x26\x93\x2a\x98\xf4\x20\xfe\x0a\x07" +
+		"\x88\x5d\x7e\x00\x00\x00")
+
+func bindataDataEsFemalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsFemalefirstnamestxt,
+		"data/es/female_first_names.txt",
+	)
+}
+
+func bindataDataEsFemalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataEsFemalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/female_first_names.txt",
+		size:        126,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsFemalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x45\x8e\xb1\x0e\xc2\x30\x0c\x44\x77\xff\x15\x52\x45\x11\xa2\x08\x05\xc4" +
+		"\x1e\xb5\x11\x54\x6a\x62\x70\x9b\x0e\xd9\xf8\x94\x8e\x1d\x3a\xa0\x7e\x82\x7f\x8c\x0b\x4b\x27\x3f\xeb\xce\x77\x2e" +
+		"\xad\xd4\xba\x58\x32\xdc\x88\x2e\x8f\xe8\x12\x95\x1c\x92\x4e\x1d\x68\xef\x24\xe8\x14\x1a\xe0\x49\xd7\x17\x46\x65" +
+		"\x65\xd0\x25\x80\xae\x10\xea\x27\xe0\xa2\xb3\xe4\x2b\x5d\xfd\x66\xa0\x63\xeb\x75\xce\x3e\x13\xdb\x44\x87\x2d\xa8" +
+		"\x40\x1b\x6c\x2c\x2e\x30\x55\x51\xbf\x9c\x48\x3f\xdd\x68\x73\x88\x61\xef\x84\x69\xd7\x71\xe8\x99\xca\x38\xb4\x08" +
+		"\xcf\xc2\xd9\xc2\x00\xe5\xc6\x58\x7b\x2a\xd8\xa3\xe4\xff\xed\x5d\xa7\xf4\xce\x60\xac\xe7\x9e\x7e\x7f\xa6\x5e\x6a" +
+		"\xd0\x00\x00\x00")
+
+func bindataDataEsFemalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsFemalelastnamestxt,
+		"data/es/female_last_names.txt",
+	)
+}
+
+func bindataDataEsFemalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataEsFemalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/female_last_names.txt",
+		size:        208,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsFemalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8d\x41\x0a\x42\x31\x10\x43\xf7\x73\xab\xe2\x47\xa4\xf0\x41\xf4\x04" +
+		"\xf1\x77\x2c\x23\x65\x06\xa6\xf6\xef\x3d\x8a\x4b\xaf\xe0\xb6\x17\x73\x5c\x25\x24\x8f\x24\xe9\xd3\x54\x8c\xb2\xf5" +
+		"\xf9\xa1\x15\x3a\xb8\xd1\xd1\xa1\x9b\xf4\xcd\x68\xc1\x2e\x85\xf2\x80\x52\x0e\xcb\x1e\x89\x4a\x20\x07\x78\xb3\x4e" +
+		"\x99\xfb\xfc\x76\x4a\x8d\x1f\xd0\xe2\x46\xab\xd4\xff\xc2\x05\x77\x84\x9c\x71\x6b\x46\x57\xf6\x1a\x17\xf3\xd5\x76" +
+		"\x04\x92\x8a\xcb\x7c\x2b\x9d\x46\x0d\x1e\x1e\xd5\x22\x1c\xfe\x07\xe2\xc7\x1b\x4f\x8c\x00\x00\x00")
+
+func bindataDataEsFemalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsFemalepatronymicstxt,
+		"data/es/female_patronymics.txt",
+	)
+}
+
+func bindataDataEsFemalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataEsFemalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/female_patronymics.txt",
+		size:        140,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsMalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8d\x41\x0a\x42\x31\x10\x43\xf7\x73\xab\xe2\x47\xa4\xf0\x41\xf4\x04" +
+		"\xf1\x77\x2c\x23\x65\x06\xa6\xf6\xef\x3d\x8a\x4b\xaf\xe0\xb6\x17\x73\x5c\x25\x24\x8f\x24\xe9\xd3\x54\x8c\xb2\xf5" +
+		"\xf9\xa1\x15\x3a\xb8\xd1\xd1\xa1\x9b\xf4\xcd\x68\xc1\x2e\x85\xf2\x80\x52\x0e\xcb\x1e\x89\x4a\x20\x07\x78\xb3\x4e" +
+		"\x99\xfb\xfc\x76\x4a\x8d\x1f\xd0\xe2\x46\xab\xd4\xff\xc2\x05\x77\x84\x9c\x71\x6b\x46\x57\xf6\x1a\x17\xf3\xd5\x76" +
+		"\x04\x92\x8a\xcb\x7c\x2b\x9d\x46\x0d\x1e\x1e\xd5\x22\x1c\xfe\x07\xe2\xc7\x1b\x4f\x8c\x00\x00\x00")
+
+func bindataDataEsMalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsMalefirstnamestxt,
+		"data/es/male_first_names.txt",
+	)
+}
+
+func bindataDataEsMalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataEsMalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/male_first_names.txt",
+		size:        140,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsMalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x45\x8e\xb1\x0e\xc2\x30\x0c\x44\x77\xff\x15\x52\x45\x11\xa2\x08\x05\xc4" +
+		"\x1e\xb5\x11\x54\x6a\x62\x70\x9b\x0e\xd9\xf8\x94\x8e\x1d\x3a\xa0\x7e\x82\x7f\x8c\x0b\x4b\x27\x3f\xeb\xce\x77\x2e" +
+		"\xad\xd4\xba\x58\x32\xdc\x88\x2e\x8f\xe8\x12\x95\x1c\x92\x4e\x1d\x68\xef\x24\xe8\x14\x1a\xe0\x49\xd7\x17\x46\x65" +
+		"\x65\xd0\x25\x80\xae\x10\xea\x27\xe0\xa2\xb3\xe4\x2b\x5d\xfd\x66\xa0\x63\xeb\x75\xce\x3e\x13\xdb\x44\x87\x2d\xa8" +
+		"\x40\x1b\x6c\x2c\x2e\x30\x55\x51\xbf\x9c\x48\x3f\xdd\x68\x73\x88\x61\xef\x84\x69\xd7\x71\xe8\x99\xca\x38\xb4\x08" +
+		"\xcf\xc2\xd9\xc2\x00\xe5\xc6\x58\x7b\x2a\xd8\xa3\xe4\xff\xed\x5d\xa7\xf4\xce\x60\xac\xe7\x9e\x7e\x7f\xa6\x5e\x6a" +
+		"\xd0\x00\x00\x00")
+
+func bindataDataEsMalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsMalelastnamestxt,
+		"data/es/male_last_names.txt",
+	)
+}
+
+func bindataDataEsMalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataEsMalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/male_last_names.txt",
+		size:        208,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsMalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8d\x41\x0a\x42\x31\x10\x43\xf7\x73\xab\xe2\x47\xa4\xf0\x41\xf4\x04" +
+		"\xf1\x77\x2c\x23\x65\x06\xa6\xf6\xef\x3d\x8a\x4b\xaf\xe0\xb6\x17\x73\x5c\x25\x24\x8f\x24\xe9\xd3\x54\x8c\xb2\xf5" +
+		"\xf9\xa1\x15\x3a\xb8\xd1\xd1\xa1\x9b\xf4\xcd\x68\xc1\x2e\x85\xf2\x80\x52\x0e\xcb\x1e\x89\x4a\x20\x07\x78\xb3\x4e" +
+		"\x99\xfb\xfc\x76\x4a\x8d\x1f\xd0\xe2\x46\xab\xd4\xff\xc2\x05\x77\x84\x9c\x71\x6b\x46\x57\xf6\x1a\x17\xf3\xd5\x76" +
+		"\x04\x92\x8a\xcb\x7c\x2b\x9d\x46\x0d\x1e\x1e\xd5\x22\x1c\xfe\x07\xe2\xc7\x1b\x4f\x8c\x00\x00\x00")
+
+func bindataDataEsMalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsMalepatronymicstxt,
+		"data/es/male_patronymics.txt",
+	)
+}
+
+func bindataDataEsMalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataEsMalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/male_patronymics.txt",
+		size:        140,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsPhonesformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd3\x36\x36\x51\x30\x53\x56\x56\x50\x86\x60\x2e\x6d\x20\xdf\x1c\x8d\x6f" +
+		"\x69\x88\xc6\x37\x46\xe3\x9b\x22\xf8\x00\x19\x35\xed\x1d\x50\x00\x00\x00")
+
+func bindataDataEsPhonesformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsPhonesformattxt,
+		"data/es/phones_format.txt",
+	)
+}
+
+func bindataDataEsPhonesformattxt() (*asset, error) {
+	bytes, err := bindataDataEsPhonesformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/phones_format.txt",
+		size:        80,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsStatestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x8d\x41\x0a\xc2\x30\x10\x45\xf7\x73\x8a\x5c\xc0\x43\xd4\x22\x22\x54" +
+		"\x91\x2e\xdc\x7f\x93\x50\x47\xd2\x14\x26\x89\xe8\xa1\xba\x10\x8f\x90\x8b\x39\xed\x46\x77\x9f\x37\xf3\xdf\x6f\xa2" +
+		"\x43\x28\xb6\xce\xa0\x46\x30\xd4\x4f\xa4\x26\xe5\x22\x8c\x44\x87\x14\x90\xcc\x16\xc1\x43\x7c\xa2\x16\x11\x2b\xd7" +
+		"\x90\x71\xd5\xa8\x29\x65\x0e\x01\x9b\x0e\xe6\x88\x68\x6f\x3f\x64\x5e\xa6\xf3\x8b\xae\x45\xd6\x85\xfa\xd6\xd3\x34" +
+		"\x96\xc8\x0e\xce\x5c\xd4\x19\x2d\xab\x90\x76\xcf\x2c\x7e\x84\x2b\x02\xda\x23\xb0\x52\x52\x5b\xcf\xd3\xfd\xbf\xe1" +
+		"\xbc\x0e\x38\x61\x47\xbd\x1f\x58\xbd\x2b\x29\xb2\xbc\x9f\xf0\x80\x68\xfd\x8c\x3a\x27\x75\x27\x3b\xd1\x17\x56\xf8" +
+		"\xb3\x85\xd8\x00\x00\x00")
+
+func bindataDataEsStatestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsStatestxt,
+		"data/es/states.txt",
+	)
+}
+
+func bindataDataEsStatestxt() (*asset, error) {
+	bytes, err := bindataDataEsStatestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/states.txt",
+		size:        216,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsStreetsuffixestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+func bindataDataEsStreetsuffixestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsStreetsuffixestxt,
+		"data/es/street_suffixes.txt",
+	)
+}
+
+func bindataDataEsStreetsuffixestxt() (*asset, error) {
+	bytes, err := bindataDataEsStreetsuffixestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/street_suffixes.txt",
+		size:        0,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsStreetstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x90\x4d\x0a\xc2\x30\x10\x85\xf7\x39\x45\xae\x52\x22\x88\x82\x52\x5a" +
+		"\x70\xff\x6c\x87\x12\x99\xce\x40\x92\x16\xf4\x36\x1e\xc0\x95\x47\xe8\xc5\x4c\xd5\x6a\xb7\x8f\x6f\xde\xcf\x38\x30" +
+		"\x93\x3d\xe0\xaa\xc1\x6c\x03\xc4\x9e\xa6\x07\x8c\x7b\xab\x2d\xd9\x82\x1b\xf0\x74\x37\x25\x22\xe9\x2c\x30\xac\x43" +
+		"\x4c\xc4\x0c\x59\xb8\x8a\xc0\xa6\x18\x49\x7c\x8b\x85\x51\x89\xc9\xa7\xa1\xf1\xd3\x53\x4c\xc9\xb8\xe1\x1b\xb2\x58" +
+		"\xb3\xad\x95\x4d\x85\xfe\xcc\xef\x23\x87\x04\x1e\xe4\xba\x0a\xaf\x29\xe4\x46\xfa\xb3\xde\x78\x74\x2a\x39\xeb\x43" +
+		"\x1c\x07\x1a\x57\x74\xb6\xd9\x75\x4c\xd1\xcf\x5a\xef\x45\xed\xc9\xd3\x45\xbf\x40\x9d\xa7\xed\x07\xc8\x6f\x09\xdb" +
+		"\x32\xa0\xd5\x75\xef\x42\xda\x5c\xa1\xf9\x3f\xc0\x51\x18\x21\x89\xa2\x79\x01\xbe\x05\x28\x56\x27\x01\x00\x00")
+
+func bindataDataEsStreetstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsStreetstxt,
+		"data/es/streets.txt",
+	)
+}
+
+func bindataDataEsStreetstxt() (*asset, error) {
+	bytes, err := bindataDataEsStreetstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/streets.txt",
+		size:        295,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataEsZipsformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x33\xb2\x50\x56\x56\xe6\x32\x00\x93\x26\x66\x60\xd2\x10\x44\x9a\x1a\x80" +
+		"\x48\x23\x4b\x10\x69\x0c\x66\x1b\x98\x83\xd9\xa6\x60\x35\x60\xf5\x00\x98\x34\x7e\x87\x3c\x00\x00\x00")
+
+func bindataDataEsZipsformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataEsZipsformattxt,
+		"data/es/zips_format.txt",
+	)
+}
+
+func bindataDataEsZipsformattxt() (*asset, error) {
+	bytes, err := bindataDataEsZipsformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/es/zips_format.txt",
+		size:        60,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrCitiestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x25\x8e\xd1\x09\xc2\x40\x10\x44\xff\xb7\x0a\x1b\x48\x11\x51\x51\x3f\x2e" +
+		"\x21\x44\x1b\xd8\x98\x21\x9c\x5c\x76\x65\x2f\x17\x62\x53\x36\x61\x63\x2e\xfa\x33\x30\x30\xef\x31\x1d\x5b\xcc\xd4" +
+		"\xb0\x65\xc4\x94\x40\xe1\xa5\x42\x37\x2d\x49\x4b\x06\xb5\xf1\xee\xc1\xb2\x20\xd3\x75\x31\xce\x83\x16\x9b\xa8\x51" +
+		"\x59\x9e\x48\x29\xc2\x68\xaf\x36\x82\xcb\x46\xe1\xc7\xf7\x10\xf1\x71\x8f\x38\x67\x0a\xd8\x5d\x78\x35\xfc\x85\x42" +
+		"\x67\x83\xe8\xe0\xab\x63\x7c\x78\xad\x65\x82\x65\x6a\x3f\xef\xd9\x91\x43\x82\xcd\x2e\xae\x4e\x30\x63\x19\xa9\x8e" +
+		"\x5b\x05\xa9\x3a\xd3\x15\xe2\x3f\xbe\xc7\x24\x8e\xcb\xab\x00\x00\x00")
+
+func bindataDataFrCitiestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrCitiestxt,
+		"data/fr/cities.txt",
+	)
+}
+
+func bindataDataFrCitiestxt() (*asset, error) {
+	bytes, err := bindataDataFrCitiestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/cities.txt",
+		size:        171,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrFemalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x8c\xcb\x0d\xc2\x40\x0c\x44\xef\xee\x0a\xe5\x14\x94\x70\x49\x05\x46" +
+		"\x58\xac\x25\x67\x8d\xf6\x83\xa0\x04\x3a\x61\xeb\x70\x63\xb1\x11\x97\xf9\xe8\x69\x66\xc5\xc2\x04\x17\x6c\x09\xc5" +
+		"\xc3\x5c\xf1\x4a\x22\x04\xdb\x5b\x9e\xde\x27\x07\x54\x38\x47\xda\x39\x80\x7d\xdc\x9d\x9c\x7b\xe8\x94\x44\x6d\xc0" +
+		"\x62\x03\x61\xc5\xac\x19\xe6\x6c\xdf\x0a\x9b\x3e\x52\x60\x1b\xf2\x1b\x0b\x72\x21\x38\xed\xd1\x09\x16\xed\x5c\xff" +
+		"\x17\xad\x91\x2f\xcb\x1d\xfb\xcb\xbf\x45\x6f\xce\x0f\xe9\x4a\x6e\x37\x95\x00\x00\x00")
+
+func bindataDataFrFemalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrFemalefirstnamestxt,
+		"data/fr/female_first_names.txt",
+	)
+}
+
+func bindataDataFrFemalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataFrFemalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/female_first_names.txt",
+		size:        149,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrFemalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8d\x41\x0e\xc2\x30\x0c\x04\xef\xfe\x15\x28\x82\x4b\x23\xa1\x82\xb8" +
+		"\xbb\x8d\x51\x2d\x51\x1b\x6d\x93\x0a\x7e\x04\xef\xe8\xc7\x48\x7a\x9e\x9d\xd9\xc8\xc8\x6a\x74\x14\x18\x23\x51\x28" +
+		"\x83\xeb\x42\xb7\xc9\x67\x5e\xa8\xf7\x41\x90\xa9\xd7\x71\x6a\xf0\x22\x59\x73\x9d\x80\x2d\x51\x27\xf0\x0f\x45\x87" +
+		"\x70\xa1\xab\xce\x6e\xd4\x71\x81\x58\xae\xe8\xb1\x7d\x57\x08\xc5\x2a\xca\x93\xce\x8c\x51\x99\x02\xaf\x9a\xda\x53" +
+		"\xde\x03\xbd\x97\x37\xdd\xd5\xc6\xa6\x9c\xbc\xc0\x54\xb0\x07\xab\xa1\x68\x87\x07\x4b\xd8\x7e\x14\xa5\xfa\x95\x85" +
+		"\xf2\xf2\xba\xfd\x03\x0b\x63\x54\x70\xb3\x00\x00\x00")
+
+func bindataDataFrFemalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrFemalelastnamestxt,
+		"data/fr/female_last_names.txt",
+	)
+}
+
+func bindataDataFrFemalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataFrFemalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/female_last_names.txt",
+		size:        179,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrFemalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8d\x41\x0a\x02\x31\x0c\x45\xf7\xff\x56\xe3\x42\x65\x18\x65\x10\x2f" +
+		"\x10\x6b\xb0\x81\x9a\x94\x4c\x73\x26\x19\x3c\x46\x2f\x66\x5d\x3e\x78\xef\xff\x99\x49\xb1\x0a\xbb\x33\x2e\x92\x32" +
+		"\x17\x4c\xfa\xf4\xbe\x63\xcd\x52\xa4\x56\xc6\x62\x21\x1b\xae\x92\xac\xd0\x86\x39\x8a\xb0\xe2\x9e\xed\x3d\x68\xd2" +
+		"\x66\xa2\x23\xa5\x96\x85\x03\x47\x27\xed\x1f\x1b\xfe\x42\xe1\xac\x0d\xe7\x78\x19\x96\x48\x43\x3e\xd1\xc3\xe5\x7f" +
+		"\xe0\x2d\x87\x8f\x89\xbe\x1b\x6e\x54\x33\xf5\x6f\xc1\x81\x6a\x93\xad\x31\x7e\x14\x36\x16\x64\x93\x00\x00\x00")
+
+func bindataDataFrFemalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrFemalepatronymicstxt,
+		"data/fr/female_patronymics.txt",
+	)
+}
+
+func bindataDataFrFemalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataFrFemalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/female_patronymics.txt",
+		size:        147,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrMalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8d\x41\x0a\x02\x31\x0c\x45\xf7\xff\x56\xe3\x42\x65\x18\x65\x10\x2f" +
+		"\x10\x6b\xb0\x81\x9a\x94\x4c\x73\x26\x19\x3c\x46\x2f\x66\x5d\x3e\x78\xef\xff\x99\x49\xb1\x0a\xbb\x33\x2e\x92\x32" +
+		"\x17\x4c\xfa\xf4\xbe\x63\xcd\x52\xa4\x56\xc6\x62\x21\x1b\xae\x92\xac\xd0\x86\x39\x8a\xb0\xe2\x9e\xed\x3d\x68\xd2" +
+		"\x66\xa2\x23\xa5\x96\x85\x03\x47\x27\xed\x1f\x1b\xfe\x42\xe1\xac\x0d\xe7\x78\x19\x96\x48\x43\x3e\xd1\xc3\xe5\x7f" +
+		"\xe0\x2d\x87\x8f\x89\xbe\x1b\x6e\x54\x33\xf5\x6f\xc1\x81\x6a\x93\xad\x31\x7e\x14\x36\x16\x64\x93\x00\x00\x00")
+
+func bindataDataFrMalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrMalefirstnamestxt,
+		"data/fr/male_first_names.txt",
+	)
+}
+
+func bindataDataFrMalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataFrMalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/male_first_names.txt",
+		size:        147,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrMalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8d\x41\x0e\xc2\x30\x0c\x04\xef\xfe\x15\x28\x82\x4b\x23\xa1\x82\xb8" +
+		"\xbb\x8d\x51\x2d\x51\x1b\x6d\x93\x0a\x7e\x04\xef\xe8\xc7\x48\x7a\x9e\x9d\xd9\xc8\xc8\x6a\x74\x14\x18\x23\x51\x28" +
+		"\x83\xeb\x42\xb7\xc9\x67\x5e\xa8\xf7\x41\x90\xa9\xd7\x71\x6a\xf0\x22\x59\x73\x9d\x80\x2d\x51\x27\xf0\x0f\x45\x87" +
+		"\x70\xa1\xab\xce\x6e\xd4\x71\x81\x58\xae\xe8\xb1\x7d\x57\x08\xc5\x2a\xca\x93\xce\x8c\x51\x99\x02\xaf\x9a\xda\x53" +
+		"\xde\x03\xbd\x97\x37\xdd\xd5\xc6\xa6\x9c\xbc\xc0\x54\xb0\x07\xab\xa1\x68\x87\x07\x4b\xd8\x7e\x14\xa5\xfa\x95\x85" +
+		"\xf2\xf2\xba\xfd\x03\x0b\x63\x54\x70\xb3\x00\x00\x00")
+
+func bindataDataFrMalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrMalelastnamestxt,
+		"data/fr/male_last_names.txt",
+	)
+}
+
+func bindataDataFrMalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataFrMalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/male_last_names.txt",
+		size:        179,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrMalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8d\x41\x0a\x02\x31\x0c\x45\xf7\xff\x56\xe3\x42\x65\x18\x65\x10\x2f" +
+		"\x10\x6b\xb0\x81\x9a\x94\x4c\x73\x26\x19\x3c\x46\x2f\x66\x5d\x3e\x78\xef\xff\x99\x49\xb1\x0a\xbb\x33\x2e\x92\x32" +
+		"\x17\x4c\xfa\xf4\xbe\x63\xcd\x52\xa4\x56\xc6\x62\x21\x1b\xae\x92\xac\xd0\x86\x39\x8a\xb0\xe2\x9e\xed\x3d\x68\xd2" +
+		"\x66\xa2\x23\xa5\x96\x85\x03\x47\x27\xed\x1f\x1b\xfe\x42\xe1\xac\x0d\xe7\x78\x19\x96\x48\x43\x3e\xd1\xc3\xe5\x7f" +
+		"\xe0\x2d\x87\x8f\x89\xbe\x1b\x6e\x54\x33\xf5\x6f\xc1\x81\x6a\x93\xad\x31\x7e\x14\x36\x16\x64\x93\x00\x00\x00")
+
+func bindataDataFrMalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrMalepatronymicstxt,
+		"data/fr/male_patronymics.txt",
+	)
+}
+
+func bindataDataFrMalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataFrMalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/male_patronymics.txt",
+		size:        147,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrPhonesformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x33\x30\x54\x50\x56\x86\x23\x2e\x03\x23\x54\xae\x31\x2a\xd7\x04\x95\x6b" +
+		"\x8a\xca\x35\x43\xe5\x9a\xa3\x70\xb5\x8d\x8d\x15\x0c\x31\x44\xcc\x30\x44\x50\x75\x01\x00\xc3\xfa\xb3\x6b\x9f\x00" +
+		"\x00\x00")
+
+func bindataDataFrPhonesformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrPhonesformattxt,
+		"data/fr/phones_format.txt",
+	)
+}
+
+func bindataDataFrPhonesformattxt() (*asset, error) {
+	bytes, err := bindataDataFrPhonesformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/phones_format.txt",
+		size:        159,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrStatestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x4e\x3b\x0e\xc2\x30\x0c\xdd\x73\x8a\x6c\x4c\x39\x44\x5a\xf1\x19\x10" +
+		"\x20\x06\x76\xab\xb1\xda\x48\x69\x0c\x4e\x52\x09\x0e\xc1\x3d\xd8\x7b\x83\x5c\x0c\x57\x74\x60\xf2\xf3\x7b\xf6\x7b" +
+		"\xcf\x96\x09\xb9\x8f\x68\xae\x43\x9d\x65\xd8\x70\xc7\xa4\x1a\x2a\xdc\xd3\x42\xef\x18\x62\x37\xa0\x69\x69\xcc\xf5" +
+		"\xa3\x1a\xc6\x0c\xc2\xab\x16\x63\x66\x34\x37\x08\xda\xa1\x3e\x92\x67\xe1\x88\x13\xaa\xbd\x7c\x38\xbd\x4d\x59\x1d" +
+		"\xa0\xe4\x64\xdc\x6a\x82\xaa\xbe\x03\xfe\xad\x27\xe2\x51\x4e\xfd\x82\xa4\x45\x10\xd1\x3e\x8a\xcf\xe0\xc5\xff\xdc" +
+		"\x75\x82\xa2\x88\x17\x78\xa6\x25\x23\xc0\x1a\x73\x61\x9a\x50\x0c\x7e\x5d\x4d\x5b\xe7\x8c\xda\x6d\xec\xab\xb0\xfa" +
+		"\x02\x2d\x70\xdb\xfb\xcf\x00\x00\x00")
+
+func bindataDataFrStatestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrStatestxt,
+		"data/fr/states.txt",
+	)
+}
+
+func bindataDataFrStatestxt() (*asset, error) {
+	bytes, err := bindataDataFrStatestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/states.txt",
+		size:        207,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrStreetsuffixestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+func bindataDataFrStreetsuffixestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrStreetsuffixestxt,
+		"data/fr/street_suffixes.txt",
+	)
+}
+
+func bindataDataFrStreetsuffixestxt() (*asset, error) {
+	bytes, err := bindataDataFrStreetsuffixestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/street_suffixes.txt",
+		size:        0,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrStreetstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x8f\x51\x6e\x83\x40\x0c\x44\xff\x39\xc5\x5e\x20\x87\x48\x91\x9a\x34" +
+		"\xaa\x2a\x4a\x23\xfe\x1d\xb0\x88\x25\xb3\xa6\x6b\x8c\xda\x23\xe4\x26\xe5\x1c\x7b\xb1\xae\x16\x90\xf2\xb7\x33\xfb" +
+		"\x3c\xf6\xd4\x86\xae\x43\xc7\xe0\x2a\xa0\x9f\xa2\x5e\x65\x4d\xb3\x30\x15\xc7\x19\x7d\x36\xd4\x95\x77\x18\x46\x3d" +
+		"\xc4\x07\xff\x6a\x5c\x50\x8b\x17\x31\xc6\x19\x42\xe7\xbe\x80\xfc\x74\x38\x61\x18\xd2\x63\x4d\x30\xf7\x0a\x76\x13" +
+		"\x0b\xfd\xf6\x7b\x16\x2f\x21\x2e\x7b\x7e\x5a\x57\xc7\x65\xb4\x1b\xd3\xb7\x61\x51\x31\xb4\xbb\x5f\x8a\x6f\x25\x74" +
+		"\x98\xd1\x86\xda\x49\x82\x3b\x5b\x2f\xfb\x31\x17\x04\xef\x2e\x60\x21\xfe\x69\x66\x2a\xd0\x09\x2d\x3c\x1d\xd4\x08" +
+		"\x4f\x40\x01\x9f\xb6\x9d\x20\xc9\xf2\x8e\x03\xf9\x5c\xa7\xa1\xde\xa7\x12\x47\xe6\x54\x26\x3b\x57\x62\x46\x63\xdd" +
+		"\x86\xd4\xc5\x47\x2b\x9c\x98\x4f\x03\xda\x52\xae\xa9\x91\xc7\xc4\x65\xe8\x03\x26\x12\x0f\x49\xbd\x0d\x23\xa8\xae" +
+		"\x63\xef\xc4\xa0\xc5\x3f\x19\x04\x7f\x2d\x57\x01\x00\x00")
+
+func bindataDataFrStreetstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrStreetstxt,
+		"data/fr/streets.txt",
+	)
+}
+
+func bindataDataFrStreetstxt() (*asset, error) {
+	bytes, err := bindataDataFrStreetstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/streets.txt",
+		size:        343,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataFrZipsformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x33\x37\x55\x56\x56\xe6\x32\x34\x06\x91\x66\x96\x20\xd2\xd8\x10\x44\x1a" +
+		"\x98\x81\x48\x13\x13\xb0\xb8\x39\x58\x1c\xcc\x36\x06\xab\x34\x05\xab\x04\x00\x38\x71\x73\x20\x3c\x00\x00\x00")
+
+func bindataDataFrZipsformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataFrZipsformattxt,
+		"data/fr/zips_format.txt",
+	)
+}
+
+func bindataDataFrZipsformattxt() (*asset, error) {
+	bytes, err := bindataDataFrZipsformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/fr/zips_format.txt",
+		size:        60,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItCitiestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8d\xcd\x0a\xc2\x40\x0c\x84\xef\x79\x0a\x9f\xa5\x05\x3d\x55\x4a\x29" +
+		"\xde\x47\x3b\x2c\x81\x6d\x22\xd9\xea\xa1\x4f\x6f\xd6\xd3\xc7\xfc\x31\x8b\xef\x90\x49\x2b\xcc\xe5\x8e\xb7\x57\x95" +
+		"\xd5\x43\x53\xcd\xa8\x8c\xdd\xe5\x46\xf3\x2f\x64\xf0\xea\xc5\x20\x57\x0d\xda\x49\x19\x10\x2a\x23\x0e\x98\x42\x1e" +
+		"\x34\x9e\x7f\x86\x67\x67\x62\x6b\x9a\x9c\xb1\xf5\xe9\x1a\xca\x76\xe4\x24\xd8\x5e\xda\xed\xe8\xa7\xbe\x31\x3b\x0b" +
+		"\x4b\x51\xbf\x8c\xa8\x78\x46\x0f\x19\x9f\x92\xfc\x01\x4a\x3f\xe0\x1a\x99\x00\x00\x00")
+
+func bindataDataItCitiestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItCitiestxt,
+		"data/it/cities.txt",
+	)
+}
+
+func bindataDataItCitiestxt() (*asset, error) {
+	bytes, err := bindataDataItCitiestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/cities.txt",
+		size:        153,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItFemalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8c\xbd\x0e\x83\x30\x0c\x84\xf7\xbc\x15\xaa\xa0\x4b\xb3\x00\xea\x7e" +
+		"\x0a\x47\x6b\x29\x24\xc8\x69\xfa\xfc\xd8\x2c\xd6\xf9\xbb\x9f\x08\x15\x84\xa1\x14\x84\xa7\xf4\xc6\xf3\x14\x93\x73" +
+		"\x6d\x0e\x3f\xcc\x8e\xeb\x1f\xee\xaf\x54\x1a\x7e\xf5\x64\x8d\x07\xf4\x70\x77\x52\x94\xc4\x96\xee\x7a\x76\xe3\x2b" +
+		"\x50\x84\xa5\xee\xbe\xdb\xb5\xda\x33\x64\x49\x0c\x11\xfa\xf3\xf1\x31\xd3\xee\xe2\xa9\x37\x4c\xdf\x70\xe2\x46\x15" +
+		"\x9b\xb9\x00\x42\x38\xc4\x37\x91\x00\x00\x00")
+
+func bindataDataItFemalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItFemalefirstnamestxt,
+		"data/it/female_first_names.txt",
+	)
+}
+
+func bindataDataItFemalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataItFemalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/female_first_names.txt",
+		size:        145,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItFemalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8e\x31\x0e\xc3\x40\x08\x04\x7b\x5e\x91\xb7\x38\x89\xdd\x38\xcd\xe5" +
+		"\x05\x18\x9f\x14\xa4\x33\x1b\xc1\x5d\xe3\xd7\x07\xa7\x43\xcb\xec\x40\x41\x84\x52\x19\x11\xa0\xb9\xba\xb3\x2b\x3d" +
+		"\xe3\x8b\xd0\x0e\x9a\x94\x4d\x3e\xb9\xc6\xc1\x06\xba\xa3\xe1\xd8\x40\x45\x45\x94\x5e\x89\x66\xb8\x78\x95\x24\x7d" +
+		"\x5c\x33\xb7\x76\x61\xd6\x95\x1e\xf5\xb6\x0e\xe1\xc4\x4c\xd4\x34\xd3\xe8\x4c\x8b\xc2\xf7\xcb\x55\xf4\x3c\x41\x6b" +
+		"\xfa\xd8\xf7\x94\xc1\x6b\xcf\xd6\xc4\xbe\x69\xcd\x1f\xe6\x94\xb0\x31\xbd\xd9\x3a\x1c\xff\x6b\x9c\x9a\xa2\xc6\x2d" +
+		"\x0b\x3f\xc8\x22\x44\x3e\xb8\x00\x00\x00")
+
+func bindataDataItFemalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItFemalelastnamestxt,
+		"data/it/female_last_names.txt",
+	)
+}
+
+func bindataDataItFemalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataItFemalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/female_last_names.txt",
+		size:        184,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItFemalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8c\xc1\x0a\xc3\x30\x0c\x43\xef\xfe\xab\xc1\x58\x2f\x2d\x8c\x75\xec" +
+		"\x6e\x12\xb7\x18\x52\xab\x38\x6e\x0e\xfb\xfa\x25\xbb\x08\x09\xe9\x69\xd2\xab\xca\x79\x0a\x4d\x8a\xc6\x66\x4a\x37" +
+		"\x0b\x98\x82\x16\xf6\xae\xf3\xa5\xbb\xd2\xc3\xd9\x92\xd4\x84\xde\xee\x52\x40\x1f\xed\xd9\xbe\xa0\xa7\x4a\x38\x68" +
+		"\xe5\xd2\x38\xe0\x32\xb0\x31\x2b\x52\x2b\x5b\xf6\x41\x64\x17\xa6\xb9\x97\x03\x58\x38\x42\xfa\xaf\xc0\xd8\x33\xe8" +
+		"\xa5\x29\xfd\xcd\x1b\xc7\xc1\x15\x74\xe7\xa6\x59\x68\x0d\xd9\xd8\x40\x3f\xc8\xed\x84\x25\xa1\x00\x00\x00")
+
+func bindataDataItFemalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItFemalepatronymicstxt,
+		"data/it/female_patronymics.txt",
+	)
+}
+
+func bindataDataItFemalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataItFemalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/female_patronymics.txt",
+		size:        161,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItMalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8c\xc1\x0a\xc3\x30\x0c\x43\xef\xfe\xab\xc1\x58\x2f\x2d\x8c\x75\xec" +
+		"\x6e\x12\xb7\x18\x52\xab\x38\x6e\x0e\xfb\xfa\x25\xbb\x08\x09\xe9\x69\xd2\xab\xca\x79\x0a\x4d\x8a\xc6\x66\x4a\x37" +
+		"\x0b\x98\x82\x16\xf6\xae\xf3\xa5\xbb\xd2\xc3\xd9\x92\xd4\x84\xde\xee\x52\x40\x1f\xed\xd9\xbe\xa0\xa7\x4a\x38\x68" +
+		"\xe5\xd2\x38\xe0\x32\xb0\x31\x2b\x52\x2b\x5b\xf6\x41\x64\x17\xa6\xb9\x97\x03\x58\x38\x42\xfa\xaf\xc0\xd8\x33\xe8" +
+		"\xa5\x29\xfd\xcd\x1b\xc7\xc1\x15\x74\xe7\xa6\x59\x68\x0d\xd9\xd8\x40\x3f\xc8\xed\x84\x25\xa1\x00\x00\x00")
+
+func bindataDataItMalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItMalefirstnamestxt,
+		"data/it/male_first_names.txt",
+	)
+}
+
+func bindataDataItMalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataItMalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/male_first_names.txt",
+		size:        161,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItMalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8e\x31\x0e\xc3\x40\x08\x04\x7b\x5e\x91\xb7\x38\x89\xdd\x38\xcd\xe5" +
+		"\x05\x18\x9f\x14\xa4\x33\x1b\xc1\x5d\xe3\xd7\x07\xa7\x43\xcb\xec\x40\x41\x84\x52\x19\x11\xa0\xb9\xba\xb3\x2b\x3d" +
+		"\xe3\x8b\xd0\x0e\x9a\x94\x4d\x3e\xb9\xc6\xc1\x06\xba\xa3\xe1\xd8\x40\x45\x45\x94\x5e\x89\x66\xb8\x78\x95\x24\x7d" +
+		"\x5c\x33\xb7\x76\x61\xd6\x95\x1e\xf5\xb6\x0e\xe1\xc4\x4c\xd4\x34\xd3\xe8\x4c\x8b\xc2\xf7\xcb\x55\xf4\x3c\x41\x6b" +
+		"\xfa\xd8\xf7\x94\xc1\x6b\xcf\xd6\xc4\xbe\x69\xcd\x1f\xe6\x94\xb0\x31\xbd\xd9\x3a\x1c\xff\x6b\x9c\x9a\xa2\xc6\x2d" +
+		"\x0b\x3f\xc8\x22\x44\x3e\xb8\x00\x00\x00")
+
+func bindataDataItMalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItMalelastnamestxt,
+		"data/it/male_last_names.txt",
+	)
+}
+
+func bindataDataItMalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataItMalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/male_last_names.txt",
+		size:        184,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItMalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8c\xc1\x0a\xc3\x30\x0c\x43\xef\xfe\xab\xc1\x58\x2f\x2d\x8c\x75\xec" +
+		"\x6e\x12\xb7\x18\x52\xab\x38\x6e\x0e\xfb\xfa\x25\xbb\x08\x09\xe9\x69\xd2\xab\xca\x79\x0a\x4d\x8a\xc6\x66\x4a\x37" +
+		"\x0b\x98\x82\x16\xf6\xae\xf3\xa5\xbb\xd2\xc3\xd9\x92\xd4\x84\xde\xee\x52\x40\x1f\xed\xd9\xbe\xa0\xa7\x4a\x38\x68" +
+		"\xe5\xd2\x38\xe0\x32\xb0\x31\x2b\x52\x2b\x5b\xf6\x41\x64\x17\xa6\xb9\x97\x03\x58\x38\x42\xfa\xaf\xc0\xd8\x33\xe8" +
+		"\xa5\x29\xfd\xcd\x1b\xc7\xc1\x15\x74\xe7\xa6\x59\x68\x0d\xd9\xd8\x40\x3f\xc8\xed\x84\x25\xa1\x00\x00\x00")
+
+func bindataDataItMalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItMalepatronymicstxt,
+		"data/it/male_patronymics.txt",
+	)
+}
+
+func bindataDataItMalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataItMalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/male_patronymics.txt",
+		size:        161,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItPhonesformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd3\x36\xb6\x54\x30\x56\x56\x56\x50\x86\x60\x65\x2e\x6d\xa0\x80\x81\x11" +
+		"\x98\x8d\x24\x60\x86\x2e\x60\x68\x88\xa6\xc5\xd4\x14\x21\x00\x00\x34\xa4\x25\x45\x55\x00\x00\x00")
+
+func bindataDataItPhonesformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItPhonesformattxt,
+		"data/it/phones_format.txt",
+	)
+}
+
+func bindataDataItPhonesformattxt() (*asset, error) {
+	bytes, err := bindataDataItPhonesformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/phones_format.txt",
+		size:        85,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItStatestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8e\xbb\x0e\xc2\x30\x0c\x45\x77\x7f\x45\x37\xa6\x7e\x44\x41\xc0\xd2" +
+		"\x4a\x08\x4a\xf7\xdb\xd4\x0a\x96\x92\x18\xe5\xb1\xe4\xeb\x49\xd8\xce\x95\x7d\xed\x33\xed\xb1\xd4\xaa\x74\x46\x12" +
+		"\x27\x06\x19\x74\x81\xc3\x1e\xa5\x83\xff\x22\x34\xb8\xfa\x36\xc3\xf8\x54\x0f\x1b\x40\xb7\x28\xc5\xc9\xb8\x71\xe0" +
+		"\x2a\x18\xee\x3d\x81\x66\x54\x51\x9a\xc5\x96\xde\x9d\xd5\xef\x88\x47\xa3\x05\xd1\x7c\x98\x16\x75\x92\x98\x1e\xc2" +
+		"\x5e\x43\x6e\x50\x6c\x6f\xbd\xda\x12\xf7\xa3\x2f\x31\xfd\x09\xad\x9a\x0c\x5a\x5e\x23\x87\x2c\x41\xc7\xc9\x65\x1d" +
+		"\xa6\x43\x2c\xd3\xdb\xff\xbd\x36\x38\xc7\xc3\x71\x9a\x34\x35\xdd\xae\x91\x95\x7e\x94\x6d\x8b\x41\xc9\x00\x00\x00" +
+		"")
+
+func bindataDataItStatestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItStatestxt,
+		"data/it/states.txt",
+	)
+}
+
+func bindataDataItStatestxt() (*asset, error) {
+	bytes, err := bindataDataItStatestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/states.txt",
+		size:        201,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItStreetsuffixestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+func bindataDataItStreetsuffixestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItStreetsuffixestxt,
+		"data/it/street_suffixes.txt",
+	)
+}
+
+func bindataDataItStreetsuffixestxt() (*asset, error) {
+	bytes, err := bindataDataItStreetsuffixestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/street_suffixes.txt",
+		size:        0,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItStreetstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x55\x8f\x4d\x0e\xc2\x40\x08\x85\xf7\x3d\x05\x57\x31\xfe\xa5\x89\x1a\xa3" +
+		"\xa6\x71\x4b\x5b\x52\x49\x28\x34\x74\xc6\x45\x4f\x2f\x76\xdc\xb8\x7b\xc0\xc7\xe3\xd1\x30\xc2\xcd\x46\xac\x9a\x10" +
+		"\x47\xce\x33\x4d\x13\xc1\x11\x9d\x5b\x94\x9e\xff\xdb\x67\x5c\x16\x56\xae\xb6\xe6\xb3\x41\xc3\x29\x99\xb3\xc1\x7e" +
+		"\x44\xcd\x24\x04\x75\xbd\xf2\x3b\xd4\x44\xb0\x11\x1e\x5e\x4c\x5e\x3c\xb6\xf8\xb6\xec\xd5\x95\xc3\x02\xa1\x27\x81" +
+		"\x5d\xb6\xd1\xd6\xd9\x19\xbd\x33\x2d\xdc\x05\x17\x36\x45\xa1\x6f\x15\x96\x41\x4a\x24\xa4\x29\xb7\xad\x70\x57\x72" +
+		"\x3e\x9f\x70\xa7\x94\x68\x6c\x7d\xe5\xe0\x11\x39\xf4\x67\xc6\x82\x21\x4b\xc4\x3a\xa1\x70\xd9\xb9\xa3\xc2\xc1\x51" +
+		"\x3b\x9a\xbb\x42\xf6\xc4\x5f\x3a\x4e\x9d\xd0\x07\x83\x8d\x0f\xa4\x89\xb5\xf0\x0d\x79\xbc\xff\x01\x8a\x4a\xb0\xe3" +
+		"\x1f\x01\x00\x00")
+
+func bindataDataItStreetstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItStreetstxt,
+		"data/it/streets.txt",
+	)
+}
+
+func bindataDataItStreetstxt() (*asset, error) {
+	bytes, err := bindataDataItStreetstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/streets.txt",
+		size:        287,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataItZipsformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x33\x30\x50\x56\x56\xe6\x32\x02\x93\x16\x60\xd2\x10\x4c\x5a\x42\xd8\x66" +
+		"\x20\xd2\x04\xcc\x36\x05\x93\xe6\x60\xd2\x18\x4c\x02\x00\xf0\xf4\xfc\x40\x3c\x00\x00\x00")
+
+func bindataDataItZipsformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataItZipsformattxt,
+		"data/it/zips_format.txt",
+	)
+}
+
+func bindataDataItZipsformattxt() (*asset, error) {
+	bytes, err := bindataDataItZipsformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/it/zips_format.txt",
+		size:        60,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlCitiestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x8e\xbd\x0e\xc2\x30\x0c\x84\x77\x3f\x05\xaf\x12\xd4\x0a\x06\x60\x40" +
+		"\xb0\x74\x33\xf5\xb5\x09\x4a\x1c\xe4\x18\x78\x7d\x52\x89\xe9\x3e\xe9\x7e\x74\xa1\x34\x87\x09\x17\xba\x56\xff\xd3" +
+		"\x00\xdd\x1d\x99\x57\xba\xbb\x61\x8e\x4e\x63\x52\x89\xf5\x03\xa5\x83\x55\x4d\xba\x76\xba\xa5\xfc\x78\xdb\x4a\x21" +
+		"\x17\x18\x68\x6f\x10\xa6\x4b\x7a\x16\x6c\x6e\x78\x21\x4b\xad\xa6\xd4\x87\x2c\xa3\x50\x30\x8d\x5d\x46\x6d\x73\x84" +
+		"\x80\x42\xaf\xb5\xa5\x47\x9c\x26\x66\x6d\xce\x42\xd3\xb7\xe6\x0c\x3a\x21\x49\xdf\x38\x33\x37\xb7\xb4\x1d\x18\x90" +
+		"\x17\xa7\x1f\x79\xc1\x5b\xb6\xac\x00\x00\x00")
+
+func bindataDataNlCitiestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlCitiestxt,
+		"data/nl/cities.txt",
+	)
+}
+
+func bindataDataNlCitiestxt() (*asset, error) {
+	bytes, err := bindataDataNlCitiestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/cities.txt",
+		size:        172,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlFemalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8c\xc1\x0a\xc2\x30\x10\x44\xef\xf3\x57\x22\x29\x28\xad\x97\xfa\x03" +
+		"\x2b\x0e\x66\x69\x92\x95\x4d\xe2\xf7\xbb\xbd\x0c\xc3\x63\xe6\x6d\xe2\x2a\xb8\x5b\x96\xd6\x04\x97\x33\xae\xe6\x8d" +
+		"\x25\x68\x2a\xda\xe5\xc5\x91\x91\x6a\x8d\xd1\x3c\xe1\x6e\xdf\xac\xc4\x93\xbd\x63\x29\x9c\x8e\x3d\xae\xc4\x6a\x63" +
+		"\x30\x04\x36\x0f\x2c\xac\x07\x91\x7e\x82\x55\x19\xed\x61\xe6\xd8\xb4\x84\xb2\x8f\x4c\xc7\xad\x7d\x5c\xdf\xf8\x03" +
+		"\xc6\xc8\x84\x85\x7d\x00\x00\x00")
+
+func bindataDataNlFemalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlFemalefirstnamestxt,
+		"data/nl/female_first_names.txt",
+	)
+}
+
+func bindataDataNlFemalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataNlFemalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/female_first_names.txt",
+		size:        125,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlFemalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x8e\xc1\x0a\xc3\x30\x0c\x43\xef\xfe\x8a\x7c\x4b\x28\x6c\x94\x05\x06" +
+		"\x83\xec\x1c\x88\x56\xd2\x6c\x31\xd8\xe9\xf6\xfb\x75\xd3\x1d\x8c\x2c\x90\x9f\x9c\xe1\x66\x6e\x0b\xcd\xa9\x29\x1a" +
+		"\x65\xb8\x28\x05\x4a\xdf\xd4\x5c\x46\x73\x1e\xb2\x0c\x33\x95\xb5\x92\x4f\xb5\x42\x46\xf8\x48\xc7\x62\x22\xf4\xf8" +
+		"\x94\x4e\x01\x65\xb5\xdd\x00\x9e\x4d\xc3\xf6\xce\xa7\xbd\x08\x73\x27\xcf\x4a\xd1\xe6\x8e\x0e\x51\xba\xa2\x65\x29" +
+		"\xf5\xec\xb9\x01\xdb\xcf\x70\x13\x06\xdd\x0b\x9b\x1d\xb7\x4f\x03\x1f\xc5\xda\x25\x8d\x1a\x3d\x89\x29\xbd\xfe\x1f" +
+		"\x8a\x0b\xb0\xec\x0e\xc7\xa3\x19\xfa\xc7\x00\x00\x00")
+
+func bindataDataNlFemalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlFemalelastnamestxt,
+		"data/nl/female_last_names.txt",
+	)
+}
+
+func bindataDataNlFemalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataNlFemalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/female_last_names.txt",
+		size:        199,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlFemalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x0d\x8b\xc1\x0a\xc2\x40\x0c\x05\xef\xef\xb3\xaa\xa0\x2c\x45\x44\x05\xcf" +
+		"\x69\x7d\xd0\xe8\x36\x0b\xc9\x6e\xbf\xdf\x5c\xe6\x30\xcc\x14\x31\x94\xb6\x89\x19\x03\xa7\xe6\xc6\xaa\x81\xbb\xb2" +
+		"\xd3\x71\xa5\x7d\x5c\x7f\x78\x6b\xad\xdc\x71\xa1\xbb\x76\x14\x59\xdb\x32\x02\x67\xc9\xf7\x99\x7e\x1e\xab\x04\x26" +
+		"\x97\x1d\xaf\x4d\xbf\x81\x59\x3c\x50\x18\x41\x3c\xc6\xc2\xac\x8e\xc4\x4d\x59\x53\xb7\x16\x1d\x53\x0e\x7f\xeb\xc7" +
+		"\xb4\x74\x7b\x00\x00\x00")
+
+func bindataDataNlFemalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlFemalepatronymicstxt,
+		"data/nl/female_patronymics.txt",
+	)
+}
+
+func bindataDataNlFemalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataNlFemalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/female_patronymics.txt",
+		size:        123,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlMalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x0d\x8b\xc1\x0a\xc2\x40\x0c\x05\xef\xef\xb3\xaa\xa0\x2c\x45\x44\x05\xcf" +
+		"\x69\x7d\xd0\xe8\x36\x0b\xc9\x6e\xbf\xdf\x5c\xe6\x30\xcc\x14\x31\x94\xb6\x89\x19\x03\xa7\xe6\xc6\xaa\x81\xbb\xb2" +
+		"\xd3\x71\xa5\x7d\x5c\x7f\x78\x6b\xad\xdc\x71\xa1\xbb\x76\x14\x59\xdb\x32\x02\x67\xc9\xf7\x99\x7e\x1e\xab\x04\x26" +
+		"\x97\x1d\xaf\x4d\xbf\x81\x59\x3c\x50\x18\x41\x3c\xc6\xc2\xac\x8e\xc4\x4d\x59\x53\xb7\x16\x1d\x53\x0e\x7f\xeb\xc7" +
+		"\xb4\x74\x7b\x00\x00\x00")
+
+func bindataDataNlMalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlMalefirstnamestxt,
+		"data/nl/male_first_names.txt",
+	)
+}
+
+func bindataDataNlMalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataNlMalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/male_first_names.txt",
+		size:        123,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlMalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x8e\xc1\x0a\xc3\x30\x0c\x43\xef\xfe\x8a\x7c\x4b\x28\x6c\x94\x05\x06" +
+		"\x83\xec\x1c\x88\x56\xd2\x6c\x31\xd8\xe9\xf6\xfb\x75\xd3\x1d\x8c\x2c\x90\x9f\x9c\xe1\x66\x6e\x0b\xcd\xa9\x29\x1a" +
+		"\x65\xb8\x28\x05\x4a\xdf\xd4\x5c\x46\x73\x1e\xb2\x0c\x33\x95\xb5\x92\x4f\xb5\x42\x46\xf8\x48\xc7\x62\x22\xf4\xf8" +
+		"\x94\x4e\x01\x65\xb5\xdd\x00\x9e\x4d\xc3\xf6\xce\xa7\xbd\x08\x73\x27\xcf\x4a\xd1\xe6\x8e\x0e\x51\xba\xa2\x65\x29" +
+		"\xf5\xec\xb9\x01\xdb\xcf\x70\x13\x06\xdd\x0b\x9b\x1d\xb7\x4f\x03\x1f\xc5\xda\x25\x8d\x1a\x3d\x89\x29\xbd\xfe\x1f" +
+		"\x8a\x0b\xb0\xec\x0e\xc7\xa3\x19\xfa\xc7\x00\x00\x00")
+
+func bindataDataNlMalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlMalelastnamestxt,
+		"data/nl/male_last_names.txt",
+	)
+}
+
+func bindataDataNlMalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataNlMalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/male_last_names.txt",
+		size:        199,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlMalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x0d\x8b\xc1\x0a\xc2\x40\x0c\x05\xef\xef\xb3\xaa\xa0\x2c\x45\x44\x05\xcf" +
+		"\x69\x7d\xd0\xe8\x36\x0b\xc9\x6e\xbf\xdf\x5c\xe6\x30\xcc\x14\x31\x94\xb6\x89\x19\x03\xa7\xe6\xc6\xaa\x81\xbb\xb2" +
+		"\xd3\x71\xa5\x7d\x5c\x7f\x78\x6b\xad\xdc\x71\xa1\xbb\x76\x14\x59\xdb\x32\x02\x67\xc9\xf7\x99\x7e\x1e\xab\x04\x26" +
+		"\x97\x1d\xaf\x4d\xbf\x81\x59\x3c\x50\x18\x41\x3c\xc6\xc2\xac\x8e\xc4\x4d\x59\x53\xb7\x16\x1d\x53\x0e\x7f\xeb\xc7" +
+		"\xb4\x74\x7b\x00\x00\x00")
+
+func bindataDataNlMalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlMalepatronymicstxt,
+		"data/nl/male_patronymics.txt",
+	)
+}
+
+func bindataDataNlMalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataNlMalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/male_patronymics.txt",
+		size:        123,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlPhonesformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd3\x36\x36\x54\x30\x53\x50\x86\x02\x2e\x6d\x20\xd7\xc8\x00\xc4\x57\x80" +
+		"\xf3\x0d\xd1\xf8\xc6\x68\x7c\x13\x24\x3e\x00\xa8\x39\xa3\xc8\x4f\x00\x00\x00")
+
+func bindataDataNlPhonesformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlPhonesformattxt,
+		"data/nl/phones_format.txt",
+	)
+}
+
+func bindataDataNlPhonesformattxt() (*asset, error) {
+	bytes, err := bindataDataNlPhonesformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/phones_format.txt",
+		size:        79,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlStatestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x45\x8a\xb1\x0a\x83\x40\x10\x05\xfb\xfd\x17\x7f\x22\x88\xa6\x08\x49\x65" +
+		"\x63\x77\xe6\x1e\xba\xb2\xd9\x85\x77\xa7\xdf\x2f\x5c\x02\xe9\x66\x98\xe9\x09\xaf\x1b\x64\x30\x9c\x61\xc9\xb3\x0c" +
+		"\x54\x94\x46\x23\x2c\x83\x5f\x64\xb8\xfa\x0a\x97\x87\x7e\x96\x83\xab\x3c\x23\x98\xbb\x1b\xd3\x92\xbc\xfe\xec\x1e" +
+		"\xd6\xee\xd7\x09\xea\x5e\x0a\x4c\xa6\x4a\xbc\xb7\x2a\x33\xd0\xd2\x7c\xe8\xff\xbb\x00\xbe\x94\x32\xf5\x7d\x00\x00" +
+		"\x00")
+
+func bindataDataNlStatestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlStatestxt,
+		"data/nl/states.txt",
+	)
+}
+
+func bindataDataNlStatestxt() (*asset, error) {
+	bytes, err := bindataDataNlStatestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/states.txt",
+		size:        125,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlStreetsuffixestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+func bindataDataNlStreetsuffixestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlStreetsuffixestxt,
+		"data/nl/street_suffixes.txt",
+	)
+}
+
+func bindataDataNlStreetsuffixestxt() (*asset, error) {
+	bytes, err := bindataDataNlStreetsuffixestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/street_suffixes.txt",
+		size:        0,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlStreetstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3d\x8e\x4b\x0e\xc2\x30\x0c\x44\xf7\x39\x05\x67\x81\x2e\x2a\xca\x4f\x42" +
+		"\x82\xb5\xd5\x9a\xc6\xaa\x6b\x23\x27\xa1\x88\xd3\x13\x24\xd3\x95\xe7\xcd\x9b\x85\x3b\xb4\x29\x65\x03\xc8\xe1\xda" +
+		"\x47\x55\x76\x38\x2a\xa3\x78\x6e\xd4\x9e\xe9\x3f\xca\x90\x49\x25\x2d\x38\x86\x7d\x61\x02\x01\x37\x77\xe2\x88\x3c" +
+		"\xd3\x5a\x9c\x08\xcb\xe2\xb9\x55\x7d\x0c\x9e\xb7\x08\xd9\xe8\xed\xd4\x01\xbf\xd0\x1c\x2e\x46\x92\x50\x46\x83\x3e" +
+		"\x56\x85\xf4\xa9\xca\xa9\x45\x5b\x4d\x03\xb3\xc1\x14\x76\xbf\x7f\x49\x46\xe4\x70\x80\x7a\x36\x37\x55\x8b\x5a\x72" +
+		"\x38\x97\x01\x7d\xfb\x05\x4d\x61\xf6\x69\xe2\x00\x00\x00")
+
+func bindataDataNlStreetstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlStreetstxt,
+		"data/nl/streets.txt",
+	)
+}
+
+func bindataDataNlStreetstxt() (*asset, error) {
+	bytes, err := bindataDataNlStreetstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/streets.txt",
+		size:        226,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataNlZipsformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x33\x54\x56\x56\x56\xb0\xb7\xe7\x32\x82\xd2\xc6\x50\xda\x04\x4a\x9b\x42" +
+		"\x69\x33\x28\x6d\x0e\xa5\x2d\xa0\xb4\x25\x94\x06\x00\x23\x13\x69\x26\x48\x00\x00\x00")
+
+func bindataDataNlZipsformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataNlZipsformattxt,
+		"data/nl/zips_format.txt",
+	)
+}
+
+func bindataDataNlZipsformattxt() (*asset, error) {
+	bytes, err := bindataDataNlZipsformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/nl/zips_format.txt",
+		size:        72,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlCitiestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\x8e\x31\x0e\xc2\x30\x0c\x45\x77\x1f\x0b\x06\x86\x22\x81\x5a\xa4\xce" +
+		"\x26\x89\x20\x6a\x88\xa5\xb8\x55\x14\x6f\x54\x5c\x82\x73\x74\x63\x2d\xb9\x17\x2e\x8b\x25\xff\xef\xff\xbe\x7b\x4c" +
+		"\x2c\x98\x11\x9a\x84\xc3\xba\x64\xa8\xcf\x75\xb1\xf5\x03\x7d\x22\x53\x67\xcc\x70\x26\x89\x58\x5f\x70\xb0\x3a\x79" +
+		"\x80\x4e\x8c\x38\xe3\x23\xec\x8a\xbd\x11\xeb\x06\xc7\xe9\x1a\x36\xc1\x63\x9d\x0b\x8f\x34\x40\x83\x23\x65\x6f\x9c" +
+		"\xa6\x4a\xf4\x08\x7b\xf9\xbe\xd5\x30\x77\xd2\xaa\x16\x2d\x3d\xe0\x42\x69\x52\x6c\x47\x1c\xf5\xd4\x19\x68\xc5\xb1" +
+		"\x6c\x2f\x34\xde\x85\x2d\x1a\xfc\x1f\x71\x0a\x2c\x63\x89\xf0\x03\x01\xfe\xf1\xfd\xab\x00\x00\x00")
+
+func bindataDataPlCitiestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlCitiestxt,
+		"data/pl/cities.txt",
+	)
+}
+
+func bindataDataPlCitiestxt() (*asset, error) {
+	bytes, err := bindataDataPlCitiestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/cities.txt",
+		size:        171,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlFemalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x25\x8d\xbd\x0a\xc3\x30\x0c\x84\x77\xbd\x95\x4b\x0b\xfd\xa1\x5b\xa7\x6e" +
+		"\x17\xac\x1a\x61\x23\x83\x9c\x12\xe2\xb1\xcf\x95\x35\xef\x55\xb9\x1d\x04\x3a\x7d\x77\xa7\xa0\x0a\xba\xc3\x04\x74" +
+		"\xc3\x0c\xeb\xeb\x4f\xef\x9f\x54\xad\xfb\x81\x42\x52\xe1\xd6\xb3\x73\x5b\xdb\x3c\xf0\x01\x36\xc1\x40\xa7\xc5\xa7" +
+		"\xec\xdb\x24\xec\xc6\x67\x7d\x79\xc9\x15\x2a\x6e\x79\xb0\x71\x73\x55\xf1\xef\x4f\x11\x85\xc7\x56\x55\xf2\x70\xc5" +
+		"\x45\x12\xe8\x08\x7d\x7b\xf4\x62\x83\x9d\x51\x46\x34\x14\xce\x0d\x1a\xfd\xc1\x17\xfd\x49\xfc\xce\x9d\x00\x00\x00" +
+		"")
+
+func bindataDataPlFemalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlFemalefirstnamestxt,
+		"data/pl/female_first_names.txt",
+	)
+}
+
+func bindataDataPlFemalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataPlFemalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/female_first_names.txt",
+		size:        157,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlFemalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xf3\xcb\x2f\x4f\xcc\xe6\xf2\x06\x92\x39\xc5\xd9\x89\x5c\xe1\x99\x47\x67" +
+		"\xe7\x65\xa6\x96\x83\xd9\x87\x37\x67\x25\x67\x42\x25\x93\xab\x2a\x81\xac\xc4\xdc\xcc\xa3\x2d\x20\x39\x9f\xd4\xf2" +
+		"\xc4\xbc\x94\x7c\xb0\xba\xa8\xcc\xd4\x1c\xa8\x70\x70\x55\x65\x6e\x22\x84\x19\x9e\x7f\x74\x57\x5e\x26\xd0\x6c\x97" +
+		"\x23\xad\x49\x45\x10\x95\xde\xf9\x55\x47\x9b\x20\x4c\xaf\xc4\xbc\x6c\x08\xcb\x37\xb1\xaa\xb4\x88\xcb\xbb\x3c\x33" +
+		"\xb1\x04\x2a\xe2\x5d\x94\x58\x0e\xb6\x2f\x20\x33\xbf\x04\xaa\xd5\xbd\x28\x31\x09\xc2\xf2\x03\xb9\x18\xc2\x0c\x48" +
+		"\x2c\x87\x99\x07\x00\xf1\xc0\x28\xf7\xc8\x00\x00\x00")
+
+func bindataDataPlFemalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlFemalelastnamestxt,
+		"data/pl/female_last_names.txt",
+	)
+}
+
+func bindataDataPlFemalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataPlFemalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/female_last_names.txt",
+		size:        200,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlFemalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8d\xc1\x0a\x83\x30\x10\x44\xef\xf3\x57\x42\xa1\xa0\x08\xd2\x7a\xea" +
+		"\x6d\x6b\x56\xbb\x15\x93\xb2\x2a\xa1\x7b\xab\xbf\xd4\x4f\x28\xf9\xaf\x26\xa7\x39\xcc\xbc\x37\x35\x79\x54\xde\xa9" +
+		"\xf1\x13\x9d\x84\x4d\xd1\xa8\xbd\x57\xdb\xc2\x88\xeb\x46\x5e\xd6\x74\x50\x44\x1f\x16\x5a\x0d\x1d\x45\x4e\x07\xea" +
+		"\xdf\xd7\x78\x44\x4b\x3a\x88\x2f\xc1\x33\x5a\x19\x1e\x94\xbb\x73\x56\x4d\x41\x0d\x35\x67\x11\x7a\x72\xbc\x67\xb2" +
+		"\x72\xb4\x20\x7d\xf6\xb9\x68\x6e\x77\x99\xbc\x70\xc4\x25\x3f\x91\x3a\x9c\x48\xa5\xac\x1a\x1a\x5e\xac\xf8\x03\x31" +
+		"\xf6\x7c\x9e\x95\x00\x00\x00")
+
+func bindataDataPlFemalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlFemalepatronymicstxt,
+		"data/pl/female_patronymics.txt",
+	)
+}
+
+func bindataDataPlFemalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataPlFemalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/female_patronymics.txt",
+		size:        149,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlMalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8d\xc1\x0a\x83\x30\x10\x44\xef\xf3\x57\x42\xa1\xa0\x08\xd2\x7a\xea" +
+		"\x6d\x6b\x56\xbb\x15\x93\xb2\x2a\xa1\x7b\xab\xbf\xd4\x4f\x28\xf9\xaf\x26\xa7\x39\xcc\xbc\x37\x35\x79\x54\xde\xa9" +
+		"\xf1\x13\x9d\x84\x4d\xd1\xa8\xbd\x57\xdb\xc2\x88\xeb\x46\x5e\xd6\x74\x50\x44\x1f\x16\x5a\x0d\x1d\x45\x4e\x07\xea" +
+		"\xdf\xd7\x78\x44\x4b\x3a\x88\x2f\xc1\x33\x5a\x19\x1e\x94\xbb\x73\x56\x4d\x41\x0d\x35\x67\x11\x7a\x72\xbc\x67\xb2" +
+		"\x72\xb4\x20\x7d\xf6\xb9\x68\x6e\x77\x99\xbc\x70\xc4\x25\x3f\x91\x3a\x9c\x48\xa5\xac\x1a\x1a\x5e\xac\xf8\x03\x31" +
+		"\xf6\x7c\x9e\x95\x00\x00\x00")
+
+func bindataDataPlMalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlMalefirstnamestxt,
+		"data/pl/male_first_names.txt",
+	)
+}
+
+func bindataDataPlMalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataPlMalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/male_first_names.txt",
+		size:        149,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlMalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xf3\xcb\x2f\x4f\xcc\xe6\xf2\x06\x92\x39\xc5\xd9\x99\x5c\xe1\x99\x47\x67" +
+		"\xe7\x65\xa6\x96\x83\xd9\x87\x37\x67\x25\x67\x42\x25\x93\xab\x2a\x81\xac\xc4\xdc\xcc\xa3\x2d\x20\x39\x9f\xd4\xf2" +
+		"\xc4\xbc\x94\x7c\xb0\xba\xa8\xcc\xd4\x1c\xa8\x70\x70\x55\x65\x6e\x22\x84\x19\x9e\x7f\x74\x57\x5e\x26\xd0\x6c\x97" +
+		"\x23\xad\x49\x45\x10\x95\xde\xf9\x55\x47\x9b\x20\x4c\xaf\xc4\xbc\x6c\x08\xcb\x37\xb1\xaa\xb4\x88\xcb\xbb\x3c\x33" +
+		"\xb1\x04\x2a\xe2\x5d\x94\x58\x0e\xb6\x2f\x20\x33\xbf\x04\xaa\xd5\xbd\x28\x31\x09\xc2\xf2\x03\xb9\x18\xc2\x0c\x48" +
+		"\x2c\x87\x99\x07\x00\xdb\xec\x73\xa5\xc8\x00\x00\x00")
+
+func bindataDataPlMalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlMalelastnamestxt,
+		"data/pl/male_last_names.txt",
+	)
+}
+
+func bindataDataPlMalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataPlMalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/male_last_names.txt",
+		size:        200,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlMalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8d\xc1\x0a\x83\x30\x10\x44\xef\xf3\x57\x42\xa1\xa0\x08\xd2\x7a\xea" +
+		"\x6d\x6b\x56\xbb\x15\x93\xb2\x2a\xa1\x7b\xab\xbf\xd4\x4f\x28\xf9\xaf\x26\xa7\x39\xcc\xbc\x37\x35\x79\x54\xde\xa9" +
+		"\xf1\x13\x9d\x84\x4d\xd1\xa8\xbd\x57\xdb\xc2\x88\xeb\x46\x5e\xd6\x74\x50\x44\x1f\x16\x5a\x0d\x1d\x45\x4e\x07\xea" +
+		"\xdf\xd7\x78\x44\x4b\x3a\x88\x2f\xc1\x33\x5a\x19\x1e\x94\xbb\x73\x56\x4d\x41\x0d\x35\x67\x11\x7a\x72\xbc\x67\xb2" +
+		"\x72\xb4\x20\x7d\xf6\xb9\x68\x6e\x77\x99\xbc\x70\xc4\x25\x3f\x91\x3a\x9c\x48\xa5\xac\x1a\x1a\x5e\xac\xf8\x03\x31" +
+		"\xf6\x7c\x9e\x95\x00\x00\x00")
+
+func bindataDataPlMalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlMalepatronymicstxt,
+		"data/pl/male_patronymics.txt",
+	)
+}
+
+func bindataDataPlMalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataPlMalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/male_patronymics.txt",
+		size:        149,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlPhonesformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd3\x36\xb1\x50\x30\x55\x56\x56\x50\x86\x60\x2e\x6d\x20\xdf\x0c\x8d\x6f" +
+		"\x8e\xc6\xb7\x40\xe3\x1b\x19\x41\xb9\x40\xc4\x05\x00\x8d\xa0\x3b\xb5\x51\x00\x00\x00")
+
+func bindataDataPlPhonesformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlPhonesformattxt,
+		"data/pl/phones_format.txt",
+	)
+}
+
+func bindataDataPlPhonesformattxt() (*asset, error) {
+	bytes, err := bindataDataPlPhonesformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/phones_format.txt",
+		size:        81,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlStatestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x45\x8e\x4b\x0e\xc3\x20\x0c\x44\xf7\xbd\x4b\x0f\xe5\x02\x52\xa9\x4d\x06" +
+		"\x91\x22\x54\x96\x51\x7b\x88\x2a\xc7\xe8\x11\x1a\xee\x95\x40\x7e\xbb\xe7\x67\x6b\xc6\x1a\xd2\xa1\x8c\x32\x7d\x7a" +
+		"\xb6\xe6\xc2\xf1\x41\xa9\x67\x5c\x3d\x1c\x42\x53\x12\x6f\x46\x76\x8a\x0d\xca\xf0\xff\xe9\x5c\xc9\x51\x19\xe0\xb1" +
+		"\xee\x1d\x65\x24\x6b\x54\xe5\x43\x7a\x68\xa6\xe0\x49\x6d\x83\xd0\xa6\xf7\xfc\xb3\xbc\x8c\xc9\x4e\xdf\x27\x38\xe4" +
+		"\x57\x13\x89\x82\xb3\xe5\x5d\xff\x59\xb2\xe3\x7a\xbf\x34\x08\x1f\xf1\x99\xd4\x1d\xba\xb3\x38\x03\x67\xa7\x02\x2f" +
+		"\xf0\xd3\x00\x00\x00")
+
+func bindataDataPlStatestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlStatestxt,
+		"data/pl/states.txt",
+	)
+}
+
+func bindataDataPlStatestxt() (*asset, error) {
+	bytes, err := bindataDataPlStatestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/states.txt",
+		size:        211,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlStreetsuffixestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+func bindataDataPlStreetsuffixestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlStreetsuffixestxt,
+		"data/pl/street_suffixes.txt",
+	)
+}
+
+func bindataDataPlStreetsuffixestxt() (*asset, error) {
+	bytes, err := bindataDataPlStreetsuffixestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/street_suffixes.txt",
+		size:        0,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlStreetstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5d\x8e\x41\x0a\xc2\x30\x10\x45\xf7\x3d\x45\xcf\x22\xae\xac\x45\xa1\x3b" +
+		"\x77\x43\x1a\xca\x98\x34\x29\x49\x43\x70\x76\x06\x3c\x45\x8f\xe1\x11\x34\xf7\x32\x94\x90\x16\x77\xf3\xfe\xcc\xff" +
+		"\x7f\x9c\x44\x06\xf5\x55\x4b\x05\x95\x5b\xe7\x33\x8f\x4b\x81\x2e\x06\xad\x38\xa3\x22\x34\xe6\xf3\x9e\x45\x59\x93" +
+		"\xd8\x39\x2f\x83\xd1\xbd\xf6\x25\x08\xa7\x0d\x0e\x86\x34\x6d\x18\x9f\xdf\x97\xd8\xb0\xf1\x08\xf3\x0e\x75\x5c\x18" +
+		"\x3a\x4b\x02\xb3\xd2\x22\x13\xc8\x3d\x32\x2a\xd5\xc8\xd5\x9f\xd4\x82\xb1\x04\x31\xa4\x60\x9b\x5e\x04\xc9\xef\x50" +
+		"\x9f\xb8\x49\xc5\x12\x47\x9b\xae\xb3\xd6\x25\xee\xc1\xa8\xb5\xa6\x9a\x24\xb0\xfa\x06\x63\xb2\x3d\x72\xd2\x31\x06" +
+		"\x37\x40\xf5\x03\xfa\xc5\xa1\x97\x1d\x01\x00\x00")
+
+func bindataDataPlStreetstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlStreetstxt,
+		"data/pl/streets.txt",
+	)
+}
+
+func bindataDataPlStreetstxt() (*asset, error) {
+	bytes, err := bindataDataPlStreetstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/streets.txt",
+		size:        285,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPlZipsformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x53\x56\xd6\x55\x56\x56\xe6\x02\x00\x44\x06\xa0\x0d\x07\x00\x00\x00")
+
+func bindataDataPlZipsformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPlZipsformattxt,
+		"data/pl/zips_format.txt",
+	)
+}
+
+func bindataDataPlZipsformattxt() (*asset, error) {
+	bytes, err := bindataDataPlZipsformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pl/zips_format.txt",
+		size:        7,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtCitiestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8e\xb1\x0d\xc2\x30\x10\x45\xfb\x9b\xc2\x0b\x30\x84\x09\x4a\x9a\x08" +
+		"\x81\x90\xe8\x7f\x92\x93\xb1\x64\xe7\x24\x3b\x76\x91\x0d\x98\x83\x8a\x15\x68\xbd\x18\x97\xf6\xe9\xe9\xbf\x3f\xfa" +
+		"\x3c\x09\xe8\x26\x69\x13\x7a\xfa\x00\x73\x95\x0a\xb3\xb0\x19\xe0\x41\x36\x62\x91\x04\x3a\x27\x38\x50\x5f\xd6\xf9" +
+		"\x85\x40\x9d\xf8\x38\x29\x7d\xf0\xd6\x7e\x93\x02\x1b\xd4\x53\xdb\x15\x84\x8a\x53\x87\xb9\x7d\x23\xdd\x0b\x87\xb2" +
+		"\x93\xad\xec\x93\x50\x7b\xd7\x63\xa9\x47\x3a\x42\x99\x0b\x8d\xca\xb5\x31\x14\x1f\x91\xda\x87\xb3\xde\x58\x37\x98" +
+		"\x0b\x07\x77\xcc\xfd\x01\x62\x5b\x41\x7b\x9c\x00\x00\x00")
+
+func bindataDataPtCitiestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtCitiestxt,
+		"data/pt/cities.txt",
+	)
+}
+
+func bindataDataPtCitiestxt() (*asset, error) {
+	bytes, err := bindataDataPtCitiestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/cities.txt",
+		size:        156,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtFemalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8b\x41\x0a\x42\x31\x0c\x44\xf7\xb9\x95\x0a\xf2\xbf\xd8\x8d\xfe\x0b" +
+		"\x8c\x6d\x94\x40\x69\x20\xad\x1b\x8f\x24\x78\x8a\x7f\x31\xa7\x9b\x90\x99\x79\x2f\x21\x0c\x72\x68\x90\x8b\x83\xf7" +
+		"\xa8\x18\x61\x1f\x49\xb3\x67\x5e\xdb\xfe\xed\x72\x42\x78\x35\xc6\xbb\x3f\x89\x5f\xd5\x9b\x07\x99\x61\xb5\x28\xd7" +
+		"\x41\x9a\xeb\xcd\x06\x11\x04\xa6\xce\x77\xd3\xd0\x0e\x59\xb4\x2a\xd7\x73\xa0\x65\xeb\x99\xfe\x7b\xff\xb1\x5f\x3b" +
+		"\x1e\x5a\x27\xfb\xa2\x5f\x20\x7f\x23\xbd\x37\x3b\x8d\x00\x00\x00")
+
+func bindataDataPtFemalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtFemalefirstnamestxt,
+		"data/pt/female_first_names.txt",
+	)
+}
+
+func bindataDataPtFemalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataPtFemalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/female_first_names.txt",
+		size:        141,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtFemalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x4e\x41\x0e\xc2\x30\x0c\xbb\xe7\x57\xd3\x24\x90\x10\x83\x89\xf2\x81" +
+		"\xa0\x46\x10\xa9\x6b\x20\x59\x2b\x7e\xc4\x43\xf8\x18\x69\xc7\xc5\xb1\x65\x39\x76\xe0\x54\x11\x02\xe6\x55\x0c\x76" +
+		"\xa4\x4a\xac\x08\x33\x6d\xf7\x9c\xb8\x76\x32\x8a\xad\x08\x17\x89\xca\xf7\x42\x06\x13\xea\xca\xd9\xe0\x40\x56\x0c" +
+		"\x82\x14\xc3\x96\xce\x98\xa3\xbb\x7b\xc9\xdf\x0f\xa6\xda\xe9\xe2\x78\x94\xe7\x16\x7a\xb5\xf0\xd0\x9d\x21\x2d\xc4" +
+		"\xd1\x9f\xf2\xcd\x2b\x04\x66\xf6\x0d\x30\xa2\x56\x4c\x0f\x81\x2b\xf1\xbb\x57\x4f\xa2\xff\x09\x6d\x9c\x6b\xea\x1d" +
+		"\xa7\x92\x1d\x7f\x54\x06\xdf\x01\xbf\x00\x00\x00")
+
+func bindataDataPtFemalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtFemalelastnamestxt,
+		"data/pt/female_last_names.txt",
+	)
+}
+
+func bindataDataPtFemalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataPtFemalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/female_last_names.txt",
+		size:        191,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtFemalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8c\x41\x0a\xc2\x30\x10\x45\xf7\xff\x56\xc5\xa2\x50\x2c\x14\xe9\x05" +
+		"\x86\x34\x96\x81\x34\x1f\x66\x92\x03\x89\x0b\x71\xe1\xca\x23\xe4\x62\xc6\xd5\xe7\x7d\x1e\x6f\x62\x7b\x12\x13\xbd" +
+		"\xbd\x31\xe4\xd2\xbe\x59\x89\xb3\x49\x0e\xea\x81\x98\x25\xd7\x98\xb0\xc4\xcd\x88\x6b\x6d\x1f\xc7\x49\x2c\xd1\xb1" +
+		"\x48\x4d\xc4\xad\x2a\x66\xdd\xff\xce\xaa\xb2\xf7\x83\x9b\x69\xdf\x0b\x73\x7b\x49\x37\x46\x65\xc7\xe1\xce\xec\xc4" +
+		"\xca\xa3\x3d\x1c\x63\x15\x2b\xb1\xc7\xad\xe8\xd1\xb3\x41\x1c\x3f\x24\xfe\xac\x4b\x89\x00\x00\x00")
+
+func bindataDataPtFemalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtFemalepatronymicstxt,
+		"data/pt/female_patronymics.txt",
+	)
+}
+
+func bindataDataPtFemalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataPtFemalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/female_patronymics.txt",
+		size:        137,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtMalefirstnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8c\x41\x0a\xc2\x30\x10\x45\xf7\xff\x56\xc5\xa2\x50\x2c\x14\xe9\x05" +
+		"\x86\x34\x96\x81\x34\x1f\x66\x92\x03\x89\x0b\x71\xe1\xca\x23\xe4\x62\xc6\xd5\xe7\x7d\x1e\x6f\x62\x7b\x12\x13\xbd" +
+		"\xbd\x31\xe4\xd2\xbe\x59\x89\xb3\x49\x0e\xea\x81\x98\x25\xd7\x98\xb0\xc4\xcd\x88\x6b\x6d\x1f\xc7\x49\x2c\xd1\xb1" +
+		"\x48\x4d\xc4\xad\x2a\x66\xdd\xff\xce\xaa\xb2\xf7\x83\x9b\x69\xdf\x0b\x73\x7b\x49\x37\x46\x65\xc7\xe1\xce\xec\xc4" +
+		"\xca\xa3\x3d\x1c\x63\x15\x2b\xb1\xc7\xad\xe8\xd1\xb3\x41\x1c\x3f\x24\xfe\xac\x4b\x89\x00\x00\x00")
+
+func bindataDataPtMalefirstnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtMalefirstnamestxt,
+		"data/pt/male_first_names.txt",
+	)
+}
+
+func bindataDataPtMalefirstnamestxt() (*asset, error) {
+	bytes, err := bindataDataPtMalefirstnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/male_first_names.txt",
+		size:        137,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtMalelastnamestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x4e\x41\x0e\xc2\x30\x0c\xbb\xe7\x57\xd3\x24\x90\x10\x83\x89\xf2\x81" +
+		"\xa0\x46\x10\xa9\x6b\x20\x59\x2b\x7e\xc4\x43\xf8\x18\x69\xc7\xc5\xb1\x65\x39\x76\xe0\x54\x11\x02\xe6\x55\x0c\x76" +
+		"\xa4\x4a\xac\x08\x33\x6d\xf7\x9c\xb8\x76\x32\x8a\xad\x08\x17\x89\xca\xf7\x42\x06\x13\xea\xca\xd9\xe0\x40\x56\x0c" +
+		"\x82\x14\xc3\x96\xce\x98\xa3\xbb\x7b\xc9\xdf\x0f\xa6\xda\xe9\xe2\x78\x94\xe7\x16\x7a\xb5\xf0\xd0\x9d\x21\x2d\xc4" +
+		"\xd1\x9f\xf2\xcd\x2b\x04\x66\xf6\x0d\x30\xa2\x56\x4c\x0f\x81\x2b\xf1\xbb\x57\x4f\xa2\xff\x09\x6d\x9c\x6b\xea\x1d" +
+		"\xa7\x92\x1d\x7f\x54\x06\xdf\x01\xbf\x00\x00\x00")
+
+func bindataDataPtMalelastnamestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtMalelastnamestxt,
+		"data/pt/male_last_names.txt",
+	)
+}
+
+func bindataDataPtMalelastnamestxt() (*asset, error) {
+	bytes, err := bindataDataPtMalelastnamestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/male_last_names.txt",
+		size:        191,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtMalepatronymicstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\x8c\x41\x0a\xc2\x30\x10\x45\xf7\xff\x56\xc5\xa2\x50\x2c\x14\xe9\x05" +
+		"\x86\x34\x96\x81\x34\x1f\x66\x92\x03\x89\x0b\x71\xe1\xca\x23\xe4\x62\xc6\xd5\xe7\x7d\x1e\x6f\x62\x7b\x12\x13\xbd" +
+		"\xbd\x31\xe4\xd2\xbe\x59\x89\xb3\x49\x0e\xea\x81\x98\x25\xd7\x98\xb0\xc4\xcd\x88\x6b\x6d\x1f\xc7\x49\x2c\xd1\xb1" +
+		"\x48\x4d\xc4\xad\x2a\x66\xdd\xff\xce\xaa\xb2\xf7\x83\x9b\x69\xdf\x0b\x73\x7b\x49\x37\x46\x65\xc7\xe1\xce\xec\xc4" +
+		"\xca\xa3\x3d\x1c\x63\x15\x2b\xb1\xc7\xad\xe8\xd1\xb3\x41\x1c\x3f\x24\xfe\xac\x4b\x89\x00\x00\x00")
+
+func bindataDataPtMalepatronymicstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtMalepatronymicstxt,
+		"data/pt/male_patronymics.txt",
+	)
+}
+
+func bindataDataPtMalepatronymicstxt() (*asset, error) {
+	bytes, err := bindataDataPtMalepatronymicstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/male_patronymics.txt",
+		size:        137,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtPhonesformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd3\x36\x36\x35\x54\xb0\x34\x54\x56\x50\x56\x06\x63\x2e\x6d\xb0\x80\x31" +
+		"\xba\x80\x19\x9a\x80\x11\xba\x16\x23\x23\x84\x00\x00\x32\x1f\x3a\x61\x55\x00\x00\x00")
+
+func bindataDataPtPhonesformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtPhonesformattxt,
+		"data/pt/phones_format.txt",
+	)
+}
+
+func bindataDataPtPhonesformattxt() (*asset, error) {
+	bytes, err := bindataDataPtPhonesformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/phones_format.txt",
+		size:        85,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtStatestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x4d\x41\x0a\xc2\x40\x10\xbb\xe7\x15\xfb\x15\x5b\xd0\x8b\x07\xb1\xe0" +
+		"\x3d\x6b\x87\xb2\xb2\xdd\x81\xe9\xb6\x7f\xf0\x25\xe2\x17\xbc\xee\xc7\x1c\x4a\x2f\x49\x48\x48\x72\xda\x24\x99\xa2" +
+		"\x93\x17\xd1\x19\xa7\x03\x4b\xfb\x10\x3d\x97\x2a\x59\x83\x3b\xe5\xa9\xe8\x35\xcd\xd1\x88\xf6\xde\xd4\xe9\x4c\xef" +
+		"\x5d\x56\xda\x48\x5c\x7d\x24\x39\xa5\x25\x2a\x71\x53\xab\xcc\x32\x99\xec\x52\x31\xb0\x54\x5a\xfb\xce\x18\xa4\xb6" +
+		"\x5f\x64\xc6\x23\xb1\x30\x8c\x1a\x8e\x13\x37\x32\xc3\x5d\xf6\x68\x91\x15\x7f\xea\xc1\x9c\xa7\x99\x00\x00\x00")
+
+func bindataDataPtStatestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtStatestxt,
+		"data/pt/states.txt",
+	)
+}
+
+func bindataDataPtStatestxt() (*asset, error) {
+	bytes, err := bindataDataPtStatestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/states.txt",
+		size:        153,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtStreetsuffixestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+func bindataDataPtStreetsuffixestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtStreetsuffixestxt,
+		"data/pt/street_suffixes.txt",
+	)
+}
+
+func bindataDataPtStreetsuffixestxt() (*asset, error) {
+	bytes, err := bindataDataPtStreetsuffixestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/street_suffixes.txt",
+		size:        0,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtStreetstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x8e\x41\x6e\xc2\x40\x0c\x45\xf7\x73\x0a\x5f\x25\x0a\xd0\x0d\x02\x94" +
+		"\x72\x81\x4f\x62\x81\xa5\x24\xae\x3c\x33\xb9\x50\x17\x55\x0f\xc0\x86\xed\x5c\xac\x2e\x03\x02\xc9\xd2\xc8\x9e\xf7" +
+		"\xbf\x5e\x97\x41\x4d\x3e\xe7\x98\x10\x9a\x85\x67\x19\x40\x3e\x5b\x39\xb1\x0d\x18\x38\x74\x0e\x0c\x4c\x9f\x98\x13" +
+		"\xa8\x45\x82\xc9\x8c\xfb\xf5\x03\x66\x9c\x52\x38\x18\xca\x8f\x43\x4a\xad\x4e\xe5\xd7\x7a\xd1\x9a\x52\xda\x67\xd3" +
+		"\x57\xad\x46\x6a\x46\x81\xbf\xf5\x1f\x91\x36\xa3\x1a\xd7\x75\x25\xc6\xe2\x16\x5b\xd8\x59\xef\x6d\x97\x7f\xf6\x29" +
+		"\xd0\x62\x2a\x57\x47\xdf\x24\x3b\xfe\x2a\xb7\xd3\x28\x7d\xf5\xd9\xe9\x82\x70\x34\x2c\x1c\x63\xd5\x81\x4d\x8f\x3c" +
+		"\xc8\x2d\xbd\x7c\x1d\x93\xa1\xa6\x5b\xb1\x3e\xcf\x0b\x46\xb7\x2f\xdf\x1a\xfe\x00\x7c\x13\x0d\x5a\x0a\x01\x00\x00" +
+		"")
+
+func bindataDataPtStreetstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtStreetstxt,
+		"data/pt/streets.txt",
+	)
+}
+
+func bindataDataPtStreetstxt() (*asset, error) {
+	bytes, err := bindataDataPtStreetstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/streets.txt",
+		size:        266,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtZipsformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x33\x54\x56\x56\xd6\x05\x62\x2e\x23\x18\xc3\x18\xc6\x30\x81\x31\x2c\x60" +
+		"\x0c\x00\x0e\xc3\xe0\x20\x2d\x00\x00\x00")
+
+func bindataDataPtZipsformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtZipsformattxt,
+		"data/pt/zips_format.txt",
+	)
+}
+
+func bindataDataPtZipsformattxt() (*asset, error) {
+	bytes, err := bindataDataPtZipsformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt/zips_format.txt",
+		size:        45,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtbrCitiestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x8e\x3d\x0a\xc2\x40\x10\x85\xfb\x39\xc5\x5e\x45\x05\x15\x51\x09\x09" +
+		"\xd8\x3f\x75\xd4\x81\xc9\x4e\xd8\x64\x2d\x3c\x8e\x16\x62\x61\x95\x23\xcc\xc5\x5c\xc5\xee\xf1\x7e\xf8\x5e\xe3\x0f" +
+		"\x0b\x15\xb2\x1a\xd5\x62\xe1\xc8\x61\x85\xc8\x92\x8c\xa6\x09\xbd\xbf\x55\x40\x0d\xf4\x8a\xa3\x25\x9a\x5b\x1a\xa0" +
+		"\x7c\x03\x4d\x59\x2d\x2c\x2d\xc9\xcd\xe2\xc0\xb4\x41\x44\xee\x69\x96\x93\x0c\xb2\x07\xd5\x7c\x90\x13\xd3\xc2\xc4" +
+		"\xef\x51\x7e\x75\x7f\xb5\x54\x95\xbd\x85\x89\xf2\x39\x95\x30\x23\x65\xbd\x58\x99\xa1\xed\x24\xa2\xa7\xe6\x7b\x66" +
+		"\x9d\xfd\xfd\x97\x0b\x8b\xfe\x44\xb9\xb6\xc1\x81\xc5\x47\xda\xa2\xf0\x69\xae\x05\x8c\xe8\x63\x67\x2a\x3d\xed\x64" +
+		"\xf0\xb1\x18\xf4\x01\x0e\xf1\x0d\x0d\xcc\x00\x00\x00")
+
+func bindataDataPtbrCitiestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtbrCitiestxt,
+		"data/pt_BR/cities.txt",
+	)
+}
+
+func bindataDataPtbrCitiestxt() (*asset, error) {
+	bytes, err := bindataDataPtbrCitiestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt_BR/cities.txt",
+		size:        204,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtbrPhonesformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd3\x36\x35\x55\x30\x34\x54\xb0\x54\x06\x02\x5d\x10\xc1\xa5\x0d\x14\x31" +
+		"\xc2\x10\x31\xc6\x10\x31\xc1\x10\x31\xc5\x10\x31\xc3\x10\x31\xc7\x10\xb1\x40\x11\x01\x00\x19\xae\x5e\xf2\x90\x00" +
+		"\x00\x00")
+
+func bindataDataPtbrPhonesformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtbrPhonesformattxt,
+		"data/pt_BR/phones_format.txt",
+	)
+}
+
+func bindataDataPtbrPhonesformattxt() (*asset, error) {
+	bytes, err := bindataDataPtbrPhonesformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt_BR/phones_format.txt",
+		size:        144,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtbrStatestxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5d\x4f\x41\x8e\x83\x30\x0c\xbc\xfb\x15\xf9\x0a\xed\x6e\x91\x56\x6a\x85" +
+		"\xa0\x1f\x98\x82\xd5\x5a\x82\x18\x39\xe1\xd2\xdf\x54\x7b\xe6\xb6\x3f\xc8\xc7\x6a\xb8\x6d\x2f\xf1\x78\x62\xcf\x8c" +
+		"\xab\xde\x98\xaa\x11\x77\x45\xa2\x6a\xc2\x5c\x5e\x5b\x79\x6a\xf4\xfe\x80\x87\x80\x8e\x0c\x73\xf6\x4b\x52\x36\xc9" +
+		"\x1a\x4e\x3c\xb0\x61\xa4\xef\x34\x97\x75\x67\x3a\xc4\xac\x54\xab\x94\x57\xa2\x33\x0c\xf1\x51\x7e\xd5\x91\xff\xd5" +
+		"\xa6\x29\xfd\xc3\x61\xf0\x8d\x65\xa4\xb3\xb8\x47\xa8\x5d\x4b\x12\x35\xbb\x87\xbf\x28\xeb\x0d\x3b\x88\x1b\xc1\x16" +
+		"\x31\xdd\x96\x5e\xa9\x11\x2c\x65\xa5\x56\x5c\x80\xc3\x0f\x22\x8b\xe9\xde\xd6\x3e\xeb\x94\xcb\x5e\xd4\x32\x7f\x70" +
+		"\x9b\x55\xab\x71\x28\x7f\xd1\x8f\x69\xd5\xed\x26\xd0\x16\x19\xe1\x88\x0c\xf3\x18\xd4\x79\xde\xd0\x60\x19\x95\x3a" +
+		"\xb6\xbb\xcc\x4c\x57\xed\x7d\x46\x62\xa2\x37\x22\x3f\xdf\x10\x24\x01\x00\x00")
+
+func bindataDataPtbrStatestxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtbrStatestxt,
+		"data/pt_BR/states.txt",
+	)
+}
+
+func bindataDataPtbrStatestxt() (*asset, error) {
+	bytes, err := bindataDataPtbrStatestxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt_BR/states.txt",
+		size:        292,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtbrStreetstxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x55\x8f\x4d\x0e\x82\x30\x10\x46\xf7\x9c\xa2\x57\x01\x0d\xc6\x8d\x12\x30" +
+		"\xc4\xed\x48\x27\xa4\x49\xe9\x98\xfe\x70\x20\x5d\x78\x07\xb7\xbd\x98\xd3\x12\x41\x57\x9d\xf4\xbd\xf9\xbe\x4c\x1b" +
+		"\x40\x48\x70\xa2\xd6\x64\xd1\x15\xe5\x8c\x46\x49\x10\x0d\x04\xad\x9c\x87\xa2\x65\x5e\x86\x31\xa4\xf9\x0b\x4b\xaf" +
+		"\xe3\xc3\x78\x35\x2c\xf8\xda\x0b\x89\xe2\x44\x33\x4e\x37\x4b\xab\x55\x59\x70\x4a\x67\xa3\x43\x8f\xc9\x49\xef\x9f" +
+		"\xd3\x70\xa7\x92\x68\x18\xf7\x60\x47\x70\x59\x67\xb0\x23\xe3\x48\x43\x7c\xc5\xe7\x66\xb7\x8a\x52\xaa\x19\x28\x6b" +
+		"\x67\x37\x80\x15\xb5\x45\x65\x71\x75\x0e\xe8\xe3\x5b\xb3\xf8\x93\xd7\x71\x88\xa8\xb8\x65\xd9\xbb\x28\x0b\xb9\x73" +
+		"\x3b\xf7\x78\xe7\x3f\x33\x2e\xf7\x74\xc0\xa6\x13\xfb\x30\x91\xf1\xc5\x07\x85\x60\x5b\xaf\x21\x01\x00\x00")
+
+func bindataDataPtbrStreetstxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtbrStreetstxt,
+		"data/pt_BR/streets.txt",
+	)
+}
+
+func bindataDataPtbrStreetstxt() (*asset, error) {
+	bytes, err := bindataDataPtbrStreetstxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt_BR/streets.txt",
+		size:        289,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
+var _bindataDataPtbrZipsformattxt = []byte(
+	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x33\x50\x06\x02\x5d\x20\xe6\x32\x84\xb3\x8c\xe0\x2c\x63\x38\xcb\x04\xce" +
+		"\x32\x85\xb3\xcc\xe0\x2c\x73\x38\xcb\x02\xce\xb2\x84\xb3\x00\x08\x0f\xe6\x43\x64\x00\x00\x00")
+
+func bindataDataPtbrZipsformattxtBytes() ([]byte, error) {
+	return bindataRead(
+		_bindataDataPtbrZipsformattxt,
+		"data/pt_BR/zips_format.txt",
+	)
+}
+
+func bindataDataPtbrZipsformattxt() (*asset, error) {
+	bytes, err := bindataDataPtbrZipsformattxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{
+		name:        "data/pt_BR/zips_format.txt",
+		size:        100,
+		md5checksum: "",
+		mode:        os.FileMode(420),
+		modTime:     time.Unix(1792108800, 0),
+	}
+
+	a := &asset{bytes: bytes, info: info}
+
+	return a, nil
+}
+
 var _bindataDataRuCharacterstxt = []byte(
 	"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x04\xc0\x55\x15\xc3\x00\x00\xc5\xd0\xff\xa8\x19\x83\xbb\x31\xc3\x99\x93" +
 		"\x31\xb7\xb5\xf0\xe2\xa8\x37\x67\x72\x21\x57\x72\x23\x77\xf2\xc0\x13\x79\x92\x17\x79\x93\x0f\xf9\x92\x1f\xf9\x93" +
@@ -6327,6 +8933,83 @@ var _bindata = map[string]func() (*asset, error){
 	"data/en/weekdays_short.txt":       bindataDataEnWeekdaysshorttxt,
 	"data/en/words.txt":                bindataDataEnWordstxt,
 	"data/en/zips_format.txt":          bindataDataEnZipsformattxt,
+	"data/es/cities.txt":               bindataDataEsCitiestxt,
+	"data/es/female_first_names.txt":   bindataDataEsFemalefirstnamestxt,
+	"data/es/female_last_names.txt":    bindataDataEsFemalelastnamestxt,
+	"data/es/female_patronymics.txt":   bindataDataEsFemalepatronymicstxt,
+	"data/es/male_first_names.txt":     bindataDataEsMalefirstnamestxt,
+	"data/es/male_last_names.txt":      bindataDataEsMalelastnamestxt,
+	"data/es/male_patronymics.txt":     bindataDataEsMalepatronymicstxt,
+	"data/es/phones_format.txt":        bindataDataEsPhonesformattxt,
+	"data/es/states.txt":               bindataDataEsStatestxt,
+	"data/es/street_suffixes.txt":      bindataDataEsStreetsuffixestxt,
+	"data/es/streets.txt":              bindataDataEsStreetstxt,
+	"data/es/zips_format.txt":          bindataDataEsZipsformattxt,
+	"data/fr/cities.txt":               bindataDataFrCitiestxt,
+	"data/fr/female_first_names.txt":   bindataDataFrFemalefirstnamestxt,
+	"data/fr/female_last_names.txt":    bindataDataFrFemalelastnamestxt,
+	"data/fr/female_patronymics.txt":   bindataDataFrFemalepatronymicstxt,
+	"data/fr/male_first_names.txt":     bindataDataFrMalefirstnamestxt,
+	"data/fr/male_last_names.txt":      bindataDataFrMalelastnamestxt,
+	"data/fr/male_patronymics.txt":     bindataDataFrMalepatronymicstxt,
+	"data/fr/phones_format.txt":        bindataDataFrPhonesformattxt,
+	"data/fr/states.txt":               bindataDataFrStatestxt,
+	"data/fr/street_suffixes.txt":      bindataDataFrStreetsuffixestxt,
+	"data/fr/streets.txt":              bindataDataFrStreetstxt,
+	"data/fr/zips_format.txt":          bindataDataFrZipsformattxt,
+	"data/it/cities.txt":               bindataDataItCitiestxt,
+	"data/it/female_first_names.txt":   bindataDataItFemalefirstnamestxt,
+	"data/it/female_last_names.txt":    bindataDataItFemalelastnamestxt,
+	"data/it/female_patronymics.txt":   bindataDataItFemalepatronymicstxt,
+	"data/it/male_first_names.txt":     bindataDataItMalefirstnamestxt,
+	"data/it/male_last_names.txt":      bindataDataItMalelastnamestxt,
+	"data/it/male_patronymics.txt":     bindataDataItMalepatronymicstxt,
+	"data/it/phones_format.txt":        bindataDataItPhonesformattxt,
+	"data/it/states.txt":               bindataDataItStatestxt,
+	"data/it/street_suffixes.txt":      bindataDataItStreetsuffixestxt,
+	"data/it/streets.txt":              bindataDataItStreetstxt,
+	"data/it/zips_format.txt":          bindataDataItZipsformattxt,
+	"data/nl/cities.txt":               bindataDataNlCitiestxt,
+	"data/nl/female_first_names.txt":   bindataDataNlFemalefirstnamestxt,
+	"data/nl/female_last_names.txt":    bindataDataNlFemalelastnamestxt,
+	"data/nl/female_patronymics.txt":   bindataDataNlFemalepatronymicstxt,
+	"data/nl/male_first_names.txt":     bindataDataNlMalefirstnamestxt,
+	"data/nl/male_last_names.txt":      bindataDataNlMalelastnamestxt,
+	"data/nl/male_patronymics.txt":     bindataDataNlMalepatronymicstxt,
+	"data/nl/phones_format.txt":        bindataDataNlPhonesformattxt,
+	"data/nl/states.txt":               bindataDataNlStatestxt,
+	"data/nl/street_suffixes.txt":      bindataDataNlStreetsuffixestxt,
+	"data/nl/streets.txt":              bindataDataNlStreetstxt,
+	"data/nl/zips_format.txt":          bindataDataNlZipsformattxt,
+	"data/pl/cities.txt":               bindataDataPlCitiestxt,
+	"data/pl/female_first_names.txt":   bindataDataPlFemalefirstnamestxt,
+	"data/pl/female_last_names.txt":    bindataDataPlFemalelastnamestxt,
+	"data/pl/female_patronymics.txt":   bindataDataPlFemalepatronymicstxt,
+	"data/pl/male_first_names.txt":     bindataDataPlMalefirstnamestxt,
+	"data/pl/male_last_names.txt":      bindataDataPlMalelastnamestxt,
+	"data/pl/male_patronymics.txt":     bindataDataPlMalepatronymicstxt,
+	"data/pl/phones_format.txt":        bindataDataPlPhonesformattxt,
+	"data/pl/states.txt":               bindataDataPlStatestxt,
+	"data/pl/street_suffixes.txt":      bindataDataPlStreetsuffixestxt,
+	"data/pl/streets.txt":              bindataDataPlStreetstxt,
+	"data/pl/zips_format.txt":          bindataDataPlZipsformattxt,
+	"data/pt/cities.txt":               bindataDataPtCitiestxt,
+	"data/pt/female_first_names.txt":   bindataDataPtFemalefirstnamestxt,
+	"data/pt/female_last_names.txt":    bindataDataPtFemalelastnamestxt,
+	"data/pt/female_patronymics.txt":   bindataDataPtFemalepatronymicstxt,
+	"data/pt/male_first_names.txt":     bindataDataPtMalefirstnamestxt,
+	"data/pt/male_last_names.txt":      bindataDataPtMalelastnamestxt,
+	"data/pt/male_patronymics.txt":     bindataDataPtMalepatronymicstxt,
+	"data/pt/phones_format.txt":        bindataDataPtPhonesformattxt,
+	"data/pt/states.txt":               bindataDataPtStatestxt,
+	"data/pt/street_suffixes.txt":      bindataDataPtStreetsuffixestxt,
+	"data/pt/streets.txt":              bindataDataPtStreetstxt,
+	"data/pt/zips_format.txt":          bindataDataPtZipsformattxt,
+	"data/pt_BR/cities.txt":            bindataDataPtbrCitiestxt,
+	"data/pt_BR/phones_format.txt":     bindataDataPtbrPhonesformattxt,
+	"data/pt_BR/states.txt":            bindataDataPtbrStatestxt,
+	"data/pt_BR/streets.txt":           bindataDataPtbrStreetstxt,
+	"data/pt_BR/zips_format.txt":       bindataDataPtbrZipsformattxt,
 	"data/ru/characters.txt":           bindataDataRuCharacterstxt,
 	"data/ru/cities.txt":               bindataDataRuCitiestxt,
 	"data/ru/colors.txt":               bindataDataRuColorstxt,
@@ -6489,6 +9172,97 @@ var _bintree = &bintree{Func: nil, Children: map[string]*bintree{
 			"words.txt":                {Func: bindataDataEnWordstxt, Children: map[string]*bintree{}},
 			"zips_format.txt":          {Func: bindataDataEnZipsformattxt, Children: map[string]*bintree{}},
 		}},
+		"es": {Func: nil, Children: map[string]*bintree{
+			"cities.txt":             {Func: bindataDataEsCitiestxt, Children: map[string]*bintree{}},
+			"female_first_names.txt": {Func: bindataDataEsFemalefirstnamestxt, Children: map[string]*bintree{}},
+			"female_last_names.txt":  {Func: bindataDataEsFemalelastnamestxt, Children: map[string]*bintree{}},
+			"female_patronymics.txt": {Func: bindataDataEsFemalepatronymicstxt, Children: map[string]*bintree{}},
+			"male_first_names.txt":   {Func: bindataDataEsMalefirstnamestxt, Children: map[string]*bintree{}},
+			"male_last_names.txt":    {Func: bindataDataEsMalelastnamestxt, Children: map[string]*bintree{}},
+			"male_patronymics.txt":   {Func: bindataDataEsMalepatronymicstxt, Children: map[string]*bintree{}},
+			"phones_format.txt":      {Func: bindataDataEsPhonesformattxt, Children: map[string]*bintree{}},
+			"states.txt":             {Func: bindataDataEsStatestxt, Children: map[string]*bintree{}},
+			"street_suffixes.txt":    {Func: bindataDataEsStreetsuffixestxt, Children: map[string]*bintree{}},
+			"streets.txt":            {Func: bindataDataEsStreetstxt, Children: map[string]*bintree{}},
+			"zips_format.txt":        {Func: bindataDataEsZipsformattxt, Children: map[string]*bintree{}},
+		}},
+		"fr": {Func: nil, Children: map[string]*bintree{
+			"cities.txt":             {Func: bindataDataFrCitiestxt, Children: map[string]*bintree{}},
+			"female_first_names.txt": {Func: bindataDataFrFemalefirstnamestxt, Children: map[string]*bintree{}},
+			"female_last_names.txt":  {Func: bindataDataFrFemalelastnamestxt, Children: map[string]*bintree{}},
+			"female_patronymics.txt": {Func: bindataDataFrFemalepatronymicstxt, Children: map[string]*bintree{}},
+			"male_first_names.txt":   {Func: bindataDataFrMalefirstnamestxt, Children: map[string]*bintree{}},
+			"male_last_names.txt":    {Func: bindataDataFrMalelastnamestxt, Children: map[string]*bintree{}},
+			"male_patronymics.txt":   {Func: bindataDataFrMalepatronymicstxt, Children: map[string]*bintree{}},
+			"phones_format.txt":      {Func: bindataDataFrPhonesformattxt, Children: map[string]*bintree{}},
+			"states.txt":             {Func: bindataDataFrStatestxt, Children: map[string]*bintree{}},
+			"street_suffixes.txt":    {Func: bindataDataFrStreetsuffixestxt, Children: map[string]*bintree{}},
+			"streets.txt":            {Func: bindataDataFrStreetstxt, Children: map[string]*bintree{}},
+			"zips_format.txt":        {Func: bindataDataFrZipsformattxt, Children: map[string]*bintree{}},
+		}},
+		"it": {Func: nil, Children: map[string]*bintree{
+			"cities.txt":             {Func: bindataDataItCitiestxt, Children: map[string]*bintree{}},
+			"female_first_names.txt": {Func: bindataDataItFemalefirstnamestxt, Children: map[string]*bintree{}},
+			"female_last_names.txt":  {Func: bindataDataItFemalelastnamestxt, Children: map[string]*bintree{}},
+			"female_patronymics.txt": {Func: bindataDataItFemalepatronymicstxt, Children: map[string]*bintree{}},
+			"male_first_names.txt":   {Func: bindataDataItMalefirstnamestxt, Children: map[string]*bintree{}},
+			"male_last_names.txt":    {Func: bindataDataItMalelastnamestxt, Children: map[string]*bintree{}},
+			"male_patronymics.txt":   {Func: bindataDataItMalepatronymicstxt, Children: map[string]*bintree{}},
+			"phones_format.txt":      {Func: bindataDataItPhonesformattxt, Children: map[string]*bintree{}},
+			"states.txt":             {Func: bindataDataItStatestxt, Children: map[string]*bintree{}},
+			"street_suffixes.txt":    {Func: bindataDataItStreetsuffixestxt, Children: map[string]*bintree{}},
+			"streets.txt":            {Func: bindataDataItStreetstxt, Children: map[string]*bintree{}},
+			"zips_format.txt":        {Func: bindataDataItZipsformattxt, Children: map[string]*bintree{}},
+		}},
+		"nl": {Func: nil, Children: map[string]*bintree{
+			"cities.txt":             {Func: bindataDataNlCitiestxt, Children: map[string]*bintree{}},
+			"female_first_names.txt": {Func: bindataDataNlFemalefirstnamestxt, Children: map[string]*bintree{}},
+			"female_last_names.txt":  {Func: bindataDataNlFemalelastnamestxt, Children: map[string]*bintree{}},
+			"female_patronymics.txt": {Func: bindataDataNlFemalepatronymicstxt, Children: map[string]*bintree{}},
+			"male_first_names.txt":   {Func: bindataDataNlMalefirstnamestxt, Children: map[string]*bintree{}},
+			"male_last_names.txt":    {Func: bindataDataNlMalelastnamestxt, Children: map[string]*bintree{}},
+			"male_patronymics.txt":   {Func: bindataDataNlMalepatronymicstxt, Children: map[string]*bintree{}},
+			"phones_format.txt":      {Func: bindataDataNlPhonesformattxt, Children: map[string]*bintree{}},
+			"states.txt":             {Func: bindataDataNlStatestxt, Children: map[string]*bintree{}},
+			"street_suffixes.txt":    {Func: bindataDataNlStreetsuffixestxt, Children: map[string]*bintree{}},
+			"streets.txt":            {Func: bindataDataNlStreetstxt, Children: map[string]*bintree{}},
+			"zips_format.txt":        {Func: bindataDataNlZipsformattxt, Children: map[string]*bintree{}},
+		}},
+		"pl": {Func: nil, Children: map[string]*bintree{
+			"cities.txt":             {Func: bindataDataPlCitiestxt, Children: map[string]*bintree{}},
+			"female_first_names.txt": {Func: bindataDataPlFemalefirstnamestxt, Children: map[string]*bintree{}},
+			"female_last_names.txt":  {Func: bindataDataPlFemalelastnamestxt, Children: map[string]*bintree{}},
+			"female_patronymics.txt": {Func: bindataDataPlFemalepatronymicstxt, Children: map[string]*bintree{}},
+			"male_first_names.txt":   {Func: bindataDataPlMalefirstnamestxt, Children: map[string]*bintree{}},
+			"male_last_names.txt":    {Func: bindataDataPlMalelastnamestxt, Children: map[string]*bintree{}},
+			"male_patronymics.txt":   {Func: bindataDataPlMalepatronymicstxt, Children: map[string]*bintree{}},
+			"phones_format.txt":      {Func: bindataDataPlPhonesformattxt, Children: map[string]*bintree{}},
+			"states.txt":             {Func: bindataDataPlStatestxt, Children: map[string]*bintree{}},
+			"street_suffixes.txt":    {Func: bindataDataPlStreetsuffixestxt, Children: map[string]*bintree{}},
+			"streets.txt":            {Func: bindataDataPlStreetstxt, Children: map[string]*bintree{}},
+			"zips_format.txt":        {Func: bindataDataPlZipsformattxt, Children: map[string]*bintree{}},
+		}},
+		"pt": {Func: nil, Children: map[string]*bintree{
+			"cities.txt":             {Func: bindataDataPtCitiestxt, Children: map[string]*bintree{}},
+			"female_first_names.txt": {Func: bindataDataPtFemalefirstnamestxt, Children: map[string]*bintree{}},
+			"female_last_names.txt":  {Func: bindataDataPtFemalelastnamestxt, Children: map[string]*bintree{}},
+			"female_patronymics.txt": {Func: bindataDataPtFemalepatronymicstxt, Children: map[string]*bintree{}},
+			"male_first_names.txt":   {Func: bindataDataPtMalefirstnamestxt, Children: map[string]*bintree{}},
+			"male_last_names.txt":    {Func: bindataDataPtMalelastnamestxt, Children: map[string]*bintree{}},
+			"male_patronymics.txt":   {Func: bindataDataPtMalepatronymicstxt, Children: map[string]*bintree{}},
+			"phones_format.txt":      {Func: bindataDataPtPhonesformattxt, Children: map[string]*bintree{}},
+			"states.txt":             {Func: bindataDataPtStatestxt, Children: map[string]*bintree{}},
+			"street_suffixes.txt":    {Func: bindataDataPtStreetsuffixestxt, Children: map[string]*bintree{}},
+			"streets.txt":            {Func: bindataDataPtStreetstxt, Children: map[string]*bintree{}},
+			"zips_format.txt":        {Func: bindataDataPtZipsformattxt, Children: map[string]*bintree{}},
+		}},
+		"pt_BR": {Func: nil, Children: map[string]*bintree{
+			"cities.txt":        {Func: bindataDataPtbrCitiestxt, Children: map[string]*bintree{}},
+			"phones_format.txt": {Func: bindataDataPtbrPhonesformattxt, Children: map[string]*bintree{}},
+			"states.txt":        {Func: bindataDataPtbrStatestxt, Children: map[string]*bintree{}},
+			"streets.txt":       {Func: bindataDataPtbrStreetstxt, Children: map[string]*bintree{}},
+			"zips_format.txt":   {Func: bindataDataPtbrZipsformattxt, Children: map[string]*bintree{}},
+		}},
 		"ru": {Func: nil, Children: map[string]*bintree{
 			"characters.txt":         {Func: bindataDataRuCharacterstxt, Children: map[string]*bintree{}},
 			"cities.txt":             {Func: bindataDataRuCitiestxt, Children: map[string]*bintree{}},
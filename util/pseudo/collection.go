@@ -0,0 +1,174 @@
+package pseudo
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+)
+
+const (
+	// tagLenPrefix defines the length of a slice or map in the struct tag,
+	// e.g. `faker:"len=3..7"` or `faker:"len=5"`.
+	tagLenPrefix = "len="
+	// DefaultMinCollectionLen default see Options.MinCollectionLen.
+	DefaultMinCollectionLen = 1
+	// DefaultMaxCollectionLen default see Options.MaxCollectionLen.
+	DefaultMaxCollectionLen = 5
+	// DefaultMaxTypeRecursion default see Options.MaxTypeRecursion.
+	DefaultMaxTypeRecursion = 2
+	// mapKeyAttempts defines how often per element a key gets generated.
+	mapKeyAttempts = 3
+)
+
+// WithTypeFakeFunc generates the values of type t with fn, e.g. for a type
+// Money struct or a []OrderItem which gets stored as a JSON column. The
+// returned value must be convertible to t, a nil value leaves the zero value.
+// Functions for a tag or field name have precedence.
+func WithTypeFakeFunc(t reflect.Type, fn FakeFunc) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			if t == nil || fn == nil {
+				return errors.NotValid.Newf("[pseudo] WithTypeFakeFunc requires a type and a function")
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.typeFuncs == nil {
+				s.typeFuncs = make(map[reflect.Type]FakeFunc)
+			}
+			s.typeFuncs[t] = fn
+			return nil
+		},
+	}
+}
+
+// typeFuncValue generates the value of t with a function of WithTypeFakeFunc.
+func (s *Service) typeFuncValue(t reflect.Type, maxLen uint64) (reflect.Value, bool, error) {
+	s.mu.RLock()
+	fn, ok := s.typeFuncs[t]
+	s.mu.RUnlock()
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+	iFaceVal := fn(int(maxLen))
+	if iFaceVal == nil {
+		return reflect.Zero(t), true, nil
+	}
+	val := reflect.ValueOf(iFaceVal)
+	if !val.Type().ConvertibleTo(t) {
+		return reflect.Value{}, true, errors.NotValid.Newf("[pseudo] WithTypeFakeFunc for type %s returned the type %s", t, val.Type())
+	}
+	return val.Convert(t), true, nil
+}
+
+// parseLenTag parses the range of a len tag, e.g. "3..7" or "5".
+func parseLenTag(tag string) (min, max int, err error) {
+	rng := strings.TrimPrefix(tag, tagLenPrefix)
+	minStr, maxStr := rng, rng
+	if i := strings.Index(rng, ".."); i >= 0 {
+		minStr, maxStr = rng[:i], rng[i+2:]
+	}
+	if min, err = strconv.Atoi(minStr); err == nil {
+		max, err = strconv.Atoi(maxStr)
+	}
+	if err != nil || min < 0 || min > max {
+		return 0, 0, errors.NotValid.Newf("[pseudo] Invalid tag %q, want len=min..max or len=n", tag)
+	}
+	return min, max, nil
+}
+
+// checkLenTag validates the len tag of field f of struct t.
+func checkLenTag(t reflect.Type, f reflect.StructField) error {
+	if _, _, err := parseLenTag(f.Tag.Get(tagName)); err != nil {
+		return errors.Wrapf(err, "[pseudo] Field %s.%s", t, f.Name)
+	}
+	if k := f.Type.Kind(); k != reflect.Slice && k != reflect.Map {
+		return errors.NotSupported.Newf("[pseudo] Field %s.%s: type %s does not support the len tag", t, f.Name, f.Type)
+	}
+	return nil
+}
+
+// randomLen returns a random length between min and max, both inclusive.
+func (s *Service) randomLen(min, max int) int {
+	if max > min {
+		return min + s.r.Intn(max-min+1)
+	}
+	return min
+}
+
+// collectionLen returns the length of a slice or map without a len tag. A
+// byte slice behaves like a string and gets limited by maxLen.
+func (s *Service) collectionLen(t reflect.Type, maxLen uint64) int {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		if maxLen > s.o.MaxLenStringLimit {
+			maxLen = s.o.MaxLenStringLimit
+		}
+		return int(s.r.Uint64n(maxLen))
+	}
+	return s.randomLen(s.o.MinCollectionLen, s.o.MaxCollectionLen)
+}
+
+// isTypeCycle reports whether the struct type of t, even behind pointers,
+// slices, arrays or maps, occurs already Options.MaxTypeRecursion times in
+// the path.
+func (s *Service) isTypeCycle(t reflect.Type, path []reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	n := 0
+	for _, pt := range path {
+		if pt == t {
+			n++
+		}
+	}
+	return n >= s.o.MaxTypeRecursion
+}
+
+// fakeCollection generates a slice, an array or a map of type t with n
+// elements. The length of an array is fixed.
+func (s *Service) fakeCollection(t reflect.Type, n int, maxLen uint64, recursionLevel int, seed uint64, path []reflect.Type) (reflect.Value, error) {
+	var v reflect.Value
+	switch t.Kind() {
+	case reflect.Array:
+		v = reflect.New(t).Elem()
+		n = t.Len()
+	case reflect.Slice:
+		v = reflect.MakeSlice(t, n, n)
+	case reflect.Map:
+		v = reflect.MakeMapWithSize(t, n)
+		// Duplicate keys get generated again, but a key type like bool limits
+		// the length.
+		for i := uint64(0); v.Len() < n && i < uint64(n*mapKeyAttempts); i++ {
+			key, err := s.getValue(t.Key(), maxLen, recursionLevel+1, mixSeed(seed, i), path)
+			if err != nil {
+				return v, err
+			}
+			val, err := s.getValue(t.Elem(), maxLen, recursionLevel+1, mixSeed(seed, i), path)
+			if err != nil {
+				return v, err
+			}
+			if !key.IsValid() || !val.IsValid() {
+				// the key or element type, like interface{}, cannot be
+				// generated, so the map stays empty.
+				return v, nil
+			}
+			v.SetMapIndex(key.Convert(t.Key()), val.Convert(t.Elem()))
+		}
+		return v, nil
+	}
+	for i := 0; i < n; i++ {
+		val, err := s.getValue(t.Elem(), maxLen, recursionLevel+1, mixSeed(seed, uint64(i)), path)
+		if err != nil {
+			return v, err
+		}
+		if val.IsValid() {
+			v.Index(i).Set(val.Convert(t.Elem()))
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,72 @@
+package pseudo
+
+// WithDeterministicPerField derives the PRNG stream of each struct field from
+// the seed, the struct type name and the field name. Adding, removing or
+// reordering fields of a struct does not change the generated values of the
+// other fields, which keeps golden files stable. The n-th call of FakeData for
+// a type always generates the same data. FakeData calls get serialized and the
+// Service should not be used concurrently for other fake data generation
+// because all functions share the same PRNG. The seed argument of NewService
+// must be set, otherwise the current time gets used. Functions added with
+// WithTagFakeFunc read from the field stream when they use the Service. The
+// sequential "id" tag and values depending on the current time are not
+// deterministic.
+func WithDeterministicPerField() optionFn {
+	return optionFn{
+		sortOrder: 1,
+		fn: func(s *Service) error {
+			s.deterministic = true
+			return nil
+		},
+	}
+}
+
+// ReseedField changes the PRNG stream of a struct field in deterministic mode
+// to intentionally generate different values for that field only. The typeName
+// contains the package name, e.g. "store.Customer", like the keys of
+// WithTagFakeFunc. The same salt always generates the same values. A salt of
+// zero restores the default stream.
+func (s *Service) ReseedField(typeName, fieldName string, salt uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := typeName + "." + fieldName
+	if salt == 0 {
+		delete(s.fieldSalts, key)
+		return
+	}
+	s.fieldSalts[key] = salt
+}
+
+// fieldSeed hashes with inlined FNV-1a the parent seed, the type and the field
+// name and its optional salt.
+func (s *Service) fieldSeed(parent uint64, typeName, fieldName string) uint64 {
+	s.mu.RLock()
+	salt := s.fieldSalts[typeName+"."+fieldName]
+	s.mu.RUnlock()
+
+	const prime64 = 1099511628211
+	h := uint64(14695981039346656037)
+	for i := uint(0); i < 64; i += 8 {
+		h ^= (parent >> i) & 0xff
+		h *= prime64
+	}
+	for i := 0; i < len(typeName); i++ {
+		h ^= uint64(typeName[i])
+		h *= prime64
+	}
+	h ^= '.'
+	h *= prime64
+	for i := 0; i < len(fieldName); i++ {
+		h ^= uint64(fieldName[i])
+		h *= prime64
+	}
+	return mixSeed(h, salt)
+}
+
+// mixSeed combines two values with the finalizer of SplitMix64.
+func mixSeed(seed, v uint64) uint64 {
+	z := seed + (v+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,128 @@
+package pseudo
+
+import (
+	"testing"
+
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type detAddress struct {
+	Street string `faker:"street"`
+	City   string `faker:"city"`
+	Zip    string
+}
+
+type detItem struct {
+	Label string
+	Qty   uint16
+}
+
+func newDeterministicService() *Service {
+	return MustNewService(4711, nil, WithDeterministicPerField(), WithTagFakeFunc("sku", func(maxLen int) interface{} {
+		return "SKU-4711"
+	}))
+}
+
+// detCommon contains the fields shared by both versions of the fixture.
+type detCommon struct {
+	Name    string
+	Email   string
+	SKU     string
+	Qty     int64
+	Price   float64
+	Address detAddress
+	Items   []detItem
+}
+
+func fakeFixtureV1(t *testing.T) detCommon {
+	type fixture struct {
+		Name    string `faker:"name"`
+		Email   string `faker:"email"`
+		SKU     string `faker:"sku"`
+		Qty     int64
+		Price   float64
+		Address detAddress
+		Items   []detItem
+	}
+	var v fixture
+	assert.NoError(t, newDeterministicService().FakeData(&v))
+	return detCommon(v)
+}
+
+// fakeFixtureV2 adds fields and reorders the fields of fixture version 1.
+func fakeFixtureV2(t *testing.T) detCommon {
+	type fixture struct {
+		Qty     int64
+		Name    string `faker:"name"`
+		Added   string `faker:"sentence"`
+		Email   string `faker:"email"`
+		SKU     string `faker:"sku"`
+		Price   float64
+		Items   []detItem
+		Address detAddress
+		Street  string `faker:"street"`
+	}
+	var v fixture
+	assert.NoError(t, newDeterministicService().FakeData(&v))
+	assert.NotEmpty(t, v.Added)
+	return detCommon{
+		Name: v.Name, Email: v.Email, SKU: v.SKU, Qty: v.Qty, Price: v.Price,
+		Address: v.Address, Items: v.Items,
+	}
+}
+
+func TestWithDeterministicPerField(t *testing.T) {
+	t.Run("field addition does not shift values", func(t *testing.T) {
+		v1 := fakeFixtureV1(t)
+		assert.Exactly(t, "SKU-4711", v1.SKU)
+		assert.NotEmpty(t, v1.Name)
+		assert.Exactly(t, v1, fakeFixtureV2(t))
+	})
+
+	t.Run("nested struct values depend on the path", func(t *testing.T) {
+		var v struct {
+			Billing  detAddress
+			Shipping detAddress
+			Items    []detItem
+		}
+		s := newDeterministicService()
+		for i := 0; i < 5 && len(v.Items) < 2; i++ {
+			assert.NoError(t, s.FakeData(&v))
+		}
+		assert.NotEqual(t, v.Billing, v.Shipping)
+		if len(v.Items) > 1 {
+			assert.NotEqual(t, v.Items[0], v.Items[1])
+		}
+	})
+
+	t.Run("successive calls differ but are reproducible", func(t *testing.T) {
+		s1 := newDeterministicService()
+		s2 := newDeterministicService()
+		var a1, b1, a2, b2 detAddress
+		assert.NoError(t, s1.FakeData(&a1))
+		assert.NoError(t, s1.FakeData(&b1))
+		assert.NoError(t, s2.FakeData(&a2))
+		assert.NoError(t, s2.FakeData(&b2))
+		assert.NotEqual(t, a1, b1)
+		assert.Exactly(t, a1, a2)
+		assert.Exactly(t, b1, b2)
+	})
+
+	t.Run("ReseedField", func(t *testing.T) {
+		var a1, a2, a3 detAddress
+		assert.NoError(t, newDeterministicService().FakeData(&a1))
+
+		s := newDeterministicService()
+		s.ReseedField("pseudo.detAddress", "Zip", 2)
+		assert.NoError(t, s.FakeData(&a2))
+		assert.Exactly(t, a1.Street, a2.Street)
+		assert.Exactly(t, a1.City, a2.City)
+		assert.NotEqual(t, a1.Zip, a2.Zip)
+
+		s = newDeterministicService()
+		s.ReseedField("pseudo.detAddress", "Zip", 2)
+		s.ReseedField("pseudo.detAddress", "Zip", 0)
+		assert.NoError(t, s.FakeData(&a3))
+		assert.Exactly(t, a1, a3)
+	})
+}
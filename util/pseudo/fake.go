@@ -10,7 +10,9 @@
 //
 // Most data and methods are ported from forgery/ffaker Ruby gems.
 //
-// Currently english and russian languages are available.
+// Currently the languages de, en, es, fr, it, nl, pl, pt, pt_BR and ru are
+// available. If a data set is missing, a region specific language like pt_BR
+// falls back to its base language pt and then to en.
 //
 // For the list of available methods please look at
 // https://godoc.org/github.com/icrowley/fake.
@@ -99,6 +101,21 @@ type Options struct {
 	MaxFloatDecimals int
 	// MaxRecursionLevel default see DefaultMaxRecursionLevel
 	MaxRecursionLevel int
+	// MinCollectionLen and MaxCollectionLen define the length of generated
+	// slices and maps without the struct tag `faker:"len=3..7"`. Byte slices
+	// get limited by max_len. Defaults see DefaultMinCollectionLen and
+	// DefaultMaxCollectionLen.
+	MinCollectionLen int
+	MaxCollectionLen int
+	// MaxTypeRecursion defines how often the same struct type can occur in the
+	// path from the root to a generated value, to stop self referencing types
+	// with a nil pointer or an empty slice or map. Default see
+	// DefaultMaxTypeRecursion.
+	MaxTypeRecursion int
+	// RegexMaxRepeat limits the repetitions of unbounded quantifiers like * or
+	// + in regular expressions, see WithTagRegex. Default see
+	// DefaultRegexMaxRepeat.
+	RegexMaxRepeat int
 }
 
 const maxLenStringLimit = 512
@@ -148,11 +165,29 @@ type Service struct {
 	o           Options
 	id          *uint64
 	ulidEntropy io.Reader
+	seed        uint64
+
+	// detMu serializes FakeData if deterministic has been enabled with
+	// WithDeterministicPerField.
+	detMu         sync.Mutex
+	deterministic bool
+	detCalls      map[reflect.Type]uint64
 
 	mu           sync.RWMutex
 	langMapping  map[string]map[string][]string // cat/subcat/lang/samples
 	funcs        map[string]FakeFunc
 	funcsAliases map[string]string // alias name => original name
+	fieldSalts   map[string]uint64 // typeName.FieldName => salt, see ReseedField
+	fieldLimits  map[string]FieldLimit
+	foreignPools map[string]func() interface{} // see WithForeignPool
+	recordGraph  *RecordGraph
+	regexes      map[string]*regexGenerator // pattern => generator
+	// nullProbabilities see WithNullProbability
+	nullProbabilities map[string]float64
+	timeSpecs         map[string]*timeSpec // field => spec, see WithTimeRange
+	timeTags          map[string]*timeSpec // struct tag => spec
+	monotonicTimes    map[string]time.Time // typeName.FieldName => previous time
+	typeFuncs         map[reflect.Type]FakeFunc
 }
 
 // MustNewService creates a new Service but panics on error.
@@ -186,13 +221,31 @@ func NewService(seed uint64, o *Options, opts ...optionFn) (*Service, error) {
 	if o.MaxRecursionLevel == 0 {
 		o.MaxRecursionLevel = DefaultMaxRecursionLevel
 	}
+	if o.RegexMaxRepeat == 0 {
+		o.RegexMaxRepeat = DefaultRegexMaxRepeat
+	}
+	if o.MinCollectionLen == 0 && o.MaxCollectionLen == 0 {
+		o.MinCollectionLen, o.MaxCollectionLen = DefaultMinCollectionLen, DefaultMaxCollectionLen
+	}
+	if o.MinCollectionLen < 0 || o.MinCollectionLen > o.MaxCollectionLen {
+		return nil, errors.NotValid.Newf("[pseudo] MinCollectionLen %d must be between zero and MaxCollectionLen %d", o.MinCollectionLen, o.MaxCollectionLen)
+	}
+	if o.MaxTypeRecursion == 0 {
+		o.MaxTypeRecursion = DefaultMaxTypeRecursion
+	}
 
 	s := &Service{
-		langMapping: make(map[string]map[string][]string),
-		r:           rand.New(&lockedSource{src: rand.NewSource(seed)}),
-		o:           *o,
-		id:          new(uint64),
-		ulidEntropy: ulid.Monotonic(rand.New(rand.NewSource(seed)), 0),
+		langMapping:    make(map[string]map[string][]string),
+		r:              rand.New(&lockedSource{src: rand.NewSource(seed)}),
+		o:              *o,
+		id:             new(uint64),
+		ulidEntropy:    ulid.Monotonic(rand.New(rand.NewSource(seed)), 0),
+		seed:           seed,
+		detCalls:       make(map[reflect.Type]uint64),
+		fieldSalts:     make(map[string]uint64),
+		regexes:        make(map[string]*regexGenerator),
+		timeTags:       make(map[string]*timeSpec),
+		monotonicTimes: make(map[string]time.Time),
 	}
 
 	s.funcs = map[string]FakeFunc{
@@ -323,17 +376,22 @@ func join(parts ...string) string {
 	return strings.Join(filtered, " ")
 }
 
+// generate replaces in a random format of category cat each # with a digit
+// and each ? with an upper case letter.
 func (s *Service) generate(lang, cat string, fallback bool) string {
 	format := s.lookup(lang, cat+"_format", fallback)
-	var result string
+	var result strings.Builder
 	for _, ru := range format {
-		if ru != '#' {
-			result += string(ru)
-		} else {
-			result += strconv.Itoa(s.r.Intn(10))
+		switch ru {
+		case '#':
+			result.WriteByte(byte('0' + s.r.Intn(10)))
+		case '?':
+			result.WriteByte(byte('A' + s.r.Intn(26)))
+		default:
+			result.WriteRune(ru)
 		}
 	}
-	return result
+	return result.String()
 }
 
 func (s *Service) lookup(lang, cat string, fallback bool) string {
@@ -349,7 +407,16 @@ func (s *Service) _lookup(lang, cat string, fallback bool) string {
 
 	samples, err := s.populateSamples(lang, cat)
 	if err != nil {
-		if pe, ok := err.(*os.PathError); lang != "en" && fallback && s.o.EnFallback && ok && pe.Err == os.ErrNotExist {
+		pe, ok := err.(*os.PathError)
+		if !ok || pe.Err != os.ErrNotExist {
+			return ""
+		}
+		// A region specific language falls back to its base language, e.g.
+		// pt_BR to pt, and then optionally to en.
+		if i := strings.LastIndexByte(lang, '_'); i > 0 {
+			return s._lookup(lang[:i], cat, fallback)
+		}
+		if lang != "en" && fallback && s.o.EnFallback {
 			return s._lookup("en", cat, false)
 		}
 		return ""
@@ -391,10 +458,21 @@ func (s *Service) FakeData(ptr interface{}) error {
 		return errors.NotSupported.Newf("[pseudo] Nil/Non-pointer values are not supported. Argument ptr should be a pointer.")
 	}
 
-	finalValue, err := s.getValue(reflectType.Elem(), 0, 0)
+	var seed uint64
+	if s.deterministic {
+		s.detMu.Lock()
+		defer s.detMu.Unlock()
+		seed = mixSeed(s.seed, s.detCalls[reflectType])
+		s.detCalls[reflectType]++
+	}
+
+	finalValue, err := s.getValue(reflectType.Elem(), 0, 0, seed, nil)
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	if !finalValue.IsValid() {
+		return nil
+	}
 
 	rVal := reflect.ValueOf(ptr)
 	rVal.Elem().Set(finalValue.Convert(reflectType.Elem()))
@@ -405,21 +483,29 @@ type scanner interface {
 	Scan(interface{}) error
 }
 
-func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int) (rVal reflect.Value, err error) {
+// getValue generates a value for type t. The argument seed gets only used in
+// deterministic mode to derive the PRNG streams of struct fields. The argument
+// path contains the struct types from the root to t to detect cycles. An
+// invalid returned value means that t has been skipped.
+func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int, seed uint64, path []reflect.Type) (rVal reflect.Value, err error) {
 	k := t.Kind()
 
 	if maxLen == 0 {
 		maxLen = math.MaxInt8
 	}
 
+	if val, ok, err := s.typeFuncValue(t, maxLen); ok {
+		return val, errors.WithStack(err)
+	}
+
 	switch k {
 	case reflect.Ptr:
-		if recursionLevel < s.o.MaxRecursionLevel {
-			v := reflect.New(t.Elem())
-			val, err := s.getValue(t.Elem(), maxLen, recursionLevel+1)
-			if err != nil {
+		if recursionLevel < s.o.MaxRecursionLevel && !s.isTypeCycle(t.Elem(), path) {
+			val, err := s.getValue(t.Elem(), maxLen, recursionLevel+1, seed, path)
+			if err != nil || !val.IsValid() {
 				return rVal, err
 			}
+			v := reflect.New(t.Elem())
 			v.Elem().Set(val.Convert(t.Elem()))
 			return v, nil
 		}
@@ -437,6 +523,7 @@ func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int) (r
 			return reflect.ValueOf(ft), nil
 		default:
 			v := reflect.New(t).Elem()
+			path = append(path, t)
 
 			var fkr Faker
 			if v.CanInterface() {
@@ -458,6 +545,12 @@ func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int) (r
 					continue
 				}
 
+				fieldSeed := seed
+				if s.deterministic {
+					fieldSeed = s.fieldSeed(seed, typeName, tf.Name)
+					s.r.Seed(fieldSeed)
+				}
+
 				if maxLenTag := tf.Tag.Get(tagMaxLenName); maxLenTag != "" {
 					maxLen, err = strconv.ParseUint(maxLenTag, 10, 64)
 					if err != nil {
@@ -504,6 +597,12 @@ func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int) (r
 							continue
 						default:
 							val := reflect.ValueOf(iFaceVal)
+							if val.Kind() == reflect.String && (isNumberKind(vf.Kind()) || vf.Kind() == reflect.Bool) {
+								if err := setConvertedValue(vf, iFaceVal, sTag); err != nil {
+									return rVal, errors.WithStack(err)
+								}
+								continue
+							}
 							if isConvertibleType(vf.Kind()) && isConvertibleType(val.Kind()) {
 								val = val.Convert(vf.Type())
 								vf.Set(val)
@@ -523,12 +622,12 @@ func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int) (r
 				}
 
 				switch {
-				case tag == "":
+				case tag == "" || isTimeTag(tag):
 					// The check of isConvertibleType protects from a panic of
 					// Convert. Especially useful when an exported field has a
 					// func or interface or channel type.
 					if recursionLevel < s.o.MaxRecursionLevel {
-						val, err := s.getValue(vf.Type(), maxLen, recursionLevel+1)
+						val, err := s.getValue(vf.Type(), maxLen, recursionLevel+1, fieldSeed, path)
 						if err != nil {
 							return reflect.Value{}, err
 						}
@@ -539,6 +638,18 @@ func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int) (r
 							vf.Set(val)
 						}
 					}
+				case strings.HasPrefix(tag, tagLenPrefix):
+					if err := checkLenTag(t, tf); err != nil {
+						return reflect.Value{}, err
+					}
+					if recursionLevel < s.o.MaxRecursionLevel && !s.isTypeCycle(vf.Type(), path) {
+						min, max, _ := parseLenTag(tag)
+						val, err := s.fakeCollection(vf.Type(), s.randomLen(min, max), maxLen, recursionLevel+1, fieldSeed, path)
+						if err != nil {
+							return reflect.Value{}, err
+						}
+						vf.Set(val)
+					}
 				default:
 					err := s.setDataWithTag(vf.Addr(), tag, maxLen, false)
 					if err != nil {
@@ -553,6 +664,17 @@ func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int) (r
 
 			if shouldResetField {
 				v.Set(reflect.New(t).Elem())
+			} else {
+				if err := s.applyNullProbabilities(v, typeName, recursionLevel, seed); err != nil {
+					return reflect.Value{}, err
+				}
+				s.applyFieldLimits(v, typeName, seed)
+				if err := s.applyForeignKeys(v, typeName, seed); err != nil {
+					return reflect.Value{}, err
+				}
+				if err := s.applyTimeSpecs(v, typeName, seed); err != nil {
+					return reflect.Value{}, err
+				}
 			}
 
 			return v, nil
@@ -565,22 +687,17 @@ func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int) (r
 		}
 		res := s.randomString(ml)
 		return reflect.ValueOf(res), nil
-	case reflect.Array, reflect.Slice:
-		if recursionLevel < s.o.MaxRecursionLevel {
+	case reflect.Array, reflect.Slice, reflect.Map:
+		if recursionLevel < s.o.MaxRecursionLevel && !s.isTypeCycle(t, path) {
 			ml := maxLen
 			if ml > s.o.MaxLenStringLimit {
 				ml = s.o.MaxLenStringLimit
 			}
-			slLen := s.r.Uint64n(ml)
-			v := reflect.MakeSlice(t, int(slLen), int(slLen))
-			for i := 0; i < v.Len(); i++ {
-				val, err := s.getValue(t.Elem(), ml, recursionLevel+1)
-				if err != nil {
-					return rVal, err
-				}
-				v.Index(i).Set(val)
+			var n int
+			if k != reflect.Array {
+				n = s.collectionLen(t, ml)
 			}
-			return v, nil
+			return s.fakeCollection(t, n, ml, recursionLevel, seed, path)
 		}
 	case reflect.Int:
 		return reflect.ValueOf(int(s.r.Uint64n(maxLen))), nil
@@ -623,24 +740,6 @@ func (s *Service) getValue(t reflect.Type, maxLen uint64, recursionLevel int) (r
 	case reflect.Uint64:
 		return reflect.ValueOf(uint64(s.r.Uint64n(maxLen))), nil
 
-	case reflect.Map:
-		if recursionLevel < s.o.MaxRecursionLevel {
-			v := reflect.MakeMap(t)
-			randLen := s.r.Uint64n(maxLen)
-			var i uint64
-			for ; i < randLen; i++ {
-				key, err := s.getValue(t.Key(), maxLen, recursionLevel+1)
-				if err != nil {
-					return rVal, err
-				}
-				val, err := s.getValue(t.Elem(), maxLen, recursionLevel+1)
-				if err != nil {
-					return rVal, err
-				}
-				v.SetMapIndex(key, val)
-			}
-			return v, nil
-		}
 	case reflect.Func, reflect.Chan, reflect.Interface, reflect.Complex64, reflect.Complex128:
 		// ignore
 		return rVal, nil
@@ -700,16 +799,28 @@ func (s *Service) setDataWithTag(v reflect.Value, tag string, maxLen uint64, tag
 	if fnAlias, ok := s.funcsAliases[tag]; ok && fnAlias != "" {
 		tag = fnAlias
 	}
-	fn, ok := s.funcs[tag]
-	if !ok && tagIsFieldName {
-		return nil
-	}
-	if !ok && !tagIsFieldName {
-		return errors.NotFound.Newf("[pseudo] Tag %q not found in map", tag)
+	var iFaceVal interface{}
+	if pattern := strings.TrimPrefix(tag, tagRegexPrefix); len(pattern) < len(tag) {
+		rg, err := s.regexGenerator(pattern)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		iFaceVal = s.regexString(rg, int(maxLen))
+	} else {
+		fn, ok := s.funcs[tag]
+		if !ok && tagIsFieldName {
+			return nil
+		}
+		if !ok && !tagIsFieldName {
+			return errors.NotFound.Newf("[pseudo] Tag %q not found in map", tag)
+		}
+		iFaceVal = fn(int(maxLen))
 	}
-	iFaceVal := fn(int(maxLen))
+	return setConvertedValue(reflect.Indirect(v), iFaceVal, tag)
+}
 
-	v = reflect.Indirect(v)
+// setConvertedValue converts iFaceVal to the kind of v.
+func setConvertedValue(v reflect.Value, iFaceVal interface{}, tag string) error {
 	switch k := v.Kind(); k {
 	case reflect.Float32, reflect.Float64:
 		val, err := conv.ToFloat64E(iFaceVal)
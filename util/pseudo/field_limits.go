@@ -0,0 +1,257 @@
+package pseudo
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldLimit describes the constraints of a database column. Generated values
+// get truncated or wrapped to fit into the column, which avoids errors like
+// 1406 (data too long) or 1264 (out of range) on insert.
+type FieldLimit struct {
+	// MaxLen defines the maximum number of characters of a string or the
+	// maximum number of bytes of a byte slice.
+	MaxLen int
+	// IntBits defines the size of an integer column: 8, 16, 24, 32 or 64.
+	IntBits int
+	// Unsigned disallows negative numbers.
+	Unsigned bool
+	// Precision and Scale of a DECIMAL column.
+	Precision int
+	Scale     int
+	// Enum contains the valid members of an ENUM column.
+	Enum []string
+}
+
+// NewFieldLimit creates a FieldLimit from the information_schema.COLUMNS
+// values DATA_TYPE, COLUMN_TYPE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION
+// and NUMERIC_SCALE, for example from a ddl.Column.
+func NewFieldLimit(dataType, columnType string, charMaxLength, precision, scale int64) FieldLimit {
+	fl := FieldLimit{
+		MaxLen:   int(charMaxLength),
+		Unsigned: strings.Contains(strings.ToLower(columnType), "unsigned"),
+	}
+	switch strings.ToLower(dataType) {
+	case "tinyint":
+		fl.IntBits = 8
+	case "smallint":
+		fl.IntBits = 16
+	case "mediumint":
+		fl.IntBits = 24
+	case "int", "integer":
+		fl.IntBits = 32
+	case "bigint":
+		fl.IntBits = 64
+	case "decimal", "numeric":
+		fl.Precision = int(precision)
+		fl.Scale = int(scale)
+	case "enum":
+		fl.Enum = parseEnumMembers(columnType)
+	}
+	return fl
+}
+
+// parseEnumMembers parses the members of a column type like enum('a','b'). A
+// quote within a member gets escaped by doubling it.
+func parseEnumMembers(columnType string) []string {
+	open := strings.IndexByte(columnType, '(')
+	end := strings.LastIndexByte(columnType, ')')
+	if open < 0 || end < open {
+		return nil
+	}
+	var members []string
+	var buf strings.Builder
+	inQuote := false
+	def := columnType[open+1 : end]
+	for i := 0; i < len(def); i++ {
+		c := def[i]
+		switch {
+		case c == '\'' && inQuote && i+1 < len(def) && def[i+1] == '\'':
+			buf.WriteByte('\'')
+			i++
+		case c == '\'' && inQuote:
+			members = append(members, buf.String())
+			buf.Reset()
+			inQuote = false
+		case c == '\'':
+			inQuote = true
+		case inQuote:
+			buf.WriteByte(c)
+		}
+	}
+	return members
+}
+
+// WithFieldLimits applies the column constraints to the generated data of a
+// struct field. The map key can be the type name and the field name, e.g.
+// "store.Customer.Email", or the column name which is the snake case field
+// name, e.g. "email". Strings get truncated to MaxLen characters, integers and
+// DECIMAL values wrap into the range of the column and ENUM columns receive a
+// random member. The limits apply also to the value of nullable types like
+// null.String and to the Precision and Scale fields of null.Decimal.
+func WithFieldLimits(limits map[string]FieldLimit) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.fieldLimits == nil {
+				s.fieldLimits = make(map[string]FieldLimit, len(limits))
+			}
+			for k, fl := range limits {
+				s.fieldLimits[k] = fl
+			}
+			return nil
+		},
+	}
+}
+
+func (s *Service) fieldLimit(typeName, fieldName string) (FieldLimit, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.fieldLimits) == 0 {
+		return FieldLimit{}, false
+	}
+	if fl, ok := s.fieldLimits[typeName+"."+fieldName]; ok {
+		return fl, true
+	}
+	fl, ok := s.fieldLimits[toSnakeCase(fieldName)]
+	return fl, ok
+}
+
+// applyFieldLimit changes the generated value v to fit into the column.
+func (s *Service) applyFieldLimit(v reflect.Value, fl FieldLimit) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			s.applyFieldLimit(v.Elem(), fl)
+		}
+	case reflect.String:
+		if len(fl.Enum) > 0 {
+			v.SetString(fl.Enum[s.r.Intn(len(fl.Enum))])
+		} else if fl.MaxLen > 0 && utf8.RuneCountInString(v.String()) > fl.MaxLen {
+			v.SetString(truncateRunes(v.String(), fl.MaxLen))
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 && fl.MaxLen > 0 && v.Len() > fl.MaxLen {
+			v.SetLen(fl.MaxLen)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(fl.wrapInt(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(fl.wrapUint(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(fl.wrapFloat(v.Float()))
+	case reflect.Struct:
+		s.applyFieldLimitStruct(v, fl)
+	}
+}
+
+// applyFieldLimitStruct handles the null types. A struct with the fields
+// Precision and Scale gets treated as a decimal, otherwise the limit applies
+// to the first exported field which is not named Valid.
+func (s *Service) applyFieldLimitStruct(v reflect.Value, fl FieldLimit) {
+	if prec, scale := v.FieldByName("Precision"), v.FieldByName("Scale"); fl.Precision > 0 &&
+		prec.IsValid() && prec.Kind() == reflect.Uint64 && prec.CanSet() &&
+		scale.IsValid() && scale.Kind() == reflect.Int32 && scale.CanSet() {
+
+		if p10 := math.Pow10(fl.Precision); p10 < math.MaxUint64 {
+			prec.SetUint(prec.Uint() % uint64(p10))
+		}
+		scale.SetInt(int64(fl.Scale))
+		if ps := v.FieldByName("PrecisionStr"); ps.IsValid() && ps.Kind() == reflect.String && ps.CanSet() {
+			ps.SetString("")
+		}
+		if neg := v.FieldByName("Negative"); fl.Unsigned && neg.IsValid() && neg.Kind() == reflect.Bool && neg.CanSet() {
+			neg.SetBool(false)
+		}
+		return
+	}
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.CanSet() && t.Field(i).Name != "Valid" {
+			s.applyFieldLimit(f, fl)
+			return
+		}
+	}
+}
+
+func truncateRunes(str string, n int) string {
+	for i := range str {
+		if n == 0 {
+			return str[:i]
+		}
+		n--
+	}
+	return str
+}
+
+func (fl FieldLimit) wrapInt(i int64) int64 {
+	if fl.Unsigned && i < 0 {
+		i = -(i + 1) // avoids the overflow of math.MinInt64
+	}
+	if fl.IntBits <= 0 || fl.IntBits >= 64 {
+		return i
+	}
+	max := int64(1)<<uint(fl.IntBits-1) - 1
+	if fl.Unsigned {
+		max = int64(1)<<uint(fl.IntBits) - 1
+	}
+	if i > max || i < -max-1 {
+		i %= max + 1
+	}
+	return i
+}
+
+func (fl FieldLimit) wrapUint(u uint64) uint64 {
+	if fl.IntBits <= 0 || fl.IntBits >= 64 {
+		return u
+	}
+	max := uint64(1)<<uint(fl.IntBits) - 1
+	if !fl.Unsigned {
+		max = uint64(1)<<uint(fl.IntBits-1) - 1
+	}
+	if u > max {
+		u %= max + 1
+	}
+	return u
+}
+
+func (fl FieldLimit) wrapFloat(f float64) float64 {
+	if fl.Unsigned {
+		f = math.Abs(f)
+	}
+	if fl.Precision <= 0 {
+		return f
+	}
+	limit := math.Pow10(fl.Precision - fl.Scale)
+	p10 := math.Pow10(fl.Scale)
+	f = math.Round(math.Mod(f, limit)*p10) / p10
+	if math.Abs(f) >= limit {
+		f = math.Copysign(limit-1/p10, f)
+	}
+	return f
+}
+
+// applyFieldLimits applies the limits to all fields of struct v after the fake
+// data has been generated.
+func (s *Service) applyFieldLimits(v reflect.Value, typeName string, seed uint64) {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		vf := v.Field(i)
+		tf := t.Field(i)
+		if !vf.CanSet() || tf.Tag.Get(tagName) == Skip {
+			continue
+		}
+		fl, ok := s.fieldLimit(typeName, tf.Name)
+		if !ok {
+			continue
+		}
+		if s.deterministic {
+			s.r.Seed(mixSeed(s.fieldSeed(seed, typeName, tf.Name), 1))
+		}
+		s.applyFieldLimit(vf, fl)
+	}
+}
@@ -0,0 +1,109 @@
+package pseudo
+
+import (
+	"math"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestNewFieldLimit(t *testing.T) {
+	tests := []struct {
+		dataType, columnType        string
+		charMaxLen, precision, scal int64
+		want                        FieldLimit
+	}{
+		{"varchar", "varchar(255)", 255, 0, 0, FieldLimit{MaxLen: 255}},
+		{"tinyint", "tinyint(3) unsigned", 0, 3, 0, FieldLimit{IntBits: 8, Unsigned: true}},
+		{"MEDIUMINT", "mediumint(8)", 0, 7, 0, FieldLimit{IntBits: 24}},
+		{"int", "int(10) unsigned", 0, 10, 0, FieldLimit{IntBits: 32, Unsigned: true}},
+		{"bigint", "bigint(20)", 0, 19, 0, FieldLimit{IntBits: 64}},
+		{"decimal", "decimal(12,4) unsigned", 0, 12, 4, FieldLimit{Unsigned: true, Precision: 12, Scale: 4}},
+		{"enum", "enum('pending','it''s','a,b')", 7, 0, 0, FieldLimit{MaxLen: 7, Enum: []string{"pending", "it's", "a,b"}}},
+	}
+	for _, test := range tests {
+		assert.Exactly(t, test.want, NewFieldLimit(test.dataType, test.columnType, test.charMaxLen, test.precision, test.scal), "%s", test.columnType)
+	}
+}
+
+type limitNullString struct {
+	String string
+	Valid  bool
+}
+
+type limitDecimal struct {
+	PrecisionStr string
+	Precision    uint64
+	Scale        int32
+	Negative     bool
+}
+
+type limitEntity struct {
+	Email  string `faker:"email"`
+	Code   string
+	Umlaut string `faker:"umlaut"`
+	Status string
+	Tiny   int64
+	Small  int16
+	UTiny  uint32
+	Price  float64
+	Name   limitNullString
+	Amount limitDecimal
+}
+
+func TestWithFieldLimits(t *testing.T) {
+	s := MustNewService(0, nil,
+		WithTagFakeFunc("umlaut", func(maxLen int) interface{} { return "äöüäöü" }),
+		WithFieldLimits(map[string]FieldLimit{
+			"email":                   {MaxLen: 8},
+			"pseudo.limitEntity.Code": {MaxLen: 3},
+			"code":                    {MaxLen: 300}, // type name has precedence
+			"umlaut":                  {MaxLen: 4},
+			"status":                  NewFieldLimit("enum", "enum('new','paid')", 4, 0, 0),
+			"tiny":                    {IntBits: 8, Unsigned: true},
+			"small":                   {IntBits: 8},
+			"u_tiny":                  {IntBits: 8},
+			"price":                   {Precision: 5, Scale: 2, Unsigned: true},
+			"name":                    {MaxLen: 2},
+			"amount":                  {Precision: 4, Scale: 2, Unsigned: true},
+		}))
+
+	statuses := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		var e limitEntity
+		assert.NoError(t, s.FakeData(&e))
+
+		assert.True(t, len(e.Email) <= 8, "Email %q", e.Email)
+		assert.Len(t, e.Code, 3)
+		assert.Exactly(t, "äöüä", e.Umlaut)
+		assert.Exactly(t, 4, utf8.RuneCountInString(e.Umlaut))
+		assert.True(t, e.Status == "new" || e.Status == "paid", "Status %q", e.Status)
+		statuses[e.Status] = true
+		assert.True(t, e.Tiny >= 0 && e.Tiny <= 255, "Tiny %d", e.Tiny)
+		assert.True(t, e.Small >= -128 && e.Small <= 127, "Small %d", e.Small)
+		assert.True(t, e.UTiny <= 127, "UTiny %d", e.UTiny)
+		assert.True(t, e.Price >= 0 && e.Price <= 999.99, "Price %f", e.Price)
+		assert.Exactly(t, e.Price, math.Round(e.Price*100)/100)
+		if e.Name.Valid {
+			assert.True(t, len(e.Name.String) <= 2, "Name %q", e.Name.String)
+		}
+		assert.True(t, e.Amount.Precision <= 9999, "Amount %d", e.Amount.Precision)
+		assert.Exactly(t, int32(2), e.Amount.Scale)
+		assert.False(t, e.Amount.Negative)
+		assert.Empty(t, e.Amount.PrecisionStr)
+	}
+	assert.Len(t, statuses, 2)
+}
+
+func TestFieldLimit_wrap(t *testing.T) {
+	assert.Exactly(t, int64(math.MaxInt64), FieldLimit{Unsigned: true}.wrapInt(math.MinInt64))
+	assert.Exactly(t, int64(-128), FieldLimit{IntBits: 8}.wrapInt(-128))
+	assert.Exactly(t, int64(0), FieldLimit{IntBits: 8}.wrapInt(128))
+	assert.Exactly(t, int64(-1), FieldLimit{IntBits: 8}.wrapInt(-129))
+	assert.Exactly(t, int64(16777215), FieldLimit{IntBits: 24, Unsigned: true}.wrapInt(16777215))
+	assert.Exactly(t, uint64(0), FieldLimit{IntBits: 16, Unsigned: true}.wrapUint(65536))
+	assert.Exactly(t, 99.99, FieldLimit{Precision: 4, Scale: 2}.wrapFloat(99.999))
+	assert.Exactly(t, -99.99, FieldLimit{Precision: 4, Scale: 2}.wrapFloat(-199.999))
+	assert.Exactly(t, 12.35, FieldLimit{Precision: 4, Scale: 2, Unsigned: true}.wrapFloat(-112.345))
+}
@@ -0,0 +1,150 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pseudo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/pseudo"
+)
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+type OrderItem struct {
+	SKU     null.String `max_len:"64"`
+	Qty     null.Int64
+	Gift    null.Bool
+	Options map[string]string `faker:"len=2"`
+}
+
+type Order struct {
+	Items    []OrderItem `faker:"len=3..7"`
+	Tags     []string
+	Meta     map[string]string
+	Sizes    [3]int
+	Billing  *OrderItem
+	Total    Money
+	Parent   *Order
+	Children []*Order
+}
+
+func TestNestedTypes(t *testing.T) {
+	t.Run("slices, maps and pointers", func(t *testing.T) {
+		ps := pseudo.MustNewService(0, nil, pseudo.WithTagTypes(Order{}))
+		for i := 0; i < 100; i++ {
+			var o Order
+			assert.NoError(t, ps.FakeData(&o))
+			assert.LenBetween(t, o.Items, 3, 7)
+			for _, item := range o.Items {
+				assert.Len(t, item.Options, 2)
+				if !item.SKU.Valid {
+					assert.Empty(t, item.SKU.Data)
+				}
+				if !item.Qty.Valid {
+					assert.Empty(t, item.Qty.Int64)
+				}
+			}
+			assert.LenBetween(t, o.Tags, pseudo.DefaultMinCollectionLen, pseudo.DefaultMaxCollectionLen)
+			assert.LenBetween(t, o.Meta, pseudo.DefaultMinCollectionLen, pseudo.DefaultMaxCollectionLen)
+			assert.NotNil(t, o.Billing)
+			assert.LenBetween(t, o.Billing.Options, 1, 5)
+		}
+	})
+
+	t.Run("self referencing type", func(t *testing.T) {
+		ps := pseudo.MustNewService(0, nil)
+		var o Order
+		assert.NoError(t, ps.FakeData(&o))
+		assert.NotNil(t, o.Parent)
+		assert.Nil(t, o.Parent.Parent)
+		assert.Nil(t, o.Parent.Children)
+		assert.NotEmpty(t, o.Children)
+
+		ps = pseudo.MustNewService(0, &pseudo.Options{MaxTypeRecursion: 1})
+		o = Order{}
+		assert.NoError(t, ps.FakeData(&o))
+		assert.Nil(t, o.Parent)
+		assert.Nil(t, o.Children)
+		assert.NotEmpty(t, o.Items)
+	})
+
+	t.Run("collection length option", func(t *testing.T) {
+		ps := pseudo.MustNewService(0, &pseudo.Options{MinCollectionLen: 10, MaxCollectionLen: 10})
+		var o Order
+		assert.NoError(t, ps.FakeData(&o))
+		assert.Len(t, o.Tags, 10)
+		assert.Len(t, o.Meta, 10)
+		assert.LenBetween(t, o.Items, 3, 7)
+
+		_, err := pseudo.NewService(0, &pseudo.Options{MinCollectionLen: 5, MaxCollectionLen: 2})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("WithTypeFakeFunc", func(t *testing.T) {
+		ps := pseudo.MustNewService(0, nil,
+			pseudo.WithTypeFakeFunc(reflect.TypeOf(Money{}), func(maxLen int) interface{} {
+				return Money{Amount: 4711, Currency: "EUR"}
+			}),
+			pseudo.WithTypeFakeFunc(reflect.TypeOf([]string{}), func(maxLen int) interface{} {
+				return []string{"a", "b"}
+			}),
+		)
+		var o Order
+		assert.NoError(t, ps.FakeData(&o))
+		assert.Exactly(t, Money{Amount: 4711, Currency: "EUR"}, o.Total)
+		assert.Exactly(t, []string{"a", "b"}, o.Tags)
+
+		ps = pseudo.MustNewService(0, nil, pseudo.WithTypeFakeFunc(reflect.TypeOf(Money{}), func(maxLen int) interface{} {
+			return "4711 EUR"
+		}))
+		assert.ErrorIsKind(t, errors.NotValid, ps.FakeData(&o))
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		fake := func() []Order {
+			ps := pseudo.MustNewService(4711, nil, pseudo.WithDeterministicPerField())
+			orders := make([]Order, 20)
+			for i := range orders {
+				assert.NoError(t, ps.FakeData(&orders[i]))
+			}
+			return orders
+		}
+		assert.Exactly(t, fake(), fake())
+	})
+
+	t.Run("invalid len tags", func(t *testing.T) {
+		type invalidRange struct {
+			Items []OrderItem `faker:"len=7..3"`
+		}
+		type invalidType struct {
+			Items string `faker:"len=3"`
+		}
+		_, err := pseudo.NewService(0, nil, pseudo.WithTagTypes(invalidRange{}))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.Contains(t, err.Error(), "Items")
+		_, err = pseudo.NewService(0, nil, pseudo.WithTagTypes(&invalidType{}))
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+
+		ps := pseudo.MustNewService(0, nil)
+		assert.ErrorIsKind(t, errors.NotValid, ps.FakeData(&invalidRange{}))
+	})
+}
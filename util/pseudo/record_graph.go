@@ -0,0 +1,517 @@
+package pseudo
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/errors"
+)
+
+// ForeignKeyIterator provides the foreign keys of a database schema, for
+// example ddl.KeyColumnUsageCollection.
+type ForeignKeyIterator interface {
+	EachForeignKey(fn func(table, column, refTable, refColumn string))
+}
+
+// RecordGraph tracks the primary keys of generated parent records and the
+// foreign keys of the child tables. A Service created with WithRecordGraph
+// sets a foreign key field of a child struct to a registered value of the
+// referenced column, hence a database with enabled foreign key checks accepts
+// the generated records. The table name of a struct type is the snake case
+// type name, e.g. CustomerEntity => customer_entity, or the table set with
+// MapType. The column name is the snake case field name. A RecordGraph is safe
+// for concurrent use.
+type RecordGraph struct {
+	mu    sync.RWMutex
+	types map[string]string          // Go type name => table name
+	refs  map[string]string          // table.column => referenced table.column
+	pools map[string]*recordPool     // table.column => registered values
+	deps  map[string]map[string]bool // referenced table => child tables
+}
+
+// recordPool contains the values of a referenced column. cumWeights stays nil
+// as long as all values have the same weight.
+type recordPool struct {
+	values     []interface{}
+	cumWeights []uint64
+}
+
+func (p *recordPool) add(v interface{}, weight uint64) {
+	if weight != 1 && p.cumWeights == nil {
+		p.cumWeights = make([]uint64, len(p.values), len(p.values)+1)
+		for i := range p.cumWeights {
+			p.cumWeights[i] = uint64(i + 1)
+		}
+	}
+	p.values = append(p.values, v)
+	if p.cumWeights != nil {
+		var total uint64
+		if l := len(p.cumWeights); l > 0 {
+			total = p.cumWeights[l-1]
+		}
+		p.cumWeights = append(p.cumWeights, total+weight)
+	}
+}
+
+// empty reports whether no value can be picked.
+func (p *recordPool) empty() bool {
+	return len(p.values) == 0 || (p.cumWeights != nil && p.cumWeights[len(p.cumWeights)-1] == 0)
+}
+
+func (p *recordPool) pick(s *Service) interface{} {
+	if p.cumWeights == nil {
+		return p.values[s.r.Intn(len(p.values))]
+	}
+	total := p.cumWeights[len(p.cumWeights)-1]
+	n := s.r.Uint64n(total)
+	return p.values[sort.Search(len(p.cumWeights), func(i int) bool { return p.cumWeights[i] > n })]
+}
+
+// NewRecordGraph creates a new empty RecordGraph.
+func NewRecordGraph() *RecordGraph {
+	return &RecordGraph{
+		types: make(map[string]string),
+		refs:  make(map[string]string),
+		pools: make(map[string]*recordPool),
+		deps:  make(map[string]map[string]bool),
+	}
+}
+
+// MapType sets the table name of a struct type. The typeName contains the
+// package name, e.g. "store.Customer", like the keys of WithTagFakeFunc.
+func (g *RecordGraph) MapType(typeName, table string) *RecordGraph {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.types[typeName] = table
+	return g
+}
+
+// AddForeignKey declares that the column of table references refColumn of
+// refTable.
+func (g *RecordGraph) AddForeignKey(table, column, refTable, refColumn string) *RecordGraph {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.refs[table+"."+column] = refTable + "." + refColumn
+	if g.deps[refTable] == nil {
+		g.deps[refTable] = make(map[string]bool)
+	}
+	g.deps[refTable][table] = true
+	return g
+}
+
+// AddForeignKeys adds all foreign keys provided by the iterators, for example
+// the result of ddl.LoadKeyColumnUsage.
+func (g *RecordGraph) AddForeignKeys(fkis ...ForeignKeyIterator) *RecordGraph {
+	for _, fki := range fkis {
+		fki.EachForeignKey(func(table, column, refTable, refColumn string) {
+			g.AddForeignKey(table, column, refTable, refColumn)
+		})
+	}
+	return g
+}
+
+// Register adds the values of a referenced column, usually the primary keys
+// of the generated parent records. All values have the same probability to
+// be picked.
+func (g *RecordGraph) Register(table, column string, values ...interface{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p := g.pool(table + "." + column)
+	for _, v := range values {
+		p.add(v, 1)
+	}
+}
+
+// RegisterInt64s same as Register but for auto increment values.
+func (g *RecordGraph) RegisterInt64s(table, column string, values ...int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p := g.pool(table + "." + column)
+	for _, v := range values {
+		p.add(v, 1)
+	}
+}
+
+// RegisterWeighted adds a value of a referenced column with a weight. A value
+// with weight 4 gets picked four times more often than a value with weight 1,
+// e.g. to assign most orders to a few customers. A weight of zero never picks
+// the value.
+func (g *RecordGraph) RegisterWeighted(table, column string, value interface{}, weight uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pool(table+"."+column).add(value, weight)
+}
+
+// Unregister removes all values of a referenced column.
+func (g *RecordGraph) Unregister(table, column string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.pools, table+"."+column)
+}
+
+// RegisterRecords adds the values of all referenced columns of table from the
+// records. Argument records must be a slice of structs or pointers to structs,
+// or a pointer to a collection struct whose field Data contains the slice. A
+// nullable field which is not valid gets skipped.
+func (g *RecordGraph) RegisterRecords(table string, records interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(records))
+	if rv.Kind() == reflect.Struct {
+		rv = rv.FieldByName("Data")
+	}
+	if k := rv.Kind(); k != reflect.Slice && k != reflect.Array {
+		return errors.NotSupported.Newf("[pseudo] RecordGraph.RegisterRecords: type %T of table %q is not supported", records, table)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	columns := make(map[string]bool)
+	for _, ref := range g.refs {
+		if i := strings.LastIndexByte(ref, '.'); ref[:i] == table {
+			columns[ref[i+1:]] = true
+		}
+	}
+	for i := 0; i < rv.Len(); i++ {
+		ev := reflect.Indirect(rv.Index(i))
+		if ev.Kind() != reflect.Struct {
+			return errors.NotSupported.Newf("[pseudo] RecordGraph.RegisterRecords: type %s of table %q is not supported", ev.Type(), table)
+		}
+		et := ev.Type()
+		for j := 0; j < ev.NumField(); j++ {
+			column := toSnakeCase(et.Field(j).Name)
+			if !columns[column] || !ev.Field(j).CanInterface() {
+				continue
+			}
+			if v, ok := recordValue(ev.Field(j)); ok {
+				g.pool(table+"."+column).add(v, 1)
+			}
+		}
+	}
+	return nil
+}
+
+// recordValue returns the value of a field and unwraps the null types.
+func recordValue(v reflect.Value) (interface{}, bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, false
+		}
+		return recordValue(v.Elem())
+	case reflect.Struct:
+		valid := v.FieldByName("Valid")
+		if !valid.IsValid() || valid.Kind() != reflect.Bool {
+			return v.Interface(), true
+		}
+		if !valid.Bool() {
+			return nil, false
+		}
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).Name != "Valid" && v.Field(i).CanInterface() {
+				return v.Field(i).Interface(), true
+			}
+		}
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+func (g *RecordGraph) pool(key string) *recordPool {
+	p, ok := g.pools[key]
+	if !ok {
+		p = new(recordPool)
+		g.pools[key] = p
+	}
+	return p
+}
+
+// Remove deletes the registered values of table and of all tables which
+// reference it directly or indirectly. It returns these tables in the order
+// to delete their records, children first. Generate them again in the
+// reverse order.
+func (g *RecordGraph) Remove(table string) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	affected := map[string]bool{table: true}
+	queue := []string{table}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for child := range g.deps[t] {
+			if !affected[child] {
+				affected[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	for key := range g.pools {
+		if affected[key[:strings.LastIndexByte(key, '.')]] {
+			delete(g.pools, key)
+		}
+	}
+	tables := g.insertOrder(affected)
+	reverseStrings(tables)
+	return tables
+}
+
+// InsertOrder returns all known tables sorted by their foreign keys, hence
+// referenced tables come first. Circular references keep the alphabetical
+// order.
+func (g *RecordGraph) InsertOrder() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.insertOrder(nil)
+}
+
+// DeleteOrder returns the reversed InsertOrder, hence child tables come
+// first.
+func (g *RecordGraph) DeleteOrder() []string {
+	tables := g.InsertOrder()
+	reverseStrings(tables)
+	return tables
+}
+
+// insertOrder sorts the tables topologically. If filter is not nil, only those
+// tables get returned.
+func (g *RecordGraph) insertOrder(filter map[string]bool) []string {
+	parents := make(map[string][]string)
+	known := make(map[string]bool)
+	for key, ref := range g.refs {
+		table := key[:strings.LastIndexByte(key, '.')]
+		refTable := ref[:strings.LastIndexByte(ref, '.')]
+		known[table] = true
+		known[refTable] = true
+		if table != refTable {
+			parents[table] = append(parents[table], refTable)
+		}
+	}
+	for key := range g.pools {
+		known[key[:strings.LastIndexByte(key, '.')]] = true
+	}
+	for t := range filter {
+		known[t] = true
+	}
+	names := make([]string, 0, len(known))
+	for t := range known {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+
+	sorted := make([]string, 0, len(names))
+	visited := make(map[string]bool, len(names))
+	var visit func(t string)
+	visit = func(t string) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		ps := parents[t]
+		sort.Strings(ps)
+		for _, p := range ps {
+			visit(p)
+		}
+		if filter == nil || filter[t] {
+			sorted = append(sorted, t)
+		}
+	}
+	for _, t := range names {
+		visit(t)
+	}
+	return sorted
+}
+
+func reverseStrings(s []string) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// foreignRef returns the referenced table.column of a foreign key field.
+func (g *RecordGraph) foreignRef(typeName, fieldName string) (ref string, declared bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	table, ok := g.types[typeName]
+	if !ok {
+		table = toSnakeCase(typeName[strings.LastIndexByte(typeName, '.')+1:])
+	}
+	ref, declared = g.refs[table+"."+toSnakeCase(fieldName)]
+	return ref, declared
+}
+
+// pick returns a random registered value of the referenced table.column.
+func (g *RecordGraph) pick(s *Service, ref string) (interface{}, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	p, ok := g.pools[ref]
+	if !ok || p.empty() {
+		return nil, false
+	}
+	return p.pick(s), true
+}
+
+// WithRecordGraph sets the foreign key fields of the generated structs to the
+// values registered in the RecordGraph. A nullable foreign key field becomes
+// NULL when the referenced table has no registered values, otherwise FakeData
+// returns a NotFound error. WithForeignPool has precedence.
+func WithRecordGraph(g *RecordGraph) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.recordGraph = g
+			return nil
+		},
+	}
+}
+
+// WithForeignPool sets the value of a foreign key field to the value returned
+// by pool, e.g. a random ID of the already generated parent records. The
+// field can be the type name and the field name, e.g. "store.Customer.StoreID",
+// or the column name which is the snake case field name, e.g. "store_id". The
+// column name also matches the primary key field of the parent struct. The
+// returned value gets converted to the type of the field and a nil value sets
+// the field to NULL. In deterministic mode pool must use the Service, e.g.
+// Intn, to generate reproducible values.
+func WithForeignPool(field string, pool func() interface{}) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.foreignPools == nil {
+				s.foreignPools = make(map[string]func() interface{})
+			}
+			s.foreignPools[field] = pool
+			return nil
+		},
+	}
+}
+
+// RecordGraph returns the RecordGraph set with WithRecordGraph or nil.
+func (s *Service) RecordGraph() *RecordGraph {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.recordGraph
+}
+
+func (s *Service) foreignPool(typeName, fieldName string) func() interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.foreignPools) == 0 {
+		return nil
+	}
+	if fn, ok := s.foreignPools[typeName+"."+fieldName]; ok {
+		return fn
+	}
+	return s.foreignPools[toSnakeCase(fieldName)]
+}
+
+// applyForeignKeys sets the foreign key fields of struct v after the fake data
+// has been generated.
+func (s *Service) applyForeignKeys(v reflect.Value, typeName string, seed uint64) error {
+	rg := s.RecordGraph()
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		vf := v.Field(i)
+		tf := t.Field(i)
+		if !vf.CanSet() || tf.Tag.Get(tagName) == Skip {
+			continue
+		}
+		fn := s.foreignPool(typeName, tf.Name)
+		var ref string
+		if fn == nil {
+			var declared bool
+			if rg != nil {
+				ref, declared = rg.foreignRef(typeName, tf.Name)
+			}
+			if !declared {
+				continue
+			}
+		}
+
+		if s.deterministic {
+			s.r.Seed(mixSeed(s.fieldSeed(seed, typeName, tf.Name), 2))
+		}
+		var val interface{}
+		if fn != nil {
+			val = fn()
+		} else if v, ok := rg.pick(s, ref); ok {
+			val = v
+		} else if !isNullable(vf) {
+			return errors.NotFound.Newf("[pseudo] No values of %q registered for the foreign key field %s.%s", ref, typeName, tf.Name)
+		}
+		if err := setForeignValue(vf, val); err != nil {
+			return errors.Wrapf(err, "[pseudo] Failed to set foreign key field %s.%s", typeName, tf.Name)
+		}
+	}
+	return nil
+}
+
+// isNullable reports whether v is a pointer or a struct with a Valid field.
+func isNullable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr:
+		return true
+	case reflect.Struct:
+		valid := v.FieldByName("Valid")
+		return valid.IsValid() && valid.Kind() == reflect.Bool
+	}
+	return false
+}
+
+// isNumberKind reports whether k is an integer or a float.
+func isNumberKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+// setForeignValue converts val to the type of v. A nil val resets v.
+func setForeignValue(v reflect.Value, val interface{}) error {
+	if val == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	rv := reflect.ValueOf(val)
+	if rv.Type().AssignableTo(v.Type()) {
+		v.Set(rv)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		pv := reflect.New(v.Type().Elem())
+		if err := setForeignValue(pv.Elem(), val); err != nil {
+			return err
+		}
+		v.Set(pv)
+		return nil
+	case reflect.String:
+		if rv.Kind() == reflect.String {
+			v.SetString(rv.String())
+			return nil
+		}
+	case reflect.Struct:
+		if !isNullable(v) {
+			break
+		}
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() && t.Field(i).Name != "Valid" {
+				if err := setForeignValue(f, val); err != nil {
+					return err
+				}
+				v.FieldByName("Valid").SetBool(true)
+				return nil
+			}
+		}
+	default:
+		if isNumberKind(v.Kind()) && isNumberKind(rv.Kind()) {
+			v.Set(rv.Convert(v.Type()))
+			return nil
+		}
+	}
+	return errors.NotSupported.Newf("[pseudo] Cannot convert %T to %s", val, v.Type())
+}
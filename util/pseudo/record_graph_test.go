@@ -0,0 +1,136 @@
+package pseudo
+
+import (
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type rgNullUint32 struct {
+	Uint32 uint32
+	Valid  bool
+}
+
+type rgWebsite struct {
+	WebsiteID uint16
+	Name      string
+}
+
+type rgStore struct {
+	StoreID   uint32
+	WebsiteID uint16
+	GroupID   rgNullUint32
+	Code      string
+}
+
+type rgForeignKeys [][4]string
+
+func (fks rgForeignKeys) EachForeignKey(fn func(table, column, refTable, refColumn string)) {
+	for _, fk := range fks {
+		fn(fk[0], fk[1], fk[2], fk[3])
+	}
+}
+
+func newTestRecordGraph() *RecordGraph {
+	return NewRecordGraph().
+		MapType("pseudo.rgStore", "store").
+		AddForeignKeys(rgForeignKeys{
+			{"store", "website_id", "rg_website", "website_id"},
+			{"store", "group_id", "store_group", "group_id"},
+			{"store_group", "website_id", "rg_website", "website_id"},
+		})
+}
+
+func TestRecordGraph(t *testing.T) {
+	t.Run("child references generated parents", func(t *testing.T) {
+		rg := newTestRecordGraph()
+		s := MustNewService(0, nil, WithRecordGraph(rg))
+
+		websites := make([]*rgWebsite, 5)
+		for i := range websites {
+			websites[i] = new(rgWebsite)
+			assert.NoError(t, s.FakeData(websites[i]))
+			websites[i].WebsiteID = uint16(i + 1)
+		}
+		assert.NoError(t, rg.RegisterRecords("rg_website", websites))
+
+		for i := 0; i < 50; i++ {
+			var st rgStore
+			assert.NoError(t, s.FakeData(&st))
+			assert.True(t, st.WebsiteID >= 1 && st.WebsiteID <= 5, "WebsiteID %d", st.WebsiteID)
+			assert.Exactly(t, rgNullUint32{}, st.GroupID, "nullable foreign key without parents must be NULL")
+		}
+
+		rg.RegisterInt64s("store_group", "group_id", 6)
+		rg.Unregister("store_group", "group_id")
+		rg.RegisterInt64s("store_group", "group_id", 7)
+		var st rgStore
+		assert.NoError(t, s.FakeData(&st))
+		assert.Exactly(t, rgNullUint32{Uint32: 7, Valid: true}, st.GroupID)
+	})
+
+	t.Run("missing parents of not null column", func(t *testing.T) {
+		s := MustNewService(0, nil, WithRecordGraph(newTestRecordGraph()))
+		var st rgStore
+		assert.ErrorIsKind(t, errors.NotFound, s.FakeData(&st))
+	})
+
+	t.Run("weighted distribution", func(t *testing.T) {
+		rg := newTestRecordGraph()
+		rg.RegisterWeighted("rg_website", "website_id", 1, 0)
+		rg.RegisterWeighted("rg_website", "website_id", 2, 3)
+		s := MustNewService(0, nil, WithRecordGraph(rg))
+		for i := 0; i < 20; i++ {
+			var st rgStore
+			assert.NoError(t, s.FakeData(&st))
+			assert.Exactly(t, uint16(2), st.WebsiteID)
+		}
+	})
+
+	t.Run("WithForeignPool has precedence", func(t *testing.T) {
+		rg := newTestRecordGraph()
+		rg.Register("rg_website", "website_id", 1)
+		s := MustNewService(0, nil, WithRecordGraph(rg),
+			WithForeignPool("pseudo.rgStore.WebsiteID", func() interface{} { return int64(3) }),
+			WithForeignPool("group_id", func() interface{} { return 4 }),
+		)
+		var st rgStore
+		assert.NoError(t, s.FakeData(&st))
+		assert.Exactly(t, uint16(3), st.WebsiteID)
+		assert.Exactly(t, rgNullUint32{Uint32: 4, Valid: true}, st.GroupID)
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		rg := newTestRecordGraph()
+		rg.RegisterInt64s("rg_website", "website_id", 1, 2, 3, 4, 5, 6, 7, 8)
+		fake := func() []rgStore {
+			s := MustNewService(4711, nil, WithDeterministicPerField(), WithRecordGraph(rg))
+			sts := make([]rgStore, 10)
+			for i := range sts {
+				assert.NoError(t, s.FakeData(&sts[i]))
+			}
+			return sts
+		}
+		assert.Exactly(t, fake(), fake())
+	})
+
+	t.Run("order", func(t *testing.T) {
+		rg := newTestRecordGraph()
+		assert.Exactly(t, []string{"rg_website", "store_group", "store"}, rg.InsertOrder())
+		assert.Exactly(t, []string{"store", "store_group", "rg_website"}, rg.DeleteOrder())
+
+		rg.Register("rg_website", "website_id", 1)
+		rg.Register("store_group", "group_id", 1)
+		assert.Exactly(t, []string{"store", "store_group"}, rg.Remove("store_group"))
+		s := MustNewService(0, nil, WithRecordGraph(rg))
+		var st rgStore
+		assert.NoError(t, s.FakeData(&st))
+		assert.Exactly(t, uint16(1), st.WebsiteID)
+		assert.False(t, st.GroupID.Valid)
+	})
+
+	t.Run("RegisterRecords unsupported type", func(t *testing.T) {
+		assert.ErrorIsKind(t, errors.NotSupported, NewRecordGraph().RegisterRecords("rg_website", 42))
+	})
+}
@@ -83,9 +83,10 @@ func TestRecursion(t *testing.T) {
 		err = ps.FakeData(st)
 		assert.NoError(t, err)
 
-		// just check that it works an no stack overflow happens.
+		// just check that it works an no stack overflow happens. The field
+		// Store contains between MinCollectionLen and MaxCollectionLen stores.
 		data, err := json.Marshal(st)
 		assert.NoError(t, err)
-		assert.LenBetween(t, data, 170, 220)
+		assert.LenBetween(t, data, 1000, 4000)
 	})
 }
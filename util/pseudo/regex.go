@@ -0,0 +1,330 @@
+package pseudo
+
+import (
+	"reflect"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/corestoreio/errors"
+)
+
+const (
+	// tagRegexPrefix introduces a regular expression in the struct tag, e.g.
+	// `faker:"regex=^[A-Z]{3}-\\d{5}$"`.
+	tagRegexPrefix = "regex="
+	// DefaultRegexMaxRepeat default see Options.RegexMaxRepeat.
+	DefaultRegexMaxRepeat = 10
+	// regexMaxLength rejects patterns which can generate longer strings.
+	regexMaxLength = 4096
+	// regexMaxLenAttempts defines how often a too long string gets generated
+	// again before it gets truncated to maxLen.
+	regexMaxLenAttempts = 10
+)
+
+// regexGenerator expands a parsed regular expression to a random matching
+// string.
+type regexGenerator struct {
+	re *syntax.Regexp
+}
+
+// printableASCII contains the runes used for the dot and preferred for
+// character classes.
+var printableASCII = []rune{' ', '~'}
+
+// newRegexGenerator parses the pattern and rejects unsupported and
+// pathological patterns.
+func newRegexGenerator(pattern string, maxRepeat int) (*regexGenerator, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[pseudo] Invalid regex %q", pattern)
+	}
+	re = re.Simplify()
+	n, err := regexMaxRunes(re, maxRepeat)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[pseudo] Regex %q", pattern)
+	}
+	if n > regexMaxLength {
+		return nil, errors.TooLarge.Newf("[pseudo] Regex %q can generate %d characters, allowed are %d", pattern, n, regexMaxLength)
+	}
+	return &regexGenerator{re: re}, nil
+}
+
+// regexMaxRunes calculates the maximum length of a generated string.
+func regexMaxRunes(re *syntax.Regexp, maxRepeat int) (int, error) {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return 0, errors.NotValid.Newf("[pseudo] Regex %q does not match anything", re)
+	case syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return 0, errors.NotSupported.Newf("[pseudo] Word boundaries are not supported")
+	case syntax.OpLiteral:
+		return len(re.Rune), nil
+	case syntax.OpCharClass:
+		if len(classRanges(re.Rune)) == 0 {
+			return 0, errors.NotValid.Newf("[pseudo] Character class %q contains only surrogates", re)
+		}
+		return 1, nil
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		return 1, nil
+	case syntax.OpCapture:
+		return regexMaxRunes(re.Sub[0], maxRepeat)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		n, err := regexMaxRunes(re.Sub[0], maxRepeat)
+		if err != nil || n > regexMaxLength {
+			return n, err
+		}
+		_, max := regexRepeatRange(re, maxRepeat)
+		return n * max, nil
+	case syntax.OpConcat:
+		sum := 0
+		for _, sub := range re.Sub {
+			n, err := regexMaxRunes(sub, maxRepeat)
+			if err != nil {
+				return 0, err
+			}
+			if sum += n; sum > regexMaxLength {
+				return sum, nil
+			}
+		}
+		return sum, nil
+	case syntax.OpAlternate:
+		max := 0
+		for _, sub := range re.Sub {
+			n, err := regexMaxRunes(sub, maxRepeat)
+			if err != nil {
+				return 0, err
+			}
+			if n > max {
+				max = n
+			}
+		}
+		return max, nil
+	}
+	return 0, nil // anchors and empty matches
+}
+
+// regexRepeatRange returns the minimum and maximum number of repetitions. An
+// unbounded repetition gets limited to maxRepeat additional repetitions.
+func regexRepeatRange(re *syntax.Regexp, maxRepeat int) (min, max int) {
+	switch re.Op {
+	case syntax.OpStar:
+		return 0, maxRepeat
+	case syntax.OpPlus:
+		return 1, 1 + maxRepeat
+	case syntax.OpQuest:
+		return 0, 1
+	}
+	if re.Max < 0 {
+		return re.Min, re.Min + maxRepeat
+	}
+	return re.Min, re.Max
+}
+
+func (s *Service) writeRegex(buf *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && s.r.Intn(2) == 0 {
+				if unicode.IsUpper(r) {
+					r = unicode.ToLower(r)
+				} else {
+					r = unicode.ToUpper(r)
+				}
+			}
+			buf.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		buf.WriteRune(s.randomClassRune(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		buf.WriteRune(s.randomClassRune(printableASCII))
+	case syntax.OpCapture:
+		s.writeRegex(buf, re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := regexRepeatRange(re, s.o.RegexMaxRepeat)
+		n := min
+		if max > min {
+			n += s.r.Intn(max - min + 1)
+		}
+		for i := 0; i < n; i++ {
+			s.writeRegex(buf, re.Sub[0])
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			s.writeRegex(buf, sub)
+		}
+	case syntax.OpAlternate:
+		s.writeRegex(buf, re.Sub[s.r.Intn(len(re.Sub))])
+	}
+}
+
+// classRanges returns the printable ASCII ranges of a character class or, if
+// there are none, the ranges without surrogates.
+func classRanges(ranges []rune) []rune {
+	var ascii, valid []rune
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if l, h := maxRune(lo, printableASCII[0]), minRune(hi, printableASCII[1]); l <= h {
+			ascii = append(ascii, l, h)
+		}
+		if l, h := lo, minRune(hi, 0xd7ff); l <= h {
+			valid = append(valid, l, h)
+		}
+		if l, h := maxRune(lo, 0xe000), hi; l <= h {
+			valid = append(valid, l, h)
+		}
+	}
+	if len(ascii) > 0 {
+		return ascii
+	}
+	return valid
+}
+
+func minRune(a, b rune) rune {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxRune(a, b rune) rune {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// randomClassRune picks a rune from the ranges of a character class.
+func (s *Service) randomClassRune(ranges []rune) rune {
+	ranges = classRanges(ranges)
+	var total int
+	for i := 0; i < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	n := rune(s.r.Intn(total))
+	for i := 0; i < len(ranges); i += 2 {
+		if size := ranges[i+1] - ranges[i] + 1; n >= size {
+			n -= size
+			continue
+		}
+		return ranges[i] + n
+	}
+	return ranges[0]
+}
+
+// regexString generates a string matching the regular expression. A string
+// longer than maxLen gets generated again and finally truncated, which might
+// violate the regular expression.
+func (s *Service) regexString(rg *regexGenerator, maxLen int) string {
+	var buf strings.Builder
+	for i := 0; i < regexMaxLenAttempts; i++ {
+		buf.Reset()
+		s.writeRegex(&buf, rg.re)
+		if maxLen <= 0 || utf8.RuneCountInString(buf.String()) <= maxLen {
+			return buf.String()
+		}
+	}
+	return truncateRunes(buf.String(), maxLen)
+}
+
+// regexGenerator returns the cached generator of a pattern.
+func (s *Service) regexGenerator(pattern string) (*regexGenerator, error) {
+	s.mu.RLock()
+	rg, ok := s.regexes[pattern]
+	s.mu.RUnlock()
+	if ok {
+		return rg, nil
+	}
+	rg, err := newRegexGenerator(pattern, s.o.RegexMaxRepeat)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	s.mu.Lock()
+	s.regexes[pattern] = rg
+	s.mu.Unlock()
+	return rg, nil
+}
+
+// WithTagRegex generates strings matching the regular expression for a tag,
+// a field name or a type name and a field name, like WithTagFakeFunc. The
+// struct tag `faker:"regex=pattern"` does the same. Supported are character
+// classes, quantifiers, alternations and groups. Unbounded quantifiers like *
+// or + repeat at most Options.RegexMaxRepeat times. An invalid pattern or a
+// pattern which can generate more than 4096 characters returns an error in
+// NewService. The max_len tag caps the generated string.
+func WithTagRegex(field, pattern string) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			rg, err := s.regexGenerator(pattern)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.funcs[field] = func(maxLen int) interface{} {
+				return s.regexString(rg, maxLen)
+			}
+			return nil
+		},
+	}
+}
+
+// WithTagTypes validates and caches the struct tags `faker:"regex=pattern"`,
+// `faker:"len=3..7"` and the time tags like `faker:"time_range=-2y..now"` of
+// the provided types in NewService. Without this option an invalid tag returns
+// an error in FakeData. Nested structs, pointers, slices and maps get
+// inspected.
+func WithTagTypes(types ...interface{}) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			visited := make(map[reflect.Type]bool)
+			for _, v := range types {
+				if err := s.checkTags(reflect.TypeOf(v), visited); err != nil {
+					return errors.WithStack(err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func (s *Service) checkTags(t reflect.Type, visited map[reflect.Type]bool) error {
+	if t == nil || visited[t] {
+		return nil
+	}
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return s.checkTags(t.Elem(), visited)
+	case reflect.Map:
+		if err := s.checkTags(t.Key(), visited); err != nil {
+			return err
+		}
+		return s.checkTags(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get(tagName)
+			switch {
+			case strings.HasPrefix(tag, tagRegexPrefix):
+				if _, err := s.regexGenerator(tag[len(tagRegexPrefix):]); err != nil {
+					return errors.Wrapf(err, "[pseudo] Field %s.%s", t, f.Name)
+				}
+			case isTimeTag(tag):
+				if err := s.checkTimeTag(t, f); err != nil {
+					return err
+				}
+			case strings.HasPrefix(tag, tagLenPrefix):
+				if err := checkLenTag(t, f); err != nil {
+					return err
+				}
+			}
+			if err := s.checkTags(f.Type, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package pseudo
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var regexTestPatterns = []string{
+	`^[A-Z]{3}-[0-9]{5}$`,
+	`^\d{2}-\d{3}$`,
+	`^(DE|AT|CH)\d{9}$`,
+	`^1Z[0-9A-Z]{16}$`,
+	`^[a-z]+(\.[a-z]+)*@example\.(com|org)$`,
+	`^\w{3,8}\s?[^a-z\s]{2}$`,
+	`^(?i:sku)-x?[[:xdigit:]]{4,}$`,
+	`^.{1,5}$`,
+	`^[äöü€]{3}$`,
+	`^(a|b(c|d)*){2}e?$`,
+	`^\p{Greek}{2}\PL$`,
+	``,
+}
+
+type regexEntity struct {
+	SKU      string `faker:"regex=^[A-Z]{3}-\\d{5}$"`
+	Tracking []byte `faker:"regex=^1Z[0-9A-Z]{16}$"`
+	Tax      string
+	// Short must be the last field because max_len applies to the following
+	// fields, too.
+	Short string `faker:"regex=^[a-z]{5,20}$" max_len:"8"`
+}
+
+func TestWithTagRegex(t *testing.T) {
+	t.Run("generated values match", func(t *testing.T) {
+		for _, pattern := range regexTestPatterns {
+			re := regexp.MustCompile(pattern)
+			for seed := uint64(1); seed <= 100; seed++ {
+				s := MustNewService(seed, nil, WithTagRegex("tax", pattern))
+				var e regexEntity
+				assert.NoError(t, s.FakeData(&e))
+				assert.True(t, re.MatchString(e.Tax), "pattern %q: %q", pattern, e.Tax)
+			}
+		}
+	})
+
+	t.Run("struct tags", func(t *testing.T) {
+		s := MustNewService(0, nil, WithTagTypes(regexEntity{}))
+		sku := regexp.MustCompile(`^[A-Z]{3}-\d{5}$`)
+		tracking := regexp.MustCompile(`^1Z[0-9A-Z]{16}$`)
+		short := regexp.MustCompile(`^[a-z]{5,8}$`)
+		for i := 0; i < 200; i++ {
+			var e regexEntity
+			assert.NoError(t, s.FakeData(&e))
+			assert.True(t, sku.MatchString(e.SKU), "SKU %q", e.SKU)
+			assert.True(t, tracking.Match(e.Tracking), "Tracking %q", e.Tracking)
+			assert.True(t, short.MatchString(e.Short), "Short %q", e.Short)
+		}
+	})
+
+	t.Run("unbounded repetition", func(t *testing.T) {
+		s := MustNewService(0, &Options{RegexMaxRepeat: 3}, WithTagRegex("tax", `^a*b+$`))
+		for i := 0; i < 100; i++ {
+			var e regexEntity
+			assert.NoError(t, s.FakeData(&e))
+			assert.True(t, utf8.RuneCountInString(e.Tax) <= 7, "Tax %q", e.Tax)
+		}
+	})
+
+	t.Run("invalid patterns", func(t *testing.T) {
+		tests := []struct {
+			pattern string
+			kind    errors.Kind
+		}{
+			{`[a-`, errors.NotValid},
+			{`(a)\1`, errors.NotValid},
+			{`\bfoo`, errors.NotSupported},
+			{`[^\x00-\x{10FFFF}]`, errors.NotValid},
+			{strings.Repeat(`[a-z]{1000}`, 5), errors.TooLarge},
+			{`(x+)+`, errors.NoKind},
+		}
+		for _, test := range tests {
+			_, err := NewService(0, nil, WithTagRegex("tax", test.pattern))
+			if test.kind == errors.NoKind {
+				assert.NoError(t, err, "%q", test.pattern)
+				continue
+			}
+			assert.ErrorIsKind(t, test.kind, err, "%q", test.pattern)
+		}
+	})
+
+	t.Run("invalid struct tag", func(t *testing.T) {
+		type invalid struct {
+			Nested []struct {
+				Code string `faker:"regex=[0-9"`
+			}
+		}
+		_, err := NewService(0, nil, WithTagTypes(&invalid{}))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+
+		s := MustNewService(0, nil)
+		var v struct {
+			Code string `faker:"regex=[0-9"`
+		}
+		assert.ErrorIsKind(t, errors.NotValid, s.FakeData(&v))
+	})
+}
@@ -0,0 +1,487 @@
+package pseudo
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// Keys of the time tags. Several keys get separated by a semicolon, e.g.
+// `faker:"time_range=-30d..now;after_field=CreatedAt"`.
+const (
+	tagTimeRange  = "time_range"
+	tagAfterField = "after_field"
+	tagMonotonic  = "monotonic"
+)
+
+// timePoint defines an absolute time or an offset to the current time.
+type timePoint struct {
+	abs                 time.Time
+	isAbs               bool
+	years, months, days int
+	dur                 time.Duration
+}
+
+func (p timePoint) resolve(now time.Time) time.Time {
+	if p.isAbs {
+		return p.abs
+	}
+	return now.AddDate(p.years, p.months, p.days).Add(p.dur)
+}
+
+// parseTimePoint parses "now", an offset like "-2y", "+1y6M", "-90d" or
+// "-12h30m" or an absolute date "2006-01-02" or RFC3339 time. The units are y
+// (years), M (months), w (weeks), d (days), h, m and s.
+func parseTimePoint(str string) (timePoint, error) {
+	var p timePoint
+	switch {
+	case str == "now":
+		return p, nil
+	case str == "":
+		return p, errors.NotValid.Newf("empty time")
+	case str[0] != '-' && str[0] != '+':
+		for _, layout := range [...]string{"2006-01-02", time.RFC3339} {
+			if t, err := time.Parse(layout, str); err == nil {
+				return timePoint{abs: t, isAbs: true}, nil
+			}
+		}
+		return p, errors.NotValid.Newf("invalid time %q, want now, an offset like -2y or a date like 2006-01-02", str)
+	}
+
+	sign := 1
+	if str[0] == '-' {
+		sign = -1
+	}
+	rest := str[1:]
+	if rest == "" {
+		return p, errors.NotValid.Newf("offset %q without a number", str)
+	}
+	for rest != "" {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		if i == 0 || i == len(rest) {
+			return p, errors.NotValid.Newf("offset %q requires a number followed by a unit", str)
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return p, errors.NotValid.New(err, "offset %q", str)
+		}
+		n *= sign
+		switch rest[i] {
+		case 'y':
+			p.years += n
+		case 'M':
+			p.months += n
+		case 'w':
+			p.days += 7 * n
+		case 'd':
+			p.days += n
+		case 'h':
+			p.dur += time.Duration(n) * time.Hour
+		case 'm':
+			p.dur += time.Duration(n) * time.Minute
+		case 's':
+			p.dur += time.Duration(n) * time.Second
+		default:
+			return p, errors.NotValid.Newf("offset %q contains the unknown unit %q, allowed are y, M, w, d, h, m and s", str, rest[i])
+		}
+		rest = rest[i+1:]
+	}
+	return p, nil
+}
+
+// timeSpec contains the parsed time tag or the options of a time field.
+type timeSpec struct {
+	hasRange   bool
+	start, end timePoint
+	afterField string
+	monotonic  time.Duration
+}
+
+// isTimeTag reports whether the tag contains time_range, after_field or
+// monotonic.
+func isTimeTag(tag string) bool {
+	return strings.HasPrefix(tag, tagTimeRange+"=") || strings.HasPrefix(tag, tagAfterField+"=") ||
+		strings.HasPrefix(tag, tagMonotonic+"=")
+}
+
+// parseTimeRange parses an expression like "-2y..now".
+func parseTimeRange(expr string) (start, end timePoint, err error) {
+	i := strings.Index(expr, "..")
+	if i < 0 {
+		return start, end, errors.NotValid.Newf("time range %q requires the format start..end", expr)
+	}
+	if start, err = parseTimePoint(expr[:i]); err != nil {
+		return start, end, err
+	}
+	if end, err = parseTimePoint(expr[i+2:]); err != nil {
+		return start, end, err
+	}
+	if now := time.Now(); start.resolve(now).After(end.resolve(now)) {
+		return start, end, errors.NotValid.Newf("time range %q starts after its end", expr)
+	}
+	return start, end, nil
+}
+
+func parseTimeTag(tag string) (*timeSpec, error) {
+	ts := new(timeSpec)
+	for _, kv := range strings.Split(tag, ";") {
+		i := strings.IndexByte(kv, '=')
+		if i < 0 {
+			return nil, errors.NotValid.Newf("%q requires the format key=value", kv)
+		}
+		switch key, val := kv[:i], kv[i+1:]; key {
+		case tagTimeRange:
+			var err error
+			if ts.start, ts.end, err = parseTimeRange(val); err != nil {
+				return nil, err
+			}
+			ts.hasRange = true
+		case tagAfterField:
+			if val == "" {
+				return nil, errors.NotValid.Newf("%s requires a field name", tagAfterField)
+			}
+			ts.afterField = val
+		case tagMonotonic:
+			d, err := time.ParseDuration(val)
+			if err != nil || d < time.Second {
+				return nil, errors.NotValid.Newf("%s %q requires a duration of at least 1s", tagMonotonic, val)
+			}
+			ts.monotonic = d
+		default:
+			return nil, errors.NotValid.Newf("unknown key %q, allowed are %s, %s and %s", key, tagTimeRange, tagAfterField, tagMonotonic)
+		}
+	}
+	return ts, nil
+}
+
+// timeSpecOfTag returns the cached spec of a time tag.
+func (s *Service) timeSpecOfTag(tag string) (*timeSpec, error) {
+	s.mu.RLock()
+	ts, ok := s.timeTags[tag]
+	s.mu.RUnlock()
+	if ok {
+		return ts, nil
+	}
+	ts, err := parseTimeTag(tag)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[pseudo] Invalid time tag %q", tag)
+	}
+	s.mu.Lock()
+	s.timeTags[tag] = ts
+	s.mu.Unlock()
+	return ts, nil
+}
+
+// WithTimeRange generates the time of a field between start and end of the
+// expression, e.g. "-2y..now" or "2020-01-01..+1M", see the struct tag
+// `faker:"time_range=-2y..now"`. The field can be the type name and the field
+// name, e.g. "store.Customer.CreatedAt", or the column name which is the
+// snake case field name, e.g. "created_at". Supported field types are
+// time.Time, *time.Time and null types with a field `Time time.Time`. The
+// times get generated in Options.TimeLocation, which defaults to UTC.
+func WithTimeRange(field, expr string) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			start, end, err := parseTimeRange(expr)
+			if err != nil {
+				return errors.NotValid.New(err, "[pseudo] WithTimeRange for field %q", field)
+			}
+			ts := s.timeSpec(field)
+			ts.start, ts.end, ts.hasRange = start, end, true
+			return nil
+		},
+	}
+}
+
+// WithTimeAfterField generates the time of a field after the time of
+// afterField of the same struct, e.g. WithTimeAfterField("updated_at",
+// "CreatedAt"), see the struct tag `faker:"after_field=CreatedAt"`. The time
+// lies between afterField and the end of the time range of the field, which
+// defaults to now.
+func WithTimeAfterField(field, afterField string) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			if afterField == "" {
+				return errors.NotValid.Newf("[pseudo] WithTimeAfterField for field %q requires afterField", field)
+			}
+			s.timeSpec(field).afterField = afterField
+			return nil
+		},
+	}
+}
+
+// WithMonotonicTime generates for each struct an increasing time of a field,
+// e.g. for event or log fixtures, see the struct tag `faker:"monotonic=1h"`.
+// The first time is the start of the time range of the field, otherwise now,
+// each next time lies up to maxStep after the previous one.
+func WithMonotonicTime(field string, maxStep time.Duration) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			if maxStep < time.Second {
+				return errors.NotValid.Newf("[pseudo] WithMonotonicTime for field %q requires a maxStep of at least 1s", field)
+			}
+			s.timeSpec(field).monotonic = maxStep
+			return nil
+		},
+	}
+}
+
+// timeSpec returns the spec of an option. Gets only called in NewService.
+func (s *Service) timeSpec(field string) *timeSpec {
+	if s.timeSpecs == nil {
+		s.timeSpecs = make(map[string]*timeSpec)
+	}
+	ts, ok := s.timeSpecs[field]
+	if !ok {
+		ts = new(timeSpec)
+		s.timeSpecs[field] = ts
+	}
+	return ts
+}
+
+// fieldTimeSpec returns the spec of the struct tag or of the options.
+func (s *Service) fieldTimeSpec(typeName string, tf reflect.StructField) (*timeSpec, error) {
+	if tag := tf.Tag.Get(tagName); isTimeTag(tag) {
+		ts, err := s.timeSpecOfTag(tag)
+		return ts, errors.Wrapf(err, "[pseudo] Field %s.%s", typeName, tf.Name)
+	}
+	if len(s.timeSpecs) == 0 {
+		return nil, nil
+	}
+	ts, ok := s.timeSpecs[typeName+"."+tf.Name]
+	col, okCol := s.timeSpecs[toSnakeCase(tf.Name)]
+	switch {
+	case !ok:
+		return col, nil
+	case !okCol:
+		return ts, nil
+	}
+	// The type and field name has precedence before the column name.
+	merged := *col
+	if ts.hasRange {
+		merged.hasRange, merged.start, merged.end = true, ts.start, ts.end
+	}
+	if ts.afterField != "" {
+		merged.afterField = ts.afterField
+	}
+	if ts.monotonic > 0 {
+		merged.monotonic = ts.monotonic
+	}
+	return &merged, nil
+}
+
+var typeTime = reflect.TypeOf(time.Time{})
+
+// isTimeType reports whether t is time.Time, a pointer to it or a null type
+// with a field `Time time.Time`.
+func isTimeType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == typeTime {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	tf, ok := t.FieldByName("Time")
+	vf, okValid := t.FieldByName("Valid")
+	return ok && okValid && vf.Type.Kind() == reflect.Bool && (tf.Type == typeTime || tf.Type == reflect.PtrTo(typeTime))
+}
+
+// checkTimeTag validates the time tag of field f of struct t.
+func (s *Service) checkTimeTag(t reflect.Type, f reflect.StructField) error {
+	ts, err := s.timeSpecOfTag(f.Tag.Get(tagName))
+	if err != nil {
+		return errors.Wrapf(err, "[pseudo] Field %s.%s", t, f.Name)
+	}
+	if !isTimeType(f.Type) {
+		return errors.NotSupported.Newf("[pseudo] Field %s.%s: type %s does not support time tags", t, f.Name, f.Type)
+	}
+	if ts.afterField == "" {
+		return nil
+	}
+	af, ok := t.FieldByName(ts.afterField)
+	if !ok || len(af.Index) != 1 || !isTimeType(af.Type) {
+		return errors.NotFound.Newf("[pseudo] Field %s.%s: %s %q is not a time field", t, f.Name, tagAfterField, ts.afterField)
+	}
+	return nil
+}
+
+// timeField returns the settable time.Time of a field.
+func timeField(v reflect.Value) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return timeField(v.Elem())
+	case reflect.Struct:
+		if _, ok := v.Interface().(time.Time); ok {
+			return v, true
+		}
+		if tv := v.FieldByName("Time"); tv.IsValid() && isNullable(v) {
+			if tv, ok := timeField(tv); ok {
+				v.FieldByName("Valid").SetBool(true)
+				return tv, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// currentTime returns the time of an existing time field and whether it is
+// set.
+func currentTime(v reflect.Value) (time.Time, bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return time.Time{}, false
+		}
+		return currentTime(v.Elem())
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			return t, !t.IsZero()
+		}
+		if isNullable(v) && v.FieldByName("Valid").Bool() {
+			if tv := v.FieldByName("Time"); tv.IsValid() {
+				return currentTime(tv)
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// randomTimeBetween returns a random time with second precision between
+// start and end, both inclusive.
+func (s *Service) randomTimeBetween(start, end time.Time) time.Time {
+	start = start.Truncate(time.Second)
+	var secs int64
+	if d := int64(end.Sub(start) / time.Second); d > 0 {
+		secs = s.r.Int63n(d + 1)
+	}
+	return start.Add(time.Duration(secs) * time.Second).In(s.o.TimeLocation)
+}
+
+// applyTimeSpecs generates the time fields with a time range, with an order
+// or monotonic times after the fake data has been generated.
+func (s *Service) applyTimeSpecs(v reflect.Value, typeName string, seed uint64) error {
+	t := v.Type()
+	specs := make(map[int]*timeSpec)
+	for i := 0; i < v.NumField(); i++ {
+		tf := t.Field(i)
+		if !v.Field(i).CanSet() || tf.Tag.Get(tagName) == Skip {
+			continue
+		}
+		ts, err := s.fieldTimeSpec(typeName, tf)
+		if err != nil {
+			return err
+		}
+		if ts != nil {
+			specs[i] = ts
+		}
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+
+	done := make(map[int]bool, len(specs))
+	var apply func(i int) error
+	apply = func(i int) error {
+		if done[i] {
+			return nil
+		}
+		done[i] = true
+		ts := specs[i]
+		tf := t.Field(i)
+		if vf := v.Field(i); isNullable(vf) && isNull(vf) {
+			return nil // keeps NULL, see WithNullProbability
+		}
+		var after time.Time
+		var hasAfter bool
+		if ts.afterField != "" {
+			af, ok := t.FieldByName(ts.afterField)
+			if !ok || len(af.Index) != 1 {
+				return errors.NotFound.Newf("[pseudo] Field %s.%s: %s %q not found", typeName, tf.Name, tagAfterField, ts.afterField)
+			}
+			if _, ok := specs[af.Index[0]]; ok {
+				if err := apply(af.Index[0]); err != nil {
+					return err
+				}
+			}
+			after, hasAfter = currentTime(v.Field(af.Index[0]))
+		}
+
+		if s.deterministic {
+			s.r.Seed(mixSeed(s.fieldSeed(seed, typeName, tf.Name), 4))
+		}
+		now := time.Now()
+		end := now
+		if ts.hasRange {
+			end = ts.end.resolve(now)
+		}
+		var val time.Time
+		switch {
+		case ts.monotonic > 0:
+			val = s.nextMonotonicTime(typeName+"."+tf.Name, ts, now)
+		case hasAfter:
+			if !end.After(after) {
+				end = after.Add(24 * time.Hour)
+			}
+			start := after.Add(time.Second)
+			if ts.hasRange {
+				if rs := ts.start.resolve(now); rs.After(start) && !rs.After(end) {
+					start = rs
+				}
+			}
+			val = s.randomTimeBetween(start, end)
+		case ts.hasRange:
+			val = s.randomTimeBetween(ts.start.resolve(now), end)
+		default:
+			return nil // the other field is NULL
+		}
+
+		tv, ok := timeField(v.Field(i))
+		if !ok {
+			return errors.NotSupported.Newf("[pseudo] Field %s.%s: type %s does not support time tags", typeName, tf.Name, tf.Type)
+		}
+		tv.Set(reflect.ValueOf(val))
+		return nil
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if _, ok := specs[i]; ok {
+			if err := apply(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nextMonotonicTime returns the next increasing time of a field.
+func (s *Service) nextMonotonicTime(key string, ts *timeSpec, now time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, ok := s.monotonicTimes[key]
+	var next time.Time
+	if !ok {
+		next = now
+		if ts.hasRange {
+			next = ts.start.resolve(now)
+		}
+		next = next.Truncate(time.Second).In(s.o.TimeLocation)
+	} else {
+		next = prev.Add(time.Duration(1+s.r.Int63n(int64(ts.monotonic/time.Second))) * time.Second)
+	}
+	s.monotonicTimes[key] = next
+	return next
+}
@@ -0,0 +1,158 @@
+package pseudo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type timeNullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+type timeEntity struct {
+	CreatedAt time.Time    `faker:"time_range=-2y..now"`
+	UpdatedAt *time.Time   `faker:"after_field=CreatedAt"`
+	DeletedAt timeNullTime `faker:"time_range=-1y..now;after_field=UpdatedAt"`
+	ValidFrom time.Time    `faker:"time_range=2020-01-01..2020-12-31"`
+	LoggedAt  time.Time
+}
+
+type timeLog struct {
+	LoggedAt  time.Time
+	ExpiresAt timeNullTime
+}
+
+type timeEvent struct {
+	ID         int64
+	OccurredAt time.Time `faker:"time_range=-30d..-30d;monotonic=10m"`
+}
+
+func TestTimeRange(t *testing.T) {
+	t.Run("struct tags", func(t *testing.T) {
+		s := MustNewService(0, nil, WithTagTypes(timeEntity{}))
+		validFromStart := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		validFromEnd := time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 500; i++ {
+			var e timeEntity
+			assert.NoError(t, s.FakeData(&e))
+			now := time.Now()
+			assert.False(t, e.CreatedAt.Before(now.AddDate(-2, 0, 0).Add(-time.Second)), "CreatedAt %s", e.CreatedAt)
+			assert.False(t, e.CreatedAt.After(now), "CreatedAt %s", e.CreatedAt)
+			assert.True(t, e.UpdatedAt.After(e.CreatedAt), "UpdatedAt %s CreatedAt %s", e.UpdatedAt, e.CreatedAt)
+			if e.DeletedAt.Valid {
+				assert.True(t, e.DeletedAt.Time.After(*e.UpdatedAt), "DeletedAt %s UpdatedAt %s", e.DeletedAt.Time, e.UpdatedAt)
+			}
+			assert.False(t, e.ValidFrom.Before(validFromStart), "ValidFrom %s", e.ValidFrom)
+			assert.False(t, e.ValidFrom.After(validFromEnd), "ValidFrom %s", e.ValidFrom)
+			assert.Exactly(t, time.UTC, e.CreatedAt.Location())
+			assert.Exactly(t, 0, e.CreatedAt.Nanosecond())
+		}
+	})
+
+	t.Run("options and location", func(t *testing.T) {
+		loc := time.FixedZone("CEST", 2*3600)
+		s := MustNewService(0, &Options{TimeLocation: loc},
+			WithTimeRange("logged_at", "-1w..+1w"),
+			WithTimeRange("pseudo.timeLog.ExpiresAt", "-1h..now"),
+			WithTimeAfterField("expires_at", "LoggedAt"),
+		)
+		for i := 0; i < 200; i++ {
+			var e timeLog
+			assert.NoError(t, s.FakeData(&e))
+			now := time.Now()
+			assert.Exactly(t, loc, e.LoggedAt.Location())
+			assert.False(t, e.LoggedAt.Before(now.AddDate(0, 0, -7).Add(-time.Second)), "LoggedAt %s", e.LoggedAt)
+			assert.False(t, e.LoggedAt.After(now.AddDate(0, 0, 7)), "LoggedAt %s", e.LoggedAt)
+			if e.ExpiresAt.Valid {
+				assert.True(t, e.ExpiresAt.Time.After(e.LoggedAt), "ExpiresAt %s LoggedAt %s", e.ExpiresAt.Time, e.LoggedAt)
+			}
+		}
+	})
+
+	t.Run("monotonic", func(t *testing.T) {
+		s := MustNewService(0, nil)
+		start := time.Now().AddDate(0, 0, -30).Add(-time.Second)
+		var prev time.Time
+		for i := 0; i < 1000; i++ {
+			var e timeEvent
+			assert.NoError(t, s.FakeData(&e))
+			if i == 0 {
+				assert.False(t, e.OccurredAt.Before(start), "OccurredAt %s", e.OccurredAt)
+			} else {
+				assert.True(t, e.OccurredAt.After(prev), "OccurredAt %s prev %s", e.OccurredAt, prev)
+				assert.False(t, e.OccurredAt.After(prev.Add(10*time.Minute)), "OccurredAt %s prev %s", e.OccurredAt, prev)
+			}
+			prev = e.OccurredAt
+		}
+
+		s = MustNewService(0, nil, WithTimeRange("logged_at", "2021-03-01..now"), WithMonotonicTime("logged_at", time.Hour))
+		var e1, e2 timeEntity
+		assert.NoError(t, s.FakeData(&e1))
+		assert.NoError(t, s.FakeData(&e2))
+		assert.Exactly(t, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), e1.LoggedAt)
+		assert.True(t, e2.LoggedAt.After(e1.LoggedAt), "LoggedAt %s %s", e2.LoggedAt, e1.LoggedAt)
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		fake := func() (times []time.Time) {
+			s := MustNewService(4711, nil, WithDeterministicPerField(), WithTimeRange("logged_at", "2020-01-01..2021-01-01"))
+			for i := 0; i < 50; i++ {
+				var e timeEntity
+				assert.NoError(t, s.FakeData(&e))
+				times = append(times, e.LoggedAt, e.ValidFrom)
+			}
+			return times
+		}
+		assert.Exactly(t, fake(), fake())
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, expr := range []string{"", "-2y", "now..-2y", "-2x..now", "yesterday..now", "-..now", "2020-13-01..now"} {
+			_, err := NewService(0, nil, WithTimeRange("created_at", expr))
+			assert.ErrorIsKind(t, errors.NotValid, err, "%q", expr)
+			assert.Contains(t, err.Error(), "created_at")
+		}
+		_, err := NewService(0, nil, WithMonotonicTime("created_at", time.Millisecond))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("invalid struct tags", func(t *testing.T) {
+		type invalidRange struct {
+			CreatedAt time.Time `faker:"time_range=now..-1d"`
+		}
+		type invalidKey struct {
+			CreatedAt time.Time `faker:"time_range=-1d..now;before=UpdatedAt"`
+		}
+		type invalidAfter struct {
+			CreatedAt time.Time `faker:"after_field=Name"`
+			Name      string
+		}
+		type invalidType struct {
+			CreatedAt string `faker:"time_range=-1d..now"`
+		}
+		tests := []struct {
+			v    interface{}
+			kind errors.Kind
+		}{
+			{invalidRange{}, errors.NotValid},
+			{[]*invalidKey{}, errors.NotValid},
+			{invalidAfter{}, errors.NotFound},
+			{map[string]invalidType{}, errors.NotSupported},
+		}
+		for _, test := range tests {
+			_, err := NewService(0, nil, WithTagTypes(test.v))
+			assert.ErrorIsKind(t, test.kind, err, "%T", test.v)
+			assert.Contains(t, err.Error(), "CreatedAt")
+		}
+
+		s := MustNewService(0, nil)
+		var v invalidRange
+		err := s.FakeData(&v)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.Contains(t, err.Error(), "CreatedAt")
+	})
+}
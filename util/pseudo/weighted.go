@@ -0,0 +1,136 @@
+package pseudo
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/corestoreio/errors"
+)
+
+// WithTagWeightedChoice generates for a tag, a field name or a type name and a
+// field name, like WithTagFakeFunc, one of the keys of weights. The
+// probability of a key is its weight divided by the sum of all weights, e.g.
+// map[string]int{"default": 90, "websites": 5, "stores": 5} returns "default"
+// in 90% of the cases. The key gets converted to the type of the field. A
+// negative weight or a sum of zero returns an error in NewService.
+func WithTagWeightedChoice(field string, weights map[string]int) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			keys := make([]string, 0, len(weights))
+			for k, w := range weights {
+				if w < 0 {
+					return errors.NotValid.Newf("[pseudo] WithTagWeightedChoice %q: weight %d of %q must not be negative", field, w, k)
+				}
+				keys = append(keys, k)
+			}
+			sort.Strings(keys) // stable order for the deterministic mode
+			p := new(recordPool)
+			for _, k := range keys {
+				p.add(k, uint64(weights[k]))
+			}
+			if p.empty() {
+				return errors.NotValid.Newf("[pseudo] WithTagWeightedChoice %q: the sum of the weights must be greater zero", field)
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.funcs[field] = func(maxLen int) interface{} {
+				return p.pick(s)
+			}
+			return nil
+		},
+	}
+}
+
+// WithNullProbability sets a nullable field to NULL with the probability p
+// between 0 and 1, otherwise the field contains a valid value. Nullable are
+// pointers and structs with a field `Valid bool` like the null.* types. The
+// field can be the type name and the field name, e.g. "store.Product.SKU", or
+// the column name which is the snake case field name, e.g. "sku".
+func WithNullProbability(field string, p float64) optionFn {
+	return optionFn{
+		sortOrder: 10,
+		fn: func(s *Service) error {
+			if p < 0 || p > 1 || p != p {
+				return errors.NotValid.Newf("[pseudo] WithNullProbability %q: probability %s must be between 0 and 1", field, strconv.FormatFloat(p, 'f', -1, 64))
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.nullProbabilities == nil {
+				s.nullProbabilities = make(map[string]float64)
+			}
+			s.nullProbabilities[field] = p
+			return nil
+		},
+	}
+}
+
+func (s *Service) nullProbability(typeName, fieldName string) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.nullProbabilities) == 0 {
+		return 0, false
+	}
+	if p, ok := s.nullProbabilities[typeName+"."+fieldName]; ok {
+		return p, true
+	}
+	p, ok := s.nullProbabilities[toSnakeCase(fieldName)]
+	return p, ok
+}
+
+// nullValidAttempts defines how often an invalid null type gets generated
+// again before Valid gets forced to true.
+const nullValidAttempts = 10
+
+// applyNullProbabilities sets the nullable fields of struct v to NULL or to a
+// valid value after the fake data has been generated.
+func (s *Service) applyNullProbabilities(v reflect.Value, typeName string, recursionLevel int, seed uint64) error {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		vf := v.Field(i)
+		tf := t.Field(i)
+		if !vf.CanSet() || tf.Tag.Get(tagName) == Skip || !isNullable(vf) {
+			continue
+		}
+		p, ok := s.nullProbability(typeName, tf.Name)
+		if !ok {
+			continue
+		}
+		fieldSeed := seed
+		if s.deterministic {
+			fieldSeed = mixSeed(s.fieldSeed(seed, typeName, tf.Name), 3)
+			s.r.Seed(fieldSeed)
+		}
+		if s.r.Float64() < p {
+			vf.Set(reflect.Zero(vf.Type()))
+			continue
+		}
+
+		var maxLen uint64
+		if maxLenTag := tf.Tag.Get(tagMaxLenName); maxLenTag != "" {
+			maxLen, _ = strconv.ParseUint(maxLenTag, 10, 64) // already validated
+		}
+		for j := 0; j < nullValidAttempts && isNull(vf); j++ {
+			val, err := s.getValue(vf.Type(), maxLen, recursionLevel+1, mixSeed(fieldSeed, uint64(j)), nil)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if val.IsValid() {
+				vf.Set(val.Convert(vf.Type()))
+			}
+		}
+		if vf.Kind() == reflect.Struct {
+			vf.FieldByName("Valid").SetBool(true)
+		}
+	}
+	return nil
+}
+
+// isNull reports whether the nullable v is nil or not valid.
+func isNull(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr {
+		return v.IsNil()
+	}
+	return !v.FieldByName("Valid").Bool()
+}
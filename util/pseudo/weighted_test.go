@@ -0,0 +1,124 @@
+package pseudo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type weightedEntity struct {
+	Scope   string
+	ScopeID int64 `faker:"scope_id"`
+}
+
+type nullableEntity struct {
+	SKU     limitNullString `max_len:"8"`
+	Comment *string         `max_len:"8"`
+}
+
+func assertShare(t *testing.T, want float64, count, total int, msg string) {
+	t.Helper()
+	have := float64(count) / float64(total)
+	assert.True(t, math.Abs(have-want) < 0.01, "%s: want %.3f have %.4f", msg, want, have)
+}
+
+func TestWithTagWeightedChoice(t *testing.T) {
+	newService := func(seed uint64, opts ...optionFn) *Service {
+		return MustNewService(seed, nil, append(opts,
+			WithTagWeightedChoice("scope", map[string]int{"default": 90, "websites": 5, "stores": 5}),
+			WithTagWeightedChoice("scope_id", map[string]int{"0": 1, "1": 3}),
+		)...)
+	}
+
+	t.Run("distribution", func(t *testing.T) {
+		const total = 50000
+		s := newService(0)
+		scopes := map[string]int{}
+		scopeIDs := map[int64]int{}
+		var e weightedEntity
+		for i := 0; i < total; i++ {
+			assert.NoError(t, s.FakeData(&e))
+			scopes[e.Scope]++
+			scopeIDs[e.ScopeID]++
+		}
+		assert.Len(t, scopes, 3)
+		assertShare(t, 0.9, scopes["default"], total, "default")
+		assertShare(t, 0.05, scopes["websites"], total, "websites")
+		assertShare(t, 0.05, scopes["stores"], total, "stores")
+		assertShare(t, 0.75, scopeIDs[1], total, "scope_id 1")
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		fake := func() (scopes []string) {
+			s := newService(4711, WithDeterministicPerField())
+			for i := 0; i < 100; i++ {
+				var e weightedEntity
+				assert.NoError(t, s.FakeData(&e))
+				scopes = append(scopes, e.Scope)
+			}
+			return scopes
+		}
+		assert.Exactly(t, fake(), fake())
+	})
+
+	t.Run("invalid weights", func(t *testing.T) {
+		_, err := NewService(0, nil, WithTagWeightedChoice("scope", map[string]int{"default": -1, "stores": 2}))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		_, err = NewService(0, nil, WithTagWeightedChoice("scope", map[string]int{"default": 0}))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
+
+func TestWithNullProbability(t *testing.T) {
+	t.Run("distribution", func(t *testing.T) {
+		const total = 50000
+		s := MustNewService(0, nil, WithNullProbability("sku", 0.05), WithNullProbability("pseudo.nullableEntity.Comment", 0.5))
+		var nullSKUs, nullComments int
+		var e nullableEntity
+		for i := 0; i < total; i++ {
+			assert.NoError(t, s.FakeData(&e))
+			if !e.SKU.Valid {
+				nullSKUs++
+				assert.Empty(t, e.SKU.String)
+			}
+			if e.Comment == nil {
+				nullComments++
+			}
+		}
+		assertShare(t, 0.05, nullSKUs, total, "SKU")
+		assertShare(t, 0.5, nullComments, total, "Comment")
+	})
+
+	t.Run("never and always", func(t *testing.T) {
+		s := MustNewService(0, nil, WithNullProbability("sku", 0), WithNullProbability("comment", 1))
+		for i := 0; i < 100; i++ {
+			var e nullableEntity
+			assert.NoError(t, s.FakeData(&e))
+			assert.True(t, e.SKU.Valid)
+			assert.NotEmpty(t, e.SKU.String)
+			assert.Nil(t, e.Comment)
+		}
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		fake := func() (skus []limitNullString) {
+			s := MustNewService(4711, nil, WithDeterministicPerField(), WithNullProbability("sku", 0.3))
+			for i := 0; i < 100; i++ {
+				var e nullableEntity
+				assert.NoError(t, s.FakeData(&e))
+				skus = append(skus, e.SKU)
+			}
+			return skus
+		}
+		assert.Exactly(t, fake(), fake())
+	})
+
+	t.Run("invalid probability", func(t *testing.T) {
+		_, err := NewService(0, nil, WithNullProbability("sku", 1.5))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		_, err = NewService(0, nil, WithNullProbability("sku", math.NaN()))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}